@@ -0,0 +1,8 @@
+"""Python client for the OpenShift Coordination Engine REST API.
+
+The client and its request and response types are generated from
+api/openapi/openapi.json; see client.py.
+"""
+
+from .client import *  # noqa: F401,F403
+from .client import __all__, __version__  # noqa: F401
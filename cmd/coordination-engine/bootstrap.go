@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"sigs.k8s.io/yaml"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/anomaly"
+	"github.com/tosin2013/openshift-coordination-engine/internal/bootstrap"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/config"
+)
+
+// bootstrapTimeout bounds the cluster inspection and apply
+const bootstrapTimeout = 5 * time.Minute
+
+// runBootstrap implements "coordination-engine bootstrap": it inspects the cluster and
+// prints a generated initial configuration for review. With -out-dir the files the
+// engine reads are written instead; with -apply namespaces are labeled with their
+// tiers and scan schedules are created through the admin API at -engine-url. -plan
+// applies a reviewed plan instead of generating one.
+func runBootstrap(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("bootstrap", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	kubeconfig := flags.String("kubeconfig", os.Getenv("KUBECONFIG"), "kubeconfig file (default in-cluster, then ~/.kube/config)")
+	tierLabel := flags.String("tier-label", anomaly.DefaultTierLabel, "namespace label holding tiers")
+	timezone := flags.String("timezone", "UTC", "IANA timezone of the generated quiet hours")
+	includeSystem := flags.Bool("include-system", false, "include the default, kube-* and openshift-* namespaces")
+	output := flags.String("output", "yaml", "plan format: yaml or json")
+	outDir := flags.String("out-dir", "", "write plan.yaml, scope-defaults.yaml and calendars.yaml to this directory")
+	apply := flags.Bool("apply", false, "label namespaces with their tiers and create the scan schedules")
+	planFile := flags.String("plan", "", "apply this reviewed plan.yaml instead of generating one (requires -apply)")
+	engineURL := flags.String("engine-url", "", "engine URL for -apply; the admin token is read from ADMIN_TOKEN")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *output != "yaml" && *output != "json" {
+		fmt.Fprintf(stderr, "invalid -output %q: must be yaml or json\n", *output)
+		return 2
+	}
+	if *planFile != "" && !*apply {
+		fmt.Fprintln(stderr, "-plan requires -apply")
+		return 2
+	}
+
+	log := logrus.New()
+	log.SetOutput(stderr)
+	log.SetLevel(logrus.WarnLevel)
+	k8sClients, err := initKubernetesClient(&config.Config{
+		Kubeconfig:      *kubeconfig,
+		KubernetesQPS:   config.DefaultKubernetesQPS,
+		KubernetesBurst: config.DefaultKubernetesBurst,
+	}, log)
+	if err != nil {
+		fmt.Fprintf(stderr, "bootstrap: %v\n", err)
+		return 1
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), bootstrapTimeout)
+	defer cancel()
+	var plan *bootstrap.Plan
+	if *planFile != "" {
+		plan, err = bootstrap.LoadPlan(*planFile)
+	} else {
+		plan, err = bootstrap.NewGenerator(k8sClients.Clientset, k8sClients.DynamicClient, bootstrap.Options{
+			TierLabel:     *tierLabel,
+			Timezone:      *timezone,
+			IncludeSystem: *includeSystem,
+		}).Generate(ctx)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "bootstrap: %v\n", err)
+		return 1
+	}
+	for _, warning := range plan.Warnings {
+		fmt.Fprintf(stderr, "warning: %s\n", warning)
+	}
+
+	switch {
+	case *planFile != "":
+		// The plan was reviewed already
+	case *outDir != "":
+		if err := writeBootstrapFiles(*outDir, plan); err != nil {
+			fmt.Fprintf(stderr, "bootstrap: %v\n", err)
+			return 1
+		}
+		fmt.Fprintf(stdout, "Wrote plan.yaml, scope-defaults.yaml and calendars.yaml to %s\n", *outDir)
+	default:
+		if err := printBootstrapPlan(stdout, plan, *output); err != nil {
+			fmt.Fprintf(stderr, "bootstrap: %v\n", err)
+			return 1
+		}
+	}
+
+	if !*apply {
+		return 0
+	}
+	failed := false
+	for _, result := range bootstrap.Apply(ctx, k8sClients.Clientset, plan, bootstrap.ApplyOptions{
+		EngineURL:  *engineURL,
+		AdminToken: os.Getenv("ADMIN_TOKEN"),
+	}) {
+		line := fmt.Sprintf("%-9s %s", result.Status, result.Target)
+		if result.Error != "" {
+			line += ": " + result.Error
+		}
+		fmt.Fprintln(stderr, line)
+		failed = failed || result.Status == bootstrap.StatusFailed
+	}
+	if failed {
+		return 1
+	}
+	return 0
+}
+
+// printBootstrapPlan writes the plan as YAML or JSON
+func printBootstrapPlan(w io.Writer, plan *bootstrap.Plan, format string) error {
+	var data []byte
+	var err error
+	if format == "json" {
+		data, err = json.MarshalIndent(plan, "", "  ")
+		data = append(data, '\n')
+	} else {
+		data, err = yaml.Marshal(plan)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to encode plan: %w", err)
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// writeBootstrapFiles writes the plan, and the scope defaults and calendars files for
+// ANOMALY_SCOPE_DEFAULTS_FILE and ANOMALY_CALENDARS_FILE
+func writeBootstrapFiles(dir string, plan *bootstrap.Plan) error {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	files := map[string]interface{}{
+		"plan.yaml":           plan,
+		"scope-defaults.yaml": plan.ScopeDefaults,
+		"calendars.yaml":      plan.Calendars,
+	}
+	for name, content := range files {
+		data, err := yaml.Marshal(content)
+		if err != nil {
+			return fmt.Errorf("failed to encode %s: %w", name, err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o600); err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+	return nil
+}
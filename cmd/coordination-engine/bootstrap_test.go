@@ -0,0 +1,45 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/anomaly"
+	"github.com/tosin2013/openshift-coordination-engine/internal/bootstrap"
+)
+
+func TestWriteBootstrapFiles(t *testing.T) {
+	plan := &bootstrap.Plan{
+		TierLabel: "criticality",
+		ScopeDefaults: bootstrap.ScopeDefaultsFile{
+			TierLabel: "criticality",
+			Rules:     []anomaly.ScopeDefaultRule{{Name: "critical-tier", Tiers: []string{"critical"}, Threshold: 0.6}},
+		},
+		Calendars: bootstrap.CalendarsFile{Calendars: []anomaly.BusinessCalendar{{
+			Name:       "quiet-hours",
+			Namespaces: []string{"reports"},
+			Windows:    []anomaly.BusinessWindow{{Name: "weekends", Days: []string{"sat", "sun"}, ThresholdIncrease: 0.1, LowerSeverity: true}},
+		}}},
+	}
+	dir := t.TempDir()
+	require.NoError(t, writeBootstrapFiles(dir, plan))
+
+	// The files load as the engine loads them
+	defaults, err := anomaly.LoadScopeDefaults(filepath.Join(dir, "scope-defaults.yaml"))
+	require.NoError(t, err)
+	assert.True(t, defaults.UsesTiers())
+	_, err = anomaly.LoadBusinessCalendars(filepath.Join(dir, "calendars.yaml"))
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(dir, "plan.yaml"))
+}
+
+func TestRunBootstrap_InvalidFlags(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, 2, runBootstrap([]string{"-output", "xml"}, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "must be yaml or json")
+	assert.Equal(t, 2, runBootstrap([]string{"-unknown"}, &stdout, &stderr))
+}
@@ -3,31 +3,82 @@ package main
 
 import (
 	"context"
+	"crypto/x509"
 	"encoding/json"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"slices"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 
+	"github.com/tosin2013/openshift-coordination-engine/internal/advice"
+	"github.com/tosin2013/openshift-coordination-engine/internal/alerting"
+	"github.com/tosin2013/openshift-coordination-engine/internal/annotation"
+	"github.com/tosin2013/openshift-coordination-engine/internal/anomaly"
+	"github.com/tosin2013/openshift-coordination-engine/internal/apitoken"
+	"github.com/tosin2013/openshift-coordination-engine/internal/backup"
+	"github.com/tosin2013/openshift-coordination-engine/internal/baseline"
+	"github.com/tosin2013/openshift-coordination-engine/internal/clock"
 	"github.com/tosin2013/openshift-coordination-engine/internal/coordination"
 	"github.com/tosin2013/openshift-coordination-engine/internal/detector"
+	"github.com/tosin2013/openshift-coordination-engine/internal/escalation"
+	"github.com/tosin2013/openshift-coordination-engine/internal/execution"
+	"github.com/tosin2013/openshift-coordination-engine/internal/featureflag"
+	"github.com/tosin2013/openshift-coordination-engine/internal/featurehistory"
+	"github.com/tosin2013/openshift-coordination-engine/internal/hardening"
+	"github.com/tosin2013/openshift-coordination-engine/internal/imagescan"
 	"github.com/tosin2013/openshift-coordination-engine/internal/integrations"
+	"github.com/tosin2013/openshift-coordination-engine/internal/knowledge"
+	"github.com/tosin2013/openshift-coordination-engine/internal/loadshed"
+	"github.com/tosin2013/openshift-coordination-engine/internal/mtls"
+	"github.com/tosin2013/openshift-coordination-engine/internal/notification"
+	"github.com/tosin2013/openshift-coordination-engine/internal/observer"
+	"github.com/tosin2013/openshift-coordination-engine/internal/ownership"
+	"github.com/tosin2013/openshift-coordination-engine/internal/platform"
+	"github.com/tosin2013/openshift-coordination-engine/internal/prediction"
+	"github.com/tosin2013/openshift-coordination-engine/internal/promql"
 	"github.com/tosin2013/openshift-coordination-engine/internal/rbac"
+	"github.com/tosin2013/openshift-coordination-engine/internal/redact"
 	"github.com/tosin2013/openshift-coordination-engine/internal/remediation"
+	"github.com/tosin2013/openshift-coordination-engine/internal/retention"
+	"github.com/tosin2013/openshift-coordination-engine/internal/runtimeconfig"
+	"github.com/tosin2013/openshift-coordination-engine/internal/security"
+	"github.com/tosin2013/openshift-coordination-engine/internal/selfmonitor"
+	"github.com/tosin2013/openshift-coordination-engine/internal/severity"
+	"github.com/tosin2013/openshift-coordination-engine/internal/slo"
+	"github.com/tosin2013/openshift-coordination-engine/internal/startup"
+	"github.com/tosin2013/openshift-coordination-engine/internal/storage"
+	"github.com/tosin2013/openshift-coordination-engine/internal/summary"
+	"github.com/tosin2013/openshift-coordination-engine/internal/tagging"
+	"github.com/tosin2013/openshift-coordination-engine/internal/tenant"
+	"github.com/tosin2013/openshift-coordination-engine/internal/upgrade"
 	v1 "github.com/tosin2013/openshift-coordination-engine/pkg/api/v1"
+	v2 "github.com/tosin2013/openshift-coordination-engine/pkg/api/v2"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/api/versioning"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/capacity"
 	"github.com/tosin2013/openshift-coordination-engine/pkg/config"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/grpcapi"
 	"github.com/tosin2013/openshift-coordination-engine/pkg/kserve"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/mcp"
 	"github.com/tosin2013/openshift-coordination-engine/pkg/middleware"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/quantity"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/runbook"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/scoring"
 )
 
 var (
@@ -45,6 +96,10 @@ type KubernetesClients struct {
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "bootstrap" {
+		os.Exit(runBootstrap(os.Args[2:], os.Stdout, os.Stderr))
+	}
+
 	// Record start time for uptime tracking
 	startTime = time.Now()
 
@@ -91,23 +146,50 @@ func main() {
 	}
 	log.Info("RBAC permissions verified successfully")
 
+	// Gate startup on its dependencies: storage now, KServe and Prometheus once their
+	// clients exist
+	startupGates := initStartupGates(cfg, log)
+
 	// Initialize ML service client
 	mlClient := integrations.NewMLClient(cfg.MLServiceURL, cfg.HTTPTimeout, log)
 	defer mlClient.Close()
 
 	log.WithField("ml_service_url", cfg.MLServiceURL).Info("ML service client initialized")
 
+	// Outbound TLS audit; clients are registered as they are created
+	tlsAuditor := security.NewAuditor(cfg.Security.RequireFIPS, log)
+	if cfg.MLServiceURL != "" {
+		tlsAuditor.RegisterHTTPClient("ml-service", cfg.MLServiceURL, mlClient.HTTPClient())
+	}
+
 	// Initialize MCO client for infrastructure layer monitoring
 	mcoClient := integrations.NewMCOClient(k8sClients.DynamicClient, log)
 	log.Info("MCO client initialized for infrastructure layer monitoring")
 
+	// Initialize ClusterOperator client for platform layer evidence
+	clusterOperatorClient := integrations.NewClusterOperatorClient(k8sClients.DynamicClient, log)
+	log.Info("ClusterOperator client initialized for platform layer monitoring")
+
+	// Fixed clock of deterministic mode (nil runs on the wall clock)
+	engineClock := initClock(cfg, log)
+
 	// Initialize deployment detector
 	deploymentDetector := detector.NewDetector(k8sClients.Clientset, log)
+	if engineClock != nil {
+		deploymentDetector.SetClock(engineClock.Now)
+	}
 	log.Info("Deployment detector initialized")
 
+	// Mask credentials before they are stored or sent in notifications
+	redactor := initRedactor(cfg, log)
+
 	// Initialize multi-layer coordination components (Phase 3)
 	layerDetector := coordination.NewLayerDetector(log)
-	log.Info("Layer detector initialized")
+	layerDetector.SetClusterOperatorSource(clusterOperatorClient)
+	if redactor != nil {
+		layerDetector.SetRedactor(redactor)
+	}
+	log.Info("Layer detector initialized with ClusterOperator evidence")
 
 	multiLayerPlanner := coordination.NewMultiLayerPlanner(log)
 	log.Info("Multi-layer planner initialized")
@@ -116,7 +198,7 @@ func main() {
 	log.Info("Health checker initialized")
 
 	// Initialize remediation components using helper function
-	orchestrator, strategySelector := initRemediationComponents(cfg, k8sClients, deploymentDetector, log)
+	orchestrator, strategySelector := initRemediationComponents(cfg, k8sClients, deploymentDetector, tlsAuditor, log)
 
 	// Initialize multi-layer orchestrator with remediation integration (Phase 4)
 	multiLayerOrchestrator := coordination.NewMultiLayerOrchestrator(
@@ -134,24 +216,109 @@ func main() {
 	// Apply global middleware
 	router.Use(middleware.Recovery(log))
 	router.Use(middleware.RequestLogger(log))
+	router.Use(middleware.Timeout(cfg.RequestTimeout, requestTimeouts(cfg), log))
+	router.Use(middleware.Compress(middleware.DefaultCompressionMinSize, log))
+	router.Use(middleware.ETag(log))
+	router.Use(middleware.Mode(observer.Mode(cfg.ObserverMode)))
+
+	// API versioning: /api/v1 is the stable default, /api/v2 carries breaking schema changes
+	apiVersions, err := versioning.NewRegistry(versioning.V1,
+		versioning.Version{Name: versioning.V1},
+		versioning.Version{Name: versioning.V2},
+	)
+	if err != nil {
+		log.WithError(err).Fatal("Failed to configure API versions")
+	}
+	router.Use(apiVersions.Middleware)
+
+	// Feature flags gating capabilities per namespace
+	featureFlags := initFeatureFlags(cfg, log)
 
 	// Initialize KServe proxy client if enabled (ADR-039, ADR-040)
-	kserveProxyHandler := initKServeProxy(cfg, log)
+	kserveProxyHandler := initKServeProxy(cfg, tlsAuditor, log)
+	if kserveProxyHandler != nil {
+		kserveProxyHandler.GetProxyClient().SetCanaryGate(func(ctx context.Context, _ string) bool {
+			namespace := featureflag.Namespace(ctx)
+			if featureFlags.Enabled(featureflag.ModelCanary, namespace) {
+				return true
+			}
+			featureflag.RecordDisabled(featureflag.ModelCanary)
+			return false
+		})
+	}
+	kserveModelsCtx, stopKServeModels := context.WithCancel(context.Background())
+	defer stopKServeModels()
+	if kserveProxyHandler != nil {
+		watchKServeModels(kserveModelsCtx, kserveProxyHandler.GetProxyClient(), log)
+	}
 
 	// Verify KServe model availability on startup
-	verifyKServeModelsOnStartup(cfg, kserveProxyHandler, log)
+	gateKServe(startupGates, cfg, kserveProxyHandler, log)
+	histogramFeatures := initHistogramFeatures(cfg, log)
 
 	// Create API handlers
 	healthHandler := v1.NewHealthHandler(log, k8sClients.Clientset, rbacVerifier, cfg.MLServiceURL, Version, startTime)
+	healthHandler.SetMode(observer.Mode(cfg.ObserverMode))
+	if kserveProxyHandler != nil && cfg.KServe.PreflightEnabled {
+		preflightKServeModels(kserveProxyHandler.GetProxyClient(), histogramFeatures, cfg.Anomaly.HistogramModels, cfg.Anomaly.RouteProbeModels, cfg.Anomaly.ControlPlaneModel, log)
+		healthHandler.SetKServeClient(kserveProxyHandler.GetProxyClient())
+	}
 	// TODO: Add MCO health monitoring to health handler in future enhancement
-	_ = mcoClient // MCO client available for infrastructure layer operations
+	mcpUpdateDetector := detector.NewMachineConfigUpdateDetector(k8sClients.Clientset, mcoClient, log)
 	remediationHandler := v1.NewRemediationHandler(orchestrator, log)
+	remediationHandler.SetMachineConfigUpdateDetector(mcpUpdateDetector)
+	if redactor != nil {
+		remediationHandler.GetIncidentStore().SetRedactor(redactor)
+	}
+	initIncidentEncryption(cfg, remediationHandler.GetIncidentStore(), log)
+	initIncidentTagRules(cfg, remediationHandler.GetIncidentStore(), log)
 	detectionHandler := v1.NewDetectionHandler(deploymentDetector, log)
 	coordinationHandler := v1.NewCoordinationHandler(layerDetector, multiLayerPlanner, multiLayerOrchestrator, log)
 	log.Info("Coordination handler initialized")
+	if cfg.ObserverMode {
+		remediationHandler.SetObserverMode(true)
+		coordinationHandler.SetObserverMode(true)
+		log.Warn("Observer mode: remediation and cluster writes are disabled (unset OBSERVER_MODE to enable them)")
+	}
+	remediationHandler.SetFeatureFlags(featureFlags)
+	coordinationHandler.SetFeatureFlags(featureFlags)
+
+	// Upgrade-aware conservative mode (optional)
+	upgradeCtx, stopUpgradeMonitor := context.WithCancel(context.Background())
+	defer stopUpgradeMonitor()
+	upgradeMonitor := initUpgradeMonitor(cfg, k8sClients.DynamicClient, log)
+	if upgradeMonitor != nil {
+		remediationHandler.SetUpgradeMonitor(upgradeMonitor)
+		coordinationHandler.SetUpgradeMonitor(upgradeMonitor)
+		remediationHandler.GetIncidentStore().SetLabeler(upgradeMonitor)
+		upgradeMonitor.Start(upgradeCtx, cfg.Upgrade.CheckInterval)
+	}
 
 	// Initialize Prometheus client for metrics querying (optional)
-	prometheusClient := initPrometheusClient(cfg, log)
+	prometheusClient := initPrometheusClient(cfg, k8sClients.Clientset, tlsAuditor, log)
+	gatePrometheus(startupGates, cfg, prometheusClient, log)
+	startupCtx, stopStartupGates := context.WithCancel(context.Background())
+	defer stopStartupGates()
+	startupGates.Start(startupCtx)
+	healthHandler.SetStartupGates(startupGates)
+	if engineClock != nil {
+		prometheusClient.SetClock(engineClock.Now)
+	}
+
+	// Baselines imported from another cluster, used until Prometheus has learned this one's
+	baselines := baseline.NewStore("")
+	if prometheusClient != nil {
+		prometheusClient.SetBaselineFallback(baselines)
+	}
+
+	// Probe which metric exporters Prometheus has data of, so handlers skip queries that
+	// cannot return data and report the missing exporters
+	probeCtx, stopCapabilityProbe := context.WithCancel(context.Background())
+	defer stopCapabilityProbe()
+	if prometheusClient != nil {
+		prometheusClient.StartCapabilityProbe(probeCtx, cfg.PrometheusCapabilityProbeInterval)
+		healthHandler.SetPrometheusClient(prometheusClient)
+	}
 
 	// Create recommendations handler with KServe integration for ML predictions
 	var recommendationsHandler *v1.RecommendationsHandler
@@ -187,27 +354,239 @@ func main() {
 		recommendationsHandler.SetPrometheusClient(prometheusClient)
 		log.WithField("prometheus_url", cfg.PrometheusURL).Info("Prometheus client configured for ML predictions")
 	}
+	if engineClock != nil {
+		recommendationsHandler.SetClock(engineClock.Now)
+		predictionHandler.SetClock(engineClock.Now)
+	}
 	log.Info("Recommendations handler initialized")
 
+	// Prediction accuracy tracking (optional)
+	trackerCtx, stopTracker := context.WithCancel(context.Background())
+	defer stopTracker()
+	predictionTracker := initPredictionTracker(cfg, prometheusClient, log)
+	if predictionTracker != nil {
+		predictionHandler.SetAccuracyTracker(predictionTracker)
+		predictionTracker.Start(trackerCtx, cfg.PredictionTracking.EvaluationInterval)
+	}
+
+	// Runbook links for recommendations and incidents
+	runbookRegistry := initRunbookRegistry(cfg, tlsAuditor, log)
+	recommendationsHandler.SetRunbooks(runbookRegistry)
+	recommendationsHandler.SetCapacityAnalyzer(capacity.NewAnalyzer(k8sClients.Clientset, log))
+
+	// Order recommended actions by their verified success rates (optional)
+	var outcomes *storage.ActionOutcomeStore
+	if cfg.ActionRanking.Enabled {
+		outcomes = storage.NewActionOutcomeStore("", cfg.ActionRanking.HistoryLimit)
+		recommendationsHandler.SetActionRanker(knowledge.NewActionRanker(outcomes, cfg.ActionRanking.MinAttempts))
+		log.WithFields(logrus.Fields{
+			"min_attempts": cfg.ActionRanking.MinAttempts,
+			"outcomes":     outcomes.Count(),
+		}).Info("Recommended action ranking enabled")
+	}
+
+	// Organization-specific recommended actions from a hot-reloaded rules file (optional)
+	rulesCtx, stopRecommendationRules := context.WithCancel(context.Background())
+	defer stopRecommendationRules()
+	if engine := initRecommendationRules(rulesCtx, cfg, log); engine != nil {
+		recommendationsHandler.SetActionRules(engine)
+	}
+
+	// DNS, certificate expiry and etcd quota checks of the platform layer (optional)
+	if cfg.Platform.Enabled {
+		certificateScanner := platform.NewCertificateScanner(k8sClients.Clientset, cfg.Platform.CertificateNamespaces, log)
+		healthChecker.SetCertificateScanner(certificateScanner)
+		recommendationsHandler.SetCertificateScanner(certificateScanner, time.Duration(cfg.Platform.CertificateExpiryWarningDays)*24*time.Hour)
+		if prometheusClient != nil {
+			healthChecker.SetDNSCheck(prometheusClient, cfg.Platform.DNSErrorRateThreshold)
+			recommendationsHandler.SetEtcdForecast(quantity.Bytes(cfg.Platform.EtcdQuotaGiB)*quantity.GiB,
+				time.Duration(cfg.Platform.EtcdQuotaWarningDays)*24*time.Hour)
+		}
+		log.WithFields(logrus.Fields{
+			"dns_error_rate_threshold": cfg.Platform.DNSErrorRateThreshold,
+			"cert_expiry_warning_days": cfg.Platform.CertificateExpiryWarningDays,
+			"cert_namespaces":          cfg.Platform.CertificateNamespaces,
+			"etcd_quota_gib":           cfg.Platform.EtcdQuotaGiB,
+			"etcd_quota_warning_days":  cfg.Platform.EtcdQuotaWarningDays,
+		}).Info("Platform DNS, certificate and etcd checks enabled")
+	}
+
+	// Recommendations for pods that stay pending and unschedulable (optional)
+	if cfg.SchedulerPressure.Enabled && prometheusClient != nil {
+		schedulerPressure := integrations.NewSchedulerPressureAnalyzer(prometheusClient, k8sClients.Clientset, integrations.SchedulerPressureOptions{
+			PendingThreshold:       cfg.SchedulerPressure.PendingThreshold,
+			UnschedulableThreshold: cfg.SchedulerPressure.UnschedulableThreshold,
+			Window:                 cfg.SchedulerPressure.Window,
+		}, log)
+		prometheusClient.SetSchedulerPressure(schedulerPressure)
+		recommendationsHandler.SetSchedulerPressure(schedulerPressure)
+		log.WithFields(logrus.Fields{
+			"pending_threshold":       cfg.SchedulerPressure.PendingThreshold,
+			"unschedulable_threshold": cfg.SchedulerPressure.UnschedulableThreshold,
+			"window":                  cfg.SchedulerPressure.Window,
+		}).Info("Scheduler pressure recommendations enabled")
+	}
+
+	// Critical vulnerabilities of workload images from Quay (optional)
+	if cfg.ImageScan.QuayURL != "" {
+		quayClient, err := imagescan.NewQuayClient(cfg.ImageScan.QuayURL, cfg.ImageScan.QuayToken, cfg.HTTPTimeout, log)
+		if err != nil {
+			log.WithError(err).Fatal("Failed to create Quay client")
+		}
+		tlsAuditor.RegisterHTTPClient("quay", cfg.ImageScan.QuayURL, quayClient.HTTPClient())
+		imageScanner := imagescan.NewScanner(quayClient, k8sClients.Clientset, cfg.ImageScan.MinRestarts, cfg.ImageScan.CacheTTL, log)
+		remediationHandler.SetImageScanner(imageScanner)
+		recommendationsHandler.SetImageScanner(imageScanner)
+		log.WithFields(logrus.Fields{
+			"quay_url":     cfg.ImageScan.QuayURL,
+			"min_restarts": cfg.ImageScan.MinRestarts,
+			"cache_ttl":    cfg.ImageScan.CacheTTL,
+		}).Info("Image vulnerability scans enabled")
+	}
+
+	// Team ownership from namespace and workload annotations (optional)
+	if cfg.Ownership.Enabled {
+		ownerResolver := ownership.NewResolver(k8sClients.Clientset, cfg.Ownership.AnnotationPrefix, log)
+		remediationHandler.GetIncidentStore().SetOwnerResolver(ownerResolver)
+		recommendationsHandler.SetOwnerResolver(ownerResolver)
+		log.WithField("annotation_prefix", cfg.Ownership.AnnotationPrefix).Info("Incident and recommendation ownership enabled")
+	}
+	remediationHandler.SetRunbooks(runbookRegistry)
+
+	// Applying recommendations through remediation, tracked to their outcome
+	recommendationsHandler.SetRemediationHandler(remediationHandler)
+	applicationsCtx, stopApplications := context.WithCancel(context.Background())
+	defer stopApplications()
+	go recommendationsHandler.TrackApplications(applicationsCtx, v1.DefaultApplicationTrackingInterval)
+
+	// Similar past incidents and their remediation outcomes (optional)
+	if cfg.Similarity.Enabled {
+		remediationHandler.SetSimilarityIndex(knowledge.NewIndex(
+			remediationHandler.GetIncidentStore(), orchestrator, cfg.Similarity.Limit, cfg.Similarity.MinScore))
+		log.WithFields(logrus.Fields{
+			"limit":     cfg.Similarity.Limit,
+			"min_score": cfg.Similarity.MinScore,
+		}).Info("Similar incident lookup enabled")
+	}
+
+	// Runtime configuration managed through the admin API: remediation policies,
+	// silences, scan schedules, notification channels, playbooks and escalation ladders
+	var runtimeConfig *runtimeconfig.Store
+	var escalations *escalation.Tracker
+	if cfg.RuntimeConfig.Enabled {
+		runtimeConfig = runtimeconfig.NewStore("")
+		remediationHandler.SetPolicies(runtimeConfig)
+		escalations = escalation.NewTracker("")
+		remediationHandler.SetEscalation(escalations, runtimeConfig, escalation.NewScaler(k8sClients.Clientset))
+	}
+	v1.NewEscalationHandler(escalations, log).RegisterRoutes(router)
+
+	// Playbook execution in an external workflow engine (optional)
+	executionCtx, stopExecutions := context.WithCancel(context.Background())
+	defer stopExecutions()
+	executionRunner := initExecutionRunner(cfg, k8sClients.DynamicClient, runtimeConfig, log)
+	if executionRunner != nil {
+		remediationHandler.SetExecutionRunner(executionRunner)
+		executionRunner.Start(executionCtx, cfg.Execution.PollInterval)
+	}
+	executionHandler := v1.NewExecutionHandler(executionRunner, log)
+	executionHandler.SetObserverMode(cfg.ObserverMode)
+	executionHandler.SetFeatureFlags(featureFlags)
+	executionHandler.RegisterRoutes(router)
+	v1.NewBudgetHandler(orchestrator.Budget(), log).RegisterRoutes(router)
+
+	// LLM incident summaries (optional)
+	summarizerCtx, stopSummarizer := context.WithCancel(context.Background())
+	defer stopSummarizer()
+	if summarizer := initSummarizer(cfg, remediationHandler.GetIncidentStore(), prometheusClient, tlsAuditor, log); summarizer != nil {
+		remediationHandler.SetSummarizer(summarizer)
+		summarizer.Start(summarizerCtx, cfg.Summarizer.Timeout)
+	}
+
+	// Tenants onboarded through the tenant API (optional)
+	var tenants *tenant.Registry
+	if cfg.TenantsEnabled {
+		tenants = tenant.NewRegistry("", k8sClients.Clientset)
+		log.WithField("tenants", len(tenants.List())).Info("Tenant onboarding enabled")
+	}
+
+	// Outbound webhook notifications (optional)
+	notifierCtx, stopNotifier := context.WithCancel(context.Background())
+	defer stopNotifier()
+	webhookNotifier := initWebhookNotifier(cfg, runtimeConfig, tenants, tlsAuditor, log)
+	if webhookNotifier != nil {
+		webhookNotifier.Start(notifierCtx, remediationHandler.GetIncidentStore())
+	}
+
+	// Incidents and remediations as Grafana annotations and Kubernetes Events (optional)
+	if publisher := initAnnotationPublisher(cfg, k8sClients.Clientset, tlsAuditor, log); publisher != nil {
+		publisher.Start(notifierCtx, remediationHandler.GetIncidentStore())
+		orchestrator.SetEventListener(publisher.WorkflowEvent)
+	}
+
+	// Inputs and model output of analyses, kept with the incidents raised from them (optional)
+	var artifactStore *storage.ArtifactStore
+	if cfg.Artifacts.Enabled {
+		artifactStore = storage.NewArtifactStore("", cfg.Artifacts.PendingTTL, cfg.Artifacts.PendingLimit)
+		artifactStore.Track(remediationHandler.GetIncidentStore())
+		remediationHandler.SetArtifactStore(artifactStore)
+		log.WithFields(logrus.Fields{
+			"pending_ttl":   cfg.Artifacts.PendingTTL,
+			"pending_limit": cfg.Artifacts.PendingLimit,
+		}).Info("Analysis artifacts enabled")
+	}
+
+	// Anomaly detection over the engine's own metrics (optional)
+	selfMonitorCtx, stopSelfMonitor := context.WithCancel(context.Background())
+	defer stopSelfMonitor()
+	if monitor := initSelfMonitor(selfMonitorCtx, cfg, remediationHandler.GetIncidentStore(), log); monitor != nil {
+		healthHandler.SetSelfMonitor(monitor)
+	}
+
 	// API v1 routes
-	apiV1 := router.PathPrefix("/api/v1").Subrouter()
+	apiV1 := apiVersions.Group(router, versioning.V1)
 
 	// Health check
 	apiV1.Handle("/health", healthHandler).Methods("GET")
+	apiV1.HandleFunc("/health/dependencies", healthHandler.Dependencies).Methods("GET")
+	apiV1.HandleFunc("/health/self", healthHandler.SelfHealth).Methods("GET")
 
 	// Remediation endpoints
 	apiV1.HandleFunc("/remediation/trigger", remediationHandler.TriggerRemediation).Methods("POST")
 	apiV1.HandleFunc("/workflows/{id}", remediationHandler.GetWorkflow).Methods("GET")
+	apiV1.HandleFunc("/workflows/{id}/events", remediationHandler.GetWorkflowEvents).Methods("GET")
 	apiV1.HandleFunc("/incidents", remediationHandler.ListIncidents).Methods("GET")
 	apiV1.HandleFunc("/incidents", remediationHandler.CreateIncident).Methods("POST")
+	apiV1.HandleFunc("/incidents/tag-stats", remediationHandler.IncidentTagStats).Methods("GET")
+	apiV1.HandleFunc("/incidents/{id}/tags", remediationHandler.UpdateIncidentTags).Methods("PATCH")
+	apiV1.HandleFunc("/incidents/{id}/summary", remediationHandler.SummarizeIncident).Methods("POST")
+	apiV1.HandleFunc("/incidents/{id}/similar", remediationHandler.SimilarIncidents).Methods("GET")
+	apiV1.HandleFunc("/incidents/{id}/artifacts", remediationHandler.IncidentArtifacts).Methods("GET")
+
+	// Alertmanager webhook receiver with deduplication and flood control
+	alertIngester := alerting.NewIngester(remediationHandler.GetIncidentStore(), cfg.AlertIngest.DedupWindow, cfg.AlertIngest.FloodLimit, log)
+	alertIngester.SetTagLabels(cfg.IncidentTags.AlertLabels)
+	v1.NewAlertWebhookHandler(alertIngester, log).RegisterRoutes(router)
 
 	// Recommendations endpoint (ML-powered remediation predictions)
 	apiV1.HandleFunc("/recommendations", recommendationsHandler.GetRecommendations).Methods("POST")
+	apiV1.HandleFunc("/recommendations/outcomes", recommendationsHandler.GetActionOutcomes).Methods("GET")
+	apiV1.HandleFunc("/recommendations/outcomes", recommendationsHandler.RecordActionOutcome).Methods("POST")
+	apiV1.HandleFunc("/recommendations/{id}/apply", recommendationsHandler.ApplyRecommendation).Methods("POST")
+	apiV1.HandleFunc("/recommendations/{id}/application", recommendationsHandler.GetRecommendationApplication).Methods("GET")
 	log.Info("Recommendations API endpoint registered: POST /api/v1/recommendations")
 
 	// Prediction endpoint (time-specific resource predictions)
 	predictionHandler.RegisterRoutes(router)
-	log.Info("Prediction API endpoint registered: POST /api/v1/predict")
+	log.Info("Prediction API endpoints registered: POST /api/v1/predict, /api/v1/predict/backtest, GET /api/v1/predict/accuracy")
+
+	// Runbook registry and knowledge-base search
+	v1.NewRunbookHandler(runbookRegistry, log).RegisterRoutes(router)
+
+	// Webhook delivery status
+	if webhookNotifier != nil {
+		webhookNotifier.RegisterRoutes(router)
+	}
 
 	// Detection endpoints
 	detectionHandler.RegisterRoutes(router)
@@ -217,15 +596,277 @@ func main() {
 	coordinationHandler.RegisterRoutes(router)
 	log.Info("Coordination API endpoints registered")
 
+	// Data retention: purge expired incidents, predictions and anomalies
+	retentionCtx, stopPurger := context.WithCancel(context.Background())
+	defer stopPurger()
+	purger := retention.NewPurger(log)
+	purger.Add(retention.IncidentRule(remediationHandler.GetIncidentStore(), cfg.Retention.Incidents))
+	if predictionTracker != nil {
+		purger.Add(retention.PredictionRule(predictionTracker.Store(), cfg.Retention.Predictions))
+	}
+	featureHistory := initFeatureHistory(cfg, log)
+	if featureHistory != nil {
+		purger.Add(retention.FeatureHistoryRule(featureHistory, cfg.Anomaly.FeatureHistoryRetention))
+	}
+	anomalyRecords := initAnomalyRecords(cfg, log)
+	if anomalyRecords != nil {
+		if engineClock != nil {
+			anomalyRecords.SetClock(engineClock.Now)
+		}
+		anomalyRecords.Start(retentionCtx, storage.DefaultAnomalyFlushInterval)
+		purger.Add(retention.AnomalyRule(anomalyRecords, cfg.Retention.Anomalies))
+		remediationHandler.SetAnomalyStore(anomalyRecords)
+	}
+	purger.Start(retentionCtx, cfg.Retention.PurgeInterval)
+
+	// Scoped API tokens for integrations, managed through the admin API
+	var apiTokens *apitoken.Store
+	if cfg.APITokens.Enabled {
+		apiTokens = apitoken.NewStore("")
+		if !cfg.Admin.Enabled() {
+			log.Warn("ADMIN_TOKEN not set, API tokens cannot be created or revoked")
+		}
+	}
+
+	// Dry-run validation of remediation policies and playbooks
+	v1.NewConfigValidationHandler(runtimeConfig, log).RegisterRoutes(router)
+
+	// Admin API: backup, restore and purge of engine state (optional)
+	if cfg.Admin.Enabled() {
+		backups := backup.NewManager(Version, cfg.Admin.BackupTimeout, log)
+		tlsAuditor.RegisterHTTPClient("backup-storage", "", backups.HTTPClient())
+		backups.Register(backup.NewIncidentSection(remediationHandler.GetIncidentStore()))
+		if predictionTracker != nil {
+			backups.Register(backup.NewPredictionSection(predictionTracker.Store()))
+		}
+		if outcomes != nil {
+			backups.Register(backup.NewActionOutcomeSection(outcomes))
+		}
+		if anomalyRecords != nil {
+			backups.Register(backup.NewAnomalySection(anomalyRecords))
+		}
+		if runtimeConfig != nil {
+			backups.Register(backup.NewRuntimeConfigSection(runtimeConfig))
+		}
+		if tenants != nil {
+			backups.Register(backup.NewTenantSection(tenants))
+		}
+		// Without a learner only the baselines imported from another cluster are backed up
+		backups.Register(baseline.NewAPIServerSection(nil, baselines, log))
+		adminHandler := v1.NewAdminHandler(backups, cfg.Admin.Token, log)
+		adminHandler.SetPurger(purger)
+		adminHandler.SetBaselines(initBaselineExport(cfg, k8sClients.Clientset, prometheusClient, predictionTracker, outcomes, baselines, log))
+		adminHandler.SetHardeningOptions(hardeningOptions(cfg, log))
+		adminHandler.SetClock(engineClock)
+		if apiTokens != nil {
+			adminHandler.SetTokenStore(apiTokens)
+		}
+		if runtimeConfig != nil {
+			adminHandler.SetConfigStore(runtimeConfig)
+		}
+		adminHandler.RegisterRoutes(router)
+	} else {
+		log.Info("ADMIN_TOKEN not set, admin API disabled")
+	}
+
+	// Tenant onboarding and offboarding
+	tenantHandler := v1.NewTenantHandler(tenants, log)
+	tenantHandler.SetPurger(purger)
+	tenantHandler.RegisterRoutes(router)
+
+	// Upgrade-aware mode status
+	if upgradeMonitor != nil {
+		v1.NewUpgradeHandler(upgradeMonitor, log).RegisterRoutes(router)
+	}
+
+	// Feature flag status
+	v1.NewFeaturesHandler(featureFlags, log).RegisterRoutes(router)
+
 	// Capacity analysis endpoints (Issue #27)
 	capacityHandler := v1.NewCapacityHandler(k8sClients.Clientset, prometheusClient, log)
+	capacityHandler.SetClusterOperatorClient(clusterOperatorClient)
+	if engineClock != nil {
+		capacityHandler.SetClock(engineClock.Now)
+	}
 	capacityHandler.RegisterRoutes(router)
-	log.Info("Capacity API endpoints registered: /api/v1/capacity/namespace/{namespace}, /api/v1/capacity/cluster")
+	log.Info("Capacity API endpoints registered: /api/v1/capacity/namespace/{namespace}, /api/v1/capacity/cluster, /api/v1/capacity/nodes, /api/v1/rightsizing")
+
+	// Named, scoped metrics queries for the console plugin
+	if cfg.MetricsQuery.Enabled {
+		metricsQueryHandler := v1.NewMetricsQueryHandler(prometheusClient, v1.MetricsQueryOptions{
+			RateLimit: cfg.MetricsQuery.RateLimit,
+			Burst:     cfg.MetricsQuery.Burst,
+			MaxRange:  cfg.MetricsQuery.MaxRange,
+		}, log)
+		if engineClock != nil {
+			metricsQueryHandler.SetClock(engineClock.Now)
+		}
+		metricsQueryHandler.RegisterRoutes(router)
+	} else {
+		log.Info("Metrics query API disabled")
+	}
+
+	// Job and CronJob workload analysis
+	batchHandler := v1.NewBatchHandler(k8sClients.Clientset, prometheusClient, log)
+	if engineClock != nil {
+		batchHandler.SetClock(engineClock.Now)
+	}
+	batchHandler.RegisterRoutes(router)
+
+	// Email alerts and digests (optional)
+	if emailNotifier := initEmailNotifier(cfg, remediationHandler.GetIncidentStore(), tlsAuditor, log); emailNotifier != nil {
+		emailNotifier.SetCapacityReporter(capacityHandler)
+		emailNotifier.SetWorkflowLister(orchestrator)
+		if runtimeConfig != nil {
+			emailNotifier.SetSilencer(runtimeConfig.Silenced)
+		}
+		emailNotifier.Start(notifierCtx)
+		emailNotifier.RegisterRoutes(router)
+	}
 
 	// Anomaly analysis endpoints (Issue #30)
 	anomalyHandler := initAnomalyHandler(kserveProxyHandler, prometheusClient, log)
+	anomalyHandler.SetRolloutDetector(detector.NewRolloutDetector(k8sClients.Clientset, log))
+	anomalyHandler.SetMachineConfigUpdateDetector(mcpUpdateDetector)
+	anomalyHandler.SetFeatureFlags(featureFlags)
+	if engineClock != nil {
+		anomalyHandler.SetClock(engineClock.Now)
+	}
+	if artifactStore != nil {
+		anomalyHandler.SetArtifactStore(artifactStore)
+	}
+	if anomalyRecords != nil {
+		anomalyHandler.SetAnomalyStore(anomalyRecords)
+	}
+	if redactor != nil {
+		anomalyHandler.SetRedactor(redactor)
+	}
+	if upgradeMonitor != nil {
+		anomalyHandler.SetUpgradeMonitor(upgradeMonitor)
+	}
+	if tenants != nil {
+		anomalyHandler.SetTenants(tenants)
+	}
+	if scopeDefaults := initScopeDefaults(cfg, k8sClients.Clientset, kserveProxyHandler, tenants, log); scopeDefaults != nil {
+		anomalyHandler.SetScopeDefaults(scopeDefaults)
+	}
+	if calendars := initBusinessCalendars(cfg, log); calendars != nil {
+		anomalyHandler.SetBusinessCalendars(calendars)
+	}
+	if postFilters := initPostFilters(cfg, k8sClients.Clientset, log); postFilters != nil {
+		anomalyHandler.SetPostFilters(postFilters)
+	}
+	if pipeline := initScoringPipeline(cfg, log); pipeline != nil {
+		anomalyHandler.SetScoringPipeline(pipeline)
+	}
+
+	// Severity and priority of anomalies and incidents
+	severityMatrix := initSeverityMatrix(cfg, log)
+	severityTiers := anomaly.NamespaceTierLookup(k8sClients.Clientset, severityMatrix.TierLabel, log)
+	if tenants != nil {
+		severityTiers = tenants.TierLookup(severityTiers)
+	}
+	anomalyHandler.SetSeverityMatrix(severityMatrix, severityTiers)
+	remediationHandler.GetIncidentStore().SetPrioritizer(severity.NewIncidentPrioritizer(severityMatrix, severityTiers))
+	if cfg.Anomaly.ExcludeInactivePods {
+		anomalyHandler.SetScopeResolver(anomaly.NewScopeResolver(k8sClients.Clientset, cfg.Anomaly.MaxScopePods))
+	}
+	if len(histogramFeatures) > 0 {
+		anomalyHandler.SetHistogramFeatures(histogramFeatures, cfg.Anomaly.HistogramModels)
+	}
+	if cfg.Anomaly.RouteProbes {
+		anomalyHandler.SetRouteProbes(integrations.NewRouteLister(k8sClients.Clientset, k8sClients.DynamicClient, log), cfg.Anomaly.RouteProbeModels)
+		log.WithField("models", cfg.Anomaly.RouteProbeModels).Info("Anomaly route probes enabled")
+	}
+	anomalyHandler.SetControlPlaneModel(cfg.Anomaly.ControlPlaneModel)
+	sloCtx, stopSLO := context.WithCancel(context.Background())
+	defer stopSLO()
+	if tracker := initAnalysisSLO(sloCtx, cfg, log); tracker != nil {
+		anomalyHandler.SetLatencySLO(tracker)
+	}
+	historyCtx, stopFeatureHistory := context.WithCancel(context.Background())
+	defer stopFeatureHistory()
+	if featureHistory != nil {
+		var namespaces v1.FeatureHistoryNamespaces
+		if tenants != nil {
+			namespaces = tenants
+		}
+		anomalyHandler.SetFeatureHistory(featureHistory, namespaces)
+		anomalyHandler.StartFeatureHistory(historyCtx)
+	}
 	anomalyHandler.RegisterRoutes(router)
-	log.Info("Anomaly analysis API endpoint registered: POST /api/v1/anomalies/analyze")
+	log.Info("Anomaly analysis API endpoints registered: POST /api/v1/anomalies/analyze, POST /api/v1/anomalies/analyze/control-plane, GET /api/v1/anomalies/recording-rules, GET /api/v1/anomalies/history, POST /api/v1/anomalies/rescore")
+
+	// Composite cluster health score for dashboards
+	healthScoreHandler := v1.NewHealthScoreHandler(v1.HealthScoreWeights{
+		ControlPlane: cfg.HealthScore.ControlPlaneWeight,
+		Anomalies:    cfg.HealthScore.AnomalyWeight,
+		Capacity:     cfg.HealthScore.CapacityWeight,
+		Incidents:    cfg.HealthScore.IncidentWeight,
+	}, remediationHandler.GetIncidentStore(), log)
+	healthScoreHandler.SetInfrastructureReporter(capacityHandler)
+	healthScoreHandler.SetCapacityReporter(capacityHandler)
+	healthScoreHandler.SetAnomalyAnalyzer(anomalyHandler)
+	if engineClock != nil {
+		healthScoreHandler.SetClock(engineClock.Now)
+	}
+	apiV1.HandleFunc("/health/score", healthScoreHandler.GetHealthScore).Methods("GET")
+
+	// Scheduled anomaly scans delivered to subscriber webhooks
+	if cfg.Anomaly.SubscriptionsEnabled {
+		subscriptionNotifier := webhookNotifier
+		if subscriptionNotifier == nil {
+			subscriptionNotifier = initSubscriptionNotifier(cfg, tlsAuditor, log)
+			subscriptionNotifier.RegisterRoutes(router)
+		}
+		subscriptions := v1.NewAnomalySubscriptions(anomalyHandler, subscriptionNotifier, v1.AnomalySubscriptionOptions{
+			MinInterval:      cfg.Anomaly.SubscriptionMinInterval,
+			MaxSubscriptions: cfg.Anomaly.MaxSubscriptions,
+		}, log)
+		if tenants != nil {
+			subscriptions.SetTenants(tenants)
+		}
+		subscriptions.RegisterRoutes(router)
+		subscriptions.Start(notifierCtx)
+	}
+
+	// Background anomaly scans of configured or onboarded namespaces, raising incidents
+	// between API requests
+	scanCtx, stopScanner := context.WithCancel(context.Background())
+	defer stopScanner()
+	if len(cfg.Anomaly.ScanNamespaces) > 0 || tenants != nil {
+		incidentStore := remediationHandler.GetIncidentStore()
+		scanner := v1.NewAnomalyScanner(anomalyHandler, incidentStore,
+			anomaly.NewPersistenceTracker(cfg.Anomaly.PersistenceEvaluations, cfg.Anomaly.PersistenceOverrides),
+			anomaly.NewAutoResolver(incidentStore, anomaly.NewResolutionTracker(cfg.Anomaly.ClearThreshold, cfg.Anomaly.ClearEvaluations), log),
+			v1.AnomalyScannerOptions{
+				Namespaces: cfg.Anomaly.ScanNamespaces,
+				Interval:   cfg.Anomaly.ScanInterval,
+			}, log)
+		if tenants != nil {
+			scanner.SetTenants(tenants)
+		}
+		scanner.Start(scanCtx)
+	}
+
+	// MCP tools for LLM-based ops assistants; remediation requires human approval via REST
+	if cfg.MCP.Enabled {
+		approvals := mcp.NewApprovalStore(cfg.MCP.ApprovalTTL, log)
+		approvals.SetAdminToken(cfg.Admin.Token)
+		approvals.RegisterRoutes(router)
+		mcp.NewServer(Version, log,
+			mcp.AnalyzeAnomaliesTool(anomalyHandler),
+			mcp.GetRecommendationsTool(recommendationsHandler),
+			mcp.GetCapacityForecastTool(capacityHandler),
+			mcp.ExecuteRemediationWithApprovalTool(remediationHandler, approvals),
+		).RegisterRoutes(router)
+	} else {
+		log.Info("MCP server disabled")
+	}
+
+	// API v2 routes share handler logic with v1 and only differ in response schemas
+	apiV2 := apiVersions.Group(router, versioning.V2)
+	v2.NewAnomalyHandler(anomalyHandler, log).RegisterRoutes(apiV2)
 
 	// KServe proxy endpoints (ADR-039, ADR-040)
 	if kserveProxyHandler != nil {
@@ -233,6 +874,9 @@ func main() {
 		log.Info("✅ KServe proxy endpoints registered: /api/v1/detect, /api/v1/models")
 	}
 
+	// Outbound TLS configuration status
+	v1.NewSecurityHandler(tlsAuditor, log).RegisterRoutes(router)
+
 	// Add simple /health endpoint for backward compatibility with deployments
 	// This provides a lightweight health check for liveness/readiness probes
 	router.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
@@ -241,6 +885,7 @@ func main() {
 		response := map[string]string{
 			"status":  "ok",
 			"version": Version,
+			"mode":    observer.Mode(cfg.ObserverMode),
 		}
 		if err := json.NewEncoder(w).Encode(response); err != nil {
 			log.WithError(err).Error("Failed to encode simple health response")
@@ -248,6 +893,12 @@ func main() {
 	}).Methods("GET")
 	log.Info("Simple /health endpoint registered for backward compatibility")
 
+	// Startup self-check of outbound TLS settings
+	if report := tlsAuditor.LogFindings(); !report.Secure && cfg.Security.StrictTLS {
+		log.WithField("high", report.Summary[security.SeverityHigh]).
+			Fatal("Insecure outbound TLS configuration and SECURITY_STRICT_TLS is set - cannot start")
+	}
+
 	// Metrics server (separate port)
 	metricsRouter := mux.NewRouter()
 	metricsRouter.Handle("/metrics", promhttp.Handler())
@@ -269,29 +920,104 @@ func main() {
 		}
 	}()
 
-	// Start main API server
+	// Start main API server, over TLS or mutual TLS when configured
+	var handler http.Handler = router
+	if apiTokens != nil {
+		handler = apitoken.Middleware(apiTokens, cfg.APITokens.Required, log)(handler)
+		log.WithField("required", cfg.APITokens.Required).Info("API token scopes enforced")
+	}
+	handler = apiVersions.Negotiate(handler)
+	tlsServer := initServerTLS(cfg, log)
+	if tlsServer != nil && tlsServer.MutualTLS() {
+		handler = tlsServer.RequireClientCert("/health", "/api/v1/health")(handler)
+	}
+	// Load shedding runs first so an overloaded engine does the least work per request
+	shedCtx, stopShedder := context.WithCancel(context.Background())
+	defer stopShedder()
+	if shedder := initLoadShedder(shedCtx, cfg, log); shedder != nil {
+		handler = shedder.Middleware(handler)
+	}
 	server := &http.Server{
 		Addr:         fmt.Sprintf(":%d", cfg.Port),
-		Handler:      router,
+		Handler:      handler,
 		ReadTimeout:  15 * time.Second,
 		WriteTimeout: 15 * time.Second,
 		IdleTimeout:  60 * time.Second,
 	}
+	if tlsServer != nil {
+		server.TLSConfig = tlsServer.TLSConfig()
+	}
 
 	// Start server in goroutine
 	go func() {
-		log.WithField("port", cfg.Port).Info("Starting API server")
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		log.WithFields(logrus.Fields{
+			"port": cfg.Port,
+			"tls":  server.TLSConfig != nil,
+		}).Info("Starting API server")
+		var err error
+		if server.TLSConfig != nil {
+			// Certificates come from TLSConfig.GetCertificate
+			err = server.ListenAndServeTLS("", "")
+		} else {
+			err = server.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
 			log.WithError(err).Fatal("API server failed")
 		}
 	}()
 
+	// Start gRPC API server for machine clients (shares handler logic with REST), with
+	// the API server's TLS and, under mutual TLS, a client certificate on every call
+	var grpcServer *grpc.Server
+	if cfg.GRPCPort != 0 {
+		grpcOpts := grpcapi.ServerOptions{
+			Tokens:         apiTokens,
+			TokensRequired: cfg.APITokens.Required,
+		}
+		if tlsServer != nil {
+			grpcOpts.TLS = tlsServer.StrictTLSConfig()
+		}
+		grpcServer = grpcapi.NewGRPCServer(grpcapi.NewServer(
+			anomalyHandler,
+			predictionHandler,
+			recommendationsHandler,
+			remediationHandler.GetIncidentStore(),
+			log,
+		), grpcOpts, log)
+
+		grpcListener, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.GRPCPort))
+		if err != nil {
+			log.WithError(err).Fatal("Failed to listen on gRPC port")
+		}
+
+		go func() {
+			log.WithFields(logrus.Fields{
+				"port":       cfg.GRPCPort,
+				"tls":        grpcOpts.TLS != nil,
+				"mutual_tls": tlsServer != nil && tlsServer.MutualTLS(),
+			}).Info("Starting gRPC API server")
+			if err := grpcServer.Serve(grpcListener); err != nil {
+				log.WithError(err).Fatal("gRPC API server failed")
+			}
+		}()
+	} else {
+		log.Info("GRPC_PORT is 0, gRPC API disabled")
+	}
+
 	// Wait for interrupt signal
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
 	log.Info("Shutting down servers...")
+	stopSummarizer()
+	stopNotifier()
+	stopTracker()
+	if anomalyRecords != nil {
+		if err := anomalyRecords.Flush(); err != nil {
+			log.WithError(err).Error("Failed to persist detected anomalies")
+		}
+	}
 
 	// Graceful shutdown
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -305,11 +1031,52 @@ func main() {
 		log.WithError(err).Error("Metrics server shutdown error")
 	}
 
+	if grpcServer != nil {
+		stopGRPCServer(ctx, grpcServer)
+	}
+
 	log.Info("Servers stopped")
 }
 
+// stopGRPCServer stops the gRPC server gracefully, forcing it closed if open streams
+// (e.g. WatchIncidents) are still running when ctx expires
+func stopGRPCServer(ctx context.Context, grpcServer *grpc.Server) {
+	stopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		grpcServer.Stop()
+	}
+}
+
+// watchKServeModels refreshes the registered KServe models on SIGHUP and when
+// KSERVE_MODELS_FILE changes, until ctx is cancelled
+func watchKServeModels(ctx context.Context, client *kserve.ProxyClient, log *logrus.Logger) {
+	client.WatchModels(ctx, kserve.DefaultModelsFileInterval)
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		defer signal.Stop(hup)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-hup:
+				log.Info("SIGHUP received, refreshing KServe models")
+				_, _ = client.RefreshModels(kserve.TriggerSignal) // failures are logged and keep the registered models
+			}
+		}
+	}()
+}
+
 // initKServeProxy initializes the KServe proxy client if enabled (ADR-039, ADR-040)
-func initKServeProxy(cfg *config.Config, log *logrus.Logger) *v1.KServeProxyHandler {
+func initKServeProxy(cfg *config.Config, auditor *security.Auditor, log *logrus.Logger) *v1.KServeProxyHandler {
 	if !cfg.KServe.Enabled {
 		log.Info("KServe integration disabled")
 		return nil
@@ -318,6 +1085,13 @@ func initKServeProxy(cfg *config.Config, log *logrus.Logger) *v1.KServeProxyHand
 	kserveProxyConfig := kserve.ProxyConfig{
 		Namespace: cfg.KServe.Namespace,
 		Timeout:   cfg.KServe.Timeout,
+		AdaptiveTimeout: kserve.AdaptiveTimeoutConfig{
+			Enabled: cfg.KServe.AdaptiveTimeout.Enabled,
+			Factor:  cfg.KServe.AdaptiveTimeout.Factor,
+			Min:     cfg.KServe.AdaptiveTimeout.Min,
+			Max:     cfg.KServe.AdaptiveTimeout.Max,
+		},
+		ModelsFile: cfg.KServe.ModelsFile,
 	}
 
 	kserveProxyClient, err := kserve.NewProxyClient(kserveProxyConfig, log)
@@ -326,7 +1100,48 @@ func initKServeProxy(cfg *config.Config, log *logrus.Logger) *v1.KServeProxyHand
 		return nil
 	}
 
+	if cfg.KServe.RolloutFile != "" {
+		rollouts, err := kserve.LoadRollouts(cfg.KServe.RolloutFile)
+		if err != nil {
+			log.WithError(err).Fatal("Invalid KSERVE_MODEL_ROLLOUT_FILE")
+		}
+		for _, name := range rollouts.Models() {
+			if _, ok := kserveProxyClient.GetModel(name); !ok {
+				log.WithField("model", name).Warn("Model rollout names a model without a KSERVE_<MODEL>_SERVICE, rollout is ignored")
+			}
+		}
+		if cfg.Deterministic.Enabled() {
+			rollouts.SetDeterministic(cfg.Deterministic.Start(), clock.Seeded(int64(cfg.Deterministic.Seed)))
+		}
+		kserveProxyClient.SetRollouts(rollouts)
+		log.WithField("models", rollouts.Models()).Info("KServe model rollouts loaded")
+	}
+
+	for _, name := range kserveProxyClient.ListModels() {
+		if model, ok := kserveProxyClient.GetModel(name); ok {
+			auditor.RegisterHTTPClient("kserve "+name, model.URL, kserveProxyClient.HTTPClient())
+		}
+		for version, model := range kserveProxyClient.VersionModels(name) {
+			auditor.RegisterHTTPClient("kserve "+name+" "+version, model.URL, kserveProxyClient.HTTPClient())
+		}
+	}
+	kserveProxyClient.OnModelsChanged(func(changes []kserve.ModelChange) {
+		for _, change := range changes {
+			if change.Action == kserve.ModelAdded {
+				auditor.RegisterHTTPClient("kserve "+change.Model, change.URL, kserveProxyClient.HTTPClient())
+			}
+		}
+	})
+
 	handler := v1.NewKServeProxyHandler(kserveProxyClient, log)
+	if infer := cfg.KServe.Infer; infer.Enabled {
+		handler.SetInferOptions(v1.InferOptions{
+			Token:        infer.Token,
+			RateLimit:    infer.RateLimit,
+			Burst:        infer.Burst,
+			MaxBodyBytes: int64(infer.MaxBodyBytes),
+		})
+	}
 	log.WithFields(logrus.Fields{
 		"models":    kserveProxyClient.ListModels(),
 		"namespace": cfg.KServe.Namespace,
@@ -335,11 +1150,185 @@ func initKServeProxy(cfg *config.Config, log *logrus.Logger) *v1.KServeProxyHand
 	return handler
 }
 
+// initServerTLS loads the API server certificate and mutual TLS settings if
+// TLS_CERT_FILE is set. Invalid certificates, CA bundles or CRLs are fatal.
+func initServerTLS(cfg *config.Config, log *logrus.Logger) *mtls.Server {
+	if !cfg.ServerTLS.Enabled() {
+		log.Info("TLS_CERT_FILE not set, API server uses plain HTTP")
+		return nil
+	}
+
+	server, err := mtls.New(mtls.Options{
+		CertFile:       cfg.ServerTLS.CertFile,
+		KeyFile:        cfg.ServerTLS.KeyFile,
+		ClientCAFile:   cfg.ServerTLS.ClientCAFile,
+		CRLFile:        cfg.ServerTLS.CRLFile,
+		OCSP:           cfg.ServerTLS.OCSP,
+		OCSPFailOpen:   cfg.ServerTLS.OCSPFailOpen,
+		AllowedClients: cfg.ServerTLS.AllowedClients,
+	}, log)
+	if err != nil {
+		log.WithError(err).Fatal("Failed to configure API server TLS")
+	}
+
+	log.WithFields(logrus.Fields{
+		"mutual_tls":      server.MutualTLS(),
+		"crl":             cfg.ServerTLS.CRLFile != "",
+		"ocsp":            cfg.ServerTLS.OCSP,
+		"allowed_clients": len(cfg.ServerTLS.AllowedClients),
+	}).Info("API server TLS configured")
+	return server
+}
+
+// requestTimeouts gives endpoints that query long metric ranges, call models or move
+// backups more time than cfg.RequestTimeout
+func requestTimeouts(cfg *config.Config) []middleware.RouteTimeout {
+	analysis := cfg.AnalysisRequestTimeout
+	backup := max(cfg.Admin.BackupTimeout+time.Minute, cfg.RequestTimeout)
+	return []middleware.RouteTimeout{
+		{Prefix: "/admin/backup", Timeout: backup},
+		{Prefix: "/admin/restore", Timeout: backup},
+		{Prefix: "/anomalies/analyze", Timeout: analysis},
+		{Prefix: "/predict", Timeout: analysis},
+		{Prefix: "/recommendations", Timeout: analysis},
+		{Prefix: "/detect", Timeout: analysis},
+		{Prefix: "/models", Suffix: "/infer", Timeout: analysis},
+		{Prefix: "/capacity", Timeout: analysis},
+		{Prefix: "/health/score", Timeout: analysis},
+		{Prefix: "/rightsizing", Timeout: analysis},
+		{Prefix: "/batch", Timeout: analysis},
+		{Prefix: "/coordination/trigger", Timeout: analysis},
+		{Prefix: "/incidents", Suffix: "/summary", Timeout: analysis},
+	}
+}
+
+// initLoadShedder starts sampling the engine's resources if LOAD_SHED_ENABLED is set
+func initLoadShedder(ctx context.Context, cfg *config.Config, log *logrus.Logger) *loadshed.Shedder {
+	if !cfg.LoadShed.Enabled {
+		log.Info("Load shedding disabled")
+		return nil
+	}
+
+	shedder := loadshed.New(loadshed.Options{
+		Interval:           cfg.LoadShed.Interval,
+		CPUThreshold:       cfg.LoadShed.CPUThreshold,
+		MemoryThreshold:    cfg.LoadShed.MemoryThreshold,
+		GoroutineThreshold: cfg.LoadShed.GoroutineThreshold,
+		RetryAfter:         cfg.LoadShed.RetryAfter,
+	}, log)
+	shedder.Start(ctx)
+
+	status := shedder.Status()
+	log.WithFields(logrus.Fields{
+		"cpu_threshold":       cfg.LoadShed.CPUThreshold,
+		"memory_threshold":    cfg.LoadShed.MemoryThreshold,
+		"memory_limit_bytes":  status.MemoryLimitBytes,
+		"goroutine_threshold": cfg.LoadShed.GoroutineThreshold,
+	}).Info("Load shedding enabled")
+	return shedder
+}
+
+// initSelfMonitor starts sampling the engine's own metrics if SELF_MONITOR_ENABLED is set
+func initSelfMonitor(ctx context.Context, cfg *config.Config, incidents *storage.IncidentStore, log *logrus.Logger) *selfmonitor.Monitor {
+	if !cfg.SelfMonitor.Enabled {
+		log.Info("Self-monitoring disabled")
+		return nil
+	}
+
+	monitor := selfmonitor.New(selfmonitor.Options{
+		Interval:            cfg.SelfMonitor.Interval,
+		ErrorRateThreshold:  cfg.SelfMonitor.ErrorRateThreshold,
+		QueueDepthThreshold: cfg.SelfMonitor.QueueDepthThreshold,
+		CacheCollapseRatio:  cfg.SelfMonitor.CacheCollapseRatio,
+		ResolveAfter:        cfg.SelfMonitor.ResolveAfter,
+	}, prometheus.DefaultGatherer, incidents, log)
+	monitor.Start(ctx)
+
+	log.WithFields(logrus.Fields{
+		"interval":              cfg.SelfMonitor.Interval,
+		"error_rate_threshold":  cfg.SelfMonitor.ErrorRateThreshold,
+		"queue_depth_threshold": cfg.SelfMonitor.QueueDepthThreshold,
+		"cache_collapse_ratio":  cfg.SelfMonitor.CacheCollapseRatio,
+	}).Info("Self-monitoring enabled")
+	return monitor
+}
+
+// initAnalysisSLO starts tracking the latency objective of analysis requests if
+// ANALYSIS_SLO_ENABLED is set
+func initAnalysisSLO(ctx context.Context, cfg *config.Config, log *logrus.Logger) *slo.Tracker {
+	if !cfg.AnalysisSLO.Enabled {
+		log.Info("Analysis latency SLO disabled")
+		return nil
+	}
+
+	// The mode was validated with the configuration
+	mode, _ := slo.ParseFastPathMode(cfg.AnalysisSLO.FastPath)
+	tracker := slo.New(slo.Options{
+		Latency:          cfg.AnalysisSLO.Latency,
+		Objective:        cfg.AnalysisSLO.Objective,
+		Window:           cfg.AnalysisSLO.Window,
+		AlertBurnRate:    cfg.AnalysisSLO.AlertBurnRate,
+		FastPath:         mode,
+		FastPathBurnRate: cfg.AnalysisSLO.FastPathBurnRate,
+	}, log)
+	tracker.Start(ctx)
+
+	log.WithFields(logrus.Fields{
+		"latency":         cfg.AnalysisSLO.Latency,
+		"objective":       cfg.AnalysisSLO.Objective,
+		"window":          cfg.AnalysisSLO.Window,
+		"alert_burn_rate": cfg.AnalysisSLO.AlertBurnRate,
+		"fast_path":       mode,
+	}).Info("Analysis latency SLO enabled")
+	return tracker
+}
+
+// initClock returns the fixed clock of deterministic mode if DETERMINISTIC_TIME is
+// set, or nil to run the engine on the wall clock
+func initClock(cfg *config.Config, log *logrus.Logger) *clock.Manual {
+	if !cfg.Deterministic.Enabled() {
+		return nil
+	}
+
+	log.WithFields(logrus.Fields{
+		"time": cfg.Deterministic.Start(),
+		"seed": cfg.Deterministic.Seed,
+	}).Warn("Deterministic mode: the engine's clock stands still until advanced through /api/v1/admin/clock; do not use in production")
+	return clock.NewManual(cfg.Deterministic.Start())
+}
+
+// initFeatureFlags loads the feature flags from FEATURE_FLAGS_FILE and FEATURE_FLAGS.
+// Invalid flags are fatal: a typo must not silently enable a capability.
+func initFeatureFlags(cfg *config.Config, log *logrus.Logger) *featureflag.Flags {
+	flags, err := featureflag.Load(cfg.FeatureFlags.File, cfg.FeatureFlags.Flags)
+	if err != nil {
+		log.WithError(err).Fatal("Invalid feature flags")
+	}
+
+	disabled := make([]string, 0)
+	overridden := make([]string, 0)
+	for _, status := range flags.List() {
+		if !status.Enabled {
+			disabled = append(disabled, status.Name)
+		}
+		if len(status.Namespaces) > 0 {
+			overridden = append(overridden, status.Name)
+		}
+	}
+	log.WithFields(logrus.Fields{
+		"file":                   cfg.FeatureFlags.File,
+		"disabled":               disabled,
+		"namespace_overrides_on": overridden,
+	}).Info("Feature flags loaded")
+	return flags
+}
+
 // initRemediationComponents initializes all remediation-related components
 func initRemediationComponents(
 	cfg *config.Config,
 	k8sClients *KubernetesClients,
 	deploymentDetector *detector.DeploymentDetector,
+	auditor *security.Auditor,
 	log *logrus.Logger,
 ) (*remediation.Orchestrator, *remediation.StrategySelector) {
 	// Initialize remediation components
@@ -362,6 +1351,7 @@ func initRemediationComponents(
 	if cfg.ArgocdAPIURL != "" {
 		argocdToken := os.Getenv("ARGOCD_TOKEN")
 		argocdClient := integrations.NewArgoCDClient(cfg.ArgocdAPIURL, argocdToken, log)
+		auditor.RegisterHTTPClient("argocd", cfg.ArgocdAPIURL, argocdClient.HTTPClient())
 		argocdRemediator := remediation.NewArgoCDRemediator(argocdClient, log)
 		strategySelector.RegisterRemediator(argocdRemediator)
 		log.WithField("argocd_url", cfg.ArgocdAPIURL).Info("ArgoCD remediator initialized")
@@ -371,13 +1361,38 @@ func initRemediationComponents(
 
 	// Initialize remediation orchestrator
 	orchestrator := remediation.NewOrchestrator(deploymentDetector, strategySelector, log)
+	orchestrator.SetEventLog(remediation.NewEventLog(""))
+	orchestrator.SetRetryPolicy(remediation.RetryPolicy{
+		MaxAttempts: cfg.Workflow.MaxAttempts,
+		Backoff:     cfg.Workflow.RetryBackoff,
+	})
+	if cfg.Workflow.VerifyTimeout > 0 {
+		orchestrator.SetVerifier(remediation.NewRolloutVerifier(k8sClients.Clientset, log), remediation.VerifyPolicy(cfg.Workflow.VerifyTimeout))
+		log.WithField("timeout", cfg.Workflow.VerifyTimeout).Info("Remediation verification enabled")
+	}
+	if cfg.Budget.DailyLimit > 0 || cfg.Budget.Cooldown > 0 {
+		orchestrator.SetBudget(remediation.NewBudget(remediation.BudgetPolicy{
+			DailyLimit: cfg.Budget.DailyLimit,
+			Cooldown:   cfg.Budget.Cooldown,
+		}))
+		log.WithFields(logrus.Fields{
+			"daily_limit": cfg.Budget.DailyLimit,
+			"cooldown":    cfg.Budget.Cooldown,
+		}).Info("Remediation budget enforced")
+	}
+	// Unfinished workflows stay in the event log until observer mode is lifted
+	if cfg.ObserverMode {
+		log.Info("Observer mode: unfinished remediation workflows are not resumed")
+	} else if resumed := orchestrator.ResumeWorkflows(); resumed > 0 {
+		log.WithField("workflows", resumed).Info("Resumed unfinished remediation workflows")
+	}
 	log.WithField("remediators", strategySelector.GetRegisteredRemediators()).Info("Remediation orchestrator initialized")
 
 	return orchestrator, strategySelector
 }
 
 // initPrometheusClient creates a Prometheus query client if configured
-func initPrometheusClient(cfg *config.Config, log *logrus.Logger) *integrations.PrometheusClient {
+func initPrometheusClient(cfg *config.Config, clientset kubernetes.Interface, auditor *security.Auditor, log *logrus.Logger) *integrations.PrometheusClient {
 	if cfg.PrometheusURL == "" {
 		log.Info("PROMETHEUS_URL not set, ML predictions will use default metric values")
 		return nil
@@ -389,60 +1404,838 @@ func initPrometheusClient(cfg *config.Config, log *logrus.Logger) *integrations.
 		return nil
 	}
 
-	log.WithField("prometheus_url", cfg.PrometheusURL).Info("Prometheus client initialized for metrics querying")
-	return client
-}
+	client.SetMaxQuerySeries(cfg.PrometheusMaxQuerySeries)
+	client.SetRangeMaxPoints(cfg.PrometheusRangeMaxPoints)
 
-// initAnomalyHandler creates the anomaly analysis handler (Issue #30)
-func initAnomalyHandler(
-	kserveProxyHandler *v1.KServeProxyHandler,
-	prometheusClient *integrations.PrometheusClient,
-	log *logrus.Logger,
-) *v1.AnomalyHandler {
-	if kserveProxyHandler != nil {
-		return v1.NewAnomalyHandler(
-			kserveProxyHandler.GetProxyClient(),
-			prometheusClient,
-			log,
-		)
+	if cfg.PrometheusCAFile != "" {
+		pem, err := os.ReadFile(cfg.PrometheusCAFile) //#nosec G304 -- path comes from operator configuration
+		if err != nil {
+			log.WithError(err).Fatal("Failed to read Prometheus CA bundle")
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			log.WithField("file", cfg.PrometheusCAFile).Fatal("Prometheus CA bundle contains no PEM certificates")
+		}
+		client.SetRootCAs(pool)
+		log.WithField("file", cfg.PrometheusCAFile).Info("Prometheus certificate will be verified against CA bundle")
 	}
-	return v1.NewAnomalyHandler(
-		nil, // No KServe client
-		prometheusClient,
-		log,
-	)
-}
+	auditor.RegisterHTTPClient("prometheus", cfg.PrometheusURL, client.HTTPClient())
 
-// verifyKServeModelsOnStartup validates KServe model availability on startup and logs warnings
-// if models are not ready. This helps operators diagnose deployment issues early.
-// Note: This function logs warnings but does not prevent startup - the coordination engine
-// will continue to start even if models are unavailable, allowing it to serve other endpoints.
-func verifyKServeModelsOnStartup(cfg *config.Config, kserveProxyHandler *v1.KServeProxyHandler, log *logrus.Logger) {
-	if !cfg.KServe.Enabled || kserveProxyHandler == nil {
-		return
+	if cfg.PrometheusUseRecordingRules {
+		client.SetUseRecordingRules(true)
+		log.Info("Anomaly feature queries will use pre-computed recording rule series")
 	}
 
-	log.Info("Verifying KServe model availability...")
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
+	if len(cfg.PrometheusExtraLabels) > 0 {
+		client.SetExternalLabels(cfg.PrometheusExtraLabels)
+		log.WithField("extra_labels", cfg.PrometheusExtraLabels).Info("Prometheus queries will include constant label matchers")
+	}
 
-	modelsHealthy := true
-	for _, modelName := range kserveProxyHandler.GetProxyClient().ListModels() {
-		health, err := kserveProxyHandler.GetProxyClient().CheckModelHealth(ctx, modelName)
-		if err != nil || health == nil || health.Status != "ready" {
-			log.WithFields(logrus.Fields{
-				"model": modelName,
-				"error": err,
-			}).Warn("KServe model not ready on startup - ML predictions will fail until models are deployed")
-			modelsHealthy = false
-		} else {
-			log.WithField("model", modelName).Info("KServe model is healthy and ready")
+	// Queries whose metrics were renamed between Kubernetes versions use the cluster's variant
+	if serverVersion, err := clientset.Discovery().ServerVersion(); err != nil {
+		log.WithError(err).Warn("Failed to detect Kubernetes version, PromQL queries will try every metric variant")
+	} else if version, err := promql.ParseKubernetesVersion(serverVersion.Major, serverVersion.Minor); err != nil {
+		log.WithError(err).Warn("Unrecognized Kubernetes version, PromQL queries will try every metric variant")
+	} else {
+		client.SetKubernetesVersion(version)
+		log.WithField("kubernetes_version", version.String()).Info("PromQL queries selected for Kubernetes version")
+	}
+
+	log.WithField("prometheus_url", cfg.PrometheusURL).Info("Prometheus client initialized for metrics querying")
+	return client
+}
+
+// initRunbookRegistry loads the runbook registry from RUNBOOK_FILE and configures wiki
+// search if RUNBOOK_WIKI_URL is set. An invalid runbook file is fatal.
+func initRunbookRegistry(cfg *config.Config, auditor *security.Auditor, log *logrus.Logger) *runbook.Registry {
+	var runbooks []runbook.Runbook
+	if cfg.Runbook.File != "" {
+		loaded, err := runbook.LoadFile(cfg.Runbook.File)
+		if err != nil {
+			log.WithError(err).Fatal("Failed to load runbook registry")
+		}
+		runbooks = loaded
+	}
+
+	registry, err := runbook.NewRegistry(runbooks, log)
+	if err != nil {
+		log.WithError(err).WithField("file", cfg.Runbook.File).Fatal("Invalid runbook registry")
+	}
+
+	if cfg.Runbook.WikiURL != "" {
+		provider := runbook.NewConfluenceProvider(
+			cfg.Runbook.WikiURL, cfg.Runbook.WikiSpace, cfg.Runbook.WikiToken, cfg.HTTPTimeout, log)
+		auditor.RegisterHTTPClient("runbook-wiki", cfg.Runbook.WikiURL, provider.HTTPClient())
+		registry.SetProvider(provider)
+	}
+
+	log.WithFields(logrus.Fields{
+		"runbooks":    len(runbooks),
+		"wiki_search": cfg.Runbook.WikiURL != "",
+	}).Info("Runbook registry initialized")
+	return registry
+}
+
+// initSummarizer creates the LLM incident summarizer if SUMMARIZER_URL is set
+func initSummarizer(
+	cfg *config.Config,
+	store *storage.IncidentStore,
+	prometheusClient *integrations.PrometheusClient,
+	auditor *security.Auditor,
+	log *logrus.Logger,
+) *summary.Summarizer {
+	if !cfg.Summarizer.Enabled() {
+		log.Info("SUMMARIZER_URL not set, incident summaries disabled")
+		return nil
+	}
+
+	llmClient := integrations.NewLLMClient(cfg.Summarizer.URL, cfg.Summarizer.APIKey, cfg.Summarizer.Model, cfg.Summarizer.Timeout, log)
+	auditor.RegisterHTTPClient("summarizer", cfg.Summarizer.URL, llmClient.HTTPClient())
+	summarizer := summary.NewSummarizer(llmClient, store, cfg.Summarizer.MaxTokens, log)
+	if prometheusClient != nil {
+		summarizer.SetMetricsSource(prometheusClient)
+	}
+
+	log.WithFields(logrus.Fields{
+		"summarizer_url": cfg.Summarizer.URL,
+		"model":          cfg.Summarizer.Model,
+	}).Info("Incident summarizer initialized")
+	return summarizer
+}
+
+// initPredictionTracker creates the prediction accuracy tracker unless
+// PREDICTION_TRACKING_ENABLED is false. Without Prometheus, predictions are stored but
+// not evaluated.
+func initPredictionTracker(
+	cfg *config.Config,
+	prometheusClient *integrations.PrometheusClient,
+	log *logrus.Logger,
+) *prediction.Tracker {
+	if !cfg.PredictionTracking.Enabled {
+		log.Info("PREDICTION_TRACKING_ENABLED is false, prediction accuracy tracking disabled")
+		return nil
+	}
+
+	store := storage.NewPredictionStore("", cfg.PredictionTracking.HistoryLimit)
+	var history prediction.HistorySource
+	if prometheusClient != nil {
+		history = prometheusClient
+	}
+
+	log.WithFields(logrus.Fields{
+		"stored_predictions":  store.Count(),
+		"evaluation_interval": cfg.PredictionTracking.EvaluationInterval,
+	}).Info("Prediction accuracy tracker initialized")
+	return prediction.NewTracker(store, history, log)
+}
+
+// initBaselineExport creates the manager of the archives carrying learned baselines
+// and calibration data to a new cluster: prediction history, action outcomes and API
+// server request rate baselines, with the cluster's shape
+func initBaselineExport(
+	cfg *config.Config,
+	k8sClient kubernetes.Interface,
+	prometheusClient *integrations.PrometheusClient,
+	predictionTracker *prediction.Tracker,
+	outcomes *storage.ActionOutcomeStore,
+	baselines *baseline.Store,
+	log *logrus.Logger,
+) *backup.Manager {
+	manager := backup.NewManager(Version, cfg.Admin.BackupTimeout, log)
+	manager.SetClusterShape(baseline.ClusterShape(k8sClient))
+	if predictionTracker != nil {
+		manager.Register(backup.NewPredictionSection(predictionTracker.Store()))
+	}
+	if outcomes != nil {
+		manager.Register(backup.NewActionOutcomeSection(outcomes))
+	}
+	var learner baseline.Learner
+	if prometheusClient != nil {
+		learner = prometheusClient
+	}
+	manager.Register(baseline.NewAPIServerSection(learner, baselines, log))
+	return manager
+}
+
+// hardeningOptions describes this deployment's ports and enabled integrations for
+// GET /api/v1/admin/hardening/manifests
+func hardeningOptions(cfg *config.Config, log *logrus.Logger) hardening.Options {
+	opts := hardening.Options{
+		Namespace:    cfg.Namespace,
+		APIPorts:     []int{cfg.Port},
+		MetricsPort:  cfg.MetricsPort,
+		AlertIngest:  true,
+		UpgradeAware: cfg.Upgrade.Enabled,
+	}
+	if cfg.GRPCPort != 0 {
+		opts.APIPorts = append(opts.APIPorts, cfg.GRPCPort)
+	}
+
+	if cfg.KServe.Enabled {
+		port := cfg.KServe.PredictorPort
+		if port == 0 {
+			port = config.DefaultKServePredictorPort
+		}
+		opts.Endpoints = append(opts.Endpoints, hardening.Endpoint{Name: "kserve", Namespace: cfg.KServe.Namespace, Port: port})
+	}
+	if cfg.Email.Enabled() {
+		opts.Endpoints = append(opts.Endpoints, hardening.HostEndpoint("smtp", cfg.Email.SMTPHost, cfg.Email.SMTPPort))
+	}
+
+	urls := map[string]string{
+		"prometheus": cfg.PrometheusURL,
+		"ml_service": cfg.MLServiceURL,
+		"argocd":     cfg.ArgocdAPIURL,
+		"summarizer": cfg.Summarizer.URL,
+		"wiki":       cfg.Runbook.WikiURL,
+	}
+	if cfg.Webhook.Enabled() {
+		// Targets were validated at startup
+		targets, _ := notification.LoadTargets(cfg.Webhook.File)
+		for _, target := range targets {
+			urls["webhook "+target.Name] = target.URL
+		}
+	}
+	for name, rawURL := range urls {
+		if rawURL == "" {
+			continue
+		}
+		endpoint, err := hardening.EndpointFromURL(name, rawURL)
+		if err != nil {
+			log.WithError(err).Warn("Skipping endpoint in hardening manifests")
+			continue
+		}
+		opts.Endpoints = append(opts.Endpoints, endpoint)
+	}
+
+	return opts
+}
+
+// initRedactor creates the credential redactor unless REDACTION_ENABLED is false. An
+// unreadable or invalid REDACTION_PATTERNS_FILE is fatal so credentials are never
+// stored because of a typo.
+func initRedactor(cfg *config.Config, log *logrus.Logger) *redact.Redactor {
+	if !cfg.Redaction.Enabled {
+		log.Warn("REDACTION_ENABLED=false, credentials in incidents and evidence are stored unmasked")
+		return nil
+	}
+
+	var patterns []string
+	if cfg.Redaction.PatternsFile != "" {
+		var err error
+		if patterns, err = redact.LoadPatterns(cfg.Redaction.PatternsFile); err != nil {
+			log.WithError(err).Fatal("Failed to load redaction patterns")
 		}
 	}
+	redactor, err := redact.New(patterns)
+	if err != nil {
+		log.WithError(err).Fatal("Failed to compile redaction patterns")
+	}
+
+	log.WithField("custom_patterns", len(patterns)).Info("Credential redaction enabled")
+	return redactor
+}
+
+// initIncidentEncryption encrypts stored incident payloads if ENCRYPTION_KEYS_FILE is
+// set. An unreadable keys file or incidents that cannot be decrypted are fatal, so the
+// engine never runs on data it cannot read.
+func initIncidentEncryption(cfg *config.Config, store *storage.IncidentStore, log *logrus.Logger) {
+	if !cfg.Encryption.Enabled() {
+		log.Info("ENCRYPTION_KEYS_FILE not set, incident payloads stored unencrypted")
+		return
+	}
+
+	fieldCipher, err := storage.LoadFieldCipher(cfg.Encryption.KeysFile)
+	if err != nil {
+		log.WithError(err).Fatal("Failed to load encryption keys")
+	}
+	if err := store.SetCipher(fieldCipher); err != nil {
+		log.WithError(err).Fatal("Failed to enable incident encryption")
+	}
+
+	log.WithField("primary_key", fieldCipher.PrimaryKeyID()).Info("Incident payload encryption at rest enabled")
+}
 
-	if !modelsHealthy {
-		log.Warn("Some KServe models are not ready. Deploy InferenceServices and ensure model files exist.")
+// initUpgradeMonitor creates the upgrade monitor unless UPGRADE_AWARE_ENABLED is false
+func initUpgradeMonitor(cfg *config.Config, dynamicClient dynamic.Interface, log *logrus.Logger) *upgrade.Monitor {
+	if !cfg.Upgrade.Enabled {
+		log.Info("UPGRADE_AWARE_ENABLED is false, upgrade-aware mode disabled")
+		return nil
 	}
+
+	log.WithFields(logrus.Fields{
+		"block_remediation":  cfg.Upgrade.BlockRemediation,
+		"threshold_increase": cfg.Upgrade.ThresholdIncrease,
+		"tag_incidents":      cfg.Upgrade.TagIncidents,
+		"check_interval":     cfg.Upgrade.CheckInterval,
+	}).Info("Upgrade-aware mode initialized")
+	return upgrade.NewMonitor(
+		integrations.NewClusterVersionClient(dynamicClient, log),
+		upgrade.Policy{
+			BlockRemediation:  cfg.Upgrade.BlockRemediation,
+			ThresholdIncrease: cfg.Upgrade.ThresholdIncrease,
+			TagIncidents:      cfg.Upgrade.TagIncidents,
+		},
+		log,
+	)
+}
+
+// initExecutionRunner creates the playbook runner if EXECUTION_BACKEND is set. Playbooks
+// are runtime configuration, so the runner also needs RUNTIME_CONFIG_ENABLED.
+func initExecutionRunner(cfg *config.Config, dynamicClient dynamic.Interface, runtimeConfig *runtimeconfig.Store, log *logrus.Logger) *execution.Runner {
+	if cfg.Execution.Backend == "" {
+		return nil
+	}
+	if runtimeConfig == nil {
+		log.Warn("EXECUTION_BACKEND is set but RUNTIME_CONFIG_ENABLED is false, playbook execution disabled")
+		return nil
+	}
+
+	backend, err := execution.NewBackend(cfg.Execution.Backend, execution.Options{
+		ServiceAccount: cfg.Execution.ServiceAccount,
+		Image:          cfg.Execution.RunnerImage,
+	})
+	if err != nil {
+		log.WithError(err).Fatal("Invalid execution backend")
+	}
+	namespace := cfg.Execution.Namespace
+	if namespace == "" {
+		namespace = cfg.Namespace
+	}
+
+	log.WithFields(logrus.Fields{
+		"backend":         backend.Name(),
+		"namespace":       namespace,
+		"service_account": cfg.Execution.ServiceAccount,
+		"runner_image":    cfg.Execution.RunnerImage,
+	}).Info("Playbook execution backend initialized")
+	return execution.NewRunner(backend, dynamicClient, runtimeConfig, execution.NewStore(""), namespace, log)
+}
+
+// initWebhookNotifier creates the outbound webhook notifier if WEBHOOK_FILE is set, or
+// if channels or tenants can add targets at runtime. An invalid targets file is fatal.
+func initWebhookNotifier(
+	cfg *config.Config,
+	runtimeConfig *runtimeconfig.Store,
+	tenants *tenant.Registry,
+	auditor *security.Auditor,
+	log *logrus.Logger,
+) *notification.WebhookNotifier {
+	if !cfg.Webhook.Enabled() && runtimeConfig == nil && tenants == nil {
+		log.Info("WEBHOOK_FILE not set, webhook notifications disabled")
+		return nil
+	}
+
+	var targets []notification.Target
+	if cfg.Webhook.Enabled() {
+		var err error
+		if targets, err = notification.LoadTargets(cfg.Webhook.File); err != nil {
+			log.WithError(err).Fatal("Failed to load webhook targets")
+		}
+	}
+
+	notifier, err := notification.NewWebhookNotifier(targets, notification.Options{
+		MaxAttempts:    cfg.Webhook.MaxAttempts,
+		InitialBackoff: cfg.Webhook.InitialBackoff,
+		MaxBackoff:     cfg.Webhook.MaxBackoff,
+		Timeout:        cfg.Webhook.Timeout,
+		DefaultSecret:  cfg.Webhook.Secret,
+	}, log)
+	if err != nil {
+		log.WithError(err).WithField("file", cfg.Webhook.File).Fatal("Invalid webhook targets")
+	}
+	for _, target := range targets {
+		auditor.RegisterHTTPClient("webhook "+target.Name, target.URL, notifier.HTTPClient())
+	}
+	if runtimeConfig != nil {
+		// Notification channels and silences managed through the admin API
+		notifier.SetTargetSource(runtimeConfig.WebhookTargets)
+		notifier.SetSilencer(runtimeConfig.Silenced)
+		auditor.RegisterHTTPClient("webhook channels", "", notifier.HTTPClient())
+	}
+	if tenants != nil {
+		// Tenant notification targets receive the events of their namespaces
+		notifier.SetNamespaceTargetSource(tenants.WebhookTargets)
+		auditor.RegisterHTTPClient("webhook tenants", "", notifier.HTTPClient())
+	}
+
+	log.WithFields(logrus.Fields{
+		"targets":      len(targets),
+		"max_attempts": cfg.Webhook.MaxAttempts,
+	}).Info("Webhook notifier initialized")
+	return notifier
+}
+
+// initSubscriptionNotifier creates a webhook notifier without configured targets for
+// anomaly subscription deliveries, used when WEBHOOK_FILE is not set
+func initSubscriptionNotifier(cfg *config.Config, auditor *security.Auditor, log *logrus.Logger) *notification.WebhookNotifier {
+	notifier, err := notification.NewWebhookNotifier(nil, notification.Options{
+		MaxAttempts:    cfg.Webhook.MaxAttempts,
+		InitialBackoff: cfg.Webhook.InitialBackoff,
+		MaxBackoff:     cfg.Webhook.MaxBackoff,
+		Timeout:        cfg.Webhook.Timeout,
+		DefaultSecret:  cfg.Webhook.Secret,
+	}, log)
+	if err != nil {
+		log.WithError(err).Fatal("Failed to create anomaly subscription notifier")
+	}
+	auditor.RegisterHTTPClient("anomaly subscriptions", "", notifier.HTTPClient())
+	return notifier
+}
+
+// initEmailNotifier creates the email notifier if EMAIL_SMTP_HOST is set. Invalid team
+// or template files are fatal.
+func initEmailNotifier(
+	cfg *config.Config,
+	store *storage.IncidentStore,
+	auditor *security.Auditor,
+	log *logrus.Logger,
+) *notification.EmailNotifier {
+	if !cfg.Email.Enabled() {
+		log.Info("EMAIL_SMTP_HOST not set, email notifications disabled")
+		return nil
+	}
+
+	teams, err := notification.LoadTeams(cfg.Email.TeamsFile)
+	if err != nil {
+		log.WithError(err).Fatal("Failed to load email teams")
+	}
+	templates, err := notification.LoadTemplates(cfg.Email.TemplateDir)
+	if err != nil {
+		log.WithError(err).Fatal("Failed to load email templates")
+	}
+
+	mailer := notification.NewSMTPMailer(cfg.Email.SMTPHost, cfg.Email.SMTPPort, cfg.Email.Username, cfg.Email.Password)
+	auditor.Register("smtp", "smtp://"+net.JoinHostPort(cfg.Email.SMTPHost, strconv.Itoa(cfg.Email.SMTPPort)), mailer.TLSConfig())
+	notifier, err := notification.NewEmailNotifier(teams, mailer, templates, store, notification.EmailOptions{
+		From:          cfg.Email.From,
+		DigestHour:    cfg.Email.DigestHour,
+		DigestWeekday: cfg.Email.Weekday(),
+		Timeout:       cfg.HTTPTimeout,
+	}, log)
+	if err != nil {
+		log.WithError(err).WithField("file", cfg.Email.TeamsFile).Fatal("Invalid email teams")
+	}
+
+	log.WithFields(logrus.Fields{
+		"smtp_host": cfg.Email.SMTPHost,
+		"teams":     len(teams),
+	}).Info("Email notifier initialized")
+	return notifier
+}
+
+// initAnomalyHandler creates the anomaly analysis handler (Issue #30)
+func initAnomalyHandler(
+	kserveProxyHandler *v1.KServeProxyHandler,
+	prometheusClient *integrations.PrometheusClient,
+	log *logrus.Logger,
+) *v1.AnomalyHandler {
+	if kserveProxyHandler != nil {
+		return v1.NewAnomalyHandler(
+			kserveProxyHandler.GetProxyClient(),
+			prometheusClient,
+			log,
+		)
+	}
+	return v1.NewAnomalyHandler(
+		nil, // No KServe client
+		prometheusClient,
+		log,
+	)
+}
+
+// initScopeDefaults loads the per-scope anomaly models and thresholds if
+// ANOMALY_SCOPE_DEFAULTS_FILE is set. An unreadable or invalid file is fatal; models
+// that KServe does not serve are only logged, as analysis reports them per request.
+func initScopeDefaults(
+	cfg *config.Config,
+	clientset kubernetes.Interface,
+	kserveProxyHandler *v1.KServeProxyHandler,
+	tenants *tenant.Registry,
+	log *logrus.Logger,
+) *anomaly.ScopeDefaults {
+	if cfg.Anomaly.ScopeDefaultsFile == "" {
+		return nil
+	}
+
+	defaults, err := anomaly.LoadScopeDefaults(cfg.Anomaly.ScopeDefaultsFile)
+	if err != nil {
+		log.WithError(err).WithField("file", cfg.Anomaly.ScopeDefaultsFile).Fatal("Invalid anomaly scope defaults")
+	}
+	if defaults.UsesTiers() {
+		tiers := anomaly.NamespaceTierLookup(clientset, defaults.TierLabel(), log)
+		if tenants != nil {
+			tiers = tenants.TierLookup(tiers)
+		}
+		defaults.SetTierLookup(tiers)
+	}
+
+	for _, model := range defaults.Models() {
+		if kserveProxyHandler == nil {
+			break
+		}
+		if _, exists := kserveProxyHandler.GetProxyClient().GetModel(model); !exists {
+			log.WithField("model", model).Warn("Anomaly scope defaults name a model that is not configured in KServe")
+		}
+	}
+
+	log.WithFields(logrus.Fields{
+		"file":       cfg.Anomaly.ScopeDefaultsFile,
+		"tier_label": defaults.TierLabel(),
+	}).Info("Anomaly scope defaults loaded")
+	return defaults
+}
+
+// initIncidentTagRules tags new incidents from the rules in INCIDENT_TAG_RULES_FILE if
+// it is set. An invalid file is fatal.
+func initIncidentTagRules(cfg *config.Config, store *storage.IncidentStore, log *logrus.Logger) {
+	if cfg.IncidentTags.RulesFile == "" {
+		return
+	}
+
+	rules, err := tagging.LoadRules(cfg.IncidentTags.RulesFile)
+	if err != nil {
+		log.WithError(err).WithField("file", cfg.IncidentTags.RulesFile).Fatal("Invalid incident tag rules")
+	}
+	store.SetTagger(rules)
+	log.WithFields(logrus.Fields{
+		"file":  cfg.IncidentTags.RulesFile,
+		"rules": rules.Len(),
+	}).Info("Incident tag rules loaded")
+}
+
+// initRecommendationRules loads the recommendation rules if RECOMMENDATION_RULES_FILE
+// is set and reloads them when the file changes. An invalid file is fatal at startup;
+// later invalid versions are logged and the previous rules kept.
+func initRecommendationRules(ctx context.Context, cfg *config.Config, log *logrus.Logger) *advice.Engine {
+	if cfg.RecommendationRules.File == "" {
+		return nil
+	}
+
+	engine, err := advice.NewEngine(cfg.RecommendationRules.File, cfg.RecommendationRules.ReloadInterval, log)
+	if err != nil {
+		log.WithError(err).WithField("file", cfg.RecommendationRules.File).Fatal("Invalid recommendation rules")
+	}
+	engine.Start(ctx)
+	log.WithFields(logrus.Fields{
+		"file":            cfg.RecommendationRules.File,
+		"rules":           engine.Len(),
+		"reload_interval": cfg.RecommendationRules.ReloadInterval,
+	}).Info("Recommendation rules loaded")
+	return engine
+}
+
+// initBusinessCalendars loads the business-cycle calendars if ANOMALY_CALENDARS_FILE is
+// set. An invalid file is fatal.
+func initBusinessCalendars(cfg *config.Config, log *logrus.Logger) *anomaly.BusinessCalendars {
+	if cfg.Anomaly.CalendarsFile == "" {
+		return nil
+	}
+
+	calendars, err := anomaly.LoadBusinessCalendars(cfg.Anomaly.CalendarsFile)
+	if err != nil {
+		log.WithError(err).WithField("file", cfg.Anomaly.CalendarsFile).Fatal("Invalid business calendars")
+	}
+	log.WithField("file", cfg.Anomaly.CalendarsFile).Info("Business calendars loaded")
+	return calendars
+}
+
+// initPostFilters loads the anomaly post-filter chain if ANOMALY_POST_FILTERS_FILE is
+// set. An invalid file is fatal.
+func initPostFilters(cfg *config.Config, clientset kubernetes.Interface, log *logrus.Logger) *anomaly.PostFilters {
+	if cfg.Anomaly.PostFiltersFile == "" {
+		return nil
+	}
+
+	filters, err := anomaly.LoadPostFilters(cfg.Anomaly.PostFiltersFile)
+	if err != nil {
+		log.WithError(err).WithField("file", cfg.Anomaly.PostFiltersFile).Fatal("Invalid anomaly post-filters")
+	}
+	if filters.UsesNamespaceLabels() {
+		filters.SetNamespaceLabels(anomaly.NamespaceLabelLookup(clientset, log))
+	}
+	if filters.UsesOwners() {
+		filters.SetOwners(ownership.NewResolver(clientset, cfg.Ownership.AnnotationPrefix, log).Team)
+	}
+	log.WithFields(logrus.Fields{
+		"file":    cfg.Anomaly.PostFiltersFile,
+		"filters": filters.Names(),
+	}).Info("Anomaly post-filters loaded")
+	return filters
+}
+
+// initAnnotationPublisher creates the publisher of incident and remediation
+// annotations, or nil unless ANNOTATIONS_GRAFANA_URL or ANNOTATIONS_KUBERNETES_EVENTS
+// is set
+func initAnnotationPublisher(
+	cfg *config.Config,
+	clientset kubernetes.Interface,
+	auditor *security.Auditor,
+	log *logrus.Logger,
+) *annotation.Publisher {
+	if !cfg.Annotations.Enabled() {
+		return nil
+	}
+	var sinks []annotation.Sink
+	if cfg.Annotations.GrafanaURL != "" {
+		grafana := annotation.NewGrafana(annotation.GrafanaOptions{
+			URL:          cfg.Annotations.GrafanaURL,
+			Token:        cfg.Annotations.GrafanaToken,
+			DashboardUID: cfg.Annotations.GrafanaDashboardUID,
+			Timeout:      cfg.Annotations.Timeout,
+		})
+		auditor.RegisterHTTPClient("grafana", cfg.Annotations.GrafanaURL, grafana.HTTPClient())
+		sinks = append(sinks, grafana)
+	}
+	if cfg.Annotations.KubernetesEvents {
+		sinks = append(sinks, annotation.NewKubeEvents(clientset))
+	}
+	publisher := annotation.NewPublisher(log, sinks...)
+	publisher.SetTimeout(cfg.Annotations.Timeout)
+	return publisher
+}
+
+// initFeatureHistory creates the rolling feature history of onboarded scopes, or nil
+// unless ANOMALY_FEATURE_HISTORY is set
+func initFeatureHistory(cfg *config.Config, log *logrus.Logger) *featurehistory.History {
+	if !cfg.Anomaly.FeatureHistory {
+		return nil
+	}
+	log.WithFields(logrus.Fields{
+		"retention":  cfg.Anomaly.FeatureHistoryRetention,
+		"resolution": cfg.Anomaly.FeatureHistoryResolution,
+		"max_scopes": cfg.Anomaly.FeatureHistoryMaxScopes,
+	}).Info("Anomaly feature history enabled")
+	return featurehistory.New(featurehistory.Options{
+		Retention:  cfg.Anomaly.FeatureHistoryRetention,
+		Resolution: cfg.Anomaly.FeatureHistoryResolution,
+		MaxScopes:  cfg.Anomaly.FeatureHistoryMaxScopes,
+	})
+}
+
+// initAnomalyRecords creates the store of detected anomalies, or nil if
+// ANOMALY_RECORDS_ENABLED is false
+func initAnomalyRecords(cfg *config.Config, log *logrus.Logger) *storage.AnomalyStore {
+	if !cfg.Anomaly.RecordsEnabled {
+		log.Info("ANOMALY_RECORDS_ENABLED is false, detected anomalies are not kept")
+		return nil
+	}
+	store := storage.NewAnomalyStore("", cfg.Anomaly.RecordLimit)
+	log.WithFields(logrus.Fields{
+		"stored_anomalies": store.Count(),
+		"retention":        cfg.Retention.Anomalies,
+	}).Info("Anomaly records enabled")
+	return store
+}
+
+// initScoringPipeline builds the scoring hooks named by ANOMALY_SCORING_HOOKS, with the
+// built-in maintenance hook in its listed position or last if only
+// ANOMALY_MAINTENANCE_NAMESPACES is set. An unknown hook is fatal.
+func initScoringPipeline(cfg *config.Config, log *logrus.Logger) *scoring.Pipeline {
+	names := cfg.Anomaly.ScoringHooks
+	if len(cfg.Anomaly.MaintenanceNamespaces) > 0 && !slices.Contains(names, scoring.MaintenanceHookName) {
+		names = append(append([]string(nil), names...), scoring.MaintenanceHookName)
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	hooks := make([]scoring.Hook, 0, len(names))
+	for _, name := range names {
+		if name == scoring.MaintenanceHookName {
+			maintenance, err := scoring.NewMaintenance(cfg.Anomaly.MaintenanceNamespaces)
+			if err != nil {
+				log.WithError(err).Fatal("Invalid maintenance namespaces")
+			}
+			hooks = append(hooks, maintenance)
+			continue
+		}
+		registered, err := scoring.Lookup([]string{name})
+		if err != nil {
+			log.WithError(err).Fatal("Invalid scoring hooks")
+		}
+		hooks = append(hooks, registered...)
+	}
+
+	pipeline, err := scoring.NewPipeline(hooks...)
+	if err != nil {
+		log.WithError(err).Fatal("Invalid scoring hooks")
+	}
+	log.WithFields(logrus.Fields{
+		"hooks":                  pipeline.Names(),
+		"maintenance_namespaces": cfg.Anomaly.MaintenanceNamespaces,
+	}).Info("Anomaly scoring hooks enabled")
+	return pipeline
+}
+
+// initSeverityMatrix loads the severity matrix from SEVERITY_MATRIX_FILE, or returns
+// the default matrix if it is not set. An invalid file is fatal.
+func initSeverityMatrix(cfg *config.Config, log *logrus.Logger) *severity.Matrix {
+	if cfg.Severity.MatrixFile == "" {
+		return severity.DefaultMatrix()
+	}
+
+	matrix, err := severity.LoadMatrix(cfg.Severity.MatrixFile)
+	if err != nil {
+		log.WithError(err).WithField("file", cfg.Severity.MatrixFile).Fatal("Invalid severity matrix")
+	}
+	log.WithFields(logrus.Fields{
+		"file":       cfg.Severity.MatrixFile,
+		"tier_label": matrix.TierLabel,
+	}).Info("Severity matrix loaded")
+	return matrix
+}
+
+// initHistogramFeatures parses the histogram quantile features of
+// ANOMALY_HISTOGRAM_FEATURES. Invalid features are fatal.
+func initHistogramFeatures(cfg *config.Config, log *logrus.Logger) []integrations.HistogramFeature {
+	if len(cfg.Anomaly.HistogramFeatures) == 0 {
+		return nil
+	}
+
+	histograms, err := integrations.ParseHistogramFeatures(cfg.Anomaly.HistogramFeatures)
+	if err != nil {
+		log.WithError(err).Fatal("Invalid anomaly histogram features")
+	}
+
+	log.WithFields(logrus.Fields{
+		"histograms": len(histograms),
+		"models":     cfg.Anomaly.HistogramModels,
+	}).Info("Anomaly histogram features enabled")
+	return histograms
+}
+
+// initStartupGates creates the startup gates and checks the storage in DATA_DIR.
+// Startup stops if it is unavailable in fail mode.
+func initStartupGates(cfg *config.Config, log *logrus.Logger) *startup.Gates {
+	gates := startup.New(startup.Options{
+		WaitTimeout:    cfg.Startup.WaitTimeout,
+		InitialBackoff: cfg.Startup.Backoff,
+		Recheck:        cfg.Startup.Recheck,
+	}, log)
+
+	dataDir := os.Getenv("DATA_DIR")
+	if dataDir == "" {
+		dataDir = "/app/data"
+	}
+	err := gates.Run(context.Background(), startup.Gate{
+		Name:  "storage",
+		Mode:  startupMode(cfg.Startup.Storage, config.DefaultStartupStorage),
+		Check: startup.WritableDir(dataDir),
+	})
+	if err != nil {
+		log.WithError(err).WithField("data_dir", dataDir).Fatal("Storage unavailable - cannot start (set STARTUP_STORAGE=degraded to start without persistence)")
+	}
+	return gates
+}
+
+// gateKServe checks that every KServe model is ready, if KServe is enabled. The
+// engine starts degraded without them unless STARTUP_KSERVE is wait or fail.
+func gateKServe(gates *startup.Gates, cfg *config.Config, kserveProxyHandler *v1.KServeProxyHandler, log *logrus.Logger) {
+	if !cfg.KServe.Enabled {
+		return
+	}
+
+	log.Info("Verifying KServe model availability...")
+	err := gates.Run(context.Background(), startup.Gate{
+		Name: "kserve",
+		Mode: startupMode(cfg.Startup.KServe, config.DefaultStartupKServe),
+		Check: func(ctx context.Context) error {
+			if kserveProxyHandler == nil {
+				return fmt.Errorf("KServe proxy client failed to initialize")
+			}
+			client := kserveProxyHandler.GetProxyClient()
+			var notReady []string
+			for _, modelName := range client.ListModels() {
+				health, err := client.CheckModelHealth(ctx, modelName)
+				if err != nil || health == nil || health.Status != "ready" {
+					log.WithFields(logrus.Fields{
+						"model": modelName,
+						"error": err,
+					}).Debug("KServe model not ready")
+					notReady = append(notReady, modelName)
+				}
+			}
+			if len(notReady) > 0 {
+				return fmt.Errorf("models not ready: %s", strings.Join(notReady, ", "))
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		log.WithError(err).Fatal("KServe unavailable - cannot start (set STARTUP_KSERVE=degraded to start without it)")
+	}
+}
+
+// gatePrometheus checks that Prometheus answers queries, if PROMETHEUS_URL is set. By
+// default startup waits for it with backoff.
+func gatePrometheus(gates *startup.Gates, cfg *config.Config, client *integrations.PrometheusClient, log *logrus.Logger) {
+	if cfg.PrometheusURL == "" {
+		return
+	}
+
+	err := gates.Run(context.Background(), startup.Gate{
+		Name: "prometheus",
+		Mode: startupMode(cfg.Startup.Prometheus, config.DefaultStartupPrometheus),
+		Check: func(ctx context.Context) error {
+			if client == nil {
+				return fmt.Errorf("prometheus client failed to initialize")
+			}
+			_, err := client.Query(ctx, "vector(1)")
+			return err
+		},
+	})
+	if err != nil {
+		log.WithError(err).Fatal("Prometheus unavailable - cannot start (set STARTUP_PROMETHEUS=degraded to start without it)")
+	}
+}
+
+// startupMode returns the configured startup mode of a dependency, or its default
+func startupMode(mode, fallback string) startup.Mode {
+	if mode == "" {
+		mode = fallback
+	}
+	// Modes were validated with the configuration
+	parsed, _ := startup.ParseMode(mode)
+	return parsed
+}
+
+// preflightKServeModels registers the feature schemas of the built-in models and of
+// the models using histogram or route probe features, and warms up every model in the
+// background.
+// Input shape mismatches are logged and reported by /api/v1/health/dependencies
+// instead of failing the first request.
+func preflightKServeModels(client *kserve.ProxyClient, histograms []integrations.HistogramFeature, histogramModels, routeModels []string, controlPlaneModel string, log *logrus.Logger) {
+	client.RegisterFeatureSchema("anomaly-detector", v1.AnomalyFeatureNames())
+	client.RegisterFeatureSchema("predictive-analytics", v1.PredictionFeatureNames())
+	if controlPlaneModel != "" {
+		client.RegisterFeatureSchema(controlPlaneModel, v1.ControlPlaneFeatureNames())
+	}
+
+	// Histogram features come first, then route probe features
+	schemas := make(map[string][]string)
+	if len(histograms) > 0 {
+		for _, model := range histogramModels {
+			schemas[model] = append(v1.AnomalyFeatureNames(), v1.HistogramFeatureNames(histograms)...)
+		}
+	}
+	for _, model := range routeModels {
+		schema, ok := schemas[model]
+		if !ok {
+			schema = v1.AnomalyFeatureNames()
+		}
+		schemas[model] = append(schema, v1.RouteFeatureNames()...)
+	}
+	for model, schema := range schemas {
+		client.RegisterFeatureSchema(model, schema)
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		defer cancel()
+		results := client.Preflight(ctx)
+		log.WithField("models", len(results)).Info("KServe model pre-flight checks completed")
+	}()
 }
 
 // initKubernetesClient creates both standard and dynamic Kubernetes clients
@@ -480,6 +2273,11 @@ func initKubernetesClient(cfg *config.Config, log *logrus.Logger) (*KubernetesCl
 	restConfig.QPS = cfg.KubernetesQPS
 	restConfig.Burst = cfg.KubernetesBurst
 
+	// In observer mode the clients can only read, whichever component uses them
+	if cfg.ObserverMode {
+		observer.ReadOnly(restConfig)
+	}
+
 	// Create standard Kubernetes clientset
 	clientset, err := kubernetes.NewForConfig(restConfig)
 	if err != nil {
@@ -0,0 +1,41 @@
+// Package main generates the OpenAPI document and the Python client of the REST API
+// from the handler annotations. Run it through "make openapi" or "go generate
+// ./pkg/api/openapi".
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/api/openapi"
+)
+
+func main() {
+	root := flag.String("root", ".", "Repository root")
+	flag.Parse()
+
+	if err := generate(*root); err != nil {
+		fmt.Fprintf(os.Stderr, "openapi-gen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// generate writes the generated files under root
+func generate(root string) error {
+	spec, python, err := openapi.Generate(root)
+	if err != nil {
+		return err
+	}
+	for file, data := range map[string][]byte{openapi.SpecFile: spec, openapi.PythonFile: python} {
+		path := filepath.Join(root, file)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, data, 0o644); err != nil { //#nosec G306 -- generated source is not secret
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package advice
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultReloadInterval is how often the rules file is checked for changes when the
+// interval is zero
+const DefaultReloadInterval = 30 * time.Second
+
+// Engine evaluates the rules of a file and reloads them when the file changes. A
+// failed reload keeps the previous rules.
+type Engine struct {
+	path     string
+	interval time.Duration
+	log      *logrus.Logger
+
+	mu      sync.RWMutex
+	rules   *Rules
+	modTime time.Time
+}
+
+// NewEngine loads the rules of a file, failing if it is invalid
+func NewEngine(path string, interval time.Duration, log *logrus.Logger) (*Engine, error) {
+	if interval <= 0 {
+		interval = DefaultReloadInterval
+	}
+	e := &Engine{path: path, interval: interval, log: log}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		RecordReload(ReloadFailed)
+		return nil, err
+	}
+	rules, err := LoadRules(path)
+	if err != nil {
+		RecordReload(ReloadFailed)
+		return nil, err
+	}
+	e.rules, e.modTime = rules, info.ModTime()
+	RecordReload(ReloadSucceeded)
+	RulesLoaded.Set(float64(rules.Len()))
+	return e, nil
+}
+
+// Len returns the number of rules currently loaded
+func (e *Engine) Len() int {
+	if e == nil {
+		return 0
+	}
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.rules.Len()
+}
+
+// Evaluate evaluates the current rules; see Rules.Evaluate. A nil engine returns the
+// built-in actions.
+func (e *Engine) Evaluate(subject Subject, builtin []string) Result {
+	if e == nil {
+		return Result{Actions: builtin}
+	}
+	e.mu.RLock()
+	rules := e.rules
+	e.mu.RUnlock()
+
+	result := rules.Evaluate(subject, builtin)
+	for _, name := range result.Matched {
+		RuleMatches.WithLabelValues(name).Inc()
+	}
+	return result
+}
+
+// Start checks the rules file for changes every reload interval until ctx is cancelled
+func (e *Engine) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(e.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				e.Reload()
+			}
+		}
+	}()
+}
+
+// Reload reloads the rules if the file's modification time changed. It reports
+// whether new rules were loaded.
+func (e *Engine) Reload() bool {
+	entry := e.log.WithField("file", e.path)
+	info, err := os.Stat(e.path)
+	if err != nil {
+		entry.WithError(err).Warn("Failed to check recommendation rules file, keeping previous rules")
+		RecordReload(ReloadFailed)
+		return false
+	}
+
+	e.mu.RLock()
+	unchanged := info.ModTime().Equal(e.modTime)
+	e.mu.RUnlock()
+	if unchanged {
+		return false
+	}
+
+	rules, err := LoadRules(e.path)
+	if err != nil {
+		entry.WithError(err).Error("Invalid recommendation rules, keeping previous rules")
+		RecordReload(ReloadFailed)
+		// Do not retry the same broken file on every check
+		e.mu.Lock()
+		e.modTime = info.ModTime()
+		e.mu.Unlock()
+		return false
+	}
+
+	e.mu.Lock()
+	e.rules, e.modTime = rules, info.ModTime()
+	e.mu.Unlock()
+	RecordReload(ReloadSucceeded)
+	RulesLoaded.Set(float64(rules.Len()))
+	entry.WithField("rules", rules.Len()).Info("Reloaded recommendation rules")
+	return true
+}
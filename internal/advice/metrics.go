@@ -0,0 +1,45 @@
+package advice
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Reload outcomes
+const (
+	ReloadSucceeded = "success"
+	ReloadFailed    = "failure"
+)
+
+var (
+	// RulesLoaded is the number of recommendation rules currently loaded
+	RulesLoaded = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "coordination_engine_recommendation_rules_loaded",
+			Help: "Number of recommendation rules currently loaded",
+		},
+	)
+
+	// RuleReloads counts loads of the recommendation rules file by outcome
+	RuleReloads = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "coordination_engine_recommendation_rule_reloads_total",
+			Help: "Total number of recommendation rules file loads, by outcome (success, failure)",
+		},
+		[]string{"outcome"},
+	)
+
+	// RuleMatches counts recommendations each rule matched
+	RuleMatches = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "coordination_engine_recommendation_rule_matches_total",
+			Help: "Total number of recommendations matched by each recommendation rule",
+		},
+		[]string{"rule"},
+	)
+)
+
+// RecordReload counts a load of the rules file
+func RecordReload(outcome string) {
+	RuleReloads.WithLabelValues(outcome).Inc()
+}
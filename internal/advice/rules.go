@@ -0,0 +1,279 @@
+// Package advice recommends remediation actions from operator-defined rules, so SREs
+// can add organization-specific advice to the engine's built-in actions without a code
+// change. Rules match an issue by type, namespace, severity and metric thresholds and
+// add weighted actions; the rules file is reloaded when it changes.
+package advice
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"sort"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// Metrics rule conditions can compare
+const (
+	// MetricConfidence is the confidence of the recommendation (0-1)
+	MetricConfidence = "confidence"
+
+	// MetricCPUUtilization and MetricMemoryUtilization are the rolling mean usage of the
+	// recommendation's namespace as a share of allocatable capacity (0-1)
+	MetricCPUUtilization    = "cpu_utilization"
+	MetricMemoryUtilization = "memory_utilization"
+)
+
+// Metrics lists the metrics rule conditions can compare
+var Metrics = []string{MetricConfidence, MetricCPUUtilization, MetricMemoryUtilization}
+
+// BuiltinWeight is the weight of each built-in action
+const BuiltinWeight = 1.0
+
+// Condition compares a metric with a value, e.g. memory_utilization > 0.9
+type Condition struct {
+	Metric string `json:"metric"`
+
+	// Op is one of >, >=, <, <=, == and !=
+	Op    string  `json:"op"`
+	Value float64 `json:"value"`
+}
+
+// Action is a recommended action and its weight. Actions are ordered by the sum of
+// their weights over the built-in actions and every matching rule.
+type Action struct {
+	Action string `json:"action"`
+
+	// Weight defaults to BuiltinWeight
+	Weight float64 `json:"weight,omitempty"`
+}
+
+// Rule adds actions to the recommendations it matches. It matches by issue type,
+// namespace pattern, severity and conditions; a rule without them matches every
+// recommendation.
+type Rule struct {
+	// Name identifies the rule in errors, logs and evidence
+	Name string `json:"name"`
+
+	// IssueTypes match recommendations for one of these issue types
+	IssueTypes []string `json:"issue_types,omitempty"`
+
+	// Namespaces are patterns of the recommendation's namespace, e.g. "payments-*"
+	// (see path.Match)
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// Severities match recommendations with one of these severities
+	Severities []string `json:"severities,omitempty"`
+
+	// When are conditions over metrics that must all hold. A metric that cannot be
+	// read fails its condition.
+	When []Condition `json:"when,omitempty"`
+
+	// Actions are added to matching recommendations
+	Actions []Action `json:"actions"`
+
+	// Replace drops the built-in actions of matching recommendations
+	Replace bool `json:"replace,omitempty"`
+}
+
+// rulesFile is the on-disk format of the rules
+type rulesFile struct {
+	Rules []Rule `json:"rules"`
+}
+
+// Subject is what rules are evaluated against: a recommendation's issue and a lookup
+// of its metrics
+type Subject struct {
+	IssueType string
+	Namespace string
+	Severity  string
+
+	// Metric returns the value of one of Metrics, or false when it is unavailable
+	Metric func(name string) (float64, bool)
+}
+
+// Result is the outcome of evaluating rules
+type Result struct {
+	// Actions are ordered by total weight, highest first
+	Actions []string
+
+	// Matched names the rules that matched, in file order
+	Matched []string
+}
+
+// Rules is a validated, immutable set of rules
+type Rules struct {
+	rules []Rule
+}
+
+// LoadRules reads rules from a YAML or JSON file
+func LoadRules(path string) (*Rules, error) {
+	data, err := os.ReadFile(path) //#nosec G304 -- path comes from operator configuration
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recommendation rules file: %w", err)
+	}
+
+	var file rulesFile
+	if err := yaml.UnmarshalStrict(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse recommendation rules file %s: %w", path, err)
+	}
+	return NewRules(file.Rules)
+}
+
+// NewRules validates the rules and fills in default weights
+func NewRules(rules []Rule) (*Rules, error) {
+	names := make(map[string]bool, len(rules))
+	for i := range rules {
+		rule := &rules[i]
+		if rule.Name == "" {
+			return nil, fmt.Errorf("recommendation rule %d: name is required", i)
+		}
+		if names[rule.Name] {
+			return nil, fmt.Errorf("recommendation rule %s: duplicate name", rule.Name)
+		}
+		names[rule.Name] = true
+
+		if len(rule.Actions) == 0 {
+			return nil, fmt.Errorf("recommendation rule %s: actions are required", rule.Name)
+		}
+		for j := range rule.Actions {
+			action := &rule.Actions[j]
+			if action.Action == "" {
+				return nil, fmt.Errorf("recommendation rule %s: action %d: action is required", rule.Name, j)
+			}
+			if action.Weight < 0 {
+				return nil, fmt.Errorf("recommendation rule %s: action %s: weight cannot be negative: %g", rule.Name, action.Action, action.Weight)
+			}
+			if action.Weight == 0 {
+				action.Weight = BuiltinWeight
+			}
+		}
+		for _, pattern := range rule.Namespaces {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("recommendation rule %s: invalid namespace pattern %q", rule.Name, pattern)
+			}
+		}
+		for _, severity := range rule.Severities {
+			if !models.IsValidSeverity(severity) {
+				return nil, fmt.Errorf("recommendation rule %s: invalid severity %q", rule.Name, severity)
+			}
+		}
+		for _, condition := range rule.When {
+			if !isMetric(condition.Metric) {
+				return nil, fmt.Errorf("recommendation rule %s: unknown metric %q, must be one of %v", rule.Name, condition.Metric, Metrics)
+			}
+			if _, ok := operators[condition.Op]; !ok {
+				return nil, fmt.Errorf("recommendation rule %s: invalid operator %q for %s", rule.Name, condition.Op, condition.Metric)
+			}
+		}
+	}
+
+	return &Rules{rules: rules}, nil
+}
+
+// Len returns the number of rules
+func (r *Rules) Len() int {
+	if r == nil {
+		return 0
+	}
+	return len(r.rules)
+}
+
+// Evaluate combines the built-in actions of a subject with the actions of every
+// matching rule. Each built-in action weighs BuiltinWeight; an action recommended
+// more than once weighs the sum of its weights. Ties keep the built-in order, then the
+// order of the rules.
+func (r *Rules) Evaluate(subject Subject, builtin []string) Result {
+	var matched []*Rule
+	replace := false
+	for i := 0; i < r.Len(); i++ {
+		rule := &r.rules[i]
+		if rule.matches(subject) {
+			matched = append(matched, rule)
+			replace = replace || rule.Replace
+		}
+	}
+
+	weights := make(map[string]float64)
+	var order []string
+	add := func(action string, weight float64) {
+		if _, ok := weights[action]; !ok {
+			order = append(order, action)
+		}
+		weights[action] += weight
+	}
+	if !replace {
+		for _, action := range builtin {
+			add(action, BuiltinWeight)
+		}
+	}
+
+	result := Result{}
+	for _, rule := range matched {
+		result.Matched = append(result.Matched, rule.Name)
+		for _, action := range rule.Actions {
+			add(action.Action, action.Weight)
+		}
+	}
+	sort.SliceStable(order, func(i, j int) bool { return weights[order[i]] > weights[order[j]] })
+	result.Actions = order
+	return result
+}
+
+// matches reports whether the rule's conditions all hold for the subject
+func (r *Rule) matches(subject Subject) bool {
+	if len(r.IssueTypes) > 0 && !contains(r.IssueTypes, subject.IssueType) {
+		return false
+	}
+	if len(r.Namespaces) > 0 && !matchesAny(r.Namespaces, subject.Namespace) {
+		return false
+	}
+	if len(r.Severities) > 0 && !contains(r.Severities, subject.Severity) {
+		return false
+	}
+	for _, condition := range r.When {
+		if subject.Metric == nil {
+			return false
+		}
+		value, ok := subject.Metric(condition.Metric)
+		if !ok || !operators[condition.Op](value, condition.Value) {
+			return false
+		}
+	}
+	return true
+}
+
+// operators compare a metric's value with a condition's value
+var operators = map[string]func(value, threshold float64) bool{
+	">":  func(value, threshold float64) bool { return value > threshold },
+	">=": func(value, threshold float64) bool { return value >= threshold },
+	"<":  func(value, threshold float64) bool { return value < threshold },
+	"<=": func(value, threshold float64) bool { return value <= threshold },
+	"==": func(value, threshold float64) bool { return value == threshold },
+	"!=": func(value, threshold float64) bool { return value != threshold },
+}
+
+func isMetric(name string) bool {
+	return contains(Metrics, name)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesAny reports whether a namespace matches one of the patterns
+func matchesAny(patterns []string, namespace string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, namespace); matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,129 @@
+package advice
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const rulesYAML = `
+rules:
+  - name: payments-memory
+    issue_types: [memory_pressure]
+    namespaces: ["payments-*"]
+    when:
+      - metric: memory_utilization
+        op: ">"
+        value: 0.8
+    actions:
+      - action: page_payments_oncall
+        weight: 3
+      - action: optimize_memory_usage
+  - name: critical-escalation
+    severities: [critical]
+    actions:
+      - action: open_major_incident
+        weight: 0.5
+  - name: batch-ignore
+    namespaces: ["batch-*"]
+    replace: true
+    actions:
+      - action: rerun_job
+`
+
+func metrics(values map[string]float64) func(string) (float64, bool) {
+	return func(name string) (float64, bool) {
+		value, ok := values[name]
+		return value, ok
+	}
+}
+
+func TestRules_Evaluate(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "rules.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(rulesYAML), 0o600))
+	rules, err := LoadRules(file)
+	require.NoError(t, err)
+	assert.Equal(t, 3, rules.Len())
+
+	builtin := []string{"increase_memory_limit", "add_horizontal_scaling", "optimize_memory_usage"}
+	subject := Subject{
+		IssueType: "memory_pressure",
+		Namespace: "payments-prod",
+		Severity:  "critical",
+		Metric:    metrics(map[string]float64{MetricMemoryUtilization: 0.92}),
+	}
+	result := rules.Evaluate(subject, builtin)
+	assert.Equal(t, []string{"payments-memory", "critical-escalation"}, result.Matched)
+	assert.Equal(t, []string{"page_payments_oncall", "optimize_memory_usage", "increase_memory_limit", "add_horizontal_scaling", "open_major_incident"},
+		result.Actions, "weights add up and ties keep the built-in order")
+
+	// A condition fails below its threshold and when its metric is unavailable
+	subject.Metric = metrics(map[string]float64{MetricMemoryUtilization: 0.5})
+	assert.Equal(t, []string{"critical-escalation"}, rules.Evaluate(subject, builtin).Matched)
+	subject.Metric = nil
+	assert.Equal(t, []string{"critical-escalation"}, rules.Evaluate(subject, builtin).Matched)
+
+	// Replacing rules drop the built-in actions
+	result = rules.Evaluate(Subject{IssueType: "pod_crash_loop", Namespace: "batch-etl", Severity: "low"}, []string{"check_container_logs"})
+	assert.Equal(t, []string{"rerun_job"}, result.Actions)
+
+	assert.Equal(t, builtin, rules.Evaluate(Subject{IssueType: "cpu_throttling", Namespace: "web"}, builtin).Actions)
+}
+
+func TestNewRules_Validation(t *testing.T) {
+	actions := []Action{{Action: "a"}}
+	for _, tc := range []struct {
+		rule Rule
+		err  string
+	}{
+		{Rule{Actions: actions}, "recommendation rule 0: name is required"},
+		{Rule{Name: "a"}, "recommendation rule a: actions are required"},
+		{Rule{Name: "a", Actions: []Action{{Weight: 1}}}, "recommendation rule a: action 0: action is required"},
+		{Rule{Name: "a", Actions: []Action{{Action: "b", Weight: -1}}}, "recommendation rule a: action b: weight cannot be negative: -1"},
+		{Rule{Name: "a", Actions: actions, Namespaces: []string{"["}}, `recommendation rule a: invalid namespace pattern "["`},
+		{Rule{Name: "a", Actions: actions, Severities: []string{"urgent"}}, `recommendation rule a: invalid severity "urgent"`},
+		{Rule{Name: "a", Actions: actions, When: []Condition{{Metric: "latency", Op: ">"}}}, `recommendation rule a: unknown metric "latency"`},
+		{Rule{Name: "a", Actions: actions, When: []Condition{{Metric: "confidence", Op: "=>"}}}, `recommendation rule a: invalid operator "=>" for confidence`},
+	} {
+		_, err := NewRules([]Rule{tc.rule})
+		assert.ErrorContains(t, err, tc.err)
+	}
+
+	_, err := NewRules([]Rule{{Name: "a", Actions: actions}, {Name: "a", Actions: actions}})
+	assert.EqualError(t, err, "recommendation rule a: duplicate name")
+}
+
+func TestEngine_Reload(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.PanicLevel)
+	file := filepath.Join(t.TempDir(), "rules.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(rulesYAML), 0o600))
+	engine, err := NewEngine(file, time.Minute, log)
+	require.NoError(t, err)
+	assert.Equal(t, 3, engine.Len())
+	assert.False(t, engine.Reload(), "an unchanged file is not reloaded")
+
+	touch := func(content string, at time.Time) {
+		require.NoError(t, os.WriteFile(file, []byte(content), 0o600))
+		require.NoError(t, os.Chtimes(file, at, at))
+	}
+	touch("rules:\n  - name: only\n    actions: [{action: a}]\n", time.Now().Add(time.Minute))
+	assert.True(t, engine.Reload())
+	assert.Equal(t, 1, engine.Len())
+
+	// An invalid version keeps the previous rules
+	touch("rules:\n  - name: only\n", time.Now().Add(2*time.Minute))
+	assert.False(t, engine.Reload())
+	assert.Equal(t, []string{"a"}, engine.Evaluate(Subject{}, nil).Actions)
+
+	_, err = NewEngine(filepath.Join(t.TempDir(), "missing.yaml"), 0, log)
+	assert.Error(t, err)
+
+	var disabled *Engine
+	assert.Equal(t, []string{"b"}, disabled.Evaluate(Subject{}, []string{"b"}).Actions)
+}
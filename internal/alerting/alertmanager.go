@@ -0,0 +1,183 @@
+// Package alerting turns Alertmanager webhook notifications into incidents.
+//
+// Alertmanager re-sends grouped alerts on every repeat interval. The Ingester folds
+// re-fires of an alert into its open incident (matched by fingerprint and namespace)
+// and caps the number of new incidents per namespace per hour, aggregating the overflow
+// into a single flood incident.
+package alerting
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// Alert statuses sent by Alertmanager
+const (
+	StatusFiring   = "firing"
+	StatusResolved = "resolved"
+)
+
+// WebhookMessage is the Alertmanager webhook payload (version 4)
+type WebhookMessage struct {
+	Version           string            `json:"version"`
+	GroupKey          string            `json:"groupKey"`
+	Status            string            `json:"status"`
+	Receiver          string            `json:"receiver"`
+	GroupLabels       map[string]string `json:"groupLabels"`
+	CommonLabels      map[string]string `json:"commonLabels"`
+	CommonAnnotations map[string]string `json:"commonAnnotations"`
+	ExternalURL       string            `json:"externalURL"`
+	Alerts            []Alert           `json:"alerts"`
+}
+
+// Alert is a single alert in a webhook payload
+type Alert struct {
+	Status       string            `json:"status"`
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	StartsAt     time.Time         `json:"startsAt"`
+	EndsAt       time.Time         `json:"endsAt"`
+	GeneratorURL string            `json:"generatorURL"`
+	Fingerprint  string            `json:"fingerprint"`
+}
+
+// AlertFingerprint returns the Alertmanager fingerprint, or a hash of the sorted label
+// set for senders that do not include one
+func (a *Alert) AlertFingerprint() string {
+	if a.Fingerprint != "" {
+		return a.Fingerprint
+	}
+
+	names := make([]string, 0, len(a.Labels))
+	for name := range a.Labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	h := sha256.New()
+	for _, name := range names {
+		h.Write([]byte(name))
+		h.Write([]byte{0})
+		h.Write([]byte(a.Labels[name]))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// Scope returns the namespace the alert belongs to, or "cluster" for cluster-wide alerts
+func (a *Alert) Scope() string {
+	if ns := a.Labels["namespace"]; ns != "" {
+		return ns
+	}
+	return "cluster"
+}
+
+// Severity maps the alert's severity label to an incident severity
+func (a *Alert) Severity() models.IncidentSeverity {
+	switch strings.ToLower(a.Labels["severity"]) {
+	case "critical":
+		return models.IncidentSeverityCritical
+	case "high", "error", "major":
+		return models.IncidentSeverityHigh
+	case "warning", "medium":
+		return models.IncidentSeverityMedium
+	default:
+		return models.IncidentSeverityLow
+	}
+}
+
+// Name returns the alertname label
+func (a *Alert) Name() string {
+	if name := a.Labels["alertname"]; name != "" {
+		return name
+	}
+	return "UnnamedAlert"
+}
+
+// resourceLabels are the workload labels reported as affected resources, in order
+var resourceLabels = []string{"deployment", "statefulset", "daemonset", "job_name", "pod"}
+
+// AnnotationTagPrefix marks alert annotations copied to incident tags without the
+// prefix, e.g. tag_cost_center: cc-42 tags the incident cost_center=cc-42
+const AnnotationTagPrefix = "tag_"
+
+// tags returns the incident tags of an alert: the values of tagLabels it has, then its
+// tag annotations, which win over labels
+func (a *Alert) tags(tagLabels []string) map[string]string {
+	tags := make(map[string]string)
+	for _, name := range tagLabels {
+		if value := a.Labels[name]; value != "" {
+			tags[name] = value
+		}
+	}
+	for name, value := range a.Annotations {
+		key := strings.TrimPrefix(name, AnnotationTagPrefix)
+		if key == name || !models.IsValidTagKey(key) || value == "" {
+			continue
+		}
+		tags[key] = truncate(value, models.MaxIncidentTagValueLen)
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+	return tags
+}
+
+// toIncident builds a new incident from a firing alert, tagged with the alert's
+// tagLabels and tag annotations
+func (a *Alert) toIncident(tagLabels []string) *models.Incident {
+	title := a.Annotations["summary"]
+	if title == "" {
+		title = a.Name()
+	}
+
+	description := a.Annotations["description"]
+	if description == "" {
+		description = a.Annotations["message"]
+	}
+	if description == "" {
+		description = "Alertmanager alert " + a.Name() + " is firing"
+	}
+
+	labels := make(map[string]string, len(a.Labels)+2)
+	for name, value := range a.Labels {
+		labels[name] = value
+	}
+	labels[LabelFingerprint] = a.AlertFingerprint()
+	labels[LabelSource] = SourceAlertmanager
+
+	var resources []string
+	for _, name := range resourceLabels {
+		if value := a.Labels[name]; value != "" {
+			resources = append(resources, strings.TrimSuffix(name, "_name")+"/"+value)
+		}
+	}
+
+	return &models.Incident{
+		Title:             truncate(title, 200),
+		Description:       truncate(description, 2000),
+		Severity:          a.Severity(),
+		Target:            a.Scope(),
+		AffectedResources: resources,
+		Labels:            labels,
+		Tags:              a.tags(tagLabels),
+	}
+}
+
+// truncate shortens s to at most n bytes without splitting a UTF-8 sequence
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	cut := n
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut]
+}
@@ -0,0 +1,317 @@
+package alerting
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/storage"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// Labels set on incidents created from alerts
+const (
+	// LabelFingerprint holds the fingerprint of the alert that opened the incident
+	LabelFingerprint = "alert_fingerprint"
+	// LabelSource identifies where the incident came from
+	LabelSource = "source"
+	// LabelOverflow marks the per-namespace incident that aggregates flood-controlled alerts
+	LabelOverflow = "alert_overflow"
+	// LabelAggregated counts the distinct alerts aggregated into an overflow incident
+	LabelAggregated = "aggregated_alerts"
+
+	// SourceAlertmanager is the LabelSource value for alert incidents
+	SourceAlertmanager = "alertmanager"
+)
+
+// Defaults for deduplication and flood control
+const (
+	DefaultDedupWindow = time.Hour
+	DefaultFloodLimit  = 20
+
+	// floodWindow is the period flood control counts new incidents over
+	floodWindow = time.Hour
+)
+
+// Outcome describes what happened to a single alert
+type Outcome string
+
+// Alert outcomes
+const (
+	OutcomeCreated      Outcome = "created"
+	OutcomeDeduplicated Outcome = "deduplicated"
+	OutcomeAggregated   Outcome = "aggregated"
+	OutcomeResolved     Outcome = "resolved"
+	OutcomeIgnored      Outcome = "ignored"
+)
+
+// Result summarizes the handling of a webhook message
+type Result struct {
+	Created      int      `json:"created"`
+	Deduplicated int      `json:"deduplicated"`
+	Aggregated   int      `json:"aggregated"`
+	Resolved     int      `json:"resolved"`
+	Ignored      int      `json:"ignored"`
+	IncidentIDs  []string `json:"incident_ids"`
+}
+
+// add records one alert's outcome
+func (r *Result) add(outcome Outcome, incidentID string) {
+	switch outcome {
+	case OutcomeCreated:
+		r.Created++
+	case OutcomeDeduplicated:
+		r.Deduplicated++
+	case OutcomeAggregated:
+		r.Aggregated++
+	case OutcomeResolved:
+		r.Resolved++
+	case OutcomeIgnored:
+		r.Ignored++
+	}
+	if incidentID == "" {
+		return
+	}
+	for _, id := range r.IncidentIDs {
+		if id == incidentID {
+			return
+		}
+	}
+	r.IncidentIDs = append(r.IncidentIDs, incidentID)
+}
+
+// Ingester creates, deduplicates and resolves incidents from Alertmanager alerts
+type Ingester struct {
+	store       *storage.IncidentStore
+	dedupWindow time.Duration
+	floodLimit  int
+	log         *logrus.Logger
+
+	// tagLabels are the alert labels copied to incident tags (see SetTagLabels)
+	tagLabels []string
+
+	// mu serializes ingestion so concurrent deliveries of the same alert do not both
+	// open an incident
+	mu sync.Mutex
+
+	// lastSeen records when each alert incident last fired. Re-fires do not update the
+	// stored incident, so after a restart UpdatedAt is used instead.
+	lastSeen map[string]time.Time
+
+	now func() time.Time
+}
+
+// NewIngester creates an ingester. Re-fires within dedupWindow of an incident's last
+// firing are folded into it; floodLimit caps new incidents per namespace per hour
+// (0 disables flood control).
+func NewIngester(store *storage.IncidentStore, dedupWindow time.Duration, floodLimit int, log *logrus.Logger) *Ingester {
+	if dedupWindow <= 0 {
+		dedupWindow = DefaultDedupWindow
+	}
+	return &Ingester{
+		store:       store,
+		dedupWindow: dedupWindow,
+		floodLimit:  floodLimit,
+		log:         log,
+		lastSeen:    make(map[string]time.Time),
+		now:         time.Now,
+	}
+}
+
+// SetTagLabels copies the values of these alert labels, e.g. team or service, to the
+// tags of incidents created from alerts. Tag annotations are always copied.
+func (i *Ingester) SetTagLabels(labels []string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.tagLabels = labels
+}
+
+// Ingest handles every alert in a webhook message
+func (i *Ingester) Ingest(msg *WebhookMessage) (*Result, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	result := &Result{IncidentIDs: []string{}}
+	for idx := range msg.Alerts {
+		alert := &msg.Alerts[idx]
+		outcome, incidentID, err := i.ingestAlert(alert)
+		if err != nil {
+			return result, fmt.Errorf("alert %s: %w", alert.Name(), err)
+		}
+		AlertsIngested.WithLabelValues(string(outcome)).Inc()
+		result.add(outcome, incidentID)
+
+		i.log.WithFields(logrus.Fields{
+			"alertname":   alert.Name(),
+			"fingerprint": alert.AlertFingerprint(),
+			"namespace":   alert.Scope(),
+			"outcome":     outcome,
+			"incident_id": incidentID,
+		}).Debug("Alert ingested")
+	}
+	return result, nil
+}
+
+// ingestAlert handles a single alert
+func (i *Ingester) ingestAlert(alert *Alert) (Outcome, string, error) {
+	fingerprint := alert.AlertFingerprint()
+	scope := alert.Scope()
+	now := i.now()
+
+	open := i.findOpen(scope, fingerprint)
+
+	if alert.Status == StatusResolved {
+		if open == nil {
+			return OutcomeIgnored, "", nil
+		}
+		resolved := *open
+		resolved.Events = append([]models.IncidentEvent(nil), open.Events...)
+		resolved.Resolve()
+		resolved.AddEvent(models.IncidentEventResolved, "Alert "+alert.Name()+" resolved in Alertmanager")
+		if err := i.store.Update(&resolved); err != nil {
+			return "", "", fmt.Errorf("failed to resolve incident: %w", err)
+		}
+		delete(i.lastSeen, open.ID)
+		return OutcomeResolved, open.ID, nil
+	}
+
+	if open != nil && now.Sub(i.lastFired(open)) <= i.dedupWindow {
+		i.lastSeen[open.ID] = now
+		return OutcomeDeduplicated, open.ID, nil
+	}
+
+	overflow := i.findOverflow(scope)
+	if overflow != nil && aggregates(overflow, fingerprint) {
+		return OutcomeDeduplicated, overflow.ID, nil
+	}
+
+	if i.floodLimit > 0 && i.recentlyCreated(scope, now) >= i.floodLimit {
+		id, err := i.aggregate(overflow, alert, scope)
+		if err != nil {
+			return "", "", err
+		}
+		return OutcomeAggregated, id, nil
+	}
+
+	incident, err := i.store.Create(alert.toIncident(i.tagLabels))
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create incident: %w", err)
+	}
+	i.lastSeen[incident.ID] = now
+	return OutcomeCreated, incident.ID, nil
+}
+
+// findOpen returns the active incident opened by the alert in the scope, if any
+func (i *Ingester) findOpen(scope, fingerprint string) *models.Incident {
+	var newest *models.Incident
+	for _, incident := range i.store.List(storage.ListFilter{Namespace: scope, Status: string(models.IncidentStatusActive)}) {
+		if incident.Labels[LabelFingerprint] != fingerprint {
+			continue
+		}
+		if newest == nil || incident.CreatedAt.After(newest.CreatedAt) {
+			newest = incident
+		}
+	}
+	return newest
+}
+
+// findOverflow returns the active flood incident for the scope, if any
+func (i *Ingester) findOverflow(scope string) *models.Incident {
+	for _, incident := range i.store.List(storage.ListFilter{Namespace: scope, Status: string(models.IncidentStatusActive)}) {
+		if incident.Labels[LabelOverflow] == "true" {
+			return incident
+		}
+	}
+	return nil
+}
+
+// lastFired returns when an alert incident last fired
+func (i *Ingester) lastFired(incident *models.Incident) time.Time {
+	if seen, ok := i.lastSeen[incident.ID]; ok {
+		return seen
+	}
+	return incident.UpdatedAt
+}
+
+// recentlyCreated counts alert incidents opened in the scope during the flood window
+func (i *Ingester) recentlyCreated(scope string, now time.Time) int {
+	count := 0
+	for _, incident := range i.store.List(storage.ListFilter{Namespace: scope}) {
+		if incident.Labels[LabelFingerprint] != "" && now.Sub(incident.CreatedAt) < floodWindow {
+			count++
+		}
+	}
+	return count
+}
+
+// aggregate records the alert on the scope's flood incident, creating it if needed.
+// The flood incident takes the highest severity of the alerts it aggregates.
+func (i *Ingester) aggregate(overflow *models.Incident, alert *Alert, scope string) (string, error) {
+	if overflow == nil {
+		created, err := i.store.Create(&models.Incident{
+			Title:    truncate("Alert flood in "+scope+": new incidents suppressed", 200),
+			Severity: alert.Severity(),
+			Target:   scope,
+			Description: fmt.Sprintf(
+				"More than %d incidents were opened in %s within an hour. Further alerts are aggregated into this incident until it is resolved.",
+				i.floodLimit, scope),
+			Labels: map[string]string{
+				LabelOverflow:   "true",
+				LabelSource:     SourceAlertmanager,
+				LabelAggregated: "0",
+			},
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to create flood incident: %w", err)
+		}
+		overflow = created
+		i.log.WithFields(logrus.Fields{
+			"namespace":   scope,
+			"incident_id": overflow.ID,
+			"flood_limit": i.floodLimit,
+		}).Warn("Alert flood control engaged, aggregating new alerts")
+	}
+
+	updated := *overflow
+	updated.Labels = make(map[string]string, len(overflow.Labels))
+	for name, value := range overflow.Labels {
+		updated.Labels[name] = value
+	}
+	updated.Events = append([]models.IncidentEvent(nil), overflow.Events...)
+
+	updated.AddEvent(models.IncidentEventAggregated, aggregatedMessage(alert))
+	count, _ := strconv.Atoi(updated.Labels[LabelAggregated])
+	updated.Labels[LabelAggregated] = strconv.Itoa(count + 1)
+	if alert.Severity().Rank() > updated.Severity.Rank() {
+		updated.Severity = alert.Severity()
+	}
+	if err := i.store.Update(&updated); err != nil {
+		return "", fmt.Errorf("failed to update flood incident: %w", err)
+	}
+	return updated.ID, nil
+}
+
+// aggregatedMessage describes an alert in the flood incident timeline. The fingerprint
+// suffix lets re-fires of an aggregated alert be recognized.
+func aggregatedMessage(alert *Alert) string {
+	title := alert.Annotations["summary"]
+	if title == "" {
+		title = alert.Name()
+	}
+	return fmt.Sprintf("[%s] %s (fingerprint %s)", alert.Severity(), title, alert.AlertFingerprint())
+}
+
+// aggregates reports whether the flood incident already holds the alert
+func aggregates(overflow *models.Incident, fingerprint string) bool {
+	suffix := "(fingerprint " + fingerprint + ")"
+	for _, event := range overflow.Events {
+		if event.Type == models.IncidentEventAggregated && strings.HasSuffix(event.Message, suffix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,227 @@
+package alerting
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/storage"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+func newTestIngester(t *testing.T, floodLimit int) (*Ingester, *storage.IncidentStore) {
+	t.Helper()
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	store := storage.NewIncidentStoreWithPath(t.TempDir())
+	return NewIngester(store, 30*time.Minute, floodLimit, log), store
+}
+
+func firing(name, namespace, fingerprint string) Alert {
+	return Alert{
+		Status: StatusFiring,
+		Labels: map[string]string{
+			"alertname":  name,
+			"namespace":  namespace,
+			"deployment": "api",
+			"severity":   "warning",
+		},
+		Annotations: map[string]string{
+			"summary":     name + " in " + namespace,
+			"description": "Pods are restarting",
+		},
+		StartsAt:    time.Now(),
+		Fingerprint: fingerprint,
+	}
+}
+
+func resolvedAlert(alert Alert) Alert {
+	alert.Status = StatusResolved
+	alert.EndsAt = time.Now()
+	return alert
+}
+
+func TestIngester_CreatesIncident(t *testing.T) {
+	ingester, store := newTestIngester(t, 0)
+
+	result, err := ingester.Ingest(&WebhookMessage{Alerts: []Alert{firing("KubePodCrashLooping", "payments", "a1")}})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Created)
+	require.Len(t, result.IncidentIDs, 1)
+
+	incident, err := store.Get(result.IncidentIDs[0])
+	require.NoError(t, err)
+	assert.Equal(t, "KubePodCrashLooping in payments", incident.Title)
+	assert.Equal(t, "Pods are restarting", incident.Description)
+	assert.Equal(t, "payments", incident.Target)
+	assert.Equal(t, models.IncidentSeverityMedium, incident.Severity)
+	assert.Equal(t, []string{"deployment/api"}, incident.AffectedResources)
+	assert.Equal(t, "a1", incident.Labels[LabelFingerprint])
+	assert.Equal(t, "KubePodCrashLooping", incident.Labels["alertname"])
+	assert.Equal(t, SourceAlertmanager, incident.Labels[LabelSource])
+}
+
+func TestIngester_DeduplicatesRefires(t *testing.T) {
+	ingester, store := newTestIngester(t, 0)
+	alert := firing("KubePodCrashLooping", "payments", "a1")
+
+	first, err := ingester.Ingest(&WebhookMessage{Alerts: []Alert{alert}})
+	require.NoError(t, err)
+
+	// Alertmanager re-sends the group on every repeat interval
+	for n := 0; n < 3; n++ {
+		result, err := ingester.Ingest(&WebhookMessage{Alerts: []Alert{alert}})
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Deduplicated)
+		assert.Equal(t, first.IncidentIDs, result.IncidentIDs)
+	}
+	assert.Equal(t, 1, store.Count())
+
+	// The same fingerprint in another namespace is a different incident
+	result, err := ingester.Ingest(&WebhookMessage{Alerts: []Alert{firing("KubePodCrashLooping", "checkout", "a1")}})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Created)
+
+	// Alerts without a fingerprint are matched on their label set
+	unfingerprinted := firing("KubeJobFailed", "payments", "")
+	_, err = ingester.Ingest(&WebhookMessage{Alerts: []Alert{unfingerprinted, unfingerprinted}})
+	require.NoError(t, err)
+	assert.Equal(t, 3, store.Count())
+}
+
+func TestIngester_DedupWindowExpires(t *testing.T) {
+	ingester, store := newTestIngester(t, 0)
+	alert := firing("KubePodCrashLooping", "payments", "a1")
+
+	first, err := ingester.Ingest(&WebhookMessage{Alerts: []Alert{alert}})
+	require.NoError(t, err)
+
+	// An alert silent for longer than the window opens a fresh incident
+	ingester.now = func() time.Time { return time.Now().Add(time.Hour) }
+	result, err := ingester.Ingest(&WebhookMessage{Alerts: []Alert{alert}})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Created)
+	assert.NotEqual(t, first.IncidentIDs, result.IncidentIDs)
+	assert.Equal(t, 2, store.Count())
+
+	// Later re-fires match the newest incident
+	result, err = ingester.Ingest(&WebhookMessage{Alerts: []Alert{alert}})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Deduplicated)
+}
+
+func TestIngester_ResolvesIncident(t *testing.T) {
+	ingester, store := newTestIngester(t, 0)
+	alert := firing("KubePodCrashLooping", "payments", "a1")
+
+	created, err := ingester.Ingest(&WebhookMessage{Alerts: []Alert{alert}})
+	require.NoError(t, err)
+
+	result, err := ingester.Ingest(&WebhookMessage{Status: StatusResolved, Alerts: []Alert{resolvedAlert(alert)}})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Resolved)
+
+	incident, err := store.Get(created.IncidentIDs[0])
+	require.NoError(t, err)
+	assert.Equal(t, models.IncidentStatusResolved, incident.Status)
+	require.NotEmpty(t, incident.Events)
+	assert.Equal(t, models.IncidentEventResolved, incident.Events[len(incident.Events)-1].Type)
+
+	// A repeated resolve notification has nothing left to resolve
+	result, err = ingester.Ingest(&WebhookMessage{Status: StatusResolved, Alerts: []Alert{resolvedAlert(alert)}})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Ignored)
+
+	// Firing again after resolution opens a new incident
+	result, err = ingester.Ingest(&WebhookMessage{Alerts: []Alert{alert}})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Created)
+}
+
+func TestIngester_FloodControl(t *testing.T) {
+	ingester, store := newTestIngester(t, 3)
+
+	var alerts []Alert
+	for n := 0; n < 6; n++ {
+		alert := firing(fmt.Sprintf("Alert%d", n), "payments", fmt.Sprintf("f%d", n))
+		if n == 5 {
+			alert.Labels["severity"] = "critical"
+		}
+		alerts = append(alerts, alert)
+	}
+
+	result, err := ingester.Ingest(&WebhookMessage{Alerts: alerts})
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Created)
+	assert.Equal(t, 3, result.Aggregated)
+	assert.Len(t, result.IncidentIDs, 4, "overflow alerts share one flood incident")
+	assert.Equal(t, 4, store.Count())
+
+	var flood *models.Incident
+	for _, incident := range store.List(storage.ListFilter{Namespace: "payments"}) {
+		if incident.Labels[LabelOverflow] == "true" {
+			flood = incident
+		}
+	}
+	require.NotNil(t, flood)
+	assert.Equal(t, "3", flood.Labels[LabelAggregated])
+	assert.Equal(t, models.IncidentSeverityCritical, flood.Severity, "the flood incident takes the highest severity")
+	require.Len(t, flood.Events, 3)
+	assert.True(t, strings.HasSuffix(flood.Events[0].Message, "(fingerprint f3)"))
+
+	// Re-fires of aggregated alerts do not grow the flood incident
+	result, err = ingester.Ingest(&WebhookMessage{Alerts: alerts[3:]})
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Deduplicated)
+	flood, err = store.Get(flood.ID)
+	require.NoError(t, err)
+	assert.Len(t, flood.Events, 3)
+
+	// Other namespaces have their own budget
+	result, err = ingester.Ingest(&WebhookMessage{Alerts: []Alert{firing("Alert0", "checkout", "c0")}})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Created)
+}
+
+func TestAlert_Mapping(t *testing.T) {
+	alert := Alert{Labels: map[string]string{"alertname": "NodeNotReady", "severity": "CRITICAL"}}
+	assert.Equal(t, "cluster", alert.Scope())
+	assert.Equal(t, models.IncidentSeverityCritical, alert.Severity())
+	assert.Len(t, alert.AlertFingerprint(), 16)
+
+	reordered := Alert{Labels: map[string]string{"severity": "CRITICAL", "alertname": "NodeNotReady"}}
+	assert.Equal(t, alert.AlertFingerprint(), reordered.AlertFingerprint())
+
+	incident := alert.toIncident(nil)
+	assert.Equal(t, "NodeNotReady", incident.Title)
+	assert.Equal(t, "Alertmanager alert NodeNotReady is firing", incident.Description)
+	require.NoError(t, incident.Validate())
+
+	assert.Nil(t, incident.Tags)
+
+	assert.Equal(t, models.IncidentSeverityLow, (&Alert{}).Severity())
+	assert.Equal(t, "ab", truncate("abé", 3), "truncation keeps UTF-8 valid")
+}
+
+func TestIngester_TagsIncident(t *testing.T) {
+	ingester, store := newTestIngester(t, 0)
+	ingester.SetTagLabels([]string{"team", "service"})
+
+	alert := firing("KubePodCrashLooping", "payments", "a1")
+	alert.Labels["team"] = "payments"
+	alert.Annotations["tag_service"] = "checkout"
+	alert.Annotations["tag_cost_center"] = "cc-42"
+	alert.Annotations["tag_bad key"] = "ignored"
+	result, err := ingester.Ingest(&WebhookMessage{Alerts: []Alert{alert}})
+	require.NoError(t, err)
+	require.Len(t, result.IncidentIDs, 1)
+
+	incident, err := store.Get(result.IncidentIDs[0])
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"team": "payments", "service": "checkout", "cost_center": "cc-42"}, incident.Tags)
+}
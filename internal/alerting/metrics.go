@@ -0,0 +1,17 @@
+package alerting
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// AlertsIngested counts Alertmanager alerts by what the ingester did with them
+	AlertsIngested = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "coordination_engine_alerts_ingested_total",
+			Help: "Total number of Alertmanager alerts received, by outcome (created, deduplicated, aggregated, resolved, ignored)",
+		},
+		[]string{"outcome"},
+	)
+)
@@ -0,0 +1,306 @@
+// Package annotation publishes the engine's actions where engineers already look:
+// Grafana dashboards and the Kubernetes Events of the affected objects.
+//
+// The Publisher turns incident creation and resolution and the start and outcome of
+// remediation workflows into annotations and hands each to every configured sink.
+// Publishing is best effort: annotations are queued, failures are logged and counted,
+// and a full queue drops annotations rather than slowing down remediation.
+package annotation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/remediation"
+	"github.com/tosin2013/openshift-coordination-engine/internal/storage"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// Reasons of published annotations, used as Kubernetes Event reasons
+const (
+	ReasonIncidentCreated      = "IncidentCreated"
+	ReasonIncidentResolved     = "IncidentResolved"
+	ReasonRemediationStarted   = "RemediationStarted"
+	ReasonRemediationSucceeded = "RemediationSucceeded"
+	ReasonRemediationFailed    = "RemediationFailed"
+)
+
+// Component is the source of published annotations
+const Component = "coordination-engine"
+
+// Defaults of the publisher
+const (
+	// DefaultQueueSize bounds the annotations waiting to be published
+	DefaultQueueSize = 256
+
+	// DefaultTimeout bounds publishing one annotation to one sink
+	DefaultTimeout = 10 * time.Second
+)
+
+// ObjectRef names an object an annotation is about, e.g. {Kind: "deployment",
+// Namespace: "payments", Name: "api"}. Kinds are lower case, as in incidents'
+// affected resources.
+type ObjectRef struct {
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+// Annotation is an engine action to publish
+type Annotation struct {
+	// Reason identifies the action, e.g. ReasonIncidentCreated
+	Reason string
+
+	// Time is when the action happened; End, if set, makes the annotation a region,
+	// e.g. an incident from creation to resolution
+	Time time.Time
+	End  time.Time
+
+	// Title summarizes the action in one line; Text adds detail
+	Title string
+	Text  string
+
+	// Warning marks actions needing attention, e.g. a failed remediation
+	Warning bool
+
+	// Tags label the annotation in Grafana, e.g. "incident" or "namespace:payments"
+	Tags []string
+
+	// Namespace is the namespace of the action, if any, and Objects the objects it
+	// affected
+	Namespace string
+	Objects   []ObjectRef
+}
+
+// Sink publishes annotations to one destination
+type Sink interface {
+	// Name identifies the sink in logs and metrics
+	Name() string
+
+	// Publish publishes one annotation
+	Publish(ctx context.Context, annotation *Annotation) error
+}
+
+// Publisher publishes incident and remediation annotations to its sinks
+type Publisher struct {
+	sinks   []Sink
+	queue   chan *Annotation
+	timeout time.Duration
+	log     *logrus.Logger
+
+	// statuses tracks the last seen status of each incident to detect resolution
+	mu       sync.Mutex
+	statuses map[string]models.IncidentStatus
+}
+
+// NewPublisher creates a publisher for the sinks
+func NewPublisher(log *logrus.Logger, sinks ...Sink) *Publisher {
+	return &Publisher{
+		sinks:    sinks,
+		queue:    make(chan *Annotation, DefaultQueueSize),
+		timeout:  DefaultTimeout,
+		log:      log,
+		statuses: make(map[string]models.IncidentStatus),
+	}
+}
+
+// SetTimeout bounds publishing one annotation to one sink (DefaultTimeout by default)
+func (p *Publisher) SetTimeout(timeout time.Duration) {
+	p.timeout = timeout
+}
+
+// Sinks returns the names of the publisher's sinks
+func (p *Publisher) Sinks() []string {
+	names := make([]string, len(p.sinks))
+	for i, sink := range p.sinks {
+		names[i] = sink.Name()
+	}
+	return names
+}
+
+// Start publishes queued annotations, and those of incident changes in store, until
+// ctx is cancelled. It subscribes to the store before returning, so no change made
+// after Start is missed.
+func (p *Publisher) Start(ctx context.Context, store *storage.IncidentStore) {
+	changes, unsubscribe := store.Subscribe(DefaultQueueSize)
+
+	go func() {
+		defer unsubscribe()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case change, ok := <-changes:
+				if !ok {
+					return
+				}
+				if annotation := p.incidentAnnotation(&change); annotation != nil {
+					p.Publish(annotation)
+				}
+			}
+		}
+	}()
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case annotation := <-p.queue:
+				p.publish(ctx, annotation)
+			}
+		}
+	}()
+
+	p.log.WithField("sinks", p.Sinks()).Info("Annotation publisher started")
+}
+
+// Publish queues an annotation, dropping it if the queue is full
+func (p *Publisher) Publish(annotation *Annotation) {
+	select {
+	case p.queue <- annotation:
+	default:
+		for _, sink := range p.sinks {
+			recordPublished(sink.Name(), annotation.Reason, OutcomeDropped)
+		}
+		p.log.WithField("reason", annotation.Reason).Warn("Annotation queue full, dropping annotation")
+	}
+}
+
+// publish hands an annotation to every sink
+func (p *Publisher) publish(ctx context.Context, annotation *Annotation) {
+	for _, sink := range p.sinks {
+		sinkCtx, cancel := context.WithTimeout(ctx, p.timeout)
+		err := sink.Publish(sinkCtx, annotation)
+		cancel()
+		if err != nil {
+			recordPublished(sink.Name(), annotation.Reason, OutcomeFailed)
+			p.log.WithError(err).WithFields(logrus.Fields{
+				"sink":   sink.Name(),
+				"reason": annotation.Reason,
+			}).Warn("Failed to publish annotation")
+			continue
+		}
+		recordPublished(sink.Name(), annotation.Reason, OutcomeSuccess)
+	}
+}
+
+// incidentAnnotation maps an incident change to an annotation, or nil for changes
+// other than creation and resolution
+func (p *Publisher) incidentAnnotation(change *storage.IncidentChange) *Annotation {
+	incident := change.Incident
+
+	p.mu.Lock()
+	previous, known := p.statuses[incident.ID]
+	if change.Type == storage.IncidentDeleted {
+		delete(p.statuses, incident.ID)
+	} else {
+		p.statuses[incident.ID] = incident.Status
+	}
+	p.mu.Unlock()
+
+	namespace := incidentNamespace(&incident)
+	annotation := &Annotation{
+		Namespace: namespace,
+		Objects:   incidentObjects(&incident, namespace),
+		Tags:      tags("incident", "severity:"+string(incident.Severity), namespace),
+	}
+	switch {
+	case change.Type == storage.IncidentCreated:
+		annotation.Reason = ReasonIncidentCreated
+		annotation.Time = incident.CreatedAt
+		annotation.Title = fmt.Sprintf("Incident %s created: %s", incident.ID, incident.Title)
+		annotation.Warning = true
+	case change.Type == storage.IncidentUpdated && incident.Status == models.IncidentStatusResolved &&
+		(!known || previous != models.IncidentStatusResolved):
+		annotation.Reason = ReasonIncidentResolved
+		annotation.Time = incident.CreatedAt
+		annotation.End = incident.UpdatedAt
+		if incident.ResolvedAt != nil {
+			annotation.End = *incident.ResolvedAt
+		}
+		annotation.Title = fmt.Sprintf("Incident %s resolved: %s", incident.ID, incident.Title)
+	default:
+		return nil
+	}
+	annotation.Text = fmt.Sprintf("Severity %s, target %s", incident.Severity, incident.Target)
+	if len(incident.AffectedResources) > 0 {
+		annotation.Text += ", affected " + strings.Join(incident.AffectedResources, ", ")
+	}
+	return annotation
+}
+
+// incidentNamespace returns the namespace of an incident: its namespace label, or else
+// its target
+func incidentNamespace(incident *models.Incident) string {
+	if namespace := incident.Labels["namespace"]; namespace != "" {
+		return namespace
+	}
+	return incident.Target
+}
+
+// incidentObjects returns the affected resources of an incident, given as kind/name
+func incidentObjects(incident *models.Incident, namespace string) []ObjectRef {
+	var objects []ObjectRef
+	for _, resource := range incident.AffectedResources {
+		kind, name, ok := strings.Cut(resource, "/")
+		if !ok || name == "" {
+			continue
+		}
+		objects = append(objects, ObjectRef{Kind: strings.ToLower(kind), Namespace: namespace, Name: name})
+	}
+	return objects
+}
+
+// WorkflowEvent publishes the start and outcome of remediation workflows; it is meant
+// as the orchestrator's event listener and does not block
+func (p *Publisher) WorkflowEvent(workflow *models.Workflow, event remediation.WorkflowEvent) {
+	annotation := &Annotation{
+		Time:      event.Time,
+		Namespace: workflow.Namespace,
+		Tags:      tags("remediation", "remediator:"+workflow.Remediator, workflow.Namespace),
+	}
+	if workflow.ResourceName != "" {
+		annotation.Objects = []ObjectRef{{Kind: strings.ToLower(workflow.ResourceKind), Namespace: workflow.Namespace, Name: workflow.ResourceName}}
+	}
+	resource := fmt.Sprintf("%s/%s", strings.ToLower(workflow.ResourceKind), workflow.ResourceName)
+	switch event.Type {
+	case remediation.EventWorkflowStarted:
+		annotation.Reason = ReasonRemediationStarted
+		annotation.Title = fmt.Sprintf("Remediation %s started for %s", workflow.ID, resource)
+	case remediation.EventWorkflowCompleted:
+		annotation.Reason = ReasonRemediationSucceeded
+		annotation.Title = fmt.Sprintf("Remediation %s succeeded for %s", workflow.ID, resource)
+	case remediation.EventWorkflowFailed:
+		annotation.Reason = ReasonRemediationFailed
+		annotation.Title = fmt.Sprintf("Remediation %s failed for %s", workflow.ID, resource)
+		annotation.Warning = true
+	default:
+		return
+	}
+	annotation.Text = fmt.Sprintf("Issue %s, remediator %s", workflow.IssueType, workflow.Remediator)
+	if workflow.IncidentID != "" {
+		annotation.Text += ", incident " + workflow.IncidentID
+	}
+	if event.Error != "" {
+		annotation.Text += ": " + event.Error
+	}
+	p.Publish(annotation)
+}
+
+// tags returns the Grafana tags of an annotation, skipping empty ones
+func tags(kind, detail, namespace string) []string {
+	result := []string{Component, kind}
+	if !strings.HasSuffix(detail, ":") {
+		result = append(result, detail)
+	}
+	if namespace != "" {
+		result = append(result, "namespace:"+namespace)
+	}
+	return result
+}
@@ -0,0 +1,231 @@
+package annotation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/remediation"
+	"github.com/tosin2013/openshift-coordination-engine/internal/storage"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// grafanaServer records the annotations posted to it
+type grafanaServer struct {
+	mu          sync.Mutex
+	annotations []grafanaAnnotation
+	auth        []string
+}
+
+func (g *grafanaServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/api/annotations" || r.Method != http.MethodPost {
+		http.NotFound(w, r)
+		return
+	}
+	var body grafanaAnnotation
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	g.mu.Lock()
+	g.annotations = append(g.annotations, body)
+	g.auth = append(g.auth, r.Header.Get("Authorization"))
+	g.mu.Unlock()
+	_, _ = w.Write([]byte(`{"id":1,"message":"Annotation added"}`))
+}
+
+func (g *grafanaServer) posted() []grafanaAnnotation {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return append([]grafanaAnnotation(nil), g.annotations...)
+}
+
+func TestPublisher_Incidents(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	grafana := &grafanaServer{}
+	server := httptest.NewServer(grafana)
+	defer server.Close()
+	clientset := fake.NewSimpleClientset(&appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "payments", UID: "uid-api"},
+	})
+
+	publisher := NewPublisher(log,
+		NewGrafana(GrafanaOptions{URL: server.URL + "/", Token: "glsa_token", DashboardUID: "engine"}),
+		NewKubeEvents(clientset))
+	assert.Equal(t, []string{"grafana", "kubernetes"}, publisher.Sinks())
+	store := storage.NewMemoryIncidentStore()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	publisher.Start(ctx, store)
+
+	incident, err := store.Create(&models.Incident{
+		Title:             "High error rate",
+		Description:       "5xx above 5%",
+		Severity:          models.IncidentSeverityHigh,
+		Target:            "payments",
+		AffectedResources: []string{"deployment/api"},
+	})
+	require.NoError(t, err)
+	incident.Title = "High error rate on api"
+	require.NoError(t, store.Update(incident))
+	incident.Resolve()
+	require.NoError(t, store.Update(incident))
+	require.NoError(t, store.Update(incident))
+
+	require.Eventually(t, func() bool { return len(grafana.posted()) == 2 }, 5*time.Second, 10*time.Millisecond)
+	posted := grafana.posted()
+	assert.Equal(t, "engine", posted[0].DashboardUID)
+	assert.Equal(t, []string{Component, "incident", "severity:high", "namespace:payments"}, posted[0].Tags)
+	assert.Contains(t, posted[0].Text, "Incident "+incident.ID+" created: High error rate")
+	assert.Zero(t, posted[0].TimeEnd)
+	assert.Contains(t, posted[1].Text, "resolved")
+	assert.Equal(t, incident.CreatedAt.UnixMilli(), posted[1].Time)
+	assert.Equal(t, incident.ResolvedAt.UnixMilli(), posted[1].TimeEnd, "a resolved incident is a region")
+	assert.Equal(t, "Bearer glsa_token", grafana.auth[0])
+
+	require.Eventually(t, func() bool {
+		events, _ := clientset.CoreV1().Events("payments").List(ctx, metav1.ListOptions{})
+		return len(events.Items) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	events, err := clientset.CoreV1().Events("payments").List(ctx, metav1.ListOptions{})
+	require.NoError(t, err)
+	reasons := map[string]string{}
+	for _, event := range events.Items {
+		reasons[event.Reason] = event.Type
+		assert.Equal(t, "Deployment", event.InvolvedObject.Kind)
+		assert.Equal(t, "apps/v1", event.InvolvedObject.APIVersion)
+		assert.Equal(t, "uid-api", string(event.InvolvedObject.UID), "events are attached to the object for oc describe")
+		assert.Equal(t, Component, event.Source.Component)
+	}
+	assert.Equal(t, map[string]string{
+		ReasonIncidentCreated:  corev1.EventTypeWarning,
+		ReasonIncidentResolved: corev1.EventTypeNormal,
+	}, reasons)
+}
+
+func TestPublisher_WorkflowEvent(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	sink := &recordingSink{}
+	publisher := NewPublisher(log, sink)
+
+	workflow := &models.Workflow{
+		ID:           "wf-1",
+		IncidentID:   "inc-1",
+		Namespace:    "payments",
+		ResourceName: "api-0",
+		ResourceKind: "pod",
+		IssueType:    "CrashLoopBackOff",
+		Remediator:   "manual",
+	}
+	at := time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC)
+	publisher.WorkflowEvent(workflow, remediation.WorkflowEvent{Type: remediation.EventWorkflowStarted, Time: at})
+	publisher.WorkflowEvent(workflow, remediation.WorkflowEvent{Type: remediation.EventStepStarted, Time: at})
+	publisher.WorkflowEvent(workflow, remediation.WorkflowEvent{Type: remediation.EventWorkflowFailed, Time: at, Error: "pod not found"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	publisher.Start(ctx, storage.NewMemoryIncidentStore())
+	require.Eventually(t, func() bool { return len(sink.published()) == 2 }, 5*time.Second, 10*time.Millisecond)
+
+	published := sink.published()
+	assert.Equal(t, ReasonRemediationStarted, published[0].Reason)
+	assert.Equal(t, "Remediation wf-1 started for pod/api-0", published[0].Title)
+	assert.Equal(t, []ObjectRef{{Kind: "pod", Namespace: "payments", Name: "api-0"}}, published[0].Objects)
+	assert.Equal(t, []string{Component, "remediation", "remediator:manual", "namespace:payments"}, published[0].Tags)
+	assert.False(t, published[0].Warning)
+	assert.Equal(t, ReasonRemediationFailed, published[1].Reason)
+	assert.True(t, published[1].Warning)
+	assert.Equal(t, "Issue CrashLoopBackOff, remediator manual, incident inc-1: pod not found", published[1].Text)
+}
+
+func TestPublisher_DropsWhenQueueFull(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.PanicLevel)
+	sink := &recordingSink{err: errors.New("unreachable")}
+	publisher := NewPublisher(log, sink)
+
+	before := counterValue(t, "recording", ReasonIncidentCreated, OutcomeDropped)
+	for i := 0; i < DefaultQueueSize+3; i++ {
+		publisher.Publish(&Annotation{Reason: ReasonIncidentCreated})
+	}
+	assert.Equal(t, before+3, counterValue(t, "recording", ReasonIncidentCreated, OutcomeDropped))
+
+	failed := counterValue(t, "recording", ReasonIncidentCreated, OutcomeFailed)
+	publisher.publish(context.Background(), &Annotation{Reason: ReasonIncidentCreated})
+	assert.Equal(t, failed+1, counterValue(t, "recording", ReasonIncidentCreated, OutcomeFailed))
+}
+
+func TestKubeEvents_Objects(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	sink := NewKubeEvents(clientset)
+	ctx := context.Background()
+
+	// Objects that do not exist still get an event; cluster-scoped ones are recorded in default
+	require.NoError(t, sink.Publish(ctx, &Annotation{
+		Reason:  ReasonIncidentCreated,
+		Title:   "Node pressure",
+		Objects: []ObjectRef{{Kind: "node", Name: "worker-1"}, {Kind: "route", Namespace: "payments", Name: "web"}},
+	}))
+	events, err := clientset.CoreV1().Events(metav1.NamespaceDefault).List(ctx, metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, events.Items, 1, "unknown kinds are skipped")
+	assert.Equal(t, "Node", events.Items[0].InvolvedObject.Kind)
+	assert.Equal(t, "Node pressure", events.Items[0].Message)
+
+	// Without known objects the event is recorded on the namespace
+	require.NoError(t, sink.Publish(ctx, &Annotation{Reason: ReasonIncidentResolved, Title: "Resolved", Text: "after 5m", Namespace: "orders"}))
+	events, err = clientset.CoreV1().Events("orders").List(ctx, metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, events.Items, 1)
+	assert.Equal(t, "Namespace", events.Items[0].InvolvedObject.Kind)
+	assert.Equal(t, "orders", events.Items[0].InvolvedObject.Name)
+	assert.Equal(t, "Resolved: after 5m", events.Items[0].Message)
+
+	err = sink.Publish(ctx, &Annotation{Reason: ReasonIncidentCreated, Objects: []ObjectRef{{Kind: "pod", Name: "api-0"}}})
+	assert.ErrorContains(t, err, "pod/api-0 has no namespace")
+}
+
+// recordingSink records the annotations published to it
+type recordingSink struct {
+	mu          sync.Mutex
+	annotations []*Annotation
+	err         error
+}
+
+func (s *recordingSink) Name() string {
+	return "recording"
+}
+
+func (s *recordingSink) Publish(_ context.Context, annotation *Annotation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.annotations = append(s.annotations, annotation)
+	return s.err
+}
+
+func (s *recordingSink) published() []*Annotation {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*Annotation(nil), s.annotations...)
+}
+
+func counterValue(t *testing.T, sink, reason, outcome string) float64 {
+	t.Helper()
+	return testutil.ToFloat64(Published.WithLabelValues(sink, reason, outcome))
+}
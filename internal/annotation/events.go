@@ -0,0 +1,144 @@
+package annotation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// maxEventMessage bounds the message of a Kubernetes Event
+const maxEventMessage = 1024
+
+// objectKind describes a kind of object Events can be recorded on
+type objectKind struct {
+	kind       string
+	apiVersion string
+	namespaced bool
+	get        func(ctx context.Context, client kubernetes.Interface, namespace, name string) (metav1.Object, error)
+}
+
+// objectKinds are the kinds Events are recorded on, by the lower-case name used in
+// incidents' affected resources and remediation workflows
+var objectKinds = map[string]objectKind{
+	"pod": {"Pod", "v1", true, func(ctx context.Context, c kubernetes.Interface, ns, name string) (metav1.Object, error) {
+		return c.CoreV1().Pods(ns).Get(ctx, name, metav1.GetOptions{})
+	}},
+	"deployment": {"Deployment", "apps/v1", true, func(ctx context.Context, c kubernetes.Interface, ns, name string) (metav1.Object, error) {
+		return c.AppsV1().Deployments(ns).Get(ctx, name, metav1.GetOptions{})
+	}},
+	"statefulset": {"StatefulSet", "apps/v1", true, func(ctx context.Context, c kubernetes.Interface, ns, name string) (metav1.Object, error) {
+		return c.AppsV1().StatefulSets(ns).Get(ctx, name, metav1.GetOptions{})
+	}},
+	"daemonset": {"DaemonSet", "apps/v1", true, func(ctx context.Context, c kubernetes.Interface, ns, name string) (metav1.Object, error) {
+		return c.AppsV1().DaemonSets(ns).Get(ctx, name, metav1.GetOptions{})
+	}},
+	"replicaset": {"ReplicaSet", "apps/v1", true, func(ctx context.Context, c kubernetes.Interface, ns, name string) (metav1.Object, error) {
+		return c.AppsV1().ReplicaSets(ns).Get(ctx, name, metav1.GetOptions{})
+	}},
+	"job": {"Job", "batch/v1", true, func(ctx context.Context, c kubernetes.Interface, ns, name string) (metav1.Object, error) {
+		return c.BatchV1().Jobs(ns).Get(ctx, name, metav1.GetOptions{})
+	}},
+	"node": {"Node", "v1", false, func(ctx context.Context, c kubernetes.Interface, _, name string) (metav1.Object, error) {
+		return c.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+	}},
+	"namespace": {"Namespace", "v1", false, func(ctx context.Context, c kubernetes.Interface, _, name string) (metav1.Object, error) {
+		return c.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+	}},
+}
+
+// KubeEvents records annotations as Kubernetes Events on the objects they affected,
+// so they show up in `oc describe`. Annotations without objects of a known kind are
+// recorded on their namespace; those without either are skipped.
+type KubeEvents struct {
+	client kubernetes.Interface
+}
+
+// NewKubeEvents creates a Kubernetes Events sink
+func NewKubeEvents(client kubernetes.Interface) *KubeEvents {
+	return &KubeEvents{client: client}
+}
+
+// Name identifies the sink
+func (k *KubeEvents) Name() string {
+	return "kubernetes"
+}
+
+// Publish records an Event on each object of the annotation
+func (k *KubeEvents) Publish(ctx context.Context, annotation *Annotation) error {
+	var objects []ObjectRef
+	for _, object := range annotation.Objects {
+		if _, ok := objectKinds[object.Kind]; ok && object.Name != "" {
+			objects = append(objects, object)
+		}
+	}
+	if len(objects) == 0 && annotation.Namespace != "" {
+		objects = []ObjectRef{{Kind: "namespace", Name: annotation.Namespace}}
+	}
+
+	var errs []error
+	for _, object := range objects {
+		if err := k.record(ctx, object, annotation); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// record creates an Event on one object. The object's UID is looked up so that
+// `oc describe` finds the Event; an object that cannot be read still gets one.
+func (k *KubeEvents) record(ctx context.Context, object ObjectRef, annotation *Annotation) error {
+	kind := objectKinds[object.Kind]
+	ref := corev1.ObjectReference{Kind: kind.kind, APIVersion: kind.apiVersion, Name: object.Name}
+	namespace := object.Namespace
+	switch {
+	case kind.namespaced && namespace == "":
+		return fmt.Errorf("%s/%s has no namespace", object.Kind, object.Name)
+	case kind.namespaced:
+		ref.Namespace = namespace
+	case object.Kind == "namespace":
+		namespace = object.Name
+	default:
+		namespace = metav1.NamespaceDefault
+	}
+	if found, err := kind.get(ctx, k.client, ref.Namespace, object.Name); err == nil {
+		ref.UID = found.GetUID()
+		ref.ResourceVersion = found.GetResourceVersion()
+	}
+
+	eventType := corev1.EventTypeNormal
+	if annotation.Warning {
+		eventType = corev1.EventTypeWarning
+	}
+	message := annotation.Title
+	if annotation.Text != "" {
+		message += ": " + annotation.Text
+	}
+	if len(message) > maxEventMessage {
+		message = message[:maxEventMessage]
+	}
+	now := metav1.NewTime(time.Now())
+	event := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s.%x", object.Name, now.UnixNano()),
+			Namespace: namespace,
+		},
+		InvolvedObject:      ref,
+		Reason:              annotation.Reason,
+		Message:             message,
+		Type:                eventType,
+		Source:              corev1.EventSource{Component: Component},
+		ReportingController: Component,
+		FirstTimestamp:      now,
+		LastTimestamp:       now,
+		Count:               1,
+	}
+	if _, err := k.client.CoreV1().Events(namespace).Create(ctx, event, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("failed to record event on %s/%s: %w", object.Kind, object.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package annotation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// GrafanaOptions configure the Grafana sink
+type GrafanaOptions struct {
+	// URL is the root of the Grafana instance, e.g. https://grafana.example.com
+	URL string
+
+	// Token is a service account token with the annotations:write permission
+	Token string
+
+	// DashboardUID limits annotations to one dashboard; empty creates organization
+	// annotations, shown on every dashboard with an annotation query for their tags
+	DashboardUID string
+
+	// Timeout bounds a single request (DefaultTimeout if zero)
+	Timeout time.Duration
+}
+
+// Grafana posts annotations to the Grafana HTTP API
+type Grafana struct {
+	opts       GrafanaOptions
+	httpClient *http.Client
+}
+
+// NewGrafana creates a Grafana sink
+func NewGrafana(opts GrafanaOptions) *Grafana {
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	opts.URL = strings.TrimSuffix(opts.URL, "/")
+	return &Grafana{
+		opts:       opts,
+		httpClient: &http.Client{Timeout: opts.Timeout},
+	}
+}
+
+// Name identifies the sink
+func (g *Grafana) Name() string {
+	return "grafana"
+}
+
+// HTTPClient returns the client used to reach Grafana, e.g. to audit its TLS configuration
+func (g *Grafana) HTTPClient() *http.Client {
+	return g.httpClient
+}
+
+// grafanaAnnotation is the body of POST /api/annotations
+type grafanaAnnotation struct {
+	DashboardUID string   `json:"dashboardUID,omitempty"`
+	Time         int64    `json:"time"`
+	TimeEnd      int64    `json:"timeEnd,omitempty"`
+	Tags         []string `json:"tags"`
+	Text         string   `json:"text"`
+}
+
+// Publish creates a Grafana annotation
+func (g *Grafana) Publish(ctx context.Context, annotation *Annotation) error {
+	body := grafanaAnnotation{
+		DashboardUID: g.opts.DashboardUID,
+		Time:         annotation.Time.UnixMilli(),
+		Tags:         annotation.Tags,
+		Text:         annotation.Title,
+	}
+	if annotation.Text != "" {
+		body.Text += "\n" + annotation.Text
+	}
+	if !annotation.End.IsZero() {
+		body.TimeEnd = annotation.End.UnixMilli()
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to encode Grafana annotation: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.opts.URL+"/api/annotations", bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create Grafana request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if g.opts.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+g.opts.Token)
+	}
+
+	resp, err := g.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("grafana request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("grafana returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package annotation
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Publish outcomes
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailed  = "failed"
+	OutcomeDropped = "dropped"
+)
+
+var (
+	// Published counts annotations by sink, reason and outcome
+	Published = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "coordination_engine_annotations_published_total",
+			Help: "Total number of annotations published, by sink, reason and outcome (success, failed, dropped)",
+		},
+		[]string{"sink", "reason", "outcome"},
+	)
+)
+
+func recordPublished(sink, reason, outcome string) {
+	Published.WithLabelValues(sink, reason, outcome).Inc()
+}
@@ -0,0 +1,297 @@
+package anomaly
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+// BusinessWindow is a recurring period of expected load, e.g. trading hours, a nightly
+// batch run or month-end close. A window is active when the current day matches its
+// days (any day if none are set) and the time of day lies between Start and End.
+type BusinessWindow struct {
+	// Name identifies the window in responses and logs
+	Name string `json:"name"`
+
+	// Days are weekdays the window recurs on: mon, tue, wed, thu, fri, sat, sun
+	Days []string `json:"days,omitempty"`
+
+	// DaysOfMonth are days of the month the window recurs on; negative values count
+	// from the end of the month, so -1 is the last day
+	DaysOfMonth []int `json:"days_of_month,omitempty"`
+
+	// Start and End are times of day as "HH:MM"; both empty covers the whole day and
+	// an End before Start spans midnight
+	Start string `json:"start,omitempty"`
+	End   string `json:"end,omitempty"`
+
+	// ThresholdIncrease raises the anomaly threshold while the window is active
+	ThresholdIncrease float64 `json:"threshold_increase,omitempty"`
+
+	// LowerSeverity maps anomalies found during the window one severity lower
+	LowerSeverity bool `json:"lower_severity,omitempty"`
+
+	start, end time.Duration
+	weekdays   map[time.Weekday]bool
+}
+
+// BusinessCalendar holds the business windows of the namespaces it matches
+type BusinessCalendar struct {
+	// Name identifies the calendar in responses and logs
+	Name string `json:"name"`
+
+	// Namespaces are namespace name patterns, e.g. "trading-*" (see path.Match); a
+	// calendar without patterns applies to every request, including cluster-wide ones
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// Timezone is the IANA zone the windows are written in (default UTC)
+	Timezone string `json:"timezone,omitempty"`
+
+	Windows []BusinessWindow `json:"windows"`
+
+	location *time.Location
+}
+
+// ActiveWindow is a business window active at the time of an analysis
+type ActiveWindow struct {
+	Calendar string `json:"calendar"`
+	Window   string `json:"window"`
+
+	ThresholdIncrease float64 `json:"threshold_increase,omitempty"`
+	LowerSeverity     bool    `json:"lower_severity,omitempty"`
+}
+
+// String returns "calendar/window"
+func (w ActiveWindow) String() string {
+	return w.Calendar + "/" + w.Window
+}
+
+// businessCalendarsFile is the on-disk format of the business calendars
+type businessCalendarsFile struct {
+	Calendars []BusinessCalendar `json:"calendars"`
+}
+
+// BusinessCalendars maps namespaces to the periods their workloads are expected to be
+// busy, so analysis can tell expected load from anomalies
+type BusinessCalendars struct {
+	calendars []BusinessCalendar
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// LoadBusinessCalendars reads business calendars from a YAML or JSON file
+func LoadBusinessCalendars(path string) (*BusinessCalendars, error) {
+	data, err := os.ReadFile(path) //#nosec G304 -- path comes from operator configuration
+	if err != nil {
+		return nil, fmt.Errorf("failed to read business calendars file: %w", err)
+	}
+
+	var file businessCalendarsFile
+	if err := yaml.UnmarshalStrict(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse business calendars file %s: %w", path, err)
+	}
+	return NewBusinessCalendars(file.Calendars)
+}
+
+// NewBusinessCalendars validates the calendars
+func NewBusinessCalendars(calendars []BusinessCalendar) (*BusinessCalendars, error) {
+	names := make(map[string]bool, len(calendars))
+	for i := range calendars {
+		calendar := &calendars[i]
+		if calendar.Name == "" {
+			return nil, fmt.Errorf("business calendar %d: name is required", i)
+		}
+		if names[calendar.Name] {
+			return nil, fmt.Errorf("business calendar %s: duplicate name", calendar.Name)
+		}
+		names[calendar.Name] = true
+
+		for _, pattern := range calendar.Namespaces {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("business calendar %s: invalid namespace pattern %q", calendar.Name, pattern)
+			}
+		}
+
+		calendar.location = time.UTC
+		if calendar.Timezone != "" {
+			location, err := time.LoadLocation(calendar.Timezone)
+			if err != nil {
+				return nil, fmt.Errorf("business calendar %s: invalid timezone %q", calendar.Name, calendar.Timezone)
+			}
+			calendar.location = location
+		}
+
+		if len(calendar.Windows) == 0 {
+			return nil, fmt.Errorf("business calendar %s: at least one window is required", calendar.Name)
+		}
+		windowNames := make(map[string]bool, len(calendar.Windows))
+		for j := range calendar.Windows {
+			window := &calendar.Windows[j]
+			if window.Name == "" {
+				return nil, fmt.Errorf("business calendar %s: window %d: name is required", calendar.Name, j)
+			}
+			if windowNames[window.Name] {
+				return nil, fmt.Errorf("business calendar %s: window %s: duplicate name", calendar.Name, window.Name)
+			}
+			windowNames[window.Name] = true
+			if err := window.compile(); err != nil {
+				return nil, fmt.Errorf("business calendar %s: window %s: %w", calendar.Name, window.Name, err)
+			}
+		}
+	}
+
+	return &BusinessCalendars{calendars: calendars}, nil
+}
+
+// compile validates a window and parses its days and times
+func (w *BusinessWindow) compile() error {
+	if w.ThresholdIncrease < 0 || w.ThresholdIncrease > 1 {
+		return fmt.Errorf("threshold_increase must be between 0.0 and 1.0")
+	}
+
+	w.weekdays = make(map[time.Weekday]bool, len(w.Days))
+	for _, day := range w.Days {
+		weekday, ok := weekdays[strings.ToLower(day)]
+		if !ok {
+			return fmt.Errorf("invalid day %q (use mon, tue, wed, thu, fri, sat or sun)", day)
+		}
+		w.weekdays[weekday] = true
+	}
+	for _, day := range w.DaysOfMonth {
+		if day == 0 || day < -31 || day > 31 {
+			return fmt.Errorf("invalid day of month %d (use 1 to 31, or -1 to -31 from the end of the month)", day)
+		}
+	}
+
+	if (w.Start == "") != (w.End == "") {
+		return fmt.Errorf("start and end must be set together")
+	}
+	if w.Start == "" {
+		return nil
+	}
+	var err error
+	if w.start, err = parseTimeOfDay(w.Start); err != nil {
+		return fmt.Errorf("invalid start: %w", err)
+	}
+	if w.end, err = parseTimeOfDay(w.End); err != nil {
+		return fmt.Errorf("invalid end: %w", err)
+	}
+	if w.start == w.end {
+		return fmt.Errorf("start and end must differ")
+	}
+	return nil
+}
+
+// parseTimeOfDay parses "HH:MM" into the offset from midnight
+func parseTimeOfDay(value string) (time.Duration, error) {
+	parsed, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a time of day as HH:MM", value)
+	}
+	return time.Duration(parsed.Hour())*time.Hour + time.Duration(parsed.Minute())*time.Minute, nil
+}
+
+// Active returns the windows active at now for the namespace ("" for cluster-wide
+// requests), across all calendars matching it
+func (c *BusinessCalendars) Active(namespace string, now time.Time) []ActiveWindow {
+	var active []ActiveWindow
+	for i := range c.calendars {
+		calendar := &c.calendars[i]
+		if !calendar.matches(namespace) {
+			continue
+		}
+		local := now.In(calendar.location)
+		for j := range calendar.Windows {
+			window := &calendar.Windows[j]
+			if window.activeAt(local) {
+				active = append(active, ActiveWindow{
+					Calendar:          calendar.Name,
+					Window:            window.Name,
+					ThresholdIncrease: window.ThresholdIncrease,
+					LowerSeverity:     window.LowerSeverity,
+				})
+			}
+		}
+	}
+	return active
+}
+
+// matches reports whether the calendar applies to the namespace
+func (c *BusinessCalendar) matches(namespace string) bool {
+	if len(c.Namespaces) == 0 {
+		return true
+	}
+	for _, pattern := range c.Namespaces {
+		if matched, _ := path.Match(pattern, namespace); matched && namespace != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// activeAt reports whether the window is active at a time in the calendar's zone. A
+// window spanning midnight belongs to the day it starts on.
+func (w *BusinessWindow) activeAt(local time.Time) bool {
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, local.Location())
+	offset := local.Sub(midnight)
+
+	switch {
+	case w.Start == "":
+		return w.onDay(local)
+	case w.start < w.end:
+		return offset >= w.start && offset < w.end && w.onDay(local)
+	case offset >= w.start:
+		return w.onDay(local)
+	case offset < w.end:
+		return w.onDay(midnight.AddDate(0, 0, -1))
+	}
+	return false
+}
+
+// onDay reports whether the window recurs on the day of t
+func (w *BusinessWindow) onDay(t time.Time) bool {
+	if len(w.weekdays) > 0 && !w.weekdays[t.Weekday()] {
+		return false
+	}
+	if len(w.DaysOfMonth) == 0 {
+		return true
+	}
+	daysInMonth := time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, t.Location()).Day()
+	for _, day := range w.DaysOfMonth {
+		if day < 0 {
+			day = daysInMonth + day + 1
+		}
+		if day == t.Day() {
+			return true
+		}
+	}
+	return false
+}
+
+// AdjustThreshold raises an anomaly threshold by the largest increase of the active
+// windows, capped at 1.0
+func AdjustThreshold(threshold float64, active []ActiveWindow) float64 {
+	var increase float64
+	for _, window := range active {
+		if window.ThresholdIncrease > increase {
+			increase = window.ThresholdIncrease
+		}
+	}
+	threshold += increase
+	if threshold > 1 {
+		threshold = 1
+	}
+	return threshold
+}
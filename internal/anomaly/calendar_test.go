@@ -0,0 +1,109 @@
+package anomaly
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testCalendars = `
+calendars:
+  - name: trading
+    namespaces: ["trading-*"]
+    timezone: America/New_York
+    windows:
+      - name: market-hours
+        days: [mon, tue, wed, thu, fri]
+        start: "09:30"
+        end: "16:00"
+        threshold_increase: 0.1
+      - name: month-end
+        days_of_month: [-2, -1]
+        lower_severity: true
+  - name: batch
+    windows:
+      - name: nightly
+        start: "23:00"
+        end: "02:00"
+        threshold_increase: 0.2
+`
+
+func TestLoadBusinessCalendars(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "calendars.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(testCalendars), 0o600))
+
+	calendars, err := LoadBusinessCalendars(path)
+	require.NoError(t, err)
+
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		namespace string
+		now       time.Time
+		want      []string
+	}{
+		{"market hours", "trading-eu", time.Date(2026, 3, 10, 10, 0, 0, 0, newYork), []string{"trading/market-hours"}},
+		{"market closed", "trading-eu", time.Date(2026, 3, 10, 17, 0, 0, 0, newYork), nil},
+		{"weekend", "trading-eu", time.Date(2026, 3, 14, 10, 0, 0, 0, newYork), nil},
+		{"window end is exclusive", "trading-eu", time.Date(2026, 3, 10, 16, 0, 0, 0, newYork), nil},
+		{"month end", "trading-eu", time.Date(2026, 2, 28, 12, 0, 0, 0, newYork), []string{"trading/month-end"}},
+		{"not month end", "trading-eu", time.Date(2026, 3, 29, 18, 0, 0, 0, newYork), nil},
+		{"other namespace", "payments", time.Date(2026, 3, 10, 10, 0, 0, 0, newYork), nil},
+		{"before midnight", "payments", time.Date(2026, 3, 10, 23, 30, 0, 0, time.UTC), []string{"batch/nightly"}},
+		{"after midnight", "", time.Date(2026, 3, 11, 1, 30, 0, 0, time.UTC), []string{"batch/nightly"}},
+		{"both calendars", "trading-eu", time.Date(2026, 3, 31, 1, 0, 0, 0, time.UTC), []string{"trading/month-end", "batch/nightly"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, window := range calendars.Active(tt.namespace, tt.now) {
+				got = append(got, window.String())
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNewBusinessCalendars_Invalid(t *testing.T) {
+	window := BusinessWindow{Name: "w", Start: "09:00", End: "17:00"}
+	tests := map[string]BusinessCalendar{
+		"name is required":           {Windows: []BusinessWindow{window}},
+		"at least one window":        {Name: "c"},
+		"invalid timezone":           {Name: "c", Timezone: "Mars/Olympus", Windows: []BusinessWindow{window}},
+		"invalid namespace pattern":  {Name: "c", Namespaces: []string{"["}, Windows: []BusinessWindow{window}},
+		"invalid day \"funday\"":     {Name: "c", Windows: []BusinessWindow{{Name: "w", Days: []string{"funday"}}}},
+		"invalid day of month 0":     {Name: "c", Windows: []BusinessWindow{{Name: "w", DaysOfMonth: []int{0}}}},
+		"start and end must be set":  {Name: "c", Windows: []BusinessWindow{{Name: "w", Start: "09:00"}}},
+		"invalid end":                {Name: "c", Windows: []BusinessWindow{{Name: "w", Start: "09:00", End: "25:00"}}},
+		"threshold_increase must be": {Name: "c", Windows: []BusinessWindow{{Name: "w", ThresholdIncrease: 1.5}}},
+		"window w: duplicate name":   {Name: "c", Windows: []BusinessWindow{window, window}},
+		"window 0: name is required": {Name: "c", Windows: []BusinessWindow{{Start: "09:00", End: "17:00"}}},
+		"start and end must differ":  {Name: "c", Windows: []BusinessWindow{{Name: "w", Start: "09:00", End: "09:00"}}},
+	}
+	for want, calendar := range tests {
+		t.Run(want, func(t *testing.T) {
+			_, err := NewBusinessCalendars([]BusinessCalendar{calendar})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), want)
+		})
+	}
+
+	_, err := NewBusinessCalendars([]BusinessCalendar{
+		{Name: "c", Windows: []BusinessWindow{window}},
+		{Name: "c", Windows: []BusinessWindow{window}},
+	})
+	assert.ErrorContains(t, err, "duplicate name")
+}
+
+func TestAdjustThreshold(t *testing.T) {
+	assert.InDelta(t, 0.7, AdjustThreshold(0.7, nil), 1e-9)
+	active := []ActiveWindow{{ThresholdIncrease: 0.1}, {ThresholdIncrease: 0.2}, {LowerSeverity: true}}
+	assert.InDelta(t, 0.9, AdjustThreshold(0.7, active), 1e-9)
+	assert.InDelta(t, 1.0, AdjustThreshold(0.95, active), 1e-9, "threshold is capped at 1.0")
+}
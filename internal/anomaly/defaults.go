@@ -0,0 +1,220 @@
+package anomaly
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+)
+
+// DefaultTierLabel is the namespace label holding a namespace's criticality tier
+const DefaultTierLabel = "criticality"
+
+// tierCacheTTL bounds how long a namespace's tier is cached
+const tierCacheTTL = time.Minute
+
+// ScopeDefaultRule selects the model and threshold for analysis requests that omit
+// them. It matches a namespace by name pattern or by criticality tier; a rule with
+// neither matches every request, including cluster-wide ones.
+type ScopeDefaultRule struct {
+	// Name identifies the rule in responses and logs
+	Name string `json:"name"`
+
+	// Namespaces are namespace name patterns, e.g. "prod-*" (see path.Match)
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// Tiers match namespaces whose tier label has one of these values, e.g. "critical"
+	Tiers []string `json:"tiers,omitempty"`
+
+	// Model is the KServe model used when the request has no model_name
+	Model string `json:"model,omitempty"`
+
+	// Threshold is the anomaly score threshold used when the request has none
+	Threshold float64 `json:"threshold,omitempty"`
+}
+
+// scopeDefaultsFile is the on-disk format of the scope defaults
+type scopeDefaultsFile struct {
+	TierLabel string             `json:"tier_label,omitempty"`
+	Rules     []ScopeDefaultRule `json:"rules"`
+}
+
+// TierLookup returns the criticality tier of a namespace, or "" if it has none
+type TierLookup func(ctx context.Context, namespace string) string
+
+// ScopeDefaults maps scopes to default models and thresholds. Rules are evaluated in
+// order and the first match wins.
+type ScopeDefaults struct {
+	rules     []ScopeDefaultRule
+	tierLabel string
+	tiers     TierLookup
+}
+
+// LoadScopeDefaults reads scope defaults from a YAML or JSON file
+func LoadScopeDefaults(path string) (*ScopeDefaults, error) {
+	data, err := os.ReadFile(path) //#nosec G304 -- path comes from operator configuration
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scope defaults file: %w", err)
+	}
+
+	var file scopeDefaultsFile
+	if err := yaml.UnmarshalStrict(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse scope defaults file %s: %w", path, err)
+	}
+	return NewScopeDefaults(file.TierLabel, file.Rules)
+}
+
+// NewScopeDefaults validates the rules. tierLabel defaults to DefaultTierLabel.
+func NewScopeDefaults(tierLabel string, rules []ScopeDefaultRule) (*ScopeDefaults, error) {
+	if tierLabel == "" {
+		tierLabel = DefaultTierLabel
+	}
+
+	names := make(map[string]bool, len(rules))
+	for i := range rules {
+		rule := &rules[i]
+		if rule.Name == "" {
+			return nil, fmt.Errorf("scope defaults rule %d: name is required", i)
+		}
+		if names[rule.Name] {
+			return nil, fmt.Errorf("scope defaults rule %s: duplicate name", rule.Name)
+		}
+		names[rule.Name] = true
+
+		if rule.Model == "" && rule.Threshold == 0 {
+			return nil, fmt.Errorf("scope defaults rule %s: model or threshold is required", rule.Name)
+		}
+		if rule.Threshold < 0 || rule.Threshold > 1 {
+			return nil, fmt.Errorf("scope defaults rule %s: threshold must be between 0.0 and 1.0", rule.Name)
+		}
+		for _, pattern := range rule.Namespaces {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("scope defaults rule %s: invalid namespace pattern %q", rule.Name, pattern)
+			}
+		}
+	}
+
+	return &ScopeDefaults{rules: rules, tierLabel: tierLabel}, nil
+}
+
+// TierLabel returns the namespace label holding the criticality tier
+func (d *ScopeDefaults) TierLabel() string {
+	return d.tierLabel
+}
+
+// SetTierLookup sets how namespace tiers are resolved; without it tier rules never match
+func (d *ScopeDefaults) SetTierLookup(lookup TierLookup) {
+	d.tiers = lookup
+}
+
+// UsesTiers returns true if any rule matches by tier
+func (d *ScopeDefaults) UsesTiers() bool {
+	for i := range d.rules {
+		if len(d.rules[i].Tiers) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Models returns the models named by the rules
+func (d *ScopeDefaults) Models() []string {
+	var models []string
+	for i := range d.rules {
+		if d.rules[i].Model != "" {
+			models = append(models, d.rules[i].Model)
+		}
+	}
+	return models
+}
+
+// Match returns the first rule matching the namespace ("" for cluster-wide requests),
+// or nil. The namespace's tier is only looked up when a tier rule is reached.
+func (d *ScopeDefaults) Match(ctx context.Context, namespace string) *ScopeDefaultRule {
+	var (
+		tier       string
+		tierLoaded bool
+	)
+	for i := range d.rules {
+		rule := &d.rules[i]
+		if len(rule.Namespaces) == 0 && len(rule.Tiers) == 0 {
+			return rule
+		}
+		if namespace == "" {
+			continue
+		}
+		for _, pattern := range rule.Namespaces {
+			if matched, _ := path.Match(pattern, namespace); matched {
+				return rule
+			}
+		}
+		if len(rule.Tiers) == 0 || d.tiers == nil {
+			continue
+		}
+		if !tierLoaded {
+			tier, tierLoaded = d.tiers(ctx, namespace), true
+		}
+		for _, t := range rule.Tiers {
+			if t == tier {
+				return rule
+			}
+		}
+	}
+	return nil
+}
+
+// namespaceTiers reads namespace tiers from a namespace label and caches them
+type namespaceTiers struct {
+	client kubernetes.Interface
+	label  string
+	log    *logrus.Logger
+
+	mu    sync.Mutex
+	cache map[string]cachedTier
+}
+
+type cachedTier struct {
+	tier    string
+	expires time.Time
+}
+
+// NamespaceTierLookup returns a TierLookup reading the label of namespaces through
+// the Kubernetes API. Results, including namespaces that cannot be read, are cached
+// for a minute.
+func NamespaceTierLookup(client kubernetes.Interface, label string, log *logrus.Logger) TierLookup {
+	tiers := &namespaceTiers{client: client, label: label, log: log, cache: make(map[string]cachedTier)}
+	return tiers.lookup
+}
+
+func (n *namespaceTiers) lookup(ctx context.Context, namespace string) string {
+	n.mu.Lock()
+	cached, ok := n.cache[namespace]
+	n.mu.Unlock()
+	if ok && time.Now().Before(cached.expires) {
+		return cached.tier
+	}
+
+	var tier string
+	ns, err := n.client.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+	switch {
+	case err == nil:
+		tier = ns.Labels[n.label]
+	case errors.Is(err, context.Canceled):
+		return ""
+	default:
+		n.log.WithError(err).WithField("namespace", namespace).Warn("Failed to read namespace tier, tier rules will not match")
+	}
+
+	n.mu.Lock()
+	n.cache[namespace] = cachedTier{tier: tier, expires: time.Now().Add(tierCacheTTL)}
+	n.mu.Unlock()
+	return tier
+}
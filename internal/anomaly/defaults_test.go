@@ -0,0 +1,94 @@
+package anomaly
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const testScopeDefaults = `
+tier_label: example.com/criticality
+rules:
+  - name: production
+    namespaces: ["prod-*", "payments"]
+    tiers: ["critical"]
+    model: anomaly-detector-v2
+    threshold: 0.8
+  - name: sandboxes
+    namespaces: ["sandbox-*"]
+    model: anomaly-detector-lite
+    threshold: 0.6
+  - name: everything-else
+    threshold: 0.75
+`
+
+func TestLoadScopeDefaults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scope-defaults.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(testScopeDefaults), 0o600))
+
+	defaults, err := LoadScopeDefaults(path)
+	require.NoError(t, err)
+	assert.Equal(t, "example.com/criticality", defaults.TierLabel())
+	assert.True(t, defaults.UsesTiers())
+	assert.Equal(t, []string{"anomaly-detector-v2", "anomaly-detector-lite"}, defaults.Models())
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	client := fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "checkout", Labels: map[string]string{"example.com/criticality": "critical"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "tools"}},
+	)
+	defaults.SetTierLookup(NamespaceTierLookup(client, defaults.TierLabel(), log))
+
+	ctx := context.Background()
+	tests := map[string]string{
+		"prod-eu":     "production",
+		"payments":    "production",
+		"checkout":    "production",
+		"sandbox-ann": "sandboxes",
+		"tools":       "everything-else",
+		"missing":     "everything-else",
+		"":            "everything-else",
+	}
+	for namespace, want := range tests {
+		rule := defaults.Match(ctx, namespace)
+		require.NotNil(t, rule, namespace)
+		assert.Equal(t, want, rule.Name, namespace)
+	}
+}
+
+func TestScopeDefaults_NoMatch(t *testing.T) {
+	defaults, err := NewScopeDefaults("", []ScopeDefaultRule{
+		{Name: "production", Namespaces: []string{"prod-*"}, Model: "anomaly-detector-v2"},
+		{Name: "critical", Tiers: []string{"critical"}, Threshold: 0.9},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, DefaultTierLabel, defaults.TierLabel())
+
+	assert.Nil(t, defaults.Match(context.Background(), "dev"))
+	assert.Nil(t, defaults.Match(context.Background(), ""), "cluster-wide requests only match catch-all rules")
+}
+
+func TestNewScopeDefaults_Errors(t *testing.T) {
+	tests := map[string][]ScopeDefaultRule{
+		"name is required":                 {{Model: "m"}},
+		"duplicate name":                   {{Name: "a", Model: "m"}, {Name: "a", Model: "m"}},
+		"model or threshold is required":   {{Name: "a"}},
+		"threshold must be between":        {{Name: "a", Threshold: 1.5}},
+		"invalid namespace pattern \"[a\"": {{Name: "a", Model: "m", Namespaces: []string{"[a"}}},
+	}
+	for want, rules := range tests {
+		_, err := NewScopeDefaults("", rules)
+		require.Error(t, err, want)
+		assert.Contains(t, err.Error(), want)
+	}
+}
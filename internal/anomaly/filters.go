@@ -0,0 +1,507 @@
+package anomaly
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+)
+
+// Post-filter actions
+const (
+	// FilterActionDrop discards matching anomalies
+	FilterActionDrop = "drop"
+
+	// FilterActionLowerSeverity maps matching anomalies one severity lower and passes
+	// them on to the next filter
+	FilterActionLowerSeverity = "lower_severity"
+
+	// FilterActionConfirm holds matching anomalies back until the scope was anomalous
+	// in Confirmations consecutive analyses
+	FilterActionConfirm = "confirm"
+)
+
+// Post-filter defaults
+const (
+	// DefaultFilterConfirmations is how many consecutive analyses a confirm filter
+	// requires
+	DefaultFilterConfirmations = 2
+
+	// DefaultFilterConfirmWithin is how far apart consecutive analyses of a confirm
+	// filter may be before the count starts over
+	DefaultFilterConfirmWithin = 30 * time.Minute
+)
+
+// anomalySeverities are the severities of detected anomalies, from lowest to highest
+var anomalySeverities = map[string]int{"info": 0, "warning": 1, "critical": 2}
+
+// FilterHours is a recurring period of the week a post-filter applies in, or outside
+// of with Outside, e.g. business hours
+type FilterHours struct {
+	// Days are weekdays: mon, tue, wed, thu, fri, sat, sun (default every day)
+	Days []string `json:"days,omitempty"`
+
+	// Start and End are times of day as "HH:MM"; an End before Start spans midnight
+	Start string `json:"start"`
+	End   string `json:"end"`
+
+	// Timezone is the IANA zone the hours are written in (default UTC)
+	Timezone string `json:"timezone,omitempty"`
+
+	// Outside matches analyses outside the hours instead of during them
+	Outside bool `json:"outside,omitempty"`
+
+	window   BusinessWindow
+	location *time.Location
+}
+
+// PostFilter is one step of the post-filter chain. It matches anomalies by every
+// condition it sets; a condition listing several values matches any of them. A filter
+// without conditions matches every anomaly.
+type PostFilter struct {
+	// Name identifies the filter in responses and logs
+	Name string `json:"name"`
+
+	// Namespaces are namespace name patterns, e.g. "dev-*" (see path.Match)
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// NamespaceLabels match namespaces carrying all of these labels, e.g.
+	// {"environment": "dev"}
+	NamespaceLabels map[string]string `json:"namespace_labels,omitempty"`
+
+	// Owners match scopes owned by one of these teams (see internal/ownership)
+	Owners []string `json:"owners,omitempty"`
+
+	// Severities match anomalies of these severities: info, warning, critical
+	Severities []string `json:"severities,omitempty"`
+
+	// Hours match analyses during, or outside of, a period of the week
+	Hours *FilterHours `json:"hours,omitempty"`
+
+	// Action is drop, lower_severity or confirm
+	Action string `json:"action"`
+
+	// Confirmations is how many consecutive analyses of the scope a confirm filter
+	// requires (default 2), and ConfirmWithin how far apart they may be, e.g. "15m"
+	// (default 30m)
+	Confirmations int    `json:"confirmations,omitempty"`
+	ConfirmWithin string `json:"confirm_within,omitempty"`
+
+	confirmWithin time.Duration
+}
+
+// postFiltersFile is the on-disk format of the post-filter chain
+type postFiltersFile struct {
+	Filters []PostFilter `json:"filters"`
+}
+
+// LabelLookup returns the labels of a namespace, or nil if it has none
+type LabelLookup func(ctx context.Context, namespace string) map[string]string
+
+// OwnerLookup returns the team owning a resource ("kind/name") in a namespace, or of
+// the namespace when resource is empty; "" if none is known
+type OwnerLookup func(ctx context.Context, namespace, resource string) string
+
+// FilterSubject is the analysis whose anomalies are filtered
+type FilterSubject struct {
+	Namespace  string
+	Deployment string
+	Pod        string
+	Model      string
+
+	// At is the evaluation time of the analysis
+	At time.Time
+
+	// Retroactive analyses neither count towards confirmations nor wait for them
+	Retroactive bool
+}
+
+// FilterVerdict is what the post-filter chain did with one anomaly
+type FilterVerdict struct {
+	// Keep is false when a filter dropped the anomaly or holds it for confirmation
+	Keep bool
+
+	// Severity is the severity of the anomaly after the chain
+	Severity string
+
+	// LoweredBy names the filters that lowered the severity, in order
+	LoweredBy []string
+
+	// Filter and Action name the filter that dropped or held the anomaly, and Reason
+	// says why
+	Filter string
+	Action string
+	Reason string
+}
+
+// PostFilters is an ordered chain of filters applied to detected anomalies before
+// incidents are opened from them. Each anomaly runs through the filters in order; a
+// drop stops the chain, a lowered severity is seen by the filters after it, and an
+// unconfirmed anomaly stops the chain until its scope was anomalous often enough.
+type PostFilters struct {
+	filters []PostFilter
+	labels  LabelLookup
+	owners  OwnerLookup
+
+	// mu guards the consecutive anomalous analyses of each confirm filter and scope
+	mu       sync.Mutex
+	confirms map[string]confirmation
+}
+
+// confirmation counts the consecutive anomalous analyses of a scope for a confirm filter
+type confirmation struct {
+	seen    int
+	expires time.Time
+}
+
+// LoadPostFilters reads the post-filter chain from a YAML or JSON file
+func LoadPostFilters(path string) (*PostFilters, error) {
+	data, err := os.ReadFile(path) //#nosec G304 -- path comes from operator configuration
+	if err != nil {
+		return nil, fmt.Errorf("failed to read post-filters file: %w", err)
+	}
+
+	var file postFiltersFile
+	if err := yaml.UnmarshalStrict(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse post-filters file %s: %w", path, err)
+	}
+	return NewPostFilters(file.Filters)
+}
+
+// NewPostFilters validates the filters
+func NewPostFilters(filters []PostFilter) (*PostFilters, error) {
+	names := make(map[string]bool, len(filters))
+	for i := range filters {
+		filter := &filters[i]
+		if filter.Name == "" {
+			return nil, fmt.Errorf("post-filter %d: name is required", i)
+		}
+		if names[filter.Name] {
+			return nil, fmt.Errorf("post-filter %s: duplicate name", filter.Name)
+		}
+		names[filter.Name] = true
+		if err := filter.compile(); err != nil {
+			return nil, fmt.Errorf("post-filter %s: %w", filter.Name, err)
+		}
+	}
+
+	return &PostFilters{
+		filters:  filters,
+		confirms: make(map[string]confirmation),
+	}, nil
+}
+
+// compile validates a filter and parses its hours and confirmation window
+func (f *PostFilter) compile() error {
+	switch f.Action {
+	case FilterActionDrop, FilterActionLowerSeverity:
+		if f.Confirmations != 0 || f.ConfirmWithin != "" {
+			return fmt.Errorf("confirmations and confirm_within only apply to the confirm action")
+		}
+	case FilterActionConfirm:
+		if f.Confirmations == 0 {
+			f.Confirmations = DefaultFilterConfirmations
+		}
+		if f.Confirmations < 2 {
+			return fmt.Errorf("confirmations must be at least 2")
+		}
+		f.confirmWithin = DefaultFilterConfirmWithin
+		if f.ConfirmWithin != "" {
+			within, err := time.ParseDuration(f.ConfirmWithin)
+			if err != nil || within <= 0 {
+				return fmt.Errorf("invalid confirm_within %q", f.ConfirmWithin)
+			}
+			f.confirmWithin = within
+		}
+	case "":
+		return fmt.Errorf("action is required")
+	default:
+		return fmt.Errorf("invalid action %q (use drop, lower_severity or confirm)", f.Action)
+	}
+
+	for _, pattern := range f.Namespaces {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid namespace pattern %q", pattern)
+		}
+	}
+	for _, severity := range f.Severities {
+		if _, ok := anomalySeverities[severity]; !ok {
+			return fmt.Errorf("invalid severity %q (use info, warning or critical)", severity)
+		}
+	}
+
+	if f.Hours == nil {
+		return nil
+	}
+	if f.Hours.Start == "" {
+		return fmt.Errorf("hours: start and end are required")
+	}
+	f.Hours.location = time.UTC
+	if f.Hours.Timezone != "" {
+		location, err := time.LoadLocation(f.Hours.Timezone)
+		if err != nil {
+			return fmt.Errorf("hours: invalid timezone %q", f.Hours.Timezone)
+		}
+		f.Hours.location = location
+	}
+	f.Hours.window = BusinessWindow{Days: f.Hours.Days, Start: f.Hours.Start, End: f.Hours.End}
+	if err := f.Hours.window.compile(); err != nil {
+		return fmt.Errorf("hours: %w", err)
+	}
+	return nil
+}
+
+// SetNamespaceLabels sets how namespace labels are read; without it namespace_labels
+// filters never match
+func (c *PostFilters) SetNamespaceLabels(lookup LabelLookup) {
+	c.labels = lookup
+}
+
+// SetOwners sets how the owning team of a scope is resolved; without it owners filters
+// never match
+func (c *PostFilters) SetOwners(lookup OwnerLookup) {
+	c.owners = lookup
+}
+
+// Names returns the names of the filters, in order
+func (c *PostFilters) Names() []string {
+	names := make([]string, len(c.filters))
+	for i := range c.filters {
+		names[i] = c.filters[i].Name
+	}
+	return names
+}
+
+// UsesNamespaceLabels returns true if any filter matches by namespace label
+func (c *PostFilters) UsesNamespaceLabels() bool {
+	for i := range c.filters {
+		if len(c.filters[i].NamespaceLabels) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// UsesOwners returns true if any filter matches by owner
+func (c *PostFilters) UsesOwners() bool {
+	for i := range c.filters {
+		if len(c.filters[i].Owners) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// scopeFacts are the namespace labels and owner of the analyzed scope, looked up once
+// per analysis and only when a filter needs them
+type scopeFacts struct {
+	labels       map[string]string
+	labelsLoaded bool
+	owner        string
+	ownerLoaded  bool
+}
+
+// Apply runs the chain on the severities of the anomalies of one analysis and returns
+// a verdict for each. A confirm filter counts the analysis once however many of its
+// anomalies it matches; an analysis it matches none of starts its count over.
+func (c *PostFilters) Apply(ctx context.Context, subject FilterSubject, severities []string) []FilterVerdict {
+	scope := ScopeKey(subject.Namespace, subject.Deployment, subject.Pod) + "@" + subject.Model
+	facts := &scopeFacts{}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Each confirm filter's count including this analysis, should it match
+	counts := make(map[string]int)
+	for i := range c.filters {
+		filter := &c.filters[i]
+		if filter.Action != FilterActionConfirm {
+			continue
+		}
+		counts[filter.Name] = 1
+		if previous, ok := c.confirms[filter.Name+"|"+scope]; ok && !subject.At.After(previous.expires) {
+			counts[filter.Name] = previous.seen + 1
+		}
+	}
+
+	matched := make(map[string]bool)
+	verdicts := make([]FilterVerdict, len(severities))
+	for i, severity := range severities {
+		verdict := FilterVerdict{Keep: true, Severity: severity}
+		for j := range c.filters {
+			filter := &c.filters[j]
+			if !c.matches(ctx, filter, subject, verdict.Severity, facts) {
+				continue
+			}
+			if filter.Action == FilterActionLowerSeverity {
+				verdict.Severity = lowerAnomalySeverity(verdict.Severity)
+				verdict.LoweredBy = append(verdict.LoweredBy, filter.Name)
+				continue
+			}
+			if filter.Action == FilterActionConfirm {
+				if subject.Retroactive {
+					continue
+				}
+				matched[filter.Name] = true
+				if counts[filter.Name] >= filter.Confirmations {
+					continue
+				}
+				verdict.Reason = fmt.Sprintf("awaiting confirmation: anomalous in %d of %d consecutive analyses",
+					counts[filter.Name], filter.Confirmations)
+			} else {
+				verdict.Reason = "dropped by post-filter"
+			}
+			verdict.Keep = false
+			verdict.Filter = filter.Name
+			verdict.Action = filter.Action
+			break
+		}
+		verdicts[i] = verdict
+	}
+
+	if !subject.Retroactive {
+		for key, previous := range c.confirms {
+			if subject.At.After(previous.expires) {
+				delete(c.confirms, key)
+			}
+		}
+		for i := range c.filters {
+			filter := &c.filters[i]
+			key := filter.Name + "|" + scope
+			if matched[filter.Name] {
+				c.confirms[key] = confirmation{seen: counts[filter.Name], expires: subject.At.Add(filter.confirmWithin)}
+			} else {
+				delete(c.confirms, key)
+			}
+		}
+	}
+	return verdicts
+}
+
+// matches reports whether a filter matches an anomaly of the given severity
+func (c *PostFilters) matches(ctx context.Context, filter *PostFilter, subject FilterSubject, severity string, facts *scopeFacts) bool {
+	if len(filter.Severities) > 0 && !contains(filter.Severities, severity) {
+		return false
+	}
+	if filter.Hours != nil {
+		local := subject.At.In(filter.Hours.location)
+		if filter.Hours.window.activeAt(local) == filter.Hours.Outside {
+			return false
+		}
+	}
+	if len(filter.Namespaces) > 0 {
+		found := false
+		for _, pattern := range filter.Namespaces {
+			if matched, _ := path.Match(pattern, subject.Namespace); matched && subject.Namespace != "" {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(filter.NamespaceLabels) > 0 {
+		if c.labels == nil || subject.Namespace == "" {
+			return false
+		}
+		if !facts.labelsLoaded {
+			facts.labels, facts.labelsLoaded = c.labels(ctx, subject.Namespace), true
+		}
+		for name, value := range filter.NamespaceLabels {
+			if actual, ok := facts.labels[name]; !ok || actual != value {
+				return false
+			}
+		}
+	}
+	if len(filter.Owners) > 0 {
+		if c.owners == nil || subject.Namespace == "" {
+			return false
+		}
+		if !facts.ownerLoaded {
+			resource := ""
+			switch {
+			case subject.Pod != "":
+				resource = "pod/" + subject.Pod
+			case subject.Deployment != "":
+				resource = "deployment/" + subject.Deployment
+			}
+			facts.owner, facts.ownerLoaded = c.owners(ctx, subject.Namespace, resource), true
+		}
+		if !contains(filter.Owners, facts.owner) {
+			return false
+		}
+	}
+	return true
+}
+
+// lowerAnomalySeverity returns the next lower anomaly severity
+func lowerAnomalySeverity(severity string) string {
+	if severity == "critical" {
+		return "warning"
+	}
+	return "info"
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// namespaceLabels reads namespace labels through the Kubernetes API and caches them
+type namespaceLabels struct {
+	client kubernetes.Interface
+	log    *logrus.Logger
+
+	mu    sync.Mutex
+	cache map[string]cachedLabels
+}
+
+type cachedLabels struct {
+	labels  map[string]string
+	expires time.Time
+}
+
+// NamespaceLabelLookup returns a LabelLookup reading namespaces through the Kubernetes
+// API. Results, including namespaces that cannot be read, are cached for a minute.
+func NamespaceLabelLookup(client kubernetes.Interface, log *logrus.Logger) LabelLookup {
+	labels := &namespaceLabels{client: client, log: log, cache: make(map[string]cachedLabels)}
+	return labels.lookup
+}
+
+func (n *namespaceLabels) lookup(ctx context.Context, namespace string) map[string]string {
+	n.mu.Lock()
+	cached, ok := n.cache[namespace]
+	n.mu.Unlock()
+	if ok && time.Now().Before(cached.expires) {
+		return cached.labels
+	}
+
+	var labels map[string]string
+	ns, err := n.client.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+	switch {
+	case err == nil:
+		labels = ns.Labels
+	case errors.Is(err, context.Canceled):
+		return nil
+	default:
+		n.log.WithError(err).WithField("namespace", namespace).Warn("Failed to read namespace labels, label filters will not match")
+	}
+
+	n.mu.Lock()
+	n.cache[namespace] = cachedLabels{labels: labels, expires: time.Now().Add(tierCacheTTL)}
+	n.mu.Unlock()
+	return labels
+}
@@ -0,0 +1,158 @@
+package anomaly
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const testPostFilters = `
+filters:
+  - name: quiet-dev
+    namespace_labels: {environment: dev}
+    severities: [info]
+    action: drop
+  - name: platform-owned
+    owners: [platform]
+    action: lower_severity
+  - name: after-hours
+    namespaces: ["payments*", "orders"]
+    hours:
+      days: [mon, tue, wed, thu, fri]
+      start: "08:00"
+      end: "18:00"
+      timezone: Europe/Berlin
+      outside: true
+    action: confirm
+    confirm_within: 15m
+`
+
+func loadTestPostFilters(t *testing.T) *PostFilters {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "post-filters.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(testPostFilters), 0o600))
+	filters, err := LoadPostFilters(path)
+	require.NoError(t, err)
+
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	filters.SetNamespaceLabels(NamespaceLabelLookup(fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop-dev", Labels: map[string]string{"environment": "dev"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop", Labels: map[string]string{"environment": "prod"}}},
+	), log))
+	filters.SetOwners(func(_ context.Context, namespace, resource string) string {
+		if namespace == "shop-dev" && resource == "deployment/ingress" {
+			return "platform"
+		}
+		return ""
+	})
+	return filters
+}
+
+func TestPostFilters_DropAndLower(t *testing.T) {
+	filters := loadTestPostFilters(t)
+	assert.Equal(t, []string{"quiet-dev", "platform-owned", "after-hours"}, filters.Names())
+	assert.True(t, filters.UsesNamespaceLabels())
+	assert.True(t, filters.UsesOwners())
+	ctx := context.Background()
+	at := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	verdicts := filters.Apply(ctx, FilterSubject{Namespace: "shop-dev", At: at}, []string{"info", "warning"})
+	assert.False(t, verdicts[0].Keep)
+	assert.Equal(t, "quiet-dev", verdicts[0].Filter)
+	assert.Equal(t, FilterActionDrop, verdicts[0].Action)
+	assert.Equal(t, FilterVerdict{Keep: true, Severity: "warning"}, verdicts[1])
+
+	verdicts = filters.Apply(ctx, FilterSubject{Namespace: "shop", At: at}, []string{"info"})
+	assert.True(t, verdicts[0].Keep, "only dev namespaces are filtered")
+
+	// Lowering to info does not bring the anomaly back past the filters before it
+	verdicts = filters.Apply(ctx, FilterSubject{Namespace: "shop-dev", Deployment: "ingress", At: at}, []string{"warning", "critical"})
+	assert.Equal(t, FilterVerdict{Keep: true, Severity: "info", LoweredBy: []string{"platform-owned"}}, verdicts[0])
+	assert.Equal(t, "warning", verdicts[1].Severity)
+
+	verdicts = filters.Apply(ctx, FilterSubject{At: at}, []string{"info"})
+	assert.True(t, verdicts[0].Keep, "cluster-wide analyses match no namespace conditions")
+}
+
+func TestPostFilters_Confirm(t *testing.T) {
+	filters := loadTestPostFilters(t)
+	ctx := context.Background()
+	evening := time.Date(2026, 3, 10, 19, 0, 0, 0, time.UTC) // 20:00 in Berlin
+	subject := FilterSubject{Namespace: "payments", Deployment: "api", Model: "anomaly-detector", At: evening}
+
+	verdicts := filters.Apply(ctx, subject, []string{"warning", "critical"})
+	assert.False(t, verdicts[0].Keep)
+	assert.Equal(t, FilterActionConfirm, verdicts[0].Action)
+	assert.Equal(t, "awaiting confirmation: anomalous in 1 of 2 consecutive analyses", verdicts[0].Reason)
+	assert.False(t, verdicts[1].Keep, "an analysis counts once")
+
+	// Another scope has its own count
+	other := subject
+	other.Deployment = "web"
+	assert.False(t, filters.Apply(ctx, other, []string{"warning"})[0].Keep)
+
+	subject.At = evening.Add(5 * time.Minute)
+	assert.True(t, filters.Apply(ctx, subject, []string{"warning"})[0].Keep, "confirmed by the second analysis")
+	subject.At = evening.Add(10 * time.Minute)
+	assert.True(t, filters.Apply(ctx, subject, []string{"warning"})[0].Keep)
+
+	// A quiet analysis starts the count over
+	subject.At = evening.Add(15 * time.Minute)
+	assert.Empty(t, filters.Apply(ctx, subject, nil))
+	subject.At = evening.Add(20 * time.Minute)
+	assert.False(t, filters.Apply(ctx, subject, []string{"warning"})[0].Keep)
+
+	// So does a gap longer than confirm_within
+	subject.At = evening.Add(40 * time.Minute)
+	assert.False(t, filters.Apply(ctx, subject, []string{"warning"})[0].Keep)
+
+	// Retroactive analyses are neither held nor counted
+	retroactive := subject
+	retroactive.Retroactive = true
+	retroactive.At = evening.Add(-24 * time.Hour)
+	assert.True(t, filters.Apply(ctx, retroactive, []string{"warning"})[0].Keep)
+
+	// During business hours anomalies pass right away
+	subject.At = time.Date(2026, 3, 10, 10, 0, 0, 0, time.UTC)
+	assert.True(t, filters.Apply(ctx, subject, []string{"warning"})[0].Keep)
+	assert.Empty(t, filters.confirms, "stale counts are dropped")
+}
+
+func TestNewPostFilters_Validation(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter PostFilter
+		err    string
+	}{
+		{"missing name", PostFilter{Action: FilterActionDrop}, "name is required"},
+		{"missing action", PostFilter{Name: "f"}, "action is required"},
+		{"unknown action", PostFilter{Name: "f", Action: "ignore"}, `invalid action "ignore"`},
+		{"confirmations on drop", PostFilter{Name: "f", Action: FilterActionDrop, Confirmations: 3}, "only apply to the confirm action"},
+		{"single confirmation", PostFilter{Name: "f", Action: FilterActionConfirm, Confirmations: 1}, "at least 2"},
+		{"invalid window", PostFilter{Name: "f", Action: FilterActionConfirm, ConfirmWithin: "soon"}, `invalid confirm_within "soon"`},
+		{"invalid severity", PostFilter{Name: "f", Action: FilterActionDrop, Severities: []string{"major"}}, `invalid severity "major"`},
+		{"invalid pattern", PostFilter{Name: "f", Action: FilterActionDrop, Namespaces: []string{"["}}, "invalid namespace pattern"},
+		{"hours without times", PostFilter{Name: "f", Action: FilterActionDrop, Hours: &FilterHours{Days: []string{"mon"}}}, "start and end are required"},
+		{"invalid day", PostFilter{Name: "f", Action: FilterActionDrop, Hours: &FilterHours{Days: []string{"monday"}, Start: "08:00", End: "18:00"}}, `invalid day "monday"`},
+		{"invalid timezone", PostFilter{Name: "f", Action: FilterActionDrop, Hours: &FilterHours{Start: "08:00", End: "18:00", Timezone: "Mars/Olympus"}}, "invalid timezone"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewPostFilters([]PostFilter{tt.filter})
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+
+	_, err := NewPostFilters([]PostFilter{{Name: "f", Action: FilterActionDrop}, {Name: "f", Action: FilterActionDrop}})
+	assert.ErrorContains(t, err, "duplicate name")
+}
@@ -0,0 +1,166 @@
+package anomaly
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Lifecycle phases a scope's pods are counted under. They are the pod phases, except
+// that pods being deleted are Terminating and Succeeded pods are Completed.
+const (
+	PodPhaseRunning     = "Running"
+	PodPhasePending     = "Pending"
+	PodPhaseTerminating = "Terminating"
+	PodPhaseCompleted   = "Completed"
+	PodPhaseFailed      = "Failed"
+	PodPhaseUnknown     = "Unknown"
+)
+
+// DefaultMaxScopePods bounds the pods a scope is narrowed to. Larger scopes are
+// analyzed unfiltered, as the pod matcher would make every query too long.
+const DefaultMaxScopePods = 200
+
+// PodScope is the pods of an analysis scope by lifecycle phase. Only running pods are
+// analyzed, so restart counts and memory ratios of pods that are starting, shutting
+// down or done do not pollute the scope's features.
+type PodScope struct {
+	// Phases counts the scope's pods by lifecycle phase
+	Phases map[string]int
+
+	// Running lists the names of the running pods, sorted
+	Running []string
+
+	// Filtered is set when pods are left out of the analysis. It is not set when every
+	// pod runs, or when the scope has more than the maximum number of running pods.
+	Filtered bool
+}
+
+// Excluded returns the number of pods left out of the analysis
+func (s *PodScope) Excluded() int {
+	if s == nil || !s.Filtered {
+		return 0
+	}
+	excluded := 0
+	for phase, count := range s.Phases {
+		if phase != PodPhaseRunning {
+			excluded += count
+		}
+	}
+	return excluded
+}
+
+// Matcher returns the PromQL label matcher selecting the running pods, or "" when the
+// scope is not filtered. A scope without running pods matches no series.
+func (s *PodScope) Matcher() string {
+	if s == nil || !s.Filtered {
+		return ""
+	}
+	names := make([]string, len(s.Running))
+	for i, name := range s.Running {
+		names[i] = regexp.QuoteMeta(name)
+	}
+	return fmt.Sprintf("pod=~%q", strings.Join(names, "|"))
+}
+
+// Describe summarizes the phases, e.g. "3 running, 1 terminating excluded"
+func (s *PodScope) Describe() string {
+	if s == nil {
+		return ""
+	}
+	running := s.Phases[PodPhaseRunning]
+	description := fmt.Sprintf("%d running", running)
+
+	var others []string
+	phases := make([]string, 0, len(s.Phases))
+	for phase := range s.Phases {
+		if phase != PodPhaseRunning {
+			phases = append(phases, phase)
+		}
+	}
+	sort.Strings(phases)
+	for _, phase := range phases {
+		others = append(others, fmt.Sprintf("%d %s", s.Phases[phase], strings.ToLower(phase)))
+	}
+	if len(others) == 0 {
+		return description
+	}
+	if !s.Filtered {
+		return fmt.Sprintf("%s, %s not excluded", description, strings.Join(others, ", "))
+	}
+	return fmt.Sprintf("%s, %s excluded", description, strings.Join(others, ", "))
+}
+
+// PodLifecyclePhase returns the lifecycle phase a pod is counted under
+func PodLifecyclePhase(pod *corev1.Pod) string {
+	if pod.DeletionTimestamp != nil {
+		return PodPhaseTerminating
+	}
+	switch pod.Status.Phase {
+	case corev1.PodRunning:
+		return PodPhaseRunning
+	case corev1.PodPending:
+		return PodPhasePending
+	case corev1.PodSucceeded:
+		return PodPhaseCompleted
+	case corev1.PodFailed:
+		return PodPhaseFailed
+	default:
+		return PodPhaseUnknown
+	}
+}
+
+// ScopeResolver resolves the pods of namespace and deployment scopes through the
+// Kubernetes API
+type ScopeResolver struct {
+	client  kubernetes.Interface
+	maxPods int
+}
+
+// NewScopeResolver creates a resolver narrowing scopes of up to maxPods running pods;
+// maxPods defaults to DefaultMaxScopePods
+func NewScopeResolver(client kubernetes.Interface, maxPods int) *ScopeResolver {
+	if maxPods <= 0 {
+		maxPods = DefaultMaxScopePods
+	}
+	return &ScopeResolver{client: client, maxPods: maxPods}
+}
+
+// Resolve lists the pods of a namespace, or of a deployment in it. Deployment pods are
+// matched by the "<deployment>-" name prefix, like the scope's PromQL matcher.
+// Cluster scopes, which have no namespace, return nil.
+func (r *ScopeResolver) Resolve(ctx context.Context, namespace, deployment string) (*PodScope, error) {
+	if namespace == "" {
+		return nil, nil
+	}
+	pods, err := r.client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods in namespace %s: %w", namespace, err)
+	}
+
+	scope := &PodScope{Phases: make(map[string]int)}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if deployment != "" && !strings.HasPrefix(pod.Name, deployment+"-") {
+			continue
+		}
+		phase := PodLifecyclePhase(pod)
+		scope.Phases[phase]++
+		if phase == PodPhaseRunning {
+			scope.Running = append(scope.Running, pod.Name)
+		}
+	}
+	sort.Strings(scope.Running)
+	total := 0
+	for _, count := range scope.Phases {
+		total += count
+	}
+	scope.Filtered = total > len(scope.Running) && len(scope.Running) <= r.maxPods
+	return scope, nil
+}
@@ -0,0 +1,96 @@
+package anomaly
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func testPod(name string, phase corev1.PodPhase, deleting bool) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "apps"},
+		Status:     corev1.PodStatus{Phase: phase},
+	}
+	if deleting {
+		now := metav1.Now()
+		pod.DeletionTimestamp = &now
+	}
+	return pod
+}
+
+func TestScopeResolver_Resolve(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		testPod("api-7d9f-abcde", corev1.PodRunning, false),
+		testPod("api-7d9f-fghij", corev1.PodRunning, false),
+		testPod("api-6c8e-klmno", corev1.PodRunning, true),
+		testPod("api-7d9f-pqrst", corev1.PodPending, false),
+		testPod("api-migrate-uvwxy", corev1.PodSucceeded, false),
+		testPod("worker-5b7d-zabcd", corev1.PodFailed, false),
+	)
+	resolver := NewScopeResolver(client, 0)
+
+	scope, err := resolver.Resolve(context.Background(), "apps", "api")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{
+		PodPhaseRunning:     2,
+		PodPhaseTerminating: 1,
+		PodPhasePending:     1,
+		PodPhaseCompleted:   1,
+	}, scope.Phases)
+	assert.Equal(t, []string{"api-7d9f-abcde", "api-7d9f-fghij"}, scope.Running)
+	assert.True(t, scope.Filtered)
+	assert.Equal(t, 3, scope.Excluded())
+	assert.Equal(t, `pod=~"api-7d9f-abcde|api-7d9f-fghij"`, scope.Matcher())
+	assert.Equal(t, "2 running, 1 completed, 1 pending, 1 terminating excluded", scope.Describe())
+
+	// The namespace scope counts every pod
+	scope, err = resolver.Resolve(context.Background(), "apps", "")
+	require.NoError(t, err)
+	assert.Equal(t, 1, scope.Phases[PodPhaseFailed])
+	assert.Equal(t, 4, scope.Excluded())
+
+	// Cluster scopes are not resolved
+	scope, err = resolver.Resolve(context.Background(), "", "")
+	require.NoError(t, err)
+	assert.Nil(t, scope)
+}
+
+func TestScopeResolver_Unfiltered(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		testPod("api-1", corev1.PodRunning, false),
+		testPod("api-2", corev1.PodRunning, false),
+		testPod("api-3", corev1.PodPending, false),
+	)
+
+	// More running pods than the maximum are analyzed unfiltered
+	scope, err := NewScopeResolver(client, 1).Resolve(context.Background(), "apps", "api")
+	require.NoError(t, err)
+	assert.False(t, scope.Filtered)
+	assert.Zero(t, scope.Excluded())
+	assert.Empty(t, scope.Matcher())
+	assert.Equal(t, "2 running, 1 pending not excluded", scope.Describe())
+
+	// Scopes where every pod runs need no filter
+	client = fake.NewSimpleClientset(testPod("api-1", corev1.PodRunning, false))
+	scope, err = NewScopeResolver(client, 0).Resolve(context.Background(), "apps", "api")
+	require.NoError(t, err)
+	assert.False(t, scope.Filtered)
+	assert.Equal(t, "1 running", scope.Describe())
+
+	var nilScope *PodScope
+	assert.Empty(t, nilScope.Matcher())
+	assert.Zero(t, nilScope.Excluded())
+}
+
+func TestPodScope_MatcherWithoutRunningPods(t *testing.T) {
+	scope := &PodScope{Phases: map[string]int{PodPhasePending: 2}, Filtered: true}
+	assert.Equal(t, `pod=~""`, scope.Matcher(), "a scope without running pods matches no series")
+
+	scope = &PodScope{Running: []string{"web.v2-0"}, Filtered: true}
+	assert.Equal(t, `pod=~"web\\.v2-0"`, scope.Matcher())
+}
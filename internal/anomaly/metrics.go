@@ -0,0 +1,38 @@
+package anomaly
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Outcomes of a background scan of a namespace
+const (
+	ScanClean     = "clean"
+	ScanAnomalous = "anomalous"
+	ScanFailed    = "failed"
+)
+
+var (
+	// Scans counts background scans by namespace and outcome
+	Scans = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "coordination_engine_anomaly_scans_total",
+			Help: "Total number of background anomaly scans, by namespace and outcome (clean, anomalous, failed)",
+		},
+		[]string{"namespace", "outcome"},
+	)
+
+	// ScanIncidents counts incidents opened by background scans by namespace
+	ScanIncidents = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "coordination_engine_anomaly_scan_incidents_total",
+			Help: "Total number of incidents opened by background anomaly scans, by namespace",
+		},
+		[]string{"namespace"},
+	)
+)
+
+// RecordScan counts a background scan of a namespace
+func RecordScan(namespace, outcome string) {
+	Scans.WithLabelValues(namespace, outcome).Inc()
+}
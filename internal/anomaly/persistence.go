@@ -0,0 +1,105 @@
+// Package anomaly implements noise reduction for repeated anomaly evaluations.
+package anomaly
+
+import (
+	"strings"
+	"sync"
+)
+
+// ScopeKey builds the key used to track a scope across evaluations.
+// Keys are hierarchical ("namespace", "namespace/deployment", "namespace/deployment/pod")
+// so that per-scope overrides can be configured at any level.
+func ScopeKey(namespace, deployment, pod string) string {
+	parts := []string{namespace}
+	if deployment != "" || pod != "" {
+		parts = append(parts, deployment)
+	}
+	if pod != "" {
+		parts = append(parts, pod)
+	}
+	key := strings.Join(parts, "/")
+	if key == "" {
+		return "cluster"
+	}
+	return key
+}
+
+// PersistenceTracker implements "for" semantics similar to Prometheus alert rules:
+// an anomaly must stay above threshold for N consecutive evaluations before it is
+// considered persistent enough to open an incident. Transient spikes reset the streak.
+type PersistenceTracker struct {
+	mu              sync.Mutex
+	defaultRequired int
+	overrides       map[string]int
+	streaks         map[string]int
+}
+
+// NewPersistenceTracker creates a tracker requiring defaultRequired consecutive evaluations.
+// overrides maps scope keys (see ScopeKey) to a scope-specific requirement.
+func NewPersistenceTracker(defaultRequired int, overrides map[string]int) *PersistenceTracker {
+	if defaultRequired < 1 {
+		defaultRequired = 1
+	}
+	copied := make(map[string]int, len(overrides))
+	for k, v := range overrides {
+		copied[k] = v
+	}
+	return &PersistenceTracker{
+		defaultRequired: defaultRequired,
+		overrides:       copied,
+		streaks:         make(map[string]int),
+	}
+}
+
+// Observe records one evaluation for the scope and reports whether the anomaly
+// has persisted long enough, along with the current streak length.
+func (t *PersistenceTracker) Observe(scope string, aboveThreshold bool) (persistent bool, streak int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !aboveThreshold {
+		delete(t.streaks, scope)
+		return false, 0
+	}
+
+	t.streaks[scope]++
+	streak = t.streaks[scope]
+	return streak >= t.required(scope), streak
+}
+
+// Required returns the number of consecutive evaluations required for a scope
+func (t *PersistenceTracker) Required(scope string) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.required(scope)
+}
+
+// Streak returns the current number of consecutive evaluations above threshold
+func (t *PersistenceTracker) Streak(scope string) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.streaks[scope]
+}
+
+// Reset clears the streak for a scope (e.g. after an incident has been created)
+func (t *PersistenceTracker) Reset(scope string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.streaks, scope)
+}
+
+// required resolves the most specific override for a scope.
+// Caller must hold the lock.
+func (t *PersistenceTracker) required(scope string) int {
+	key := scope
+	for {
+		if n, ok := t.overrides[key]; ok && n > 0 {
+			return n
+		}
+		idx := strings.LastIndex(key, "/")
+		if idx < 0 {
+			return t.defaultRequired
+		}
+		key = key[:idx]
+	}
+}
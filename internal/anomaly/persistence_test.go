@@ -0,0 +1,70 @@
+package anomaly
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScopeKey(t *testing.T) {
+	assert.Equal(t, "cluster", ScopeKey("", "", ""))
+	assert.Equal(t, "prod", ScopeKey("prod", "", ""))
+	assert.Equal(t, "prod/api", ScopeKey("prod", "api", ""))
+	assert.Equal(t, "prod/api/api-1", ScopeKey("prod", "api", "api-1"))
+	assert.Equal(t, "prod//api-1", ScopeKey("prod", "", "api-1"))
+}
+
+func TestPersistenceTracker_Observe(t *testing.T) {
+	tracker := NewPersistenceTracker(3, nil)
+	scope := ScopeKey("prod", "api", "")
+
+	t.Run("requires consecutive evaluations", func(t *testing.T) {
+		persistent, streak := tracker.Observe(scope, true)
+		assert.False(t, persistent)
+		assert.Equal(t, 1, streak)
+
+		persistent, _ = tracker.Observe(scope, true)
+		assert.False(t, persistent)
+
+		persistent, streak = tracker.Observe(scope, true)
+		assert.True(t, persistent)
+		assert.Equal(t, 3, streak)
+	})
+
+	t.Run("evaluation below threshold resets the streak", func(t *testing.T) {
+		persistent, streak := tracker.Observe(scope, false)
+		assert.False(t, persistent)
+		assert.Equal(t, 0, streak)
+
+		persistent, streak = tracker.Observe(scope, true)
+		assert.False(t, persistent)
+		assert.Equal(t, 1, streak)
+	})
+
+	t.Run("reset clears the streak", func(t *testing.T) {
+		tracker.Reset(scope)
+		assert.Equal(t, 0, tracker.Streak(scope))
+	})
+}
+
+func TestPersistenceTracker_Overrides(t *testing.T) {
+	tracker := NewPersistenceTracker(3, map[string]int{
+		"prod":     5,
+		"prod/api": 2,
+		"dev":      1,
+	})
+
+	assert.Equal(t, 2, tracker.Required("prod/api"))
+	assert.Equal(t, 2, tracker.Required("prod/api/api-1"), "pod inherits deployment override")
+	assert.Equal(t, 5, tracker.Required("prod/web"), "deployment inherits namespace override")
+	assert.Equal(t, 1, tracker.Required("dev"))
+	assert.Equal(t, 3, tracker.Required("staging"), "falls back to default")
+
+	persistent, _ := tracker.Observe("dev", true)
+	assert.True(t, persistent, "single evaluation is enough when override is 1")
+}
+
+func TestNewPersistenceTracker_MinimumOne(t *testing.T) {
+	tracker := NewPersistenceTracker(0, nil)
+	assert.Equal(t, 1, tracker.Required("any"))
+}
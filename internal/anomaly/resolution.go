@@ -0,0 +1,136 @@
+package anomaly
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/storage"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// ResolutionTracker implements hysteresis for incident resolution. An incident is
+// only considered cleared once the anomaly score has stayed below a clear threshold
+// (lower than the trigger threshold) for M consecutive evaluations, so a score
+// hovering around the trigger threshold does not open and close incidents repeatedly.
+type ResolutionTracker struct {
+	mu             sync.Mutex
+	clearThreshold float64
+	required       int
+	streaks        map[string]int
+}
+
+// NewResolutionTracker creates a tracker that clears after required evaluations below clearThreshold
+func NewResolutionTracker(clearThreshold float64, required int) *ResolutionTracker {
+	if required < 1 {
+		required = 1
+	}
+	return &ResolutionTracker{
+		clearThreshold: clearThreshold,
+		required:       required,
+		streaks:        make(map[string]int),
+	}
+}
+
+// Observe records one evaluation score for the scope and reports whether the
+// condition has cleared, along with the current streak of clear evaluations.
+func (t *ResolutionTracker) Observe(scope string, score float64) (cleared bool, streak int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if score >= t.clearThreshold {
+		delete(t.streaks, scope)
+		return false, 0
+	}
+
+	t.streaks[scope]++
+	streak = t.streaks[scope]
+	return streak >= t.required, streak
+}
+
+// ClearThreshold returns the score below which an evaluation counts as clear
+func (t *ResolutionTracker) ClearThreshold() float64 {
+	return t.clearThreshold
+}
+
+// Required returns the number of consecutive clear evaluations needed
+func (t *ResolutionTracker) Required() int {
+	return t.required
+}
+
+// Reset clears the streak for a scope
+func (t *ResolutionTracker) Reset(scope string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.streaks, scope)
+}
+
+// IncidentSummarizer writes a human-readable summary onto an incident
+type IncidentSummarizer interface {
+	Summarize(ctx context.Context, incident *models.Incident) error
+}
+
+// AutoResolver resolves incidents whose anomaly condition has cleared. Notification
+// channels watch the incident store, so they report the resolution like any other.
+type AutoResolver struct {
+	store      *storage.IncidentStore
+	tracker    *ResolutionTracker
+	summarizer IncidentSummarizer
+	log        *logrus.Logger
+}
+
+// NewAutoResolver creates an auto-resolver backed by the incident store
+func NewAutoResolver(store *storage.IncidentStore, tracker *ResolutionTracker, log *logrus.Logger) *AutoResolver {
+	return &AutoResolver{
+		store:   store,
+		tracker: tracker,
+		log:     log,
+	}
+}
+
+// SetSummarizer refreshes the incident summary on resolution, before the incident is
+// stored, so notifications describe the resolved incident
+func (r *AutoResolver) SetSummarizer(s IncidentSummarizer) {
+	r.summarizer = s
+}
+
+// Evaluate records the latest score for the scope of an open incident and resolves
+// the incident once the condition has cleared. It returns true if the incident was resolved.
+func (r *AutoResolver) Evaluate(ctx context.Context, scope, incidentID string, score float64) (bool, error) {
+	cleared, streak := r.tracker.Observe(scope, score)
+	if !cleared {
+		return false, nil
+	}
+
+	incident, err := r.store.Get(incidentID)
+	if err != nil {
+		return false, err
+	}
+	if !incident.IsActive() {
+		r.tracker.Reset(scope)
+		return false, nil
+	}
+
+	incident.AutoResolve(fmt.Sprintf(
+		"Condition cleared: anomaly score %.2f stayed below clear threshold %.2f for %d consecutive evaluations",
+		score, r.tracker.ClearThreshold(), streak))
+	if r.summarizer != nil {
+		if err := r.summarizer.Summarize(ctx, incident); err != nil {
+			r.log.WithError(err).WithField("incident_id", incidentID).Warn("Failed to summarize resolved incident")
+		}
+	}
+	if err := r.store.Update(incident); err != nil {
+		return false, fmt.Errorf("failed to resolve incident %s: %w", incidentID, err)
+	}
+	r.tracker.Reset(scope)
+
+	r.log.WithFields(logrus.Fields{
+		"incident_id": incidentID,
+		"scope":       scope,
+		"score":       score,
+	}).Info("Incident auto-resolved after condition cleared")
+
+	return true, nil
+}
@@ -0,0 +1,116 @@
+package anomaly
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/storage"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+type staticSummarizer string
+
+func (s staticSummarizer) Summarize(_ context.Context, incident *models.Incident) error {
+	incident.Summary = string(s) + " (" + string(incident.Status) + ")"
+	return nil
+}
+
+func TestResolutionTracker_Hysteresis(t *testing.T) {
+	tracker := NewResolutionTracker(0.5, 2)
+	scope := "prod/api"
+
+	cleared, _ := tracker.Observe(scope, 0.6)
+	assert.False(t, cleared, "score between clear and trigger threshold is not clear")
+
+	cleared, streak := tracker.Observe(scope, 0.3)
+	assert.False(t, cleared)
+	assert.Equal(t, 1, streak)
+
+	cleared, _ = tracker.Observe(scope, 0.55)
+	assert.False(t, cleared, "score back above clear threshold resets the streak")
+
+	tracker.Observe(scope, 0.2)
+	cleared, streak = tracker.Observe(scope, 0.1)
+	assert.True(t, cleared)
+	assert.Equal(t, 2, streak)
+}
+
+func newTestIncident(t *testing.T, store *storage.IncidentStore) *models.Incident {
+	incident, err := store.Create(&models.Incident{
+		Title:       "Anomaly detected",
+		Description: "Memory usage anomaly",
+		Severity:    models.IncidentSeverityHigh,
+		Target:      "prod",
+	})
+	require.NoError(t, err)
+	return incident
+}
+
+func TestAutoResolver_Evaluate(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	store := storage.NewIncidentStoreWithPath(t.TempDir())
+	incident := newTestIncident(t, store)
+
+	resolver := NewAutoResolver(store, NewResolutionTracker(0.5, 2), log)
+
+	resolved, err := resolver.Evaluate(context.Background(), "prod", incident.ID, 0.2)
+	require.NoError(t, err)
+	assert.False(t, resolved)
+
+	resolved, err = resolver.Evaluate(context.Background(), "prod", incident.ID, 0.1)
+	require.NoError(t, err)
+	assert.True(t, resolved)
+
+	stored, err := store.Get(incident.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.IncidentStatusResolved, stored.Status)
+	assert.NotNil(t, stored.ResolvedAt)
+	require.Len(t, stored.Events, 1)
+	assert.Equal(t, models.IncidentEventAutoResolved, stored.Events[0].Type)
+	assert.Contains(t, stored.Events[0].Message, "clear threshold 0.50")
+}
+
+func TestAutoResolver_SummaryStoredWithResolution(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	store := storage.NewIncidentStoreWithPath(t.TempDir())
+	incident := newTestIncident(t, store)
+
+	resolver := NewAutoResolver(store, NewResolutionTracker(0.5, 1), log)
+	resolver.SetSummarizer(staticSummarizer("Memory anomaly in prod cleared"))
+
+	resolved, err := resolver.Evaluate(context.Background(), "prod", incident.ID, 0.1)
+	require.NoError(t, err)
+	require.True(t, resolved)
+
+	stored, err := store.Get(incident.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Memory anomaly in prod cleared (resolved)", stored.Summary)
+}
+
+func TestAutoResolver_AlreadyResolved(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	store := storage.NewIncidentStoreWithPath(t.TempDir())
+	incident := newTestIncident(t, store)
+	incident.Resolve()
+	require.NoError(t, store.Update(incident))
+
+	resolver := NewAutoResolver(store, NewResolutionTracker(0.5, 1), log)
+
+	resolved, err := resolver.Evaluate(context.Background(), "prod", incident.ID, 0.1)
+	require.NoError(t, err)
+	assert.False(t, resolved)
+
+	stored, err := store.Get(incident.ID)
+	require.NoError(t, err)
+	assert.Empty(t, stored.Events, "no auto-resolution event")
+}
@@ -0,0 +1,31 @@
+package apitoken
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Rejection reasons
+const (
+	ReasonMissing           = "missing"
+	ReasonInvalid           = "invalid"
+	ReasonRevoked           = "revoked"
+	ReasonExpired           = "expired"
+	ReasonInsufficientScope = "insufficient_scope"
+)
+
+var (
+	// RejectionsTotal counts API requests rejected by the token check, by reason
+	RejectionsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "coordination_engine_api_token_rejections_total",
+			Help: "Total number of API requests rejected by the API token check, by reason",
+		},
+		[]string{"reason"},
+	)
+)
+
+// RecordRejection records a rejected request
+func RecordRejection(reason string) {
+	RejectionsTotal.WithLabelValues(reason).Inc()
+}
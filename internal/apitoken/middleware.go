@@ -0,0 +1,179 @@
+package apitoken
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// routeScope grants access to the routes under prefix (and ending in suffix, if set):
+// read to GET and HEAD requests, write to every other method. Routes with empty
+// scopes are exempt because they are public or authenticate callers themselves.
+type routeScope struct {
+	prefix, suffix string
+	read, write    Scope
+}
+
+// routeScopes maps API paths, without their /api/vN prefix, to the scopes they need.
+// The first matching entry applies, so more specific prefixes come first.
+var routeScopes = []routeScope{
+	{prefix: "/health/score", read: ScopeReadIncidents, write: ScopeReadIncidents},
+	{prefix: "/health"},
+	{prefix: "/admin"},                    // admin token
+	{prefix: "/models", suffix: "/infer"}, // inference gateway token or client certificate
+	{prefix: "/anomalies/analyze", read: ScopeReadAnomalies, write: ScopeReadAnomalies},
+	{prefix: "/anomalies/rescore", read: ScopeReadAnomalies, write: ScopeReadAnomalies},
+	{prefix: "/anomalies", read: ScopeReadAnomalies, write: ScopeWriteAnomalies},
+	{prefix: "/detect/cache/clear", read: ScopeWriteAnomalies, write: ScopeWriteAnomalies},
+	{prefix: "/detect", read: ScopeReadAnomalies, write: ScopeReadAnomalies},
+	{prefix: "/predict", read: ScopeReadAnomalies, write: ScopeReadAnomalies},
+	{prefix: "/models", read: ScopeReadAnomalies, write: ScopeReadAnomalies},
+	{prefix: "/capacity", read: ScopeReadAnomalies, write: ScopeReadAnomalies},
+	{prefix: "/rightsizing", read: ScopeReadAnomalies, write: ScopeReadAnomalies},
+	{prefix: "/metrics", read: ScopeReadAnomalies, write: ScopeReadAnomalies},
+	{prefix: "/batch", read: ScopeReadAnomalies, write: ScopeReadAnomalies},
+	{prefix: "/recommendations/outcomes", read: ScopeReadIncidents, write: ScopeWriteFeedback},
+	{prefix: "/recommendations", suffix: "/apply", read: ScopeExecuteRemediation, write: ScopeExecuteRemediation},
+	{prefix: "/recommendations", read: ScopeReadIncidents, write: ScopeReadIncidents},
+	{prefix: "/incidents", read: ScopeReadIncidents, write: ScopeWriteIncidents},
+	{prefix: "/alerts", read: ScopeWriteIncidents, write: ScopeWriteIncidents},
+	{prefix: "/notifications", read: ScopeReadIncidents, write: ScopeWriteIncidents},
+	{prefix: "/runbooks", read: ScopeReadIncidents, write: ScopeReadIncidents},
+	{prefix: "/policies", read: ScopeReadIncidents, write: ScopeReadIncidents},
+	{prefix: "/playbooks", suffix: "/run", read: ScopeExecuteRemediation, write: ScopeExecuteRemediation},
+	{prefix: "/playbooks", read: ScopeReadIncidents, write: ScopeReadIncidents},
+	{prefix: "/executions", read: ScopeReadIncidents, write: ScopeExecuteRemediation},
+	{prefix: "/incident-groups", read: ScopeReadIncidents, write: ScopeWriteIncidents},
+	{prefix: "/tenants", read: ScopeReadIncidents, write: ScopeWriteIncidents},
+	{prefix: "/upgrade", read: ScopeReadIncidents, write: ScopeReadIncidents},
+	{prefix: "/features", read: ScopeReadIncidents, write: ScopeReadIncidents},
+	{prefix: "/status", read: ScopeReadIncidents, write: ScopeReadIncidents},
+	{prefix: "/workflows", read: ScopeReadIncidents, write: ScopeExecuteRemediation},
+	{prefix: "/coordination", read: ScopeReadIncidents, write: ScopeExecuteRemediation},
+	{prefix: "/remediation/budget", read: ScopeReadIncidents, write: ScopeExecuteRemediation},
+	{prefix: "/remediation", read: ScopeExecuteRemediation, write: ScopeExecuteRemediation},
+	{prefix: "/mcp/approvals", read: ScopeReadIncidents, write: ScopeApproveRemediation},
+	{prefix: "/mcp", read: ScopeExecuteRemediation, write: ScopeExecuteRemediation},
+}
+
+// versionPrefix matches the API version at the start of a path
+var versionPrefix = regexp.MustCompile(`^/api/v[0-9]+`)
+
+// RequiredScope returns the scope a request needs, or "" if the route is exempt
+func RequiredScope(method, path string) Scope {
+	path = versionPrefix.ReplaceAllString(path, "")
+	for _, route := range routeScopes {
+		if (path != route.prefix && !strings.HasPrefix(path, route.prefix+"/")) || !strings.HasSuffix(path, route.suffix) {
+			continue
+		}
+		if method == http.MethodGet || method == http.MethodHead {
+			return route.read
+		}
+		return route.write
+	}
+	return ""
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying the API token that authenticated a request
+func NewContext(ctx context.Context, token *Token) context.Context {
+	return context.WithValue(ctx, contextKey{}, token)
+}
+
+// FromContext returns the API token that authenticated a request, or nil
+func FromContext(ctx context.Context) *Token {
+	token, _ := ctx.Value(contextKey{}).(*Token)
+	return token
+}
+
+// Middleware checks the scopes of API tokens. Requests to exempt routes pass
+// unchecked. Other requests presenting an engine token need an active token with the
+// route's scope; without one they are rejected when tokens are required and pass
+// otherwise, so mutual TLS or the route alone protects them.
+func Middleware(store *Store, required bool, log *logrus.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			scope := RequiredScope(r.Method, r.URL.Path)
+			if scope == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			credential, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || !IsToken(credential) {
+				if !required {
+					next.ServeHTTP(w, r)
+					return
+				}
+				reject(w, r, log, http.StatusUnauthorized, ReasonMissing, scope, nil, "API token required")
+				return
+			}
+
+			token, err := store.Authenticate(credential)
+			if err != nil {
+				reject(w, r, log, http.StatusUnauthorized, AuthenticationReason(err), scope, nil, err.Error())
+				return
+			}
+			if !token.HasScope(scope) {
+				reject(w, r, log, http.StatusForbidden, ReasonInsufficientScope, scope, token,
+					fmt.Sprintf("API token lacks scope %s", scope))
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), token)))
+		})
+	}
+}
+
+// AuthenticationReason returns the rejection reason of a failed Authenticate
+func AuthenticationReason(err error) string {
+	switch {
+	case errors.Is(err, ErrRevokedToken):
+		return ReasonRevoked
+	case errors.Is(err, ErrExpiredToken):
+		return ReasonExpired
+	default:
+		return ReasonInvalid
+	}
+}
+
+// reject records and answers a rejected request
+func reject(w http.ResponseWriter, r *http.Request, log *logrus.Logger, status int, reason string, scope Scope, token *Token, message string) {
+	RecordRejection(reason)
+	fields := logrus.Fields{
+		"method":      r.Method,
+		"path":        r.URL.Path,
+		"remote_addr": r.RemoteAddr,
+		"reason":      reason,
+		"scope":       scope,
+	}
+	if token != nil {
+		fields["token_id"] = token.ID
+		fields["token_name"] = token.Name
+	}
+	log.WithFields(fields).Warn("Request rejected by API token check")
+
+	challenge := `Bearer scope="` + string(scope) + `"`
+	if status == http.StatusForbidden {
+		challenge = `Bearer error="insufficient_scope", scope="` + string(scope) + `"`
+	} else if reason != ReasonMissing {
+		challenge = `Bearer error="invalid_token"`
+	}
+	w.Header().Set("WWW-Authenticate", challenge)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(map[string]string{
+		"status":         "error",
+		"error":          message,
+		"required_scope": string(scope),
+	}); err != nil {
+		log.WithError(err).Error("Failed to encode API token error response")
+	}
+}
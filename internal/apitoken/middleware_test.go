@@ -0,0 +1,111 @@
+package apitoken
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequiredScope(t *testing.T) {
+	tests := []struct {
+		method, path string
+		want         Scope
+	}{
+		{"GET", "/health", ""},
+		{"GET", "/api/v1/health/dependencies", ""},
+		{"GET", "/api/v1/health/self", ""},
+		{"GET", "/api/v1/health/score", ScopeReadIncidents},
+		{"POST", "/api/v1/admin/backup", ""},
+		{"POST", "/api/v1/models/anomaly-detector/infer", ""},
+		{"GET", "/api/v1/models/anomaly-detector/health", ScopeReadAnomalies},
+		{"POST", "/api/v1/anomalies/analyze", ScopeReadAnomalies},
+		{"POST", "/api/v2/anomalies/analyze", ScopeReadAnomalies},
+		{"POST", "/api/v1/anomalies/rescore", ScopeReadAnomalies},
+		{"POST", "/api/v1/anomalies/subscriptions", ScopeWriteAnomalies},
+		{"GET", "/api/v1/anomalies/subscriptions", ScopeReadAnomalies},
+		{"POST", "/api/v1/detect/cache/clear", ScopeWriteAnomalies},
+		{"GET", "/api/v1/metrics/query", ScopeReadAnomalies},
+		{"GET", "/api/v1/incidents", ScopeReadIncidents},
+		{"POST", "/api/v1/incidents", ScopeWriteIncidents},
+		{"DELETE", "/api/v1/incident-groups/grp-1a2b3c4d5e6f", ScopeWriteIncidents},
+		{"POST", "/api/v1/recommendations", ScopeReadIncidents},
+		{"POST", "/api/v1/recommendations/outcomes", ScopeWriteFeedback},
+		{"POST", "/api/v1/recommendations/rec-hist-001/apply", ScopeExecuteRemediation},
+		{"GET", "/api/v1/recommendations/rec-hist-001/application", ScopeReadIncidents},
+		{"POST", "/api/v1/policies/validate", ScopeReadIncidents},
+		{"POST", "/api/v1/playbooks/restart/run", ScopeExecuteRemediation},
+		{"GET", "/api/v1/executions/exec-1a2b3c4d", ScopeReadIncidents},
+		{"GET", "/api/v1/tenants/payments", ScopeReadIncidents},
+		{"GET", "/api/v1/features", ScopeReadIncidents},
+		{"DELETE", "/api/v1/tenants/payments", ScopeWriteIncidents},
+		{"POST", "/api/v1/remediation/trigger", ScopeExecuteRemediation},
+		{"GET", "/api/v1/remediation/budget/payments", ScopeReadIncidents},
+		{"POST", "/api/v1/remediation/budget/approvals", ScopeExecuteRemediation},
+		{"POST", "/api/v1/mcp/approvals/apr-1/approve", ScopeApproveRemediation},
+		{"GET", "/api/v1/mcp/approvals", ScopeReadIncidents},
+		{"POST", "/mcp", ScopeExecuteRemediation},
+		{"GET", "/api/v1/incidentsummary", ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, RequiredScope(tt.method, tt.path), "%s %s", tt.method, tt.path)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	store := NewStore(t.TempDir())
+	readOnly, _, err := store.Create("console-plugin", []string{"read:incidents"}, 0)
+	require.NoError(t, err)
+	bot, botToken, err := store.Create("chatops", []string{"read:incidents", "execute:remediation"}, 0)
+	require.NoError(t, err)
+
+	var seen *Token
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = FromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	})
+	serve := func(required bool, method, path, auth string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, http.NoBody)
+		if auth != "" {
+			req.Header.Set("Authorization", "Bearer "+auth)
+		}
+		rr := httptest.NewRecorder()
+		Middleware(store, required, log)(next).ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := serve(false, "GET", "/api/v1/incidents", readOnly)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	require.NotNil(t, seen)
+	assert.Equal(t, "console-plugin", seen.Name)
+
+	rr = serve(false, "POST", "/api/v1/remediation/trigger", readOnly)
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.Equal(t, `Bearer error="insufficient_scope", scope="execute:remediation"`, rr.Header().Get("WWW-Authenticate"))
+	assert.Contains(t, rr.Body.String(), `"required_scope":"execute:remediation"`)
+
+	assert.Equal(t, http.StatusOK, serve(false, "POST", "/api/v1/remediation/trigger", bot).Code)
+	assert.Equal(t, http.StatusUnauthorized, serve(false, "GET", "/api/v1/incidents", Prefix+"forged").Code)
+
+	// Without tokens required, requests without one pass
+	seen = nil
+	assert.Equal(t, http.StatusOK, serve(false, "GET", "/api/v1/incidents", "").Code)
+	assert.Nil(t, seen)
+	assert.Equal(t, http.StatusOK, serve(false, "GET", "/api/v1/incidents", "some-other-token").Code)
+
+	// With tokens required they are rejected, except on exempt routes
+	assert.Equal(t, http.StatusUnauthorized, serve(true, "GET", "/api/v1/incidents", "").Code)
+	assert.Equal(t, http.StatusOK, serve(true, "GET", "/api/v1/health", "").Code)
+	assert.Equal(t, http.StatusOK, serve(true, "POST", "/api/v1/admin/backup", "admin-secret").Code)
+
+	_, err = store.Revoke(botToken.ID)
+	require.NoError(t, err)
+	rr = serve(true, "POST", "/api/v1/remediation/trigger", bot)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.Contains(t, rr.Body.String(), "API token revoked")
+}
@@ -0,0 +1,255 @@
+package apitoken
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/api/validation"
+)
+
+// lastUsedResolution bounds how often a token's last use is persisted
+const lastUsedResolution = time.Minute
+
+// Authentication errors
+var (
+	// ErrUnknownToken is returned for credentials that match no issued token
+	ErrUnknownToken = errors.New("unknown API token")
+
+	// ErrRevokedToken is returned for revoked tokens
+	ErrRevokedToken = errors.New("API token revoked")
+
+	// ErrExpiredToken is returned for expired tokens
+	ErrExpiredToken = errors.New("API token expired")
+
+	// ErrNotFound is returned when revoking a token that does not exist
+	ErrNotFound = errors.New("API token not found")
+)
+
+// Store persists issued API tokens by the hash of their secret
+type Store struct {
+	tokens   map[string]*Token // by ID
+	byHash   map[string]*Token
+	mu       sync.RWMutex
+	dataFile string
+	now      func() time.Time
+}
+
+// NewStore creates an API token store in dataDir (DATA_DIR or /app/data if empty)
+func NewStore(dataDir string) *Store {
+	if dataDir == "" {
+		dataDir = os.Getenv("DATA_DIR")
+	}
+	if dataDir == "" {
+		dataDir = "/app/data"
+	}
+
+	if err := os.MkdirAll(dataDir, 0o755); err != nil {
+		fmt.Printf("Warning: Could not create data directory %s: %v\n", dataDir, err)
+	}
+	store := &Store{
+		tokens:   make(map[string]*Token),
+		byHash:   make(map[string]*Token),
+		dataFile: filepath.Join(dataDir, "api_tokens.json"),
+		now:      time.Now,
+	}
+
+	if err := store.load(); err != nil {
+		fmt.Printf("Warning: Could not load API tokens from disk: %v\n", err)
+	} else {
+		fmt.Printf("Loaded %d API tokens from %s\n", len(store.tokens), store.dataFile)
+	}
+
+	return store
+}
+
+// load reads API tokens from the JSON file
+func (s *Store) load() error {
+	data, err := os.ReadFile(s.dataFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read data file: %w", err)
+	}
+
+	var tokens []*Token
+	if err := json.Unmarshal(data, &tokens); err != nil {
+		return fmt.Errorf("failed to unmarshal API tokens: %w", err)
+	}
+	for _, token := range tokens {
+		s.tokens[token.ID] = token
+		s.byHash[token.Hash] = token
+	}
+
+	return nil
+}
+
+// save writes all API tokens to the JSON file. Callers must hold s.mu.
+func (s *Store) save() error {
+	dir := filepath.Dir(s.dataFile)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	tokens := make([]*Token, 0, len(s.tokens))
+	for _, token := range s.tokens {
+		tokens = append(tokens, token)
+	}
+	sort.Slice(tokens, func(i, j int) bool {
+		if tokens[i].CreatedAt.Equal(tokens[j].CreatedAt) {
+			return tokens[i].ID < tokens[j].ID
+		}
+		return tokens[i].CreatedAt.Before(tokens[j].CreatedAt)
+	})
+	data, err := json.Marshal(tokens)
+	if err != nil {
+		return fmt.Errorf("failed to marshal API tokens: %w", err)
+	}
+
+	// Write to temp file first, then rename (atomic); hashes stay readable by the
+	// engine only
+	tmpFile := s.dataFile + ".tmp"
+	if err := os.WriteFile(tmpFile, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write temp file: %w", err)
+	}
+	if err := os.Rename(tmpFile, s.dataFile); err != nil {
+		return fmt.Errorf("failed to rename temp file: %w", err)
+	}
+
+	return nil
+}
+
+// Create issues a token with the given scopes. A positive ttl makes it expire. It
+// returns the secret, which is not stored and cannot be retrieved again.
+func (s *Store) Create(name string, scopes []string, ttl time.Duration) (string, *Token, error) {
+	var errs validation.Errors
+	if name == "" {
+		errs.Add("name", validation.ConstraintRequired, nil, "name is required")
+	} else if len(name) > 100 {
+		errs.Add("name", validation.ConstraintLength, len(name), "name must not exceed 100 characters")
+	}
+	if len(scopes) == 0 {
+		errs.Add("scopes", validation.ConstraintRequired, nil, "at least one scope is required")
+	}
+	granted := make([]Scope, 0, len(scopes))
+	seen := make(map[string]bool, len(scopes))
+	for _, scope := range scopes {
+		if !IsValidScope(scope) {
+			errs.Add("scopes", validation.ConstraintEnum, scope, fmt.Sprintf("invalid scope %q, must be one of %v", scope, ValidScopes()))
+			continue
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			granted = append(granted, Scope(scope))
+		}
+	}
+	if ttl < 0 {
+		errs.Add("expires_in", validation.ConstraintRange, ttl.String(), "expires_in must not be negative")
+	}
+	if err := errs.Err(); err != nil {
+		return "", nil, err
+	}
+
+	secret, err := generateSecret()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to generate API token: %w", err)
+	}
+	now := s.now()
+	token := &Token{
+		ID:        "tok-" + uuid.New().String()[:8],
+		Name:      name,
+		Scopes:    granted,
+		Hint:      secret[:len(Prefix)+4],
+		Hash:      hashSecret(secret),
+		CreatedAt: now,
+	}
+	if ttl > 0 {
+		expires := now.Add(ttl)
+		token.ExpiresAt = &expires
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens[token.ID] = token
+	s.byHash[token.Hash] = token
+	if err := s.save(); err != nil {
+		delete(s.tokens, token.ID)
+		delete(s.byHash, token.Hash)
+		return "", nil, fmt.Errorf("failed to persist API token: %w", err)
+	}
+	return secret, token.Public(), nil
+}
+
+// List returns every issued token, including revoked and expired ones, oldest first
+func (s *Store) List() []*Token {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tokens := make([]*Token, 0, len(s.tokens))
+	for _, token := range s.tokens {
+		tokens = append(tokens, token.Public())
+	}
+	sort.Slice(tokens, func(i, j int) bool {
+		if tokens[i].CreatedAt.Equal(tokens[j].CreatedAt) {
+			return tokens[i].ID < tokens[j].ID
+		}
+		return tokens[i].CreatedAt.Before(tokens[j].CreatedAt)
+	})
+	return tokens
+}
+
+// Revoke revokes a token by ID. Revoking a revoked token returns it unchanged.
+func (s *Store) Revoke(id string) (*Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token, ok := s.tokens[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if token.RevokedAt == nil {
+		now := s.now()
+		token.RevokedAt = &now
+		if err := s.save(); err != nil {
+			token.RevokedAt = nil
+			return nil, fmt.Errorf("failed to persist API token: %w", err)
+		}
+	}
+	return token.Public(), nil
+}
+
+// Authenticate returns the token a secret belongs to if it is active, and records its
+// use
+func (s *Store) Authenticate(secret string) (*Token, error) {
+	hash := hashSecret(secret)
+	now := s.now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token, ok := s.byHash[hash]
+	switch {
+	case !ok:
+		return nil, ErrUnknownToken
+	case token.RevokedAt != nil:
+		return nil, ErrRevokedToken
+	case !token.Active(now):
+		return nil, ErrExpiredToken
+	}
+
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= lastUsedResolution {
+		token.LastUsedAt = &now
+		if err := s.save(); err != nil {
+			fmt.Printf("Warning: Failed to persist API token use: %v\n", err)
+		}
+	}
+	return token.Public(), nil
+}
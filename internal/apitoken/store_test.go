@@ -0,0 +1,79 @@
+package apitoken
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/api/validation"
+)
+
+func TestStore(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(dir)
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+
+	secret, token, err := store.Create("console-plugin", []string{"read:anomalies", "read:incidents", "read:anomalies"}, 0)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(secret, Prefix))
+	assert.Equal(t, []Scope{ScopeReadAnomalies, ScopeReadIncidents}, token.Scopes, "duplicate scopes are dropped")
+	assert.Equal(t, secret[:8], token.Hint)
+	assert.Empty(t, token.Hash, "hashes are not returned")
+	assert.Nil(t, token.ExpiresAt)
+
+	// Only the hash is persisted
+	data, err := os.ReadFile(filepath.Join(dir, "api_tokens.json"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), secret)
+	assert.Contains(t, string(data), hashSecret(secret))
+
+	authenticated, err := store.Authenticate(secret)
+	require.NoError(t, err)
+	assert.Equal(t, token.ID, authenticated.ID)
+	assert.True(t, authenticated.HasScope(ScopeReadIncidents))
+	assert.False(t, authenticated.HasScope(ScopeExecuteRemediation))
+	require.NotNil(t, authenticated.LastUsedAt)
+
+	_, err = store.Authenticate(Prefix + "unknown")
+	assert.ErrorIs(t, err, ErrUnknownToken)
+
+	// Expiry
+	now = now.Add(time.Minute)
+	ciSecret, ciToken, err := store.Create("ci-gate", []string{"read:anomalies"}, time.Hour)
+	require.NoError(t, err)
+	require.NotNil(t, ciToken.ExpiresAt)
+	now = now.Add(2 * time.Hour)
+	_, err = store.Authenticate(ciSecret)
+	assert.ErrorIs(t, err, ErrExpiredToken)
+
+	// Revocation survives a restart
+	revoked, err := store.Revoke(token.ID)
+	require.NoError(t, err)
+	require.NotNil(t, revoked.RevokedAt)
+	_, err = store.Revoke("tok-missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	reloaded := NewStore(dir)
+	_, err = reloaded.Authenticate(secret)
+	assert.ErrorIs(t, err, ErrRevokedToken)
+	tokens := reloaded.List()
+	require.Len(t, tokens, 2)
+	assert.Equal(t, "console-plugin", tokens[0].Name)
+	assert.Empty(t, tokens[0].Hash)
+
+	// Validation
+	_, _, err = store.Create("", []string{"delete:everything"}, -time.Hour)
+	fields := validation.Fields(err)
+	require.Len(t, fields, 3)
+	assert.Equal(t, "name", fields[0].Field)
+	assert.Equal(t, "scopes", fields[1].Field)
+	assert.Equal(t, "expires_in", fields[2].Field)
+	_, _, err = store.Create("bot", nil, 0)
+	assert.ErrorContains(t, err, "at least one scope is required")
+}
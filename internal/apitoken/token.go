@@ -0,0 +1,132 @@
+// Package apitoken issues scoped API tokens so integrations such as the console plugin,
+// CI gates and chatops bots each get least-privilege credentials. Tokens are shown
+// once when created and only their SHA-256 hash is stored.
+package apitoken
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+// Prefix starts every token, so engine tokens can be told apart from the admin and
+// inference gateway tokens in the same Authorization header
+const Prefix = "cet_"
+
+// Scope is a permission granted to a token
+type Scope string
+
+// Token scopes
+const (
+	// ScopeReadAnomalies allows anomaly analysis, detection, predictions, models,
+	// capacity reports and scoped metrics queries
+	ScopeReadAnomalies Scope = "read:anomalies"
+
+	// ScopeWriteAnomalies allows managing anomaly subscriptions and the detection cache
+	ScopeWriteAnomalies Scope = "write:anomalies"
+
+	// ScopeReadIncidents allows reading incidents, workflows, recommendations, runbooks,
+	// notification deliveries and status reports
+	ScopeReadIncidents Scope = "read:incidents"
+
+	// ScopeWriteIncidents allows creating and summarizing incidents, posting alerts and
+	// sending digests
+	ScopeWriteIncidents Scope = "write:incidents"
+
+	// ScopeWriteFeedback allows reporting verified remediation outcomes
+	ScopeWriteFeedback Scope = "write:feedback"
+
+	// ScopeExecuteRemediation allows triggering remediation, deciding budget approvals
+	// and calling MCP tools
+	ScopeExecuteRemediation Scope = "execute:remediation"
+
+	// ScopeApproveRemediation allows deciding remediations requested through MCP. Grant
+	// it to operators only, never to the assistant's token.
+	ScopeApproveRemediation Scope = "approve:remediation"
+)
+
+// ValidScopes returns every scope a token can be granted
+func ValidScopes() []Scope {
+	return []Scope{
+		ScopeReadAnomalies,
+		ScopeWriteAnomalies,
+		ScopeReadIncidents,
+		ScopeWriteIncidents,
+		ScopeWriteFeedback,
+		ScopeExecuteRemediation,
+		ScopeApproveRemediation,
+	}
+}
+
+// IsValidScope checks if a scope string is valid
+func IsValidScope(scope string) bool {
+	for _, s := range ValidScopes() {
+		if string(s) == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Token is an issued API token. The secret is never stored; Hash identifies it.
+type Token struct {
+	ID     string  `json:"id"`
+	Name   string  `json:"name"`
+	Scopes []Scope `json:"scopes"`
+
+	// Hint is the start of the secret, to recognize a token in listings
+	Hint string `json:"hint"`
+	Hash string `json:"hash,omitempty"`
+
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+// HasScope reports whether the token grants a scope
+func (t *Token) HasScope(scope Scope) bool {
+	for _, s := range t.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Active reports whether the token is neither revoked nor expired at now
+func (t *Token) Active(now time.Time) bool {
+	return t.RevokedAt == nil && (t.ExpiresAt == nil || now.Before(*t.ExpiresAt))
+}
+
+// Public returns a copy of the token without its hash, for API responses
+func (t *Token) Public() *Token {
+	public := *t
+	public.Hash = ""
+	public.Scopes = append([]Scope(nil), t.Scopes...)
+	return &public
+}
+
+// IsToken reports whether a bearer credential looks like an engine API token
+func IsToken(credential string) bool {
+	return strings.HasPrefix(credential, Prefix)
+}
+
+// generateSecret returns a new random token secret
+func generateSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return Prefix + base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// hashSecret returns the hex SHA-256 hash of a token secret. The secrets are random,
+// so a fast unsalted hash does not make them guessable.
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
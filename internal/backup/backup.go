@@ -0,0 +1,420 @@
+// Package backup exports and restores the engine's persisted state.
+//
+// A backup is a gzip-compressed tar archive holding a manifest.json and one
+// <section>.json file per registered Section (incidents, prediction history, ...).
+// Archives can be downloaded directly or uploaded to object storage through a
+// pre-signed URL, and restored on the same or another cluster to migrate the
+// engine or recover its learned state.
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Archive format settings
+const (
+	// FormatVersion is the archive layout version written to the manifest
+	FormatVersion = 1
+
+	// ManifestFile is the name of the manifest entry in the archive
+	ManifestFile = "manifest.json"
+
+	// MaxArchiveSize bounds the uncompressed size of an archive read during restore
+	MaxArchiveSize = 512 << 20
+)
+
+// Section is a piece of engine state included in backups
+type Section interface {
+	// Name identifies the section; its data is stored in the archive as <name>.json
+	Name() string
+
+	// Export returns the section's items, written to the archive as JSON, and their count
+	Export() (items interface{}, count int, err error)
+
+	// Import restores the section from the JSON written by Export and returns the
+	// number of items restored. With replace, existing items are dropped first.
+	Import(data []byte, replace bool) (count int, err error)
+}
+
+// Manifest describes the contents of an archive
+type Manifest struct {
+	FormatVersion int           `json:"format_version"`
+	EngineVersion string        `json:"engine_version"`
+	CreatedAt     time.Time     `json:"created_at"`
+	Sections      []SectionInfo `json:"sections"`
+
+	// ClusterShape is the size of the cluster the archive was written on, when known
+	ClusterShape *ClusterShape `json:"cluster_shape,omitempty"`
+}
+
+// SectionInfo is the item count of one section
+type SectionInfo struct {
+	Name  string `json:"name"`
+	Items int    `json:"items"`
+}
+
+// RestoreResult reports what a restore changed
+type RestoreResult struct {
+	Manifest Manifest      `json:"manifest"`
+	Replace  bool          `json:"replace"`
+	Restored []SectionInfo `json:"restored"`
+
+	// Skipped lists archive sections this engine does not know about
+	Skipped []string `json:"skipped,omitempty"`
+
+	// Missing lists registered sections absent from the archive; they are left unchanged
+	Missing []string `json:"missing,omitempty"`
+
+	// ShapeMismatch describes how the archive's cluster differs from this one when an
+	// archive from a cluster of a different shape was imported with force
+	ShapeMismatch string `json:"shape_mismatch,omitempty"`
+}
+
+var (
+	// ErrInvalidArchive is returned when an archive cannot be read or is not a backup
+	ErrInvalidArchive = errors.New("invalid backup archive")
+
+	// ErrInvalidURL is returned for object storage URLs that are not absolute http(s) URLs
+	ErrInvalidURL = errors.New("invalid url: must be an absolute http or https URL")
+)
+
+// Manager writes and restores backup archives of the registered sections
+type Manager struct {
+	sections      []Section
+	engineVersion string
+	shape         ShapeFunc
+	httpClient    *http.Client
+	log           *logrus.Logger
+
+	// now is replaceable in tests
+	now func() time.Time
+}
+
+// NewManager creates a backup manager. timeout bounds object storage transfers.
+func NewManager(engineVersion string, timeout time.Duration, log *logrus.Logger) *Manager {
+	return &Manager{
+		engineVersion: engineVersion,
+		httpClient:    &http.Client{Timeout: timeout},
+		log:           log,
+		now:           time.Now,
+	}
+}
+
+// HTTPClient returns the client used to reach object storage, e.g. to audit its TLS configuration
+func (m *Manager) HTTPClient() *http.Client {
+	return m.httpClient
+}
+
+// SetClusterShape records the cluster's shape in archives and lets Import compare it
+// with the shape of the cluster an archive was written on
+func (m *Manager) SetClusterShape(shape ShapeFunc) {
+	m.shape = shape
+}
+
+// Register adds a section to backups. Sections are restored in registration order.
+func (m *Manager) Register(section Section) {
+	m.sections = append(m.sections, section)
+}
+
+// Write exports every registered section and writes the archive to w
+func (m *Manager) Write(w io.Writer) (*Manifest, error) {
+	manifest := &Manifest{
+		FormatVersion: FormatVersion,
+		EngineVersion: m.engineVersion,
+		CreatedAt:     m.now().UTC(),
+		Sections:      make([]SectionInfo, 0, len(m.sections)),
+		ClusterShape:  m.clusterShape(),
+	}
+
+	files := make(map[string][]byte, len(m.sections)+1)
+	for _, section := range m.sections {
+		items, count, err := section.Export()
+		if err != nil {
+			return nil, fmt.Errorf("failed to export %s: %w", section.Name(), err)
+		}
+		data, err := json.Marshal(items)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal %s: %w", section.Name(), err)
+		}
+		files[section.Name()+".json"] = data
+		manifest.Sections = append(manifest.Sections, SectionInfo{Name: section.Name(), Items: count})
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	// The manifest goes first so readers can inspect an archive without unpacking it all
+	if err := writeEntry(tw, ManifestFile, data, manifest.CreatedAt); err != nil {
+		return nil, err
+	}
+	for _, info := range manifest.Sections {
+		name := info.Name + ".json"
+		if err := writeEntry(tw, name, files[name], manifest.CreatedAt); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finish archive: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress archive: %w", err)
+	}
+
+	m.log.WithField("sections", len(manifest.Sections)).Info("Engine state backup written")
+	return manifest, nil
+}
+
+// writeEntry adds one file to the archive
+func writeEntry(tw *tar.Writer, name string, data []byte, modTime time.Time) error {
+	header := &tar.Header{
+		Name:    name,
+		Mode:    0o644,
+		Size:    int64(len(data)),
+		ModTime: modTime,
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+// clusterShape returns the cluster's shape, nil when it is unknown
+func (m *Manager) clusterShape() *ClusterShape {
+	if m.shape == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), m.httpClient.Timeout)
+	defer cancel()
+	shape, err := m.shape(ctx)
+	if err != nil {
+		m.log.WithError(err).Warn("Failed to read the cluster shape")
+		return nil
+	}
+	return shape
+}
+
+// Restore reads an archive and imports every registered section it contains. The
+// whole archive is read and checked before anything is imported, so a corrupt
+// archive leaves the engine state unchanged.
+func (m *Manager) Restore(r io.Reader, replace bool) (*RestoreResult, error) {
+	return m.restore(r, replace, nil)
+}
+
+// Import merges the sections of an archive written on another cluster into the
+// engine's state. Archives from a cluster not within MaxShapeRatio of this one in
+// size are rejected with ErrShapeMismatch unless force is set.
+func (m *Manager) Import(r io.Reader, force bool) (*RestoreResult, error) {
+	var mismatch string
+	result, err := m.restore(r, false, func(manifest *Manifest) error {
+		local := m.clusterShape()
+		if manifest.ClusterShape == nil || local == nil || local.Similar(manifest.ClusterShape) {
+			return nil
+		}
+		mismatch = fmt.Sprintf("archive was written on a cluster with %s, this cluster has %s", manifest.ClusterShape, local)
+		if !force {
+			return fmt.Errorf("%w: %s", ErrShapeMismatch, mismatch)
+		}
+		return nil
+	})
+	if result != nil {
+		result.ShapeMismatch = mismatch
+	}
+	return result, err
+}
+
+// restore reads an archive, checks it with check if set and imports its sections
+func (m *Manager) restore(r io.Reader, replace bool, check func(*Manifest) error) (*RestoreResult, error) {
+	files, err := readArchive(r)
+	if err != nil {
+		return nil, err
+	}
+
+	data, ok := files[ManifestFile]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s not found", ErrInvalidArchive, ManifestFile)
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("%w: failed to parse manifest: %v", ErrInvalidArchive, err)
+	}
+	if manifest.FormatVersion < 1 || manifest.FormatVersion > FormatVersion {
+		return nil, fmt.Errorf("%w: unsupported format version %d", ErrInvalidArchive, manifest.FormatVersion)
+	}
+
+	result := &RestoreResult{
+		Manifest: manifest,
+		Replace:  replace,
+		Restored: []SectionInfo{},
+	}
+
+	registered := make(map[string]bool, len(m.sections))
+	for _, section := range m.sections {
+		registered[section.Name()] = true
+	}
+	inArchive := make(map[string]bool, len(manifest.Sections))
+	for _, info := range manifest.Sections {
+		inArchive[info.Name] = true
+		if !registered[info.Name] {
+			result.Skipped = append(result.Skipped, info.Name)
+			continue
+		}
+		sectionData, ok := files[info.Name+".json"]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s.json listed in manifest but not found", ErrInvalidArchive, info.Name)
+		}
+		if !json.Valid(sectionData) {
+			return nil, fmt.Errorf("%w: %s.json is not valid JSON", ErrInvalidArchive, info.Name)
+		}
+	}
+
+	if check != nil {
+		if err := check(&manifest); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, section := range m.sections {
+		if !inArchive[section.Name()] {
+			result.Missing = append(result.Missing, section.Name())
+			continue
+		}
+		count, err := section.Import(files[section.Name()+".json"], replace)
+		if err != nil {
+			return result, fmt.Errorf("failed to restore %s: %w", section.Name(), err)
+		}
+		result.Restored = append(result.Restored, SectionInfo{Name: section.Name(), Items: count})
+	}
+	sort.Strings(result.Skipped)
+
+	m.log.WithFields(logrus.Fields{
+		"created_at":     manifest.CreatedAt,
+		"engine_version": manifest.EngineVersion,
+		"replace":        replace,
+		"restored":       len(result.Restored),
+		"skipped":        len(result.Skipped),
+	}).Warn("Engine state restored from backup")
+
+	return result, nil
+}
+
+// readArchive reads every file of a gzip-compressed tar archive into memory,
+// rejecting archives larger than MaxArchiveSize
+func readArchive(r io.Reader) (map[string][]byte, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+	}
+	defer gz.Close()
+
+	files := make(map[string][]byte)
+	var total int64
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		total += header.Size
+		if total > MaxArchiveSize {
+			return nil, fmt.Errorf("%w: larger than %d bytes", ErrInvalidArchive, MaxArchiveSize)
+		}
+		data, err := io.ReadAll(io.LimitReader(tr, header.Size))
+		if err != nil {
+			return nil, fmt.Errorf("%w: failed to read %s: %v", ErrInvalidArchive, header.Name, err)
+		}
+		files[path.Clean(header.Name)] = data
+	}
+	return files, nil
+}
+
+// Upload writes an archive and PUTs it to a pre-signed object storage URL (S3, GCS,
+// Azure Blob or any HTTP endpoint accepting PUT)
+func (m *Manager) Upload(ctx context.Context, target string) (*Manifest, error) {
+	if err := validateURL(target); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	manifest, err := m.Write(&buf)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, target, &buf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create upload request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/gzip")
+
+	resp, err := m.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload backup: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("failed to upload backup: object storage returned %s", resp.Status)
+	}
+
+	m.log.WithField("host", req.URL.Host).Info("Engine state backup uploaded")
+	return manifest, nil
+}
+
+// Download fetches an archive from a (pre-signed) URL and restores it
+func (m *Manager) Download(ctx context.Context, source string, replace bool) (*RestoreResult, error) {
+	if err := validateURL(source); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create download request: %w", err)
+	}
+
+	resp, err := m.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download backup: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download backup: object storage returned %s", resp.Status)
+	}
+
+	return m.Restore(resp.Body, replace)
+}
+
+// validateURL accepts absolute http and https URLs
+func validateURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrInvalidURL
+	}
+	return nil
+}
@@ -0,0 +1,325 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/notification"
+	"github.com/tosin2013/openshift-coordination-engine/internal/runtimeconfig"
+	"github.com/tosin2013/openshift-coordination-engine/internal/storage"
+	"github.com/tosin2013/openshift-coordination-engine/internal/tenant"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// testEngine is one engine's stores registered with a backup manager
+type testEngine struct {
+	dir         string
+	incidents   *storage.IncidentStore
+	predictions *storage.PredictionStore
+	manager     *Manager
+}
+
+func newTestEngine(t *testing.T) *testEngine {
+	t.Helper()
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	dir := t.TempDir()
+	e := &testEngine{
+		dir:         dir,
+		incidents:   storage.NewIncidentStoreWithPath(dir),
+		predictions: storage.NewPredictionStore(dir, 0),
+		manager:     NewManager("v1.2.3", time.Second, log),
+	}
+	e.manager.Register(NewIncidentSection(e.incidents))
+	e.manager.Register(NewPredictionSection(e.predictions))
+	return e
+}
+
+func (e *testEngine) addIncident(t *testing.T, title string) *models.Incident {
+	t.Helper()
+	incident, err := e.incidents.Create(&models.Incident{
+		Title:       title,
+		Description: title,
+		Severity:    models.IncidentSeverityHigh,
+		Target:      "apps",
+	})
+	require.NoError(t, err)
+	return incident
+}
+
+func TestManager_RoundTrip(t *testing.T) {
+	source := newTestEngine(t)
+	incident := source.addIncident(t, "API pods restarting")
+	source.addIncident(t, "Database latency")
+	require.NoError(t, source.predictions.Add([]*models.PredictionRecord{
+		{Model: "predictive-analytics", Scope: "namespace", Target: "apps", TargetTime: time.Now()},
+	}))
+
+	var archive bytes.Buffer
+	manifest, err := source.manager.Write(&archive)
+	require.NoError(t, err)
+	assert.Equal(t, FormatVersion, manifest.FormatVersion)
+	assert.Equal(t, "v1.2.3", manifest.EngineVersion)
+	assert.Equal(t, []SectionInfo{
+		{Name: SectionIncidents, Items: 2},
+		{Name: SectionPredictions, Items: 1},
+	}, manifest.Sections)
+
+	target := newTestEngine(t)
+	existing := target.addIncident(t, "Existing incident")
+
+	result, err := target.manager.Restore(bytes.NewReader(archive.Bytes()), false)
+	require.NoError(t, err)
+	assert.Equal(t, manifest.Sections, result.Restored)
+	assert.Empty(t, result.Skipped)
+	assert.Equal(t, 3, target.incidents.Count(), "merge keeps existing incidents")
+	assert.Equal(t, 1, target.predictions.Count())
+
+	restored, err := target.incidents.Get(incident.ID)
+	require.NoError(t, err)
+	assert.Equal(t, incident.Title, restored.Title)
+	assert.True(t, incident.CreatedAt.Equal(restored.CreatedAt))
+
+	_, err = target.manager.Restore(bytes.NewReader(archive.Bytes()), true)
+	require.NoError(t, err)
+	assert.Equal(t, 2, target.incidents.Count(), "replace drops existing incidents")
+	_, err = target.incidents.Get(existing.ID)
+	assert.Error(t, err)
+
+	// Restored state is persisted
+	assert.Equal(t, 2, storage.NewIncidentStoreWithPath(target.dir).Count())
+}
+
+func TestManager_RestoreSkipsUnknownSections(t *testing.T) {
+	source := newTestEngine(t)
+	source.addIncident(t, "API pods restarting")
+	var archive bytes.Buffer
+	_, err := source.manager.Write(&archive)
+	require.NoError(t, err)
+
+	// An engine that only backs up incidents skips predictions
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	store := storage.NewIncidentStoreWithPath(t.TempDir())
+	manager := NewManager("v1.2.3", time.Second, log)
+	manager.Register(NewIncidentSection(store))
+
+	result, err := manager.Restore(&archive, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{SectionPredictions}, result.Skipped)
+	assert.Equal(t, 1, store.Count())
+}
+
+func TestManager_RoundTripConfiguration(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	newManager := func(dir string) (*runtimeconfig.Store, *tenant.Registry, *Manager) {
+		config := runtimeconfig.NewStore(dir)
+		tenants := tenant.NewRegistry(dir, nil)
+		manager := NewManager("v1.2.3", time.Second, log)
+		manager.Register(NewRuntimeConfigSection(config))
+		manager.Register(NewTenantSection(tenants))
+		return config, tenants, manager
+	}
+
+	sourceConfig, sourceTenants, source := newManager(t.TempDir())
+	policy, err := sourceConfig.Create(runtimeconfig.KindPolicy, "deny-prod", json.RawMessage(
+		`{"match":{"namespaces":["prod-*"]},"action":"deny","reason":"change freeze"}`))
+	require.NoError(t, err)
+	_, err = sourceConfig.Create(runtimeconfig.KindSilence, "maintenance", json.RawMessage(
+		`{"match":{"namespaces":["payments"]},"ends_at":"2099-01-01T00:00:00Z","comment":"CHG-1234"}`))
+	require.NoError(t, err)
+	_, err = sourceConfig.Create(runtimeconfig.KindNotificationChannel, "oncall", json.RawMessage(
+		`{"type":"webhook","url":"https://hooks.example.com/oncall","secret":"s3cret","events":["incident.created"]}`))
+	require.NoError(t, err)
+	_, err = sourceTenants.Create(&tenant.Tenant{
+		Name:          "payments",
+		Namespace:     "payments",
+		Notifications: []tenant.Webhook{{URL: "https://hooks.example.com/payments", Secret: "t3nant", Events: []notification.EventType{notification.EventAll}}},
+	})
+	require.NoError(t, err)
+
+	var archive bytes.Buffer
+	manifest, err := source.Write(&archive)
+	require.NoError(t, err)
+	assert.Equal(t, []SectionInfo{
+		{Name: SectionRuntimeConfig, Items: 3},
+		{Name: SectionTenants, Items: 1},
+	}, manifest.Sections)
+
+	dir := t.TempDir()
+	targetConfig, targetTenants, target := newManager(dir)
+	_, err = targetConfig.Create(runtimeconfig.KindPolicy, "allow-all", json.RawMessage(`{"action":"allow"}`))
+	require.NoError(t, err)
+
+	_, err = target.Restore(bytes.NewReader(archive.Bytes()), true)
+	require.NoError(t, err)
+
+	_, err = targetConfig.Get(runtimeconfig.KindPolicy, "allow-all")
+	assert.ErrorIs(t, err, runtimeconfig.ErrNotFound, "replace drops existing objects")
+	name, restored := targetConfig.PolicyFor(&models.Issue{Namespace: "prod-payments"})
+	assert.Equal(t, "deny-prod", name)
+	require.NotNil(t, restored)
+	assert.Equal(t, "change freeze", restored.Reason)
+	assert.Equal(t, "maintenance", targetConfig.Silenced(&models.Incident{Target: "payments"}))
+
+	restoredPolicy, err := targetConfig.Get(runtimeconfig.KindPolicy, "deny-prod")
+	require.NoError(t, err)
+	assert.True(t, policy.CreatedAt.Equal(restoredPolicy.CreatedAt))
+
+	// Secrets survive the round trip and restored state is persisted
+	reloaded := runtimeconfig.NewStore(dir)
+	require.Len(t, reloaded.WebhookTargets(), 1)
+	assert.Equal(t, "s3cret", reloaded.WebhookTargets()[0].Secret)
+	targets := targetTenants.WebhookTargets("payments")
+	require.Len(t, targets, 1)
+	assert.Equal(t, "t3nant", targets[0].Secret)
+	assert.Len(t, tenant.NewRegistry(dir, nil).List(), 1)
+
+	// Merging keeps local objects absent from the archive
+	_, err = targetConfig.Create(runtimeconfig.KindPolicy, "allow-all", json.RawMessage(`{"action":"allow"}`))
+	require.NoError(t, err)
+	_, err = target.Restore(bytes.NewReader(archive.Bytes()), false)
+	require.NoError(t, err)
+	assert.Equal(t, 2, targetConfig.Counts()[runtimeconfig.KindPolicy])
+}
+
+func TestManager_RestoreInvalidArchive(t *testing.T) {
+	target := newTestEngine(t)
+	target.addIncident(t, "Existing incident")
+
+	_, err := target.manager.Restore(bytes.NewReader([]byte("not an archive")), true)
+	assert.ErrorIs(t, err, ErrInvalidArchive)
+
+	// An archive without a manifest is rejected before anything is restored
+	var archive bytes.Buffer
+	gz := gzip.NewWriter(&archive)
+	tw := tar.NewWriter(gz)
+	require.NoError(t, writeEntry(tw, "incidents.json", []byte("[]"), time.Now()))
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+
+	_, err = target.manager.Restore(&archive, true)
+	assert.ErrorIs(t, err, ErrInvalidArchive)
+	assert.Equal(t, 1, target.incidents.Count())
+
+	// Sections missing from the archive are left unchanged
+	empty := NewManager("v1.2.3", time.Second, logrus.New())
+	archive.Reset()
+	_, err = empty.Write(&archive)
+	require.NoError(t, err)
+
+	result, err := target.manager.Restore(&archive, true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{SectionIncidents, SectionPredictions}, result.Missing)
+	assert.Equal(t, 1, target.incidents.Count())
+}
+
+// fixedShape returns a ShapeFunc of a fixed shape
+func fixedShape(shape ClusterShape) ShapeFunc {
+	return func(context.Context) (*ClusterShape, error) {
+		return &shape, nil
+	}
+}
+
+func TestManager_Import(t *testing.T) {
+	source := newTestEngine(t)
+	sourceOutcomes := storage.NewActionOutcomeStore(source.dir, 0)
+	source.manager.Register(NewActionOutcomeSection(sourceOutcomes))
+	source.manager.SetClusterShape(fixedShape(ClusterShape{Nodes: 6, CPUCores: 48, MemoryBytes: 192 << 30}))
+	_, err := sourceOutcomes.Add(&models.ActionOutcome{IssueType: "pod_crash_loop", Action: "restart_pod", Success: true})
+	require.NoError(t, err)
+
+	var archive bytes.Buffer
+	manifest, err := source.manager.Write(&archive)
+	require.NoError(t, err)
+	require.NotNil(t, manifest.ClusterShape)
+	assert.Equal(t, 6, manifest.ClusterShape.Nodes)
+
+	// A cluster of similar shape imports the archive, merging with its own state
+	target := newTestEngine(t)
+	targetOutcomes := storage.NewActionOutcomeStore(target.dir, 0)
+	target.manager.Register(NewActionOutcomeSection(targetOutcomes))
+	_, err = targetOutcomes.Add(&models.ActionOutcome{IssueType: "oom_kill", Action: "increase_memory_limit"})
+	require.NoError(t, err)
+	target.manager.SetClusterShape(fixedShape(ClusterShape{Nodes: 8, CPUCores: 64, MemoryBytes: 256 << 30}))
+
+	result, err := target.manager.Import(bytes.NewReader(archive.Bytes()), false)
+	require.NoError(t, err)
+	assert.Empty(t, result.ShapeMismatch)
+	assert.False(t, result.Replace)
+	assert.Equal(t, 2, targetOutcomes.Count())
+	assert.Equal(t, storage.ActionStats{Attempts: 1, Successes: 1}, targetOutcomes.Stats("pod_crash_loop")["restart_pod"])
+
+	// A much larger cluster rejects it unless forced
+	large := newTestEngine(t)
+	large.manager.SetClusterShape(fixedShape(ClusterShape{Nodes: 60, CPUCores: 480, MemoryBytes: 1920 << 30}))
+	_, err = large.manager.Import(bytes.NewReader(archive.Bytes()), false)
+	require.ErrorIs(t, err, ErrShapeMismatch)
+	assert.Contains(t, err.Error(), "archive was written on a cluster with 6 nodes, 48 cores, 192 GiB memory, this cluster has 60 nodes")
+
+	result, err = large.manager.Import(bytes.NewReader(archive.Bytes()), true)
+	require.NoError(t, err)
+	assert.NotEmpty(t, result.ShapeMismatch)
+	assert.Contains(t, result.Skipped, SectionActionOutcomes)
+}
+
+func TestClusterShape_Similar(t *testing.T) {
+	shape := &ClusterShape{Nodes: 6, CPUCores: 48, MemoryBytes: 192 << 30}
+	assert.True(t, shape.Similar(&ClusterShape{Nodes: 3, CPUCores: 96, MemoryBytes: 100 << 30}))
+	assert.False(t, shape.Similar(&ClusterShape{Nodes: 13, CPUCores: 48, MemoryBytes: 192 << 30}))
+	assert.False(t, shape.Similar(&ClusterShape{Nodes: 6, CPUCores: 48, MemoryBytes: 64 << 30}))
+	assert.True(t, shape.Similar(&ClusterShape{Nodes: 6}), "unknown sizes are not compared")
+}
+
+func TestManager_UploadAndDownload(t *testing.T) {
+	var stored []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPut:
+			assert.Equal(t, "application/gzip", r.Header.Get("Content-Type"))
+			stored, _ = io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusOK)
+		case http.MethodGet:
+			if stored == nil {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write(stored)
+		}
+	}))
+	defer server.Close()
+	ctx := context.Background()
+
+	source := newTestEngine(t)
+	source.addIncident(t, "API pods restarting")
+	target := newTestEngine(t)
+
+	_, err := target.manager.Download(ctx, server.URL+"/backup.tar.gz", false)
+	assert.Error(t, err, "missing object")
+
+	manifest, err := source.manager.Upload(ctx, server.URL+"/backup.tar.gz?X-Amz-Signature=abc")
+	require.NoError(t, err)
+	assert.Equal(t, 1, manifest.Sections[0].Items)
+
+	result, err := target.manager.Download(ctx, server.URL+"/backup.tar.gz", false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Restored[0].Items)
+	assert.Equal(t, 1, target.incidents.Count())
+
+	_, err = source.manager.Upload(ctx, "file:///etc/passwd")
+	assert.Error(t, err)
+}
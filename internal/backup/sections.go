@@ -0,0 +1,222 @@
+package backup
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/runtimeconfig"
+	"github.com/tosin2013/openshift-coordination-engine/internal/storage"
+	"github.com/tosin2013/openshift-coordination-engine/internal/tenant"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// Section names
+const (
+	SectionIncidents      = "incidents"
+	SectionPredictions    = "predictions"
+	SectionActionOutcomes = "action_outcomes"
+	SectionAnomalies      = "anomalies"
+	SectionRuntimeConfig  = "runtime_config"
+	SectionTenants        = "tenants"
+)
+
+// IncidentSection backs up stored incidents
+type IncidentSection struct {
+	store *storage.IncidentStore
+}
+
+// NewIncidentSection creates the incidents section
+func NewIncidentSection(store *storage.IncidentStore) *IncidentSection {
+	return &IncidentSection{store: store}
+}
+
+// Name implements Section
+func (s *IncidentSection) Name() string {
+	return SectionIncidents
+}
+
+// Export implements Section
+func (s *IncidentSection) Export() (interface{}, int, error) {
+	incidents := s.store.List(storage.ListFilter{})
+	return incidents, len(incidents), nil
+}
+
+// Import implements Section
+func (s *IncidentSection) Import(data []byte, replace bool) (int, error) {
+	var incidents []*models.Incident
+	if err := json.Unmarshal(data, &incidents); err != nil {
+		return 0, fmt.Errorf("failed to parse incidents: %w", err)
+	}
+	if err := s.store.Restore(incidents, replace); err != nil {
+		return 0, err
+	}
+	return len(incidents), nil
+}
+
+// PredictionSection backs up stored predictions and their realized error, from which
+// accuracy and drift baselines are computed
+type PredictionSection struct {
+	store *storage.PredictionStore
+}
+
+// NewPredictionSection creates the predictions section
+func NewPredictionSection(store *storage.PredictionStore) *PredictionSection {
+	return &PredictionSection{store: store}
+}
+
+// Name implements Section
+func (s *PredictionSection) Name() string {
+	return SectionPredictions
+}
+
+// Export implements Section
+func (s *PredictionSection) Export() (interface{}, int, error) {
+	records := s.store.List(storage.PredictionFilter{})
+	return records, len(records), nil
+}
+
+// Import implements Section
+func (s *PredictionSection) Import(data []byte, replace bool) (int, error) {
+	var records []models.PredictionRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		return 0, fmt.Errorf("failed to parse predictions: %w", err)
+	}
+	if err := s.store.Restore(records, replace); err != nil {
+		return 0, err
+	}
+	return len(records), nil
+}
+
+// ActionOutcomeSection backs up the verified outcomes of remediation actions, from
+// which recommended actions are ranked
+type ActionOutcomeSection struct {
+	store *storage.ActionOutcomeStore
+}
+
+// NewActionOutcomeSection creates the action outcomes section
+func NewActionOutcomeSection(store *storage.ActionOutcomeStore) *ActionOutcomeSection {
+	return &ActionOutcomeSection{store: store}
+}
+
+// Name implements Section
+func (s *ActionOutcomeSection) Name() string {
+	return SectionActionOutcomes
+}
+
+// Export implements Section
+func (s *ActionOutcomeSection) Export() (interface{}, int, error) {
+	outcomes := s.store.List()
+	return outcomes, len(outcomes), nil
+}
+
+// Import implements Section
+func (s *ActionOutcomeSection) Import(data []byte, replace bool) (int, error) {
+	var outcomes []models.ActionOutcome
+	if err := json.Unmarshal(data, &outcomes); err != nil {
+		return 0, fmt.Errorf("failed to parse action outcomes: %w", err)
+	}
+	if err := s.store.Restore(outcomes, replace); err != nil {
+		return 0, err
+	}
+	return len(outcomes), nil
+}
+
+// AnomalySection backs up the anomalies detected by past analyses
+type AnomalySection struct {
+	store *storage.AnomalyStore
+}
+
+// NewAnomalySection creates the anomalies section
+func NewAnomalySection(store *storage.AnomalyStore) *AnomalySection {
+	return &AnomalySection{store: store}
+}
+
+// Name implements Section
+func (s *AnomalySection) Name() string {
+	return SectionAnomalies
+}
+
+// Export implements Section
+func (s *AnomalySection) Export() (interface{}, int, error) {
+	records := s.store.List(storage.AnomalyFilter{})
+	return records, len(records), nil
+}
+
+// Import implements Section
+func (s *AnomalySection) Import(data []byte, replace bool) (int, error) {
+	var records []models.AnomalyRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		return 0, fmt.Errorf("failed to parse anomalies: %w", err)
+	}
+	if err := s.store.Restore(records, replace); err != nil {
+		return 0, err
+	}
+	return len(records), nil
+}
+
+// RuntimeConfigSection backs up the runtime configuration objects: policies, silences,
+// scan schedules, notification channels, playbooks and escalation ladders
+type RuntimeConfigSection struct {
+	store *runtimeconfig.Store
+}
+
+// NewRuntimeConfigSection creates the runtime configuration section
+func NewRuntimeConfigSection(store *runtimeconfig.Store) *RuntimeConfigSection {
+	return &RuntimeConfigSection{store: store}
+}
+
+// Name implements Section
+func (s *RuntimeConfigSection) Name() string {
+	return SectionRuntimeConfig
+}
+
+// Export implements Section
+func (s *RuntimeConfigSection) Export() (interface{}, int, error) {
+	objects := s.store.Export()
+	return objects, len(objects), nil
+}
+
+// Import implements Section
+func (s *RuntimeConfigSection) Import(data []byte, replace bool) (int, error) {
+	var objects []*runtimeconfig.Object
+	if err := json.Unmarshal(data, &objects); err != nil {
+		return 0, fmt.Errorf("failed to parse runtime configuration: %w", err)
+	}
+	if err := s.store.Restore(objects, replace); err != nil {
+		return 0, err
+	}
+	return len(objects), nil
+}
+
+// TenantSection backs up the onboarded tenants
+type TenantSection struct {
+	registry *tenant.Registry
+}
+
+// NewTenantSection creates the tenants section
+func NewTenantSection(registry *tenant.Registry) *TenantSection {
+	return &TenantSection{registry: registry}
+}
+
+// Name implements Section
+func (s *TenantSection) Name() string {
+	return SectionTenants
+}
+
+// Export implements Section
+func (s *TenantSection) Export() (interface{}, int, error) {
+	tenants := s.registry.Export()
+	return tenants, len(tenants), nil
+}
+
+// Import implements Section
+func (s *TenantSection) Import(data []byte, replace bool) (int, error) {
+	var tenants []*tenant.Tenant
+	if err := json.Unmarshal(data, &tenants); err != nil {
+		return 0, fmt.Errorf("failed to parse tenants: %w", err)
+	}
+	if err := s.registry.Restore(tenants, replace); err != nil {
+		return 0, err
+	}
+	return len(tenants), nil
+}
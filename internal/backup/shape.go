@@ -0,0 +1,48 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// MaxShapeRatio is how many times larger or smaller than this cluster the cluster an
+// archive was written on may be, in nodes, CPU or memory, for Import to accept it
+const MaxShapeRatio = 2.0
+
+// ErrShapeMismatch is returned when an archive was written on a cluster of a very
+// different shape
+var ErrShapeMismatch = errors.New("cluster shape mismatch")
+
+// ClusterShape is the size of the cluster an archive was written on
+type ClusterShape struct {
+	Nodes       int     `json:"nodes"`
+	CPUCores    float64 `json:"cpu_cores"`
+	MemoryBytes int64   `json:"memory_bytes"`
+}
+
+// ShapeFunc returns the shape of the cluster the engine runs on
+type ShapeFunc func(ctx context.Context) (*ClusterShape, error)
+
+// String describes the shape, e.g. "6 nodes, 48 cores, 192 GiB memory"
+func (s *ClusterShape) String() string {
+	return fmt.Sprintf("%d nodes, %.0f cores, %.0f GiB memory", s.Nodes, s.CPUCores, float64(s.MemoryBytes)/(1<<30))
+}
+
+// Similar reports whether two clusters are within MaxShapeRatio of each other in
+// nodes, CPU and memory
+func (s *ClusterShape) Similar(other *ClusterShape) bool {
+	return withinRatio(float64(s.Nodes), float64(other.Nodes)) &&
+		withinRatio(s.CPUCores, other.CPUCores) &&
+		withinRatio(float64(s.MemoryBytes), float64(other.MemoryBytes))
+}
+
+// withinRatio reports whether a and b are within MaxShapeRatio of each other. Unknown
+// (zero) sizes are not compared.
+func withinRatio(a, b float64) bool {
+	if a <= 0 || b <= 0 {
+		return true
+	}
+	return math.Max(a, b)/math.Min(a, b) <= MaxShapeRatio
+}
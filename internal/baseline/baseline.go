@@ -0,0 +1,252 @@
+// Package baseline carries learned baselines between clusters.
+//
+// A freshly installed engine has no history to learn baselines from: Prometheus needs
+// days of data before API server request rates can be compared with their baseline.
+// Baselines exported from an established cluster of similar shape are imported into
+// the new one and used until its own Prometheus has learned them.
+package baseline
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/backup"
+	"github.com/tosin2013/openshift-coordination-engine/internal/integrations"
+)
+
+// SectionAPIServerVerbs is the archive section of API server request rate baselines
+const SectionAPIServerVerbs = "apiserver_verb_baselines"
+
+// exportTimeout bounds the Prometheus queries of an export
+const exportTimeout = 30 * time.Second
+
+// Store persists the API server verb baselines imported from another cluster
+type Store struct {
+	verbs    map[string]integrations.APIServerVerbBaseline
+	mu       sync.RWMutex
+	dataFile string
+}
+
+// NewStore creates a baseline store in dataDir (DATA_DIR or /app/data if empty)
+func NewStore(dataDir string) *Store {
+	if dataDir == "" {
+		dataDir = os.Getenv("DATA_DIR")
+	}
+	if dataDir == "" {
+		dataDir = "/app/data"
+	}
+
+	if err := os.MkdirAll(dataDir, 0o755); err != nil {
+		fmt.Printf("Warning: Could not create data directory %s: %v\n", dataDir, err)
+	}
+	store := &Store{
+		verbs:    make(map[string]integrations.APIServerVerbBaseline),
+		dataFile: filepath.Join(dataDir, "baselines.json"),
+	}
+
+	if err := store.load(); err != nil {
+		fmt.Printf("Warning: Could not load imported baselines from disk: %v\n", err)
+	}
+	return store
+}
+
+// load reads imported baselines from the JSON file
+func (s *Store) load() error {
+	data, err := os.ReadFile(s.dataFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read data file: %w", err)
+	}
+
+	var verbs []integrations.APIServerVerbBaseline
+	if err := json.Unmarshal(data, &verbs); err != nil {
+		return fmt.Errorf("failed to unmarshal baselines: %w", err)
+	}
+	for _, verb := range verbs {
+		s.verbs[verb.Verb] = verb
+	}
+	return nil
+}
+
+// save writes the imported baselines to the JSON file. Callers must hold s.mu.
+func (s *Store) save() error {
+	data, err := json.Marshal(s.list())
+	if err != nil {
+		return fmt.Errorf("failed to marshal baselines: %w", err)
+	}
+
+	// Write to temp file first, then rename (atomic)
+	tmpFile := s.dataFile + ".tmp"
+	if err := os.WriteFile(tmpFile, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write temp file: %w", err)
+	}
+	if err := os.Rename(tmpFile, s.dataFile); err != nil {
+		return fmt.Errorf("failed to rename temp file: %w", err)
+	}
+	return nil
+}
+
+// APIServerVerbBaseline implements integrations.BaselineFallback
+func (s *Store) APIServerVerbBaseline(verb string) (integrations.APIServerVerbBaseline, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	baseline, ok := s.verbs[verb]
+	return baseline, ok
+}
+
+// List returns the imported baselines sorted by verb
+func (s *Store) List() []integrations.APIServerVerbBaseline {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.list()
+}
+
+// list returns the imported baselines sorted by verb. Callers must hold s.mu.
+func (s *Store) list() []integrations.APIServerVerbBaseline {
+	verbs := make([]integrations.APIServerVerbBaseline, 0, len(s.verbs))
+	for _, verb := range s.verbs {
+		verbs = append(verbs, verb)
+	}
+	sort.Slice(verbs, func(i, j int) bool { return verbs[i].Verb < verbs[j].Verb })
+	return verbs
+}
+
+// Restore imports baselines. Without replace they are merged with the stored ones,
+// an imported baseline overwriting the stored one of its verb.
+func (s *Store) Restore(verbs []integrations.APIServerVerbBaseline, replace bool) error {
+	for _, verb := range verbs {
+		if verb.Verb == "" {
+			return fmt.Errorf("baseline without verb in archive")
+		}
+		if verb.MeanQPS < 0 || verb.StdDevQPS < 0 {
+			return fmt.Errorf("baseline of %s must not be negative", verb.Verb)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previous := s.verbs
+	restored := make(map[string]integrations.APIServerVerbBaseline, len(previous)+len(verbs))
+	if !replace {
+		for name, verb := range previous {
+			restored[name] = verb
+		}
+	}
+	for _, verb := range verbs {
+		restored[verb.Verb] = verb
+	}
+
+	s.verbs = restored
+	if err := s.save(); err != nil {
+		s.verbs = previous
+		return fmt.Errorf("failed to persist imported baselines: %w", err)
+	}
+	return nil
+}
+
+// Learner returns the baselines learned on this cluster
+type Learner interface {
+	APIServerVerbBaselines(ctx context.Context) ([]integrations.APIServerVerbBaseline, error)
+}
+
+// APIServerSection exports the API server verb baselines learned on this cluster,
+// completed with imported ones for verbs without a learned baseline, and imports
+// baselines into the store
+type APIServerSection struct {
+	learner Learner
+	store   *Store
+	log     *logrus.Logger
+}
+
+// NewAPIServerSection creates the API server baselines section. learner may be nil
+// when Prometheus is not configured, in which case only imported baselines are exported.
+func NewAPIServerSection(learner Learner, store *Store, log *logrus.Logger) *APIServerSection {
+	return &APIServerSection{learner: learner, store: store, log: log}
+}
+
+// Name implements backup.Section
+func (s *APIServerSection) Name() string {
+	return SectionAPIServerVerbs
+}
+
+// Export implements backup.Section
+func (s *APIServerSection) Export() (interface{}, int, error) {
+	verbs := make(map[string]integrations.APIServerVerbBaseline)
+	for _, verb := range s.store.List() {
+		verbs[verb.Verb] = verb
+	}
+	if s.learner != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), exportTimeout)
+		defer cancel()
+		learned, err := s.learner.APIServerVerbBaselines(ctx)
+		if err != nil {
+			s.log.WithError(err).Warn("Failed to read learned API server baselines; exporting imported ones only")
+		}
+		for _, verb := range learned {
+			verbs[verb.Verb] = verb
+		}
+	}
+
+	baselines := make([]integrations.APIServerVerbBaseline, 0, len(verbs))
+	for _, verb := range verbs {
+		baselines = append(baselines, verb)
+	}
+	sort.Slice(baselines, func(i, j int) bool { return baselines[i].Verb < baselines[j].Verb })
+	return baselines, len(baselines), nil
+}
+
+// Import implements backup.Section
+func (s *APIServerSection) Import(data []byte, replace bool) (int, error) {
+	var verbs []integrations.APIServerVerbBaseline
+	if err := json.Unmarshal(data, &verbs); err != nil {
+		return 0, fmt.Errorf("failed to parse API server baselines: %w", err)
+	}
+	if err := s.store.Restore(verbs, replace); err != nil {
+		return 0, err
+	}
+	return len(verbs), nil
+}
+
+// ClusterShape returns a backup.ShapeFunc reading the number of worker nodes and their
+// allocatable CPU and memory
+func ClusterShape(client kubernetes.Interface) backup.ShapeFunc {
+	return func(ctx context.Context) (*backup.ClusterShape, error) {
+		nodes, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list nodes: %w", err)
+		}
+
+		shape := &backup.ClusterShape{}
+		for i := range nodes.Items {
+			node := &nodes.Items[i]
+			if _, isMaster := node.Labels["node-role.kubernetes.io/master"]; isMaster {
+				continue
+			}
+			if _, isControlPlane := node.Labels["node-role.kubernetes.io/control-plane"]; isControlPlane {
+				continue
+			}
+			shape.Nodes++
+			if cpu, ok := node.Status.Allocatable[corev1.ResourceCPU]; ok {
+				shape.CPUCores += cpu.AsApproximateFloat64()
+			}
+			if memory, ok := node.Status.Allocatable[corev1.ResourceMemory]; ok {
+				shape.MemoryBytes += memory.Value()
+			}
+		}
+		return shape, nil
+	}
+}
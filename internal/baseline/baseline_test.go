@@ -0,0 +1,120 @@
+package baseline
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/integrations"
+)
+
+// fakeLearner returns fixed learned baselines
+type fakeLearner struct {
+	baselines []integrations.APIServerVerbBaseline
+	err       error
+}
+
+func (f *fakeLearner) APIServerVerbBaselines(context.Context) ([]integrations.APIServerVerbBaseline, error) {
+	return f.baselines, f.err
+}
+
+func TestStore_Restore(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(dir)
+	_, ok := store.APIServerVerbBaseline("LIST")
+	assert.False(t, ok)
+
+	require.NoError(t, store.Restore([]integrations.APIServerVerbBaseline{
+		{Verb: "LIST", MeanQPS: 40, StdDevQPS: 5},
+		{Verb: "GET", MeanQPS: 100, StdDevQPS: 50},
+	}, false))
+	require.NoError(t, store.Restore([]integrations.APIServerVerbBaseline{{Verb: "LIST", MeanQPS: 42}}, false))
+
+	list, ok := store.APIServerVerbBaseline("LIST")
+	require.True(t, ok)
+	assert.Equal(t, 42.0, list.MeanQPS, "imported baselines overwrite stored ones")
+
+	// Imported baselines are persisted
+	reloaded := NewStore(dir)
+	assert.Equal(t, store.List(), reloaded.List())
+	assert.Len(t, reloaded.List(), 2)
+
+	require.NoError(t, store.Restore([]integrations.APIServerVerbBaseline{{Verb: "WATCH", MeanQPS: 10}}, true))
+	assert.Len(t, store.List(), 1, "replace drops stored baselines")
+
+	assert.Error(t, store.Restore([]integrations.APIServerVerbBaseline{{MeanQPS: 1}}, false))
+	assert.Error(t, store.Restore([]integrations.APIServerVerbBaseline{{Verb: "GET", MeanQPS: -1}}, false))
+	assert.Len(t, store.List(), 1, "invalid baselines are rejected")
+}
+
+func TestAPIServerSection(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	store := NewStore(t.TempDir())
+	require.NoError(t, store.Restore([]integrations.APIServerVerbBaseline{
+		{Verb: "CREATE", MeanQPS: 2},
+		{Verb: "LIST", MeanQPS: 1},
+	}, false))
+	learner := &fakeLearner{baselines: []integrations.APIServerVerbBaseline{{Verb: "LIST", MeanQPS: 40, StdDevQPS: 5}}}
+	section := NewAPIServerSection(learner, store, log)
+	assert.Equal(t, SectionAPIServerVerbs, section.Name())
+
+	// Learned baselines are exported, completed with imported ones
+	items, count, err := section.Export()
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, []integrations.APIServerVerbBaseline{
+		{Verb: "CREATE", MeanQPS: 2},
+		{Verb: "LIST", MeanQPS: 40, StdDevQPS: 5},
+	}, items)
+
+	// Without Prometheus only imported baselines are exported
+	learner.err = errors.New("prometheus unavailable")
+	learner.baselines = nil
+	_, count, err = section.Export()
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	data, err := json.Marshal(items)
+	require.NoError(t, err)
+	target := NewStore(t.TempDir())
+	count, err = NewAPIServerSection(nil, target, log).Import(data, false)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	list, ok := target.APIServerVerbBaseline("LIST")
+	require.True(t, ok)
+	assert.Equal(t, 40.0, list.MeanQPS)
+}
+
+func TestClusterShape(t *testing.T) {
+	node := func(name string, labels map[string]string, cpu, memory string) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+			Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(cpu),
+				corev1.ResourceMemory: resource.MustParse(memory),
+			}},
+		}
+	}
+	client := fake.NewSimpleClientset(
+		node("master-0", map[string]string{"node-role.kubernetes.io/control-plane": ""}, "8", "32Gi"),
+		node("worker-0", nil, "16", "64Gi"),
+		node("worker-1", nil, "15500m", "64Gi"),
+	)
+
+	shape, err := ClusterShape(client)(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, shape.Nodes, "control-plane nodes are not counted")
+	assert.InDelta(t, 31.5, shape.CPUCores, 1e-9)
+	assert.Equal(t, int64(128<<30), shape.MemoryBytes)
+}
@@ -0,0 +1,138 @@
+package bootstrap
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Apply result statuses
+const (
+	StatusLabeled   = "labeled"
+	StatusUnchanged = "unchanged"
+	StatusCreated   = "created"
+	StatusExists    = "exists"
+	StatusSkipped   = "skipped"
+	StatusFailed    = "failed"
+)
+
+// ApplyOptions select what Apply changes
+type ApplyOptions struct {
+	// EngineURL and AdminToken create the plan's runtime configuration objects through
+	// the engine's admin API; without EngineURL they are skipped
+	EngineURL  string
+	AdminToken string
+
+	// HTTPClient defaults to http.DefaultClient
+	HTTPClient *http.Client
+}
+
+// ApplyResult reports the change made for one namespace or object
+type ApplyResult struct {
+	// Target is "namespaces/<name>" or "<kind>/<name>"
+	Target string `json:"target"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Apply labels the plan's namespaces with their tiers and creates its runtime
+// configuration objects. Existing tier labels and objects are left unchanged, so
+// Apply can be run again after editing the plan. The scope defaults and calendars
+// are files the engine reads at startup and are not applied.
+func Apply(ctx context.Context, client kubernetes.Interface, plan *Plan, opts ApplyOptions) []ApplyResult {
+	results := make([]ApplyResult, 0, len(plan.Namespaces)+len(plan.Config))
+	for i := range plan.Namespaces {
+		results = append(results, labelNamespace(ctx, client, plan.TierLabel, &plan.Namespaces[i]))
+	}
+	for _, obj := range plan.Config {
+		results = append(results, createObject(ctx, obj, opts))
+	}
+	return results
+}
+
+// labelNamespace sets a namespace's tier label unless it already has one
+func labelNamespace(ctx context.Context, client kubernetes.Interface, label string, profile *NamespaceProfile) ApplyResult {
+	result := ApplyResult{Target: "namespaces/" + profile.Name}
+	if profile.Source == SourceLabel {
+		result.Status = StatusUnchanged
+		return result
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": map[string]string{label: profile.Tier},
+		},
+	})
+	if err != nil {
+		result.Status, result.Error = StatusFailed, err.Error()
+		return result
+	}
+	if _, err := client.CoreV1().Namespaces().Patch(ctx, profile.Name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		result.Status, result.Error = StatusFailed, err.Error()
+		return result
+	}
+	result.Status = StatusLabeled
+	return result
+}
+
+// createObject creates a runtime configuration object through the admin API
+func createObject(ctx context.Context, obj ConfigObject, opts ApplyOptions) ApplyResult {
+	result := ApplyResult{Target: string(obj.Kind) + "/" + obj.Name}
+	if opts.EngineURL == "" {
+		result.Status, result.Error = StatusSkipped, "no engine URL"
+		return result
+	}
+
+	body, err := json.Marshal(map[string]interface{}{"name": obj.Name, "spec": obj.Spec})
+	if err != nil {
+		result.Status, result.Error = StatusFailed, err.Error()
+		return result
+	}
+	url := strings.TrimSuffix(opts.EngineURL, "/") + "/api/v1/admin/config/" + string(obj.Kind)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		result.Status, result.Error = StatusFailed, err.Error()
+		return result
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+opts.AdminToken)
+
+	client := opts.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		result.Status, result.Error = StatusFailed, err.Error()
+		return result
+	}
+	defer resp.Body.Close()
+
+	var response struct {
+		Error string `json:"error"`
+	}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	_ = json.Unmarshal(data, &response)
+
+	switch {
+	case resp.StatusCode == http.StatusCreated:
+		result.Status = StatusCreated
+	case resp.StatusCode == http.StatusConflict && strings.Contains(response.Error, "already exists"):
+		result.Status = StatusExists
+	default:
+		result.Status = StatusFailed
+		result.Error = fmt.Sprintf("admin API responded %d", resp.StatusCode)
+		if response.Error != "" {
+			result.Error += ": " + response.Error
+		}
+	}
+	return result
+}
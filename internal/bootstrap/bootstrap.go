@@ -0,0 +1,486 @@
+// Package bootstrap generates an initial engine configuration from an inspection of
+// the cluster, so new installs start from tiers, thresholds and quiet hours that fit
+// their namespaces instead of hand-written files.
+//
+// Generate only reads the cluster: namespaces, the size of their workloads and their
+// existing alerting rules. The plan it returns is meant to be reviewed; Apply labels
+// the namespaces with their tiers and creates the plan's runtime configuration
+// objects through the admin API.
+package bootstrap
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/anomaly"
+	"github.com/tosin2013/openshift-coordination-engine/internal/runtimeconfig"
+	"github.com/tosin2013/openshift-coordination-engine/internal/tenant"
+)
+
+// Tier heuristics
+const (
+	// largeReplicas and largeCPUMillicores mark namespaces whose workloads are big
+	// enough to be treated as high tier
+	largeReplicas      = 10
+	largeCPUMillicores = 8000
+
+	// quietHoursIncrease raises the threshold of low and medium tier namespaces at
+	// night and on weekends
+	quietHoursIncrease = 0.1
+)
+
+// Tier sources, reported with each namespace
+const (
+	SourceLabel     = "label"
+	SourceName      = "name"
+	SourceWorkloads = "workloads"
+	SourceAlerts    = "alerts"
+	SourceDefault   = "default"
+)
+
+// prometheusRuleGVR identifies the Prometheus Operator's PrometheusRule resources
+var prometheusRuleGVR = schema.GroupVersionResource{
+	Group:    "monitoring.coreos.com",
+	Version:  "v1",
+	Resource: "prometheusrules",
+}
+
+// tiers lists the tiers from most to least critical, with the anomaly threshold and
+// scan interval generated for each. More critical namespaces get a lower threshold, so
+// fewer anomalies are missed, and are scanned more often; medium and low tier
+// namespaces are left to on-demand analysis.
+var tiers = []struct {
+	name         string
+	threshold    float64
+	scanInterval string
+}{
+	{tenant.CriticalityCritical, 0.6, "5m"},
+	{tenant.CriticalityHigh, 0.65, "15m"},
+	{tenant.CriticalityMedium, 0.7, ""},
+	{tenant.CriticalityLow, 0.8, ""},
+}
+
+// nameHints map words of a namespace name, split on '-', to the tier they suggest.
+// Low and medium words are checked first, so "payments-prod-test" is low.
+var nameHints = []struct {
+	tier  string
+	words []string
+}{
+	{tenant.CriticalityLow, []string{"dev", "development", "test", "testing", "sandbox", "demo", "tmp", "scratch", "ci", "playground", "ephemeral"}},
+	{tenant.CriticalityMedium, []string{"stage", "staging", "uat", "qa", "preprod", "perf"}},
+	{tenant.CriticalityCritical, []string{"prod", "production", "prd", "live"}},
+}
+
+// Options tune the generated configuration
+type Options struct {
+	// TierLabel is the namespace label holding tiers (default "criticality")
+	TierLabel string
+
+	// Timezone is the IANA zone of the generated quiet hours (default UTC)
+	Timezone string
+
+	// IncludeSystem includes the default, kube-* and openshift-* namespaces, which
+	// are skipped by default as they belong to the platform
+	IncludeSystem bool
+}
+
+// NamespaceProfile is what the inspection found about a namespace and the tier
+// chosen for it
+type NamespaceProfile struct {
+	Name string `json:"name"`
+	Tier string `json:"tier"`
+
+	// Source is what decided the tier (label, name, workloads, alerts or default)
+	// and Reason explains it
+	Source string `json:"source"`
+	Reason string `json:"reason"`
+
+	Workloads             int   `json:"workloads"`
+	Replicas              int32 `json:"replicas"`
+	CPURequestsMillicores int64 `json:"cpu_requests_millicores"`
+	MemoryRequestsBytes   int64 `json:"memory_requests_bytes"`
+	AlertRules            int   `json:"alert_rules"`
+}
+
+// ScopeDefaultsFile is the content of ANOMALY_SCOPE_DEFAULTS_FILE
+type ScopeDefaultsFile struct {
+	TierLabel string                     `json:"tier_label,omitempty"`
+	Rules     []anomaly.ScopeDefaultRule `json:"rules"`
+}
+
+// CalendarsFile is the content of ANOMALY_CALENDARS_FILE
+type CalendarsFile struct {
+	Calendars []anomaly.BusinessCalendar `json:"calendars"`
+}
+
+// ConfigObject is a runtime configuration object created through the admin API
+type ConfigObject struct {
+	Kind runtimeconfig.Kind `json:"kind"`
+	Name string             `json:"name"`
+	Spec runtimeconfig.Spec `json:"spec"`
+}
+
+// UnmarshalJSON decodes the spec of the object's kind, so edited plans can be loaded
+func (o *ConfigObject) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Kind runtimeconfig.Kind `json:"kind"`
+		Name string             `json:"name"`
+		Spec json.RawMessage    `json:"spec"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if raw.Kind != runtimeconfig.KindScanSchedule {
+		return fmt.Errorf("config object %s: unsupported kind %q", raw.Name, raw.Kind)
+	}
+	spec := &runtimeconfig.ScanSchedule{}
+	if err := json.Unmarshal(raw.Spec, spec); err != nil {
+		return fmt.Errorf("config object %s: %w", raw.Name, err)
+	}
+	o.Kind, o.Name, o.Spec = raw.Kind, raw.Name, spec
+	return nil
+}
+
+// Plan is a generated initial configuration
+type Plan struct {
+	GeneratedAt time.Time `json:"generated_at"`
+	TierLabel   string    `json:"tier_label"`
+
+	Namespaces    []NamespaceProfile `json:"namespaces"`
+	ScopeDefaults ScopeDefaultsFile  `json:"scope_defaults"`
+	Calendars     CalendarsFile      `json:"calendars"`
+	Config        []ConfigObject     `json:"config"`
+
+	// Warnings describe what could not be inspected
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// Generator inspects a cluster and generates plans
+type Generator struct {
+	client  kubernetes.Interface
+	dynamic dynamic.Interface
+	opts    Options
+
+	// now is replaceable in tests
+	now func() time.Time
+}
+
+// NewGenerator creates a generator. dynamicClient may be nil, in which case existing
+// alerting rules are not inspected.
+func NewGenerator(client kubernetes.Interface, dynamicClient dynamic.Interface, opts Options) *Generator {
+	if opts.TierLabel == "" {
+		opts.TierLabel = anomaly.DefaultTierLabel
+	}
+	if opts.Timezone == "" {
+		opts.Timezone = "UTC"
+	}
+	return &Generator{client: client, dynamic: dynamicClient, opts: opts, now: time.Now}
+}
+
+// Generate inspects the cluster and returns the generated configuration. It does not
+// change the cluster.
+func (g *Generator) Generate(ctx context.Context) (*Plan, error) {
+	if _, err := time.LoadLocation(g.opts.Timezone); err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %w", g.opts.Timezone, err)
+	}
+
+	namespaces, err := g.client.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list namespaces: %w", err)
+	}
+	plan := &Plan{GeneratedAt: g.now().UTC(), TierLabel: g.opts.TierLabel}
+
+	profiles := make(map[string]*NamespaceProfile)
+	labels := make(map[string]map[string]string)
+	for i := range namespaces.Items {
+		ns := &namespaces.Items[i]
+		if !g.opts.IncludeSystem && isSystemNamespace(ns.Name) {
+			continue
+		}
+		profiles[ns.Name] = &NamespaceProfile{Name: ns.Name}
+		labels[ns.Name] = ns.Labels
+	}
+	if err := g.inspectWorkloads(ctx, profiles); err != nil {
+		return nil, err
+	}
+	if warning := g.inspectAlerts(ctx, profiles); warning != "" {
+		plan.Warnings = append(plan.Warnings, warning)
+	}
+
+	for _, profile := range profiles {
+		profile.Tier, profile.Source, profile.Reason = g.tier(profile, labels[profile.Name])
+		plan.Namespaces = append(plan.Namespaces, *profile)
+	}
+	sort.Slice(plan.Namespaces, func(i, j int) bool {
+		return plan.Namespaces[i].Name < plan.Namespaces[j].Name
+	})
+
+	g.generateScopeDefaults(plan)
+	g.generateQuietHours(plan)
+	g.generateScanSchedules(plan)
+	if err := plan.validate(); err != nil {
+		return nil, fmt.Errorf("generated configuration is invalid: %w", err)
+	}
+	return plan, nil
+}
+
+// inspectWorkloads sums the replicas and resource requests of each namespace's
+// Deployments and StatefulSets
+func (g *Generator) inspectWorkloads(ctx context.Context, profiles map[string]*NamespaceProfile) error {
+	add := func(namespace string, replicas *int32, pod *corev1.PodSpec) {
+		profile, ok := profiles[namespace]
+		if !ok {
+			return
+		}
+		count := int32(1)
+		if replicas != nil {
+			count = *replicas
+		}
+		profile.Workloads++
+		profile.Replicas += count
+		for i := range pod.Containers {
+			requests := pod.Containers[i].Resources.Requests
+			profile.CPURequestsMillicores += requests.Cpu().MilliValue() * int64(count)
+			profile.MemoryRequestsBytes += requests.Memory().Value() * int64(count)
+		}
+	}
+
+	deployments, err := g.client.AppsV1().Deployments("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list deployments: %w", err)
+	}
+	for i := range deployments.Items {
+		deployment := &deployments.Items[i]
+		add(deployment.Namespace, deployment.Spec.Replicas, &deployment.Spec.Template.Spec)
+	}
+
+	statefulSets, err := g.client.AppsV1().StatefulSets("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list statefulsets: %w", err)
+	}
+	for i := range statefulSets.Items {
+		statefulSet := &statefulSets.Items[i]
+		add(statefulSet.Namespace, statefulSet.Spec.Replicas, &statefulSet.Spec.Template.Spec)
+	}
+	return nil
+}
+
+// inspectAlerts counts the alerting rules of each namespace's PrometheusRules. It
+// returns a warning if they cannot be listed, e.g. without the Prometheus Operator.
+func (g *Generator) inspectAlerts(ctx context.Context, profiles map[string]*NamespaceProfile) string {
+	if g.dynamic == nil {
+		return "alerting rules not inspected: no dynamic client"
+	}
+	rules, err := g.dynamic.Resource(prometheusRuleGVR).Namespace("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return "alerting rules not inspected: PrometheusRule resources are not available"
+		}
+		return fmt.Sprintf("alerting rules not inspected: %v", err)
+	}
+	for i := range rules.Items {
+		rule := &rules.Items[i]
+		if profile, ok := profiles[rule.GetNamespace()]; ok {
+			profile.AlertRules += countAlerts(rule)
+		}
+	}
+	return ""
+}
+
+// countAlerts counts the alerting rules of a PrometheusRule; recording rules are not
+// counted
+func countAlerts(rule *unstructured.Unstructured) int {
+	groups, _, _ := unstructured.NestedSlice(rule.Object, "spec", "groups")
+	count := 0
+	for _, group := range groups {
+		groupMap, ok := group.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		rules, _, _ := unstructured.NestedSlice(groupMap, "rules")
+		for _, r := range rules {
+			if ruleMap, ok := r.(map[string]interface{}); ok && ruleMap["alert"] != nil {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// tier chooses a namespace's tier. An existing tier label is kept; otherwise the
+// namespace name decides, then the absence of workloads, then existing alerting
+// rules or large workloads, which mark namespaces someone depends on.
+func (g *Generator) tier(profile *NamespaceProfile, labels map[string]string) (tier, source, reason string) {
+	if value := labels[g.opts.TierLabel]; isTier(value) {
+		return value, SourceLabel, fmt.Sprintf("labeled %s=%s", g.opts.TierLabel, value)
+	}
+
+	words := strings.Split(profile.Name, "-")
+	for _, hint := range nameHints {
+		for _, word := range words {
+			if contains(hint.words, word) {
+				return hint.tier, SourceName, fmt.Sprintf("name contains %q", word)
+			}
+		}
+	}
+
+	switch {
+	case profile.Workloads == 0:
+		return tenant.CriticalityLow, SourceWorkloads, "no deployments or statefulsets"
+	case profile.AlertRules > 0:
+		return tenant.CriticalityHigh, SourceAlerts, fmt.Sprintf("%d existing alerting rules", profile.AlertRules)
+	case profile.Replicas >= largeReplicas || profile.CPURequestsMillicores >= largeCPUMillicores:
+		return tenant.CriticalityHigh, SourceWorkloads,
+			fmt.Sprintf("%d replicas requesting %dm CPU", profile.Replicas, profile.CPURequestsMillicores)
+	}
+	return tenant.CriticalityMedium, SourceDefault, "no tier label, name hint, alerting rules or large workloads"
+}
+
+// generateScopeDefaults adds one threshold rule per tier, matched by the tier label
+func (g *Generator) generateScopeDefaults(plan *Plan) {
+	plan.ScopeDefaults.TierLabel = g.opts.TierLabel
+	for _, tier := range tiers {
+		plan.ScopeDefaults.Rules = append(plan.ScopeDefaults.Rules, anomaly.ScopeDefaultRule{
+			Name:      tier.name + "-tier",
+			Tiers:     []string{tier.name},
+			Threshold: tier.threshold,
+		})
+	}
+}
+
+// generateQuietHours adds a calendar raising the threshold and lowering the severity
+// of anomalies in low and medium tier namespaces at night and on weekends
+func (g *Generator) generateQuietHours(plan *Plan) {
+	namespaces := plan.namespacesIn(tenant.CriticalityMedium, tenant.CriticalityLow)
+	if len(namespaces) == 0 {
+		// A calendar without namespaces would apply to every namespace
+		plan.Calendars.Calendars = []anomaly.BusinessCalendar{}
+		return
+	}
+	plan.Calendars.Calendars = []anomaly.BusinessCalendar{{
+		Name:       "quiet-hours",
+		Namespaces: namespaces,
+		Timezone:   g.opts.Timezone,
+		Windows: []anomaly.BusinessWindow{
+			{Name: "nights", Days: []string{"mon", "tue", "wed", "thu", "fri"}, Start: "20:00", End: "07:00",
+				ThresholdIncrease: quietHoursIncrease, LowerSeverity: true},
+			{Name: "weekends", Days: []string{"sat", "sun"}, ThresholdIncrease: quietHoursIncrease, LowerSeverity: true},
+		},
+	}}
+}
+
+// generateScanSchedules adds scan schedules for the tiers that are scanned, split
+// into schedules of at most runtimeconfig.MaxScanNamespaces namespaces
+func (g *Generator) generateScanSchedules(plan *Plan) {
+	plan.Config = []ConfigObject{}
+	for _, tier := range tiers {
+		if tier.scanInterval == "" {
+			continue
+		}
+		namespaces := plan.namespacesIn(tier.name)
+		for part := 0; len(namespaces) > 0; part++ {
+			n := min(len(namespaces), runtimeconfig.MaxScanNamespaces)
+			name := "scan-" + tier.name
+			if part > 0 {
+				name = fmt.Sprintf("%s-%d", name, part+1)
+			}
+			plan.Config = append(plan.Config, ConfigObject{
+				Kind: runtimeconfig.KindScanSchedule,
+				Name: name,
+				Spec: &runtimeconfig.ScanSchedule{Namespaces: namespaces[:n], Interval: tier.scanInterval, Threshold: tier.threshold},
+			})
+			namespaces = namespaces[n:]
+		}
+	}
+}
+
+// LoadPlan reads a plan written by the bootstrap command, e.g. after review
+func LoadPlan(path string) (*Plan, error) {
+	data, err := os.ReadFile(path) //#nosec G304 -- path comes from the command line
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plan: %w", err)
+	}
+	var plan Plan
+	if err := yaml.UnmarshalStrict(data, &plan); err != nil {
+		return nil, fmt.Errorf("failed to parse plan %s: %w", path, err)
+	}
+	if plan.TierLabel == "" {
+		plan.TierLabel = anomaly.DefaultTierLabel
+	}
+	for _, profile := range plan.Namespaces {
+		if !isTier(profile.Tier) {
+			return nil, fmt.Errorf("plan %s: namespace %s has unknown tier %q", path, profile.Name, profile.Tier)
+		}
+	}
+	if err := plan.validate(); err != nil {
+		return nil, fmt.Errorf("plan %s is invalid: %w", path, err)
+	}
+	return &plan, nil
+}
+
+// namespacesIn returns the names of the plan's namespaces in the given tiers
+func (p *Plan) namespacesIn(tierNames ...string) []string {
+	var names []string
+	for i := range p.Namespaces {
+		if contains(tierNames, p.Namespaces[i].Tier) {
+			names = append(names, p.Namespaces[i].Name)
+		}
+	}
+	return names
+}
+
+// validate checks the generated files and objects the way the engine loads them
+func (p *Plan) validate() error {
+	if _, err := anomaly.NewScopeDefaults(p.ScopeDefaults.TierLabel, p.ScopeDefaults.Rules); err != nil {
+		return err
+	}
+	if _, err := anomaly.NewBusinessCalendars(p.Calendars.Calendars); err != nil {
+		return err
+	}
+	for _, obj := range p.Config {
+		if err := obj.Spec.Validate(); err != nil {
+			return fmt.Errorf("%s %s: %w", obj.Kind, obj.Name, err)
+		}
+	}
+	return nil
+}
+
+// isSystemNamespace reports whether a namespace belongs to the platform
+func isSystemNamespace(name string) bool {
+	return name == "default" || name == "openshift" ||
+		strings.HasPrefix(name, "kube-") || strings.HasPrefix(name, "openshift-")
+}
+
+// isTier reports whether value is a known tier
+func isTier(value string) bool {
+	for _, tier := range tiers {
+		if tier.name == value {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
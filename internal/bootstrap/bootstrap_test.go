@@ -0,0 +1,218 @@
+package bootstrap
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/yaml"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/runtimeconfig"
+)
+
+func namespace(name string, labels map[string]string) *corev1.Namespace {
+	return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+}
+
+func deployment(namespace, name string, replicas int32, cpu string) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{
+				Name: "app",
+				Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse(cpu),
+					corev1.ResourceMemory: resource.MustParse("256Mi"),
+				}},
+			}}}},
+		},
+	}
+}
+
+func prometheusRule(namespace string, alerts int) *unstructured.Unstructured {
+	rules := []interface{}{map[string]interface{}{"record": "job:up:sum", "expr": "sum(up)"}}
+	for i := 0; i < alerts; i++ {
+		rules = append(rules, map[string]interface{}{"alert": "Down", "expr": "up == 0"})
+	}
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "monitoring.coreos.com/v1",
+		"kind":       "PrometheusRule",
+		"metadata":   map[string]interface{}{"namespace": namespace, "name": "alerts"},
+		"spec":       map[string]interface{}{"groups": []interface{}{map[string]interface{}{"name": "app", "rules": rules}}},
+	}}
+}
+
+func newTestGenerator(t *testing.T) (*Generator, *fake.Clientset) {
+	t.Helper()
+	client := fake.NewSimpleClientset(
+		namespace("payments", map[string]string{"criticality": "critical"}),
+		namespace("shop-prod", nil),
+		namespace("shop-dev", nil),
+		namespace("orders", nil),
+		namespace("search", nil),
+		namespace("reports", nil),
+		namespace("empty", nil),
+		namespace("openshift-monitoring", nil),
+		deployment("payments", "api", 2, "500m"),
+		deployment("shop-prod", "web", 3, "250m"),
+		deployment("shop-dev", "web", 1, "100m"),
+		deployment("orders", "api", 2, "500m"),
+		deployment("search", "index", 12, "1"),
+		deployment("reports", "worker", 1, "200m"),
+		deployment("openshift-monitoring", "prometheus", 2, "1"),
+	)
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{prometheusRuleGVR: "PrometheusRuleList"},
+		prometheusRule("orders", 2), prometheusRule("shop-dev", 1))
+
+	generator := NewGenerator(client, dynamicClient, Options{Timezone: "Europe/Berlin"})
+	generator.now = func() time.Time { return time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC) }
+	return generator, client
+}
+
+func TestGenerator_Generate(t *testing.T) {
+	generator, _ := newTestGenerator(t)
+	plan, err := generator.Generate(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, plan.Warnings)
+
+	tiers := map[string]string{}
+	sources := map[string]string{}
+	for _, profile := range plan.Namespaces {
+		tiers[profile.Name] = profile.Tier
+		sources[profile.Name] = profile.Source
+	}
+	assert.Equal(t, map[string]string{
+		"payments":  "critical",
+		"shop-prod": "critical",
+		"shop-dev":  "low",
+		"orders":    "high",
+		"search":    "high",
+		"reports":   "medium",
+		"empty":     "low",
+	}, tiers, "system namespaces are skipped")
+	assert.Equal(t, SourceLabel, sources["payments"])
+	assert.Equal(t, SourceName, sources["shop-dev"], "name hints win over alerting rules")
+	assert.Equal(t, SourceAlerts, sources["orders"])
+	assert.Equal(t, SourceWorkloads, sources["search"])
+
+	search := plan.Namespaces[len(plan.Namespaces)-3]
+	require.Equal(t, "search", search.Name)
+	assert.Equal(t, int32(12), search.Replicas)
+	assert.Equal(t, int64(12000), search.CPURequestsMillicores)
+	assert.Equal(t, int64(12*256<<20), search.MemoryRequestsBytes)
+
+	require.Len(t, plan.ScopeDefaults.Rules, 4)
+	assert.Equal(t, "critical-tier", plan.ScopeDefaults.Rules[0].Name)
+	assert.Equal(t, 0.6, plan.ScopeDefaults.Rules[0].Threshold)
+
+	require.Len(t, plan.Calendars.Calendars, 1)
+	quiet := plan.Calendars.Calendars[0]
+	assert.Equal(t, "Europe/Berlin", quiet.Timezone)
+	assert.Equal(t, []string{"empty", "reports", "shop-dev"}, quiet.Namespaces)
+
+	require.Len(t, plan.Config, 2)
+	assert.Equal(t, runtimeconfig.KindScanSchedule, plan.Config[0].Kind)
+	assert.Equal(t, "scan-critical", plan.Config[0].Name)
+	assert.Equal(t, []string{"payments", "shop-prod"}, plan.Config[0].Spec.(*runtimeconfig.ScanSchedule).Namespaces)
+	assert.Equal(t, []string{"orders", "search"}, plan.Config[1].Spec.(*runtimeconfig.ScanSchedule).Namespaces)
+}
+
+func TestGenerator_Generate_WithoutPrometheusRules(t *testing.T) {
+	client := fake.NewSimpleClientset(namespace("orders", nil), deployment("orders", "api", 1, "100m"))
+	plan, err := NewGenerator(client, nil, Options{IncludeSystem: true}).Generate(context.Background())
+	require.NoError(t, err)
+	require.Len(t, plan.Warnings, 1)
+	assert.Equal(t, "medium", plan.Namespaces[0].Tier)
+	assert.Empty(t, plan.Config)
+
+	_, err = NewGenerator(client, nil, Options{Timezone: "Mars/Olympus"}).Generate(context.Background())
+	assert.Error(t, err)
+}
+
+func TestApply(t *testing.T) {
+	generator, client := newTestGenerator(t)
+	ctx := context.Background()
+	plan, err := generator.Generate(ctx)
+	require.NoError(t, err)
+
+	var created []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/admin/config/scan-schedules", r.URL.Path)
+		assert.Equal(t, "Bearer s3cret", r.Header.Get("Authorization"))
+		var body struct {
+			Name string                     `json:"name"`
+			Spec runtimeconfig.ScanSchedule `json:"spec"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		if body.Name == "scan-high" {
+			w.WriteHeader(http.StatusConflict)
+			_, _ = w.Write([]byte(`{"status":"error","error":"configuration object already exists: scan-high"}`))
+			return
+		}
+		created = append(created, body.Name)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	results := Apply(ctx, client, plan, ApplyOptions{EngineURL: server.URL, AdminToken: "s3cret"})
+	statuses := map[string]string{}
+	for _, result := range results {
+		statuses[result.Target] = result.Status
+	}
+	assert.Equal(t, StatusUnchanged, statuses["namespaces/payments"])
+	assert.Equal(t, StatusLabeled, statuses["namespaces/orders"])
+	assert.Equal(t, StatusCreated, statuses["scan-schedules/scan-critical"])
+	assert.Equal(t, StatusExists, statuses["scan-schedules/scan-high"])
+	assert.Equal(t, []string{"scan-critical"}, created)
+
+	ns, err := client.CoreV1().Namespaces().Get(ctx, "orders", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "high", ns.Labels["criticality"])
+
+	// Without an engine URL only namespaces are labeled
+	results = Apply(ctx, client, plan, ApplyOptions{})
+	assert.Equal(t, StatusSkipped, results[len(results)-1].Status)
+}
+
+func TestLoadPlan(t *testing.T) {
+	generator, _ := newTestGenerator(t)
+	plan, err := generator.Generate(context.Background())
+	require.NoError(t, err)
+
+	// A reviewed plan round-trips through YAML
+	plan.Namespaces[0].Tier = "high"
+	data, err := yaml.Marshal(plan)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "plan.yaml")
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+
+	loaded, err := LoadPlan(path)
+	require.NoError(t, err)
+	assert.Equal(t, "high", loaded.Namespaces[0].Tier)
+	assert.Equal(t, plan.Config, loaded.Config)
+
+	plan.Namespaces[0].Tier = "urgent"
+	data, err = yaml.Marshal(plan)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	_, err = LoadPlan(path)
+	assert.ErrorContains(t, err, `unknown tier "urgent"`)
+}
@@ -0,0 +1,30 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManual(t *testing.T) {
+	start := time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC)
+	clock := NewManual(start)
+	assert.Equal(t, start, clock.Now())
+	assert.Equal(t, start, clock.Now(), "the clock stands still")
+
+	assert.Equal(t, start.Add(5*time.Minute), clock.Advance(5*time.Minute))
+	assert.Equal(t, start.Add(5*time.Minute), clock.Now())
+
+	clock.Set(start)
+	assert.Equal(t, start, clock.Now())
+}
+
+func TestSeeded(t *testing.T) {
+	first, second := Seeded(42), Seeded(42)
+	for i := 0; i < 100; i++ {
+		n := first(100)
+		assert.Equal(t, n, second(100), "same seed, same sequence")
+		assert.True(t, n >= 0 && n < 100)
+	}
+}
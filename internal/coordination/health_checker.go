@@ -4,6 +4,8 @@ package coordination
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
@@ -12,6 +14,10 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/integrations"
+	"github.com/tosin2013/openshift-coordination-engine/internal/platform"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
 )
 
 // HealthChecker verifies layer-specific health conditions
@@ -19,6 +25,11 @@ type HealthChecker struct {
 	clientset     kubernetes.Interface
 	dynamicClient dynamic.Interface
 	log           *logrus.Logger
+
+	// Optional platform checks of in-cluster DNS and TLS certificates
+	prometheusClient *integrations.PrometheusClient
+	maxDNSErrorRate  float64
+	certificates     *platform.CertificateScanner
 }
 
 // NewHealthChecker creates a new health checker
@@ -30,6 +41,19 @@ func NewHealthChecker(clientset kubernetes.Interface, dynamicClient dynamic.Inte
 	}
 }
 
+// SetDNSCheck fails platform health when more than maxErrorRate of CoreDNS responses
+// are server failures or refusals
+func (hc *HealthChecker) SetDNSCheck(client *integrations.PrometheusClient, maxErrorRate float64) {
+	hc.prometheusClient = client
+	hc.maxDNSErrorRate = maxErrorRate
+}
+
+// SetCertificateScanner fails platform health while a TLS secret holds an expired
+// certificate
+func (hc *HealthChecker) SetCertificateScanner(scanner *platform.CertificateScanner) {
+	hc.certificates = scanner
+}
+
 // CheckInfrastructureHealth verifies infrastructure layer health
 func (hc *HealthChecker) CheckInfrastructureHealth(ctx context.Context) error {
 	hc.log.Info("Checking infrastructure layer health")
@@ -58,6 +82,8 @@ func (hc *HealthChecker) CheckPlatformHealth(ctx context.Context) error {
 		hc.checkOperatorsReady,
 		hc.checkNetworkingFunctional,
 		hc.checkIngressAvailable,
+		hc.checkDNSHealthy,
+		hc.checkCertificatesValid,
 	}
 
 	for _, check := range checks {
@@ -246,73 +272,32 @@ func (hc *HealthChecker) checkOperatorsReady(ctx context.Context) error {
 		return nil
 	}
 
-	// Define ClusterOperator GVR
-	coGVR := schema.GroupVersionResource{
-		Group:    "config.openshift.io",
-		Version:  "v1",
-		Resource: "clusteroperators",
-	}
-
 	// List ClusterOperators
-	coList, err := hc.dynamicClient.Resource(coGVR).List(ctx, metav1.ListOptions{})
+	coList, err := hc.dynamicClient.Resource(integrations.ClusterOperatorGVR).List(ctx, metav1.ListOptions{})
 	if err != nil {
 		// ClusterOperators might not exist in non-OpenShift clusters
 		hc.log.WithError(err).Debug("Failed to list ClusterOperators (may not be OpenShift)")
 		return nil
 	}
 
-	degradedOperators := 0
-	unavailableOperators := 0
-	for _, item := range coList.Items {
-		// Extract status conditions
-		conditions, found, err := unstructured.NestedSlice(item.Object, "status", "conditions")
-		if err != nil || !found {
-			continue
-		}
-
-		isDegraded := false
-		isAvailable := false
-
-		for _, cond := range conditions {
-			condition, ok := cond.(map[string]interface{})
-			if !ok {
-				continue
-			}
-
-			condType, found, err := unstructured.NestedString(condition, "type")
-			if err != nil || !found {
-				continue
-			}
-			condStatus, found, err := unstructured.NestedString(condition, "status")
-			if err != nil || !found {
-				continue
-			}
-
-			if condType == "Degraded" && condStatus == "True" {
-				isDegraded = true
-			}
-
-			if condType == "Available" && condStatus == "True" {
-				isAvailable = true
-			}
-		}
-
-		if isDegraded {
-			degradedOperators++
-			hc.log.WithField("operator", item.GetName()).Warn("ClusterOperator is degraded")
-		}
-
-		if !isAvailable {
-			unavailableOperators++
-			hc.log.WithField("operator", item.GetName()).Warn("ClusterOperator is not available")
-		}
+	operators := make([]models.ClusterOperatorStatus, 0, len(coList.Items))
+	for i := range coList.Items {
+		operators = append(operators, integrations.ParseClusterOperator(&coList.Items[i]))
 	}
+	summary := models.SummarizeClusterOperators(operators)
 
-	if degradedOperators > 0 || unavailableOperators > 0 {
-		return fmt.Errorf("%d ClusterOperator(s) degraded, %d unavailable", degradedOperators, unavailableOperators)
+	if len(summary.Unhealthy) > 0 {
+		// Name each operator so platform incidents point at the exact one
+		evidence := make([]string, 0, len(summary.Unhealthy))
+		for i := range summary.Unhealthy {
+			evidence = append(evidence, summary.Unhealthy[i].Evidence())
+			hc.log.WithField("operator", summary.Unhealthy[i].Name).Warn(evidence[i])
+		}
+		return fmt.Errorf("%d ClusterOperator(s) degraded, %d unavailable: %s",
+			len(summary.Degraded), len(summary.Unavailable), strings.Join(evidence, "; "))
 	}
 
-	hc.log.WithField("operators", len(coList.Items)).Debug("All ClusterOperators are ready")
+	hc.log.WithField("operators", summary.Total).Debug("All ClusterOperators are ready")
 	return nil
 }
 
@@ -414,6 +399,56 @@ func (hc *HealthChecker) checkIngressAvailable(ctx context.Context) error {
 	return nil
 }
 
+func (hc *HealthChecker) checkDNSHealthy(ctx context.Context) error {
+	if hc.prometheusClient == nil {
+		return nil
+	}
+	hc.log.Debug("Checking in-cluster DNS error rate")
+
+	health, err := platform.CheckDNS(ctx, hc.prometheusClient)
+	if err != nil {
+		hc.log.WithError(err).Debug("DNS metrics not available, skipping DNS check")
+		return nil
+	}
+
+	if health.ErrorRate > hc.maxDNSErrorRate {
+		hc.log.WithFields(logrus.Fields{
+			"error_rate":          health.ErrorRate,
+			"latency_p99_seconds": health.LatencyP99Seconds,
+		}).Warn("In-cluster DNS error rate is high")
+		return fmt.Errorf("in-cluster DNS error rate %.1f%% exceeds %.1f%% (CoreDNS SERVFAIL/REFUSED responses)",
+			health.ErrorRate*100, hc.maxDNSErrorRate*100)
+	}
+
+	hc.log.WithField("error_rate", health.ErrorRate).Debug("In-cluster DNS is healthy")
+	return nil
+}
+
+func (hc *HealthChecker) checkCertificatesValid(ctx context.Context) error {
+	if hc.certificates == nil {
+		return nil
+	}
+	hc.log.Debug("Checking TLS certificate expiry")
+
+	expiring, err := hc.certificates.Expiring(ctx, 0)
+	if err != nil {
+		hc.log.WithError(err).Debug("Failed to scan TLS secrets, skipping certificate check")
+		return nil
+	}
+
+	if len(expiring) > 0 {
+		evidence := make([]string, 0, len(expiring))
+		for _, certificate := range expiring {
+			evidence = append(evidence, fmt.Sprintf("%s/%s expired %s",
+				certificate.Namespace, certificate.Secret, certificate.NotAfter.Format(time.RFC3339)))
+		}
+		return fmt.Errorf("%d TLS certificate(s) expired: %s", len(expiring), strings.Join(evidence, "; "))
+	}
+
+	hc.log.Debug("No expired TLS certificates")
+	return nil
+}
+
 // Application checks
 
 func (hc *HealthChecker) checkPodsRunning(ctx context.Context) error {
@@ -3,10 +3,19 @@ package coordination
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
 	k8sfake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/platform"
 )
 
 func TestNewHealthChecker(t *testing.T) {
@@ -87,3 +96,57 @@ func TestHealthChecker_CheckPodsRunning(t *testing.T) {
 	err := hc.checkPodsRunning(ctx)
 	assert.NoError(t, err)
 }
+
+func TestHealthChecker_CheckOperatorsReady(t *testing.T) {
+	operator := func(name, available, degraded, reason string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "config.openshift.io/v1",
+			"kind":       "ClusterOperator",
+			"metadata":   map[string]interface{}{"name": name},
+			"status": map[string]interface{}{
+				"conditions": []interface{}{
+					map[string]interface{}{"type": "Available", "status": available},
+					map[string]interface{}{"type": "Degraded", "status": degraded, "reason": reason},
+				},
+			},
+		}}
+	}
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	ctx := context.Background()
+
+	healthy := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), operator("ingress", "True", "False", ""))
+	hc := NewHealthChecker(k8sfake.NewSimpleClientset(), healthy, log)
+	assert.NoError(t, hc.checkOperatorsReady(ctx))
+
+	degraded := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(),
+		operator("ingress", "True", "False", ""),
+		operator("network", "True", "True", "RolloutHung"),
+	)
+	hc = NewHealthChecker(k8sfake.NewSimpleClientset(), degraded, log)
+	err := hc.checkOperatorsReady(ctx)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "1 ClusterOperator(s) degraded, 0 unavailable")
+	assert.Contains(t, err.Error(), "ClusterOperator network: Degraded=True (RolloutHung)")
+}
+
+func TestHealthChecker_CheckCertificatesValid(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	clientset := k8sfake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "router-certs",
+			Namespace:   "openshift-ingress",
+			Annotations: map[string]string{platform.ServingCertExpiryAnnotation: time.Now().Add(-time.Hour).Format(time.RFC3339)},
+		},
+		Type: corev1.SecretTypeTLS,
+	})
+
+	hc := NewHealthChecker(clientset, nil, log)
+	require.NoError(t, hc.checkCertificatesValid(context.Background()), "check is skipped without a scanner")
+
+	hc.SetCertificateScanner(platform.NewCertificateScanner(clientset, nil, log))
+	err := hc.checkCertificatesValid(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "1 TLS certificate(s) expired: openshift-ingress/router-certs")
+}
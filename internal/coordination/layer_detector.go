@@ -7,12 +7,20 @@ import (
 
 	"github.com/sirupsen/logrus"
 
+	"github.com/tosin2013/openshift-coordination-engine/internal/redact"
 	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
 )
 
+// ClusterOperatorSource reports degraded or unavailable ClusterOperators
+type ClusterOperatorSource interface {
+	UnhealthyClusterOperators(ctx context.Context) ([]models.ClusterOperatorStatus, error)
+}
+
 // LayerDetector detects which layers are affected by an issue
 type LayerDetector struct {
-	log *logrus.Logger
+	operatorSource ClusterOperatorSource
+	redactor       *redact.Redactor
+	log            *logrus.Logger
 }
 
 // NewLayerDetector creates a new layer detector
@@ -22,6 +30,16 @@ func NewLayerDetector(log *logrus.Logger) *LayerDetector {
 	}
 }
 
+// SetClusterOperatorSource enables ClusterOperator evidence for platform-layer issues
+func (ld *LayerDetector) SetClusterOperatorSource(source ClusterOperatorSource) {
+	ld.operatorSource = source
+}
+
+// SetRedactor masks credentials in ClusterOperator condition messages used as evidence
+func (ld *LayerDetector) SetRedactor(redactor *redact.Redactor) {
+	ld.redactor = redactor
+}
+
 // DetectLayers analyzes an issue and determines affected layers
 // Returns a LayeredIssue with affected layers, root cause, and grouped resources
 func (ld *LayerDetector) DetectLayers(ctx context.Context, issueID, issueDescription string, resources []models.Resource) *models.LayeredIssue {
@@ -54,6 +72,11 @@ func (ld *LayerDetector) DetectLayers(ctx context.Context, issueID, issueDescrip
 	// Group resources by layer
 	ld.groupAndAddResources(layeredIssue, resources)
 
+	// Reference the exact unhealthy ClusterOperators for platform issues
+	if layeredIssue.RequiresPlatformRemediation() {
+		ld.addClusterOperatorEvidence(ctx, layeredIssue)
+	}
+
 	ld.log.WithFields(logrus.Fields{
 		"issue_id":        issueID,
 		"affected_layers": layeredIssue.AffectedLayers,
@@ -207,6 +230,44 @@ func (ld *LayerDetector) groupAndAddResources(layeredIssue *models.LayeredIssue,
 	}
 }
 
+// addClusterOperatorEvidence records degraded or unavailable ClusterOperators as
+// platform evidence and impacted resources. Lookup failures are logged and ignored
+// so detection still works on clusters without ClusterOperators.
+func (ld *LayerDetector) addClusterOperatorEvidence(ctx context.Context, layeredIssue *models.LayeredIssue) {
+	if ld.operatorSource == nil {
+		return
+	}
+
+	operators, err := ld.operatorSource.UnhealthyClusterOperators(ctx)
+	if err != nil {
+		ld.log.WithError(err).Debug("Failed to read ClusterOperator status, skipping operator evidence")
+		return
+	}
+
+	for i := range operators {
+		evidence := operators[i].Evidence()
+		if ld.redactor != nil {
+			evidence = ld.redactor.Redact(redact.SourceEvidence, evidence)
+		}
+		layeredIssue.AddEvidence(models.LayerPlatform, evidence)
+
+		if !layeredIssue.HasImpactedResource(models.LayerPlatform, "ClusterOperator", operators[i].Name) {
+			layeredIssue.AddImpactedResource(models.LayerPlatform, models.Resource{
+				Kind:  "ClusterOperator",
+				Name:  operators[i].Name,
+				Issue: evidence,
+			})
+		}
+	}
+
+	if len(operators) > 0 {
+		ld.log.WithFields(logrus.Fields{
+			"issue_id":  layeredIssue.ID,
+			"operators": len(operators),
+		}).Info("Unhealthy ClusterOperators added to platform evidence")
+	}
+}
+
 // resourceToLayer maps a resource kind to its layer
 func (ld *LayerDetector) resourceToLayer(resource models.Resource) models.Layer {
 	switch resource.Kind {
@@ -291,3 +291,50 @@ func TestGetLayersByPriority(t *testing.T) {
 	assert.Equal(t, models.LayerPlatform, orderedLayers[1])
 	assert.Equal(t, models.LayerApplication, orderedLayers[2])
 }
+
+// stubOperatorSource returns fixed ClusterOperator statuses
+type stubOperatorSource struct {
+	operators []models.ClusterOperatorStatus
+}
+
+func (s *stubOperatorSource) UnhealthyClusterOperators(ctx context.Context) ([]models.ClusterOperatorStatus, error) {
+	return s.operators, nil
+}
+
+func TestDetectLayers_ClusterOperatorEvidence(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	detector := NewLayerDetector(log)
+	detector.SetClusterOperatorSource(&stubOperatorSource{operators: []models.ClusterOperatorStatus{
+		{
+			Name:      "network",
+			Available: true,
+			Degraded:  true,
+			Conditions: []models.ClusterOperatorCondition{
+				{Type: "Degraded", Status: "True", Reason: "RolloutHung", Message: "ovnkube-node is not making progress"},
+			},
+		},
+		{Name: "dns"},
+	}})
+	ctx := context.Background()
+
+	resources := []models.Resource{
+		{Kind: "ClusterOperator", Name: "network", Issue: "Degraded"},
+	}
+
+	layeredIssue := detector.DetectLayers(ctx, "issue-008", "cluster operator degraded", resources)
+
+	assert.Equal(t, []string{
+		"ClusterOperator network: Degraded=True (RolloutHung: ovnkube-node is not making progress)",
+		"ClusterOperator dns: Available condition not reported",
+	}, layeredIssue.Evidence[models.LayerPlatform])
+
+	// The referenced operator is not duplicated; the other degraded operator is added
+	platform := layeredIssue.GetResourcesForLayer(models.LayerPlatform)
+	assert.Len(t, platform, 2)
+	assert.True(t, layeredIssue.HasImpactedResource(models.LayerPlatform, "ClusterOperator", "dns"))
+
+	// Application-only issues do not pick up operator evidence
+	appIssue := detector.DetectLayers(ctx, "issue-009", "pod crashloop", []models.Resource{{Kind: "Pod", Name: "app"}})
+	assert.Empty(t, appIssue.Evidence)
+}
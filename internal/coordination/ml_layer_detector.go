@@ -8,6 +8,7 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/tosin2013/openshift-coordination-engine/internal/integrations"
+	"github.com/tosin2013/openshift-coordination-engine/internal/redact"
 	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
 )
 
@@ -101,6 +102,16 @@ func NewMLLayerDetectorDual(kserveClient *integrations.KServeClient, mlClient *i
 	}
 }
 
+// SetClusterOperatorSource enables ClusterOperator evidence for platform-layer issues
+func (mld *MLLayerDetector) SetClusterOperatorSource(source ClusterOperatorSource) {
+	mld.baseDetector.SetClusterOperatorSource(source)
+}
+
+// SetRedactor masks credentials in ClusterOperator evidence
+func (mld *MLLayerDetector) SetRedactor(redactor *redact.Redactor) {
+	mld.baseDetector.SetRedactor(redactor)
+}
+
 // DetectLayersWithML performs ML-enhanced layer detection
 func (mld *MLLayerDetector) DetectLayersWithML(ctx context.Context, issueID, issueDescription string, resources []models.Resource) *models.LayeredIssue {
 	// 1. Start with keyword-based detection (fast path)
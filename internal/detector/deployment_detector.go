@@ -48,6 +48,7 @@ type deploymentCache struct {
 	mu      sync.RWMutex
 	entries map[string]*cacheEntry
 	ttl     time.Duration
+	now     func() time.Time
 }
 
 type cacheEntry struct {
@@ -63,6 +64,7 @@ func NewDeploymentDetector(clientset kubernetes.Interface, log *logrus.Logger) *
 		cache: &deploymentCache{
 			entries: make(map[string]*cacheEntry),
 			ttl:     5 * time.Minute, // Cache entries for 5 minutes
+			now:     time.Now,
 		},
 	}
 }
@@ -307,6 +309,14 @@ func (d *DeploymentDetector) DetectDaemonSetMethod(ctx context.Context, namespac
 	return info, nil
 }
 
+// SetClock replaces the clock of cache expiry, e.g. with the fixed clock of
+// deterministic mode
+func (d *DeploymentDetector) SetClock(now func() time.Time) {
+	d.cache.mu.Lock()
+	defer d.cache.mu.Unlock()
+	d.cache.now = now
+}
+
 // ClearCache clears all cached deployment detection results
 func (d *DeploymentDetector) ClearCache() {
 	d.cache.clear()
@@ -320,7 +330,7 @@ func (d *DeploymentDetector) GetCacheStats() map[string]interface{} {
 
 	validEntries := 0
 	expiredEntries := 0
-	now := time.Now()
+	now := d.cache.now()
 
 	for _, entry := range d.cache.entries {
 		if now.Before(entry.expiresAt) {
@@ -350,7 +360,7 @@ func (c *deploymentCache) get(key string) *models.DeploymentInfo {
 	}
 
 	// Check if expired
-	if time.Now().After(entry.expiresAt) {
+	if c.now().After(entry.expiresAt) {
 		return nil
 	}
 
@@ -363,7 +373,7 @@ func (c *deploymentCache) set(key string, info *models.DeploymentInfo) {
 
 	c.entries[key] = &cacheEntry{
 		info:      info,
-		expiresAt: time.Now().Add(c.ttl),
+		expiresAt: c.now().Add(c.ttl),
 	}
 }
 
@@ -0,0 +1,159 @@
+package detector
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/integrations"
+)
+
+// Node annotations maintained by the machine-config daemon
+const (
+	CurrentConfigAnnotation = "machineconfiguration.openshift.io/currentConfig"
+	DesiredConfigAnnotation = "machineconfiguration.openshift.io/desiredConfig"
+	MCDStateAnnotation      = "machineconfiguration.openshift.io/state"
+
+	// MCDStateWorking is the daemon state while a node is being updated
+	MCDStateWorking = "Working"
+)
+
+// MachineConfigUpdate describes a MachineConfigPool rolling update in progress
+type MachineConfigUpdate struct {
+	Pool            string   `json:"pool"`
+	UpdatedMachines int32    `json:"updated_machines"`
+	MachineCount    int32    `json:"machine_count"`
+	UpdatingNodes   []string `json:"updating_nodes"`
+}
+
+// MachineConfigUpdateDetector finds in-progress MachineConfigPool updates. During an
+// update the MCO cordons, drains and reboots nodes one by one, so pod evictions,
+// restarts and NotReady nodes are expected and should not be remediated.
+type MachineConfigUpdateDetector struct {
+	clientset kubernetes.Interface
+	mcoClient *integrations.MCOClient
+	log       *logrus.Logger
+}
+
+// NewMachineConfigUpdateDetector creates a MachineConfigPool update detector
+func NewMachineConfigUpdateDetector(clientset kubernetes.Interface, mcoClient *integrations.MCOClient, log *logrus.Logger) *MachineConfigUpdateDetector {
+	return &MachineConfigUpdateDetector{
+		clientset: clientset,
+		mcoClient: mcoClient,
+		log:       log,
+	}
+}
+
+// ActiveUpdates returns the MachineConfigPools that are rolling out a new configuration,
+// with the nodes currently being updated, sorted by pool name
+func (d *MachineConfigUpdateDetector) ActiveUpdates(ctx context.Context) ([]MachineConfigUpdate, error) {
+	pools, err := d.mcoClient.ListPoolStatuses(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make(map[string]*MachineConfigUpdate)
+	for i := range pools {
+		pool := &pools[i]
+		if !pool.Updating && pool.UpdatedMachineCount >= pool.MachineCount {
+			continue
+		}
+		updates[pool.Name] = &MachineConfigUpdate{
+			Pool:            pool.Name,
+			UpdatedMachines: pool.UpdatedMachineCount,
+			MachineCount:    pool.MachineCount,
+			UpdatingNodes:   []string{},
+		}
+	}
+	if len(updates) == 0 {
+		return nil, nil
+	}
+
+	nodes, err := d.clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		if !nodeUpdating(node) {
+			continue
+		}
+		if update := updates[nodePool(node)]; update != nil {
+			update.UpdatingNodes = append(update.UpdatingNodes, node.Name)
+		}
+	}
+
+	result := make([]MachineConfigUpdate, 0, len(updates))
+	for _, update := range updates {
+		sort.Strings(update.UpdatingNodes)
+		result = append(result, *update)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Pool < result[j].Pool
+	})
+
+	d.log.WithField("pools", len(result)).Debug("MachineConfigPool updates in progress")
+
+	return result, nil
+}
+
+// UpdatesAffecting returns the in-progress updates that explain churn in a scope.
+// For a node, or a pod scheduled on a node, only the update of that node's pool
+// counts; for a namespace or the whole cluster every update counts, since drained
+// pods are rescheduled anywhere.
+func (d *MachineConfigUpdateDetector) UpdatesAffecting(ctx context.Context, namespace, pod, node string) ([]MachineConfigUpdate, error) {
+	updates, err := d.ActiveUpdates(ctx)
+	if err != nil || len(updates) == 0 {
+		return nil, err
+	}
+
+	if node == "" && pod != "" && namespace != "" {
+		p, err := d.clientset.CoreV1().Pods(namespace).Get(ctx, pod, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pod %s/%s: %w", namespace, pod, err)
+		}
+		node = p.Spec.NodeName
+	}
+	if node == "" {
+		return updates, nil
+	}
+
+	n, err := d.clientset.CoreV1().Nodes().Get(ctx, node, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get node %s: %w", node, err)
+	}
+	pool := nodePool(n)
+	for i := range updates {
+		if updates[i].Pool == pool {
+			return updates[i : i+1], nil
+		}
+	}
+	return nil, nil
+}
+
+// nodeUpdating reports whether the machine-config daemon is updating a node
+func nodeUpdating(node *corev1.Node) bool {
+	current := node.Annotations[CurrentConfigAnnotation]
+	desired := node.Annotations[DesiredConfigAnnotation]
+	return node.Annotations[MCDStateAnnotation] == MCDStateWorking || (desired != "" && current != desired)
+}
+
+// nodePool derives a node's MachineConfigPool from its rendered config name,
+// "rendered-<pool>-<hash>"
+func nodePool(node *corev1.Node) string {
+	config := node.Annotations[DesiredConfigAnnotation]
+	if config == "" {
+		config = node.Annotations[CurrentConfigAnnotation]
+	}
+	config = strings.TrimPrefix(config, "rendered-")
+	if idx := strings.LastIndex(config, "-"); idx > 0 {
+		return config[:idx]
+	}
+	return ""
+}
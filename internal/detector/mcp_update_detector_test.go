@@ -0,0 +1,127 @@
+package detector
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/integrations"
+)
+
+func testPool(name string, machines, updated int64, updating bool) *unstructured.Unstructured {
+	status := "False"
+	if updating {
+		status = "True"
+	}
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "machineconfiguration.openshift.io/v1",
+		"kind":       "MachineConfigPool",
+		"metadata":   map[string]interface{}{"name": name},
+		"status": map[string]interface{}{
+			"machineCount":        machines,
+			"updatedMachineCount": updated,
+			"readyMachineCount":   updated,
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Updating", "status": status},
+			},
+		},
+	}}
+}
+
+func testMCDNode(name, current, desired, state string) *corev1.Node {
+	return &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name: name,
+		Annotations: map[string]string{
+			CurrentConfigAnnotation: current,
+			DesiredConfigAnnotation: desired,
+			MCDStateAnnotation:      state,
+		},
+	}}
+}
+
+func newTestMCPDetector(t *testing.T, pools []runtime.Object, objects ...runtime.Object) *MachineConfigUpdateDetector {
+	t.Helper()
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	mco := integrations.NewMCOClient(dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), pools...), log)
+	return NewMachineConfigUpdateDetector(fake.NewSimpleClientset(objects...), mco, log)
+}
+
+func TestMachineConfigUpdateDetector_ActiveUpdates(t *testing.T) {
+	d := newTestMCPDetector(t,
+		[]runtime.Object{
+			testPool("worker", 3, 1, true),
+			testPool("master", 3, 3, false),
+		},
+		testMCDNode("worker-0", "rendered-worker-new", "rendered-worker-new", "Done"),
+		testMCDNode("worker-1", "rendered-worker-old", "rendered-worker-new", "Working"),
+		testMCDNode("worker-2", "rendered-worker-old", "rendered-worker-new", "Done"),
+		testMCDNode("master-0", "rendered-master-a", "rendered-master-a", "Done"),
+	)
+
+	updates, err := d.ActiveUpdates(context.Background())
+	require.NoError(t, err)
+	require.Len(t, updates, 1)
+	assert.Equal(t, MachineConfigUpdate{
+		Pool:            "worker",
+		UpdatedMachines: 1,
+		MachineCount:    3,
+		UpdatingNodes:   []string{"worker-1", "worker-2"},
+	}, updates[0])
+}
+
+func TestMachineConfigUpdateDetector_UpdatesAffecting(t *testing.T) {
+	pod := func(name, node string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "apps"},
+			Spec:       corev1.PodSpec{NodeName: node},
+		}
+	}
+	d := newTestMCPDetector(t,
+		[]runtime.Object{testPool("worker", 2, 1, true), testPool("infra", 2, 2, false)},
+		testMCDNode("worker-1", "rendered-worker-old", "rendered-worker-new", "Working"),
+		testMCDNode("infra-0", "rendered-infra-a", "rendered-infra-a", "Done"),
+		pod("api-1", "worker-1"),
+		pod("router-1", "infra-0"),
+	)
+	ctx := context.Background()
+
+	updates, err := d.UpdatesAffecting(ctx, "apps", "", "")
+	require.NoError(t, err)
+	assert.Len(t, updates, 1, "namespace scope is affected by any update")
+
+	updates, err = d.UpdatesAffecting(ctx, "apps", "api-1", "")
+	require.NoError(t, err)
+	assert.Len(t, updates, 1)
+
+	updates, err = d.UpdatesAffecting(ctx, "apps", "router-1", "")
+	require.NoError(t, err)
+	assert.Empty(t, updates, "pods on nodes of a stable pool are not affected")
+
+	updates, err = d.UpdatesAffecting(ctx, "", "", "infra-0")
+	require.NoError(t, err)
+	assert.Empty(t, updates)
+
+	_, err = d.UpdatesAffecting(ctx, "apps", "missing", "")
+	assert.Error(t, err)
+
+	stable := newTestMCPDetector(t, []runtime.Object{testPool("worker", 2, 2, false)})
+	updates, err = stable.UpdatesAffecting(ctx, "apps", "api-1", "")
+	require.NoError(t, err)
+	assert.Empty(t, updates)
+}
+
+func TestNodePool(t *testing.T) {
+	assert.Equal(t, "worker", nodePool(testMCDNode("n", "rendered-worker-abc", "rendered-worker-abc", "Done")))
+	assert.Equal(t, "worker-gpu", nodePool(testMCDNode("n", "rendered-worker-gpu-abc", "", "Done")))
+	assert.Equal(t, "", nodePool(&corev1.Node{}))
+}
@@ -70,6 +70,16 @@ var (
 		},
 		[]string{"status"}, // valid, expired
 	)
+
+	// ExpectedChurnTotal counts anomalies downgraded and remediations deferred because
+	// a MachineConfigPool update was in progress
+	ExpectedChurnTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "coordination_engine_machineconfig_update_suppressions_total",
+			Help: "Total number of anomalies and remediations suppressed during MachineConfigPool updates",
+		},
+		[]string{"source"}, // anomaly, remediation
+	)
 )
 
 // RecordDetection records metrics for a successful detection
@@ -98,3 +108,8 @@ func UpdateCacheSize(validEntries, expiredEntries int) {
 	CacheSize.WithLabelValues("valid").Set(float64(validEntries))
 	CacheSize.WithLabelValues("expired").Set(float64(expiredEntries))
 }
+
+// RecordExpectedChurn records an anomaly or remediation suppressed during a MachineConfigPool update
+func RecordExpectedChurn(source string) {
+	ExpectedChurnTotal.WithLabelValues(source).Inc()
+}
@@ -0,0 +1,218 @@
+package detector
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// RevisionAnnotation is set by the deployment controller on every ReplicaSet it creates
+	RevisionAnnotation = "deployment.kubernetes.io/revision"
+
+	// DefaultRolloutWindow is how far back a rollout is considered "recent"
+	// when correlating it with an anomaly
+	DefaultRolloutWindow = 30 * time.Minute
+)
+
+// RolloutChange describes a deployment revision that was rolled out recently
+type RolloutChange struct {
+	Namespace      string    `json:"namespace"`
+	Deployment     string    `json:"deployment"`
+	Revision       int64     `json:"revision"`
+	ReplicaSet     string    `json:"replicaset"`
+	ChangedAt      time.Time `json:"changed_at"`
+	Images         []string  `json:"images"`
+	PreviousImages []string  `json:"previous_images,omitempty"`
+	ImageChanged   bool      `json:"image_changed"`
+}
+
+// RolloutDetector finds recent deployment rollouts by inspecting ReplicaSet revisions.
+// A new ReplicaSet revision is created by the deployment controller each time the
+// pod template changes, so its creation time marks the start of a rollout.
+type RolloutDetector struct {
+	clientset kubernetes.Interface
+	log       *logrus.Logger
+	window    time.Duration
+}
+
+// NewRolloutDetector creates a rollout detector using the default correlation window
+func NewRolloutDetector(clientset kubernetes.Interface, log *logrus.Logger) *RolloutDetector {
+	return &RolloutDetector{
+		clientset: clientset,
+		log:       log,
+		window:    DefaultRolloutWindow,
+	}
+}
+
+// SetWindow overrides the correlation window
+func (r *RolloutDetector) SetWindow(window time.Duration) {
+	if window > 0 {
+		r.window = window
+	}
+}
+
+// Window returns the correlation window
+func (r *RolloutDetector) Window() time.Duration {
+	return r.window
+}
+
+// RecentRollouts returns rollouts started within the correlation window before the given time.
+// If deployment is empty, every deployment in the namespace is considered.
+// If pod is set, the owning deployment is resolved through its ReplicaSet.
+func (r *RolloutDetector) RecentRollouts(ctx context.Context, namespace, deployment, pod string, at time.Time) ([]RolloutChange, error) {
+	if namespace == "" {
+		return nil, nil
+	}
+
+	if deployment == "" && pod != "" {
+		owner, err := r.deploymentForPod(ctx, namespace, pod)
+		if err != nil {
+			return nil, err
+		}
+		deployment = owner
+	}
+
+	rsList, err := r.clientset.AppsV1().ReplicaSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list replicasets in namespace %s: %w", namespace, err)
+	}
+
+	// Group ReplicaSets by owning deployment
+	byDeployment := make(map[string][]appsv1.ReplicaSet)
+	for i := range rsList.Items {
+		rs := rsList.Items[i]
+		owner := deploymentOwner(&rs)
+		if owner == "" || (deployment != "" && owner != deployment) {
+			continue
+		}
+		byDeployment[owner] = append(byDeployment[owner], rs)
+	}
+
+	since := at.Add(-r.window)
+	var changes []RolloutChange
+	for name, replicaSets := range byDeployment {
+		if change := latestRollout(namespace, name, replicaSets, since, at); change != nil {
+			changes = append(changes, *change)
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].ChangedAt.After(changes[j].ChangedAt)
+	})
+
+	r.log.WithFields(logrus.Fields{
+		"namespace":  namespace,
+		"deployment": deployment,
+		"rollouts":   len(changes),
+		"window":     r.window.String(),
+	}).Debug("Checked for recent rollouts")
+
+	return changes, nil
+}
+
+// deploymentForPod resolves the deployment that owns a pod (pod -> ReplicaSet -> Deployment)
+func (r *RolloutDetector) deploymentForPod(ctx context.Context, namespace, pod string) (string, error) {
+	p, err := r.clientset.CoreV1().Pods(namespace).Get(ctx, pod, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get pod %s/%s: %w", namespace, pod, err)
+	}
+
+	for _, ref := range p.OwnerReferences {
+		if ref.Kind != "ReplicaSet" {
+			continue
+		}
+		rs, err := r.clientset.AppsV1().ReplicaSets(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return "", fmt.Errorf("failed to get replicaset %s/%s: %w", namespace, ref.Name, err)
+		}
+		return deploymentOwner(rs), nil
+	}
+
+	return "", nil
+}
+
+// latestRollout returns the newest revision if it was created within [since, at]
+func latestRollout(namespace, deployment string, replicaSets []appsv1.ReplicaSet, since, at time.Time) *RolloutChange {
+	sort.Slice(replicaSets, func(i, j int) bool {
+		return revisionOf(&replicaSets[i]) > revisionOf(&replicaSets[j])
+	})
+
+	latest := &replicaSets[0]
+	created := latest.CreationTimestamp.Time
+	if created.Before(since) || created.After(at) {
+		return nil
+	}
+
+	change := &RolloutChange{
+		Namespace:  namespace,
+		Deployment: deployment,
+		Revision:   revisionOf(latest),
+		ReplicaSet: latest.Name,
+		ChangedAt:  created,
+		Images:     containerImages(latest),
+	}
+
+	if len(replicaSets) > 1 {
+		change.PreviousImages = containerImages(&replicaSets[1])
+		change.ImageChanged = !sameImages(change.Images, change.PreviousImages)
+	} else {
+		// First revision: the deployment itself is new
+		change.ImageChanged = true
+	}
+
+	return change
+}
+
+// deploymentOwner returns the name of the owning deployment, or "" if none
+func deploymentOwner(rs *appsv1.ReplicaSet) string {
+	for _, ref := range rs.OwnerReferences {
+		if ref.Kind == "Deployment" {
+			return ref.Name
+		}
+	}
+	return ""
+}
+
+// revisionOf parses the deployment revision annotation of a ReplicaSet
+func revisionOf(rs *appsv1.ReplicaSet) int64 {
+	rev, err := strconv.ParseInt(rs.Annotations[RevisionAnnotation], 10, 64)
+	if err != nil {
+		return 0
+	}
+	return rev
+}
+
+// containerImages returns the container images of a ReplicaSet's pod template
+func containerImages(rs *appsv1.ReplicaSet) []string {
+	images := make([]string, 0, len(rs.Spec.Template.Spec.Containers))
+	for _, c := range rs.Spec.Template.Spec.Containers {
+		images = append(images, c.Image)
+	}
+	return images
+}
+
+// sameImages compares two image lists ignoring order
+func sameImages(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	seen := make(map[string]int, len(a))
+	for _, img := range a {
+		seen[img]++
+	}
+	for _, img := range b {
+		if seen[img] == 0 {
+			return false
+		}
+		seen[img]--
+	}
+	return true
+}
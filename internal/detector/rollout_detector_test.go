@@ -0,0 +1,108 @@
+package detector
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func testReplicaSet(name, deployment, revision, image string, created time.Time) *appsv1.ReplicaSet {
+	return &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "default",
+			CreationTimestamp: metav1.NewTime(created),
+			Annotations:       map[string]string{RevisionAnnotation: revision},
+			OwnerReferences: []metav1.OwnerReference{
+				{Kind: "Deployment", Name: deployment},
+			},
+		},
+		Spec: appsv1.ReplicaSetSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "app", Image: image}},
+				},
+			},
+		},
+	}
+}
+
+func TestRolloutDetector_RecentRollouts(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	now := time.Now()
+
+	clientset := fake.NewSimpleClientset(
+		testReplicaSet("api-1", "api", "1", "api:v1", now.Add(-24*time.Hour)),
+		testReplicaSet("api-2", "api", "2", "api:v2", now.Add(-10*time.Minute)),
+		testReplicaSet("web-1", "web", "1", "web:v1", now.Add(-48*time.Hour)),
+		testReplicaSet("web-2", "web", "2", "web:v2", now.Add(-2*time.Hour)),
+	)
+	d := NewRolloutDetector(clientset, log)
+
+	t.Run("namespace scope returns only recent rollouts", func(t *testing.T) {
+		changes, err := d.RecentRollouts(context.Background(), "default", "", "", now)
+		require.NoError(t, err)
+		require.Len(t, changes, 1)
+		assert.Equal(t, "api", changes[0].Deployment)
+		assert.Equal(t, int64(2), changes[0].Revision)
+		assert.Equal(t, []string{"api:v2"}, changes[0].Images)
+		assert.Equal(t, []string{"api:v1"}, changes[0].PreviousImages)
+		assert.True(t, changes[0].ImageChanged)
+	})
+
+	t.Run("deployment scope filters other deployments", func(t *testing.T) {
+		changes, err := d.RecentRollouts(context.Background(), "default", "web", "", now)
+		require.NoError(t, err)
+		assert.Empty(t, changes)
+	})
+
+	t.Run("wider window includes older rollouts", func(t *testing.T) {
+		wide := NewRolloutDetector(clientset, log)
+		wide.SetWindow(3 * time.Hour)
+		changes, err := wide.RecentRollouts(context.Background(), "default", "", "", now)
+		require.NoError(t, err)
+		require.Len(t, changes, 2)
+		assert.Equal(t, "api", changes[0].Deployment, "newest rollout first")
+	})
+
+	t.Run("no namespace returns nothing", func(t *testing.T) {
+		changes, err := d.RecentRollouts(context.Background(), "", "", "", now)
+		require.NoError(t, err)
+		assert.Nil(t, changes)
+	})
+}
+
+func TestRolloutDetector_PodScope(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	now := time.Now()
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "api-2-abcde",
+			Namespace:       "default",
+			OwnerReferences: []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "api-2"}},
+		},
+	}
+	clientset := fake.NewSimpleClientset(
+		pod,
+		testReplicaSet("api-1", "api", "1", "api:v1", now.Add(-24*time.Hour)),
+		testReplicaSet("api-2", "api", "2", "api:v1", now.Add(-5*time.Minute)),
+	)
+	d := NewRolloutDetector(clientset, log)
+
+	changes, err := d.RecentRollouts(context.Background(), "default", "", "api-2-abcde", now)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.Equal(t, "api", changes[0].Deployment)
+	assert.False(t, changes[0].ImageChanged, "config-only rollout keeps the same image")
+}
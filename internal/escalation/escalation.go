@@ -0,0 +1,319 @@
+// Package escalation escalates the remediation of incidents that keep recurring.
+//
+// Remediation requests for the same resource and issue type form an incident group.
+// An escalation ladder (a runtime configuration object) lists the actions taken for
+// the group: typically restart first, then scale, then page a human. Each recurrence
+// within the window of the previous remediation takes the next step, and the window
+// doubles with every step, so a group only starts over at the first step once it
+// stayed quiet long enough for the escalation it reached.
+package escalation
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/runtimeconfig"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// LabelGroup is set on paged incidents to the ID of their incident group
+const LabelGroup = "escalation_group"
+
+// Tracker settings
+const (
+	// MaxWindow caps the recurrence window of a group however far it escalated
+	MaxWindow = 7 * 24 * time.Hour
+
+	// maxIncidentIDs bounds the incident IDs remembered per group
+	maxIncidentIDs = 20
+
+	// retention is how long a group is kept after its window ended
+	retention = 7 * 24 * time.Hour
+)
+
+// Group is the escalation state of recurring incidents of one issue type on one
+// resource
+type Group struct {
+	ID        string `json:"id"`
+	Namespace string `json:"namespace"`
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	IssueType string `json:"issue_type"`
+
+	// Ladder names the escalation ladder; Step is the 1-based step last taken and
+	// Action its action
+	Ladder string `json:"ladder"`
+	Step   int    `json:"step"`
+	Action string `json:"action"`
+
+	// Occurrences counts the remediations since the ladder last started over
+	Occurrences int `json:"occurrences"`
+
+	// IncidentIDs are the most recent incidents of the group, oldest first
+	IncidentIDs []string `json:"incident_ids"`
+
+	FirstSeen time.Time `json:"first_seen"`
+	LastSeen  time.Time `json:"last_seen"`
+
+	// WindowEnds is when the group stops counting as recurring; an incident before
+	// then takes the next step
+	WindowEnds time.Time `json:"window_ends"`
+}
+
+// Decision is the escalation step planned for a remediation request
+type Decision struct {
+	GroupID    string `json:"group_id"`
+	Ladder     string `json:"ladder"`
+	Step       int    `json:"step"`
+	Action     string `json:"action"`
+	Occurrence int    `json:"occurrence"`
+
+	// Detail describes what the step did, e.g. the replicas a scale step set
+	Detail string `json:"detail,omitempty"`
+
+	// Replicas and Message configure scale and page steps
+	Replicas int    `json:"-"`
+	Message  string `json:"-"`
+
+	issue  *models.Issue
+	window time.Duration
+}
+
+// groupKey identifies the group of an issue
+func groupKey(issue *models.Issue) string {
+	return strings.Join([]string{issue.Namespace, strings.ToLower(issue.ResourceType), issue.ResourceName, issue.Type}, "/")
+}
+
+// GroupID returns the ID of the group an issue belongs to
+func GroupID(issue *models.Issue) string {
+	sum := sha256.Sum256([]byte(groupKey(issue)))
+	return "grp-" + hex.EncodeToString(sum[:])[:12]
+}
+
+// stepWindow is the recurrence window after taking the step with the given 0-based
+// index: the ladder's window doubled per step, at most MaxWindow
+func stepWindow(window time.Duration, index int) time.Duration {
+	for i := 0; i < index && window < MaxWindow; i++ {
+		window *= 2
+	}
+	if window > MaxWindow {
+		window = MaxWindow
+	}
+	return window
+}
+
+// Tracker keeps the incident groups in memory, persisted as JSON in
+// incident_groups.json
+type Tracker struct {
+	groups   map[string]*Group
+	mu       sync.RWMutex
+	dataFile string
+
+	// now is replaceable in tests
+	now func() time.Time
+}
+
+// NewTracker creates a tracker in dataDir (DATA_DIR or /app/data if empty) and loads
+// the groups recorded before a restart
+func NewTracker(dataDir string) *Tracker {
+	if dataDir == "" {
+		dataDir = os.Getenv("DATA_DIR")
+	}
+	if dataDir == "" {
+		dataDir = "/app/data"
+	}
+
+	if err := os.MkdirAll(dataDir, 0o755); err != nil {
+		fmt.Printf("Warning: Could not create data directory %s: %v\n", dataDir, err)
+	}
+	tracker := &Tracker{
+		groups:   make(map[string]*Group),
+		dataFile: filepath.Join(dataDir, "incident_groups.json"),
+		now:      time.Now,
+	}
+
+	if err := tracker.load(); err != nil {
+		fmt.Printf("Warning: Could not load incident groups from disk: %v\n", err)
+	} else {
+		fmt.Printf("Loaded %d incident groups from %s\n", len(tracker.groups), tracker.dataFile)
+	}
+
+	return tracker
+}
+
+// load reads the groups from the data file
+func (t *Tracker) load() error {
+	data, err := os.ReadFile(t.dataFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read data file: %w", err)
+	}
+
+	var groups []*Group
+	if err := json.Unmarshal(data, &groups); err != nil {
+		return fmt.Errorf("failed to unmarshal incident groups: %w", err)
+	}
+	for _, group := range groups {
+		t.groups[group.ID] = group
+	}
+	return nil
+}
+
+// save drops groups past their retention and writes the rest to the data file.
+// Callers must hold t.mu.
+func (t *Tracker) save() error {
+	now := t.now()
+	for id, group := range t.groups {
+		if now.Sub(group.WindowEnds) > retention {
+			delete(t.groups, id)
+		}
+	}
+
+	data, err := json.MarshalIndent(t.sorted(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal incident groups: %w", err)
+	}
+
+	// Write to temp file first, then rename (atomic)
+	tmpFile := t.dataFile + ".tmp"
+	if err := os.WriteFile(tmpFile, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write temp file: %w", err)
+	}
+	if err := os.Rename(tmpFile, t.dataFile); err != nil {
+		return fmt.Errorf("failed to rename temp file: %w", err)
+	}
+	return nil
+}
+
+// sorted returns copies of the groups, most recently seen first. Callers must hold t.mu.
+func (t *Tracker) sorted() []*Group {
+	groups := make([]*Group, 0, len(t.groups))
+	for _, group := range t.groups {
+		groups = append(groups, copyGroup(group))
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].LastSeen.Equal(groups[j].LastSeen) {
+			return groups[i].ID < groups[j].ID
+		}
+		return groups[i].LastSeen.After(groups[j].LastSeen)
+	})
+	return groups
+}
+
+// copyGroup copies a group and its incident IDs
+func copyGroup(group *Group) *Group {
+	copied := *group
+	copied.IncidentIDs = append([]string(nil), group.IncidentIDs...)
+	return &copied
+}
+
+// Plan returns the step the ladder takes for the issue, without recording it. The
+// group recurs, and takes the step after its last one, if its window has not ended
+// and it was escalated by the same ladder; otherwise the ladder starts over.
+func (t *Tracker) Plan(issue *models.Issue, ladderName string, ladder *runtimeconfig.EscalationLadder) *Decision {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	decision := &Decision{
+		GroupID:    GroupID(issue),
+		Ladder:     ladderName,
+		Step:       1,
+		Occurrence: 1,
+		issue:      issue,
+	}
+	if group, ok := t.groups[decision.GroupID]; ok && group.Ladder == ladderName && t.now().Before(group.WindowEnds) {
+		decision.Step = group.Step + 1
+		decision.Occurrence = group.Occurrences + 1
+	}
+	if decision.Step > len(ladder.Steps) {
+		decision.Step = len(ladder.Steps)
+	}
+
+	step := ladder.Steps[decision.Step-1]
+	decision.Action = step.Action
+	decision.Replicas = step.Replicas
+	decision.Message = step.Message
+	decision.window = stepWindow(ladder.WindowDuration(), decision.Step-1)
+	return decision
+}
+
+// Record records that the planned step was taken for an incident and returns the
+// updated group. The group is kept in memory even if it cannot be persisted.
+func (t *Tracker) Record(decision *Decision, incidentID string) (*Group, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	group, ok := t.groups[decision.GroupID]
+	if !ok || decision.Occurrence == 1 {
+		group = &Group{
+			ID:        decision.GroupID,
+			Namespace: decision.issue.Namespace,
+			Kind:      strings.ToLower(decision.issue.ResourceType),
+			Name:      decision.issue.ResourceName,
+			IssueType: decision.issue.Type,
+			FirstSeen: now,
+		}
+		t.groups[group.ID] = group
+	}
+	group.Ladder = decision.Ladder
+	group.Step = decision.Step
+	group.Action = decision.Action
+	group.Occurrences = decision.Occurrence
+	group.LastSeen = now
+	group.WindowEnds = now.Add(decision.window)
+	if incidentID != "" {
+		group.IncidentIDs = append(group.IncidentIDs, incidentID)
+		if len(group.IncidentIDs) > maxIncidentIDs {
+			group.IncidentIDs = group.IncidentIDs[len(group.IncidentIDs)-maxIncidentIDs:]
+		}
+	}
+	RecordEscalation(decision.Action, decision.Step)
+
+	if err := t.save(); err != nil {
+		return copyGroup(group), err
+	}
+	return copyGroup(group), nil
+}
+
+// List returns the groups, most recently seen first
+func (t *Tracker) List() []*Group {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.sorted()
+}
+
+// Get returns a copy of a group, or nil if it does not exist
+func (t *Tracker) Get(id string) *Group {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	group, ok := t.groups[id]
+	if !ok {
+		return nil
+	}
+	return copyGroup(group)
+}
+
+// Reset forgets a group, so its next incident takes the first step again. It
+// reports whether the group existed.
+func (t *Tracker) Reset(id string) (bool, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.groups[id]; !ok {
+		return false, nil
+	}
+	delete(t.groups, id)
+	return true, t.save()
+}
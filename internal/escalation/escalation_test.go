@@ -0,0 +1,129 @@
+package escalation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/runtimeconfig"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+func testLadder() *runtimeconfig.EscalationLadder {
+	return &runtimeconfig.EscalationLadder{
+		IssueTypes: []string{"CrashLoopBackOff"},
+		Window:     "1h",
+		Steps: []runtimeconfig.EscalationStep{
+			{Action: runtimeconfig.EscalationRestart},
+			{Action: runtimeconfig.EscalationScale, Replicas: 2},
+			{Action: runtimeconfig.EscalationPage},
+		},
+	}
+}
+
+func TestTracker_Escalates(t *testing.T) {
+	dir := t.TempDir()
+	tracker := NewTracker(dir)
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	tracker.now = func() time.Time { return now }
+	issue := &models.Issue{Type: "CrashLoopBackOff", Namespace: "payments", ResourceType: "Deployment", ResourceName: "api"}
+	ladder := testLadder()
+
+	take := func(incidentID string) *Decision {
+		decision := tracker.Plan(issue, "crashloop", ladder)
+		_, err := tracker.Record(decision, incidentID)
+		require.NoError(t, err)
+		return decision
+	}
+
+	assert.Equal(t, runtimeconfig.EscalationRestart, take("inc-1").Action)
+
+	// Each recurrence within the window takes the next step; the window doubles
+	now = now.Add(50 * time.Minute)
+	decision := take("inc-2")
+	assert.Equal(t, runtimeconfig.EscalationScale, decision.Action)
+	assert.Equal(t, 2, decision.Replicas)
+	group := tracker.Get(GroupID(issue))
+	require.NotNil(t, group)
+	assert.Equal(t, now.Add(2*time.Hour), group.WindowEnds)
+
+	now = now.Add(90 * time.Minute)
+	assert.Equal(t, runtimeconfig.EscalationPage, take("inc-3").Action)
+	now = now.Add(3 * time.Hour)
+	decision = take("inc-4")
+	assert.Equal(t, 3, decision.Step, "the last step repeats")
+	assert.Equal(t, 4, decision.Occurrence)
+
+	// Groups survive a restart
+	reloaded := NewTracker(dir)
+	group = reloaded.Get(GroupID(issue))
+	require.NotNil(t, group)
+	assert.Equal(t, []string{"inc-1", "inc-2", "inc-3", "inc-4"}, group.IncidentIDs)
+	assert.Equal(t, "deployment", group.Kind)
+
+	// After a quiet window the ladder starts over
+	now = now.Add(5 * time.Hour)
+	decision = take("inc-5")
+	assert.Equal(t, 1, decision.Step)
+	group = tracker.Get(GroupID(issue))
+	assert.Equal(t, 1, group.Occurrences)
+	assert.Equal(t, []string{"inc-5"}, group.IncidentIDs)
+
+	found, err := tracker.Reset(group.ID)
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Empty(t, tracker.List())
+}
+
+func TestStepWindow(t *testing.T) {
+	assert.Equal(t, time.Hour, stepWindow(time.Hour, 0))
+	assert.Equal(t, 4*time.Hour, stepWindow(time.Hour, 2))
+	assert.Equal(t, MaxWindow, stepWindow(time.Hour, 9))
+}
+
+func TestScaler(t *testing.T) {
+	ctx := context.Background()
+	two := int32(2)
+	controller := true
+	clientset := fake.NewSimpleClientset(
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "payments"},
+			Spec:       appsv1.DeploymentSpec{Replicas: &two},
+		},
+		&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+			Name: "api-7d9f8", Namespace: "payments",
+			OwnerReferences: []metav1.OwnerReference{{Kind: "Deployment", Name: "api", Controller: &controller}},
+		}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name: "api-7d9f8-x2x4q", Namespace: "payments",
+			OwnerReferences: []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "api-7d9f8", Controller: &controller}},
+		}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "debug", Namespace: "payments"}},
+	)
+	scaler := NewScaler(clientset)
+
+	kind, name, err := scaler.Workload(ctx, "payments", "Pod", "api-7d9f8-x2x4q")
+	require.NoError(t, err)
+	assert.Equal(t, "deployment", kind)
+	assert.Equal(t, "api", name)
+
+	from, to, err := scaler.ScaleUp(ctx, "payments", kind, name, 2)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), from)
+	assert.Equal(t, int32(4), to)
+	deployment, err := clientset.AppsV1().Deployments("payments").Get(ctx, "api", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(4), *deployment.Spec.Replicas)
+
+	_, _, err = scaler.Workload(ctx, "payments", "pod", "debug")
+	assert.ErrorIs(t, err, ErrNotScalable)
+	_, _, err = scaler.Workload(ctx, "payments", "cronjob", "report")
+	assert.ErrorIs(t, err, ErrNotScalable)
+}
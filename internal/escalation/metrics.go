@@ -0,0 +1,22 @@
+package escalation
+
+import (
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Escalations counts the escalation steps taken by action and 1-based step
+var Escalations = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "coordination_engine_escalation_steps_total",
+		Help: "Escalation ladder steps taken for recurring incidents, by action and step",
+	},
+	[]string{"action", "step"},
+)
+
+// RecordEscalation records an escalation step
+func RecordEscalation(action string, step int) {
+	Escalations.WithLabelValues(action, strconv.Itoa(step)).Inc()
+}
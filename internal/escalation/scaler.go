@@ -0,0 +1,102 @@
+package escalation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ErrNotScalable is returned when a scale step targets a resource that is neither a
+// Deployment or StatefulSet nor a pod owned by one
+var ErrNotScalable = errors.New("resource cannot be scaled")
+
+// Scaler adds replicas to the workload of a remediated resource
+type Scaler struct {
+	clientset kubernetes.Interface
+}
+
+// NewScaler creates a scaler
+func NewScaler(clientset kubernetes.Interface) *Scaler {
+	return &Scaler{clientset: clientset}
+}
+
+// ScaleUp adds replicas to a Deployment or StatefulSet and returns its replicas
+// before and after
+func (s *Scaler) ScaleUp(ctx context.Context, namespace, kind, name string, by int) (int32, int32, error) {
+	switch kind {
+	case "deployment":
+		deployments := s.clientset.AppsV1().Deployments(namespace)
+		deployment, err := deployments.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to get deployment %s/%s: %w", namespace, name, err)
+		}
+		from := replicas(deployment.Spec.Replicas)
+		to := from + int32(by) //nolint:gosec // bounded by the ladder validation
+		deployment.Spec.Replicas = &to
+		if _, err := deployments.Update(ctx, deployment, metav1.UpdateOptions{}); err != nil {
+			return 0, 0, fmt.Errorf("failed to scale deployment %s/%s: %w", namespace, name, err)
+		}
+		return from, to, nil
+	case "statefulset":
+		statefulSets := s.clientset.AppsV1().StatefulSets(namespace)
+		statefulSet, err := statefulSets.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to get statefulset %s/%s: %w", namespace, name, err)
+		}
+		from := replicas(statefulSet.Spec.Replicas)
+		to := from + int32(by) //nolint:gosec // bounded by the ladder validation
+		statefulSet.Spec.Replicas = &to
+		if _, err := statefulSets.Update(ctx, statefulSet, metav1.UpdateOptions{}); err != nil {
+			return 0, 0, fmt.Errorf("failed to scale statefulset %s/%s: %w", namespace, name, err)
+		}
+		return from, to, nil
+	}
+	return 0, 0, fmt.Errorf("%w: %s/%s", ErrNotScalable, kind, name)
+}
+
+// Workload resolves the Deployment or StatefulSet to scale for a resource (pod ->
+// ReplicaSet -> Deployment, or pod -> StatefulSet) and returns its lowercase kind and
+// name
+func (s *Scaler) Workload(ctx context.Context, namespace, kind, name string) (string, string, error) {
+	switch kind = strings.ToLower(kind); kind {
+	case "deployment", "statefulset":
+		return kind, name, nil
+	case "pod":
+	default:
+		return "", "", fmt.Errorf("%w: %s/%s", ErrNotScalable, kind, name)
+	}
+
+	pod, err := s.clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get pod %s/%s: %w", namespace, name, err)
+	}
+	for _, ref := range pod.OwnerReferences {
+		switch ref.Kind {
+		case "StatefulSet":
+			return "statefulset", ref.Name, nil
+		case "ReplicaSet":
+			rs, err := s.clientset.AppsV1().ReplicaSets(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+			if err != nil {
+				return "", "", fmt.Errorf("failed to get replicaset %s/%s: %w", namespace, ref.Name, err)
+			}
+			for _, owner := range rs.OwnerReferences {
+				if owner.Kind == "Deployment" {
+					return "deployment", owner.Name, nil
+				}
+			}
+		}
+	}
+	return "", "", fmt.Errorf("%w: pod/%s is not owned by a Deployment or StatefulSet", ErrNotScalable, name)
+}
+
+// replicas returns the desired replicas, which default to 1
+func replicas(desired *int32) int32 {
+	if desired == nil {
+		return 1
+	}
+	return *desired
+}
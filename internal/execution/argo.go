@@ -0,0 +1,119 @@
+package execution
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/runtimeconfig"
+)
+
+// argoWorkflows is the resource of Argo Workflows
+var argoWorkflows = schema.GroupVersionResource{Group: "argoproj.io", Version: "v1alpha1", Resource: "workflows"}
+
+// argoBackend renders playbooks into Argo Workflows. Each step is a template run in
+// sequence; wait steps suspend the workflow for their duration and manual steps
+// suspend it until an operator resumes it.
+type argoBackend struct {
+	opts Options
+}
+
+// Name returns "argo"
+func (b *argoBackend) Name() string { return BackendArgo }
+
+// Resource returns the Argo Workflow resource
+func (b *argoBackend) Resource() schema.GroupVersionResource { return argoWorkflows }
+
+// Render returns a Workflow running the playbook's steps one after another
+func (b *argoBackend) Render(run *Run) (*unstructured.Unstructured, error) {
+	steps := make([]interface{}, 0, len(run.Playbook.Steps))
+	templates := make([]interface{}, 0, len(run.Playbook.Steps)+1)
+	templates = append(templates, nil) // the entrypoint, filled in below
+
+	for i := range run.Playbook.Steps {
+		step := &run.Playbook.Steps[i]
+		name := stepName(i, step.Name)
+
+		template := map[string]interface{}{"name": name}
+		switch step.Action {
+		case runtimeconfig.ActionWait:
+			template["suspend"] = map[string]interface{}{"duration": step.Params["duration"]}
+		case runtimeconfig.ActionManual:
+			template["suspend"] = map[string]interface{}{}
+		case runtimeconfig.ActionNotify:
+			template["container"] = b.container("echo", []string{step.Params["message"]})
+		default:
+			args, err := command(step, run.Execution.Target)
+			if err != nil {
+				return nil, err
+			}
+			template["container"] = b.container("oc", args)
+		}
+		if timeout := stepTimeout(step); timeout > 0 {
+			template["activeDeadlineSeconds"] = int64(timeout.Seconds())
+		}
+		templates = append(templates, template)
+
+		workflowStep := map[string]interface{}{"name": name, "template": name}
+		if step.ContinueOnError {
+			workflowStep["continueOn"] = map[string]interface{}{"failed": true, "error": true}
+		}
+		// Each step is its own parallel group, so the steps run in order
+		steps = append(steps, []interface{}{workflowStep})
+	}
+	templates[0] = map[string]interface{}{"name": "playbook", "steps": steps}
+
+	spec := map[string]interface{}{
+		"entrypoint": "playbook",
+		"templates":  templates,
+	}
+	if b.opts.ServiceAccount != "" {
+		spec["serviceAccountName"] = b.opts.ServiceAccount
+	}
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": argoWorkflows.GroupVersion().String(),
+		"kind":       "Workflow",
+		"metadata":   run.objectMeta(),
+		"spec":       spec,
+	}}, nil
+}
+
+// container returns a container running command with args
+func (b *argoBackend) container(command string, args []string) map[string]interface{} {
+	return map[string]interface{}{
+		"image":   b.opts.Image,
+		"command": []interface{}{command},
+		"args":    toInterfaces(args),
+	}
+}
+
+// Status maps the workflow phase: Pending, Running, Succeeded, Failed or Error
+func (b *argoBackend) Status(obj *unstructured.Unstructured) (Phase, string) {
+	phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase")
+	message, _, _ := unstructured.NestedString(obj.Object, "status", "message")
+	switch phase {
+	case "", "Pending":
+		return PhasePending, message
+	case "Running":
+		return PhaseRunning, message
+	case "Succeeded":
+		return PhaseSucceeded, message
+	case "Failed", "Error":
+		if message == "" {
+			message = fmt.Sprintf("workflow %s", phase)
+		}
+		return PhaseFailed, message
+	default:
+		return PhaseRunning, message
+	}
+}
+
+// toInterfaces converts strings for use in unstructured objects
+func toInterfaces(values []string) []interface{} {
+	converted := make([]interface{}, len(values))
+	for i, v := range values {
+		converted[i] = v
+	}
+	return converted
+}
@@ -0,0 +1,188 @@
+package execution
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/runtimeconfig"
+)
+
+// Backend names
+const (
+	BackendArgo   = "argo"
+	BackendTekton = "tekton"
+)
+
+// Defaults for rendered runs
+const (
+	// DefaultRunnerImage provides the oc binary the steps run
+	DefaultRunnerImage = "registry.redhat.io/openshift4/ose-cli:latest"
+
+	// DefaultArgoCDNamespace is where sync_argocd steps look for the Application
+	DefaultArgoCDNamespace = "openshift-gitops"
+)
+
+// Labels and annotations set on submitted objects
+const (
+	LabelManagedBy     = "app.kubernetes.io/managed-by"
+	LabelExecutionID   = "coordination-engine.openshift.io/execution-id"
+	LabelPlaybook      = "coordination-engine.openshift.io/playbook"
+	AnnotationIncident = "coordination-engine.openshift.io/incident-id"
+	AnnotationTarget   = "coordination-engine.openshift.io/target"
+
+	managedBy = "coordination-engine"
+)
+
+// ErrUnsupported is returned when a playbook cannot be rendered for a backend
+var ErrUnsupported = errors.New("playbook not supported by execution backend")
+
+// Backend renders playbooks into objects of a workflow engine and reads their status
+type Backend interface {
+	// Name returns the backend name
+	Name() string
+
+	// Resource returns the resource of the objects the backend submits
+	Resource() schema.GroupVersionResource
+
+	// Render returns the object that runs the playbook
+	Render(run *Run) (*unstructured.Unstructured, error)
+
+	// Status returns the phase of a submitted object and a message explaining it
+	Status(obj *unstructured.Unstructured) (Phase, string)
+}
+
+// Options configure how runs are rendered
+type Options struct {
+	// ServiceAccount runs the steps; empty uses the workflow engine's default
+	ServiceAccount string
+
+	// Image provides the oc binary; empty uses DefaultRunnerImage
+	Image string
+}
+
+// NewBackend returns the backend with the given name
+func NewBackend(name string, opts Options) (Backend, error) {
+	if opts.Image == "" {
+		opts.Image = DefaultRunnerImage
+	}
+	switch name {
+	case BackendArgo:
+		return &argoBackend{opts: opts}, nil
+	case BackendTekton:
+		return &tektonBackend{opts: opts}, nil
+	default:
+		return nil, fmt.Errorf("unknown execution backend %q (use %s or %s)", name, BackendArgo, BackendTekton)
+	}
+}
+
+// Run is a playbook resolved for an execution
+type Run struct {
+	Execution *Execution
+	Playbook  *runtimeconfig.Playbook
+}
+
+// objectMeta returns the metadata of a run's object
+func (r *Run) objectMeta() map[string]interface{} {
+	exec := r.Execution
+	annotations := map[string]interface{}{
+		AnnotationTarget: fmt.Sprintf("%s/%s/%s", exec.Target.Namespace, exec.Target.Kind, exec.Target.Name),
+	}
+	if exec.IncidentID != "" {
+		annotations[AnnotationIncident] = exec.IncidentID
+	}
+	return map[string]interface{}{
+		"name":      exec.Object.Name,
+		"namespace": exec.Object.Namespace,
+		"labels": map[string]interface{}{
+			LabelManagedBy:   managedBy,
+			LabelExecutionID: exec.ID,
+			LabelPlaybook:    exec.Playbook,
+		},
+		"annotations": annotations,
+	}
+}
+
+// resourceKinds maps the kinds a playbook can target to their oc resource names
+var resourceKinds = map[string]string{
+	"pod":         "pod",
+	"deployment":  "deployment",
+	"statefulset": "statefulset",
+	"daemonset":   "daemonset",
+}
+
+// normalizeKind lowercases a resource kind
+func normalizeKind(kind string) string {
+	return strings.ToLower(kind)
+}
+
+// command returns the oc arguments that run a cluster action against target
+func command(step *runtimeconfig.PlaybookStep, target Target) ([]string, error) {
+	kind := resourceKinds[normalizeKind(target.Kind)]
+	workload := kind + "/" + target.Name
+	switch step.Action {
+	case runtimeconfig.ActionRestartPod:
+		if selector := step.Params["selector"]; selector != "" {
+			return []string{"delete", "pods", "-n", target.Namespace, "-l", selector}, nil
+		}
+		if kind == "pod" {
+			return []string{"delete", "pod", target.Name, "-n", target.Namespace}, nil
+		}
+		return []string{"delete", "pods", "-n", target.Namespace, "-l", "app=" + target.Name}, nil
+	case runtimeconfig.ActionRolloutRestart, runtimeconfig.ActionRollback:
+		if kind == "pod" {
+			return nil, fmt.Errorf("%w: step %s: %s needs a workload, not a pod", ErrUnsupported, step.Name, step.Action)
+		}
+		verb := "restart"
+		if step.Action == runtimeconfig.ActionRollback {
+			verb = "undo"
+		}
+		return []string{"rollout", verb, workload, "-n", target.Namespace}, nil
+	case runtimeconfig.ActionScale:
+		if kind == "pod" || kind == "daemonset" {
+			return nil, fmt.Errorf("%w: step %s: a %s cannot be scaled", ErrUnsupported, step.Name, kind)
+		}
+		return []string{"scale", workload, "--replicas=" + step.Params["replicas"], "-n", target.Namespace}, nil
+	case runtimeconfig.ActionSyncArgoCD:
+		application := step.Params["application"]
+		if application == "" {
+			application = target.Name
+		}
+		namespace := step.Params["namespace"]
+		if namespace == "" {
+			namespace = DefaultArgoCDNamespace
+		}
+		return []string{
+			"patch", "applications.argoproj.io", application, "-n", namespace, "--type", "merge",
+			"-p", `{"operation":{"initiatedBy":{"username":"` + managedBy + `"},"sync":{}}}`,
+		}, nil
+	default:
+		return nil, fmt.Errorf("%w: step %s: action %s", ErrUnsupported, step.Name, step.Action)
+	}
+}
+
+// invalidNameChars matches the characters not allowed in step and template names
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// stepName returns a DNS label naming the i-th step, unique within the playbook
+func stepName(i int, name string) string {
+	label := fmt.Sprintf("%02d-%s", i+1, invalidNameChars.ReplaceAllString(strings.ToLower(name), "-"))
+	if len(label) > 63 {
+		label = label[:63]
+	}
+	return strings.TrimRight(label, "-")
+}
+
+// stepTimeout returns a step's timeout, or zero if it has none
+func stepTimeout(step *runtimeconfig.PlaybookStep) time.Duration {
+	timeout, err := time.ParseDuration(step.Timeout)
+	if err != nil {
+		return 0
+	}
+	return timeout
+}
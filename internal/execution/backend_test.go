@@ -0,0 +1,162 @@
+package execution
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/runtimeconfig"
+)
+
+func testRun(steps ...runtimeconfig.PlaybookStep) *Run {
+	return &Run{
+		Execution: &Execution{
+			ID:         "exec-1a2b3c4d",
+			Playbook:   "crashloop",
+			IncidentID: "inc-1",
+			Target:     Target{Namespace: "payments", Kind: "deployment", Name: "api"},
+			Object:     ObjectRef{Namespace: "remediation", Name: "crashloop-1a2b3c4d"},
+		},
+		Playbook: &runtimeconfig.Playbook{Steps: steps},
+	}
+}
+
+var testSteps = []runtimeconfig.PlaybookStep{
+	{Name: "Restart", Action: runtimeconfig.ActionRolloutRestart, Timeout: "5m"},
+	{Name: "settle", Action: runtimeconfig.ActionWait, Params: map[string]string{"duration": "2m"}},
+	{Name: "scale", Action: runtimeconfig.ActionScale, Params: map[string]string{"replicas": "3"}, ContinueOnError: true},
+	{Name: "tell", Action: runtimeconfig.ActionNotify, Params: map[string]string{"message": "api restarted; $(reboot)"}},
+}
+
+func TestArgoBackend_Render(t *testing.T) {
+	backend, err := NewBackend(BackendArgo, Options{ServiceAccount: "remediator"})
+	require.NoError(t, err)
+
+	obj, err := backend.Render(testRun(append(testSteps, runtimeconfig.PlaybookStep{Name: "approve", Action: runtimeconfig.ActionManual})...))
+	require.NoError(t, err)
+	assert.Equal(t, "argoproj.io/v1alpha1", obj.GetAPIVersion())
+	assert.Equal(t, "Workflow", obj.GetKind())
+	assert.Equal(t, "remediation", obj.GetNamespace())
+	assert.Equal(t, "exec-1a2b3c4d", obj.GetLabels()[LabelExecutionID])
+	assert.Equal(t, "payments/deployment/api", obj.GetAnnotations()[AnnotationTarget])
+
+	spec := obj.Object["spec"].(map[string]interface{})
+	assert.Equal(t, "remediator", spec["serviceAccountName"])
+	templates := spec["templates"].([]interface{})
+	require.Len(t, templates, 6)
+
+	// The entrypoint runs the steps one after another
+	steps := templates[0].(map[string]interface{})["steps"].([]interface{})
+	require.Len(t, steps, 5)
+	assert.Equal(t, []interface{}{map[string]interface{}{"name": "01-restart", "template": "01-restart"}}, steps[0])
+	assert.Equal(t, map[string]interface{}{"failed": true, "error": true}, steps[2].([]interface{})[0].(map[string]interface{})["continueOn"])
+
+	restart := templates[1].(map[string]interface{})
+	assert.Equal(t, int64(300), restart["activeDeadlineSeconds"])
+	assert.Equal(t, DefaultRunnerImage, restart["container"].(map[string]interface{})["image"])
+	assert.Equal(t, []interface{}{"rollout", "restart", "deployment/api", "-n", "payments"}, restart["container"].(map[string]interface{})["args"])
+	assert.Equal(t, map[string]interface{}{"duration": "2m"}, templates[2].(map[string]interface{})["suspend"])
+	assert.Equal(t, []interface{}{"api restarted; $(reboot)"}, templates[4].(map[string]interface{})["container"].(map[string]interface{})["args"],
+		"messages are passed as arguments, not run by a shell")
+	assert.Equal(t, map[string]interface{}{}, templates[5].(map[string]interface{})["suspend"], "manual steps wait for an operator")
+
+	// Objects must survive the deep copies made by clients
+	assert.NotPanics(t, func() { obj.DeepCopy() })
+}
+
+func TestTektonBackend_Render(t *testing.T) {
+	backend, err := NewBackend(BackendTekton, Options{Image: "quay.io/example/oc:4.16"})
+	require.NoError(t, err)
+
+	obj, err := backend.Render(testRun(testSteps...))
+	require.NoError(t, err)
+	assert.Equal(t, "tekton.dev/v1", obj.GetAPIVersion())
+	assert.Equal(t, "PipelineRun", obj.GetKind())
+
+	tasks, _, _ := unstructured.NestedSlice(obj.Object, "spec", "pipelineSpec", "tasks")
+	require.Len(t, tasks, 1)
+	steps, _, _ := unstructured.NestedSlice(tasks[0].(map[string]interface{}), "taskSpec", "steps")
+	require.Len(t, steps, 4)
+	assert.Equal(t, map[string]interface{}{
+		"name":    "01-restart",
+		"image":   "quay.io/example/oc:4.16",
+		"command": []interface{}{"oc"},
+		"args":    []interface{}{"rollout", "restart", "deployment/api", "-n", "payments"},
+		"timeout": "5m0s",
+	}, steps[0])
+	assert.Equal(t, []interface{}{"sleep"}, steps[1].(map[string]interface{})["command"])
+	assert.Equal(t, []interface{}{"120"}, steps[1].(map[string]interface{})["args"])
+	assert.Equal(t, "continue", steps[2].(map[string]interface{})["onError"])
+	assert.NotPanics(t, func() { obj.DeepCopy() })
+
+	_, err = backend.Render(testRun(runtimeconfig.PlaybookStep{Name: "approve", Action: runtimeconfig.ActionManual}))
+	assert.ErrorIs(t, err, ErrUnsupported)
+}
+
+func TestCommand(t *testing.T) {
+	pod := Target{Namespace: "payments", Kind: "pod", Name: "api-7d9f"}
+	deployment := Target{Namespace: "payments", Kind: "deployment", Name: "api"}
+
+	tests := []struct {
+		name   string
+		step   runtimeconfig.PlaybookStep
+		target Target
+		want   []string
+	}{
+		{"restart pod", runtimeconfig.PlaybookStep{Action: runtimeconfig.ActionRestartPod}, pod, []string{"delete", "pod", "api-7d9f", "-n", "payments"}},
+		{"restart workload pods", runtimeconfig.PlaybookStep{Action: runtimeconfig.ActionRestartPod}, deployment, []string{"delete", "pods", "-n", "payments", "-l", "app=api"}},
+		{"restart by selector", runtimeconfig.PlaybookStep{Action: runtimeconfig.ActionRestartPod, Params: map[string]string{"selector": "tier=web"}}, deployment, []string{"delete", "pods", "-n", "payments", "-l", "tier=web"}},
+		{"rollback", runtimeconfig.PlaybookStep{Action: runtimeconfig.ActionRollback}, deployment, []string{"rollout", "undo", "deployment/api", "-n", "payments"}},
+		{"sync argocd", runtimeconfig.PlaybookStep{Action: runtimeconfig.ActionSyncArgoCD, Params: map[string]string{"application": "payments-api"}}, deployment,
+			[]string{"patch", "applications.argoproj.io", "payments-api", "-n", "openshift-gitops", "--type", "merge", "-p", `{"operation":{"initiatedBy":{"username":"coordination-engine"},"sync":{}}}`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := command(&tt.step, tt.target)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err := command(&runtimeconfig.PlaybookStep{Name: "scale", Action: runtimeconfig.ActionScale, Params: map[string]string{"replicas": "2"}}, pod)
+	assert.ErrorIs(t, err, ErrUnsupported)
+}
+
+func TestBackend_Status(t *testing.T) {
+	argo, _ := NewBackend(BackendArgo, Options{})
+	workflow := func(phase, message string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"status": map[string]interface{}{"phase": phase, "message": message},
+		}}
+	}
+	phase, _ := argo.Status(&unstructured.Unstructured{Object: map[string]interface{}{}})
+	assert.Equal(t, PhasePending, phase)
+	phase, _ = argo.Status(workflow("Running", ""))
+	assert.Equal(t, PhaseRunning, phase)
+	phase, message := argo.Status(workflow("Error", ""))
+	assert.Equal(t, PhaseFailed, phase)
+	assert.Equal(t, "workflow Error", message)
+
+	tekton, _ := NewBackend(BackendTekton, Options{})
+	pipelineRun := func(status, reason, message string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"status": map[string]interface{}{"conditions": []interface{}{
+				map[string]interface{}{"type": "Succeeded", "status": status, "reason": reason, "message": message},
+			}},
+		}}
+	}
+	phase, _ = tekton.Status(pipelineRun("Unknown", "PipelineRunPending", ""))
+	assert.Equal(t, PhasePending, phase)
+	phase, _ = tekton.Status(pipelineRun("Unknown", "Running", "Tasks Completed: 0"))
+	assert.Equal(t, PhaseRunning, phase)
+	phase, _ = tekton.Status(pipelineRun("True", "Succeeded", "Tasks Completed: 1"))
+	assert.Equal(t, PhaseSucceeded, phase)
+	phase, message = tekton.Status(pipelineRun("False", "Failed", "Tasks Completed: 1 (Failed: 1)"))
+	assert.Equal(t, PhaseFailed, phase)
+	assert.Equal(t, "Tasks Completed: 1 (Failed: 1)", message)
+
+	_, err := NewBackend("jenkins", Options{})
+	assert.Error(t, err)
+}
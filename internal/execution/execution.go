@@ -0,0 +1,105 @@
+// Package execution runs remediation playbooks in an external workflow engine.
+//
+// Organizations that require remediation to run in their own audited workflow engine
+// configure an execution backend. A playbook is then rendered into an Argo Workflow or
+// a Tekton PipelineRun, submitted to the cluster, and its status is tracked until the
+// run finishes. Every step runs as a container invoking oc, so the run's logs, retries
+// and RBAC stay in the workflow engine.
+package execution
+
+import (
+	"fmt"
+	"time"
+
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/api/validation"
+)
+
+// Phase is the state of a playbook execution
+type Phase string
+
+// Execution phases
+const (
+	PhasePending   Phase = "pending"
+	PhaseRunning   Phase = "running"
+	PhaseSucceeded Phase = "succeeded"
+	PhaseFailed    Phase = "failed"
+)
+
+// Finished reports whether the phase is final
+func (p Phase) Finished() bool {
+	return p == PhaseSucceeded || p == PhaseFailed
+}
+
+// Target is the resource a playbook remediates
+type Target struct {
+	Namespace string `json:"namespace"`
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+}
+
+// ObjectRef identifies the object submitted to the workflow engine
+type ObjectRef struct {
+	APIVersion string `json:"api_version"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace"`
+	Name       string `json:"name"`
+}
+
+// Execution is a playbook run submitted to a workflow engine
+type Execution struct {
+	ID         string    `json:"id"`
+	Backend    string    `json:"backend"`
+	Playbook   string    `json:"playbook"`
+	IncidentID string    `json:"incident_id,omitempty"`
+	IssueType  string    `json:"issue_type,omitempty"`
+	Target     Target    `json:"target"`
+	Object     ObjectRef `json:"object"`
+
+	Phase   Phase  `json:"phase"`
+	Message string `json:"message,omitempty"`
+
+	SubmittedAt time.Time  `json:"submitted_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+}
+
+// Request asks for a playbook to be run against a resource
+type Request struct {
+	// Playbook names the playbook to run. If empty, the first playbook listing the
+	// issue type is run.
+	Playbook   string `json:"playbook,omitempty"`
+	IncidentID string `json:"incident_id,omitempty"`
+	IssueType  string `json:"issue_type,omitempty"`
+	Target     Target `json:"target"`
+}
+
+// Validate checks the request. Target fields become arguments of oc commands, so
+// they must be valid Kubernetes names.
+func (r *Request) Validate() error {
+	var errs validation.Errors
+	if r.Playbook == "" && r.IssueType == "" {
+		errs.Add("playbook", validation.ConstraintRequired, nil, "playbook or issue_type is required")
+	}
+	checkName(&errs, "target.namespace", r.Target.Namespace, k8svalidation.IsDNS1123Label)
+	checkName(&errs, "target.kind", r.Target.Kind, func(kind string) []string {
+		if _, ok := resourceKinds[normalizeKind(kind)]; !ok {
+			return []string{"must be one of Pod, Deployment, StatefulSet or DaemonSet"}
+		}
+		return nil
+	})
+	checkName(&errs, "target.name", r.Target.Name, k8svalidation.IsDNS1123Subdomain)
+	return errs.Err()
+}
+
+// checkName adds an error if value is empty or rejected by check
+func checkName(errs *validation.Errors, field, value string, check func(string) []string) {
+	if value == "" {
+		errs.Add(field, validation.ConstraintRequired, nil, field+" is required")
+		return
+	}
+	if problems := check(value); len(problems) > 0 {
+		errs.Add(field, validation.ConstraintFormat, value, fmt.Sprintf("%s %q is invalid: %s", field, value, problems[0]))
+	}
+}
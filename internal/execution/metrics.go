@@ -0,0 +1,21 @@
+package execution
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// PlaybookExecutions counts playbook executions by backend and outcome: submitted,
+// submit_failed, succeeded or failed
+var PlaybookExecutions = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "coordination_engine_playbook_executions_total",
+		Help: "Playbook executions submitted to external workflow engines, by outcome",
+	},
+	[]string{"backend", "outcome"},
+)
+
+// RecordExecution records a playbook execution outcome
+func RecordExecution(backend, outcome string) {
+	PlaybookExecutions.WithLabelValues(backend, outcome).Inc()
+}
@@ -0,0 +1,227 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/runtimeconfig"
+)
+
+// DefaultPollInterval is how often the status of unfinished executions is read
+const DefaultPollInterval = 15 * time.Second
+
+var (
+	// ErrPlaybookNotFound is returned when the requested playbook does not exist
+	ErrPlaybookNotFound = errors.New("playbook not found")
+
+	// ErrNoPlaybook is returned when no playbook lists the requested issue type
+	ErrNoPlaybook = errors.New("no playbook for issue type")
+)
+
+// Playbooks looks up playbooks (implemented by runtimeconfig.Store)
+type Playbooks interface {
+	Playbook(name string) (*runtimeconfig.Playbook, error)
+	PlaybookFor(issueType string) (string, *runtimeconfig.Playbook)
+}
+
+// Runner submits playbook runs to the execution backend and tracks their status
+type Runner struct {
+	backend   Backend
+	client    dynamic.Interface
+	playbooks Playbooks
+	store     *Store
+	namespace string
+	log       *logrus.Logger
+
+	// now is replaceable in tests
+	now func() time.Time
+}
+
+// NewRunner creates a runner submitting to namespace
+func NewRunner(backend Backend, client dynamic.Interface, playbooks Playbooks, store *Store, namespace string, log *logrus.Logger) *Runner {
+	return &Runner{
+		backend:   backend,
+		client:    client,
+		playbooks: playbooks,
+		store:     store,
+		namespace: namespace,
+		log:       log,
+		now:       time.Now,
+	}
+}
+
+// Backend returns the name of the execution backend
+func (r *Runner) Backend() string {
+	return r.backend.Name()
+}
+
+// Render resolves the request's playbook and returns the execution it would create
+// and the object that would be submitted, without submitting it
+func (r *Runner) Render(req *Request) (*Execution, *unstructured.Unstructured, error) {
+	if err := req.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	name, playbook := req.Playbook, (*runtimeconfig.Playbook)(nil)
+	if name != "" {
+		var err error
+		if playbook, err = r.playbooks.Playbook(name); err != nil {
+			return nil, nil, fmt.Errorf("%w: %s", ErrPlaybookNotFound, name)
+		}
+	} else if name, playbook = r.playbooks.PlaybookFor(req.IssueType); playbook == nil {
+		return nil, nil, fmt.Errorf("%w: %s", ErrNoPlaybook, req.IssueType)
+	}
+
+	id := "exec-" + uuid.New().String()[:8]
+	exec := &Execution{
+		ID:         id,
+		Backend:    r.backend.Name(),
+		Playbook:   name,
+		IncidentID: req.IncidentID,
+		IssueType:  req.IssueType,
+		Target:     req.Target,
+		Object:     ObjectRef{Namespace: r.namespace, Name: objectName(name, id)},
+		Phase:      PhasePending,
+	}
+	exec.Target.Kind = normalizeKind(exec.Target.Kind)
+
+	obj, err := r.backend.Render(&Run{Execution: exec, Playbook: playbook})
+	if err != nil {
+		return nil, nil, err
+	}
+	exec.Object.APIVersion, exec.Object.Kind = obj.GetAPIVersion(), obj.GetKind()
+	return exec, obj, nil
+}
+
+// Run renders the request's playbook and submits it to the workflow engine
+func (r *Runner) Run(ctx context.Context, req *Request) (*Execution, error) {
+	exec, obj, err := r.Render(req)
+	if err != nil {
+		return nil, err
+	}
+	return r.Submit(ctx, exec, obj)
+}
+
+// Submit submits an object returned by Render and starts tracking its execution
+func (r *Runner) Submit(ctx context.Context, exec *Execution, obj *unstructured.Unstructured) (*Execution, error) {
+	created, err := r.client.Resource(r.backend.Resource()).Namespace(r.namespace).Create(ctx, obj, metav1.CreateOptions{})
+	if err != nil {
+		RecordExecution(r.backend.Name(), "submit_failed")
+		return nil, fmt.Errorf("failed to submit %s %s: %w", obj.GetKind(), obj.GetName(), err)
+	}
+
+	now := r.now()
+	exec.SubmittedAt, exec.UpdatedAt = now, now
+	exec.Phase, exec.Message = r.backend.Status(created)
+	if err := r.store.Put(exec); err != nil {
+		r.log.WithError(err).WithField("execution_id", exec.ID).Warn("Failed to persist playbook execution")
+	}
+	RecordExecution(r.backend.Name(), "submitted")
+
+	r.log.WithFields(logrus.Fields{
+		"execution_id": exec.ID,
+		"playbook":     exec.Playbook,
+		"incident_id":  exec.IncidentID,
+		"object":       exec.Object.Kind + "/" + exec.Object.Name,
+	}).Info("Submitted playbook execution")
+	return exec, nil
+}
+
+// Get returns an execution, or nil if it is unknown
+func (r *Runner) Get(id string) *Execution {
+	return r.store.Get(id)
+}
+
+// List returns the executions, newest first
+func (r *Runner) List() []*Execution {
+	return r.store.List()
+}
+
+// Refresh reads the status of unfinished executions from the workflow engine and
+// returns how many changed. Executions whose object was deleted fail.
+func (r *Runner) Refresh(ctx context.Context) int {
+	changed := 0
+	for _, exec := range r.store.List() {
+		if exec.Phase.Finished() {
+			continue
+		}
+
+		phase, message := PhaseFailed, "object was deleted from the cluster"
+		obj, err := r.client.Resource(r.backend.Resource()).Namespace(exec.Object.Namespace).Get(ctx, exec.Object.Name, metav1.GetOptions{})
+		switch {
+		case err == nil:
+			phase, message = r.backend.Status(obj)
+		case !apierrors.IsNotFound(err):
+			r.log.WithError(err).WithField("execution_id", exec.ID).Debug("Failed to read playbook execution status")
+			continue
+		}
+		if phase == exec.Phase && message == exec.Message {
+			continue
+		}
+
+		now := r.now()
+		exec.Phase, exec.Message, exec.UpdatedAt = phase, message, now
+		if phase.Finished() {
+			exec.FinishedAt = &now
+			RecordExecution(r.backend.Name(), string(phase))
+		}
+		if err := r.store.Put(exec); err != nil {
+			r.log.WithError(err).WithField("execution_id", exec.ID).Warn("Failed to persist playbook execution")
+		}
+		changed++
+
+		r.log.WithFields(logrus.Fields{
+			"execution_id": exec.ID,
+			"phase":        phase,
+			"message":      message,
+		}).Info("Playbook execution status changed")
+	}
+	return changed
+}
+
+// Start refreshes the status of unfinished executions every interval until ctx is
+// cancelled
+func (r *Runner) Start(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				r.Refresh(ctx)
+			}
+		}
+	}()
+
+	r.log.WithFields(logrus.Fields{
+		"backend":   r.backend.Name(),
+		"namespace": r.namespace,
+		"interval":  interval,
+	}).Info("Playbook execution tracking started")
+}
+
+// objectName returns the name of the submitted object: the playbook name, shortened to
+// fit, and the execution ID
+func objectName(playbook, id string) string {
+	prefix := invalidNameChars.ReplaceAllString(strings.ToLower(playbook), "-")
+	if len(prefix) > 40 {
+		prefix = prefix[:40]
+	}
+	return strings.Trim(prefix, "-") + "-" + strings.TrimPrefix(id, "exec-")
+}
@@ -0,0 +1,102 @@
+package execution
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic/fake"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/runtimeconfig"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/api/validation"
+)
+
+func testRunner(t *testing.T, dataDir string) (*Runner, *fake.FakeDynamicClient) {
+	t.Helper()
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	playbooks := runtimeconfig.NewStore(t.TempDir())
+	_, err := playbooks.Create(runtimeconfig.KindPlaybook, "crashloop", json.RawMessage(
+		`{"issue_types":["pod_crash_loop"],"steps":[{"name":"restart","action":"rollout_restart","timeout":"5m"}]}`))
+	require.NoError(t, err)
+
+	client := fake.NewSimpleDynamicClient(runtime.NewScheme())
+	backend, err := NewBackend(BackendArgo, Options{})
+	require.NoError(t, err)
+	return NewRunner(backend, client, playbooks, NewStore(dataDir), "remediation", log), client
+}
+
+func TestRunner_RunAndTrack(t *testing.T) {
+	dir := t.TempDir()
+	runner, client := testRunner(t, dir)
+	ctx := context.Background()
+
+	// Without a playbook name, the playbook listing the issue type runs
+	exec, err := runner.Run(ctx, &Request{
+		IncidentID: "inc-1",
+		IssueType:  "pod_crash_loop",
+		Target:     Target{Namespace: "payments", Kind: "Deployment", Name: "api"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "crashloop", exec.Playbook)
+	assert.Equal(t, "deployment", exec.Target.Kind)
+	assert.Equal(t, PhasePending, exec.Phase)
+	assert.Equal(t, ObjectRef{APIVersion: "argoproj.io/v1alpha1", Kind: "Workflow", Namespace: "remediation", Name: "crashloop-" + exec.ID[len("exec-"):]}, exec.Object)
+
+	workflows := client.Resource(argoWorkflows).Namespace("remediation")
+	obj, err := workflows.Get(ctx, exec.Object.Name, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "inc-1", obj.GetAnnotations()[AnnotationIncident])
+
+	// The workflow engine reports progress
+	require.NoError(t, unstructured.SetNestedField(obj.Object, "Failed", "status", "phase"))
+	require.NoError(t, unstructured.SetNestedField(obj.Object, "child 01-restart failed", "status", "message"))
+	_, err = workflows.Update(ctx, obj, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, runner.Refresh(ctx))
+	assert.Equal(t, 0, runner.Refresh(ctx), "finished executions are not read again")
+	updated := runner.Get(exec.ID)
+	assert.Equal(t, PhaseFailed, updated.Phase)
+	assert.Equal(t, "child 01-restart failed", updated.Message)
+	assert.NotNil(t, updated.FinishedAt)
+
+	// Executions survive a restart
+	assert.Len(t, NewStore(dir).List(), 1)
+}
+
+func TestRunner_Refresh_DeletedObject(t *testing.T) {
+	runner, client := testRunner(t, t.TempDir())
+	ctx := context.Background()
+
+	exec, err := runner.Run(ctx, &Request{Playbook: "crashloop", Target: Target{Namespace: "payments", Kind: "deployment", Name: "api"}})
+	require.NoError(t, err)
+	require.NoError(t, client.Resource(argoWorkflows).Namespace("remediation").Delete(ctx, exec.Object.Name, metav1.DeleteOptions{}))
+
+	assert.Equal(t, 1, runner.Refresh(ctx))
+	assert.Equal(t, PhaseFailed, runner.Get(exec.ID).Phase)
+}
+
+func TestRunner_Render_Errors(t *testing.T) {
+	runner, _ := testRunner(t, t.TempDir())
+	target := Target{Namespace: "payments", Kind: "deployment", Name: "api"}
+
+	_, _, err := runner.Render(&Request{Playbook: "missing", Target: target})
+	assert.ErrorIs(t, err, ErrPlaybookNotFound)
+	_, _, err = runner.Render(&Request{IssueType: "oom_killed", Target: target})
+	assert.ErrorIs(t, err, ErrNoPlaybook)
+
+	_, _, err = runner.Render(&Request{Playbook: "crashloop", Target: Target{Namespace: "payments", Kind: "cronjob", Name: "--all"}})
+	fields := validation.Fields(err)
+	require.Len(t, fields, 2)
+	assert.Equal(t, "target.kind", fields[0].Field)
+	assert.Equal(t, "target.name", fields[1].Field)
+	assert.Empty(t, runner.List(), "rendering does not create executions")
+}
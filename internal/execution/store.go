@@ -0,0 +1,156 @@
+package execution
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// DefaultMaxExecutions is the number of executions kept; the oldest finished ones are
+// dropped first
+const DefaultMaxExecutions = 1000
+
+// Store keeps executions in memory, persisted as JSON in playbook_executions.json
+type Store struct {
+	executions map[string]*Execution
+	limit      int
+	mu         sync.RWMutex
+	dataFile   string
+}
+
+// NewStore creates a store in dataDir (DATA_DIR or /app/data if empty) and loads the
+// executions recorded before a restart
+func NewStore(dataDir string) *Store {
+	if dataDir == "" {
+		dataDir = os.Getenv("DATA_DIR")
+	}
+	if dataDir == "" {
+		dataDir = "/app/data"
+	}
+
+	if err := os.MkdirAll(dataDir, 0o755); err != nil {
+		fmt.Printf("Warning: Could not create data directory %s: %v\n", dataDir, err)
+	}
+	store := &Store{
+		executions: make(map[string]*Execution),
+		limit:      DefaultMaxExecutions,
+		dataFile:   filepath.Join(dataDir, "playbook_executions.json"),
+	}
+
+	if err := store.load(); err != nil {
+		fmt.Printf("Warning: Could not load playbook executions from disk: %v\n", err)
+	} else {
+		fmt.Printf("Loaded %d playbook executions from %s\n", len(store.executions), store.dataFile)
+	}
+
+	return store
+}
+
+// load reads the executions from the data file
+func (s *Store) load() error {
+	data, err := os.ReadFile(s.dataFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read data file: %w", err)
+	}
+
+	var executions []*Execution
+	if err := json.Unmarshal(data, &executions); err != nil {
+		return fmt.Errorf("failed to unmarshal executions: %w", err)
+	}
+	for _, exec := range executions {
+		s.executions[exec.ID] = exec
+	}
+	return nil
+}
+
+// save writes the executions to the data file. Callers must hold s.mu.
+func (s *Store) save() error {
+	data, err := json.MarshalIndent(s.sorted(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal executions: %w", err)
+	}
+
+	// Write to temp file first, then rename (atomic)
+	tmpFile := s.dataFile + ".tmp"
+	if err := os.WriteFile(tmpFile, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write temp file: %w", err)
+	}
+	if err := os.Rename(tmpFile, s.dataFile); err != nil {
+		return fmt.Errorf("failed to rename temp file: %w", err)
+	}
+	return nil
+}
+
+// sorted returns the executions, newest first. Callers must hold s.mu.
+func (s *Store) sorted() []*Execution {
+	executions := make([]*Execution, 0, len(s.executions))
+	for _, exec := range s.executions {
+		executions = append(executions, exec)
+	}
+	sort.Slice(executions, func(i, j int) bool {
+		if executions[i].SubmittedAt.Equal(executions[j].SubmittedAt) {
+			return executions[i].ID > executions[j].ID
+		}
+		return executions[i].SubmittedAt.After(executions[j].SubmittedAt)
+	})
+	return executions
+}
+
+// Put adds or replaces an execution. The execution is kept in memory even if it
+// cannot be persisted.
+func (s *Store) Put(exec *Execution) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	copied := *exec
+	s.executions[exec.ID] = &copied
+	s.prune()
+	return s.save()
+}
+
+// prune drops the oldest finished executions beyond the limit. Callers must hold s.mu.
+func (s *Store) prune() {
+	excess := len(s.executions) - s.limit
+	if excess <= 0 {
+		return
+	}
+	sorted := s.sorted()
+	for i := len(sorted) - 1; i >= 0 && excess > 0; i-- {
+		if sorted[i].Phase.Finished() {
+			delete(s.executions, sorted[i].ID)
+			excess--
+		}
+	}
+}
+
+// Get returns a copy of an execution, or nil if it is unknown
+func (s *Store) Get(id string) *Execution {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	exec, ok := s.executions[id]
+	if !ok {
+		return nil
+	}
+	copied := *exec
+	return &copied
+}
+
+// List returns copies of the executions, newest first
+func (s *Store) List() []*Execution {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sorted := s.sorted()
+	for i, exec := range sorted {
+		copied := *exec
+		sorted[i] = &copied
+	}
+	return sorted
+}
@@ -0,0 +1,121 @@
+package execution
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/runtimeconfig"
+)
+
+// tektonPipelineRuns is the resource of Tekton PipelineRuns
+var tektonPipelineRuns = schema.GroupVersionResource{Group: "tekton.dev", Version: "v1", Resource: "pipelineruns"}
+
+// tektonBackend renders playbooks into Tekton PipelineRuns with an embedded pipeline
+// of a single task, whose steps run the playbook's steps in order. PipelineRuns cannot
+// pause for an operator, so playbooks with manual steps are not supported.
+type tektonBackend struct {
+	opts Options
+}
+
+// Name returns "tekton"
+func (b *tektonBackend) Name() string { return BackendTekton }
+
+// Resource returns the Tekton PipelineRun resource
+func (b *tektonBackend) Resource() schema.GroupVersionResource { return tektonPipelineRuns }
+
+// Render returns a PipelineRun running the playbook's steps one after another
+func (b *tektonBackend) Render(run *Run) (*unstructured.Unstructured, error) {
+	steps := make([]interface{}, 0, len(run.Playbook.Steps))
+	for i := range run.Playbook.Steps {
+		step := &run.Playbook.Steps[i]
+
+		var cmd string
+		var args []string
+		switch step.Action {
+		case runtimeconfig.ActionWait:
+			duration, err := time.ParseDuration(step.Params["duration"])
+			if err != nil {
+				return nil, fmt.Errorf("%w: step %s: invalid duration %q", ErrUnsupported, step.Name, step.Params["duration"])
+			}
+			cmd, args = "sleep", []string{strconv.FormatInt(int64(duration.Seconds()), 10)}
+		case runtimeconfig.ActionManual:
+			return nil, fmt.Errorf("%w: step %s: tekton cannot pause for manual steps, use the argo backend", ErrUnsupported, step.Name)
+		case runtimeconfig.ActionNotify:
+			cmd, args = "echo", []string{step.Params["message"]}
+		default:
+			var err error
+			if args, err = command(step, run.Execution.Target); err != nil {
+				return nil, err
+			}
+			cmd = "oc"
+		}
+
+		tektonStep := map[string]interface{}{
+			"name":    stepName(i, step.Name),
+			"image":   b.opts.Image,
+			"command": []interface{}{cmd},
+			"args":    toInterfaces(args),
+		}
+		if timeout := stepTimeout(step); timeout > 0 {
+			tektonStep["timeout"] = timeout.String()
+		}
+		if step.ContinueOnError {
+			tektonStep["onError"] = "continue"
+		}
+		steps = append(steps, tektonStep)
+	}
+
+	spec := map[string]interface{}{
+		"pipelineSpec": map[string]interface{}{
+			"tasks": []interface{}{
+				map[string]interface{}{
+					"name":     "playbook",
+					"taskSpec": map[string]interface{}{"steps": steps},
+				},
+			},
+		},
+	}
+	if b.opts.ServiceAccount != "" {
+		spec["taskRunTemplate"] = map[string]interface{}{"serviceAccountName": b.opts.ServiceAccount}
+	}
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": tektonPipelineRuns.GroupVersion().String(),
+		"kind":       "PipelineRun",
+		"metadata":   run.objectMeta(),
+		"spec":       spec,
+	}}, nil
+}
+
+// Status maps the PipelineRun's Succeeded condition: Unknown while it runs, True or
+// False once it finished
+func (b *tektonBackend) Status(obj *unstructured.Unstructured) (Phase, string) {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok || condition["type"] != "Succeeded" {
+			continue
+		}
+		status, _ := condition["status"].(string)
+		reason, _ := condition["reason"].(string)
+		message, _ := condition["message"].(string)
+		switch status {
+		case "True":
+			return PhaseSucceeded, message
+		case "False":
+			if message == "" {
+				message = reason
+			}
+			return PhaseFailed, message
+		default:
+			if reason == "PipelineRunPending" {
+				return PhasePending, message
+			}
+			return PhaseRunning, message
+		}
+	}
+	return PhasePending, ""
+}
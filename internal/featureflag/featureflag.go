@@ -0,0 +1,233 @@
+// Package featureflag gates engine behaviors so platform teams can enable capabilities
+// incrementally, per environment and per namespace, without separate builds.
+//
+// Every flag has a built-in default. A flags file (FEATURE_FLAGS_FILE) sets a flag's
+// value for the whole engine and overrides it for namespaces, by name or glob pattern
+// such as "team-*". FEATURE_FLAGS sets engine-wide values per environment and takes
+// precedence over the file; namespace overrides take precedence over both.
+package featureflag
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Flags known to the engine
+const (
+	// AutoRemediation lets the engine start remediation workflows, playbooks and
+	// escalations in a namespace
+	AutoRemediation = "auto_remediation"
+
+	// ModelCanary sends a share of a namespace's model requests to the canary version
+	// of a model rollout
+	ModelCanary = "model_canary"
+
+	// HistogramFeatures queries the histogram quantile feature group for the models
+	// that take it; when disabled its features take default values
+	HistogramFeatures = "histogram_features"
+
+	// RouteFeatures queries the route probe feature group for the models that take
+	// it; when disabled its features take default values
+	RouteFeatures = "route_features"
+)
+
+// Sources of a flag's engine-wide value
+const (
+	SourceDefault = "default"
+	SourceFile    = "file"
+	SourceEnv     = "env"
+)
+
+// Definition describes a flag
+type Definition struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Default     bool   `json:"default"`
+}
+
+// Definitions are the flags known to the engine. Flags gating behaviors that predate
+// the flag default to enabled so upgrading does not change the engine's behavior.
+var Definitions = []Definition{
+	{Name: AutoRemediation, Default: true, Description: "Start remediation workflows, playbooks and escalations"},
+	{Name: ModelCanary, Default: true, Description: "Send a share of model requests to the canary version of a model rollout"},
+	{Name: HistogramFeatures, Default: true, Description: "Query histogram quantile features for the models that take them"},
+	{Name: RouteFeatures, Default: true, Description: "Query route probe features for the models that take them"},
+}
+
+// Config sets a flag in the flags file
+type Config struct {
+	// Enabled sets the engine-wide value; nil keeps the default
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// Namespaces overrides the value for namespaces, by name or glob pattern
+	Namespaces map[string]bool `json:"namespaces,omitempty"`
+}
+
+// file is the on-disk format of the flags file
+type file struct {
+	Flags map[string]Config `json:"flags"`
+}
+
+// Status reports a flag's configuration
+type Status struct {
+	Definition
+
+	// Enabled is the engine-wide value and Source where it comes from
+	Enabled bool   `json:"enabled"`
+	Source  string `json:"source"`
+
+	// Namespaces are the namespace overrides, by name or glob pattern
+	Namespaces map[string]bool `json:"namespaces,omitempty"`
+}
+
+// Evaluation is the value of a flag for a namespace
+type Evaluation struct {
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+
+	// Override is the namespace name or pattern that decided the value; empty when
+	// the engine-wide value applies
+	Override string `json:"override,omitempty"`
+}
+
+// Flags holds the configured flags. A nil *Flags reports every flag's default.
+type Flags struct {
+	flags map[string]*Status
+}
+
+// Load reads the flags file at path, if any, and applies the engine-wide values of env
+func Load(path string, env map[string]bool) (*Flags, error) {
+	var configs map[string]Config
+	if path != "" {
+		data, err := os.ReadFile(path) //#nosec G304 -- path comes from operator configuration
+		if err != nil {
+			return nil, fmt.Errorf("failed to read feature flags file: %w", err)
+		}
+		var f file
+		if err := yaml.UnmarshalStrict(data, &f); err != nil {
+			return nil, fmt.Errorf("failed to parse feature flags file %s: %w", path, err)
+		}
+		configs = f.Flags
+	}
+	return New(configs, env)
+}
+
+// New validates the flag configs and engine-wide values. Unknown flag names and
+// invalid namespace patterns are rejected.
+func New(configs map[string]Config, env map[string]bool) (*Flags, error) {
+	flags := make(map[string]*Status, len(Definitions))
+	for _, definition := range Definitions {
+		flags[definition.Name] = &Status{Definition: definition, Enabled: definition.Default, Source: SourceDefault}
+	}
+
+	for name, config := range configs {
+		flag, ok := flags[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown feature flag %q in feature flags file", name)
+		}
+		for pattern := range config.Namespaces {
+			if _, err := path.Match(pattern, ""); err != nil || pattern == "" {
+				return nil, fmt.Errorf("feature flag %s: %q is not a namespace name or glob pattern", name, pattern)
+			}
+		}
+		if config.Enabled != nil {
+			flag.Enabled, flag.Source = *config.Enabled, SourceFile
+		}
+		if len(config.Namespaces) > 0 {
+			flag.Namespaces = config.Namespaces
+		}
+	}
+	for name, enabled := range env {
+		flag, ok := flags[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown feature flag %q in FEATURE_FLAGS", name)
+		}
+		flag.Enabled, flag.Source = enabled, SourceEnv
+	}
+	return &Flags{flags: flags}, nil
+}
+
+// Enabled reports whether a flag is enabled for a namespace; an empty namespace gets
+// the engine-wide value. Unknown flags are disabled.
+func (f *Flags) Enabled(name, namespace string) bool {
+	return f.Evaluate(name, namespace).Enabled
+}
+
+// Evaluate returns the value of a flag for a namespace. An override naming the
+// namespace wins over patterns, and a longer pattern over a shorter one.
+func (f *Flags) Evaluate(name, namespace string) Evaluation {
+	if f == nil {
+		for _, definition := range Definitions {
+			if definition.Name == name {
+				return Evaluation{Name: name, Enabled: definition.Default}
+			}
+		}
+		return Evaluation{Name: name}
+	}
+	flag, ok := f.flags[name]
+	if !ok {
+		return Evaluation{Name: name}
+	}
+
+	evaluation := Evaluation{Name: name, Enabled: flag.Enabled}
+	if namespace == "" {
+		return evaluation
+	}
+	if enabled, ok := flag.Namespaces[namespace]; ok {
+		evaluation.Enabled, evaluation.Override = enabled, namespace
+		return evaluation
+	}
+	for pattern, enabled := range flag.Namespaces {
+		if matched, _ := path.Match(pattern, namespace); !matched {
+			continue
+		}
+		if evaluation.Override == "" || len(pattern) > len(evaluation.Override) ||
+			(len(pattern) == len(evaluation.Override) && pattern < evaluation.Override) {
+			evaluation.Enabled, evaluation.Override = enabled, pattern
+		}
+	}
+	return evaluation
+}
+
+// EvaluateAll returns the value of every flag for a namespace, sorted by name
+func (f *Flags) EvaluateAll(namespace string) []Evaluation {
+	evaluations := make([]Evaluation, 0, len(Definitions))
+	for _, definition := range Definitions {
+		evaluations = append(evaluations, f.Evaluate(definition.Name, namespace))
+	}
+	sort.Slice(evaluations, func(i, j int) bool { return evaluations[i].Name < evaluations[j].Name })
+	return evaluations
+}
+
+// List returns the configuration of every flag, sorted by name
+func (f *Flags) List() []Status {
+	statuses := make([]Status, 0, len(Definitions))
+	for _, definition := range Definitions {
+		if f == nil {
+			statuses = append(statuses, Status{Definition: definition, Enabled: definition.Default, Source: SourceDefault})
+			continue
+		}
+		statuses = append(statuses, *f.flags[definition.Name])
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
+
+type namespaceKey struct{}
+
+// WithNamespace returns a context carrying the namespace a request concerns, for
+// gates deep in the call chain such as model canary routing
+func WithNamespace(ctx context.Context, namespace string) context.Context {
+	return context.WithValue(ctx, namespaceKey{}, namespace)
+}
+
+// Namespace returns the namespace carried by ctx, or "" if there is none
+func Namespace(ctx context.Context) string {
+	namespace, _ := ctx.Value(namespaceKey{}).(string)
+	return namespace
+}
@@ -0,0 +1,90 @@
+package featureflag
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlags_Evaluate(t *testing.T) {
+	disabled := false
+	flags, err := New(map[string]Config{
+		AutoRemediation: {
+			Enabled: &disabled,
+			Namespaces: map[string]bool{
+				"team-*":          true,
+				"team-payments-*": false,
+				"team-payments-a": true,
+			},
+		},
+	}, nil)
+	require.NoError(t, err)
+
+	assert.False(t, flags.Enabled(AutoRemediation, ""), "engine-wide value")
+	assert.False(t, flags.Enabled(AutoRemediation, "default"))
+	assert.Equal(t, Evaluation{Name: AutoRemediation, Enabled: true, Override: "team-*"}, flags.Evaluate(AutoRemediation, "team-web"))
+	assert.Equal(t, Evaluation{Name: AutoRemediation, Enabled: false, Override: "team-payments-*"}, flags.Evaluate(AutoRemediation, "team-payments-b"),
+		"the longer pattern wins")
+	assert.Equal(t, Evaluation{Name: AutoRemediation, Enabled: true, Override: "team-payments-a"}, flags.Evaluate(AutoRemediation, "team-payments-a"),
+		"a namespace name wins over patterns")
+
+	assert.True(t, flags.Enabled(ModelCanary, "team-web"), "flags without config take their default")
+	assert.False(t, flags.Enabled("unknown", ""))
+
+	var unset *Flags
+	assert.True(t, unset.Enabled(AutoRemediation, "team-web"), "nil flags report defaults")
+	assert.Len(t, unset.List(), len(Definitions))
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "flags.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`flags:
+  model_canary:
+    enabled: false
+    namespaces:
+      staging: true
+  route_features:
+    enabled: true
+`), 0o600))
+
+	flags, err := Load(path, map[string]bool{RouteFeatures: false})
+	require.NoError(t, err)
+	assert.True(t, flags.Enabled(ModelCanary, "staging"))
+	assert.False(t, flags.Enabled(ModelCanary, "production"))
+	assert.False(t, flags.Enabled(RouteFeatures, "staging"), "FEATURE_FLAGS takes precedence over the file")
+
+	statuses := flags.List()
+	require.Len(t, statuses, len(Definitions))
+	byName := make(map[string]Status)
+	for _, status := range statuses {
+		byName[status.Name] = status
+	}
+	assert.Equal(t, SourceFile, byName[ModelCanary].Source)
+	assert.Equal(t, map[string]bool{"staging": true}, byName[ModelCanary].Namespaces)
+	assert.Equal(t, SourceEnv, byName[RouteFeatures].Source)
+	assert.Equal(t, SourceDefault, byName[AutoRemediation].Source)
+
+	evaluations := flags.EvaluateAll("staging")
+	require.Len(t, evaluations, len(Definitions))
+	assert.Equal(t, AutoRemediation, evaluations[0].Name)
+
+	_, err = New(map[string]Config{"auto_remedation": {}}, nil)
+	assert.ErrorContains(t, err, `unknown feature flag "auto_remedation"`)
+	_, err = New(map[string]Config{ModelCanary: {Namespaces: map[string]bool{"team-[": true}}}, nil)
+	assert.Error(t, err)
+	_, err = New(nil, map[string]bool{"canary": true})
+	assert.ErrorContains(t, err, "FEATURE_FLAGS")
+
+	require.NoError(t, os.WriteFile(path, []byte("flags:\n  model_canary:\n    enable: false\n"), 0o600))
+	_, err = Load(path, nil)
+	assert.Error(t, err, "unknown fields are rejected")
+}
+
+func TestNamespaceContext(t *testing.T) {
+	assert.Empty(t, Namespace(context.Background()))
+	assert.Equal(t, "payments", Namespace(WithNamespace(context.Background(), "payments")))
+}
@@ -0,0 +1,20 @@
+package featureflag
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Disabled counts behaviors skipped or requests rejected because a flag is disabled
+var Disabled = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "coordination_engine_feature_flag_disabled_total",
+		Help: "Total number of behaviors skipped or requests rejected because a feature flag is disabled, by flag",
+	},
+	[]string{"flag"},
+)
+
+// RecordDisabled records a behavior skipped because a flag is disabled
+func RecordDisabled(flag string) {
+	Disabled.WithLabelValues(flag).Inc()
+}
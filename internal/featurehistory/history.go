@@ -0,0 +1,266 @@
+// Package featurehistory keeps the recent anomaly feature vectors of analyzed scopes.
+//
+// Each scope has a ring buffer holding one vector per resolution step over the
+// retention period, e.g. 1440 vectors for 24h at one-minute resolution, stored as
+// float32 to keep the buffers compact. When a new model version is deployed, the
+// retained vectors are re-scored with it and its decisions compared with the pinned
+// version's, without waiting for new data.
+package featurehistory
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Defaults used when Options leave a field unset
+const (
+	DefaultRetention  = 24 * time.Hour
+	DefaultResolution = time.Minute
+	DefaultMaxScopes  = 100
+)
+
+// Options configure a History
+type Options struct {
+	// Retention is how far back vectors are kept
+	Retention time.Duration
+
+	// Resolution is the spacing of kept vectors; a scope analyzed more often keeps the
+	// latest vector of each step
+	Resolution time.Duration
+
+	// MaxScopes bounds the number of scopes; the least recently recorded scope is
+	// dropped first
+	MaxScopes int
+}
+
+// Key identifies the feature history of a model's input for a scope
+type Key struct {
+	Model      string `json:"model"`
+	Namespace  string `json:"namespace"`
+	Deployment string `json:"deployment,omitempty"`
+	Pod        string `json:"pod,omitempty"`
+}
+
+// Sample is a feature vector and the time it was evaluated at, truncated to the
+// resolution
+type Sample struct {
+	At       time.Time
+	Features []float64
+}
+
+// ScopeStatus describes the retained history of a scope
+type ScopeStatus struct {
+	Key
+	Samples  int       `json:"samples"`
+	Features int       `json:"features"`
+	Oldest   time.Time `json:"oldest"`
+	Newest   time.Time `json:"newest"`
+}
+
+// History holds the feature history of every recorded scope. It is safe for
+// concurrent use.
+type History struct {
+	opts     Options
+	capacity int
+
+	mu     sync.Mutex
+	scopes map[Key]*ring
+}
+
+// ring is the feature history of one scope, oldest sample at head
+type ring struct {
+	at       []time.Time
+	features [][]float32
+	head     int
+	size     int
+	width    int
+	recorded time.Time
+}
+
+// New creates an empty feature history
+func New(opts Options) *History {
+	if opts.Retention <= 0 {
+		opts.Retention = DefaultRetention
+	}
+	if opts.Resolution <= 0 {
+		opts.Resolution = DefaultResolution
+	}
+	if opts.MaxScopes <= 0 {
+		opts.MaxScopes = DefaultMaxScopes
+	}
+	return &History{
+		opts:     opts,
+		capacity: max(int(opts.Retention/opts.Resolution), 1),
+		scopes:   make(map[Key]*ring),
+	}
+}
+
+// Retention returns how far back vectors are kept
+func (h *History) Retention() time.Duration {
+	return h.opts.Retention
+}
+
+// Resolution returns the spacing of kept vectors
+func (h *History) Resolution() time.Duration {
+	return h.opts.Resolution
+}
+
+// Record keeps the feature vector of a scope evaluated at a time. A vector of a step
+// already recorded replaces it; vectors older than the scope's newest are ignored. A
+// vector of another width, e.g. after the model's feature set changed, starts the
+// scope's history over.
+func (h *History) Record(key Key, at time.Time, features []float64) {
+	if len(features) == 0 {
+		return
+	}
+	step := at.UTC().Truncate(h.opts.Resolution)
+	compact := make([]float32, len(features))
+	for i, value := range features {
+		compact[i] = float32(value)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	r, ok := h.scopes[key]
+	if !ok || r.width != len(features) {
+		if !ok && len(h.scopes) >= h.opts.MaxScopes {
+			h.evictLocked()
+		}
+		r = &ring{
+			at:       make([]time.Time, h.capacity),
+			features: make([][]float32, h.capacity),
+			width:    len(features),
+		}
+		h.scopes[key] = r
+	}
+	r.recorded = time.Now()
+
+	if r.size > 0 {
+		last := (r.head + r.size - 1) % h.capacity
+		switch {
+		case step.Equal(r.at[last]):
+			r.features[last] = compact
+			return
+		case step.Before(r.at[last]):
+			return
+		}
+	}
+	idx := (r.head + r.size) % h.capacity
+	if r.size == h.capacity {
+		idx = r.head
+		r.head = (r.head + 1) % h.capacity
+	} else {
+		r.size++
+	}
+	r.at[idx] = step
+	r.features[idx] = compact
+}
+
+// evictLocked drops the least recently recorded scope. Callers must hold h.mu.
+func (h *History) evictLocked() {
+	var oldest Key
+	var oldestAt time.Time
+	first := true
+	for key, r := range h.scopes {
+		if first || r.recorded.Before(oldestAt) {
+			oldest, oldestAt, first = key, r.recorded, false
+		}
+	}
+	delete(h.scopes, oldest)
+}
+
+// Samples returns the vectors of a scope evaluated at or after since, oldest first
+func (h *History) Samples(key Key, since time.Time) []Sample {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	r, ok := h.scopes[key]
+	if !ok {
+		return nil
+	}
+	samples := make([]Sample, 0, r.size)
+	for i := 0; i < r.size; i++ {
+		idx := (r.head + i) % h.capacity
+		if r.at[idx].Before(since) {
+			continue
+		}
+		features := make([]float64, len(r.features[idx]))
+		for j, value := range r.features[idx] {
+			features[j] = float64(value)
+		}
+		samples = append(samples, Sample{At: r.at[idx], Features: features})
+	}
+	return samples
+}
+
+// Scopes describes the history of every scope, ordered by model and scope
+func (h *History) Scopes() []ScopeStatus {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	statuses := make([]ScopeStatus, 0, len(h.scopes))
+	for key, r := range h.scopes {
+		if r.size == 0 {
+			continue
+		}
+		statuses = append(statuses, ScopeStatus{
+			Key:      key,
+			Samples:  r.size,
+			Features: r.width,
+			Oldest:   r.at[r.head],
+			Newest:   r.at[(r.head+r.size-1)%h.capacity],
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		a, b := statuses[i].Key, statuses[j].Key
+		if a.Model != b.Model {
+			return a.Model < b.Model
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Deployment != b.Deployment {
+			return a.Deployment < b.Deployment
+		}
+		return a.Pod < b.Pod
+	})
+	return statuses
+}
+
+// PurgeBefore drops the vectors evaluated before cutoff and returns how many were dropped
+func (h *History) PurgeBefore(cutoff time.Time) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	purged := 0
+	for key, r := range h.scopes {
+		for r.size > 0 && r.at[r.head].Before(cutoff) {
+			r.features[r.head] = nil
+			r.head = (r.head + 1) % h.capacity
+			r.size--
+			purged++
+		}
+		if r.size == 0 {
+			delete(h.scopes, key)
+		}
+	}
+	return purged
+}
+
+// Forget drops the history of every scope in a namespace and returns the number of
+// vectors dropped
+func (h *History) Forget(namespace string) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	purged := 0
+	for key, r := range h.scopes {
+		if key.Namespace == namespace {
+			purged += r.size
+			delete(h.scopes, key)
+		}
+	}
+	return purged
+}
@@ -0,0 +1,64 @@
+package featurehistory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistory_Record(t *testing.T) {
+	history := New(Options{Retention: 5 * time.Minute, Resolution: time.Minute})
+	key := Key{Model: "anomaly-detector", Namespace: "payments"}
+	start := time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 7; i++ {
+		history.Record(key, start.Add(time.Duration(i)*time.Minute+10*time.Second), []float64{float64(i), 0.5})
+	}
+	// The latest vector of a step wins; older vectors are ignored
+	history.Record(key, start.Add(6*time.Minute+50*time.Second), []float64{6.5, 0.5})
+	history.Record(key, start.Add(time.Minute), []float64{-1, -1})
+
+	samples := history.Samples(key, time.Time{})
+	require.Len(t, samples, 5, "the ring keeps retention / resolution vectors")
+	assert.Equal(t, start.Add(2*time.Minute), samples[0].At)
+	assert.Equal(t, []float64{2, 0.5}, samples[0].Features)
+	assert.Equal(t, start.Add(6*time.Minute), samples[4].At)
+	assert.Equal(t, []float64{6.5, 0.5}, samples[4].Features)
+
+	assert.Len(t, history.Samples(key, start.Add(5*time.Minute)), 2)
+	assert.Empty(t, history.Samples(Key{Model: "anomaly-detector", Namespace: "other"}, time.Time{}))
+
+	assert.Equal(t, []ScopeStatus{{Key: key, Samples: 5, Features: 2, Oldest: start.Add(2 * time.Minute), Newest: start.Add(6 * time.Minute)}}, history.Scopes())
+
+	// A vector of another width starts over
+	history.Record(key, start.Add(7*time.Minute), []float64{1, 2, 3})
+	samples = history.Samples(key, time.Time{})
+	require.Len(t, samples, 1)
+	assert.Len(t, samples[0].Features, 3)
+}
+
+func TestHistory_Limits(t *testing.T) {
+	history := New(Options{Retention: time.Hour, Resolution: time.Minute, MaxScopes: 2})
+	start := time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC)
+	a := Key{Model: "m", Namespace: "a"}
+	b := Key{Model: "m", Namespace: "b", Deployment: "api"}
+	c := Key{Model: "m", Namespace: "c"}
+
+	history.Record(a, start, []float64{1})
+	history.Record(a, start.Add(time.Minute), []float64{1})
+	history.Record(b, start, []float64{1})
+	history.Record(c, start, []float64{1})
+	scopes := history.Scopes()
+	require.Len(t, scopes, 2)
+	assert.Equal(t, b, scopes[0].Key, "the least recently recorded scope is dropped")
+	assert.Equal(t, c, scopes[1].Key)
+
+	history.Record(b, start.Add(2*time.Minute), []float64{1})
+	assert.Equal(t, 2, history.PurgeBefore(start.Add(time.Minute)))
+	assert.Len(t, history.Scopes(), 1, "scopes without vectors are dropped")
+
+	assert.Equal(t, 1, history.Forget("b"))
+	assert.Empty(t, history.Scopes())
+}
@@ -0,0 +1,327 @@
+// Package hardening generates least-privilege deployment manifests for the engine.
+//
+// The manifests depend on which integrations are enabled: a NetworkPolicy that only
+// admits monitoring and in-namespace traffic and only allows egress to DNS, the
+// Kubernetes API and the configured integrations, and ClusterRoles granting only the
+// API access the engine's enabled features use.
+package hardening
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/yaml"
+)
+
+// Defaults for generated manifests
+const (
+	DefaultName                = "coordination-engine"
+	DefaultMonitoringNamespace = "openshift-monitoring"
+)
+
+// namespaceNameLabel is set on every namespace by Kubernetes
+const namespaceNameLabel = "kubernetes.io/metadata.name"
+
+// Endpoint is a destination the engine connects to
+type Endpoint struct {
+	// Name describes the integration, e.g. prometheus
+	Name string `json:"name"`
+
+	// Namespace is set for in-cluster services; empty means outside the cluster
+	Namespace string `json:"namespace,omitempty"`
+
+	Port int `json:"port"`
+}
+
+// EndpointFromURL derives an endpoint from an http(s) URL. Hosts of the form
+// <service>.<namespace>.svc[.cluster.local] are in-cluster services.
+func EndpointFromURL(name, rawURL string) (Endpoint, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return Endpoint{}, fmt.Errorf("%s: invalid url %q", name, rawURL)
+	}
+
+	port := 443
+	if u.Scheme == "http" {
+		port = 80
+	}
+	if p := u.Port(); p != "" {
+		parsed, err := strconv.Atoi(p)
+		if err != nil || parsed < 1 || parsed > 65535 {
+			return Endpoint{}, fmt.Errorf("%s: invalid port in %q", name, rawURL)
+		}
+		port = parsed
+	}
+	return HostEndpoint(name, u.Hostname(), port), nil
+}
+
+// HostEndpoint creates an endpoint for a host and port, e.g. an SMTP relay
+func HostEndpoint(name, host string, port int) Endpoint {
+	endpoint := Endpoint{Name: name, Port: port}
+	if net.ParseIP(host) == nil {
+		host = strings.TrimSuffix(host, ".cluster.local")
+		if parts := strings.Split(host, "."); len(parts) == 3 && parts[2] == "svc" {
+			endpoint.Namespace = parts[1]
+		}
+	}
+	return endpoint
+}
+
+// Options describe the engine deployment manifests are generated for
+type Options struct {
+	// Name prefixes generated objects and is the ServiceAccount name
+	Name string
+
+	// Namespace the engine runs in
+	Namespace string
+
+	// APIPorts are the ports serving the REST, gRPC and MCP APIs
+	APIPorts []int
+
+	// MetricsPort is scraped by Prometheus
+	MetricsPort int
+
+	// MonitoringNamespace holds Prometheus and Alertmanager
+	MonitoringNamespace string
+
+	// AlertIngest admits Alertmanager webhooks from the monitoring namespace
+	AlertIngest bool
+
+	// UpgradeAware grants read access to the ClusterVersion
+	UpgradeAware bool
+
+	// Endpoints the engine connects to besides DNS and the Kubernetes API
+	Endpoints []Endpoint
+}
+
+// withDefaults fills unset options
+func (o Options) withDefaults() Options {
+	if o.Name == "" {
+		o.Name = DefaultName
+	}
+	if o.MonitoringNamespace == "" {
+		o.MonitoringNamespace = DefaultMonitoringNamespace
+	}
+	return o
+}
+
+// labels are set on every generated object
+func (o Options) labels() map[string]string {
+	return map[string]string{
+		"app.kubernetes.io/name":      o.Name,
+		"app.kubernetes.io/component": "hardening",
+	}
+}
+
+// NetworkPolicy admits API traffic from the engine's namespace (and Alertmanager when
+// alerts are ingested) and metrics scrapes from the monitoring namespace, and allows
+// egress only to DNS, the Kubernetes API and the configured endpoints. External
+// endpoints cannot be selected by host, so they are allowed by port only.
+func NetworkPolicy(opts Options) *networkingv1.NetworkPolicy {
+	opts = opts.withDefaults()
+	sameNamespace := networkingv1.NetworkPolicyPeer{PodSelector: &metav1.LabelSelector{}}
+	monitoring := namespacePeer(opts.MonitoringNamespace)
+
+	apiPeers := []networkingv1.NetworkPolicyPeer{sameNamespace}
+	if opts.AlertIngest {
+		apiPeers = append(apiPeers, monitoring)
+	}
+	var ingress []networkingv1.NetworkPolicyIngressRule
+	if len(opts.APIPorts) > 0 {
+		ingress = append(ingress, networkingv1.NetworkPolicyIngressRule{From: apiPeers, Ports: tcpPorts(opts.APIPorts...)})
+	}
+	if opts.MetricsPort > 0 {
+		ingress = append(ingress, networkingv1.NetworkPolicyIngressRule{
+			From:  []networkingv1.NetworkPolicyPeer{monitoring},
+			Ports: tcpPorts(opts.MetricsPort),
+		})
+	}
+
+	egress := []networkingv1.NetworkPolicyEgressRule{
+		// DNS; OpenShift's DNS pods listen on 5353
+		{Ports: append(tcpPorts(53, 5353), portsWithProtocol(corev1.ProtocolUDP, 53, 5353)...)},
+		// Kubernetes API server, which runs on the host network
+		{Ports: tcpPorts(443, 6443)},
+	}
+	egress = append(egress, groupEndpoints(opts.Endpoints)...)
+
+	return &networkingv1.NetworkPolicy{
+		TypeMeta: metav1.TypeMeta{APIVersion: "networking.k8s.io/v1", Kind: "NetworkPolicy"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      opts.Name,
+			Namespace: opts.Namespace,
+			Labels:    opts.labels(),
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app.kubernetes.io/name": opts.Name}},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress},
+			Ingress:     ingress,
+			Egress:      egress,
+		},
+	}
+}
+
+// groupEndpoints builds one egress rule per destination namespace (and one for
+// external endpoints), sorted for stable output
+func groupEndpoints(endpoints []Endpoint) []networkingv1.NetworkPolicyEgressRule {
+	ports := make(map[string]map[int]bool)
+	for _, endpoint := range endpoints {
+		if ports[endpoint.Namespace] == nil {
+			ports[endpoint.Namespace] = make(map[int]bool)
+		}
+		ports[endpoint.Namespace][endpoint.Port] = true
+	}
+
+	namespaces := make([]string, 0, len(ports))
+	for namespace := range ports {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+
+	rules := make([]networkingv1.NetworkPolicyEgressRule, 0, len(namespaces))
+	for _, namespace := range namespaces {
+		list := make([]int, 0, len(ports[namespace]))
+		for port := range ports[namespace] {
+			list = append(list, port)
+		}
+		sort.Ints(list)
+
+		rule := networkingv1.NetworkPolicyEgressRule{Ports: tcpPorts(list...)}
+		if namespace != "" {
+			rule.To = []networkingv1.NetworkPolicyPeer{namespacePeer(namespace)}
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// ClusterRoles returns the roles the engine needs: a cluster-wide reader for nodes and
+// platform state, and a workload role for detecting and remediating issues in
+// application namespaces. Remediations that patch operator custom resources need
+// additional rules for those resources.
+func ClusterRoles(opts Options) []*rbacv1.ClusterRole {
+	opts = opts.withDefaults()
+	read := []string{"get", "list", "watch"}
+
+	reader := []rbacv1.PolicyRule{
+		{APIGroups: []string{""}, Resources: []string{"nodes", "namespaces", "persistentvolumes"}, Verbs: read},
+		{APIGroups: []string{"machineconfiguration.openshift.io"}, Resources: []string{"machineconfigs", "machineconfigpools"}, Verbs: read},
+		{APIGroups: []string{"config.openshift.io"}, Resources: []string{"clusteroperators"}, Verbs: read},
+		// RBAC self-checks at startup
+		{APIGroups: []string{"authorization.k8s.io"}, Resources: []string{"selfsubjectaccessreviews"}, Verbs: []string{"create"}},
+	}
+	if opts.UpgradeAware {
+		reader = append(reader, rbacv1.PolicyRule{APIGroups: []string{"config.openshift.io"}, Resources: []string{"clusterversions"}, Verbs: read})
+	}
+
+	workloads := []rbacv1.PolicyRule{
+		{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get", "list", "watch", "delete"}},
+		{APIGroups: []string{""}, Resources: []string{"services", "endpoints", "resourcequotas"}, Verbs: read},
+		{APIGroups: []string{""}, Resources: []string{"events"}, Verbs: []string{"get", "list", "watch", "create"}},
+		{APIGroups: []string{"apps"}, Resources: []string{"deployments"}, Verbs: []string{"get", "list", "watch", "update", "patch"}},
+		{APIGroups: []string{"apps"}, Resources: []string{"replicasets", "statefulsets", "daemonsets"}, Verbs: read},
+		{APIGroups: []string{"batch"}, Resources: []string{"jobs"}, Verbs: []string{"get", "list"}},
+	}
+
+	return []*rbacv1.ClusterRole{
+		clusterRole(opts, opts.Name+"-cluster-reader", reader),
+		clusterRole(opts, opts.Name+"-workloads", workloads),
+	}
+}
+
+// clusterRole creates a labelled ClusterRole
+func clusterRole(opts Options, name string, rules []rbacv1.PolicyRule) *rbacv1.ClusterRole {
+	return &rbacv1.ClusterRole{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRole"},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: opts.labels()},
+		Rules:      rules,
+	}
+}
+
+// ClusterRoleBindings bind each role to the engine's ServiceAccount
+func ClusterRoleBindings(opts Options, roles []*rbacv1.ClusterRole) []*rbacv1.ClusterRoleBinding {
+	opts = opts.withDefaults()
+	bindings := make([]*rbacv1.ClusterRoleBinding, 0, len(roles))
+	for _, role := range roles {
+		bindings = append(bindings, &rbacv1.ClusterRoleBinding{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRoleBinding"},
+			ObjectMeta: metav1.ObjectMeta{Name: role.Name, Labels: opts.labels()},
+			RoleRef:    rbacv1.RoleRef{APIGroup: "rbac.authorization.k8s.io", Kind: "ClusterRole", Name: role.Name},
+			Subjects: []rbacv1.Subject{{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      opts.Name,
+				Namespace: opts.Namespace,
+			}},
+		})
+	}
+	return bindings
+}
+
+// GenerateYAML renders the NetworkPolicy, ClusterRoles and ClusterRoleBindings as a
+// multi-document YAML stream that can be applied with "oc apply -f"
+func GenerateYAML(opts Options) ([]byte, error) {
+	if opts.Namespace == "" {
+		return nil, fmt.Errorf("namespace is required")
+	}
+
+	roles := ClusterRoles(opts)
+	objects := []interface{}{NetworkPolicy(opts)}
+	for _, role := range roles {
+		objects = append(objects, role)
+	}
+	for _, binding := range ClusterRoleBindings(opts, roles) {
+		objects = append(objects, binding)
+	}
+
+	var buf bytes.Buffer
+	for i, object := range objects {
+		data, err := yaml.Marshal(object)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal manifest: %w", err)
+		}
+		if i > 0 {
+			buf.WriteString("---\n")
+		}
+		buf.Write(removeCreationTimestamp(data))
+	}
+	return buf.Bytes(), nil
+}
+
+// removeCreationTimestamp drops the "creationTimestamp: null" that typed objects
+// marshal with, which is noise in generated manifests
+func removeCreationTimestamp(data []byte) []byte {
+	return bytes.ReplaceAll(data, []byte("  creationTimestamp: null\n"), nil)
+}
+
+// namespacePeer selects all pods in a namespace
+func namespacePeer(namespace string) networkingv1.NetworkPolicyPeer {
+	return networkingv1.NetworkPolicyPeer{
+		NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{namespaceNameLabel: namespace}},
+	}
+}
+
+// tcpPorts returns TCP network policy ports
+func tcpPorts(ports ...int) []networkingv1.NetworkPolicyPort {
+	return portsWithProtocol(corev1.ProtocolTCP, ports...)
+}
+
+// portsWithProtocol returns network policy ports with the given protocol
+func portsWithProtocol(protocol corev1.Protocol, ports ...int) []networkingv1.NetworkPolicyPort {
+	result := make([]networkingv1.NetworkPolicyPort, 0, len(ports))
+	for _, port := range ports {
+		p := intstr.FromInt32(int32(port)) //nolint:gosec // ports are at most 65535
+		proto := protocol
+		result = append(result, networkingv1.NetworkPolicyPort{Protocol: &proto, Port: &p})
+	}
+	return result
+}
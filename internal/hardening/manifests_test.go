@@ -0,0 +1,100 @@
+package hardening
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	networkingv1 "k8s.io/api/networking/v1"
+	"sigs.k8s.io/yaml"
+)
+
+func TestEndpointFromURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		want Endpoint
+	}{
+		{"https://thanos-querier.openshift-monitoring.svc:9091", Endpoint{Name: "test", Namespace: "openshift-monitoring", Port: 9091}},
+		{"http://predictor.ml.svc.cluster.local/v1", Endpoint{Name: "test", Namespace: "ml", Port: 80}},
+		{"https://api.openai.com/v1", Endpoint{Name: "test", Port: 443}},
+		{"http://10.0.0.5:8080", Endpoint{Name: "test", Port: 8080}},
+	}
+	for _, tt := range tests {
+		got, err := EndpointFromURL("test", tt.url)
+		require.NoError(t, err, tt.url)
+		assert.Equal(t, tt.want, got, tt.url)
+	}
+
+	_, err := EndpointFromURL("test", "ftp://example.com")
+	assert.Error(t, err)
+}
+
+func TestNetworkPolicy(t *testing.T) {
+	policy := NetworkPolicy(Options{
+		Namespace:   "self-healing-platform",
+		APIPorts:    []int{8080, 50051},
+		MetricsPort: 9090,
+		AlertIngest: true,
+		Endpoints: []Endpoint{
+			{Name: "prometheus", Namespace: "openshift-monitoring", Port: 9091},
+			{Name: "kserve", Namespace: "self-healing-platform", Port: 8080},
+			{Name: "summarizer", Port: 443},
+			{Name: "webhook", Port: 443},
+		},
+	})
+
+	assert.Equal(t, "coordination-engine", policy.Spec.PodSelector.MatchLabels["app.kubernetes.io/name"])
+	require.Len(t, policy.Spec.Ingress, 2)
+	assert.Len(t, policy.Spec.Ingress[0].From, 2, "API is open to the namespace and Alertmanager")
+	assert.Equal(t, "openshift-monitoring", policy.Spec.Ingress[1].From[0].NamespaceSelector.MatchLabels[namespaceNameLabel])
+
+	// DNS, API server, then one rule per destination with external endpoints first
+	require.Len(t, policy.Spec.Egress, 5)
+	external := policy.Spec.Egress[2]
+	assert.Empty(t, external.To)
+	require.Len(t, external.Ports, 1, "duplicate ports are merged")
+	assert.Equal(t, int32(443), external.Ports[0].Port.IntVal)
+	assert.Equal(t, "openshift-monitoring", policy.Spec.Egress[3].To[0].NamespaceSelector.MatchLabels[namespaceNameLabel])
+	assert.Equal(t, "self-healing-platform", policy.Spec.Egress[4].To[0].NamespaceSelector.MatchLabels[namespaceNameLabel])
+
+	// Without alert ingestion only the engine's namespace reaches the API
+	policy = NetworkPolicy(Options{Namespace: "self-healing-platform", APIPorts: []int{8080}})
+	assert.Len(t, policy.Spec.Ingress[0].From, 1)
+}
+
+func TestClusterRoles(t *testing.T) {
+	resources := func(opts Options) string {
+		var names []string
+		for _, role := range ClusterRoles(opts) {
+			for _, rule := range role.Rules {
+				names = append(names, rule.Resources...)
+			}
+		}
+		return strings.Join(names, ",")
+	}
+
+	minimal := resources(Options{})
+	assert.NotContains(t, minimal, "clusterversions")
+	assert.NotContains(t, minimal, "secrets", "the engine never reads secrets through the API")
+
+	assert.Contains(t, resources(Options{UpgradeAware: true}), "clusterversions")
+}
+
+func TestGenerateYAML(t *testing.T) {
+	_, err := GenerateYAML(Options{})
+	assert.Error(t, err, "namespace is required")
+
+	data, err := GenerateYAML(Options{Namespace: "self-healing-platform", APIPorts: []int{8080}})
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "creationTimestamp")
+
+	docs := strings.Split(string(data), "---\n")
+	require.Len(t, docs, 5, "network policy, two roles and two bindings")
+	var policy networkingv1.NetworkPolicy
+	require.NoError(t, yaml.Unmarshal([]byte(docs[0]), &policy))
+	assert.Equal(t, "NetworkPolicy", policy.Kind)
+	assert.Equal(t, "self-healing-platform", policy.Namespace)
+	assert.Contains(t, docs[4], "kind: ClusterRoleBinding")
+	assert.Contains(t, docs[4], "name: coordination-engine-workloads")
+}
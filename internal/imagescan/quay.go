@@ -0,0 +1,163 @@
+// Package imagescan looks up the known vulnerabilities of the images workloads run in
+// a Quay registry's Clair security scans, so incidents and recommendations can tell
+// crashes of vulnerable images apart.
+package imagescan
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Scan statuses reported by Quay for a manifest
+const (
+	StatusScanned     = "scanned"
+	StatusQueued      = "queued"
+	StatusUnsupported = "unsupported"
+	StatusFailed      = "failed"
+)
+
+// severityCritical is the Clair severity of the vulnerabilities reported
+const severityCritical = "critical"
+
+// Vulnerability is a known critical vulnerability of a package in an image
+type Vulnerability struct {
+	ID      string `json:"id"`
+	Package string `json:"package"`
+	Version string `json:"version"`
+
+	// FixedBy is the package version fixing the vulnerability, empty without a fix
+	FixedBy string `json:"fixed_by,omitempty"`
+	Link    string `json:"link,omitempty"`
+}
+
+// QuayClient reads manifest security scans from the Quay API
+type QuayClient struct {
+	baseURL    string
+	host       string
+	token      string
+	httpClient *http.Client
+	log        *logrus.Logger
+}
+
+// NewQuayClient creates a Quay API client. token is sent as a bearer token (an OAuth
+// application token with repo:read) when set.
+func NewQuayClient(baseURL, token string, timeout time.Duration, log *logrus.Logger) (*QuayClient, error) {
+	parsed, err := url.Parse(baseURL)
+	if err != nil || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid Quay URL %q", baseURL)
+	}
+	return &QuayClient{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		host:    parsed.Host,
+		token:   token,
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+		log: log,
+	}, nil
+}
+
+// HTTPClient returns the client used to reach Quay, e.g. to audit its TLS configuration
+func (c *QuayClient) HTTPClient() *http.Client {
+	return c.httpClient
+}
+
+// Host returns the registry host whose images the client can look up
+func (c *QuayClient) Host() string {
+	return c.host
+}
+
+// quaySecurityResponse is the subset of the manifest security endpoint used by the client
+type quaySecurityResponse struct {
+	Status string `json:"status"`
+	Data   *struct {
+		Layer struct {
+			Features []struct {
+				Name            string `json:"Name"`
+				Version         string `json:"Version"`
+				Vulnerabilities []struct {
+					Name     string `json:"Name"`
+					Severity string `json:"Severity"`
+					Link     string `json:"Link"`
+					FixedBy  string `json:"FixedBy"`
+				} `json:"Vulnerabilities"`
+			} `json:"Features"`
+		} `json:"Layer"`
+	} `json:"data"`
+}
+
+// CriticalVulnerabilities returns the scan status of a manifest of a repository
+// ("namespace/name") and its critical vulnerabilities, sorted by ID
+func (c *QuayClient) CriticalVulnerabilities(ctx context.Context, repository, digest string) (string, []Vulnerability, error) {
+	endpoint := fmt.Sprintf("%s/api/v1/repository/%s/manifest/%s/security?vulnerabilities=true",
+		c.baseURL, repository, url.PathEscape(digest))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, http.NoBody)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			c.log.WithError(closeErr).Warn("Failed to close response body")
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		body, readErr := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		if readErr != nil {
+			return "", nil, fmt.Errorf("unexpected status %d, failed to read body: %w", resp.StatusCode, readErr)
+		}
+		return "", nil, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var result quaySecurityResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if result.Data == nil {
+		return result.Status, nil, nil
+	}
+
+	seen := make(map[string]bool)
+	vulnerabilities := make([]Vulnerability, 0)
+	for _, feature := range result.Data.Layer.Features {
+		for _, vuln := range feature.Vulnerabilities {
+			key := vuln.Name + "/" + feature.Name
+			if !strings.EqualFold(vuln.Severity, severityCritical) || seen[key] {
+				continue
+			}
+			seen[key] = true
+			vulnerabilities = append(vulnerabilities, Vulnerability{
+				ID:      vuln.Name,
+				Package: feature.Name,
+				Version: feature.Version,
+				FixedBy: vuln.FixedBy,
+				Link:    vuln.Link,
+			})
+		}
+	}
+	sort.Slice(vulnerabilities, func(i, j int) bool {
+		if vulnerabilities[i].ID != vulnerabilities[j].ID {
+			return vulnerabilities[i].ID < vulnerabilities[j].ID
+		}
+		return vulnerabilities[i].Package < vulnerabilities[j].Package
+	})
+	return result.Status, vulnerabilities, nil
+}
@@ -0,0 +1,85 @@
+package imagescan
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const securityResponse = `{
+  "status": "scanned",
+  "data": {"Layer": {"Features": [
+    {"Name": "openssl", "Version": "1.1.1k", "Vulnerabilities": [
+      {"Name": "CVE-2023-0286", "Severity": "Critical", "FixedBy": "1.1.1t", "Link": "https://access.redhat.com/security/cve/CVE-2023-0286"},
+      {"Name": "CVE-2022-4304", "Severity": "Medium", "FixedBy": "1.1.1t"}
+    ]},
+    {"Name": "glibc", "Version": "2.28", "Vulnerabilities": [
+      {"Name": "CVE-2021-3999", "Severity": "critical"},
+      {"Name": "CVE-2021-3999", "Severity": "critical"}
+    ]},
+    {"Name": "zlib", "Version": "1.2.11"}
+  ]}}
+}`
+
+// quayServer serves the security scan of every manifest and counts the requests
+func quayServer(t *testing.T, body string) (*QuayClient, *int32) {
+	t.Helper()
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if r.URL.Path != "/api/v1/repository/acme/api/manifest/sha256:abc/security" || r.URL.Query().Get("vulnerabilities") != "true" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer quay-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	client, err := NewQuayClient(server.URL+"/", "quay-token", 5*time.Second, log)
+	require.NoError(t, err)
+	return client, &requests
+}
+
+func TestQuayClient_CriticalVulnerabilities(t *testing.T) {
+	client, _ := quayServer(t, securityResponse)
+
+	status, vulns, err := client.CriticalVulnerabilities(context.Background(), "acme/api", "sha256:abc")
+	require.NoError(t, err)
+	assert.Equal(t, StatusScanned, status)
+	assert.Equal(t, []Vulnerability{
+		{ID: "CVE-2021-3999", Package: "glibc", Version: "2.28"},
+		{ID: "CVE-2023-0286", Package: "openssl", Version: "1.1.1k", FixedBy: "1.1.1t", Link: "https://access.redhat.com/security/cve/CVE-2023-0286"},
+	}, vulns)
+
+	_, _, err = client.CriticalVulnerabilities(context.Background(), "acme/other", "sha256:abc")
+	assert.ErrorContains(t, err, "unexpected status 404")
+
+	client, _ = quayServer(t, `{"status": "queued", "data": null}`)
+	status, vulns, err = client.CriticalVulnerabilities(context.Background(), "acme/api", "sha256:abc")
+	require.NoError(t, err)
+	assert.Equal(t, StatusQueued, status)
+	assert.Empty(t, vulns)
+}
+
+func TestNewQuayClient(t *testing.T) {
+	client, err := NewQuayClient("https://quay.example.com", "", time.Second, logrus.New())
+	require.NoError(t, err)
+	assert.Equal(t, "quay.example.com", client.Host())
+
+	_, err = NewQuayClient("quay.example.com", "", time.Second, logrus.New())
+	assert.ErrorContains(t, err, "invalid Quay URL")
+}
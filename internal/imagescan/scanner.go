@@ -0,0 +1,298 @@
+package imagescan
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ImageReport is the scan of an image a workload runs
+type ImageReport struct {
+	// Image is the repository and digest the image was pulled by
+	Image  string `json:"image"`
+	Status string `json:"status"`
+
+	Critical []Vulnerability `json:"critical_vulnerabilities,omitempty"`
+}
+
+// CriticalIDs returns the IDs of the image's critical vulnerabilities
+func (r *ImageReport) CriticalIDs() []string {
+	ids := make([]string, 0, len(r.Critical))
+	seen := make(map[string]bool)
+	for _, vuln := range r.Critical {
+		if !seen[vuln.ID] {
+			seen[vuln.ID] = true
+			ids = append(ids, vuln.ID)
+		}
+	}
+	return ids
+}
+
+// CrashingImage is an image with critical vulnerabilities whose containers keep
+// crashing in a namespace
+type CrashingImage struct {
+	Namespace string   `json:"namespace"`
+	Pods      []string `json:"pods"`
+	Container string   `json:"container"`
+
+	// Restarts is summed over the pods; Reason is the last termination or waiting
+	// reason, e.g. Error or CrashLoopBackOff
+	Restarts int32  `json:"restarts"`
+	Reason   string `json:"reason"`
+
+	Report ImageReport `json:"report"`
+}
+
+// reference is an image pulled from a Quay registry by digest
+type reference struct {
+	host       string
+	repository string
+	digest     string
+}
+
+// String returns the image as repository@digest
+func (r reference) String() string {
+	return r.host + "/" + r.repository + "@" + r.digest
+}
+
+// parseImageID parses the image ID of a container status, e.g.
+// "quay.io/acme/api@sha256:...", optionally with a runtime scheme such as
+// "docker-pullable://". IDs without a registry host or digest do not parse.
+func parseImageID(imageID string) (reference, bool) {
+	if i := strings.Index(imageID, "://"); i >= 0 {
+		imageID = imageID[i+3:]
+	}
+	name, digest, ok := strings.Cut(imageID, "@")
+	if !ok || !strings.HasPrefix(digest, "sha256:") {
+		return reference{}, false
+	}
+	host, repository, ok := strings.Cut(name, "/")
+	if !ok || !strings.ContainsAny(host, ".:") {
+		return reference{}, false
+	}
+	return reference{host: host, repository: repository, digest: digest}, true
+}
+
+type cachedReport struct {
+	report    ImageReport
+	expiresAt time.Time
+}
+
+// Scanner finds the images running in workloads and looks up their critical
+// vulnerabilities in Quay. Only images pulled from the Quay host are looked up; scans
+// are cached per digest.
+type Scanner struct {
+	quay        *QuayClient
+	clientset   kubernetes.Interface
+	minRestarts int32
+	ttl         time.Duration
+	log         *logrus.Logger
+
+	mu    sync.Mutex
+	cache map[string]cachedReport
+}
+
+// NewScanner creates an image scanner. Containers restarted minRestarts times count as
+// crashing; scans are cached for ttl.
+func NewScanner(quay *QuayClient, clientset kubernetes.Interface, minRestarts int, ttl time.Duration, log *logrus.Logger) *Scanner {
+	return &Scanner{
+		quay:        quay,
+		clientset:   clientset,
+		minRestarts: int32(minRestarts),
+		ttl:         ttl,
+		log:         log,
+		cache:       make(map[string]cachedReport),
+	}
+}
+
+// scan returns the report of an image, from the cache while it is fresh. Images that
+// are still being scanned are not cached.
+func (s *Scanner) scan(ctx context.Context, ref reference) (ImageReport, error) {
+	key := ref.String()
+	s.mu.Lock()
+	cached, ok := s.cache[key]
+	s.mu.Unlock()
+	if ok && time.Now().Before(cached.expiresAt) {
+		return cached.report, nil
+	}
+
+	status, critical, err := s.quay.CriticalVulnerabilities(ctx, ref.repository, ref.digest)
+	if err != nil {
+		return ImageReport{}, fmt.Errorf("failed to look up %s: %w", key, err)
+	}
+	report := ImageReport{Image: key, Status: status, Critical: critical}
+	if status != StatusQueued {
+		s.mu.Lock()
+		s.cache[key] = cachedReport{report: report, expiresAt: time.Now().Add(s.ttl)}
+		s.mu.Unlock()
+	}
+	return report, nil
+}
+
+// WorkloadImages returns the reports of the Quay images a workload runs. resource is
+// "kind/name" of a pod, deployment, statefulset, daemonset or replicaset; other kinds
+// have no images. Images failing to be looked up are left out.
+func (s *Scanner) WorkloadImages(ctx context.Context, namespace, resource string) ([]ImageReport, error) {
+	pods, err := s.workloadPods(ctx, namespace, resource)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	reports := make([]ImageReport, 0)
+	for i := range pods {
+		for _, status := range pods[i].Status.ContainerStatuses {
+			ref, ok := parseImageID(status.ImageID)
+			if !ok || ref.host != s.quay.Host() || seen[ref.String()] {
+				continue
+			}
+			seen[ref.String()] = true
+			report, err := s.scan(ctx, ref)
+			if err != nil {
+				s.log.WithError(err).Debug("Skipping image without a Quay scan")
+				continue
+			}
+			reports = append(reports, report)
+		}
+	}
+	return reports, nil
+}
+
+// workloadPods returns the pods of a workload resource
+func (s *Scanner) workloadPods(ctx context.Context, namespace, resource string) ([]corev1.Pod, error) {
+	kind, name, ok := strings.Cut(resource, "/")
+	if !ok || name == "" {
+		return nil, nil
+	}
+
+	var selector *metav1.LabelSelector
+	switch strings.ToLower(kind) {
+	case "pod":
+		pod, err := s.clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pod %s/%s: %w", namespace, name, err)
+		}
+		return []corev1.Pod{*pod}, nil
+	case "deployment":
+		deployment, err := s.clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get deployment %s/%s: %w", namespace, name, err)
+		}
+		selector = deployment.Spec.Selector
+	case "statefulset":
+		statefulSet, err := s.clientset.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get statefulset %s/%s: %w", namespace, name, err)
+		}
+		selector = statefulSet.Spec.Selector
+	case "daemonset":
+		daemonSet, err := s.clientset.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get daemonset %s/%s: %w", namespace, name, err)
+		}
+		selector = daemonSet.Spec.Selector
+	case "replicaset":
+		replicaSet, err := s.clientset.AppsV1().ReplicaSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get replicaset %s/%s: %w", namespace, name, err)
+		}
+		selector = replicaSet.Spec.Selector
+	default:
+		return nil, nil
+	}
+
+	matcher, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil || matcher.Empty() {
+		return nil, fmt.Errorf("%s has no usable selector", resource)
+	}
+	pods, err := s.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: matcher.String()})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods of %s: %w", resource, err)
+	}
+	return pods.Items, nil
+}
+
+// CrashingVulnerableImages returns the Quay images with critical vulnerabilities whose
+// containers keep crashing in a namespace (all namespaces when empty), most restarts
+// first. Containers killed for running out of memory are not counted: their limits,
+// not their image, explain the crashes.
+func (s *Scanner) CrashingVulnerableImages(ctx context.Context, namespace string) ([]CrashingImage, error) {
+	pods, err := s.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	byImage := make(map[string]*CrashingImage)
+	refs := make(map[string]reference)
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		for _, status := range pod.Status.ContainerStatuses {
+			reason, crashing := s.crashReason(status)
+			if !crashing {
+				continue
+			}
+			ref, ok := parseImageID(status.ImageID)
+			if !ok || ref.host != s.quay.Host() {
+				continue
+			}
+			key := pod.Namespace + "/" + status.Name + "/" + ref.String()
+			crash, ok := byImage[key]
+			if !ok {
+				crash = &CrashingImage{Namespace: pod.Namespace, Container: status.Name}
+				byImage[key] = crash
+				refs[key] = ref
+			}
+			crash.Pods = append(crash.Pods, pod.Name)
+			crash.Restarts += status.RestartCount
+			crash.Reason = reason
+		}
+	}
+
+	crashes := make([]CrashingImage, 0)
+	for key, crash := range byImage {
+		report, err := s.scan(ctx, refs[key])
+		if err != nil {
+			s.log.WithError(err).Debug("Skipping crashing image without a Quay scan")
+			continue
+		}
+		if len(report.Critical) == 0 {
+			continue
+		}
+		crash.Report = report
+		sort.Strings(crash.Pods)
+		crashes = append(crashes, *crash)
+	}
+	sort.Slice(crashes, func(i, j int) bool {
+		if crashes[i].Restarts != crashes[j].Restarts {
+			return crashes[i].Restarts > crashes[j].Restarts
+		}
+		if crashes[i].Namespace != crashes[j].Namespace {
+			return crashes[i].Namespace < crashes[j].Namespace
+		}
+		return crashes[i].Container < crashes[j].Container
+	})
+	return crashes, nil
+}
+
+// crashReason reports whether a container keeps crashing and why
+func (s *Scanner) crashReason(status corev1.ContainerStatus) (string, bool) {
+	var reason string
+	if terminated := status.LastTerminationState.Terminated; terminated != nil {
+		reason = terminated.Reason
+	}
+	if reason == "OOMKilled" {
+		return "", false
+	}
+	if waiting := status.State.Waiting; waiting != nil && waiting.Reason == "CrashLoopBackOff" {
+		return waiting.Reason, true
+	}
+	return reason, status.RestartCount >= s.minRestarts
+}
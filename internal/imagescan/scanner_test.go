@@ -0,0 +1,112 @@
+package imagescan
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func testPod(name, imageID string, status corev1.ContainerStatus) *corev1.Pod {
+	status.Name = "api"
+	status.ImageID = imageID
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop", Labels: map[string]string{"app": "api"}},
+		Status:     corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{status}},
+	}
+}
+
+func TestParseImageID(t *testing.T) {
+	ref, ok := parseImageID("docker-pullable://quay.io/acme/api@sha256:abc")
+	require.True(t, ok)
+	assert.Equal(t, reference{host: "quay.io", repository: "acme/api", digest: "sha256:abc"}, ref)
+	assert.Equal(t, "quay.io/acme/api@sha256:abc", ref.String())
+
+	_, ok = parseImageID("quay.io/acme/api:latest")
+	assert.False(t, ok, "no digest")
+	_, ok = parseImageID("library/nginx@sha256:abc")
+	assert.False(t, ok, "no registry host")
+}
+
+func TestScanner_WorkloadImages(t *testing.T) {
+	quay, requests := quayServer(t, securityResponse)
+	image := quay.Host() + "/acme/api@sha256:abc"
+	clientset := fake.NewSimpleClientset(
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "shop"},
+			Spec:       appsv1.DeploymentSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "api"}}},
+		},
+		testPod("api-1", image, corev1.ContainerStatus{}),
+		testPod("api-2", image, corev1.ContainerStatus{}),
+		testPod("api-3", "docker.io/library/nginx@sha256:def", corev1.ContainerStatus{}),
+	)
+	scanner := NewScanner(quay, clientset, 3, time.Hour, logrus.New())
+
+	reports, err := scanner.WorkloadImages(context.Background(), "shop", "deployment/api")
+	require.NoError(t, err)
+	require.Len(t, reports, 1, "images on other registries are not looked up")
+	assert.Equal(t, image, reports[0].Image)
+	assert.Equal(t, StatusScanned, reports[0].Status)
+	assert.Equal(t, []string{"CVE-2021-3999", "CVE-2023-0286"}, reports[0].CriticalIDs())
+
+	// Scans are cached per digest
+	reports, err = scanner.WorkloadImages(context.Background(), "shop", "pod/api-1")
+	require.NoError(t, err)
+	require.Len(t, reports, 1)
+	assert.Equal(t, int32(1), *requests)
+
+	reports, err = scanner.WorkloadImages(context.Background(), "shop", "service/api")
+	require.NoError(t, err)
+	assert.Empty(t, reports)
+
+	_, err = scanner.WorkloadImages(context.Background(), "shop", "deployment/missing")
+	assert.ErrorContains(t, err, "failed to get deployment shop/missing")
+}
+
+func TestScanner_CrashingVulnerableImages(t *testing.T) {
+	quay, _ := quayServer(t, securityResponse)
+	image := quay.Host() + "/acme/api@sha256:abc"
+	crashLoop := corev1.ContainerStatus{
+		RestartCount: 4,
+		State:        corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+	}
+	clientset := fake.NewSimpleClientset(
+		testPod("api-1", image, crashLoop),
+		testPod("api-2", image, corev1.ContainerStatus{
+			RestartCount:         5,
+			LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "Error"}},
+		}),
+		// Killed for its memory limit, and restarted too rarely to count
+		testPod("api-3", image, corev1.ContainerStatus{
+			RestartCount:         9,
+			LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled"}},
+		}),
+		testPod("api-4", image, corev1.ContainerStatus{RestartCount: 1}),
+	)
+	scanner := NewScanner(quay, clientset, 3, time.Hour, logrus.New())
+
+	crashes, err := scanner.CrashingVulnerableImages(context.Background(), "")
+	require.NoError(t, err)
+	require.Len(t, crashes, 1)
+	crash := crashes[0]
+	assert.Equal(t, "shop", crash.Namespace)
+	assert.Equal(t, "api", crash.Container)
+	assert.Equal(t, []string{"api-1", "api-2"}, crash.Pods)
+	assert.Equal(t, int32(9), crash.Restarts)
+	assert.Equal(t, image, crash.Report.Image)
+	assert.Len(t, crash.Report.Critical, 2)
+
+	// Images without critical vulnerabilities are not reported
+	quay, _ = quayServer(t, `{"status": "scanned", "data": {"Layer": {"Features": []}}}`)
+	scanner = NewScanner(quay, fake.NewSimpleClientset(testPod("api-1", quay.Host()+"/acme/api@sha256:abc", crashLoop)), 3, time.Hour, logrus.New())
+	crashes, err = scanner.CrashingVulnerableImages(context.Background(), "shop")
+	require.NoError(t, err)
+	assert.Empty(t, crashes)
+}
@@ -0,0 +1,288 @@
+package integrations
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/promql"
+)
+
+// APIServerVerbs are the API server request verbs broken down by GetAPIServerQPSDetailed
+var APIServerVerbs = []string{"GET", "LIST", "WATCH", "CREATE", "UPDATE", "DELETE", "PATCH"}
+
+// APIServerVerbBaselineWindow is the window per-verb request rate baselines are learned
+// over. It ends an hour ago so an ongoing storm does not raise its own baseline.
+const APIServerVerbBaselineWindow = "7d"
+
+// Thresholds a verb's request rate must all exceed to be anomalous
+const (
+	// apiServerVerbMinQPS ignores verbs too quiet for their surges to matter
+	apiServerVerbMinQPS = 1.0
+
+	// apiServerVerbMinRatio is the fewest times the baseline mean
+	apiServerVerbMinRatio = 2.0
+
+	// apiServerVerbMinZScore is the fewest standard deviations above the baseline mean
+	apiServerVerbMinZScore = 3.0
+
+	// apiServerVerbCriticalRatio is the ratio to the baseline mean from which an
+	// anomaly is critical rather than a warning
+	apiServerVerbCriticalRatio = 5.0
+)
+
+// Kinds of per-verb API server anomalies
+const (
+	APIServerListStorm  = "list_storm"
+	APIServerWatchChurn = "watch_churn"
+	APIServerGetSurge   = "get_surge"
+	APIServerWriteSurge = "write_surge"
+)
+
+// apiServerAnomalyKinds describe each kind of anomaly in explanations and name the
+// action recommended for it
+var apiServerAnomalyKinds = map[string]struct{ title, action string }{
+	APIServerListStorm:  {"LIST storm", "investigate_list_storm"},
+	APIServerWatchChurn: {"WATCH churn", "investigate_watch_churn"},
+	APIServerGetSurge:   {"GET surge", "investigate_client_polling"},
+	APIServerWriteSurge: {"write surge", "investigate_controller_hot_loop"},
+}
+
+// APIServerVerbRate is the current request rate of a verb and its learned baseline
+type APIServerVerbRate struct {
+	Verb string  `json:"verb"`
+	QPS  float64 `json:"qps"`
+
+	// BaselineQPS and BaselineStdDev are the mean and standard deviation of the rate
+	// over APIServerVerbBaselineWindow, unset when no baseline was learned
+	BaselineQPS    float64 `json:"baseline_qps"`
+	BaselineStdDev float64 `json:"baseline_stddev"`
+	HasBaseline    bool    `json:"has_baseline"`
+
+	// BaselineImported is set when Prometheus has no baseline for the verb yet and one
+	// imported from another cluster is used instead
+	BaselineImported bool `json:"baseline_imported,omitempty"`
+}
+
+// APIServerVerbBaseline is the learned request rate of a verb
+type APIServerVerbBaseline struct {
+	Verb      string  `json:"verb"`
+	MeanQPS   float64 `json:"mean_qps"`
+	StdDevQPS float64 `json:"stddev_qps"`
+}
+
+// BaselineFallback provides verb baselines, e.g. imported from another cluster, for
+// verbs Prometheus has not learned a baseline for yet
+type BaselineFallback interface {
+	APIServerVerbBaseline(verb string) (APIServerVerbBaseline, bool)
+}
+
+// APIServerClient is a client sending requests of an anomalous verb, identified by the
+// client label of apiserver_request_total (its user agent)
+type APIServerClient struct {
+	Client string  `json:"client"`
+	QPS    float64 `json:"qps"`
+}
+
+// APIServerVerbAnomaly is a verb whose request rate is far above its baseline, such as a
+// LIST storm or WATCH churn from a misbehaving controller
+type APIServerVerbAnomaly struct {
+	Verb     string  `json:"verb"`
+	Kind     string  `json:"kind"`
+	Severity string  `json:"severity"` // critical, warning
+	QPS      float64 `json:"qps"`
+
+	BaselineQPS float64 `json:"baseline_qps"`
+	Ratio       float64 `json:"ratio"`
+
+	// ZScore is unset when the baseline has no variance
+	ZScore float64 `json:"z_score,omitempty"`
+
+	Explanation       string `json:"explanation"`
+	RecommendedAction string `json:"recommended_action"`
+
+	// TopClients are the clients sending the most requests of the verb, empty when the
+	// API server does not record the client label
+	TopClients []APIServerClient `json:"top_clients,omitempty"`
+}
+
+// APIServerVerbAnalysis is the per-verb API server request rates compared with their
+// baselines
+type APIServerVerbAnalysis struct {
+	BaselineWindow string                 `json:"baseline_window"`
+	Verbs          []APIServerVerbRate    `json:"verbs"`
+	Anomalies      []APIServerVerbAnomaly `json:"anomalies"`
+}
+
+// apiServerAnomalyKind returns the kind of anomaly of a verb's surge
+func apiServerAnomalyKind(verb string) string {
+	switch verb {
+	case "LIST":
+		return APIServerListStorm
+	case "WATCH":
+		return APIServerWatchChurn
+	case "GET":
+		return APIServerGetSurge
+	}
+	return APIServerWriteSurge
+}
+
+// AnalyzeAPIServerVerbs compares the current request rate of each verb from
+// GetAPIServerQPSDetailed with its baseline over APIServerVerbBaselineWindow. Verbs
+// without a baseline are reported but never anomalous.
+func (c *PrometheusClient) AnalyzeAPIServerVerbs(ctx context.Context) (*APIServerVerbAnalysis, error) {
+	current, err := c.GetAPIServerQPSDetailed(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	baselines, err := c.APIServerVerbBaselines(ctx)
+	if err != nil {
+		return nil, err
+	}
+	learned := make(map[string]APIServerVerbBaseline, len(baselines))
+	for _, baseline := range baselines {
+		learned[baseline.Verb] = baseline
+	}
+
+	analysis := &APIServerVerbAnalysis{
+		BaselineWindow: APIServerVerbBaselineWindow,
+		Verbs:          make([]APIServerVerbRate, 0, len(APIServerVerbs)),
+		Anomalies:      make([]APIServerVerbAnomaly, 0),
+	}
+	for _, verb := range APIServerVerbs {
+		qps, ok := current[strings.ToLower(verb)]
+		if !ok {
+			continue
+		}
+		rate := APIServerVerbRate{Verb: verb, QPS: qps}
+		baseline, ok := learned[verb]
+		if !ok && c.baselineFallback != nil {
+			baseline, ok = c.baselineFallback.APIServerVerbBaseline(verb)
+			rate.BaselineImported = ok
+		}
+		if ok {
+			rate.BaselineQPS, rate.BaselineStdDev, rate.HasBaseline = baseline.MeanQPS, baseline.StdDevQPS, true
+		}
+		analysis.Verbs = append(analysis.Verbs, rate)
+
+		if anomaly, ok := detectAPIServerVerbAnomaly(rate); ok {
+			anomaly.TopClients = c.topAPIServerClients(ctx, verb)
+			anomaly.Explanation = explainAPIServerVerbAnomaly(anomaly)
+			analysis.Anomalies = append(analysis.Anomalies, anomaly)
+		}
+	}
+
+	sort.SliceStable(analysis.Anomalies, func(i, j int) bool {
+		return analysis.Anomalies[i].Ratio > analysis.Anomalies[j].Ratio
+	})
+	return analysis, nil
+}
+
+// APIServerVerbBaselines returns the request rate baselines Prometheus has learned over
+// APIServerVerbBaselineWindow, for the verbs in APIServerVerbs it has data for
+func (c *PrometheusClient) APIServerVerbBaselines(ctx context.Context) ([]APIServerVerbBaseline, error) {
+	params := promql.Params{Window: APIServerVerbBaselineWindow}
+	means, err := c.QueryVector(ctx, c.Queries().MustRender("apiserver.request_rate_baseline_mean", params))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query API server request rate baselines: %w", err)
+	}
+	stddevs, err := c.QueryVector(ctx, c.Queries().MustRender("apiserver.request_rate_baseline_stddev", params))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query API server request rate baselines: %w", err)
+	}
+	mean := samplesByLabel(means, "verb")
+	stddev := samplesByLabel(stddevs, "verb")
+
+	baselines := make([]APIServerVerbBaseline, 0, len(APIServerVerbs))
+	for _, verb := range APIServerVerbs {
+		if value, ok := mean[verb]; ok {
+			baselines = append(baselines, APIServerVerbBaseline{Verb: verb, MeanQPS: value, StdDevQPS: stddev[verb]})
+		}
+	}
+	return baselines, nil
+}
+
+// SetBaselineFallback sets the verb baselines used until Prometheus has learned its own
+func (c *PrometheusClient) SetBaselineFallback(fallback BaselineFallback) {
+	c.baselineFallback = fallback
+}
+
+// detectAPIServerVerbAnomaly reports whether a verb's rate is far enough above its
+// baseline to be anomalous
+func detectAPIServerVerbAnomaly(rate APIServerVerbRate) (APIServerVerbAnomaly, bool) {
+	if !rate.HasBaseline || rate.BaselineQPS <= 0 || rate.QPS < apiServerVerbMinQPS {
+		return APIServerVerbAnomaly{}, false
+	}
+	ratio := rate.QPS / rate.BaselineQPS
+	if ratio < apiServerVerbMinRatio {
+		return APIServerVerbAnomaly{}, false
+	}
+	var zScore float64
+	if rate.BaselineStdDev > 0 {
+		zScore = (rate.QPS - rate.BaselineQPS) / rate.BaselineStdDev
+		if zScore < apiServerVerbMinZScore {
+			return APIServerVerbAnomaly{}, false
+		}
+	}
+
+	kind := apiServerAnomalyKind(rate.Verb)
+	severity := "warning"
+	if ratio >= apiServerVerbCriticalRatio {
+		severity = "critical"
+	}
+	return APIServerVerbAnomaly{
+		Verb:              rate.Verb,
+		Kind:              kind,
+		Severity:          severity,
+		QPS:               rate.QPS,
+		BaselineQPS:       rate.BaselineQPS,
+		Ratio:             math.Round(ratio*100) / 100,
+		ZScore:            math.Round(zScore*100) / 100,
+		RecommendedAction: apiServerAnomalyKinds[kind].action,
+	}, true
+}
+
+// topAPIServerClients returns the clients sending the most requests of a verb, nil when
+// they cannot be queried
+func (c *PrometheusClient) topAPIServerClients(ctx context.Context, verb string) []APIServerClient {
+	samples, err := c.QueryVector(ctx, c.Queries().MustRender("apiserver.top_clients_by_verb", promql.Params{Verb: verb}))
+	if err != nil {
+		c.log.WithError(err).WithField("verb", verb).Debug("Failed to query top API server clients")
+		return nil
+	}
+	clients := make([]APIServerClient, 0, len(samples))
+	for _, sample := range samples {
+		if sample.Labels["client"] == "" {
+			continue
+		}
+		clients = append(clients, APIServerClient{Client: sample.Labels["client"], QPS: sample.Value})
+	}
+	sort.SliceStable(clients, func(i, j int) bool { return clients[i].QPS > clients[j].QPS })
+	return clients
+}
+
+// explainAPIServerVerbAnomaly describes an anomaly and its top clients
+func explainAPIServerVerbAnomaly(anomaly APIServerVerbAnomaly) string {
+	explanation := fmt.Sprintf("%s: %.1f requests/s against a %s baseline of %.1f (%.1fx)",
+		apiServerAnomalyKinds[anomaly.Kind].title, anomaly.QPS, APIServerVerbBaselineWindow, anomaly.BaselineQPS, anomaly.Ratio)
+	if len(anomaly.TopClients) == 0 {
+		return explanation
+	}
+	clients := make([]string, 0, len(anomaly.TopClients))
+	for _, client := range anomaly.TopClients {
+		clients = append(clients, fmt.Sprintf("%s (%.1f/s)", client.Client, client.QPS))
+	}
+	return explanation + "; top clients: " + strings.Join(clients, ", ")
+}
+
+// samplesByLabel indexes samples by the value of a label
+func samplesByLabel(samples []VectorSample, label string) map[string]float64 {
+	values := make(map[string]float64, len(samples))
+	for _, sample := range samples {
+		values[sample.Labels[label]] = sample.Value
+	}
+	return values
+}
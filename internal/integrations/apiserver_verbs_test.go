@@ -0,0 +1,129 @@
+package integrations
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// apiServerVerbServer serves per-verb rates, their baselines and the top LIST clients
+func apiServerVerbServer(t *testing.T, current map[string]string) *PrometheusClient {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query().Get("query")
+		w.Header().Set("Content-Type", "application/json")
+		var result string
+		switch {
+		case strings.HasPrefix(query, "avg_over_time"):
+			result = `{"metric":{"verb":"LIST"},"value":[1700000000,"40"]},{"metric":{"verb":"WATCH"},"value":[1700000000,"10"]},{"metric":{"verb":"GET"},"value":[1700000000,"100"]}`
+		case strings.HasPrefix(query, "stddev_over_time"):
+			result = `{"metric":{"verb":"LIST"},"value":[1700000000,"5"]},{"metric":{"verb":"WATCH"},"value":[1700000000,"0"]},{"metric":{"verb":"GET"},"value":[1700000000,"50"]}`
+		case strings.HasPrefix(query, "topk"):
+			if strings.Contains(query, `verb="LIST"`) {
+				result = `{"metric":{"client":"kube-controller-manager/v1.29.0"},"value":[1700000000,"20"]},{"metric":{"client":"rogue-operator/v0.1"},"value":[1700000000,"380"]}`
+			}
+		default:
+			value := "600"
+			for verb, qps := range current {
+				if strings.Contains(query, `verb="`+verb+`"`) {
+					value = qps
+				}
+			}
+			result = `{"metric":{},"value":[1700000000,"` + value + `"]}`
+		}
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[` + result + `]}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	return NewPrometheusClient(server.URL, 5*time.Second, log)
+}
+
+func TestAnalyzeAPIServerVerbs(t *testing.T) {
+	// A LIST storm at 10x its baseline and WATCH churn at 3x a flat baseline; GET is
+	// within its normal variance and the other verbs have no baseline
+	client := apiServerVerbServer(t, map[string]string{"LIST": "400", "WATCH": "30", "GET": "220", "CREATE": "5"})
+
+	analysis, err := client.AnalyzeAPIServerVerbs(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "7d", analysis.BaselineWindow)
+	require.Len(t, analysis.Verbs, len(APIServerVerbs))
+	assert.Equal(t, APIServerVerbRate{Verb: "LIST", QPS: 400, BaselineQPS: 40, BaselineStdDev: 5, HasBaseline: true}, analysis.Verbs[1])
+	assert.False(t, analysis.Verbs[3].HasBaseline)
+
+	require.Len(t, analysis.Anomalies, 2)
+	storm := analysis.Anomalies[0]
+	assert.Equal(t, APIServerListStorm, storm.Kind)
+	assert.Equal(t, "critical", storm.Severity)
+	assert.Equal(t, 10.0, storm.Ratio)
+	assert.Equal(t, 72.0, storm.ZScore)
+	assert.Equal(t, "investigate_list_storm", storm.RecommendedAction)
+	assert.Equal(t, []APIServerClient{
+		{Client: "rogue-operator/v0.1", QPS: 380},
+		{Client: "kube-controller-manager/v1.29.0", QPS: 20},
+	}, storm.TopClients)
+	assert.Equal(t, "LIST storm: 400.0 requests/s against a 7d baseline of 40.0 (10.0x); top clients: rogue-operator/v0.1 (380.0/s), kube-controller-manager/v1.29.0 (20.0/s)", storm.Explanation)
+
+	churn := analysis.Anomalies[1]
+	assert.Equal(t, APIServerWatchChurn, churn.Kind)
+	assert.Equal(t, "warning", churn.Severity)
+	assert.Zero(t, churn.ZScore, "a flat baseline has no z-score")
+	assert.Empty(t, churn.TopClients, "no client label recorded")
+	assert.Equal(t, "WATCH churn: 30.0 requests/s against a 7d baseline of 10.0 (3.0x)", churn.Explanation)
+}
+
+// verbBaselines is a BaselineFallback of fixed baselines
+type verbBaselines map[string]APIServerVerbBaseline
+
+func (b verbBaselines) APIServerVerbBaseline(verb string) (APIServerVerbBaseline, bool) {
+	baseline, ok := b[verb]
+	return baseline, ok
+}
+
+func TestAnalyzeAPIServerVerbs_BaselineFallback(t *testing.T) {
+	client := apiServerVerbServer(t, map[string]string{"LIST": "40", "WATCH": "10", "GET": "100", "CREATE": "5"})
+
+	baselines, err := client.APIServerVerbBaselines(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []APIServerVerbBaseline{
+		{Verb: "GET", MeanQPS: 100, StdDevQPS: 50},
+		{Verb: "LIST", MeanQPS: 40, StdDevQPS: 5},
+		{Verb: "WATCH", MeanQPS: 10},
+	}, baselines)
+
+	// Imported baselines stand in for verbs Prometheus has not learned yet
+	client.SetBaselineFallback(verbBaselines{
+		"CREATE": {Verb: "CREATE", MeanQPS: 1, StdDevQPS: 0.5},
+		"LIST":   {Verb: "LIST", MeanQPS: 1},
+	})
+	analysis, err := client.AnalyzeAPIServerVerbs(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, APIServerVerbRate{Verb: "LIST", QPS: 40, BaselineQPS: 40, BaselineStdDev: 5, HasBaseline: true}, analysis.Verbs[1],
+		"learned baselines take precedence")
+	assert.Equal(t, APIServerVerbRate{Verb: "CREATE", QPS: 5, BaselineQPS: 1, BaselineStdDev: 0.5, HasBaseline: true, BaselineImported: true}, analysis.Verbs[3])
+
+	require.Len(t, analysis.Anomalies, 1)
+	assert.Equal(t, APIServerWriteSurge, analysis.Anomalies[0].Kind)
+	assert.Equal(t, 8.0, analysis.Anomalies[0].ZScore)
+}
+
+func TestDetectAPIServerVerbAnomaly(t *testing.T) {
+	_, ok := detectAPIServerVerbAnomaly(APIServerVerbRate{Verb: "PATCH", QPS: 0.5, BaselineQPS: 0.01, HasBaseline: true})
+	assert.False(t, ok, "too quiet to matter")
+
+	_, ok = detectAPIServerVerbAnomaly(APIServerVerbRate{Verb: "PATCH", QPS: 50, HasBaseline: false})
+	assert.False(t, ok, "no baseline")
+
+	anomaly, ok := detectAPIServerVerbAnomaly(APIServerVerbRate{Verb: "PATCH", QPS: 50, BaselineQPS: 20, BaselineStdDev: 2, HasBaseline: true})
+	require.True(t, ok)
+	assert.Equal(t, APIServerWriteSurge, anomaly.Kind)
+	assert.Equal(t, "investigate_controller_hot_loop", anomaly.RecommendedAction)
+}
@@ -33,6 +33,11 @@ func NewArgoCDClient(baseURL, token string, log *logrus.Logger) *ArgoCDClient {
 	}
 }
 
+// HTTPClient returns the client used to reach the Argo CD API, e.g. to audit its TLS configuration
+func (c *ArgoCDClient) HTTPClient() *http.Client {
+	return c.httpClient
+}
+
 // Application represents an ArgoCD application
 type Application struct {
 	Metadata ApplicationMetadata `json:"metadata"`
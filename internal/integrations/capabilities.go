@@ -0,0 +1,169 @@
+package integrations
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/promql"
+)
+
+// Exporters whose metric families the engine probes for
+const (
+	ExporterKubeStateMetrics = "kube-state-metrics"
+	ExporterNodeExporter     = "node-exporter"
+	ExporterCAdvisor         = "cadvisor"
+	ExporterDCGM             = "dcgm"
+	ExporterIstio            = "istio"
+	ExporterBlackbox         = "blackbox-exporter"
+	ExporterCoreDNS          = "coredns"
+)
+
+// exporterProbes maps each exporter to a metric family only it provides. Optional
+// exporters are not needed by the built-in queries, so missing them is not a problem.
+var exporterProbes = []struct {
+	exporter string
+	metric   string
+	optional bool
+}{
+	{ExporterKubeStateMetrics, "kube_pod_info", false},
+	{ExporterNodeExporter, "node_cpu_seconds_total", false},
+	{ExporterCAdvisor, "container_cpu_usage_seconds_total", false},
+	{ExporterDCGM, "DCGM_FI_DEV_GPU_UTIL", true},
+	{ExporterIstio, "istio_requests_total", true},
+	{ExporterBlackbox, "probe_success", true},
+	{ExporterCoreDNS, "coredns_dns_responses_total", true},
+}
+
+// MetricExporters lists the exporters each anomaly base metric is computed from
+var MetricExporters = map[string][]string{
+	"node_cpu_utilization":    {ExporterNodeExporter},
+	"node_memory_utilization": {ExporterNodeExporter},
+	"pod_cpu_usage":           {ExporterCAdvisor},
+	"pod_memory_usage":        {ExporterCAdvisor, ExporterKubeStateMetrics},
+	"container_restart_count": {ExporterKubeStateMetrics},
+}
+
+// ExporterStatus is the probe result of one exporter
+type ExporterStatus struct {
+	Exporter  string `json:"exporter"`
+	Metric    string `json:"metric"`
+	Available bool   `json:"available"`
+	Optional  bool   `json:"optional,omitempty"`
+
+	// Error is set when the probe query failed, so availability is unknown
+	Error string `json:"error,omitempty"`
+}
+
+// Capabilities maps exporters to whether Prometheus holds their metrics
+type Capabilities struct {
+	ProbedAt  time.Time                 `json:"probed_at"`
+	Exporters map[string]ExporterStatus `json:"exporters"`
+}
+
+// Missing returns the exporters among the given ones that were probed successfully
+// and found absent. Exporters that were not probed, or whose probe failed, are
+// assumed present.
+func (c *Capabilities) Missing(exporters ...string) []string {
+	if c == nil {
+		return nil
+	}
+	var missing []string
+	for _, exporter := range exporters {
+		status, ok := c.Exporters[exporter]
+		if ok && status.Error == "" && !status.Available {
+			missing = append(missing, exporter)
+		}
+	}
+	return missing
+}
+
+// Statuses returns the exporter statuses sorted by exporter name
+func (c *Capabilities) Statuses() []ExporterStatus {
+	if c == nil {
+		return nil
+	}
+	statuses := make([]ExporterStatus, 0, len(c.Exporters))
+	for _, status := range c.Exporters {
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Exporter < statuses[j].Exporter })
+	return statuses
+}
+
+// ProbeCapabilities checks which exporters' metric families exist in Prometheus and
+// stores the result, which handlers use to skip queries that cannot return data
+func (c *PrometheusClient) ProbeCapabilities(ctx context.Context) *Capabilities {
+	capabilities := &Capabilities{
+		ProbedAt:  c.currentTime().UTC(),
+		Exporters: make(map[string]ExporterStatus, len(exporterProbes)),
+	}
+	for _, probe := range exporterProbes {
+		status := ExporterStatus{Exporter: probe.exporter, Metric: probe.metric, Optional: probe.optional}
+		samples, err := c.QueryVector(ctx, c.Queries().MustRender("probe.series_count", promql.Params{Metric: probe.metric}))
+		if err != nil {
+			status.Error = err.Error()
+		} else {
+			status.Available = len(samples) > 0
+		}
+		capabilities.Exporters[probe.exporter] = status
+	}
+
+	c.capabilitiesMu.Lock()
+	c.capabilities = capabilities
+	c.capabilitiesMu.Unlock()
+	return capabilities
+}
+
+// Capabilities returns the result of the last probe, or nil before the first one
+func (c *PrometheusClient) Capabilities() *Capabilities {
+	if c == nil {
+		return nil
+	}
+	c.capabilitiesMu.RLock()
+	defer c.capabilitiesMu.RUnlock()
+	return c.capabilities
+}
+
+// MissingExporters returns the given exporters that the last probe found absent
+func (c *PrometheusClient) MissingExporters(exporters ...string) []string {
+	return c.Capabilities().Missing(exporters...)
+}
+
+// StartCapabilityProbe probes the exporters now and then every interval until ctx is
+// done; an interval of 0 probes once
+func (c *PrometheusClient) StartCapabilityProbe(ctx context.Context, interval time.Duration) {
+	probe := func() {
+		capabilities := c.ProbeCapabilities(ctx)
+		for _, status := range capabilities.Statuses() {
+			entry := c.log.WithFields(logrus.Fields{"exporter": status.Exporter, "metric": status.Metric})
+			switch {
+			case status.Error != "":
+				entry.WithField("error", status.Error).Warn("Failed to probe metric exporter")
+			case !status.Available && !status.Optional:
+				entry.Warn("Metric exporter not found in Prometheus, dependent features will use defaults")
+			default:
+				entry.WithField("available", status.Available).Debug("Probed metric exporter")
+			}
+		}
+	}
+
+	go func() {
+		probe()
+		if interval <= 0 {
+			return
+		}
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				probe()
+			}
+		}
+	}()
+}
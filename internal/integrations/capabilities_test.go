@@ -0,0 +1,60 @@
+package integrations
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrometheusClient_ProbeCapabilities(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("query") {
+		case "count(kube_pod_info)", "count(istio_requests_total)":
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[]}}`))
+		case "count(node_cpu_seconds_total)":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1700000000,"42"]}]}}`))
+		}
+	}))
+	defer server.Close()
+
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	client := NewPrometheusClient(server.URL, 5*time.Second, log)
+	assert.Nil(t, client.Capabilities(), "nothing is known before the first probe")
+	assert.Empty(t, client.MissingExporters(ExporterKubeStateMetrics))
+
+	capabilities := client.ProbeCapabilities(context.Background())
+	require.Same(t, capabilities, client.Capabilities())
+
+	statuses := capabilities.Statuses()
+	require.Len(t, statuses, len(exporterProbes))
+	assert.Equal(t, ExporterBlackbox, statuses[0].Exporter, "sorted by exporter")
+	assert.True(t, capabilities.Exporters[ExporterCAdvisor].Available)
+	assert.True(t, capabilities.Exporters[ExporterDCGM].Available)
+	assert.False(t, capabilities.Exporters[ExporterKubeStateMetrics].Available)
+	assert.True(t, capabilities.Exporters[ExporterIstio].Optional)
+	assert.NotEmpty(t, capabilities.Exporters[ExporterNodeExporter].Error)
+
+	// Exporters whose probe failed are assumed present
+	assert.Equal(t, []string{ExporterKubeStateMetrics},
+		client.MissingExporters(MetricExporters["pod_memory_usage"]...))
+	assert.Empty(t, client.MissingExporters(MetricExporters["node_cpu_utilization"]...))
+}
+
+func TestCapabilities_NilSafe(t *testing.T) {
+	var client *PrometheusClient
+	assert.Nil(t, client.Capabilities())
+	assert.Empty(t, client.MissingExporters(ExporterCAdvisor))
+
+	var capabilities *Capabilities
+	assert.Empty(t, capabilities.Statuses())
+}
@@ -0,0 +1,171 @@
+package integrations
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// ClusterOperatorClient reads OpenShift ClusterOperator conditions (read-only)
+type ClusterOperatorClient struct {
+	dynamicClient dynamic.Interface
+	log           *logrus.Logger
+}
+
+// NewClusterOperatorClient creates a new ClusterOperator status client
+func NewClusterOperatorClient(dynamicClient dynamic.Interface, log *logrus.Logger) *ClusterOperatorClient {
+	return &ClusterOperatorClient{
+		dynamicClient: dynamicClient,
+		log:           log,
+	}
+}
+
+var (
+	// ClusterOperatorGVR identifies config.openshift.io/v1 ClusterOperators
+	ClusterOperatorGVR = schema.GroupVersionResource{
+		Group:    "config.openshift.io",
+		Version:  "v1",
+		Resource: "clusteroperators",
+	}
+)
+
+// ListClusterOperators returns the status of all ClusterOperators, sorted by name
+func (c *ClusterOperatorClient) ListClusterOperators(ctx context.Context) ([]models.ClusterOperatorStatus, error) {
+	c.log.Debug("Listing ClusterOperators")
+
+	list, err := c.dynamicClient.Resource(ClusterOperatorGVR).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ClusterOperators: %w", err)
+	}
+
+	operators := make([]models.ClusterOperatorStatus, 0, len(list.Items))
+	for i := range list.Items {
+		operators = append(operators, ParseClusterOperator(&list.Items[i]))
+	}
+	sort.Slice(operators, func(i, j int) bool {
+		return operators[i].Name < operators[j].Name
+	})
+
+	c.log.WithField("count", len(operators)).Debug("ClusterOperators listed")
+
+	return operators, nil
+}
+
+// GetClusterOperator returns the status of a single ClusterOperator
+func (c *ClusterOperatorClient) GetClusterOperator(ctx context.Context, name string) (*models.ClusterOperatorStatus, error) {
+	obj, err := c.dynamicClient.Resource(ClusterOperatorGVR).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ClusterOperator %s: %w", name, err)
+	}
+
+	status := ParseClusterOperator(obj)
+	return &status, nil
+}
+
+// UnhealthyClusterOperators returns the ClusterOperators that are degraded or unavailable
+func (c *ClusterOperatorClient) UnhealthyClusterOperators(ctx context.Context) ([]models.ClusterOperatorStatus, error) {
+	operators, err := c.ListClusterOperators(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var unhealthy []models.ClusterOperatorStatus
+	for i := range operators {
+		if !operators[i].IsHealthy() {
+			unhealthy = append(unhealthy, operators[i])
+		}
+	}
+	return unhealthy, nil
+}
+
+// GetSummary returns aggregated ClusterOperator health
+func (c *ClusterOperatorClient) GetSummary(ctx context.Context) (*models.ClusterOperatorSummary, error) {
+	operators, err := c.ListClusterOperators(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := models.SummarizeClusterOperators(operators)
+
+	c.log.WithFields(logrus.Fields{
+		"total":       summary.Total,
+		"degraded":    len(summary.Degraded),
+		"unavailable": len(summary.Unavailable),
+		"progressing": len(summary.Progressing),
+	}).Debug("ClusterOperator summary computed")
+
+	return summary, nil
+}
+
+// ParseClusterOperator extracts conditions and the operator version from an
+// unstructured ClusterOperator. Missing conditions leave the flags false, so an
+// operator that reports nothing is treated as unavailable.
+func ParseClusterOperator(obj *unstructured.Unstructured) models.ClusterOperatorStatus {
+	status := models.ClusterOperatorStatus{
+		Name: obj.GetName(),
+	}
+
+	if versions, found, err := unstructured.NestedSlice(obj.Object, "status", "versions"); err == nil && found {
+		for _, v := range versions {
+			versionMap, ok := v.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if name, _, _ := unstructured.NestedString(versionMap, "name"); name == "operator" {
+				status.Version, _, _ = unstructured.NestedString(versionMap, "version")
+			}
+		}
+	}
+
+	conditions, found, err := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	if err != nil || !found {
+		return status
+	}
+
+	for _, cond := range conditions {
+		condMap, ok := cond.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		condType, typeFound, typeErr := unstructured.NestedString(condMap, "type")
+		condStatus, statusFound, statusErr := unstructured.NestedString(condMap, "status")
+		if typeErr != nil || !typeFound || statusErr != nil || !statusFound {
+			continue
+		}
+
+		condition := models.ClusterOperatorCondition{
+			Type:   condType,
+			Status: condStatus,
+		}
+		condition.Reason, _, _ = unstructured.NestedString(condMap, "reason")
+		condition.Message, _, _ = unstructured.NestedString(condMap, "message")
+		if ts, found, err := unstructured.NestedString(condMap, "lastTransitionTime"); err == nil && found {
+			if parsed, err := time.Parse(time.RFC3339, ts); err == nil {
+				condition.LastTransitionTime = parsed
+			}
+		}
+		status.Conditions = append(status.Conditions, condition)
+
+		isTrue := condStatus == "True"
+		switch condType {
+		case models.ClusterOperatorAvailable:
+			status.Available = isTrue
+		case models.ClusterOperatorDegraded:
+			status.Degraded = isTrue
+		case models.ClusterOperatorProgressing:
+			status.Progressing = isTrue
+		}
+	}
+
+	return status
+}
@@ -0,0 +1,112 @@
+package integrations
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic/fake"
+)
+
+// createClusterOperator creates a fake ClusterOperator with the given condition statuses
+func createClusterOperator(name, available, degraded, progressing, reason, message string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "config.openshift.io/v1",
+			"kind":       "ClusterOperator",
+			"metadata": map[string]interface{}{
+				"name": name,
+			},
+			"status": map[string]interface{}{
+				"versions": []interface{}{
+					map[string]interface{}{"name": "operator", "version": "4.16.3"},
+				},
+				"conditions": []interface{}{
+					map[string]interface{}{"type": "Available", "status": available},
+					map[string]interface{}{
+						"type":               "Degraded",
+						"status":             degraded,
+						"reason":             reason,
+						"message":            message,
+						"lastTransitionTime": "2026-01-15T10:00:00Z",
+					},
+					map[string]interface{}{"type": "Progressing", "status": progressing},
+				},
+			},
+		},
+	}
+}
+
+func TestParseClusterOperator(t *testing.T) {
+	status := ParseClusterOperator(createClusterOperator("network", "True", "True", "False",
+		"RolloutHung", "DaemonSet openshift-ovn-kubernetes/ovnkube-node is not making progress"))
+
+	assert.Equal(t, "network", status.Name)
+	assert.Equal(t, "4.16.3", status.Version)
+	assert.True(t, status.Available)
+	assert.True(t, status.Degraded)
+	assert.False(t, status.Progressing)
+	assert.False(t, status.IsHealthy())
+	require.Len(t, status.Conditions, 3)
+
+	degraded := status.Condition("Degraded")
+	require.NotNil(t, degraded)
+	assert.Equal(t, "RolloutHung", degraded.Reason)
+	assert.Equal(t, time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC), degraded.LastTransitionTime)
+	assert.Equal(t,
+		"ClusterOperator network: Degraded=True (RolloutHung: DaemonSet openshift-ovn-kubernetes/ovnkube-node is not making progress)",
+		status.Evidence())
+
+	// An operator without conditions is treated as unavailable
+	empty := ParseClusterOperator(&unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "dns"},
+	}})
+	assert.False(t, empty.Available)
+	assert.Equal(t, "ClusterOperator dns: Available condition not reported", empty.Evidence())
+}
+
+func TestClusterOperatorClient_Summary(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	dynamicClient := fake.NewSimpleDynamicClient(runtime.NewScheme(),
+		createClusterOperator("network", "True", "True", "False", "RolloutHung", "ovnkube-node is not making progress"),
+		createClusterOperator("authentication", "False", "False", "True", "", ""),
+		createClusterOperator("ingress", "True", "False", "False", "", ""),
+		createClusterOperator("image-registry", "True", "False", "True", "", ""),
+	)
+	client := NewClusterOperatorClient(dynamicClient, log)
+	ctx := context.Background()
+
+	operators, err := client.ListClusterOperators(ctx)
+	require.NoError(t, err)
+	require.Len(t, operators, 4)
+	assert.Equal(t, "authentication", operators[0].Name, "operators are sorted by name")
+
+	unhealthy, err := client.UnhealthyClusterOperators(ctx)
+	require.NoError(t, err)
+	require.Len(t, unhealthy, 2)
+	assert.Equal(t, "authentication", unhealthy[0].Name)
+	assert.Equal(t, "network", unhealthy[1].Name)
+
+	summary, err := client.GetSummary(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 4, summary.Total)
+	assert.Equal(t, 2, summary.Healthy)
+	assert.Equal(t, []string{"network"}, summary.Degraded)
+	assert.Equal(t, []string{"authentication"}, summary.Unavailable)
+	assert.Equal(t, []string{"authentication", "image-registry"}, summary.Progressing)
+	require.Len(t, summary.Unhealthy, 2)
+
+	status, err := client.GetClusterOperator(ctx, "ingress")
+	require.NoError(t, err)
+	assert.True(t, status.IsHealthy())
+
+	_, err = client.GetClusterOperator(ctx, "missing")
+	assert.Error(t, err)
+}
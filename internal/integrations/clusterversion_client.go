@@ -0,0 +1,116 @@
+package integrations
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// ClusterVersionName is the name of the cluster-scoped ClusterVersion singleton
+const ClusterVersionName = "version"
+
+var (
+	// ClusterVersionGVR identifies config.openshift.io/v1 ClusterVersions
+	ClusterVersionGVR = schema.GroupVersionResource{
+		Group:    "config.openshift.io",
+		Version:  "v1",
+		Resource: "clusterversions",
+	}
+)
+
+// ClusterVersionStatus is the upgrade state reported by the cluster version operator
+type ClusterVersionStatus struct {
+	// Version is the last completed version
+	Version string `json:"version,omitempty"`
+
+	// DesiredVersion is the version the cluster is moving to
+	DesiredVersion string `json:"desired_version,omitempty"`
+
+	// Progressing is true while an upgrade is rolling out
+	Progressing bool   `json:"progressing"`
+	Message     string `json:"message,omitempty"`
+
+	// Since is when the Progressing condition last changed
+	Since time.Time `json:"since,omitempty"`
+}
+
+// ClusterVersionClient reads the OpenShift ClusterVersion (read-only)
+type ClusterVersionClient struct {
+	dynamicClient dynamic.Interface
+	log           *logrus.Logger
+}
+
+// NewClusterVersionClient creates a new ClusterVersion client
+func NewClusterVersionClient(dynamicClient dynamic.Interface, log *logrus.Logger) *ClusterVersionClient {
+	return &ClusterVersionClient{
+		dynamicClient: dynamicClient,
+		log:           log,
+	}
+}
+
+// GetStatus returns the current upgrade state of the cluster
+func (c *ClusterVersionClient) GetStatus(ctx context.Context) (*ClusterVersionStatus, error) {
+	obj, err := c.dynamicClient.Resource(ClusterVersionGVR).Get(ctx, ClusterVersionName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ClusterVersion: %w", err)
+	}
+
+	status := ParseClusterVersion(obj)
+
+	c.log.WithFields(logrus.Fields{
+		"version":         status.Version,
+		"desired_version": status.DesiredVersion,
+		"progressing":     status.Progressing,
+	}).Debug("ClusterVersion status retrieved")
+
+	return status, nil
+}
+
+// ParseClusterVersion extracts the completed and desired versions and the
+// Progressing condition from an unstructured ClusterVersion
+func ParseClusterVersion(obj *unstructured.Unstructured) *ClusterVersionStatus {
+	status := &ClusterVersionStatus{}
+	status.DesiredVersion, _, _ = unstructured.NestedString(obj.Object, "status", "desired", "version")
+
+	// History is newest first; the first completed entry is the running version
+	if history, found, err := unstructured.NestedSlice(obj.Object, "status", "history"); err == nil && found {
+		for _, h := range history {
+			entry, ok := h.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if state, _, _ := unstructured.NestedString(entry, "state"); state == "Completed" {
+				status.Version, _, _ = unstructured.NestedString(entry, "version")
+				break
+			}
+		}
+	}
+
+	if conditions, found, err := unstructured.NestedSlice(obj.Object, "status", "conditions"); err == nil && found {
+		for _, cond := range conditions {
+			condMap, ok := cond.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if condType, _, _ := unstructured.NestedString(condMap, "type"); condType != "Progressing" {
+				continue
+			}
+			condStatus, _, _ := unstructured.NestedString(condMap, "status")
+			status.Progressing = condStatus == "True"
+			status.Message, _, _ = unstructured.NestedString(condMap, "message")
+			if ts, found, err := unstructured.NestedString(condMap, "lastTransitionTime"); err == nil && found {
+				if parsed, err := time.Parse(time.RFC3339, ts); err == nil {
+					status.Since = parsed
+				}
+			}
+		}
+	}
+
+	return status
+}
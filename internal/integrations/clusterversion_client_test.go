@@ -0,0 +1,63 @@
+package integrations
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic/fake"
+)
+
+func createClusterVersion(progressing string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "config.openshift.io/v1",
+			"kind":       "ClusterVersion",
+			"metadata": map[string]interface{}{
+				"name": "version",
+			},
+			"status": map[string]interface{}{
+				"desired": map[string]interface{}{"version": "4.16.5"},
+				"history": []interface{}{
+					map[string]interface{}{"state": "Partial", "version": "4.16.5"},
+					map[string]interface{}{"state": "Completed", "version": "4.16.3"},
+				},
+				"conditions": []interface{}{
+					map[string]interface{}{"type": "Available", "status": "True"},
+					map[string]interface{}{
+						"type":               "Progressing",
+						"status":             progressing,
+						"message":            "Working towards 4.16.5: 512 of 845 done (60% complete)",
+						"lastTransitionTime": "2026-01-15T10:00:00Z",
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestClusterVersionClient_GetStatus(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	ctx := context.Background()
+
+	client := NewClusterVersionClient(fake.NewSimpleDynamicClient(runtime.NewScheme(), createClusterVersion("True")), log)
+	status, err := client.GetStatus(ctx)
+	require.NoError(t, err)
+	assert.True(t, status.Progressing)
+	assert.Equal(t, "4.16.3", status.Version)
+	assert.Equal(t, "4.16.5", status.DesiredVersion)
+	assert.Contains(t, status.Message, "Working towards 4.16.5")
+	assert.Equal(t, time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC), status.Since)
+
+	status = ParseClusterVersion(createClusterVersion("False"))
+	assert.False(t, status.Progressing)
+
+	_, err = NewClusterVersionClient(fake.NewSimpleDynamicClient(runtime.NewScheme()), log).GetStatus(ctx)
+	assert.Error(t, err)
+}
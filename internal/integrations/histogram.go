@@ -0,0 +1,72 @@
+package integrations
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/promql"
+)
+
+var (
+	featureNamePattern = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+	metricNamePattern  = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+)
+
+// HistogramFeature is a latency-style anomaly feature computed from a Prometheus
+// histogram, e.g. the p99 of http_request_duration_seconds in the analyzed scope
+type HistogramFeature struct {
+	// Name prefixes the feature's names in the model input, e.g. "api_latency_p99"
+	Name string `json:"name"`
+
+	// Metric is the histogram's base name, without the _bucket suffix
+	Metric string `json:"metric"`
+
+	// Quantile is the quantile to compute (0 < q < 1)
+	Quantile float64 `json:"quantile"`
+}
+
+// ParseHistogramFeatures parses histogram features written as "name=metric:quantile",
+// e.g. "api_latency_p99=http_request_duration_seconds:0.99". The order is kept, as it
+// is the order of the features in the model input.
+func ParseHistogramFeatures(specs []string) ([]HistogramFeature, error) {
+	features := make([]HistogramFeature, 0, len(specs))
+	names := make(map[string]bool, len(specs))
+	for _, spec := range specs {
+		name, rest, ok := strings.Cut(spec, "=")
+		// Metric names may contain colons; the quantile follows the last one
+		sep := strings.LastIndex(rest, ":")
+		if !ok || sep < 0 {
+			return nil, fmt.Errorf("histogram feature %q: expected name=metric:quantile", spec)
+		}
+		name = strings.TrimSpace(name)
+		metric := strings.TrimSuffix(strings.TrimSpace(rest[:sep]), "_bucket")
+		quantileStr := rest[sep+1:]
+
+		switch {
+		case !featureNamePattern.MatchString(name):
+			return nil, fmt.Errorf("histogram feature %q: invalid name %q", spec, name)
+		case names[name]:
+			return nil, fmt.Errorf("histogram feature %q: duplicate name %q", spec, name)
+		case !metricNamePattern.MatchString(metric):
+			return nil, fmt.Errorf("histogram feature %q: invalid metric name %q", spec, metric)
+		}
+		quantile, err := strconv.ParseFloat(strings.TrimSpace(quantileStr), 64)
+		if err != nil || quantile <= 0 || quantile >= 1 {
+			return nil, fmt.Errorf("histogram feature %q: quantile must be between 0 and 1 (exclusive)", spec)
+		}
+
+		names[name] = true
+		features = append(features, HistogramFeature{Name: name, Metric: metric, Quantile: quantile})
+	}
+	return features, nil
+}
+
+// Query returns the PromQL computing the feature for a label selector such as
+// `namespace="payments"`. Buckets are summed by le only, so the quantile is taken over
+// the whole scope rather than per series.
+func (f HistogramFeature) Query(selector string) string {
+	return promql.Default().MustRender("histogram.quantile",
+		promql.Params{Quantile: f.Quantile, Metric: f.Metric, Selector: selector})
+}
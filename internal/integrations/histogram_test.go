@@ -0,0 +1,45 @@
+package integrations
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseHistogramFeatures(t *testing.T) {
+	features, err := ParseHistogramFeatures([]string{
+		"api_latency_p99=http_request_duration_seconds:0.99",
+		"apiserver_p90 = apiserver_request_duration_seconds_bucket : 0.9",
+		"recorded_p50=job:request_latency_seconds:0.5",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []HistogramFeature{
+		{Name: "api_latency_p99", Metric: "http_request_duration_seconds", Quantile: 0.99},
+		{Name: "apiserver_p90", Metric: "apiserver_request_duration_seconds", Quantile: 0.9},
+		{Name: "recorded_p50", Metric: "job:request_latency_seconds", Quantile: 0.5},
+	}, features)
+
+	assert.Equal(t,
+		`histogram_quantile(0.99, sum by (le) (rate(http_request_duration_seconds_bucket{namespace="payments"}[5m])))`,
+		features[0].Query(`namespace="payments"`))
+	assert.Equal(t,
+		`histogram_quantile(0.9, sum by (le) (rate(apiserver_request_duration_seconds_bucket{}[5m])))`,
+		features[1].Query(""))
+
+	invalid := map[string]string{
+		"http_request_duration_seconds:0.99":     "expected name=metric:quantile",
+		"p99=http_request_duration_seconds":      "expected name=metric:quantile",
+		"P99=http_request_duration_seconds:0.99": "invalid name",
+		"p99=http-request-duration:0.99":         "invalid metric name",
+		"p99=http_request_duration_seconds:1":    "quantile must be between 0 and 1",
+		"p99=http_request_duration_seconds:high": "quantile must be between 0 and 1",
+	}
+	for spec, want := range invalid {
+		_, err := ParseHistogramFeatures([]string{spec})
+		assert.ErrorContains(t, err, want, spec)
+	}
+
+	_, err = ParseHistogramFeatures([]string{"p99=a_seconds:0.99", "p99=b_seconds:0.99"})
+	assert.ErrorContains(t, err, "duplicate name")
+}
@@ -0,0 +1,92 @@
+package integrations
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/promql"
+)
+
+// JobRunRecord is a Job run recorded by kube-state-metrics
+type JobRunRecord struct {
+	Job     string
+	CronJob string // empty for Jobs not created by a CronJob
+
+	StartTime      time.Time
+	CompletionTime time.Time // zero unless the Job succeeded
+	Failed         bool
+}
+
+// GetJobRunHistory queries the Jobs of a namespace that started within window (e.g.
+// "14d"), including Jobs deleted since, sorted by start time
+func (c *PrometheusClient) GetJobRunHistory(ctx context.Context, namespace, window string) ([]JobRunRecord, error) {
+	if !c.IsAvailable() {
+		return nil, fmt.Errorf("prometheus client not available")
+	}
+
+	params := promql.Params{Namespace: namespace, Window: window}
+	query := func(name string) ([]VectorSample, error) {
+		return c.QueryVector(ctx, c.Queries().MustRender(name, params))
+	}
+
+	starts, err := query("batch.job_start_time")
+	if err != nil {
+		return nil, err
+	}
+	runs := make(map[string]*JobRunRecord, len(starts))
+	for _, sample := range starts {
+		name := sample.Labels["job_name"]
+		runs[name] = &JobRunRecord{Job: name, StartTime: unixTime(sample.Value)}
+	}
+
+	completions, err := query("batch.job_completion_time")
+	if err != nil {
+		return nil, err
+	}
+	for _, sample := range completions {
+		if run := runs[sample.Labels["job_name"]]; run != nil {
+			run.CompletionTime = unixTime(sample.Value)
+		}
+	}
+
+	failed, err := query("batch.job_failed")
+	if err != nil {
+		return nil, err
+	}
+	for _, sample := range failed {
+		if run := runs[sample.Labels["job_name"]]; run != nil {
+			run.Failed = true
+		}
+	}
+
+	owners, err := query("batch.job_owner")
+	if err != nil {
+		return nil, err
+	}
+	for _, sample := range owners {
+		if run := runs[sample.Labels["job_name"]]; run != nil {
+			run.CronJob = sample.Labels["owner_name"]
+		}
+	}
+
+	records := make([]JobRunRecord, 0, len(runs))
+	for _, run := range runs {
+		records = append(records, *run)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		if !records[i].StartTime.Equal(records[j].StartTime) {
+			return records[i].StartTime.Before(records[j].StartTime)
+		}
+		return records[i].Job < records[j].Job
+	})
+	return records, nil
+}
+
+// unixTime converts a Unix timestamp sample to a UTC time
+func unixTime(seconds float64) time.Time {
+	whole, frac := math.Modf(seconds)
+	return time.Unix(int64(whole), int64(frac*1e9)).UTC()
+}
@@ -0,0 +1,146 @@
+package integrations
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// LLMClient is a client for an OpenAI-compatible chat completions API, such as
+// OpenAI itself or a vLLM model served by KServe (which exposes /openai/v1)
+type LLMClient struct {
+	baseURL    string
+	apiKey     string
+	model      string
+	httpClient *http.Client
+	log        *logrus.Logger
+}
+
+// NewLLMClient creates a new LLM client. baseURL is the API root that
+// /chat/completions is appended to, e.g. https://api.openai.com/v1 or
+// http://granite-predictor.models.svc.cluster.local:8080/openai/v1.
+// apiKey is optional; it is sent as a bearer token when set.
+func NewLLMClient(baseURL, apiKey, model string, timeout time.Duration, log *logrus.Logger) *LLMClient {
+	transport := &http.Transport{
+		MaxIdleConns:        10,
+		MaxIdleConnsPerHost: 5,
+		IdleConnTimeout:     90 * time.Second,
+		DisableKeepAlives:   false,
+	}
+
+	return &LLMClient{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		apiKey:  apiKey,
+		model:   model,
+		httpClient: &http.Client{
+			Transport: transport,
+			Timeout:   timeout,
+		},
+		log: log,
+	}
+}
+
+// HTTPClient returns the client used to reach the LLM endpoint, e.g. to audit its TLS configuration
+func (c *LLMClient) HTTPClient() *http.Client {
+	return c.httpClient
+}
+
+// ChatMessage is a single message in a chat completion request
+type ChatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// chatCompletionRequest is the body of POST /chat/completions
+type chatCompletionRequest struct {
+	Model       string        `json:"model"`
+	Messages    []ChatMessage `json:"messages"`
+	MaxTokens   int           `json:"max_tokens,omitempty"`
+	Temperature float64       `json:"temperature"`
+}
+
+// chatCompletionResponse is the subset of the chat completion response the client uses
+type chatCompletionResponse struct {
+	Choices []struct {
+		Message      ChatMessage `json:"message"`
+		FinishReason string      `json:"finish_reason"`
+	} `json:"choices"`
+}
+
+// Complete sends the messages to the chat completions API and returns the text of
+// the first choice. Temperature is fixed at 0 so summaries are as repeatable as the
+// model allows.
+func (c *LLMClient) Complete(ctx context.Context, messages []ChatMessage, maxTokens int) (string, error) {
+	body, err := json.Marshal(chatCompletionRequest{
+		Model:     c.model,
+		Messages:  messages,
+		MaxTokens: maxTokens,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	url := c.baseURL + "/chat/completions"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	start := time.Now()
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("request failed: %w", err)
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			c.log.WithError(closeErr).Warn("Failed to close response body")
+		}
+	}()
+
+	c.log.WithFields(logrus.Fields{
+		"url":      url,
+		"model":    c.model,
+		"status":   resp.StatusCode,
+		"duration": time.Since(start).Milliseconds(),
+	}).Debug("LLM request completed")
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		bodyBytes, readErr := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		if readErr != nil {
+			return "", fmt.Errorf("unexpected status %d, failed to read body: %w", resp.StatusCode, readErr)
+		}
+		return "", fmt.Errorf("unexpected status %d: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	var completion chatCompletionResponse
+	if err := json.NewDecoder(resp.Body).Decode(&completion); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+	if len(completion.Choices) == 0 {
+		return "", fmt.Errorf("response contained no choices")
+	}
+
+	return strings.TrimSpace(completion.Choices[0].Message.Content), nil
+}
+
+// Model returns the model name sent with each request
+func (c *LLMClient) Model() string {
+	return c.model
+}
+
+// Close closes the HTTP client connections
+func (c *LLMClient) Close() {
+	c.httpClient.CloseIdleConnections()
+}
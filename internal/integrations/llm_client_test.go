@@ -0,0 +1,77 @@
+package integrations
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLLMClient_Complete(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/openai/v1/chat/completions", r.URL.Path)
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+
+		var req chatCompletionRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "granite", req.Model)
+		assert.Equal(t, 128, req.MaxTokens)
+		require.Len(t, req.Messages, 2)
+		assert.Equal(t, "system", req.Messages[0].Role)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"  Pods were OOMKilled.\n"},"finish_reason":"stop"}]}`))
+	}))
+	defer server.Close()
+
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	client := NewLLMClient(server.URL+"/openai/v1/", "secret", "granite", 5*time.Second, log)
+	defer client.Close()
+
+	text, err := client.Complete(context.Background(), []ChatMessage{
+		{Role: "system", Content: "Summarize."},
+		{Role: "user", Content: "timeline"},
+	}, 128)
+	require.NoError(t, err)
+	assert.Equal(t, "Pods were OOMKilled.", text)
+}
+
+func TestLLMClient_CompleteErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantErr string
+	}{
+		{name: "server error", status: http.StatusInternalServerError, body: "model not loaded", wantErr: "unexpected status 500: model not loaded"},
+		{name: "no choices", status: http.StatusOK, body: `{"choices":[]}`, wantErr: "no choices"},
+		{name: "invalid json", status: http.StatusOK, body: `{`, wantErr: "failed to decode"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Empty(t, r.Header.Get("Authorization"))
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			log := logrus.New()
+			log.SetLevel(logrus.ErrorLevel)
+			client := NewLLMClient(server.URL, "", "m", 5*time.Second, log)
+
+			_, err := client.Complete(context.Background(), []ChatMessage{{Role: "user", Content: "x"}}, 0)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
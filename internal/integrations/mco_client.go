@@ -250,6 +250,27 @@ func (mc *MCOClient) ListMachineConfigPools(ctx context.Context) ([]string, erro
 	return poolNames, nil
 }
 
+// ListPoolStatuses returns the status of all MachineConfigPools. Pools whose status
+// cannot be parsed (e.g. just created) are skipped.
+func (mc *MCOClient) ListPoolStatuses(ctx context.Context) ([]MachineConfigPoolStatus, error) {
+	pools, err := mc.dynamicClient.Resource(mcpGVR).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list MachineConfigPools: %w", err)
+	}
+
+	statuses := make([]MachineConfigPoolStatus, 0, len(pools.Items))
+	for i := range pools.Items {
+		status, err := mc.parsePoolStatus(&pools.Items[i])
+		if err != nil {
+			mc.log.WithError(err).WithField("pool", pools.Items[i].GetName()).Debug("Skipping MachineConfigPool without status")
+			continue
+		}
+		statuses = append(statuses, *status)
+	}
+
+	return statuses, nil
+}
+
 // WaitForAllPoolsStable waits for all MachineConfigPools to become stable
 func (mc *MCOClient) WaitForAllPoolsStable(ctx context.Context, timeout time.Duration) error {
 	mc.log.WithField("timeout", timeout).Info("Waiting for all MachineConfigPools to stabilize")
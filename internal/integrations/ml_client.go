@@ -39,6 +39,11 @@ func NewMLClient(baseURL string, timeout time.Duration, log *logrus.Logger) *MLC
 	}
 }
 
+// HTTPClient returns the client used to reach the ML service, e.g. to audit its TLS configuration
+func (c *MLClient) HTTPClient() *http.Client {
+	return c.httpClient
+}
+
 // AnomalyDetectionRequest represents a request to detect anomalies
 type AnomalyDetectionRequest struct {
 	Metrics   []MetricData `json:"metrics"`
@@ -4,6 +4,7 @@ package integrations
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -11,34 +12,45 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/sirupsen/logrus"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/promql"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/diagnostics"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/quantity"
 )
 
 // ScopeType defines the scope of metric queries
-type ScopeType string
+type ScopeType = models.ScopeLevel
 
 const (
 	// ScopePod queries metrics for a specific pod
-	ScopePod ScopeType = "pod"
+	ScopePod = models.ScopePod
 	// ScopeDeployment queries metrics for pods belonging to a deployment
-	ScopeDeployment ScopeType = "deployment"
+	ScopeDeployment = models.ScopeDeployment
 	// ScopeNamespace queries metrics for all pods in a namespace
-	ScopeNamespace ScopeType = "namespace"
+	ScopeNamespace = models.ScopeNamespace
 	// ScopeCluster queries metrics across the entire cluster
-	ScopeCluster ScopeType = "cluster"
+	ScopeCluster = models.ScopeCluster
 )
 
 // QueryOptions specifies filtering options for Prometheus queries
 type QueryOptions struct {
-	Namespace  string        // Filter by namespace
-	Deployment string        // Filter by deployment name (matches pod prefix)
-	Pod        string        // Filter by exact pod name
-	Scope      ScopeType     // Query scope level
-	TimeRange  time.Duration // Time range for historical queries
+	// Scope filters by namespace, deployment (matching the pod prefix) or exact pod
+	// name at the scope's level
+	models.Scope
+	TimeRange time.Duration // Time range for historical queries
+
+	// ExtraLabels are constant label matchers added to the scoped selector,
+	// e.g. {"cluster": "prod-east"} when querying a federated Prometheus
+	ExtraLabels map[string]string
 }
 
 // TrendPoint represents a single data point for trend analysis
@@ -72,10 +84,43 @@ type PrometheusClient struct {
 	httpClient *http.Client
 	log        *logrus.Logger
 
+	// externalLabels are injected into every query (federated Prometheus support)
+	externalLabels map[string]string
+
+	// useRecordingRules queries pre-computed feature series when the scope allows it
+	useRecordingRules bool
+
+	// maxQuerySeries refuses queries estimated to touch more series (0 disables the guard)
+	maxQuerySeries int
+
+	// rangeMaxPoints downsamples range query results to at most this many points (0 keeps all)
+	rangeMaxPoints int
+
+	// queries renders PromQL for the cluster's Kubernetes version (nil for an unknown version)
+	queries *promql.Library
+
+	// capabilities is the last exporter probe (nil until the first one)
+	capabilities   *Capabilities
+	capabilitiesMu sync.RWMutex
+
+	// schedulerPressure explains scheduler queue growth in the infrastructure health
+	// summary (nil leaves it out)
+	schedulerPressure *SchedulerPressureAnalyzer
+
+	// baselineFallback provides API server verb baselines Prometheus has not learned
+	// yet, e.g. imported from another cluster (nil uses none)
+	baselineFallback BaselineFallback
+
 	// Cache for rolling mean values with TTL
 	cache    map[string]cachedMetric
 	cacheMu  sync.RWMutex
 	cacheTTL time.Duration
+
+	// now is the clock of cache expiry and query windows, time.Now when nil. With a
+	// fixed clock (pinnedTime) instant queries are evaluated at its time instead of
+	// Prometheus'.
+	now        func() time.Time
+	pinnedTime bool
 }
 
 // cachedMetric holds a cached metric value with expiration
@@ -127,6 +172,11 @@ func NewPrometheusClient(baseURL string, timeout time.Duration, log *logrus.Logg
 	}
 }
 
+// HTTPClient returns the client used to reach Prometheus, e.g. to audit its TLS configuration
+func (c *PrometheusClient) HTTPClient() *http.Client {
+	return c.httpClient
+}
+
 // Close releases resources held by the client
 func (c *PrometheusClient) Close() {
 	if c != nil && c.httpClient != nil {
@@ -134,6 +184,88 @@ func (c *PrometheusClient) Close() {
 	}
 }
 
+// SetRootCAs verifies the Prometheus server certificate against pool instead of
+// skipping verification, e.g. with the OpenShift service CA bundle
+func (c *PrometheusClient) SetRootCAs(pool *x509.CertPool) {
+	if c == nil {
+		return
+	}
+	if transport, ok := c.httpClient.Transport.(*http.Transport); ok {
+		transport.TLSClientConfig = &tls.Config{
+			RootCAs:    pool,
+			MinVersion: tls.VersionTLS12,
+		}
+	}
+}
+
+// SetExternalLabels configures constant label matchers (e.g. cluster="prod-east") that are
+// injected into every PromQL query. Use this when queries go through a federated or
+// central Prometheus containing series from many clusters.
+func (c *PrometheusClient) SetExternalLabels(labels map[string]string) {
+	if c == nil {
+		return
+	}
+	copied := make(map[string]string, len(labels))
+	for k, v := range labels {
+		copied[k] = v
+	}
+	c.externalLabels = copied
+	c.ClearCache()
+}
+
+// ExternalLabels returns the constant label matchers applied to every query
+func (c *PrometheusClient) ExternalLabels() map[string]string {
+	return c.externalLabels
+}
+
+// SetKubernetesVersion selects the query variants that apply to the cluster's
+// Kubernetes version, e.g. apiserver_storage_objects instead of etcd_object_counts.
+// Until it is called every variant is tried in turn.
+func (c *PrometheusClient) SetKubernetesVersion(version promql.Version) {
+	if c == nil {
+		return
+	}
+	c.queries = promql.NewLibrary(version)
+	c.ClearCache()
+}
+
+// SetClock replaces the clock of cache expiry and query windows with the fixed clock
+// of deterministic mode. Instant queries outside a snapshot are then evaluated at the
+// clock's time, so they return the same values on every run.
+func (c *PrometheusClient) SetClock(now func() time.Time) {
+	if c == nil {
+		return
+	}
+	c.now = now
+	c.pinnedTime = true
+	c.ClearCache()
+}
+
+// currentTime returns the time of the client's clock
+func (c *PrometheusClient) currentTime() time.Time {
+	if c.now == nil {
+		return time.Now()
+	}
+	return c.now()
+}
+
+// SetSchedulerPressure adds the scheduler pressure analysis to the infrastructure
+// health summary
+func (c *PrometheusClient) SetSchedulerPressure(analyzer *SchedulerPressureAnalyzer) {
+	if c == nil {
+		return
+	}
+	c.schedulerPressure = analyzer
+}
+
+// Queries returns the query library for the cluster's Kubernetes version
+func (c *PrometheusClient) Queries() *promql.Library {
+	if c == nil || c.queries == nil {
+		return promql.Default()
+	}
+	return c.queries
+}
+
 // IsAvailable returns true if the Prometheus client is configured
 func (c *PrometheusClient) IsAvailable() bool {
 	return c != nil && c.baseURL != ""
@@ -148,31 +280,22 @@ func (c *PrometheusClient) GetCPURollingMean(ctx context.Context) (float64, erro
 	}
 
 	cacheKey := "cpu_rolling_mean"
-	if value, ok := c.getCached(cacheKey); ok {
+	if value, ok := c.getMetric(ctx, cacheKey); ok {
 		return value, nil
 	}
 
-	// Primary query: Cluster CPU utilization as ratio of allocatable capacity
-	// sum(rate(...)) = Total CPU cores used across all containers
-	// sum(kube_node_status_allocatable{resource="cpu"}) = Total allocatable CPU cores
-	query := `sum(rate(container_cpu_usage_seconds_total{container!="",pod!=""}[5m])) / sum(kube_node_status_allocatable{resource="cpu"})`
-
-	value, err := c.queryInstant(ctx, query)
+	// Cluster CPU utilization as ratio of allocatable capacity, falling back to node-level
+	// CPU idle time (works without kube-state-metrics)
+	value, query, err := c.queryTemplate(ctx, "cluster.cpu_utilization", promql.Params{})
 	if err != nil {
-		// Fallback: Use node-level CPU idle time (works without kube-state-metrics)
-		c.log.WithError(err).Debug("Primary CPU query failed, trying node-level fallback")
-		query = `1 - avg(rate(node_cpu_seconds_total{mode="idle"}[5m]))`
-		value, err = c.queryInstant(ctx, query)
-		if err != nil {
-			c.log.WithError(err).Debug("Failed to query CPU rolling mean from Prometheus")
-			return 0, err
-		}
+		c.log.WithError(err).Debug("Failed to query CPU rolling mean from Prometheus")
+		return 0, err
 	}
 
 	// Value should already be 0-1 range (utilization ratio)
 	normalizedValue := clampToUnitRange(value)
 
-	c.setCached(cacheKey, normalizedValue)
+	c.setMetric(ctx, cacheKey, normalizedValue)
 	c.log.WithFields(logrus.Fields{
 		"raw_value":        value,
 		"normalized_value": normalizedValue,
@@ -191,32 +314,22 @@ func (c *PrometheusClient) GetMemoryRollingMean(ctx context.Context) (float64, e
 	}
 
 	cacheKey := "memory_rolling_mean"
-	if value, ok := c.getCached(cacheKey); ok {
+	if value, ok := c.getMetric(ctx, cacheKey); ok {
 		return value, nil
 	}
 
-	// Primary query: Cluster memory utilization as ratio of allocatable capacity
-	// container_memory_working_set_bytes = Actual memory in use (excludes cache)
-	// sum(kube_node_status_allocatable{resource="memory"}) = Total allocatable memory
-	query := `sum(container_memory_working_set_bytes{container!="",pod!=""}) / sum(kube_node_status_allocatable{resource="memory"})`
-
-	value, err := c.queryInstant(ctx, query)
+	// Cluster memory working set as ratio of allocatable capacity, falling back to
+	// node-level available memory (works without kube-state-metrics)
+	value, query, err := c.queryTemplate(ctx, "cluster.memory_utilization", promql.Params{})
 	if err != nil {
-		// Fallback: Use node-level available memory (works without kube-state-metrics)
-		// Note: This is more accurate than the previous fallback because it uses sum() across nodes
-		c.log.WithError(err).Debug("Primary memory query failed, trying node-level fallback")
-		query = `1 - (sum(node_memory_MemAvailable_bytes) / sum(node_memory_MemTotal_bytes))`
-		value, err = c.queryInstant(ctx, query)
-		if err != nil {
-			c.log.WithError(err).Debug("Failed to query memory rolling mean from Prometheus")
-			return 0, err
-		}
+		c.log.WithError(err).Debug("Failed to query memory rolling mean from Prometheus")
+		return 0, err
 	}
 
 	// Value should already be 0-1 range (utilization ratio)
 	normalizedValue := clampToUnitRange(value)
 
-	c.setCached(cacheKey, normalizedValue)
+	c.setMetric(ctx, cacheKey, normalizedValue)
 	c.log.WithFields(logrus.Fields{
 		"raw_value":        value,
 		"normalized_value": normalizedValue,
@@ -233,26 +346,18 @@ func (c *PrometheusClient) GetNamespaceCPURollingMean(ctx context.Context, names
 	}
 
 	cacheKey := fmt.Sprintf("cpu_rolling_mean_%s", namespace)
-	if value, ok := c.getCached(cacheKey); ok {
+	if value, ok := c.getMetric(ctx, cacheKey); ok {
 		return value, nil
 	}
 
-	// Primary: Namespace CPU usage as ratio of cluster allocatable CPU
-	query := fmt.Sprintf(`sum(rate(container_cpu_usage_seconds_total{container!="",pod!="",namespace=%q}[5m])) / sum(kube_node_status_allocatable{resource="cpu"})`, namespace)
-
-	value, err := c.queryInstant(ctx, query)
+	// Namespace CPU usage as ratio of cluster allocatable CPU, or of the namespace quota
+	value, _, err := c.queryTemplate(ctx, "namespace.cpu_utilization", promql.Params{Namespace: namespace})
 	if err != nil {
-		// Fallback: Use namespace quota if available
-		c.log.WithError(err).Debug("Primary namespace CPU query failed, trying quota fallback")
-		query = fmt.Sprintf(`sum(rate(container_cpu_usage_seconds_total{container!="",pod!="",namespace=%q}[5m])) / sum(kube_resourcequota{resource="limits.cpu",namespace=%q})`, namespace, namespace)
-		value, err = c.queryInstant(ctx, query)
-		if err != nil {
-			return 0, err
-		}
+		return 0, err
 	}
 
 	normalizedValue := clampToUnitRange(value)
-	c.setCached(cacheKey, normalizedValue)
+	c.setMetric(ctx, cacheKey, normalizedValue)
 
 	return normalizedValue, nil
 }
@@ -264,26 +369,18 @@ func (c *PrometheusClient) GetNamespaceMemoryRollingMean(ctx context.Context, na
 	}
 
 	cacheKey := fmt.Sprintf("memory_rolling_mean_%s", namespace)
-	if value, ok := c.getCached(cacheKey); ok {
+	if value, ok := c.getMetric(ctx, cacheKey); ok {
 		return value, nil
 	}
 
-	// Primary: Namespace memory usage as ratio of cluster allocatable memory
-	query := fmt.Sprintf(`sum(container_memory_working_set_bytes{container!="",pod!="",namespace=%q}) / sum(kube_node_status_allocatable{resource="memory"})`, namespace)
-
-	value, err := c.queryInstant(ctx, query)
+	// Namespace memory usage as ratio of cluster allocatable memory, or of the namespace quota
+	value, _, err := c.queryTemplate(ctx, "namespace.memory_utilization", promql.Params{Namespace: namespace})
 	if err != nil {
-		// Fallback: Use namespace quota if available
-		c.log.WithError(err).Debug("Primary namespace memory query failed, trying quota fallback")
-		query = fmt.Sprintf(`sum(container_memory_working_set_bytes{container!="",pod!="",namespace=%q}) / sum(kube_resourcequota{resource="limits.memory",namespace=%q})`, namespace, namespace)
-		value, err = c.queryInstant(ctx, query)
-		if err != nil {
-			return 0, err
-		}
+		return 0, err
 	}
 
 	normalizedValue := clampToUnitRange(value)
-	c.setCached(cacheKey, normalizedValue)
+	c.setMetric(ctx, cacheKey, normalizedValue)
 
 	return normalizedValue, nil
 }
@@ -296,32 +393,24 @@ func (c *PrometheusClient) GetScopedCPURollingMean(ctx context.Context, namespac
 	}
 
 	cacheKey := fmt.Sprintf("cpu_rolling_mean_scoped_%s_%s_%s", namespace, deployment, pod)
-	if value, ok := c.getCached(cacheKey); ok {
+	if value, ok := c.getMetric(ctx, cacheKey); ok {
 		return value, nil
 	}
 
-	// Build primary PromQL query: scoped CPU / cluster allocatable
-	query := c.buildScopedCPUQuery(namespace, deployment, pod)
-
-	value, err := c.queryInstant(ctx, query)
+	// Scoped CPU / cluster allocatable, falling back to the node CPU count without
+	// kube-state-metrics
+	value, query, err := c.queryTemplate(ctx, "scope.cpu_utilization", promql.Params{Selector: ScopedSelector(namespace, deployment, pod)})
 	if err != nil {
-		// Fallback: try without kube-state-metrics denominator
-		c.log.WithError(err).Debug("Primary scoped CPU query failed, trying fallback")
-		fallbackQuery := c.buildScopedCPUQueryFallback(namespace, deployment, pod)
-		value, err = c.queryInstant(ctx, fallbackQuery)
-		if err != nil {
-			c.log.WithError(err).WithFields(logrus.Fields{
-				"namespace":  namespace,
-				"deployment": deployment,
-				"pod":        pod,
-				"query":      query,
-			}).Debug("Failed to query scoped CPU rolling mean from Prometheus")
-			return 0, err
-		}
+		c.log.WithError(err).WithFields(logrus.Fields{
+			"namespace":  namespace,
+			"deployment": deployment,
+			"pod":        pod,
+		}).Debug("Failed to query scoped CPU rolling mean from Prometheus")
+		return 0, err
 	}
 
 	normalizedValue := clampToUnitRange(value)
-	c.setCached(cacheKey, normalizedValue)
+	c.setMetric(ctx, cacheKey, normalizedValue)
 
 	c.log.WithFields(logrus.Fields{
 		"raw_value":        value,
@@ -329,6 +418,7 @@ func (c *PrometheusClient) GetScopedCPURollingMean(ctx context.Context, namespac
 		"namespace":        namespace,
 		"deployment":       deployment,
 		"pod":              pod,
+		"query":            query,
 	}).Debug("Retrieved scoped CPU rolling mean from Prometheus")
 
 	return normalizedValue, nil
@@ -342,31 +432,24 @@ func (c *PrometheusClient) GetScopedMemoryRollingMean(ctx context.Context, names
 	}
 
 	cacheKey := fmt.Sprintf("memory_rolling_mean_scoped_%s_%s_%s", namespace, deployment, pod)
-	if value, ok := c.getCached(cacheKey); ok {
+	if value, ok := c.getMetric(ctx, cacheKey); ok {
 		return value, nil
 	}
 
-	// Build primary PromQL query: scoped memory / cluster allocatable
-	query := c.buildScopedMemoryQuery(namespace, deployment, pod)
-
-	value, err := c.queryInstant(ctx, query)
+	// Scoped memory / cluster allocatable, falling back to node memory without
+	// kube-state-metrics
+	value, query, err := c.queryTemplate(ctx, "scope.memory_utilization", promql.Params{Selector: ScopedSelector(namespace, deployment, pod)})
 	if err != nil {
-		// Try fallback query without kube-state-metrics
-		c.log.WithError(err).Debug("Scoped memory ratio query failed, trying alternative")
-		fallbackQuery := c.buildScopedMemoryQueryFallback(namespace, deployment, pod)
-		value, err = c.queryInstant(ctx, fallbackQuery)
-		if err != nil {
-			c.log.WithError(err).WithFields(logrus.Fields{
-				"namespace":  namespace,
-				"deployment": deployment,
-				"pod":        pod,
-			}).Debug("Failed to query scoped memory rolling mean from Prometheus")
-			return 0, err
-		}
+		c.log.WithError(err).WithFields(logrus.Fields{
+			"namespace":  namespace,
+			"deployment": deployment,
+			"pod":        pod,
+		}).Debug("Failed to query scoped memory rolling mean from Prometheus")
+		return 0, err
 	}
 
 	normalizedValue := clampToUnitRange(value)
-	c.setCached(cacheKey, normalizedValue)
+	c.setMetric(ctx, cacheKey, normalizedValue)
 
 	c.log.WithFields(logrus.Fields{
 		"raw_value":        value,
@@ -374,100 +457,70 @@ func (c *PrometheusClient) GetScopedMemoryRollingMean(ctx context.Context, names
 		"namespace":        namespace,
 		"deployment":       deployment,
 		"pod":              pod,
+		"query":            query,
 	}).Debug("Retrieved scoped memory rolling mean from Prometheus")
 
 	return normalizedValue, nil
 }
 
-// buildScopedCPUQuery constructs a PromQL query for CPU metrics normalized by cluster allocatable
-func (c *PrometheusClient) buildScopedCPUQuery(namespace, deployment, pod string) string {
-	var labelSelectors []string
-
-	// Always exclude empty containers and pods
-	labelSelectors = append(labelSelectors, `container!=""`, `pod!=""`)
-
-	// Add namespace filter
-	if namespace != "" {
-		labelSelectors = append(labelSelectors, fmt.Sprintf(`namespace=%q`, namespace))
+// GetScopedUsageHistory returns CPU and memory utilization as ratios of cluster allocatable
+// (0-1) between start and end, sampled every step, using the same queries as the scoped
+// rolling means. Empty scope fields widen the query; all empty is cluster-wide.
+func (c *PrometheusClient) GetScopedUsageHistory(
+	ctx context.Context,
+	namespace, deployment, pod string,
+	start, end time.Time,
+	step time.Duration,
+) (cpu, memory []MetricDataPoint, err error) {
+	if !c.IsAvailable() {
+		return nil, nil, fmt.Errorf("prometheus client not available")
 	}
 
-	// Add deployment filter (matches pods with deployment prefix)
-	if deployment != "" {
-		labelSelectors = append(labelSelectors, fmt.Sprintf(`pod=~"%s-.*"`, deployment))
+	params := promql.Params{Selector: ScopedSelector(namespace, deployment, pod)}
+	cpu, err = c.queryScopedHistory(ctx, c.Queries().MustCandidates("scope.cpu_utilization", params), start, end, step)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get CPU history: %w", err)
 	}
 
-	// Add pod filter (exact match)
-	if pod != "" {
-		labelSelectors = append(labelSelectors, fmt.Sprintf(`pod=%q`, pod))
+	memory, err = c.queryScopedHistory(ctx, c.Queries().MustCandidates("scope.memory_utilization", params), start, end, step)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get memory history: %w", err)
 	}
 
-	selector := "{" + joinSelectors(labelSelectors) + "}"
-	// Return CPU usage as ratio of cluster allocatable CPU
-	return fmt.Sprintf(`sum(rate(container_cpu_usage_seconds_total%s[5m])) / sum(kube_node_status_allocatable{resource="cpu"})`, selector)
+	return cpu, memory, nil
 }
 
-// buildScopedCPUQueryFallback constructs a fallback CPU query using node-level metrics
-func (c *PrometheusClient) buildScopedCPUQueryFallback(namespace, deployment, pod string) string {
-	var labelSelectors []string
-
-	// Always exclude empty containers and pods
-	labelSelectors = append(labelSelectors, `container!=""`, `pod!=""`)
-
-	// Add namespace filter
-	if namespace != "" {
-		labelSelectors = append(labelSelectors, fmt.Sprintf(`namespace=%q`, namespace))
-	}
-
-	// Add deployment filter (matches pods with deployment prefix)
-	if deployment != "" {
-		labelSelectors = append(labelSelectors, fmt.Sprintf(`pod=~"%s-.*"`, deployment))
-	}
-
-	// Add pod filter (exact match)
-	if pod != "" {
-		labelSelectors = append(labelSelectors, fmt.Sprintf(`pod=%q`, pod))
+// queryScopedHistory runs a range query, retrying with the next candidate query on
+// failure, and clamps the samples to the 0-1 range
+func (c *PrometheusClient) queryScopedHistory(ctx context.Context, queries []string, start, end time.Time, step time.Duration) ([]MetricDataPoint, error) {
+	stepStr, err := c.guardRangeQuery(ctx, queries[0], start, end, formatDurationForPromQL(step))
+	if err != nil {
+		return nil, err
 	}
 
-	selector := "{" + joinSelectors(labelSelectors) + "}"
-	// Fallback: estimate cluster capacity from node_cpu metrics
-	// Use sum of node CPUs as denominator
-	return fmt.Sprintf(`sum(rate(container_cpu_usage_seconds_total%s[5m])) / count(count by (cpu) (node_cpu_seconds_total{mode="idle"}))`, selector)
-}
-
-// buildScopedMemoryQuery constructs a PromQL query for memory metrics normalized by cluster allocatable
-func (c *PrometheusClient) buildScopedMemoryQuery(namespace, deployment, pod string) string {
-	var labelSelectors []string
-
-	// Always exclude empty containers and pods
-	labelSelectors = append(labelSelectors, `container!=""`, `pod!=""`)
-
-	// Add namespace filter
-	if namespace != "" {
-		labelSelectors = append(labelSelectors, fmt.Sprintf(`namespace=%q`, namespace))
+	var points []MetricDataPoint
+	for _, query := range queries {
+		points, err = c.executeTracedRangeQuery(ctx, query, start, end, stepStr)
+		if err == nil {
+			break
+		}
+		c.log.WithError(err).WithField("query", query).Debug("Scoped history query failed, trying next variant")
 	}
-
-	// Add deployment filter (matches pods with deployment prefix)
-	if deployment != "" {
-		labelSelectors = append(labelSelectors, fmt.Sprintf(`pod=~"%s-.*"`, deployment))
+	if err != nil {
+		return nil, err
 	}
 
-	// Add pod filter (exact match)
-	if pod != "" {
-		labelSelectors = append(labelSelectors, fmt.Sprintf(`pod=%q`, pod))
+	for i := range points {
+		points[i].Value = clampToUnitRange(points[i].Value)
 	}
-
-	selector := "{" + joinSelectors(labelSelectors) + "}"
-	// Return memory working set as ratio of cluster allocatable memory
-	return fmt.Sprintf(`sum(container_memory_working_set_bytes%s) / sum(kube_node_status_allocatable{resource="memory"})`, selector)
+	return points, nil
 }
 
-// buildScopedMemoryQueryFallback constructs a fallback PromQL query for memory metrics
-// Used when kube-state-metrics is not available
-func (c *PrometheusClient) buildScopedMemoryQueryFallback(namespace, deployment, pod string) string {
-	var labelSelectors []string
-
+// ScopedSelector returns the label matchers of a namespace, deployment or pod scope,
+// without braces. Empty fields widen the scope.
+func ScopedSelector(namespace, deployment, pod string) string {
 	// Always exclude empty containers and pods
-	labelSelectors = append(labelSelectors, `container!=""`, `pod!=""`)
+	labelSelectors := []string{`container!=""`, `pod!=""`}
 
 	// Add namespace filter
 	if namespace != "" {
@@ -476,7 +529,7 @@ func (c *PrometheusClient) buildScopedMemoryQueryFallback(namespace, deployment,
 
 	// Add deployment filter (matches pods with deployment prefix)
 	if deployment != "" {
-		labelSelectors = append(labelSelectors, fmt.Sprintf(`pod=~"%s-.*"`, deployment))
+		labelSelectors = append(labelSelectors, fmt.Sprintf(`pod=~%q`, regexp.QuoteMeta(deployment)+"-.*"))
 	}
 
 	// Add pod filter (exact match)
@@ -484,9 +537,7 @@ func (c *PrometheusClient) buildScopedMemoryQueryFallback(namespace, deployment,
 		labelSelectors = append(labelSelectors, fmt.Sprintf(`pod=%q`, pod))
 	}
 
-	selector := "{" + joinSelectors(labelSelectors) + "}"
-	// Fallback: Use node memory total as denominator
-	return fmt.Sprintf(`sum(container_memory_working_set_bytes%s) / sum(node_memory_MemTotal_bytes)`, selector)
+	return joinSelectors(labelSelectors)
 }
 
 // joinSelectors joins label selectors with commas
@@ -501,23 +552,76 @@ func joinSelectors(selectors []string) string {
 	return result
 }
 
-// queryInstant executes an instant query against Prometheus
+// queryTemplate renders a query template and runs its variants in order until one
+// succeeds, returning its value and query
+func (c *PrometheusClient) queryTemplate(ctx context.Context, name string, params promql.Params) (float64, string, error) {
+	var lastErr error
+	for _, query := range c.Queries().MustCandidates(name, params) {
+		value, err := c.queryInstant(ctx, query)
+		if err == nil {
+			return value, query, nil
+		}
+		c.log.WithError(err).WithFields(logrus.Fields{
+			"template": name,
+			"query":    query,
+		}).Debug("PromQL query failed, trying next variant")
+		lastErr = err
+	}
+	return 0, "", lastErr
+}
+
+// queryInstant executes an instant query against Prometheus after checking its estimated cost
 func (c *PrometheusClient) queryInstant(ctx context.Context, query string) (float64, error) {
+	if err := c.checkQueryCost(ctx, query); err != nil {
+		return 0, err
+	}
+
+	start := time.Now()
+	value, err := c.executeInstantQuery(ctx, query)
+	diagnostics.FromContext(ctx).RecordQuery("instant", InjectLabelMatchers(query, c.externalLabels), time.Since(start), err)
+	return value, err
+}
+
+// executeInstantQuery executes an instant query against Prometheus and returns the
+// value of the first series
+func (c *PrometheusClient) executeInstantQuery(ctx context.Context, query string) (float64, error) {
+	promResp, err := c.fetchInstantQuery(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(promResp.Data.Result) == 0 {
+		return 0, fmt.Errorf("no data returned for query: %s", query)
+	}
+
+	// Extract value from result
+	// Value is [timestamp, "string_value"]
+	return parseSampleValue(promResp.Data.Result[0].Value)
+}
+
+// fetchInstantQuery executes an instant query against Prometheus and returns the
+// decoded response
+func (c *PrometheusClient) fetchInstantQuery(ctx context.Context, query string) (*PrometheusQueryResponse, error) {
 	endpoint := fmt.Sprintf("%s/api/v1/query", c.baseURL)
 
 	// Build request URL with query parameter
 	reqURL, err := url.Parse(endpoint)
 	if err != nil {
-		return 0, fmt.Errorf("failed to parse URL: %w", err)
+		return nil, fmt.Errorf("failed to parse URL: %w", err)
 	}
 
 	params := url.Values{}
-	params.Set("query", query)
+	params.Set("query", InjectLabelMatchers(query, c.externalLabels))
+	if snapshot := SnapshotFromContext(ctx); snapshot != nil {
+		params.Set("time", snapshot.timeParam())
+	} else if c.pinnedTime {
+		params.Set("time", strconv.FormatInt(c.currentTime().Unix(), 10))
+	}
 	reqURL.RawQuery = params.Encode()
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL.String(), http.NoBody)
 	if err != nil {
-		return 0, fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Accept", "application/json")
@@ -529,51 +633,89 @@ func (c *PrometheusClient) queryInstant(ctx context.Context, query string) (floa
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return 0, fmt.Errorf("failed to execute query: %w", err)
+		return nil, fmt.Errorf("failed to execute query: %w", err)
 	}
 	defer closeBody(resp)
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return 0, fmt.Errorf("failed to read response: %w", err)
+		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return 0, fmt.Errorf("prometheus returned status %d: %s", resp.StatusCode, string(body))
+		return nil, fmt.Errorf("prometheus returned status %d: %s", resp.StatusCode, string(body))
 	}
 
 	var promResp PrometheusQueryResponse
 	if err := json.Unmarshal(body, &promResp); err != nil {
-		return 0, fmt.Errorf("failed to parse response: %w", err)
+		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
 	if promResp.Status != "success" {
-		return 0, fmt.Errorf("prometheus query failed: %s - %s", promResp.ErrorType, promResp.Error)
+		return nil, fmt.Errorf("prometheus query failed: %s - %s", promResp.ErrorType, promResp.Error)
 	}
 
-	if len(promResp.Data.Result) == 0 {
-		return 0, fmt.Errorf("no data returned for query: %s", query)
-	}
+	return &promResp, nil
+}
 
-	// Extract value from result
-	// Value is [timestamp, "string_value"]
-	if len(promResp.Data.Result[0].Value) < 2 {
+// parseSampleValue parses a [timestamp, "value"] instant query sample
+func parseSampleValue(sample []interface{}) (float64, error) {
+	if len(sample) < 2 {
 		return 0, fmt.Errorf("unexpected result format")
 	}
 
-	valueStr, ok := promResp.Data.Result[0].Value[1].(string)
+	valueStr, ok := sample[1].(string)
 	if !ok {
 		return 0, fmt.Errorf("unexpected value type in result")
 	}
 
-	var value float64
-	if _, err := fmt.Sscanf(valueStr, "%f", &value); err != nil {
-		return 0, fmt.Errorf("failed to parse value '%s': %w", valueStr, err)
+	value, err := quantity.ParseFloat(valueStr)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse value: %w", err)
 	}
 
 	return value, nil
 }
 
+// VectorSample is one series of an instant vector query result
+type VectorSample struct {
+	Labels map[string]string
+	Value  float64
+}
+
+// QueryVector executes an instant query and returns every series in the result, after
+// checking its estimated cost. NaN and infinite samples are dropped; an empty result is
+// not an error.
+func (c *PrometheusClient) QueryVector(ctx context.Context, query string) ([]VectorSample, error) {
+	if !c.IsAvailable() {
+		return nil, fmt.Errorf("prometheus client not available")
+	}
+	if err := c.checkQueryCost(ctx, query); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	promResp, err := c.fetchInstantQuery(ctx, query)
+	diagnostics.FromContext(ctx).RecordQuery("instant", InjectLabelMatchers(query, c.externalLabels), time.Since(start), err)
+	if err != nil {
+		return nil, err
+	}
+
+	samples := make([]VectorSample, 0, len(promResp.Data.Result))
+	for _, result := range promResp.Data.Result {
+		value, err := parseSampleValue(result.Value)
+		if err != nil {
+			c.log.WithError(err).WithField("metric", result.Metric).Debug("Skipping unparseable sample")
+			continue
+		}
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			continue
+		}
+		samples = append(samples, VectorSample{Labels: result.Metric, Value: value})
+	}
+	return samples, nil
+}
+
 // getServiceAccountToken reads the service account token for in-cluster authentication
 func (c *PrometheusClient) getServiceAccountToken() string {
 	token, err := os.ReadFile("/var/run/secrets/kubernetes.io/serviceaccount/token")
@@ -590,7 +732,7 @@ func (c *PrometheusClient) getCached(key string) (float64, bool) {
 	defer c.cacheMu.RUnlock()
 
 	cached, exists := c.cache[key]
-	if !exists || time.Now().After(cached.expiresAt) {
+	if !exists || c.currentTime().After(cached.expiresAt) {
 		return 0, false
 	}
 	return cached.value, true
@@ -603,8 +745,28 @@ func (c *PrometheusClient) setCached(key string, value float64) {
 
 	c.cache[key] = cachedMetric{
 		value:     value,
-		expiresAt: time.Now().Add(c.cacheTTL),
+		expiresAt: c.currentTime().Add(c.cacheTTL),
+	}
+}
+
+// getMetric returns a metric value memoized on the context's snapshot, or a cached
+// value when ctx carries no snapshot
+func (c *PrometheusClient) getMetric(ctx context.Context, key string) (float64, bool) {
+	if snapshot := SnapshotFromContext(ctx); snapshot != nil {
+		return snapshot.get(key)
+	}
+	return c.getCached(key)
+}
+
+// setMetric memoizes a metric value on the context's snapshot, or caches it when ctx
+// carries no snapshot. Snapshot values are never cached, since they were evaluated at
+// the snapshot's timestamp rather than now.
+func (c *PrometheusClient) setMetric(ctx context.Context, key string, value float64) {
+	if snapshot := SnapshotFromContext(ctx); snapshot != nil {
+		snapshot.set(key, value)
+		return
 	}
+	c.setCached(key, value)
 }
 
 // ClearCache clears all cached metrics
@@ -663,7 +825,7 @@ func (c *PrometheusClient) GetNamespaceCPUTrend(ctx context.Context, namespace,
 	}
 
 	// Query for CPU usage rate over time with 1 hour resolution
-	query := fmt.Sprintf(`sum(rate(container_cpu_usage_seconds_total{namespace=%q,container!=""}[5m]))`, namespace)
+	query := c.Queries().MustRender("namespace.cpu_usage", promql.Params{Namespace: namespace})
 
 	return c.queryRange(ctx, query, window, "1h")
 }
@@ -675,118 +837,212 @@ func (c *PrometheusClient) GetNamespaceMemoryTrend(ctx context.Context, namespac
 	}
 
 	// Query for memory usage over time with 1 hour resolution
-	query := fmt.Sprintf(`sum(container_memory_usage_bytes{namespace=%q,container!=""})`, namespace)
+	query := c.Queries().MustRender("namespace.memory_usage", promql.Params{Namespace: namespace})
 
 	return c.queryRange(ctx, query, window, "1h")
 }
 
+// UsagePercentiles holds usage percentiles over a window
+type UsagePercentiles struct {
+	P50 float64
+	P95 float64
+	P99 float64
+}
+
+// ContainerUsagePercentiles holds the usage percentiles of one pod container. CPU is
+// in cores and memory in bytes.
+type ContainerUsagePercentiles struct {
+	Pod       string
+	Container string
+	CPU       UsagePercentiles
+	Memory    UsagePercentiles
+}
+
+// GetContainerUsagePercentiles queries p50/p95/p99 CPU and memory usage for every
+// container in a namespace over window (e.g. "7d"), sampled every 5 minutes
+func (c *PrometheusClient) GetContainerUsagePercentiles(ctx context.Context, namespace, window string) ([]ContainerUsagePercentiles, error) {
+	if !c.IsAvailable() {
+		return nil, fmt.Errorf("prometheus client not available")
+	}
+
+	params := promql.Params{Namespace: namespace}
+	cpuQuery := c.Queries().MustRender("namespace.container_cpu_usage", params)
+	memoryQuery := c.Queries().MustRender("namespace.container_memory_usage", params)
+
+	type containerKey struct{ pod, container string }
+	usage := make(map[containerKey]*ContainerUsagePercentiles)
+	collect := func(query string, quantile float64, set func(*ContainerUsagePercentiles, float64)) error {
+		samples, err := c.QueryVector(ctx, c.Queries().MustRender("range.quantile_over_time",
+			promql.Params{Quantile: quantile, Query: query, Window: window}))
+		if err != nil {
+			return err
+		}
+		for _, sample := range samples {
+			key := containerKey{sample.Labels["pod"], sample.Labels["container"]}
+			if usage[key] == nil {
+				usage[key] = &ContainerUsagePercentiles{Pod: key.pod, Container: key.container}
+			}
+			set(usage[key], sample.Value)
+		}
+		return nil
+	}
+
+	queries := []struct {
+		query    string
+		quantile float64
+		set      func(*ContainerUsagePercentiles, float64)
+	}{
+		{cpuQuery, 0.5, func(u *ContainerUsagePercentiles, v float64) { u.CPU.P50 = v }},
+		{cpuQuery, 0.95, func(u *ContainerUsagePercentiles, v float64) { u.CPU.P95 = v }},
+		{cpuQuery, 0.99, func(u *ContainerUsagePercentiles, v float64) { u.CPU.P99 = v }},
+		{memoryQuery, 0.5, func(u *ContainerUsagePercentiles, v float64) { u.Memory.P50 = v }},
+		{memoryQuery, 0.95, func(u *ContainerUsagePercentiles, v float64) { u.Memory.P95 = v }},
+		{memoryQuery, 0.99, func(u *ContainerUsagePercentiles, v float64) { u.Memory.P99 = v }},
+	}
+	for _, q := range queries {
+		if err := collect(q.query, q.quantile, q.set); err != nil {
+			return nil, err
+		}
+	}
+
+	results := make([]ContainerUsagePercentiles, 0, len(usage))
+	for _, u := range usage {
+		results = append(results, *u)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Pod != results[j].Pod {
+			return results[i].Pod < results[j].Pod
+		}
+		return results[i].Container < results[j].Container
+	})
+	return results, nil
+}
+
 // GetNamespaceCPUUsage queries current CPU usage for a namespace (in cores)
-func (c *PrometheusClient) GetNamespaceCPUUsage(ctx context.Context, namespace string) (float64, error) {
+func (c *PrometheusClient) GetNamespaceCPUUsage(ctx context.Context, namespace string) (quantity.Cores, error) {
 	if !c.IsAvailable() {
 		return 0, fmt.Errorf("prometheus client not available")
 	}
 
 	cacheKey := fmt.Sprintf("cpu_usage_%s", namespace)
-	if value, ok := c.getCached(cacheKey); ok {
-		return value, nil
+	if value, ok := c.getMetric(ctx, cacheKey); ok {
+		return quantity.CoresFromFloat(value)
 	}
 
-	query := fmt.Sprintf(`sum(rate(container_cpu_usage_seconds_total{namespace=%q,container!=""}[5m]))`, namespace)
+	query := c.Queries().MustRender("namespace.cpu_usage", promql.Params{Namespace: namespace})
 
 	value, err := c.queryInstant(ctx, query)
 	if err != nil {
 		return 0, err
 	}
 
-	c.setCached(cacheKey, value)
-	return value, nil
+	result, err := quantity.CoresFromFloat(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid CPU usage: %w", err)
+	}
+
+	c.setMetric(ctx, cacheKey, value)
+	return result, nil
 }
 
 // GetNamespaceMemoryUsage queries current memory usage for a namespace (in bytes)
-func (c *PrometheusClient) GetNamespaceMemoryUsage(ctx context.Context, namespace string) (int64, error) {
+func (c *PrometheusClient) GetNamespaceMemoryUsage(ctx context.Context, namespace string) (quantity.Bytes, error) {
 	if !c.IsAvailable() {
 		return 0, fmt.Errorf("prometheus client not available")
 	}
 
 	cacheKey := fmt.Sprintf("memory_usage_%s", namespace)
-	if value, ok := c.getCached(cacheKey); ok {
-		return int64(value), nil
+	if value, ok := c.getMetric(ctx, cacheKey); ok {
+		return quantity.BytesFromFloat(value)
 	}
 
-	query := fmt.Sprintf(`sum(container_memory_usage_bytes{namespace=%q,container!=""})`, namespace)
+	query := c.Queries().MustRender("namespace.memory_usage", promql.Params{Namespace: namespace})
 
 	value, err := c.queryInstant(ctx, query)
 	if err != nil {
 		return 0, err
 	}
 
-	c.setCached(cacheKey, value)
-	return int64(value), nil
+	result, err := quantity.BytesFromFloat(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid memory usage: %w", err)
+	}
+
+	c.setMetric(ctx, cacheKey, value)
+	return result, nil
 }
 
 // GetClusterCPUUsage queries current total cluster CPU usage (in cores)
-func (c *PrometheusClient) GetClusterCPUUsage(ctx context.Context) (float64, error) {
+func (c *PrometheusClient) GetClusterCPUUsage(ctx context.Context) (quantity.Cores, error) {
 	if !c.IsAvailable() {
 		return 0, fmt.Errorf("prometheus client not available")
 	}
 
 	cacheKey := "cluster_cpu_usage"
-	if value, ok := c.getCached(cacheKey); ok {
-		return value, nil
+	if value, ok := c.getMetric(ctx, cacheKey); ok {
+		return quantity.CoresFromFloat(value)
 	}
 
-	query := `sum(rate(container_cpu_usage_seconds_total{container!=""}[5m]))`
+	query := c.Queries().MustRender("cluster.cpu_usage", promql.Params{})
 
 	value, err := c.queryInstant(ctx, query)
 	if err != nil {
 		return 0, err
 	}
 
-	c.setCached(cacheKey, value)
-	return value, nil
+	result, err := quantity.CoresFromFloat(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid CPU usage: %w", err)
+	}
+
+	c.setMetric(ctx, cacheKey, value)
+	return result, nil
 }
 
 // GetClusterMemoryUsage queries current total cluster memory usage (in bytes)
-func (c *PrometheusClient) GetClusterMemoryUsage(ctx context.Context) (int64, error) {
+func (c *PrometheusClient) GetClusterMemoryUsage(ctx context.Context) (quantity.Bytes, error) {
 	if !c.IsAvailable() {
 		return 0, fmt.Errorf("prometheus client not available")
 	}
 
 	cacheKey := "cluster_memory_usage"
-	if value, ok := c.getCached(cacheKey); ok {
-		return int64(value), nil
+	if value, ok := c.getMetric(ctx, cacheKey); ok {
+		return quantity.BytesFromFloat(value)
 	}
 
-	query := `sum(container_memory_usage_bytes{container!=""})`
+	query := c.Queries().MustRender("cluster.memory_usage", promql.Params{})
 
 	value, err := c.queryInstant(ctx, query)
 	if err != nil {
 		return 0, err
 	}
 
-	c.setCached(cacheKey, value)
-	return int64(value), nil
+	result, err := quantity.BytesFromFloat(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid memory usage: %w", err)
+	}
+
+	c.setMetric(ctx, cacheKey, value)
+	return result, nil
 }
 
 // GetEtcdObjectCount queries the total number of objects in etcd
-func (c *PrometheusClient) GetEtcdObjectCount(ctx context.Context) (int64, error) {
+func (c *PrometheusClient) GetEtcdObjectCount(ctx context.Context) (quantity.Count, error) {
 	if !c.IsAvailable() {
 		return 0, fmt.Errorf("prometheus client not available")
 	}
 
-	query := `sum(etcd_object_counts)`
-
-	value, err := c.queryInstant(ctx, query)
+	// etcd_object_counts was replaced by apiserver_storage_objects in Kubernetes 1.21
+	value, _, err := c.queryTemplate(ctx, "etcd.object_count", promql.Params{})
 	if err != nil {
-		// Try alternative query
-		query = `sum(apiserver_storage_objects)`
-		value, err = c.queryInstant(ctx, query)
-		if err != nil {
-			return 0, err
-		}
+		return 0, err
 	}
 
-	return int64(value), nil
+	result, err := quantity.CountFromFloat(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid etcd object count: %w", err)
+	}
+	return result, nil
 }
 
 // GetAPIServerQPS queries the current API server requests per second
@@ -795,7 +1051,7 @@ func (c *PrometheusClient) GetAPIServerQPS(ctx context.Context) (float64, error)
 		return 0, fmt.Errorf("prometheus client not available")
 	}
 
-	query := `sum(rate(apiserver_request_total[5m]))`
+	query := c.Queries().MustRender("apiserver.request_rate", promql.Params{})
 
 	return c.queryInstant(ctx, query)
 }
@@ -806,7 +1062,7 @@ func (c *PrometheusClient) GetSchedulerQueueLength(ctx context.Context) (int, er
 		return 0, fmt.Errorf("prometheus client not available")
 	}
 
-	query := `sum(scheduler_pending_pods)`
+	query := c.Queries().MustRender("scheduler.pending_pods", promql.Params{})
 
 	value, err := c.queryInstant(ctx, query)
 	if err != nil {
@@ -823,7 +1079,7 @@ func (c *PrometheusClient) GetControlPlaneHealth(ctx context.Context) (string, e
 	}
 
 	// Check if etcd is healthy
-	query := `sum(etcd_server_has_leader)`
+	query := c.Queries().MustRender("etcd.has_leader", promql.Params{})
 	value, err := c.queryInstant(ctx, query)
 	if err != nil {
 		return "unknown", nil
@@ -839,22 +1095,35 @@ func (c *PrometheusClient) GetControlPlaneHealth(ctx context.Context) (string, e
 func (c *PrometheusClient) queryRange(ctx context.Context, query, window, step string) ([]MetricDataPoint, error) {
 	start, end := c.calculateTimeRange(window)
 
-	reqURL, err := c.buildRangeQueryURL(query, start, end, step)
+	step, err := c.guardRangeQuery(ctx, query, start, end, step)
 	if err != nil {
 		return nil, err
 	}
 
-	body, err := c.executeRangeQuery(ctx, reqURL)
+	return c.executeTracedRangeQuery(ctx, query, start, end, step)
+}
+
+// executeTracedRangeQuery builds, executes and parses a range query, recording its
+// timing in the request's diagnostics trace
+func (c *PrometheusClient) executeTracedRangeQuery(ctx context.Context, query string, start, end time.Time, step string) ([]MetricDataPoint, error) {
+	reqURL, err := c.buildRangeQueryURL(query, start, end, step)
 	if err != nil {
 		return nil, err
 	}
 
-	return c.parseRangeResponse(body, query)
+	queryStart := time.Now()
+	points, err := c.executeRangeQuery(ctx, reqURL, query, RangeStreamOptions{
+		MaxPoints: c.rangeMaxPoints,
+		Start:     start,
+		End:       end,
+	})
+	diagnostics.FromContext(ctx).RecordQuery("range", InjectLabelMatchers(query, c.externalLabels), time.Since(queryStart), err)
+	return points, err
 }
 
 // calculateTimeRange returns start and end times based on window
 func (c *PrometheusClient) calculateTimeRange(window string) (start, end time.Time) {
-	end = time.Now()
+	end = c.currentTime()
 	switch window {
 	case "30d":
 		start = end.AddDate(0, 0, -30)
@@ -875,7 +1144,7 @@ func (c *PrometheusClient) buildRangeQueryURL(query string, start, end time.Time
 	}
 
 	params := url.Values{}
-	params.Set("query", query)
+	params.Set("query", InjectLabelMatchers(query, c.externalLabels))
 	params.Set("start", fmt.Sprintf("%d", start.Unix()))
 	params.Set("end", fmt.Sprintf("%d", end.Unix()))
 	params.Set("step", step)
@@ -884,11 +1153,31 @@ func (c *PrometheusClient) buildRangeQueryURL(query string, start, end time.Time
 	return reqURL.String(), nil
 }
 
-// executeRangeQuery executes the HTTP request for a range query
-func (c *PrometheusClient) executeRangeQuery(ctx context.Context, reqURL string) ([]byte, error) {
+// executeRangeQuery executes the HTTP request for a range query and streams the first
+// series of the response
+func (c *PrometheusClient) executeRangeQuery(ctx context.Context, reqURL, query string, opts RangeStreamOptions) ([]MetricDataPoint, error) {
+	// Only the first series is used; the rest of the response is not decoded
+	var points []MetricDataPoint
+	found := false
+	err := c.streamRangeQuery(ctx, reqURL, opts, func(series RangeSeries) error {
+		points, found = series.Points, true
+		return ErrStopStream
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("no data returned for query: %s", query)
+	}
+	return points, nil
+}
+
+// streamRangeQuery executes the HTTP request for a range query and calls fn with each
+// series of the response
+func (c *PrometheusClient) streamRangeQuery(ctx context.Context, reqURL string, opts RangeStreamOptions, fn func(RangeSeries) error) error {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, http.NoBody)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Accept", "application/json")
@@ -898,87 +1187,33 @@ func (c *PrometheusClient) executeRangeQuery(ctx context.Context, reqURL string)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute query: %w", err)
+		return fmt.Errorf("failed to execute query: %w", err)
 	}
 	defer closeBody(resp)
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
-
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("prometheus returned status %d: %s", resp.StatusCode, string(body))
-	}
-
-	return body, nil
-}
-
-// parseRangeResponse parses the Prometheus range query response
-func (c *PrometheusClient) parseRangeResponse(body []byte, query string) ([]MetricDataPoint, error) {
-	var promResp PrometheusRangeQueryResponse
-	if err := json.Unmarshal(body, &promResp); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
-	}
-
-	if promResp.Status != "success" {
-		return nil, fmt.Errorf("prometheus query failed: %s - %s", promResp.ErrorType, promResp.Error)
-	}
-
-	if len(promResp.Data.Result) == 0 {
-		return nil, fmt.Errorf("no data returned for query: %s", query)
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
+		return fmt.Errorf("prometheus returned status %d: %s", resp.StatusCode, string(body))
 	}
 
-	return c.extractDataPoints(promResp.Data.Result[0].Values), nil
-}
-
-// extractDataPoints extracts MetricDataPoints from raw Prometheus values
-func (c *PrometheusClient) extractDataPoints(values [][]interface{}) []MetricDataPoint {
-	dataPoints := make([]MetricDataPoint, 0, len(values))
-	for _, v := range values {
-		if dp, ok := c.parseDataPoint(v); ok {
-			dataPoints = append(dataPoints, dp)
-		}
-	}
-	return dataPoints
-}
-
-// parseDataPoint parses a single data point from Prometheus response
-func (c *PrometheusClient) parseDataPoint(values []interface{}) (MetricDataPoint, bool) {
-	if len(values) < 2 {
-		return MetricDataPoint{}, false
-	}
-
-	ts, ok := values[0].(float64)
-	if !ok {
-		return MetricDataPoint{}, false
-	}
-
-	valueStr, ok := values[1].(string)
-	if !ok {
-		return MetricDataPoint{}, false
-	}
-
-	var value float64
-	if _, err := fmt.Sscanf(valueStr, "%f", &value); err != nil {
-		return MetricDataPoint{}, false
-	}
-
-	return MetricDataPoint{
-		Timestamp: time.Unix(int64(ts), 0),
-		Value:     value,
-	}, true
+	return StreamRangeResponse(resp.Body, opts, fn)
 }
 
 // =============================================================================
 // Scoped Query Methods (Issue #28 Enhancements)
 // =============================================================================
 
-// buildQueryWithScope constructs a PromQL query with scope-based label selectors
-func (c *PrometheusClient) buildQueryWithScope(baseQuery string, opts QueryOptions) string {
+// buildQueryWithScope renders a query template with scope-based label selectors
+func (c *PrometheusClient) buildQueryWithScope(name string, opts QueryOptions, params promql.Params) string {
+	params.Selector = scopeFilters(opts)
+	return c.Queries().MustRender(name, params)
+}
+
+// scopeFilters returns the label matchers of a query scope, without braces
+func scopeFilters(opts QueryOptions) string {
 	filters := []string{`container!=""`}
 
-	switch opts.Scope {
+	switch opts.Level {
 	case ScopePod:
 		if opts.Pod != "" {
 			filters = append(filters, fmt.Sprintf(`pod=%q`, opts.Pod))
@@ -988,7 +1223,7 @@ func (c *PrometheusClient) buildQueryWithScope(baseQuery string, opts QueryOptio
 		}
 	case ScopeDeployment:
 		if opts.Deployment != "" {
-			filters = append(filters, fmt.Sprintf(`pod=~"%s-.*"`, opts.Deployment))
+			filters = append(filters, fmt.Sprintf(`pod=~%q`, regexp.QuoteMeta(opts.Deployment)+"-.*"))
 		}
 		if opts.Namespace != "" {
 			filters = append(filters, fmt.Sprintf(`namespace=%q`, opts.Namespace))
@@ -1003,8 +1238,29 @@ func (c *PrometheusClient) buildQueryWithScope(baseQuery string, opts QueryOptio
 		// Default to cluster scope
 	}
 
-	filterStr := strings.Join(filters, ",")
-	return fmt.Sprintf(baseQuery, filterStr)
+	filters = append(filters, extraLabelFilters(opts.ExtraLabels)...)
+
+	return strings.Join(filters, ",")
+}
+
+// extraLabelFilters converts constant label matchers into sorted selector filters
+func extraLabelFilters(labels map[string]string) []string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	filters := make([]string, 0, len(keys))
+	for _, k := range keys {
+		filters = append(filters, fmt.Sprintf(`%s=%q`, k, labels[k]))
+	}
+	return filters
+}
+
+// labelKey returns a stable representation of the extra labels for cache keys
+func (o QueryOptions) labelKey() string {
+	return strings.Join(extraLabelFilters(o.ExtraLabels), ",")
 }
 
 // GetCPUUsage returns the current CPU usage with scoped query options
@@ -1013,17 +1269,17 @@ func (c *PrometheusClient) GetCPUUsage(ctx context.Context, opts QueryOptions) (
 		return 0, fmt.Errorf("prometheus client not available")
 	}
 
-	cacheKey := fmt.Sprintf("cpu_usage_scoped_%s_%s_%s_%s", opts.Scope, opts.Namespace, opts.Deployment, opts.Pod)
-	if value, ok := c.getCached(cacheKey); ok {
+	cacheKey := fmt.Sprintf("cpu_usage_scoped_%s_%s_%s_%s_%s", opts.Level, opts.Namespace, opts.Deployment, opts.Pod, opts.labelKey())
+	if value, ok := c.getMetric(ctx, cacheKey); ok {
 		return value, nil
 	}
 
-	query := c.buildQueryWithScope(`sum(rate(container_cpu_usage_seconds_total{%s}[5m]))`, opts)
+	query := c.buildQueryWithScope("scope.cpu_usage", opts, promql.Params{})
 
 	value, err := c.queryInstant(ctx, query)
 	if err != nil {
 		c.log.WithError(err).WithFields(logrus.Fields{
-			"scope":      opts.Scope,
+			"scope":      opts.Level,
 			"namespace":  opts.Namespace,
 			"deployment": opts.Deployment,
 			"pod":        opts.Pod,
@@ -1032,7 +1288,7 @@ func (c *PrometheusClient) GetCPUUsage(ctx context.Context, opts QueryOptions) (
 		return 0, err
 	}
 
-	c.setCached(cacheKey, value)
+	c.setMetric(ctx, cacheKey, value)
 	return value, nil
 }
 
@@ -1047,13 +1303,13 @@ func (c *PrometheusClient) GetCPURollingMeanScoped(ctx context.Context, opts Que
 		window = 24 * time.Hour
 	}
 
-	cacheKey := fmt.Sprintf("cpu_rolling_mean_scoped_%s_%s_%s_%s_%v", opts.Scope, opts.Namespace, opts.Deployment, opts.Pod, window)
-	if value, ok := c.getCached(cacheKey); ok {
+	cacheKey := fmt.Sprintf("cpu_rolling_mean_scoped_%s_%s_%s_%s_%v_%s", opts.Level, opts.Namespace, opts.Deployment, opts.Pod, window, opts.labelKey())
+	if value, ok := c.getMetric(ctx, cacheKey); ok {
 		return value, nil
 	}
 
 	windowStr := formatDurationForPromQL(window)
-	query := c.buildQueryWithScope(fmt.Sprintf(`avg(rate(container_cpu_usage_seconds_total{%%s}[%s]))`, windowStr), opts)
+	query := c.buildQueryWithScope("scope.cpu_usage_avg", opts, promql.Params{Window: windowStr})
 
 	value, err := c.queryInstant(ctx, query)
 	if err != nil {
@@ -1062,27 +1318,27 @@ func (c *PrometheusClient) GetCPURollingMeanScoped(ctx context.Context, opts Que
 	}
 
 	normalizedValue := clampToUnitRange(value)
-	c.setCached(cacheKey, normalizedValue)
+	c.setMetric(ctx, cacheKey, normalizedValue)
 	return normalizedValue, nil
 }
 
 // GetMemoryUsage returns the current memory usage with scoped query options (in bytes)
-func (c *PrometheusClient) GetMemoryUsage(ctx context.Context, opts QueryOptions) (int64, error) {
+func (c *PrometheusClient) GetMemoryUsage(ctx context.Context, opts QueryOptions) (quantity.Bytes, error) {
 	if !c.IsAvailable() {
 		return 0, fmt.Errorf("prometheus client not available")
 	}
 
-	cacheKey := fmt.Sprintf("memory_usage_scoped_%s_%s_%s_%s", opts.Scope, opts.Namespace, opts.Deployment, opts.Pod)
-	if value, ok := c.getCached(cacheKey); ok {
-		return int64(value), nil
+	cacheKey := fmt.Sprintf("memory_usage_scoped_%s_%s_%s_%s_%s", opts.Level, opts.Namespace, opts.Deployment, opts.Pod, opts.labelKey())
+	if value, ok := c.getMetric(ctx, cacheKey); ok {
+		return quantity.BytesFromFloat(value)
 	}
 
-	query := c.buildQueryWithScope(`sum(container_memory_usage_bytes{%s})`, opts)
+	query := c.buildQueryWithScope("scope.memory_usage", opts, promql.Params{})
 
 	value, err := c.queryInstant(ctx, query)
 	if err != nil {
 		c.log.WithError(err).WithFields(logrus.Fields{
-			"scope":      opts.Scope,
+			"scope":      opts.Level,
 			"namespace":  opts.Namespace,
 			"deployment": opts.Deployment,
 			"pod":        opts.Pod,
@@ -1091,8 +1347,13 @@ func (c *PrometheusClient) GetMemoryUsage(ctx context.Context, opts QueryOptions
 		return 0, err
 	}
 
-	c.setCached(cacheKey, value)
-	return int64(value), nil
+	result, err := quantity.BytesFromFloat(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid memory usage: %w", err)
+	}
+
+	c.setMetric(ctx, cacheKey, value)
+	return result, nil
 }
 
 // GetMemoryRollingMeanScoped returns the rolling mean memory usage with scoped query options (normalized 0-1)
@@ -1106,64 +1367,24 @@ func (c *PrometheusClient) GetMemoryRollingMeanScoped(ctx context.Context, opts
 		window = 24 * time.Hour
 	}
 
-	cacheKey := fmt.Sprintf("memory_rolling_mean_scoped_%s_%s_%s_%s_%v", opts.Scope, opts.Namespace, opts.Deployment, opts.Pod, window)
-	if value, ok := c.getCached(cacheKey); ok {
+	cacheKey := fmt.Sprintf("memory_rolling_mean_scoped_%s_%s_%s_%s_%v_%s", opts.Level, opts.Namespace, opts.Deployment, opts.Pod, window, opts.labelKey())
+	if value, ok := c.getMetric(ctx, cacheKey); ok {
 		return value, nil
 	}
 
-	windowStr := formatDurationForPromQL(window)
-	// Need to apply scope twice for the ratio query
-	query := c.buildMemoryRatioQuery(opts, windowStr)
-
-	value, err := c.queryInstant(ctx, query)
+	// Usage as ratio of the container memory limits, falling back to a fixed 2Gi for
+	// containers without limits
+	params := promql.Params{Selector: scopeFilters(opts), Window: formatDurationForPromQL(window)}
+	value, _, err := c.queryTemplate(ctx, "scope.memory_limit_ratio", params)
 	if err != nil {
-		// Try fallback query without limits
-		c.log.WithError(err).Debug("Memory ratio query failed, trying fallback")
-		fallbackQuery := c.buildQueryWithScope(
-			fmt.Sprintf(`avg(avg_over_time(container_memory_usage_bytes{%%s}[%s]) / 2147483648)`, windowStr),
-			opts,
-		)
-		value, err = c.queryInstant(ctx, fallbackQuery)
-		if err != nil {
-			return 0, err
-		}
+		return 0, err
 	}
 
 	normalizedValue := clampToUnitRange(value)
-	c.setCached(cacheKey, normalizedValue)
+	c.setMetric(ctx, cacheKey, normalizedValue)
 	return normalizedValue, nil
 }
 
-// buildMemoryRatioQuery constructs a memory ratio query with proper scoping
-func (c *PrometheusClient) buildMemoryRatioQuery(opts QueryOptions, windowStr string) string {
-	filters := []string{`container!=""`}
-
-	switch opts.Scope {
-	case ScopePod:
-		if opts.Pod != "" {
-			filters = append(filters, fmt.Sprintf(`pod=%q`, opts.Pod))
-		}
-		if opts.Namespace != "" {
-			filters = append(filters, fmt.Sprintf(`namespace=%q`, opts.Namespace))
-		}
-	case ScopeDeployment:
-		if opts.Deployment != "" {
-			filters = append(filters, fmt.Sprintf(`pod=~"%s-.*"`, opts.Deployment))
-		}
-		if opts.Namespace != "" {
-			filters = append(filters, fmt.Sprintf(`namespace=%q`, opts.Namespace))
-		}
-	case ScopeNamespace:
-		if opts.Namespace != "" {
-			filters = append(filters, fmt.Sprintf(`namespace=%q`, opts.Namespace))
-		}
-	}
-
-	filterStr := strings.Join(filters, ",")
-	return fmt.Sprintf(`avg(avg_over_time(container_memory_usage_bytes{%s}[%s]) / container_spec_memory_limit_bytes{%s} > 0)`,
-		filterStr, windowStr, filterStr)
-}
-
 // =============================================================================
 // Trending Analysis Methods (Issue #28 Enhancements)
 // =============================================================================
@@ -1175,10 +1396,7 @@ func (c *PrometheusClient) GetCPUTrend(ctx context.Context, opts QueryOptions, w
 	}
 
 	windowStr := formatDurationForPromQL(window)
-	query := c.buildQueryWithScope(
-		fmt.Sprintf(`avg_over_time(sum(rate(container_cpu_usage_seconds_total{%%s}[5m]))[%s:1h])`, windowStr),
-		opts,
-	)
+	query := c.buildQueryWithScope("scope.cpu_trend", opts, promql.Params{Window: windowStr})
 
 	dataPoints, err := c.queryRangeWithDuration(ctx, query, window, time.Hour)
 	if err != nil {
@@ -1195,10 +1413,7 @@ func (c *PrometheusClient) GetMemoryTrend(ctx context.Context, opts QueryOptions
 	}
 
 	windowStr := formatDurationForPromQL(window)
-	query := c.buildQueryWithScope(
-		fmt.Sprintf(`avg_over_time(sum(container_memory_usage_bytes{%%s})[%s:1h])`, windowStr),
-		opts,
-	)
+	query := c.buildQueryWithScope("scope.memory_trend", opts, promql.Params{Window: windowStr})
 
 	dataPoints, err := c.queryRangeWithDuration(ctx, query, window, time.Hour)
 	if err != nil {
@@ -1276,7 +1491,7 @@ func (c *PrometheusClient) CalculateTrend(data *TrendData, threshold float64) *T
 		if dailyAbsoluteChange > 0 {
 			days := delta / dailyAbsoluteChange
 			daysUntil = int(math.Ceil(days))
-			projectedDate = time.Now().AddDate(0, 0, daysUntil)
+			projectedDate = c.currentTime().AddDate(0, 0, daysUntil)
 		}
 	}
 
@@ -1376,28 +1591,15 @@ func (c *PrometheusClient) calculateTrendConfidence(points []TrendPoint, rSquare
 
 // queryRangeWithDuration executes a range query using time.Duration instead of string
 func (c *PrometheusClient) queryRangeWithDuration(ctx context.Context, query string, window, step time.Duration) ([]MetricDataPoint, error) {
-	end := time.Now()
+	end := c.currentTime()
 	start := end.Add(-window)
 
-	endpoint := fmt.Sprintf("%s/api/v1/query_range", c.baseURL)
-	reqURL, err := url.Parse(endpoint)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse URL: %w", err)
-	}
-
-	params := url.Values{}
-	params.Set("query", query)
-	params.Set("start", fmt.Sprintf("%d", start.Unix()))
-	params.Set("end", fmt.Sprintf("%d", end.Unix()))
-	params.Set("step", formatDurationForPromQL(step))
-	reqURL.RawQuery = params.Encode()
-
-	body, err := c.executeRangeQuery(ctx, reqURL.String())
+	stepStr, err := c.guardRangeQuery(ctx, query, start, end, formatDurationForPromQL(step))
 	if err != nil {
 		return nil, err
 	}
 
-	return c.parseRangeResponse(body, query)
+	return c.executeTracedRangeQuery(ctx, query, start, end, stepStr)
 }
 
 // formatDurationForPromQL formats a duration for use in PromQL queries
@@ -1426,24 +1628,20 @@ func (c *PrometheusClient) GetETCDObjectCount(ctx context.Context) (int, error)
 		return 0, fmt.Errorf("prometheus client not available")
 	}
 
-	// Try primary query first
-	query := `sum(etcd_object_counts)`
-	value, err := c.queryInstant(ctx, query)
+	// Object count metric of the cluster's version, then the database size as a rough estimate
+	value, _, err := c.queryTemplate(ctx, "etcd.object_count", promql.Params{})
 	if err != nil {
-		// Try alternative query for newer metrics
-		query = `sum(apiserver_storage_objects)`
-		value, err = c.queryInstant(ctx, query)
+		value, _, err = c.queryTemplate(ctx, "etcd.db_size_mb", promql.Params{})
 		if err != nil {
-			// Try another alternative
-			query = `sum(etcd_mvcc_db_total_size_in_bytes) / 1024 / 1024` // MB as rough object estimate
-			value, err = c.queryInstant(ctx, query)
-			if err != nil {
-				return 0, fmt.Errorf("failed to query etcd object count: %w", err)
-			}
+			return 0, fmt.Errorf("failed to query etcd object count: %w", err)
 		}
 	}
 
-	return int(value), nil
+	count, err := quantity.CountFromFloat(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid etcd object count: %w", err)
+	}
+	return int(count), nil
 }
 
 // GetAPIServerQPSDetailed returns detailed API server QPS with breakdown by verb
@@ -1455,16 +1653,15 @@ func (c *PrometheusClient) GetAPIServerQPSDetailed(ctx context.Context) (map[str
 	result := make(map[string]float64)
 
 	// Total QPS
-	totalQuery := `sum(rate(apiserver_request_total[5m]))`
+	totalQuery := c.Queries().MustRender("apiserver.request_rate", promql.Params{})
 	totalValue, err := c.queryInstant(ctx, totalQuery)
 	if err == nil {
 		result["total"] = totalValue
 	}
 
 	// QPS by verb (common operations)
-	verbs := []string{"GET", "LIST", "WATCH", "CREATE", "UPDATE", "DELETE", "PATCH"}
-	for _, verb := range verbs {
-		verbQuery := fmt.Sprintf(`sum(rate(apiserver_request_total{verb=%q}[5m]))`, verb)
+	for _, verb := range APIServerVerbs {
+		verbQuery := c.Queries().MustRender("apiserver.request_rate_by_verb", promql.Params{Verb: verb})
 		verbValue, err := c.queryInstant(ctx, verbQuery)
 		if err == nil {
 			result[strings.ToLower(verb)] = verbValue
@@ -1483,28 +1680,27 @@ func (c *PrometheusClient) GetSchedulerMetrics(ctx context.Context) (map[string]
 	result := make(map[string]interface{})
 
 	// Pending pods (queue length)
-	queueQuery := `sum(scheduler_pending_pods)`
+	queueQuery := c.Queries().MustRender("scheduler.pending_pods", promql.Params{})
 	queueValue, err := c.queryInstant(ctx, queueQuery)
 	if err == nil {
 		result["queue_length"] = int(queueValue)
 	}
 
 	// Scheduling attempts
-	attemptsQuery := `sum(rate(scheduler_schedule_attempts_total[5m]))`
+	attemptsQuery := c.Queries().MustRender("scheduler.attempt_rate", promql.Params{})
 	attemptsValue, err := c.queryInstant(ctx, attemptsQuery)
 	if err == nil {
 		result["scheduling_rate_per_second"] = attemptsValue
 	}
 
-	// Scheduling latency (p99)
-	latencyQuery := `histogram_quantile(0.99, sum(rate(scheduler_scheduling_attempt_duration_seconds_bucket[5m])) by (le))`
-	latencyValue, err := c.queryInstant(ctx, latencyQuery)
+	// Scheduling latency (p99); the histogram was renamed in Kubernetes 1.23
+	latencyValue, _, err := c.queryTemplate(ctx, "scheduler.latency_p99", promql.Params{})
 	if err == nil {
 		result["p99_latency_seconds"] = latencyValue
 	}
 
 	// Unschedulable pods
-	unschedulableQuery := `sum(scheduler_pending_pods{queue="unschedulable"})`
+	unschedulableQuery := c.Queries().MustRender("scheduler.unschedulable_pods", promql.Params{})
 	unschedulableValue, err := c.queryInstant(ctx, unschedulableQuery)
 	if err == nil {
 		result["unschedulable_pods"] = int(unschedulableValue)
@@ -1522,21 +1718,21 @@ func (c *PrometheusClient) GetControllerManagerMetrics(ctx context.Context) (map
 	result := make(map[string]interface{})
 
 	// Work queue depth
-	queueDepthQuery := `sum(workqueue_depth)`
+	queueDepthQuery := c.Queries().MustRender("controller_manager.workqueue_depth", promql.Params{})
 	queueDepthValue, err := c.queryInstant(ctx, queueDepthQuery)
 	if err == nil {
 		result["total_queue_depth"] = int(queueDepthValue)
 	}
 
 	// Work queue adds rate
-	queueAddsQuery := `sum(rate(workqueue_adds_total[5m]))`
+	queueAddsQuery := c.Queries().MustRender("controller_manager.workqueue_adds_rate", promql.Params{})
 	queueAddsValue, err := c.queryInstant(ctx, queueAddsQuery)
 	if err == nil {
 		result["queue_adds_per_second"] = queueAddsValue
 	}
 
 	// Work queue retries rate
-	retriesQuery := `sum(rate(workqueue_retries_total[5m]))`
+	retriesQuery := c.Queries().MustRender("controller_manager.workqueue_retries_rate", promql.Params{})
 	retriesValue, err := c.queryInstant(ctx, retriesQuery)
 	if err == nil {
 		result["retries_per_second"] = retriesValue
@@ -1578,6 +1774,11 @@ func (c *PrometheusClient) GetInfrastructureHealthSummary(ctx context.Context) (
 	if err == nil {
 		result["scheduler_queue_length"] = schedulerQueue
 	}
+	if c.schedulerPressure != nil {
+		if pressure, err := c.schedulerPressure.Analyze(ctx); err == nil {
+			result["scheduler_pressure"] = pressure
+		}
+	}
 
 	// Cluster CPU/Memory
 	clusterCPU, err := c.GetClusterCPUUsage(ctx)
@@ -1651,14 +1852,16 @@ func (c *PrometheusClient) GetAnomalyMetricFeatures(ctx context.Context, baseQue
 	}
 
 	// Query rolling statistics (5m window)
-	mean5m := c.QueryWithDefault(ctx, fmt.Sprintf("avg_over_time((%s)[5m:])", baseQuery), value)
-	std5m := c.QueryWithDefault(ctx, fmt.Sprintf("stddev_over_time((%s)[5m:])", baseQuery), 0)
-	min5m := c.QueryWithDefault(ctx, fmt.Sprintf("min_over_time((%s)[5m:])", baseQuery), value)
-	max5m := c.QueryWithDefault(ctx, fmt.Sprintf("max_over_time((%s)[5m:])", baseQuery), value)
+	queries := c.Queries()
+	params := promql.Params{Query: baseQuery}
+	mean5m := c.QueryWithDefault(ctx, queries.MustRender("rolling.mean_5m", params), value)
+	std5m := c.QueryWithDefault(ctx, queries.MustRender("rolling.stddev_5m", params), 0)
+	min5m := c.QueryWithDefault(ctx, queries.MustRender("rolling.min_5m", params), value)
+	max5m := c.QueryWithDefault(ctx, queries.MustRender("rolling.max_5m", params), value)
 
 	// Query lag values
-	lag1 := c.QueryWithDefault(ctx, fmt.Sprintf("(%s) offset 1m", baseQuery), value)
-	lag5 := c.QueryWithDefault(ctx, fmt.Sprintf("(%s) offset 5m", baseQuery), value)
+	lag1 := c.QueryWithDefault(ctx, queries.MustRender("rolling.lag_1m", params), value)
+	lag5 := c.QueryWithDefault(ctx, queries.MustRender("rolling.lag_5m", params), value)
 
 	// Calculate derived features
 	diff := value - lag1
@@ -1682,7 +1885,7 @@ func (c *PrometheusClient) GetAnomalyMetricFeatures(ctx context.Context, baseQue
 
 // GetNodeCPUUtilization returns node CPU utilization (0-1 range)
 func (c *PrometheusClient) GetNodeCPUUtilization(ctx context.Context) (float64, error) {
-	query := `avg(1 - rate(node_cpu_seconds_total{mode="idle"}[5m]))`
+	query := c.Queries().MustRender("node.cpu_utilization", promql.Params{})
 	value, err := c.queryInstant(ctx, query)
 	if err != nil {
 		return 0, err
@@ -1692,7 +1895,7 @@ func (c *PrometheusClient) GetNodeCPUUtilization(ctx context.Context) (float64,
 
 // GetNodeMemoryUtilization returns node memory utilization (0-1 range)
 func (c *PrometheusClient) GetNodeMemoryUtilization(ctx context.Context) (float64, error) {
-	query := `1 - (node_memory_MemAvailable_bytes / node_memory_MemTotal_bytes)`
+	query := c.Queries().MustRender("node.memory_utilization", promql.Params{})
 	value, err := c.queryInstant(ctx, query)
 	if err != nil {
 		return 0, err
@@ -1702,28 +1905,23 @@ func (c *PrometheusClient) GetNodeMemoryUtilization(ctx context.Context) (float6
 
 // GetPodCPUUsage returns pod CPU usage for a namespace (in cores)
 func (c *PrometheusClient) GetPodCPUUsage(ctx context.Context, namespace string) (float64, error) {
-	query := fmt.Sprintf(`sum(rate(container_cpu_usage_seconds_total{namespace=%q,container!=""}[5m]))`, namespace)
+	query := c.Queries().MustRender("namespace.cpu_usage", promql.Params{Namespace: namespace})
 	return c.queryInstant(ctx, query)
 }
 
 // GetPodMemoryUsageRatio returns pod memory usage as ratio of limits (0-1 range)
 func (c *PrometheusClient) GetPodMemoryUsageRatio(ctx context.Context, namespace string) (float64, error) {
-	query := fmt.Sprintf(`sum(container_memory_working_set_bytes{namespace=%q,container!=""}) / sum(kube_pod_container_resource_limits{resource="memory",namespace=%q})`, namespace, namespace)
-	value, err := c.queryInstant(ctx, query)
+	// Falls back to a fixed 2Gi for containers without limits
+	value, _, err := c.queryTemplate(ctx, "namespace.memory_limit_ratio", promql.Params{Namespace: namespace})
 	if err != nil {
-		// Fallback to simpler query without limits
-		query = fmt.Sprintf(`avg(container_memory_working_set_bytes{namespace=%q,container!=""} / 2147483648)`, namespace)
-		value, err = c.queryInstant(ctx, query)
-		if err != nil {
-			return 0, err
-		}
+		return 0, err
 	}
 	return clampToUnitRange(value), nil
 }
 
 // GetContainerRestartCount returns the total container restart count for a namespace
 func (c *PrometheusClient) GetContainerRestartCount(ctx context.Context, namespace string) (float64, error) {
-	query := fmt.Sprintf(`sum(kube_pod_container_status_restarts_total{namespace=%q})`, namespace)
+	query := c.Queries().MustRender("namespace.container_restarts", promql.Params{Namespace: namespace})
 	return c.queryInstant(ctx, query)
 }
 
@@ -1757,7 +1955,14 @@ func (c *PrometheusClient) BuildAnomalyFeatureVector(ctx context.Context, namesp
 			continue
 		}
 
-		metricFeatures, err := c.GetAnomalyMetricFeatures(ctx, query)
+		var metricFeatures *AnomalyMetricFeatures
+		var err error
+		// The feature_vector.* queries total the scope, so per-pod series do not apply
+		if recorded, ok := c.RecordedFeatureQueries(name, namespace, deployment, pod); ok && !recorded.PerPod {
+			metricFeatures, err = c.GetRecordedAnomalyMetricFeatures(ctx, recorded)
+		} else {
+			metricFeatures, err = c.GetAnomalyMetricFeatures(ctx, query)
+		}
 		if err != nil {
 			c.log.WithError(err).WithField("metric", name).Debug("Failed to get metric features, using defaults")
 			features = append(features, c.defaultMetricFeatures()...)
@@ -1783,38 +1988,17 @@ func (c *PrometheusClient) buildAnomalyQueries(namespace, pod, deployment string
 		selectors = append(selectors, fmt.Sprintf(`pod=%q`, pod))
 	}
 	if deployment != "" {
-		selectors = append(selectors, fmt.Sprintf(`pod=~"%s-.*"`, deployment))
-	}
-
-	selectorStr := ""
-	if len(selectors) > 0 {
-		selectorStr = strings.Join(selectors, ",")
-	}
-
-	prependComma := func(s string) string {
-		if s != "" {
-			return "," + s
-		}
-		return ""
+		selectors = append(selectors, fmt.Sprintf(`pod=~%q`, regexp.QuoteMeta(deployment)+"-.*"))
 	}
 
+	queries := c.Queries()
+	params := promql.Params{Selector: strings.Join(selectors, ",")}
 	return map[string]string{
-		"node_cpu_utilization":    `avg(1 - rate(node_cpu_seconds_total{mode="idle"}[5m]))`,
-		"node_memory_utilization": `1 - (node_memory_MemAvailable_bytes / node_memory_MemTotal_bytes)`,
-		"pod_cpu_usage": fmt.Sprintf(
-			`sum(rate(container_cpu_usage_seconds_total{container!=""%s}[5m]))`,
-			prependComma(selectorStr),
-		),
-		"pod_memory_usage": fmt.Sprintf(
-			`sum(container_memory_working_set_bytes{container!=""%s}) / sum(kube_pod_container_resource_limits{resource="memory"%s})`,
-			prependComma(selectorStr), prependComma(selectorStr),
-		),
-		"container_restart_count": func() string {
-			if selectorStr != "" {
-				return fmt.Sprintf(`sum(kube_pod_container_status_restarts_total{%s})`, selectorStr)
-			}
-			return `sum(kube_pod_container_status_restarts_total)`
-		}(),
+		"node_cpu_utilization":    queries.MustRender("node.cpu_utilization", promql.Params{}),
+		"node_memory_utilization": queries.MustRender("node.memory_utilization", promql.Params{}),
+		"pod_cpu_usage":           queries.MustRender("feature_vector.pod_cpu_usage", params),
+		"pod_memory_usage":        queries.MustRender("feature_vector.pod_memory_usage", params),
+		"container_restart_count": queries.MustRender("feature_vector.container_restarts", params),
 	}
 }
 
@@ -7,12 +7,17 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/promql"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/quantity"
 )
 
 // mockPrometheusResponse creates a mock Prometheus response
@@ -84,13 +89,10 @@ func TestPrometheusClient_BuildQueryWithScope_Pod(t *testing.T) {
 	client := &PrometheusClient{log: log}
 
 	opts := QueryOptions{
-		Namespace: "default",
-		Pod:       "my-pod-12345",
-		Scope:     ScopePod,
+		Scope: models.Scope{Namespace: "default", Pod: "my-pod-12345", Level: ScopePod},
 	}
 
-	baseQuery := `sum(rate(container_cpu_usage_seconds_total{%s}[5m]))`
-	result := client.buildQueryWithScope(baseQuery, opts)
+	result := client.buildQueryWithScope("scope.cpu_usage", opts, promql.Params{})
 
 	assert.Contains(t, result, `container!=""`)
 	assert.Contains(t, result, `pod="my-pod-12345"`)
@@ -103,13 +105,10 @@ func TestPrometheusClient_BuildQueryWithScope_Deployment(t *testing.T) {
 	client := &PrometheusClient{log: log}
 
 	opts := QueryOptions{
-		Namespace:  "production",
-		Deployment: "web-app",
-		Scope:      ScopeDeployment,
+		Scope: models.Scope{Namespace: "production", Deployment: "web-app", Level: ScopeDeployment},
 	}
 
-	baseQuery := `sum(rate(container_cpu_usage_seconds_total{%s}[5m]))`
-	result := client.buildQueryWithScope(baseQuery, opts)
+	result := client.buildQueryWithScope("scope.cpu_usage", opts, promql.Params{})
 
 	assert.Contains(t, result, `container!=""`)
 	assert.Contains(t, result, `pod=~"web-app-.*"`)
@@ -122,12 +121,10 @@ func TestPrometheusClient_BuildQueryWithScope_Namespace(t *testing.T) {
 	client := &PrometheusClient{log: log}
 
 	opts := QueryOptions{
-		Namespace: "kube-system",
-		Scope:     ScopeNamespace,
+		Scope: models.Scope{Namespace: "kube-system", Level: ScopeNamespace},
 	}
 
-	baseQuery := `sum(container_memory_usage_bytes{%s})`
-	result := client.buildQueryWithScope(baseQuery, opts)
+	result := client.buildQueryWithScope("scope.memory_usage", opts, promql.Params{})
 
 	assert.Contains(t, result, `container!=""`)
 	assert.Contains(t, result, `namespace="kube-system"`)
@@ -140,11 +137,10 @@ func TestPrometheusClient_BuildQueryWithScope_Cluster(t *testing.T) {
 	client := &PrometheusClient{log: log}
 
 	opts := QueryOptions{
-		Scope: ScopeCluster,
+		Scope: models.Scope{Level: ScopeCluster},
 	}
 
-	baseQuery := `sum(rate(container_cpu_usage_seconds_total{%s}[5m]))`
-	result := client.buildQueryWithScope(baseQuery, opts)
+	result := client.buildQueryWithScope("scope.cpu_usage", opts, promql.Params{})
 
 	assert.Contains(t, result, `container!=""`)
 	assert.NotContains(t, result, `namespace=`)
@@ -164,8 +160,7 @@ func TestPrometheusClient_GetCPUTrend(t *testing.T) {
 	defer server.Close()
 
 	opts := QueryOptions{
-		Namespace: "default",
-		Scope:     ScopeNamespace,
+		Scope: models.Scope{Namespace: "default", Level: ScopeNamespace},
 	}
 
 	trendData, err := client.GetCPUTrend(context.Background(), opts, 7*24*time.Hour)
@@ -189,8 +184,7 @@ func TestPrometheusClient_GetMemoryTrend(t *testing.T) {
 	defer server.Close()
 
 	opts := QueryOptions{
-		Namespace: "production",
-		Scope:     ScopeNamespace,
+		Scope: models.Scope{Namespace: "production", Level: ScopeNamespace},
 	}
 
 	trendData, err := client.GetMemoryTrend(context.Background(), opts, 7*24*time.Hour)
@@ -423,30 +417,25 @@ func TestPrometheusClient_ScopedCPUUsage(t *testing.T) {
 		{
 			name: "pod scope",
 			opts: QueryOptions{
-				Namespace: "default",
-				Pod:       "my-pod-12345",
-				Scope:     ScopePod,
+				Scope: models.Scope{Namespace: "default", Pod: "my-pod-12345", Level: ScopePod},
 			},
 		},
 		{
 			name: "deployment scope",
 			opts: QueryOptions{
-				Namespace:  "production",
-				Deployment: "web-app",
-				Scope:      ScopeDeployment,
+				Scope: models.Scope{Namespace: "production", Deployment: "web-app", Level: ScopeDeployment},
 			},
 		},
 		{
 			name: "namespace scope",
 			opts: QueryOptions{
-				Namespace: "kube-system",
-				Scope:     ScopeNamespace,
+				Scope: models.Scope{Namespace: "kube-system", Level: ScopeNamespace},
 			},
 		},
 		{
 			name: "cluster scope",
 			opts: QueryOptions{
-				Scope: ScopeCluster,
+				Scope: models.Scope{Level: ScopeCluster},
 			},
 		},
 	}
@@ -472,13 +461,12 @@ func TestPrometheusClient_ScopedMemoryUsage(t *testing.T) {
 	defer server.Close()
 
 	opts := QueryOptions{
-		Namespace: "default",
-		Scope:     ScopeNamespace,
+		Scope: models.Scope{Namespace: "default", Level: ScopeNamespace},
 	}
 
 	value, err := client.GetMemoryUsage(context.Background(), opts)
 	require.NoError(t, err)
-	assert.Equal(t, int64(1073741824), value)
+	assert.Equal(t, quantity.GiB, value)
 }
 
 // TestPrometheusClient_Cache tests caching behavior
@@ -494,8 +482,7 @@ func TestPrometheusClient_Cache(t *testing.T) {
 	defer server.Close()
 
 	opts := QueryOptions{
-		Namespace: "test",
-		Scope:     ScopeNamespace,
+		Scope: models.Scope{Namespace: "test", Level: ScopeNamespace},
 	}
 
 	// First call should hit the server
@@ -569,17 +556,14 @@ func TestScopeType(t *testing.T) {
 // TestQueryOptions tests QueryOptions struct
 func TestQueryOptions(t *testing.T) {
 	opts := QueryOptions{
-		Namespace:  "production",
-		Deployment: "web-app",
-		Pod:        "web-app-12345",
-		Scope:      ScopeDeployment,
-		TimeRange:  24 * time.Hour,
+		Scope:     models.Scope{Namespace: "production", Deployment: "web-app", Pod: "web-app-12345", Level: ScopeDeployment},
+		TimeRange: 24 * time.Hour,
 	}
 
 	assert.Equal(t, "production", opts.Namespace)
 	assert.Equal(t, "web-app", opts.Deployment)
 	assert.Equal(t, "web-app-12345", opts.Pod)
-	assert.Equal(t, ScopeDeployment, opts.Scope)
+	assert.Equal(t, ScopeDeployment, opts.Level)
 	assert.Equal(t, 24*time.Hour, opts.TimeRange)
 }
 
@@ -808,7 +792,7 @@ func TestPrometheusClient_BuildScopedCPUQuery_Normalized(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			query := client.buildScopedCPUQuery(tt.namespace, tt.deployment, tt.pod)
+			query := client.Queries().MustRender("scope.cpu_utilization", promql.Params{Selector: ScopedSelector(tt.namespace, tt.deployment, tt.pod)})
 			for _, exp := range tt.expected {
 				assert.Contains(t, query, exp, "Query should contain: %s", exp)
 			}
@@ -853,7 +837,7 @@ func TestPrometheusClient_BuildScopedMemoryQuery_Normalized(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			query := client.buildScopedMemoryQuery(tt.namespace, tt.deployment, tt.pod)
+			query := client.Queries().MustRender("scope.memory_utilization", promql.Params{Selector: ScopedSelector(tt.namespace, tt.deployment, tt.pod)})
 			for _, exp := range tt.expected {
 				assert.Contains(t, query, exp, "Query should contain: %s", exp)
 			}
@@ -971,3 +955,89 @@ func TestPrometheusClient_NormalizedValues_InRange(t *testing.T) {
 		})
 	}
 }
+
+func TestPrometheusClient_GetScopedUsageHistory(t *testing.T) {
+	var queries []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query().Get("query"))
+		assert.Equal(t, "/api/v1/query_range", r.URL.Path)
+		assert.Equal(t, "2h", r.URL.Query().Get("step"))
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(mockPrometheusRangeResponse([]float64{0.4, 1.5})))
+	})
+
+	client, server := newTestPrometheusClient(t, handler)
+	defer server.Close()
+
+	end := time.Now()
+	cpu, memory, err := client.GetScopedUsageHistory(context.Background(), "payments", "api", "", end.Add(-4*time.Hour), end, 2*time.Hour)
+	require.NoError(t, err)
+	require.Len(t, cpu, 2)
+	require.Len(t, memory, 2)
+	assert.Equal(t, 0.4, cpu[0].Value)
+	assert.Equal(t, 1.0, cpu[1].Value, "values are clamped to the unit range")
+
+	require.Len(t, queries, 2)
+	assert.Contains(t, queries[0], `container_cpu_usage_seconds_total{container!="",pod!="",namespace="payments",pod=~"api-.*"}`)
+	assert.Contains(t, queries[1], "container_memory_working_set_bytes")
+}
+
+func TestPrometheusClient_GetContainerUsagePercentiles(t *testing.T) {
+	var queries []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query().Get("query")
+		queries = append(queries, query)
+		assert.Equal(t, "/api/v1/query", r.URL.Path)
+
+		value := "0.25"
+		if strings.Contains(query, "container_memory_working_set_bytes") {
+			value = "268435456"
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[` +
+			`{"metric":{"pod":"api-7d9f5c-x2x7k","container":"api"},"value":[1700000000,"` + value + `"]},` +
+			`{"metric":{"pod":"api-7d9f5c-x2x7k","container":"proxy"},"value":[1700000000,"NaN"]}]}}`))
+	})
+
+	client, server := newTestPrometheusClient(t, handler)
+	defer server.Close()
+
+	usage, err := client.GetContainerUsagePercentiles(context.Background(), "payments", "7d")
+	require.NoError(t, err)
+	require.Len(t, usage, 1, "NaN samples are dropped")
+
+	assert.Equal(t, "api-7d9f5c-x2x7k", usage[0].Pod)
+	assert.Equal(t, "api", usage[0].Container)
+	assert.Equal(t, UsagePercentiles{P50: 0.25, P95: 0.25, P99: 0.25}, usage[0].CPU)
+	assert.Equal(t, 268435456.0, usage[0].Memory.P99)
+
+	require.Len(t, queries, 6)
+	assert.Contains(t, queries[0], `quantile_over_time(0.5, sum by (pod, container) (rate(container_cpu_usage_seconds_total{namespace="payments"`)
+	assert.Contains(t, queries[0], `[7d:5m])`)
+	assert.Contains(t, queries[5], "quantile_over_time(0.99, sum by (pod, container) (container_memory_working_set_bytes")
+}
+
+// TestPrometheusClient_StrictSampleValues tests that malformed and non-finite samples are
+// rejected instead of being truncated into plausible numbers
+func TestPrometheusClient_StrictSampleValues(t *testing.T) {
+	body := `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1700000000,"12abc"]}]}}`
+	client, server := newTestPrometheusClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(body))
+	})
+	defer server.Close()
+	opts := QueryOptions{Scope: models.Scope{Namespace: "default", Level: ScopeNamespace}}
+
+	_, err := client.GetMemoryUsage(context.Background(), opts)
+	assert.Error(t, err, "trailing garbage must not parse as 12")
+
+	body = `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1700000000,"NaN"]}]}}`
+	client.ClearCache()
+	_, err = client.GetMemoryUsage(context.Background(), opts)
+	assert.ErrorIs(t, err, quantity.ErrNotFinite)
+}
+
+func TestScopedSelector_EscapesDeployment(t *testing.T) {
+	assert.Equal(t, `container!="",pod!="",namespace="apps",pod=~"api\\.v2-.*"`, ScopedSelector("apps", "api.v2", ""))
+	assert.Contains(t, ScopedSelector("apps", `a"} or vector(1)`, ""), `pod=~"a\"\\} or vector\\(1\\)-.*"`)
+}
@@ -0,0 +1,227 @@
+package integrations
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// promqlKeywords are identifiers that never name a metric
+var promqlKeywords = map[string]bool{
+	"by": true, "without": true, "on": true, "ignoring": true,
+	"group_left": true, "group_right": true, "bool": true, "offset": true,
+	"and": true, "or": true, "unless": true, "atan2": true,
+	"inf": true, "nan": true,
+}
+
+// promqlGroupingKeywords are followed by a parenthesised list of label names
+var promqlGroupingKeywords = map[string]bool{
+	"by": true, "without": true, "on": true, "ignoring": true,
+	"group_left": true, "group_right": true,
+}
+
+// existingMatcherPattern finds label names already matched inside a selector
+var existingMatcherPattern = regexp.MustCompile(`([a-zA-Z_][a-zA-Z0-9_]*)\s*(=~|!~|!=|=)`)
+
+// InjectLabelMatchers adds constant label matchers (e.g. cluster="prod-east") to every
+// vector selector in a PromQL expression. This is required when querying a federated or
+// central Prometheus (Thanos, Cortex, Mimir) that holds series from many clusters.
+// Selectors that already match a label keep their own matcher for it.
+func InjectLabelMatchers(query string, labels map[string]string) string {
+	if len(labels) == 0 {
+		return query
+	}
+
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return walkSelectors(query, func(_, matchers string) string {
+		return mergeSelector(matchers, keys, labels)
+	})
+}
+
+// ExtractSelectors returns every vector selector in a PromQL expression,
+// e.g. `container_cpu_usage_seconds_total{namespace="default"}`
+func ExtractSelectors(query string) []string {
+	var selectors []string
+	walkSelectors(query, func(metric, matchers string) string {
+		selectors = append(selectors, metric+"{"+strings.TrimSpace(matchers)+"}")
+		return "{" + matchers + "}"
+	})
+	return selectors
+}
+
+// walkSelectors scans a PromQL expression and calls fn for every vector selector with the
+// metric name (possibly empty) and the raw matcher text between the braces. The "{...}"
+// string returned by fn replaces the selector's braces (or is appended to a bare metric name).
+func walkSelectors(query string, fn func(metric, matchers string) string) string {
+	var out strings.Builder
+	out.Grow(len(query) + 32)
+
+	lastMetric := ""
+	i := 0
+	for i < len(query) {
+		ch := query[i]
+		switch {
+		case ch == '"' || ch == '\'' || ch == '`':
+			end := skipString(query, i)
+			out.WriteString(query[i:end])
+			i = end
+
+		case ch == '{':
+			end := findClosingBrace(query, i)
+			if end < 0 {
+				out.WriteString(query[i:])
+				return out.String()
+			}
+			out.WriteString(fn(lastMetric, query[i+1:end]))
+			lastMetric = ""
+			i = end + 1
+
+		case ch == '[':
+			// Range and subquery durations contain no selectors
+			end := strings.IndexByte(query[i:], ']')
+			if end < 0 {
+				out.WriteString(query[i:])
+				return out.String()
+			}
+			out.WriteString(query[i : i+end+1])
+			i += end + 1
+
+		case isDigit(ch):
+			// Numbers and durations (5m, 1e3, 0x1f)
+			start := i
+			for i < len(query) && (isIdentChar(query[i]) || query[i] == '.') {
+				i++
+			}
+			out.WriteString(query[start:i])
+
+		case isIdentStart(ch):
+			start := i
+			for i < len(query) && isIdentChar(query[i]) {
+				i++
+			}
+			ident := query[start:i]
+			out.WriteString(ident)
+
+			next := skipSpaces(query, i)
+			lower := strings.ToLower(ident)
+			switch {
+			case promqlGroupingKeywords[lower] && next < len(query) && query[next] == '(':
+				// Copy the label list verbatim
+				end := strings.IndexByte(query[next:], ')')
+				if end < 0 {
+					out.WriteString(query[i:])
+					return out.String()
+				}
+				out.WriteString(query[i : next+end+1])
+				i = next + end + 1
+			case promqlKeywords[lower]:
+				// keyword, nothing to inject
+			case next < len(query) && query[next] == '{':
+				// metric name; its selector is handled by the '{' case
+				lastMetric = ident
+			case next < len(query) && query[next] == '(':
+				// function call
+			case isAggregationModifier(query, next):
+				// aggregation operator written as "sum by (...) (...)"
+			default:
+				out.WriteString(fn(ident, ""))
+			}
+
+		default:
+			out.WriteByte(ch)
+			i++
+		}
+	}
+
+	return out.String()
+}
+
+// mergeSelector returns a "{...}" selector containing the existing matchers plus any
+// configured labels that are not already matched
+func mergeSelector(existing string, keys []string, labels map[string]string) string {
+	present := make(map[string]bool)
+	for _, m := range existingMatcherPattern.FindAllStringSubmatch(existing, -1) {
+		present[m[1]] = true
+	}
+
+	matchers := make([]string, 0, len(keys)+1)
+	for _, k := range keys {
+		if !present[k] {
+			matchers = append(matchers, fmt.Sprintf("%s=%q", k, labels[k]))
+		}
+	}
+	if trimmed := strings.TrimSpace(existing); trimmed != "" {
+		matchers = append(matchers, trimmed)
+	}
+
+	return "{" + strings.Join(matchers, ",") + "}"
+}
+
+// isAggregationModifier reports whether the word starting at i is "by" or "without"
+func isAggregationModifier(s string, i int) bool {
+	end := i
+	for end < len(s) && isIdentChar(s[end]) {
+		end++
+	}
+	word := strings.ToLower(s[i:end])
+	return word == "by" || word == "without"
+}
+
+// findClosingBrace returns the index of the '}' closing the selector opened at i,
+// ignoring braces inside quoted label values
+func findClosingBrace(s string, i int) int {
+	j := i + 1
+	for j < len(s) {
+		switch s[j] {
+		case '"', '\'', '`':
+			j = skipString(s, j)
+			continue
+		case '}':
+			return j
+		}
+		j++
+	}
+	return -1
+}
+
+// skipString returns the index just past the string literal starting at i
+func skipString(s string, i int) int {
+	quote := s[i]
+	j := i + 1
+	for j < len(s) {
+		if s[j] == '\\' && quote != '`' {
+			j += 2
+			continue
+		}
+		if s[j] == quote {
+			return j + 1
+		}
+		j++
+	}
+	return len(s)
+}
+
+func skipSpaces(s string, i int) int {
+	for i < len(s) && (s[i] == ' ' || s[i] == '\t' || s[i] == '\n') {
+		i++
+	}
+	return i
+}
+
+func isDigit(ch byte) bool {
+	return ch >= '0' && ch <= '9'
+}
+
+func isIdentStart(ch byte) bool {
+	return ch == '_' || ch == ':' || (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z')
+}
+
+func isIdentChar(ch byte) bool {
+	return isIdentStart(ch) || isDigit(ch)
+}
@@ -0,0 +1,153 @@
+package integrations
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/promql"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/quantity"
+)
+
+func TestInjectLabelMatchers(t *testing.T) {
+	labels := map[string]string{"cluster": "prod-east"}
+
+	tests := []struct {
+		name     string
+		query    string
+		expected string
+	}{
+		{
+			name:     "bare metric",
+			query:    `up`,
+			expected: `up{cluster="prod-east"}`,
+		},
+		{
+			name:     "selector with matchers",
+			query:    `kube_pod_info{namespace="default"}`,
+			expected: `kube_pod_info{cluster="prod-east",namespace="default"}`,
+		},
+		{
+			name:     "empty selector",
+			query:    `kube_pod_info{}`,
+			expected: `kube_pod_info{cluster="prod-east"}`,
+		},
+		{
+			name:     "function with range",
+			query:    `sum(rate(container_cpu_usage_seconds_total{container!=""}[5m]))`,
+			expected: `sum(rate(container_cpu_usage_seconds_total{cluster="prod-east",container!=""}[5m]))`,
+		},
+		{
+			name:     "aggregation grouping is untouched",
+			query:    `sum by (namespace, pod) (kube_pod_container_status_restarts_total)`,
+			expected: `sum by (namespace, pod) (kube_pod_container_status_restarts_total{cluster="prod-east"})`,
+		},
+		{
+			name:     "binary operation with matching",
+			query:    `a / on(pod) group_left b > 0`,
+			expected: `a{cluster="prod-east"} / on(pod) group_left b{cluster="prod-east"} > 0`,
+		},
+		{
+			name:     "existing cluster matcher is kept",
+			query:    `up{cluster="other"}`,
+			expected: `up{cluster="other"}`,
+		},
+		{
+			name:     "subquery and offset",
+			query:    `avg_over_time(node_load1[1h:5m] offset 1d)`,
+			expected: `avg_over_time(node_load1{cluster="prod-east"}[1h:5m] offset 1d)`,
+		},
+		{
+			name:     "string arguments are untouched",
+			query:    `label_replace(up, "dst", "$1", "src", "(.*)")`,
+			expected: `label_replace(up{cluster="prod-east"}, "dst", "$1", "src", "(.*)")`,
+		},
+		{
+			name:     "brace inside label value",
+			query:    `up{path="/a}b"}`,
+			expected: `up{cluster="prod-east",path="/a}b"}`,
+		},
+		{
+			name:     "scalar literal",
+			query:    `1 - avg(node_cpu_seconds_total{mode="idle"})`,
+			expected: `1 - avg(node_cpu_seconds_total{cluster="prod-east",mode="idle"})`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, InjectLabelMatchers(tt.query, labels))
+		})
+	}
+}
+
+func TestInjectLabelMatchers_NoLabels(t *testing.T) {
+	query := `sum(rate(container_cpu_usage_seconds_total[5m]))`
+	assert.Equal(t, query, InjectLabelMatchers(query, nil))
+}
+
+func TestInjectLabelMatchers_MultipleLabelsSorted(t *testing.T) {
+	result := InjectLabelMatchers(`up`, map[string]string{"env": "prod", "cluster": "east"})
+	assert.Equal(t, `up{cluster="east",env="prod"}`, result)
+}
+
+func TestPrometheusClient_ExternalLabels(t *testing.T) {
+	var received string
+	client, server := newTestPrometheusClient(t, func(w http.ResponseWriter, r *http.Request) {
+		received = r.URL.Query().Get("query")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(mockPrometheusResponse(0.5)))
+	})
+	defer server.Close()
+
+	client.SetExternalLabels(map[string]string{"cluster": "prod-east"})
+	assert.Equal(t, map[string]string{"cluster": "prod-east"}, client.ExternalLabels())
+
+	_, err := client.Query(context.Background(), `sum(kube_pod_info{namespace="default"})`)
+	require.NoError(t, err)
+	assert.Equal(t, `sum(kube_pod_info{cluster="prod-east",namespace="default"})`, received)
+}
+
+func TestPrometheusClient_BuildQueryWithScope_ExtraLabels(t *testing.T) {
+	client := &PrometheusClient{}
+
+	opts := QueryOptions{
+		Scope:       models.Scope{Namespace: "default", Level: ScopeNamespace},
+		ExtraLabels: map[string]string{"cluster": "prod-east"},
+	}
+
+	result := client.buildQueryWithScope("scope.memory_usage", opts, promql.Params{})
+	assert.Equal(t, `sum(container_memory_usage_bytes{container!="",namespace="default",cluster="prod-east"})`, result)
+	assert.Contains(t, client.buildQueryWithScope("scope.memory_limit_ratio", opts, promql.Params{Window: "1h"}), `cluster="prod-east"`)
+}
+
+func TestPrometheusClient_SetKubernetesVersion(t *testing.T) {
+	var queries []string
+	client, server := newTestPrometheusClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query().Get("query")
+		queries = append(queries, query)
+		if query != "sum(apiserver_storage_objects)" {
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[]}}`))
+			return
+		}
+		_, _ = w.Write([]byte(mockPrometheusResponse(1234)))
+	})
+	defer server.Close()
+
+	// An unknown version tries the pre-1.21 metric first
+	count, err := client.GetEtcdObjectCount(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, quantity.Count(1234), count)
+	assert.Equal(t, []string{"sum(etcd_object_counts)", "sum(apiserver_storage_objects)"}, queries)
+
+	queries = nil
+	client.SetKubernetesVersion(promql.Version{Major: 1, Minor: 27})
+	count, err = client.GetEtcdObjectCount(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, quantity.Count(1234), count)
+	assert.Equal(t, []string{"sum(apiserver_storage_objects)"}, queries)
+}
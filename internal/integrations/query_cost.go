@@ -0,0 +1,247 @@
+package integrations
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultMaxQuerySamples bounds series × points for range queries. Queries above it are
+// downgraded to a coarser step rather than refused.
+const DefaultMaxQuerySamples = 10_000_000
+
+// QueryCostError is returned when a query is estimated to touch too many series
+type QueryCostError struct {
+	Query           string `json:"query"`
+	EstimatedSeries int    `json:"estimated_series"`
+	Limit           int    `json:"limit"`
+	Suggestion      string `json:"suggestion"`
+}
+
+// Error implements the error interface
+func (e *QueryCostError) Error() string {
+	return fmt.Sprintf("query refused: estimated %d series exceeds limit of %d. %s",
+		e.EstimatedSeries, e.Limit, e.Suggestion)
+}
+
+// IsQueryCostError reports whether err was caused by the query cost guardrail
+func IsQueryCostError(err error) (*QueryCostError, bool) {
+	var costErr *QueryCostError
+	if errors.As(err, &costErr) {
+		return costErr, true
+	}
+	return nil, false
+}
+
+// SetMaxQuerySeries enables the query cost guardrail. Before executing a query, the number
+// of series matched by its selectors is estimated and queries above the limit are refused.
+// A limit of 0 disables the guardrail.
+func (c *PrometheusClient) SetMaxQuerySeries(limit int) {
+	if c == nil {
+		return
+	}
+	c.maxQuerySeries = limit
+}
+
+// MaxQuerySeries returns the configured series limit (0 when disabled)
+func (c *PrometheusClient) MaxQuerySeries() int {
+	if c == nil {
+		return 0
+	}
+	return c.maxQuerySeries
+}
+
+// seriesLookback is the window in which a series must have samples to be counted by
+// EstimateSeries, matching Prometheus' default lookback delta
+const seriesLookback = 5 * time.Minute
+
+// EstimateSeries estimates how many series a query touches by counting the series
+// matched by each of its vector selectors through the series metadata API, which reads
+// the index only and never the samples the guard protects. With the guard enabled each
+// selector counts at most one series beyond the limit. Counts are cached like other
+// metrics.
+func (c *PrometheusClient) EstimateSeries(ctx context.Context, query string) (int, error) {
+	total := 0
+	for _, selector := range ExtractSelectors(query) {
+		cacheKey := "series_count_" + selector
+		if count, ok := c.getCached(cacheKey); ok {
+			total += int(count)
+			continue
+		}
+
+		count, err := c.countSeries(ctx, selector)
+		if err != nil {
+			return 0, fmt.Errorf("failed to estimate series for %s: %w", selector, err)
+		}
+		c.setCached(cacheKey, float64(count))
+		total += count
+	}
+	return total, nil
+}
+
+// countSeries counts the series matching a selector that had samples within the last
+// seriesLookback, using GET /api/v1/series with a limit when the guard is enabled
+func (c *PrometheusClient) countSeries(ctx context.Context, selector string) (int, error) {
+	reqURL, err := url.Parse(fmt.Sprintf("%s/api/v1/series", c.baseURL))
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse URL: %w", err)
+	}
+
+	end := c.currentTime()
+	params := url.Values{}
+	params.Set("match[]", InjectLabelMatchers(selector, c.externalLabels))
+	params.Set("start", strconv.FormatInt(end.Add(-seriesLookback).Unix(), 10))
+	params.Set("end", strconv.FormatInt(end.Unix(), 10))
+	if c.maxQuerySeries > 0 {
+		params.Set("limit", strconv.Itoa(c.maxQuerySeries+1))
+	}
+	reqURL.RawQuery = params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL.String(), http.NoBody)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if token := c.getServiceAccountToken(); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to execute series request: %w", err)
+	}
+	defer closeBody(resp)
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("prometheus returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var seriesResp struct {
+		Status string            `json:"status"`
+		Data   []json.RawMessage `json:"data"`
+		Error  string            `json:"error"`
+	}
+	if err := json.Unmarshal(body, &seriesResp); err != nil {
+		return 0, fmt.Errorf("failed to parse response: %w", err)
+	}
+	if seriesResp.Status != "success" {
+		return 0, fmt.Errorf("prometheus series request failed: %s", seriesResp.Error)
+	}
+	return len(seriesResp.Data), nil
+}
+
+// checkQueryCost refuses queries estimated to exceed the series limit.
+// Estimation failures are logged and do not block the query.
+func (c *PrometheusClient) checkQueryCost(ctx context.Context, query string) error {
+	if c.maxQuerySeries <= 0 {
+		return nil
+	}
+
+	series, err := c.EstimateSeries(ctx, query)
+	if err != nil {
+		c.log.WithError(err).Debug("Query cost estimation failed, executing query anyway")
+		return nil
+	}
+
+	if series > c.maxQuerySeries {
+		costErr := &QueryCostError{
+			Query:           query,
+			EstimatedSeries: series,
+			Limit:           c.maxQuerySeries,
+			Suggestion:      suggestNarrowerScope(query),
+		}
+		c.log.WithFields(logrus.Fields{
+			"estimated_series": series,
+			"limit":            c.maxQuerySeries,
+		}).Warn("Refusing expensive Prometheus query")
+		return costErr
+	}
+
+	return nil
+}
+
+// guardRangeQuery checks a range query's cost and returns the step to use. Queries touching
+// too many series are refused; queries returning too many samples get a coarser step.
+func (c *PrometheusClient) guardRangeQuery(ctx context.Context, query string, start, end time.Time, step string) (string, error) {
+	if c.maxQuerySeries <= 0 {
+		return step, nil
+	}
+
+	series, err := c.EstimateSeries(ctx, query)
+	if err != nil {
+		c.log.WithError(err).Debug("Query cost estimation failed, executing query anyway")
+		return step, nil
+	}
+
+	if series > c.maxQuerySeries {
+		return step, &QueryCostError{
+			Query:           query,
+			EstimatedSeries: series,
+			Limit:           c.maxQuerySeries,
+			Suggestion:      suggestNarrowerScope(query),
+		}
+	}
+
+	stepDur, ok := parsePromStep(step)
+	if !ok || series == 0 {
+		return step, nil
+	}
+
+	points := int(end.Sub(start) / stepDur)
+	if series*points <= DefaultMaxQuerySamples {
+		return step, nil
+	}
+
+	// Downgrade resolution so that series × points fits the sample budget
+	maxPoints := DefaultMaxQuerySamples / series
+	if maxPoints < 1 {
+		maxPoints = 1
+	}
+	newStep := time.Duration(math.Ceil(float64(end.Sub(start))/float64(maxPoints)/float64(time.Minute))) * time.Minute
+	downgraded := formatDurationForPromQL(newStep)
+
+	c.log.WithFields(logrus.Fields{
+		"estimated_series": series,
+		"original_step":    step,
+		"downgraded_step":  downgraded,
+	}).Info("Downgraded range query resolution to limit query cost")
+
+	return downgraded, nil
+}
+
+// parsePromStep parses a Prometheus step ("5m", "1h" or a number of seconds)
+func parsePromStep(step string) (time.Duration, bool) {
+	if d, err := time.ParseDuration(step); err == nil && d > 0 {
+		return d, true
+	}
+	if secs, err := strconv.ParseFloat(step, 64); err == nil && secs > 0 {
+		return time.Duration(secs * float64(time.Second)), true
+	}
+	return 0, false
+}
+
+// suggestNarrowerScope proposes how to reduce a query's cardinality
+func suggestNarrowerScope(query string) string {
+	switch {
+	case !strings.Contains(query, "namespace="):
+		return "Narrow the scope by specifying a namespace."
+	case !strings.Contains(query, "pod=") && !strings.Contains(query, "pod=~"):
+		return "Narrow the scope by specifying a deployment or pod within the namespace."
+	default:
+		return "Reduce the time range or use pre-computed recording rules."
+	}
+}
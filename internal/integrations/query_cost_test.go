@@ -0,0 +1,152 @@
+package integrations
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newCostTestClient returns a client whose series API reports the given series count,
+// truncated to the requested limit as Prometheus does
+func newCostTestClient(t *testing.T, seriesCount int) (*PrometheusClient, *[]*http.Request, func()) {
+	t.Helper()
+	var requests []*http.Request
+	client, server := newTestPrometheusClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/series":
+			n := seriesCount
+			if limit, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && limit < n {
+				n = limit
+			}
+			series := make([]string, n)
+			for i := range series {
+				series[i] = fmt.Sprintf(`{"__name__":"m","i":"%d"}`, i)
+			}
+			_, _ = fmt.Fprintf(w, `{"status":"success","data":[%s]}`, strings.Join(series, ","))
+		case "/api/v1/query_range":
+			_, _ = w.Write([]byte(mockPrometheusRangeResponse([]float64{0.1, 0.2})))
+		default:
+			_, _ = w.Write([]byte(mockPrometheusResponse(0.5)))
+		}
+	})
+	return client, &requests, server.Close
+}
+
+func TestExtractSelectors(t *testing.T) {
+	selectors := ExtractSelectors(`sum(rate(container_cpu_usage_seconds_total{namespace="a"}[5m])) / sum(kube_node_status_allocatable)`)
+	assert.Equal(t, []string{
+		`container_cpu_usage_seconds_total{namespace="a"}`,
+		`kube_node_status_allocatable{}`,
+	}, selectors)
+}
+
+func TestPrometheusClient_EstimateSeries(t *testing.T) {
+	client, requests, done := newCostTestClient(t, 1200)
+	defer done()
+
+	series, err := client.EstimateSeries(context.Background(), `a{namespace="x"} / b`)
+	require.NoError(t, err)
+	assert.Equal(t, 2400, series)
+
+	// Estimation reads series metadata, never samples
+	require.Len(t, *requests, 2)
+	assert.Equal(t, "/api/v1/series", (*requests)[0].URL.Path)
+	assert.Equal(t, `a{namespace="x"}`, (*requests)[0].URL.Query().Get("match[]"))
+	assert.Empty(t, (*requests)[0].URL.Query().Get("limit"), "no limit without a guard")
+
+	// Second estimate is served from cache
+	_, err = client.EstimateSeries(context.Background(), `a{namespace="x"} / b`)
+	require.NoError(t, err)
+	assert.Len(t, *requests, 2)
+}
+
+func TestPrometheusClient_QueryCostGuard(t *testing.T) {
+	t.Run("disabled by default", func(t *testing.T) {
+		client, requests, done := newCostTestClient(t, 5_000)
+		defer done()
+
+		_, err := client.Query(context.Background(), `sum(container_memory_working_set_bytes) by (pod)`)
+		require.NoError(t, err)
+		assert.Len(t, *requests, 1, "no series request when guard is disabled")
+	})
+
+	t.Run("refuses expensive query with suggestion", func(t *testing.T) {
+		client, requests, done := newCostTestClient(t, 5_000)
+		defer done()
+		client.SetMaxQuerySeries(1_000)
+
+		_, err := client.Query(context.Background(), `sum(container_memory_working_set_bytes) by (pod)`)
+		require.Error(t, err)
+
+		// Counting stops one series past the limit
+		require.Len(t, *requests, 1)
+		assert.Equal(t, "1001", (*requests)[0].URL.Query().Get("limit"))
+
+		costErr, ok := IsQueryCostError(err)
+		require.True(t, ok)
+		assert.Equal(t, 1_001, costErr.EstimatedSeries)
+		assert.Equal(t, 1_000, costErr.Limit)
+		assert.Contains(t, costErr.Suggestion, "namespace")
+	})
+
+	t.Run("allows cheap query", func(t *testing.T) {
+		client, _, done := newCostTestClient(t, 50)
+		defer done()
+		client.SetMaxQuerySeries(1_000_000)
+
+		value, err := client.Query(context.Background(), `sum(container_memory_working_set_bytes{namespace="a"})`)
+		require.NoError(t, err)
+		assert.Equal(t, 0.5, value)
+	})
+}
+
+func TestPrometheusClient_GuardRangeQuery_Downgrade(t *testing.T) {
+	client, _, done := newCostTestClient(t, 100_000)
+	defer done()
+	client.SetMaxQuerySeries(1_000_000)
+
+	end := time.Now()
+	start := end.Add(-24 * time.Hour)
+
+	// 100k series × 1440 points (1m step) exceeds the sample budget
+	step, err := client.guardRangeQuery(context.Background(), `up{namespace="a"}`, start, end, "1m")
+	require.NoError(t, err)
+	assert.NotEqual(t, "1m", step)
+
+	stepDur, ok := parsePromStep(step)
+	require.True(t, ok)
+	assert.LessOrEqual(t, 100_000*int(24*time.Hour/stepDur), DefaultMaxQuerySamples)
+
+	// Coarse step already within budget is unchanged
+	step, err = client.guardRangeQuery(context.Background(), `up{namespace="a"}`, start, end, "1h")
+	require.NoError(t, err)
+	assert.Equal(t, "1h", step)
+}
+
+func TestSuggestNarrowerScope(t *testing.T) {
+	assert.Contains(t, suggestNarrowerScope(`sum(up) by (pod)`), "namespace")
+	assert.Contains(t, suggestNarrowerScope(`sum(up{namespace="a"}) by (pod)`), "deployment or pod")
+	assert.Contains(t, suggestNarrowerScope(`up{namespace="a",pod="b"}`), "time range")
+}
+
+func TestParsePromStep(t *testing.T) {
+	d, ok := parsePromStep("5m")
+	assert.True(t, ok)
+	assert.Equal(t, 5*time.Minute, d)
+
+	d, ok = parsePromStep("3600")
+	assert.True(t, ok)
+	assert.Equal(t, time.Hour, d)
+
+	_, ok = parsePromStep("bad")
+	assert.False(t, ok)
+}
@@ -0,0 +1,211 @@
+package integrations
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/promql"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/diagnostics"
+)
+
+// RangeAggregation selects how the series of a range query result are combined into one
+type RangeAggregation string
+
+const (
+	// RangeFirst keeps the first series Prometheus returned, as single-series queries
+	// have always done
+	RangeFirst RangeAggregation = "first"
+	// RangeSum adds the samples of all series at each timestamp
+	RangeSum RangeAggregation = "sum"
+	// RangeAvg averages the samples of all series at each timestamp
+	RangeAvg RangeAggregation = "avg"
+	// RangeMin keeps the lowest sample at each timestamp
+	RangeMin RangeAggregation = "min"
+	// RangeMax keeps the highest sample at each timestamp
+	RangeMax RangeAggregation = "max"
+)
+
+// RangeMatrix holds every series of a range query result, in response order
+type RangeMatrix []RangeSeries
+
+// SeriesKey returns the label set of a series in PromQL notation with sorted label
+// names, e.g. {container="app",pod="api-1"}. It identifies a series within a result.
+func SeriesKey(metric map[string]string) string {
+	names := make([]string, 0, len(metric))
+	for name := range metric {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, name := range names {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(name)
+		b.WriteByte('=')
+		b.WriteString(strconv.Quote(metric[name]))
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// ByKey returns the series keyed by their label set
+func (m RangeMatrix) ByKey() map[string]RangeSeries {
+	result := make(map[string]RangeSeries, len(m))
+	for _, series := range m {
+		result[SeriesKey(series.Metric)] = series
+	}
+	return result
+}
+
+// ByLabel returns the series keyed by the value of one label, e.g. "pod" for a per-pod
+// breakdown. Series sharing a value are summed; series without the label are left out.
+func (m RangeMatrix) ByLabel(name string) map[string][]MetricDataPoint {
+	groups := make(map[string]RangeMatrix)
+	for _, series := range m {
+		value, ok := series.Metric[name]
+		if !ok {
+			continue
+		}
+		groups[value] = append(groups[value], series)
+	}
+
+	result := make(map[string][]MetricDataPoint, len(groups))
+	for value, group := range groups {
+		if len(group) == 1 {
+			result[value] = group[0].Points
+			continue
+		}
+		result[value] = group.Aggregate(RangeSum)
+	}
+	return result
+}
+
+// Aggregate combines the series into one. RangeFirst returns the first series;
+// the other aggregations combine the samples of all series at each timestamp, in
+// timestamp order, skipping non-finite samples.
+func (m RangeMatrix) Aggregate(agg RangeAggregation) []MetricDataPoint {
+	if len(m) == 0 {
+		return nil
+	}
+	if agg == RangeFirst || agg == "" || len(m) == 1 {
+		return m[0].Points
+	}
+
+	type accumulator struct {
+		value float64
+		count int
+	}
+	byTime := make(map[int64]*accumulator)
+	for _, series := range m {
+		for _, point := range series.Points {
+			if math.IsNaN(point.Value) || math.IsInf(point.Value, 0) {
+				continue
+			}
+			ts := point.Timestamp.Unix()
+			acc := byTime[ts]
+			if acc == nil {
+				byTime[ts] = &accumulator{value: point.Value, count: 1}
+				continue
+			}
+			switch agg {
+			case RangeMin:
+				acc.value = math.Min(acc.value, point.Value)
+			case RangeMax:
+				acc.value = math.Max(acc.value, point.Value)
+			default:
+				acc.value += point.Value
+			}
+			acc.count++
+		}
+	}
+
+	points := make([]MetricDataPoint, 0, len(byTime))
+	for ts, acc := range byTime {
+		value := acc.value
+		if agg == RangeAvg {
+			value /= float64(acc.count)
+		}
+		points = append(points, MetricDataPoint{Timestamp: time.Unix(ts, 0), Value: value})
+	}
+	sort.Slice(points, func(i, j int) bool { return points[i].Timestamp.Before(points[j].Timestamp) })
+	return points
+}
+
+// QueryRangeMatrix runs a range query over [start, end] and returns every series of the
+// result. It is subject to the same cardinality guard and downsampling as the
+// single-series range queries.
+func (c *PrometheusClient) QueryRangeMatrix(ctx context.Context, query string, start, end time.Time, step time.Duration) (RangeMatrix, error) {
+	if !c.IsAvailable() {
+		return nil, fmt.Errorf("prometheus client not available")
+	}
+
+	stepStr, err := c.guardRangeQuery(ctx, query, start, end, formatDurationForPromQL(step))
+	if err != nil {
+		return nil, err
+	}
+	reqURL, err := c.buildRangeQueryURL(query, start, end, stepStr)
+	if err != nil {
+		return nil, err
+	}
+
+	var matrix RangeMatrix
+	queryStart := time.Now()
+	err = c.streamRangeQuery(ctx, reqURL, RangeStreamOptions{MaxPoints: c.rangeMaxPoints, Start: start, End: end}, func(series RangeSeries) error {
+		matrix = append(matrix, series)
+		return nil
+	})
+	diagnostics.FromContext(ctx).RecordQuery("range", InjectLabelMatchers(query, c.externalLabels), time.Since(queryStart), err)
+	if err != nil {
+		return nil, err
+	}
+	return matrix, nil
+}
+
+// QueryRange runs a range query over [start, end] and combines its series with agg.
+// RangeFirst reads only the first series, like the built-in trend queries; it fails
+// when the result is empty.
+func (c *PrometheusClient) QueryRange(ctx context.Context, query string, start, end time.Time, step time.Duration, agg RangeAggregation) ([]MetricDataPoint, error) {
+	if agg == RangeFirst || agg == "" {
+		if !c.IsAvailable() {
+			return nil, fmt.Errorf("prometheus client not available")
+		}
+		stepStr, err := c.guardRangeQuery(ctx, query, start, end, formatDurationForPromQL(step))
+		if err != nil {
+			return nil, err
+		}
+		return c.executeTracedRangeQuery(ctx, query, start, end, stepStr)
+	}
+
+	matrix, err := c.QueryRangeMatrix(ctx, query, start, end, step)
+	if err != nil {
+		return nil, err
+	}
+	if len(matrix) == 0 {
+		return nil, fmt.Errorf("no data returned for query: %s", query)
+	}
+	return matrix.Aggregate(agg), nil
+}
+
+// GetNamespacePodCPUTrends queries the hourly CPU usage of every pod in a namespace
+// over window (7d, 14d or 30d)
+func (c *PrometheusClient) GetNamespacePodCPUTrends(ctx context.Context, namespace, window string) (RangeMatrix, error) {
+	start, end := c.calculateTimeRange(window)
+	query := c.Queries().MustRender("namespace.pod_cpu_usage", promql.Params{Namespace: namespace})
+	return c.QueryRangeMatrix(ctx, query, start, end, time.Hour)
+}
+
+// GetNamespacePodMemoryTrends queries the hourly memory usage of every pod in a
+// namespace over window (7d, 14d or 30d)
+func (c *PrometheusClient) GetNamespacePodMemoryTrends(ctx context.Context, namespace, window string) (RangeMatrix, error) {
+	start, end := c.calculateTimeRange(window)
+	query := c.Queries().MustRender("namespace.pod_memory_usage", promql.Params{Namespace: namespace})
+	return c.QueryRangeMatrix(ctx, query, start, end, time.Hour)
+}
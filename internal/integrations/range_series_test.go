@@ -0,0 +1,116 @@
+package integrations
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// seriesAt builds a series with one point per value, a minute apart from start
+func seriesAt(metric map[string]string, start time.Time, values ...float64) RangeSeries {
+	series := RangeSeries{Metric: metric}
+	for i, v := range values {
+		series.Points = append(series.Points, MetricDataPoint{Timestamp: start.Add(time.Duration(i) * time.Minute), Value: v})
+	}
+	return series
+}
+
+func TestSeriesKey(t *testing.T) {
+	assert.Equal(t, `{container="app",pod="api-1"}`, SeriesKey(map[string]string{"pod": "api-1", "container": "app"}))
+	assert.Equal(t, `{pod="a\"b"}`, SeriesKey(map[string]string{"pod": `a"b`}))
+	assert.Equal(t, `{}`, SeriesKey(nil))
+}
+
+func TestRangeMatrix(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	matrix := RangeMatrix{
+		seriesAt(map[string]string{"pod": "api-1", "container": "app"}, start, 1, 2, 3),
+		seriesAt(map[string]string{"pod": "api-1", "container": "sidecar"}, start, 0.5, 0.5, math.NaN()),
+		seriesAt(map[string]string{"pod": "api-2", "container": "app"}, start.Add(time.Minute), 4, 6),
+		seriesAt(map[string]string{"job": "kubelet"}, start, 9),
+	}
+
+	t.Run("by key", func(t *testing.T) {
+		byKey := matrix.ByKey()
+		require.Len(t, byKey, 4)
+		assert.Equal(t, matrix[1], byKey[`{container="sidecar",pod="api-1"}`])
+	})
+
+	t.Run("by label", func(t *testing.T) {
+		byPod := matrix.ByLabel("pod")
+		require.Len(t, byPod, 2, "series without the label are left out")
+		require.Len(t, byPod["api-1"], 3)
+		assert.Equal(t, 1.5, byPod["api-1"][0].Value, "containers of a pod are summed")
+		assert.Equal(t, 3.0, byPod["api-1"][2].Value, "NaN samples are skipped")
+		assert.Equal(t, matrix[2].Points, byPod["api-2"])
+	})
+
+	t.Run("aggregate", func(t *testing.T) {
+		assert.Equal(t, matrix[0].Points, matrix.Aggregate(RangeFirst), "first keeps the single-series behavior")
+		assert.Equal(t, matrix[0].Points, matrix.Aggregate(""))
+
+		sum := matrix.Aggregate(RangeSum)
+		require.Len(t, sum, 3)
+		assert.Equal(t, start, sum[0].Timestamp)
+		assert.Equal(t, []float64{10.5, 6.5, 9}, []float64{sum[0].Value, sum[1].Value, sum[2].Value})
+
+		avg := matrix.Aggregate(RangeAvg)
+		assert.InDelta(t, 10.5/3, avg[0].Value, 1e-9)
+		assert.Equal(t, 4.5, avg[2].Value)
+
+		assert.Equal(t, 0.5, matrix.Aggregate(RangeMin)[0].Value)
+		assert.Equal(t, 9.0, matrix.Aggregate(RangeMax)[0].Value)
+
+		assert.Nil(t, RangeMatrix{}.Aggregate(RangeSum))
+	})
+}
+
+func TestPrometheusClient_QueryRangeMatrix(t *testing.T) {
+	start := time.Now().Add(-2 * time.Hour).Truncate(time.Hour)
+	body := rangeBody(3, start, time.Hour, 3)
+	var queries []string
+	client, server := newTestPrometheusClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/query_range" {
+			http.NotFound(w, r)
+			return
+		}
+		queries = append(queries, r.URL.Query().Get("query"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	})
+	defer server.Close()
+	ctx := context.Background()
+
+	matrix, err := client.QueryRangeMatrix(ctx, "up", start, start.Add(2*time.Hour), time.Hour)
+	require.NoError(t, err)
+	require.Len(t, matrix, 3)
+	assert.Equal(t, "pod-2", matrix[2].Metric["pod"])
+
+	// Every series has the values 0, 1 and 2
+	points, err := client.QueryRange(ctx, "up", start, start.Add(2*time.Hour), time.Hour, RangeSum)
+	require.NoError(t, err)
+	require.Len(t, points, 3)
+	assert.Equal(t, 6.0, points[2].Value)
+
+	points, err = client.QueryRange(ctx, "up", start, start.Add(2*time.Hour), time.Hour, RangeFirst)
+	require.NoError(t, err)
+	assert.Equal(t, 2.0, points[2].Value)
+
+	pods, err := client.GetNamespacePodCPUTrends(ctx, "payments", "7d")
+	require.NoError(t, err)
+	assert.Len(t, pods.ByLabel("pod"), 3)
+	assert.True(t, strings.HasPrefix(queries[len(queries)-1], "sum by (pod)"), queries[len(queries)-1])
+
+	body = `{"status":"success","data":{"resultType":"matrix","result":[]}}`
+	matrix, err = client.QueryRangeMatrix(ctx, "up", start, start.Add(2*time.Hour), time.Hour)
+	require.NoError(t, err)
+	assert.Empty(t, matrix)
+	_, err = client.QueryRange(ctx, "up", start, start.Add(2*time.Hour), time.Hour, RangeAvg)
+	assert.ErrorContains(t, err, "no data returned")
+}
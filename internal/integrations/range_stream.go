@@ -0,0 +1,318 @@
+package integrations
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/quantity"
+)
+
+// maxErrorBodyBytes bounds the part of an error response included in the error
+const maxErrorBodyBytes = 64 << 10
+
+// ErrStopStream is returned by a StreamRangeResponse callback to stop reading
+// further series without failing
+var ErrStopStream = errors.New("stop streaming range response")
+
+// RangeSeries is one series of a range query result
+type RangeSeries struct {
+	Metric map[string]string
+	Points []MetricDataPoint
+}
+
+// RangeStreamOptions control how StreamRangeResponse builds series
+type RangeStreamOptions struct {
+	// MaxPoints downsamples every series to at most this many points by averaging
+	// the samples of equal time buckets between Start and End. Zero keeps all points.
+	MaxPoints int
+
+	// Start and End bound the queried range; they are required for downsampling
+	Start, End time.Time
+}
+
+// SetRangeMaxPoints downsamples range query results to at most limit points per
+// series, averaging samples into equal time buckets while the response is read.
+// A limit of 0 keeps every point.
+func (c *PrometheusClient) SetRangeMaxPoints(limit int) {
+	if c == nil {
+		return
+	}
+	c.rangeMaxPoints = limit
+}
+
+// bucketWidth returns the width of a downsampling bucket, or 0 without downsampling
+func (o RangeStreamOptions) bucketWidth() time.Duration {
+	if o.MaxPoints <= 0 || !o.End.After(o.Start) {
+		return 0
+	}
+	return time.Duration(math.Ceil(float64(o.End.Sub(o.Start)) / float64(o.MaxPoints)))
+}
+
+// StreamRangeResponse decodes a Prometheus range query response from r one series at
+// a time, calling fn with each series as soon as it is read. Only the series being
+// decoded is held in memory, and with MaxPoints it is downsampled while its samples
+// are read, so responses over long windows and many series are parsed in bounded
+// memory. Samples that cannot be parsed are skipped. A callback returning
+// ErrStopStream ends the stream early without error.
+func StreamRangeResponse(r io.Reader, opts RangeStreamOptions, fn func(RangeSeries) error) error {
+	dec := json.NewDecoder(r)
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+
+	var status, errorType, errMsg string
+	for dec.More() {
+		key, err := objectKey(dec)
+		if err != nil {
+			return err
+		}
+		switch key {
+		case "status":
+			err = dec.Decode(&status)
+		case "errorType":
+			err = dec.Decode(&errorType)
+		case "error":
+			err = dec.Decode(&errMsg)
+		case "data":
+			if status != "" && status != "success" {
+				return fmt.Errorf("prometheus query failed: %s - %s", errorType, errMsg)
+			}
+			err = streamRangeData(dec, opts, fn)
+			if errors.Is(err, ErrStopStream) {
+				return nil
+			}
+		default:
+			err = skipValue(dec)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if status != "success" {
+		return fmt.Errorf("prometheus query failed: %s - %s", errorType, errMsg)
+	}
+	return nil
+}
+
+// streamRangeData decodes the data object of a range query response
+func streamRangeData(dec *json.Decoder, opts RangeStreamOptions, fn func(RangeSeries) error) error {
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+	for dec.More() {
+		key, err := objectKey(dec)
+		if err != nil {
+			return err
+		}
+		if key != "result" {
+			if err := skipValue(dec); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := expectDelim(dec, '['); err != nil {
+			return err
+		}
+		for dec.More() {
+			series, err := decodeRangeSeries(dec, opts)
+			if err != nil {
+				return err
+			}
+			if err := fn(series); err != nil {
+				return err
+			}
+		}
+		if err := expectDelim(dec, ']'); err != nil {
+			return err
+		}
+	}
+	return expectDelim(dec, '}')
+}
+
+// decodeRangeSeries decodes one series, sample by sample
+func decodeRangeSeries(dec *json.Decoder, opts RangeStreamOptions) (RangeSeries, error) {
+	var series RangeSeries
+	if err := expectDelim(dec, '{'); err != nil {
+		return series, err
+	}
+	for dec.More() {
+		key, err := objectKey(dec)
+		if err != nil {
+			return series, err
+		}
+		switch key {
+		case "metric":
+			err = dec.Decode(&series.Metric)
+		case "values":
+			series.Points, err = decodeSamples(dec, opts)
+		default:
+			err = skipValue(dec)
+		}
+		if err != nil {
+			return series, err
+		}
+	}
+	return series, expectDelim(dec, '}')
+}
+
+// decodeSamples decodes a values array of [timestamp, "value"] pairs
+func decodeSamples(dec *json.Decoder, opts RangeStreamOptions) ([]MetricDataPoint, error) {
+	if err := expectDelim(dec, '['); err != nil {
+		return nil, err
+	}
+	sampler := newDownsampler(opts)
+	var raw json.RawMessage
+	for dec.More() {
+		raw = raw[:0]
+		if err := dec.Decode(&raw); err != nil {
+			return nil, fmt.Errorf("failed to parse response: %w", err)
+		}
+		if point, ok := parseSamplePair(raw); ok {
+			sampler.add(point)
+		}
+	}
+	if err := expectDelim(dec, ']'); err != nil {
+		return nil, err
+	}
+	return sampler.points(), nil
+}
+
+// parseSamplePair parses a [1700000000.123,"0.5"] sample without intermediate values
+func parseSamplePair(raw []byte) (MetricDataPoint, bool) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) < 2 || raw[0] != '[' || raw[len(raw)-1] != ']' {
+		return MetricDataPoint{}, false
+	}
+	tsField, valueField, found := bytes.Cut(raw[1:len(raw)-1], []byte(","))
+	if !found {
+		return MetricDataPoint{}, false
+	}
+	ts, err := strconv.ParseFloat(string(bytes.TrimSpace(tsField)), 64)
+	if err != nil {
+		return MetricDataPoint{}, false
+	}
+	valueField = bytes.TrimSpace(valueField)
+	if len(valueField) < 2 || valueField[0] != '"' || valueField[len(valueField)-1] != '"' {
+		return MetricDataPoint{}, false
+	}
+	value, err := quantity.ParseFloat(string(valueField[1 : len(valueField)-1]))
+	if err != nil {
+		return MetricDataPoint{}, false
+	}
+	return MetricDataPoint{Timestamp: time.Unix(int64(ts), 0), Value: value}, true
+}
+
+// downsampler averages samples into time buckets as they are read
+type downsampler struct {
+	start   time.Time
+	width   time.Duration
+	buckets int64
+
+	out    []MetricDataPoint
+	bucket int64
+	sum    float64
+	count  int
+	first  time.Time
+}
+
+// newDownsampler returns a downsampler for the options; without MaxPoints it keeps
+// every sample
+func newDownsampler(opts RangeStreamOptions) *downsampler {
+	d := &downsampler{start: opts.Start, width: opts.bucketWidth(), buckets: int64(opts.MaxPoints), bucket: -1}
+	if d.width > 0 {
+		d.out = make([]MetricDataPoint, 0, opts.MaxPoints)
+	}
+	return d
+}
+
+// add records a sample; samples arrive in timestamp order. Non-finite samples are
+// left out of bucket means.
+func (d *downsampler) add(point MetricDataPoint) {
+	if d.width == 0 {
+		d.out = append(d.out, point)
+		return
+	}
+	if math.IsNaN(point.Value) || math.IsInf(point.Value, 0) {
+		return
+	}
+	// Samples at the range bounds, which Prometheus aligns to whole seconds, fall
+	// into the first and last buckets
+	bucket := int64(point.Timestamp.Sub(d.start) / d.width)
+	bucket = max(0, min(bucket, d.buckets-1))
+	if bucket != d.bucket {
+		d.flush()
+		d.bucket = bucket
+		d.first = point.Timestamp
+	}
+	d.sum += point.Value
+	d.count++
+}
+
+// flush emits the current bucket's mean at the time of its first sample
+func (d *downsampler) flush() {
+	if d.count == 0 {
+		return
+	}
+	d.out = append(d.out, MetricDataPoint{Timestamp: d.first, Value: d.sum / float64(d.count)})
+	d.sum, d.count = 0, 0
+}
+
+// points returns the downsampled series
+func (d *downsampler) points() []MetricDataPoint {
+	d.flush()
+	return d.out
+}
+
+// expectDelim reads a JSON delimiter token
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != want {
+		return fmt.Errorf("failed to parse response: expected %q, got %v", want, tok)
+	}
+	return nil
+}
+
+// objectKey reads the next key of a JSON object
+func objectKey(dec *json.Decoder) (string, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return "", fmt.Errorf("failed to parse response: %w", err)
+	}
+	key, ok := tok.(string)
+	if !ok {
+		return "", fmt.Errorf("failed to parse response: expected object key, got %v", tok)
+	}
+	return key, nil
+}
+
+// skipValue reads past the next JSON value without keeping it
+func skipValue(dec *json.Decoder) error {
+	depth := 0
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return fmt.Errorf("failed to parse response: %w", err)
+		}
+		if delim, ok := tok.(json.Delim); ok {
+			switch delim {
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
+			}
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package integrations
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rangeBody builds a range query response of series with count samples each, step
+// apart from start
+func rangeBody(series int, start time.Time, step time.Duration, count int) string {
+	var b strings.Builder
+	b.WriteString(`{"status":"success","data":{"resultType":"matrix","result":[`)
+	for s := 0; s < series; s++ {
+		if s > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, `{"metric":{"pod":"pod-%d"},"values":[`, s)
+		for i := 0; i < count; i++ {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			fmt.Fprintf(&b, `[%d.123,"%d"]`, start.Add(time.Duration(i)*step).Unix(), i)
+		}
+		b.WriteString(`]}`)
+	}
+	b.WriteString(`]},"warnings":["partial"]}`)
+	return b.String()
+}
+
+func TestStreamRangeResponse(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+
+	t.Run("series in order", func(t *testing.T) {
+		body := `{"status":"success","data":{"resultType":"matrix","result":[` +
+			`{"metric":{"pod":"a"},"values":[[1700000000,"1.5"],[1700000060,"NaN"],[1700000120,"oops"],[1700000180,"2"]]},` +
+			`{"values":[[1700000000,"3"]],"metric":{"pod":"b"}}` +
+			`],"stats":{"timings":{"evalTotalTime":0.1}}}}`
+		var got []RangeSeries
+		err := StreamRangeResponse(strings.NewReader(body), RangeStreamOptions{}, func(series RangeSeries) error {
+			got = append(got, series)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Len(t, got, 2)
+		assert.Equal(t, "a", got[0].Metric["pod"])
+		require.Len(t, got[0].Points, 3, "unparseable samples are skipped")
+		assert.Equal(t, 1.5, got[0].Points[0].Value)
+		assert.True(t, math.IsNaN(got[0].Points[1].Value))
+		assert.Equal(t, time.Unix(1700000180, 0), got[0].Points[2].Timestamp)
+		assert.Equal(t, "b", got[1].Metric["pod"])
+	})
+
+	t.Run("stop after the first series", func(t *testing.T) {
+		// The rest of the response is never read, so a truncated tail does not matter
+		body := rangeBody(3, start, time.Minute, 5)
+		body = body[:len(body)-40]
+		calls := 0
+		err := StreamRangeResponse(strings.NewReader(body), RangeStreamOptions{}, func(RangeSeries) error {
+			calls++
+			return ErrStopStream
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("error status", func(t *testing.T) {
+		body := `{"status":"error","errorType":"bad_data","error":"invalid parameter \"query\""}`
+		err := StreamRangeResponse(strings.NewReader(body), RangeStreamOptions{}, func(RangeSeries) error { return nil })
+		assert.ErrorContains(t, err, "prometheus query failed: bad_data")
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		for _, body := range []string{`[]`, `{"status":"success","data":{"result":{}}}`, `{"status":"success","data":{"result":[{"values":[`} {
+			err := StreamRangeResponse(strings.NewReader(body), RangeStreamOptions{}, func(RangeSeries) error { return nil })
+			assert.ErrorContains(t, err, "failed to parse response", body)
+		}
+	})
+
+	t.Run("downsampling", func(t *testing.T) {
+		// 30 days at a 5 minute step are 8640 samples with values 0..8639
+		end := start.Add(30 * 24 * time.Hour)
+		body := rangeBody(1, start, 5*time.Minute, 8641)
+		var points []MetricDataPoint
+		err := StreamRangeResponse(strings.NewReader(body), RangeStreamOptions{MaxPoints: 720, Start: start, End: end}, func(series RangeSeries) error {
+			points = series.Points
+			return nil
+		})
+		require.NoError(t, err)
+		require.Len(t, points, 720, "the sample at the end of the range joins the last bucket")
+		assert.Equal(t, start, points[0].Timestamp)
+		assert.Equal(t, 5.5, points[0].Value, "each hour averages 12 samples")
+		assert.Equal(t, start.Add(time.Hour), points[1].Timestamp)
+		assert.InDelta(t, (8628.0+8640.0)/2, points[719].Value, 1e-9)
+	})
+}
+
+func TestPrometheusClient_RangeMaxPoints(t *testing.T) {
+	var body string
+	client, server := newTestPrometheusClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/query_range" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	})
+	defer server.Close()
+	ctx := context.Background()
+
+	// A week of 1 minute samples
+	body = rangeBody(2, time.Now().Add(-7*24*time.Hour), time.Minute, 7*24*60)
+	points, err := client.GetNamespaceCPUTrend(ctx, "payments", "7d")
+	require.NoError(t, err)
+	assert.Len(t, points, 7*24*60, "all points are kept by default")
+
+	client.SetRangeMaxPoints(168)
+	points, err = client.GetNamespaceCPUTrend(ctx, "payments", "7d")
+	require.NoError(t, err)
+	assert.LessOrEqual(t, len(points), 168)
+	assert.GreaterOrEqual(t, len(points), 167)
+
+	body = `{"status":"success","data":{"resultType":"matrix","result":[]}}`
+	_, err = client.GetNamespaceCPUTrend(ctx, "payments", "7d")
+	assert.ErrorContains(t, err, "no data returned")
+}
+
+func BenchmarkStreamRangeResponse(b *testing.B) {
+	start := time.Unix(1700000000, 0)
+	body := []byte(rangeBody(20, start, 5*time.Minute, 8640))
+	for _, maxPoints := range []int{0, 720} {
+		b.Run(fmt.Sprintf("max_points=%d", maxPoints), func(b *testing.B) {
+			opts := RangeStreamOptions{MaxPoints: maxPoints, Start: start, End: start.Add(30 * 24 * time.Hour)}
+			b.ReportAllocs()
+			b.SetBytes(int64(len(body)))
+			for i := 0; i < b.N; i++ {
+				err := StreamRangeResponse(bytes.NewReader(body), opts, func(RangeSeries) error { return nil })
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,219 @@
+package integrations
+
+import (
+	"context"
+	"fmt"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Recorded series names for pre-computed anomaly features.
+// Names follow the Prometheus "level:metric:operations" recording rule convention.
+const (
+	RecordedNodeCPUUtilization    = "cluster:node_cpu_utilization:avg_rate5m"
+	RecordedNodeMemoryUtilization = "cluster:node_memory_utilization:ratio"
+	RecordedPodCPUUsage           = "namespace_pod:container_cpu_usage_seconds:sum_rate5m"
+	RecordedPodMemoryUsage        = "namespace_pod:container_memory_working_set_bytes:limit_ratio"
+	RecordedContainerRestarts     = "namespace_pod:kube_pod_container_status_restarts:sum"
+	RecordedContainerRestartDelta = "namespace:kube_pod_container_status_restarts:increase5m"
+)
+
+// Suffixes appended to a recorded series for its 5-minute rolling statistics
+const (
+	recordedMeanSuffix   = ":avg_over_time5m"
+	recordedStdDevSuffix = ":stddev_over_time5m"
+	recordedMinSuffix    = ":min_over_time5m"
+	recordedMaxSuffix    = ":max_over_time5m"
+)
+
+// RecordingRule is a single Prometheus recording rule
+type RecordingRule struct {
+	Record string `json:"record"`
+	Expr   string `json:"expr"`
+}
+
+// RecordingRuleGroup is a named group of recording rules evaluated together
+type RecordingRuleGroup struct {
+	Name     string          `json:"name"`
+	Interval string          `json:"interval,omitempty"`
+	Rules    []RecordingRule `json:"rules"`
+}
+
+// FeatureQueries holds the queries for the directly-queried anomaly features of a metric
+type FeatureQueries struct {
+	Value  string
+	Mean   string
+	StdDev string
+	Min    string
+	Max    string
+
+	// PerPod is true when the series hold one value per pod, like the anomaly.* query
+	// templates, rather than one value for the whole scope
+	PerPod bool
+}
+
+// featureRecordingSources maps each anomaly base metric to its recorded series and
+// the expression that produces it. Each expression is the metric's anomaly.* query
+// template without a selector; pod-level series are grouped by namespace as well as by
+// pod so one rule serves every monitored namespace with the per-pod values the raw
+// queries return.
+var featureRecordingSources = []struct {
+	metric string
+	record string
+	expr   string
+}{
+	{"node_cpu_utilization", RecordedNodeCPUUtilization,
+		`avg(1 - rate(node_cpu_seconds_total{mode="idle"}[5m]))`},
+	{"node_memory_utilization", RecordedNodeMemoryUtilization,
+		`1 - (node_memory_MemAvailable_bytes / node_memory_MemTotal_bytes)`},
+	{"pod_cpu_usage", RecordedPodCPUUsage,
+		`sum(rate(container_cpu_usage_seconds_total{container!=""}[5m])) by (namespace, pod)`},
+	{"pod_memory_usage", RecordedPodMemoryUsage,
+		`sum(container_memory_working_set_bytes{container!=""}) by (namespace, pod) / sum(kube_pod_container_resource_limits{resource="memory"}) by (namespace, pod)`},
+	{"container_restart_count", RecordedContainerRestarts,
+		`sum(kube_pod_container_status_restarts_total) by (namespace, pod)`},
+}
+
+// FeatureRecordingRules returns the recording rule groups that pre-compute the
+// features used by anomaly detection: the base series, their 5-minute rolling
+// statistics, and per-namespace restart deltas.
+func FeatureRecordingRules(interval string) []RecordingRuleGroup {
+	base := RecordingRuleGroup{Name: "coordination-engine.anomaly-features.base", Interval: interval}
+	stats := RecordingRuleGroup{Name: "coordination-engine.anomaly-features.rolling", Interval: interval}
+
+	for _, src := range featureRecordingSources {
+		base.Rules = append(base.Rules, RecordingRule{Record: src.record, Expr: src.expr})
+		stats.Rules = append(stats.Rules,
+			RecordingRule{Record: src.record + recordedMeanSuffix, Expr: fmt.Sprintf("avg_over_time(%s[5m])", src.record)},
+			RecordingRule{Record: src.record + recordedStdDevSuffix, Expr: fmt.Sprintf("stddev_over_time(%s[5m])", src.record)},
+			RecordingRule{Record: src.record + recordedMinSuffix, Expr: fmt.Sprintf("min_over_time(%s[5m])", src.record)},
+			RecordingRule{Record: src.record + recordedMaxSuffix, Expr: fmt.Sprintf("max_over_time(%s[5m])", src.record)},
+		)
+	}
+
+	base.Rules = append(base.Rules, RecordingRule{
+		Record: RecordedContainerRestartDelta,
+		Expr:   `sum by (namespace) (increase(kube_pod_container_status_restarts_total[5m]))`,
+	})
+
+	// Rolling statistics depend on the base series, so they live in a separate group
+	// that is evaluated after the base group.
+	return []RecordingRuleGroup{base, stats}
+}
+
+// GenerateRecordingRulesYAML renders the feature recording rules as a PrometheusRule
+// manifest that can be applied with "oc apply -f".
+func GenerateRecordingRulesYAML(name, namespace, interval string) ([]byte, error) {
+	if name == "" {
+		name = "coordination-engine-anomaly-features"
+	}
+
+	manifest := map[string]interface{}{
+		"apiVersion": "monitoring.coreos.com/v1",
+		"kind":       "PrometheusRule",
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": namespace,
+			"labels": map[string]string{
+				"app.kubernetes.io/name":      "coordination-engine",
+				"app.kubernetes.io/component": "recording-rules",
+			},
+		},
+		"spec": map[string]interface{}{
+			"groups": FeatureRecordingRules(interval),
+		},
+	}
+
+	data, err := yaml.Marshal(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal PrometheusRule: %w", err)
+	}
+	return data, nil
+}
+
+// SetUseRecordingRules switches anomaly feature queries to the pre-computed series
+// produced by FeatureRecordingRules. Only namespace and cluster scoped queries can use
+// recorded series; pod and deployment scopes always query raw metrics.
+func (c *PrometheusClient) SetUseRecordingRules(enabled bool) {
+	if c == nil {
+		return
+	}
+	c.useRecordingRules = enabled
+	c.ClearCache()
+}
+
+// UsesRecordingRules returns true if pre-computed series are queried when possible
+func (c *PrometheusClient) UsesRecordingRules() bool {
+	return c != nil && c.useRecordingRules
+}
+
+// RecordedFeatureQueries returns queries against pre-computed series for a base anomaly
+// metric. ok is false when recording rules are disabled or the scope is too narrow.
+func (c *PrometheusClient) RecordedFeatureQueries(metric, namespace, deployment, pod string) (FeatureQueries, bool) {
+	if !c.UsesRecordingRules() || deployment != "" || pod != "" {
+		return FeatureQueries{}, false
+	}
+
+	for _, src := range featureRecordingSources {
+		if src.metric != metric {
+			continue
+		}
+
+		clusterLevel := src.record == RecordedNodeCPUUtilization || src.record == RecordedNodeMemoryUtilization
+		if !clusterLevel && namespace == "" {
+			// Per-pod series cannot be meaningfully combined into cluster-wide features
+			return FeatureQueries{}, false
+		}
+
+		selector := ""
+		if !clusterLevel {
+			selector = fmt.Sprintf(`{namespace=%q}`, namespace)
+		}
+		series := func(record string) string {
+			return record + selector
+		}
+
+		return FeatureQueries{
+			Value:  series(src.record),
+			Mean:   series(src.record + recordedMeanSuffix),
+			StdDev: series(src.record + recordedStdDevSuffix),
+			Min:    series(src.record + recordedMinSuffix),
+			Max:    series(src.record + recordedMaxSuffix),
+			PerPod: !clusterLevel,
+		}, true
+	}
+
+	return FeatureQueries{}, false
+}
+
+// GetRecordedAnomalyMetricFeatures queries the 9 anomaly features of a metric from
+// pre-computed series. Rolling statistics are read directly from their recorded series
+// instead of being computed with subqueries at request time.
+func (c *PrometheusClient) GetRecordedAnomalyMetricFeatures(ctx context.Context, q FeatureQueries) (*AnomalyMetricFeatures, error) {
+	if !c.IsAvailable() {
+		return nil, fmt.Errorf("prometheus client not available")
+	}
+
+	value, err := c.queryInstant(ctx, q.Value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query recorded series %s: %w", q.Value, err)
+	}
+
+	lag1 := c.QueryWithDefault(ctx, q.Value+" offset 1m", value)
+	pctChange := 0.0
+	if lag1 != 0 {
+		pctChange = (value - lag1) / lag1
+	}
+
+	return &AnomalyMetricFeatures{
+		Value:     value,
+		Mean5m:    c.QueryWithDefault(ctx, q.Mean, value),
+		Std5m:     c.QueryWithDefault(ctx, q.StdDev, 0),
+		Min5m:     c.QueryWithDefault(ctx, q.Min, value),
+		Max5m:     c.QueryWithDefault(ctx, q.Max, value),
+		Lag1:      lag1,
+		Lag5:      c.QueryWithDefault(ctx, q.Value+" offset 5m", value),
+		Diff:      value - lag1,
+		PctChange: pctChange,
+	}, nil
+}
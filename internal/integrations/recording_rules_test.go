@@ -0,0 +1,98 @@
+package integrations
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+)
+
+func TestFeatureRecordingRules(t *testing.T) {
+	groups := FeatureRecordingRules("30s")
+	require.Len(t, groups, 2)
+
+	records := make(map[string]string)
+	for _, g := range groups {
+		assert.Equal(t, "30s", g.Interval)
+		for _, r := range g.Rules {
+			records[r.Record] = r.Expr
+		}
+	}
+
+	assert.Contains(t, records, RecordedPodCPUUsage)
+	assert.Contains(t, records, RecordedContainerRestartDelta)
+	assert.Equal(t, "avg_over_time("+RecordedPodMemoryUsage+"[5m])", records[RecordedPodMemoryUsage+recordedMeanSuffix])
+	assert.Contains(t, records[RecordedContainerRestartDelta], "by (namespace)")
+	assert.Contains(t, records[RecordedPodCPUUsage], "by (namespace, pod)")
+}
+
+func TestGenerateRecordingRulesYAML(t *testing.T) {
+	data, err := GenerateRecordingRulesYAML("", "openshift-monitoring", "1m")
+	require.NoError(t, err)
+
+	var manifest map[string]interface{}
+	require.NoError(t, yaml.Unmarshal(data, &manifest))
+	assert.Equal(t, "monitoring.coreos.com/v1", manifest["apiVersion"])
+	assert.Equal(t, "PrometheusRule", manifest["kind"])
+
+	metadata := manifest["metadata"].(map[string]interface{})
+	assert.Equal(t, "coordination-engine-anomaly-features", metadata["name"])
+	assert.Equal(t, "openshift-monitoring", metadata["namespace"])
+	assert.True(t, strings.Contains(string(data), "record: "+RecordedNodeCPUUtilization))
+}
+
+func TestPrometheusClient_RecordedFeatureQueries(t *testing.T) {
+	client := &PrometheusClient{baseURL: "http://prometheus"}
+
+	_, ok := client.RecordedFeatureQueries("pod_cpu_usage", "default", "", "")
+	assert.False(t, ok, "disabled by default")
+
+	client.SetUseRecordingRules(true)
+
+	q, ok := client.RecordedFeatureQueries("pod_cpu_usage", "default", "", "")
+	require.True(t, ok)
+	assert.Equal(t, RecordedPodCPUUsage+`{namespace="default"}`, q.Value)
+	assert.Equal(t, RecordedPodCPUUsage+recordedStdDevSuffix+`{namespace="default"}`, q.StdDev)
+	assert.True(t, q.PerPod)
+
+	q, ok = client.RecordedFeatureQueries("node_cpu_utilization", "default", "", "")
+	require.True(t, ok)
+	assert.Equal(t, RecordedNodeCPUUtilization, q.Value, "cluster-level series take no namespace selector")
+	assert.False(t, q.PerPod)
+
+	_, ok = client.RecordedFeatureQueries("pod_cpu_usage", "default", "api", "")
+	assert.False(t, ok, "deployment scope uses raw queries")
+
+	_, ok = client.RecordedFeatureQueries("pod_cpu_usage", "", "", "")
+	assert.False(t, ok, "cluster scope cannot use per-namespace series")
+
+	_, ok = client.RecordedFeatureQueries("unknown_metric", "default", "", "")
+	assert.False(t, ok)
+}
+
+func TestPrometheusClient_GetRecordedAnomalyMetricFeatures(t *testing.T) {
+	var queries []string
+	client, server := newTestPrometheusClient(t, func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query().Get("query"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(mockPrometheusResponse(0.4)))
+	})
+	defer server.Close()
+	client.SetUseRecordingRules(true)
+
+	q, ok := client.RecordedFeatureQueries("pod_memory_usage", "default", "", "")
+	require.True(t, ok)
+
+	features, err := client.GetRecordedAnomalyMetricFeatures(context.Background(), q)
+	require.NoError(t, err)
+	assert.Len(t, features.ToSlice(), 9)
+	assert.Equal(t, 0.4, features.Value)
+
+	for _, query := range queries {
+		assert.NotContains(t, query, "[5m:]", "no subqueries when using recorded series")
+	}
+}
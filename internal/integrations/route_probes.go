@@ -0,0 +1,258 @@
+package integrations
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/promql"
+)
+
+// Kinds of externally exposed targets
+const (
+	RouteKindRoute   = "Route"
+	RouteKindIngress = "Ingress"
+)
+
+var routeGVR = schema.GroupVersionResource{
+	Group:    "route.openshift.io",
+	Version:  "v1",
+	Resource: "routes",
+}
+
+// RouteTarget is a host exposed outside the cluster by a Route or an Ingress
+type RouteTarget struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	Host string `json:"host"`
+}
+
+// RouteAvailability is the external availability of a route measured by
+// blackbox-exporter probes of its host
+type RouteAvailability struct {
+	RouteTarget
+
+	// Availability is the fraction of successful probes within the window (0-1)
+	Availability float64 `json:"availability"`
+
+	// LatencySeconds is the mean probe duration within the window
+	LatencySeconds float64 `json:"latency_seconds"`
+}
+
+// RouteLister finds the Routes and Ingresses exposing a namespace or a deployment
+type RouteLister struct {
+	clientset     kubernetes.Interface
+	dynamicClient dynamic.Interface
+	log           *logrus.Logger
+}
+
+// NewRouteLister creates a route lister. Without a dynamic client only Ingresses are
+// listed.
+func NewRouteLister(clientset kubernetes.Interface, dynamicClient dynamic.Interface, log *logrus.Logger) *RouteLister {
+	return &RouteLister{
+		clientset:     clientset,
+		dynamicClient: dynamicClient,
+		log:           log,
+	}
+}
+
+// Targets returns the hosts of the Routes and Ingresses of a namespace, sorted by host.
+// When deployment is set, only those sending traffic to a Service selecting the
+// deployment's pods are returned. Clusters without the Route API have Ingresses only.
+func (l *RouteLister) Targets(ctx context.Context, namespace, deployment string) ([]RouteTarget, error) {
+	var services map[string]bool
+	if deployment != "" {
+		var err error
+		if services, err = l.deploymentServices(ctx, namespace, deployment); err != nil {
+			return nil, err
+		}
+		if len(services) == 0 {
+			return nil, nil
+		}
+	}
+	exposes := func(service string) bool { return services == nil || services[service] }
+
+	targets := make(map[string]RouteTarget)
+	add := func(kind, name, host string) {
+		if _, seen := targets[host]; host != "" && !seen {
+			targets[host] = RouteTarget{Kind: kind, Name: name, Host: host}
+		}
+	}
+
+	if l.dynamicClient != nil {
+		routes, err := l.dynamicClient.Resource(routeGVR).Namespace(namespace).List(ctx, metav1.ListOptions{})
+		switch {
+		case apierrors.IsNotFound(err):
+			l.log.Debug("Route API not available, probing Ingresses only")
+		case err != nil:
+			return nil, fmt.Errorf("failed to list routes in namespace %s: %w", namespace, err)
+		default:
+			for _, route := range routes.Items {
+				host, _, _ := unstructured.NestedString(route.Object, "spec", "host")
+				for _, service := range routeServices(&route) {
+					if exposes(service) {
+						add(RouteKindRoute, route.GetName(), host)
+						break
+					}
+				}
+			}
+		}
+	}
+
+	ingresses, err := l.clientset.NetworkingV1().Ingresses(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ingresses in namespace %s: %w", namespace, err)
+	}
+	for _, ingress := range ingresses.Items {
+		defaultService := ""
+		if backend := ingress.Spec.DefaultBackend; backend != nil && backend.Service != nil {
+			defaultService = backend.Service.Name
+		}
+		for _, rule := range ingress.Spec.Rules {
+			matched := services == nil || (defaultService != "" && exposes(defaultService))
+			if rule.HTTP != nil {
+				for _, path := range rule.HTTP.Paths {
+					if path.Backend.Service != nil && exposes(path.Backend.Service.Name) {
+						matched = true
+					}
+				}
+			}
+			if matched {
+				add(RouteKindIngress, ingress.Name, rule.Host)
+			}
+		}
+	}
+
+	result := make([]RouteTarget, 0, len(targets))
+	for _, target := range targets {
+		result = append(result, target)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Host < result[j].Host })
+	return result, nil
+}
+
+// deploymentServices returns the names of the Services selecting a deployment's pods
+func (l *RouteLister) deploymentServices(ctx context.Context, namespace, deployment string) (map[string]bool, error) {
+	dep, err := l.clientset.AppsV1().Deployments(namespace).Get(ctx, deployment, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deployment %s/%s: %w", namespace, deployment, err)
+	}
+	services, err := l.clientset.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list services in namespace %s: %w", namespace, err)
+	}
+
+	podLabels := labels.Set(dep.Spec.Template.Labels)
+	names := make(map[string]bool)
+	for _, service := range services.Items {
+		if len(service.Spec.Selector) > 0 && labels.SelectorFromSet(service.Spec.Selector).Matches(podLabels) {
+			names[service.Name] = true
+		}
+	}
+	return names, nil
+}
+
+// routeServices returns the Services a Route sends traffic to
+func routeServices(route *unstructured.Unstructured) []string {
+	var services []string
+	if name, _, _ := unstructured.NestedString(route.Object, "spec", "to", "name"); name != "" {
+		services = append(services, name)
+	}
+	backends, _, _ := unstructured.NestedSlice(route.Object, "spec", "alternateBackends")
+	for _, backend := range backends {
+		if backend, ok := backend.(map[string]interface{}); ok {
+			if name, ok := backend["name"].(string); ok && name != "" {
+				services = append(services, name)
+			}
+		}
+	}
+	return services
+}
+
+// RouteProbeMatcher returns the PromQL label matcher selecting the blackbox probes of
+// the targets' hosts, whose instance label is the probed URL or host, or "" without
+// targets
+func RouteProbeMatcher(targets []RouteTarget) string {
+	if len(targets) == 0 {
+		return ""
+	}
+	hosts := make([]string, len(targets))
+	for i, target := range targets {
+		hosts[i] = regexp.QuoteMeta(target.Host)
+	}
+	return fmt.Sprintf("instance=~%q", "([a-z]+://)?("+strings.Join(hosts, "|")+")(:[0-9]+)?(/.*)?")
+}
+
+// GetRouteAvailability queries the blackbox probes of the targets within window (e.g.
+// "5m"). Targets without probes are left out; a host probed through several URLs
+// reports its least available and slowest one.
+func (c *PrometheusClient) GetRouteAvailability(ctx context.Context, targets []RouteTarget, window string) ([]RouteAvailability, error) {
+	if !c.IsAvailable() {
+		return nil, fmt.Errorf("prometheus client not available")
+	}
+	if len(targets) == 0 {
+		return nil, nil
+	}
+
+	byHost := make(map[string]*RouteAvailability, len(targets))
+	for _, target := range targets {
+		byHost[target.Host] = &RouteAvailability{RouteTarget: target, Availability: math.NaN()}
+	}
+
+	params := promql.Params{Selector: RouteProbeMatcher(targets), Window: window}
+	availability, err := c.QueryVector(ctx, c.Queries().MustRender("route.availability_by_target", params))
+	if err != nil {
+		return nil, err
+	}
+	for _, sample := range availability {
+		if result := byHost[probeHost(sample.Labels["instance"])]; result != nil {
+			if math.IsNaN(result.Availability) || sample.Value < result.Availability {
+				result.Availability = sample.Value
+			}
+		}
+	}
+
+	latency, err := c.QueryVector(ctx, c.Queries().MustRender("route.latency_by_target", params))
+	if err != nil {
+		return nil, err
+	}
+	for _, sample := range latency {
+		if result := byHost[probeHost(sample.Labels["instance"])]; result != nil {
+			result.LatencySeconds = math.Max(result.LatencySeconds, sample.Value)
+		}
+	}
+
+	var results []RouteAvailability
+	for _, target := range targets {
+		if result := byHost[target.Host]; !math.IsNaN(result.Availability) {
+			results = append(results, *result)
+		}
+	}
+	return results, nil
+}
+
+// probeHost returns the host of a blackbox probe instance, a URL or a host with an
+// optional port
+func probeHost(instance string) string {
+	if !strings.Contains(instance, "://") {
+		instance = "//" + instance
+	}
+	u, err := url.Parse(instance)
+	if err != nil {
+		return instance
+	}
+	return u.Hostname()
+}
@@ -0,0 +1,123 @@
+package integrations
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func testRoute(name, host, service string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "route.openshift.io/v1",
+		"kind":       "Route",
+		"metadata":   map[string]interface{}{"name": name, "namespace": "shop"},
+		"spec": map[string]interface{}{
+			"host": host,
+			"to":   map[string]interface{}{"kind": "Service", "name": service},
+		},
+	}}
+}
+
+func TestRouteLister_Targets(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "frontend", Namespace: "shop"},
+			Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "frontend", "tier": "web"}},
+			}},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "frontend", Namespace: "shop"},
+			Spec:       corev1.ServiceSpec{Selector: map[string]string{"app": "frontend"}},
+		},
+		&networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Name: "legacy", Namespace: "shop"},
+			Spec: networkingv1.IngressSpec{Rules: []networkingv1.IngressRule{{
+				Host: "legacy.example.com",
+				IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{
+					Paths: []networkingv1.HTTPIngressPath{{Backend: networkingv1.IngressBackend{
+						Service: &networkingv1.IngressServiceBackend{Name: "frontend"},
+					}}},
+				}},
+			}}},
+		},
+	)
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(),
+		testRoute("shop", "shop.apps.example.com", "frontend"),
+		testRoute("admin", "admin.apps.example.com", "admin"),
+	)
+
+	lister := NewRouteLister(clientset, dynamicClient, logrus.New())
+
+	targets, err := lister.Targets(context.Background(), "shop", "")
+	require.NoError(t, err)
+	assert.Equal(t, []RouteTarget{
+		{Kind: RouteKindRoute, Name: "admin", Host: "admin.apps.example.com"},
+		{Kind: RouteKindIngress, Name: "legacy", Host: "legacy.example.com"},
+		{Kind: RouteKindRoute, Name: "shop", Host: "shop.apps.example.com"},
+	}, targets)
+
+	targets, err = lister.Targets(context.Background(), "shop", "frontend")
+	require.NoError(t, err)
+	assert.Equal(t, []RouteTarget{
+		{Kind: RouteKindIngress, Name: "legacy", Host: "legacy.example.com"},
+		{Kind: RouteKindRoute, Name: "shop", Host: "shop.apps.example.com"},
+	}, targets)
+
+	_, err = lister.Targets(context.Background(), "shop", "missing")
+	assert.Error(t, err)
+}
+
+func TestRouteProbeMatcher(t *testing.T) {
+	assert.Empty(t, RouteProbeMatcher(nil))
+	assert.Equal(t, `instance=~"([a-z]+://)?(shop\\.example\\.com|api\\.example\\.com)(:[0-9]+)?(/.*)?"`,
+		RouteProbeMatcher([]RouteTarget{{Host: "shop.example.com"}, {Host: "api.example.com"}}))
+}
+
+func TestPrometheusClient_GetRouteAvailability(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasPrefix(r.URL.Query().Get("query"), "avg by (instance)"):
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[
+				{"metric":{"instance":"https://shop.example.com/health"},"value":[1700000000,"0.4"]},
+				{"metric":{"instance":"http://shop.example.com"},"value":[1700000000,"1"]},
+				{"metric":{"instance":"other.example.com:443"},"value":[1700000000,"1"]}]}}`))
+		default:
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[
+				{"metric":{"instance":"https://shop.example.com/health"},"value":[1700000000,"0.25"]},
+				{"metric":{"instance":"http://shop.example.com"},"value":[1700000000,"0.5"]}]}}`))
+		}
+	}))
+	defer server.Close()
+
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	client := NewPrometheusClient(server.URL, 5*time.Second, log)
+
+	results, err := client.GetRouteAvailability(context.Background(), []RouteTarget{
+		{Kind: RouteKindRoute, Name: "shop", Host: "shop.example.com"},
+		{Kind: RouteKindRoute, Name: "unprobed", Host: "unprobed.example.com"},
+	}, "5m")
+	require.NoError(t, err)
+	assert.Equal(t, []RouteAvailability{{
+		RouteTarget:    RouteTarget{Kind: RouteKindRoute, Name: "shop", Host: "shop.example.com"},
+		Availability:   0.4,
+		LatencySeconds: 0.5,
+	}}, results)
+}
@@ -0,0 +1,327 @@
+package integrations
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/promql"
+)
+
+// Reasons the scheduler gives for not placing a pod, grouped by the remediation they call for
+const (
+	UnschedulableInsufficientResources = "insufficient_resources"
+	UnschedulableUntoleratedTaints     = "untolerated_taints"
+	UnschedulableNodeAffinity          = "node_affinity"
+	UnschedulableCordonedNodes         = "cordoned_nodes"
+	UnschedulableVolumeBinding         = "volume_binding"
+	UnschedulableOther                 = "other"
+)
+
+// failedSchedulingReason is the reason of the events the scheduler records for pods it
+// could not place
+const failedSchedulingReason = "FailedScheduling"
+
+// SchedulerPressureOptions are the thresholds pressure must stay above to be sustained
+type SchedulerPressureOptions struct {
+	// PendingThreshold is the fewest pending pods over Window that counts as pressure
+	PendingThreshold int
+
+	// UnschedulableThreshold is the fewest unschedulable pods over Window that counts
+	// as pressure
+	UnschedulableThreshold int
+
+	// Window is how long both counts must stay at their thresholds
+	Window time.Duration
+}
+
+// UnschedulableReason is how often a reason kept pods from being scheduled
+type UnschedulableReason struct {
+	Reason string `json:"reason"`
+
+	// Pods is the number of pods the reason applied to
+	Pods int `json:"pods"`
+
+	// Nodes is the number of nodes the reason ruled out, summed over those pods
+	Nodes int `json:"nodes"`
+
+	// Detail is the scheduler's most frequent wording of the reason, such as
+	// "Insufficient cpu"
+	Detail string `json:"detail"`
+}
+
+// SchedulerPressure is the scheduler queue over a window and, when the pressure is
+// sustained, why pods could not be scheduled
+type SchedulerPressure struct {
+	// PendingPods and UnschedulablePods are the fewest pods queued over the window
+	PendingPods       int    `json:"pending_pods"`
+	UnschedulablePods int    `json:"unschedulable_pods"`
+	Window            string `json:"window"`
+	Sustained         bool   `json:"sustained"`
+
+	// FailedPods is the number of pods with FailedScheduling events within the window
+	FailedPods int `json:"failed_pods,omitempty"`
+
+	// DominantReason is the reason applying to the most pods, unset without
+	// FailedScheduling events
+	DominantReason string                `json:"dominant_reason,omitempty"`
+	Reasons        []UnschedulableReason `json:"reasons,omitempty"`
+
+	// Actions are the remediations for the dominant reason
+	Actions []string `json:"recommended_actions,omitempty"`
+}
+
+// Dominant returns the breakdown of the dominant reason, nil when there is none
+func (p *SchedulerPressure) Dominant() *UnschedulableReason {
+	for i := range p.Reasons {
+		if p.Reasons[i].Reason == p.DominantReason {
+			return &p.Reasons[i]
+		}
+	}
+	return nil
+}
+
+// schedulerPressureActions are the remediations for each unschedulable reason
+var schedulerPressureActions = map[string][]string{
+	UnschedulableInsufficientResources: {"scale_out_nodes", "review_resource_requests", "review_priority_classes"},
+	UnschedulableUntoleratedTaints:     {"review_node_taints", "add_pod_tolerations"},
+	UnschedulableNodeAffinity:          {"review_node_selectors", "label_nodes"},
+	UnschedulableCordonedNodes:         {"uncordon_nodes", "scale_out_nodes"},
+	UnschedulableVolumeBinding:         {"review_persistent_volume_claims", "review_storage_topology"},
+	UnschedulableOther:                 {"investigate_scheduling_failures"},
+}
+
+// SchedulerPressureActions returns the remediations for an unschedulable reason
+func SchedulerPressureActions(reason string) []string {
+	if actions, ok := schedulerPressureActions[reason]; ok {
+		return append([]string(nil), actions...)
+	}
+	return append([]string(nil), schedulerPressureActions[UnschedulableOther]...)
+}
+
+// SchedulerPressureAnalyzer detects sustained scheduler queue growth from Prometheus and
+// explains it from the scheduler's FailedScheduling events
+type SchedulerPressureAnalyzer struct {
+	prometheus *PrometheusClient
+	clientset  kubernetes.Interface
+	opts       SchedulerPressureOptions
+	log        *logrus.Logger
+}
+
+// NewSchedulerPressureAnalyzer creates a scheduler pressure analyzer. Without a clientset
+// pressure is detected but not explained.
+func NewSchedulerPressureAnalyzer(prometheus *PrometheusClient, clientset kubernetes.Interface, opts SchedulerPressureOptions, log *logrus.Logger) *SchedulerPressureAnalyzer {
+	return &SchedulerPressureAnalyzer{
+		prometheus: prometheus,
+		clientset:  clientset,
+		opts:       opts,
+		log:        log,
+	}
+}
+
+// Analyze measures the scheduler queue over the window. Events are only read when the
+// pressure is sustained; failing to read them leaves the pressure unexplained.
+func (a *SchedulerPressureAnalyzer) Analyze(ctx context.Context) (*SchedulerPressure, error) {
+	if a.prometheus == nil || !a.prometheus.IsAvailable() {
+		return nil, fmt.Errorf("prometheus client not available")
+	}
+
+	window := formatDurationForPromQL(a.opts.Window)
+	queries := a.prometheus.Queries()
+	pending, err := a.prometheus.Query(ctx, queries.MustRender("scheduler.pending_pods_min", promql.Params{Window: window}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pending pods: %w", err)
+	}
+	// The unschedulable queue is absent while empty
+	unschedulable := a.prometheus.QueryWithDefault(ctx, queries.MustRender("scheduler.unschedulable_pods_min", promql.Params{Window: window}), 0)
+
+	pressure := &SchedulerPressure{
+		PendingPods:       int(pending),
+		UnschedulablePods: int(unschedulable),
+		Window:            window,
+	}
+	pressure.Sustained = pressure.PendingPods >= a.opts.PendingThreshold &&
+		pressure.UnschedulablePods >= a.opts.UnschedulableThreshold
+	if !pressure.Sustained || a.clientset == nil {
+		return pressure, nil
+	}
+
+	messages, err := a.failedSchedulingMessages(ctx)
+	if err != nil {
+		a.log.WithError(err).Warn("Failed to list FailedScheduling events")
+		return pressure, nil
+	}
+	pressure.FailedPods = len(messages)
+	pressure.Reasons = summarizeUnschedulableReasons(messages)
+	if len(pressure.Reasons) > 0 {
+		pressure.DominantReason = pressure.Reasons[0].Reason
+		pressure.Actions = SchedulerPressureActions(pressure.DominantReason)
+	}
+	return pressure, nil
+}
+
+// failedSchedulingMessages returns the latest FailedScheduling message of each pod seen
+// within the window
+func (a *SchedulerPressureAnalyzer) failedSchedulingMessages(ctx context.Context) ([]string, error) {
+	events, err := a.clientset.CoreV1().Events("").List(ctx, metav1.ListOptions{
+		FieldSelector: "reason=" + failedSchedulingReason,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	since := time.Now().Add(-a.opts.Window)
+	latest := make(map[string]corev1.Event)
+	for _, event := range events.Items {
+		if event.Reason != failedSchedulingReason || event.InvolvedObject.Kind != "Pod" {
+			continue
+		}
+		seen := eventTime(event)
+		if seen.Before(since) {
+			continue
+		}
+		key := event.InvolvedObject.Namespace + "/" + event.InvolvedObject.Name
+		if previous, ok := latest[key]; !ok || eventTime(previous).Before(seen) {
+			latest[key] = event
+		}
+	}
+
+	keys := make([]string, 0, len(latest))
+	for key := range latest {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	messages := make([]string, 0, len(keys))
+	for _, key := range keys {
+		messages = append(messages, latest[key].Message)
+	}
+	return messages, nil
+}
+
+// eventTime is when an event was last seen
+func eventTime(event corev1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case event.Series != nil && !event.Series.LastObservedTime.IsZero():
+		return event.Series.LastObservedTime.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	}
+	return event.CreationTimestamp.Time
+}
+
+// unschedulableFit is one reason of a FailedScheduling message and the nodes it ruled out
+type unschedulableFit struct {
+	reason string
+	nodes  int
+	detail string
+}
+
+// parseFailedScheduling splits a FailedScheduling message such as "0/6 nodes are
+// available: 3 Insufficient cpu, 2 node(s) had untolerated taint {a: b}. preemption: ..."
+// into its reasons. Messages without per-node counts are a single reason.
+func parseFailedScheduling(message string) []unschedulableFit {
+	summary := message
+	if i := strings.Index(summary, ". preemption:"); i >= 0 {
+		summary = summary[:i]
+	}
+	if i := strings.Index(summary, "nodes are available:"); i >= 0 {
+		summary = summary[i+len("nodes are available:"):]
+	}
+	summary = strings.TrimSuffix(strings.TrimSpace(summary), ".")
+
+	var fits []unschedulableFit
+	for _, part := range strings.Split(summary, ", ") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		nodes := 0
+		if count, rest, ok := strings.Cut(part, " "); ok {
+			if n, err := strconv.Atoi(count); err == nil {
+				nodes, part = n, rest
+			}
+		}
+		fits = append(fits, unschedulableFit{reason: classifyUnschedulable(part), nodes: nodes, detail: part})
+	}
+	return fits
+}
+
+// classifyUnschedulable maps the scheduler's wording of a reason to an unschedulable
+// reason. Volume binding is checked first since its messages also mention node affinity.
+func classifyUnschedulable(detail string) string {
+	lower := strings.ToLower(detail)
+	switch {
+	case strings.Contains(lower, "volume") || strings.Contains(lower, "persistentvolumeclaim"):
+		return UnschedulableVolumeBinding
+	case strings.Contains(lower, "insufficient") || strings.Contains(lower, "too many pods"):
+		return UnschedulableInsufficientResources
+	case strings.Contains(lower, "taint"):
+		return UnschedulableUntoleratedTaints
+	case strings.Contains(lower, "affinity") || strings.Contains(lower, "selector"):
+		return UnschedulableNodeAffinity
+	case strings.Contains(lower, "unschedulable"):
+		return UnschedulableCordonedNodes
+	}
+	return UnschedulableOther
+}
+
+// summarizeUnschedulableReasons counts the pods and nodes of each reason across
+// messages, most pods first
+func summarizeUnschedulableReasons(messages []string) []UnschedulableReason {
+	byReason := make(map[string]*UnschedulableReason)
+	details := make(map[string]map[string]int)
+	for _, message := range messages {
+		counted := make(map[string]bool)
+		for _, fit := range parseFailedScheduling(message) {
+			summary, ok := byReason[fit.reason]
+			if !ok {
+				summary = &UnschedulableReason{Reason: fit.reason}
+				byReason[fit.reason] = summary
+				details[fit.reason] = make(map[string]int)
+			}
+			if !counted[fit.reason] {
+				summary.Pods++
+				counted[fit.reason] = true
+			}
+			summary.Nodes += fit.nodes
+			details[fit.reason][fit.detail]++
+		}
+	}
+
+	reasons := make([]UnschedulableReason, 0, len(byReason))
+	for reason, summary := range byReason {
+		summary.Detail = mostFrequent(details[reason])
+		reasons = append(reasons, *summary)
+	}
+	sort.Slice(reasons, func(i, j int) bool {
+		if reasons[i].Pods != reasons[j].Pods {
+			return reasons[i].Pods > reasons[j].Pods
+		}
+		if reasons[i].Nodes != reasons[j].Nodes {
+			return reasons[i].Nodes > reasons[j].Nodes
+		}
+		return reasons[i].Reason < reasons[j].Reason
+	})
+	return reasons
+}
+
+// mostFrequent returns the most counted key, the smallest one on ties
+func mostFrequent(counts map[string]int) string {
+	best, bestCount := "", 0
+	for key, count := range counts {
+		if count > bestCount || (count == bestCount && key < best) {
+			best, bestCount = key, count
+		}
+	}
+	return best
+}
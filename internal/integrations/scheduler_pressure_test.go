@@ -0,0 +1,111 @@
+package integrations
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func failedScheduling(name, message string, seen time.Time) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: name + "." + seen.Format("150405"), Namespace: "shop"},
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Namespace: "shop", Name: name},
+		Reason:         "FailedScheduling",
+		Message:        message,
+		LastTimestamp:  metav1.NewTime(seen),
+	}
+}
+
+// schedulerServer serves pending and unschedulable queue minimums
+func schedulerServer(t *testing.T, pending, unschedulable string) *PrometheusClient {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		value := pending
+		if strings.Contains(r.URL.Query().Get("query"), "unschedulable") {
+			value = unschedulable
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1700000000,"` + value + `"]}]}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	return NewPrometheusClient(server.URL, 5*time.Second, log)
+}
+
+func TestParseFailedScheduling(t *testing.T) {
+	fits := parseFailedScheduling("0/6 nodes are available: 3 Insufficient cpu, 2 node(s) had untolerated taint {node-role.kubernetes.io/master: }, 1 node(s) were unschedulable. preemption: 0/6 nodes are available: 3 No preemption victims found for incoming pod, 3 Preemption is not helpful for scheduling.")
+	require.Len(t, fits, 3)
+	assert.Equal(t, unschedulableFit{reason: UnschedulableInsufficientResources, nodes: 3, detail: "Insufficient cpu"}, fits[0])
+	assert.Equal(t, UnschedulableUntoleratedTaints, fits[1].reason)
+	assert.Equal(t, 2, fits[1].nodes)
+	assert.Equal(t, unschedulableFit{reason: UnschedulableCordonedNodes, nodes: 1, detail: "node(s) were unschedulable"}, fits[2])
+
+	fits = parseFailedScheduling("0/3 nodes are available: 3 node(s) had volume node affinity conflict.")
+	require.Len(t, fits, 1)
+	assert.Equal(t, UnschedulableVolumeBinding, fits[0].reason, "volume conflicts are not node affinity")
+
+	fits = parseFailedScheduling("pod has unbound immediate PersistentVolumeClaims")
+	require.Len(t, fits, 1)
+	assert.Equal(t, unschedulableFit{reason: UnschedulableVolumeBinding, detail: "pod has unbound immediate PersistentVolumeClaims"}, fits[0])
+
+	assert.Equal(t, UnschedulableNodeAffinity, classifyUnschedulable("node(s) didn't match Pod's node affinity/selector"))
+	assert.Equal(t, UnschedulableOther, classifyUnschedulable("node(s) didn't have free ports for the requested pod ports"))
+}
+
+func TestSchedulerPressureAnalyzer_Analyze(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	now := time.Now()
+	clientset := fake.NewSimpleClientset(
+		// web-1 was first tainted out, then ran short of cpu; only its latest event counts
+		failedScheduling("web-1", "0/4 nodes are available: 4 node(s) had untolerated taint {dedicated: infra}.", now.Add(-10*time.Minute)),
+		failedScheduling("web-1", "0/4 nodes are available: 3 Insufficient cpu, 1 node(s) were unschedulable.", now.Add(-time.Minute)),
+		failedScheduling("web-2", "0/4 nodes are available: 4 Insufficient memory.", now.Add(-2*time.Minute)),
+		failedScheduling("db-0", "0/4 nodes are available: 4 node(s) had untolerated taint {dedicated: infra}.", now.Add(-3*time.Minute)),
+		// Outside the window
+		failedScheduling("old", "0/4 nodes are available: 4 node(s) had untolerated taint {dedicated: infra}.", now.Add(-time.Hour)),
+	)
+	opts := SchedulerPressureOptions{PendingThreshold: 10, UnschedulableThreshold: 5, Window: 15 * time.Minute}
+
+	pressure, err := NewSchedulerPressureAnalyzer(schedulerServer(t, "25", "8"), clientset, opts, log).Analyze(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 25, pressure.PendingPods)
+	assert.Equal(t, 8, pressure.UnschedulablePods)
+	assert.Equal(t, "15m", pressure.Window)
+	assert.True(t, pressure.Sustained)
+	assert.Equal(t, 3, pressure.FailedPods)
+	assert.Equal(t, UnschedulableInsufficientResources, pressure.DominantReason)
+	assert.Equal(t, []string{"scale_out_nodes", "review_resource_requests", "review_priority_classes"}, pressure.Actions)
+	require.Len(t, pressure.Reasons, 3)
+	assert.Equal(t, UnschedulableReason{Reason: UnschedulableInsufficientResources, Pods: 2, Nodes: 7, Detail: "Insufficient cpu"}, pressure.Reasons[0])
+	assert.Equal(t, UnschedulableReason{Reason: UnschedulableUntoleratedTaints, Pods: 1, Nodes: 4, Detail: "node(s) had untolerated taint {dedicated: infra}"}, pressure.Reasons[1])
+	assert.Equal(t, UnschedulableCordonedNodes, pressure.Reasons[2].Reason)
+	assert.Equal(t, &pressure.Reasons[0], pressure.Dominant())
+
+	// A queue that drained during the window is not sustained and events are not read
+	pressure, err = NewSchedulerPressureAnalyzer(schedulerServer(t, "25", "2"), clientset, opts, log).Analyze(context.Background())
+	require.NoError(t, err)
+	assert.False(t, pressure.Sustained)
+	assert.Empty(t, pressure.Reasons)
+	assert.Nil(t, pressure.Dominant())
+
+	_, err = NewSchedulerPressureAnalyzer(nil, clientset, opts, log).Analyze(context.Background())
+	assert.ErrorContains(t, err, "prometheus client not available")
+}
+
+func TestSchedulerPressureActions(t *testing.T) {
+	assert.Equal(t, []string{"review_node_taints", "add_pod_tolerations"}, SchedulerPressureActions(UnschedulableUntoleratedTaints))
+	assert.Equal(t, []string{"investigate_scheduling_failures"}, SchedulerPressureActions("unknown"))
+}
@@ -0,0 +1,83 @@
+package integrations
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+)
+
+type snapshotKey struct{}
+
+// Snapshot pins every instant query of one analysis to a single evaluation timestamp,
+// so the features computed for it describe the same instant. While a snapshot is in
+// the context, instant queries send Prometheus the snapshot's time parameter and
+// results are memoized on the snapshot instead of the client's shared cache, whose
+// values may have been evaluated up to one TTL earlier.
+//
+// All methods are safe to call on a nil *Snapshot.
+type Snapshot struct {
+	at     time.Time
+	mu     sync.Mutex
+	values map[string]float64
+}
+
+// NewSnapshot creates a snapshot evaluated at at, truncated to whole seconds. A zero
+// time means now.
+func NewSnapshot(at time.Time) *Snapshot {
+	if at.IsZero() {
+		at = time.Now()
+	}
+	return &Snapshot{at: at.Truncate(time.Second), values: make(map[string]float64)}
+}
+
+// WithSnapshot returns a context carrying the snapshot
+func WithSnapshot(ctx context.Context, s *Snapshot) context.Context {
+	if s == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, snapshotKey{}, s)
+}
+
+// SnapshotFromContext returns the snapshot carried by ctx, or nil
+func SnapshotFromContext(ctx context.Context) *Snapshot {
+	if ctx == nil {
+		return nil
+	}
+	s, _ := ctx.Value(snapshotKey{}).(*Snapshot)
+	return s
+}
+
+// At returns the evaluation timestamp, or the zero time for a nil snapshot
+func (s *Snapshot) At() time.Time {
+	if s == nil {
+		return time.Time{}
+	}
+	return s.at
+}
+
+// timeParam formats the evaluation timestamp for the Prometheus time parameter
+func (s *Snapshot) timeParam() string {
+	return strconv.FormatInt(s.at.Unix(), 10)
+}
+
+// get returns a value memoized on the snapshot
+func (s *Snapshot) get(key string) (float64, bool) {
+	if s == nil {
+		return 0, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	value, ok := s.values[key]
+	return value, ok
+}
+
+// set memoizes a value on the snapshot
+func (s *Snapshot) set(key string, value float64) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[key] = value
+}
@@ -0,0 +1,86 @@
+package integrations
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshot(t *testing.T) {
+	var times []string
+	calls := 0
+	client, server := newTestPrometheusClient(t, func(w http.ResponseWriter, r *http.Request) {
+		times = append(times, r.URL.Query().Get("time"))
+		calls++
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(mockPrometheusResponse(float64(calls) / 10)))
+	})
+	defer server.Close()
+
+	// Warm the shared cache without a snapshot
+	cached, err := client.GetCPURollingMean(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "", times[0], "queries without a snapshot are evaluated now")
+
+	at := time.Date(2026, 3, 1, 12, 0, 0, 500, time.UTC)
+	snapshot := NewSnapshot(at)
+	assert.Equal(t, at.Truncate(time.Second), snapshot.At())
+	ctx := WithSnapshot(context.Background(), snapshot)
+	assert.Same(t, snapshot, SnapshotFromContext(ctx))
+
+	value, err := client.GetCPURollingMean(ctx)
+	require.NoError(t, err)
+	assert.NotEqual(t, cached, value, "the shared cache is bypassed")
+	assert.Equal(t, "1772366400", times[1])
+
+	again, err := client.GetCPURollingMean(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, value, again, "values are memoized on the snapshot")
+	assert.Equal(t, 2, calls)
+
+	fresh, err := client.GetCPURollingMean(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, cached, fresh, "snapshot values never reach the shared cache")
+
+	var nilSnapshot *Snapshot
+	assert.True(t, nilSnapshot.At().IsZero())
+	assert.Equal(t, context.Background(), WithSnapshot(context.Background(), nil))
+	assert.Nil(t, SnapshotFromContext(context.Background()))
+}
+
+func TestPrometheusClient_SetClock(t *testing.T) {
+	var times []string
+	calls := 0
+	client, server := newTestPrometheusClient(t, func(w http.ResponseWriter, r *http.Request) {
+		times = append(times, r.URL.Query().Get("time"))
+		calls++
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(mockPrometheusResponse(float64(calls) / 10)))
+	})
+	defer server.Close()
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	client.SetClock(func() time.Time { return now })
+
+	// Queries outside a snapshot are evaluated at the clock's time
+	first, err := client.GetCPURollingMean(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1772366400"}, times)
+
+	// Cached values expire by the clock, not the wall clock
+	now = now.Add(4 * time.Minute)
+	cached, err := client.GetCPURollingMean(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, first, cached)
+	assert.Equal(t, 1, calls)
+
+	now = now.Add(2 * time.Minute)
+	_, err = client.GetCPURollingMean(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, "1772366760", times[1])
+}
@@ -0,0 +1,114 @@
+package knowledge
+
+import (
+	"math"
+	"sort"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/storage"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// DefaultMinAttempts is how many verified outcomes an action needs before its own
+// success rate ranks it
+const DefaultMinAttempts = 3
+
+// priorSuccessRate ranks actions without enough verified outcomes
+const priorSuccessRate = 0.5
+
+// ActionSuccessRate is the verified success rate of an action for an issue type in this
+// cluster
+type ActionSuccessRate struct {
+	Action      string  `json:"action"`
+	SuccessRate float64 `json:"success_rate"`
+	Attempts    int     `json:"attempts"`
+}
+
+// ActionRanker orders recommended actions by how often they resolved the same issue
+// type before
+type ActionRanker struct {
+	outcomes    *storage.ActionOutcomeStore
+	minAttempts int
+}
+
+// NewActionRanker creates an action ranker over the verified outcomes in store;
+// minAttempts <= 0 uses DefaultMinAttempts
+func NewActionRanker(store *storage.ActionOutcomeStore, minAttempts int) *ActionRanker {
+	if minAttempts <= 0 {
+		minAttempts = DefaultMinAttempts
+	}
+	return &ActionRanker{outcomes: store, minAttempts: minAttempts}
+}
+
+// Rank orders actions by their Laplace-smoothed success rate for issueType, most
+// successful first. Actions with fewer than the minimum attempts rank as if half of
+// their attempts succeeded, and ties keep the given order, so without outcomes the
+// order is unchanged. It also returns the success rates of the actions with enough
+// attempts, in ranked order.
+func (r *ActionRanker) Rank(issueType string, actions []string) ([]string, []ActionSuccessRate) {
+	if r == nil || len(actions) == 0 {
+		return actions, nil
+	}
+	stats := r.outcomes.Stats(issueType)
+	if len(stats) == 0 {
+		return actions, nil
+	}
+
+	score := func(action string) float64 {
+		s, ok := stats[action]
+		if !ok || s.Attempts < r.minAttempts {
+			return priorSuccessRate
+		}
+		return float64(s.Successes+1) / float64(s.Attempts+2)
+	}
+
+	ranked := append([]string(nil), actions...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return score(ranked[i]) > score(ranked[j])
+	})
+
+	var rates []ActionSuccessRate
+	for _, action := range ranked {
+		if s := stats[action]; s.Attempts >= r.minAttempts {
+			rates = append(rates, ActionSuccessRate{
+				Action:      action,
+				SuccessRate: math.Round(s.SuccessRate()*100) / 100,
+				Attempts:    s.Attempts,
+			})
+		}
+	}
+	return ranked, rates
+}
+
+// Stats returns the success rates of every action with recorded outcomes for
+// issueType, most successful first
+func (r *ActionRanker) Stats(issueType string) []ActionSuccessRate {
+	stats := r.outcomes.Stats(issueType)
+	rates := make([]ActionSuccessRate, 0, len(stats))
+	for action, s := range stats {
+		rates = append(rates, ActionSuccessRate{
+			Action:      action,
+			SuccessRate: math.Round(s.SuccessRate()*100) / 100,
+			Attempts:    s.Attempts,
+		})
+	}
+	sort.Slice(rates, func(i, j int) bool {
+		if rates[i].SuccessRate != rates[j].SuccessRate {
+			return rates[i].SuccessRate > rates[j].SuccessRate
+		}
+		if rates[i].Attempts != rates[j].Attempts {
+			return rates[i].Attempts > rates[j].Attempts
+		}
+		return rates[i].Action < rates[j].Action
+	})
+	return rates
+}
+
+// IssueTypes returns the issue types with recorded outcomes
+func (r *ActionRanker) IssueTypes() []string {
+	return r.outcomes.IssueTypes()
+}
+
+// Record stores a verified action outcome
+func (r *ActionRanker) Record(outcome *models.ActionOutcome) (*models.ActionOutcome, error) {
+	return r.outcomes.Add(outcome)
+}
@@ -0,0 +1,57 @@
+package knowledge
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/storage"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+func TestActionRanker_Rank(t *testing.T) {
+	dataDir := t.TempDir()
+	store := storage.NewActionOutcomeStore(dataDir, 0)
+	ranker := NewActionRanker(store, 0)
+
+	static := []string{"check_container_logs", "verify_resource_limits", "restart_pod"}
+
+	// Without outcomes the static order is kept
+	ranked, rates := ranker.Rank("pod_crash_loop", static)
+	assert.Equal(t, static, ranked)
+	assert.Empty(t, rates)
+
+	record := func(action string, successes, failures int) {
+		for i := 0; i < successes+failures; i++ {
+			_, err := ranker.Record(&models.ActionOutcome{IssueType: "pod_crash_loop", Action: action, Success: i < successes})
+			require.NoError(t, err)
+		}
+	}
+	record("restart_pod", 9, 1)
+	record("check_container_logs", 1, 3)
+	record("verify_resource_limits", 1, 0) // too few attempts to rank
+
+	ranked, rates = ranker.Rank("pod_crash_loop", static)
+	assert.Equal(t, []string{"restart_pod", "verify_resource_limits", "check_container_logs"}, ranked)
+	assert.Equal(t, []ActionSuccessRate{
+		{Action: "restart_pod", SuccessRate: 0.9, Attempts: 10},
+		{Action: "check_container_logs", SuccessRate: 0.25, Attempts: 4},
+	}, rates)
+	assert.Equal(t, static, []string{"check_container_logs", "verify_resource_limits", "restart_pod"}, "input is not reordered")
+
+	// Outcomes of other issue types do not apply
+	ranked, _ = ranker.Rank("memory_pressure", static)
+	assert.Equal(t, static, ranked)
+
+	assert.Equal(t, []string{"pod_crash_loop"}, ranker.IssueTypes())
+	require.Len(t, ranker.Stats("pod_crash_loop"), 3)
+	assert.Equal(t, "verify_resource_limits", ranker.Stats("pod_crash_loop")[0].Action)
+
+	// Outcomes are persisted
+	reloaded := storage.NewActionOutcomeStore(dataDir, 0)
+	assert.Equal(t, 15, reloaded.Count())
+
+	_, err := ranker.Record(&models.ActionOutcome{IssueType: "pod_crash_loop"})
+	assert.ErrorContains(t, err, "action is required")
+}
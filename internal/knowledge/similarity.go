@@ -0,0 +1,419 @@
+// Package knowledge retains what past incidents teach: which earlier incidents resemble
+// a new one and which remediations resolved them.
+//
+// Incidents are compared by the cosine similarity of sparse term vectors built from
+// their metadata. Issue type, alert name and target weigh most; words of the title and
+// description, affected resources and labels add the rest. Workflows the orchestrator
+// ran for an incident tell how it was remediated and whether that succeeded.
+package knowledge
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/storage"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// Defaults for the similar-incident lookup
+const (
+	DefaultLimit    = 3
+	DefaultMinScore = 0.3
+)
+
+// Term weights of incident metadata
+const (
+	weightIssueType = 3.0
+	weightAlertName = 3.0
+	weightTarget    = 2.0
+	weightResource  = 1.0
+	weightSeverity  = 1.0
+	weightWord      = 1.0
+	weightLabel     = 0.5
+)
+
+// WorkflowSource lists remediation workflows (implemented by remediation.Orchestrator)
+type WorkflowSource interface {
+	ListWorkflows() []*models.Workflow
+}
+
+// SimilarIncident is a past incident resembling the one looked up
+type SimilarIncident struct {
+	ID        string    `json:"id"`
+	Title     string    `json:"title,omitempty"`
+	IssueType string    `json:"issue_type,omitempty"`
+	Target    string    `json:"target"`
+	Severity  string    `json:"severity,omitempty"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Score is the cosine similarity of the incidents' metadata (0-1)
+	Score float64 `json:"score"`
+
+	// Remediations are the workflows run for the incident
+	Remediations []PastRemediation `json:"remediations,omitempty"`
+}
+
+// PastRemediation is a remediation workflow run for a past incident
+type PastRemediation struct {
+	WorkflowID string `json:"workflow_id"`
+	Method     string `json:"method"`
+	IssueType  string `json:"issue_type"`
+	Status     string `json:"status"`
+}
+
+// RemediationOutcome counts how often a remediation of the similar incidents succeeded
+// or failed. A remediation is the deployment method used to remediate an issue type.
+type RemediationOutcome struct {
+	Method    string `json:"method"`
+	IssueType string `json:"issue_type"`
+	Succeeded int    `json:"succeeded"`
+	Failed    int    `json:"failed"`
+}
+
+// Lookup is the result of a similar-incident lookup
+type Lookup struct {
+	Incidents []SimilarIncident `json:"incidents"`
+
+	// Remediations of the similar incidents, most successful first
+	Remediations []RemediationOutcome `json:"remediations,omitempty"`
+
+	// Summary reads e.g. "3 similar past incidents; argocd remediation of
+	// pod_crash_loop succeeded twice"
+	Summary string `json:"summary"`
+}
+
+// Index finds past incidents similar to a given one
+type Index struct {
+	store     *storage.IncidentStore
+	workflows WorkflowSource
+	limit     int
+	minScore  float64
+}
+
+// NewIndex creates a similar-incident index over the stored incidents and the
+// incidents remediated by workflows. workflows may be nil; limit <= 0 uses DefaultLimit
+// and minScore <= 0 uses DefaultMinScore.
+func NewIndex(store *storage.IncidentStore, workflows WorkflowSource, limit int, minScore float64) *Index {
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	if minScore <= 0 {
+		minScore = DefaultMinScore
+	}
+	return &Index{
+		store:     store,
+		workflows: workflows,
+		limit:     limit,
+		minScore:  minScore,
+	}
+}
+
+// candidate is a past incident with its term vector
+type candidate struct {
+	incident  SimilarIncident
+	vector    termVector
+	workflows []*models.Workflow
+}
+
+// Similar returns the past incidents most similar to incident, created before it, with
+// the outcomes of their remediations. limit <= 0 uses the index's limit.
+func (x *Index) Similar(incident *models.Incident, limit int) *Lookup {
+	if limit <= 0 {
+		limit = x.limit
+	}
+	query := incidentVector(incident)
+
+	var matches []candidate
+	for _, c := range x.candidates(incident) {
+		score := query.cosine(c.vector)
+		if score < x.minScore {
+			continue
+		}
+		c.incident.Score = math.Round(score*100) / 100
+		matches = append(matches, c)
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].incident.Score != matches[j].incident.Score {
+			return matches[i].incident.Score > matches[j].incident.Score
+		}
+		return matches[i].incident.CreatedAt.After(matches[j].incident.CreatedAt)
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	lookup := &Lookup{Incidents: make([]SimilarIncident, 0, len(matches))}
+	outcomes := make(map[string]*RemediationOutcome)
+	for _, match := range matches {
+		for _, wf := range match.workflows {
+			match.incident.Remediations = append(match.incident.Remediations, PastRemediation{
+				WorkflowID: wf.ID,
+				Method:     wf.DeploymentMethod,
+				IssueType:  wf.IssueType,
+				Status:     string(wf.Status),
+			})
+			if wf.Status != models.WorkflowStatusCompleted && wf.Status != models.WorkflowStatusFailed {
+				continue
+			}
+			key := wf.DeploymentMethod + "/" + wf.IssueType
+			outcome := outcomes[key]
+			if outcome == nil {
+				outcome = &RemediationOutcome{Method: wf.DeploymentMethod, IssueType: wf.IssueType}
+				outcomes[key] = outcome
+			}
+			if wf.Status == models.WorkflowStatusCompleted {
+				outcome.Succeeded++
+			} else {
+				outcome.Failed++
+			}
+		}
+		lookup.Incidents = append(lookup.Incidents, match.incident)
+	}
+
+	for _, outcome := range outcomes {
+		lookup.Remediations = append(lookup.Remediations, *outcome)
+	}
+	sort.Slice(lookup.Remediations, func(i, j int) bool {
+		a, b := lookup.Remediations[i], lookup.Remediations[j]
+		if a.Succeeded != b.Succeeded {
+			return a.Succeeded > b.Succeeded
+		}
+		if a.Failed != b.Failed {
+			return a.Failed < b.Failed
+		}
+		return a.Method+"/"+a.IssueType < b.Method+"/"+b.IssueType
+	})
+	lookup.Summary = summarize(lookup)
+	return lookup
+}
+
+// candidates returns the stored incidents created before incident, and the incidents
+// known only from their remediation workflows
+func (x *Index) candidates(incident *models.Incident) []candidate {
+	stored := x.store.List(storage.ListFilter{})
+
+	var workflows []*models.Workflow
+	if x.workflows != nil {
+		workflows = x.workflows.ListWorkflows()
+	}
+	byIncident := make(map[string][]*models.Workflow)
+	byID := make(map[string]*models.Workflow, len(workflows))
+	for _, wf := range workflows {
+		byIncident[wf.IncidentID] = append(byIncident[wf.IncidentID], wf)
+		byID[wf.ID] = wf
+	}
+
+	known := map[string]bool{incident.ID: true}
+	var candidates []candidate
+	for _, past := range stored {
+		known[past.ID] = true
+		if past.ID == incident.ID || (!incident.CreatedAt.IsZero() && !past.CreatedAt.Before(incident.CreatedAt)) {
+			continue
+		}
+		linked := byIncident[past.ID]
+		if wf := byID[past.WorkflowID]; wf != nil && wf.IncidentID != past.ID {
+			linked = append(linked, wf)
+		}
+		candidates = append(candidates, candidate{
+			incident: SimilarIncident{
+				ID:        past.ID,
+				Title:     past.Title,
+				IssueType: past.Labels["issue_type"],
+				Target:    past.Target,
+				Severity:  string(past.Severity),
+				Status:    string(past.Status),
+				CreatedAt: past.CreatedAt,
+			},
+			vector:    incidentVector(past),
+			workflows: sortedWorkflows(linked),
+		})
+	}
+
+	for incidentID, linked := range byIncident {
+		if incidentID == "" || known[incidentID] {
+			continue
+		}
+		linked = sortedWorkflows(linked)
+		first := linked[0]
+		if !incident.CreatedAt.IsZero() && !first.CreatedAt.Before(incident.CreatedAt) {
+			continue
+		}
+		candidates = append(candidates, candidate{
+			incident: SimilarIncident{
+				ID:        incidentID,
+				IssueType: first.IssueType,
+				Target:    first.Namespace,
+				Status:    workflowIncidentStatus(linked[len(linked)-1].Status),
+				CreatedAt: first.CreatedAt,
+			},
+			vector:    workflowVector(first),
+			workflows: linked,
+		})
+	}
+	return candidates
+}
+
+// sortedWorkflows orders workflows oldest first
+func sortedWorkflows(workflows []*models.Workflow) []*models.Workflow {
+	sort.SliceStable(workflows, func(i, j int) bool {
+		return workflows[i].CreatedAt.Before(workflows[j].CreatedAt)
+	})
+	return workflows
+}
+
+// workflowIncidentStatus maps the status of an incident's last workflow to an incident
+// status, as GET /api/v1/incidents does
+func workflowIncidentStatus(status models.WorkflowStatus) string {
+	switch status {
+	case models.WorkflowStatusCompleted:
+		return "remediated"
+	case models.WorkflowStatusFailed:
+		return "failed"
+	default:
+		return "in_progress"
+	}
+}
+
+// summarize describes a lookup in one line
+func summarize(lookup *Lookup) string {
+	var summary string
+	switch len(lookup.Incidents) {
+	case 0:
+		return "no similar past incidents"
+	case 1:
+		summary = "1 similar past incident"
+	default:
+		summary = fmt.Sprintf("%d similar past incidents", len(lookup.Incidents))
+	}
+	if len(lookup.Remediations) == 0 {
+		return summary
+	}
+
+	best := lookup.Remediations[0]
+	remediation := fmt.Sprintf("%s remediation of %s", best.Method, best.IssueType)
+	switch {
+	case best.Succeeded > 0 && best.Failed > 0:
+		return fmt.Sprintf("%s; %s succeeded %s and failed %s", summary, remediation, times(best.Succeeded), times(best.Failed))
+	case best.Succeeded > 0:
+		return fmt.Sprintf("%s; %s succeeded %s", summary, remediation, times(best.Succeeded))
+	default:
+		return fmt.Sprintf("%s; %s failed %s", summary, remediation, times(best.Failed))
+	}
+}
+
+// times spells out a repetition count
+func times(n int) string {
+	switch n {
+	case 1:
+		return "once"
+	case 2:
+		return "twice"
+	default:
+		return fmt.Sprintf("%d times", n)
+	}
+}
+
+// termVector is a sparse weighted bag of metadata terms
+type termVector map[string]float64
+
+func (v termVector) add(term string, weight float64) {
+	if term != "" {
+		v[term] += weight
+	}
+}
+
+// cosine returns the cosine similarity of two vectors, 0 when either is empty
+func (v termVector) cosine(other termVector) float64 {
+	var dot, normV, normOther float64
+	for term, weight := range v {
+		normV += weight * weight
+		dot += weight * other[term]
+	}
+	for _, weight := range other {
+		normOther += weight * weight
+	}
+	if normV == 0 || normOther == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(normV*normOther)
+}
+
+// incidentVector builds the term vector of an incident's metadata
+func incidentVector(incident *models.Incident) termVector {
+	v := make(termVector)
+	v.add(keyTerm("issue_type", incident.Labels["issue_type"]), weightIssueType)
+	v.add(keyTerm("alertname", incident.Labels["alertname"]), weightAlertName)
+	v.add(keyTerm("target", incident.Target), weightTarget)
+	v.add(keyTerm("severity", string(incident.Severity)), weightSeverity)
+	for _, resource := range incident.AffectedResources {
+		addResource(v, resource)
+	}
+	for key, value := range incident.Labels {
+		if key != "issue_type" && key != "alertname" {
+			v.add(keyTerm("label", key+"="+value), weightLabel)
+		}
+	}
+	for _, word := range words(incident.Title + " " + incident.Description) {
+		v.add(word, weightWord)
+	}
+	return v
+}
+
+// workflowVector builds the term vector of an incident known only from its workflow
+func workflowVector(wf *models.Workflow) termVector {
+	v := make(termVector)
+	v.add(keyTerm("issue_type", wf.IssueType), weightIssueType)
+	v.add(keyTerm("target", wf.Namespace), weightTarget)
+	if wf.ResourceKind != "" && wf.ResourceName != "" {
+		addResource(v, wf.ResourceKind+"/"+wf.ResourceName)
+	}
+	for _, word := range words(strings.ReplaceAll(wf.IssueType, "_", " ")) {
+		v.add(word, weightWord)
+	}
+	return v
+}
+
+// addResource adds a "kind/name" resource and its kind, so resources of the same kind
+// match even when pod names differ
+func addResource(v termVector, resource string) {
+	resource = strings.ToLower(resource)
+	v.add(keyTerm("resource", resource), weightResource)
+	if kind, _, found := strings.Cut(resource, "/"); found {
+		v.add(keyTerm("kind", kind), weightResource)
+	}
+}
+
+func keyTerm(key, value string) string {
+	if value == "" {
+		return ""
+	}
+	return key + ":" + strings.ToLower(value)
+}
+
+// stopWords are left out of title and description terms
+var stopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "with": true, "was": true, "are": true,
+	"has": true, "have": true, "not": true, "from": true, "this": true, "that": true,
+	"into": true, "after": true, "than": true, "its": true, "been": true, "were": true,
+}
+
+// words splits text into lowercase words of at least three letters. Words with digits,
+// such as generated pod names and timestamps, are left out.
+func words(text string) []string {
+	var result []string
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-'
+	}) {
+		word = strings.Trim(word, "-")
+		if len(word) < 3 || stopWords[word] || strings.IndexFunc(word, unicode.IsDigit) >= 0 {
+			continue
+		}
+		result = append(result, word)
+	}
+	return result
+}
@@ -0,0 +1,105 @@
+package knowledge
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/storage"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// workflowList is a fixed WorkflowSource
+type workflowList []*models.Workflow
+
+func (w workflowList) ListWorkflows() []*models.Workflow { return w }
+
+func TestIndex_Similar(t *testing.T) {
+	t.Setenv("DATA_DIR", t.TempDir())
+	base := time.Date(2025, 3, 1, 8, 0, 0, 0, time.UTC)
+
+	crashLoop := func(id string, daysAgo int) *models.Incident {
+		return &models.Incident{
+			ID:                id,
+			Title:             "Pods crash looping",
+			Description:       "checkout pods restart with CrashLoopBackOff after the config change",
+			Severity:          models.IncidentSeverityHigh,
+			Target:            "shop",
+			Status:            models.IncidentStatusResolved,
+			AffectedResources: []string{"Pod/checkout-" + id},
+			Labels:            map[string]string{"issue_type": "pod_crash_loop"},
+			CreatedAt:         base.Add(-time.Duration(daysAgo) * 24 * time.Hour),
+		}
+	}
+	store := storage.NewIncidentStore()
+	require.NoError(t, store.Restore([]*models.Incident{
+		crashLoop("inc-1", 30),
+		crashLoop("inc-2", 10),
+		crashLoop("inc-later", -1),
+		{
+			ID:          "inc-disk",
+			Title:       "Node disk pressure",
+			Description: "worker-3 evicts pods under disk pressure",
+			Severity:    models.IncidentSeverityMedium,
+			Target:      "openshift-monitoring",
+			Status:      models.IncidentStatusResolved,
+			Labels:      map[string]string{"issue_type": "disk_pressure"},
+			CreatedAt:   base.Add(-5 * 24 * time.Hour),
+		},
+	}, true))
+
+	workflows := workflowList{
+		{ID: "wf-1", IncidentID: "inc-1", Status: models.WorkflowStatusCompleted, DeploymentMethod: "argocd", IssueType: "pod_crash_loop", CreatedAt: base.Add(-30 * 24 * time.Hour)},
+		{ID: "wf-2", IncidentID: "inc-2", Status: models.WorkflowStatusFailed, DeploymentMethod: "helm", IssueType: "pod_crash_loop", CreatedAt: base.Add(-10 * 24 * time.Hour)},
+		{ID: "wf-3", IncidentID: "inc-2", Status: models.WorkflowStatusCompleted, DeploymentMethod: "argocd", IssueType: "pod_crash_loop", CreatedAt: base.Add(-10*24*time.Hour + time.Hour)},
+		// An incident only known from its workflow
+		{ID: "wf-4", IncidentID: "inc-wf", Status: models.WorkflowStatusCompleted, DeploymentMethod: "argocd", IssueType: "pod_crash_loop",
+			Namespace: "shop", ResourceKind: "Pod", ResourceName: "checkout-9", CreatedAt: base.Add(-2 * 24 * time.Hour)},
+	}
+
+	current := crashLoop("inc-new", 0)
+	current.Status = models.IncidentStatusActive
+	index := NewIndex(store, workflows, 0, 0)
+
+	lookup := index.Similar(current, 0)
+	require.Len(t, lookup.Incidents, DefaultLimit)
+	assert.Equal(t, "inc-2", lookup.Incidents[0].ID, "most recent of the equally similar incidents first")
+	assert.Equal(t, "inc-1", lookup.Incidents[1].ID)
+	assert.Equal(t, "inc-wf", lookup.Incidents[2].ID)
+	assert.Equal(t, "remediated", lookup.Incidents[2].Status)
+	assert.Greater(t, lookup.Incidents[0].Score, lookup.Incidents[2].Score)
+	for _, incident := range lookup.Incidents {
+		assert.NotEqual(t, "inc-later", incident.ID, "incidents created later are not past incidents")
+		assert.NotEqual(t, "inc-disk", incident.ID)
+	}
+	require.Len(t, lookup.Incidents[0].Remediations, 2)
+	assert.Equal(t, "wf-2", lookup.Incidents[0].Remediations[0].WorkflowID)
+
+	require.Len(t, lookup.Remediations, 2)
+	assert.Equal(t, RemediationOutcome{Method: "argocd", IssueType: "pod_crash_loop", Succeeded: 3}, lookup.Remediations[0])
+	assert.Equal(t, RemediationOutcome{Method: "helm", IssueType: "pod_crash_loop", Failed: 1}, lookup.Remediations[1])
+	assert.Equal(t, "3 similar past incidents; argocd remediation of pod_crash_loop succeeded 3 times", lookup.Summary)
+
+	lookup = index.Similar(current, 1)
+	require.Len(t, lookup.Incidents, 1)
+	assert.Equal(t, "1 similar past incident; argocd remediation of pod_crash_loop succeeded once", lookup.Summary)
+
+	unrelated := &models.Incident{
+		ID:          "inc-cert",
+		Title:       "Certificate expired",
+		Description: "router certificate expired",
+		Severity:    models.IncidentSeverityCritical,
+		Target:      "openshift-ingress",
+		CreatedAt:   base,
+	}
+	lookup = index.Similar(unrelated, 0)
+	assert.Empty(t, lookup.Incidents)
+	assert.Equal(t, "no similar past incidents", lookup.Summary)
+}
+
+func TestWords(t *testing.T) {
+	assert.Equal(t, []string{"pods", "crash-looping", "namespace", "times"},
+		words("Pods api-7f9c4 crash-looping in the namespace, 3 times"))
+}
@@ -0,0 +1,10 @@
+//go:build !unix
+
+package loadshed
+
+import "time"
+
+// processCPUTime is not available on this platform, so CPU is not checked
+func processCPUTime() (time.Duration, bool) {
+	return 0, false
+}
@@ -0,0 +1,422 @@
+// Package loadshed protects the engine when its own resources run short.
+//
+// A Shedder samples the engine's CPU use, memory use and goroutine count. When a
+// signal crosses its threshold the engine is under elevated pressure and expensive
+// endpoints, such as batch workload reports and 7-day trends, are answered with 503 and
+// Retry-After. When a signal goes well past its threshold the pressure is critical and
+// every request is shed except health probes and incident reads, so the engine stays
+// observable and operators can still see what is going on.
+package loadshed
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"regexp"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Defaults used when an option is zero
+const (
+	DefaultInterval           = 5 * time.Second
+	DefaultCPUThreshold       = 0.85
+	DefaultMemoryThreshold    = 0.85
+	DefaultGoroutineThreshold = 10000
+	DefaultRetryAfter         = 30 * time.Second
+)
+
+// criticalFactor is how far past its threshold a signal must go for critical pressure
+const criticalFactor = 1.15
+
+// Level is the resource pressure of the engine
+type Level int
+
+// Pressure levels
+const (
+	LevelNormal Level = iota
+	LevelElevated
+	LevelCritical
+)
+
+// String returns the name of the level
+func (l Level) String() string {
+	switch l {
+	case LevelElevated:
+		return "elevated"
+	case LevelCritical:
+		return "critical"
+	default:
+		return "normal"
+	}
+}
+
+// Priority is how important it is to keep serving a request under pressure
+type Priority int
+
+// Request priorities: expensive requests are shed under elevated pressure, normal ones
+// under critical pressure, and critical ones never
+const (
+	PriorityCritical Priority = iota
+	PriorityNormal
+	PriorityExpensive
+)
+
+// String returns the name of the priority
+func (p Priority) String() string {
+	switch p {
+	case PriorityCritical:
+		return "critical"
+	case PriorityExpensive:
+		return "expensive"
+	default:
+		return "normal"
+	}
+}
+
+// Options configures a Shedder
+type Options struct {
+	// Interval is how often resources are sampled
+	Interval time.Duration
+
+	// CPUThreshold is the share of the CPUs available to the engine (GOMAXPROCS) in use
+	// at which pressure is elevated
+	CPUThreshold float64
+
+	// MemoryThreshold is the share of the memory limit in use at which pressure is
+	// elevated. The limit is MemoryLimit, GOMEMLIMIT or the container's cgroup limit;
+	// without one memory is not checked.
+	MemoryThreshold float64
+	MemoryLimit     uint64
+
+	// GoroutineThreshold is the goroutine count at which pressure is elevated
+	GoroutineThreshold int
+
+	// RetryAfter is sent to shed clients
+	RetryAfter time.Duration
+}
+
+// Status is the latest resource sample and the pressure derived from it
+type Status struct {
+	Level            string    `json:"level"`
+	Reasons          []string  `json:"reasons,omitempty"`
+	CPU              float64   `json:"cpu"`
+	MemoryBytes      uint64    `json:"memory_bytes"`
+	MemoryLimitBytes uint64    `json:"memory_limit_bytes,omitempty"`
+	Goroutines       int       `json:"goroutines"`
+	SampledAt        time.Time `json:"sampled_at"`
+}
+
+// Shedder samples the engine's resources and sheds requests under pressure
+type Shedder struct {
+	opts        Options
+	memoryLimit uint64
+	log         *logrus.Logger
+
+	mu       sync.RWMutex
+	level    Level
+	status   Status
+	lastCPU  time.Duration
+	lastWall time.Time
+
+	// Resource readers, replaceable in tests
+	now        func() time.Time
+	cpuTime    func() (time.Duration, bool)
+	memory     func() uint64
+	goroutines func() int
+	procs      func() int
+}
+
+// New creates a shedder; zero options take their defaults
+func New(opts Options, log *logrus.Logger) *Shedder {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultInterval
+	}
+	if opts.CPUThreshold <= 0 {
+		opts.CPUThreshold = DefaultCPUThreshold
+	}
+	if opts.MemoryThreshold <= 0 {
+		opts.MemoryThreshold = DefaultMemoryThreshold
+	}
+	if opts.GoroutineThreshold <= 0 {
+		opts.GoroutineThreshold = DefaultGoroutineThreshold
+	}
+	if opts.RetryAfter <= 0 {
+		opts.RetryAfter = DefaultRetryAfter
+	}
+	limit := opts.MemoryLimit
+	if limit == 0 {
+		limit = detectMemoryLimit()
+	}
+	return &Shedder{
+		opts:        opts,
+		memoryLimit: limit,
+		log:         log,
+		status:      Status{Level: LevelNormal.String()},
+		now:         time.Now,
+		cpuTime:     processCPUTime,
+		memory:      memoryInUse,
+		goroutines:  runtime.NumGoroutine,
+		procs:       func() int { return runtime.GOMAXPROCS(0) },
+	}
+}
+
+// Start samples resources every interval until ctx is cancelled
+func (s *Shedder) Start(ctx context.Context) {
+	s.Sample()
+	go func() {
+		ticker := time.NewTicker(s.opts.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.Sample()
+			}
+		}
+	}()
+}
+
+// Sample reads the engine's resources and updates the pressure level
+func (s *Shedder) Sample() Status {
+	now := s.now()
+	memory := s.memory()
+	goroutines := s.goroutines()
+	cpuTime, cpuOK := s.cpuTime()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cpu := 0.0
+	if cpuOK && !s.lastWall.IsZero() {
+		if wall := now.Sub(s.lastWall); wall > 0 {
+			cpu = float64(cpuTime-s.lastCPU) / (float64(wall) * float64(max(s.procs(), 1)))
+		}
+	}
+	if cpuOK {
+		s.lastCPU, s.lastWall = cpuTime, now
+	}
+
+	level := LevelNormal
+	var reasons []string
+	check := func(name string, value, threshold float64) {
+		var signal Level
+		switch {
+		case value >= threshold*criticalFactor:
+			signal = LevelCritical
+		case value >= threshold:
+			signal = LevelElevated
+		default:
+			return
+		}
+		reasons = append(reasons, fmt.Sprintf("%s %.2f >= %.2f", name, value, threshold))
+		level = max(level, signal)
+	}
+	check("cpu", cpu, s.opts.CPUThreshold)
+	if s.memoryLimit > 0 {
+		check("memory", float64(memory)/float64(s.memoryLimit), s.opts.MemoryThreshold)
+	}
+	check("goroutines", float64(goroutines), float64(s.opts.GoroutineThreshold))
+
+	if level != s.level {
+		entry := s.log.WithFields(logrus.Fields{
+			"level":      level.String(),
+			"previous":   s.level.String(),
+			"reasons":    reasons,
+			"cpu":        cpu,
+			"memory":     memory,
+			"goroutines": goroutines,
+		})
+		if level > s.level {
+			entry.Warn("Engine under resource pressure, shedding load")
+		} else {
+			entry.Info("Engine resource pressure eased")
+		}
+	}
+	s.level = level
+	s.status = Status{
+		Level:            level.String(),
+		Reasons:          reasons,
+		CPU:              cpu,
+		MemoryBytes:      memory,
+		MemoryLimitBytes: s.memoryLimit,
+		Goroutines:       goroutines,
+		SampledAt:        now,
+	}
+	recordSample(level, cpu, memory, s.memoryLimit, goroutines)
+	return s.status
+}
+
+// Status returns the latest sample
+func (s *Shedder) Status() Status {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.status
+}
+
+// Level returns the current pressure level
+func (s *Shedder) Level() Level {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.level
+}
+
+// Sheds reports whether requests of a priority are shed at a pressure level
+func Sheds(level Level, priority Priority) bool {
+	switch priority {
+	case PriorityExpensive:
+		return level >= LevelElevated
+	case PriorityNormal:
+		return level >= LevelCritical
+	default:
+		return false
+	}
+}
+
+// Middleware answers requests shed at the current pressure level with 503 and
+// Retry-After, and passes the others
+func (s *Shedder) Middleware(next http.Handler) http.Handler {
+	retryAfter := strconv.Itoa(int(math.Ceil(s.opts.RetryAfter.Seconds())))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		level := s.Level()
+		if level == LevelNormal {
+			next.ServeHTTP(w, r)
+			return
+		}
+		priority := Classify(r)
+		if !Sheds(level, priority) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		RecordShed(priority)
+		s.log.WithFields(logrus.Fields{
+			"method":   r.Method,
+			"path":     r.URL.Path,
+			"priority": priority.String(),
+			"level":    level.String(),
+		}).Debug("Request shed under resource pressure")
+
+		w.Header().Set("Retry-After", retryAfter)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		if err := json.NewEncoder(w).Encode(map[string]string{
+			"status": "error",
+			"error":  fmt.Sprintf("engine is under %s resource pressure, retry later", level),
+			"code":   "overloaded",
+		}); err != nil {
+			s.log.WithError(err).Error("Failed to encode load shedding response")
+		}
+	})
+}
+
+// routePriority sets the priority of the routes under prefix. Routes with a window
+// parameter are expensive when the window covers at least a week; trend names a
+// boolean parameter that, when false, makes the window irrelevant.
+type routePriority struct {
+	prefix   string
+	method   string
+	priority Priority
+
+	window, defaultWindow, trend string
+}
+
+// routePriorities maps API paths, without their /api/vN prefix, to their priority.
+// The first matching entry applies; unlisted routes are normal.
+var routePriorities = []routePriority{
+	{prefix: "/health", priority: PriorityCritical},
+	{prefix: "/incidents", method: http.MethodGet, priority: PriorityCritical},
+	{prefix: "/batch", priority: PriorityExpensive},
+	{prefix: "/capacity/namespace", priority: PriorityNormal, window: "window", defaultWindow: "7d", trend: "include_trending"},
+	{prefix: "/rightsizing", priority: PriorityNormal, window: "window", defaultWindow: "7d"},
+	{prefix: "/predict/accuracy", priority: PriorityNormal, window: "window", defaultWindow: "24h"},
+}
+
+// versionPrefix matches the API version at the start of a path
+var versionPrefix = regexp.MustCompile(`^/api/v[0-9]+`)
+
+// expensiveWindow is the trend window from which a request is expensive
+const expensiveWindow = 7 * 24 * time.Hour
+
+// Classify returns the priority of a request
+func Classify(r *http.Request) Priority {
+	path := versionPrefix.ReplaceAllString(r.URL.Path, "")
+	for _, route := range routePriorities {
+		if path != route.prefix && !strings.HasPrefix(path, route.prefix+"/") {
+			continue
+		}
+		if route.method != "" && r.Method != route.method && (route.method != http.MethodGet || r.Method != http.MethodHead) {
+			continue
+		}
+		if route.window == "" {
+			return route.priority
+		}
+
+		query := r.URL.Query()
+		if route.trend != "" && query.Get(route.trend) == "false" {
+			return route.priority
+		}
+		window := query.Get(route.window)
+		if window == "" {
+			window = route.defaultWindow
+		}
+		if span, ok := parseWindow(window); ok && span >= expensiveWindow {
+			return PriorityExpensive
+		}
+		return route.priority
+	}
+	return PriorityNormal
+}
+
+// parseWindow parses a window such as 7d or 168h
+func parseWindow(window string) (time.Duration, bool) {
+	if days, ok := strings.CutSuffix(window, "d"); ok {
+		n, err := strconv.Atoi(days)
+		return time.Duration(n) * 24 * time.Hour, err == nil
+	}
+	span, err := time.ParseDuration(window)
+	return span, err == nil
+}
+
+// memoryInUse returns the memory the Go runtime holds from the OS
+func memoryInUse() uint64 {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return stats.Sys - stats.HeapReleased
+}
+
+// cgroupMemoryLimitFiles hold the container memory limit under cgroup v2 and v1
+var cgroupMemoryLimitFiles = []string{
+	"/sys/fs/cgroup/memory.max",
+	"/sys/fs/cgroup/memory/memory.limit_in_bytes",
+}
+
+// detectMemoryLimit returns GOMEMLIMIT or the container memory limit, or 0 when
+// neither is set
+func detectMemoryLimit() uint64 {
+	if limit := debug.SetMemoryLimit(-1); limit > 0 && limit < math.MaxInt64 {
+		return uint64(limit)
+	}
+	for _, file := range cgroupMemoryLimitFiles {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		limit, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+		// cgroup v1 reports an unlimited container as a huge page-aligned number
+		if err == nil && limit > 0 && limit < 1<<62 {
+			return limit
+		}
+	}
+	return 0
+}
@@ -0,0 +1,134 @@
+package loadshed
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeResources drives the readers of a shedder
+type fakeResources struct {
+	now        time.Time
+	cpu        time.Duration
+	memory     uint64
+	goroutines int
+}
+
+func newTestShedder(t *testing.T, res *fakeResources) *Shedder {
+	t.Helper()
+	log := logrus.New()
+	log.SetLevel(logrus.PanicLevel)
+	s := New(Options{MemoryLimit: 1000, GoroutineThreshold: 100, RetryAfter: 1500 * time.Millisecond}, log)
+	s.now = func() time.Time { return res.now }
+	s.cpuTime = func() (time.Duration, bool) { return res.cpu, true }
+	s.memory = func() uint64 { return res.memory }
+	s.goroutines = func() int { return res.goroutines }
+	s.procs = func() int { return 2 }
+	return s
+}
+
+func TestSample(t *testing.T) {
+	res := &fakeResources{now: time.Now(), memory: 100, goroutines: 10}
+	s := newTestShedder(t, res)
+
+	status := s.Sample()
+	assert.Equal(t, "normal", status.Level)
+	assert.Zero(t, status.CPU, "the first sample has no CPU baseline")
+
+	// 1.8s of CPU over 1s on 2 CPUs is 90%
+	res.now = res.now.Add(time.Second)
+	res.cpu += 1800 * time.Millisecond
+	status = s.Sample()
+	assert.Equal(t, "elevated", status.Level)
+	assert.InDelta(t, 0.9, status.CPU, 0.001)
+	assert.Equal(t, []string{"cpu 0.90 >= 0.85"}, status.Reasons)
+
+	// memory well past its threshold is critical
+	res.now = res.now.Add(time.Second)
+	res.memory = 990
+	status = s.Sample()
+	assert.Equal(t, LevelCritical, s.Level())
+	assert.Equal(t, []string{"memory 0.99 >= 0.85"}, status.Reasons)
+
+	res.now = res.now.Add(time.Second)
+	res.memory = 100
+	res.goroutines = 100
+	assert.Equal(t, "elevated", s.Sample().Level)
+
+	res.now = res.now.Add(time.Second)
+	res.goroutines = 10
+	assert.Equal(t, "normal", s.Sample().Level)
+}
+
+func TestSample_NoMemoryLimit(t *testing.T) {
+	res := &fakeResources{now: time.Now(), memory: 1 << 40}
+	s := newTestShedder(t, res)
+	s.memoryLimit = 0
+
+	assert.Equal(t, LevelNormal.String(), s.Sample().Level)
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		method, target string
+		want           Priority
+	}{
+		{http.MethodGet, "/health", PriorityCritical},
+		{http.MethodGet, "/api/v1/health/dependencies", PriorityCritical},
+		{http.MethodGet, "/api/v1/incidents", PriorityCritical},
+		{http.MethodGet, "/api/v1/incidents/inc-1/artifacts", PriorityCritical},
+		{http.MethodPost, "/api/v1/incidents", PriorityNormal},
+		{http.MethodGet, "/api/v1/batch/workloads?namespace=jobs", PriorityExpensive},
+		{http.MethodGet, "/api/v1/capacity/namespace/payments", PriorityExpensive},
+		{http.MethodGet, "/api/v1/capacity/namespace/payments?window=24h", PriorityNormal},
+		{http.MethodGet, "/api/v1/capacity/namespace/payments?include_trending=false", PriorityNormal},
+		{http.MethodGet, "/api/v1/rightsizing?namespace=payments&window=30d", PriorityExpensive},
+		{http.MethodGet, "/api/v1/rightsizing?namespace=payments&window=1d", PriorityNormal},
+		{http.MethodGet, "/api/v1/predict/accuracy", PriorityNormal},
+		{http.MethodGet, "/api/v1/predict/accuracy?window=720h", PriorityExpensive},
+		{http.MethodPost, "/api/v1/anomalies/analyze", PriorityNormal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.target, func(t *testing.T) {
+			assert.Equal(t, tt.want, Classify(httptest.NewRequest(tt.method, tt.target, nil)))
+		})
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	res := &fakeResources{now: time.Now(), memory: 100, goroutines: 10}
+	s := newTestShedder(t, res)
+	handler := s.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	serve := func(method, target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(method, target, nil))
+		return rec
+	}
+
+	s.Sample()
+	assert.Equal(t, http.StatusOK, serve(http.MethodGet, "/api/v1/batch/workloads").Code)
+
+	res.goroutines = 100
+	s.Sample()
+	rec := serve(http.MethodGet, "/api/v1/batch/workloads")
+	require.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "2", rec.Header().Get("Retry-After"))
+	var body map[string]string
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "overloaded", body["code"])
+	assert.Equal(t, http.StatusOK, serve(http.MethodPost, "/api/v1/anomalies/analyze").Code)
+
+	res.goroutines = 200
+	s.Sample()
+	assert.Equal(t, http.StatusServiceUnavailable, serve(http.MethodPost, "/api/v1/anomalies/analyze").Code)
+	assert.Equal(t, http.StatusOK, serve(http.MethodGet, "/health").Code)
+	assert.Equal(t, http.StatusOK, serve(http.MethodGet, "/api/v1/incidents").Code)
+}
@@ -0,0 +1,64 @@
+package loadshed
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// PressureLevel is the current pressure level (0 normal, 1 elevated, 2 critical)
+	PressureLevel = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "coordination_engine_load_shed_level",
+			Help: "Resource pressure level of the engine (0 normal, 1 elevated, 2 critical)",
+		},
+	)
+
+	// CPUUsage is the share of the available CPUs used by the engine
+	CPUUsage = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "coordination_engine_load_shed_cpu_ratio",
+			Help: "Share of the CPUs available to the engine (GOMAXPROCS) used in the last sample",
+		},
+	)
+
+	// MemoryUsage is the share of the memory limit used by the engine
+	MemoryUsage = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "coordination_engine_load_shed_memory_ratio",
+			Help: "Share of the memory limit used by the engine (0 without a limit)",
+		},
+	)
+
+	// Goroutines is the goroutine count of the engine
+	Goroutines = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "coordination_engine_load_shed_goroutines",
+			Help: "Number of goroutines in the engine at the last sample",
+		},
+	)
+
+	// RequestsShed counts requests answered with 503 under resource pressure
+	RequestsShed = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "coordination_engine_load_shed_requests_total",
+			Help: "Total number of requests shed under resource pressure, by priority (expensive, normal)",
+		},
+		[]string{"priority"},
+	)
+)
+
+// recordSample records a resource sample
+func recordSample(level Level, cpu float64, memory, memoryLimit uint64, goroutines int) {
+	PressureLevel.Set(float64(level))
+	CPUUsage.Set(cpu)
+	if memoryLimit > 0 {
+		MemoryUsage.Set(float64(memory) / float64(memoryLimit))
+	}
+	Goroutines.Set(float64(goroutines))
+}
+
+// RecordShed records a shed request
+func RecordShed(priority Priority) {
+	RequestsShed.WithLabelValues(priority.String()).Inc()
+}
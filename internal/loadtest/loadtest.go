@@ -0,0 +1,195 @@
+package loadtest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Config bounds a load run
+type Config struct {
+	// Name labels the run in the report
+	Name string
+
+	// Concurrency is the number of workers issuing requests (default 1)
+	Concurrency int
+
+	// Duration stops the run after this long
+	Duration time.Duration
+
+	// Requests stops the run after this many requests; zero runs for Duration
+	Requests int
+}
+
+// Report summarizes a load run. Allocations are counted process-wide, so they include
+// the in-process mock backends and are comparable between runs rather than absolute.
+type Report struct {
+	Name        string        `json:"name"`
+	Concurrency int           `json:"concurrency"`
+	Requests    int           `json:"requests"`
+	Errors      int           `json:"errors"`
+	Elapsed     time.Duration `json:"elapsed_ns"`
+
+	// Throughput is completed requests per second
+	Throughput float64 `json:"throughput_rps"`
+
+	P50 time.Duration `json:"p50_ns"`
+	P95 time.Duration `json:"p95_ns"`
+	P99 time.Duration `json:"p99_ns"`
+	Max time.Duration `json:"max_ns"`
+
+	AllocsPerOp float64 `json:"allocs_per_op"`
+	BytesPerOp  float64 `json:"bytes_per_op"`
+
+	// BackendCallsPerOp counts mock backend requests per request, set by callers
+	// that track them
+	BackendCallsPerOp map[string]float64 `json:"backend_calls_per_op,omitempty"`
+
+	// FirstError is the first request error, if any
+	FirstError string `json:"first_error,omitempty"`
+}
+
+// Run calls fn from Concurrency workers until Requests requests completed, Duration
+// passed or ctx is done, whichever comes first, and reports the results. At least one
+// of Requests and Duration must be set.
+func Run(ctx context.Context, cfg Config, fn func(ctx context.Context) error) (*Report, error) {
+	if cfg.Requests <= 0 && cfg.Duration <= 0 {
+		return nil, fmt.Errorf("load run %q: requests or duration must be set", cfg.Name)
+	}
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = 1
+	}
+	if cfg.Duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.Duration)
+		defer cancel()
+	}
+
+	var (
+		issued    atomic.Int64
+		errCount  atomic.Int64
+		firstErr  atomic.Value
+		mu        sync.Mutex
+		latencies = make([]time.Duration, 0, 1024)
+		wg        sync.WaitGroup
+		before    runtime.MemStats
+		after     runtime.MemStats
+	)
+
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	for i := 0; i < cfg.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			local := make([]time.Duration, 0, 256)
+			for ctx.Err() == nil {
+				if cfg.Requests > 0 && issued.Add(1) > int64(cfg.Requests) {
+					break
+				}
+				begin := time.Now()
+				err := fn(ctx)
+				if err != nil && ctx.Err() != nil {
+					// Requests cut off by the end of the run are not counted
+					break
+				}
+				local = append(local, time.Since(begin))
+				if err != nil {
+					errCount.Add(1)
+					firstErr.CompareAndSwap(nil, err.Error())
+				}
+			}
+			mu.Lock()
+			latencies = append(latencies, local...)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+
+	report := &Report{
+		Name:        cfg.Name,
+		Concurrency: cfg.Concurrency,
+		Requests:    len(latencies),
+		Errors:      int(errCount.Load()),
+		Elapsed:     elapsed,
+	}
+	if msg, ok := firstErr.Load().(string); ok {
+		report.FirstError = msg
+	}
+	if report.Requests == 0 {
+		return report, nil
+	}
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	report.Throughput = float64(report.Requests) / elapsed.Seconds()
+	report.P50 = percentile(latencies, 0.50)
+	report.P95 = percentile(latencies, 0.95)
+	report.P99 = percentile(latencies, 0.99)
+	report.Max = latencies[len(latencies)-1]
+	report.AllocsPerOp = float64(after.Mallocs-before.Mallocs) / float64(report.Requests)
+	report.BytesPerOp = float64(after.TotalAlloc-before.TotalAlloc) / float64(report.Requests)
+	return report, nil
+}
+
+// percentile returns the nearest-rank percentile of sorted latencies
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
+
+// WriteText writes reports as an aligned table
+func WriteText(w io.Writer, reports []*Report) error {
+	if _, err := fmt.Fprintf(w, "%-32s %5s %8s %6s %10s %10s %10s %10s %10s %12s  %s\n",
+		"NAME", "CONC", "REQS", "ERRS", "REQ/S", "P50", "P95", "P99", "ALLOCS/OP", "BYTES/OP", "BACKEND CALLS/OP"); err != nil {
+		return err
+	}
+	for _, r := range reports {
+		if _, err := fmt.Fprintf(w, "%-32s %5d %8d %6d %10.1f %10s %10s %10s %10.0f %12.0f  %s\n",
+			r.Name, r.Concurrency, r.Requests, r.Errors, r.Throughput,
+			roundDuration(r.P50), roundDuration(r.P95), roundDuration(r.P99),
+			r.AllocsPerOp, r.BytesPerOp, formatCalls(r.BackendCallsPerOp)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// formatCalls formats backend calls per request as "kserve=1 prometheus=35"
+func formatCalls(calls map[string]float64) string {
+	names := make([]string, 0, len(calls))
+	for name := range calls {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = name + "=" + strconv.FormatFloat(calls[name], 'g', 4, 64)
+	}
+	return strings.Join(parts, " ")
+}
+
+// roundDuration shortens a latency for display
+func roundDuration(d time.Duration) time.Duration {
+	switch {
+	case d >= time.Second:
+		return d.Round(time.Millisecond)
+	case d >= time.Millisecond:
+		return d.Round(10 * time.Microsecond)
+	default:
+		return d.Round(time.Microsecond)
+	}
+}
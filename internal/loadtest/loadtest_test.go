@@ -0,0 +1,99 @@
+package loadtest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/integrations"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/kserve"
+)
+
+func TestRun(t *testing.T) {
+	var calls atomic.Int64
+	report, err := Run(context.Background(), Config{Name: "counted", Concurrency: 4, Requests: 100}, func(context.Context) error {
+		if calls.Add(1)%10 == 0 {
+			return errors.New("boom")
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), calls.Load())
+	assert.Equal(t, 100, report.Requests)
+	assert.Equal(t, 10, report.Errors)
+	assert.Equal(t, "boom", report.FirstError)
+	assert.Positive(t, report.Throughput)
+	assert.LessOrEqual(t, report.P50, report.P99)
+	assert.LessOrEqual(t, report.P99, report.Max)
+
+	// Duration-bounded runs stop on time
+	report, err = Run(context.Background(), Config{Name: "timed", Concurrency: 2, Duration: 50 * time.Millisecond}, func(ctx context.Context) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(5 * time.Millisecond):
+			return nil
+		}
+	})
+	require.NoError(t, err)
+	assert.Positive(t, report.Requests)
+	assert.Zero(t, report.Errors, "requests cut off by the end of the run are not errors")
+
+	_, err = Run(context.Background(), Config{Name: "unbounded"}, func(context.Context) error { return nil })
+	assert.Error(t, err)
+
+	var out bytes.Buffer
+	report.BackendCallsPerOp = map[string]float64{"prometheus": 35, "kserve": 1}
+	require.NoError(t, WriteText(&out, []*Report{report}))
+	assert.True(t, strings.HasPrefix(out.String(), "NAME"))
+	assert.Contains(t, out.String(), "timed")
+	assert.Contains(t, out.String(), "kserve=1 prometheus=35")
+}
+
+func TestMocks(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	ctx := context.Background()
+
+	prom := MockPrometheus(MockOptions{})
+	defer prom.Close()
+	value, err := integrations.NewPrometheusClient(prom.URL, 5*time.Second, log).Query(ctx, "up")
+	require.NoError(t, err)
+	assert.Equal(t, 0.5, value)
+	assert.Equal(t, int64(1), prom.Requests())
+	prom.Reset()
+	assert.Zero(t, prom.Requests())
+
+	model := MockKServe(MockOptions{Latency: time.Millisecond})
+	defer model.Close()
+	os.Setenv("KSERVE_ANOMALY_DETECTOR_SERVICE", "anomaly-detector-predictor")
+	defer os.Unsetenv("KSERVE_ANOMALY_DETECTOR_SERVICE")
+	os.Setenv("KSERVE_PREDICTIVE_ANALYTICS_SERVICE", "predictive-analytics-predictor")
+	defer os.Unsetenv("KSERVE_PREDICTIVE_ANALYTICS_SERVICE")
+	client, err := kserve.NewProxyClient(kserve.ProxyConfig{Namespace: "test-ns", Timeout: 5 * time.Second}, log)
+	require.NoError(t, err)
+	rollouts, err := kserve.NewRollouts(map[string]kserve.ModelRollout{
+		"anomaly-detector":     {Versions: map[string]string{"v1": model.URL}, Pinned: "v1"},
+		"predictive-analytics": {Versions: map[string]string{"v1": model.URL}, Pinned: "v1"},
+	})
+	require.NoError(t, err)
+	client.SetRollouts(rollouts)
+
+	detected, err := client.Predict(ctx, "anomaly-detector", [][]float64{make([]float64, 45)})
+	require.NoError(t, err)
+	assert.Equal(t, []int{-1}, detected.Predictions)
+
+	forecast, err := client.PredictFlexible(ctx, "predictive-analytics", [][]float64{make([]float64, ForecastFeatures)})
+	require.NoError(t, err)
+	assert.Equal(t, "forecast", forecast.Type)
+	assert.Equal(t, int64(2), model.Requests())
+}
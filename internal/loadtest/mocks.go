@@ -0,0 +1,111 @@
+// Package loadtest provides synthetic backends and a load runner for measuring the
+// analysis path without a cluster.
+//
+// MockPrometheus and MockKServe stand in for Prometheus and the KServe predictors
+// with a configurable latency, so benchmarks and the load harness in test/load
+// exercise the same feature engineering, inference and post-processing code the
+// engine runs in production. Run drives a request function concurrently and
+// reports throughput, latency percentiles and allocations per request.
+package loadtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"time"
+)
+
+// MockOptions configure a mock backend
+type MockOptions struct {
+	// Latency delays every response, modelling the network and query time of the
+	// real backend. Zero responds immediately.
+	Latency time.Duration
+}
+
+// Mock is a synthetic backend served over HTTP
+type Mock struct {
+	*httptest.Server
+	requests atomic.Int64
+}
+
+// Requests returns the number of requests served
+func (m *Mock) Requests() int64 {
+	return m.requests.Load()
+}
+
+// Reset clears the request count
+func (m *Mock) Reset() {
+	m.requests.Store(0)
+}
+
+// serve counts a request and waits for the configured latency
+func (m *Mock) serve(opts MockOptions) {
+	m.requests.Add(1)
+	if opts.Latency > 0 {
+		time.Sleep(opts.Latency)
+	}
+}
+
+// ForecastFeatures is the width of predictive-analytics model instances: hour, day of
+// week and the CPU and memory rolling means
+const ForecastFeatures = 4
+
+// promVectorResponse is the body of every mock Prometheus query
+var promVectorResponse = []byte(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1700000000,"0.5"]}]}}`)
+
+// MockPrometheus starts a Prometheus mock answering every instant query with a
+// single sample. Close it when done.
+func MockPrometheus(opts MockOptions) *Mock {
+	mock := &Mock{}
+	mock.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mock.serve(opts)
+		if r.URL.Path != "/api/v1/query" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(promVectorResponse)
+	}))
+	return mock
+}
+
+// MockKServe starts a KServe predictor mock. Instances with ForecastFeatures values,
+// the inputs of the predictive-analytics model, get a CPU and memory forecast; all
+// others get anomaly-detector predictions flagging the instance as anomalous (-1), so
+// post-processing runs in full. Close it when done.
+func MockKServe(opts MockOptions) *Mock {
+	mock := &Mock{}
+	mock.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mock.serve(opts)
+		var req struct {
+			Instances [][]float64 `json:"instances"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("invalid request: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		var predictions interface{}
+		if len(req.Instances) > 0 && len(req.Instances[0]) == ForecastFeatures {
+			forecasts := make([][]float64, len(req.Instances))
+			for i := range forecasts {
+				forecasts[i] = []float64{0.7, 0.75}
+			}
+			predictions = forecasts
+		} else {
+			flags := make([]int, len(req.Instances))
+			for i := range flags {
+				flags[i] = -1
+			}
+			predictions = flags
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"predictions":   predictions,
+			"model_version": "loadtest",
+		})
+	}))
+	return mock
+}
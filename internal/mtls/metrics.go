@@ -0,0 +1,22 @@
+package mtls
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// RejectionsTotal counts API clients rejected by mutual TLS, by reason
+	RejectionsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "coordination_engine_mtls_rejections_total",
+			Help: "Total number of API clients rejected by mutual TLS, by reason",
+		},
+		[]string{"reason"},
+	)
+)
+
+// RecordRejection records a rejected client
+func RecordRejection(reason string) {
+	RejectionsTotal.WithLabelValues(reason).Inc()
+}
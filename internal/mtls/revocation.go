@@ -0,0 +1,218 @@
+package mtls
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+)
+
+// errRevoked is returned when an OCSP responder reports a certificate as revoked
+var errRevoked = errors.New("client certificate is revoked")
+
+// revocationList is the set of revoked serial numbers per issuer
+type revocationList struct {
+	// serials maps the issuer's raw subject to its revoked serial numbers
+	serials map[string]map[string]bool
+}
+
+// revoked reports whether the issuer's CRL lists cert
+func (l *revocationList) revoked(cert, issuer *x509.Certificate) bool {
+	return l.serials[string(issuer.RawSubject)][cert.SerialNumber.String()]
+}
+
+// loadRevocationList reads PEM or DER CRLs and verifies each against its issuer in
+// the client CA bundle
+func loadRevocationList(path, caPath string) (*revocationList, error) {
+	data, err := os.ReadFile(path) //#nosec G304 -- path comes from operator configuration
+	if err != nil {
+		return nil, err
+	}
+	issuers, err := loadCertificates(caPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var ders [][]byte
+	if bytes.Contains(data, []byte("-----BEGIN")) {
+		for {
+			var block *pem.Block
+			block, data = pem.Decode(data)
+			if block == nil {
+				break
+			}
+			if block.Type == "X509 CRL" {
+				ders = append(ders, block.Bytes)
+			}
+		}
+		if len(ders) == 0 {
+			return nil, fmt.Errorf("%s contains no X509 CRL blocks", path)
+		}
+	} else {
+		ders = [][]byte{data}
+	}
+
+	list := &revocationList{serials: make(map[string]map[string]bool)}
+	for _, der := range ders {
+		crl, err := x509.ParseRevocationList(der)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CRL in %s: %w", path, err)
+		}
+		issuer := findIssuer(issuers, crl.RawIssuer)
+		if issuer == nil {
+			return nil, fmt.Errorf("CRL issuer %s is not in the client CA bundle", crl.Issuer)
+		}
+		if err := crl.CheckSignatureFrom(issuer); err != nil {
+			return nil, fmt.Errorf("CRL signature from %s is invalid: %w", crl.Issuer, err)
+		}
+
+		serials := list.serials[string(crl.RawIssuer)]
+		if serials == nil {
+			serials = make(map[string]bool)
+			list.serials[string(crl.RawIssuer)] = serials
+		}
+		for _, entry := range crl.RevokedCertificateEntries {
+			serials[entry.SerialNumber.String()] = true
+		}
+	}
+	return list, nil
+}
+
+// loadCertificates reads every certificate in a PEM bundle
+func loadCertificates(path string) ([]*x509.Certificate, error) {
+	data, err := os.ReadFile(path) //#nosec G304 -- path comes from operator configuration
+	if err != nil {
+		return nil, err
+	}
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid certificate in %s: %w", path, err)
+		}
+		certs = append(certs, cert)
+	}
+	return certs, nil
+}
+
+func findIssuer(certs []*x509.Certificate, rawSubject []byte) *x509.Certificate {
+	for _, cert := range certs {
+		if bytes.Equal(cert.RawSubject, rawSubject) {
+			return cert
+		}
+	}
+	return nil
+}
+
+// defaultOCSPTimeout bounds an OCSP request, which runs during the TLS handshake
+const defaultOCSPTimeout = 5 * time.Second
+
+// defaultOCSPCacheTTL is used for responses without a next update time
+const defaultOCSPCacheTTL = 5 * time.Minute
+
+// ocspChecker queries OCSP responders and caches their answers until the next update
+type ocspChecker struct {
+	httpClient *http.Client
+	timeout    time.Duration
+
+	mu    sync.Mutex
+	cache map[string]ocspResult
+}
+
+type ocspResult struct {
+	status  int
+	expires time.Time
+}
+
+func newOCSPChecker(timeout time.Duration) *ocspChecker {
+	return &ocspChecker{
+		httpClient: &http.Client{Timeout: timeout},
+		timeout:    timeout,
+		cache:      make(map[string]ocspResult),
+	}
+}
+
+// check returns errRevoked for revoked certificates, and an error when the status is
+// unknown or the responder cannot be reached
+func (c *ocspChecker) check(cert, issuer *x509.Certificate) error {
+	key := string(issuer.RawSubject) + "/" + cert.SerialNumber.String()
+
+	c.mu.Lock()
+	result, ok := c.cache[key]
+	c.mu.Unlock()
+	if !ok || time.Now().After(result.expires) {
+		var err error
+		result, err = c.query(cert, issuer)
+		if err != nil {
+			return err
+		}
+		c.mu.Lock()
+		c.cache[key] = result
+		c.mu.Unlock()
+	}
+
+	switch result.status {
+	case ocsp.Good:
+		return nil
+	case ocsp.Revoked:
+		return errRevoked
+	default:
+		return fmt.Errorf("OCSP responder does not know client certificate %s", cert.SerialNumber)
+	}
+}
+
+// query asks the certificate's first OCSP responder for its status
+func (c *ocspChecker) query(cert, issuer *x509.Certificate) (ocspResult, error) {
+	request, err := ocsp.CreateRequest(cert, issuer, nil)
+	if err != nil {
+		return ocspResult{}, fmt.Errorf("failed to create OCSP request: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cert.OCSPServer[0], bytes.NewReader(request))
+	if err != nil {
+		return ocspResult{}, fmt.Errorf("invalid OCSP responder URL: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/ocsp-request")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return ocspResult{}, fmt.Errorf("OCSP request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return ocspResult{}, fmt.Errorf("OCSP responder returned status %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return ocspResult{}, fmt.Errorf("failed to read OCSP response: %w", err)
+	}
+
+	parsed, err := ocsp.ParseResponseForCert(body, cert, issuer)
+	if err != nil {
+		return ocspResult{}, fmt.Errorf("invalid OCSP response: %w", err)
+	}
+	expires := parsed.NextUpdate
+	if expires.IsZero() {
+		expires = time.Now().Add(defaultOCSPCacheTTL)
+	}
+	return ocspResult{status: parsed.Status, expires: expires}, nil
+}
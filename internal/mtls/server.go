@@ -0,0 +1,384 @@
+// Package mtls serves the HTTP API over TLS and optionally requires mutual TLS.
+//
+// With a client CA bundle, callers must present a certificate issued by one of those
+// CAs. Certificates are checked against a certificate revocation list and their
+// issuer's OCSP responder, and can be limited to an allow-list of client names, so
+// only trusted in-cluster services can call the engine even when the route or ingress
+// is bypassed. The serving certificate, CA bundle and CRL are re-read when their files
+// change, e.g. when the OpenShift service CA rotates them.
+package mtls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Rejection reasons reported in metrics
+const (
+	ReasonNoCertificate   = "no_certificate"
+	ReasonUntrusted       = "untrusted"
+	ReasonRevoked         = "revoked"
+	ReasonOCSPUnavailable = "ocsp_unavailable"
+	ReasonNotAllowed      = "not_allowed"
+)
+
+// Options configures the TLS server
+type Options struct {
+	// CertFile and KeyFile are the PEM serving certificate and key
+	CertFile string
+	KeyFile  string
+
+	// ClientCAFile is a PEM bundle of CAs that issue client certificates (empty disables mTLS)
+	ClientCAFile string
+
+	// CRLFile holds PEM or DER revocation lists issued by the client CAs
+	CRLFile string
+
+	// OCSP checks client certificates that name an OCSP responder
+	OCSP bool
+
+	// OCSPFailOpen accepts client certificates when the responder cannot be reached or
+	// answers "unknown"; by default such connections are rejected
+	OCSPFailOpen bool
+
+	// AllowedClients limits client certificates to these DNS names or common names
+	AllowedClients []string
+}
+
+// Server holds the TLS state of the API server
+type Server struct {
+	opts    Options
+	allowed map[string]bool
+	log     *logrus.Logger
+
+	cert      *watchedFile[*tls.Certificate]
+	clientCAs *watchedFile[*x509.CertPool]
+	crl       *watchedFile[*revocationList]
+	ocsp      *ocspChecker
+}
+
+// New loads the serving certificate and, with a client CA bundle, the mTLS settings.
+// Every configured file must be readable and valid.
+func New(opts Options, log *logrus.Logger) (*Server, error) {
+	if opts.CertFile == "" || opts.KeyFile == "" {
+		return nil, errors.New("certificate and key files are required")
+	}
+	if opts.ClientCAFile == "" && (opts.CRLFile != "" || opts.OCSP || len(opts.AllowedClients) > 0) {
+		return nil, errors.New("revocation checks and allowed clients require a client CA bundle")
+	}
+
+	s := &Server{opts: opts, log: log}
+	s.cert = newWatchedFile(log, func() (*tls.Certificate, error) {
+		cert, err := tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		return &cert, nil
+	}, opts.CertFile, opts.KeyFile)
+	if err := s.cert.load(); err != nil {
+		return nil, fmt.Errorf("failed to load serving certificate: %w", err)
+	}
+
+	if opts.ClientCAFile == "" {
+		return s, nil
+	}
+
+	s.clientCAs = newWatchedFile(log, func() (*x509.CertPool, error) {
+		return loadCertPool(opts.ClientCAFile)
+	}, opts.ClientCAFile)
+	if err := s.clientCAs.load(); err != nil {
+		return nil, fmt.Errorf("failed to load client CA bundle: %w", err)
+	}
+
+	if opts.CRLFile != "" {
+		s.crl = newWatchedFile(log, func() (*revocationList, error) {
+			return loadRevocationList(opts.CRLFile, opts.ClientCAFile)
+		}, opts.CRLFile, opts.ClientCAFile)
+		if err := s.crl.load(); err != nil {
+			return nil, fmt.Errorf("failed to load certificate revocation list: %w", err)
+		}
+	}
+
+	if opts.OCSP {
+		s.ocsp = newOCSPChecker(defaultOCSPTimeout)
+	}
+
+	if len(opts.AllowedClients) > 0 {
+		s.allowed = make(map[string]bool, len(opts.AllowedClients))
+		for _, name := range opts.AllowedClients {
+			s.allowed[strings.ToLower(name)] = true
+		}
+	}
+	return s, nil
+}
+
+// MutualTLS returns true if client certificates are required
+func (s *Server) MutualTLS() bool {
+	return s.clientCAs != nil
+}
+
+// TLSConfig returns the configuration for the API server. Client certificates are
+// verified when presented; RequireClientCert rejects requests without one so that
+// health probes, which cannot present a certificate, keep working.
+func (s *Server) TLSConfig() *tls.Config {
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return s.cert.get(), nil
+		},
+	}
+	if !s.MutualTLS() {
+		return config
+	}
+
+	// Verification runs in VerifyPeerCertificate so that the CA bundle can be reloaded
+	config.ClientAuth = tls.RequestClientCert
+	config.VerifyPeerCertificate = func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		if len(rawCerts) == 0 {
+			return nil
+		}
+		return s.verifyClient(rawCerts)
+	}
+	return config
+}
+
+// StrictTLSConfig returns the configuration for listeners without health probes, such
+// as the gRPC API. With mutual TLS every connection must present a verified client
+// certificate: connections without one fail the handshake.
+func (s *Server) StrictTLSConfig() *tls.Config {
+	config := s.TLSConfig()
+	if !s.MutualTLS() {
+		return config
+	}
+
+	config.VerifyPeerCertificate = func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		if len(rawCerts) == 0 {
+			RecordRejection(ReasonNoCertificate)
+			s.log.Warn("TLS connection without client certificate rejected")
+			return errors.New("client certificate required")
+		}
+		return s.verifyClient(rawCerts)
+	}
+	return config
+}
+
+// verifyClient verifies a client certificate chain against the client CAs, the CRL,
+// OCSP and the allowed client names
+func (s *Server) verifyClient(rawCerts [][]byte) error {
+	certs := make([]*x509.Certificate, 0, len(rawCerts))
+	for _, raw := range rawCerts {
+		cert, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return fmt.Errorf("invalid client certificate: %w", err)
+		}
+		certs = append(certs, cert)
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	leaf := certs[0]
+	chains, err := leaf.Verify(x509.VerifyOptions{
+		Roots:         s.clientCAs.get(),
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	if err != nil {
+		s.reject(leaf, ReasonUntrusted, "Client certificate not trusted")
+		return fmt.Errorf("client certificate not trusted: %w", err)
+	}
+	chain := chains[0]
+
+	if s.crl != nil {
+		crl := s.crl.get()
+		for i := 0; i < len(chain)-1; i++ {
+			if crl.revoked(chain[i], chain[i+1]) {
+				s.reject(leaf, ReasonRevoked, "Client certificate revoked by CRL")
+				return fmt.Errorf("client certificate %s is revoked", chain[i].Subject)
+			}
+		}
+	}
+
+	if s.ocsp != nil && len(chain) > 1 && len(leaf.OCSPServer) > 0 {
+		if err := s.ocsp.check(leaf, chain[1]); err != nil {
+			if errors.Is(err, errRevoked) {
+				s.reject(leaf, ReasonRevoked, "Client certificate revoked by OCSP responder")
+				return err
+			}
+			if !s.opts.OCSPFailOpen {
+				s.reject(leaf, ReasonOCSPUnavailable, "OCSP check failed for client certificate")
+				return err
+			}
+			s.log.WithError(err).WithField("subject", leaf.Subject.String()).
+				Warn("OCSP check failed, accepting client certificate (fail open)")
+		}
+	}
+
+	if s.allowed != nil && !s.isAllowed(leaf) {
+		s.reject(leaf, ReasonNotAllowed, "Client certificate not in allowed clients")
+		return fmt.Errorf("client %s is not allowed", leaf.Subject)
+	}
+	return nil
+}
+
+// isAllowed reports whether a DNS name or the common name is in the allowed clients
+func (s *Server) isAllowed(cert *x509.Certificate) bool {
+	for _, name := range cert.DNSNames {
+		if s.allowed[strings.ToLower(name)] {
+			return true
+		}
+	}
+	return cert.Subject.CommonName != "" && s.allowed[strings.ToLower(cert.Subject.CommonName)]
+}
+
+// reject logs and counts a rejected client certificate
+func (s *Server) reject(cert *x509.Certificate, reason, message string) {
+	RecordRejection(reason)
+	s.log.WithFields(logrus.Fields{
+		"subject": cert.Subject.String(),
+		"serial":  cert.SerialNumber.String(),
+		"reason":  reason,
+	}).Warn(message)
+}
+
+// RequireClientCert creates a middleware that rejects requests without a verified
+// client certificate, except for the exempt paths (health probes)
+func (s *Server) RequireClientCert(exempt ...string) func(http.Handler) http.Handler {
+	exemptPaths := make(map[string]bool, len(exempt))
+	for _, path := range exempt {
+		exemptPaths[path] = true
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if exemptPaths[r.URL.Path] || (r.TLS != nil && len(r.TLS.PeerCertificates) > 0) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			RecordRejection(ReasonNoCertificate)
+			s.log.WithFields(logrus.Fields{
+				"method":      r.Method,
+				"path":        r.URL.Path,
+				"remote_addr": r.RemoteAddr,
+			}).Warn("Request without client certificate rejected")
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			if err := json.NewEncoder(w).Encode(map[string]string{
+				"status": "error",
+				"error":  "client certificate required",
+			}); err != nil {
+				s.log.WithError(err).Error("Failed to encode client certificate error response")
+			}
+		})
+	}
+}
+
+// loadCertPool reads a PEM CA bundle
+func loadCertPool(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path) //#nosec G304 -- path comes from operator configuration
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("%s contains no PEM certificates", path)
+	}
+	return pool, nil
+}
+
+// watchedFile caches a value loaded from files and reloads it when any file's
+// modification time changes. A failed reload keeps the previous value.
+type watchedFile[T any] struct {
+	paths []string
+	parse func() (T, error)
+	log   *logrus.Logger
+
+	mu        sync.Mutex
+	value     T
+	modTimes  []time.Time
+	checkedAt time.Time
+}
+
+// reloadCheckInterval bounds how often file modification times are checked
+const reloadCheckInterval = 10 * time.Second
+
+func newWatchedFile[T any](log *logrus.Logger, parse func() (T, error), paths ...string) *watchedFile[T] {
+	return &watchedFile[T]{paths: paths, parse: parse, log: log}
+}
+
+// load reads the files, failing if they are invalid
+func (f *watchedFile[T]) load() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	modTimes, err := f.stat()
+	if err != nil {
+		return err
+	}
+	value, err := f.parse()
+	if err != nil {
+		return err
+	}
+	f.value, f.modTimes, f.checkedAt = value, modTimes, time.Now()
+	return nil
+}
+
+// get returns the current value, reloading it if the files changed
+func (f *watchedFile[T]) get() T {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if time.Since(f.checkedAt) < reloadCheckInterval {
+		return f.value
+	}
+	f.checkedAt = time.Now()
+
+	modTimes, err := f.stat()
+	if err != nil || sameTimes(modTimes, f.modTimes) {
+		return f.value
+	}
+	value, err := f.parse()
+	if err != nil {
+		f.log.WithError(err).WithField("files", f.paths).Error("Failed to reload TLS file, keeping previous version")
+		return f.value
+	}
+	f.value, f.modTimes = value, modTimes
+	f.log.WithField("files", f.paths).Info("Reloaded TLS file")
+	return f.value
+}
+
+func (f *watchedFile[T]) stat() ([]time.Time, error) {
+	modTimes := make([]time.Time, 0, len(f.paths))
+	for _, path := range f.paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		modTimes = append(modTimes, info.ModTime())
+	}
+	return modTimes, nil
+}
+
+func sameTimes(a, b []time.Time) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Equal(b[i]) {
+			return false
+		}
+	}
+	return true
+}
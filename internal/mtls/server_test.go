@@ -0,0 +1,333 @@
+package mtls
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ocsp"
+)
+
+// testPKI is a CA writing its certificate, a serving certificate and CRLs to a temp dir
+type testPKI struct {
+	t      *testing.T
+	dir    string
+	ca     *x509.Certificate
+	caKey  *ecdsa.PrivateKey
+	serial int64
+}
+
+func newTestPKI(t *testing.T) *testPKI {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	ca, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	p := &testPKI{t: t, dir: t.TempDir(), ca: ca, caKey: key, serial: 1}
+	p.writePEM("ca.pem", "CERTIFICATE", der)
+
+	serving := p.issue("localhost", x509.ExtKeyUsageServerAuth, "")
+	p.writePEM("tls.crt", "CERTIFICATE", serving.Certificate[0])
+	keyDER, err := x509.MarshalECPrivateKey(serving.PrivateKey.(*ecdsa.PrivateKey))
+	require.NoError(t, err)
+	p.writePEM("tls.key", "EC PRIVATE KEY", keyDER)
+	return p
+}
+
+func (p *testPKI) path(name string) string {
+	return filepath.Join(p.dir, name)
+}
+
+func (p *testPKI) writePEM(name, blockType string, der []byte) {
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	require.NoError(p.t, os.WriteFile(p.path(name), data, 0o600))
+}
+
+// issue creates a certificate for name signed by the CA
+func (p *testPKI) issue(name string, usage x509.ExtKeyUsage, ocspURL string) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(p.t, err)
+	p.serial++
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(p.serial),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	if name == "localhost" {
+		template.IPAddresses = []net.IP{net.ParseIP("127.0.0.1")}
+	}
+	if ocspURL != "" {
+		template.OCSPServer = []string{ocspURL}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, p.ca, &key.PublicKey, p.caKey)
+	require.NoError(p.t, err)
+	leaf, err := x509.ParseCertificate(der)
+	require.NoError(p.t, err)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
+
+// revoke writes a CRL listing the certificates
+func (p *testPKI) revoke(certs ...tls.Certificate) {
+	list := &x509.RevocationList{
+		Number:     big.NewInt(1),
+		ThisUpdate: time.Now().Add(-time.Minute),
+		NextUpdate: time.Now().Add(time.Hour),
+	}
+	for _, cert := range certs {
+		list.RevokedCertificateEntries = append(list.RevokedCertificateEntries, x509.RevocationListEntry{
+			SerialNumber:   cert.Leaf.SerialNumber,
+			RevocationTime: time.Now(),
+		})
+	}
+	der, err := x509.CreateRevocationList(rand.Reader, list, p.ca, p.caKey)
+	require.NoError(p.t, err)
+	p.writePEM("ca.crl", "X509 CRL", der)
+}
+
+// ocspResponder answers every request with status for the requested serial
+func (p *testPKI) ocspResponder(status int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(p.t, err)
+		req, err := ocsp.ParseRequest(body)
+		require.NoError(p.t, err)
+		resp, err := ocsp.CreateResponse(p.ca, p.ca, ocsp.Response{
+			Status:       status,
+			SerialNumber: req.SerialNumber,
+			ThisUpdate:   time.Now().Add(-time.Minute),
+			NextUpdate:   time.Now().Add(time.Hour),
+			RevokedAt:    time.Now().Add(-time.Minute),
+		}, p.caKey)
+		require.NoError(p.t, err)
+		_, _ = w.Write(resp)
+	}))
+}
+
+// serve starts an HTTPS server with the mTLS configuration and returns its URL
+func serve(t *testing.T, s *Server) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	handler := s.RequireClientCert("/health")(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server := &http.Server{Handler: handler, TLSConfig: s.TLSConfig(), ReadHeaderTimeout: 5 * time.Second}
+	go func() { _ = server.ServeTLS(listener, "", "") }()
+	t.Cleanup(func() { _ = server.Close() })
+	return "https://" + listener.Addr().String()
+}
+
+// get calls path with an optional client certificate
+func (p *testPKI) get(url string, cert *tls.Certificate) (int, error) {
+	roots := x509.NewCertPool()
+	roots.AddCert(p.ca)
+	config := &tls.Config{RootCAs: roots, MinVersion: tls.VersionTLS12}
+	if cert != nil {
+		config.Certificates = []tls.Certificate{*cert}
+	}
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: config}, Timeout: 5 * time.Second}
+	resp, err := client.Get(url)
+	if err != nil {
+		return 0, err
+	}
+	_ = resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+func newTestServer(t *testing.T, opts Options) *Server {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	s, err := New(opts, log)
+	require.NoError(t, err)
+	return s
+}
+
+func TestServer_MutualTLS(t *testing.T) {
+	pki := newTestPKI(t)
+	trusted := pki.issue("console-plugin", x509.ExtKeyUsageClientAuth, "")
+	revoked := pki.issue("old-assistant", x509.ExtKeyUsageClientAuth, "")
+	other := pki.issue("unknown-service", x509.ExtKeyUsageClientAuth, "")
+	serverUsage := pki.issue("not-a-client", x509.ExtKeyUsageServerAuth, "")
+	pki.revoke(revoked)
+
+	s := newTestServer(t, Options{
+		CertFile:       pki.path("tls.crt"),
+		KeyFile:        pki.path("tls.key"),
+		ClientCAFile:   pki.path("ca.pem"),
+		CRLFile:        pki.path("ca.crl"),
+		AllowedClients: []string{"console-plugin", "old-assistant"},
+	})
+	assert.True(t, s.MutualTLS())
+	url := serve(t, s)
+
+	status, err := pki.get(url+"/api/v1/incidents", &trusted)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+
+	status, err = pki.get(url+"/api/v1/incidents", nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, status, "requests without a certificate are rejected")
+
+	status, err = pki.get(url+"/health", nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status, "health probes are exempt")
+
+	_, err = pki.get(url+"/api/v1/incidents", &revoked)
+	assert.Error(t, err, "revoked by CRL")
+	_, err = pki.get(url+"/api/v1/incidents", &other)
+	assert.Error(t, err, "not an allowed client")
+	_, err = pki.get(url+"/api/v1/incidents", &serverUsage)
+	assert.Error(t, err, "certificate is not valid for client authentication")
+}
+
+func TestServer_StrictTLSConfig(t *testing.T) {
+	pki := newTestPKI(t)
+	trusted := pki.issue("console-plugin", x509.ExtKeyUsageClientAuth, "")
+	revoked := pki.issue("old-assistant", x509.ExtKeyUsageClientAuth, "")
+	pki.revoke(revoked)
+
+	s := newTestServer(t, Options{
+		CertFile:     pki.path("tls.crt"),
+		KeyFile:      pki.path("tls.key"),
+		ClientCAFile: pki.path("ca.pem"),
+		CRLFile:      pki.path("ca.crl"),
+	})
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", s.StrictTLSConfig())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				_ = conn.(*tls.Conn).Handshake()
+				_ = conn.Close()
+			}()
+		}
+	}()
+
+	// handshake dials the listener and reports whether the server accepted the client
+	handshake := func(cert *tls.Certificate) error {
+		roots := x509.NewCertPool()
+		roots.AddCert(pki.ca)
+		config := &tls.Config{RootCAs: roots, MinVersion: tls.VersionTLS12, ServerName: "localhost"}
+		if cert != nil {
+			config.Certificates = []tls.Certificate{*cert}
+		}
+		conn, err := tls.Dial("tcp", listener.Addr().String(), config)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		// TLS 1.3 clients learn about a rejected certificate on their first read
+		_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		_, err = conn.Read(make([]byte, 1))
+		if err == io.EOF {
+			return nil
+		}
+		return err
+	}
+
+	assert.NoError(t, handshake(&trusted))
+	assert.Error(t, handshake(nil), "connections without a certificate are rejected")
+	assert.Error(t, handshake(&revoked), "revoked by CRL")
+}
+
+func TestServer_OCSP(t *testing.T) {
+	pki := newTestPKI(t)
+	good := pki.ocspResponder(ocsp.Good)
+	defer good.Close()
+	revoked := pki.ocspResponder(ocsp.Revoked)
+	defer revoked.Close()
+	unavailable := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer unavailable.Close()
+
+	opts := Options{
+		CertFile:     pki.path("tls.crt"),
+		KeyFile:      pki.path("tls.key"),
+		ClientCAFile: pki.path("ca.pem"),
+		OCSP:         true,
+	}
+	url := serve(t, newTestServer(t, opts))
+
+	goodCert := pki.issue("console-plugin", x509.ExtKeyUsageClientAuth, good.URL)
+	status, err := pki.get(url, &goodCert)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+
+	revokedCert := pki.issue("old-assistant", x509.ExtKeyUsageClientAuth, revoked.URL)
+	_, err = pki.get(url, &revokedCert)
+	assert.Error(t, err, "revoked by OCSP")
+
+	unavailableCert := pki.issue("ai-assistant", x509.ExtKeyUsageClientAuth, unavailable.URL)
+	_, err = pki.get(url, &unavailableCert)
+	assert.Error(t, err, "fails closed by default")
+
+	opts.OCSPFailOpen = true
+	status, err = pki.get(serve(t, newTestServer(t, opts)), &unavailableCert)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status, "fail open accepts the certificate")
+}
+
+func TestNew_Errors(t *testing.T) {
+	pki := newTestPKI(t)
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	_, err := New(Options{}, log)
+	assert.Error(t, err, "certificate is required")
+
+	_, err = New(Options{CertFile: pki.path("tls.crt"), KeyFile: pki.path("tls.key"), OCSP: true}, log)
+	assert.Error(t, err, "OCSP requires a client CA bundle")
+
+	_, err = New(Options{CertFile: pki.path("tls.crt"), KeyFile: pki.path("ca.pem")}, log)
+	assert.Error(t, err, "key does not match")
+
+	require.NoError(t, os.WriteFile(pki.path("bad.crl"), []byte("not a crl"), 0o600))
+	_, err = New(Options{
+		CertFile:     pki.path("tls.crt"),
+		KeyFile:      pki.path("tls.key"),
+		ClientCAFile: pki.path("ca.pem"),
+		CRLFile:      pki.path("bad.crl"),
+	}, log)
+	assert.Error(t, err)
+
+	s, err := New(Options{CertFile: pki.path("tls.crt"), KeyFile: pki.path("tls.key")}, log)
+	require.NoError(t, err)
+	assert.False(t, s.MutualTLS())
+	assert.Equal(t, tls.NoClientCert, s.TLSConfig().ClientAuth)
+}
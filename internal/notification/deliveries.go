@@ -0,0 +1,248 @@
+package notification
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/api/validation"
+)
+
+// DefaultDeliveryLogSize is the number of deliveries kept in memory
+const DefaultDeliveryLogSize = 500
+
+// DeliveryStatus is the state of a webhook delivery
+type DeliveryStatus string
+
+// Delivery statuses
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliveryRetrying  DeliveryStatus = "retrying"
+	DeliveryDelivered DeliveryStatus = "delivered"
+	DeliveryFailed    DeliveryStatus = "failed"
+)
+
+// Delivery records the attempts to send one event to one target
+type Delivery struct {
+	ID             string         `json:"id"`
+	EventID        string         `json:"event_id"`
+	EventType      EventType      `json:"event_type"`
+	Target         string         `json:"target"`
+	URL            string         `json:"url"`
+	Status         DeliveryStatus `json:"status"`
+	Attempts       int            `json:"attempts"`
+	LastStatusCode int            `json:"last_status_code,omitempty"`
+	LastError      string         `json:"last_error,omitempty"`
+	NextAttemptAt  *time.Time     `json:"next_attempt_at,omitempty"`
+	DeliveredAt    *time.Time     `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+}
+
+// DeliveryFilter selects deliveries from the log; empty fields match everything
+type DeliveryFilter struct {
+	Status    DeliveryStatus
+	Target    string
+	EventType EventType
+	Limit     int
+}
+
+// DeliveryLog keeps the most recent deliveries in memory, dropping the oldest
+// once it is full
+type DeliveryLog struct {
+	mu         sync.RWMutex
+	size       int
+	order      []string
+	deliveries map[string]*Delivery
+}
+
+// NewDeliveryLog creates a delivery log holding at most size deliveries
+func NewDeliveryLog(size int) *DeliveryLog {
+	if size <= 0 {
+		size = DefaultDeliveryLogSize
+	}
+	return &DeliveryLog{
+		size:       size,
+		deliveries: make(map[string]*Delivery),
+	}
+}
+
+// Add records a new pending delivery and returns a copy with its ID set
+func (l *DeliveryLog) Add(d *Delivery) Delivery {
+	now := time.Now().UTC()
+	d.ID = "dlv-" + uuid.New().String()
+	d.Status = DeliveryPending
+	d.CreatedAt = now
+	d.UpdatedAt = now
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.order) >= l.size {
+		delete(l.deliveries, l.order[0])
+		l.order = l.order[1:]
+	}
+	l.order = append(l.order, d.ID)
+	l.deliveries[d.ID] = d
+	return *d
+}
+
+// Get returns a copy of a delivery
+func (l *DeliveryLog) Get(id string) (Delivery, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	d, ok := l.deliveries[id]
+	if !ok {
+		return Delivery{}, false
+	}
+	return *d, true
+}
+
+// List returns matching deliveries, newest first
+func (l *DeliveryLog) List(filter DeliveryFilter) []Delivery {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	results := make([]Delivery, 0)
+	for i := len(l.order) - 1; i >= 0; i-- {
+		d := l.deliveries[l.order[i]]
+		if filter.Status != "" && d.Status != filter.Status {
+			continue
+		}
+		if filter.Target != "" && d.Target != filter.Target {
+			continue
+		}
+		if filter.EventType != "" && d.EventType != filter.EventType {
+			continue
+		}
+		results = append(results, *d)
+		if filter.Limit > 0 && len(results) >= filter.Limit {
+			break
+		}
+	}
+	return results
+}
+
+// recordSuccess marks a delivery as delivered
+func (l *DeliveryLog) recordSuccess(id string, attempts, statusCode int) {
+	l.update(id, func(d *Delivery) {
+		now := time.Now().UTC()
+		d.Status = DeliveryDelivered
+		d.Attempts = attempts
+		d.LastStatusCode = statusCode
+		d.LastError = ""
+		d.NextAttemptAt = nil
+		d.DeliveredAt = &now
+	})
+}
+
+// recordFailure records a failed attempt. A non-zero nextAttempt means the delivery
+// will be retried; otherwise it has failed permanently.
+func (l *DeliveryLog) recordFailure(id string, attempts, statusCode int, err error, nextAttempt time.Time) {
+	l.update(id, func(d *Delivery) {
+		d.Attempts = attempts
+		d.LastStatusCode = statusCode
+		d.LastError = err.Error()
+		if nextAttempt.IsZero() {
+			d.Status = DeliveryFailed
+			d.NextAttemptAt = nil
+		} else {
+			next := nextAttempt.UTC()
+			d.Status = DeliveryRetrying
+			d.NextAttemptAt = &next
+		}
+	})
+}
+
+// update applies fn to a delivery if it is still in the log
+func (l *DeliveryLog) update(id string, fn func(d *Delivery)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	d, ok := l.deliveries[id]
+	if !ok {
+		return
+	}
+	fn(d)
+	d.UpdatedAt = time.Now().UTC()
+}
+
+// RegisterRoutes registers the delivery status API
+func (n *WebhookNotifier) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/api/v1/notifications/deliveries", n.handleListDeliveries).Methods("GET")
+	router.HandleFunc("/api/v1/notifications/deliveries/{id}", n.handleGetDelivery).Methods("GET")
+	n.log.Info("Notification API endpoints registered: /api/v1/notifications/deliveries")
+}
+
+// handleListDeliveries handles GET /api/v1/notifications/deliveries
+// Query parameters status, target, event_type and limit filter the results.
+func (n *WebhookNotifier) handleListDeliveries(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := DeliveryFilter{
+		Status:    DeliveryStatus(query.Get("status")),
+		Target:    query.Get("target"),
+		EventType: EventType(query.Get("event_type")),
+		Limit:     100,
+	}
+
+	var fieldErrs validation.Errors
+	switch filter.Status {
+	case "", DeliveryPending, DeliveryRetrying, DeliveryDelivered, DeliveryFailed:
+	default:
+		fieldErrs.Add("status", validation.ConstraintEnum, filter.Status, "status must be one of pending, retrying, delivered, failed")
+	}
+
+	if limitStr := query.Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > DefaultDeliveryLogSize {
+			fieldErrs.Add("limit", validation.ConstraintRange, limitStr, "limit must be between 1 and 500")
+		} else {
+			filter.Limit = limit
+		}
+	}
+	if len(fieldErrs) > 0 {
+		n.respondError(w, http.StatusBadRequest, fieldErrs.Error(), fieldErrs...)
+		return
+	}
+
+	deliveries := n.deliveries.List(filter)
+	n.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"deliveries": deliveries,
+		"total":      len(deliveries),
+	})
+}
+
+// handleGetDelivery handles GET /api/v1/notifications/deliveries/{id}
+func (n *WebhookNotifier) handleGetDelivery(w http.ResponseWriter, r *http.Request) {
+	delivery, ok := n.deliveries.Get(mux.Vars(r)["id"])
+	if !ok {
+		n.respondError(w, http.StatusNotFound, "delivery not found")
+		return
+	}
+	n.respondJSON(w, http.StatusOK, delivery)
+}
+
+func (n *WebhookNotifier) respondJSON(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		n.log.WithError(err).Error("Failed to encode notification response")
+	}
+}
+
+func (n *WebhookNotifier) respondError(w http.ResponseWriter, statusCode int, message string, fieldErrs ...validation.FieldError) {
+	response := map[string]interface{}{
+		"status": "error",
+		"error":  message,
+	}
+	if len(fieldErrs) > 0 {
+		response["errors"] = fieldErrs
+	}
+	n.respondJSON(w, statusCode, response)
+}
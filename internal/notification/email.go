@@ -0,0 +1,607 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"sigs.k8s.io/yaml"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/storage"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/api/validation"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/capacity"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// DigestPeriod is how often a team receives a digest email
+type DigestPeriod string
+
+// Digest periods
+const (
+	DigestDaily  DigestPeriod = "daily"
+	DigestWeekly DigestPeriod = "weekly"
+)
+
+// window returns the length of time a digest covers
+func (p DigestPeriod) window() time.Duration {
+	if p == DigestWeekly {
+		return 7 * 24 * time.Hour
+	}
+	return 24 * time.Hour
+}
+
+// Digest limits
+const (
+	maxDigestIncidents = 10
+	maxDigestFailures  = 5
+)
+
+// Team is a group of email recipients and the namespaces they own
+type Team struct {
+	Name       string   `json:"name"`
+	Recipients []string `json:"recipients"`
+
+	// Namespaces routes incidents, capacity warnings and workflows to the team; empty matches all
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// Digest is daily, weekly or empty for no digest
+	Digest DigestPeriod `json:"digest,omitempty"`
+
+	// AlertSeverity is the minimum severity emailed immediately (default critical)
+	AlertSeverity models.IncidentSeverity `json:"alert_severity,omitempty"`
+
+	// DisableAlerts turns off immediate alerts, leaving only digests
+	DisableAlerts bool `json:"disable_alerts,omitempty"`
+}
+
+// Validate checks the team configuration
+func (t *Team) Validate() error {
+	if t.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if len(t.Recipients) == 0 {
+		return fmt.Errorf("team %s: at least one recipient is required", t.Name)
+	}
+	for _, rcpt := range t.Recipients {
+		if !strings.Contains(rcpt, "@") {
+			return fmt.Errorf("team %s: invalid recipient %q", t.Name, rcpt)
+		}
+	}
+	if t.Digest != "" && t.Digest != DigestDaily && t.Digest != DigestWeekly {
+		return fmt.Errorf("team %s: digest must be daily or weekly: %q", t.Name, t.Digest)
+	}
+	if t.AlertSeverity != "" && !models.IsValidSeverity(string(t.AlertSeverity)) {
+		return fmt.Errorf("team %s: alert_severity must be one of: low, medium, high, critical", t.Name)
+	}
+	return nil
+}
+
+// owns reports whether the team is responsible for a namespace
+func (t *Team) owns(namespace string) bool {
+	if len(t.Namespaces) == 0 {
+		return true
+	}
+	for _, ns := range t.Namespaces {
+		if ns == "*" || ns == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// ownsIncident reports whether the team is responsible for an incident: it owns the
+// incident's namespace or is named as the incident's owner
+func (t *Team) ownsIncident(incident *models.Incident) bool {
+	if incident.Owner != nil && incident.Owner.Team == t.Name {
+		return true
+	}
+	return t.owns(incident.Target)
+}
+
+// alertRank returns the minimum severity rank that triggers an immediate alert
+func (t *Team) alertRank() int {
+	if t.AlertSeverity == "" {
+		return models.IncidentSeverityCritical.Rank()
+	}
+	return t.AlertSeverity.Rank()
+}
+
+// teamsFile is the on-disk format of the email team list
+type teamsFile struct {
+	Teams []Team `json:"teams"`
+}
+
+// LoadTeams reads email teams from a YAML or JSON file of the form {"teams": [...]}
+func LoadTeams(path string) ([]Team, error) {
+	data, err := os.ReadFile(path) //#nosec G304 -- path comes from operator configuration
+	if err != nil {
+		return nil, fmt.Errorf("failed to read email teams file: %w", err)
+	}
+
+	var file teamsFile
+	if err := yaml.UnmarshalStrict(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse email teams file %s: %w", path, err)
+	}
+	return file.Teams, nil
+}
+
+// CapacityReporter lists namespaces that are close to their quota
+type CapacityReporter interface {
+	CapacityWarnings(ctx context.Context) ([]capacity.Warning, error)
+}
+
+// WorkflowLister lists remediation workflows
+type WorkflowLister interface {
+	ListWorkflows() []*models.Workflow
+}
+
+// AlertData is the data passed to the alert template
+type AlertData struct {
+	Team     string
+	Reason   string // "opened" or "escalated"
+	Incident models.Incident
+}
+
+// Digest is the data passed to the digest template
+type Digest struct {
+	Team              string
+	Period            DigestPeriod
+	Start             time.Time
+	End               time.Time
+	TopIncidents      []models.Incident
+	OpenIncidents     int
+	ResolvedIncidents int
+	CapacityWarnings  []capacity.Warning
+	Remediation       RemediationSummary
+}
+
+// RemediationSummary counts the remediation workflows started during a digest period
+type RemediationSummary struct {
+	Total      int
+	Completed  int
+	Failed     int
+	InProgress int
+	Failures   []models.Workflow
+}
+
+// EmailOptions configures the email notifier
+type EmailOptions struct {
+	// From is the sender address
+	From string
+	// DigestHour is the hour of day (UTC) digests are sent
+	DigestHour int
+	// DigestWeekday is the day weekly digests are sent
+	DigestWeekday time.Weekday
+	// Timeout bounds sending a single email
+	Timeout time.Duration
+}
+
+// EmailNotifier emails immediate incident alerts and periodic digests to teams
+type EmailNotifier struct {
+	teams     []Team
+	mailer    Mailer
+	templates *Templates
+	store     *storage.IncidentStore
+	opts      EmailOptions
+	log       *logrus.Logger
+
+	capacity  CapacityReporter
+	workflows WorkflowLister
+	silenced  func(incident *models.Incident) string
+
+	// severities tracks the last seen severity of each incident to detect escalation
+	mu         sync.Mutex
+	severities map[string]models.IncidentSeverity
+
+	now func() time.Time
+}
+
+// NewEmailNotifier creates an email notifier, rejecting invalid or duplicate teams
+func NewEmailNotifier(
+	teams []Team,
+	mailer Mailer,
+	templates *Templates,
+	store *storage.IncidentStore,
+	opts EmailOptions,
+	log *logrus.Logger,
+) (*EmailNotifier, error) {
+	seen := make(map[string]bool, len(teams))
+	for i := range teams {
+		if err := teams[i].Validate(); err != nil {
+			return nil, err
+		}
+		if seen[teams[i].Name] {
+			return nil, fmt.Errorf("duplicate email team name: %s", teams[i].Name)
+		}
+		seen[teams[i].Name] = true
+	}
+	if opts.From == "" {
+		return nil, fmt.Errorf("from address is required")
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	if templates == nil {
+		templates = DefaultTemplates()
+	}
+
+	return &EmailNotifier{
+		teams:      teams,
+		mailer:     mailer,
+		templates:  templates,
+		store:      store,
+		opts:       opts,
+		log:        log,
+		severities: make(map[string]models.IncidentSeverity),
+		now:        time.Now,
+	}, nil
+}
+
+// SetCapacityReporter sets the source of capacity warnings for digests
+func (n *EmailNotifier) SetCapacityReporter(reporter CapacityReporter) {
+	n.capacity = reporter
+}
+
+// SetWorkflowLister sets the source of remediation workflows for digests
+func (n *EmailNotifier) SetWorkflowLister(lister WorkflowLister) {
+	n.workflows = lister
+}
+
+// SetSilencer suppresses immediate alerts while silenced returns a non-empty silence
+// name for the incident. Digests still list silenced incidents.
+func (n *EmailNotifier) SetSilencer(silenced func(incident *models.Incident) string) {
+	n.silenced = silenced
+}
+
+// Start sends immediate alerts for incident changes and scheduled digests until ctx is
+// cancelled. It subscribes to the store before returning.
+func (n *EmailNotifier) Start(ctx context.Context) {
+	changes, unsubscribe := n.store.Subscribe(256)
+
+	go func() {
+		defer unsubscribe()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case change, ok := <-changes:
+				if !ok {
+					return
+				}
+				n.handleChange(ctx, &change)
+			}
+		}
+	}()
+
+	go n.runDigests(ctx)
+
+	n.log.WithFields(logrus.Fields{
+		"teams":       len(n.teams),
+		"digest_hour": n.opts.DigestHour,
+	}).Info("Email notifier started")
+}
+
+// handleChange emails teams about incidents opened at or escalated to their alert severity
+func (n *EmailNotifier) handleChange(ctx context.Context, change *storage.IncidentChange) {
+	incident := change.Incident
+
+	n.mu.Lock()
+	previous, known := n.severities[incident.ID]
+	if change.Type == storage.IncidentDeleted {
+		delete(n.severities, incident.ID)
+	} else {
+		n.severities[incident.ID] = incident.Severity
+	}
+	n.mu.Unlock()
+
+	if !incident.IsActive() {
+		return
+	}
+
+	var reason string
+	switch {
+	case change.Type == storage.IncidentCreated:
+		reason = "opened"
+	case change.Type == storage.IncidentUpdated && known && incident.Severity.Rank() > previous.Rank():
+		reason = "escalated"
+	default:
+		return
+	}
+	if n.silenced != nil {
+		if silence := n.silenced(&incident); silence != "" {
+			n.log.WithFields(logrus.Fields{
+				"incident_id": incident.ID,
+				"silence":     silence,
+			}).Debug("Incident alert email silenced")
+			return
+		}
+	}
+
+	for i := range n.teams {
+		team := &n.teams[i]
+		if team.DisableAlerts || !team.ownsIncident(&incident) || incident.Severity.Rank() < team.alertRank() {
+			continue
+		}
+		// Escalations only alert when they cross the team's threshold
+		if reason == "escalated" && previous.Rank() >= team.alertRank() {
+			continue
+		}
+
+		if err := n.SendAlert(ctx, team, &incident, reason); err != nil {
+			n.log.WithError(err).WithFields(logrus.Fields{
+				"team":        team.Name,
+				"incident_id": incident.ID,
+			}).Warn("Failed to send incident alert email")
+		}
+	}
+
+	// Owners without a configured team are alerted at their annotated email address
+	if owner := ownerTeam(&incident); owner != nil && n.team(owner.Name) == nil &&
+		incident.Severity.Rank() >= owner.alertRank() && (reason != "escalated" || previous.Rank() < owner.alertRank()) {
+		if err := n.SendAlert(ctx, owner, &incident, reason); err != nil {
+			n.log.WithError(err).WithFields(logrus.Fields{
+				"owner":       owner.Name,
+				"incident_id": incident.ID,
+			}).Warn("Failed to send incident alert email to owner")
+		}
+	}
+}
+
+// ownerTeam returns a team alerting the annotated email address of an incident's
+// owner at the default severity, or nil when the owner has no email
+func ownerTeam(incident *models.Incident) *Team {
+	owner := incident.Owner
+	if owner == nil || !strings.Contains(owner.Email, "@") {
+		return nil
+	}
+	name := owner.Team
+	if name == "" {
+		name = owner.Email
+	}
+	return &Team{Name: name, Recipients: []string{owner.Email}}
+}
+
+// SendAlert emails a single incident alert to a team
+func (n *EmailNotifier) SendAlert(ctx context.Context, team *Team, incident *models.Incident, reason string) error {
+	subject, body, err := render(n.templates.alert, AlertData{Team: team.Name, Reason: reason, Incident: *incident})
+	if err != nil {
+		return err
+	}
+	return n.send(ctx, team.Recipients, subject, body)
+}
+
+// SendDigest builds and emails a digest for the named team covering the period ending now
+func (n *EmailNotifier) SendDigest(ctx context.Context, teamName string, period DigestPeriod) (*Digest, error) {
+	team := n.team(teamName)
+	if team == nil {
+		return nil, fmt.Errorf("unknown team: %s", teamName)
+	}
+
+	digest := n.BuildDigest(ctx, team, period, n.now())
+	subject, body, err := render(n.templates.digest, digest)
+	if err != nil {
+		return nil, err
+	}
+	if err := n.send(ctx, team.Recipients, subject, body); err != nil {
+		return nil, err
+	}
+	return digest, nil
+}
+
+// BuildDigest collects the incidents, capacity warnings and remediation workflows for a
+// team's namespaces over the period ending at end. Sources that fail or are not configured
+// leave their section empty.
+func (n *EmailNotifier) BuildDigest(ctx context.Context, team *Team, period DigestPeriod, end time.Time) *Digest {
+	digest := &Digest{
+		Team:             team.Name,
+		Period:           period,
+		Start:            end.Add(-period.window()),
+		End:              end,
+		TopIncidents:     []models.Incident{},
+		CapacityWarnings: []capacity.Warning{},
+	}
+
+	for _, incident := range n.store.List(storage.ListFilter{}) {
+		if !team.ownsIncident(incident) {
+			continue
+		}
+		if incident.IsActive() {
+			digest.OpenIncidents++
+		}
+		if incident.ResolvedAt != nil && inWindow(*incident.ResolvedAt, digest.Start, end) {
+			digest.ResolvedIncidents++
+		}
+		if inWindow(incident.CreatedAt, digest.Start, end) {
+			digest.TopIncidents = append(digest.TopIncidents, *incident)
+		}
+	}
+	sort.SliceStable(digest.TopIncidents, func(i, j int) bool {
+		a, b := &digest.TopIncidents[i], &digest.TopIncidents[j]
+		if a.EffectivePriority() != b.EffectivePriority() {
+			return a.EffectivePriority() > b.EffectivePriority()
+		}
+		return a.CreatedAt.After(b.CreatedAt)
+	})
+	if len(digest.TopIncidents) > maxDigestIncidents {
+		digest.TopIncidents = digest.TopIncidents[:maxDigestIncidents]
+	}
+
+	if n.capacity != nil {
+		warnings, err := n.capacity.CapacityWarnings(ctx)
+		if err != nil {
+			n.log.WithError(err).WithField("team", team.Name).Warn("Failed to collect capacity warnings for digest")
+		}
+		for _, warning := range warnings {
+			if team.owns(warning.Namespace) {
+				digest.CapacityWarnings = append(digest.CapacityWarnings, warning)
+			}
+		}
+	}
+
+	if n.workflows != nil {
+		for _, wf := range n.workflows.ListWorkflows() {
+			if !team.owns(wf.Namespace) || !inWindow(wf.CreatedAt, digest.Start, end) {
+				continue
+			}
+			digest.Remediation.Total++
+			switch wf.Status {
+			case models.WorkflowStatusCompleted:
+				digest.Remediation.Completed++
+			case models.WorkflowStatusFailed:
+				digest.Remediation.Failed++
+				if len(digest.Remediation.Failures) < maxDigestFailures {
+					digest.Remediation.Failures = append(digest.Remediation.Failures, *wf)
+				}
+			default:
+				digest.Remediation.InProgress++
+			}
+		}
+	}
+
+	return digest
+}
+
+// runDigests sends daily digests at DigestHour and weekly digests on DigestWeekday
+func (n *EmailNotifier) runDigests(ctx context.Context) {
+	for {
+		next := nextDigestTime(n.now(), n.opts.DigestHour)
+		if !sleepContext(ctx, time.Until(next)) {
+			return
+		}
+
+		for i := range n.teams {
+			team := &n.teams[i]
+			due := team.Digest == DigestDaily || (team.Digest == DigestWeekly && next.Weekday() == n.opts.DigestWeekday)
+			if !due {
+				continue
+			}
+			if _, err := n.SendDigest(ctx, team.Name, team.Digest); err != nil {
+				n.log.WithError(err).WithField("team", team.Name).Warn("Failed to send digest email")
+			}
+		}
+	}
+}
+
+// nextDigestTime returns the next occurrence of hour:00 UTC strictly after now
+func nextDigestTime(now time.Time, hour int) time.Time {
+	now = now.UTC()
+	next := time.Date(now.Year(), now.Month(), now.Day(), hour, 0, 0, 0, time.UTC)
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// inWindow reports whether t falls in (start, end]
+func inWindow(t, start, end time.Time) bool {
+	return t.After(start) && !t.After(end)
+}
+
+// team returns the named team or nil
+func (n *EmailNotifier) team(name string) *Team {
+	for i := range n.teams {
+		if n.teams[i].Name == name {
+			return &n.teams[i]
+		}
+	}
+	return nil
+}
+
+// send renders the message and hands it to the mailer
+func (n *EmailNotifier) send(ctx context.Context, to []string, subject, body string) error {
+	msg, err := buildMessage(n.opts.From, to, subject, body, n.now())
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, n.opts.Timeout)
+	defer cancel()
+	if err := n.mailer.Send(ctx, n.opts.From, to, msg); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	n.log.WithFields(logrus.Fields{
+		"recipients": len(to),
+		"subject":    subject,
+	}).Debug("Email sent")
+	return nil
+}
+
+// digestRequest is the body of POST /api/v1/notifications/email/digest
+type digestRequest struct {
+	Team   string       `json:"team"`
+	Period DigestPeriod `json:"period"`
+}
+
+// RegisterRoutes registers the email digest API
+func (n *EmailNotifier) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/api/v1/notifications/email/digest", n.handleSendDigest).Methods("POST")
+	n.log.Info("Email notification API endpoints registered: /api/v1/notifications/email/digest")
+}
+
+// handleSendDigest handles POST /api/v1/notifications/email/digest, sending a team's
+// digest immediately (for example to check a customized template)
+func (n *EmailNotifier) handleSendDigest(w http.ResponseWriter, r *http.Request) {
+	var req digestRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		n.respondError(w, http.StatusBadRequest, "invalid request body: "+err.Error(), validation.DecodeFields(err)...)
+		return
+	}
+	if req.Period == "" {
+		req.Period = DigestDaily
+	}
+	if req.Period != DigestDaily && req.Period != DigestWeekly {
+		n.respondError(w, http.StatusBadRequest, "period must be daily or weekly",
+			validation.FieldError{Field: "period", Constraint: validation.ConstraintEnum, Value: req.Period, Message: "period must be daily or weekly"})
+		return
+	}
+
+	team := n.team(req.Team)
+	if team == nil {
+		n.respondError(w, http.StatusNotFound, "team not found")
+		return
+	}
+
+	digest, err := n.SendDigest(r.Context(), team.Name, req.Period)
+	if err != nil {
+		n.log.WithError(err).WithField("team", team.Name).Error("Failed to send digest on request")
+		n.respondError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+
+	n.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"status":            "sent",
+		"team":              team.Name,
+		"period":            req.Period,
+		"recipients":        team.Recipients,
+		"incidents":         len(digest.TopIncidents),
+		"capacity_warnings": len(digest.CapacityWarnings),
+	})
+}
+
+func (n *EmailNotifier) respondJSON(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		n.log.WithError(err).Error("Failed to encode notification response")
+	}
+}
+
+func (n *EmailNotifier) respondError(w http.ResponseWriter, statusCode int, message string, fieldErrs ...validation.FieldError) {
+	response := map[string]interface{}{
+		"status": "error",
+		"error":  message,
+	}
+	if len(fieldErrs) > 0 {
+		response["errors"] = fieldErrs
+	}
+	n.respondJSON(w, statusCode, response)
+}
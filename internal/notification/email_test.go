@@ -0,0 +1,356 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"mime/quotedprintable"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/storage"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/capacity"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// sentMail is a message captured by fakeMailer
+type sentMail struct {
+	to      []string
+	subject string
+	body    string
+}
+
+// fakeMailer records messages instead of sending them
+type fakeMailer struct {
+	mu   sync.Mutex
+	sent []sentMail
+	err  error
+}
+
+func (m *fakeMailer) Send(_ context.Context, _ string, to []string, msg []byte) error {
+	if m.err != nil {
+		return m.err
+	}
+	headers, body, _ := strings.Cut(string(msg), "\r\n\r\n")
+	decoded, _ := io.ReadAll(quotedprintable.NewReader(strings.NewReader(body)))
+
+	mail := sentMail{to: to, body: strings.ReplaceAll(string(decoded), "\r\n", "\n")}
+	for _, line := range strings.Split(headers, "\r\n") {
+		if subject, ok := strings.CutPrefix(line, "Subject: "); ok {
+			mail.subject = subject
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = append(m.sent, mail)
+	return nil
+}
+
+func (m *fakeMailer) messages() []sentMail {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]sentMail(nil), m.sent...)
+}
+
+type staticCapacity []capacity.Warning
+
+func (s staticCapacity) CapacityWarnings(context.Context) ([]capacity.Warning, error) {
+	return s, nil
+}
+
+type staticWorkflows []*models.Workflow
+
+func (s staticWorkflows) ListWorkflows() []*models.Workflow {
+	return s
+}
+
+var testTeams = []Team{
+	{Name: "payments", Recipients: []string{"payments-oncall@example.com"}, Namespaces: []string{"payments"}, Digest: DigestDaily},
+	{Name: "platform", Recipients: []string{"platform@example.com"}, AlertSeverity: models.IncidentSeverityHigh, Digest: DigestWeekly},
+	{Name: "reporting", Recipients: []string{"reports@example.com"}, DisableAlerts: true},
+}
+
+func newTestEmailNotifier(t *testing.T, teams []Team) (*EmailNotifier, *fakeMailer, *storage.IncidentStore) {
+	t.Helper()
+	store := storage.NewIncidentStoreWithPath(t.TempDir())
+	mailer := &fakeMailer{}
+	notifier, err := NewEmailNotifier(teams, mailer, nil, store, EmailOptions{From: "engine@example.com"}, testLogger())
+	require.NoError(t, err)
+	return notifier, mailer, store
+}
+
+func createIncident(t *testing.T, store *storage.IncidentStore, namespace string, severity models.IncidentSeverity) *models.Incident {
+	t.Helper()
+	incident, err := store.Create(&models.Incident{
+		Title:       "Pods crash looping",
+		Description: "api pods in CrashLoopBackOff",
+		Severity:    severity,
+		Target:      namespace,
+	})
+	require.NoError(t, err)
+	return incident
+}
+
+func TestEmailNotifier_ImmediateAlerts(t *testing.T) {
+	notifier, mailer, store := newTestEmailNotifier(t, testTeams)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	notifier.Start(ctx)
+
+	// Critical in payments: payments (default critical) and platform (high and above)
+	createIncident(t, store, "payments", models.IncidentSeverityCritical)
+	require.Eventually(t, func() bool { return len(mailer.messages()) == 2 }, 5*time.Second, 10*time.Millisecond)
+
+	recipients := map[string]sentMail{}
+	for _, mail := range mailer.messages() {
+		recipients[mail.to[0]] = mail
+	}
+	require.Contains(t, recipients, "payments-oncall@example.com")
+	require.Contains(t, recipients, "platform@example.com")
+	assert.Equal(t, "[CRITICAL] Pods crash looping (payments)", recipients["platform@example.com"].subject)
+	assert.Contains(t, recipients["platform@example.com"].body, "was opened for team platform")
+
+	// Medium in checkout alerts nobody; escalating it to high alerts platform only
+	incident := createIncident(t, store, "checkout", models.IncidentSeverityMedium)
+	escalated := *incident
+	escalated.Severity = models.IncidentSeverityHigh
+	require.NoError(t, store.Update(&escalated))
+
+	require.Eventually(t, func() bool { return len(mailer.messages()) == 3 }, 5*time.Second, 10*time.Millisecond)
+	last := mailer.messages()[2]
+	assert.Equal(t, []string{"platform@example.com"}, last.to)
+	assert.Contains(t, last.body, "was escalated")
+
+	// Further updates at the same severity do not re-alert
+	escalated.Description = "still crash looping"
+	require.NoError(t, store.Update(&escalated))
+	time.Sleep(50 * time.Millisecond)
+	assert.Len(t, mailer.messages(), 3)
+}
+
+func TestEmailNotifier_OwnerAlerts(t *testing.T) {
+	teams := []Team{
+		{Name: "payments", Recipients: []string{"payments-oncall@example.com"}, Namespaces: []string{"payments"}},
+		{Name: "search", Recipients: []string{"search-oncall@example.com"}, Namespaces: []string{"search"}},
+	}
+	notifier, mailer, store := newTestEmailNotifier(t, teams)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	notifier.Start(ctx)
+
+	create := func(owner *models.Owner) {
+		_, err := store.Create(&models.Incident{
+			Title:       "Pods crash looping",
+			Description: "api pods in CrashLoopBackOff",
+			Severity:    models.IncidentSeverityCritical,
+			Target:      "shared",
+			Owner:       owner,
+		})
+		require.NoError(t, err)
+	}
+
+	// The owning team is alerted outside its namespaces
+	create(&models.Owner{Team: "search", Email: "search-leads@example.com"})
+	require.Eventually(t, func() bool { return len(mailer.messages()) == 1 }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"search-oncall@example.com"}, mailer.messages()[0].to)
+
+	// Owners without a team are alerted at their email
+	create(&models.Owner{Team: "ledger", Email: "ledger@example.com"})
+	require.Eventually(t, func() bool { return len(mailer.messages()) == 2 }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"ledger@example.com"}, mailer.messages()[1].to)
+	assert.Contains(t, mailer.messages()[1].body, "was opened for team ledger")
+
+	create(&models.Owner{Team: "ledger"})
+	time.Sleep(50 * time.Millisecond)
+	assert.Len(t, mailer.messages(), 2)
+}
+
+func TestEmailNotifier_BuildDigest(t *testing.T) {
+	notifier, _, store := newTestEmailNotifier(t, testTeams)
+	now := time.Now().UTC()
+
+	createIncident(t, store, "payments", models.IncidentSeverityMedium)
+	createIncident(t, store, "payments", models.IncidentSeverityCritical)
+	resolved := createIncident(t, store, "payments", models.IncidentSeverityLow)
+	done := *resolved
+	done.Resolve()
+	require.NoError(t, store.Update(&done))
+	createIncident(t, store, "checkout", models.IncidentSeverityHigh)
+
+	completed := now.Add(-time.Hour)
+	notifier.SetCapacityReporter(staticCapacity{
+		{Namespace: "payments", CPUPercent: 91, MemoryPercent: 40, DaysUntil85Percent: 0},
+		{Namespace: "checkout", CPUPercent: 20, MemoryPercent: 82, DaysUntil85Percent: -1},
+	})
+	notifier.SetWorkflowLister(staticWorkflows{
+		{ID: "wf-1", Namespace: "payments", Status: models.WorkflowStatusCompleted, CreatedAt: now.Add(-2 * time.Hour), CompletedAt: &completed},
+		{ID: "wf-2", Namespace: "payments", Status: models.WorkflowStatusFailed, IssueType: "pod_crash_loop", ResourceName: "api", ErrorMessage: "rollout timed out", CreatedAt: now.Add(-time.Hour)},
+		{ID: "wf-3", Namespace: "payments", Status: models.WorkflowStatusCompleted, CreatedAt: now.Add(-48 * time.Hour)},
+	})
+
+	digest := notifier.BuildDigest(context.Background(), &testTeams[0], DigestDaily, now.Add(time.Second))
+	require.Len(t, digest.TopIncidents, 3)
+	assert.Equal(t, models.IncidentSeverityCritical, digest.TopIncidents[0].Severity, "incidents are ordered by severity")
+	assert.Equal(t, 2, digest.OpenIncidents)
+	assert.Equal(t, 1, digest.ResolvedIncidents)
+	require.Len(t, digest.CapacityWarnings, 1)
+	assert.Equal(t, "payments", digest.CapacityWarnings[0].Namespace)
+	assert.Equal(t, RemediationSummary{Total: 2, Completed: 1, Failed: 1, Failures: digest.Remediation.Failures}, digest.Remediation)
+	require.Len(t, digest.Remediation.Failures, 1)
+
+	// The platform team owns every namespace
+	digest = notifier.BuildDigest(context.Background(), &testTeams[1], DigestWeekly, now.Add(time.Second))
+	assert.Len(t, digest.TopIncidents, 4)
+	assert.Len(t, digest.CapacityWarnings, 2)
+	assert.Equal(t, 3, digest.Remediation.Total)
+}
+
+func TestEmailNotifier_SendDigest(t *testing.T) {
+	notifier, mailer, store := newTestEmailNotifier(t, testTeams)
+	createIncident(t, store, "payments", models.IncidentSeverityHigh)
+	notifier.SetCapacityReporter(staticCapacity{{Namespace: "payments", CPUPercent: 91, MemoryPercent: 40, DaysUntil85Percent: 3}})
+
+	_, err := notifier.SendDigest(context.Background(), "payments", DigestDaily)
+	require.NoError(t, err)
+
+	messages := mailer.messages()
+	require.Len(t, messages, 1)
+	assert.Equal(t, "Daily digest for payments: 1 incidents, 1 capacity warnings", messages[0].subject)
+	assert.Contains(t, messages[0].body, "[high] Pods crash looping (payments, active")
+	assert.Contains(t, messages[0].body, "payments: CPU 91%, memory 40%, 85% of quota in 3 days")
+	assert.Contains(t, messages[0].body, "Workflows: 0")
+
+	_, err = notifier.SendDigest(context.Background(), "unknown", DigestDaily)
+	assert.Error(t, err)
+
+	mailer.err = errors.New("connection refused")
+	_, err = notifier.SendDigest(context.Background(), "payments", DigestDaily)
+	assert.ErrorContains(t, err, "failed to send email")
+}
+
+func TestEmailNotifier_CustomTemplates(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, AlertTemplateFile), []byte(
+		`{{define "subject"}}PAGE {{.Team}}: {{.Incident.Title}}{{end}}{{define "body"}}{{.Incident.Target}} needs attention{{end}}`,
+	), 0o600))
+
+	templates, err := LoadTemplates(dir)
+	require.NoError(t, err)
+
+	store := storage.NewIncidentStoreWithPath(t.TempDir())
+	mailer := &fakeMailer{}
+	notifier, err := NewEmailNotifier(testTeams[:1], mailer, templates, store, EmailOptions{From: "engine@example.com"}, testLogger())
+	require.NoError(t, err)
+
+	incident := &models.Incident{ID: "inc-1", Title: "Disk full", Target: "payments", Severity: models.IncidentSeverityCritical}
+	require.NoError(t, notifier.SendAlert(context.Background(), &testTeams[0], incident, "opened"))
+	require.Len(t, mailer.messages(), 1)
+	assert.Equal(t, "PAGE payments: Disk full", mailer.messages()[0].subject)
+	assert.Equal(t, "payments needs attention", mailer.messages()[0].body)
+
+	// The digest template was not overridden
+	_, err = notifier.SendDigest(context.Background(), "payments", DigestDaily)
+	require.NoError(t, err)
+	assert.Contains(t, mailer.messages()[1].subject, "Daily digest for payments")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, DigestTemplateFile), []byte(`{{define "body"}}x{{end}}`), 0o600))
+	_, err = LoadTemplates(dir)
+	assert.ErrorContains(t, err, `must define "subject"`)
+}
+
+func TestDigestAPI(t *testing.T) {
+	notifier, mailer, _ := newTestEmailNotifier(t, testTeams)
+	router := mux.NewRouter()
+	notifier.RegisterRoutes(router)
+
+	post := func(body string) int {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/v1/notifications/email/digest", bytes.NewBufferString(body)))
+		return rr.Code
+	}
+
+	assert.Equal(t, http.StatusOK, post(`{"team": "platform", "period": "weekly"}`))
+	require.Len(t, mailer.messages(), 1)
+	assert.Contains(t, mailer.messages()[0].subject, "Weekly digest for platform")
+
+	assert.Equal(t, http.StatusNotFound, post(`{"team": "unknown"}`))
+	assert.Equal(t, http.StatusBadRequest, post(`{"team": "platform", "period": "hourly"}`))
+	assert.Equal(t, http.StatusBadRequest, post(`not json`))
+}
+
+func TestNewEmailNotifier_Invalid(t *testing.T) {
+	store := storage.NewIncidentStoreWithPath(t.TempDir())
+	tests := []struct {
+		name    string
+		teams   []Team
+		from    string
+		wantErr string
+	}{
+		{name: "missing name", teams: []Team{{Recipients: []string{"a@example.com"}}}, from: "e@example.com", wantErr: "name is required"},
+		{name: "no recipients", teams: []Team{{Name: "a"}}, from: "e@example.com", wantErr: "at least one recipient"},
+		{name: "bad recipient", teams: []Team{{Name: "a", Recipients: []string{"oncall"}}}, from: "e@example.com", wantErr: "invalid recipient"},
+		{name: "bad digest", teams: []Team{{Name: "a", Recipients: []string{"a@example.com"}, Digest: "hourly"}}, from: "e@example.com", wantErr: "digest must be daily or weekly"},
+		{name: "bad severity", teams: []Team{{Name: "a", Recipients: []string{"a@example.com"}, AlertSeverity: "urgent"}}, from: "e@example.com", wantErr: "alert_severity"},
+		{name: "duplicate", teams: []Team{testTeams[0], testTeams[0]}, from: "e@example.com", wantErr: "duplicate email team name"},
+		{name: "no sender", teams: testTeams, wantErr: "from address is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewEmailNotifier(tt.teams, &fakeMailer{}, nil, store, EmailOptions{From: tt.from}, testLogger())
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestLoadTeams(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "teams.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+teams:
+  - name: payments
+    recipients: [payments-oncall@example.com]
+    namespaces: [payments, payments-staging]
+    digest: weekly
+`), 0o600))
+
+	teams, err := LoadTeams(path)
+	require.NoError(t, err)
+	require.Len(t, teams, 1)
+	assert.Equal(t, DigestWeekly, teams[0].Digest)
+	assert.True(t, teams[0].owns("payments-staging"))
+	assert.False(t, teams[0].owns("checkout"))
+
+	require.NoError(t, os.WriteFile(path, []byte("teams:\n  - name: a\n    recipient: [a@example.com]\n"), 0o600))
+	_, err = LoadTeams(path)
+	assert.Error(t, err, "unknown fields are rejected")
+}
+
+func TestNextDigestTime(t *testing.T) {
+	now := time.Date(2026, 3, 2, 7, 30, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC), nextDigestTime(now, 8))
+	assert.Equal(t, time.Date(2026, 3, 3, 6, 0, 0, 0, time.UTC), nextDigestTime(now, 6))
+	assert.Equal(t, time.Date(2026, 3, 3, 7, 0, 0, 0, time.UTC), nextDigestTime(now.Add(-30*time.Minute), 7), "a digest due now runs tomorrow")
+}
+
+func TestBuildMessage(t *testing.T) {
+	msg, err := buildMessage("engine@example.com", []string{"a@example.com", "b@example.com"}, "Café alert", "line one\nline two", time.Now())
+	require.NoError(t, err)
+
+	text := string(msg)
+	assert.Contains(t, text, "To: a@example.com, b@example.com\r\n")
+	assert.Contains(t, text, "Subject: =?utf-8?q?Caf=C3=A9_alert?=\r\n")
+	assert.Contains(t, text, "Content-Transfer-Encoding: quoted-printable\r\n\r\nline one\r\nline two")
+}
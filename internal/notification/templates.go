@@ -0,0 +1,171 @@
+package notification
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// Template file names looked up in the email template directory
+const (
+	AlertTemplateFile  = "alert.tmpl"
+	DigestTemplateFile = "digest.tmpl"
+)
+
+// defaultAlertTemplate renders an immediate incident alert (data: AlertData)
+const defaultAlertTemplate = `{{define "subject"}}[{{upper (print .Incident.Severity)}}] {{.Incident.Title}} ({{.Incident.Target}}){{end}}
+{{define "body"}}A {{.Incident.Severity}} incident was {{.Reason}} for team {{.Team}}.
+
+Incident:  {{.Incident.ID}}
+Title:     {{.Incident.Title}}
+Namespace: {{.Incident.Target}}
+Severity:  {{.Incident.Severity}}
+Priority:  {{priority .Incident}}
+Status:    {{.Incident.Status}}
+Opened:    {{timestamp .Incident.CreatedAt}}
+{{- if .Incident.AffectedResources}}
+Affected:  {{join .Incident.AffectedResources ", "}}
+{{- end}}
+
+{{.Incident.Description}}
+{{- if .Incident.Summary}}
+
+Summary:
+{{.Incident.Summary}}
+{{- end}}
+{{end}}`
+
+// defaultDigestTemplate renders a daily or weekly digest (data: Digest)
+const defaultDigestTemplate = `{{define "subject"}}{{title (print .Period)}} digest for {{.Team}}: {{len .TopIncidents}} incidents, {{len .CapacityWarnings}} capacity warnings{{end}}
+{{define "body"}}{{title (print .Period)}} digest for team {{.Team}}
+{{timestamp .Start}} to {{timestamp .End}}
+
+TOP ANOMALIES
+{{- if .TopIncidents}}
+{{- range .TopIncidents}}
+- [{{.Severity}}] {{.Title}} ({{.Target}}, {{.Status}}, priority {{priority .}}, opened {{timestamp .CreatedAt}})
+{{- end}}
+{{- else}}
+No incidents were opened.
+{{- end}}
+Open incidents: {{.OpenIncidents}}, resolved in period: {{.ResolvedIncidents}}
+
+CAPACITY WARNINGS
+{{- if .CapacityWarnings}}
+{{- range .CapacityWarnings}}
+- {{.Namespace}}: CPU {{percent .CPUPercent}}, memory {{percent .MemoryPercent}}
+{{- if ge .DaysUntil85Percent 0}}, 85% of quota in {{.DaysUntil85Percent}} days{{end}}
+{{- end}}
+{{- else}}
+No namespaces are close to their quota.
+{{- end}}
+
+REMEDIATION
+Workflows: {{.Remediation.Total}} ({{.Remediation.Completed}} completed, {{.Remediation.Failed}} failed, {{.Remediation.InProgress}} in progress)
+{{- range .Remediation.Failures}}
+- FAILED {{.IssueType}} on {{.Namespace}}/{{.ResourceName}}: {{.ErrorMessage}}
+{{- end}}
+{{end}}`
+
+// templateFuncs are available to alert and digest templates
+var templateFuncs = template.FuncMap{
+	"upper": strings.ToUpper,
+	"title": func(s string) string {
+		if s == "" {
+			return s
+		}
+		return strings.ToUpper(s[:1]) + s[1:]
+	},
+	"join": strings.Join,
+	"timestamp": func(t time.Time) string {
+		return t.UTC().Format("2006-01-02 15:04 UTC")
+	},
+	"percent": func(v float64) string {
+		return fmt.Sprintf("%.0f%%", v)
+	},
+	"priority": func(incident models.Incident) int {
+		return incident.EffectivePriority()
+	},
+}
+
+// Templates renders email subjects and bodies. Each template defines a "subject"
+// and a "body" template.
+type Templates struct {
+	alert  *template.Template
+	digest *template.Template
+}
+
+// DefaultTemplates returns the built-in plain-text templates
+func DefaultTemplates() *Templates {
+	return &Templates{
+		alert:  template.Must(parseTemplate(AlertTemplateFile, defaultAlertTemplate)),
+		digest: template.Must(parseTemplate(DigestTemplateFile, defaultDigestTemplate)),
+	}
+}
+
+// LoadTemplates returns the built-in templates, replacing each with alert.tmpl or
+// digest.tmpl from dir when that file exists. An empty dir uses the built-ins.
+func LoadTemplates(dir string) (*Templates, error) {
+	templates := DefaultTemplates()
+	if dir == "" {
+		return templates, nil
+	}
+
+	for name, target := range map[string]**template.Template{
+		AlertTemplateFile:  &templates.alert,
+		DigestTemplateFile: &templates.digest,
+	} {
+		path := filepath.Join(dir, name)
+		data, err := os.ReadFile(path) //#nosec G304 -- path comes from operator configuration
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read email template: %w", err)
+		}
+
+		parsed, err := parseTemplate(name, string(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse email template %s: %w", path, err)
+		}
+		*target = parsed
+	}
+	return templates, nil
+}
+
+// parseTemplate parses a template and checks it defines "subject" and "body"
+func parseTemplate(name, text string) (*template.Template, error) {
+	tmpl, err := template.New(name).Funcs(templateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	for _, required := range []string{"subject", "body"} {
+		if tmpl.Lookup(required) == nil {
+			return nil, fmt.Errorf("template %s must define %q", name, required)
+		}
+	}
+	return tmpl, nil
+}
+
+// render executes the subject and body templates. Newlines in the subject are collapsed.
+func render(tmpl *template.Template, data interface{}) (subject, body string, err error) {
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, "subject", data); err != nil {
+		return "", "", fmt.Errorf("failed to render subject: %w", err)
+	}
+	subject = strings.Join(strings.Fields(buf.String()), " ")
+
+	buf.Reset()
+	if err := tmpl.ExecuteTemplate(&buf, "body", data); err != nil {
+		return "", "", fmt.Errorf("failed to render body: %w", err)
+	}
+	return subject, buf.String(), nil
+}
@@ -0,0 +1,244 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// EventDigest batches the events a throttled target was held from receiving; targets
+// cannot subscribe to it, every throttled target receives its own digests
+const EventDigest EventType = "notification.digest"
+
+// maxDigestEvents caps the events carried by a digest; further held events are only
+// counted in its omitted field
+const maxDigestEvents = 100
+
+// throttleWindow is the sliding window of a target's MaxPerHour
+const throttleWindow = time.Hour
+
+// throttleFlushInterval is how often held events are checked for a digest
+const throttleFlushInterval = time.Minute
+
+// QuietHours is a daily window in which a target's events are held and sent as a
+// digest once it ends
+type QuietHours struct {
+	// Start and End are times of day (HH:MM); the window wraps past midnight when
+	// End is before Start
+	Start string `json:"start"`
+	End   string `json:"end"`
+
+	// Timezone is an IANA time zone such as Europe/Berlin (default UTC)
+	Timezone string `json:"timezone,omitempty"`
+
+	// AllowSeverity is the minimum severity of incidents delivered during quiet
+	// hours, e.g. critical; empty holds every event
+	AllowSeverity models.IncidentSeverity `json:"allow_severity,omitempty"`
+}
+
+// Validate checks the quiet hours
+func (q *QuietHours) Validate() error {
+	start, err := parseClock(q.Start)
+	if err != nil {
+		return fmt.Errorf("start %w", err)
+	}
+	end, err := parseClock(q.End)
+	if err != nil {
+		return fmt.Errorf("end %w", err)
+	}
+	if start == end {
+		return fmt.Errorf("start and end must differ")
+	}
+	if _, err := time.LoadLocation(q.Timezone); err != nil {
+		return fmt.Errorf("invalid timezone %q", q.Timezone)
+	}
+	if q.AllowSeverity != "" && !models.IsValidSeverity(string(q.AllowSeverity)) {
+		return fmt.Errorf("allow_severity must be one of: low, medium, high, critical")
+	}
+	return nil
+}
+
+// contains reports whether t falls within the quiet hours. Invalid quiet hours never
+// contain a time.
+func (q *QuietHours) contains(t time.Time) bool {
+	start, err := parseClock(q.Start)
+	if err != nil {
+		return false
+	}
+	end, err := parseClock(q.End)
+	if err != nil {
+		return false
+	}
+	location, err := time.LoadLocation(q.Timezone)
+	if err != nil {
+		return false
+	}
+
+	local := t.In(location)
+	minute := local.Hour()*60 + local.Minute()
+	if start < end {
+		return minute >= start && minute < end
+	}
+	return minute >= start || minute < end
+}
+
+// allows reports whether an incident is delivered during quiet hours
+func (q *QuietHours) allows(incident *models.Incident) bool {
+	return q.AllowSeverity != "" && incident != nil && incident.Severity.Rank() >= q.AllowSeverity.Rank()
+}
+
+// parseClock parses a time of day (HH:MM) into minutes since midnight
+func parseClock(value string) (int, error) {
+	parsed, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("must be a time of day (HH:MM): %q", value)
+	}
+	return parsed.Hour()*60 + parsed.Minute(), nil
+}
+
+// throttled reports whether the target limits or holds its events
+func (t *Target) throttled() bool {
+	return t.MaxPerHour > 0 || t.QuietHours != nil
+}
+
+// quiet reports whether the target holds events at now
+func (t *Target) quiet(now time.Time) bool {
+	return t.QuietHours != nil && t.QuietHours.contains(now)
+}
+
+// throttle is the recent deliveries and held events of a throttled target
+type throttle struct {
+	target  Target
+	sent    []time.Time
+	held    []*Event
+	omitted int
+}
+
+// prune forgets deliveries that left the window
+func (t *throttle) prune(now time.Time) {
+	keep := 0
+	for _, sent := range t.sent {
+		if now.Sub(sent) < throttleWindow {
+			t.sent[keep] = sent
+			keep++
+		}
+	}
+	t.sent = t.sent[:keep]
+}
+
+// hasCapacity reports whether the target can receive another message in the window
+func (t *throttle) hasCapacity() bool {
+	return t.target.MaxPerHour <= 0 || len(t.sent) < t.target.MaxPerHour
+}
+
+// hold keeps an event for the target's next digest
+func (t *throttle) hold(event *Event) {
+	if len(t.held) >= maxDigestEvents {
+		t.omitted++
+		return
+	}
+	t.held = append(t.held, event)
+}
+
+// admit reports whether an event is delivered to a target now, holding it for a
+// digest otherwise. Events wait behind held ones so digests keep their order, except
+// incidents allowed through quiet hours, which only wait for the rate limit.
+func (n *WebhookNotifier) admit(target *Target, event *Event) bool {
+	if !target.throttled() {
+		return true
+	}
+	now := n.now()
+
+	n.throttleMu.Lock()
+	defer n.throttleMu.Unlock()
+	state, ok := n.throttles[target.Name]
+	if !ok {
+		state = &throttle{}
+		n.throttles[target.Name] = state
+	}
+	state.target = *target
+	state.prune(now)
+
+	urgent := target.QuietHours != nil && target.QuietHours.allows(event.Incident)
+	if target.quiet(now) && !urgent {
+		state.hold(event)
+		return false
+	}
+	if state.hasCapacity() && (len(state.held) == 0 || urgent) {
+		state.sent = append(state.sent, now)
+		return true
+	}
+	state.hold(event)
+	return false
+}
+
+// runThrottles sends the digests of throttled targets until ctx is cancelled
+func (n *WebhookNotifier) runThrottles(ctx context.Context) {
+	ticker := time.NewTicker(throttleFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n.flushThrottles(ctx)
+		}
+	}
+}
+
+// flushThrottles sends a digest of its held events to every target that is outside
+// its quiet hours and below its rate limit
+func (n *WebhookNotifier) flushThrottles(ctx context.Context) {
+	now := n.now()
+
+	type digest struct {
+		target Target
+		event  *Event
+	}
+	var digests []digest
+
+	n.throttleMu.Lock()
+	for name, state := range n.throttles {
+		state.prune(now)
+		if len(state.held) == 0 {
+			if len(state.sent) == 0 {
+				delete(n.throttles, name)
+			}
+			continue
+		}
+		if state.target.quiet(now) || !state.hasCapacity() {
+			continue
+		}
+		digests = append(digests, digest{target: state.target, event: &Event{
+			ID:        uuid.New().String(),
+			Type:      EventDigest,
+			Timestamp: now.UTC(),
+			Events:    state.held,
+			Omitted:   state.omitted,
+		}})
+		state.sent = append(state.sent, now)
+		state.held = nil
+		state.omitted = 0
+	}
+	n.throttleMu.Unlock()
+
+	for _, d := range digests {
+		body, err := json.Marshal(d.event)
+		if err != nil {
+			n.log.WithError(err).WithField("target", d.target.Name).Error("Failed to marshal webhook digest")
+			continue
+		}
+		n.log.WithFields(logrus.Fields{
+			"target":  d.target.Name,
+			"events":  len(d.event.Events),
+			"omitted": d.event.Omitted,
+		}).Info("Sending webhook digest of held events")
+		n.Deliver(ctx, d.target, d.event.ID, EventDigest, body)
+	}
+}
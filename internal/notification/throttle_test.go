@@ -0,0 +1,130 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+func TestQuietHours_Contains(t *testing.T) {
+	overnight := &QuietHours{Start: "22:00", End: "07:00", Timezone: "Europe/Berlin"}
+	require.NoError(t, overnight.Validate())
+	// Berlin is UTC+2 in June
+	assert.True(t, overnight.contains(time.Date(2025, 6, 1, 1, 0, 0, 0, time.UTC)))
+	assert.True(t, overnight.contains(time.Date(2025, 6, 1, 20, 0, 0, 0, time.UTC)))
+	assert.False(t, overnight.contains(time.Date(2025, 6, 1, 5, 0, 0, 0, time.UTC)), "07:00 local ends quiet hours")
+	assert.False(t, overnight.contains(time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)))
+
+	lunch := &QuietHours{Start: "12:00", End: "13:00"}
+	assert.True(t, lunch.contains(time.Date(2025, 6, 1, 12, 30, 0, 0, time.UTC)))
+	assert.False(t, lunch.contains(time.Date(2025, 6, 1, 13, 0, 0, 0, time.UTC)))
+
+	assert.EqualError(t, (&QuietHours{Start: "22:00", End: "22:00"}).Validate(), "start and end must differ")
+	assert.EqualError(t, (&QuietHours{Start: "22:00", End: "07:00", Timezone: "Mars/Olympus"}).Validate(), `invalid timezone "Mars/Olympus"`)
+	assert.Error(t, (&QuietHours{Start: "22:00", End: "07:00", AllowSeverity: "urgent"}).Validate())
+}
+
+// digestOf decodes a delivered digest
+func digestOf(t *testing.T, body []byte) Event {
+	t.Helper()
+	var digest Event
+	require.NoError(t, json.Unmarshal(body, &digest))
+	require.Equal(t, EventDigest, digest.Type)
+	return digest
+}
+
+func TestWebhookNotifier_RateLimitDigest(t *testing.T) {
+	slack := newReceiver(t)
+	audit := newReceiver(t)
+	notifier, _ := newTestNotifier(t, []Target{
+		{Name: "slack", URL: slack.server.URL, Events: []EventType{EventAll}, MaxPerHour: 2},
+		{Name: "audit", URL: audit.server.URL, Events: []EventType{EventAll}},
+	}, Options{})
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	notifier.now = func() time.Time { return now }
+
+	for i := 0; i < 5; i++ {
+		notifier.Notify(context.Background(), testEvent(EventIncidentCreated))
+		now = now.Add(time.Minute)
+	}
+	notifier.Wait()
+	assert.Equal(t, 2, slack.count(), "events beyond the limit are held")
+	assert.Equal(t, 5, audit.count(), "unthrottled targets are unaffected")
+
+	// The limit still applies within the hour
+	notifier.flushThrottles(context.Background())
+	notifier.Wait()
+	assert.Equal(t, 2, slack.count())
+
+	// Once the first delivery leaves the window, the held events go out as one digest
+	now = time.Date(2025, 6, 1, 13, 0, 0, 0, time.UTC)
+	notifier.flushThrottles(context.Background())
+	notifier.Wait()
+	require.Equal(t, 3, slack.count())
+	digest := digestOf(t, slack.bodies[2])
+	assert.Len(t, digest.Events, 3)
+	assert.Equal(t, string(EventDigest), slack.requests[2].Header.Get(HeaderEvent))
+	assert.Len(t, notifier.Deliveries().List(DeliveryFilter{EventType: EventDigest}), 1)
+
+	notifier.flushThrottles(context.Background())
+	notifier.Wait()
+	assert.Equal(t, 3, slack.count(), "digests are only sent for held events")
+}
+
+func TestWebhookNotifier_QuietHours(t *testing.T) {
+	slack := newReceiver(t)
+	notifier, _ := newTestNotifier(t, []Target{{
+		Name:       "slack",
+		URL:        slack.server.URL,
+		Events:     []EventType{EventAll},
+		QuietHours: &QuietHours{Start: "22:00", End: "07:00", AllowSeverity: models.IncidentSeverityCritical},
+	}}, Options{})
+	now := time.Date(2025, 6, 1, 3, 0, 0, 0, time.UTC)
+	notifier.now = func() time.Time { return now }
+
+	notifier.Notify(context.Background(), testEvent(EventIncidentCreated))
+	notifier.Notify(context.Background(), testEvent(EventIncidentUpdated))
+	critical := testEvent(EventIncidentCreated)
+	critical.Incident.Severity = models.IncidentSeverityCritical
+	notifier.Notify(context.Background(), critical)
+	notifier.flushThrottles(context.Background())
+	notifier.Wait()
+	require.Equal(t, 1, slack.count(), "only critical incidents get through quiet hours")
+
+	now = time.Date(2025, 6, 1, 7, 1, 0, 0, time.UTC)
+	notifier.flushThrottles(context.Background())
+	notifier.Wait()
+	require.Equal(t, 2, slack.count())
+	digest := digestOf(t, slack.bodies[1])
+	require.Len(t, digest.Events, 2)
+	assert.Equal(t, EventIncidentCreated, digest.Events[0].Type)
+	assert.Equal(t, EventIncidentUpdated, digest.Events[1].Type)
+}
+
+func TestWebhookNotifier_DigestOmitsOverflow(t *testing.T) {
+	slack := newReceiver(t)
+	notifier, _ := newTestNotifier(t, []Target{{
+		Name: "slack", URL: slack.server.URL, Events: []EventType{EventAll},
+		QuietHours: &QuietHours{Start: "00:00", End: "06:00"},
+	}}, Options{})
+	now := time.Date(2025, 6, 1, 1, 0, 0, 0, time.UTC)
+	notifier.now = func() time.Time { return now }
+
+	for i := 0; i < maxDigestEvents+5; i++ {
+		notifier.Notify(context.Background(), testEvent(EventIncidentUpdated))
+	}
+	now = now.Add(6 * time.Hour)
+	notifier.flushThrottles(context.Background())
+	notifier.Wait()
+
+	require.Equal(t, 1, slack.count())
+	digest := digestOf(t, slack.bodies[0])
+	assert.Len(t, digest.Events, maxDigestEvents)
+	assert.Equal(t, 5, digest.Omitted)
+}
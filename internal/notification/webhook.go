@@ -0,0 +1,556 @@
+// Package notification delivers incident events to outbound webhooks and email.
+//
+// Each webhook target subscribes to a set of event types. Payloads are JSON, signed with
+// HMAC-SHA256 when the target has a secret, and retried with exponential backoff on
+// network errors, 5xx and 429 responses. Every delivery is recorded in an in-memory
+// log served at GET /api/v1/notifications/deliveries.
+//
+// Email teams receive immediate alerts for incidents at or above their alert severity
+// and optional daily or weekly digests, rendered from customizable Go templates.
+package notification
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"sigs.k8s.io/yaml"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/storage"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// EventType identifies the kind of event a webhook is sent for
+type EventType string
+
+// Event types
+const (
+	EventIncidentCreated  EventType = "incident.created"
+	EventIncidentUpdated  EventType = "incident.updated"
+	EventIncidentResolved EventType = "incident.resolved"
+	EventIncidentDeleted  EventType = "incident.deleted"
+
+	// EventIncidentEscalated is sent when an escalation ladder pages a human for a
+	// recurring incident
+	EventIncidentEscalated EventType = "incident.escalated"
+
+	// EventAll subscribes a target to every event type
+	EventAll EventType = "*"
+
+	// EventAnomalyScanCompleted is sent to anomaly subscriptions after each scheduled
+	// scan; targets from the webhook file cannot subscribe to it
+	EventAnomalyScanCompleted EventType = "anomaly.scan_completed"
+)
+
+// validEventTypes lists the event types a target can subscribe to
+var validEventTypes = map[EventType]bool{
+	EventIncidentCreated:   true,
+	EventIncidentUpdated:   true,
+	EventIncidentResolved:  true,
+	EventIncidentDeleted:   true,
+	EventIncidentEscalated: true,
+	EventAll:               true,
+}
+
+// Request headers sent with every delivery
+const (
+	HeaderEvent     = "X-Coordination-Event"
+	HeaderDelivery  = "X-Coordination-Delivery"
+	HeaderTimestamp = "X-Coordination-Timestamp"
+	HeaderSignature = "X-Coordination-Signature"
+)
+
+// Defaults for delivery retries
+const (
+	DefaultMaxAttempts    = 5
+	DefaultInitialBackoff = time.Second
+	DefaultMaxBackoff     = 5 * time.Minute
+	DefaultTimeout        = 10 * time.Second
+)
+
+// Target is a webhook endpoint and the events it receives
+type Target struct {
+	Name    string            `json:"name"`
+	URL     string            `json:"url"`
+	Events  []EventType       `json:"events"`
+	Secret  string            `json:"secret,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// Teams limits the target to incidents owned by these teams, e.g. an on-call
+	// rotation's pager; empty receives incidents of every owner
+	Teams []string `json:"teams,omitempty"`
+
+	// MaxPerHour limits the messages sent to the target in any hour; further events are
+	// held and sent as one digest when the limit allows (0 is unlimited)
+	MaxPerHour int `json:"max_per_hour,omitempty"`
+
+	// QuietHours holds events for a digest during a daily window
+	QuietHours *QuietHours `json:"quiet_hours,omitempty"`
+}
+
+// Validate checks the target configuration
+func (t *Target) Validate() error {
+	if t.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if !strings.HasPrefix(t.URL, "http://") && !strings.HasPrefix(t.URL, "https://") {
+		return fmt.Errorf("target %s: url must start with http:// or https://", t.Name)
+	}
+	if len(t.Events) == 0 {
+		return fmt.Errorf("target %s: at least one event type is required", t.Name)
+	}
+	for _, event := range t.Events {
+		if !validEventTypes[event] {
+			return fmt.Errorf("target %s: unknown event type %q", t.Name, event)
+		}
+	}
+	if t.MaxPerHour < 0 {
+		return fmt.Errorf("target %s: max_per_hour cannot be negative: %d", t.Name, t.MaxPerHour)
+	}
+	if t.QuietHours != nil {
+		if err := t.QuietHours.Validate(); err != nil {
+			return fmt.Errorf("target %s: quiet_hours: %w", t.Name, err)
+		}
+	}
+	return nil
+}
+
+// subscribes reports whether the target receives the event type
+func (t *Target) subscribes(eventType EventType) bool {
+	for _, event := range t.Events {
+		if event == EventAll || event == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// owns reports whether the target receives events of an incident by its owner
+func (t *Target) owns(incident *models.Incident) bool {
+	if len(t.Teams) == 0 {
+		return true
+	}
+	if incident == nil || incident.Owner == nil {
+		return false
+	}
+	for _, team := range t.Teams {
+		if team == incident.Owner.Team {
+			return true
+		}
+	}
+	return false
+}
+
+// targetsFile is the on-disk format of the webhook target list
+type targetsFile struct {
+	Targets []Target `json:"targets"`
+}
+
+// LoadTargets reads webhook targets from a YAML or JSON file of the form {"targets": [...]}
+func LoadTargets(path string) ([]Target, error) {
+	data, err := os.ReadFile(path) //#nosec G304 -- path comes from operator configuration
+	if err != nil {
+		return nil, fmt.Errorf("failed to read webhook targets file: %w", err)
+	}
+
+	var file targetsFile
+	if err := yaml.UnmarshalStrict(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse webhook targets file %s: %w", path, err)
+	}
+	return file.Targets, nil
+}
+
+// Event is the JSON payload delivered to webhook targets
+type Event struct {
+	ID        string           `json:"id"`
+	Type      EventType        `json:"type"`
+	Timestamp time.Time        `json:"timestamp"`
+	Incident  *models.Incident `json:"incident,omitempty"`
+
+	// Events and Omitted are set on digests: the held events, oldest first, and how
+	// many more were held than a digest carries
+	Events  []*Event `json:"events,omitempty"`
+	Omitted int      `json:"omitted,omitempty"`
+}
+
+// Options configures delivery retries
+type Options struct {
+	// MaxAttempts is the total number of attempts per delivery, including the first
+	MaxAttempts int
+	// InitialBackoff is the wait before the first retry; it doubles for each retry
+	InitialBackoff time.Duration
+	// MaxBackoff caps the wait between retries
+	MaxBackoff time.Duration
+	// Timeout bounds a single attempt
+	Timeout time.Duration
+	// DefaultSecret signs payloads for targets without their own secret
+	DefaultSecret string
+}
+
+// WebhookNotifier delivers events to webhook targets
+type WebhookNotifier struct {
+	targets    []Target
+	opts       Options
+	httpClient *http.Client
+	deliveries *DeliveryLog
+	log        *logrus.Logger
+
+	// statuses tracks the last seen status of each incident to detect resolution, and
+	// escalations the number of escalated events in its timeline to detect pages
+	mu          sync.Mutex
+	statuses    map[string]models.IncidentStatus
+	escalations map[string]int
+
+	// wg tracks in-flight deliveries
+	wg sync.WaitGroup
+
+	// extraTargets, namespaceTargets and silenced are set by SetTargetSource,
+	// SetNamespaceTargetSource and SetSilencer
+	extraTargets     func() []Target
+	namespaceTargets func(namespace string) []Target
+	silenced         func(incident *models.Incident) string
+
+	// throttles tracks the recent deliveries and held events of throttled targets by name
+	throttleMu sync.Mutex
+	throttles  map[string]*throttle
+
+	// sleep waits between retries and now reads the clock; both are replaceable in tests
+	sleep func(ctx context.Context, d time.Duration) bool
+	now   func() time.Time
+}
+
+// NewWebhookNotifier creates a notifier, rejecting invalid or duplicate targets.
+// Zero options use the package defaults.
+func NewWebhookNotifier(targets []Target, opts Options, log *logrus.Logger) (*WebhookNotifier, error) {
+	seen := make(map[string]bool, len(targets))
+	for i := range targets {
+		if err := targets[i].Validate(); err != nil {
+			return nil, err
+		}
+		if seen[targets[i].Name] {
+			return nil, fmt.Errorf("duplicate webhook target name: %s", targets[i].Name)
+		}
+		seen[targets[i].Name] = true
+	}
+
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = DefaultMaxAttempts
+	}
+	if opts.InitialBackoff <= 0 {
+		opts.InitialBackoff = DefaultInitialBackoff
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = DefaultMaxBackoff
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+
+	return &WebhookNotifier{
+		targets:     targets,
+		opts:        opts,
+		httpClient:  &http.Client{Timeout: opts.Timeout},
+		deliveries:  NewDeliveryLog(DefaultDeliveryLogSize),
+		log:         log,
+		statuses:    make(map[string]models.IncidentStatus),
+		escalations: make(map[string]int),
+		throttles:   make(map[string]*throttle),
+		sleep:       sleepContext,
+		now:         time.Now,
+	}, nil
+}
+
+// SetTargetSource adds targets managed at runtime, e.g. notification channels created
+// through the admin API. The source is called for every event and its targets must
+// be valid.
+func (n *WebhookNotifier) SetTargetSource(source func() []Target) {
+	n.extraTargets = source
+}
+
+// SetNamespaceTargetSource adds targets that only receive the events of incidents in
+// a namespace, e.g. the notification targets of tenants. The source is called with
+// the incident's target namespace for every incident event and its targets must be
+// valid.
+func (n *WebhookNotifier) SetNamespaceTargetSource(source func(namespace string) []Target) {
+	n.namespaceTargets = source
+}
+
+// SetSilencer suppresses incident events while silenced returns a non-empty silence
+// name for the incident
+func (n *WebhookNotifier) SetSilencer(silenced func(incident *models.Incident) string) {
+	n.silenced = silenced
+}
+
+// HTTPClient returns the client used to reach webhook targets, e.g. to audit its TLS configuration
+func (n *WebhookNotifier) HTTPClient() *http.Client {
+	return n.httpClient
+}
+
+// Deliveries returns the delivery log
+func (n *WebhookNotifier) Deliveries() *DeliveryLog {
+	return n.deliveries
+}
+
+// Start sends webhooks for incident changes, and digests of the events throttled
+// targets were held from, until ctx is cancelled. It subscribes to the store before
+// returning, so no change made after Start is missed.
+func (n *WebhookNotifier) Start(ctx context.Context, store *storage.IncidentStore) {
+	changes, unsubscribe := store.Subscribe(256)
+
+	go func() {
+		defer unsubscribe()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case change, ok := <-changes:
+				if !ok {
+					return
+				}
+				n.Notify(ctx, n.eventFor(&change))
+			}
+		}
+	}()
+
+	go n.runThrottles(ctx)
+
+	n.log.WithField("targets", len(n.targets)).Info("Webhook notifier started")
+}
+
+// Wait blocks until in-flight deliveries have finished
+func (n *WebhookNotifier) Wait() {
+	n.wg.Wait()
+}
+
+// eventFor maps an incident store change to a webhook event. An update that moves an
+// incident to resolved is reported as incident.resolved, and one that adds an
+// escalated event to its timeline as incident.escalated.
+func (n *WebhookNotifier) eventFor(change *storage.IncidentChange) *Event {
+	incident := change.Incident
+	escalations := countEscalations(&incident)
+
+	n.mu.Lock()
+	previous, known := n.statuses[incident.ID]
+	previousEscalations, seen := n.escalations[incident.ID]
+	if change.Type == storage.IncidentDeleted {
+		delete(n.statuses, incident.ID)
+		delete(n.escalations, incident.ID)
+	} else {
+		n.statuses[incident.ID] = incident.Status
+		n.escalations[incident.ID] = escalations
+	}
+	n.mu.Unlock()
+
+	// Without a previous count, e.g. after a restart, only an escalation at the end
+	// of the timeline is new
+	escalated := escalations > previousEscalations &&
+		(seen || incident.Events[len(incident.Events)-1].Type == models.IncidentEventEscalated)
+
+	eventType := EventIncidentUpdated
+	switch {
+	case change.Type == storage.IncidentCreated:
+		eventType = EventIncidentCreated
+	case change.Type == storage.IncidentDeleted:
+		eventType = EventIncidentDeleted
+	case incident.Status == models.IncidentStatusResolved && (!known || previous != models.IncidentStatusResolved):
+		eventType = EventIncidentResolved
+	case escalated:
+		eventType = EventIncidentEscalated
+	}
+
+	return &Event{
+		ID:        "evt-" + uuid.New().String(),
+		Type:      eventType,
+		Timestamp: time.Now().UTC(),
+		Incident:  &incident,
+	}
+}
+
+// countEscalations counts the escalated events in an incident's timeline
+func countEscalations(incident *models.Incident) int {
+	count := 0
+	for _, event := range incident.Events {
+		if event.Type == models.IncidentEventEscalated {
+			count++
+		}
+	}
+	return count
+}
+
+// Notify delivers the event to every subscribed target routed its incident's owner in
+// the background, unless the incident is silenced. Throttled targets may hold the
+// event for a later digest.
+func (n *WebhookNotifier) Notify(ctx context.Context, event *Event) {
+	if event.Incident != nil && n.silenced != nil {
+		if silence := n.silenced(event.Incident); silence != "" {
+			n.log.WithFields(logrus.Fields{
+				"event_type":  event.Type,
+				"incident_id": event.Incident.ID,
+				"silence":     silence,
+			}).Debug("Webhook event silenced")
+			return
+		}
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		n.log.WithError(err).WithField("event_type", event.Type).Error("Failed to marshal webhook event")
+		return
+	}
+
+	targets := n.targets
+	if n.extraTargets != nil {
+		targets = append(targets[:len(targets):len(targets)], n.extraTargets()...)
+	}
+	if n.namespaceTargets != nil && event.Incident != nil && event.Incident.Target != "" {
+		targets = append(targets[:len(targets):len(targets)], n.namespaceTargets(event.Incident.Target)...)
+	}
+	for i := range targets {
+		if targets[i].subscribes(event.Type) && targets[i].owns(event.Incident) && n.admit(&targets[i], event) {
+			n.Deliver(ctx, targets[i], event.ID, event.Type, body)
+		}
+	}
+}
+
+// Deliver sends a payload to a target in the background, with the same signing,
+// retries and delivery log as incident events. It is used for targets registered
+// at runtime, such as anomaly subscriptions; the target is not validated.
+func (n *WebhookNotifier) Deliver(ctx context.Context, target Target, eventID string, eventType EventType, body []byte) {
+	delivery := n.deliveries.Add(&Delivery{
+		EventID:   eventID,
+		EventType: eventType,
+		Target:    target.Name,
+		URL:       target.URL,
+	})
+
+	n.wg.Add(1)
+	go func() {
+		defer n.wg.Done()
+		n.deliver(ctx, &target, delivery.ID, eventType, body)
+	}()
+}
+
+// deliver sends a payload to a target, retrying transient failures with exponential backoff
+func (n *WebhookNotifier) deliver(ctx context.Context, target *Target, deliveryID string, eventType EventType, body []byte) {
+	backoff := n.opts.InitialBackoff
+
+	for attempt := 1; attempt <= n.opts.MaxAttempts; attempt++ {
+		statusCode, err := n.send(ctx, target, deliveryID, eventType, body)
+		if err == nil {
+			n.deliveries.recordSuccess(deliveryID, attempt, statusCode)
+			n.log.WithFields(logrus.Fields{
+				"target":      target.Name,
+				"delivery_id": deliveryID,
+				"event_type":  eventType,
+				"attempts":    attempt,
+			}).Debug("Webhook delivered")
+			return
+		}
+
+		retryable := isRetryable(statusCode)
+		if !retryable || attempt == n.opts.MaxAttempts {
+			n.deliveries.recordFailure(deliveryID, attempt, statusCode, err, time.Time{})
+			n.log.WithError(err).WithFields(logrus.Fields{
+				"target":      target.Name,
+				"delivery_id": deliveryID,
+				"event_type":  eventType,
+				"attempts":    attempt,
+			}).Warn("Webhook delivery failed")
+			return
+		}
+
+		n.deliveries.recordFailure(deliveryID, attempt, statusCode, err, time.Now().Add(backoff))
+		if !n.sleep(ctx, backoff) {
+			n.deliveries.recordFailure(deliveryID, attempt, statusCode, fmt.Errorf("delivery abandoned at shutdown: %w", err), time.Time{})
+			return
+		}
+		backoff = min(backoff*2, n.opts.MaxBackoff)
+	}
+}
+
+// send makes one delivery attempt. A zero status code means no response was received.
+func (n *WebhookNotifier) send(ctx context.Context, target *Target, deliveryID string, eventType EventType, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	for name, value := range target.Headers {
+		req.Header.Set(name, value)
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "openshift-coordination-engine")
+	req.Header.Set(HeaderEvent, string(eventType))
+	req.Header.Set(HeaderDelivery, deliveryID)
+	req.Header.Set(HeaderTimestamp, timestamp)
+
+	secret := target.Secret
+	if secret == "" {
+		secret = n.opts.DefaultSecret
+	}
+	if secret != "" {
+		req.Header.Set(HeaderSignature, Sign(secret, timestamp, body))
+	}
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("request failed: %w", err)
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			n.log.WithError(closeErr).Warn("Failed to close response body")
+		}
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, readErr := io.ReadAll(io.LimitReader(resp.Body, 512))
+		if readErr != nil {
+			return resp.StatusCode, fmt.Errorf("unexpected status %d, failed to read body: %w", resp.StatusCode, readErr)
+		}
+		return resp.StatusCode, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	return resp.StatusCode, nil
+}
+
+// Sign returns the signature header value for a payload: "sha256=" followed by the
+// hex HMAC-SHA256 of "<timestamp>.<body>". Including the timestamp lets receivers
+// reject replayed deliveries.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// isRetryable reports whether a failed attempt should be retried: network errors
+// (no status), rate limiting and server errors are transient, other 4xx are not
+func isRetryable(statusCode int) bool {
+	return statusCode == 0 || statusCode == http.StatusTooManyRequests || statusCode >= 500
+}
+
+// sleepContext waits for d, returning false if ctx is cancelled first
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
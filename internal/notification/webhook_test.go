@@ -0,0 +1,461 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/storage"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+func testLogger() *logrus.Logger {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	return log
+}
+
+// receiver is a webhook endpoint that records requests and replies with queued status codes
+type receiver struct {
+	mu       sync.Mutex
+	requests []*http.Request
+	bodies   [][]byte
+	statuses []int
+	server   *httptest.Server
+}
+
+func newReceiver(t *testing.T, statuses ...int) *receiver {
+	t.Helper()
+	r := &receiver{statuses: statuses}
+	r.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		r.mu.Lock()
+		r.requests = append(r.requests, req)
+		r.bodies = append(r.bodies, body)
+		status := http.StatusOK
+		if len(r.statuses) > 0 {
+			status = r.statuses[0]
+			r.statuses = r.statuses[1:]
+		}
+		r.mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(r.server.Close)
+	return r
+}
+
+func (r *receiver) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.requests)
+}
+
+func newTestNotifier(t *testing.T, targets []Target, opts Options) (*WebhookNotifier, *[]time.Duration) {
+	t.Helper()
+	notifier, err := NewWebhookNotifier(targets, opts, testLogger())
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	waits := &[]time.Duration{}
+	notifier.sleep = func(_ context.Context, d time.Duration) bool {
+		mu.Lock()
+		defer mu.Unlock()
+		*waits = append(*waits, d)
+		return true
+	}
+	return notifier, waits
+}
+
+func testEvent(eventType EventType) *Event {
+	return &Event{
+		ID:        "evt-1",
+		Type:      eventType,
+		Timestamp: time.Now().UTC(),
+		Incident:  &models.Incident{ID: "inc-1", Title: "Pods crash looping", Severity: models.IncidentSeverityHigh},
+	}
+}
+
+func TestWebhookNotifier_SignsPayload(t *testing.T) {
+	recv := newReceiver(t)
+	notifier, _ := newTestNotifier(t, []Target{{
+		Name:    "pager",
+		URL:     recv.server.URL,
+		Events:  []EventType{EventIncidentCreated},
+		Secret:  "s3cret",
+		Headers: map[string]string{"X-Team": "sre"},
+	}}, Options{})
+
+	notifier.Notify(context.Background(), testEvent(EventIncidentCreated))
+	notifier.Wait()
+
+	require.Equal(t, 1, recv.count())
+	req, body := recv.requests[0], recv.bodies[0]
+	assert.Equal(t, "incident.created", req.Header.Get(HeaderEvent))
+	assert.Equal(t, "sre", req.Header.Get("X-Team"))
+	assert.NotEmpty(t, req.Header.Get(HeaderDelivery))
+	assert.Equal(t, Sign("s3cret", req.Header.Get(HeaderTimestamp), body), req.Header.Get(HeaderSignature))
+	assert.NotEqual(t, Sign("other", req.Header.Get(HeaderTimestamp), body), req.Header.Get(HeaderSignature))
+
+	var event Event
+	require.NoError(t, json.Unmarshal(body, &event))
+	assert.Equal(t, "inc-1", event.Incident.ID)
+
+	deliveries := notifier.Deliveries().List(DeliveryFilter{})
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, DeliveryDelivered, deliveries[0].Status)
+	assert.Equal(t, 1, deliveries[0].Attempts)
+	assert.Equal(t, req.Header.Get(HeaderDelivery), deliveries[0].ID)
+}
+
+func TestWebhookNotifier_DefaultSecretAndUnsigned(t *testing.T) {
+	recv := newReceiver(t)
+	target := Target{Name: "chat", URL: recv.server.URL, Events: []EventType{EventAll}}
+
+	notifier, _ := newTestNotifier(t, []Target{target}, Options{DefaultSecret: "shared"})
+	notifier.Notify(context.Background(), testEvent(EventIncidentDeleted))
+	notifier.Wait()
+	require.Equal(t, 1, recv.count())
+	assert.Equal(t, Sign("shared", recv.requests[0].Header.Get(HeaderTimestamp), recv.bodies[0]), recv.requests[0].Header.Get(HeaderSignature))
+
+	notifier, _ = newTestNotifier(t, []Target{target}, Options{})
+	notifier.Notify(context.Background(), testEvent(EventIncidentDeleted))
+	notifier.Wait()
+	require.Equal(t, 2, recv.count())
+	assert.Empty(t, recv.requests[1].Header.Get(HeaderSignature))
+}
+
+func TestWebhookNotifier_RetriesWithBackoff(t *testing.T) {
+	recv := newReceiver(t, http.StatusBadGateway, http.StatusTooManyRequests, http.StatusOK)
+	notifier, waits := newTestNotifier(t, []Target{{
+		Name:   "pager",
+		URL:    recv.server.URL,
+		Events: []EventType{EventAll},
+	}}, Options{InitialBackoff: time.Second, MaxBackoff: time.Minute})
+
+	notifier.Notify(context.Background(), testEvent(EventIncidentCreated))
+	notifier.Wait()
+
+	assert.Equal(t, 3, recv.count())
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second}, *waits)
+	assert.Equal(t, recv.requests[0].Header.Get(HeaderDelivery), recv.requests[2].Header.Get(HeaderDelivery),
+		"retries reuse the delivery ID")
+
+	delivery := notifier.Deliveries().List(DeliveryFilter{})[0]
+	assert.Equal(t, DeliveryDelivered, delivery.Status)
+	assert.Equal(t, 3, delivery.Attempts)
+	assert.Empty(t, delivery.LastError)
+}
+
+func TestWebhookNotifier_GivesUp(t *testing.T) {
+	recv := newReceiver(t, http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable)
+	notifier, waits := newTestNotifier(t, []Target{{
+		Name:   "pager",
+		URL:    recv.server.URL,
+		Events: []EventType{EventAll},
+	}}, Options{MaxAttempts: 4, InitialBackoff: time.Second, MaxBackoff: 3 * time.Second})
+
+	notifier.Notify(context.Background(), testEvent(EventIncidentCreated))
+	notifier.Wait()
+
+	assert.Equal(t, 4, recv.count())
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second, 3 * time.Second}, *waits, "backoff is capped")
+
+	delivery := notifier.Deliveries().List(DeliveryFilter{})[0]
+	assert.Equal(t, DeliveryFailed, delivery.Status)
+	assert.Equal(t, 4, delivery.Attempts)
+	assert.Equal(t, http.StatusServiceUnavailable, delivery.LastStatusCode)
+	assert.Contains(t, delivery.LastError, "unexpected status 503")
+	assert.Nil(t, delivery.NextAttemptAt)
+}
+
+func TestWebhookNotifier_ClientErrorNotRetried(t *testing.T) {
+	recv := newReceiver(t, http.StatusBadRequest)
+	notifier, waits := newTestNotifier(t, []Target{{
+		Name:   "pager",
+		URL:    recv.server.URL,
+		Events: []EventType{EventAll},
+	}}, Options{})
+
+	notifier.Notify(context.Background(), testEvent(EventIncidentCreated))
+	notifier.Wait()
+
+	assert.Equal(t, 1, recv.count())
+	assert.Empty(t, *waits)
+	assert.Equal(t, DeliveryFailed, notifier.Deliveries().List(DeliveryFilter{})[0].Status)
+}
+
+func TestWebhookNotifier_EventRouting(t *testing.T) {
+	pager := newReceiver(t)
+	audit := newReceiver(t)
+	notifier, _ := newTestNotifier(t, []Target{
+		{Name: "pager", URL: pager.server.URL, Events: []EventType{EventIncidentCreated, EventIncidentResolved}},
+		{Name: "audit", URL: audit.server.URL, Events: []EventType{EventAll}},
+	}, Options{})
+
+	for _, eventType := range []EventType{EventIncidentCreated, EventIncidentUpdated, EventIncidentResolved} {
+		notifier.Notify(context.Background(), testEvent(eventType))
+	}
+	notifier.Wait()
+
+	assert.Equal(t, 2, pager.count())
+	assert.Equal(t, 3, audit.count())
+	assert.Len(t, notifier.Deliveries().List(DeliveryFilter{Target: "pager"}), 2)
+	assert.Len(t, notifier.Deliveries().List(DeliveryFilter{EventType: EventIncidentUpdated}), 1)
+}
+
+func TestWebhookNotifier_OwnerRouting(t *testing.T) {
+	payments := newReceiver(t)
+	audit := newReceiver(t)
+	notifier, _ := newTestNotifier(t, []Target{
+		{Name: "payments-pager", URL: payments.server.URL, Events: []EventType{EventAll}, Teams: []string{"payments"}},
+		{Name: "audit", URL: audit.server.URL, Events: []EventType{EventAll}},
+	}, Options{})
+
+	owned := testEvent(EventIncidentCreated)
+	owned.Incident.Owner = &models.Owner{Team: "payments", SlackChannel: "#payments-oncall"}
+	notifier.Notify(context.Background(), owned)
+	other := testEvent(EventIncidentCreated)
+	other.Incident.Owner = &models.Owner{Team: "search"}
+	notifier.Notify(context.Background(), other)
+	notifier.Notify(context.Background(), testEvent(EventIncidentCreated))
+	notifier.Wait()
+
+	require.Equal(t, 1, payments.count())
+	assert.Contains(t, string(payments.bodies[0]), `"slack_channel":"#payments-oncall"`)
+	assert.Equal(t, 3, audit.count())
+}
+
+func TestWebhookNotifier_TargetSourceAndSilencer(t *testing.T) {
+	file := newReceiver(t)
+	channel := newReceiver(t)
+	notifier, _ := newTestNotifier(t, []Target{
+		{Name: "file", URL: file.server.URL, Events: []EventType{EventAll}},
+	}, Options{})
+	notifier.SetTargetSource(func() []Target {
+		return []Target{{Name: "channel/oncall", URL: channel.server.URL, Events: []EventType{EventIncidentCreated}}}
+	})
+	silenced := false
+	notifier.SetSilencer(func(*models.Incident) string {
+		if silenced {
+			return "maintenance"
+		}
+		return ""
+	})
+
+	notifier.Notify(context.Background(), testEvent(EventIncidentCreated))
+	notifier.Notify(context.Background(), testEvent(EventIncidentUpdated))
+	silenced = true
+	notifier.Notify(context.Background(), testEvent(EventIncidentCreated))
+	notifier.Wait()
+
+	assert.Equal(t, 2, file.count())
+	assert.Equal(t, 1, channel.count())
+	assert.Len(t, notifier.Deliveries().List(DeliveryFilter{Target: "channel/oncall"}), 1)
+}
+
+func TestWebhookNotifier_NamespaceTargetSource(t *testing.T) {
+	tenant := newReceiver(t)
+	notifier, _ := newTestNotifier(t, nil, Options{})
+	notifier.SetNamespaceTargetSource(func(namespace string) []Target {
+		if namespace != "payments" {
+			return nil
+		}
+		return []Target{{Name: "tenant/payments/0", URL: tenant.server.URL, Events: []EventType{EventAll}}}
+	})
+
+	event := testEvent(EventIncidentCreated)
+	event.Incident.Target = "payments"
+	notifier.Notify(context.Background(), event)
+	notifier.Notify(context.Background(), testEvent(EventIncidentCreated))
+	notifier.Wait()
+
+	assert.Equal(t, 1, tenant.count())
+	assert.Len(t, notifier.Deliveries().List(DeliveryFilter{Target: "tenant/payments/0"}), 1)
+}
+
+func TestWebhookNotifier_Start(t *testing.T) {
+	store := storage.NewIncidentStoreWithPath(t.TempDir())
+	recv := newReceiver(t)
+	notifier, _ := newTestNotifier(t, []Target{{Name: "audit", URL: recv.server.URL, Events: []EventType{EventAll}}}, Options{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	notifier.Start(ctx, store)
+
+	incident, err := store.Create(&models.Incident{
+		Title:       "Pods crash looping",
+		Description: "api pods in CrashLoopBackOff",
+		Severity:    models.IncidentSeverityHigh,
+		Target:      "production",
+	})
+	require.NoError(t, err)
+
+	updated := *incident
+	updated.Description = "api pods restarting"
+	require.NoError(t, store.Update(&updated))
+	resolved := updated
+	resolved.Resolve()
+	require.NoError(t, store.Update(&resolved))
+	resolved.Description = "root cause found"
+	require.NoError(t, store.Update(&resolved))
+	require.NoError(t, store.Delete(incident.ID))
+
+	require.Eventually(t, func() bool { return recv.count() == 5 }, 5*time.Second, 10*time.Millisecond)
+	notifier.Wait()
+
+	var types []EventType
+	for _, body := range recv.bodies {
+		var event Event
+		require.NoError(t, json.Unmarshal(body, &event))
+		types = append(types, event.Type)
+	}
+	// Deliveries run concurrently, so arrival order is not guaranteed
+	assert.ElementsMatch(t, []EventType{
+		EventIncidentCreated,
+		EventIncidentUpdated,
+		EventIncidentResolved,
+		EventIncidentUpdated,
+		EventIncidentDeleted,
+	}, types)
+}
+
+func TestWebhookNotifier_EscalatedEvents(t *testing.T) {
+	notifier, _ := newTestNotifier(t, nil, Options{})
+	incident := models.Incident{ID: "inc-1", Status: models.IncidentStatusActive}
+	eventType := func(changeType storage.IncidentChangeType) EventType {
+		return notifier.eventFor(&storage.IncidentChange{Type: changeType, Incident: incident}).Type
+	}
+
+	// An update after a restart is an escalation only if the escalation is the newest event
+	incident.AddEvent(models.IncidentEventEscalated, "paged")
+	incident.AddEvent(models.IncidentEventAggregated, "alert re-fired")
+	assert.Equal(t, EventIncidentUpdated, eventType(storage.IncidentUpdated))
+
+	incident.AddEvent(models.IncidentEventEscalated, "paged again")
+	assert.Equal(t, EventIncidentEscalated, eventType(storage.IncidentUpdated))
+	assert.Equal(t, EventIncidentUpdated, eventType(storage.IncidentUpdated), "the same escalation is reported once")
+}
+
+func TestNewWebhookNotifier_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		targets []Target
+		wantErr string
+	}{
+		{name: "missing name", targets: []Target{{URL: "https://hooks.example.com"}}, wantErr: "name is required"},
+		{name: "bad url", targets: []Target{{Name: "a", URL: "hooks.example.com", Events: []EventType{EventAll}}}, wantErr: "url must start with"},
+		{name: "no events", targets: []Target{{Name: "a", URL: "https://hooks.example.com"}}, wantErr: "at least one event type"},
+		{name: "unknown event", targets: []Target{{Name: "a", URL: "https://hooks.example.com", Events: []EventType{"incident.opened"}}}, wantErr: "unknown event type"},
+		{name: "negative rate", targets: []Target{{Name: "a", URL: "https://hooks.example.com", Events: []EventType{EventAll}, MaxPerHour: -1}}, wantErr: "max_per_hour cannot be negative"},
+		{
+			name:    "bad quiet hours",
+			targets: []Target{{Name: "a", URL: "https://hooks.example.com", Events: []EventType{EventAll}, QuietHours: &QuietHours{Start: "22:00", End: "7am"}}},
+			wantErr: `quiet_hours: end must be a time of day (HH:MM): "7am"`,
+		},
+		{
+			name: "duplicate",
+			targets: []Target{
+				{Name: "a", URL: "https://hooks.example.com", Events: []EventType{EventAll}},
+				{Name: "a", URL: "https://hooks.example.com", Events: []EventType{EventAll}},
+			},
+			wantErr: "duplicate webhook target name",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewWebhookNotifier(tt.targets, Options{}, testLogger())
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestLoadTargets(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "webhooks.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+targets:
+  - name: pager
+    url: https://hooks.example.com/pager
+    events: [incident.created, incident.resolved]
+    secret: s3cret
+`), 0o600))
+
+	targets, err := LoadTargets(path)
+	require.NoError(t, err)
+	require.Len(t, targets, 1)
+	assert.Equal(t, []EventType{EventIncidentCreated, EventIncidentResolved}, targets[0].Events)
+	assert.Equal(t, "s3cret", targets[0].Secret)
+
+	require.NoError(t, os.WriteFile(path, []byte("targets:\n  - name: a\n    event: [\"*\"]\n"), 0o600))
+	_, err = LoadTargets(path)
+	assert.Error(t, err, "unknown fields are rejected")
+}
+
+func TestDeliveryLog_Bounded(t *testing.T) {
+	log := NewDeliveryLog(2)
+	first := log.Add(&Delivery{Target: "a"})
+	log.Add(&Delivery{Target: "b"})
+	log.Add(&Delivery{Target: "c"})
+
+	_, ok := log.Get(first.ID)
+	assert.False(t, ok, "the oldest delivery is dropped")
+
+	deliveries := log.List(DeliveryFilter{})
+	require.Len(t, deliveries, 2)
+	assert.Equal(t, "c", deliveries[0].Target, "newest first")
+	assert.Len(t, log.List(DeliveryFilter{Limit: 1}), 1)
+}
+
+func TestDeliveriesAPI(t *testing.T) {
+	recv := newReceiver(t, http.StatusBadRequest)
+	notifier, _ := newTestNotifier(t, []Target{{Name: "pager", URL: recv.server.URL, Events: []EventType{EventAll}}}, Options{})
+	notifier.Notify(context.Background(), testEvent(EventIncidentCreated))
+	notifier.Notify(context.Background(), testEvent(EventIncidentUpdated))
+	notifier.Wait()
+
+	router := mux.NewRouter()
+	notifier.RegisterRoutes(router)
+
+	get := func(url string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, url, http.NoBody))
+		return rr
+	}
+
+	rr := get("/api/v1/notifications/deliveries?status=failed")
+	require.Equal(t, http.StatusOK, rr.Code)
+	var resp struct {
+		Deliveries []Delivery `json:"deliveries"`
+		Total      int        `json:"total"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	require.Equal(t, 1, resp.Total)
+	assert.Equal(t, "pager", resp.Deliveries[0].Target)
+
+	rr = get("/api/v1/notifications/deliveries/" + resp.Deliveries[0].ID)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	assert.Equal(t, http.StatusNotFound, get("/api/v1/notifications/deliveries/dlv-missing").Code)
+	assert.Equal(t, http.StatusBadRequest, get("/api/v1/notifications/deliveries?status=lost").Code)
+	assert.Equal(t, http.StatusBadRequest, get("/api/v1/notifications/deliveries?limit=0").Code)
+}
+
+func TestSleepContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.False(t, sleepContext(ctx, time.Hour))
+	assert.True(t, sleepContext(context.Background(), time.Millisecond))
+}
@@ -0,0 +1,21 @@
+package observer
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Blocked counts the operations observer mode refused, by source: the HTTP method of
+// a Kubernetes API request, or the API that rejected a remediation request
+var Blocked = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "coordination_engine_observer_blocked_total",
+		Help: "Mutating operations refused because the engine runs in observer mode",
+	},
+	[]string{"source"},
+)
+
+// RecordBlocked records a refused operation
+func RecordBlocked(source string) {
+	Blocked.WithLabelValues(source).Inc()
+}
@@ -0,0 +1,82 @@
+// Package observer implements the read-only observer mode of the engine.
+//
+// In observer mode every remediation capability stays compiled in and keeps
+// detecting, planning and reporting, but nothing is allowed to change the cluster:
+// the API rejects remediation requests and the Kubernetes clients refuse any
+// request that is not a read. It is meant for initial deployments, so operators can
+// review what the engine would do before trusting it to act.
+package observer
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"k8s.io/client-go/rest"
+)
+
+// Engine modes, reported with every API response and in the health response
+const (
+	ModeActive   = "active"
+	ModeObserver = "observer"
+)
+
+// ErrObserverMode is returned when a mutating operation is attempted in observer mode
+var ErrObserverMode = errors.New("observer mode: mutating operations are disabled")
+
+// reviewResources are created, but only to ask the API server a question; they do
+// not change the cluster and the engine needs them to verify its permissions
+var reviewResources = []string{
+	"/selfsubjectaccessreviews",
+	"/selfsubjectrulesreviews",
+	"/subjectaccessreviews",
+	"/localsubjectaccessreviews",
+	"/tokenreviews",
+}
+
+// Mode returns the mode name for the observer flag
+func Mode(observer bool) string {
+	if observer {
+		return ModeObserver
+	}
+	return ModeActive
+}
+
+// readOnlyTransport rejects Kubernetes API requests that could change the cluster
+type readOnlyTransport struct {
+	next http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *readOnlyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !Allowed(req) {
+		RecordBlocked(req.Method)
+		return nil, fmt.Errorf("%w: %s %s", ErrObserverMode, req.Method, req.URL.Path)
+	}
+	return t.next.RoundTrip(req)
+}
+
+// Allowed reports whether a Kubernetes API request is permitted in observer mode:
+// reads, and the creation of access and token reviews
+func Allowed(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	case http.MethodPost:
+		for _, resource := range reviewResources {
+			if strings.HasSuffix(req.URL.Path, resource) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ReadOnly makes the clients created from a REST config refuse every request that
+// Allowed rejects, whatever code path issues it
+func ReadOnly(config *rest.Config) {
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &readOnlyTransport{next: rt}
+	})
+}
@@ -0,0 +1,63 @@
+package observer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+func TestAllowed(t *testing.T) {
+	tests := []struct {
+		method, path string
+		allowed      bool
+	}{
+		{http.MethodGet, "/api/v1/namespaces/payments/pods", true},
+		{http.MethodHead, "/version", true},
+		{http.MethodPost, "/apis/authorization.k8s.io/v1/selfsubjectaccessreviews", true},
+		{http.MethodPost, "/api/v1/namespaces/payments/pods", false},
+		{http.MethodPut, "/apis/apps/v1/namespaces/payments/deployments/api", false},
+		{http.MethodPatch, "/apis/apps/v1/namespaces/payments/deployments/api", false},
+		{http.MethodDelete, "/api/v1/namespaces/payments/pods/api-1", false},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, http.NoBody)
+		assert.Equal(t, tt.allowed, Allowed(req), "%s %s", tt.method, tt.path)
+	}
+}
+
+func TestReadOnly(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"kind":"SelfSubjectAccessReview","apiVersion":"authorization.k8s.io/v1","status":{"allowed":true}}`))
+	}))
+	defer server.Close()
+
+	config := &rest.Config{Host: server.URL}
+	ReadOnly(config)
+	clientset, err := kubernetes.NewForConfig(config)
+	require.NoError(t, err)
+	ctx := t.Context()
+
+	err = clientset.CoreV1().Pods("payments").Delete(ctx, "api-1", metav1.DeleteOptions{})
+	assert.ErrorIs(t, err, ErrObserverMode)
+
+	review, err := clientset.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx,
+		&authorizationv1.SelfSubjectAccessReview{}, metav1.CreateOptions{})
+	require.NoError(t, err)
+	assert.True(t, review.Status.Allowed)
+	assert.Equal(t, []string{"POST /apis/authorization.k8s.io/v1/selfsubjectaccessreviews"}, requests)
+}
+
+func TestMode(t *testing.T) {
+	assert.Equal(t, ModeObserver, Mode(true))
+	assert.Equal(t, ModeActive, Mode(false))
+}
@@ -0,0 +1,239 @@
+// Package ownership resolves the team owning a workload or namespace, and its Slack
+// channel and email, from annotations, so incidents, recommendations and notifications
+// reach the right on-call rotation.
+package ownership
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// DefaultAnnotationPrefix is the prefix of the owner annotations
+const DefaultAnnotationPrefix = "coordination.openshift.io/"
+
+// Owner annotation names, after the prefix
+const (
+	AnnotationTeam         = "owner"
+	AnnotationSlackChannel = "owner-slack-channel"
+	AnnotationEmail        = "owner-email"
+)
+
+// cacheTTL bounds how long the owner annotations of an object are cached
+const cacheTTL = time.Minute
+
+// lookupTimeout bounds resolving the owner of an incident
+const lookupTimeout = 5 * time.Second
+
+// maxControllerDepth is how many controllers are followed up from a pod, e.g. pod to
+// replicaset to deployment
+const maxControllerDepth = 2
+
+// object is the owner annotations of a Kubernetes object and the kind and name of its
+// controller, if any
+type object struct {
+	owner      models.Owner
+	controller string
+	expires    time.Time
+}
+
+// Resolver reads owners from the annotations of workloads, their controllers and their
+// namespace. The most specific object wins field by field: a deployment's Slack channel
+// overrides its namespace's, and the namespace fills in what the deployment leaves out.
+type Resolver struct {
+	client kubernetes.Interface
+	prefix string
+	log    *logrus.Logger
+
+	mu    sync.Mutex
+	cache map[string]object
+}
+
+// NewResolver creates an owner resolver. prefix defaults to DefaultAnnotationPrefix.
+func NewResolver(client kubernetes.Interface, prefix string, log *logrus.Logger) *Resolver {
+	if prefix == "" {
+		prefix = DefaultAnnotationPrefix
+	}
+	return &Resolver{client: client, prefix: prefix, log: log, cache: make(map[string]object)}
+}
+
+// Resolve returns the owner of a resource ("kind/name") in a namespace, or of the
+// namespace alone when resource is empty or not a pod, deployment, statefulset,
+// daemonset, replicaset or job. It returns nil when no owner is annotated.
+func (r *Resolver) Resolve(ctx context.Context, namespace, resource string) *models.Owner {
+	if namespace == "" {
+		return nil
+	}
+
+	owner := &models.Owner{}
+	kind, name, _ := strings.Cut(resource, "/")
+	kind = strings.ToLower(kind)
+	for depth := 0; name != "" && supportedKinds[kind] && depth <= maxControllerDepth; depth++ {
+		obj := r.object(ctx, namespace, kind, name)
+		merge(owner, obj.owner, kind+"/"+name)
+		kind, name, _ = strings.Cut(obj.controller, "/")
+	}
+	merge(owner, r.object(ctx, "", "namespace", namespace).owner, "namespace/"+namespace)
+
+	if owner.IsZero() {
+		return nil
+	}
+	return owner
+}
+
+// Team returns the team owning a resource in a namespace (see Resolve), or "" if no
+// team is annotated
+func (r *Resolver) Team(ctx context.Context, namespace, resource string) string {
+	if owner := r.Resolve(ctx, namespace, resource); owner != nil {
+		return owner.Team
+	}
+	return ""
+}
+
+// IncidentOwner returns the owner of an incident's first affected workload in its
+// namespace (the namespace label, or else its target). It implements
+// storage.IncidentOwnerResolver.
+func (r *Resolver) IncidentOwner(incident *models.Incident) *models.Owner {
+	namespace := incident.Labels["namespace"]
+	if namespace == "" {
+		namespace = incident.Target
+	}
+
+	var resource string
+	for _, affected := range incident.AffectedResources {
+		kind, _, _ := strings.Cut(affected, "/")
+		if supportedKinds[strings.ToLower(kind)] {
+			resource = affected
+			break
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), lookupTimeout)
+	defer cancel()
+	return r.Resolve(ctx, namespace, resource)
+}
+
+// merge fills the fields of owner that are unset from annotated, recording source as
+// the owner's source if it contributed the first field
+func merge(owner *models.Owner, annotated models.Owner, source string) {
+	if annotated.IsZero() {
+		return
+	}
+	if owner.Source == "" {
+		owner.Source = source
+	}
+	if owner.Team == "" {
+		owner.Team = annotated.Team
+	}
+	if owner.SlackChannel == "" {
+		owner.SlackChannel = annotated.SlackChannel
+	}
+	if owner.Email == "" {
+		owner.Email = annotated.Email
+	}
+}
+
+// supportedKinds are the workload kinds whose annotations are read
+var supportedKinds = map[string]bool{
+	"pod": true, "deployment": true, "statefulset": true, "daemonset": true, "replicaset": true, "job": true,
+}
+
+// object returns the owner annotations and controller of an object, from the cache
+// while they are fresh. Objects that cannot be read have no owner.
+func (r *Resolver) object(ctx context.Context, namespace, kind, name string) object {
+	key := namespace + "/" + kind + "/" + name
+	r.mu.Lock()
+	cached, ok := r.cache[key]
+	r.mu.Unlock()
+	if ok && time.Now().Before(cached.expires) {
+		return cached
+	}
+
+	meta, err := r.get(ctx, namespace, kind, name)
+	if err != nil {
+		// Lookups cut short are retried next time rather than cached as unowned
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return object{}
+		}
+		if !apierrors.IsNotFound(err) {
+			r.log.WithError(err).WithField("object", key).Warn("Failed to read owner annotations")
+		}
+		meta = &metav1.ObjectMeta{}
+	}
+
+	obj := object{
+		owner: models.Owner{
+			Team:         meta.Annotations[r.prefix+AnnotationTeam],
+			SlackChannel: meta.Annotations[r.prefix+AnnotationSlackChannel],
+			Email:        meta.Annotations[r.prefix+AnnotationEmail],
+		},
+		expires: time.Now().Add(cacheTTL),
+	}
+	if controller := metav1.GetControllerOf(meta); controller != nil {
+		obj.controller = strings.ToLower(controller.Kind) + "/" + controller.Name
+	}
+
+	r.mu.Lock()
+	r.cache[key] = obj
+	r.mu.Unlock()
+	return obj
+}
+
+// get reads the metadata of an object
+func (r *Resolver) get(ctx context.Context, namespace, kind, name string) (*metav1.ObjectMeta, error) {
+	opts := metav1.GetOptions{}
+	switch kind {
+	case "namespace":
+		ns, err := r.client.CoreV1().Namespaces().Get(ctx, name, opts)
+		if err != nil {
+			return nil, err
+		}
+		return &ns.ObjectMeta, nil
+	case "pod":
+		pod, err := r.client.CoreV1().Pods(namespace).Get(ctx, name, opts)
+		if err != nil {
+			return nil, err
+		}
+		return &pod.ObjectMeta, nil
+	case "deployment":
+		deployment, err := r.client.AppsV1().Deployments(namespace).Get(ctx, name, opts)
+		if err != nil {
+			return nil, err
+		}
+		return &deployment.ObjectMeta, nil
+	case "statefulset":
+		statefulSet, err := r.client.AppsV1().StatefulSets(namespace).Get(ctx, name, opts)
+		if err != nil {
+			return nil, err
+		}
+		return &statefulSet.ObjectMeta, nil
+	case "daemonset":
+		daemonSet, err := r.client.AppsV1().DaemonSets(namespace).Get(ctx, name, opts)
+		if err != nil {
+			return nil, err
+		}
+		return &daemonSet.ObjectMeta, nil
+	case "replicaset":
+		replicaSet, err := r.client.AppsV1().ReplicaSets(namespace).Get(ctx, name, opts)
+		if err != nil {
+			return nil, err
+		}
+		return &replicaSet.ObjectMeta, nil
+	case "job":
+		job, err := r.client.BatchV1().Jobs(namespace).Get(ctx, name, opts)
+		if err != nil {
+			return nil, err
+		}
+		return &job.ObjectMeta, nil
+	}
+	return &metav1.ObjectMeta{}, nil
+}
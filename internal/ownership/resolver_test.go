@@ -0,0 +1,80 @@
+package ownership
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+func controlledBy(kind, name string) []metav1.OwnerReference {
+	controller := true
+	return []metav1.OwnerReference{{Kind: kind, Name: name, Controller: &controller}}
+}
+
+func newTestResolver() *Resolver {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	clientset := fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop", Annotations: map[string]string{
+			DefaultAnnotationPrefix + AnnotationTeam:         "storefront",
+			DefaultAnnotationPrefix + AnnotationSlackChannel: "#storefront-oncall",
+			DefaultAnnotationPrefix + AnnotationEmail:        "storefront@example.com",
+		}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "infra"}},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "shop", Annotations: map[string]string{
+			DefaultAnnotationPrefix + AnnotationTeam:         "payments",
+			DefaultAnnotationPrefix + AnnotationSlackChannel: "#payments-oncall",
+		}}},
+		&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "checkout-7d9f", Namespace: "shop", OwnerReferences: controlledBy("Deployment", "checkout")}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "checkout-7d9f-x2x", Namespace: "shop", OwnerReferences: controlledBy("ReplicaSet", "checkout-7d9f")}},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "catalog", Namespace: "shop"}},
+	)
+	return NewResolver(clientset, "", log)
+}
+
+func TestResolver_Resolve(t *testing.T) {
+	resolver := newTestResolver()
+	ctx := context.Background()
+
+	assert.Equal(t, &models.Owner{
+		Team:         "payments",
+		SlackChannel: "#payments-oncall",
+		Email:        "storefront@example.com",
+		Source:       "deployment/checkout",
+	}, resolver.Resolve(ctx, "shop", "pod/checkout-7d9f-x2x"), "the deployment's annotations override the namespace's")
+
+	namespaceOwner := &models.Owner{
+		Team:         "storefront",
+		SlackChannel: "#storefront-oncall",
+		Email:        "storefront@example.com",
+		Source:       "namespace/shop",
+	}
+	assert.Equal(t, namespaceOwner, resolver.Resolve(ctx, "shop", "deployment/catalog"))
+	assert.Equal(t, namespaceOwner, resolver.Resolve(ctx, "shop", "deployment/missing"))
+	assert.Equal(t, namespaceOwner, resolver.Resolve(ctx, "shop", "node/worker-1"))
+	assert.Equal(t, namespaceOwner, resolver.Resolve(ctx, "shop", ""))
+
+	assert.Nil(t, resolver.Resolve(ctx, "infra", ""))
+	assert.Nil(t, resolver.Resolve(ctx, "missing", ""))
+	assert.Nil(t, resolver.Resolve(ctx, "", "deployment/checkout"))
+}
+
+func TestResolver_IncidentOwner(t *testing.T) {
+	resolver := newTestResolver()
+
+	owner := resolver.IncidentOwner(&models.Incident{Target: "shop", AffectedResources: []string{"node/worker-1", "Deployment/checkout"}})
+	assert.Equal(t, "payments", owner.Team)
+
+	owner = resolver.IncidentOwner(&models.Incident{Target: "cluster", Labels: map[string]string{"namespace": "shop"}})
+	assert.Equal(t, "storefront", owner.Team, "the namespace label wins over the target")
+
+	assert.Nil(t, resolver.IncidentOwner(&models.Incident{Target: "cluster"}))
+}
@@ -0,0 +1,173 @@
+// Package platform checks cluster services every workload depends on: in-cluster DNS,
+// TLS serving certificates and etcd storage.
+package platform
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Annotations the OpenShift service CA operator sets on service serving certificate
+// secrets
+const (
+	ServingCertExpiryAnnotation  = "service.beta.openshift.io/expiry"
+	ServingCertServiceAnnotation = "service.beta.openshift.io/originating-service-name"
+
+	servingCertAlphaExpiryAnnotation = "service.alpha.openshift.io/expiry"
+)
+
+// Certificate is the leaf certificate of a TLS secret
+type Certificate struct {
+	Namespace string    `json:"namespace"`
+	Secret    string    `json:"secret"`
+	Subject   string    `json:"subject,omitempty"`
+	DNSNames  []string  `json:"dns_names,omitempty"`
+	NotAfter  time.Time `json:"not_after"`
+
+	// DaysRemaining is negative once the certificate has expired
+	DaysRemaining int `json:"days_remaining"`
+
+	// ServingService is set for service serving certificates, which the service CA
+	// operator regenerates when the secret is deleted
+	ServingService string `json:"serving_service,omitempty"`
+}
+
+// Expired reports whether the certificate is past its NotAfter time
+func (c Certificate) Expired() bool {
+	return c.DaysRemaining < 0
+}
+
+// CertificateScanner reads the expiry of the certificates held in TLS secrets
+type CertificateScanner struct {
+	clientset  kubernetes.Interface
+	namespaces []string
+	log        *logrus.Logger
+	now        func() time.Time
+}
+
+// NewCertificateScanner creates a scanner of the TLS secrets of namespaces, or of every
+// namespace when none are given
+func NewCertificateScanner(clientset kubernetes.Interface, namespaces []string, log *logrus.Logger) *CertificateScanner {
+	return &CertificateScanner{
+		clientset:  clientset,
+		namespaces: namespaces,
+		log:        log,
+		now:        time.Now,
+	}
+}
+
+// Scan returns the certificates of the TLS secrets, soonest expiry first. Secrets whose
+// certificate cannot be parsed are skipped unless the service CA annotated their expiry.
+func (s *CertificateScanner) Scan(ctx context.Context) ([]Certificate, error) {
+	namespaces := s.namespaces
+	if len(namespaces) == 0 {
+		namespaces = []string{metav1.NamespaceAll}
+	}
+
+	now := s.now()
+	var certificates []Certificate
+	for _, namespace := range namespaces {
+		secrets, err := s.clientset.CoreV1().Secrets(namespace).List(ctx, metav1.ListOptions{
+			FieldSelector: "type=" + string(corev1.SecretTypeTLS),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list TLS secrets in namespace %q: %w", namespace, err)
+		}
+		for i := range secrets.Items {
+			secret := &secrets.Items[i]
+			if secret.Type != corev1.SecretTypeTLS {
+				continue
+			}
+			certificate, err := secretCertificate(secret)
+			if err != nil {
+				s.log.WithError(err).WithFields(logrus.Fields{
+					"namespace": secret.Namespace,
+					"secret":    secret.Name,
+				}).Debug("Skipping TLS secret without a readable certificate")
+				continue
+			}
+			certificate.DaysRemaining = int(math.Floor(certificate.NotAfter.Sub(now).Hours() / 24))
+			certificates = append(certificates, certificate)
+		}
+	}
+
+	sort.Slice(certificates, func(i, j int) bool {
+		if !certificates[i].NotAfter.Equal(certificates[j].NotAfter) {
+			return certificates[i].NotAfter.Before(certificates[j].NotAfter)
+		}
+		return certificates[i].Namespace+"/"+certificates[i].Secret < certificates[j].Namespace+"/"+certificates[j].Secret
+	})
+	return certificates, nil
+}
+
+// Expiring returns the certificates that have expired or expire within the given time,
+// soonest first
+func (s *CertificateScanner) Expiring(ctx context.Context, within time.Duration) ([]Certificate, error) {
+	certificates, err := s.Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	deadline := s.now().Add(within)
+	var expiring []Certificate
+	for _, certificate := range certificates {
+		if certificate.NotAfter.After(deadline) {
+			break
+		}
+		expiring = append(expiring, certificate)
+	}
+	return expiring, nil
+}
+
+// secretCertificate reads the leaf certificate of a TLS secret, falling back to the
+// expiry annotation of service serving certificates
+func secretCertificate(secret *corev1.Secret) (Certificate, error) {
+	certificate := Certificate{
+		Namespace:      secret.Namespace,
+		Secret:         secret.Name,
+		ServingService: secret.Annotations[ServingCertServiceAnnotation],
+	}
+
+	leaf, parseErr := parseLeaf(secret.Data[corev1.TLSCertKey])
+	if parseErr == nil {
+		certificate.Subject = leaf.Subject.String()
+		certificate.DNSNames = leaf.DNSNames
+		certificate.NotAfter = leaf.NotAfter.UTC()
+		return certificate, nil
+	}
+
+	for _, annotation := range []string{ServingCertExpiryAnnotation, servingCertAlphaExpiryAnnotation} {
+		if value, ok := secret.Annotations[annotation]; ok {
+			notAfter, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return Certificate{}, fmt.Errorf("invalid %s annotation %q: %w", annotation, value, err)
+			}
+			certificate.NotAfter = notAfter.UTC()
+			return certificate, nil
+		}
+	}
+	return Certificate{}, parseErr
+}
+
+// parseLeaf parses the first certificate of a PEM bundle
+func parseLeaf(data []byte) (*x509.Certificate, error) {
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return nil, fmt.Errorf("no PEM certificate found in %s", corev1.TLSCertKey)
+		}
+		if block.Type == "CERTIFICATE" {
+			return x509.ParseCertificate(block.Bytes)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package platform
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+var scanTime = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+// tlsSecret returns a TLS secret holding a self-signed certificate valid until notAfter
+func tlsSecret(t *testing.T, namespace, name string, notAfter time.Time) *corev1.Secret {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: name + "." + namespace + ".svc"},
+		DNSNames:     []string{name + "." + namespace + ".svc"},
+		NotBefore:    notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Type:       corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			corev1.TLSCertKey: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		},
+	}
+}
+
+func TestCertificateScanner_Scan(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	servingCert := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "api-serving-cert",
+			Namespace: "payments",
+			Annotations: map[string]string{
+				ServingCertExpiryAnnotation:  scanTime.Add(5 * 24 * time.Hour).Format(time.RFC3339),
+				ServingCertServiceAnnotation: "api",
+			},
+		},
+		Type: corev1.SecretTypeTLS,
+	}
+	unreadable := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "broken", Namespace: "payments"},
+		Type:       corev1.SecretTypeTLS,
+		Data:       map[string][]byte{corev1.TLSCertKey: []byte("not a certificate")},
+	}
+	opaque := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "credentials", Namespace: "payments"},
+		Type:       corev1.SecretTypeOpaque,
+	}
+	clientset := fake.NewSimpleClientset(
+		tlsSecret(t, "payments", "gateway-tls", scanTime.Add(90*24*time.Hour)),
+		tlsSecret(t, "shop", "shop-tls", scanTime.Add(-36*time.Hour)),
+		servingCert, unreadable, opaque,
+	)
+
+	scanner := NewCertificateScanner(clientset, nil, log)
+	scanner.now = func() time.Time { return scanTime }
+
+	certificates, err := scanner.Scan(context.Background())
+	require.NoError(t, err)
+	require.Len(t, certificates, 3)
+
+	assert.Equal(t, "shop-tls", certificates[0].Secret)
+	assert.True(t, certificates[0].Expired())
+	assert.Equal(t, -2, certificates[0].DaysRemaining)
+	assert.Equal(t, []string{"shop-tls.shop.svc"}, certificates[0].DNSNames)
+
+	assert.Equal(t, "api-serving-cert", certificates[1].Secret)
+	assert.Equal(t, "api", certificates[1].ServingService)
+	assert.Equal(t, 5, certificates[1].DaysRemaining)
+
+	assert.Equal(t, "gateway-tls", certificates[2].Secret)
+	assert.Equal(t, "CN=gateway-tls.payments.svc", certificates[2].Subject)
+	assert.False(t, certificates[2].Expired())
+
+	// Expiring keeps the expired certificates and those within the warning window
+	expiring, err := scanner.Expiring(context.Background(), 7*24*time.Hour)
+	require.NoError(t, err)
+	require.Len(t, expiring, 2)
+	assert.Equal(t, "api-serving-cert", expiring[1].Secret)
+
+	expired, err := scanner.Expiring(context.Background(), 0)
+	require.NoError(t, err)
+	require.Len(t, expired, 1)
+	assert.Equal(t, "shop-tls", expired[0].Secret)
+
+	// Scans are limited to the configured namespaces
+	scanner = NewCertificateScanner(clientset, []string{"shop"}, log)
+	scanner.now = func() time.Time { return scanTime }
+	certificates, err = scanner.Scan(context.Background())
+	require.NoError(t, err)
+	require.Len(t, certificates, 1)
+	assert.Equal(t, "shop", certificates[0].Namespace)
+}
@@ -0,0 +1,46 @@
+package platform
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/integrations"
+	"github.com/tosin2013/openshift-coordination-engine/internal/promql"
+)
+
+// DNSHealth is the health of in-cluster DNS measured from CoreDNS metrics over the last
+// 5 minutes
+type DNSHealth struct {
+	// ErrorRate is the fraction of responses that were SERVFAIL or REFUSED
+	ErrorRate float64 `json:"error_rate"`
+
+	// LatencyP99Seconds is the p99 request duration, 0 when unknown
+	LatencyP99Seconds float64 `json:"latency_p99_seconds"`
+}
+
+// CheckDNS queries the CoreDNS error rate and latency. It fails when CoreDNS metrics are
+// missing or no DNS request was served.
+func CheckDNS(ctx context.Context, client *integrations.PrometheusClient) (*DNSHealth, error) {
+	if client == nil || !client.IsAvailable() {
+		return nil, fmt.Errorf("prometheus client not available")
+	}
+	if missing := client.MissingExporters(integrations.ExporterCoreDNS); len(missing) > 0 {
+		return nil, fmt.Errorf("CoreDNS metrics not found in Prometheus")
+	}
+
+	queries := client.Queries()
+	errorRate, err := client.Query(ctx, queries.MustRender("dns.error_ratio", promql.Params{}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query DNS error rate: %w", err)
+	}
+	if math.IsNaN(errorRate) {
+		return nil, fmt.Errorf("no DNS requests served in the last 5m")
+	}
+
+	health := &DNSHealth{ErrorRate: errorRate}
+	if latency, err := client.Query(ctx, queries.MustRender("dns.latency_p99", promql.Params{})); err == nil && !math.IsNaN(latency) {
+		health.LatencyP99Seconds = latency
+	}
+	return health, nil
+}
@@ -0,0 +1,50 @@
+package platform
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/integrations"
+)
+
+func TestCheckDNS(t *testing.T) {
+	errorRatio := "0.12"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query().Get("query")
+		value := "1"
+		switch {
+		case strings.Contains(query, "SERVFAIL"):
+			value = errorRatio
+		case strings.HasPrefix(query, "histogram_quantile"):
+			value = "0.25"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1700000000,"` + value + `"]}]}}`))
+	}))
+	defer server.Close()
+
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	client := integrations.NewPrometheusClient(server.URL, 5*time.Second, log)
+
+	health, err := CheckDNS(context.Background(), client)
+	require.NoError(t, err)
+	assert.Equal(t, 0.12, health.ErrorRate)
+	assert.Equal(t, 0.25, health.LatencyP99Seconds)
+
+	// No DNS traffic gives a NaN ratio
+	errorRatio = "NaN"
+	_, err = CheckDNS(context.Background(), client)
+	assert.ErrorContains(t, err, "no DNS requests")
+
+	_, err = CheckDNS(context.Background(), nil)
+	assert.ErrorContains(t, err, "prometheus client not available")
+}
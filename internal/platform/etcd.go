@@ -0,0 +1,116 @@
+package platform
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/integrations"
+	"github.com/tosin2013/openshift-coordination-engine/internal/promql"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/quantity"
+)
+
+// EtcdGrowthWindow is the window the database growth trend is measured over
+const EtcdGrowthWindow = "7d"
+
+// maxEtcdForecastDays bounds forecasts; slower growth is treated as no growth
+const maxEtcdForecastDays = 3650
+
+// Sources of the etcd backend quota of a forecast
+const (
+	EtcdQuotaFromMetric = "metric"
+	EtcdQuotaFromConfig = "config"
+)
+
+// EtcdForecast is the projected time until the etcd database reaches its backend quota,
+// after which etcd raises a NOSPACE alarm and the API server can no longer write
+type EtcdForecast struct {
+	DBSize quantity.Bytes `json:"db_size_bytes"`
+
+	// InUse is the part of the database holding keys, 0 when unknown. The rest is
+	// freed pages only a defragmentation returns.
+	InUse quantity.Bytes `json:"in_use_bytes,omitempty"`
+
+	Quota       quantity.Bytes `json:"quota_bytes"`
+	QuotaSource string         `json:"quota_source"`
+
+	// GrowthPerDay is the database growth in bytes per day over EtcdGrowthWindow
+	GrowthPerDay float64 `json:"growth_bytes_per_day"`
+
+	// DaysUntilQuota and ExhaustsAt are unset when the database is not growing and
+	// still below its quota
+	DaysUntilQuota *float64   `json:"days_until_quota,omitempty"`
+	ExhaustsAt     *time.Time `json:"exhausts_at,omitempty"`
+}
+
+// Reclaimable returns the bytes a defragmentation would return, 0 when unknown
+func (f EtcdForecast) Reclaimable() quantity.Bytes {
+	if f.InUse <= 0 || f.InUse >= f.DBSize {
+		return 0
+	}
+	return f.DBSize - f.InUse
+}
+
+// ExhaustsWithin reports whether the quota is forecast to be reached within window
+func (f EtcdForecast) ExhaustsWithin(window time.Duration) bool {
+	return f.DaysUntilQuota != nil && *f.DaysUntilQuota*24 < window.Hours()
+}
+
+// ForecastEtcd projects the growth of the etcd database to its backend quota. The quota
+// etcd exports is preferred; quota is used when it exports none. It fails when etcd
+// metrics are missing.
+func ForecastEtcd(ctx context.Context, client *integrations.PrometheusClient, quota quantity.Bytes) (*EtcdForecast, error) {
+	if client == nil || !client.IsAvailable() {
+		return nil, fmt.Errorf("prometheus client not available")
+	}
+
+	queries := client.Queries()
+	size, err := client.Query(ctx, queries.MustRender("etcd.db_size_bytes", promql.Params{}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query etcd database size: %w", err)
+	}
+	dbSize, err := quantity.BytesFromFloat(size)
+	if err != nil {
+		return nil, fmt.Errorf("invalid etcd database size: %w", err)
+	}
+
+	forecast := &EtcdForecast{DBSize: dbSize, Quota: quota, QuotaSource: EtcdQuotaFromConfig}
+	if value, err := client.Query(ctx, queries.MustRender("etcd.quota_bytes", promql.Params{})); err == nil {
+		if exported, err := quantity.BytesFromFloat(value); err == nil && exported > 0 {
+			forecast.Quota = exported
+			forecast.QuotaSource = EtcdQuotaFromMetric
+		}
+	}
+	if forecast.Quota <= 0 {
+		return nil, fmt.Errorf("etcd backend quota unknown")
+	}
+	if value, err := client.Query(ctx, queries.MustRender("etcd.db_in_use_bytes", promql.Params{})); err == nil {
+		if inUse, err := quantity.BytesFromFloat(value); err == nil {
+			forecast.InUse = inUse
+		}
+	}
+
+	growth, err := client.Query(ctx, queries.MustRender("etcd.db_growth_bytes_per_day", promql.Params{Window: EtcdGrowthWindow}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query etcd database growth: %w", err)
+	}
+	if math.IsNaN(growth) || math.IsInf(growth, 0) {
+		growth = 0
+	}
+	forecast.GrowthPerDay = growth
+
+	var days float64
+	switch {
+	case forecast.DBSize >= forecast.Quota:
+		days = 0
+	case growth > 0 && float64(forecast.Quota-forecast.DBSize)/growth <= maxEtcdForecastDays:
+		days = float64(forecast.Quota-forecast.DBSize) / growth
+	default:
+		return forecast, nil
+	}
+	exhaustsAt := time.Now().Add(time.Duration(days * float64(24*time.Hour))).UTC().Truncate(time.Second)
+	forecast.DaysUntilQuota = &days
+	forecast.ExhaustsAt = &exhaustsAt
+	return forecast, nil
+}
@@ -0,0 +1,98 @@
+package platform
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/integrations"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/quantity"
+)
+
+// etcdServer serves the etcd queries with values, leaving out the ones set to ""
+func etcdServer(t *testing.T, values map[string]string) *integrations.PrometheusClient {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query().Get("query")
+		var value string
+		switch {
+		case strings.Contains(query, "deriv("):
+			value = values["growth"]
+		case strings.Contains(query, "size_in_use"):
+			value = values["in_use"]
+		case strings.Contains(query, "quota_backend"):
+			value = values["quota"]
+		default:
+			value = values["size"]
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if value == "" {
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[]}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1700000000,"` + value + `"]}]}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	return integrations.NewPrometheusClient(server.URL, 5*time.Second, log)
+}
+
+func TestForecastEtcd(t *testing.T) {
+	// 6GiB of an 8GiB quota growing 100MiB a day
+	client := etcdServer(t, map[string]string{
+		"size":   "6442450944",
+		"in_use": "4294967296",
+		"quota":  "8589934592",
+		"growth": "104857600",
+	})
+
+	forecast, err := ForecastEtcd(context.Background(), client, 2*quantity.GiB)
+	require.NoError(t, err)
+	assert.Equal(t, 8*quantity.GiB, forecast.Quota)
+	assert.Equal(t, EtcdQuotaFromMetric, forecast.QuotaSource)
+	assert.Equal(t, 2*quantity.GiB, forecast.Reclaimable())
+	require.NotNil(t, forecast.DaysUntilQuota)
+	assert.InDelta(t, 20.48, *forecast.DaysUntilQuota, 0.01)
+	require.NotNil(t, forecast.ExhaustsAt)
+	assert.True(t, forecast.ExhaustsWithin(30*24*time.Hour))
+	assert.False(t, forecast.ExhaustsWithin(14*24*time.Hour))
+}
+
+func TestForecastEtcd_ConfiguredQuota(t *testing.T) {
+	client := etcdServer(t, map[string]string{"size": "1073741824", "growth": "0"})
+
+	forecast, err := ForecastEtcd(context.Background(), client, 8*quantity.GiB)
+	require.NoError(t, err)
+	assert.Equal(t, 8*quantity.GiB, forecast.Quota)
+	assert.Equal(t, EtcdQuotaFromConfig, forecast.QuotaSource)
+	assert.Zero(t, forecast.Reclaimable(), "unknown without the in-use size")
+	assert.Nil(t, forecast.DaysUntilQuota, "a database that does not grow is never exhausted")
+	assert.False(t, forecast.ExhaustsWithin(30*24*time.Hour))
+
+	// A database at its quota is exhausted whatever its growth
+	client = etcdServer(t, map[string]string{"size": "8589934592", "growth": "-1000"})
+	forecast, err = ForecastEtcd(context.Background(), client, 8*quantity.GiB)
+	require.NoError(t, err)
+	require.NotNil(t, forecast.DaysUntilQuota)
+	assert.Zero(t, *forecast.DaysUntilQuota)
+}
+
+func TestForecastEtcd_Unavailable(t *testing.T) {
+	_, err := ForecastEtcd(context.Background(), etcdServer(t, map[string]string{}), 8*quantity.GiB)
+	assert.ErrorContains(t, err, "failed to query etcd database size")
+
+	_, err = ForecastEtcd(context.Background(), etcdServer(t, map[string]string{"size": "1024"}), 0)
+	assert.ErrorContains(t, err, "etcd backend quota unknown")
+
+	_, err = ForecastEtcd(context.Background(), nil, 8*quantity.GiB)
+	assert.ErrorContains(t, err, "prometheus client not available")
+}
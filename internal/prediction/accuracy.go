@@ -0,0 +1,239 @@
+package prediction
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// Drift detection thresholds. A group drifts when its recent MAE exceeds the baseline
+// MAE by DriftFactor and by at least DriftMinDelta percentage points, with enough
+// samples on both sides.
+const (
+	DriftFactor     = 1.5
+	DriftMinDelta   = 2.0
+	DriftMinSamples = 10
+
+	// BaselineWindow is the period before the accuracy window that drift is measured against
+	BaselineWindow = 7 * 24 * time.Hour
+)
+
+// ErrorStats summarizes prediction error. MAE and Bias (mean signed error, positive when
+// over-predicting) are in percentage points; MAPE is in percent of the actual value and
+// skips samples whose actual usage was zero.
+type ErrorStats struct {
+	MAE  float64 `json:"mae"`
+	MAPE float64 `json:"mape"`
+	Bias float64 `json:"bias"`
+}
+
+// ComputeErrorStats computes the error of predicted against actual values
+func ComputeErrorStats(predicted, actual []float64) ErrorStats {
+	if len(predicted) == 0 {
+		return ErrorStats{}
+	}
+
+	var absSum, signedSum, pctSum float64
+	pctCount := 0
+	for i := range predicted {
+		diff := predicted[i] - actual[i]
+		absSum += math.Abs(diff)
+		signedSum += diff
+		if actual[i] > 0 {
+			pctSum += math.Abs(diff) / actual[i] * 100
+			pctCount++
+		}
+	}
+
+	n := float64(len(predicted))
+	stats := ErrorStats{
+		MAE:  round2(absSum / n),
+		Bias: round2(signedSum / n),
+	}
+	if pctCount > 0 {
+		stats.MAPE = round2(pctSum / float64(pctCount))
+	}
+	return stats
+}
+
+// AccuracyFilter selects the predictions an accuracy summary covers
+type AccuracyFilter struct {
+	Model  string
+	Scope  string
+	Target string
+
+	// Window is the rolling period of target times summarized (default 24h)
+	Window time.Duration
+}
+
+// AccuracySummary reports rolling prediction accuracy per model and target
+type AccuracySummary struct {
+	Window      string          `json:"window"`
+	From        time.Time       `json:"from"`
+	To          time.Time       `json:"to"`
+	Evaluated   int             `json:"evaluated"`
+	Pending     int             `json:"pending"`
+	CPU         ErrorStats      `json:"cpu"`
+	Memory      ErrorStats      `json:"memory"`
+	DriftAlerts int             `json:"drift_alerts"`
+	Groups      []AccuracyGroup `json:"groups"`
+}
+
+// AccuracyGroup is the accuracy of one model's predictions for one target
+type AccuracyGroup struct {
+	Model     string     `json:"model"`
+	Scope     string     `json:"scope"`
+	Target    string     `json:"target"`
+	Evaluated int        `json:"evaluated"`
+	Pending   int        `json:"pending"`
+	CPU       ErrorStats `json:"cpu"`
+	Memory    ErrorStats `json:"memory"`
+
+	// Baseline is the error over BaselineWindow before the accuracy window
+	BaselineSamples int        `json:"baseline_samples"`
+	BaselineCPU     ErrorStats `json:"baseline_cpu"`
+	BaselineMemory  ErrorStats `json:"baseline_memory"`
+
+	Drift       bool   `json:"drift"`
+	DriftReason string `json:"drift_reason,omitempty"`
+}
+
+// groupKey identifies an accuracy group
+type groupKey struct {
+	model, scope, target string
+}
+
+// errorSamples collects predicted and actual values for one group and period
+type errorSamples struct {
+	predictedCPU, actualCPU       []float64
+	predictedMemory, actualMemory []float64
+}
+
+// add records an evaluated prediction
+func (e *errorSamples) add(record *models.PredictionRecord) {
+	e.predictedCPU = append(e.predictedCPU, record.PredictedCPU)
+	e.actualCPU = append(e.actualCPU, *record.ActualCPU)
+	e.predictedMemory = append(e.predictedMemory, record.PredictedMemory)
+	e.actualMemory = append(e.actualMemory, *record.ActualMemory)
+}
+
+// count returns the number of samples
+func (e *errorSamples) count() int {
+	return len(e.predictedCPU)
+}
+
+// summarize builds the accuracy summary for records whose target time falls in
+// [to-window, to]; records in the preceding BaselineWindow form the drift baseline
+func summarize(records []models.PredictionRecord, window time.Duration, to time.Time) *AccuracySummary {
+	from := to.Add(-window)
+	baselineFrom := from.Add(-BaselineWindow)
+
+	recent := make(map[groupKey]*errorSamples)
+	baseline := make(map[groupKey]*errorSamples)
+	pending := make(map[groupKey]int)
+	var total errorSamples
+	totalPending := 0
+
+	for i := range records {
+		record := &records[i]
+		key := groupKey{record.Model, record.Scope, record.Target}
+		inWindow := !record.TargetTime.Before(from) && !record.TargetTime.After(to)
+
+		switch {
+		case inWindow && !record.Evaluated():
+			pending[key]++
+			totalPending++
+		case inWindow:
+			if recent[key] == nil {
+				recent[key] = &errorSamples{}
+			}
+			recent[key].add(record)
+			total.add(record)
+		case record.Evaluated() && !record.TargetTime.Before(baselineFrom) && record.TargetTime.Before(from):
+			if baseline[key] == nil {
+				baseline[key] = &errorSamples{}
+			}
+			baseline[key].add(record)
+		}
+	}
+
+	summary := &AccuracySummary{
+		Window:    window.String(),
+		From:      from,
+		To:        to,
+		Evaluated: total.count(),
+		Pending:   totalPending,
+		CPU:       ComputeErrorStats(total.predictedCPU, total.actualCPU),
+		Memory:    ComputeErrorStats(total.predictedMemory, total.actualMemory),
+		Groups:    []AccuracyGroup{},
+	}
+
+	keys := make(map[groupKey]struct{}, len(recent)+len(pending))
+	for key := range recent {
+		keys[key] = struct{}{}
+	}
+	for key := range pending {
+		keys[key] = struct{}{}
+	}
+
+	for key := range keys {
+		group := AccuracyGroup{
+			Model:   key.model,
+			Scope:   key.scope,
+			Target:  key.target,
+			Pending: pending[key],
+		}
+		if samples := recent[key]; samples != nil {
+			group.Evaluated = samples.count()
+			group.CPU = ComputeErrorStats(samples.predictedCPU, samples.actualCPU)
+			group.Memory = ComputeErrorStats(samples.predictedMemory, samples.actualMemory)
+		}
+		if samples := baseline[key]; samples != nil {
+			group.BaselineSamples = samples.count()
+			group.BaselineCPU = ComputeErrorStats(samples.predictedCPU, samples.actualCPU)
+			group.BaselineMemory = ComputeErrorStats(samples.predictedMemory, samples.actualMemory)
+		}
+		group.Drift, group.DriftReason = detectDrift(&group)
+		if group.Drift {
+			summary.DriftAlerts++
+		}
+		summary.Groups = append(summary.Groups, group)
+	}
+
+	sort.Slice(summary.Groups, func(i, j int) bool {
+		a, b := summary.Groups[i], summary.Groups[j]
+		if a.Model != b.Model {
+			return a.Model < b.Model
+		}
+		return a.Target < b.Target
+	})
+
+	return summary
+}
+
+// detectDrift compares a group's recent error with its baseline
+func detectDrift(group *AccuracyGroup) (bool, string) {
+	if group.Evaluated < DriftMinSamples || group.BaselineSamples < DriftMinSamples {
+		return false, ""
+	}
+	if drifted(group.CPU.MAE, group.BaselineCPU.MAE) {
+		return true, fmt.Sprintf("cpu MAE rose from %.2f to %.2f points", group.BaselineCPU.MAE, group.CPU.MAE)
+	}
+	if drifted(group.Memory.MAE, group.BaselineMemory.MAE) {
+		return true, fmt.Sprintf("memory MAE rose from %.2f to %.2f points", group.BaselineMemory.MAE, group.Memory.MAE)
+	}
+	return false, ""
+}
+
+// drifted reports whether the recent MAE is significantly worse than the baseline
+func drifted(recent, baseline float64) bool {
+	return recent-baseline >= DriftMinDelta && recent > baseline*DriftFactor
+}
+
+// round2 rounds to two decimal places
+func round2(value float64) float64 {
+	return math.Round(value*100) / 100
+}
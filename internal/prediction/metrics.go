@@ -0,0 +1,44 @@
+package prediction
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// PredictionsRecorded counts stored predictions by model
+	PredictionsRecorded = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "coordination_engine_predictions_recorded_total",
+			Help: "Total number of predictions stored for accuracy tracking, by model",
+		},
+		[]string{"model"},
+	)
+
+	// PredictionsEvaluated counts predictions whose observed usage was recorded
+	PredictionsEvaluated = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "coordination_engine_predictions_evaluated_total",
+			Help: "Total number of predictions compared with observed usage, by model",
+		},
+		[]string{"model"},
+	)
+
+	// PredictionMAE is the rolling 24h mean absolute error in percentage points
+	PredictionMAE = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "coordination_engine_prediction_mae",
+			Help: "Rolling 24h mean absolute prediction error in percentage points, by model and resource (cpu, memory)",
+		},
+		[]string{"model", "resource"},
+	)
+
+	// PredictionDriftTargets is the number of targets whose error drifted above baseline
+	PredictionDriftTargets = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "coordination_engine_prediction_drift_targets",
+			Help: "Number of prediction targets whose rolling error drifted above their baseline, by model",
+		},
+		[]string{"model"},
+	)
+)
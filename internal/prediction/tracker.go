@@ -0,0 +1,235 @@
+// Package prediction tracks the realized accuracy of resource usage predictions.
+//
+// Every prediction served by the API is stored. Once its target time has passed, the
+// Tracker looks up the usage Prometheus actually observed and records it, so rolling
+// error (MAE, MAPE, bias) can be reported per model and target and compared against a
+// baseline to detect model drift.
+package prediction
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/integrations"
+	"github.com/tosin2013/openshift-coordination-engine/internal/storage"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// Evaluation settings
+const (
+	// DefaultEvaluationInterval is how often due predictions are evaluated
+	DefaultEvaluationInterval = 5 * time.Minute
+
+	// DefaultAccuracyWindow is the rolling window reported when none is requested
+	DefaultAccuracyWindow = 24 * time.Hour
+
+	// evaluationDelay leaves Prometheus time to scrape the target time before evaluating
+	evaluationDelay = 5 * time.Minute
+
+	// maxEvaluationAge stops retrying predictions whose actual usage cannot be found
+	maxEvaluationAge = 7 * 24 * time.Hour
+
+	// maxEvaluationBatch bounds the predictions evaluated per run
+	maxEvaluationBatch = 500
+)
+
+// HistorySource supplies observed usage (implemented by integrations.PrometheusClient)
+type HistorySource interface {
+	GetScopedUsageHistory(
+		ctx context.Context,
+		namespace, deployment, pod string,
+		start, end time.Time,
+		step time.Duration,
+	) (cpu, memory []integrations.MetricDataPoint, err error)
+}
+
+// Tracker stores predictions and records their realized error
+type Tracker struct {
+	store   *storage.PredictionStore
+	history HistorySource
+	log     *logrus.Logger
+
+	// now is replaceable in tests
+	now func() time.Time
+}
+
+// NewTracker creates a tracker. history may be nil, in which case predictions are
+// stored but never evaluated.
+func NewTracker(store *storage.PredictionStore, history HistorySource, log *logrus.Logger) *Tracker {
+	return &Tracker{
+		store:   store,
+		history: history,
+		log:     log,
+		now:     time.Now,
+	}
+}
+
+// Store returns the prediction store, e.g. for backups
+func (t *Tracker) Store() *storage.PredictionStore {
+	return t.store
+}
+
+// Record stores served predictions. Failures are logged rather than returned so a
+// storage problem never fails the prediction request.
+func (t *Tracker) Record(records []*models.PredictionRecord) {
+	if len(records) == 0 {
+		return
+	}
+	if err := t.store.Add(records); err != nil {
+		t.log.WithError(err).Warn("Failed to store predictions")
+		return
+	}
+	PredictionsRecorded.WithLabelValues(records[0].Model).Add(float64(len(records)))
+}
+
+// Start evaluates due predictions every interval until ctx is cancelled
+func (t *Tracker) Start(ctx context.Context, interval time.Duration) {
+	if t.history == nil {
+		t.log.Info("Prometheus not configured, prediction accuracy will not be evaluated")
+		return
+	}
+	if interval <= 0 {
+		interval = DefaultEvaluationInterval
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := t.EvaluateDue(ctx); err != nil {
+					t.log.WithError(err).Warn("Failed to evaluate predictions")
+				}
+			}
+		}
+	}()
+
+	t.log.WithField("interval", interval).Info("Prediction accuracy tracking started")
+}
+
+// usageKey identifies one observed-usage lookup shared by predictions for the same
+// workload and target time
+type usageKey struct {
+	namespace, deployment, pod string
+	targetTime                 time.Time
+}
+
+// EvaluateDue records the observed usage for predictions whose target time has passed
+// and returns how many were evaluated. Predictions whose usage cannot be found yet are
+// retried on later runs until they are maxEvaluationAge old.
+func (t *Tracker) EvaluateDue(ctx context.Context) (int, error) {
+	if t.history == nil {
+		return 0, fmt.Errorf("no usage history source configured")
+	}
+
+	now := t.now()
+	pending := false
+	due := t.store.List(storage.PredictionFilter{
+		TargetFrom: now.Add(-maxEvaluationAge),
+		TargetTo:   now.Add(-evaluationDelay),
+		Evaluated:  &pending,
+		Limit:      maxEvaluationBatch,
+	})
+	if len(due) == 0 {
+		return 0, nil
+	}
+
+	byUsage := make(map[usageKey][]*models.PredictionRecord)
+	for i := range due {
+		record := &due[i]
+		key := usageKey{record.Namespace, record.Deployment, record.Pod, record.TargetTime}
+		byUsage[key] = append(byUsage[key], record)
+	}
+
+	actuals := make([]storage.PredictionActual, 0, len(due))
+	for key, records := range byUsage {
+		cpu, memory, err := t.history.GetScopedUsageHistory(ctx,
+			key.namespace, key.deployment, key.pod, key.targetTime, key.targetTime, time.Hour)
+		if err != nil || len(cpu) == 0 || len(memory) == 0 {
+			t.log.WithError(err).WithFields(logrus.Fields{
+				"namespace":   key.namespace,
+				"deployment":  key.deployment,
+				"pod":         key.pod,
+				"target_time": key.targetTime,
+			}).Debug("Observed usage not available for prediction target time")
+			continue
+		}
+
+		actualCPU := cpu[len(cpu)-1].Value * 100
+		actualMemory := memory[len(memory)-1].Value * 100
+		for _, record := range records {
+			actuals = append(actuals, storage.PredictionActual{
+				ID:         record.ID,
+				CPU:        actualCPU,
+				Memory:     actualMemory,
+				ObservedAt: now,
+			})
+			PredictionsEvaluated.WithLabelValues(record.Model).Inc()
+		}
+	}
+
+	if len(actuals) == 0 {
+		return 0, nil
+	}
+	if err := t.store.RecordActuals(actuals); err != nil {
+		return 0, err
+	}
+
+	t.updateMetrics(due)
+	t.log.WithFields(logrus.Fields{
+		"due":       len(due),
+		"evaluated": len(actuals),
+	}).Debug("Prediction accuracy evaluated")
+
+	return len(actuals), nil
+}
+
+// Accuracy summarizes rolling prediction accuracy
+func (t *Tracker) Accuracy(filter AccuracyFilter) *AccuracySummary {
+	if filter.Window <= 0 {
+		filter.Window = DefaultAccuracyWindow
+	}
+	now := t.now()
+
+	records := t.store.List(storage.PredictionFilter{
+		Model:      filter.Model,
+		Scope:      filter.Scope,
+		Target:     filter.Target,
+		TargetFrom: now.Add(-filter.Window - BaselineWindow),
+		TargetTo:   now,
+	})
+	return summarize(records, filter.Window, now)
+}
+
+// updateMetrics refreshes the accuracy gauges for the models just evaluated
+func (t *Tracker) updateMetrics(evaluated []models.PredictionRecord) {
+	seen := make(map[string]bool)
+	for i := range evaluated {
+		model := evaluated[i].Model
+		if seen[model] {
+			continue
+		}
+		seen[model] = true
+
+		summary := t.Accuracy(AccuracyFilter{Model: model})
+		if summary.Evaluated == 0 {
+			continue
+		}
+		PredictionMAE.WithLabelValues(model, "cpu").Set(summary.CPU.MAE)
+		PredictionMAE.WithLabelValues(model, "memory").Set(summary.Memory.MAE)
+		PredictionDriftTargets.WithLabelValues(model).Set(float64(summary.DriftAlerts))
+
+		if summary.DriftAlerts > 0 {
+			t.log.WithFields(logrus.Fields{
+				"model":         model,
+				"drift_targets": summary.DriftAlerts,
+			}).Warn("Prediction accuracy drift detected")
+		}
+	}
+}
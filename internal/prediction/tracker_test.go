@@ -0,0 +1,176 @@
+package prediction
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/integrations"
+	"github.com/tosin2013/openshift-coordination-engine/internal/storage"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// fakeHistory returns fixed usage ratios and counts lookups
+type fakeHistory struct {
+	cpu, memory float64
+	calls       int
+	err         error
+}
+
+func (f *fakeHistory) GetScopedUsageHistory(
+	_ context.Context,
+	_, _, _ string,
+	start, _ time.Time,
+	_ time.Duration,
+) (cpu, memory []integrations.MetricDataPoint, err error) {
+	f.calls++
+	if f.err != nil {
+		return nil, nil, f.err
+	}
+	return []integrations.MetricDataPoint{{Timestamp: start, Value: f.cpu}},
+		[]integrations.MetricDataPoint{{Timestamp: start, Value: f.memory}}, nil
+}
+
+func newTestTracker(t *testing.T, history HistorySource) (*Tracker, *storage.PredictionStore) {
+	t.Helper()
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	store := storage.NewPredictionStore(t.TempDir(), 0)
+	return NewTracker(store, history, log), store
+}
+
+func record(target string, targetTime time.Time, cpu, memory float64) *models.PredictionRecord {
+	return &models.PredictionRecord{
+		Model:           "predictive-analytics",
+		Scope:           "namespace",
+		Target:          target,
+		Namespace:       target,
+		TargetTime:      targetTime,
+		PredictedCPU:    cpu,
+		PredictedMemory: memory,
+	}
+}
+
+func TestTracker_EvaluateDue(t *testing.T) {
+	history := &fakeHistory{cpu: 0.4, memory: 0.5}
+	tracker, store := newTestTracker(t, history)
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	tracker.now = func() time.Time { return now }
+
+	due := now.Add(-time.Hour)
+	tracker.Record([]*models.PredictionRecord{
+		record("payments", due, 50, 45),
+		record("payments", due, 30, 55), // same workload and time share one lookup
+		record("payments", now.Add(time.Hour), 50, 50),
+		record("payments", now.Add(-8*24*time.Hour), 50, 50), // too old to evaluate
+	})
+	require.Equal(t, 4, store.Count())
+
+	evaluated, err := tracker.EvaluateDue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, evaluated)
+	assert.Equal(t, 1, history.calls)
+
+	done := true
+	records := store.List(storage.PredictionFilter{Evaluated: &done})
+	require.Len(t, records, 2)
+	assert.InDelta(t, 40.0, *records[0].ActualCPU, 1e-9)
+	assert.InDelta(t, 50.0, *records[0].ActualMemory, 1e-9)
+	assert.Equal(t, now, *records[0].EvaluatedAt)
+
+	// Evaluated predictions are not looked up again
+	evaluated, err = tracker.EvaluateDue(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, evaluated)
+	assert.Equal(t, 1, history.calls)
+
+	// Predictions stay pending while their usage is unavailable
+	tracker.Record([]*models.PredictionRecord{record("checkout", due, 10, 10)})
+	history.err = fmt.Errorf("no data")
+	evaluated, err = tracker.EvaluateDue(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, evaluated)
+}
+
+func TestTracker_Accuracy(t *testing.T) {
+	tracker, store := newTestTracker(t, &fakeHistory{})
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	tracker.now = func() time.Time { return now }
+
+	var records []*models.PredictionRecord
+	var actuals []storage.PredictionActual
+	add := func(target string, targetTime time.Time, predicted, actual float64) {
+		r := record(target, targetTime, predicted, predicted)
+		r.ID = fmt.Sprintf("pred-%d", len(records))
+		records = append(records, r)
+		actuals = append(actuals, storage.PredictionActual{ID: r.ID, CPU: actual, Memory: actual, ObservedAt: targetTime})
+	}
+	for n := 0; n < 12; n++ {
+		// payments: 1 point error last week, 6 points in the last day (drift)
+		add("payments", now.Add(-48*time.Hour-time.Duration(n)*time.Hour), 51, 50)
+		add("payments", now.Add(-time.Duration(n+1)*time.Hour), 56, 50)
+		// checkout: steady 2 point error
+		add("checkout", now.Add(-48*time.Hour-time.Duration(n)*time.Hour), 48, 50)
+		add("checkout", now.Add(-time.Duration(n+1)*time.Hour), 48, 50)
+	}
+	pending := record("checkout", now.Add(-30*time.Minute), 50, 50)
+	require.NoError(t, store.Add(append(records, pending)))
+	require.NoError(t, store.RecordActuals(actuals))
+
+	summary := tracker.Accuracy(AccuracyFilter{})
+	assert.Equal(t, "24h0m0s", summary.Window)
+	assert.Equal(t, 24, summary.Evaluated)
+	assert.Equal(t, 1, summary.Pending)
+	assert.Equal(t, 4.0, summary.CPU.MAE)
+	assert.Equal(t, 1, summary.DriftAlerts)
+	require.Len(t, summary.Groups, 2)
+
+	checkout, payments := summary.Groups[0], summary.Groups[1]
+	assert.Equal(t, "checkout", checkout.Target)
+	assert.Equal(t, 12, checkout.Evaluated)
+	assert.Equal(t, 1, checkout.Pending)
+	assert.Equal(t, ErrorStats{MAE: 2, MAPE: 4, Bias: -2}, checkout.CPU)
+	assert.False(t, checkout.Drift)
+
+	assert.Equal(t, "payments", payments.Target)
+	assert.Equal(t, 12, payments.BaselineSamples)
+	assert.Equal(t, 1.0, payments.BaselineCPU.MAE)
+	assert.Equal(t, 6.0, payments.CPU.MAE)
+	assert.True(t, payments.Drift)
+	assert.Equal(t, "cpu MAE rose from 1.00 to 6.00 points", payments.DriftReason)
+
+	// Filters narrow the summary
+	summary = tracker.Accuracy(AccuracyFilter{Target: "checkout", Window: time.Hour})
+	require.Len(t, summary.Groups, 1)
+	assert.Equal(t, 1, summary.Evaluated)
+}
+
+func TestComputeErrorStats(t *testing.T) {
+	stats := ComputeErrorStats([]float64{50, 60, 10}, []float64{40, 60, 0})
+	assert.Equal(t, 6.67, stats.MAE)
+	assert.Equal(t, 6.67, stats.Bias)
+	assert.Equal(t, 12.5, stats.MAPE, "zero actuals are excluded from MAPE")
+
+	assert.Equal(t, ErrorStats{}, ComputeErrorStats(nil, nil))
+}
+
+func TestPredictionStore_Limit(t *testing.T) {
+	dir := t.TempDir()
+	store := storage.NewPredictionStore(dir, 2)
+	base := time.Now()
+	for n := 0; n < 3; n++ {
+		r := record("payments", base, 1, 1)
+		r.CreatedAt = base.Add(time.Duration(n) * time.Minute)
+		require.NoError(t, store.Add([]*models.PredictionRecord{r}))
+	}
+	assert.Equal(t, 2, store.Count(), "oldest predictions are dropped")
+
+	// Predictions survive a restart
+	reloaded := storage.NewPredictionStore(dir, 2)
+	assert.Equal(t, 2, reloaded.Count())
+}
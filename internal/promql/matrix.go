@@ -0,0 +1,57 @@
+package promql
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MatrixVersions are the Kubernetes versions of the compatibility matrix, one per
+// metric change that affects the templates plus the current OpenShift releases
+var MatrixVersions = []Version{
+	{Major: 1, Minor: 20},
+	{Major: 1, Minor: 21},
+	{Major: 1, Minor: 23},
+	{Major: 1, Minor: 27},
+	{Major: 1, Minor: 28},
+	{Major: 1, Minor: 30},
+}
+
+// CompatibilityMatrix renders a markdown table of the variants each template uses on
+// each version, in the order they are tried. Templates with a single unbounded
+// variant are left out, as they are the same everywhere.
+func CompatibilityMatrix(versions []Version) string {
+	var b strings.Builder
+
+	b.WriteString("| Template |")
+	for _, version := range versions {
+		fmt.Fprintf(&b, " Kubernetes %s (OpenShift 4.%d) |", version, version.Minor-13)
+	}
+	b.WriteString("\n|---|")
+	b.WriteString(strings.Repeat("---|", len(versions)))
+	b.WriteString("\n")
+
+	library := Default()
+	for _, name := range library.Names() {
+		tmpl, _ := library.Template(name)
+		if len(tmpl.Variants) == 1 && !tmpl.Variants[0].Since.Known() && !tmpl.Variants[0].Until.Known() {
+			continue
+		}
+
+		fmt.Fprintf(&b, "| `%s` |", tmpl.ID())
+		for _, version := range versions {
+			var labels []string
+			for _, variant := range tmpl.Variants {
+				if variant.appliesTo(version) {
+					labels = append(labels, variant.Label)
+				}
+			}
+			if len(labels) == 0 {
+				b.WriteString(" unsupported |")
+				continue
+			}
+			fmt.Fprintf(&b, " %s |", strings.Join(labels, " → "))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
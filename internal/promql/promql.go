@@ -0,0 +1,255 @@
+// Package promql holds the PromQL queries of the engine as named, versioned
+// templates.
+//
+// A template has one or more variants, tried in order: later variants are fallbacks
+// for clusters where an earlier one returns no data, e.g. without kube-state-metrics.
+// Variants can be limited to a range of Kubernetes versions when a metric was renamed,
+// e.g. etcd_object_counts (before 1.23) and apiserver_storage_objects (1.21 and
+// later). A Library for a known cluster version only offers the variants that apply
+// to it; for an unknown version it offers all of them.
+package promql
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// Version is a Kubernetes minor version; the zero value is an unknown version
+type Version struct {
+	Major int
+	Minor int
+}
+
+// ParseKubernetesVersion parses the major and minor fields of a Kubernetes version
+// as reported by the API server's /version endpoint, e.g. "1" and "27+"
+func ParseKubernetesVersion(major, minor string) (Version, error) {
+	maj, err := strconv.Atoi(strings.TrimSuffix(major, "+"))
+	if err != nil {
+		return Version{}, fmt.Errorf("invalid Kubernetes major version %q", major)
+	}
+	min, err := strconv.Atoi(strings.TrimSuffix(minor, "+"))
+	if err != nil {
+		return Version{}, fmt.Errorf("invalid Kubernetes minor version %q", minor)
+	}
+	return Version{Major: maj, Minor: min}, nil
+}
+
+// OpenShiftVersion returns the Kubernetes version an OpenShift 4 release is based
+// on, e.g. 1.27 for "4.14" or "4.14.3"
+func OpenShiftVersion(release string) (Version, error) {
+	parts := strings.Split(release, ".")
+	if len(parts) < 2 || parts[0] != "4" {
+		return Version{}, fmt.Errorf("unsupported OpenShift version %q", release)
+	}
+	minor, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return Version{}, fmt.Errorf("unsupported OpenShift version %q", release)
+	}
+	return Version{Major: 1, Minor: minor + 13}, nil
+}
+
+// Known reports whether the version is set
+func (v Version) Known() bool {
+	return v != Version{}
+}
+
+// Before reports whether v is older than other
+func (v Version) Before(other Version) bool {
+	if v.Major != other.Major {
+		return v.Major < other.Major
+	}
+	return v.Minor < other.Minor
+}
+
+// String returns "1.27", or "unknown"
+func (v Version) String() string {
+	if !v.Known() {
+		return "unknown"
+	}
+	return fmt.Sprintf("%d.%d", v.Major, v.Minor)
+}
+
+// Variant is one formulation of a query
+type Variant struct {
+	// Label names the variant in logs and the compatibility matrix
+	Label string
+
+	// Query is a text/template rendered with Params
+	Query string
+
+	// Since and Until bound the Kubernetes versions the variant applies to; Since is
+	// inclusive, Until exclusive, and a zero version is unbounded
+	Since Version
+	Until Version
+}
+
+// appliesTo reports whether the variant can be used on a cluster version
+func (v Variant) appliesTo(version Version) bool {
+	if !version.Known() {
+		return true
+	}
+	if v.Since.Known() && version.Before(v.Since) {
+		return false
+	}
+	return !v.Until.Known() || version.Before(v.Until)
+}
+
+// Template is a named query. Version is raised whenever the query's meaning changes,
+// so results computed with different versions are not compared by mistake.
+type Template struct {
+	Name        string
+	Version     int
+	Description string
+	Variants    []Variant
+}
+
+// ID returns "name@vN"
+func (t Template) ID() string {
+	return fmt.Sprintf("%s@v%d", t.Name, t.Version)
+}
+
+// Params are the values templates are rendered with. Each template uses a subset.
+type Params struct {
+	// Selector is a list of label matchers without braces, e.g. `namespace="a",pod!=""`
+	Selector string
+
+	Namespace string
+
+	// Window is a PromQL duration such as "5m" or "24h"
+	Window string
+
+	Verb     string
+	Metric   string
+	Quantile float64
+
+	// Query is the inner query of templates that wrap another query
+	Query string
+}
+
+// funcs are the functions available to templates
+var funcs = template.FuncMap{
+	// quote renders a label value, as %q does
+	"quote": strconv.Quote,
+	// sel wraps label matchers in braces
+	"sel": func(matchers string) string { return "{" + matchers + "}" },
+}
+
+type compiled struct {
+	Template
+	variants []*template.Template
+}
+
+// Library renders the query templates for one cluster version
+type Library struct {
+	version   Version
+	templates map[string]*compiled
+}
+
+var registry = mustCompile(templates)
+
+// mustCompile parses the built-in templates; a malformed template is a programming error
+func mustCompile(defs []Template) map[string]*compiled {
+	compiledTemplates := make(map[string]*compiled, len(defs))
+	for _, def := range defs {
+		if _, exists := compiledTemplates[def.Name]; exists {
+			panic("promql: duplicate template " + def.Name)
+		}
+		c := &compiled{Template: def}
+		for _, variant := range def.Variants {
+			tmpl := template.Must(template.New(def.Name + "/" + variant.Label).
+				Funcs(funcs).Option("missingkey=error").Parse(variant.Query))
+			c.variants = append(c.variants, tmpl)
+		}
+		compiledTemplates[def.Name] = c
+	}
+	return compiledTemplates
+}
+
+// NewLibrary returns the library for a Kubernetes version; the zero version offers
+// every variant
+func NewLibrary(version Version) *Library {
+	return &Library{version: version, templates: registry}
+}
+
+var defaultLibrary = NewLibrary(Version{})
+
+// Default returns the library for an unknown cluster version
+func Default() *Library {
+	return defaultLibrary
+}
+
+// Version returns the Kubernetes version the library renders for
+func (l *Library) Version() Version {
+	return l.version
+}
+
+// Names returns the names of all templates, sorted
+func (l *Library) Names() []string {
+	names := make([]string, 0, len(l.templates))
+	for name := range l.templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Template returns a template by name
+func (l *Library) Template(name string) (Template, bool) {
+	c, ok := l.templates[name]
+	if !ok {
+		return Template{}, false
+	}
+	return c.Template, true
+}
+
+// Candidates renders the variants of a template that apply to the library's version,
+// in the order they should be tried
+func (l *Library) Candidates(name string, params Params) ([]string, error) {
+	c, ok := l.templates[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown PromQL template %q", name)
+	}
+
+	var queries []string
+	for i, variant := range c.Variants {
+		if !variant.appliesTo(l.version) {
+			continue
+		}
+		var b strings.Builder
+		if err := c.variants[i].Execute(&b, params); err != nil {
+			return nil, fmt.Errorf("failed to render PromQL template %s/%s: %w", c.ID(), variant.Label, err)
+		}
+		queries = append(queries, b.String())
+	}
+	if len(queries) == 0 {
+		return nil, fmt.Errorf("PromQL template %s has no variant for Kubernetes %s", c.ID(), l.version)
+	}
+	return queries, nil
+}
+
+// Render renders the first variant of a template that applies to the library's version
+func (l *Library) Render(name string, params Params) (string, error) {
+	queries, err := l.Candidates(name, params)
+	if err != nil {
+		return "", err
+	}
+	return queries[0], nil
+}
+
+// MustCandidates is Candidates for the built-in templates, which are covered by
+// golden tests; it panics if a template is unknown or fails to render
+func (l *Library) MustCandidates(name string, params Params) []string {
+	queries, err := l.Candidates(name, params)
+	if err != nil {
+		panic(err)
+	}
+	return queries
+}
+
+// MustRender is Render for the built-in templates; it panics like MustCandidates
+func (l *Library) MustRender(name string, params Params) string {
+	return l.MustCandidates(name, params)[0]
+}
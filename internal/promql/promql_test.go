@@ -0,0 +1,127 @@
+package promql
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+// goldenParams fills every parameter, so each template renders completely
+var goldenParams = Params{
+	Selector:  `namespace="payments",pod=~"api-.*"`,
+	Namespace: "payments",
+	Window:    "24h",
+	Verb:      "LIST",
+	Metric:    "http_request_duration_seconds",
+	Quantile:  0.99,
+	Query:     `sum(rate(http_requests_total{namespace="payments"}[5m]))`,
+}
+
+// assertGolden compares output with testdata/name, rewriting it with -update
+func assertGolden(t *testing.T, name, output string) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		require.NoError(t, os.WriteFile(path, []byte(output), 0o600))
+	}
+	want, err := os.ReadFile(path) //#nosec G304 -- test fixture
+	require.NoError(t, err)
+	assert.Equal(t, string(want), output, "rendered output differs from %s; run go test ./internal/promql -update if the change is intended", path)
+}
+
+func TestTemplates_Golden(t *testing.T) {
+	library := Default()
+	var b strings.Builder
+	for _, name := range library.Names() {
+		tmpl, _ := library.Template(name)
+		fmt.Fprintf(&b, "# %s: %s\n", tmpl.ID(), tmpl.Description)
+
+		scoped, err := library.Candidates(name, goldenParams)
+		require.NoError(t, err, name)
+		unscoped, err := library.Candidates(name, Params{})
+		require.NoError(t, err, name)
+		require.Len(t, scoped, len(tmpl.Variants), "an unknown version renders every variant")
+
+		for i, variant := range tmpl.Variants {
+			fmt.Fprintf(&b, "## %s", variant.Label)
+			if variant.Since.Known() {
+				fmt.Fprintf(&b, " since %s", variant.Since)
+			}
+			if variant.Until.Known() {
+				fmt.Fprintf(&b, " until %s", variant.Until)
+			}
+			fmt.Fprintf(&b, "\n%s\n", scoped[i])
+			// Templates with an optional selector also render without one
+			if strings.Contains(variant.Query, "with .Selector") {
+				fmt.Fprintf(&b, "%s\n", unscoped[i])
+			}
+		}
+		b.WriteString("\n")
+	}
+	assertGolden(t, "queries.golden", b.String())
+}
+
+func TestCompatibilityMatrix_Golden(t *testing.T) {
+	assertGolden(t, "compatibility_matrix.golden", CompatibilityMatrix(MatrixVersions))
+}
+
+func TestLibrary_VersionVariants(t *testing.T) {
+	tests := []struct {
+		version Version
+		etcd    []string
+		latency []string
+	}{
+		{Version{}, []string{"sum(etcd_object_counts)", "sum(apiserver_storage_objects)"}, []string{"scheduling_attempt", "e2e_scheduling"}},
+		{Version{Major: 1, Minor: 20}, []string{"sum(etcd_object_counts)"}, []string{"e2e_scheduling"}},
+		{Version{Major: 1, Minor: 22}, []string{"sum(etcd_object_counts)", "sum(apiserver_storage_objects)"}, []string{"e2e_scheduling"}},
+		{Version{Major: 1, Minor: 27}, []string{"sum(apiserver_storage_objects)"}, []string{"scheduling_attempt", "e2e_scheduling"}},
+		{Version{Major: 1, Minor: 30}, []string{"sum(apiserver_storage_objects)"}, []string{"scheduling_attempt"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version.String(), func(t *testing.T) {
+			library := NewLibrary(tt.version)
+			assert.Equal(t, tt.etcd, library.MustCandidates("etcd.object_count", Params{}))
+
+			latency := library.MustCandidates("scheduler.latency_p99", Params{})
+			require.Len(t, latency, len(tt.latency))
+			for i, metric := range tt.latency {
+				assert.Contains(t, latency[i], metric)
+			}
+		})
+	}
+}
+
+func TestLibrary_Errors(t *testing.T) {
+	_, err := Default().Render("no.such_query", Params{})
+	assert.ErrorContains(t, err, "unknown PromQL template")
+
+	assert.Panics(t, func() { Default().MustRender("no.such_query", Params{}) })
+}
+
+func TestParseVersions(t *testing.T) {
+	version, err := ParseKubernetesVersion("1", "27+")
+	require.NoError(t, err)
+	assert.Equal(t, Version{Major: 1, Minor: 27}, version)
+	assert.Equal(t, "1.27", version.String())
+
+	_, err = ParseKubernetesVersion("1", "")
+	assert.Error(t, err)
+
+	version, err = OpenShiftVersion("4.14.3")
+	require.NoError(t, err)
+	assert.Equal(t, Version{Major: 1, Minor: 27}, version)
+
+	_, err = OpenShiftVersion("3.11")
+	assert.Error(t, err)
+
+	assert.Equal(t, "unknown", Version{}.String())
+	assert.True(t, Version{Major: 1, Minor: 9}.Before(Version{Major: 1, Minor: 10}))
+}
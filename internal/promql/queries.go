@@ -0,0 +1,435 @@
+package promql
+
+// Kubernetes versions at which metrics used by the templates changed
+var (
+	// apiserver_storage_objects replaced etcd_object_counts in 1.21; the old name was
+	// removed in 1.23
+	storageObjectsSince  = Version{Major: 1, Minor: 21}
+	etcdObjectCountsTill = Version{Major: 1, Minor: 23}
+
+	// scheduler_scheduling_attempt_duration_seconds was added in 1.23 and
+	// scheduler_e2e_scheduling_duration_seconds removed in 1.28
+	schedulingAttemptSince = Version{Major: 1, Minor: 23}
+	e2eSchedulingTill      = Version{Major: 1, Minor: 28}
+)
+
+// templates are the queries of the engine. Variants are listed in the order they are
+// tried.
+var templates = []Template{
+	// Utilization ratios used for rolling means
+
+	{
+		Name: "cluster.cpu_utilization", Version: 1,
+		Description: "Cluster CPU usage as a fraction of allocatable CPU",
+		Variants: []Variant{
+			{Label: "allocatable", Query: `sum(rate(container_cpu_usage_seconds_total{container!="",pod!=""}[5m])) / sum(kube_node_status_allocatable{resource="cpu"})`},
+			{Label: "node_idle", Query: `1 - avg(rate(node_cpu_seconds_total{mode="idle"}[5m]))`},
+		},
+	},
+	{
+		Name: "cluster.memory_utilization", Version: 1,
+		Description: "Cluster memory working set as a fraction of allocatable memory",
+		Variants: []Variant{
+			{Label: "allocatable", Query: `sum(container_memory_working_set_bytes{container!="",pod!=""}) / sum(kube_node_status_allocatable{resource="memory"})`},
+			{Label: "node_available", Query: `1 - (sum(node_memory_MemAvailable_bytes) / sum(node_memory_MemTotal_bytes))`},
+		},
+	},
+	{
+		Name: "namespace.cpu_utilization", Version: 1,
+		Description: "Namespace CPU usage as a fraction of allocatable CPU, or of the CPU limit quota",
+		Variants: []Variant{
+			{Label: "allocatable", Query: `sum(rate(container_cpu_usage_seconds_total{container!="",pod!="",namespace={{quote .Namespace}}}[5m])) / sum(kube_node_status_allocatable{resource="cpu"})`},
+			{Label: "quota", Query: `sum(rate(container_cpu_usage_seconds_total{container!="",pod!="",namespace={{quote .Namespace}}}[5m])) / sum(kube_resourcequota{resource="limits.cpu",namespace={{quote .Namespace}}})`},
+		},
+	},
+	{
+		Name: "namespace.memory_utilization", Version: 1,
+		Description: "Namespace memory working set as a fraction of allocatable memory, or of the memory limit quota",
+		Variants: []Variant{
+			{Label: "allocatable", Query: `sum(container_memory_working_set_bytes{container!="",pod!="",namespace={{quote .Namespace}}}) / sum(kube_node_status_allocatable{resource="memory"})`},
+			{Label: "quota", Query: `sum(container_memory_working_set_bytes{container!="",pod!="",namespace={{quote .Namespace}}}) / sum(kube_resourcequota{resource="limits.memory",namespace={{quote .Namespace}}})`},
+		},
+	},
+	{
+		Name: "scope.cpu_utilization", Version: 1,
+		Description: "CPU usage of the selected containers as a fraction of allocatable CPU, or of the node CPU count",
+		Variants: []Variant{
+			{Label: "allocatable", Query: `sum(rate(container_cpu_usage_seconds_total{{sel .Selector}}[5m])) / sum(kube_node_status_allocatable{resource="cpu"})`},
+			{Label: "node_cpus", Query: `sum(rate(container_cpu_usage_seconds_total{{sel .Selector}}[5m])) / count(count by (cpu) (node_cpu_seconds_total{mode="idle"}))`},
+		},
+	},
+	{
+		Name: "scope.memory_utilization", Version: 1,
+		Description: "Memory working set of the selected containers as a fraction of allocatable memory, or of node memory",
+		Variants: []Variant{
+			{Label: "allocatable", Query: `sum(container_memory_working_set_bytes{{sel .Selector}}) / sum(kube_node_status_allocatable{resource="memory"})`},
+			{Label: "node_memory", Query: `sum(container_memory_working_set_bytes{{sel .Selector}}) / sum(node_memory_MemTotal_bytes)`},
+		},
+	},
+
+	// Usage
+
+	{
+		Name: "cluster.cpu_usage", Version: 1,
+		Description: "Cluster CPU usage in cores",
+		Variants:    []Variant{{Label: "default", Query: `sum(rate(container_cpu_usage_seconds_total{container!=""}[5m]))`}},
+	},
+	{
+		Name: "cluster.memory_usage", Version: 1,
+		Description: "Cluster memory usage in bytes",
+		Variants:    []Variant{{Label: "default", Query: `sum(container_memory_usage_bytes{container!=""})`}},
+	},
+	{
+		Name: "namespace.cpu_usage", Version: 1,
+		Description: "Namespace CPU usage in cores",
+		Variants:    []Variant{{Label: "default", Query: `sum(rate(container_cpu_usage_seconds_total{namespace={{quote .Namespace}},container!=""}[5m]))`}},
+	},
+	{
+		Name: "namespace.memory_usage", Version: 1,
+		Description: "Namespace memory usage in bytes",
+		Variants:    []Variant{{Label: "default", Query: `sum(container_memory_usage_bytes{namespace={{quote .Namespace}},container!=""})`}},
+	},
+	{
+		Name: "namespace.memory_limit_ratio", Version: 1,
+		Description: "Namespace memory working set as a fraction of memory limits, or of 2Gi per container",
+		Variants: []Variant{
+			{Label: "limits", Query: `sum(container_memory_working_set_bytes{namespace={{quote .Namespace}},container!=""}) / sum(kube_pod_container_resource_limits{resource="memory",namespace={{quote .Namespace}}})`},
+			{Label: "fixed_2gi", Query: `avg(container_memory_working_set_bytes{namespace={{quote .Namespace}},container!=""} / 2147483648)`},
+		},
+	},
+	{
+		Name: "namespace.container_restarts", Version: 1,
+		Description: "Total container restarts in a namespace",
+		Variants:    []Variant{{Label: "default", Query: `sum(kube_pod_container_status_restarts_total{namespace={{quote .Namespace}}})`}},
+	},
+	{
+		Name: "namespace.container_cpu_usage", Version: 1,
+		Description: "CPU usage of each container in a namespace",
+		Variants:    []Variant{{Label: "default", Query: `sum by (pod, container) (rate(container_cpu_usage_seconds_total{namespace={{quote .Namespace}},container!="",container!="POD",pod!=""}[5m]))`}},
+	},
+	{
+		Name: "namespace.container_memory_usage", Version: 1,
+		Description: "Memory working set of each container in a namespace",
+		Variants:    []Variant{{Label: "default", Query: `sum by (pod, container) (container_memory_working_set_bytes{namespace={{quote .Namespace}},container!="",container!="POD",pod!=""})`}},
+	},
+	{
+		Name: "namespace.pod_cpu_usage", Version: 1,
+		Description: "CPU usage of each pod in a namespace in cores",
+		Variants:    []Variant{{Label: "default", Query: `sum by (pod) (rate(container_cpu_usage_seconds_total{namespace={{quote .Namespace}},container!="",container!="POD",pod!=""}[5m]))`}},
+	},
+	{
+		Name: "namespace.pod_memory_usage", Version: 1,
+		Description: "Memory usage of each pod in a namespace in bytes",
+		Variants:    []Variant{{Label: "default", Query: `sum by (pod) (container_memory_usage_bytes{namespace={{quote .Namespace}},container!="",container!="POD",pod!=""})`}},
+	},
+	{
+		Name: "scope.cpu_usage", Version: 1,
+		Description: "CPU usage of the selected containers in cores",
+		Variants:    []Variant{{Label: "default", Query: `sum(rate(container_cpu_usage_seconds_total{{sel .Selector}}[5m]))`}},
+	},
+	{
+		Name: "scope.cpu_usage_avg", Version: 1,
+		Description: "Average CPU usage per selected container over a window",
+		Variants:    []Variant{{Label: "default", Query: `avg(rate(container_cpu_usage_seconds_total{{sel .Selector}}[{{.Window}}]))`}},
+	},
+	{
+		Name: "scope.memory_usage", Version: 1,
+		Description: "Memory usage of the selected containers in bytes",
+		Variants:    []Variant{{Label: "default", Query: `sum(container_memory_usage_bytes{{sel .Selector}})`}},
+	},
+	{
+		Name: "scope.memory_limit_ratio", Version: 1,
+		Description: "Average memory usage of the selected containers over a window, as a fraction of their limit or of 2Gi",
+		Variants: []Variant{
+			{Label: "limits", Query: `avg(avg_over_time(container_memory_usage_bytes{{sel .Selector}}[{{.Window}}]) / container_spec_memory_limit_bytes{{sel .Selector}} > 0)`},
+			{Label: "fixed_2gi", Query: `avg(avg_over_time(container_memory_usage_bytes{{sel .Selector}}[{{.Window}}]) / 2147483648)`},
+		},
+	},
+	{
+		Name: "scope.cpu_trend", Version: 1,
+		Description: "Hourly CPU usage of the selected containers over a window",
+		Variants:    []Variant{{Label: "default", Query: `avg_over_time(sum(rate(container_cpu_usage_seconds_total{{sel .Selector}}[5m]))[{{.Window}}:1h])`}},
+	},
+	{
+		Name: "scope.memory_trend", Version: 1,
+		Description: "Hourly memory usage of the selected containers over a window",
+		Variants:    []Variant{{Label: "default", Query: `avg_over_time(sum(container_memory_usage_bytes{{sel .Selector}})[{{.Window}}:1h])`}},
+	},
+	{
+		Name: "range.quantile_over_time", Version: 1,
+		Description: "Quantile of a query over a window, sampled every 5 minutes",
+		Variants:    []Variant{{Label: "default", Query: `quantile_over_time({{.Quantile}}, {{.Query}}[{{.Window}}:5m])`}},
+	},
+
+	// Control plane
+
+	{
+		Name: "etcd.object_count", Version: 1,
+		Description: "Number of objects stored in etcd",
+		Variants: []Variant{
+			{Label: "etcd_object_counts", Query: `sum(etcd_object_counts)`, Until: etcdObjectCountsTill},
+			{Label: "apiserver_storage_objects", Query: `sum(apiserver_storage_objects)`, Since: storageObjectsSince},
+		},
+	},
+	{
+		Name: "etcd.db_size_mb", Version: 1,
+		Description: "etcd database size in MB, a rough estimate of the object count",
+		Variants:    []Variant{{Label: "default", Query: `sum(etcd_mvcc_db_total_size_in_bytes) / 1024 / 1024`}},
+	},
+	{
+		Name: "etcd.has_leader", Version: 1,
+		Description: "Number of etcd members that see a leader",
+		Variants:    []Variant{{Label: "default", Query: `sum(etcd_server_has_leader)`}},
+	},
+	{
+		Name: "etcd.db_size_bytes", Version: 1,
+		Description: "Size of the largest etcd member database in bytes, the one the backend quota is reached on first",
+		Variants:    []Variant{{Label: "default", Query: `max(etcd_mvcc_db_total_size_in_bytes)`}},
+	},
+	{
+		Name: "etcd.db_in_use_bytes", Version: 1,
+		Description: "Bytes in use in the largest etcd member database; the rest is freed pages a defragmentation returns",
+		Variants:    []Variant{{Label: "default", Query: `max(etcd_mvcc_db_total_size_in_use_in_bytes)`}},
+	},
+	{
+		Name: "etcd.db_growth_bytes_per_day", Version: 1,
+		Description: "Growth of the fastest growing etcd member database over the window in bytes per day",
+		Variants:    []Variant{{Label: "default", Query: `max(deriv(etcd_mvcc_db_total_size_in_bytes[{{.Window}}])) * 86400`}},
+	},
+	{
+		Name: "etcd.quota_bytes", Version: 1,
+		Description: "Smallest etcd backend quota in bytes, past which etcd raises a NOSPACE alarm and refuses writes",
+		Variants:    []Variant{{Label: "default", Query: `min(etcd_server_quota_backend_bytes)`}},
+	},
+	{
+		Name: "etcd.wal_fsync_p99", Version: 1,
+		Description: "99th percentile etcd write-ahead log fsync duration in seconds, the disk latency every write waits for",
+		Variants:    []Variant{{Label: "default", Query: `histogram_quantile(0.99, sum by (le) (rate(etcd_disk_wal_fsync_duration_seconds_bucket[5m])))`}},
+	},
+	{
+		Name: "apiserver.request_rate", Version: 1,
+		Description: "API server requests per second",
+		Variants:    []Variant{{Label: "default", Query: `sum(rate(apiserver_request_total[5m]))`}},
+	},
+	{
+		Name: "apiserver.request_latency_p99", Version: 1,
+		Description: "99th percentile API server request latency in seconds, excluding long-running watches and connections",
+		Variants:    []Variant{{Label: "default", Query: `histogram_quantile(0.99, sum by (le) (rate(apiserver_request_duration_seconds_bucket{verb!~"WATCH|CONNECT"}[5m])))`}},
+	},
+	{
+		Name: "apiserver.request_rate_by_verb", Version: 1,
+		Description: "API server requests per second for one verb",
+		Variants:    []Variant{{Label: "default", Query: `sum(rate(apiserver_request_total{verb={{quote .Verb}}}[5m]))`}},
+	},
+	{
+		Name: "apiserver.request_rate_baseline_mean", Version: 1,
+		Description: "Mean API server requests per second of each verb over the window ending an hour ago",
+		Variants:    []Variant{{Label: "default", Query: `avg_over_time(sum by (verb) (rate(apiserver_request_total[5m]))[{{.Window}}:5m] offset 1h)`}},
+	},
+	{
+		Name: "apiserver.request_rate_baseline_stddev", Version: 1,
+		Description: "Standard deviation of the API server requests per second of each verb over the window ending an hour ago",
+		Variants:    []Variant{{Label: "default", Query: `stddev_over_time(sum by (verb) (rate(apiserver_request_total[5m]))[{{.Window}}:5m] offset 1h)`}},
+	},
+	{
+		Name: "apiserver.top_clients_by_verb", Version: 1,
+		Description: "The five clients sending the most API server requests per second of one verb",
+		Variants:    []Variant{{Label: "default", Query: `topk(5, sum by (client) (rate(apiserver_request_total{verb={{quote .Verb}},client!=""}[5m])))`}},
+	},
+	{
+		Name: "scheduler.pending_pods", Version: 1,
+		Description: "Pods waiting in the scheduler queues",
+		Variants:    []Variant{{Label: "default", Query: `sum(scheduler_pending_pods)`}},
+	},
+	{
+		Name: "scheduler.unschedulable_pods", Version: 1,
+		Description: "Pods in the scheduler's unschedulable queue",
+		Variants:    []Variant{{Label: "default", Query: `sum(scheduler_pending_pods{queue="unschedulable"})`}},
+	},
+	{
+		Name: "scheduler.pending_pods_min", Version: 1,
+		Description: "Fewest pods waiting in the scheduler queues over the window",
+		Variants:    []Variant{{Label: "default", Query: `min_over_time(sum(scheduler_pending_pods)[{{.Window}}:1m])`}},
+	},
+	{
+		Name: "scheduler.unschedulable_pods_min", Version: 1,
+		Description: "Fewest pods in the scheduler's unschedulable queue over the window",
+		Variants:    []Variant{{Label: "default", Query: `min_over_time(sum(scheduler_pending_pods{queue="unschedulable"})[{{.Window}}:1m])`}},
+	},
+	{
+		Name: "scheduler.attempt_rate", Version: 1,
+		Description: "Scheduling attempts per second",
+		Variants:    []Variant{{Label: "default", Query: `sum(rate(scheduler_schedule_attempts_total[5m]))`}},
+	},
+	{
+		Name: "scheduler.latency_p99", Version: 1,
+		Description: "99th percentile scheduling latency in seconds",
+		Variants: []Variant{
+			{Label: "attempt_duration", Query: `histogram_quantile(0.99, sum(rate(scheduler_scheduling_attempt_duration_seconds_bucket[5m])) by (le))`, Since: schedulingAttemptSince},
+			{Label: "e2e_duration", Query: `histogram_quantile(0.99, sum(rate(scheduler_e2e_scheduling_duration_seconds_bucket[5m])) by (le))`, Until: e2eSchedulingTill},
+		},
+	},
+	{
+		Name: "controller_manager.workqueue_depth", Version: 1,
+		Description: "Items waiting in controller work queues",
+		Variants:    []Variant{{Label: "default", Query: `sum(workqueue_depth)`}},
+	},
+	{
+		Name: "controller_manager.workqueue_adds_rate", Version: 1,
+		Description: "Items added to controller work queues per second",
+		Variants:    []Variant{{Label: "default", Query: `sum(rate(workqueue_adds_total[5m]))`}},
+	},
+	{
+		Name: "controller_manager.workqueue_retries_rate", Version: 1,
+		Description: "Controller work queue retries per second",
+		Variants:    []Variant{{Label: "default", Query: `sum(rate(workqueue_retries_total[5m]))`}},
+	},
+
+	// Anomaly detection base metrics. Selector is optional; the anomaly.* metrics are
+	// the ones the anomaly API sends to the models, the feature_vector.* ones are used
+	// by PrometheusClient.BuildAnomalyFeatureVector.
+
+	{
+		Name: "node.cpu_utilization", Version: 1,
+		Description: "Average node CPU utilization",
+		Variants:    []Variant{{Label: "default", Query: `avg(1 - rate(node_cpu_seconds_total{mode="idle"{{with .Selector}},{{.}}{{end}}}[5m]))`}},
+	},
+	{
+		Name: "node.memory_utilization", Version: 1,
+		Description: "Node memory utilization",
+		Variants:    []Variant{{Label: "default", Query: `1 - (node_memory_MemAvailable_bytes{{with .Selector}}{{sel .}}{{end}} / node_memory_MemTotal_bytes{{with .Selector}}{{sel .}}{{end}})`}},
+	},
+	{
+		Name: "anomaly.pod_cpu_usage", Version: 1,
+		Description: "CPU usage per pod",
+		Variants:    []Variant{{Label: "default", Query: `sum(rate(container_cpu_usage_seconds_total{container!=""{{with .Selector}},{{.}}{{end}}}[5m])) by (pod)`}},
+	},
+	{
+		Name: "anomaly.pod_memory_usage", Version: 1,
+		Description: "Memory working set per pod as a fraction of its memory limits",
+		Variants:    []Variant{{Label: "default", Query: `sum(container_memory_working_set_bytes{container!=""{{with .Selector}},{{.}}{{end}}}) by (pod) / sum(kube_pod_container_resource_limits{resource="memory"{{with .Selector}},{{.}}{{end}}}) by (pod)`}},
+	},
+	{
+		Name: "anomaly.container_restarts", Version: 1,
+		Description: "Container restarts per pod",
+		Variants:    []Variant{{Label: "default", Query: `sum(kube_pod_container_status_restarts_total{{sel .Selector}}) by (pod)`}},
+	},
+	{
+		Name: "feature_vector.pod_cpu_usage", Version: 1,
+		Description: "CPU usage of the selected containers",
+		Variants:    []Variant{{Label: "default", Query: `sum(rate(container_cpu_usage_seconds_total{container!=""{{with .Selector}},{{.}}{{end}}}[5m]))`}},
+	},
+	{
+		Name: "feature_vector.pod_memory_usage", Version: 1,
+		Description: "Memory working set of the selected containers as a fraction of their memory limits",
+		Variants:    []Variant{{Label: "default", Query: `sum(container_memory_working_set_bytes{container!=""{{with .Selector}},{{.}}{{end}}}) / sum(kube_pod_container_resource_limits{resource="memory"{{with .Selector}},{{.}}{{end}}})`}},
+	},
+	{
+		Name: "feature_vector.container_restarts", Version: 1,
+		Description: "Container restarts of the selected pods",
+		Variants:    []Variant{{Label: "default", Query: `sum(kube_pod_container_status_restarts_total{{with .Selector}}{{sel .}}{{end}})`}},
+	},
+	{
+		Name: "histogram.quantile", Version: 1,
+		Description: "Quantile of a histogram metric over the selected series",
+		Variants:    []Variant{{Label: "default", Query: `histogram_quantile({{.Quantile}}, sum by (le) (rate({{.Metric}}_bucket{{sel .Selector}}[5m])))`}},
+	},
+
+	// External availability of Routes and Ingresses, probed by blackbox-exporter
+
+	{
+		Name: "route.availability", Version: 1,
+		Description: "Fraction of the selected blackbox probes currently succeeding",
+		Variants:    []Variant{{Label: "default", Query: `avg(probe_success{{sel .Selector}})`}},
+	},
+	{
+		Name: "route.latency", Version: 1,
+		Description: "Slowest duration of the selected blackbox probes in seconds",
+		Variants:    []Variant{{Label: "default", Query: `max(probe_duration_seconds{{sel .Selector}})`}},
+	},
+	{
+		Name: "route.availability_by_target", Version: 1,
+		Description: "Fraction of successful blackbox probes of each target within the window",
+		Variants:    []Variant{{Label: "default", Query: `avg by (instance) (avg_over_time(probe_success{{sel .Selector}}[{{.Window}}]))`}},
+	},
+	{
+		Name: "route.latency_by_target", Version: 1,
+		Description: "Mean blackbox probe duration of each target within the window in seconds",
+		Variants:    []Variant{{Label: "default", Query: `max by (instance) (avg_over_time(probe_duration_seconds{{sel .Selector}}[{{.Window}}]))`}},
+	},
+
+	// In-cluster DNS served by CoreDNS
+
+	{
+		Name: "dns.error_ratio", Version: 1,
+		Description: "Fraction of DNS responses that were server failures or refusals; NXDOMAIN is expected from search paths",
+		Variants:    []Variant{{Label: "default", Query: `(sum(rate(coredns_dns_responses_total{rcode=~"SERVFAIL|REFUSED"}[5m])) or vector(0)) / sum(rate(coredns_dns_responses_total[5m]))`}},
+	},
+	{
+		Name: "dns.latency_p99", Version: 1,
+		Description: "p99 DNS request duration in seconds",
+		Variants:    []Variant{{Label: "default", Query: `histogram_quantile(0.99, sum by (le) (rate(coredns_dns_request_duration_seconds_bucket[5m])))`}},
+	},
+
+	{
+		Name: "probe.series_count", Version: 1,
+		Description: "Number of series of a metric, to probe whether its exporter is installed",
+		Variants:    []Variant{{Label: "default", Query: `count({{.Metric}})`}},
+	},
+
+	// Job run history recorded by kube-state-metrics, kept after the Jobs are deleted
+
+	{
+		Name: "batch.job_start_time", Version: 1,
+		Description: "Start time of each Job of a namespace seen within the window",
+		Variants:    []Variant{{Label: "default", Query: `max by (job_name) (max_over_time(kube_job_status_start_time{namespace={{quote .Namespace}}}[{{.Window}}]))`}},
+	},
+	{
+		Name: "batch.job_completion_time", Version: 1,
+		Description: "Completion time of each Job of a namespace that succeeded within the window",
+		Variants:    []Variant{{Label: "default", Query: `max by (job_name) (max_over_time(kube_job_status_completion_time{namespace={{quote .Namespace}}}[{{.Window}}]))`}},
+	},
+	{
+		Name: "batch.job_failed", Version: 1,
+		Description: "Jobs of a namespace that failed within the window",
+		Variants:    []Variant{{Label: "default", Query: `max by (job_name) (max_over_time(kube_job_failed{namespace={{quote .Namespace}},condition="true"}[{{.Window}}])) > 0`}},
+	},
+	{
+		Name: "batch.job_owner", Version: 1,
+		Description: "CronJob owning each Job of a namespace seen within the window",
+		Variants:    []Variant{{Label: "default", Query: `max by (job_name, owner_name) (max_over_time(kube_job_owner{namespace={{quote .Namespace}},owner_kind="CronJob"}[{{.Window}}]))`}},
+	},
+
+	// Rolling statistics of another query, the derived anomaly features
+
+	{
+		Name: "rolling.mean_5m", Version: 1,
+		Description: "5-minute rolling mean of a query",
+		Variants:    []Variant{{Label: "default", Query: `avg_over_time(({{.Query}})[5m:])`}},
+	},
+	{
+		Name: "rolling.stddev_5m", Version: 1,
+		Description: "5-minute rolling standard deviation of a query",
+		Variants:    []Variant{{Label: "default", Query: `stddev_over_time(({{.Query}})[5m:])`}},
+	},
+	{
+		Name: "rolling.min_5m", Version: 1,
+		Description: "5-minute rolling minimum of a query",
+		Variants:    []Variant{{Label: "default", Query: `min_over_time(({{.Query}})[5m:])`}},
+	},
+	{
+		Name: "rolling.max_5m", Version: 1,
+		Description: "5-minute rolling maximum of a query",
+		Variants:    []Variant{{Label: "default", Query: `max_over_time(({{.Query}})[5m:])`}},
+	},
+	{
+		Name: "rolling.lag_1m", Version: 1,
+		Description: "A query's value one minute ago",
+		Variants:    []Variant{{Label: "default", Query: `({{.Query}}) offset 1m`}},
+	},
+	{
+		Name: "rolling.lag_5m", Version: 1,
+		Description: "A query's value five minutes ago",
+		Variants:    []Variant{{Label: "default", Query: `({{.Query}}) offset 5m`}},
+	},
+}
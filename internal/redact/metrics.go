@@ -0,0 +1,22 @@
+package redact
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// Redactions counts masked values by source
+	Redactions = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "coordination_engine_redactions_total",
+			Help: "Total number of credentials masked before storage or notification, by source (incident, evidence, explanation)",
+		},
+		[]string{"source"},
+	)
+)
+
+// RecordRedactions records masked values
+func RecordRedactions(source string, count int) {
+	Redactions.WithLabelValues(source).Add(float64(count))
+}
@@ -0,0 +1,458 @@
+package remediation
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/api/validation"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// Budget settings
+const (
+	// BudgetWindow is the rolling window the daily limit counts actions in
+	BudgetWindow = 24 * time.Hour
+
+	// DefaultBudgetApprovalTTL is how long a budget approval stays valid if the
+	// approver sets no TTL
+	DefaultBudgetApprovalTTL = 24 * time.Hour
+
+	// MaxBudgetApprovalTTL bounds the TTL of budget approvals
+	MaxBudgetApprovalTTL = 7 * 24 * time.Hour
+)
+
+// Reasons a remediation exceeds its budget
+const (
+	BudgetReasonDailyLimit = "daily_limit"
+	BudgetReasonCooldown   = "cooldown"
+)
+
+// BudgetPolicy limits automated remediation
+type BudgetPolicy struct {
+	// DailyLimit is the number of automated actions allowed per namespace in a
+	// rolling 24 hours; 0 is unlimited
+	DailyLimit int
+
+	// Cooldown is the minimum time between actions on the same resource; 0 disables it
+	Cooldown time.Duration
+}
+
+// BudgetExceededError is returned when a remediation would exceed its namespace's
+// daily limit or its resource's cooldown
+type BudgetExceededError struct {
+	Reason    string
+	Namespace string
+	Target    string
+	Limit     int
+	Used      int
+
+	// RetryAfter is when the remediation would be admitted without an approval
+	RetryAfter time.Time
+}
+
+// Error implements the error interface
+func (e *BudgetExceededError) Error() string {
+	if e.Reason == BudgetReasonCooldown {
+		return fmt.Sprintf("%s was remediated recently and is cooling down until %s", e.Target, e.RetryAfter.UTC().Format(time.RFC3339))
+	}
+	return fmt.Sprintf("namespace %s used %d of %d automated remediations in the last 24h; the next is allowed at %s",
+		e.Namespace, e.Used, e.Limit, e.RetryAfter.UTC().Format(time.RFC3339))
+}
+
+// BudgetApproval lets remediations exceed the budget. It applies to a namespace, or
+// only to one resource in it, and is used up after Actions remediations or when it
+// expires.
+type BudgetApproval struct {
+	ID        string `json:"id"`
+	Namespace string `json:"namespace"`
+
+	// Target is "<kind>/<name>"; empty approves any resource in the namespace
+	Target string `json:"target,omitempty"`
+
+	// Actions is the number of remediations the approval still admits
+	Actions int `json:"actions"`
+
+	ApprovedBy string    `json:"approved_by"`
+	Reason     string    `json:"reason,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// BudgetApprovalRequest asks for a budget approval
+type BudgetApprovalRequest struct {
+	Namespace  string `json:"namespace"`
+	Target     string `json:"target,omitempty"`
+	Actions    int    `json:"actions,omitempty"`
+	ApprovedBy string `json:"approved_by"`
+	Reason     string `json:"reason,omitempty"`
+
+	// TTL is how long the approval is valid, e.g. "4h"; default 24h, at most 7 days
+	TTL string `json:"ttl,omitempty"`
+}
+
+// Validate checks the request
+func (r *BudgetApprovalRequest) Validate() error {
+	var errs validation.Errors
+	if r.Namespace == "" {
+		errs.Add("namespace", validation.ConstraintRequired, nil, "namespace is required")
+	}
+	if r.Target != "" {
+		if kind, name, ok := strings.Cut(r.Target, "/"); !ok || kind == "" || name == "" {
+			errs.Add("target", validation.ConstraintFormat, r.Target, "target must be <kind>/<name>")
+		}
+	}
+	if r.Actions < 0 || r.Actions > 100 {
+		errs.Add("actions", validation.ConstraintRange, r.Actions, "actions must be between 1 and 100")
+	}
+	if r.ApprovedBy == "" {
+		errs.Add("approved_by", validation.ConstraintRequired, nil, "approved_by is required")
+	}
+	if r.TTL != "" {
+		if ttl, err := time.ParseDuration(r.TTL); err != nil || ttl <= 0 || ttl > MaxBudgetApprovalTTL {
+			errs.Add("ttl", validation.ConstraintFormat, r.TTL, "ttl must be a positive duration of at most 168h")
+		}
+	}
+	return errs.Err()
+}
+
+// NamespaceBudget is the budget state of a namespace
+type NamespaceBudget struct {
+	Namespace string `json:"namespace"`
+
+	// Limit is the daily limit (0 is unlimited); Used counts the actions in the last
+	// 24 hours and Remaining those still allowed
+	Limit     int `json:"limit"`
+	Used      int `json:"used"`
+	Remaining int `json:"remaining,omitempty"`
+
+	// ResetsAt is when the oldest counted action leaves the window
+	ResetsAt *time.Time `json:"resets_at,omitempty"`
+
+	Cooldowns []TargetCooldown `json:"cooldowns"`
+	Approvals []BudgetApproval `json:"approvals"`
+}
+
+// TargetCooldown is a resource that cannot be remediated again until Until
+type TargetCooldown struct {
+	Target     string    `json:"target"`
+	LastAction time.Time `json:"last_action"`
+	Until      time.Time `json:"until"`
+}
+
+// budgetAction is an admitted remediation
+type budgetAction struct {
+	namespace, target string
+	at                time.Time
+}
+
+// Budget enforces a BudgetPolicy. It counts the remediations it admitted; the
+// orchestrator seeds it from the workflow event log so the count survives restarts.
+type Budget struct {
+	policy    BudgetPolicy
+	actions   []budgetAction // oldest first
+	approvals map[string]*BudgetApproval
+	mu        sync.Mutex
+
+	// now is replaceable in tests
+	now func() time.Time
+}
+
+// NewBudget creates a budget enforcing policy
+func NewBudget(policy BudgetPolicy) *Budget {
+	return &Budget{
+		policy:    policy,
+		approvals: make(map[string]*BudgetApproval),
+		now:       time.Now,
+	}
+}
+
+// Policy returns the enforced policy
+func (b *Budget) Policy() BudgetPolicy {
+	return b.policy
+}
+
+// budgetTarget identifies an issue's resource within its namespace as "<kind>/<name>"
+func budgetTarget(issue *models.Issue) string {
+	return strings.ToLower(issue.ResourceType) + "/" + issue.ResourceName
+}
+
+// approverKey carries the approver of a remediation request in its context
+type approverKey struct{}
+
+// WithApprover marks a remediation as approved by a person, so it is admitted even if
+// it exceeds the budget
+func WithApprover(ctx context.Context, approvedBy string) context.Context {
+	return context.WithValue(ctx, approverKey{}, approvedBy)
+}
+
+// approver returns the approver set by WithApprover, or ""
+func approver(ctx context.Context) string {
+	approvedBy, _ := ctx.Value(approverKey{}).(string)
+	return approvedBy
+}
+
+// Admit records a remediation of the issue if the budget allows it. A remediation
+// exceeding the budget is admitted if the context carries an approver or a matching
+// budget approval has actions left, which uses one of them. It returns who approved
+// exceeding the budget, or "" if the remediation was within budget.
+func (b *Budget) Admit(ctx context.Context, issue *models.Issue) (string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	b.prune(now)
+	target := budgetTarget(issue)
+
+	override := ""
+	if exceeded := b.check(issue.Namespace, target, now); exceeded != nil {
+		switch approval := b.matchingApproval(issue.Namespace, target, now); {
+		case approver(ctx) != "":
+			override = approver(ctx)
+		case approval != nil:
+			approval.Actions--
+			override = approval.ApprovedBy
+			if approval.Actions == 0 {
+				delete(b.approvals, approval.ID)
+			}
+		default:
+			RecordBudgetRejection(exceeded.Reason)
+			return "", exceeded
+		}
+		RecordBudgetOverride(exceeded.Reason)
+	}
+
+	b.actions = append(b.actions, budgetAction{namespace: issue.Namespace, target: target, at: now})
+	return override, nil
+}
+
+// record counts a past remediation, e.g. one replayed from the event log
+func (b *Budget) record(issue *models.Issue, at time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	action := budgetAction{namespace: issue.Namespace, target: budgetTarget(issue), at: at}
+	i := sort.Search(len(b.actions), func(i int) bool { return b.actions[i].at.After(at) })
+	b.actions = append(b.actions, budgetAction{})
+	copy(b.actions[i+1:], b.actions[i:])
+	b.actions[i] = action
+}
+
+// check returns the budget error of a remediation, or nil if it is within budget.
+// Callers must hold b.mu.
+func (b *Budget) check(namespace, target string, now time.Time) *BudgetExceededError {
+	if b.policy.Cooldown > 0 {
+		for i := len(b.actions) - 1; i >= 0; i-- {
+			action := b.actions[i]
+			if action.namespace == namespace && action.target == target {
+				if until := action.at.Add(b.policy.Cooldown); now.Before(until) {
+					return &BudgetExceededError{
+						Reason:     BudgetReasonCooldown,
+						Namespace:  namespace,
+						Target:     namespace + "/" + target,
+						RetryAfter: until,
+					}
+				}
+				break
+			}
+		}
+	}
+
+	if b.policy.DailyLimit > 0 {
+		used, oldest := b.used(namespace, now)
+		if used >= b.policy.DailyLimit {
+			return &BudgetExceededError{
+				Reason:     BudgetReasonDailyLimit,
+				Namespace:  namespace,
+				Target:     namespace + "/" + target,
+				Limit:      b.policy.DailyLimit,
+				Used:       used,
+				RetryAfter: oldest.Add(BudgetWindow),
+			}
+		}
+	}
+	return nil
+}
+
+// used counts a namespace's actions in the window and returns the time of the oldest.
+// Callers must hold b.mu.
+func (b *Budget) used(namespace string, now time.Time) (int, time.Time) {
+	used, oldest := 0, time.Time{}
+	for _, action := range b.actions {
+		if action.namespace == namespace && now.Sub(action.at) < BudgetWindow {
+			if used == 0 {
+				oldest = action.at
+			}
+			used++
+		}
+	}
+	return used, oldest
+}
+
+// matchingApproval returns the unexpired approval for the target with actions left,
+// preferring one for the resource over one for the whole namespace. Callers must hold
+// b.mu.
+func (b *Budget) matchingApproval(namespace, target string, now time.Time) *BudgetApproval {
+	var match *BudgetApproval
+	for _, approval := range b.approvals {
+		if approval.Namespace != namespace || approval.Actions <= 0 || !now.Before(approval.ExpiresAt) {
+			continue
+		}
+		if approval.Target != "" && !strings.EqualFold(approval.Target, target) {
+			continue
+		}
+		if match == nil || (match.Target == "" && approval.Target != "") ||
+			(match.Target == approval.Target && approval.CreatedAt.Before(match.CreatedAt)) {
+			match = approval
+		}
+	}
+	return match
+}
+
+// prune drops actions that no longer count toward the limit or a cooldown, and
+// expired approvals. Callers must hold b.mu.
+func (b *Budget) prune(now time.Time) {
+	keep := BudgetWindow
+	if b.policy.Cooldown > keep {
+		keep = b.policy.Cooldown
+	}
+	i := 0
+	for i < len(b.actions) && now.Sub(b.actions[i].at) >= keep {
+		i++
+	}
+	b.actions = b.actions[i:]
+
+	for id, approval := range b.approvals {
+		if !now.Before(approval.ExpiresAt) {
+			delete(b.approvals, id)
+		}
+	}
+}
+
+// Approve grants a budget approval
+func (b *Budget) Approve(req *BudgetApprovalRequest) (*BudgetApproval, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	ttl := DefaultBudgetApprovalTTL
+	if req.TTL != "" {
+		ttl, _ = time.ParseDuration(req.TTL)
+	}
+	actions := req.Actions
+	if actions == 0 {
+		actions = 1
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	approval := &BudgetApproval{
+		ID:         "bap-" + uuid.New().String()[:8],
+		Namespace:  req.Namespace,
+		Target:     strings.ToLower(req.Target),
+		Actions:    actions,
+		ApprovedBy: req.ApprovedBy,
+		Reason:     req.Reason,
+		CreatedAt:  now,
+		ExpiresAt:  now.Add(ttl),
+	}
+	b.approvals[approval.ID] = approval
+	copied := *approval
+	return &copied, nil
+}
+
+// Revoke removes a budget approval and reports whether it existed
+func (b *Budget) Revoke(id string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	_, ok := b.approvals[id]
+	delete(b.approvals, id)
+	return ok
+}
+
+// Namespace returns the budget state of a namespace
+func (b *Budget) Namespace(namespace string) NamespaceBudget {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	b.prune(now)
+	return b.namespaceState(namespace, now)
+}
+
+// Namespaces returns the budget state of the namespaces with counted actions,
+// cooldowns or approvals, ordered by name
+func (b *Budget) Namespaces() []NamespaceBudget {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	b.prune(now)
+	seen := make(map[string]bool)
+	for _, action := range b.actions {
+		seen[action.namespace] = true
+	}
+	for _, approval := range b.approvals {
+		seen[approval.Namespace] = true
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	states := make([]NamespaceBudget, 0, len(names))
+	for _, name := range names {
+		states = append(states, b.namespaceState(name, now))
+	}
+	return states
+}
+
+// namespaceState builds the budget state of a namespace. Callers must hold b.mu.
+func (b *Budget) namespaceState(namespace string, now time.Time) NamespaceBudget {
+	state := NamespaceBudget{
+		Namespace: namespace,
+		Limit:     b.policy.DailyLimit,
+		Cooldowns: []TargetCooldown{},
+		Approvals: []BudgetApproval{},
+	}
+	var oldest time.Time
+	state.Used, oldest = b.used(namespace, now)
+	if state.Used > 0 {
+		resetsAt := oldest.Add(BudgetWindow)
+		state.ResetsAt = &resetsAt
+	}
+	if b.policy.DailyLimit > 0 && state.Used < b.policy.DailyLimit {
+		state.Remaining = b.policy.DailyLimit - state.Used
+	}
+
+	if b.policy.Cooldown > 0 {
+		last := make(map[string]time.Time)
+		for _, action := range b.actions {
+			if action.namespace == namespace {
+				last[action.target] = action.at
+			}
+		}
+		for target, at := range last {
+			if until := at.Add(b.policy.Cooldown); now.Before(until) {
+				state.Cooldowns = append(state.Cooldowns, TargetCooldown{Target: target, LastAction: at, Until: until})
+			}
+		}
+		sort.Slice(state.Cooldowns, func(i, j int) bool { return state.Cooldowns[i].Target < state.Cooldowns[j].Target })
+	}
+
+	for _, approval := range b.approvals {
+		if approval.Namespace == namespace {
+			state.Approvals = append(state.Approvals, *approval)
+		}
+	}
+	sort.Slice(state.Approvals, func(i, j int) bool { return state.Approvals[i].CreatedAt.Before(state.Approvals[j].CreatedAt) })
+	return state
+}
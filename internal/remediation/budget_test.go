@@ -0,0 +1,118 @@
+package remediation
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+func budgetIssue(namespace, name string) *models.Issue {
+	return &models.Issue{Namespace: namespace, ResourceType: "Pod", ResourceName: name}
+}
+
+func TestBudget_DailyLimitAndCooldown(t *testing.T) {
+	budget := NewBudget(BudgetPolicy{DailyLimit: 2, Cooldown: 30 * time.Minute})
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	budget.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	override, err := budget.Admit(ctx, budgetIssue("payments", "api-1"))
+	require.NoError(t, err)
+	assert.Empty(t, override)
+
+	// The same pod is cooling down
+	_, err = budget.Admit(ctx, budgetIssue("payments", "api-1"))
+	var exceeded *BudgetExceededError
+	require.True(t, errors.As(err, &exceeded))
+	assert.Equal(t, BudgetReasonCooldown, exceeded.Reason)
+	assert.Equal(t, now.Add(30*time.Minute), exceeded.RetryAfter)
+
+	_, err = budget.Admit(ctx, budgetIssue("payments", "api-2"))
+	require.NoError(t, err)
+
+	// The namespace used its daily limit; other namespaces are unaffected
+	_, err = budget.Admit(ctx, budgetIssue("payments", "api-3"))
+	require.True(t, errors.As(err, &exceeded))
+	assert.Equal(t, BudgetReasonDailyLimit, exceeded.Reason)
+	assert.Equal(t, 2, exceeded.Used)
+	assert.Equal(t, now.Add(BudgetWindow), exceeded.RetryAfter)
+	_, err = budget.Admit(ctx, budgetIssue("checkout", "api-1"))
+	require.NoError(t, err)
+
+	state := budget.Namespace("payments")
+	assert.Equal(t, 2, state.Used)
+	assert.Zero(t, state.Remaining)
+	require.Len(t, state.Cooldowns, 2)
+	assert.Equal(t, "pod/api-1", state.Cooldowns[0].Target)
+
+	// Cooldowns end, then the window moves past the counted actions
+	now = now.Add(time.Hour)
+	assert.Empty(t, budget.Namespace("payments").Cooldowns)
+	now = now.Add(BudgetWindow)
+	_, err = budget.Admit(ctx, budgetIssue("payments", "api-3"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, budget.Namespace("payments").Used)
+}
+
+func TestBudget_Approvals(t *testing.T) {
+	budget := NewBudget(BudgetPolicy{DailyLimit: 1})
+	ctx := context.Background()
+	_, err := budget.Admit(ctx, budgetIssue("payments", "api-1"))
+	require.NoError(t, err)
+
+	_, err = budget.Approve(&BudgetApprovalRequest{Namespace: "payments", Target: "pod", Actions: 101})
+	require.Error(t, err)
+
+	approval, err := budget.Approve(&BudgetApprovalRequest{Namespace: "payments", Target: "Pod/api-2", ApprovedBy: "alice"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, approval.Actions)
+	assert.Equal(t, "pod/api-2", approval.Target)
+
+	// The approval only covers its resource and is used up by one remediation
+	_, err = budget.Admit(ctx, budgetIssue("payments", "api-3"))
+	require.Error(t, err)
+	override, err := budget.Admit(ctx, budgetIssue("payments", "api-2"))
+	require.NoError(t, err)
+	assert.Equal(t, "alice", override)
+	assert.Empty(t, budget.Namespace("payments").Approvals)
+
+	// An approver on the context overrides the budget
+	override, err = budget.Admit(WithApprover(ctx, "bob"), budgetIssue("payments", "api-3"))
+	require.NoError(t, err)
+	assert.Equal(t, "bob", override)
+	assert.Equal(t, 3, budget.Namespace("payments").Used)
+
+	approval, err = budget.Approve(&BudgetApprovalRequest{Namespace: "payments", ApprovedBy: "alice", TTL: "1h"})
+	require.NoError(t, err)
+	assert.True(t, budget.Revoke(approval.ID))
+	assert.False(t, budget.Revoke(approval.ID))
+}
+
+func TestOrchestrator_SetBudget_SeedsFromEventLog(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	events := NewEventLog(t.TempDir())
+	_, err := events.Append(WorkflowEvent{
+		WorkflowID: "wf-1",
+		Type:       EventWorkflowCreated,
+		Time:       time.Now().Add(-time.Hour),
+		Issue:      budgetIssue("payments", "api-1"),
+	})
+	require.NoError(t, err)
+
+	orchestrator := NewOrchestrator(nil, nil, log)
+	orchestrator.SetEventLog(events)
+	orchestrator.SetBudget(NewBudget(BudgetPolicy{DailyLimit: 1}))
+
+	assert.Equal(t, 1, orchestrator.Budget().Namespace("payments").Used)
+	_, err = orchestrator.Admit(context.Background(), budgetIssue("payments", "api-2"))
+	var exceeded *BudgetExceededError
+	assert.True(t, errors.As(err, &exceeded))
+}
@@ -0,0 +1,348 @@
+package remediation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// RetryPolicy controls how often a failing workflow step is attempted
+type RetryPolicy struct {
+	// MaxAttempts is the number of attempts, including the first; values below 1 mean 1
+	MaxAttempts int
+
+	// Backoff is the delay before the first retry. It doubles after each further
+	// failure, up to MaxBackoff if that is set.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+}
+
+// attempts returns the number of attempts the policy allows
+func (p RetryPolicy) attempts() int {
+	if p.MaxAttempts < 1 {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+// delay returns the delay before the attempt following the failed attempt
+func (p RetryPolicy) delay(failedAttempt int) time.Duration {
+	delay := p.Backoff
+	for i := 1; i < failedAttempt; i++ {
+		delay *= 2
+		if p.MaxBackoff > 0 && delay >= p.MaxBackoff {
+			break
+		}
+	}
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+	return delay
+}
+
+// stepDefinition describes how a workflow step runs
+type stepDefinition struct {
+	name        string
+	description string
+	retry       RetryPolicy
+
+	// when decides whether the step runs; if it returns false the step is skipped for
+	// the returned reason. Nil means the step always runs.
+	when func(r *workflowRun) (bool, string)
+
+	run func(ctx context.Context, r *workflowRun) error
+
+	// compensate undoes the step after a later step failed. Nil means the step needs
+	// no compensation.
+	compensate func(ctx context.Context, r *workflowRun) error
+}
+
+// ErrCompensationUnsupported is returned by compensations that cannot undo a step
+var ErrCompensationUnsupported = errors.New("compensation not supported")
+
+// workflowRun is a workflow's state, derived from its events
+type workflowRun struct {
+	workflow   models.Workflow
+	issue      *models.Issue
+	deployment *models.DeploymentInfo
+}
+
+// replay derives a workflow's state from its events
+func replay(events []WorkflowEvent) *workflowRun {
+	r := &workflowRun{}
+	for i := range events {
+		r.apply(&events[i])
+	}
+	return r
+}
+
+// apply updates the state with an event. It is the only place workflow state changes.
+func (r *workflowRun) apply(e *WorkflowEvent) {
+	wf := &r.workflow
+	at := e.Time
+
+	switch e.Type {
+	case EventWorkflowCreated:
+		r.issue = e.Issue
+		*wf = models.Workflow{
+			ID:         e.WorkflowID,
+			IncidentID: e.IncidentID,
+			Status:     models.WorkflowStatusPending,
+			Remediator: e.Remediator,
+			CreatedAt:  at,
+			Steps:      make([]models.WorkflowStep, 0, len(e.Steps)),
+		}
+		if e.Issue != nil {
+			wf.Namespace = e.Issue.Namespace
+			wf.ResourceName = e.Issue.ResourceName
+			wf.ResourceKind = e.Issue.ResourceType
+			wf.IssueType = e.Issue.Type
+		}
+		for i, planned := range e.Steps {
+			wf.Steps = append(wf.Steps, models.WorkflowStep{
+				Order:       i,
+				Name:        planned.Name,
+				Description: planned.Description,
+				Status:      models.StepStatusPending,
+			})
+		}
+	case EventWorkflowStarted:
+		wf.Status = models.WorkflowStatusRunning
+		wf.StartedAt = &at
+	case EventWorkflowCompleted:
+		wf.Status = models.WorkflowStatusCompleted
+		wf.CompletedAt = &at
+	case EventWorkflowFailed:
+		wf.Status = models.WorkflowStatusFailed
+		wf.ErrorMessage = e.Error
+		wf.CompletedAt = &at
+	}
+
+	step := r.step(e.Step)
+	if step == nil {
+		return
+	}
+	switch e.Type {
+	case EventStepStarted:
+		step.Status = models.StepStatusRunning
+		step.Attempts = e.Attempt
+		step.StartedAt = &at
+		step.NextAttemptAt = nil
+	case EventStepSucceeded:
+		step.Status = models.StepStatusCompleted
+		step.CompletedAt = &at
+		step.ErrorMessage = ""
+		if e.DeploymentInfo != nil {
+			r.deployment = e.DeploymentInfo
+			wf.DeploymentMethod = string(e.DeploymentInfo.Method)
+		}
+	case EventStepFailed:
+		step.ErrorMessage = e.Error
+		if e.RetryAt != nil {
+			step.Status = models.StepStatusRetrying
+			step.NextAttemptAt = e.RetryAt
+		} else {
+			step.Status = models.StepStatusFailed
+			step.CompletedAt = &at
+		}
+	case EventStepSkipped:
+		step.Status = models.StepStatusSkipped
+		step.Reason = e.Reason
+	case EventStepCompensated:
+		step.Status = models.StepStatusCompensated
+	case EventStepCompensationFailed:
+		step.Status = models.StepStatusCompensationFailed
+		step.ErrorMessage = e.Error
+	case EventStepCompensationSkipped:
+		step.Reason = e.Reason
+	}
+}
+
+// step returns the named step, or nil
+func (r *workflowRun) step(name string) *models.WorkflowStep {
+	if name == "" {
+		return nil
+	}
+	for i := range r.workflow.Steps {
+		if r.workflow.Steps[i].Name == name {
+			return &r.workflow.Steps[i]
+		}
+	}
+	return nil
+}
+
+// snapshot returns a copy of the workflow that later events do not modify
+func (r *workflowRun) snapshot() *models.Workflow {
+	wf := r.workflow
+	wf.Steps = append([]models.WorkflowStep(nil), r.workflow.Steps...)
+	return &wf
+}
+
+// engine executes workflows step by step, recording every transition as an event
+type engine struct {
+	// record appends an event to the log and applies it to the run
+	record func(r *workflowRun, event WorkflowEvent)
+	now    func() time.Time
+	sleep  func(ctx context.Context, d time.Duration) error
+	log    *logrus.Logger
+}
+
+// execute runs the steps of a workflow that have not finished yet, in order. If a step
+// fails for good, the completed steps are compensated in reverse order and the
+// workflow fails. It returns false if ctx was cancelled, leaving the workflow to be
+// resumed from its events.
+func (e *engine) execute(ctx context.Context, r *workflowRun, defs map[string]*stepDefinition) bool {
+	if r.workflow.Status == models.WorkflowStatusPending {
+		e.record(r, WorkflowEvent{Type: EventWorkflowStarted})
+	}
+
+	for i := range r.workflow.Steps {
+		name := r.workflow.Steps[i].Name
+		def := defs[name]
+		if def == nil {
+			e.fail(ctx, r, defs, i, fmt.Errorf("workflow step %s is not defined", name))
+			return true
+		}
+
+		switch err := e.runStep(ctx, r, def); {
+		case err == nil:
+			continue
+		case ctx.Err() != nil:
+			return false
+		default:
+			e.fail(ctx, r, defs, i, fmt.Errorf("step %s failed: %w", name, err))
+			return true
+		}
+	}
+
+	e.record(r, WorkflowEvent{Type: EventWorkflowCompleted})
+	return true
+}
+
+// runStep runs a step until it succeeds or its retry policy is exhausted. Steps that
+// already finished are not run again. A step that was running or waiting for a retry
+// when the engine stopped continues with its next attempt.
+func (e *engine) runStep(ctx context.Context, r *workflowRun, def *stepDefinition) error {
+	step := r.step(def.name)
+	switch step.Status {
+	case models.StepStatusCompleted, models.StepStatusSkipped:
+		return nil
+	case models.StepStatusFailed:
+		return errors.New(step.ErrorMessage)
+	case models.StepStatusRunning:
+		if err := e.failAttempt(r, def, step.Attempts, errStepInterrupted); err != nil {
+			return err
+		}
+	case models.StepStatusPending:
+		if def.when != nil {
+			if ok, reason := def.when(r); !ok {
+				e.record(r, WorkflowEvent{Type: EventStepSkipped, Step: def.name, Reason: reason})
+				return nil
+			}
+		}
+	}
+
+	for {
+		if next := step.NextAttemptAt; next != nil {
+			if err := e.sleep(ctx, next.Sub(e.now())); err != nil {
+				return err
+			}
+		}
+
+		attempt := step.Attempts + 1
+		e.record(r, WorkflowEvent{Type: EventStepStarted, Step: def.name, Attempt: attempt})
+		start := e.now()
+		err := def.run(ctx, r)
+		if ctx.Err() != nil {
+			// Shutting down: the attempt is retried when the workflow resumes
+			return ctx.Err()
+		}
+		duration := e.now().Sub(start).Seconds()
+		if err == nil {
+			RecordWorkflowStep(def.name, "success", duration)
+			event := WorkflowEvent{Type: EventStepSucceeded, Step: def.name, Attempt: attempt}
+			if def.name == stepDetectDeployment {
+				event.DeploymentInfo = r.deployment
+			}
+			e.record(r, event)
+			return nil
+		}
+		RecordWorkflowStep(def.name, "failure", duration)
+		if err := e.failAttempt(r, def, attempt, err); err != nil {
+			return err
+		}
+	}
+}
+
+// errStepInterrupted is the error of attempts cut short by an engine restart
+var errStepInterrupted = errors.New("attempt interrupted by engine restart")
+
+// failAttempt records a failed attempt. It returns err if the retry policy is
+// exhausted, and nil if the step will be retried.
+func (e *engine) failAttempt(r *workflowRun, def *stepDefinition, attempt int, err error) error {
+	event := WorkflowEvent{Type: EventStepFailed, Step: def.name, Attempt: attempt, Error: err.Error()}
+	if attempt < def.retry.attempts() {
+		retryAt := e.now().Add(def.retry.delay(attempt))
+		event.RetryAt = &retryAt
+	}
+	e.record(r, event)
+
+	e.log.WithError(err).WithFields(logrus.Fields{
+		"workflow_id": r.workflow.ID,
+		"step":        def.name,
+		"attempt":     attempt,
+		"will_retry":  event.RetryAt != nil,
+	}).Warn("Workflow step failed")
+	if event.RetryAt == nil {
+		return err
+	}
+	return nil
+}
+
+// fail compensates the steps completed before the failed step, in reverse order, and
+// fails the workflow
+func (e *engine) fail(ctx context.Context, r *workflowRun, defs map[string]*stepDefinition, failed int, cause error) {
+	var completed []string
+	for _, step := range r.workflow.Steps[:failed] {
+		if step.Status == models.StepStatusCompleted {
+			completed = append(completed, step.Name)
+		}
+	}
+
+	if len(completed) > 0 {
+		e.record(r, WorkflowEvent{Type: EventCompensationStarted, Reason: cause.Error()})
+	}
+	for i := len(completed) - 1; i >= 0; i-- {
+		if def := defs[completed[i]]; def != nil && def.compensate != nil {
+			e.compensate(ctx, r, def)
+		}
+	}
+
+	e.record(r, WorkflowEvent{Type: EventWorkflowFailed, Error: cause.Error()})
+}
+
+// compensate undoes a completed step
+func (e *engine) compensate(ctx context.Context, r *workflowRun, def *stepDefinition) {
+	name := def.name
+	err := def.compensate(ctx, r)
+	switch {
+	case err == nil:
+		RecordCompensation(name, "success")
+		e.record(r, WorkflowEvent{Type: EventStepCompensated, Step: name})
+	case errors.Is(err, ErrCompensationUnsupported):
+		RecordCompensation(name, "unsupported")
+		e.record(r, WorkflowEvent{Type: EventStepCompensationSkipped, Step: name, Reason: err.Error()})
+	default:
+		RecordCompensation(name, "failure")
+		e.log.WithError(err).WithFields(logrus.Fields{
+			"workflow_id": r.workflow.ID,
+			"step":        name,
+		}).Error("Workflow step compensation failed")
+		e.record(r, WorkflowEvent{Type: EventStepCompensationFailed, Step: name, Error: err.Error()})
+	}
+}
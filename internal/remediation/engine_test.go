@@ -0,0 +1,232 @@
+package remediation
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 5, Backoff: 10 * time.Second, MaxBackoff: 30 * time.Second}
+	assert.Equal(t, 10*time.Second, policy.delay(1))
+	assert.Equal(t, 20*time.Second, policy.delay(2))
+	assert.Equal(t, 30*time.Second, policy.delay(3))
+	assert.Equal(t, 30*time.Second, policy.delay(10))
+	assert.Equal(t, 1, RetryPolicy{}.attempts())
+}
+
+// testEngine records events in log and never sleeps; slept collects the waits
+func testEngine(log *EventLog, slept *[]time.Duration) *engine {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	return &engine{
+		record: func(r *workflowRun, event WorkflowEvent) {
+			if event.WorkflowID == "" {
+				event.WorkflowID = r.workflow.ID
+			}
+			event.Time = now
+			appended, err := log.Append(event)
+			if err != nil {
+				panic(err)
+			}
+			r.apply(&appended)
+		},
+		now: func() time.Time { return now },
+		sleep: func(ctx context.Context, d time.Duration) error {
+			*slept = append(*slept, d)
+			return ctx.Err()
+		},
+		log: logger,
+	}
+}
+
+func createRun(e *engine, steps ...string) *workflowRun {
+	plan := make([]PlannedStep, 0, len(steps))
+	for _, name := range steps {
+		plan = append(plan, PlannedStep{Name: name, Description: name})
+	}
+	r := &workflowRun{}
+	e.record(r, WorkflowEvent{
+		WorkflowID: "wf-test",
+		Type:       EventWorkflowCreated,
+		Issue:      &models.Issue{ID: "issue-1", Type: "pod_crash_loop", Namespace: "payments", ResourceName: "api", ResourceType: "deployment"},
+		Steps:      plan,
+	})
+	return r
+}
+
+func eventTypes(events []WorkflowEvent) []string {
+	types := make([]string, 0, len(events))
+	for _, event := range events {
+		types = append(types, string(event.Type)+":"+event.Step)
+	}
+	return types
+}
+
+func TestEngine_RetriesAndCompletes(t *testing.T) {
+	log := newMemoryEventLog()
+	var slept []time.Duration
+	e := testEngine(log, &slept)
+
+	calls := 0
+	defs := map[string]*stepDefinition{
+		"remediate": {
+			name:  "remediate",
+			retry: RetryPolicy{MaxAttempts: 3, Backoff: time.Second},
+			run: func(context.Context, *workflowRun) error {
+				calls++
+				if calls < 3 {
+					return errors.New("connection refused")
+				}
+				return nil
+			},
+		},
+		"verify": {
+			name: "verify",
+			when: func(*workflowRun) (bool, string) { return false, "nothing to verify" },
+			run:  func(context.Context, *workflowRun) error { t.Fatal("skipped step ran"); return nil },
+		},
+	}
+
+	r := createRun(e, "remediate", "verify")
+	require.True(t, e.execute(context.Background(), r, defs))
+
+	assert.Equal(t, models.WorkflowStatusCompleted, r.workflow.Status)
+	assert.Equal(t, 3, r.workflow.Steps[0].Attempts)
+	assert.Equal(t, models.StepStatusCompleted, r.workflow.Steps[0].Status)
+	assert.Equal(t, models.StepStatusSkipped, r.workflow.Steps[1].Status)
+	assert.Equal(t, "nothing to verify", r.workflow.Steps[1].Reason)
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second}, slept)
+
+	events := log.Events("wf-test")
+	assert.Equal(t, []string{
+		"workflow_created:", "workflow_started:",
+		"step_started:remediate", "step_failed:remediate",
+		"step_started:remediate", "step_failed:remediate",
+		"step_started:remediate", "step_succeeded:remediate",
+		"step_skipped:verify", "workflow_completed:",
+	}, eventTypes(events))
+	assert.NotNil(t, events[3].RetryAt)
+
+	// The state is derived from the events alone
+	assert.Equal(t, r.snapshot(), replay(events).snapshot())
+}
+
+func TestEngine_CompensatesOnFailure(t *testing.T) {
+	log := newMemoryEventLog()
+	var slept []time.Duration
+	e := testEngine(log, &slept)
+
+	var compensated []string
+	defs := map[string]*stepDefinition{
+		"scale": {
+			name:       "scale",
+			run:        func(context.Context, *workflowRun) error { return nil },
+			compensate: func(context.Context, *workflowRun) error { compensated = append(compensated, "scale"); return nil },
+		},
+		"restart": {
+			name:       "restart",
+			run:        func(context.Context, *workflowRun) error { return nil },
+			compensate: func(context.Context, *workflowRun) error { return ErrCompensationUnsupported },
+		},
+		"verify": {
+			name:  "verify",
+			retry: RetryPolicy{MaxAttempts: 2},
+			run:   func(context.Context, *workflowRun) error { return errors.New("0 of 3 replicas ready") },
+		},
+	}
+
+	r := createRun(e, "scale", "restart", "verify")
+	require.True(t, e.execute(context.Background(), r, defs))
+
+	assert.Equal(t, models.WorkflowStatusFailed, r.workflow.Status)
+	assert.Equal(t, "step verify failed: 0 of 3 replicas ready", r.workflow.ErrorMessage)
+	assert.Equal(t, []string{"scale"}, compensated)
+	assert.Equal(t, models.StepStatusCompensated, r.workflow.Steps[0].Status)
+	assert.Equal(t, models.StepStatusCompleted, r.workflow.Steps[1].Status, "steps that cannot be undone stay completed")
+	assert.Equal(t, models.StepStatusFailed, r.workflow.Steps[2].Status)
+	assert.Equal(t, 2, r.workflow.Steps[2].Attempts)
+
+	types := eventTypes(log.Events("wf-test"))
+	assert.Equal(t, []string{
+		"compensation_started:", "step_compensation_skipped:restart", "step_compensated:scale", "workflow_failed:",
+	}, types[len(types)-4:])
+}
+
+func TestEngine_ResumesInterruptedStep(t *testing.T) {
+	log := newMemoryEventLog()
+	var slept []time.Duration
+	e := testEngine(log, &slept)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defs := map[string]*stepDefinition{
+		"detect": {name: "detect", run: func(context.Context, *workflowRun) error { return nil }},
+		"remediate": {
+			name:  "remediate",
+			retry: RetryPolicy{MaxAttempts: 2},
+			run: func(ctx context.Context, _ *workflowRun) error {
+				cancel() // the engine shuts down mid-attempt
+				return ctx.Err()
+			},
+		},
+	}
+
+	r := createRun(e, "detect", "remediate")
+	require.False(t, e.execute(ctx, r, defs))
+	assert.Equal(t, models.StepStatusRunning, r.workflow.Steps[1].Status)
+
+	// After a restart the workflow is rebuilt from its events and the interrupted
+	// attempt is retried; the completed step does not run again
+	resumed := replay(log.Events("wf-test"))
+	defs["detect"].run = func(context.Context, *workflowRun) error { t.Fatal("completed step ran"); return nil }
+	defs["remediate"].run = func(context.Context, *workflowRun) error { return nil }
+	require.True(t, e.execute(context.Background(), resumed, defs))
+
+	assert.Equal(t, models.WorkflowStatusCompleted, resumed.workflow.Status)
+	assert.Equal(t, 2, resumed.workflow.Steps[1].Attempts)
+	failed := log.Events("wf-test")[5]
+	assert.Equal(t, EventStepFailed, failed.Type)
+	assert.Equal(t, errStepInterrupted.Error(), failed.Error)
+}
+
+func TestEventLog_Persistence(t *testing.T) {
+	dir := t.TempDir()
+	log := NewEventLog(dir)
+	for _, id := range []string{"wf-1", "wf-2"} {
+		_, err := log.Append(WorkflowEvent{WorkflowID: id, Type: EventWorkflowCreated})
+		require.NoError(t, err)
+	}
+	_, err := log.Append(WorkflowEvent{WorkflowID: "wf-1", Type: EventWorkflowCompleted})
+	require.NoError(t, err)
+
+	// A line cut short by a crash is skipped
+	file, err := os.OpenFile(filepath.Join(dir, "workflow_events.jsonl"), os.O_APPEND|os.O_WRONLY, 0o644)
+	require.NoError(t, err)
+	_, err = file.WriteString(`{"sequence":4,"workflow_id":"wf-2","ty`)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	reloaded := NewEventLog(dir)
+	assert.Equal(t, []string{"wf-1", "wf-2"}, reloaded.WorkflowIDs())
+	assert.Len(t, reloaded.Events("wf-1"), 2)
+	event, err := reloaded.Append(WorkflowEvent{WorkflowID: "wf-2", Type: EventWorkflowStarted})
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), event.Sequence, "sequence numbers continue after a restart")
+	assert.Nil(t, reloaded.Events("wf-3"))
+
+	// Finished workflows are dropped first once the limit is reached
+	reloaded.limit = 1
+	_, err = reloaded.Append(WorkflowEvent{WorkflowID: "wf-3", Type: EventWorkflowCreated})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"wf-2", "wf-3"}, reloaded.WorkflowIDs())
+}
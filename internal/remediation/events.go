@@ -0,0 +1,271 @@
+package remediation
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// DefaultMaxWorkflows is the number of workflows whose events are kept; the events of
+// the oldest finished workflows are dropped first
+const DefaultMaxWorkflows = 1000
+
+// EventType identifies what happened to a workflow
+type EventType string
+
+// Workflow event types
+const (
+	EventWorkflowCreated   EventType = "workflow_created"
+	EventWorkflowStarted   EventType = "workflow_started"
+	EventWorkflowRecovered EventType = "workflow_recovered"
+	EventWorkflowCompleted EventType = "workflow_completed"
+	EventWorkflowFailed    EventType = "workflow_failed"
+
+	EventStepStarted   EventType = "step_started"
+	EventStepSucceeded EventType = "step_succeeded"
+	EventStepFailed    EventType = "step_failed"
+	EventStepSkipped   EventType = "step_skipped"
+
+	EventCompensationStarted     EventType = "compensation_started"
+	EventStepCompensated         EventType = "step_compensated"
+	EventStepCompensationFailed  EventType = "step_compensation_failed"
+	EventStepCompensationSkipped EventType = "step_compensation_skipped"
+)
+
+// WorkflowEvent is an entry in a workflow's event log. A workflow's state is derived
+// entirely from its events.
+type WorkflowEvent struct {
+	Sequence   int64     `json:"sequence"`
+	WorkflowID string    `json:"workflow_id"`
+	Type       EventType `json:"type"`
+	Time       time.Time `json:"time"`
+
+	Step    string `json:"step,omitempty"`
+	Attempt int    `json:"attempt,omitempty"`
+	Error   string `json:"error,omitempty"`
+	Reason  string `json:"reason,omitempty"`
+
+	// RetryAt is set on step_failed events when the step will be retried
+	RetryAt *time.Time `json:"retry_at,omitempty"`
+
+	// IncidentID, Issue, Remediator and Steps are set on workflow_created events
+	IncidentID string        `json:"incident_id,omitempty"`
+	Issue      *models.Issue `json:"issue,omitempty"`
+	Remediator string        `json:"remediator,omitempty"`
+	Steps      []PlannedStep `json:"steps,omitempty"`
+
+	// BudgetOverride names who approved exceeding the remediation budget; it is set
+	// on workflow_created events of remediations admitted over budget
+	BudgetOverride string `json:"budget_override,omitempty"`
+
+	// DeploymentInfo is set when the deployment detection step succeeds
+	DeploymentInfo *models.DeploymentInfo `json:"deployment_info,omitempty"`
+}
+
+// PlannedStep is a step a workflow was created with
+type PlannedStep struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// terminal reports whether the event ends a workflow
+func (e *WorkflowEvent) terminal() bool {
+	return e.Type == EventWorkflowCompleted || e.Type == EventWorkflowFailed
+}
+
+// EventLog is an append-only log of workflow events, persisted as JSON lines in
+// workflow_events.jsonl
+type EventLog struct {
+	events   map[string][]WorkflowEvent
+	order    []string // workflow IDs in creation order
+	sequence int64
+	limit    int
+	mu       sync.RWMutex
+	dataFile string
+}
+
+// NewEventLog creates an event log in dataDir (DATA_DIR or /app/data if empty) and
+// loads the events recorded before a restart
+func NewEventLog(dataDir string) *EventLog {
+	if dataDir == "" {
+		dataDir = os.Getenv("DATA_DIR")
+	}
+	if dataDir == "" {
+		dataDir = "/app/data"
+	}
+
+	if err := os.MkdirAll(dataDir, 0o755); err != nil {
+		fmt.Printf("Warning: Could not create data directory %s: %v\n", dataDir, err)
+	}
+	log := newMemoryEventLog()
+	log.dataFile = filepath.Join(dataDir, "workflow_events.jsonl")
+
+	if err := log.load(); err != nil {
+		fmt.Printf("Warning: Could not load workflow events from disk: %v\n", err)
+	} else {
+		fmt.Printf("Loaded events of %d workflows from %s\n", len(log.order), log.dataFile)
+	}
+
+	return log
+}
+
+// newMemoryEventLog creates an event log that is not persisted
+func newMemoryEventLog() *EventLog {
+	return &EventLog{
+		events: make(map[string][]WorkflowEvent),
+		limit:  DefaultMaxWorkflows,
+	}
+}
+
+// load reads the events from the data file. Malformed lines, e.g. one cut short by a
+// crash, are skipped. If workflows were dropped the file is rewritten.
+func (l *EventLog) load() error {
+	data, err := os.ReadFile(l.dataFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read data file: %w", err)
+	}
+
+	skipped := 0
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var event WorkflowEvent
+		if err := json.Unmarshal(line, &event); err != nil || event.WorkflowID == "" {
+			skipped++
+			continue
+		}
+		l.add(event)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read workflow events: %w", err)
+	}
+	if skipped > 0 {
+		fmt.Printf("Warning: Skipped %d malformed workflow events in %s\n", skipped, l.dataFile)
+	}
+
+	if l.prune() || skipped > 0 {
+		return l.rewrite()
+	}
+	return nil
+}
+
+// add stores an event in memory. Callers must hold l.mu.
+func (l *EventLog) add(event WorkflowEvent) {
+	if _, exists := l.events[event.WorkflowID]; !exists {
+		l.order = append(l.order, event.WorkflowID)
+	}
+	l.events[event.WorkflowID] = append(l.events[event.WorkflowID], event)
+	if event.Sequence > l.sequence {
+		l.sequence = event.Sequence
+	}
+}
+
+// prune drops the events of the oldest finished workflows beyond the limit and reports
+// whether any were dropped. Callers must hold l.mu.
+func (l *EventLog) prune() bool {
+	excess := len(l.order) - l.limit
+	if excess <= 0 {
+		return false
+	}
+	kept := l.order[:0]
+	for _, id := range l.order {
+		events := l.events[id]
+		if excess > 0 && events[len(events)-1].terminal() {
+			delete(l.events, id)
+			excess--
+			continue
+		}
+		kept = append(kept, id)
+	}
+	pruned := len(kept) < len(l.order)
+	l.order = kept
+	return pruned
+}
+
+// rewrite replaces the data file with the events in memory. Callers must hold l.mu.
+func (l *EventLog) rewrite() error {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, id := range l.order {
+		for i := range l.events[id] {
+			if err := encoder.Encode(&l.events[id][i]); err != nil {
+				return fmt.Errorf("failed to marshal workflow event: %w", err)
+			}
+		}
+	}
+
+	// Write to temp file first, then rename (atomic)
+	tmpFile := l.dataFile + ".tmp"
+	if err := os.WriteFile(tmpFile, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed to write temp file: %w", err)
+	}
+	if err := os.Rename(tmpFile, l.dataFile); err != nil {
+		return fmt.Errorf("failed to rename temp file: %w", err)
+	}
+	return nil
+}
+
+// Append assigns the event its sequence number (and time, if unset) and records it.
+// The event is kept in memory even if it cannot be persisted.
+func (l *EventLog) Append(event WorkflowEvent) (WorkflowEvent, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	event.Sequence = l.sequence + 1
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	l.add(event)
+	l.prune()
+
+	if l.dataFile == "" {
+		return event, nil
+	}
+	line, err := json.Marshal(&event)
+	if err != nil {
+		return event, fmt.Errorf("failed to marshal workflow event: %w", err)
+	}
+	file, err := os.OpenFile(l.dataFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return event, fmt.Errorf("failed to open data file: %w", err)
+	}
+	defer file.Close()
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return event, fmt.Errorf("failed to append workflow event: %w", err)
+	}
+	return event, nil
+}
+
+// Events returns a workflow's events in order, or nil if it is unknown
+func (l *EventLog) Events(workflowID string) []WorkflowEvent {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	events := l.events[workflowID]
+	if events == nil {
+		return nil
+	}
+	return append([]WorkflowEvent(nil), events...)
+}
+
+// WorkflowIDs returns the IDs of the logged workflows in creation order
+func (l *EventLog) WorkflowIDs() []string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return append([]string(nil), l.order...)
+}
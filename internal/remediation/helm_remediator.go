@@ -112,6 +112,26 @@ func (hr *HelmRemediator) Remediate(ctx context.Context, deploymentInfo *models.
 	return nil
 }
 
+// Compensate rolls the release back to the revision before the remediation. This
+// undoes both the upgrade and the rollback Remediate may have performed.
+func (hr *HelmRemediator) Compensate(ctx context.Context, deploymentInfo *models.DeploymentInfo, issue *models.Issue) error {
+	releaseName := deploymentInfo.GetDetail("release_name")
+	if releaseName == "" {
+		return fmt.Errorf("helm release name not found in deployment info")
+	}
+	releaseNamespace := deploymentInfo.GetDetail("release_namespace")
+	if releaseNamespace == "" {
+		releaseNamespace = deploymentInfo.Namespace
+	}
+
+	hr.log.WithFields(logrus.Fields{
+		"release":    releaseName,
+		"namespace":  releaseNamespace,
+		"issue_type": issue.Type,
+	}).Warn("Rolling back Helm remediation")
+	return hr.rollbackRelease(ctx, releaseName, releaseNamespace)
+}
+
 // CanRemediate returns true if deployment is Helm-managed
 func (hr *HelmRemediator) CanRemediate(deploymentInfo *models.DeploymentInfo) bool {
 	return deploymentInfo.Method == models.DeploymentMethodHelm || deploymentInfo.IsHelmManaged()
@@ -18,6 +18,14 @@ type Remediator interface {
 	Name() string
 }
 
+// Compensator is implemented by remediators that can undo a remediation, e.g. when
+// the workload does not recover afterwards
+type Compensator interface {
+	// Compensate reverts the changes Remediate made. It returns
+	// ErrCompensationUnsupported if the remediation cannot be undone.
+	Compensate(ctx context.Context, deploymentInfo *models.DeploymentInfo, issue *models.Issue) error
+}
+
 // RemediationResult contains the outcome of remediation
 //
 //nolint:revive // intentional naming for clarity in external package usage
@@ -88,6 +88,25 @@ var (
 		[]string{"step_type", "status"},
 	)
 
+	// WorkflowCompensations counts compensations of workflow steps by outcome
+	WorkflowCompensations = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "coordination_engine_workflow_compensations_total",
+			Help: "Total number of workflow step compensations after a later step failed",
+		},
+		[]string{"step_type", "status"},
+	)
+
+	// BudgetDecisions counts remediations that exceeded their budget, by reason and
+	// whether they were rejected or admitted by an approval
+	BudgetDecisions = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "coordination_engine_remediation_budget_exceeded_total",
+			Help: "Total number of remediations exceeding a namespace limit or resource cooldown",
+		},
+		[]string{"reason", "decision"},
+	)
+
 	// RemediatorHealthScore tracks health/availability of each remediator
 	RemediatorHealthScore = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
@@ -147,6 +166,21 @@ func RecordWorkflowStep(stepType, status string, duration float64) {
 	WorkflowStepDuration.WithLabelValues(stepType, status).Observe(duration)
 }
 
+// RecordCompensation records the compensation of a workflow step
+func RecordCompensation(stepType, status string) {
+	WorkflowCompensations.WithLabelValues(stepType, status).Inc()
+}
+
+// RecordBudgetRejection records a remediation rejected for exceeding its budget
+func RecordBudgetRejection(reason string) {
+	BudgetDecisions.WithLabelValues(reason, "rejected").Inc()
+}
+
+// RecordBudgetOverride records a remediation admitted over budget by an approval
+func RecordBudgetOverride(reason string) {
+	BudgetDecisions.WithLabelValues(reason, "approved").Inc()
+}
+
 // UpdateRemediatorHealth updates the health score for a remediator
 func UpdateRemediatorHealth(remediator string, healthScore float64) {
 	RemediatorHealthScore.WithLabelValues(remediator).Set(healthScore)
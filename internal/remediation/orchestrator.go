@@ -13,30 +13,134 @@ import (
 	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
 )
 
-// Orchestrator manages remediation workflow execution
+// Workflow step names
+const (
+	stepDetectDeployment = "detect_deployment"
+	stepRemediate        = "remediate"
+	stepVerify           = "verify"
+)
+
+// Orchestrator manages remediation workflow execution. Workflows are event sourced:
+// every step transition is appended to an event log, and a workflow's state is derived
+// from its events, so workflows interrupted by a restart can be resumed.
 type Orchestrator struct {
 	detector   *detector.Detector
 	remediator Remediator
-	workflows  map[string]*models.Workflow
-	mu         sync.RWMutex
-	log        *logrus.Logger
+	events     *EventLog
+	engine     *engine
+
+	retry        RetryPolicy
+	verifier     Verifier
+	verifyPolicy RetryPolicy
+	budget       *Budget
+	listener     func(*models.Workflow, WorkflowEvent)
+
+	workflows map[string]*models.Workflow // projections of the event log
+	mu        sync.RWMutex
+	log       *logrus.Logger
 }
 
-// NewOrchestrator creates a new remediation orchestrator
+// NewOrchestrator creates a new remediation orchestrator. Its events are kept in
+// memory until SetEventLog is called.
 func NewOrchestrator(
 	det *detector.Detector,
 	remediator Remediator,
 	log *logrus.Logger,
 ) *Orchestrator {
-	return &Orchestrator{
+	o := &Orchestrator{
 		detector:   det,
 		remediator: remediator,
+		events:     newMemoryEventLog(),
 		workflows:  make(map[string]*models.Workflow),
 		log:        log,
 	}
+	o.engine = &engine{
+		record: o.record,
+		now:    time.Now,
+		sleep:  sleepContext,
+		log:    log,
+	}
+	return o
+}
+
+// SetEventLog sets the log workflow events are recorded in and loads the workflows
+// it contains. Call ResumeWorkflows to continue the unfinished ones.
+func (o *Orchestrator) SetEventLog(events *EventLog) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.events = events
+	o.workflows = make(map[string]*models.Workflow)
+	for _, id := range events.WorkflowIDs() {
+		o.workflows[id] = replay(events.Events(id)).snapshot()
+	}
+}
+
+// SetRetryPolicy sets how often the remediation step is attempted
+func (o *Orchestrator) SetRetryPolicy(policy RetryPolicy) {
+	o.retry = policy
 }
 
-// TriggerRemediation initiates a remediation workflow
+// SetVerifier adds a verification step after remediation. The step is retried
+// according to policy until the resource recovered; if it does not, the remediation
+// is compensated where the remediator supports it.
+func (o *Orchestrator) SetVerifier(verifier Verifier, policy RetryPolicy) {
+	o.verifier = verifier
+	o.verifyPolicy = policy
+}
+
+// SetBudget limits automated remediations per namespace and resource. The budget
+// counts the workflows already in the event log, so call it after SetEventLog.
+func (o *Orchestrator) SetBudget(budget *Budget) {
+	for _, id := range o.events.WorkflowIDs() {
+		if events := o.events.Events(id); events[0].Type == EventWorkflowCreated && events[0].Issue != nil {
+			budget.record(events[0].Issue, events[0].Time)
+		}
+	}
+	o.budget = budget
+}
+
+// SetEventListener sets a function called with the workflow and each event recorded
+// for it, after the event is applied. It is called synchronously, so it must not block.
+func (o *Orchestrator) SetEventListener(listener func(*models.Workflow, WorkflowEvent)) {
+	o.listener = listener
+}
+
+// Budget returns the remediation budget, or nil if remediations are unlimited
+func (o *Orchestrator) Budget() *Budget {
+	return o.budget
+}
+
+// Admit counts a remediation of the issue against the budget. It returns a
+// *BudgetExceededError if the remediation exceeds the budget and was not approved, and
+// otherwise who approved exceeding the budget, if anyone. TriggerRemediation admits
+// its workflows itself; other automated actions, such as playbooks run by an external
+// workflow engine, must be admitted before they start.
+func (o *Orchestrator) Admit(ctx context.Context, issue *models.Issue) (string, error) {
+	if o.budget == nil {
+		return "", nil
+	}
+	override, err := o.budget.Admit(ctx, issue)
+	if err != nil {
+		o.log.WithFields(logrus.Fields{
+			"namespace": issue.Namespace,
+			"resource":  issue.ResourceName,
+		}).WithError(err).Info("Remediation rejected by budget")
+		return "", err
+	}
+	if override != "" {
+		o.log.WithFields(logrus.Fields{
+			"namespace":   issue.Namespace,
+			"resource":    issue.ResourceName,
+			"approved_by": override,
+		}).Info("Remediation admitted over budget by approval")
+	}
+	return override, nil
+}
+
+// TriggerRemediation initiates a remediation workflow if the budget admits it. The
+// deployment method is detected before it returns; the remaining steps run in the
+// background.
 func (o *Orchestrator) TriggerRemediation(ctx context.Context, incidentID string, issue *models.Issue) (*models.Workflow, error) {
 	o.log.WithFields(logrus.Fields{
 		"incident_id": incidentID,
@@ -49,35 +153,64 @@ func (o *Orchestrator) TriggerRemediation(ctx context.Context, incidentID string
 	if err := issue.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid issue: %w", err)
 	}
-
-	// Detect deployment method
-	deploymentInfo, err := o.detectDeploymentMethod(ctx, issue)
+	override, err := o.Admit(ctx, issue)
 	if err != nil {
-		o.log.WithError(err).Warn("Failed to detect deployment method, using manual remediation")
-		// Create unknown deployment info for manual remediation
-		deploymentInfo = models.NewDeploymentInfo(
-			issue.Namespace,
-			issue.ResourceName,
-			issue.ResourceType,
-			models.DeploymentMethodUnknown,
-			0.5,
-		)
+		return nil, err
 	}
 
-	// Create workflow
-	workflow := o.createWorkflow(incidentID, issue, deploymentInfo)
+	defs := o.definitions()
+	plan := []PlannedStep{
+		{Name: stepDetectDeployment, Description: defs[stepDetectDeployment].description},
+		{Name: stepRemediate, Description: defs[stepRemediate].description},
+	}
+	if o.verifier != nil {
+		plan = append(plan, PlannedStep{Name: stepVerify, Description: defs[stepVerify].description})
+	}
 
-	// Store workflow
-	o.mu.Lock()
-	o.workflows[workflow.ID] = workflow
-	o.mu.Unlock()
+	run := &workflowRun{}
+	o.record(run, WorkflowEvent{
+		WorkflowID:     generateWorkflowID(),
+		Type:           EventWorkflowCreated,
+		IncidentID:     incidentID,
+		Issue:          issue,
+		Remediator:     o.remediator.Name(),
+		Steps:          plan,
+		BudgetOverride: override,
+	})
+
+	RecordWorkflowStart()
+	o.record(run, WorkflowEvent{Type: EventWorkflowStarted})
+
+	// Detection falls back to manual remediation and does not fail
+	_ = o.engine.runStep(ctx, run, defs[stepDetectDeployment])
+	workflow := run.snapshot()
 
 	// Execute remediation in background
-	go o.executeWorkflow(context.Background(), workflow, deploymentInfo, issue)
+	go o.executeWorkflow(context.Background(), run, defs)
 
 	return workflow, nil
 }
 
+// ResumeWorkflows continues the workflows that were unfinished when the engine
+// stopped, from the step they were at. It returns the number of resumed workflows.
+func (o *Orchestrator) ResumeWorkflows() int {
+	defs := o.definitions()
+	resumed := 0
+	for _, id := range o.events.WorkflowIDs() {
+		run := replay(o.events.Events(id))
+		if !run.workflow.IsActive() {
+			continue
+		}
+
+		o.log.WithField("workflow_id", id).Info("Resuming remediation workflow")
+		o.record(run, WorkflowEvent{Type: EventWorkflowRecovered})
+		RecordWorkflowStart()
+		go o.executeWorkflow(context.Background(), run, defs)
+		resumed++
+	}
+	return resumed
+}
+
 // GetWorkflow retrieves a workflow by ID
 func (o *Orchestrator) GetWorkflow(workflowID string) (*models.Workflow, error) {
 	o.mu.RLock()
@@ -104,81 +237,140 @@ func (o *Orchestrator) ListWorkflows() []*models.Workflow {
 	return workflows
 }
 
-// createWorkflow creates a new workflow instance
-func (o *Orchestrator) createWorkflow(incidentID string, issue *models.Issue, deploymentInfo *models.DeploymentInfo) *models.Workflow {
-	workflow := &models.Workflow{
-		ID:               generateWorkflowID(),
-		IncidentID:       incidentID,
-		Status:           models.WorkflowStatusPending,
-		DeploymentMethod: string(deploymentInfo.Method),
-		Namespace:        issue.Namespace,
-		ResourceName:     issue.ResourceName,
-		ResourceKind:     issue.ResourceType,
-		IssueType:        issue.Type,
-		CreatedAt:        time.Now(),
+// WorkflowEvents returns the event log of a workflow, oldest first
+func (o *Orchestrator) WorkflowEvents(workflowID string) ([]WorkflowEvent, error) {
+	events := o.events.Events(workflowID)
+	if events == nil {
+		return nil, fmt.Errorf("workflow not found: %s", workflowID)
 	}
-
-	// Add initial step
-	workflow.AddStep(fmt.Sprintf("Detect deployment method for %s/%s", issue.Namespace, issue.ResourceName))
-
-	return workflow
+	return events, nil
 }
 
-// executeWorkflow executes the remediation workflow
-func (o *Orchestrator) executeWorkflow(ctx context.Context, workflow *models.Workflow, deploymentInfo *models.DeploymentInfo, issue *models.Issue) {
-	o.log.WithField("workflow_id", workflow.ID).Info("Starting workflow execution")
+// record appends an event to the log, applies it to the workflow and publishes the
+// resulting state
+func (o *Orchestrator) record(run *workflowRun, event WorkflowEvent) {
+	if event.WorkflowID == "" {
+		event.WorkflowID = run.workflow.ID
+	}
+	appended, err := o.events.Append(event)
+	if err != nil {
+		o.log.WithError(err).WithField("workflow_id", event.WorkflowID).Warn("Failed to persist workflow event")
+	}
+	run.apply(&appended)
 
-	// Record workflow start metrics
-	RecordWorkflowStart()
+	o.mu.Lock()
+	o.workflows[run.workflow.ID] = run.snapshot()
+	if len(o.workflows) > o.events.limit {
+		o.dropPruned()
+	}
+	o.mu.Unlock()
 
-	// Update workflow status
-	o.updateWorkflowStatus(workflow, models.WorkflowStatusRunning)
-	startTime := time.Now()
-	workflow.StartedAt = &startTime
+	if o.listener != nil {
+		o.listener(run.snapshot(), appended)
+	}
+}
 
-	// Add remediation step
-	step := workflow.AddStep(fmt.Sprintf("Execute %s remediation for %s", o.remediator.Name(), issue.Type))
-	workflow.Remediator = o.remediator.Name()
+// dropPruned removes the workflows whose events were pruned from the log. Callers
+// must hold o.mu.
+func (o *Orchestrator) dropPruned() {
+	logged := make(map[string]bool, len(o.workflows))
+	for _, id := range o.events.WorkflowIDs() {
+		logged[id] = true
+	}
+	for id := range o.workflows {
+		if !logged[id] {
+			delete(o.workflows, id)
+		}
+	}
+}
 
-	// Save workflow state
-	o.saveWorkflow(workflow)
+// definitions returns the steps of remediation workflows by name
+func (o *Orchestrator) definitions() map[string]*stepDefinition {
+	defs := map[string]*stepDefinition{
+		stepDetectDeployment: {
+			name:        stepDetectDeployment,
+			description: "Detect deployment method",
+			run:         o.detectStep,
+		},
+		stepRemediate: {
+			name:        stepRemediate,
+			description: fmt.Sprintf("Execute %s remediation", o.remediator.Name()),
+			retry:       o.retry,
+			run:         o.remediateStep,
+		},
+	}
+	if compensator, ok := o.remediator.(Compensator); ok {
+		defs[stepRemediate].compensate = func(ctx context.Context, r *workflowRun) error {
+			return compensator.Compensate(ctx, r.deployment, r.issue)
+		}
+	}
+	if o.verifier != nil {
+		defs[stepVerify] = &stepDefinition{
+			name:        stepVerify,
+			description: "Verify the resource recovered",
+			retry:       o.verifyPolicy,
+			when: func(r *workflowRun) (bool, string) {
+				return o.verifier.CanVerify(r.issue)
+			},
+			run: func(ctx context.Context, r *workflowRun) error {
+				return o.verifier.Verify(ctx, r.issue)
+			},
+		}
+	}
+	return defs
+}
 
-	// Execute remediation
-	err := o.remediator.Remediate(ctx, deploymentInfo, issue)
+// detectStep detects how the resource was deployed, falling back to manual
+// remediation if detection fails
+func (o *Orchestrator) detectStep(ctx context.Context, r *workflowRun) error {
+	deploymentInfo, err := o.detectDeploymentMethod(ctx, r.issue)
+	if err != nil {
+		o.log.WithError(err).Warn("Failed to detect deployment method, using manual remediation")
+		// Create unknown deployment info for manual remediation
+		deploymentInfo = models.NewDeploymentInfo(
+			r.issue.Namespace,
+			r.issue.ResourceName,
+			r.issue.ResourceType,
+			models.DeploymentMethodUnknown,
+			0.5,
+		)
+	}
+	r.deployment = deploymentInfo
+	return nil
+}
 
-	completedTime := time.Now()
-	workflow.CompletedAt = &completedTime
-	duration := completedTime.Sub(startTime).Seconds()
+// remediateStep executes the remediation
+func (o *Orchestrator) remediateStep(ctx context.Context, r *workflowRun) error {
+	method, issueType := string(r.deployment.Method), r.issue.Type
+	start := time.Now()
+	err := o.remediator.Remediate(ctx, r.deployment, r.issue)
+	duration := time.Since(start).Seconds()
 
 	if err != nil {
 		o.log.WithError(err).Error("Remediation failed")
-		workflow.Status = models.WorkflowStatusFailed
-		workflow.ErrorMessage = err.Error()
-		step.Status = "failed"
-		step.ErrorMessage = err.Error()
+		RecordRemediation(o.remediator.Name(), method, issueType, duration, false)
+		RecordRemediationFailure(o.remediator.Name(), method, issueType, "remediation_error")
+		return err
+	}
+	o.log.Info("Remediation completed successfully")
+	RecordRemediation(o.remediator.Name(), method, issueType, duration, true)
+	return nil
+}
 
-		// Record remediation failure metrics
-		RecordRemediation(o.remediator.Name(), string(deploymentInfo.Method), issue.Type, duration, false)
-		RecordRemediationFailure(o.remediator.Name(), string(deploymentInfo.Method), issue.Type, "remediation_error")
-		RecordWorkflowEnd("failed")
-	} else {
-		o.log.Info("Remediation completed successfully")
-		workflow.Status = models.WorkflowStatusCompleted
-		step.Status = "completed"
-		step.CompletedAt = &completedTime
+// executeWorkflow executes the remaining steps of a workflow
+func (o *Orchestrator) executeWorkflow(ctx context.Context, run *workflowRun, defs map[string]*stepDefinition) {
+	o.log.WithField("workflow_id", run.workflow.ID).Info("Starting workflow execution")
 
-		// Record remediation success metrics
-		RecordRemediation(o.remediator.Name(), string(deploymentInfo.Method), issue.Type, duration, true)
-		RecordWorkflowEnd("completed")
+	if !o.engine.execute(ctx, run, defs) {
+		o.log.WithField("workflow_id", run.workflow.ID).Warn("Workflow execution interrupted")
+		return
 	}
-
-	// Save final workflow state
-	o.saveWorkflow(workflow)
+	RecordWorkflowEnd(string(run.workflow.Status))
 
 	o.log.WithFields(logrus.Fields{
-		"workflow_id": workflow.ID,
-		"status":      workflow.Status,
-		"duration":    workflow.Duration().String(),
+		"workflow_id": run.workflow.ID,
+		"status":      run.workflow.Status,
+		"duration":    run.workflow.Duration().String(),
 	}).Info("Workflow execution completed")
 }
 
@@ -207,18 +399,19 @@ func (o *Orchestrator) detectDeploymentMethod(ctx context.Context, issue *models
 	return deploymentInfo, nil
 }
 
-// updateWorkflowStatus updates the workflow status
-func (o *Orchestrator) updateWorkflowStatus(workflow *models.Workflow, status models.WorkflowStatus) {
-	o.mu.Lock()
-	defer o.mu.Unlock()
-	workflow.Status = status
-}
-
-// saveWorkflow persists workflow state
-func (o *Orchestrator) saveWorkflow(workflow *models.Workflow) {
-	o.mu.Lock()
-	defer o.mu.Unlock()
-	o.workflows[workflow.ID] = workflow
+// sleepContext waits for d or until ctx is cancelled
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
 // generateWorkflowID generates a unique workflow ID
@@ -113,6 +113,30 @@ func (ss *StrategySelector) Remediate(ctx context.Context, deploymentInfo *model
 	return nil
 }
 
+// Compensate undoes a remediation using the remediator selected for the deployment
+func (ss *StrategySelector) Compensate(ctx context.Context, deploymentInfo *models.DeploymentInfo, issue *models.Issue) error {
+	remediator := ss.SelectRemediator(deploymentInfo)
+	if remediator == nil {
+		return fmt.Errorf("no remediator available for deployment method: %s", deploymentInfo.Method)
+	}
+	compensator, ok := remediator.(Compensator)
+	if !ok {
+		return fmt.Errorf("%s remediation: %w", remediator.Name(), ErrCompensationUnsupported)
+	}
+
+	ss.log.WithFields(logrus.Fields{
+		"issue_id":   issue.ID,
+		"remediator": remediator.Name(),
+		"namespace":  issue.Namespace,
+		"resource":   issue.ResourceName,
+	}).Warn("Compensating remediation")
+
+	if err := compensator.Compensate(ctx, deploymentInfo, issue); err != nil {
+		return fmt.Errorf("%s compensation failed: %w", remediator.Name(), err)
+	}
+	return nil
+}
+
 // CanRemediate returns true if any remediator can handle the deployment
 func (ss *StrategySelector) CanRemediate(deploymentInfo *models.DeploymentInfo) bool {
 	return ss.SelectRemediator(deploymentInfo) != nil
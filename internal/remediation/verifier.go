@@ -0,0 +1,117 @@
+package remediation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// VerifyInterval is how often the verification step checks the resource
+const VerifyInterval = 15 * time.Second
+
+// VerifyPolicy returns the retry policy that checks the resource every VerifyInterval
+// until timeout has passed
+func VerifyPolicy(timeout time.Duration) RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: int(timeout/VerifyInterval) + 1,
+		Backoff:     VerifyInterval,
+		MaxBackoff:  VerifyInterval,
+	}
+}
+
+// Verifier checks whether the resource of a remediated issue recovered
+type Verifier interface {
+	// CanVerify reports whether the issue's resource can be verified, and why not
+	CanVerify(issue *models.Issue) (bool, string)
+
+	// Verify returns an error if the resource has not recovered (yet)
+	Verify(ctx context.Context, issue *models.Issue) error
+}
+
+// RolloutVerifier verifies that a Deployment, StatefulSet or DaemonSet has finished
+// rolling out and all its replicas are ready
+type RolloutVerifier struct {
+	clientset kubernetes.Interface
+	log       *logrus.Logger
+}
+
+// NewRolloutVerifier creates a rollout verifier
+func NewRolloutVerifier(clientset kubernetes.Interface, log *logrus.Logger) *RolloutVerifier {
+	return &RolloutVerifier{
+		clientset: clientset,
+		log:       log,
+	}
+}
+
+// CanVerify reports whether the issue's resource is a workload with a rollout status
+func (v *RolloutVerifier) CanVerify(issue *models.Issue) (bool, string) {
+	switch strings.ToLower(issue.ResourceType) {
+	case "deployment", "statefulset", "daemonset":
+		return true, ""
+	default:
+		return false, fmt.Sprintf("resource type %q has no rollout status to verify", issue.ResourceType)
+	}
+}
+
+// Verify checks the rollout status of the issue's workload
+func (v *RolloutVerifier) Verify(ctx context.Context, issue *models.Issue) error {
+	namespace, name := issue.Namespace, issue.ResourceName
+
+	var generation, observed int64
+	var desired, updated, ready int32
+	switch strings.ToLower(issue.ResourceType) {
+	case "deployment":
+		d, err := v.clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to get deployment: %w", err)
+		}
+		desired = 1
+		if d.Spec.Replicas != nil {
+			desired = *d.Spec.Replicas
+		}
+		generation, observed = d.Generation, d.Status.ObservedGeneration
+		updated, ready = d.Status.UpdatedReplicas, d.Status.ReadyReplicas
+	case "statefulset":
+		s, err := v.clientset.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to get statefulset: %w", err)
+		}
+		desired = 1
+		if s.Spec.Replicas != nil {
+			desired = *s.Spec.Replicas
+		}
+		generation, observed = s.Generation, s.Status.ObservedGeneration
+		updated, ready = s.Status.UpdatedReplicas, s.Status.ReadyReplicas
+	case "daemonset":
+		ds, err := v.clientset.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to get daemonset: %w", err)
+		}
+		desired = ds.Status.DesiredNumberScheduled
+		generation, observed = ds.Generation, ds.Status.ObservedGeneration
+		updated, ready = ds.Status.UpdatedNumberScheduled, ds.Status.NumberReady
+	default:
+		return fmt.Errorf("cannot verify resource type %q", issue.ResourceType)
+	}
+
+	if observed < generation {
+		return fmt.Errorf("%s/%s rollout not yet observed by the controller", namespace, name)
+	}
+	if updated < desired || ready < desired {
+		return fmt.Errorf("%s/%s not recovered: %d of %d replicas updated, %d ready", namespace, name, updated, desired, ready)
+	}
+
+	v.log.WithFields(logrus.Fields{
+		"namespace": namespace,
+		"resource":  name,
+		"replicas":  desired,
+	}).Debug("Workload rollout verified")
+	return nil
+}
@@ -0,0 +1,28 @@
+package retention
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Purge reasons
+const (
+	ReasonExpired   = "expired"
+	ReasonNamespace = "namespace"
+)
+
+var (
+	// ItemsPurged counts deleted items by data type and reason
+	ItemsPurged = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "coordination_engine_retention_purged_total",
+			Help: "Total number of stored items deleted, by data type and reason (expired, namespace)",
+		},
+		[]string{"data_type", "reason"},
+	)
+)
+
+// RecordPurged records deleted items
+func RecordPurged(dataType, reason string, count int) {
+	ItemsPurged.WithLabelValues(dataType, reason).Add(float64(count))
+}
@@ -0,0 +1,249 @@
+// Package retention enforces how long the engine keeps stored data.
+//
+// Each data type has a Rule with its retention period. The Purger deletes data older
+// than the period on a schedule, and deletes everything belonging to a namespace on
+// request, e.g. when a tenant is offboarded.
+package retention
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/featurehistory"
+	"github.com/tosin2013/openshift-coordination-engine/internal/storage"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// Data type names
+const (
+	DataTypeIncidents   = "incidents"
+	DataTypePredictions = "predictions"
+	DataTypeAnomalies   = "anomalies"
+	DataTypeFeatures    = "feature_history"
+)
+
+// DefaultPurgeInterval is how often expired data is purged when no interval is configured
+const DefaultPurgeInterval = time.Hour
+
+// Rule is the retention policy of one data type
+type Rule struct {
+	DataType string
+
+	// Retention is how long data is kept; 0 keeps it forever
+	Retention time.Duration
+
+	// PurgeBefore deletes data last updated before cutoff
+	PurgeBefore func(cutoff time.Time) (int, error)
+
+	// PurgeNamespace deletes all data belonging to a namespace
+	PurgeNamespace func(namespace string) (int, error)
+}
+
+// IncidentRule purges incidents that have not been updated within retention. An
+// incident belongs to the namespace in its target.
+func IncidentRule(store *storage.IncidentStore, retention time.Duration) Rule {
+	return Rule{
+		DataType:  DataTypeIncidents,
+		Retention: retention,
+		PurgeBefore: func(cutoff time.Time) (int, error) {
+			return store.DeleteMatching(func(incident *models.Incident) bool {
+				lastActivity := incident.UpdatedAt
+				if lastActivity.IsZero() {
+					lastActivity = incident.CreatedAt
+				}
+				return lastActivity.Before(cutoff)
+			})
+		},
+		PurgeNamespace: func(namespace string) (int, error) {
+			return store.DeleteMatching(func(incident *models.Incident) bool {
+				return incident.Target == namespace
+			})
+		},
+	}
+}
+
+// PredictionRule purges predictions created before retention
+func PredictionRule(store *storage.PredictionStore, retention time.Duration) Rule {
+	return Rule{
+		DataType:  DataTypePredictions,
+		Retention: retention,
+		PurgeBefore: func(cutoff time.Time) (int, error) {
+			return store.DeleteMatching(func(record *models.PredictionRecord) bool {
+				return record.CreatedAt.Before(cutoff)
+			})
+		},
+		PurgeNamespace: func(namespace string) (int, error) {
+			return store.DeleteMatching(func(record *models.PredictionRecord) bool {
+				return record.Namespace == namespace
+			})
+		},
+	}
+}
+
+// AnomalyRule purges anomalies detected before retention
+func AnomalyRule(store *storage.AnomalyStore, retention time.Duration) Rule {
+	return Rule{
+		DataType:  DataTypeAnomalies,
+		Retention: retention,
+		PurgeBefore: func(cutoff time.Time) (int, error) {
+			return store.DeleteMatching(func(record *models.AnomalyRecord) bool {
+				return record.DetectedAt.Before(cutoff)
+			})
+		},
+		PurgeNamespace: func(namespace string) (int, error) {
+			return store.DeleteMatching(func(record *models.AnomalyRecord) bool {
+				return record.Namespace == namespace
+			})
+		},
+	}
+}
+
+// FeatureHistoryRule purges feature vectors evaluated before retention. The history
+// holds at most retention of vectors per scope anyway; the rule drops scopes that
+// are no longer analyzed.
+func FeatureHistoryRule(history *featurehistory.History, retention time.Duration) Rule {
+	return Rule{
+		DataType:  DataTypeFeatures,
+		Retention: retention,
+		PurgeBefore: func(cutoff time.Time) (int, error) {
+			return history.PurgeBefore(cutoff), nil
+		},
+		PurgeNamespace: func(namespace string) (int, error) {
+			return history.Forget(namespace), nil
+		},
+	}
+}
+
+// PurgeResult is the number of items deleted for one data type
+type PurgeResult struct {
+	DataType string `json:"data_type"`
+	Purged   int    `json:"purged"`
+}
+
+// Policy describes a rule for reporting
+type Policy struct {
+	DataType  string `json:"data_type"`
+	Retention string `json:"retention"`
+}
+
+// Purger applies retention rules
+type Purger struct {
+	rules []Rule
+	log   *logrus.Logger
+
+	// now is replaceable in tests
+	now func() time.Time
+}
+
+// NewPurger creates a purger without rules
+func NewPurger(log *logrus.Logger) *Purger {
+	return &Purger{
+		log: log,
+		now: time.Now,
+	}
+}
+
+// Add registers a retention rule
+func (p *Purger) Add(rule Rule) {
+	p.rules = append(p.rules, rule)
+}
+
+// Policies returns the configured retention per data type ("forever" when disabled)
+func (p *Purger) Policies() []Policy {
+	policies := make([]Policy, 0, len(p.rules))
+	for _, rule := range p.rules {
+		retention := "forever"
+		if rule.Retention > 0 {
+			retention = rule.Retention.String()
+		}
+		policies = append(policies, Policy{DataType: rule.DataType, Retention: retention})
+	}
+	return policies
+}
+
+// Start purges expired data every interval until ctx is cancelled
+func (p *Purger) Start(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultPurgeInterval
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := p.PurgeExpired(); err != nil {
+					p.log.WithError(err).Warn("Failed to purge expired data")
+				}
+			}
+		}
+	}()
+
+	p.log.WithFields(logrus.Fields{
+		"interval": interval,
+		"policies": p.Policies(),
+	}).Info("Data retention purging started")
+}
+
+// PurgeExpired deletes data older than each rule's retention. A failing data type
+// does not stop the others; the first error is returned.
+func (p *Purger) PurgeExpired() ([]PurgeResult, error) {
+	now := p.now()
+	results := make([]PurgeResult, 0, len(p.rules))
+	var firstErr error
+
+	for _, rule := range p.rules {
+		if rule.Retention <= 0 {
+			continue
+		}
+		purged, err := rule.PurgeBefore(now.Add(-rule.Retention))
+		if err != nil {
+			p.log.WithError(err).WithField("data_type", rule.DataType).Warn("Failed to purge expired data")
+			if firstErr == nil {
+				firstErr = fmt.Errorf("failed to purge %s: %w", rule.DataType, err)
+			}
+			continue
+		}
+		results = append(results, PurgeResult{DataType: rule.DataType, Purged: purged})
+		if purged > 0 {
+			RecordPurged(rule.DataType, ReasonExpired, purged)
+			p.log.WithFields(logrus.Fields{
+				"data_type": rule.DataType,
+				"purged":    purged,
+				"retention": rule.Retention,
+			}).Info("Purged expired data")
+		}
+	}
+
+	return results, firstErr
+}
+
+// PurgeNamespace deletes all data belonging to a namespace from every data type
+func (p *Purger) PurgeNamespace(namespace string) ([]PurgeResult, error) {
+	if namespace == "" {
+		return nil, fmt.Errorf("namespace is required")
+	}
+
+	results := make([]PurgeResult, 0, len(p.rules))
+	for _, rule := range p.rules {
+		purged, err := rule.PurgeNamespace(namespace)
+		if err != nil {
+			return results, fmt.Errorf("failed to purge %s: %w", rule.DataType, err)
+		}
+		results = append(results, PurgeResult{DataType: rule.DataType, Purged: purged})
+		RecordPurged(rule.DataType, ReasonNamespace, purged)
+	}
+
+	p.log.WithFields(logrus.Fields{
+		"namespace": namespace,
+		"results":   results,
+	}).Warn("Purged namespace data")
+
+	return results, nil
+}
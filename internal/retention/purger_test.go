@@ -0,0 +1,135 @@
+package retention
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/featurehistory"
+	"github.com/tosin2013/openshift-coordination-engine/internal/storage"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+func newTestPurger(t *testing.T, incidentRetention, predictionRetention time.Duration) (*Purger, *storage.IncidentStore, *storage.PredictionStore) {
+	t.Helper()
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	dir := t.TempDir()
+	incidents := storage.NewIncidentStoreWithPath(dir)
+	predictions := storage.NewPredictionStore(dir, 0)
+
+	require.NoError(t, incidents.Restore([]*models.Incident{
+		{ID: "inc-old", Target: "tenant-a", CreatedAt: now.AddDate(0, 0, -120), UpdatedAt: now.AddDate(0, 0, -100)},
+		{ID: "inc-updated", Target: "tenant-b", CreatedAt: now.AddDate(0, 0, -120), UpdatedAt: now.AddDate(0, 0, -10)},
+		{ID: "inc-new", Target: "tenant-a", CreatedAt: now.AddDate(0, 0, -1), UpdatedAt: now.AddDate(0, 0, -1)},
+	}, true))
+	require.NoError(t, predictions.Restore([]models.PredictionRecord{
+		{ID: "pred-old", Namespace: "tenant-a", CreatedAt: now.AddDate(0, 0, -45)},
+		{ID: "pred-new", Namespace: "tenant-b", CreatedAt: now.AddDate(0, 0, -2)},
+	}, true))
+
+	purger := NewPurger(log)
+	purger.now = func() time.Time { return now }
+	purger.Add(IncidentRule(incidents, incidentRetention))
+	purger.Add(PredictionRule(predictions, predictionRetention))
+	return purger, incidents, predictions
+}
+
+func TestPurger_PurgeExpired(t *testing.T) {
+	purger, incidents, predictions := newTestPurger(t, 90*24*time.Hour, 30*24*time.Hour)
+
+	results, err := purger.PurgeExpired()
+	require.NoError(t, err)
+	assert.Equal(t, []PurgeResult{
+		{DataType: DataTypeIncidents, Purged: 1},
+		{DataType: DataTypePredictions, Purged: 1},
+	}, results)
+
+	_, err = incidents.Get("inc-old")
+	assert.Error(t, err)
+	_, err = incidents.Get("inc-updated")
+	assert.NoError(t, err, "retention counts from the last update")
+	assert.Equal(t, 2, incidents.Count())
+	assert.Equal(t, 1, predictions.Count())
+
+	// A retention of 0 keeps data forever
+	purger, incidents, _ = newTestPurger(t, 0, 30*24*time.Hour)
+	results, err = purger.PurgeExpired()
+	require.NoError(t, err)
+	assert.Equal(t, []PurgeResult{{DataType: DataTypePredictions, Purged: 1}}, results)
+	assert.Equal(t, 3, incidents.Count())
+	assert.Equal(t, []Policy{
+		{DataType: DataTypeIncidents, Retention: "forever"},
+		{DataType: DataTypePredictions, Retention: "720h0m0s"},
+	}, purger.Policies())
+}
+
+func TestPurger_PurgeNamespace(t *testing.T) {
+	purger, incidents, predictions := newTestPurger(t, 0, 0)
+
+	results, err := purger.PurgeNamespace("tenant-a")
+	require.NoError(t, err)
+	assert.Equal(t, []PurgeResult{
+		{DataType: DataTypeIncidents, Purged: 2},
+		{DataType: DataTypePredictions, Purged: 1},
+	}, results)
+	assert.Equal(t, 1, incidents.Count())
+	assert.Equal(t, 1, predictions.Count())
+
+	changes, unsubscribe := incidents.Subscribe(1)
+	defer unsubscribe()
+	_, err = purger.PurgeNamespace("tenant-b")
+	require.NoError(t, err)
+	assert.Equal(t, storage.IncidentDeleted, (<-changes).Type, "subscribers see purged incidents as deleted")
+	assert.Zero(t, incidents.Count())
+
+	_, err = purger.PurgeNamespace("")
+	assert.Error(t, err)
+}
+
+func TestFeatureHistoryRule(t *testing.T) {
+	history := featurehistory.New(featurehistory.Options{Retention: time.Hour, Resolution: time.Minute})
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	history.Record(featurehistory.Key{Model: "anomaly-detector", Namespace: "tenant-a"}, now.Add(-2*time.Hour), []float64{0.5})
+	history.Record(featurehistory.Key{Model: "anomaly-detector", Namespace: "tenant-b"}, now.Add(-time.Minute), []float64{0.5})
+	history.Record(featurehistory.Key{Model: "anomaly-detector", Namespace: "tenant-b"}, now, []float64{0.5})
+
+	purger := NewPurger(logrus.New())
+	purger.now = func() time.Time { return now }
+	purger.Add(FeatureHistoryRule(history, time.Hour))
+
+	results, err := purger.PurgeExpired()
+	require.NoError(t, err)
+	assert.Equal(t, []PurgeResult{{DataType: DataTypeFeatures, Purged: 1}}, results)
+	results, err = purger.PurgeNamespace("tenant-b")
+	require.NoError(t, err)
+	assert.Equal(t, []PurgeResult{{DataType: DataTypeFeatures, Purged: 2}}, results)
+	assert.Empty(t, history.Scopes())
+}
+
+func TestAnomalyRule(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	anomalies := storage.NewMemoryAnomalyStore(0)
+	require.NoError(t, anomalies.Restore([]models.AnomalyRecord{
+		{ID: "anom-old", Namespace: "tenant-a", DetectedAt: now.AddDate(0, 0, -45)},
+		{ID: "anom-new", Namespace: "tenant-a", DetectedAt: now.AddDate(0, 0, -2)},
+		{ID: "anom-other", Namespace: "tenant-b", DetectedAt: now.AddDate(0, 0, -2)},
+	}, true))
+
+	purger := NewPurger(logrus.New())
+	purger.now = func() time.Time { return now }
+	purger.Add(AnomalyRule(anomalies, 30*24*time.Hour))
+
+	results, err := purger.PurgeExpired()
+	require.NoError(t, err)
+	assert.Equal(t, []PurgeResult{{DataType: DataTypeAnomalies, Purged: 1}}, results)
+	results, err = purger.PurgeNamespace("tenant-a")
+	require.NoError(t, err)
+	assert.Equal(t, []PurgeResult{{DataType: DataTypeAnomalies, Purged: 1}}, results)
+	assert.Equal(t, 1, anomalies.Count())
+}
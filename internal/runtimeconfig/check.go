@@ -0,0 +1,435 @@
+package runtimeconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/api/validation"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// DiagnosticSeverity ranks diagnostics. Only errors make a spec invalid.
+type DiagnosticSeverity string
+
+// Diagnostic severities
+const (
+	DiagnosticError   DiagnosticSeverity = "error"
+	DiagnosticWarning DiagnosticSeverity = "warning"
+	DiagnosticInfo    DiagnosticSeverity = "info"
+)
+
+// Diagnostic codes reported by lint checks. Type-check errors use the validation
+// constraint (required, range, enum, ...) as their code.
+const (
+	CodeUnknownPlaybook      = "unknown_playbook"
+	CodeMatchesEverything    = "matches_everything"
+	CodeShadowed             = "shadowed"
+	CodeDisabled             = "disabled"
+	CodeIgnoredPlaybook      = "ignored_playbook"
+	CodeIgnoredReason        = "ignored_reason"
+	CodeNoIssueTypes         = "no_issue_types"
+	CodeUnreferenced         = "unreferenced"
+	CodeUnknownParam         = "unknown_param"
+	CodeScaleToZero          = "scale_to_zero"
+	CodeMissingTimeout       = "missing_timeout"
+	CodeLongDuration         = "long_duration"
+	CodeTrailingContinue     = "trailing_continue_on_error"
+	CodeSampleNotMatched     = "sample_not_matched"
+	CodeDecidedByOtherPolicy = "decided_by_other_policy"
+	CodeReferencesUnchecked  = "references_unchecked"
+)
+
+// longStepDuration is the wait or timeout above which a step is reported as long
+const longStepDuration = time.Hour
+
+// Diagnostic is a problem or remark about a spec
+type Diagnostic struct {
+	Severity DiagnosticSeverity `json:"severity"`
+
+	// Field is the JSON path of the offending field, e.g. "spec.steps[1].params"
+	Field string `json:"field,omitempty"`
+
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// CheckResult is the outcome of a dry-run check of a policy or playbook
+type CheckResult struct {
+	// Valid is true when no diagnostic is an error, i.e. the spec would be accepted
+	Valid       bool         `json:"valid"`
+	Diagnostics []Diagnostic `json:"diagnostics"`
+
+	// Policy and Playbook simulate the spec against the sample incident, if one was given
+	Policy   *PolicySimulation   `json:"policy_simulation,omitempty"`
+	Playbook *PlaybookSimulation `json:"playbook_simulation,omitempty"`
+}
+
+// PolicySimulation is how remediation of the sample incident would be decided
+type PolicySimulation struct {
+	// Matches reports whether the checked policy matches the sample
+	Matches bool `json:"matches"`
+
+	// Decision is allow, deny or none (no policy matches, so remediation is allowed)
+	// taking the stored policies into account, with the checked policy in place of
+	// the stored one of the same name
+	Decision  string `json:"decision"`
+	DecidedBy string `json:"decided_by,omitempty"`
+	Playbook  string `json:"playbook,omitempty"`
+}
+
+// DecisionNone is the simulated decision when no policy matches
+const DecisionNone = "none"
+
+// PlaybookSimulation is the plan the playbook would run for the sample incident
+type PlaybookSimulation struct {
+	// Matches reports whether the sample's issue type is one of the playbook's
+	Matches bool `json:"matches"`
+
+	// AssignedBy lists the stored policies that match the sample and assign the playbook
+	AssignedBy []string `json:"assigned_by,omitempty"`
+
+	Steps []SimulatedStep `json:"steps"`
+
+	// MaxDuration is the sum of the steps' waits and timeouts; steps without a
+	// timeout are not counted
+	MaxDuration string `json:"max_duration"`
+}
+
+// SimulatedStep is a playbook step resolved against the sample incident
+type SimulatedStep struct {
+	Name   string            `json:"name"`
+	Action string            `json:"action"`
+	Target string            `json:"target,omitempty"`
+	Params map[string]string `json:"params,omitempty"`
+
+	Timeout         string `json:"timeout,omitempty"`
+	ContinueOnError bool   `json:"continue_on_error,omitempty"`
+}
+
+// clusterActions change cluster state and should have a timeout
+var clusterActions = map[string]bool{
+	ActionRestartPod:     true,
+	ActionRolloutRestart: true,
+	ActionScale:          true,
+	ActionRollback:       true,
+	ActionSyncArgoCD:     true,
+}
+
+// knownParams lists the parameters each action reads
+var knownParams = map[string][]string{
+	ActionRestartPod:     {"pod"},
+	ActionRolloutRestart: {"kind", "name"},
+	ActionScale:          {"kind", "name", "replicas"},
+	ActionRollback:       {"kind", "name", "revision"},
+	ActionSyncArgoCD:     {"application"},
+	ActionWait:           {"duration"},
+	ActionNotify:         {"message", "channel"},
+	ActionManual:         {"instructions"},
+}
+
+// namedPolicy is a decoded policy and its name
+type namedPolicy struct {
+	name   string
+	policy *Policy
+}
+
+// CheckPolicy type-checks and lints a policy spec stored under name (which may be
+// empty) and, if incident is not nil, simulates the remediation decision for it.
+// The store provides the other policies and playbooks; it may be nil.
+func CheckPolicy(store *Store, name string, raw json.RawMessage, incident *models.Incident) *CheckResult {
+	result := &CheckResult{Diagnostics: []Diagnostic{}}
+	spec, ok := typeCheck(result, KindPolicy, name, raw)
+	if !ok {
+		return result
+	}
+	policy := spec.(*Policy)
+
+	if policy.Disabled {
+		result.info("spec.disabled", CodeDisabled, "policy is disabled and is not evaluated")
+	}
+	if isEmptySelector(&policy.Match) {
+		if policy.Action == PolicyDeny {
+			result.warn("spec.match", CodeMatchesEverything, "policy matches every remediation request and denies all automated remediation")
+		} else {
+			result.info("spec.match", CodeMatchesEverything, "policy matches every remediation request; later policies are never evaluated")
+		}
+	}
+	if policy.Action == PolicyDeny && policy.Playbook != "" {
+		result.warn("spec.playbook", CodeIgnoredPlaybook, "playbook is ignored because the policy denies remediation")
+	}
+	if policy.Action == PolicyAllow && policy.Reason != "" {
+		result.info("spec.reason", CodeIgnoredReason, "reason is only reported by deny policies")
+	}
+
+	others := store.policies(name)
+	if store != nil {
+		if policy.Playbook != "" {
+			if _, err := store.Get(KindPlaybook, policy.Playbook); err != nil {
+				result.warn("spec.playbook", CodeUnknownPlaybook, fmt.Sprintf("playbook %s does not exist; create it before relying on this policy", policy.Playbook))
+			}
+		}
+		for _, other := range others {
+			if name != "" && other.name > name {
+				break
+			}
+			if !other.policy.Disabled && covers(&other.policy.Match, &policy.Match) {
+				result.warn("spec.match", CodeShadowed, fmt.Sprintf("policy %s is evaluated first and matches every request this policy matches, so this policy never applies", other.name))
+				break
+			}
+		}
+	} else {
+		result.info("", CodeReferencesUnchecked, "runtime configuration is disabled; references to other objects were not checked")
+	}
+
+	if incident != nil {
+		result.Policy = simulatePolicy(result, name, policy, others, issueFromIncident(incident))
+	}
+	return result
+}
+
+// CheckPlaybook type-checks and lints a playbook spec stored under name (which may be
+// empty) and, if incident is not nil, resolves its steps for the incident. The store
+// provides the policies that assign playbooks; it may be nil.
+func CheckPlaybook(store *Store, name string, raw json.RawMessage, incident *models.Incident) *CheckResult {
+	result := &CheckResult{Diagnostics: []Diagnostic{}}
+	spec, ok := typeCheck(result, KindPlaybook, name, raw)
+	if !ok {
+		return result
+	}
+	playbook := spec.(*Playbook)
+
+	if len(playbook.IssueTypes) == 0 {
+		result.info("spec.issue_types", CodeNoIssueTypes, "playbook lists no issue types and only runs when a policy assigns it")
+	}
+	for i := range playbook.Steps {
+		lintStep(result, fmt.Sprintf("spec.steps[%d]", i), &playbook.Steps[i])
+	}
+	if last := len(playbook.Steps) - 1; last >= 0 && playbook.Steps[last].ContinueOnError {
+		result.info(fmt.Sprintf("spec.steps[%d].continue_on_error", last), CodeTrailingContinue, "continue_on_error has no effect on the last step")
+	}
+
+	policies := store.policies("")
+	if store != nil && name != "" {
+		referenced := false
+		for _, p := range policies {
+			referenced = referenced || p.policy.Playbook == name
+		}
+		if !referenced && len(playbook.IssueTypes) == 0 {
+			result.warn("", CodeUnreferenced, "no policy assigns this playbook and it lists no issue types, so it never runs")
+		} else if !referenced {
+			result.info("", CodeUnreferenced, "no policy assigns this playbook")
+		}
+	}
+
+	if incident != nil {
+		result.Playbook = simulatePlaybook(result, name, playbook, policies, incident)
+	}
+	return result
+}
+
+// typeCheck decodes and validates a spec and its name, recording errors as diagnostics
+func typeCheck(result *CheckResult, kind Kind, name string, raw json.RawMessage) (Spec, bool) {
+	spec, err := decodeSpec(kind, raw, time.Now())
+	fieldErrs := validation.Fields(err)
+	if name != "" {
+		fieldErrs = append(validation.Fields(validateName(name)), fieldErrs...)
+	}
+	if err != nil && fieldErrs == nil {
+		fieldErrs = []validation.FieldError{{Field: "spec", Constraint: validation.ConstraintFormat, Message: err.Error()}}
+	}
+	for _, fieldErr := range fieldErrs {
+		result.Diagnostics = append(result.Diagnostics, Diagnostic{
+			Severity: DiagnosticError,
+			Field:    fieldErr.Field,
+			Code:     fieldErr.Constraint,
+			Message:  fieldErr.Message,
+		})
+	}
+	result.Valid = len(fieldErrs) == 0
+	return spec, result.Valid
+}
+
+// lintStep reports risky or ineffective step settings
+func lintStep(result *CheckResult, field string, step *PlaybookStep) {
+	known := knownParams[step.Action]
+	names := make([]string, 0, len(step.Params))
+	for param := range step.Params {
+		names = append(names, param)
+	}
+	sort.Strings(names)
+	for _, param := range names {
+		if !contains(known, param) {
+			result.warn(field+".params."+param, CodeUnknownParam, fmt.Sprintf("action %s does not read parameter %s", step.Action, param))
+		}
+	}
+
+	if step.Action == ActionScale && step.Params["replicas"] == "0" {
+		result.warn(field+".params.replicas", CodeScaleToZero, "step scales the workload to zero replicas, taking it offline")
+	}
+	if clusterActions[step.Action] && step.Timeout == "" {
+		result.info(field+".timeout", CodeMissingTimeout, fmt.Sprintf("step has no timeout; a stuck %s blocks the playbook until the backend gives up", step.Action))
+	}
+	if timeout, err := time.ParseDuration(step.Timeout); err == nil && timeout > longStepDuration {
+		result.warn(field+".timeout", CodeLongDuration, fmt.Sprintf("timeout %s is longer than %s", step.Timeout, longStepDuration))
+	}
+	if wait, err := time.ParseDuration(step.Params["duration"]); err == nil && step.Action == ActionWait && wait > longStepDuration {
+		result.warn(field+".params.duration", CodeLongDuration, fmt.Sprintf("wait of %s is longer than %s", step.Params["duration"], longStepDuration))
+	}
+}
+
+// simulatePolicy decides remediation of the issue with the checked policy in place
+func simulatePolicy(result *CheckResult, name string, policy *Policy, others []namedPolicy, issue *models.Issue) *PolicySimulation {
+	// An unnamed policy is evaluated after the stored ones
+	candidates := append(others, namedPolicy{name: name, policy: policy})
+	if name != "" {
+		sort.Slice(candidates, func(i, j int) bool { return candidates[i].name < candidates[j].name })
+	}
+
+	simulation := &PolicySimulation{
+		Matches:  !policy.Disabled && policy.Match.MatchesIssue(issue),
+		Decision: DecisionNone,
+	}
+	for _, candidate := range candidates {
+		if candidate.policy.Disabled || !candidate.policy.Match.MatchesIssue(issue) {
+			continue
+		}
+		simulation.Decision = candidate.policy.Action
+		simulation.DecidedBy = candidate.name
+		if candidate.policy.Action == PolicyAllow {
+			simulation.Playbook = candidate.policy.Playbook
+		}
+		break
+	}
+
+	switch {
+	case !simulation.Matches:
+		result.info("spec.match", CodeSampleNotMatched, "policy does not match the sample incident")
+	case simulation.DecidedBy != name:
+		result.warn("spec.match", CodeDecidedByOtherPolicy,
+			fmt.Sprintf("policy matches the sample incident, but policy %s is evaluated first and decides %s", simulation.DecidedBy, simulation.Decision))
+	}
+	return simulation
+}
+
+// simulatePlaybook resolves the playbook's steps against the incident
+func simulatePlaybook(result *CheckResult, name string, playbook *Playbook, policies []namedPolicy, incident *models.Incident) *PlaybookSimulation {
+	issue := issueFromIncident(incident)
+	simulation := &PlaybookSimulation{
+		Matches: len(playbook.IssueTypes) > 0 && contains(playbook.IssueTypes, issue.Type),
+		Steps:   make([]SimulatedStep, 0, len(playbook.Steps)),
+	}
+	for _, p := range policies {
+		if name != "" && p.policy.Playbook == name && p.policy.Action == PolicyAllow && !p.policy.Disabled && p.policy.Match.MatchesIssue(issue) {
+			simulation.AssignedBy = append(simulation.AssignedBy, p.name)
+		}
+	}
+	if !simulation.Matches && len(simulation.AssignedBy) == 0 {
+		result.info("spec.issue_types", CodeSampleNotMatched, "playbook would not run for the sample incident: its issue type is not listed and no policy assigns the playbook")
+	}
+
+	resource := incident.Target
+	if len(incident.AffectedResources) > 0 {
+		resource = incident.AffectedResources[0]
+	}
+	var total time.Duration
+	for i := range playbook.Steps {
+		step := &playbook.Steps[i]
+		simulated := SimulatedStep{
+			Name:            step.Name,
+			Action:          step.Action,
+			Params:          step.Params,
+			Timeout:         step.Timeout,
+			ContinueOnError: step.ContinueOnError,
+		}
+		if clusterActions[step.Action] && issue.Namespace != "" {
+			simulated.Target = issue.Namespace + "/" + resource
+		}
+		if timeout, err := time.ParseDuration(step.Timeout); err == nil {
+			total += timeout
+		}
+		if wait, err := time.ParseDuration(step.Params["duration"]); err == nil && step.Action == ActionWait {
+			total += wait
+		}
+		simulation.Steps = append(simulation.Steps, simulated)
+	}
+	simulation.MaxDuration = total.String()
+	return simulation
+}
+
+// policies returns the stored policies other than the one named exclude, in name
+// order. It returns nil for a nil store.
+func (s *Store) policies(exclude string) []namedPolicy {
+	if s == nil {
+		return nil
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var policies []namedPolicy
+	for _, obj := range sortedObjects(s.objects[KindPolicy]) {
+		if obj.Name != exclude {
+			copied := *obj.spec.(*Policy)
+			policies = append(policies, namedPolicy{name: obj.Name, policy: &copied})
+		}
+	}
+	return policies
+}
+
+// issueFromIncident returns the attributes policies match for an incident
+func issueFromIncident(incident *models.Incident) *models.Issue {
+	namespace, issueType := incidentAttributes(incident)
+	return &models.Issue{ID: incident.ID, Namespace: namespace, Type: issueType, Severity: string(incident.Severity)}
+}
+
+// isEmptySelector reports whether a selector matches everything
+func isEmptySelector(s *Selector) bool {
+	return len(s.Namespaces) == 0 && len(s.IssueTypes) == 0 && len(s.Severities) == 0 && len(s.Labels) == 0
+}
+
+// covers reports whether every request b matches is also matched by a
+func covers(a, b *Selector) bool {
+	coversField := func(outer, inner []string, glob bool) bool {
+		if len(outer) == 0 {
+			return true
+		}
+		if len(inner) == 0 {
+			return false
+		}
+		for _, entry := range inner {
+			if !matchAny(outer, entry, glob) {
+				return false
+			}
+		}
+		return true
+	}
+	if !coversField(a.Namespaces, b.Namespaces, true) || !coversField(a.IssueTypes, b.IssueTypes, false) ||
+		!coversField(a.Severities, b.Severities, false) {
+		return false
+	}
+	for label, value := range a.Labels {
+		if b.Labels[label] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// contains reports whether values contains value
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *CheckResult) warn(field, code, message string) {
+	r.Diagnostics = append(r.Diagnostics, Diagnostic{Severity: DiagnosticWarning, Field: field, Code: code, Message: message})
+}
+
+func (r *CheckResult) info(field, code, message string) {
+	r.Diagnostics = append(r.Diagnostics, Diagnostic{Severity: DiagnosticInfo, Field: field, Code: code, Message: message})
+}
@@ -0,0 +1,106 @@
+package runtimeconfig
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+func diagnosticCodes(result *CheckResult) map[string]DiagnosticSeverity {
+	codes := make(map[string]DiagnosticSeverity, len(result.Diagnostics))
+	for _, d := range result.Diagnostics {
+		codes[d.Code] = d.Severity
+	}
+	return codes
+}
+
+func TestCheckPolicy(t *testing.T) {
+	store := NewStore(t.TempDir())
+	_, err := store.Create(KindPolicy, "10-deny-prod", json.RawMessage(`{"match":{"namespaces":["prod-*"]},"action":"deny","reason":"change freeze"}`))
+	require.NoError(t, err)
+
+	// Type errors are reported as error diagnostics without storing anything
+	result := CheckPolicy(store, "Bad_Name", json.RawMessage(`{"action":"block"}`), nil)
+	assert.False(t, result.Valid)
+	fields := map[string]DiagnosticSeverity{}
+	for _, d := range result.Diagnostics {
+		fields[d.Field] = d.Severity
+	}
+	assert.Equal(t, DiagnosticError, fields["name"])
+	assert.Equal(t, DiagnosticError, fields["spec.action"])
+
+	// A later policy covered by an earlier one never applies
+	result = CheckPolicy(store, "20-allow-payments", json.RawMessage(`{"match":{"namespaces":["prod-payments"]},"action":"allow","playbook":"restart"}`), nil)
+	assert.True(t, result.Valid)
+	codes := diagnosticCodes(result)
+	assert.Equal(t, DiagnosticWarning, codes[CodeShadowed])
+	assert.Equal(t, DiagnosticWarning, codes[CodeUnknownPlaybook])
+
+	// Evaluated first it is not shadowed, and decides for matching incidents
+	incident := &models.Incident{Target: "prod-payments", Severity: models.IncidentSeverityHigh, Labels: map[string]string{"alertname": "pod_crash_loop"}}
+	result = CheckPolicy(store, "05-allow-payments", json.RawMessage(`{"match":{"namespaces":["prod-payments"]},"action":"allow"}`), incident)
+	assert.NotContains(t, diagnosticCodes(result), CodeShadowed)
+	require.NotNil(t, result.Policy)
+	assert.Equal(t, &PolicySimulation{Matches: true, Decision: PolicyAllow, DecidedBy: "05-allow-payments"}, result.Policy)
+
+	// An unnamed policy is evaluated after the stored ones
+	result = CheckPolicy(store, "", json.RawMessage(`{"match":{"issue_types":["pod_crash_loop"]},"action":"allow"}`), incident)
+	assert.Equal(t, PolicyDeny, result.Policy.Decision)
+	assert.Equal(t, "10-deny-prod", result.Policy.DecidedBy)
+	assert.Equal(t, DiagnosticWarning, diagnosticCodes(result)[CodeDecidedByOtherPolicy])
+
+	result = CheckPolicy(store, "30-deny-all", json.RawMessage(`{"action":"deny","playbook":"restart"}`), &models.Incident{Target: "staging"})
+	codes = diagnosticCodes(result)
+	assert.Equal(t, DiagnosticWarning, codes[CodeMatchesEverything])
+	assert.Equal(t, DiagnosticWarning, codes[CodeIgnoredPlaybook])
+	assert.Equal(t, PolicyDeny, result.Policy.Decision)
+
+	// Without a store references are not checked
+	result = CheckPolicy(nil, "allow", json.RawMessage(`{"match":{"severities":["low"]},"action":"allow","playbook":"restart"}`), &models.Incident{Severity: models.IncidentSeverityHigh})
+	codes = diagnosticCodes(result)
+	assert.Equal(t, DiagnosticInfo, codes[CodeReferencesUnchecked])
+	assert.NotContains(t, codes, CodeUnknownPlaybook)
+	assert.Equal(t, DecisionNone, result.Policy.Decision)
+	assert.Equal(t, DiagnosticInfo, codes[CodeSampleNotMatched])
+}
+
+func TestCheckPlaybook(t *testing.T) {
+	store := NewStore(t.TempDir())
+	_, err := store.Create(KindPolicy, "10-allow-payments", json.RawMessage(`{"match":{"namespaces":["payments"]},"action":"allow","playbook":"scale-down"}`))
+	require.NoError(t, err)
+
+	result := CheckPlaybook(store, "scale-down", json.RawMessage(`{"steps":[{"name":"scale","action":"scale"}]}`), nil)
+	assert.False(t, result.Valid)
+	assert.Equal(t, DiagnosticError, diagnosticCodes(result)["required"])
+
+	spec := json.RawMessage(`{"steps":[
+		{"name":"scale","action":"scale","params":{"replicas":"0","force":"true"},"timeout":"2h"},
+		{"name":"settle","action":"wait","params":{"duration":"5m"}},
+		{"name":"page","action":"notify","params":{"message":"scaled down"},"continue_on_error":true}]}`)
+	incident := &models.Incident{Target: "payments", AffectedResources: []string{"deployment/api"}, Labels: map[string]string{"alertname": "memory_pressure"}}
+	result = CheckPlaybook(store, "scale-down", spec, incident)
+	assert.True(t, result.Valid)
+	codes := diagnosticCodes(result)
+	assert.Equal(t, DiagnosticInfo, codes[CodeNoIssueTypes])
+	assert.Equal(t, DiagnosticWarning, codes[CodeUnknownParam])
+	assert.Equal(t, DiagnosticWarning, codes[CodeScaleToZero])
+	assert.Equal(t, DiagnosticWarning, codes[CodeLongDuration])
+	assert.Equal(t, DiagnosticInfo, codes[CodeTrailingContinue])
+	assert.NotContains(t, codes, CodeUnreferenced)
+
+	require.NotNil(t, result.Playbook)
+	assert.False(t, result.Playbook.Matches)
+	assert.Equal(t, []string{"10-allow-payments"}, result.Playbook.AssignedBy)
+	require.Len(t, result.Playbook.Steps, 3)
+	assert.Equal(t, "payments/deployment/api", result.Playbook.Steps[0].Target)
+	assert.Empty(t, result.Playbook.Steps[1].Target, "waits do not touch the cluster")
+	assert.Equal(t, "2h5m0s", result.Playbook.MaxDuration)
+
+	// A playbook nothing assigns never runs
+	result = CheckPlaybook(store, "orphan", json.RawMessage(`{"steps":[{"name":"restart","action":"restart_pod","timeout":"5m"}]}`), nil)
+	assert.Equal(t, DiagnosticWarning, diagnosticCodes(result)[CodeUnreferenced])
+}
@@ -0,0 +1,632 @@
+// Package runtimeconfig stores engine configuration that administrators manage at
+// runtime through the admin API instead of ConfigMaps: remediation policies,
+// notification silences, scan schedules, notification channels, playbooks and
+// escalation ladders.
+//
+// Every object has a kind, a name unique within its kind, a kind-specific spec and a
+// resource version. Updates and deletions may name the resource version they were
+// based on and fail with ErrConflict when the object changed in the meantime, so two
+// administrators editing the same object cannot silently overwrite each other.
+package runtimeconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/notification"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/api/validation"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// Kind identifies a type of runtime configuration object
+type Kind string
+
+// Object kinds
+const (
+	KindPolicy              Kind = "policies"
+	KindSilence             Kind = "silences"
+	KindScanSchedule        Kind = "scan-schedules"
+	KindNotificationChannel Kind = "notification-channels"
+	KindPlaybook            Kind = "playbooks"
+	KindEscalationLadder    Kind = "escalation-ladders"
+)
+
+// Kinds returns every object kind
+func Kinds() []Kind {
+	return []Kind{KindPolicy, KindSilence, KindScanSchedule, KindNotificationChannel, KindPlaybook, KindEscalationLadder}
+}
+
+// newSpec returns an empty spec of a kind, or nil for unknown kinds
+func newSpec(kind Kind) Spec {
+	switch kind {
+	case KindPolicy:
+		return &Policy{}
+	case KindSilence:
+		return &Silence{}
+	case KindScanSchedule:
+		return &ScanSchedule{}
+	case KindNotificationChannel:
+		return &NotificationChannel{}
+	case KindPlaybook:
+		return &Playbook{}
+	case KindEscalationLadder:
+		return &EscalationLadder{}
+	}
+	return nil
+}
+
+// Spec is the kind-specific part of an object
+type Spec interface {
+	// Validate returns a validation.Errors with field paths relative to the spec
+	Validate() error
+}
+
+// defaulter is implemented by specs that fill in omitted fields when stored
+type defaulter interface {
+	setDefaults(now time.Time)
+}
+
+// RedactedSecret replaces secrets in objects returned by the store. Updates that send
+// it back, or omit the secret, keep the stored secret.
+const RedactedSecret = "********"
+
+// secretHolder is implemented by specs with write-only secrets
+type secretHolder interface {
+	// keepSecrets copies secrets the update did not change from the previous spec
+	keepSecrets(previous Spec)
+	// redacted returns a copy of the spec with its secrets replaced by RedactedSecret
+	redacted() Spec
+}
+
+// Object is a stored configuration object
+type Object struct {
+	Kind            Kind            `json:"kind"`
+	Name            string          `json:"name"`
+	ResourceVersion string          `json:"resource_version"`
+	CreatedAt       time.Time       `json:"created_at"`
+	UpdatedAt       time.Time       `json:"updated_at"`
+	Spec            json.RawMessage `json:"spec"`
+
+	// spec is the decoded Spec
+	spec Spec
+}
+
+// namePattern restricts names to DNS labels, so they are safe in URLs and logs
+var namePattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// validateName checks an object name
+func validateName(name string) error {
+	switch {
+	case name == "":
+		return validation.New("name", validation.ConstraintRequired, nil, "name is required")
+	case len(name) > 63:
+		return validation.New("name", validation.ConstraintLength, len(name), "name must not exceed 63 characters")
+	case !namePattern.MatchString(name):
+		return validation.New("name", validation.ConstraintFormat, name,
+			"name must consist of lowercase letters, digits and '-', and start and end with a letter or digit")
+	}
+	return nil
+}
+
+// Selector matches incidents and remediation requests. Empty fields match anything;
+// a value matches a field if it matches any of its entries.
+type Selector struct {
+	// Namespaces are namespace names or glob patterns such as "team-*"
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// IssueTypes are issue types such as "pod_crash_loop"; for incidents the
+	// issue_type label is used, or the alertname label if there is none
+	IssueTypes []string `json:"issue_types,omitempty"`
+
+	// Severities are incident severities (low, medium, high, critical)
+	Severities []string `json:"severities,omitempty"`
+
+	// Labels must all be present on an incident with these values
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// validate checks the selector's patterns and severities
+func (s *Selector) validate(errs *validation.Errors, field string) {
+	for i, pattern := range s.Namespaces {
+		if _, err := path.Match(pattern, ""); err != nil || pattern == "" {
+			errs.Add(fmt.Sprintf("%s.namespaces[%d]", field, i), validation.ConstraintFormat, pattern,
+				fmt.Sprintf("%s.namespaces[%d] must be a namespace name or glob pattern", field, i))
+		}
+	}
+	for i, severity := range s.Severities {
+		if !models.IsValidSeverity(severity) {
+			errs.Add(fmt.Sprintf("%s.severities[%d]", field, i), validation.ConstraintEnum, severity,
+				fmt.Sprintf("%s.severities[%d] must be one of: low, medium, high, critical", field, i))
+		}
+	}
+}
+
+// matches reports whether the selector matches the given attributes
+func (s *Selector) matches(namespace, issueType, severity string, labels map[string]string) bool {
+	if len(s.Namespaces) > 0 && !matchAny(s.Namespaces, namespace, true) {
+		return false
+	}
+	if len(s.IssueTypes) > 0 && !matchAny(s.IssueTypes, issueType, false) {
+		return false
+	}
+	if len(s.Severities) > 0 && !matchAny(s.Severities, severity, false) {
+		return false
+	}
+	for name, value := range s.Labels {
+		if labels[name] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// MatchesIncident reports whether the selector matches an incident
+func (s *Selector) MatchesIncident(incident *models.Incident) bool {
+	namespace, issueType := incidentAttributes(incident)
+	return s.matches(namespace, issueType, string(incident.Severity), incident.Labels)
+}
+
+// incidentAttributes returns an incident's namespace (its namespace label, or else its
+// target) and issue type (its issue_type label, or else its alertname label)
+func incidentAttributes(incident *models.Incident) (namespace, issueType string) {
+	namespace = incident.Labels["namespace"]
+	if namespace == "" {
+		namespace = incident.Target
+	}
+	issueType = incident.Labels["issue_type"]
+	if issueType == "" {
+		issueType = incident.Labels["alertname"]
+	}
+	return namespace, issueType
+}
+
+// MatchesIssue reports whether the selector matches a remediation request's issue.
+// Issues have no labels, so selectors with labels never match them.
+func (s *Selector) MatchesIssue(issue *models.Issue) bool {
+	if len(s.Labels) > 0 {
+		return false
+	}
+	return s.matches(issue.Namespace, issue.Type, issue.Severity, nil)
+}
+
+// matchAny reports whether value matches one of the entries, as a glob if glob is set
+func matchAny(entries []string, value string, glob bool) bool {
+	for _, entry := range entries {
+		if entry == value {
+			return true
+		}
+		if glob {
+			if ok, _ := path.Match(entry, value); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Policy actions
+const (
+	PolicyAllow = "allow"
+	PolicyDeny  = "deny"
+)
+
+// Policy decides whether automated remediation may run for matching requests.
+// Policies are evaluated in name order and the first enabled match decides.
+type Policy struct {
+	Description string `json:"description,omitempty"`
+
+	// Match selects the remediation requests the policy applies to
+	Match Selector `json:"match"`
+
+	// Action is allow or deny
+	Action string `json:"action"`
+
+	// Playbook names the playbook to run for matching requests
+	Playbook string `json:"playbook,omitempty"`
+
+	// Reason is reported when the policy denies a request
+	Reason string `json:"reason,omitempty"`
+
+	Disabled bool `json:"disabled,omitempty"`
+}
+
+// Validate checks the policy
+func (p *Policy) Validate() error {
+	var errs validation.Errors
+	p.Match.validate(&errs, "match")
+	if len(p.Match.Labels) > 0 {
+		errs.Add("match.labels", validation.ConstraintExclusive, nil,
+			"match.labels cannot be used in policies because remediation requests have no labels")
+	}
+	if fieldErr := validation.OneOf("action", p.Action, PolicyAllow, PolicyDeny)(); fieldErr != nil {
+		errs = append(errs, *fieldErr)
+	}
+	if p.Playbook != "" {
+		if err := validateName(p.Playbook); err != nil {
+			errs.Add("playbook", validation.ConstraintFormat, p.Playbook, "playbook must be a playbook name")
+		}
+	}
+	return errs.Err()
+}
+
+// Silence suppresses notifications for matching incidents between StartsAt and EndsAt
+type Silence struct {
+	Match Selector `json:"match"`
+
+	// StartsAt defaults to the time the silence is created
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+
+	// Comment explains the silence, e.g. a maintenance ticket
+	Comment   string `json:"comment"`
+	CreatedBy string `json:"created_by,omitempty"`
+}
+
+func (s *Silence) setDefaults(now time.Time) {
+	if s.StartsAt.IsZero() {
+		s.StartsAt = now.UTC()
+	}
+}
+
+// Validate checks the silence
+func (s *Silence) Validate() error {
+	var errs validation.Errors
+	s.Match.validate(&errs, "match")
+	if len(s.Match.Namespaces) == 0 && len(s.Match.IssueTypes) == 0 && len(s.Match.Severities) == 0 && len(s.Match.Labels) == 0 {
+		errs.Add("match", validation.ConstraintRequired, nil, "match must select incidents; a silence cannot match everything")
+	}
+	if s.EndsAt.IsZero() {
+		errs.Add("ends_at", validation.ConstraintRequired, nil, "ends_at is required")
+	} else if !s.StartsAt.IsZero() && !s.EndsAt.After(s.StartsAt) {
+		errs.Add("ends_at", validation.ConstraintRange, s.EndsAt, "ends_at must be after starts_at")
+	}
+	if s.Comment == "" {
+		errs.Add("comment", validation.ConstraintRequired, nil, "comment is required")
+	}
+	return errs.Err()
+}
+
+// Active reports whether the silence is in effect at now
+func (s *Silence) Active(now time.Time) bool {
+	return !now.Before(s.StartsAt) && now.Before(s.EndsAt)
+}
+
+// Scan schedule bounds
+const (
+	// MinScanInterval is the shortest interval a scan schedule may use
+	MinScanInterval = time.Minute
+
+	// MaxScanNamespaces bounds the namespaces of a scan schedule
+	MaxScanNamespaces = 100
+)
+
+// ScanSchedule is a set of namespaces scanned for anomalies at an interval
+type ScanSchedule struct {
+	Namespaces []string `json:"namespaces"`
+
+	// Interval between scans, e.g. "15m"
+	Interval string `json:"interval"`
+
+	// Threshold is the anomaly score reported as an anomaly (default 0.7)
+	Threshold float64 `json:"threshold,omitempty"`
+
+	Disabled bool `json:"disabled,omitempty"`
+}
+
+func (s *ScanSchedule) setDefaults(time.Time) {
+	validation.Default(&s.Threshold, validation.DefaultThreshold)
+}
+
+// Validate checks the scan schedule
+func (s *ScanSchedule) Validate() error {
+	var errs validation.Errors
+	if len(s.Namespaces) == 0 {
+		errs.Add("namespaces", validation.ConstraintRequired, nil, "at least one namespace is required")
+	} else if len(s.Namespaces) > MaxScanNamespaces {
+		errs.Add("namespaces", validation.ConstraintLength, len(s.Namespaces),
+			fmt.Sprintf("namespaces must not list more than %d namespaces", MaxScanNamespaces))
+	}
+	for i, namespace := range s.Namespaces {
+		if namespace == "" {
+			errs.Add(fmt.Sprintf("namespaces[%d]", i), validation.ConstraintRequired, nil, fmt.Sprintf("namespaces[%d] must not be empty", i))
+		}
+	}
+	if s.Interval == "" {
+		errs.Add("interval", validation.ConstraintRequired, nil, "interval is required")
+	} else if interval, err := time.ParseDuration(s.Interval); err != nil {
+		errs.Add("interval", validation.ConstraintFormat, s.Interval, "interval must be a duration such as 15m")
+	} else if interval < MinScanInterval {
+		errs.Add("interval", validation.ConstraintRange, s.Interval, fmt.Sprintf("interval must be at least %s", MinScanInterval))
+	}
+	if fieldErr := validation.Threshold("threshold", s.Threshold)(); fieldErr != nil {
+		errs = append(errs, *fieldErr)
+	}
+	return errs.Err()
+}
+
+// Notification channel types
+const (
+	ChannelWebhook = "webhook"
+)
+
+// NotificationChannel is an additional destination for incident notifications
+type NotificationChannel struct {
+	// Type is the channel type; only webhook is supported
+	Type string `json:"type"`
+
+	// URL, Secret, Headers and Events configure a webhook channel as in WEBHOOK_FILE
+	URL     string                   `json:"url"`
+	Secret  string                   `json:"secret,omitempty"`
+	Headers map[string]string        `json:"headers,omitempty"`
+	Events  []notification.EventType `json:"events"`
+
+	// MaxPerHour and QuietHours throttle the channel as in WEBHOOK_FILE, batching held
+	// events into digests
+	MaxPerHour int                      `json:"max_per_hour,omitempty"`
+	QuietHours *notification.QuietHours `json:"quiet_hours,omitempty"`
+
+	Disabled bool `json:"disabled,omitempty"`
+}
+
+// Validate checks the channel
+func (c *NotificationChannel) Validate() error {
+	var errs validation.Errors
+	if fieldErr := validation.OneOf("type", c.Type, ChannelWebhook)(); fieldErr != nil {
+		errs = append(errs, *fieldErr)
+	}
+	if !strings.HasPrefix(c.URL, "http://") && !strings.HasPrefix(c.URL, "https://") {
+		errs.Add("url", validation.ConstraintFormat, c.URL, "url must start with http:// or https://")
+	}
+	if len(c.Events) == 0 {
+		errs.Add("events", validation.ConstraintRequired, nil, "at least one event type is required")
+	}
+	for i, event := range c.Events {
+		// Validate the event type alone, with a target that is otherwise valid
+		probe := notification.Target{Name: "probe", URL: "https://probe", Events: []notification.EventType{event}}
+		if err := probe.Validate(); err != nil {
+			errs.Add(fmt.Sprintf("events[%d]", i), validation.ConstraintEnum, event, fmt.Sprintf("events[%d] is not a known incident event type", i))
+		}
+	}
+	if c.MaxPerHour < 0 {
+		errs.Add("max_per_hour", validation.ConstraintRange, c.MaxPerHour, "max_per_hour cannot be negative")
+	}
+	if c.QuietHours != nil {
+		if err := c.QuietHours.Validate(); err != nil {
+			errs.Add("quiet_hours", validation.ConstraintFormat, c.QuietHours, "quiet_hours: "+err.Error())
+		}
+	}
+	return errs.Err()
+}
+
+func (c *NotificationChannel) keepSecrets(previous Spec) {
+	if c.Secret == "" || c.Secret == RedactedSecret {
+		c.Secret = previous.(*NotificationChannel).Secret
+	}
+}
+
+func (c *NotificationChannel) redacted() Spec {
+	copied := *c
+	if copied.Secret != "" {
+		copied.Secret = RedactedSecret
+	}
+	return &copied
+}
+
+// target returns the channel as a webhook target
+func (c *NotificationChannel) target(name string) notification.Target {
+	return notification.Target{
+		Name: name, URL: c.URL, Secret: c.Secret, Headers: c.Headers, Events: c.Events,
+		MaxPerHour: c.MaxPerHour, QuietHours: c.QuietHours,
+	}
+}
+
+// Playbook step actions
+const (
+	ActionRestartPod     = "restart_pod"
+	ActionRolloutRestart = "rollout_restart"
+	ActionScale          = "scale"
+	ActionRollback       = "rollback"
+	ActionSyncArgoCD     = "sync_argocd"
+	ActionWait           = "wait"
+	ActionNotify         = "notify"
+	ActionManual         = "manual"
+)
+
+// PlaybookActions returns the actions a playbook step can run
+func PlaybookActions() []string {
+	return []string{
+		ActionRestartPod, ActionRolloutRestart, ActionScale, ActionRollback,
+		ActionSyncArgoCD, ActionWait, ActionNotify, ActionManual,
+	}
+}
+
+// MaxPlaybookSteps bounds the steps of a playbook
+const MaxPlaybookSteps = 50
+
+// Playbook is an ordered list of remediation steps for an issue
+type Playbook struct {
+	Description string `json:"description,omitempty"`
+
+	// IssueTypes are the issue types the playbook remediates
+	IssueTypes []string `json:"issue_types,omitempty"`
+
+	Steps []PlaybookStep `json:"steps"`
+}
+
+// PlaybookStep is a single playbook action
+type PlaybookStep struct {
+	Name   string            `json:"name"`
+	Action string            `json:"action"`
+	Params map[string]string `json:"params,omitempty"`
+
+	// Timeout bounds the step, e.g. "5m"
+	Timeout string `json:"timeout,omitempty"`
+
+	// ContinueOnError runs the next step even if this one fails
+	ContinueOnError bool `json:"continue_on_error,omitempty"`
+}
+
+// requiredParams lists the parameters each action needs
+var requiredParams = map[string][]string{
+	ActionScale:  {"replicas"},
+	ActionWait:   {"duration"},
+	ActionNotify: {"message"},
+}
+
+// Validate checks the playbook
+func (p *Playbook) Validate() error {
+	var errs validation.Errors
+	if len(p.Steps) == 0 {
+		errs.Add("steps", validation.ConstraintRequired, nil, "at least one step is required")
+	} else if len(p.Steps) > MaxPlaybookSteps {
+		errs.Add("steps", validation.ConstraintLength, len(p.Steps), fmt.Sprintf("steps must not exceed %d", MaxPlaybookSteps))
+	}
+
+	seen := make(map[string]bool, len(p.Steps))
+	for i := range p.Steps {
+		step := &p.Steps[i]
+		field := fmt.Sprintf("steps[%d]", i)
+		if step.Name == "" {
+			errs.Add(field+".name", validation.ConstraintRequired, nil, field+".name is required")
+		} else if seen[step.Name] {
+			errs.Add(field+".name", validation.ConstraintFormat, step.Name, fmt.Sprintf("%s.name %q is used by an earlier step", field, step.Name))
+		}
+		seen[step.Name] = true
+
+		if fieldErr := validation.OneOf(field+".action", step.Action, PlaybookActions()...)(); fieldErr != nil {
+			errs = append(errs, *fieldErr)
+		}
+		for _, param := range requiredParams[step.Action] {
+			if step.Params[param] == "" {
+				errs.Add(field+".params."+param, validation.ConstraintRequired, nil,
+					fmt.Sprintf("%s.params.%s is required for action %s", field, param, step.Action))
+			}
+		}
+		if replicas, ok := step.Params["replicas"]; ok && step.Action == ActionScale {
+			if n, err := strconv.Atoi(replicas); err != nil || n < 0 {
+				errs.Add(field+".params.replicas", validation.ConstraintFormat, replicas, field+".params.replicas must be a non-negative integer")
+			}
+		}
+		if duration, ok := step.Params["duration"]; ok && step.Action == ActionWait {
+			if _, err := time.ParseDuration(duration); err != nil {
+				errs.Add(field+".params.duration", validation.ConstraintFormat, duration, field+".params.duration must be a duration such as 2m")
+			}
+		}
+		if step.Timeout != "" {
+			if timeout, err := time.ParseDuration(step.Timeout); err != nil || timeout <= 0 {
+				errs.Add(field+".timeout", validation.ConstraintFormat, step.Timeout, field+".timeout must be a positive duration such as 5m")
+			}
+		}
+	}
+	return errs.Err()
+}
+
+// Escalation actions
+const (
+	EscalationRestart = "restart"
+	EscalationScale   = "scale"
+	EscalationPage    = "page"
+)
+
+// EscalationActions returns the actions an escalation step can take
+func EscalationActions() []string {
+	return []string{EscalationRestart, EscalationScale, EscalationPage}
+}
+
+// Escalation ladder bounds
+const (
+	// DefaultEscalationWindow is the recurrence window of a ladder that sets none
+	DefaultEscalationWindow = "1h"
+
+	// MaxEscalationSteps bounds the steps of a ladder
+	MaxEscalationSteps = 10
+
+	// MaxEscalationScale bounds the replicas a scale step adds
+	MaxEscalationScale = 10
+)
+
+// EscalationLadder escalates the remediation of an incident that keeps recurring.
+// The first occurrence takes the first step; each recurrence within the window of
+// the previous remediation takes the next step, and the last step repeats. The
+// window doubles with every step taken, so the further an incident escalated, the
+// longer it must stay quiet before the ladder starts over.
+type EscalationLadder struct {
+	Description string `json:"description,omitempty"`
+
+	// IssueTypes are the issue types the ladder applies to
+	IssueTypes []string `json:"issue_types"`
+
+	// Window is the recurrence window after the first step, e.g. "1h"
+	Window string `json:"window"`
+
+	Steps []EscalationStep `json:"steps"`
+
+	Disabled bool `json:"disabled,omitempty"`
+}
+
+// EscalationStep is a rung of an escalation ladder
+type EscalationStep struct {
+	// Action is restart (the regular remediation), scale or page
+	Action string `json:"action"`
+
+	// Replicas is the number of replicas a scale step adds (default 1)
+	Replicas int `json:"replicas,omitempty"`
+
+	// Message is added to the incident when a page step pages
+	Message string `json:"message,omitempty"`
+}
+
+func (l *EscalationLadder) setDefaults(time.Time) {
+	if l.Window == "" {
+		l.Window = DefaultEscalationWindow
+	}
+	for i := range l.Steps {
+		if l.Steps[i].Action == EscalationScale {
+			validation.Default(&l.Steps[i].Replicas, 1)
+		}
+	}
+}
+
+// Validate checks the ladder
+func (l *EscalationLadder) Validate() error {
+	var errs validation.Errors
+	if len(l.IssueTypes) == 0 {
+		errs.Add("issue_types", validation.ConstraintRequired, nil, "at least one issue type is required")
+	}
+	if l.Window != "" {
+		if window, err := time.ParseDuration(l.Window); err != nil || window < time.Minute {
+			errs.Add("window", validation.ConstraintFormat, l.Window, "window must be a duration of at least 1m")
+		}
+	}
+	if len(l.Steps) == 0 {
+		errs.Add("steps", validation.ConstraintRequired, nil, "at least one step is required")
+	} else if len(l.Steps) > MaxEscalationSteps {
+		errs.Add("steps", validation.ConstraintLength, len(l.Steps), fmt.Sprintf("steps must not exceed %d", MaxEscalationSteps))
+	}
+	for i := range l.Steps {
+		step := &l.Steps[i]
+		field := fmt.Sprintf("steps[%d]", i)
+		if fieldErr := validation.OneOf(field+".action", step.Action, EscalationActions()...)(); fieldErr != nil {
+			errs = append(errs, *fieldErr)
+		}
+		if step.Replicas < 0 || step.Replicas > MaxEscalationScale {
+			errs.Add(field+".replicas", validation.ConstraintRange, step.Replicas,
+				fmt.Sprintf("%s.replicas must be between 1 and %d", field, MaxEscalationScale))
+		}
+	}
+	return errs.Err()
+}
+
+// WindowDuration returns the parsed recurrence window
+func (l *EscalationLadder) WindowDuration() time.Duration {
+	window, err := time.ParseDuration(l.Window)
+	if err != nil {
+		window, _ = time.ParseDuration(DefaultEscalationWindow)
+	}
+	return window
+}
@@ -0,0 +1,540 @@
+package runtimeconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/notification"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/api/validation"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// DefaultMaxObjects bounds the number of objects of each kind
+const DefaultMaxObjects = 200
+
+// Store errors
+var (
+	// ErrUnknownKind is returned for kinds other than those listed by Kinds
+	ErrUnknownKind = errors.New("unknown configuration kind")
+
+	// ErrNotFound is returned when an object does not exist
+	ErrNotFound = errors.New("configuration object not found")
+
+	// ErrAlreadyExists is returned when creating an object whose name is taken
+	ErrAlreadyExists = errors.New("configuration object already exists")
+
+	// ErrConflict is returned when an object changed since the given resource version
+	ErrConflict = errors.New("configuration object was modified")
+
+	// ErrLimitReached is returned when a kind already holds DefaultMaxObjects objects
+	ErrLimitReached = errors.New("configuration object limit reached")
+)
+
+// storeFile is the on-disk format of the store
+type storeFile struct {
+	ResourceVersion int64     `json:"resource_version"`
+	Objects         []*Object `json:"objects"`
+}
+
+// Store persists runtime configuration objects as JSON in the data directory
+type Store struct {
+	objects  map[Kind]map[string]*Object
+	version  int64
+	mu       sync.RWMutex
+	dataFile string
+	now      func() time.Time
+}
+
+// NewStore creates a configuration store in dataDir (DATA_DIR or /app/data if empty)
+func NewStore(dataDir string) *Store {
+	if dataDir == "" {
+		dataDir = os.Getenv("DATA_DIR")
+	}
+	if dataDir == "" {
+		dataDir = "/app/data"
+	}
+
+	if err := os.MkdirAll(dataDir, 0o755); err != nil {
+		fmt.Printf("Warning: Could not create data directory %s: %v\n", dataDir, err)
+	}
+	store := &Store{
+		objects:  make(map[Kind]map[string]*Object),
+		dataFile: filepath.Join(dataDir, "runtime_config.json"),
+		now:      time.Now,
+	}
+	for _, kind := range Kinds() {
+		store.objects[kind] = make(map[string]*Object)
+	}
+
+	if err := store.load(); err != nil {
+		fmt.Printf("Warning: Could not load runtime configuration from disk: %v\n", err)
+	} else {
+		fmt.Printf("Loaded runtime configuration version %d from %s\n", store.version, store.dataFile)
+	}
+
+	return store
+}
+
+// load reads the objects from the JSON file, skipping objects that no longer decode
+func (s *Store) load() error {
+	data, err := os.ReadFile(s.dataFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read data file: %w", err)
+	}
+
+	var file storeFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("failed to unmarshal runtime configuration: %w", err)
+	}
+	s.version = file.ResourceVersion
+	for _, obj := range file.Objects {
+		objects, ok := s.objects[obj.Kind]
+		if !ok {
+			fmt.Printf("Warning: Skipping runtime configuration object %s of unknown kind %s\n", obj.Name, obj.Kind)
+			continue
+		}
+		spec, err := decodeSpec(obj.Kind, obj.Spec, time.Time{})
+		if err != nil {
+			fmt.Printf("Warning: Skipping invalid runtime configuration object %s/%s: %v\n", obj.Kind, obj.Name, err)
+			continue
+		}
+		obj.spec = spec
+		objects[obj.Name] = obj
+	}
+
+	return nil
+}
+
+// save writes all objects to the JSON file. Callers must hold s.mu.
+func (s *Store) save() error {
+	dir := filepath.Dir(s.dataFile)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	file := storeFile{ResourceVersion: s.version, Objects: []*Object{}}
+	for _, kind := range Kinds() {
+		file.Objects = append(file.Objects, sortedObjects(s.objects[kind])...)
+	}
+	data, err := json.Marshal(file)
+	if err != nil {
+		return fmt.Errorf("failed to marshal runtime configuration: %w", err)
+	}
+
+	// Write to temp file first, then rename (atomic); channels may hold webhook secrets
+	tmpFile := s.dataFile + ".tmp"
+	if err := os.WriteFile(tmpFile, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write temp file: %w", err)
+	}
+	if err := os.Rename(tmpFile, s.dataFile); err != nil {
+		return fmt.Errorf("failed to rename temp file: %w", err)
+	}
+
+	return nil
+}
+
+// decodeSpec decodes and validates a spec, rejecting unknown fields so typos are
+// reported instead of ignored. A non-zero now fills in omitted fields first.
+func decodeSpec(kind Kind, raw json.RawMessage, now time.Time) (Spec, error) {
+	spec := newSpec(kind)
+	if spec == nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKind, kind)
+	}
+	if len(raw) == 0 {
+		return nil, validation.New("spec", validation.ConstraintRequired, nil, "spec is required")
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(spec); err != nil {
+		if fieldErrs := validation.DecodeFields(err); fieldErrs != nil {
+			return nil, validation.Nest("spec", validation.Errors(fieldErrs))
+		}
+		return nil, validation.New("spec", validation.ConstraintFormat, nil, "invalid spec: "+err.Error())
+	}
+	if d, ok := spec.(defaulter); ok && !now.IsZero() {
+		d.setDefaults(now)
+	}
+	if err := spec.Validate(); err != nil {
+		return nil, validation.Nest("spec", err)
+	}
+	return spec, nil
+}
+
+// prepare decodes, defaults and re-encodes a spec for storage
+func (s *Store) prepare(kind Kind, raw json.RawMessage) (Spec, json.RawMessage, error) {
+	spec, err := decodeSpec(kind, raw, s.now())
+	if err != nil {
+		return nil, nil, err
+	}
+	encoded, err := json.Marshal(spec)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal spec: %w", err)
+	}
+	return spec, encoded, nil
+}
+
+// nextVersion increments the store's resource version. Callers must hold s.mu.
+func (s *Store) nextVersion() string {
+	s.version++
+	return strconv.FormatInt(s.version, 10)
+}
+
+// Create stores a new object
+func (s *Store) Create(kind Kind, name string, raw json.RawMessage) (*Object, error) {
+	spec, encoded, err := s.prepare(kind, raw)
+	if err != nil && validation.Fields(err) == nil {
+		return nil, err
+	}
+	// Report an invalid name together with invalid spec fields
+	if errs := append(validation.Fields(validateName(name)), validation.Fields(err)...); len(errs) > 0 {
+		return nil, validation.Errors(errs)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	objects := s.objects[kind]
+	if _, exists := objects[name]; exists {
+		return nil, fmt.Errorf("%w: %s/%s", ErrAlreadyExists, kind, name)
+	}
+	if len(objects) >= DefaultMaxObjects {
+		return nil, fmt.Errorf("%w: at most %d %s", ErrLimitReached, DefaultMaxObjects, kind)
+	}
+
+	now := s.now().UTC()
+	obj := &Object{
+		Kind:            kind,
+		Name:            name,
+		ResourceVersion: s.nextVersion(),
+		CreatedAt:       now,
+		UpdatedAt:       now,
+		Spec:            encoded,
+		spec:            spec,
+	}
+	objects[name] = obj
+	if err := s.save(); err != nil {
+		delete(objects, name)
+		return nil, fmt.Errorf("failed to persist configuration object: %w", err)
+	}
+	return obj.public(), nil
+}
+
+// Get returns an object
+func (s *Store) Get(kind Kind, name string) (*Object, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	objects, ok := s.objects[kind]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKind, kind)
+	}
+	obj, ok := objects[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s/%s", ErrNotFound, kind, name)
+	}
+	return obj.public(), nil
+}
+
+// List returns the objects of a kind, ordered by name
+func (s *Store) List(kind Kind) ([]*Object, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	objects, ok := s.objects[kind]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKind, kind)
+	}
+	sorted := sortedObjects(objects)
+	for i, obj := range sorted {
+		sorted[i] = obj.public()
+	}
+	return sorted, nil
+}
+
+// Counts returns the number of objects of each kind
+func (s *Store) Counts() map[Kind]int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	counts := make(map[Kind]int, len(s.objects))
+	for kind, objects := range s.objects {
+		counts[kind] = len(objects)
+	}
+	return counts
+}
+
+// Update replaces an object's spec. It fails with ErrConflict unless resourceVersion
+// is the object's current resource version.
+func (s *Store) Update(kind Kind, name, resourceVersion string, raw json.RawMessage) (*Object, error) {
+	spec, encoded, err := s.prepare(kind, raw)
+	if err != nil {
+		return nil, err
+	}
+	if resourceVersion == "" {
+		return nil, validation.New("resource_version", validation.ConstraintRequired, nil, "resource_version is required")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	obj, ok := s.objects[kind][name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s/%s", ErrNotFound, kind, name)
+	}
+	if obj.ResourceVersion != resourceVersion {
+		return nil, fmt.Errorf("%w: %s/%s is at resource_version %s, not %s", ErrConflict, kind, name, obj.ResourceVersion, resourceVersion)
+	}
+
+	if holder, ok := spec.(secretHolder); ok {
+		holder.keepSecrets(obj.spec)
+		if encoded, err = json.Marshal(spec); err != nil {
+			return nil, fmt.Errorf("failed to marshal spec: %w", err)
+		}
+	}
+
+	previous := *obj
+	obj.Spec = encoded
+	obj.spec = spec
+	obj.UpdatedAt = s.now().UTC()
+	obj.ResourceVersion = s.nextVersion()
+	if err := s.save(); err != nil {
+		*obj = previous
+		return nil, fmt.Errorf("failed to persist configuration object: %w", err)
+	}
+	return obj.public(), nil
+}
+
+// Delete removes an object. A non-empty resourceVersion must match the object's
+// current resource version.
+func (s *Store) Delete(kind Kind, name, resourceVersion string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	objects, ok := s.objects[kind]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownKind, kind)
+	}
+	obj, ok := objects[name]
+	if !ok {
+		return fmt.Errorf("%w: %s/%s", ErrNotFound, kind, name)
+	}
+	if resourceVersion != "" && obj.ResourceVersion != resourceVersion {
+		return fmt.Errorf("%w: %s/%s is at resource_version %s, not %s", ErrConflict, kind, name, obj.ResourceVersion, resourceVersion)
+	}
+
+	delete(objects, name)
+	s.version++
+	if err := s.save(); err != nil {
+		objects[name] = obj
+		return fmt.Errorf("failed to persist configuration object: %w", err)
+	}
+	return nil
+}
+
+// Export returns copies of all objects, ordered by kind and name, with their secrets
+// so that a restored engine keeps delivering signed webhooks
+func (s *Store) Export() []*Object {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	exported := []*Object{}
+	for _, kind := range Kinds() {
+		for _, obj := range sortedObjects(s.objects[kind]) {
+			copied := *obj
+			copied.spec = nil
+			exported = append(exported, &copied)
+		}
+	}
+	return exported
+}
+
+// Restore imports exported objects. Without replace they are merged with the stored
+// ones, an imported object overwriting the stored one of its kind and name. Restored
+// objects are given new resource versions.
+func (s *Store) Restore(objects []*Object, replace bool) error {
+	specs := make([]Spec, len(objects))
+	for i, obj := range objects {
+		if obj == nil {
+			return fmt.Errorf("empty configuration object in archive")
+		}
+		if newSpec(obj.Kind) == nil {
+			return fmt.Errorf("%w: %s", ErrUnknownKind, obj.Kind)
+		}
+		if err := validateName(obj.Name); err != nil {
+			return fmt.Errorf("invalid configuration object %s/%s: %w", obj.Kind, obj.Name, err)
+		}
+		spec, err := decodeSpec(obj.Kind, obj.Spec, time.Time{})
+		if err != nil {
+			return fmt.Errorf("invalid configuration object %s/%s: %w", obj.Kind, obj.Name, err)
+		}
+		specs[i] = spec
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previous, previousVersion := s.objects, s.version
+	restored := make(map[Kind]map[string]*Object, len(previous))
+	for kind, stored := range previous {
+		restored[kind] = make(map[string]*Object, len(stored))
+		if replace {
+			continue
+		}
+		for name, obj := range stored {
+			restored[kind][name] = obj
+		}
+	}
+	for i, obj := range objects {
+		copied := *obj
+		copied.spec = specs[i]
+		copied.ResourceVersion = s.nextVersion()
+		restored[obj.Kind][obj.Name] = &copied
+	}
+	for kind, objects := range restored {
+		if len(objects) > DefaultMaxObjects {
+			s.version = previousVersion
+			return fmt.Errorf("%w: at most %d %s", ErrLimitReached, DefaultMaxObjects, kind)
+		}
+	}
+
+	s.objects = restored
+	if err := s.save(); err != nil {
+		s.objects, s.version = previous, previousVersion
+		return fmt.Errorf("failed to persist runtime configuration: %w", err)
+	}
+	return nil
+}
+
+// public returns a copy of the object with its secrets redacted
+func (o *Object) public() *Object {
+	copied := *o
+	if holder, ok := o.spec.(secretHolder); ok {
+		if encoded, err := json.Marshal(holder.redacted()); err == nil {
+			copied.Spec = encoded
+		}
+	}
+	copied.spec = nil
+	return &copied
+}
+
+// sortedObjects returns objects ordered by name
+func sortedObjects(objects map[string]*Object) []*Object {
+	sorted := make([]*Object, 0, len(objects))
+	for _, obj := range objects {
+		sorted = append(sorted, obj)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+// Silenced returns the name of an active silence matching the incident, or ""
+func (s *Store) Silenced(incident *models.Incident) string {
+	now := s.now()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, obj := range sortedObjects(s.objects[KindSilence]) {
+		silence := obj.spec.(*Silence)
+		if silence.Active(now) && silence.Match.MatchesIncident(incident) {
+			return obj.Name
+		}
+	}
+	return ""
+}
+
+// PolicyFor returns the first enabled policy, in name order, matching the issue, or
+// nil if none matches
+func (s *Store) PolicyFor(issue *models.Issue) (string, *Policy) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, obj := range sortedObjects(s.objects[KindPolicy]) {
+		policy := obj.spec.(*Policy)
+		if !policy.Disabled && policy.Match.MatchesIssue(issue) {
+			copied := *policy
+			return obj.Name, &copied
+		}
+	}
+	return "", nil
+}
+
+// Playbook returns a copy of the named playbook
+func (s *Store) Playbook(name string) (*Playbook, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	obj, ok := s.objects[KindPlaybook][name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s/%s", ErrNotFound, KindPlaybook, name)
+	}
+	return copyPlaybook(obj.spec.(*Playbook)), nil
+}
+
+// PlaybookFor returns the first playbook, in name order, listing the issue type, or
+// nil if none does
+func (s *Store) PlaybookFor(issueType string) (string, *Playbook) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, obj := range sortedObjects(s.objects[KindPlaybook]) {
+		playbook := obj.spec.(*Playbook)
+		if contains(playbook.IssueTypes, issueType) {
+			return obj.Name, copyPlaybook(playbook)
+		}
+	}
+	return "", nil
+}
+
+// copyPlaybook copies a playbook and its steps
+func copyPlaybook(playbook *Playbook) *Playbook {
+	copied := *playbook
+	copied.Steps = append([]PlaybookStep(nil), playbook.Steps...)
+	return &copied
+}
+
+// EscalationLadderFor returns the first enabled escalation ladder, in name order,
+// listing the issue type, or nil if none does
+func (s *Store) EscalationLadderFor(issueType string) (string, *EscalationLadder) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, obj := range sortedObjects(s.objects[KindEscalationLadder]) {
+		ladder := obj.spec.(*EscalationLadder)
+		if !ladder.Disabled && contains(ladder.IssueTypes, issueType) {
+			copied := *ladder
+			copied.Steps = append([]EscalationStep(nil), ladder.Steps...)
+			return obj.Name, &copied
+		}
+	}
+	return "", nil
+}
+
+// WebhookTargets returns the enabled webhook channels as webhook targets named
+// "channel/<name>"
+func (s *Store) WebhookTargets() []notification.Target {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var targets []notification.Target
+	for _, obj := range sortedObjects(s.objects[KindNotificationChannel]) {
+		channel := obj.spec.(*NotificationChannel)
+		if !channel.Disabled && channel.Type == ChannelWebhook {
+			targets = append(targets, channel.target("channel/"+obj.Name))
+		}
+	}
+	return targets
+}
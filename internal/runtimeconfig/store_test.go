@@ -0,0 +1,215 @@
+package runtimeconfig
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/notification"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/api/validation"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+func TestStore_CRUD(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(dir)
+
+	created, err := store.Create(KindScanSchedule, "payments", json.RawMessage(`{"namespaces":["payments"],"interval":"15m"}`))
+	require.NoError(t, err)
+	assert.Equal(t, "1", created.ResourceVersion)
+	assert.JSONEq(t, `{"namespaces":["payments"],"interval":"15m","threshold":0.7}`, string(created.Spec), "defaults are filled in")
+
+	_, err = store.Create(KindScanSchedule, "payments", json.RawMessage(`{"namespaces":["payments"],"interval":"15m"}`))
+	assert.ErrorIs(t, err, ErrAlreadyExists)
+	_, err = store.Create("widgets", "payments", json.RawMessage(`{}`))
+	assert.ErrorIs(t, err, ErrUnknownKind)
+
+	// Updates must be based on the current version
+	updated, err := store.Update(KindScanSchedule, "payments", "1", json.RawMessage(`{"namespaces":["payments","checkout"],"interval":"5m"}`))
+	require.NoError(t, err)
+	assert.Equal(t, "2", updated.ResourceVersion)
+	_, err = store.Update(KindScanSchedule, "payments", "1", json.RawMessage(`{"namespaces":["payments"],"interval":"5m"}`))
+	assert.ErrorIs(t, err, ErrConflict)
+	_, err = store.Update(KindScanSchedule, "payments", "", json.RawMessage(`{"namespaces":["payments"],"interval":"5m"}`))
+	assert.Equal(t, "resource_version", validation.Fields(err)[0].Field)
+	_, err = store.Update(KindScanSchedule, "missing", "2", json.RawMessage(`{"namespaces":["payments"],"interval":"5m"}`))
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// Objects and versions survive a restart
+	reloaded := NewStore(dir)
+	obj, err := reloaded.Get(KindScanSchedule, "payments")
+	require.NoError(t, err)
+	assert.Equal(t, "2", obj.ResourceVersion)
+	assert.Contains(t, string(obj.Spec), "checkout")
+	_, err = reloaded.Create(KindPlaybook, "restart", json.RawMessage(`{"steps":[{"name":"restart","action":"restart_pod"}]}`))
+	require.NoError(t, err)
+	assert.Equal(t, map[Kind]int{KindPolicy: 0, KindSilence: 0, KindScanSchedule: 1, KindNotificationChannel: 0, KindPlaybook: 1, KindEscalationLadder: 0}, reloaded.Counts())
+
+	assert.ErrorIs(t, reloaded.Delete(KindScanSchedule, "payments", "1"), ErrConflict)
+	require.NoError(t, reloaded.Delete(KindScanSchedule, "payments", "2"))
+	assert.ErrorIs(t, reloaded.Delete(KindScanSchedule, "payments", ""), ErrNotFound)
+	objects, err := reloaded.List(KindScanSchedule)
+	require.NoError(t, err)
+	assert.Empty(t, objects)
+}
+
+func TestStore_Validation(t *testing.T) {
+	store := NewStore(t.TempDir())
+
+	tests := []struct {
+		name   string
+		kind   Kind
+		obj    string
+		spec   string
+		fields []string
+	}{
+		{"invalid name and unknown field", KindPolicy, "Deny_Prod", `{"action":"allow","actoin":"deny"}`, []string{"name", "spec"}},
+		{"policy", KindPolicy, "deny-prod", `{"match":{"severities":["urgent"],"labels":{"team":"a"}},"action":"block"}`,
+			[]string{"spec.match.severities[0]", "spec.match.labels", "spec.action"}},
+		{"silence", KindSilence, "maintenance", `{"match":{},"ends_at":"2020-01-01T00:00:00Z"}`,
+			[]string{"spec.match", "spec.ends_at", "spec.comment"}},
+		{"scan schedule", KindScanSchedule, "all", `{"namespaces":[],"interval":"10s","threshold":2}`,
+			[]string{"spec.namespaces", "spec.interval", "spec.threshold"}},
+		{"channel", KindNotificationChannel, "slack", `{"type":"sms","url":"slack.example.com","events":["incident.exploded"],"max_per_hour":-1,"quiet_hours":{"start":"22:00","end":"22:00"}}`,
+			[]string{"spec.type", "spec.url", "spec.events[0]", "spec.max_per_hour", "spec.quiet_hours"}},
+		{"playbook", KindPlaybook, "scale-up", `{"steps":[{"name":"scale","action":"scale","params":{"replicas":"many"}},{"name":"scale","action":"reboot","timeout":"soon"}]}`,
+			[]string{"spec.steps[0].params.replicas", "spec.steps[1].name", "spec.steps[1].action", "spec.steps[1].timeout"}},
+		{"wrong type", KindScanSchedule, "typed", `{"namespaces":"payments","interval":"5m"}`, []string{"spec.namespaces"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := store.Create(tt.kind, tt.obj, json.RawMessage(tt.spec))
+			require.Error(t, err)
+			var fields []string
+			for _, fieldErr := range validation.Fields(err) {
+				fields = append(fields, fieldErr.Field)
+			}
+			assert.Equal(t, tt.fields, fields, err.Error())
+		})
+	}
+}
+
+func TestStore_Silenced(t *testing.T) {
+	store := NewStore(t.TempDir())
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+
+	_, err := store.Create(KindSilence, "payments-maintenance", json.RawMessage(
+		`{"match":{"namespaces":["payments-*"],"severities":["low","medium"]},"ends_at":"2025-06-01T14:00:00Z","comment":"CHG-1234"}`))
+	require.NoError(t, err)
+
+	incident := &models.Incident{Target: "payments-prod", Severity: models.IncidentSeverityMedium}
+	assert.Equal(t, "payments-maintenance", store.Silenced(incident))
+	assert.Empty(t, store.Silenced(&models.Incident{Target: "payments-prod", Severity: models.IncidentSeverityCritical}))
+	assert.Empty(t, store.Silenced(&models.Incident{Target: "checkout", Severity: models.IncidentSeverityLow}))
+
+	// The namespace label takes precedence over the target
+	assert.Empty(t, store.Silenced(&models.Incident{
+		Target: "payments-prod", Severity: models.IncidentSeverityLow, Labels: map[string]string{"namespace": "checkout"},
+	}))
+
+	now = now.Add(3 * time.Hour)
+	assert.Empty(t, store.Silenced(incident), "expired silences do not apply")
+}
+
+func TestStore_PolicyFor(t *testing.T) {
+	store := NewStore(t.TempDir())
+	_, err := store.Create(KindPolicy, "10-allow-crashloops", json.RawMessage(
+		`{"match":{"namespaces":["prod-*"],"issue_types":["pod_crash_loop"]},"action":"allow","playbook":"restart"}`))
+	require.NoError(t, err)
+	_, err = store.Create(KindPolicy, "20-deny-prod", json.RawMessage(
+		`{"match":{"namespaces":["prod-*"]},"action":"deny","reason":"change freeze"}`))
+	require.NoError(t, err)
+	_, err = store.Create(KindPolicy, "00-disabled", json.RawMessage(`{"action":"deny","disabled":true}`))
+	require.NoError(t, err)
+
+	name, policy := store.PolicyFor(&models.Issue{Namespace: "prod-payments", Type: "pod_crash_loop"})
+	assert.Equal(t, "10-allow-crashloops", name)
+	require.NotNil(t, policy)
+	assert.Equal(t, "restart", policy.Playbook)
+
+	name, policy = store.PolicyFor(&models.Issue{Namespace: "prod-payments", Type: "memory_pressure"})
+	assert.Equal(t, "20-deny-prod", name)
+	assert.Equal(t, PolicyDeny, policy.Action)
+
+	_, policy = store.PolicyFor(&models.Issue{Namespace: "staging", Type: "memory_pressure"})
+	assert.Nil(t, policy)
+}
+
+func TestStore_WebhookTargets(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(dir)
+	_, err := store.Create(KindNotificationChannel, "oncall", json.RawMessage(
+		`{"type":"webhook","url":"https://hooks.example.com/oncall","secret":"s3cret","events":["incident.created"],"max_per_hour":10,"quiet_hours":{"start":"22:00","end":"07:00"}}`))
+	require.NoError(t, err)
+	_, err = store.Create(KindNotificationChannel, "muted", json.RawMessage(
+		`{"type":"webhook","url":"https://hooks.example.com/muted","events":["*"],"disabled":true}`))
+	require.NoError(t, err)
+
+	targets := store.WebhookTargets()
+	require.Len(t, targets, 1)
+	assert.Equal(t, "channel/oncall", targets[0].Name)
+	assert.Equal(t, "s3cret", targets[0].Secret)
+	assert.Equal(t, 10, targets[0].MaxPerHour)
+	assert.Equal(t, &notification.QuietHours{Start: "22:00", End: "07:00"}, targets[0].QuietHours)
+
+	// Secrets are write-only
+	obj, err := store.Get(KindNotificationChannel, "oncall")
+	require.NoError(t, err)
+	assert.NotContains(t, string(obj.Spec), "s3cret")
+	assert.Contains(t, string(obj.Spec), RedactedSecret)
+
+	_, err = store.Update(KindNotificationChannel, "oncall", obj.ResourceVersion, obj.Spec)
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", NewStore(dir).WebhookTargets()[0].Secret, "sending the redacted secret back keeps it")
+}
+
+func TestStore_Playbooks(t *testing.T) {
+	store := NewStore(t.TempDir())
+	for _, name := range []string{"b-crashloop", "a-crashloop"} {
+		_, err := store.Create(KindPlaybook, name, json.RawMessage(`{"issue_types":["pod_crash_loop"],"steps":[{"name":"restart","action":"rollout_restart"}]}`))
+		require.NoError(t, err)
+	}
+
+	name, playbook := store.PlaybookFor("pod_crash_loop")
+	assert.Equal(t, "a-crashloop", name, "playbooks are matched in name order")
+	require.NotNil(t, playbook)
+	playbook.Steps[0].Action = ActionManual
+
+	stored, err := store.Playbook("a-crashloop")
+	require.NoError(t, err)
+	assert.Equal(t, ActionRolloutRestart, stored.Steps[0].Action, "callers get a copy")
+
+	_, playbook = store.PlaybookFor("oom_killed")
+	assert.Nil(t, playbook)
+	_, err = store.Playbook("missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestStore_EscalationLadders(t *testing.T) {
+	store := NewStore(t.TempDir())
+	_, err := store.Create(KindEscalationLadder, "crashloop", json.RawMessage(
+		`{"issue_types":["pod_crash_loop"],"steps":[{"action":"restart"},{"action":"scale"},{"action":"page"}]}`))
+	require.NoError(t, err)
+
+	name, ladder := store.EscalationLadderFor("pod_crash_loop")
+	assert.Equal(t, "crashloop", name)
+	require.NotNil(t, ladder)
+	assert.Equal(t, DefaultEscalationWindow, ladder.Window)
+	assert.Equal(t, time.Hour, ladder.WindowDuration())
+	assert.Equal(t, 1, ladder.Steps[1].Replicas, "scale steps add one replica by default")
+
+	_, ladder = store.EscalationLadderFor("oom_killed")
+	assert.Nil(t, ladder)
+
+	_, err = store.Create(KindEscalationLadder, "invalid", json.RawMessage(
+		`{"issue_types":["oom_killed"],"window":"10s","steps":[{"action":"reboot"},{"action":"scale","replicas":50}]}`))
+	fields := validation.Fields(err)
+	require.Len(t, fields, 3)
+	assert.Equal(t, "spec.window", fields[0].Field)
+	assert.Equal(t, "spec.steps[0].action", fields[1].Field)
+	assert.Equal(t, "spec.steps[1].replicas", fields[2].Field)
+}
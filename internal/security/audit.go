@@ -0,0 +1,307 @@
+// Package security audits the TLS configuration of the engine's outbound clients.
+//
+// Each client registers its endpoint and TLS settings with an Auditor. The Auditor
+// reports the protocol versions and cipher suites each client allows and flags
+// settings that are insecure or not FIPS 140 compatible, such as disabled certificate
+// verification, TLS versions below 1.2 and plaintext connections. The report is logged
+// at startup and served at GET /api/v1/status/security.
+package security
+
+import (
+	"crypto/fips140"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/url"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Severity ranks a finding
+type Severity string
+
+// Finding severities
+const (
+	SeverityHigh   Severity = "high"
+	SeverityMedium Severity = "medium"
+	SeverityLow    Severity = "low"
+)
+
+// Finding is an insecure or non-compliant setting
+type Finding struct {
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+}
+
+// ClientReport is the TLS configuration of one outbound client
+type ClientReport struct {
+	Name string `json:"name"`
+
+	// Endpoint is the scheme and host the client connects to; paths and query strings
+	// are omitted because they may hold credentials
+	Endpoint string `json:"endpoint,omitempty"`
+
+	TLS                  bool     `json:"tls"`
+	MinVersion           string   `json:"min_version,omitempty"`
+	MaxVersion           string   `json:"max_version,omitempty"`
+	CipherSuites         []string `json:"cipher_suites"`
+	VerifiesCertificates bool     `json:"verifies_certificates"`
+
+	// FIPSCompatible is true when the client only allows FIPS 140 approved protocol
+	// versions and cipher suites and verifies certificates
+	FIPSCompatible bool      `json:"fips_compatible"`
+	Findings       []Finding `json:"findings"`
+}
+
+// Report is the result of an audit
+type Report struct {
+	GeneratedAt time.Time `json:"generated_at"`
+	GoVersion   string    `json:"go_version"`
+
+	// FIPSMode is true when the Go runtime runs in FIPS 140-3 mode (GODEBUG=fips140=on)
+	FIPSMode bool `json:"fips_mode"`
+
+	// Secure is false when any high severity finding exists
+	Secure   bool             `json:"secure"`
+	Summary  map[Severity]int `json:"summary"`
+	Findings []Finding        `json:"findings"`
+	Clients  []ClientReport   `json:"clients"`
+}
+
+// registration is a client registered with the auditor
+type registration struct {
+	name     string
+	endpoint string
+	config   *tls.Config
+}
+
+// Auditor collects outbound clients and audits their TLS configuration
+type Auditor struct {
+	mu          sync.RWMutex
+	clients     []registration
+	requireFIPS bool
+	log         *logrus.Logger
+
+	// fipsEnabled and now are replaceable in tests
+	fipsEnabled func() bool
+	now         func() time.Time
+}
+
+// NewAuditor creates an auditor. With requireFIPS, running outside FIPS 140-3 mode is
+// a high severity finding.
+func NewAuditor(requireFIPS bool, log *logrus.Logger) *Auditor {
+	return &Auditor{
+		requireFIPS: requireFIPS,
+		log:         log,
+		fipsEnabled: fips140.Enabled,
+		now:         time.Now,
+	}
+}
+
+// Register adds a client with its endpoint URL and TLS configuration. A nil config
+// means Go's defaults. An empty endpoint means the destination varies per request,
+// e.g. pre-signed object storage URLs.
+func (a *Auditor) Register(name, endpoint string, config *tls.Config) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.clients = append(a.clients, registration{name: name, endpoint: endpoint, config: config})
+}
+
+// RegisterHTTPClient adds an HTTP client, reading the TLS configuration of its transport
+func (a *Auditor) RegisterHTTPClient(name, endpoint string, client *http.Client) {
+	var config *tls.Config
+	if client != nil {
+		if transport, ok := client.Transport.(*http.Transport); ok {
+			config = transport.TLSClientConfig
+		}
+	}
+	a.Register(name, endpoint, config)
+}
+
+// Report audits every registered client
+func (a *Auditor) Report() *Report {
+	a.mu.RLock()
+	clients := make([]registration, len(a.clients))
+	copy(clients, a.clients)
+	a.mu.RUnlock()
+
+	report := &Report{
+		GeneratedAt: a.now().UTC(),
+		GoVersion:   runtime.Version(),
+		FIPSMode:    a.fipsEnabled(),
+		Summary:     map[Severity]int{SeverityHigh: 0, SeverityMedium: 0, SeverityLow: 0},
+		Findings:    []Finding{},
+		Clients:     make([]ClientReport, 0, len(clients)),
+	}
+	if a.requireFIPS && !report.FIPSMode {
+		report.Findings = append(report.Findings, Finding{
+			Severity: SeverityHigh,
+			Message:  "FIPS 140-3 mode is required but not enabled; start the engine with GODEBUG=fips140=on",
+		})
+	}
+	for _, finding := range report.Findings {
+		report.Summary[finding.Severity]++
+	}
+
+	for _, client := range clients {
+		clientReport := auditClient(client)
+		for _, finding := range clientReport.Findings {
+			report.Summary[finding.Severity]++
+		}
+		report.Clients = append(report.Clients, clientReport)
+	}
+	sort.Slice(report.Clients, func(i, j int) bool { return report.Clients[i].Name < report.Clients[j].Name })
+	report.Secure = report.Summary[SeverityHigh] == 0
+
+	UpdateFindings(report.Summary)
+	return report
+}
+
+// LogFindings audits every client and logs each finding; it is the startup self-check
+func (a *Auditor) LogFindings() *Report {
+	report := a.Report()
+	for _, finding := range report.Findings {
+		a.logFinding("", finding)
+	}
+	for _, client := range report.Clients {
+		for _, finding := range client.Findings {
+			a.logFinding(client.Name, finding)
+		}
+	}
+
+	a.log.WithFields(logrus.Fields{
+		"clients":   len(report.Clients),
+		"fips_mode": report.FIPSMode,
+		"high":      report.Summary[SeverityHigh],
+		"medium":    report.Summary[SeverityMedium],
+		"low":       report.Summary[SeverityLow],
+	}).Info("Outbound TLS configuration audited")
+	return report
+}
+
+// logFinding logs a finding at a level matching its severity
+func (a *Auditor) logFinding(client string, finding Finding) {
+	entry := a.log.WithField("severity", finding.Severity)
+	if client != "" {
+		entry = entry.WithField("client", client)
+	}
+	if finding.Severity == SeverityLow {
+		entry.Info(finding.Message)
+		return
+	}
+	entry.Warn(finding.Message)
+}
+
+// auditClient reports the effective TLS settings of a client and flags insecure ones
+func auditClient(client registration) ClientReport {
+	report := ClientReport{
+		Name:                 client.name,
+		CipherSuites:         []string{},
+		VerifiesCertificates: true,
+		Findings:             []Finding{},
+	}
+	config := client.config
+	if config == nil {
+		config = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+
+	scheme, host := "", ""
+	if client.endpoint != "" {
+		if u, err := url.Parse(client.endpoint); err == nil {
+			scheme, host = u.Scheme, u.Hostname()
+			report.Endpoint = u.Scheme + "://" + u.Host
+		}
+	}
+
+	switch scheme {
+	case "http":
+		severity := SeverityMedium
+		message := "connects over plaintext HTTP"
+		if inCluster(host) {
+			severity = SeverityLow
+			message = "connects to an in-cluster service over plaintext HTTP"
+		}
+		report.Findings = append(report.Findings, Finding{Severity: severity, Message: message})
+		report.VerifiesCertificates = false
+		return report
+	case "smtp":
+		report.Findings = append(report.Findings, Finding{
+			Severity: SeverityMedium,
+			Message:  "STARTTLS is only used when the SMTP server offers it; mail may be sent in plaintext",
+		})
+	}
+	report.TLS = true
+
+	// Clients default to TLS 1.2 when MinVersion is not set
+	minVersion := config.MinVersion
+	if minVersion == 0 {
+		minVersion = tls.VersionTLS12
+	}
+	report.MinVersion = tls.VersionName(minVersion)
+	report.MaxVersion = tls.VersionName(tls.VersionTLS13)
+	if config.MaxVersion != 0 {
+		report.MaxVersion = tls.VersionName(config.MaxVersion)
+	}
+	if minVersion < tls.VersionTLS12 {
+		report.Findings = append(report.Findings, Finding{
+			Severity: SeverityHigh,
+			Message:  "allows " + report.MinVersion + "; set the minimum version to TLS 1.2 or later",
+		})
+	}
+
+	if config.InsecureSkipVerify {
+		report.VerifiesCertificates = false
+		report.Findings = append(report.Findings, Finding{
+			Severity: SeverityHigh,
+			Message:  "certificate verification is disabled (InsecureSkipVerify); connections can be intercepted",
+		})
+	}
+
+	approvedSuites := true
+	for _, id := range config.CipherSuites {
+		name := tls.CipherSuiteName(id)
+		report.CipherSuites = append(report.CipherSuites, name)
+		switch {
+		case insecureCipherSuites[id]:
+			report.Findings = append(report.Findings, Finding{Severity: SeverityHigh, Message: "allows insecure cipher suite " + name})
+			approvedSuites = false
+		case !fipsCipherSuites[id]:
+			report.Findings = append(report.Findings, Finding{Severity: SeverityLow, Message: "allows cipher suite " + name + ", which is not FIPS 140 approved"})
+			approvedSuites = false
+		}
+	}
+
+	report.FIPSCompatible = report.VerifiesCertificates && minVersion >= tls.VersionTLS12 && approvedSuites
+	return report
+}
+
+// inCluster reports whether host is a Kubernetes service name
+func inCluster(host string) bool {
+	if net.ParseIP(host) != nil {
+		return false
+	}
+	return !strings.Contains(host, ".") || strings.HasSuffix(host, ".svc") || strings.HasSuffix(host, ".svc.cluster.local")
+}
+
+// insecureCipherSuites are the suites Go considers insecure
+var insecureCipherSuites = func() map[uint16]bool {
+	ids := make(map[uint16]bool)
+	for _, suite := range tls.InsecureCipherSuites() {
+		ids[suite.ID] = true
+	}
+	return ids
+}()
+
+// fipsCipherSuites are the TLS 1.2 suites approved for FIPS 140 (ECDHE with AES-GCM).
+// TLS 1.3 suites are not configurable and restricted by the runtime in FIPS mode.
+var fipsCipherSuites = map[uint16]bool{
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256: true,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384: true,
+	tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256:   true,
+	tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384:   true,
+}
@@ -0,0 +1,128 @@
+package security
+
+import (
+	"crypto/tls"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestAuditor(requireFIPS, fipsMode bool) *Auditor {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	auditor := NewAuditor(requireFIPS, log)
+	auditor.fipsEnabled = func() bool { return fipsMode }
+	return auditor
+}
+
+func severities(findings []Finding) []Severity {
+	result := []Severity{}
+	for _, finding := range findings {
+		result = append(result, finding.Severity)
+	}
+	return result
+}
+
+func TestAuditor_InsecureSkipVerify(t *testing.T) {
+	auditor := newTestAuditor(false, false)
+	auditor.RegisterHTTPClient("prometheus", "https://thanos-querier.openshift-monitoring.svc:9091/api?token=x", &http.Client{
+		Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}, //nolint:gosec // audited
+	})
+
+	report := auditor.Report()
+	assert.False(t, report.Secure)
+	assert.Equal(t, 1, report.Summary[SeverityHigh])
+	require.Len(t, report.Clients, 1)
+
+	client := report.Clients[0]
+	assert.Equal(t, "https://thanos-querier.openshift-monitoring.svc:9091", client.Endpoint, "path and query are omitted")
+	assert.True(t, client.TLS)
+	assert.Equal(t, "TLS 1.2", client.MinVersion)
+	assert.Equal(t, "TLS 1.3", client.MaxVersion)
+	assert.False(t, client.VerifiesCertificates)
+	assert.False(t, client.FIPSCompatible)
+	assert.Equal(t, []Severity{SeverityHigh}, severities(client.Findings))
+}
+
+func TestAuditor_VersionsAndCipherSuites(t *testing.T) {
+	auditor := newTestAuditor(false, false)
+	auditor.Register("legacy", "https://legacy.example.com", &tls.Config{
+		MinVersion: tls.VersionTLS10, //nolint:gosec // audited
+		MaxVersion: tls.VersionTLS12,
+		CipherSuites: []uint16{
+			tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256,
+			tls.TLS_RSA_WITH_RC4_128_SHA,
+		},
+	})
+	auditor.Register("modern", "https://api.example.com", &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		CipherSuites: []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384},
+	})
+
+	report := auditor.Report()
+	require.Len(t, report.Clients, 2)
+
+	legacy := report.Clients[0]
+	assert.Equal(t, "TLS 1.0", legacy.MinVersion)
+	assert.Equal(t, "TLS 1.2", legacy.MaxVersion)
+	assert.Len(t, legacy.CipherSuites, 3)
+	assert.ElementsMatch(t, []Severity{SeverityHigh, SeverityLow, SeverityHigh}, severities(legacy.Findings),
+		"TLS 1.0, a non-FIPS suite and an insecure suite")
+	assert.False(t, legacy.FIPSCompatible)
+
+	modern := report.Clients[1]
+	assert.Empty(t, modern.Findings)
+	assert.True(t, modern.FIPSCompatible)
+}
+
+func TestAuditor_PlaintextAndDefaults(t *testing.T) {
+	auditor := newTestAuditor(false, false)
+	auditor.RegisterHTTPClient("ml-service", "http://aiops-ml-service:8080", &http.Client{})
+	auditor.RegisterHTTPClient("wiki", "http://wiki.example.com", nil)
+	auditor.Register("smtp", "smtp://smtp.example.com:587", &tls.Config{ServerName: "smtp.example.com", MinVersion: tls.VersionTLS12})
+	auditor.RegisterHTTPClient("backup-storage", "", &http.Client{})
+
+	report := auditor.Report()
+	require.Len(t, report.Clients, 4)
+	assert.True(t, report.Secure)
+
+	byName := map[string]ClientReport{}
+	for _, client := range report.Clients {
+		byName[client.Name] = client
+	}
+	assert.False(t, byName["ml-service"].TLS)
+	assert.Equal(t, []Severity{SeverityLow}, severities(byName["ml-service"].Findings), "in-cluster plaintext")
+	assert.Equal(t, []Severity{SeverityMedium}, severities(byName["wiki"].Findings), "external plaintext")
+	assert.True(t, byName["smtp"].TLS)
+	assert.Equal(t, []Severity{SeverityMedium}, severities(byName["smtp"].Findings), "opportunistic STARTTLS")
+
+	defaults := byName["backup-storage"]
+	assert.Empty(t, defaults.Endpoint)
+	assert.True(t, defaults.TLS)
+	assert.True(t, defaults.VerifiesCertificates)
+	assert.True(t, defaults.FIPSCompatible)
+	assert.Empty(t, defaults.Findings)
+
+	assert.Equal(t, map[Severity]int{SeverityHigh: 0, SeverityMedium: 2, SeverityLow: 1}, report.Summary)
+}
+
+func TestAuditor_RequireFIPS(t *testing.T) {
+	report := newTestAuditor(true, false).LogFindings()
+	assert.False(t, report.Secure)
+	assert.False(t, report.FIPSMode)
+	require.Len(t, report.Findings, 1)
+	assert.Equal(t, SeverityHigh, report.Findings[0].Severity)
+
+	report = newTestAuditor(true, true).LogFindings()
+	assert.True(t, report.Secure)
+	assert.True(t, report.FIPSMode)
+	assert.Empty(t, report.Findings)
+
+	report = newTestAuditor(false, false).Report()
+	assert.True(t, report.Secure, "FIPS mode is only required when configured")
+}
@@ -0,0 +1,24 @@
+package security
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// TLSFindings is the number of findings of the last TLS audit by severity
+	TLSFindings = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "coordination_engine_security_tls_findings",
+			Help: "Number of insecure or non-FIPS-compatible outbound TLS settings found by the last audit, by severity",
+		},
+		[]string{"severity"},
+	)
+)
+
+// UpdateFindings records the finding counts of an audit
+func UpdateFindings(summary map[Severity]int) {
+	for severity, count := range summary {
+		TLSFindings.WithLabelValues(string(severity)).Set(float64(count))
+	}
+}
@@ -0,0 +1,36 @@
+package selfmonitor
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// SignalValue is the latest value of each self-monitoring signal
+	SignalValue = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "coordination_engine_self_monitor_signal_value",
+			Help: "Latest value of each engine self-monitoring signal (error rate, queue depth or cache hit rate)",
+		},
+		[]string{"signal"},
+	)
+
+	// SignalAnomalous is 1 while a self-monitoring signal is anomalous
+	SignalAnomalous = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "coordination_engine_self_monitor_signal_anomalous",
+			Help: "Whether each engine self-monitoring signal is anomalous (1) or not (0)",
+		},
+		[]string{"signal"},
+	)
+)
+
+// recordSignal records the latest value of a signal
+func recordSignal(s SignalStatus) {
+	SignalValue.WithLabelValues(s.Name).Set(s.Value)
+	anomalous := 0.0
+	if s.Anomalous {
+		anomalous = 1
+	}
+	SignalAnomalous.WithLabelValues(s.Name).Set(anomalous)
+}
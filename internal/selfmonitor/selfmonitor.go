@@ -0,0 +1,537 @@
+// Package selfmonitor watches the engine's own behavior.
+//
+// A Monitor reads the engine's Prometheus metrics in process every interval and checks
+// them for signs that the engine itself is failing: error rates of deployment detection,
+// remediation and model inference, remediation workflows and coordination plans piling
+// up, and the detection cache hit rate collapsing against its learned average. While a
+// signal is anomalous the capability it belongs to is flagged as reduced and a single
+// self-incident targeting the engine is kept open. The incident is resolved once every
+// signal has been normal for several consecutive samples, so the system that watches
+// the cluster also watches itself.
+package selfmonitor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/sirupsen/logrus"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/storage"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// Defaults used when an option is zero
+const (
+	DefaultInterval            = time.Minute
+	DefaultErrorRateThreshold  = 0.25
+	DefaultQueueDepthThreshold = 50
+	DefaultCacheCollapseRatio  = 0.5
+	DefaultResolveAfter        = 3
+)
+
+// minEvents is the number of attempts or cache lookups an interval needs for its error
+// or hit rate to be judged, so a single failure on an idle engine is not an anomaly
+const minEvents = 10
+
+// Learning the cache hit rate: the average needs baselineSamples judged intervals before
+// a collapse is detected, and follows new intervals with weight baselineAlpha
+const (
+	baselineSamples = 3
+	baselineAlpha   = 0.2
+)
+
+// Self-incidents target the engine and are labeled with their source and the
+// capabilities that are reduced
+const (
+	IncidentTarget            = "coordination-engine"
+	LabelSource               = "source"
+	SourceSelfMonitoring      = "self-monitoring"
+	LabelReducedFunctionality = "reduced_functionality"
+)
+
+// Capabilities flagged as reduced while one of their signals is anomalous
+const (
+	CapabilityDetection    = "deployment_detection"
+	CapabilityRemediation  = "remediation"
+	CapabilityInference    = "ml_inference"
+	CapabilityCoordination = "coordination"
+)
+
+// SignalKind is how a signal is judged
+type SignalKind string
+
+// Signal kinds
+const (
+	// KindErrorRate is the share of failed attempts in an interval
+	KindErrorRate SignalKind = "error_rate"
+
+	// KindQueueDepth is the current number of items in flight
+	KindQueueDepth SignalKind = "queue_depth"
+
+	// KindCacheHitRate is the share of cache lookups in an interval that hit
+	KindCacheHitRate SignalKind = "cache_hit_rate"
+)
+
+// series selects the series of a metric to sum; match may be nil to sum them all
+type series struct {
+	metric string
+	match  func(labels map[string]string) bool
+}
+
+// signal is one behavior of the engine that is monitored. Error rates are failed/total
+// and cache hit rates failed (the hits) over total (the lookups); queue depths read total.
+type signal struct {
+	name       string
+	kind       SignalKind
+	capability string
+	failed     []series
+	total      []series
+}
+
+// signals are the monitored behaviors
+var signals = []signal{
+	{
+		name: "detection_errors", kind: KindErrorRate, capability: CapabilityDetection,
+		failed: []series{{metric: "coordination_engine_detection_errors_total"}},
+		total: []series{
+			{metric: "coordination_engine_detection_total"},
+			{metric: "coordination_engine_detection_errors_total"},
+		},
+	},
+	{
+		name: "remediation_failures", kind: KindErrorRate, capability: CapabilityRemediation,
+		failed: []series{{metric: "coordination_engine_remediation_failures_total"}},
+		total:  []series{{metric: "coordination_engine_remediation_total"}},
+	},
+	{
+		name: "inference_errors", kind: KindErrorRate, capability: CapabilityInference,
+		failed: []series{{metric: "coordination_engine_kserve_infer_requests_total", match: func(labels map[string]string) bool {
+			return labels["outcome"] != "success"
+		}}},
+		total: []series{{metric: "coordination_engine_kserve_infer_requests_total"}},
+	},
+	{
+		name: "workflow_queue", kind: KindQueueDepth, capability: CapabilityRemediation,
+		total: []series{{metric: "coordination_engine_workflows_active"}},
+	},
+	{
+		name: "plan_queue", kind: KindQueueDepth, capability: CapabilityCoordination,
+		total: []series{{metric: "coordination_engine_plans_active"}},
+	},
+	{
+		name: "detection_cache_hit_rate", kind: KindCacheHitRate, capability: CapabilityDetection,
+		failed: []series{{metric: "coordination_engine_detection_cache_hits_total"}},
+		total: []series{
+			{metric: "coordination_engine_detection_cache_hits_total"},
+			{metric: "coordination_engine_detection_cache_misses_total"},
+		},
+	},
+}
+
+// Options configures a Monitor
+type Options struct {
+	// Interval is how often the engine's metrics are sampled
+	Interval time.Duration
+
+	// ErrorRateThreshold is the share of failed detections, remediations or model
+	// inferences in an interval at which the signal is anomalous
+	ErrorRateThreshold float64
+
+	// QueueDepthThreshold is the number of active workflows or plans at which the
+	// signal is anomalous
+	QueueDepthThreshold int
+
+	// CacheCollapseRatio is the share of its learned average the detection cache hit
+	// rate must fall below to be anomalous
+	CacheCollapseRatio float64
+
+	// ResolveAfter is the number of consecutive normal samples after which the
+	// self-incident is resolved
+	ResolveAfter int
+}
+
+// SignalStatus is the latest value of a signal
+type SignalStatus struct {
+	Name       string     `json:"name"`
+	Kind       SignalKind `json:"kind"`
+	Capability string     `json:"capability"`
+
+	// Value is the error rate or cache hit rate of the last interval, or the queue depth
+	Value float64 `json:"value"`
+
+	// Events is the number of attempts or cache lookups in the last interval; rates
+	// are only judged from minEvents
+	Events float64 `json:"events,omitempty"`
+
+	// Baseline is the learned average cache hit rate
+	Baseline float64 `json:"baseline,omitempty"`
+
+	Threshold float64 `json:"threshold"`
+	Anomalous bool    `json:"anomalous"`
+	Reason    string  `json:"reason,omitempty"`
+}
+
+// Status is the latest sample of the engine's own behavior
+type Status struct {
+	Healthy bool `json:"healthy"`
+
+	// ReducedFunctionality lists the capabilities with an anomalous signal
+	ReducedFunctionality []string       `json:"reduced_functionality,omitempty"`
+	Signals              []SignalStatus `json:"signals"`
+
+	// IncidentID is the open self-incident, if any
+	IncidentID string    `json:"incident_id,omitempty"`
+	SampledAt  time.Time `json:"sampled_at"`
+}
+
+// baseline is the learned average of a rate
+type baseline struct {
+	value   float64
+	samples int
+}
+
+// Monitor samples the engine's own metrics and raises a self-incident when they turn
+// anomalous
+type Monitor struct {
+	opts      Options
+	gatherer  prometheus.Gatherer
+	incidents *storage.IncidentStore
+	log       *logrus.Logger
+
+	mu          sync.RWMutex
+	status      Status
+	counters    map[string]float64
+	baselines   map[string]*baseline
+	incidentID  string
+	clearStreak int
+
+	now func() time.Time
+}
+
+// New creates a monitor reading metrics from gatherer; zero options take their defaults.
+// incidents may be nil, in which case anomalies are only reported in the status.
+func New(opts Options, gatherer prometheus.Gatherer, incidents *storage.IncidentStore, log *logrus.Logger) *Monitor {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultInterval
+	}
+	if opts.ErrorRateThreshold <= 0 {
+		opts.ErrorRateThreshold = DefaultErrorRateThreshold
+	}
+	if opts.QueueDepthThreshold <= 0 {
+		opts.QueueDepthThreshold = DefaultQueueDepthThreshold
+	}
+	if opts.CacheCollapseRatio <= 0 {
+		opts.CacheCollapseRatio = DefaultCacheCollapseRatio
+	}
+	if opts.ResolveAfter <= 0 {
+		opts.ResolveAfter = DefaultResolveAfter
+	}
+	return &Monitor{
+		opts:      opts,
+		gatherer:  gatherer,
+		incidents: incidents,
+		log:       log,
+		status:    Status{Healthy: true},
+		counters:  make(map[string]float64),
+		baselines: make(map[string]*baseline),
+		now:       time.Now,
+	}
+}
+
+// Start samples the engine's metrics every interval until ctx is cancelled. The first
+// sample only records counter values, so rates are judged from the second interval on.
+func (m *Monitor) Start(ctx context.Context) {
+	m.Sample()
+	go func() {
+		ticker := time.NewTicker(m.opts.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				m.Sample()
+			}
+		}
+	}()
+}
+
+// Status returns the latest sample
+func (m *Monitor) Status() Status {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.status
+}
+
+// Sample reads the engine's metrics, judges every signal and opens, updates or
+// resolves the self-incident
+func (m *Monitor) Sample() Status {
+	families, err := m.gatherer.Gather()
+	if err != nil {
+		// Gather returns what it could collect along with the error
+		m.log.WithError(err).Warn("Self-monitoring could not gather all engine metrics")
+	}
+	values := make(map[string][]*dto.Metric, len(families))
+	for _, family := range families {
+		values[family.GetName()] = family.GetMetric()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	status := Status{Healthy: true, SampledAt: m.now()}
+	reduced := make(map[string]bool)
+	var reasons []string
+	for _, sig := range signals {
+		s := m.judge(sig, values)
+		recordSignal(s)
+		status.Signals = append(status.Signals, s)
+		if s.Anomalous {
+			status.Healthy = false
+			reduced[s.Capability] = true
+			reasons = append(reasons, s.Reason)
+		}
+	}
+	for capability := range reduced {
+		status.ReducedFunctionality = append(status.ReducedFunctionality, capability)
+	}
+	sort.Strings(status.ReducedFunctionality)
+
+	if m.incidents != nil {
+		m.reconcileIncident(status.ReducedFunctionality, reasons)
+	}
+	status.IncidentID = m.incidentID
+
+	if status.Healthy != m.status.Healthy {
+		entry := m.log.WithFields(logrus.Fields{
+			"reduced_functionality": status.ReducedFunctionality,
+			"reasons":               reasons,
+		})
+		if status.Healthy {
+			entry.Info("Engine self-monitoring signals back to normal")
+		} else {
+			entry.Warn("Engine self-monitoring detected anomalous behavior")
+		}
+	}
+	m.status = status
+	return status
+}
+
+// judge computes the value of a signal and whether it is anomalous. Callers must hold m.mu.
+func (m *Monitor) judge(sig signal, values map[string][]*dto.Metric) SignalStatus {
+	s := SignalStatus{Name: sig.name, Kind: sig.kind, Capability: sig.capability}
+
+	if sig.kind == KindQueueDepth {
+		s.Value = sum(values, sig.total)
+		s.Threshold = float64(m.opts.QueueDepthThreshold)
+		if s.Value >= s.Threshold {
+			s.Anomalous = true
+			s.Reason = fmt.Sprintf("%s: %.0f in flight, threshold %.0f", sig.name, s.Value, s.Threshold)
+		}
+		return s
+	}
+
+	failed, failedOK := m.delta(sig.name+"/failed", sum(values, sig.failed))
+	total, totalOK := m.delta(sig.name+"/total", sum(values, sig.total))
+	s.Events = total
+	if !failedOK || !totalOK || total < minEvents {
+		s.Threshold = m.threshold(sig)
+		return s
+	}
+	s.Value = min(failed/total, 1)
+
+	if sig.kind == KindErrorRate {
+		s.Threshold = m.opts.ErrorRateThreshold
+		if s.Value >= s.Threshold {
+			s.Anomalous = true
+			s.Reason = fmt.Sprintf("%s: %.0f%% of %.0f attempts failed, threshold %.0f%%", sig.name, 100*s.Value, total, 100*s.Threshold)
+		}
+		return s
+	}
+
+	// A collapsed hit rate is not learned, so a lasting collapse stays anomalous
+	learned := m.baselines[sig.name]
+	if learned == nil {
+		learned = &baseline{}
+		m.baselines[sig.name] = learned
+	}
+	s.Threshold = m.opts.CacheCollapseRatio * learned.value
+	s.Baseline = learned.value
+	if learned.samples >= baselineSamples && s.Value < s.Threshold {
+		s.Anomalous = true
+		s.Reason = fmt.Sprintf("%s: %.0f%% of %.0f lookups hit, against an average of %.0f%%", sig.name, 100*s.Value, total, 100*learned.value)
+		return s
+	}
+	if learned.samples == 0 {
+		learned.value = s.Value
+	} else {
+		learned.value += baselineAlpha * (s.Value - learned.value)
+	}
+	learned.samples++
+	return s
+}
+
+// threshold returns the threshold of a rate signal
+func (m *Monitor) threshold(sig signal) float64 {
+	if sig.kind == KindErrorRate {
+		return m.opts.ErrorRateThreshold
+	}
+	if learned := m.baselines[sig.name]; learned != nil {
+		return m.opts.CacheCollapseRatio * learned.value
+	}
+	return 0
+}
+
+// delta returns how much a counter grew since the last sample, and false on the first
+// sample. A counter that went down was reset and counts from zero. Callers must hold m.mu.
+func (m *Monitor) delta(key string, value float64) (float64, bool) {
+	last, seen := m.counters[key]
+	m.counters[key] = value
+	if !seen {
+		return 0, false
+	}
+	if value < last {
+		return value, true
+	}
+	return value - last, true
+}
+
+// sum adds up the counter and gauge values of the selected series
+func sum(values map[string][]*dto.Metric, selected []series) float64 {
+	total := 0.0
+	for _, sel := range selected {
+		for _, metric := range values[sel.metric] {
+			if sel.match != nil {
+				labels := make(map[string]string, len(metric.GetLabel()))
+				for _, pair := range metric.GetLabel() {
+					labels[pair.GetName()] = pair.GetValue()
+				}
+				if !sel.match(labels) {
+					continue
+				}
+			}
+			switch {
+			case metric.GetCounter() != nil:
+				total += metric.GetCounter().GetValue()
+			case metric.GetGauge() != nil:
+				total += metric.GetGauge().GetValue()
+			case metric.GetUntyped() != nil:
+				total += metric.GetUntyped().GetValue()
+			}
+		}
+	}
+	return total
+}
+
+// reconcileIncident opens the self-incident when functionality is reduced, keeps its
+// flags current, and resolves it after ResolveAfter normal samples. Callers must hold m.mu.
+func (m *Monitor) reconcileIncident(reduced, reasons []string) {
+	if m.incidentID == "" {
+		m.incidentID = m.findOpenIncident()
+	}
+
+	if len(reduced) == 0 {
+		if m.incidentID == "" {
+			return
+		}
+		m.clearStreak++
+		if m.clearStreak < m.opts.ResolveAfter {
+			return
+		}
+		incident, err := m.incidents.Get(m.incidentID)
+		if err != nil || !incident.IsActive() {
+			m.incidentID, m.clearStreak = "", 0
+			return
+		}
+		incident.AutoResolve(fmt.Sprintf("Engine self-monitoring signals normal for %d consecutive samples", m.clearStreak))
+		if err := m.incidents.Update(incident); err != nil {
+			m.log.WithError(err).WithField("incident_id", m.incidentID).Warn("Failed to resolve self-incident")
+			return
+		}
+		m.log.WithField("incident_id", m.incidentID).Info("Self-incident resolved")
+		m.incidentID, m.clearStreak = "", 0
+		return
+	}
+
+	m.clearStreak = 0
+	flags := strings.Join(reduced, ",")
+	description := "The coordination engine detected anomalies in its own behavior:\n- " + strings.Join(reasons, "\n- ")
+
+	if m.incidentID != "" {
+		incident, err := m.incidents.Get(m.incidentID)
+		if err == nil && incident.IsActive() {
+			if incident.Labels[LabelReducedFunctionality] == flags && incident.Description == description {
+				return
+			}
+			if incident.Labels == nil {
+				incident.Labels = make(map[string]string)
+			}
+			incident.Labels[LabelReducedFunctionality] = flags
+			incident.Title = incidentTitle(reduced)
+			incident.Description = description
+			incident.Severity = incidentSeverity(reduced)
+			if err := m.incidents.Update(incident); err != nil {
+				m.log.WithError(err).WithField("incident_id", m.incidentID).Warn("Failed to update self-incident")
+			}
+			return
+		}
+		m.incidentID = ""
+	}
+
+	incident, err := m.incidents.Create(&models.Incident{
+		Title:       incidentTitle(reduced),
+		Description: description,
+		Severity:    incidentSeverity(reduced),
+		Target:      IncidentTarget,
+		Labels: map[string]string{
+			LabelSource:               SourceSelfMonitoring,
+			LabelReducedFunctionality: flags,
+		},
+	})
+	if err != nil {
+		m.log.WithError(err).Warn("Failed to create self-incident")
+		return
+	}
+	m.incidentID = incident.ID
+	m.log.WithFields(logrus.Fields{
+		"incident_id":           incident.ID,
+		"reduced_functionality": reduced,
+	}).Warn("Self-incident opened")
+}
+
+// findOpenIncident returns the ID of an open self-incident, e.g. one opened before a
+// restart, or "" if there is none. Callers must hold m.mu.
+func (m *Monitor) findOpenIncident() string {
+	open := m.incidents.List(storage.ListFilter{
+		Namespace: IncidentTarget,
+		Status:    string(models.IncidentStatusActive),
+	})
+	for _, incident := range open {
+		if incident.Labels[LabelSource] == SourceSelfMonitoring {
+			return incident.ID
+		}
+	}
+	return ""
+}
+
+// incidentTitle names the reduced capabilities
+func incidentTitle(reduced []string) string {
+	return "Coordination engine running with reduced functionality: " + strings.Join(reduced, ", ")
+}
+
+// incidentSeverity is high when remediation is affected, since the engine may then fail
+// to heal the cluster, and medium otherwise
+func incidentSeverity(reduced []string) models.IncidentSeverity {
+	for _, capability := range reduced {
+		if capability == CapabilityRemediation {
+			return models.IncidentSeverityHigh
+		}
+	}
+	return models.IncidentSeverityMedium
+}
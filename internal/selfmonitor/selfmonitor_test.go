@@ -0,0 +1,161 @@
+package selfmonitor
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/storage"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// engineMetrics registers the engine metrics read by the monitor in a private registry
+type engineMetrics struct {
+	registry         *prometheus.Registry
+	detections       prometheus.Counter
+	detectionErrors  prometheus.Counter
+	remediations     prometheus.Counter
+	remediationFails prometheus.Counter
+	cacheHits        prometheus.Counter
+	cacheMisses      prometheus.Counter
+	workflows        prometheus.Gauge
+}
+
+func newEngineMetrics() *engineMetrics {
+	counter := func(name string) prometheus.Counter {
+		return prometheus.NewCounter(prometheus.CounterOpts{Name: name, Help: name})
+	}
+	m := &engineMetrics{
+		registry:         prometheus.NewRegistry(),
+		detections:       counter("coordination_engine_detection_total"),
+		detectionErrors:  counter("coordination_engine_detection_errors_total"),
+		remediations:     counter("coordination_engine_remediation_total"),
+		remediationFails: counter("coordination_engine_remediation_failures_total"),
+		cacheHits:        counter("coordination_engine_detection_cache_hits_total"),
+		cacheMisses:      counter("coordination_engine_detection_cache_misses_total"),
+		workflows:        prometheus.NewGauge(prometheus.GaugeOpts{Name: "coordination_engine_workflows_active", Help: "workflows"}),
+	}
+	m.registry.MustRegister(m.detections, m.detectionErrors, m.remediations, m.remediationFails, m.cacheHits, m.cacheMisses, m.workflows)
+	return m
+}
+
+func signalStatus(t *testing.T, status Status, name string) SignalStatus {
+	t.Helper()
+	for _, s := range status.Signals {
+		if s.Name == name {
+			return s
+		}
+	}
+	t.Fatalf("signal %s not reported", name)
+	return SignalStatus{}
+}
+
+func TestMonitor_SelfIncident(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	metrics := newEngineMetrics()
+	incidents := storage.NewIncidentStoreWithPath(t.TempDir())
+	monitor := New(Options{ResolveAfter: 2}, metrics.registry, incidents, log)
+
+	// The first sample records counters without judging them
+	metrics.remediations.Add(100)
+	metrics.remediationFails.Add(90)
+	status := monitor.Sample()
+	assert.True(t, status.Healthy)
+
+	// Too few attempts in an interval are not judged
+	metrics.remediations.Add(5)
+	metrics.remediationFails.Add(5)
+	status = monitor.Sample()
+	assert.True(t, status.Healthy)
+	assert.Equal(t, 5.0, signalStatus(t, status, "remediation_failures").Events)
+
+	metrics.remediations.Add(20)
+	metrics.remediationFails.Add(10)
+	status = monitor.Sample()
+	assert.False(t, status.Healthy)
+	remediation := signalStatus(t, status, "remediation_failures")
+	assert.True(t, remediation.Anomalous)
+	assert.InDelta(t, 0.5, remediation.Value, 1e-9)
+	assert.Equal(t, []string{CapabilityRemediation}, status.ReducedFunctionality)
+	require.NotEmpty(t, status.IncidentID)
+
+	incident, err := incidents.Get(status.IncidentID)
+	require.NoError(t, err)
+	assert.Equal(t, IncidentTarget, incident.Target)
+	assert.Equal(t, models.IncidentSeverityHigh, incident.Severity)
+	assert.Equal(t, SourceSelfMonitoring, incident.Labels[LabelSource])
+	assert.Equal(t, CapabilityRemediation, incident.Labels[LabelReducedFunctionality])
+	assert.Contains(t, incident.Description, "remediation_failures: 50% of 20 attempts failed")
+
+	// A second anomaly updates the flags of the same incident
+	metrics.workflows.Set(60)
+	status = monitor.Sample()
+	assert.Equal(t, incident.ID, status.IncidentID)
+	assert.Equal(t, []string{CapabilityRemediation}, status.ReducedFunctionality, "both signals belong to remediation")
+	assert.True(t, signalStatus(t, status, "workflow_queue").Anomalous)
+	assert.Len(t, incidents.List(storage.ListFilter{Namespace: IncidentTarget}), 1)
+
+	// A restarted monitor picks up the open incident
+	restarted := New(Options{ResolveAfter: 2}, metrics.registry, incidents, log)
+	status = restarted.Sample()
+	assert.Equal(t, incident.ID, status.IncidentID)
+
+	// The incident resolves after ResolveAfter normal samples
+	metrics.workflows.Set(0)
+	status = monitor.Sample()
+	assert.True(t, status.Healthy)
+	assert.Equal(t, incident.ID, status.IncidentID)
+	status = monitor.Sample()
+	assert.Empty(t, status.IncidentID)
+	incident, err = incidents.Get(incident.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.IncidentStatusResolved, incident.Status)
+	assert.Equal(t, models.IncidentEventAutoResolved, incident.Events[len(incident.Events)-1].Type)
+}
+
+func TestMonitor_CacheHitRateCollapse(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	metrics := newEngineMetrics()
+	monitor := New(Options{}, metrics.registry, nil, log)
+	monitor.Sample()
+
+	// The hit rate is learned over a few intervals
+	for i := 0; i < baselineSamples; i++ {
+		metrics.cacheHits.Add(90)
+		metrics.cacheMisses.Add(10)
+		status := monitor.Sample()
+		assert.True(t, status.Healthy)
+	}
+
+	metrics.cacheHits.Add(20)
+	metrics.cacheMisses.Add(80)
+	status := monitor.Sample()
+	cache := signalStatus(t, status, "detection_cache_hit_rate")
+	assert.True(t, cache.Anomalous)
+	assert.InDelta(t, 0.2, cache.Value, 1e-9)
+	assert.InDelta(t, 0.9, cache.Baseline, 1e-9)
+	assert.Equal(t, []string{CapabilityDetection}, status.ReducedFunctionality)
+	assert.Empty(t, status.IncidentID, "no incident store")
+
+	// A lasting collapse is not learned as the new normal
+	metrics.cacheHits.Add(20)
+	metrics.cacheMisses.Add(80)
+	status = monitor.Sample()
+	assert.True(t, signalStatus(t, status, "detection_cache_hit_rate").Anomalous)
+
+	// Detection errors flag the same capability
+	metrics.detections.Add(10)
+	metrics.detectionErrors.Add(10)
+	metrics.cacheHits.Add(90)
+	metrics.cacheMisses.Add(10)
+	status = monitor.Sample()
+	assert.False(t, signalStatus(t, status, "detection_cache_hit_rate").Anomalous)
+	detection := signalStatus(t, status, "detection_errors")
+	assert.True(t, detection.Anomalous)
+	assert.InDelta(t, 0.5, detection.Value, 1e-9)
+}
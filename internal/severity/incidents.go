@@ -0,0 +1,48 @@
+package severity
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// BurnRateLabel is the incident label carrying the SLO burn rate of the affected
+// service, e.g. from the alert that opened the incident
+const BurnRateLabel = "slo_burn_rate"
+
+// tierLookupTimeout bounds reading an incident namespace's tier
+const tierLookupTimeout = 5 * time.Second
+
+// IncidentPrioritizer prioritizes incidents by their reported severity, the tier of
+// their namespace, the number of affected resources and the SLO burn rate label. It
+// implements storage.IncidentPrioritizer.
+type IncidentPrioritizer struct {
+	matrix *Matrix
+	tiers  func(ctx context.Context, namespace string) string
+}
+
+// NewIncidentPrioritizer creates an incident prioritizer; without tiers the namespace
+// tier is not taken into account
+func NewIncidentPrioritizer(matrix *Matrix, tiers func(ctx context.Context, namespace string) string) *IncidentPrioritizer {
+	return &IncidentPrioritizer{matrix: matrix, tiers: tiers}
+}
+
+// IncidentPriority returns the priority of an incident. The reported severity stands in
+// for the anomaly score: low 0.25, medium 0.5, high 0.75, critical 1.
+func (p *IncidentPrioritizer) IncidentPriority(incident *models.Incident) int {
+	factors := Factors{
+		Score:       float64(incident.Severity.Rank()) / float64(len(models.ValidSeverities())),
+		BlastRadius: len(incident.AffectedResources),
+	}
+	if burn, err := strconv.ParseFloat(incident.Labels[BurnRateLabel], 64); err == nil {
+		factors.BurnRate = burn
+	}
+	if p.tiers != nil && incident.Target != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), tierLookupTimeout)
+		factors.Tier = p.tiers(ctx, incident.Target)
+		cancel()
+	}
+	return p.matrix.Assess(factors).Priority
+}
@@ -0,0 +1,222 @@
+// Package severity derives the severity and priority of anomalies and incidents from a
+// configurable matrix of anomaly score, namespace criticality tier, blast radius and
+// SLO burn rate.
+package severity
+
+import (
+	"fmt"
+	"math"
+	"os"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// DefaultTierLabel is the namespace label holding a namespace's criticality tier
+const DefaultTierLabel = "criticality"
+
+// Default matrix values
+const (
+	DefaultBlastRadiusPoints = 15
+	DefaultBlastRadiusMax    = 10
+	DefaultSLOBurnPoints     = 20
+	DefaultSLOBurnMax        = 14.4
+	DefaultSLOTarget         = 0.999
+
+	DefaultCriticalPriority = 90
+	DefaultHighPriority     = 70
+	DefaultMediumPriority   = 40
+)
+
+// Factors are the inputs of an assessment. Zero values mean the factor is unknown and
+// leave the priority unchanged.
+type Factors struct {
+	// Score is the anomaly score, 0.0-1.0
+	Score float64
+
+	// Tier is the criticality tier of the namespace, e.g. "critical"
+	Tier string
+
+	// BlastRadius is the number of affected pods or resources
+	BlastRadius int
+
+	// BurnRate is how fast the error budget of the SLO is consumed; 1 uses it up
+	// exactly over the SLO window
+	BurnRate float64
+}
+
+// Assessment is the severity and priority the matrix gives a set of factors
+type Assessment struct {
+	Severity models.IncidentSeverity `json:"severity"`
+
+	// Priority orders work across incidents, notifications and remediation, 0-100
+	Priority int `json:"priority"`
+}
+
+// Boost adds up to Points priority points as a factor grows from 1 to Max
+type Boost struct {
+	Points float64 `json:"points"`
+	Max    float64 `json:"max"`
+}
+
+// Bands are the lowest priorities of the critical, high and medium severities; lower
+// priorities are low
+type Bands struct {
+	Critical int `json:"critical"`
+	High     int `json:"high"`
+	Medium   int `json:"medium"`
+}
+
+// Matrix weighs anomaly score, namespace tier, blast radius and SLO burn into a
+// priority. The score sets the base priority (score x 100), the namespace tier
+// multiplies it, and blast radius and SLO burn add points. The priority's band is the
+// severity. Without tier, blast radius and burn the default bands reproduce the fixed
+// score bands: critical from 0.9, high (warning) from 0.7.
+type Matrix struct {
+	// TierLabel is the namespace label holding the tier (default "criticality")
+	TierLabel string `json:"tier_label,omitempty"`
+
+	// Tiers multiply the base priority of namespaces in each tier; unlisted tiers use 1
+	Tiers map[string]float64 `json:"tiers,omitempty"`
+
+	// BlastRadius adds points by the number of affected pods or resources
+	BlastRadius Boost `json:"blast_radius"`
+
+	// SLOBurn adds points by the SLO error budget burn rate
+	SLOBurn Boost `json:"slo_burn"`
+
+	// SLOTarget is the availability objective burn rates are derived from, e.g. 0.999
+	SLOTarget float64 `json:"slo_target,omitempty"`
+
+	Bands Bands `json:"bands"`
+}
+
+// DefaultMatrix returns the matrix used without SEVERITY_MATRIX_FILE
+func DefaultMatrix() *Matrix {
+	return &Matrix{
+		TierLabel: DefaultTierLabel,
+		Tiers: map[string]float64{
+			"critical": 1.2,
+			"high":     1.1,
+			"medium":   1.0,
+			"low":      0.8,
+		},
+		BlastRadius: Boost{Points: DefaultBlastRadiusPoints, Max: DefaultBlastRadiusMax},
+		SLOBurn:     Boost{Points: DefaultSLOBurnPoints, Max: DefaultSLOBurnMax},
+		SLOTarget:   DefaultSLOTarget,
+		Bands: Bands{
+			Critical: DefaultCriticalPriority,
+			High:     DefaultHighPriority,
+			Medium:   DefaultMediumPriority,
+		},
+	}
+}
+
+// LoadMatrix reads a severity matrix from a YAML or JSON file. Settings the file omits
+// keep their defaults.
+func LoadMatrix(path string) (*Matrix, error) {
+	data, err := os.ReadFile(path) //#nosec G304 -- path comes from operator configuration
+	if err != nil {
+		return nil, fmt.Errorf("failed to read severity matrix file: %w", err)
+	}
+
+	matrix := DefaultMatrix()
+	matrix.Tiers = nil
+	if err := yaml.UnmarshalStrict(data, matrix); err != nil {
+		return nil, fmt.Errorf("failed to parse severity matrix file %s: %w", path, err)
+	}
+	if matrix.Tiers == nil {
+		matrix.Tiers = DefaultMatrix().Tiers
+	}
+	if err := matrix.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid severity matrix file %s: %w", path, err)
+	}
+	return matrix, nil
+}
+
+// Validate checks the weights and bands of the matrix
+func (m *Matrix) Validate() error {
+	if m.TierLabel == "" {
+		return fmt.Errorf("tier_label must not be empty")
+	}
+	for tier, weight := range m.Tiers {
+		if weight <= 0 {
+			return fmt.Errorf("tier %s: weight must be positive, got %v", tier, weight)
+		}
+	}
+	for name, boost := range map[string]Boost{"blast_radius": m.BlastRadius, "slo_burn": m.SLOBurn} {
+		if boost.Points < 0 || boost.Points > 100 {
+			return fmt.Errorf("%s: points must be between 0 and 100, got %v", name, boost.Points)
+		}
+		if boost.Max <= 1 {
+			return fmt.Errorf("%s: max must be greater than 1, got %v", name, boost.Max)
+		}
+	}
+	if m.SLOTarget <= 0 || m.SLOTarget >= 1 {
+		return fmt.Errorf("slo_target must be between 0 and 1 (exclusive), got %v", m.SLOTarget)
+	}
+	if m.Bands.Medium <= 0 || m.Bands.Medium >= m.Bands.High || m.Bands.High >= m.Bands.Critical || m.Bands.Critical > 100 {
+		return fmt.Errorf("bands must satisfy 0 < medium < high < critical <= 100, got %d/%d/%d",
+			m.Bands.Medium, m.Bands.High, m.Bands.Critical)
+	}
+	return nil
+}
+
+// Assess returns the severity and priority of the factors
+func (m *Matrix) Assess(factors Factors) Assessment {
+	priority := factors.Score * 100
+	if weight, ok := m.Tiers[factors.Tier]; ok {
+		priority *= weight
+	}
+	priority += m.BlastRadius.Points * ramp(float64(factors.BlastRadius), m.BlastRadius.Max)
+	priority += m.SLOBurn.Points * ramp(factors.BurnRate, m.SLOBurn.Max)
+
+	clamped := int(math.Round(math.Max(0, math.Min(100, priority))))
+	return Assessment{Severity: m.Band(clamped), Priority: clamped}
+}
+
+// Band returns the severity of a priority
+func (m *Matrix) Band(priority int) models.IncidentSeverity {
+	switch {
+	case priority >= m.Bands.Critical:
+		return models.IncidentSeverityCritical
+	case priority >= m.Bands.High:
+		return models.IncidentSeverityHigh
+	case priority >= m.Bands.Medium:
+		return models.IncidentSeverityMedium
+	default:
+		return models.IncidentSeverityLow
+	}
+}
+
+// Lower returns the highest priority of the band below the priority's band, so work
+// downgraded as expected churn or load sorts with the lower severity
+func (m *Matrix) Lower(priority int) int {
+	switch {
+	case priority >= m.Bands.Critical:
+		return m.Bands.Critical - 1
+	case priority >= m.Bands.High:
+		return m.Bands.High - 1
+	case priority >= m.Bands.Medium:
+		return m.Bands.Medium - 1
+	default:
+		return priority
+	}
+}
+
+// BurnRate returns the SLO error budget burn rate of an observed availability
+func (m *Matrix) BurnRate(availability float64) float64 {
+	if availability >= 1 {
+		return 0
+	}
+	return (1 - availability) / (1 - m.SLOTarget)
+}
+
+// ramp scales value from 1 (0) to max (1)
+func ramp(value, max float64) float64 {
+	if value <= 1 {
+		return 0
+	}
+	return math.Min(1, (value-1)/(max-1))
+}
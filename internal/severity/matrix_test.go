@@ -0,0 +1,102 @@
+package severity
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+func TestMatrix_Assess(t *testing.T) {
+	matrix := DefaultMatrix()
+
+	// The score alone reproduces the fixed bands
+	assert.Equal(t, Assessment{Severity: models.IncidentSeverityCritical, Priority: 92}, matrix.Assess(Factors{Score: 0.92}))
+	assert.Equal(t, Assessment{Severity: models.IncidentSeverityHigh, Priority: 70}, matrix.Assess(Factors{Score: 0.7}))
+	assert.Equal(t, Assessment{Severity: models.IncidentSeverityMedium, Priority: 50}, matrix.Assess(Factors{Score: 0.5}))
+	assert.Equal(t, Assessment{Severity: models.IncidentSeverityLow, Priority: 10}, matrix.Assess(Factors{Score: 0.1}))
+
+	// Tier, blast radius and SLO burn raise or lower the priority
+	assert.Equal(t, 90, matrix.Assess(Factors{Score: 0.75, Tier: "critical"}).Priority)
+	assert.Equal(t, 60, matrix.Assess(Factors{Score: 0.75, Tier: "low"}).Priority)
+	assert.Equal(t, 75, matrix.Assess(Factors{Score: 0.75, Tier: "unlisted"}).Priority)
+	assert.Equal(t, 75, matrix.Assess(Factors{Score: 0.75, BlastRadius: 1}).Priority, "a single pod adds nothing")
+	assert.Equal(t, 90, matrix.Assess(Factors{Score: 0.75, BlastRadius: 40}).Priority)
+	assert.Equal(t, 85, matrix.Assess(Factors{Score: 0.75, BurnRate: 7.7}).Priority)
+	assert.Equal(t, 100, matrix.Assess(Factors{Score: 0.9, Tier: "critical", BlastRadius: 10, BurnRate: 20}).Priority)
+
+	assert.Equal(t, 89, matrix.Lower(95))
+	assert.Equal(t, 69, matrix.Lower(75))
+	assert.Equal(t, 39, matrix.Lower(69))
+	assert.Equal(t, 20, matrix.Lower(20))
+
+	assert.InDelta(t, 100, matrix.BurnRate(0.9), 1e-6)
+	assert.Equal(t, 0.0, matrix.BurnRate(1))
+}
+
+func TestLoadMatrix(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+
+	matrix, err := LoadMatrix(write("matrix.yaml", `
+tier_label: tier
+tiers:
+  gold: 1.5
+slo_burn:
+  points: 30
+  max: 6
+bands:
+  critical: 80
+  high: 60
+  medium: 30
+`))
+	require.NoError(t, err)
+	assert.Equal(t, "tier", matrix.TierLabel)
+	assert.Equal(t, map[string]float64{"gold": 1.5}, matrix.Tiers)
+	assert.Equal(t, Boost{Points: DefaultBlastRadiusPoints, Max: DefaultBlastRadiusMax}, matrix.BlastRadius, "omitted settings keep their defaults")
+	assert.Equal(t, Assessment{Severity: models.IncidentSeverityCritical, Priority: 90}, matrix.Assess(Factors{Score: 0.6, Tier: "gold"}))
+
+	for name, content := range map[string]string{
+		"unknown field": "weights: {}",
+		"bad tier":      "tiers: {gold: 0}",
+		"bad boost":     "blast_radius: {points: 10, max: 1}",
+		"bad target":    "slo_target: 1",
+		"bad bands":     "bands: {critical: 50, high: 60, medium: 30}",
+	} {
+		_, err := LoadMatrix(write("invalid.yaml", content))
+		assert.Error(t, err, name)
+	}
+	_, err = LoadMatrix(filepath.Join(dir, "missing.yaml"))
+	assert.Error(t, err)
+}
+
+func TestIncidentPrioritizer(t *testing.T) {
+	tiers := func(_ context.Context, namespace string) string {
+		if namespace == "payments" {
+			return "critical"
+		}
+		return ""
+	}
+	prioritizer := NewIncidentPrioritizer(DefaultMatrix(), tiers)
+
+	incident := &models.Incident{Severity: models.IncidentSeverityHigh, Target: "sandbox"}
+	assert.Equal(t, 75, prioritizer.IncidentPriority(incident))
+
+	incident.Target = "payments"
+	assert.Equal(t, 90, prioritizer.IncidentPriority(incident))
+
+	incident.AffectedResources = []string{"Pod/a", "Pod/b", "Pod/c", "Pod/d"}
+	incident.Labels = map[string]string{BurnRateLabel: "14.4"}
+	assert.Equal(t, 100, prioritizer.IncidentPriority(incident))
+
+	assert.Equal(t, 25, NewIncidentPrioritizer(DefaultMatrix(), nil).IncidentPriority(&models.Incident{Severity: models.IncidentSeverityLow}))
+}
@@ -0,0 +1,113 @@
+package slo
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Window labels of the burn rate
+const (
+	windowLong  = "long"
+	windowShort = "short"
+)
+
+var (
+	// RequestDuration observes the end-to-end duration of requests tracked by an objective
+	RequestDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "coordination_engine_slo_request_duration_seconds",
+			Help:    "End-to-end duration of requests tracked by a latency SLO",
+			Buckets: []float64{0.25, 0.5, 1, 2, 3, 5, 10, 30},
+		},
+		[]string{"slo"},
+	)
+
+	// Requests counts the requests tracked by an objective, by whether they met its latency
+	Requests = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "coordination_engine_slo_requests_total",
+			Help: "Total number of requests tracked by a latency SLO, by outcome (good, slow)",
+		},
+		[]string{"slo", "outcome"},
+	)
+
+	// Target is the latency and objective of an objective
+	Target = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "coordination_engine_slo_target",
+			Help: "Latency SLO target: the latency in seconds (target=latency_seconds) and the share of requests that must meet it (target=objective)",
+		},
+		[]string{"slo", "target"},
+	)
+
+	// BurnRate is the rate an objective's error budget is spent at
+	BurnRate = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "coordination_engine_slo_burn_rate",
+			Help: "Rate the SLO error budget is spent at over the window (long) and a twelfth of it (short); 1 spends it exactly over the window",
+		},
+		[]string{"slo", "window"},
+	)
+
+	// BudgetRemaining is the share of an objective's error budget left in the window
+	BudgetRemaining = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "coordination_engine_slo_error_budget_remaining",
+			Help: "Share of the SLO error budget left in the window, negative once exhausted",
+		},
+		[]string{"slo"},
+	)
+
+	// Alerting is 1 while an objective burns its error budget faster than its alert burn rate
+	Alerting = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "coordination_engine_slo_alerting",
+			Help: "Whether the SLO burns its error budget faster than its alert burn rate in both windows (1) or not (0)",
+		},
+		[]string{"slo"},
+	)
+
+	// FastPathActive is 1 while analyses use the batched range query fast path
+	FastPathActive = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "coordination_engine_slo_fast_path_active",
+			Help: "Whether analyses use the batched range query fast path to protect the SLO (1) or not (0)",
+		},
+		[]string{"slo"},
+	)
+)
+
+// recordObjective records the target of an objective
+func recordObjective(name string, latency time.Duration, objective float64) {
+	Target.WithLabelValues(name, "latency_seconds").Set(latency.Seconds())
+	Target.WithLabelValues(name, "objective").Set(objective)
+}
+
+// recordRequest records a request tracked by an objective
+func recordRequest(name string, duration time.Duration, slow bool) {
+	RequestDuration.WithLabelValues(name).Observe(duration.Seconds())
+	outcome := "good"
+	if slow {
+		outcome = "slow"
+	}
+	Requests.WithLabelValues(name, outcome).Inc()
+}
+
+// recordStatus records the state of an objective
+func recordStatus(status Status) {
+	BurnRate.WithLabelValues(status.SLO, windowLong).Set(status.BurnRate)
+	BurnRate.WithLabelValues(status.SLO, windowShort).Set(status.ShortBurnRate)
+	BudgetRemaining.WithLabelValues(status.SLO).Set(status.BudgetRemaining)
+	Alerting.WithLabelValues(status.SLO).Set(boolValue(status.Alerting))
+	FastPathActive.WithLabelValues(status.SLO).Set(boolValue(status.FastPath))
+}
+
+// boolValue is 1 for true and 0 for false
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,344 @@
+// Package slo tracks the engine's own latency objective for analysis requests.
+//
+// A Tracker counts the analysis requests of a rolling window in one-minute buckets and
+// the share of them slower than the objective's latency. The burn rate is that share
+// divided by the error budget (1 - objective): at 1 the budget lasts exactly the window,
+// above 1 it runs out early. The burn rates of the window and of a short window (a
+// twelfth of it) are exported as metrics, and the objective alerts while both burn
+// faster than the alert burn rate, so a single slow request does not page and a
+// recovered engine stops alerting quickly.
+//
+// While the budget is at risk the tracker enables the fast path: analyses fetch the
+// rolling features of each metric with one batched range query instead of one instant
+// query per feature, trading a little precision for far fewer Prometheus round trips.
+package slo
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Defaults used when an option is zero
+const (
+	DefaultName             = "analysis_latency"
+	DefaultLatency          = 3 * time.Second
+	DefaultObjective        = 0.95
+	DefaultWindow           = time.Hour
+	DefaultAlertBurnRate    = 2.0
+	DefaultFastPathBurnRate = 1.0
+)
+
+// minRequests is the number of requests the short window needs before its burn rate
+// alerts or enables the fast path, so a single slow request on an idle engine does not
+const minRequests = 10
+
+// shortWindowDivisor is the ratio of the window to the short window
+const shortWindowDivisor = 12
+
+// FastPathMode is when analyses use the batched range query fast path
+type FastPathMode string
+
+// Fast path modes
+const (
+	// FastPathAuto enables the fast path while the error budget is at risk
+	FastPathAuto FastPathMode = "auto"
+
+	// FastPathAlways always uses the fast path
+	FastPathAlways FastPathMode = "always"
+
+	// FastPathNever never uses the fast path
+	FastPathNever FastPathMode = "never"
+)
+
+// ParseFastPathMode parses a fast path mode; empty is auto
+func ParseFastPathMode(mode string) (FastPathMode, error) {
+	switch FastPathMode(mode) {
+	case "", FastPathAuto:
+		return FastPathAuto, nil
+	case FastPathAlways, FastPathNever:
+		return FastPathMode(mode), nil
+	default:
+		return "", fmt.Errorf("invalid fast path mode %q (expected auto, always or never)", mode)
+	}
+}
+
+// Options configures a Tracker
+type Options struct {
+	// Name labels the objective's metrics
+	Name string
+
+	// Latency is the duration a request must complete within
+	Latency time.Duration
+
+	// Objective is the share of requests that must complete within Latency, e.g. 0.95
+	// for "p95 < Latency"
+	Objective float64
+
+	// Window is the rolling window the error budget is computed over
+	Window time.Duration
+
+	// AlertBurnRate is the burn rate of both the window and the short window at which
+	// the objective alerts
+	AlertBurnRate float64
+
+	// FastPath is when analyses use the batched range query fast path, and
+	// FastPathBurnRate the short-window burn rate that enables it in auto mode
+	FastPath         FastPathMode
+	FastPathBurnRate float64
+}
+
+// Status is the state of the objective over its windows
+type Status struct {
+	SLO       string  `json:"slo"`
+	Latency   string  `json:"latency"`
+	Objective float64 `json:"objective"`
+	Window    string  `json:"window"`
+
+	// Requests and Slow count the requests of the window and those slower than Latency
+	Requests int `json:"requests"`
+	Slow     int `json:"slow"`
+
+	// BurnRate and ShortBurnRate are the rates the error budget is spent at over the
+	// window and the short window; 1 spends it exactly over the window
+	BurnRate      float64 `json:"burn_rate"`
+	ShortBurnRate float64 `json:"short_burn_rate"`
+
+	// BudgetRemaining is the share of the window's error budget left, negative once
+	// it is exhausted
+	BudgetRemaining float64 `json:"budget_remaining"`
+
+	Alerting bool `json:"alerting"`
+	FastPath bool `json:"fast_path"`
+}
+
+// bucket counts the requests of one minute
+type bucket struct {
+	minute int64
+	total  int
+	slow   int
+}
+
+// Tracker tracks a latency objective
+type Tracker struct {
+	opts Options
+	log  *logrus.Logger
+
+	mu       sync.Mutex
+	buckets  []bucket
+	alerting bool
+	fastPath bool
+
+	// Clock, replaceable in tests and by deterministic mode
+	now func() time.Time
+}
+
+// New creates a tracker; zero options take their defaults
+func New(opts Options, log *logrus.Logger) *Tracker {
+	if opts.Name == "" {
+		opts.Name = DefaultName
+	}
+	if opts.Latency <= 0 {
+		opts.Latency = DefaultLatency
+	}
+	if opts.Objective <= 0 || opts.Objective >= 1 {
+		opts.Objective = DefaultObjective
+	}
+	if opts.Window < shortWindowDivisor*time.Minute {
+		opts.Window = DefaultWindow
+	}
+	if opts.AlertBurnRate <= 0 {
+		opts.AlertBurnRate = DefaultAlertBurnRate
+	}
+	if opts.FastPath == "" {
+		opts.FastPath = FastPathAuto
+	}
+	if opts.FastPathBurnRate <= 0 {
+		opts.FastPathBurnRate = DefaultFastPathBurnRate
+	}
+	t := &Tracker{
+		opts:     opts,
+		log:      log,
+		buckets:  make([]bucket, int(opts.Window/time.Minute)),
+		fastPath: opts.FastPath == FastPathAlways,
+		now:      time.Now,
+	}
+	recordObjective(opts.Name, opts.Latency, opts.Objective)
+	recordStatus(t.evaluateLocked(t.now()))
+	return t
+}
+
+// SetClock replaces the clock of the tracker's windows
+func (t *Tracker) SetClock(now func() time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.now = now
+}
+
+// Options returns the tracker's options with their defaults applied
+func (t *Tracker) Options() Options {
+	return t.opts
+}
+
+// Start re-evaluates the objective every minute until ctx is cancelled, so its burn
+// rates decay and its alert clears while no requests arrive
+func (t *Tracker) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				t.Evaluate()
+			}
+		}
+	}()
+}
+
+// Observe records the duration of a request and re-evaluates the objective
+func (t *Tracker) Observe(duration time.Duration) {
+	slow := duration > t.opts.Latency
+	recordRequest(t.opts.Name, duration, slow)
+
+	t.mu.Lock()
+	now := t.now()
+	minute := now.Unix() / 60
+	b := &t.buckets[minute%int64(len(t.buckets))]
+	if b.minute != minute {
+		*b = bucket{minute: minute}
+	}
+	b.total++
+	if slow {
+		b.slow++
+	}
+	status := t.evaluateLocked(now)
+	t.mu.Unlock()
+
+	recordStatus(status)
+}
+
+// Evaluate re-evaluates the objective at the current time
+func (t *Tracker) Evaluate() Status {
+	t.mu.Lock()
+	status := t.evaluateLocked(t.now())
+	t.mu.Unlock()
+
+	recordStatus(status)
+	return status
+}
+
+// Status returns the state of the objective at the current time without logging
+// transitions
+func (t *Tracker) Status() Status {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.statusLocked(t.now())
+}
+
+// FastPath reports whether analyses should use the batched range query fast path
+func (t *Tracker) FastPath() bool {
+	if t == nil {
+		return false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.fastPath
+}
+
+// counts sums the requests of the last minutes up to now
+func (t *Tracker) counts(now time.Time, minutes int) (total, slow int) {
+	current := now.Unix() / 60
+	for _, b := range t.buckets {
+		if b.minute > current-int64(minutes) && b.minute <= current {
+			total += b.total
+			slow += b.slow
+		}
+	}
+	return total, slow
+}
+
+// burnRate is the rate the error budget is spent at by slow of total requests
+func (t *Tracker) burnRate(total, slow int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(slow) / float64(total) / (1 - t.opts.Objective)
+}
+
+// statusLocked computes the status from the buckets; the caller holds mu
+func (t *Tracker) statusLocked(now time.Time) Status {
+	minutes := len(t.buckets)
+	total, slow := t.counts(now, minutes)
+	shortTotal, shortSlow := t.counts(now, minutes/shortWindowDivisor)
+	burn := t.burnRate(total, slow)
+	return Status{
+		SLO:             t.opts.Name,
+		Latency:         t.opts.Latency.String(),
+		Objective:       t.opts.Objective,
+		Window:          t.opts.Window.String(),
+		Requests:        total,
+		Slow:            slow,
+		BurnRate:        burn,
+		ShortBurnRate:   t.burnRate(shortTotal, shortSlow),
+		BudgetRemaining: 1 - burn,
+		Alerting:        t.alerting,
+		FastPath:        t.fastPath,
+	}
+}
+
+// evaluateLocked updates the alert and the fast path from the current burn rates and
+// logs their transitions; the caller holds mu
+func (t *Tracker) evaluateLocked(now time.Time) Status {
+	status := t.statusLocked(now)
+	shortTotal, _ := t.counts(now, len(t.buckets)/shortWindowDivisor)
+	judged := shortTotal >= minRequests
+
+	alerting := judged && status.BurnRate >= t.opts.AlertBurnRate && status.ShortBurnRate >= t.opts.AlertBurnRate
+	if alerting != t.alerting {
+		entry := t.log.WithFields(logrus.Fields{
+			"slo":             t.opts.Name,
+			"burn_rate":       status.BurnRate,
+			"short_burn_rate": status.ShortBurnRate,
+			"budget":          status.BudgetRemaining,
+		})
+		if alerting {
+			entry.Warnf("Analysis latency SLO burning its error budget %.1fx too fast", status.ShortBurnRate)
+		} else {
+			entry.Info("Analysis latency SLO burn rate recovered")
+		}
+		t.alerting = alerting
+	}
+
+	// In auto mode the fast path is enabled once the short window burns too fast and
+	// kept until both windows have recovered, so it does not flap
+	fastPath := t.opts.FastPath == FastPathAlways
+	if t.opts.FastPath == FastPathAuto {
+		threshold := t.opts.FastPathBurnRate
+		if t.fastPath {
+			fastPath = status.ShortBurnRate >= threshold || status.BurnRate >= threshold
+		} else {
+			fastPath = judged && status.ShortBurnRate >= threshold
+		}
+	}
+	if fastPath != t.fastPath {
+		entry := t.log.WithFields(logrus.Fields{
+			"slo":             t.opts.Name,
+			"short_burn_rate": status.ShortBurnRate,
+		})
+		if fastPath {
+			entry.Warn("Analysis latency error budget at risk, enabling batched range query fast path")
+		} else {
+			entry.Info("Analysis latency error budget recovered, disabling fast path")
+		}
+		t.fastPath = fastPath
+	}
+
+	status.Alerting = t.alerting
+	status.FastPath = t.fastPath
+	return status
+}
@@ -0,0 +1,117 @@
+package slo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTracker(t *testing.T, opts Options, now *time.Time) *Tracker {
+	t.Helper()
+	log := logrus.New()
+	log.SetLevel(logrus.PanicLevel)
+	tracker := New(opts, log)
+	tracker.SetClock(func() time.Time { return *now })
+	return tracker
+}
+
+func observe(tracker *Tracker, good, slow int) {
+	for i := 0; i < good; i++ {
+		tracker.Observe(time.Second)
+	}
+	for i := 0; i < slow; i++ {
+		tracker.Observe(5 * time.Second)
+	}
+}
+
+func TestTracker_BurnRateAndAlert(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	tracker := newTestTracker(t, Options{Name: "test_alert", FastPath: FastPathNever}, &now)
+	opts := tracker.Options()
+	assert.Equal(t, DefaultLatency, opts.Latency)
+	assert.Equal(t, DefaultWindow, opts.Window)
+
+	// 5% slow spends the budget of a 95% objective exactly over the window
+	observe(tracker, 95, 5)
+	status := tracker.Status()
+	assert.Equal(t, 100, status.Requests)
+	assert.Equal(t, 5, status.Slow)
+	assert.InDelta(t, 1.0, status.BurnRate, 1e-9)
+	assert.InDelta(t, 0.0, status.BudgetRemaining, 1e-9)
+	assert.False(t, status.Alerting)
+
+	// 30% slow in the short window burns 6x; the hour burns (5+6)/120 / 0.05 = 1.83x
+	now = now.Add(10 * time.Minute)
+	observe(tracker, 14, 6)
+	status = tracker.Status()
+	assert.InDelta(t, 6.0, status.ShortBurnRate, 1e-9)
+	assert.InDelta(t, 11.0/120/0.05, status.BurnRate, 1e-9)
+	assert.False(t, status.Alerting, "both windows must burn faster than the alert rate")
+
+	observe(tracker, 0, 4)
+	status = tracker.Status()
+	assert.True(t, status.Alerting)
+	assert.Equal(t, 1.0, testutil.ToFloat64(Alerting.WithLabelValues("test_alert")))
+	assert.Equal(t, status.ShortBurnRate, testutil.ToFloat64(BurnRate.WithLabelValues("test_alert", "short")))
+	assert.Equal(t, 15.0, testutil.ToFloat64(Requests.WithLabelValues("test_alert", "slow")))
+	assert.False(t, status.FastPath, "the fast path is never enabled")
+
+	// The short window clears once its minutes pass without slow requests
+	now = now.Add(6 * time.Minute)
+	status = tracker.Evaluate()
+	assert.Zero(t, status.ShortBurnRate)
+	assert.False(t, status.Alerting)
+	assert.Zero(t, testutil.ToFloat64(Alerting.WithLabelValues("test_alert")))
+
+	// Requests older than the window are forgotten
+	now = now.Add(time.Hour)
+	assert.Zero(t, tracker.Evaluate().Requests)
+}
+
+func TestTracker_FastPath(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	tracker := newTestTracker(t, Options{Name: "test_fast_path"}, &now)
+
+	// Too few requests to judge
+	observe(tracker, 0, minRequests-1)
+	assert.False(t, tracker.FastPath())
+
+	observe(tracker, 0, 1)
+	assert.True(t, tracker.FastPath())
+	assert.Equal(t, 1.0, testutil.ToFloat64(FastPathActive.WithLabelValues("test_fast_path")))
+
+	// Kept while the long window still burns too fast, although the short one recovered
+	now = now.Add(10 * time.Minute)
+	observe(tracker, 20, 0)
+	status := tracker.Status()
+	assert.Zero(t, status.ShortBurnRate)
+	assert.True(t, status.FastPath)
+
+	observe(tracker, 200, 0)
+	assert.False(t, tracker.FastPath())
+
+	always := newTestTracker(t, Options{Name: "test_always", FastPath: FastPathAlways}, &now)
+	assert.True(t, always.FastPath())
+	observe(always, 100, 0)
+	assert.True(t, always.FastPath())
+
+	var none *Tracker
+	assert.False(t, none.FastPath())
+}
+
+func TestParseFastPathMode(t *testing.T) {
+	mode, err := ParseFastPathMode("")
+	require.NoError(t, err)
+	assert.Equal(t, FastPathAuto, mode)
+
+	mode, err = ParseFastPathMode("always")
+	require.NoError(t, err)
+	assert.Equal(t, FastPathAlways, mode)
+
+	_, err = ParseFastPathMode("sometimes")
+	assert.ErrorContains(t, err, `invalid fast path mode "sometimes"`)
+}
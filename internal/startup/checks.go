@@ -0,0 +1,29 @@
+package startup
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// probeFile is written and removed to check that a directory is writable
+const probeFile = ".startup-probe"
+
+// WritableDir checks that dir exists, or can be created, and accepts writes, e.g. the
+// DATA_DIR incidents and workflow events are persisted in
+func WritableDir(dir string) func(ctx context.Context) error {
+	return func(context.Context) error {
+		if err := os.MkdirAll(dir, 0o750); err != nil {
+			return fmt.Errorf("cannot create %s: %w", dir, err)
+		}
+		path := filepath.Join(dir, probeFile)
+		if err := os.WriteFile(path, []byte("ok"), 0o600); err != nil {
+			return fmt.Errorf("%s is not writable: %w", dir, err)
+		}
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("cannot remove %s: %w", path, err)
+		}
+		return nil
+	}
+}
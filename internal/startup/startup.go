@@ -0,0 +1,275 @@
+// Package startup gates engine startup on its dependencies.
+//
+// Each dependency is checked once before the engine serves requests, and what
+// happens when it is unavailable depends on its mode:
+//
+//   - wait retries the check with exponential backoff until it passes or the wait
+//     timeout expires, then starts degraded. It suits dependencies that usually come
+//     up with the engine, such as Prometheus after a cluster restart.
+//   - degraded logs a warning and starts without it; the features that need it
+//     answer with defaults or 503 until it becomes available.
+//   - fail refuses to start, so Kubernetes restarts the engine instead of running it
+//     without, e.g., the storage incidents are persisted in.
+//
+// After startup the dependencies are re-checked in the background, so their health
+// reflects recoveries and outages, and reported with their mode.
+package startup
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Defaults used when an option is zero
+const (
+	DefaultWaitTimeout    = 30 * time.Second
+	DefaultInitialBackoff = time.Second
+	DefaultMaxBackoff     = 10 * time.Second
+	DefaultCheckTimeout   = 10 * time.Second
+	DefaultRecheck        = 30 * time.Second
+)
+
+// Mode is how startup treats an unavailable dependency
+type Mode string
+
+// Startup modes
+const (
+	ModeWait     Mode = "wait"
+	ModeDegraded Mode = "degraded"
+	ModeFail     Mode = "fail"
+)
+
+// ParseMode parses a startup mode
+func ParseMode(mode string) (Mode, error) {
+	switch Mode(mode) {
+	case ModeWait, ModeDegraded, ModeFail:
+		return Mode(mode), nil
+	default:
+		return "", fmt.Errorf("invalid startup mode %q (expected wait, degraded or fail)", mode)
+	}
+}
+
+// Gate is a dependency checked at startup
+type Gate struct {
+	// Name identifies the dependency, e.g. "prometheus"
+	Name string
+
+	// Mode is how startup treats the dependency while it is unavailable
+	Mode Mode
+
+	// Check returns nil while the dependency is available
+	Check func(ctx context.Context) error
+}
+
+// Options configures the gates
+type Options struct {
+	// WaitTimeout bounds how long a dependency in wait mode is waited for
+	WaitTimeout time.Duration
+
+	// InitialBackoff is the delay before the second check in wait mode; it doubles
+	// after every failed check up to MaxBackoff
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+
+	// CheckTimeout bounds a single check
+	CheckTimeout time.Duration
+
+	// Recheck is how often dependencies are checked again after startup
+	Recheck time.Duration
+}
+
+// Result is the latest check of a dependency
+type Result struct {
+	Dependency string    `json:"dependency"`
+	Mode       Mode      `json:"mode"`
+	Available  bool      `json:"available"`
+	Attempts   int       `json:"attempts"`
+	Waited     string    `json:"waited,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	CheckedAt  time.Time `json:"checked_at"`
+}
+
+// Gates checks dependencies at startup and keeps their latest results
+type Gates struct {
+	opts Options
+	log  *logrus.Logger
+
+	mu      sync.RWMutex
+	gates   []Gate
+	results map[string]Result
+
+	// Clock and delay, replaceable in tests
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// New creates the gates; zero options take their defaults
+func New(opts Options, log *logrus.Logger) *Gates {
+	if opts.WaitTimeout <= 0 {
+		opts.WaitTimeout = DefaultWaitTimeout
+	}
+	if opts.InitialBackoff <= 0 {
+		opts.InitialBackoff = DefaultInitialBackoff
+	}
+	if opts.MaxBackoff < opts.InitialBackoff {
+		opts.MaxBackoff = max(DefaultMaxBackoff, opts.InitialBackoff)
+	}
+	if opts.CheckTimeout <= 0 {
+		opts.CheckTimeout = DefaultCheckTimeout
+	}
+	if opts.Recheck <= 0 {
+		opts.Recheck = DefaultRecheck
+	}
+	return &Gates{
+		opts:    opts,
+		log:     log,
+		results: make(map[string]Result),
+		now:     time.Now,
+		sleep:   sleepContext,
+	}
+}
+
+// Run checks a dependency according to its mode. It returns an error only in fail
+// mode, when the engine must not start.
+func (g *Gates) Run(ctx context.Context, gate Gate) error {
+	start := g.now()
+	backoff := g.opts.InitialBackoff
+	entry := g.log.WithFields(logrus.Fields{"dependency": gate.Name, "mode": gate.Mode})
+
+	attempts := 0
+	var err error
+	for {
+		attempts++
+		err = g.check(ctx, gate)
+		if err == nil || gate.Mode != ModeWait {
+			break
+		}
+		waited := g.now().Sub(start)
+		if waited+backoff > g.opts.WaitTimeout {
+			break
+		}
+		entry.WithError(err).WithField("retry_in", backoff).Info("Waiting for dependency")
+		if g.sleep(ctx, backoff) != nil {
+			break
+		}
+		backoff = min(backoff*2, g.opts.MaxBackoff)
+	}
+
+	result := g.record(gate, err)
+	result.Attempts = attempts
+	if gate.Mode == ModeWait {
+		result.Waited = g.now().Sub(start).Round(time.Millisecond).String()
+	}
+	g.mu.Lock()
+	g.gates = append(g.gates, gate)
+	g.results[gate.Name] = result
+	g.mu.Unlock()
+
+	switch {
+	case err == nil:
+		entry.WithField("attempts", attempts).Info("Dependency available")
+	case gate.Mode == ModeFail:
+		return fmt.Errorf("%s unavailable: %w", gate.Name, err)
+	case gate.Mode == ModeWait:
+		entry.WithError(err).WithField("waited", result.Waited).Error("Dependency still unavailable after waiting, starting degraded")
+	default:
+		entry.WithError(err).Warn("Dependency unavailable, starting degraded")
+	}
+	return nil
+}
+
+// Start re-checks the dependencies every Recheck interval until ctx is cancelled
+func (g *Gates) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(g.opts.Recheck)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				g.Recheck(ctx)
+			}
+		}
+	}()
+}
+
+// Recheck checks every dependency again and logs those that recovered or were lost
+func (g *Gates) Recheck(ctx context.Context) {
+	g.mu.RLock()
+	gates := append([]Gate(nil), g.gates...)
+	g.mu.RUnlock()
+
+	for _, gate := range gates {
+		err := g.check(ctx, gate)
+		result := g.record(gate, err)
+
+		g.mu.Lock()
+		previous := g.results[gate.Name]
+		result.Attempts = previous.Attempts + 1
+		result.Waited = previous.Waited
+		g.results[gate.Name] = result
+		g.mu.Unlock()
+
+		entry := g.log.WithFields(logrus.Fields{"dependency": gate.Name, "mode": gate.Mode})
+		switch {
+		case result.Available && !previous.Available:
+			entry.Info("Dependency recovered")
+		case !result.Available && previous.Available:
+			entry.WithError(err).Warn("Dependency became unavailable")
+		}
+	}
+}
+
+// Results returns the latest result of every dependency, sorted by name
+func (g *Gates) Results() []Result {
+	if g == nil {
+		return nil
+	}
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	results := make([]Result, 0, len(g.results))
+	for _, result := range g.results {
+		results = append(results, result)
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Dependency < results[j].Dependency })
+	return results
+}
+
+// check runs a gate's check within the check timeout
+func (g *Gates) check(ctx context.Context, gate Gate) error {
+	ctx, cancel := context.WithTimeout(ctx, g.opts.CheckTimeout)
+	defer cancel()
+	return gate.Check(ctx)
+}
+
+// record builds the result of a check
+func (g *Gates) record(gate Gate, err error) Result {
+	result := Result{
+		Dependency: gate.Name,
+		Mode:       gate.Mode,
+		Available:  err == nil,
+		CheckedAt:  g.now(),
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// sleepContext waits for d or until ctx is cancelled
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
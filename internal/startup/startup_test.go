@@ -0,0 +1,114 @@
+package startup
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock advances when the gates sleep
+type fakeClock struct {
+	now    time.Time
+	sleeps []time.Duration
+}
+
+func newTestGates(t *testing.T, opts Options) (*Gates, *fakeClock) {
+	t.Helper()
+	log := logrus.New()
+	log.SetLevel(logrus.PanicLevel)
+	clock := &fakeClock{now: time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)}
+	gates := New(opts, log)
+	gates.now = func() time.Time { return clock.now }
+	gates.sleep = func(_ context.Context, d time.Duration) error {
+		clock.sleeps = append(clock.sleeps, d)
+		clock.now = clock.now.Add(d)
+		return nil
+	}
+	return gates, clock
+}
+
+// flaky fails its first n checks
+func flaky(n int) func(context.Context) error {
+	calls := 0
+	return func(context.Context) error {
+		calls++
+		if calls <= n {
+			return errors.New("connection refused")
+		}
+		return nil
+	}
+}
+
+func TestGates_Wait(t *testing.T) {
+	gates, clock := newTestGates(t, Options{WaitTimeout: 30 * time.Second, InitialBackoff: time.Second, MaxBackoff: 4 * time.Second})
+	ctx := context.Background()
+
+	require.NoError(t, gates.Run(ctx, Gate{Name: "prometheus", Mode: ModeWait, Check: flaky(3)}))
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second, 4 * time.Second}, clock.sleeps)
+	result := gates.Results()[0]
+	assert.True(t, result.Available)
+	assert.Equal(t, 4, result.Attempts)
+	assert.Equal(t, "7s", result.Waited)
+
+	// A dependency that does not come up in time starts degraded
+	clock.sleeps = nil
+	require.NoError(t, gates.Run(ctx, Gate{Name: "slow", Mode: ModeWait, Check: flaky(100)}))
+	var waited time.Duration
+	for _, d := range clock.sleeps {
+		waited += d
+	}
+	assert.LessOrEqual(t, waited, 30*time.Second)
+	result = gates.Results()[1]
+	assert.Equal(t, "slow", result.Dependency)
+	assert.False(t, result.Available)
+	assert.Equal(t, "connection refused", result.Error)
+}
+
+func TestGates_DegradedAndFail(t *testing.T) {
+	gates, clock := newTestGates(t, Options{})
+	ctx := context.Background()
+
+	require.NoError(t, gates.Run(ctx, Gate{Name: "kserve", Mode: ModeDegraded, Check: flaky(1)}))
+	assert.Empty(t, clock.sleeps, "degraded dependencies are checked once")
+	assert.False(t, gates.Results()[0].Available)
+
+	err := gates.Run(ctx, Gate{Name: "storage", Mode: ModeFail, Check: flaky(1)})
+	assert.EqualError(t, err, "storage unavailable: connection refused")
+
+	// Rechecks follow recoveries
+	gates.Recheck(ctx)
+	results := gates.Results()
+	require.Len(t, results, 2)
+	assert.Equal(t, "kserve", results[0].Dependency)
+	assert.True(t, results[0].Available)
+	assert.Equal(t, 2, results[0].Attempts)
+	assert.Equal(t, ModeFail, results[1].Mode)
+}
+
+func TestWritableDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "data")
+	require.NoError(t, WritableDir(dir)(context.Background()))
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries, "the probe file is removed")
+
+	file := filepath.Join(t.TempDir(), "file")
+	require.NoError(t, os.WriteFile(file, nil, 0o600))
+	assert.ErrorContains(t, WritableDir(filepath.Join(file, "data"))(context.Background()), "cannot create")
+}
+
+func TestParseMode(t *testing.T) {
+	mode, err := ParseMode("wait")
+	require.NoError(t, err)
+	assert.Equal(t, ModeWait, mode)
+
+	_, err = ParseMode("retry")
+	assert.ErrorContains(t, err, `invalid startup mode "retry"`)
+}
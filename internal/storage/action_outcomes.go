@@ -0,0 +1,268 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// DefaultActionOutcomeLimit is the number of action outcomes kept; the oldest are
+// dropped first
+const DefaultActionOutcomeLimit = 5000
+
+// ActionStats counts the verified outcomes of an action for an issue type
+type ActionStats struct {
+	Attempts  int `json:"attempts"`
+	Successes int `json:"successes"`
+}
+
+// SuccessRate returns the fraction of successful attempts, 0 without attempts
+func (s ActionStats) SuccessRate() float64 {
+	if s.Attempts == 0 {
+		return 0
+	}
+	return float64(s.Successes) / float64(s.Attempts)
+}
+
+// ActionOutcomeStore persists the verified outcomes of remediation actions
+type ActionOutcomeStore struct {
+	outcomes []*models.ActionOutcome
+	limit    int
+	mu       sync.RWMutex
+	dataFile string
+}
+
+// NewActionOutcomeStore creates an action outcome store in dataDir (DATA_DIR or
+// /app/data if empty) keeping at most limit outcomes (DefaultActionOutcomeLimit if not
+// positive)
+func NewActionOutcomeStore(dataDir string, limit int) *ActionOutcomeStore {
+	if dataDir == "" {
+		dataDir = os.Getenv("DATA_DIR")
+	}
+	if dataDir == "" {
+		dataDir = "/app/data"
+	}
+	if limit <= 0 {
+		limit = DefaultActionOutcomeLimit
+	}
+
+	if err := os.MkdirAll(dataDir, 0o755); err != nil {
+		fmt.Printf("Warning: Could not create data directory %s: %v\n", dataDir, err)
+	}
+	store := &ActionOutcomeStore{
+		limit:    limit,
+		dataFile: filepath.Join(dataDir, "action_outcomes.json"),
+	}
+
+	if err := store.load(); err != nil {
+		fmt.Printf("Warning: Could not load action outcomes from disk: %v\n", err)
+	} else {
+		fmt.Printf("Loaded %d action outcomes from %s\n", len(store.outcomes), store.dataFile)
+	}
+
+	return store
+}
+
+// NewMemoryActionOutcomeStore creates an action outcome store that is never persisted,
+// keeping at most limit outcomes (DefaultActionOutcomeLimit if not positive)
+func NewMemoryActionOutcomeStore(limit int) *ActionOutcomeStore {
+	if limit <= 0 {
+		limit = DefaultActionOutcomeLimit
+	}
+	return &ActionOutcomeStore{limit: limit}
+}
+
+// load reads action outcomes from the JSON file
+func (s *ActionOutcomeStore) load() error {
+	data, err := os.ReadFile(s.dataFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read data file: %w", err)
+	}
+
+	var outcomes []*models.ActionOutcome
+	if err := json.Unmarshal(data, &outcomes); err != nil {
+		return fmt.Errorf("failed to unmarshal action outcomes: %w", err)
+	}
+	sort.SliceStable(outcomes, func(i, j int) bool {
+		return outcomes[i].VerifiedAt.Before(outcomes[j].VerifiedAt)
+	})
+	s.outcomes = outcomes
+	s.prune()
+
+	return nil
+}
+
+// save writes all action outcomes to the JSON file. Callers must hold s.mu.
+func (s *ActionOutcomeStore) save() error {
+	if s.dataFile == "" {
+		return nil
+	}
+	dir := filepath.Dir(s.dataFile)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	data, err := json.Marshal(s.outcomes)
+	if err != nil {
+		return fmt.Errorf("failed to marshal action outcomes: %w", err)
+	}
+
+	// Write to temp file first, then rename (atomic)
+	tmpFile := s.dataFile + ".tmp"
+	if err := os.WriteFile(tmpFile, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write temp file: %w", err)
+	}
+	if err := os.Rename(tmpFile, s.dataFile); err != nil {
+		return fmt.Errorf("failed to rename temp file: %w", err)
+	}
+
+	return nil
+}
+
+// prune drops the oldest outcomes beyond the limit. Callers must hold s.mu.
+func (s *ActionOutcomeStore) prune() {
+	if len(s.outcomes) > s.limit {
+		s.outcomes = append([]*models.ActionOutcome(nil), s.outcomes[len(s.outcomes)-s.limit:]...)
+	}
+}
+
+// Add validates and stores an action outcome, assigning its ID and verification time
+func (s *ActionOutcomeStore) Add(outcome *models.ActionOutcome) (*models.ActionOutcome, error) {
+	if err := outcome.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := *outcome
+	if stored.ID == "" {
+		stored.ID = generateActionOutcomeID()
+	}
+	if stored.VerifiedAt.IsZero() {
+		stored.VerifiedAt = time.Now()
+	}
+
+	previous := s.outcomes
+	s.outcomes = append(s.outcomes, &stored)
+	s.prune()
+	if err := s.save(); err != nil {
+		s.outcomes = previous
+		return nil, fmt.Errorf("failed to persist action outcome: %w", err)
+	}
+	return &stored, nil
+}
+
+// Stats returns the outcome counts of each action tried for an issue type, keyed by
+// action
+func (s *ActionOutcomeStore) Stats(issueType string) map[string]ActionStats {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	stats := make(map[string]ActionStats)
+	for _, outcome := range s.outcomes {
+		if outcome.IssueType != issueType {
+			continue
+		}
+		actionStats := stats[outcome.Action]
+		actionStats.Attempts++
+		if outcome.Success {
+			actionStats.Successes++
+		}
+		stats[outcome.Action] = actionStats
+	}
+	return stats
+}
+
+// IssueTypes returns the issue types with recorded outcomes, sorted
+func (s *ActionOutcomeStore) IssueTypes() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	seen := make(map[string]bool)
+	var issueTypes []string
+	for _, outcome := range s.outcomes {
+		if !seen[outcome.IssueType] {
+			seen[outcome.IssueType] = true
+			issueTypes = append(issueTypes, outcome.IssueType)
+		}
+	}
+	sort.Strings(issueTypes)
+	return issueTypes
+}
+
+// List returns a copy of the stored action outcomes, oldest first
+func (s *ActionOutcomeStore) List() []models.ActionOutcome {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	outcomes := make([]models.ActionOutcome, 0, len(s.outcomes))
+	for _, outcome := range s.outcomes {
+		outcomes = append(outcomes, *outcome)
+	}
+	return outcomes
+}
+
+// Restore imports action outcomes from a backup. Without replace they are merged with
+// the stored ones, an imported outcome overwriting a stored one with the same ID.
+func (s *ActionOutcomeStore) Restore(outcomes []models.ActionOutcome, replace bool) error {
+	for i := range outcomes {
+		if outcomes[i].ID == "" {
+			return fmt.Errorf("action outcome without id in backup")
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previous := s.outcomes
+	byID := make(map[string]*models.ActionOutcome, len(previous)+len(outcomes))
+	if !replace {
+		for _, outcome := range previous {
+			byID[outcome.ID] = outcome
+		}
+	}
+	for i := range outcomes {
+		outcome := outcomes[i]
+		byID[outcome.ID] = &outcome
+	}
+
+	restored := make([]*models.ActionOutcome, 0, len(byID))
+	for _, outcome := range byID {
+		restored = append(restored, outcome)
+	}
+	sort.SliceStable(restored, func(i, j int) bool {
+		return restored[i].VerifiedAt.Before(restored[j].VerifiedAt)
+	})
+
+	s.outcomes = restored
+	s.prune()
+	if err := s.save(); err != nil {
+		s.outcomes = previous
+		return fmt.Errorf("failed to persist restored action outcomes: %w", err)
+	}
+	return nil
+}
+
+// Count returns the number of stored action outcomes
+func (s *ActionOutcomeStore) Count() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.outcomes)
+}
+
+// generateActionOutcomeID generates a unique action outcome ID
+func generateActionOutcomeID() string {
+	return "outcome-" + uuid.New().String()[:8]
+}
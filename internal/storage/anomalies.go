@@ -0,0 +1,389 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// DefaultAnomalyLimit is the number of anomaly records kept; the oldest are dropped
+// first
+const DefaultAnomalyLimit = 10000
+
+// DefaultAnomalyFlushInterval is how often added anomalies are written to disk
+const DefaultAnomalyFlushInterval = 10 * time.Second
+
+// AnomalyStore persists detected anomalies. Added anomalies are kept in memory and
+// written to disk in batches by Start and Flush, so analyses do not wait for the file
+// to be rewritten.
+type AnomalyStore struct {
+	records  map[string]*models.AnomalyRecord
+	limit    int
+	mu       sync.RWMutex
+	dataFile string
+	now      func() time.Time
+
+	// dirty is set when added anomalies have not been written yet
+	dirty bool
+}
+
+// AnomalyFilter defines filter options for listing anomalies
+type AnomalyFilter struct {
+	Namespace string
+	Severity  string
+
+	// Since and Until bound the detection time (inclusive); zero means unbounded
+	Since time.Time
+	Until time.Time
+
+	Limit int
+}
+
+// Matches reports whether an anomaly passes the filter
+func (f *AnomalyFilter) Matches(record *models.AnomalyRecord) bool {
+	if f.Namespace != "" && record.Namespace != f.Namespace {
+		return false
+	}
+	if f.Severity != "" && record.Severity != f.Severity {
+		return false
+	}
+	if !f.Since.IsZero() && record.DetectedAt.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && record.DetectedAt.After(f.Until) {
+		return false
+	}
+	return true
+}
+
+// NewAnomalyStore creates an anomaly store in dataDir (DATA_DIR or /app/data if empty)
+// keeping at most limit records (DefaultAnomalyLimit if not positive)
+func NewAnomalyStore(dataDir string, limit int) *AnomalyStore {
+	if dataDir == "" {
+		dataDir = os.Getenv("DATA_DIR")
+	}
+	if dataDir == "" {
+		dataDir = "/app/data"
+	}
+	if limit <= 0 {
+		limit = DefaultAnomalyLimit
+	}
+
+	if err := os.MkdirAll(dataDir, 0o755); err != nil {
+		fmt.Printf("Warning: Could not create data directory %s: %v\n", dataDir, err)
+	}
+	store := &AnomalyStore{
+		records:  make(map[string]*models.AnomalyRecord),
+		limit:    limit,
+		dataFile: filepath.Join(dataDir, "anomalies.json"),
+		now:      time.Now,
+	}
+
+	if err := store.load(); err != nil {
+		fmt.Printf("Warning: Could not load anomalies from disk: %v\n", err)
+	} else {
+		fmt.Printf("Loaded %d anomalies from %s\n", len(store.records), store.dataFile)
+	}
+
+	return store
+}
+
+// NewMemoryAnomalyStore creates an anomaly store that is never persisted, keeping at
+// most limit records (DefaultAnomalyLimit if not positive)
+func NewMemoryAnomalyStore(limit int) *AnomalyStore {
+	if limit <= 0 {
+		limit = DefaultAnomalyLimit
+	}
+	return &AnomalyStore{
+		records: make(map[string]*models.AnomalyRecord),
+		limit:   limit,
+		now:     time.Now,
+	}
+}
+
+// SetClock replaces the clock of creation times, e.g. with the fixed clock of
+// deterministic mode
+func (s *AnomalyStore) SetClock(now func() time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.now = now
+}
+
+// Start writes added anomalies to disk every interval until ctx is cancelled, then
+// writes any that are still pending
+func (s *AnomalyStore) Start(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultAnomalyFlushInterval
+	}
+
+	flush := func() {
+		if err := s.Flush(); err != nil {
+			fmt.Printf("Warning: Could not persist anomalies: %v\n", err)
+		}
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				flush()
+				return
+			case <-ticker.C:
+				flush()
+			}
+		}
+	}()
+}
+
+// Flush writes added anomalies to disk if any are pending
+func (s *AnomalyStore) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.dirty {
+		return nil
+	}
+	if err := s.save(); err != nil {
+		return fmt.Errorf("failed to persist anomalies: %w", err)
+	}
+	return nil
+}
+
+// load reads anomalies from the JSON file
+func (s *AnomalyStore) load() error {
+	data, err := os.ReadFile(s.dataFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read data file: %w", err)
+	}
+
+	var records []*models.AnomalyRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		return fmt.Errorf("failed to unmarshal anomalies: %w", err)
+	}
+
+	for _, record := range records {
+		s.records[record.ID] = record
+	}
+	s.prune()
+
+	return nil
+}
+
+// save writes all anomalies to the JSON file, including pending ones. Callers must
+// hold s.mu.
+func (s *AnomalyStore) save() error {
+	if s.dataFile == "" {
+		s.dirty = false
+		return nil
+	}
+	dir := filepath.Dir(s.dataFile)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	records := make([]*models.AnomalyRecord, 0, len(s.records))
+	for _, record := range s.records {
+		records = append(records, record)
+	}
+
+	data, err := json.Marshal(records)
+	if err != nil {
+		return fmt.Errorf("failed to marshal anomalies: %w", err)
+	}
+
+	// Write to temp file first, then rename (atomic)
+	tmpFile := s.dataFile + ".tmp"
+	if err := os.WriteFile(tmpFile, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write temp file: %w", err)
+	}
+	if err := os.Rename(tmpFile, s.dataFile); err != nil {
+		return fmt.Errorf("failed to rename temp file: %w", err)
+	}
+
+	s.dirty = false
+	return nil
+}
+
+// prune drops the oldest records beyond the limit, by ID among those created at the
+// same time. Callers must hold s.mu.
+func (s *AnomalyStore) prune() {
+	if len(s.records) <= s.limit {
+		return
+	}
+
+	records := make([]*models.AnomalyRecord, 0, len(s.records))
+	for _, record := range s.records {
+		records = append(records, record)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].CreatedAt.Equal(records[j].CreatedAt) {
+			return records[i].ID < records[j].ID
+		}
+		return records[i].CreatedAt.Before(records[j].CreatedAt)
+	})
+	for _, record := range records[:len(records)-s.limit] {
+		delete(s.records, record.ID)
+	}
+}
+
+// Add stores new anomalies, assigning IDs and creation times. They are written to disk
+// by the next Flush.
+func (s *AnomalyStore) Add(records []*models.AnomalyRecord) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	for _, record := range records {
+		if record.ID == "" {
+			record.ID = generateAnomalyID()
+		}
+		if record.CreatedAt.IsZero() {
+			record.CreatedAt = now
+		}
+		s.records[record.ID] = record
+	}
+	s.prune()
+	s.dirty = true
+}
+
+// AttachIncident links the anomalies of an analysis to the incident raised from it and
+// returns how many were linked
+func (s *AnomalyStore) AttachIncident(analysisID, incidentID string) (int, error) {
+	if analysisID == "" {
+		return 0, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	linked := 0
+	for id, record := range s.records {
+		if record.AnalysisID != analysisID {
+			continue
+		}
+		updated := *record
+		updated.IncidentID = incidentID
+		s.records[id] = &updated
+		linked++
+	}
+	if linked == 0 {
+		return 0, nil
+	}
+
+	if err := s.save(); err != nil {
+		return 0, fmt.Errorf("failed to persist anomaly incident: %w", err)
+	}
+	return linked, nil
+}
+
+// List returns copies of the anomalies matching the filter, most recently detected
+// first
+func (s *AnomalyStore) List(filter AnomalyFilter) []models.AnomalyRecord {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	results := make([]models.AnomalyRecord, 0)
+	for _, record := range s.records {
+		if filter.Matches(record) {
+			results = append(results, *record)
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].DetectedAt.Equal(results[j].DetectedAt) {
+			return results[i].ID < results[j].ID
+		}
+		return results[i].DetectedAt.After(results[j].DetectedAt)
+	})
+
+	if filter.Limit > 0 && len(results) > filter.Limit {
+		results = results[:filter.Limit]
+	}
+	return results
+}
+
+// Restore loads anomalies from a backup. With replace, existing anomalies are dropped
+// first; otherwise restored anomalies are merged in, overwriting those with the same
+// ID. The store limit still applies.
+func (s *AnomalyStore) Restore(records []models.AnomalyRecord, replace bool) error {
+	for i := range records {
+		if records[i].ID == "" {
+			return fmt.Errorf("anomaly without id in backup")
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previous := s.records
+	restored := make(map[string]*models.AnomalyRecord, len(records))
+	if !replace {
+		for id, record := range previous {
+			restored[id] = record
+		}
+	}
+	for i := range records {
+		record := records[i]
+		restored[record.ID] = &record
+	}
+
+	s.records = restored
+	s.prune()
+	if err := s.save(); err != nil {
+		s.records = previous
+		return fmt.Errorf("failed to persist restored anomalies: %w", err)
+	}
+	return nil
+}
+
+// DeleteMatching removes every anomaly for which match returns true and returns how
+// many were removed
+func (s *AnomalyStore) DeleteMatching(match func(*models.AnomalyRecord) bool) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var removed []*models.AnomalyRecord
+	for id, record := range s.records {
+		if match(record) {
+			removed = append(removed, record)
+			delete(s.records, id)
+		}
+	}
+	if len(removed) == 0 {
+		return 0, nil
+	}
+
+	if err := s.save(); err != nil {
+		for _, record := range removed {
+			s.records[record.ID] = record
+		}
+		return 0, fmt.Errorf("failed to persist anomaly deletion: %w", err)
+	}
+	return len(removed), nil
+}
+
+// Count returns the number of stored anomalies
+func (s *AnomalyStore) Count() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.records)
+}
+
+// generateAnomalyID generates a unique anomaly ID
+func generateAnomalyID() string {
+	return "anom-" + uuid.New().String()[:8]
+}
@@ -0,0 +1,97 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+func TestAnomalyStore(t *testing.T) {
+	dir := t.TempDir()
+	store := NewAnomalyStore(dir, 3)
+	detected := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	records := []*models.AnomalyRecord{
+		{Namespace: "payments", Severity: "critical", AnalysisID: "an-1", DetectedAt: detected, CreatedAt: detected},
+		{Namespace: "payments", Severity: "warning", AnalysisID: "an-2", DetectedAt: detected.Add(time.Hour), CreatedAt: detected.Add(time.Hour)},
+		{Namespace: "checkout", Severity: "warning", DetectedAt: detected.Add(2 * time.Hour), CreatedAt: detected.Add(2 * time.Hour)},
+	}
+	store.Add(records)
+	assert.NotEmpty(t, records[0].ID)
+
+	all := store.List(AnomalyFilter{})
+	require.Len(t, all, 3)
+	assert.Equal(t, "checkout", all[0].Namespace, "most recently detected first")
+
+	assert.Len(t, store.List(AnomalyFilter{Namespace: "payments"}), 2)
+	assert.Len(t, store.List(AnomalyFilter{Severity: "warning"}), 2)
+	assert.Len(t, store.List(AnomalyFilter{Since: detected.Add(time.Hour)}), 2)
+	assert.Len(t, store.List(AnomalyFilter{Until: detected}), 1)
+	assert.Len(t, store.List(AnomalyFilter{Limit: 1}), 1)
+
+	linked, err := store.AttachIncident("an-2", "inc-1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, linked)
+	linked, err = store.AttachIncident("", "inc-2")
+	require.NoError(t, err)
+	assert.Zero(t, linked)
+
+	// The oldest anomaly is dropped beyond the limit
+	store.SetClock(func() time.Time { return detected.Add(3 * time.Hour) })
+	store.Add([]*models.AnomalyRecord{{Namespace: "payments", Severity: "info", DetectedAt: detected.Add(3 * time.Hour)}})
+	assert.Equal(t, 3, store.Count())
+	assert.Len(t, store.List(AnomalyFilter{Severity: "critical"}), 0)
+	info := store.List(AnomalyFilter{Severity: "info"})
+	require.Len(t, info, 1)
+	assert.Equal(t, detected.Add(3*time.Hour), info[0].CreatedAt, "created at the store's clock")
+
+	// Added anomalies are written by the next flush
+	assert.Empty(t, NewAnomalyStore(dir, 3).List(AnomalyFilter{Severity: "info"}))
+	require.NoError(t, store.Flush())
+
+	reloaded := NewAnomalyStore(dir, 3)
+	assert.Equal(t, 3, reloaded.Count())
+	payments := reloaded.List(AnomalyFilter{Namespace: "payments", Severity: "warning"})
+	require.Len(t, payments, 1)
+	assert.Equal(t, "an-2", payments[0].AnalysisID)
+	assert.Equal(t, "inc-1", payments[0].IncidentID)
+}
+
+func TestAnomalyStore_PruneTieBreaker(t *testing.T) {
+	store := NewMemoryAnomalyStore(2)
+	created := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	store.Add([]*models.AnomalyRecord{
+		{ID: "anom-c", CreatedAt: created},
+		{ID: "anom-a", CreatedAt: created},
+		{ID: "anom-b", CreatedAt: created},
+	})
+
+	// Among anomalies created at the same time, the lowest ID is dropped first
+	ids := make([]string, 0, 2)
+	for _, record := range store.List(AnomalyFilter{}) {
+		ids = append(ids, record.ID)
+	}
+	assert.Equal(t, []string{"anom-b", "anom-c"}, ids)
+}
+
+func TestAnomalyStore_Start(t *testing.T) {
+	dir := t.TempDir()
+	store := NewAnomalyStore(dir, 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	store.Start(ctx, time.Hour)
+	store.Add([]*models.AnomalyRecord{{Namespace: "payments", Severity: "warning"}})
+	assert.Zero(t, NewAnomalyStore(dir, 0).Count())
+
+	// Pending anomalies are written when the store stops
+	cancel()
+	assert.Eventually(t, func() bool {
+		return NewAnomalyStore(dir, 0).Count() == 1
+	}, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,257 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// Analysis artifact store defaults
+const (
+	DefaultArtifactPendingTTL   = time.Hour
+	DefaultArtifactPendingLimit = 500
+)
+
+// ErrArtifactNotFound is returned when an analysis artifact does not exist, has
+// expired or is already attached to an incident
+var ErrArtifactNotFound = errors.New("analysis artifact not found")
+
+// ArtifactStore keeps the artifacts of recent anomaly analyses in memory until an
+// incident is created from one of them, and persists the attached artifacts until
+// their incident is deleted
+type ArtifactStore struct {
+	pending      map[string]*models.AnalysisArtifact
+	attached     []*models.AnalysisArtifact
+	pendingTTL   time.Duration
+	pendingLimit int
+	mu           sync.Mutex
+	dataFile     string
+	now          func() time.Time
+}
+
+// NewArtifactStore creates an artifact store in dataDir (DATA_DIR or /app/data if
+// empty). Unattached artifacts are kept for pendingTTL, at most pendingLimit of them;
+// non-positive values select the defaults.
+func NewArtifactStore(dataDir string, pendingTTL time.Duration, pendingLimit int) *ArtifactStore {
+	if dataDir == "" {
+		dataDir = os.Getenv("DATA_DIR")
+	}
+	if dataDir == "" {
+		dataDir = "/app/data"
+	}
+	if pendingTTL <= 0 {
+		pendingTTL = DefaultArtifactPendingTTL
+	}
+	if pendingLimit <= 0 {
+		pendingLimit = DefaultArtifactPendingLimit
+	}
+
+	if err := os.MkdirAll(dataDir, 0o755); err != nil {
+		fmt.Printf("Warning: Could not create data directory %s: %v\n", dataDir, err)
+	}
+	store := &ArtifactStore{
+		pending:      make(map[string]*models.AnalysisArtifact),
+		pendingTTL:   pendingTTL,
+		pendingLimit: pendingLimit,
+		dataFile:     filepath.Join(dataDir, "analysis_artifacts.json"),
+		now:          time.Now,
+	}
+
+	if err := store.load(); err != nil {
+		fmt.Printf("Warning: Could not load analysis artifacts from disk: %v\n", err)
+	} else {
+		fmt.Printf("Loaded %d analysis artifacts from %s\n", len(store.attached), store.dataFile)
+	}
+
+	return store
+}
+
+// load reads the attached artifacts from the JSON file
+func (s *ArtifactStore) load() error {
+	data, err := os.ReadFile(s.dataFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read data file: %w", err)
+	}
+
+	var artifacts []*models.AnalysisArtifact
+	if err := json.Unmarshal(data, &artifacts); err != nil {
+		return fmt.Errorf("failed to unmarshal analysis artifacts: %w", err)
+	}
+	s.attached = artifacts
+	return nil
+}
+
+// save writes the attached artifacts to the JSON file. Callers must hold s.mu.
+func (s *ArtifactStore) save() error {
+	dir := filepath.Dir(s.dataFile)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	data, err := json.Marshal(s.attached)
+	if err != nil {
+		return fmt.Errorf("failed to marshal analysis artifacts: %w", err)
+	}
+
+	// Write to temp file first, then rename (atomic)
+	tmpFile := s.dataFile + ".tmp"
+	if err := os.WriteFile(tmpFile, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write temp file: %w", err)
+	}
+	if err := os.Rename(tmpFile, s.dataFile); err != nil {
+		return fmt.Errorf("failed to rename temp file: %w", err)
+	}
+
+	return nil
+}
+
+// expire drops pending artifacts older than the TTL and the oldest beyond the limit.
+// Callers must hold s.mu.
+func (s *ArtifactStore) expire() {
+	cutoff := s.now().Add(-s.pendingTTL)
+	for id, artifact := range s.pending {
+		if artifact.CreatedAt.Before(cutoff) {
+			delete(s.pending, id)
+		}
+	}
+	if len(s.pending) <= s.pendingLimit {
+		return
+	}
+
+	oldest := make([]*models.AnalysisArtifact, 0, len(s.pending))
+	for _, artifact := range s.pending {
+		oldest = append(oldest, artifact)
+	}
+	sort.Slice(oldest, func(i, j int) bool { return oldest[i].CreatedAt.Before(oldest[j].CreatedAt) })
+	for _, artifact := range oldest[:len(oldest)-s.pendingLimit] {
+		delete(s.pending, artifact.ID)
+	}
+}
+
+// Remember keeps an artifact until it is attached to an incident or expires, assigning
+// its ID and creation time. It returns the ID.
+func (s *ArtifactStore) Remember(artifact *models.AnalysisArtifact) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := *artifact
+	stored.ID = generateAnalysisID()
+	stored.CreatedAt = s.now()
+	s.pending[stored.ID] = &stored
+	s.expire()
+	return stored.ID
+}
+
+// Pending reports whether an artifact can still be attached to an incident
+func (s *ArtifactStore) Pending(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.expire()
+	_, ok := s.pending[id]
+	return ok
+}
+
+// Attach persists a pending artifact as part of an incident
+func (s *ArtifactStore) Attach(id, incidentID string) (*models.AnalysisArtifact, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.expire()
+	artifact, ok := s.pending[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrArtifactNotFound, id)
+	}
+
+	attached := *artifact
+	attached.IncidentID = incidentID
+	s.attached = append(s.attached, &attached)
+	if err := s.save(); err != nil {
+		s.attached = s.attached[:len(s.attached)-1]
+		return nil, err
+	}
+	delete(s.pending, id)
+
+	result := attached
+	return &result, nil
+}
+
+// ForIncident returns the artifacts attached to an incident, oldest first
+func (s *ArtifactStore) ForIncident(incidentID string) []*models.AnalysisArtifact {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var result []*models.AnalysisArtifact
+	for _, artifact := range s.attached {
+		if artifact.IncidentID == incidentID {
+			copied := *artifact
+			result = append(result, &copied)
+		}
+	}
+	return result
+}
+
+// DeleteIncident removes the artifacts attached to an incident and returns how many
+// were removed
+func (s *ArtifactStore) DeleteIncident(incidentID string) (int, error) {
+	return s.DeleteIncidents([]string{incidentID})
+}
+
+// DeleteIncidents removes the artifacts attached to any of the incidents and returns how
+// many were removed
+func (s *ArtifactStore) DeleteIncidents(incidentIDs []string) (int, error) {
+	deleted := make(map[string]bool, len(incidentIDs))
+	for _, id := range incidentIDs {
+		deleted[id] = true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	kept := make([]*models.AnalysisArtifact, 0, len(s.attached))
+	for _, artifact := range s.attached {
+		if !deleted[artifact.IncidentID] {
+			kept = append(kept, artifact)
+		}
+	}
+	removed := len(s.attached) - len(kept)
+	if removed == 0 {
+		return 0, nil
+	}
+
+	previous := s.attached
+	s.attached = kept
+	if err := s.save(); err != nil {
+		s.attached = previous
+		return 0, err
+	}
+	return removed, nil
+}
+
+// Track removes the artifacts of incidents as they are deleted from incidents, including
+// by retention and namespace purges. Removal runs as part of the deletion, so artifacts
+// are not left behind when incident change subscribers fall behind.
+func (s *ArtifactStore) Track(incidents *IncidentStore) {
+	incidents.OnDelete(func(incidentIDs []string) {
+		if _, err := s.DeleteIncidents(incidentIDs); err != nil {
+			fmt.Printf("Warning: Could not delete analysis artifacts of %d incidents: %v\n", len(incidentIDs), err)
+		}
+	})
+}
+
+// generateAnalysisID generates a unique analysis ID
+func generateAnalysisID() string {
+	return "analysis-" + uuid.New().String()
+}
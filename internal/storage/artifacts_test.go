@@ -0,0 +1,104 @@
+package storage
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+func TestArtifactStore(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 10, 14, 3, 12, 0, 0, time.UTC)
+	store := NewArtifactStore(dir, time.Hour, 2)
+	store.now = func() time.Time { return now }
+
+	artifact := &models.AnalysisArtifact{
+		Request:       json.RawMessage(`{"namespace":"payments"}`),
+		Model:         "anomaly-detector",
+		FeatureNames:  []string{"cpu_usage_value"},
+		Features:      []float64{0.42},
+		Queries:       []models.ArtifactQuery{{Kind: "instant", Query: "up"}},
+		ModelResponse: json.RawMessage(`{"predictions":[-1]}`),
+	}
+	id := store.Remember(artifact)
+	assert.True(t, store.Pending(id))
+	assert.Empty(t, artifact.ID, "the caller's artifact is not modified")
+
+	attached, err := store.Attach(id, "inc-1")
+	require.NoError(t, err)
+	assert.Equal(t, "inc-1", attached.IncidentID)
+	assert.Equal(t, now, attached.CreatedAt)
+	assert.False(t, store.Pending(id), "an artifact is attached once")
+	_, err = store.Attach(id, "inc-2")
+	assert.ErrorIs(t, err, ErrArtifactNotFound)
+
+	// Attached artifacts survive a restart
+	reloaded := NewArtifactStore(dir, time.Hour, 2)
+	artifacts := reloaded.ForIncident("inc-1")
+	require.Len(t, artifacts, 1)
+	assert.JSONEq(t, `{"predictions":[-1]}`, string(artifacts[0].ModelResponse))
+	assert.Equal(t, []float64{0.42}, artifacts[0].Features)
+	assert.Empty(t, reloaded.ForIncident("inc-2"))
+
+	t.Run("pending artifacts expire", func(t *testing.T) {
+		old := store.Remember(artifact)
+		now = now.Add(61 * time.Minute)
+		assert.False(t, store.Pending(old))
+	})
+
+	t.Run("oldest pending artifacts are dropped beyond the limit", func(t *testing.T) {
+		first := store.Remember(artifact)
+		now = now.Add(time.Second)
+		second := store.Remember(artifact)
+		now = now.Add(time.Second)
+		third := store.Remember(artifact)
+		assert.False(t, store.Pending(first))
+		assert.True(t, store.Pending(second))
+		assert.True(t, store.Pending(third))
+	})
+
+	t.Run("artifacts are deleted with their incident", func(t *testing.T) {
+		incidents := NewIncidentStoreWithPath(t.TempDir())
+		incident, err := incidents.Create(&models.Incident{Title: "High CPU", Description: "cpu", Severity: models.IncidentSeverityHigh, Target: "payments"})
+		require.NoError(t, err)
+		_, err = store.Attach(store.Remember(artifact), incident.ID)
+		require.NoError(t, err)
+		require.Len(t, store.ForIncident(incident.ID), 1)
+
+		store.Track(incidents)
+		require.NoError(t, incidents.Delete(incident.ID))
+		assert.Empty(t, store.ForIncident(incident.ID))
+		assert.Len(t, store.ForIncident("inc-1"), 1)
+	})
+}
+
+func TestArtifactStore_PurgeManyIncidents(t *testing.T) {
+	store := NewArtifactStore(t.TempDir(), time.Hour, 0)
+	incidents := NewIncidentStoreWithPath(t.TempDir())
+	store.Track(incidents)
+
+	// A subscriber that never reads would miss most of these deletions
+	_, unsubscribe := incidents.Subscribe(1)
+	defer unsubscribe()
+
+	var ids []string
+	for i := 0; i < 100; i++ {
+		incident, err := incidents.Create(&models.Incident{Title: "High CPU", Description: "cpu", Severity: models.IncidentSeverityHigh, Target: "payments"})
+		require.NoError(t, err)
+		_, err = store.Attach(store.Remember(&models.AnalysisArtifact{Model: "anomaly-detector"}), incident.ID)
+		require.NoError(t, err)
+		ids = append(ids, incident.ID)
+	}
+
+	purged, err := incidents.DeleteMatching(func(incident *models.Incident) bool { return incident.Target == "payments" })
+	require.NoError(t, err)
+	assert.Equal(t, 100, purged)
+	for _, id := range ids {
+		assert.Empty(t, store.ForIncident(id))
+	}
+}
@@ -0,0 +1,151 @@
+package storage
+
+import (
+	"bufio"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// encryptedPrefix marks an encrypted field value: enc:v1:<key id>:<base64(nonce|ciphertext)>
+const encryptedPrefix = "enc:v1:"
+
+// keyIDPattern matches valid encryption key IDs
+var keyIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// EncryptionKey is a named AES key
+type EncryptionKey struct {
+	ID  string
+	Key []byte
+}
+
+// FieldCipher encrypts individual stored fields with AES-GCM. The primary key
+// encrypts; every key decrypts, so keys can be rotated by adding a new primary key
+// and keeping the old one until all data has been re-encrypted.
+type FieldCipher struct {
+	primary string
+	aeads   map[string]cipher.AEAD
+}
+
+// NewFieldCipher creates a cipher from keys; the first key is the primary key.
+// Keys must be 16, 24 or 32 bytes (AES-128, AES-192 or AES-256).
+func NewFieldCipher(keys []EncryptionKey) (*FieldCipher, error) {
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("at least one encryption key is required")
+	}
+
+	c := &FieldCipher{
+		primary: keys[0].ID,
+		aeads:   make(map[string]cipher.AEAD, len(keys)),
+	}
+	for _, key := range keys {
+		if !keyIDPattern.MatchString(key.ID) {
+			return nil, fmt.Errorf("invalid encryption key id %q: use letters, digits, '-' and '_'", key.ID)
+		}
+		if _, exists := c.aeads[key.ID]; exists {
+			return nil, fmt.Errorf("duplicate encryption key id %q", key.ID)
+		}
+		block, err := aes.NewCipher(key.Key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid encryption key %q: %w", key.ID, err)
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("invalid encryption key %q: %w", key.ID, err)
+		}
+		c.aeads[key.ID] = aead
+	}
+	return c, nil
+}
+
+// LoadFieldCipher reads keys from a file, typically a mounted Secret, with one
+// "<id>=<base64 key>" per line. The first key is the primary key; blank lines and
+// lines starting with # are ignored.
+func LoadFieldCipher(path string) (*FieldCipher, error) {
+	f, err := os.Open(path) //#nosec G304 -- path comes from operator configuration
+	if err != nil {
+		return nil, fmt.Errorf("failed to open encryption keys file: %w", err)
+	}
+	defer f.Close()
+
+	var keys []EncryptionKey
+	scanner := bufio.NewScanner(f)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		id, encoded, ok := strings.Cut(text, "=")
+		if !ok {
+			return nil, fmt.Errorf("encryption keys file line %d: expected <id>=<base64 key>", line)
+		}
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+		if err != nil {
+			return nil, fmt.Errorf("encryption keys file line %d: invalid base64: %w", line, err)
+		}
+		keys = append(keys, EncryptionKey{ID: strings.TrimSpace(id), Key: key})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read encryption keys file: %w", err)
+	}
+
+	return NewFieldCipher(keys)
+}
+
+// PrimaryKeyID returns the ID of the key used for encryption
+func (c *FieldCipher) PrimaryKeyID() string {
+	return c.primary
+}
+
+// Encrypt encrypts a value with the primary key. Empty values stay empty.
+func (c *FieldCipher) Encrypt(plaintext string) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+
+	aead := c.aeads[c.primary]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	// The key ID is authenticated so a value cannot be relabeled to another key
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), []byte(c.primary))
+	return encryptedPrefix + c.primary + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt returns the plaintext of an encrypted value. Values without the encrypted
+// prefix are returned unchanged, so data written before encryption was enabled can
+// still be read. stale is true when the value is not encrypted with the primary key
+// and should be re-encrypted.
+func (c *FieldCipher) Decrypt(value string) (plaintext string, stale bool, err error) {
+	rest, encrypted := strings.CutPrefix(value, encryptedPrefix)
+	if !encrypted {
+		return value, value != "", nil
+	}
+
+	keyID, encoded, ok := strings.Cut(rest, ":")
+	if !ok {
+		return "", false, fmt.Errorf("malformed encrypted value")
+	}
+	aead, exists := c.aeads[keyID]
+	if !exists {
+		return "", false, fmt.Errorf("unknown encryption key %q", keyID)
+	}
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", false, fmt.Errorf("malformed encrypted value")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	opened, err := aead.Open(nil, nonce, ciphertext, []byte(keyID))
+	if err != nil {
+		return "", false, fmt.Errorf("failed to decrypt value with key %q: %w", keyID, err)
+	}
+	return string(opened), keyID != c.primary, nil
+}
@@ -0,0 +1,126 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+func testKey(id string, b byte) EncryptionKey {
+	return EncryptionKey{ID: id, Key: bytes.Repeat([]byte{b}, 32)}
+}
+
+func TestFieldCipher_RoundTrip(t *testing.T) {
+	c, err := NewFieldCipher([]EncryptionKey{testKey("k2", 2), testKey("k1", 1)})
+	require.NoError(t, err)
+
+	encrypted, err := c.Encrypt("password=hunter2 in pod logs")
+	require.NoError(t, err)
+	assert.Contains(t, encrypted, "enc:v1:k2:")
+	assert.NotContains(t, encrypted, "hunter2")
+
+	plain, stale, err := c.Decrypt(encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "password=hunter2 in pod logs", plain)
+	assert.False(t, stale)
+
+	// Values from before encryption was enabled, or from an older key, are stale
+	plain, stale, err = c.Decrypt("plaintext")
+	require.NoError(t, err)
+	assert.Equal(t, "plaintext", plain)
+	assert.True(t, stale)
+
+	old, err := NewFieldCipher([]EncryptionKey{testKey("k1", 1)})
+	require.NoError(t, err)
+	oldValue, err := old.Encrypt("rotated")
+	require.NoError(t, err)
+	plain, stale, err = c.Decrypt(oldValue)
+	require.NoError(t, err)
+	assert.Equal(t, "rotated", plain)
+	assert.True(t, stale)
+
+	// Unknown keys and tampered values fail
+	other, err := NewFieldCipher([]EncryptionKey{testKey("k3", 3)})
+	require.NoError(t, err)
+	_, _, err = other.Decrypt(encrypted)
+	assert.Error(t, err)
+	_, _, err = c.Decrypt(encrypted[:len(encrypted)-4] + "AAAA")
+	assert.Error(t, err)
+
+	_, err = NewFieldCipher([]EncryptionKey{{ID: "short", Key: []byte("too short")}})
+	assert.Error(t, err)
+	_, err = NewFieldCipher([]EncryptionKey{testKey("k1", 1), testKey("k1", 2)})
+	assert.Error(t, err)
+}
+
+func TestLoadFieldCipher(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys")
+	content := "# primary first\n" +
+		"k2=" + base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{2}, 32)) + "\n\n" +
+		"k1=" + base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, 16)) + "\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	c, err := LoadFieldCipher(path)
+	require.NoError(t, err)
+	assert.Equal(t, "k2", c.PrimaryKeyID())
+
+	require.NoError(t, os.WriteFile(path, []byte("no-separator\n"), 0o600))
+	_, err = LoadFieldCipher(path)
+	assert.Error(t, err)
+}
+
+func TestIncidentStore_EncryptionAtRest(t *testing.T) {
+	dir := t.TempDir()
+	store := NewIncidentStoreWithPath(dir)
+	_, err := store.Create(&models.Incident{
+		ID:          "inc-1",
+		Title:       "Database credentials in logs",
+		Description: "password=hunter2",
+		Severity:    models.IncidentSeverityHigh,
+		Target:      "apps",
+		Events:      []models.IncidentEvent{{Type: models.IncidentEventResolved, Message: "token=abc123"}},
+	})
+	require.NoError(t, err)
+
+	// Enabling encryption re-encrypts incidents stored in plaintext
+	v1, err := NewFieldCipher([]EncryptionKey{testKey("v1", 1)})
+	require.NoError(t, err)
+	require.NoError(t, store.SetCipher(v1))
+
+	data, err := os.ReadFile(filepath.Join(dir, "incidents.json"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "hunter2")
+	assert.NotContains(t, string(data), "abc123")
+	assert.Contains(t, string(data), "Database credentials in logs", "titles are not encrypted")
+
+	incident, err := store.Get("inc-1")
+	require.NoError(t, err)
+	assert.Equal(t, "password=hunter2", incident.Description, "reads are transparently decrypted")
+
+	// Rotating to a new primary key re-encrypts on load
+	reloaded := NewIncidentStoreWithPath(dir)
+	v2, err := NewFieldCipher([]EncryptionKey{testKey("v2", 2), testKey("v1", 1)})
+	require.NoError(t, err)
+	require.NoError(t, reloaded.SetCipher(v2))
+	data, err = os.ReadFile(filepath.Join(dir, "incidents.json"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "enc:v1:v2:")
+	assert.NotContains(t, string(data), "enc:v1:v1:")
+
+	incident, err = reloaded.Get("inc-1")
+	require.NoError(t, err)
+	assert.Equal(t, "token=abc123", incident.Events[0].Message)
+
+	// Without the key the store refuses the cipher and keeps its state
+	other := NewIncidentStoreWithPath(dir)
+	wrong, err := NewFieldCipher([]EncryptionKey{testKey("v3", 3)})
+	require.NoError(t, err)
+	assert.Error(t, other.SetCipher(wrong))
+}
@@ -1,4 +1,3 @@
-
 // Package storage provides in-memory and persistent storage for coordination engine data.
 package storage
 
@@ -21,6 +20,82 @@ type IncidentStore struct {
 	incidents map[string]*models.Incident
 	mu        sync.RWMutex
 	dataFile  string
+
+	// subscribers receive incident changes (see Subscribe)
+	subscribers map[chan IncidentChange]struct{}
+
+	// labeler adds context labels to new incidents (see SetLabeler)
+	labeler IncidentLabeler
+
+	// tagger adds policy tags to new incidents (see SetTagger)
+	tagger IncidentTagger
+
+	// owners resolves the owner of new incidents (see SetOwnerResolver)
+	owners IncidentOwnerResolver
+
+	// prioritizer sets the priority of new and updated incidents (see SetPrioritizer)
+	prioritizer IncidentPrioritizer
+
+	// cipher encrypts payload fields on disk (see SetCipher)
+	cipher *FieldCipher
+
+	// redactor masks credentials in payload fields before they are stored (see SetRedactor)
+	redactor TextRedactor
+
+	// deleteHooks remove data tied to deleted incidents (see OnDelete)
+	deleteHooks []func(incidentIDs []string)
+}
+
+// IncidentLabeler supplies labels added to every incident when it is created,
+// e.g. during_upgrade=true while the cluster is upgrading
+type IncidentLabeler interface {
+	IncidentLabels() map[string]string
+}
+
+// IncidentTagger supplies tags added to an incident when it is created, e.g. the team
+// owning its namespace (implemented by tagging.Rules)
+type IncidentTagger interface {
+	IncidentTags(incident *models.Incident) map[string]string
+}
+
+// IncidentOwnerResolver returns the team owning an incident's workload or namespace,
+// or nil (implemented by ownership.Resolver)
+type IncidentOwnerResolver interface {
+	IncidentOwner(incident *models.Incident) *models.Owner
+}
+
+// OwnerTeamTag is the tag set to the owner's team on new incidents without it
+const OwnerTeamTag = "team"
+
+// IncidentPrioritizer derives the priority of an incident, e.g. from its severity,
+// namespace tier and blast radius (implemented by severity.IncidentPrioritizer)
+type IncidentPrioritizer interface {
+	IncidentPriority(incident *models.Incident) int
+}
+
+// TextRedactor masks credentials in free text (implemented by redact.Redactor)
+type TextRedactor interface {
+	Redact(source, text string) string
+}
+
+// redactionSource labels incident text in redaction metrics
+const redactionSource = "incident"
+
+// IncidentChangeType identifies the kind of change in an IncidentChange
+type IncidentChangeType string
+
+// Incident change types
+const (
+	IncidentCreated IncidentChangeType = "created"
+	IncidentUpdated IncidentChangeType = "updated"
+	IncidentDeleted IncidentChangeType = "deleted"
+)
+
+// IncidentChange describes a change to a stored incident. Incident is a copy taken
+// when the change happened, so subscribers can read it without holding the store lock.
+type IncidentChange struct {
+	Type     IncidentChangeType
+	Incident models.Incident
 }
 
 // NewIncidentStore creates a new incident store
@@ -41,8 +116,9 @@ func NewIncidentStoreWithPath(dataDir string) *IncidentStore {
 		fmt.Printf("Warning: Could not create data directory %s: %v\n", dataDir, err)
 	}
 	store := &IncidentStore{
-		incidents: make(map[string]*models.Incident),
-		dataFile:  filepath.Join(dataDir, "incidents.json"),
+		incidents:   make(map[string]*models.Incident),
+		dataFile:    filepath.Join(dataDir, "incidents.json"),
+		subscribers: make(map[chan IncidentChange]struct{}),
 	}
 
 	// Load existing data from disk
@@ -55,6 +131,15 @@ func NewIncidentStoreWithPath(dataDir string) *IncidentStore {
 	return store
 }
 
+// NewMemoryIncidentStore creates an incident store that is never persisted, for tests
+// and embedding
+func NewMemoryIncidentStore() *IncidentStore {
+	return &IncidentStore{
+		incidents:   make(map[string]*models.Incident),
+		subscribers: make(map[chan IncidentChange]struct{}),
+	}
+}
+
 // load reads incidents from the JSON file
 func (s *IncidentStore) load() error {
 	data, err := os.ReadFile(s.dataFile)
@@ -79,6 +164,10 @@ func (s *IncidentStore) load() error {
 
 // save writes all incidents to the JSON file
 func (s *IncidentStore) save() error {
+	if s.dataFile == "" {
+		return nil
+	}
+
 	// Ensure directory exists
 	dir := filepath.Dir(s.dataFile)
 	if err := os.MkdirAll(dir, 0755); err != nil {
@@ -87,6 +176,13 @@ func (s *IncidentStore) save() error {
 
 	incidents := make([]*models.Incident, 0, len(s.incidents))
 	for _, inc := range s.incidents {
+		if s.cipher != nil {
+			encrypted, err := encryptIncident(s.cipher, inc)
+			if err != nil {
+				return fmt.Errorf("failed to encrypt incident %s: %w", inc.ID, err)
+			}
+			inc = encrypted
+		}
 		incidents = append(incidents, inc)
 	}
 
@@ -108,8 +204,194 @@ func (s *IncidentStore) save() error {
 	return nil
 }
 
+// SetCipher encrypts incident payloads (description, summary and timeline messages)
+// on disk. Incidents already loaded are decrypted; any stored in plaintext or with a
+// key other than the primary key are re-encrypted with the primary key right away.
+// On error the store is left unchanged.
+func (s *IncidentStore) SetCipher(c *FieldCipher) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	decrypted := make(map[string]*models.Incident, len(s.incidents))
+	stale := 0
+	for id, incident := range s.incidents {
+		plain, wasStale, err := decryptIncident(c, incident)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt incident %s: %w", id, err)
+		}
+		if wasStale {
+			stale++
+		}
+		decrypted[id] = plain
+	}
+
+	previous := s.incidents
+	s.incidents = decrypted
+	s.cipher = c
+	if stale == 0 {
+		return nil
+	}
+	if err := s.save(); err != nil {
+		s.incidents = previous
+		s.cipher = nil
+		return fmt.Errorf("failed to re-encrypt incidents: %w", err)
+	}
+	return nil
+}
+
+// encryptIncident returns a copy of an incident with its payload fields encrypted
+func encryptIncident(c *FieldCipher, incident *models.Incident) (*models.Incident, error) {
+	encrypted := *incident
+	var err error
+	if encrypted.Description, err = c.Encrypt(incident.Description); err != nil {
+		return nil, err
+	}
+	if encrypted.Summary, err = c.Encrypt(incident.Summary); err != nil {
+		return nil, err
+	}
+	if len(incident.Events) > 0 {
+		encrypted.Events = make([]models.IncidentEvent, len(incident.Events))
+		for i, event := range incident.Events {
+			if event.Message, err = c.Encrypt(event.Message); err != nil {
+				return nil, err
+			}
+			encrypted.Events[i] = event
+		}
+	}
+	return &encrypted, nil
+}
+
+// decryptIncident returns a copy of an incident with its payload fields decrypted and
+// whether any field needs re-encryption with the primary key
+func decryptIncident(c *FieldCipher, incident *models.Incident) (*models.Incident, bool, error) {
+	decrypted := *incident
+	var stale, fieldStale bool
+	var err error
+	if decrypted.Description, fieldStale, err = c.Decrypt(incident.Description); err != nil {
+		return nil, false, err
+	}
+	stale = stale || fieldStale
+	if decrypted.Summary, fieldStale, err = c.Decrypt(incident.Summary); err != nil {
+		return nil, false, err
+	}
+	stale = stale || fieldStale
+	if len(incident.Events) > 0 {
+		decrypted.Events = make([]models.IncidentEvent, len(incident.Events))
+		for i, event := range incident.Events {
+			if event.Message, fieldStale, err = c.Decrypt(event.Message); err != nil {
+				return nil, false, err
+			}
+			stale = stale || fieldStale
+			decrypted.Events[i] = event
+		}
+	}
+	return &decrypted, stale, nil
+}
+
+// OnDelete registers hook to be called with the IDs of incidents removed by Delete and
+// DeleteMatching. Unlike Subscribe, hooks run synchronously and never miss a deletion,
+// so data tied to incidents is removed with them. Hooks run with the store locked and
+// must not call back into it.
+func (s *IncidentStore) OnDelete(hook func(incidentIDs []string)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deleteHooks = append(s.deleteHooks, hook)
+}
+
+// runDeleteHooks passes the IDs of deleted incidents to the delete hooks. Callers must
+// hold s.mu.
+func (s *IncidentStore) runDeleteHooks(incidentIDs []string) {
+	for _, hook := range s.deleteHooks {
+		hook(incidentIDs)
+	}
+}
+
+// SetLabeler sets the labeler applied to new incidents. Labels already present on
+// an incident are not overwritten.
+func (s *IncidentStore) SetLabeler(labeler IncidentLabeler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.labeler = labeler
+}
+
+// SetTagger sets the tagger applied to new incidents. Tags already present on an
+// incident, e.g. from the request that created it, are not overwritten.
+func (s *IncidentStore) SetTagger(tagger IncidentTagger) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tagger = tagger
+}
+
+// SetOwnerResolver sets the resolver of the owner of new incidents. Incidents created
+// with an owner keep it.
+func (s *IncidentStore) SetOwnerResolver(owners IncidentOwnerResolver) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.owners = owners
+}
+
+// resolveOwner sets the owner of a new incident. It reads annotations from the
+// Kubernetes API, so it runs before the store is locked.
+func (s *IncidentStore) resolveOwner(incident *models.Incident) {
+	s.mu.RLock()
+	owners := s.owners
+	s.mu.RUnlock()
+	if owners != nil && incident.Owner == nil {
+		incident.Owner = owners.IncidentOwner(incident)
+	}
+}
+
+// SetPrioritizer sets the prioritizer applied to incidents when they are created or
+// updated. Without it incidents keep the priority they were stored with.
+func (s *IncidentStore) SetPrioritizer(prioritizer IncidentPrioritizer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.prioritizer = prioritizer
+}
+
+// prioritize sets an incident's priority. It reads the namespace tier, which may call
+// the Kubernetes API, so it runs before the store is locked.
+func (s *IncidentStore) prioritize(incident *models.Incident) {
+	s.mu.RLock()
+	prioritizer := s.prioritizer
+	s.mu.RUnlock()
+	if prioritizer != nil {
+		incident.Priority = prioritizer.IncidentPriority(incident)
+	}
+}
+
+// SetRedactor masks credentials in the title, description, summary and timeline of every
+// incident created or updated from now on, before it is persisted or published
+func (s *IncidentStore) SetRedactor(redactor TextRedactor) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.redactor = redactor
+}
+
+// redact masks credentials in an incident's free-text fields in place
+func (s *IncidentStore) redact(incident *models.Incident) {
+	if s.redactor == nil {
+		return
+	}
+	incident.Title = s.redactor.Redact(redactionSource, incident.Title)
+	incident.Description = s.redactor.Redact(redactionSource, incident.Description)
+	incident.Summary = s.redactor.Redact(redactionSource, incident.Summary)
+	// Copies of an incident share its timeline, so redact into a new slice
+	if len(incident.Events) > 0 {
+		events := make([]models.IncidentEvent, len(incident.Events))
+		for i, event := range incident.Events {
+			event.Message = s.redactor.Redact(redactionSource, event.Message)
+			events[i] = event
+		}
+		incident.Events = events
+	}
+}
+
 // Create stores a new incident and returns the generated ID
 func (s *IncidentStore) Create(incident *models.Incident) (*models.Incident, error) {
+	s.prioritize(incident)
+	s.resolveOwner(incident)
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -133,6 +415,40 @@ func (s *IncidentStore) Create(incident *models.Incident) (*models.Incident, err
 		incident.Status = models.IncidentStatusActive
 	}
 
+	s.redact(incident)
+
+	// Add context labels
+	if s.labeler != nil {
+		for key, value := range s.labeler.IncidentLabels() {
+			if incident.Labels == nil {
+				incident.Labels = make(map[string]string)
+			}
+			if _, exists := incident.Labels[key]; !exists {
+				incident.Labels[key] = value
+			}
+		}
+	}
+
+	// Add policy tags
+	if s.tagger != nil {
+		for key, value := range s.tagger.IncidentTags(incident) {
+			if incident.Tags == nil {
+				incident.Tags = make(map[string]string)
+			}
+			if _, exists := incident.Tags[key]; !exists {
+				incident.Tags[key] = value
+			}
+		}
+	}
+	if owner := incident.Owner; owner != nil && owner.Team != "" && len(owner.Team) <= models.MaxIncidentTagValueLen {
+		if _, exists := incident.Tags[OwnerTeamTag]; !exists {
+			if incident.Tags == nil {
+				incident.Tags = make(map[string]string)
+			}
+			incident.Tags[OwnerTeamTag] = owner.Team
+		}
+	}
+
 	// Store incident
 	s.incidents[incident.ID] = incident
 
@@ -143,6 +459,8 @@ func (s *IncidentStore) Create(incident *models.Incident) (*models.Incident, err
 		return nil, fmt.Errorf("failed to persist incident: %w", err)
 	}
 
+	s.publish(IncidentCreated, incident)
+
 	return incident, nil
 }
 
@@ -161,6 +479,8 @@ func (s *IncidentStore) Get(id string) (*models.Incident, error) {
 
 // Update modifies an existing incident
 func (s *IncidentStore) Update(incident *models.Incident) error {
+	s.prioritize(incident)
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -169,6 +489,7 @@ func (s *IncidentStore) Update(incident *models.Incident) error {
 	}
 
 	incident.UpdatedAt = time.Now()
+	s.redact(incident)
 	s.incidents[incident.ID] = incident
 
 	// Persist to disk
@@ -176,6 +497,8 @@ func (s *IncidentStore) Update(incident *models.Incident) error {
 		fmt.Printf("Warning: Failed to persist incident update: %v\n", err)
 	}
 
+	s.publish(IncidentUpdated, incident)
+
 	return nil
 }
 
@@ -184,7 +507,8 @@ func (s *IncidentStore) Delete(id string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if _, exists := s.incidents[id]; !exists {
+	incident, exists := s.incidents[id]
+	if !exists {
 		return fmt.Errorf("incident not found: %s", id)
 	}
 
@@ -195,15 +519,82 @@ func (s *IncidentStore) Delete(id string) error {
 		fmt.Printf("Warning: Failed to persist incident deletion: %v\n", err)
 	}
 
+	s.runDeleteHooks([]string{id})
+	s.publish(IncidentDeleted, incident)
+
 	return nil
 }
 
+// DeleteMatching removes every incident for which match returns true and returns how
+// many were removed. Delete hooks receive all removed IDs at once; subscribers receive a
+// deleted event for each removed incident.
+func (s *IncidentStore) DeleteMatching(match func(*models.Incident) bool) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var removed []*models.Incident
+	for id, incident := range s.incidents {
+		if match(incident) {
+			removed = append(removed, incident)
+			delete(s.incidents, id)
+		}
+	}
+	if len(removed) == 0 {
+		return 0, nil
+	}
+
+	if err := s.save(); err != nil {
+		// Keep in-memory and on-disk state consistent
+		for _, incident := range removed {
+			s.incidents[incident.ID] = incident
+		}
+		return 0, fmt.Errorf("failed to persist incident deletion: %w", err)
+	}
+
+	ids := make([]string, 0, len(removed))
+	for _, incident := range removed {
+		ids = append(ids, incident.ID)
+	}
+	s.runDeleteHooks(ids)
+
+	for _, incident := range removed {
+		s.publish(IncidentDeleted, incident)
+	}
+	return len(removed), nil
+}
+
 // ListFilter defines filter options for listing incidents
 type ListFilter struct {
 	Namespace string
 	Severity  string
 	Status    string
 	Limit     int
+
+	// Tags must all be set on an incident; an empty value matches any value of the key
+	Tags map[string]string
+
+	// ByPriority orders incidents by priority, highest first, instead of newest first
+	ByPriority bool
+}
+
+// Matches reports whether an incident passes the namespace, severity, status and tag filters
+func (f ListFilter) Matches(incident *models.Incident) bool {
+	if f.Namespace != "" && incident.Target != f.Namespace {
+		return false
+	}
+	if f.Severity != "" && f.Severity != "all" && string(incident.Severity) != f.Severity {
+		return false
+	}
+	if f.Status != "" && f.Status != "all" && string(incident.Status) != f.Status {
+		return false
+	}
+	for key, value := range f.Tags {
+		tag, ok := incident.Tags[key]
+		if !ok || (value != "" && tag != value) {
+			return false
+		}
+	}
+	return true
 }
 
 // List returns incidents matching the filter criteria
@@ -214,22 +605,19 @@ func (s *IncidentStore) List(filter ListFilter) []*models.Incident {
 	results := make([]*models.Incident, 0, len(s.incidents))
 
 	for _, incident := range s.incidents {
-		// Apply filters
-		if filter.Namespace != "" && incident.Target != filter.Namespace {
-			continue
-		}
-		if filter.Severity != "" && filter.Severity != "all" && string(incident.Severity) != filter.Severity {
-			continue
-		}
-		if filter.Status != "" && filter.Status != "all" && string(incident.Status) != filter.Status {
-			continue
+		if filter.Matches(incident) {
+			results = append(results, incident)
 		}
-
-		results = append(results, incident)
 	}
 
-	// Sort by created_at descending (newest first)
+	// Sort by created_at descending (newest first), or by priority with the newest
+	// first among equal priorities
 	sort.Slice(results, func(i, j int) bool {
+		if filter.ByPriority {
+			if pi, pj := results[i].EffectivePriority(), results[j].EffectivePriority(); pi != pj {
+				return pi > pj
+			}
+		}
 		return results[i].CreatedAt.After(results[j].CreatedAt)
 	})
 
@@ -241,6 +629,38 @@ func (s *IncidentStore) List(filter ListFilter) []*models.Incident {
 	return results
 }
 
+// Restore loads incidents from a backup. With replace, existing incidents are dropped
+// first; otherwise restored incidents are merged in, overwriting those with the same
+// ID. Subscribers are not notified, so restoring does not re-send notifications.
+func (s *IncidentStore) Restore(incidents []*models.Incident, replace bool) error {
+	for _, incident := range incidents {
+		if incident == nil || incident.ID == "" {
+			return fmt.Errorf("incident without id in backup")
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previous := s.incidents
+	restored := make(map[string]*models.Incident, len(incidents))
+	if !replace {
+		for id, incident := range previous {
+			restored[id] = incident
+		}
+	}
+	for _, incident := range incidents {
+		restored[incident.ID] = incident
+	}
+
+	s.incidents = restored
+	if err := s.save(); err != nil {
+		s.incidents = previous
+		return fmt.Errorf("failed to persist restored incidents: %w", err)
+	}
+	return nil
+}
+
 // Count returns the total number of incidents
 func (s *IncidentStore) Count() int {
 	s.mu.RLock()
@@ -248,6 +668,43 @@ func (s *IncidentStore) Count() int {
 	return len(s.incidents)
 }
 
+// Subscribe registers for incident changes. Events are delivered on the returned
+// channel, buffered to the given size; a subscriber that falls behind misses events
+// rather than blocking writers. Call the returned function to unsubscribe, which
+// closes the channel.
+func (s *IncidentStore) Subscribe(buffer int) (<-chan IncidentChange, func()) {
+	ch := make(chan IncidentChange, buffer)
+
+	s.mu.Lock()
+	if s.subscribers == nil {
+		s.subscribers = make(map[chan IncidentChange]struct{})
+	}
+	s.subscribers[ch] = struct{}{}
+	s.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			s.mu.Lock()
+			delete(s.subscribers, ch)
+			s.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// publish notifies subscribers of a change. Callers must hold s.mu.
+func (s *IncidentStore) publish(changeType IncidentChangeType, incident *models.Incident) {
+	change := IncidentChange{Type: changeType, Incident: *incident}
+	for ch := range s.subscribers {
+		select {
+		case ch <- change:
+		default:
+			fmt.Printf("Warning: Dropping incident %s change for %s, subscriber is not keeping up\n", changeType, incident.ID)
+		}
+	}
+}
+
 // generateIncidentID generates a unique incident ID
 func generateIncidentID() string {
 	return "inc-" + uuid.New().String()[:8]
@@ -0,0 +1,168 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// maskRedactor masks the word "hunter2"
+type maskRedactor struct{}
+
+func (maskRedactor) Redact(_, text string) string {
+	return strings.ReplaceAll(text, "hunter2", "[REDACTED]")
+}
+
+func TestIncidentStore_Redaction(t *testing.T) {
+	dir := t.TempDir()
+	store := NewIncidentStoreWithPath(dir)
+	store.SetRedactor(maskRedactor{})
+	changes, unsubscribe := store.Subscribe(2)
+	defer unsubscribe()
+
+	incident, err := store.Create(&models.Incident{
+		Title:       "Login failures",
+		Description: "connect failed password=hunter2",
+		Severity:    models.IncidentSeverityHigh,
+		Target:      "apps",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "connect failed password=[REDACTED]", incident.Description)
+	assert.Equal(t, "connect failed password=[REDACTED]", (<-changes).Incident.Description, "subscribers only see redacted text")
+
+	updated := *incident
+	updated.Summary = "The password hunter2 was rejected"
+	updated.AddEvent(models.IncidentEventResolved, "rotated hunter2")
+	require.NoError(t, store.Update(&updated))
+	change := <-changes
+	assert.Equal(t, "The password [REDACTED] was rejected", change.Incident.Summary)
+	assert.Equal(t, "rotated [REDACTED]", change.Incident.Events[0].Message)
+
+	data, err := os.ReadFile(filepath.Join(dir, "incidents.json"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "hunter2")
+}
+
+// affectedPrioritizer prioritizes incidents by their number of affected resources
+type affectedPrioritizer struct{}
+
+func (affectedPrioritizer) IncidentPriority(incident *models.Incident) int {
+	return 10 * len(incident.AffectedResources)
+}
+
+func TestIncidentStore_Prioritizer(t *testing.T) {
+	store := NewIncidentStoreWithPath(t.TempDir())
+
+	create := func(title string, severity models.IncidentSeverity, affected ...string) *models.Incident {
+		incident, err := store.Create(&models.Incident{Title: title, Description: title, Severity: severity, Target: "apps", AffectedResources: affected})
+		require.NoError(t, err)
+		return incident
+	}
+
+	// Without a prioritizer the severity orders incidents
+	unprioritized := create("Disk filling", models.IncidentSeverityHigh)
+	assert.Zero(t, unprioritized.Priority)
+	assert.Equal(t, 75, unprioritized.EffectivePriority())
+
+	store.SetPrioritizer(affectedPrioritizer{})
+	wide := create("Pods crash looping", models.IncidentSeverityMedium, "Pod/a", "Pod/b", "Pod/c", "Pod/d", "Pod/e", "Pod/f", "Pod/g", "Pod/h")
+	narrow := create("Pod crash looping", models.IncidentSeverityCritical, "Pod/a")
+	assert.Equal(t, 80, wide.Priority)
+	assert.Equal(t, 10, narrow.Priority)
+
+	updated := *narrow
+	updated.AffectedResources = append(updated.AffectedResources, "Pod/b")
+	require.NoError(t, store.Update(&updated))
+	stored, err := store.Get(narrow.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 20, stored.Priority, "updates are prioritized again")
+
+	var titles []string
+	for _, incident := range store.List(ListFilter{ByPriority: true}) {
+		titles = append(titles, incident.Title)
+	}
+	assert.Equal(t, []string{"Pods crash looping", "Disk filling", "Pod crash looping"}, titles)
+}
+
+// teamTagger tags incidents of the apps namespace with the team owning it
+type teamTagger struct{}
+
+func (teamTagger) IncidentTags(incident *models.Incident) map[string]string {
+	if incident.Target != "apps" {
+		return nil
+	}
+	return map[string]string{"team": "storefront", "cost_center": "cc-42"}
+}
+
+func TestIncidentStore_Tags(t *testing.T) {
+	store := NewIncidentStoreWithPath(t.TempDir())
+	store.SetTagger(teamTagger{})
+
+	create := func(title, target string, tags map[string]string) *models.Incident {
+		incident, err := store.Create(&models.Incident{Title: title, Description: title, Severity: models.IncidentSeverityHigh, Target: target, Tags: tags})
+		require.NoError(t, err)
+		return incident
+	}
+
+	tagged := create("Checkout errors", "apps", map[string]string{"team": "payments", "service": "checkout"})
+	assert.Equal(t, map[string]string{"team": "payments", "service": "checkout", "cost_center": "cc-42"}, tagged.Tags,
+		"request tags win over policy tags")
+	create("Catalog slow", "apps", nil)
+	create("Disk filling", "infra", nil)
+
+	titles := func(tags map[string]string) []string {
+		var titles []string
+		for _, incident := range store.List(ListFilter{Tags: tags}) {
+			titles = append(titles, incident.Title)
+		}
+		return titles
+	}
+	assert.Equal(t, []string{"Checkout errors"}, titles(map[string]string{"team": "payments"}))
+	assert.Equal(t, []string{"Catalog slow"}, titles(map[string]string{"team": "storefront", "cost_center": "cc-42"}))
+	assert.ElementsMatch(t, []string{"Checkout errors", "Catalog slow"}, titles(map[string]string{"team": ""}))
+	assert.Empty(t, titles(map[string]string{"service": "catalog"}))
+
+	_, err := store.Create(&models.Incident{Title: "Bad", Description: "Bad", Severity: models.IncidentSeverityLow, Target: "apps",
+		Tags: map[string]string{"-team": "x"}})
+	assert.ErrorContains(t, err, `tag key "-team"`)
+}
+
+// namespaceOwners owns incidents by their namespace
+type namespaceOwners map[string]string
+
+func (o namespaceOwners) IncidentOwner(incident *models.Incident) *models.Owner {
+	if team, ok := o[incident.Target]; ok {
+		return &models.Owner{Team: team, Source: "namespace/" + incident.Target}
+	}
+	return nil
+}
+
+func TestIncidentStore_Owner(t *testing.T) {
+	store := NewIncidentStoreWithPath(t.TempDir())
+	store.SetOwnerResolver(namespaceOwners{"apps": "storefront"})
+
+	create := func(target string, owner *models.Owner, tags map[string]string) *models.Incident {
+		incident, err := store.Create(&models.Incident{Title: "Pods crashing", Description: "Pods crashing", Severity: models.IncidentSeverityHigh,
+			Target: target, Owner: owner, Tags: tags})
+		require.NoError(t, err)
+		return incident
+	}
+
+	incident := create("apps", nil, nil)
+	assert.Equal(t, &models.Owner{Team: "storefront", Source: "namespace/apps"}, incident.Owner)
+	assert.Equal(t, map[string]string{OwnerTeamTag: "storefront"}, incident.Tags, "the owner's team tags the incident")
+
+	incident = create("apps", &models.Owner{Team: "payments"}, map[string]string{OwnerTeamTag: "checkout"})
+	assert.Equal(t, "payments", incident.Owner.Team, "incidents created with an owner keep it")
+	assert.Equal(t, "checkout", incident.Tags[OwnerTeamTag])
+
+	incident = create("infra", nil, nil)
+	assert.Nil(t, incident.Owner)
+	assert.Nil(t, incident.Tags)
+}
@@ -0,0 +1,336 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// DefaultPredictionLimit is the number of prediction records kept; the oldest are
+// dropped first
+const DefaultPredictionLimit = 10000
+
+// PredictionStore persists predictions and their realized error
+type PredictionStore struct {
+	records  map[string]*models.PredictionRecord
+	limit    int
+	mu       sync.RWMutex
+	dataFile string
+}
+
+// PredictionActual is the usage observed at a prediction's target time
+type PredictionActual struct {
+	ID         string
+	CPU        float64
+	Memory     float64
+	ObservedAt time.Time
+}
+
+// PredictionFilter defines filter options for listing predictions
+type PredictionFilter struct {
+	Model  string
+	Scope  string
+	Target string
+
+	// TargetFrom and TargetTo bound the target time (inclusive); zero means unbounded
+	TargetFrom time.Time
+	TargetTo   time.Time
+
+	// Evaluated, if set, selects only evaluated (true) or pending (false) predictions
+	Evaluated *bool
+
+	Limit int
+}
+
+// Matches reports whether a prediction passes the filter
+func (f *PredictionFilter) Matches(record *models.PredictionRecord) bool {
+	if f.Model != "" && record.Model != f.Model {
+		return false
+	}
+	if f.Scope != "" && record.Scope != f.Scope {
+		return false
+	}
+	if f.Target != "" && record.Target != f.Target {
+		return false
+	}
+	if !f.TargetFrom.IsZero() && record.TargetTime.Before(f.TargetFrom) {
+		return false
+	}
+	if !f.TargetTo.IsZero() && record.TargetTime.After(f.TargetTo) {
+		return false
+	}
+	if f.Evaluated != nil && record.Evaluated() != *f.Evaluated {
+		return false
+	}
+	return true
+}
+
+// NewPredictionStore creates a prediction store in dataDir (DATA_DIR or /app/data if
+// empty) keeping at most limit records (DefaultPredictionLimit if not positive)
+func NewPredictionStore(dataDir string, limit int) *PredictionStore {
+	if dataDir == "" {
+		dataDir = os.Getenv("DATA_DIR")
+	}
+	if dataDir == "" {
+		dataDir = "/app/data"
+	}
+	if limit <= 0 {
+		limit = DefaultPredictionLimit
+	}
+
+	if err := os.MkdirAll(dataDir, 0o755); err != nil {
+		fmt.Printf("Warning: Could not create data directory %s: %v\n", dataDir, err)
+	}
+	store := &PredictionStore{
+		records:  make(map[string]*models.PredictionRecord),
+		limit:    limit,
+		dataFile: filepath.Join(dataDir, "predictions.json"),
+	}
+
+	if err := store.load(); err != nil {
+		fmt.Printf("Warning: Could not load predictions from disk: %v\n", err)
+	} else {
+		fmt.Printf("Loaded %d predictions from %s\n", len(store.records), store.dataFile)
+	}
+
+	return store
+}
+
+// NewMemoryPredictionStore creates a prediction store that is never persisted,
+// keeping at most limit records (DefaultPredictionLimit if not positive)
+func NewMemoryPredictionStore(limit int) *PredictionStore {
+	if limit <= 0 {
+		limit = DefaultPredictionLimit
+	}
+	return &PredictionStore{
+		records: make(map[string]*models.PredictionRecord),
+		limit:   limit,
+	}
+}
+
+// load reads predictions from the JSON file
+func (s *PredictionStore) load() error {
+	data, err := os.ReadFile(s.dataFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read data file: %w", err)
+	}
+
+	var records []*models.PredictionRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		return fmt.Errorf("failed to unmarshal predictions: %w", err)
+	}
+
+	for _, record := range records {
+		s.records[record.ID] = record
+	}
+	s.prune()
+
+	return nil
+}
+
+// save writes all predictions to the JSON file. Callers must hold s.mu.
+func (s *PredictionStore) save() error {
+	if s.dataFile == "" {
+		return nil
+	}
+	dir := filepath.Dir(s.dataFile)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	records := make([]*models.PredictionRecord, 0, len(s.records))
+	for _, record := range s.records {
+		records = append(records, record)
+	}
+
+	data, err := json.Marshal(records)
+	if err != nil {
+		return fmt.Errorf("failed to marshal predictions: %w", err)
+	}
+
+	// Write to temp file first, then rename (atomic)
+	tmpFile := s.dataFile + ".tmp"
+	if err := os.WriteFile(tmpFile, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write temp file: %w", err)
+	}
+	if err := os.Rename(tmpFile, s.dataFile); err != nil {
+		return fmt.Errorf("failed to rename temp file: %w", err)
+	}
+
+	return nil
+}
+
+// prune drops the oldest records beyond the limit. Callers must hold s.mu.
+func (s *PredictionStore) prune() {
+	if len(s.records) <= s.limit {
+		return
+	}
+
+	records := make([]*models.PredictionRecord, 0, len(s.records))
+	for _, record := range s.records {
+		records = append(records, record)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].CreatedAt.Before(records[j].CreatedAt)
+	})
+	for _, record := range records[:len(records)-s.limit] {
+		delete(s.records, record.ID)
+	}
+}
+
+// Add stores new predictions, assigning IDs and creation times
+func (s *PredictionStore) Add(records []*models.PredictionRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for _, record := range records {
+		if record.ID == "" {
+			record.ID = generatePredictionID()
+		}
+		if record.CreatedAt.IsZero() {
+			record.CreatedAt = now
+		}
+		s.records[record.ID] = record
+	}
+	s.prune()
+
+	if err := s.save(); err != nil {
+		return fmt.Errorf("failed to persist predictions: %w", err)
+	}
+	return nil
+}
+
+// RecordActuals stores the observed usage for evaluated predictions. Unknown IDs
+// (pruned since they were listed) are skipped.
+func (s *PredictionStore) RecordActuals(actuals []PredictionActual) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, actual := range actuals {
+		record, exists := s.records[actual.ID]
+		if !exists {
+			continue
+		}
+		updated := *record
+		cpu, memory, observedAt := actual.CPU, actual.Memory, actual.ObservedAt
+		updated.ActualCPU = &cpu
+		updated.ActualMemory = &memory
+		updated.EvaluatedAt = &observedAt
+		s.records[actual.ID] = &updated
+	}
+
+	if err := s.save(); err != nil {
+		return fmt.Errorf("failed to persist prediction actuals: %w", err)
+	}
+	return nil
+}
+
+// List returns copies of the predictions matching the filter, ordered by target time
+// (earliest first)
+func (s *PredictionStore) List(filter PredictionFilter) []models.PredictionRecord {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	results := make([]models.PredictionRecord, 0)
+	for _, record := range s.records {
+		if filter.Matches(record) {
+			results = append(results, *record)
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].TargetTime.Equal(results[j].TargetTime) {
+			return results[i].ID < results[j].ID
+		}
+		return results[i].TargetTime.Before(results[j].TargetTime)
+	})
+
+	if filter.Limit > 0 && len(results) > filter.Limit {
+		results = results[:filter.Limit]
+	}
+	return results
+}
+
+// Restore loads predictions from a backup. With replace, existing predictions are
+// dropped first; otherwise restored predictions are merged in, overwriting those with
+// the same ID. The store limit still applies.
+func (s *PredictionStore) Restore(records []models.PredictionRecord, replace bool) error {
+	for i := range records {
+		if records[i].ID == "" {
+			return fmt.Errorf("prediction without id in backup")
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previous := s.records
+	restored := make(map[string]*models.PredictionRecord, len(records))
+	if !replace {
+		for id, record := range previous {
+			restored[id] = record
+		}
+	}
+	for i := range records {
+		record := records[i]
+		restored[record.ID] = &record
+	}
+
+	s.records = restored
+	s.prune()
+	if err := s.save(); err != nil {
+		s.records = previous
+		return fmt.Errorf("failed to persist restored predictions: %w", err)
+	}
+	return nil
+}
+
+// DeleteMatching removes every prediction for which match returns true and returns
+// how many were removed
+func (s *PredictionStore) DeleteMatching(match func(*models.PredictionRecord) bool) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var removed []*models.PredictionRecord
+	for id, record := range s.records {
+		if match(record) {
+			removed = append(removed, record)
+			delete(s.records, id)
+		}
+	}
+	if len(removed) == 0 {
+		return 0, nil
+	}
+
+	if err := s.save(); err != nil {
+		for _, record := range removed {
+			s.records[record.ID] = record
+		}
+		return 0, fmt.Errorf("failed to persist prediction deletion: %w", err)
+	}
+	return len(removed), nil
+}
+
+// Count returns the number of stored predictions
+func (s *PredictionStore) Count() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.records)
+}
+
+// generatePredictionID generates a unique prediction ID
+func generatePredictionID() string {
+	return "pred-" + uuid.New().String()[:8]
+}
@@ -0,0 +1,276 @@
+// Package summary generates natural-language incident summaries with an LLM.
+//
+// The summarizer turns an incident's structured fields, timeline and (optionally)
+// namespace metrics into a prompt for an OpenAI-compatible chat completions endpoint
+// and stores the short summary it returns on the incident, where it is included in
+// API responses and resolution notifications.
+package summary
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/integrations"
+	"github.com/tosin2013/openshift-coordination-engine/internal/storage"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// DefaultMaxTokens bounds the length of a generated summary
+const DefaultMaxTokens = 256
+
+// maxSummaryLength caps the stored summary in case the model ignores the token limit
+const maxSummaryLength = 2000
+
+// systemPrompt instructs the model to write a short operator-facing summary
+const systemPrompt = "You are an SRE assistant for an OpenShift cluster. Summarize the incident below " +
+	"for an on-call engineer in at most three sentences: what happened, what was affected, and its " +
+	"current state. Use only the facts provided; do not speculate about causes that are not stated. " +
+	"Reply with plain text only."
+
+// Completer generates text from chat messages (implemented by integrations.LLMClient)
+type Completer interface {
+	Complete(ctx context.Context, messages []integrations.ChatMessage, maxTokens int) (string, error)
+}
+
+// MetricsSource supplies namespace resource usage (implemented by integrations.PrometheusClient)
+type MetricsSource interface {
+	GetNamespaceCPURollingMean(ctx context.Context, namespace string) (float64, error)
+	GetNamespaceMemoryRollingMean(ctx context.Context, namespace string) (float64, error)
+}
+
+// Summarizer writes LLM-generated summaries onto incidents
+type Summarizer struct {
+	llm       Completer
+	metrics   MetricsSource
+	store     *storage.IncidentStore
+	maxTokens int
+	log       *logrus.Logger
+
+	// summarized maps incident IDs to the fingerprint of the incident state that was
+	// last summarized, so an incident is not summarized again until it changes
+	mu         sync.Mutex
+	summarized map[string]string
+
+	// now is replaceable in tests
+	now func() time.Time
+}
+
+// NewSummarizer creates a summarizer; maxTokens <= 0 uses DefaultMaxTokens
+func NewSummarizer(llm Completer, store *storage.IncidentStore, maxTokens int, log *logrus.Logger) *Summarizer {
+	if maxTokens <= 0 {
+		maxTokens = DefaultMaxTokens
+	}
+	return &Summarizer{
+		llm:        llm,
+		store:      store,
+		maxTokens:  maxTokens,
+		log:        log,
+		summarized: make(map[string]string),
+		now:        time.Now,
+	}
+}
+
+// SetMetricsSource adds namespace CPU and memory usage to summary prompts
+func (s *Summarizer) SetMetricsSource(metrics MetricsSource) {
+	s.metrics = metrics
+}
+
+// Summarize generates a summary and sets it on the incident. It does not persist
+// the incident; callers that update the store afterwards save the summary with it.
+func (s *Summarizer) Summarize(ctx context.Context, incident *models.Incident) error {
+	text, err := s.llm.Complete(ctx, []integrations.ChatMessage{
+		{Role: "system", Content: systemPrompt},
+		{Role: "user", Content: buildPrompt(incident, s.collectMetrics(ctx, incident.Target))},
+	}, s.maxTokens)
+	if err != nil {
+		return fmt.Errorf("failed to generate summary for incident %s: %w", incident.ID, err)
+	}
+	if text == "" {
+		return fmt.Errorf("empty summary generated for incident %s", incident.ID)
+	}
+	if len(text) > maxSummaryLength {
+		text = text[:maxSummaryLength]
+	}
+
+	now := s.now()
+	incident.Summary = text
+	incident.SummarizedAt = &now
+
+	s.mu.Lock()
+	s.summarized[incident.ID] = fingerprint(incident)
+	s.mu.Unlock()
+
+	return nil
+}
+
+// SummarizeByID regenerates the summary of a stored incident and saves it
+func (s *Summarizer) SummarizeByID(ctx context.Context, id string) (*models.Incident, error) {
+	stored, err := s.store.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Work on a copy so readers never see a half-updated incident
+	incident := *stored
+	if err := s.Summarize(ctx, &incident); err != nil {
+		return nil, err
+	}
+	if err := s.store.Update(&incident); err != nil {
+		return nil, fmt.Errorf("failed to save summary for incident %s: %w", id, err)
+	}
+
+	s.log.WithField("incident_id", id).Info("Incident summary generated")
+	return &incident, nil
+}
+
+// Start summarizes incidents as they are created and whenever their status or
+// timeline changes, until ctx is cancelled. It subscribes to the store before
+// returning, so no change made after Start is missed. Failures are logged; the
+// incident keeps its previous summary.
+func (s *Summarizer) Start(ctx context.Context, timeout time.Duration) {
+	changes, unsubscribe := s.store.Subscribe(64)
+
+	go func() {
+		defer unsubscribe()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case change, ok := <-changes:
+				if !ok {
+					return
+				}
+				s.handleChange(ctx, &change, timeout)
+			}
+		}
+	}()
+
+	s.log.Info("Incident summarizer started")
+}
+
+// handleChange summarizes an incident if it changed since it was last summarized
+func (s *Summarizer) handleChange(ctx context.Context, change *storage.IncidentChange, timeout time.Duration) {
+	id := change.Incident.ID
+
+	if change.Type == storage.IncidentDeleted {
+		s.mu.Lock()
+		delete(s.summarized, id)
+		s.mu.Unlock()
+		return
+	}
+
+	if !s.needsSummary(&change.Incident) {
+		return
+	}
+
+	summarizeCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if _, err := s.SummarizeByID(summarizeCtx, id); err != nil {
+		s.log.WithError(err).WithField("incident_id", id).Warn("Failed to summarize incident")
+	}
+}
+
+// needsSummary reports whether the incident state differs from the last summarized state
+func (s *Summarizer) needsSummary(incident *models.Incident) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	last, ok := s.summarized[incident.ID]
+	if !ok {
+		// Not summarized since startup: only summarize if the stored summary is stale
+		return incident.SummarizedAt == nil || incident.UpdatedAt.After(*incident.SummarizedAt)
+	}
+	return last != fingerprint(incident)
+}
+
+// fingerprint identifies the parts of an incident a summary describes
+func fingerprint(incident *models.Incident) string {
+	return fmt.Sprintf("%s/%s/%d", incident.Status, incident.Severity, len(incident.Events))
+}
+
+// namespaceMetrics is the resource usage included in a prompt
+type namespaceMetrics struct {
+	CPU    *float64
+	Memory *float64
+}
+
+// collectMetrics fetches namespace usage; unavailable metrics are omitted
+func (s *Summarizer) collectMetrics(ctx context.Context, namespace string) namespaceMetrics {
+	var m namespaceMetrics
+	if s.metrics == nil || namespace == "" {
+		return m
+	}
+
+	if cpu, err := s.metrics.GetNamespaceCPURollingMean(ctx, namespace); err == nil {
+		m.CPU = &cpu
+	} else {
+		s.log.WithError(err).WithField("namespace", namespace).Debug("CPU usage unavailable for incident summary")
+	}
+	if mem, err := s.metrics.GetNamespaceMemoryRollingMean(ctx, namespace); err == nil {
+		m.Memory = &mem
+	} else {
+		s.log.WithError(err).WithField("namespace", namespace).Debug("Memory usage unavailable for incident summary")
+	}
+	return m
+}
+
+// buildPrompt renders the incident and metrics as the user message sent to the model
+func buildPrompt(incident *models.Incident, metrics namespaceMetrics) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Incident %s: %s\n", incident.ID, incident.Title)
+	fmt.Fprintf(&b, "Description: %s\n", incident.Description)
+	fmt.Fprintf(&b, "Severity: %s\n", incident.Severity)
+	fmt.Fprintf(&b, "Namespace: %s\n", incident.Target)
+	fmt.Fprintf(&b, "Status: %s\n", incident.Status)
+	fmt.Fprintf(&b, "Opened: %s\n", incident.CreatedAt.UTC().Format(time.RFC3339))
+	if incident.ResolvedAt != nil {
+		fmt.Fprintf(&b, "Resolved: %s (after %s)\n",
+			incident.ResolvedAt.UTC().Format(time.RFC3339),
+			incident.ResolvedAt.Sub(incident.CreatedAt).Round(time.Second))
+	}
+	if incident.WorkflowID != "" {
+		fmt.Fprintf(&b, "Remediation workflow: %s\n", incident.WorkflowID)
+	}
+	if len(incident.AffectedResources) > 0 {
+		fmt.Fprintf(&b, "Affected resources: %s\n", strings.Join(incident.AffectedResources, ", "))
+	}
+	if len(incident.Labels) > 0 {
+		keys := make([]string, 0, len(incident.Labels))
+		for k := range incident.Labels {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		pairs := make([]string, 0, len(keys))
+		for _, k := range keys {
+			pairs = append(pairs, k+"="+incident.Labels[k])
+		}
+		fmt.Fprintf(&b, "Labels: %s\n", strings.Join(pairs, ", "))
+	}
+
+	if metrics.CPU != nil || metrics.Memory != nil {
+		b.WriteString("Namespace resource usage (share of cluster allocatable):\n")
+		if metrics.CPU != nil {
+			fmt.Fprintf(&b, "- CPU: %.1f%%\n", *metrics.CPU*100)
+		}
+		if metrics.Memory != nil {
+			fmt.Fprintf(&b, "- Memory: %.1f%%\n", *metrics.Memory*100)
+		}
+	}
+
+	if len(incident.Events) > 0 {
+		b.WriteString("Timeline:\n")
+		for _, event := range incident.Events {
+			fmt.Fprintf(&b, "- %s [%s] %s\n", event.Timestamp.UTC().Format(time.RFC3339), event.Type, event.Message)
+		}
+	}
+
+	return b.String()
+}
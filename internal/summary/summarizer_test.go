@@ -0,0 +1,153 @@
+package summary
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/integrations"
+	"github.com/tosin2013/openshift-coordination-engine/internal/storage"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// fakeLLM returns numbered summaries and records the prompts it received
+type fakeLLM struct {
+	mu      sync.Mutex
+	prompts []string
+	err     error
+}
+
+func (f *fakeLLM) Complete(_ context.Context, messages []integrations.ChatMessage, _ int) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.prompts = append(f.prompts, messages[len(messages)-1].Content)
+	if f.err != nil {
+		return "", f.err
+	}
+	return fmt.Sprintf("summary %d", len(f.prompts)), nil
+}
+
+func (f *fakeLLM) calls() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.prompts)
+}
+
+type fakeMetrics struct{}
+
+func (fakeMetrics) GetNamespaceCPURollingMean(context.Context, string) (float64, error) {
+	return 0.423, nil
+}
+
+func (fakeMetrics) GetNamespaceMemoryRollingMean(context.Context, string) (float64, error) {
+	return 0, errors.New("no data")
+}
+
+func newTestSummarizer(t *testing.T, llm *fakeLLM) (*Summarizer, *storage.IncidentStore) {
+	t.Helper()
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	store := storage.NewIncidentStoreWithPath(t.TempDir())
+	return NewSummarizer(llm, store, 0, log), store
+}
+
+func createIncident(t *testing.T, store *storage.IncidentStore) *models.Incident {
+	t.Helper()
+	incident, err := store.Create(&models.Incident{
+		Title:             "Pods OOMKilled",
+		Description:       "api pods restarted with OOMKilled",
+		Severity:          models.IncidentSeverityHigh,
+		Target:            "production",
+		AffectedResources: []string{"deployment/api"},
+		Labels:            map[string]string{"team": "payments", "app": "api"},
+	})
+	require.NoError(t, err)
+	return incident
+}
+
+func TestSummarizer_Prompt(t *testing.T) {
+	llm := &fakeLLM{}
+	s, store := newTestSummarizer(t, llm)
+	s.SetMetricsSource(fakeMetrics{})
+
+	incident := createIncident(t, store)
+	incident.AutoResolve("Condition cleared")
+	require.NoError(t, store.Update(incident))
+
+	updated, err := s.SummarizeByID(context.Background(), incident.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "summary 1", updated.Summary)
+	assert.NotNil(t, updated.SummarizedAt)
+
+	prompt := llm.prompts[0]
+	assert.Contains(t, prompt, "Pods OOMKilled")
+	assert.Contains(t, prompt, "Status: resolved")
+	assert.Contains(t, prompt, "Affected resources: deployment/api")
+	assert.Contains(t, prompt, "Labels: app=api, team=payments")
+	assert.Contains(t, prompt, "- CPU: 42.3%")
+	assert.NotContains(t, prompt, "Memory:")
+	assert.Contains(t, prompt, "[auto_resolved] Condition cleared")
+
+	stored, err := store.Get(incident.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "summary 1", stored.Summary)
+}
+
+func TestSummarizer_ErrorKeepsPreviousSummary(t *testing.T) {
+	llm := &fakeLLM{}
+	s, store := newTestSummarizer(t, llm)
+	incident := createIncident(t, store)
+
+	_, err := s.SummarizeByID(context.Background(), incident.ID)
+	require.NoError(t, err)
+
+	llm.err = errors.New("model not loaded")
+	_, err = s.SummarizeByID(context.Background(), incident.ID)
+	require.Error(t, err)
+
+	stored, err := store.Get(incident.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "summary 1", stored.Summary)
+
+	_, err = s.SummarizeByID(context.Background(), "inc-missing")
+	assert.Error(t, err)
+}
+
+func TestSummarizer_Start(t *testing.T) {
+	llm := &fakeLLM{}
+	s, store := newTestSummarizer(t, llm)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s.Start(ctx, time.Second)
+
+	incident := createIncident(t, store)
+	require.Eventually(t, func() bool {
+		stored, err := store.Get(incident.ID)
+		return err == nil && stored.Summary != ""
+	}, 2*time.Second, 10*time.Millisecond)
+
+	// Saving the summary does not trigger another summary
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, 1, llm.calls())
+
+	// A status change does
+	stored, err := store.Get(incident.ID)
+	require.NoError(t, err)
+	resolved := *stored
+	resolved.Resolve()
+	require.NoError(t, store.Update(&resolved))
+
+	require.Eventually(t, func() bool {
+		stored, err := store.Get(incident.ID)
+		return err == nil && stored.Summary == "summary 2"
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Contains(t, llm.prompts[1], "Status: resolved")
+}
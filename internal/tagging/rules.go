@@ -0,0 +1,158 @@
+// Package tagging attaches tags such as the owning team, service or cost center to
+// incidents from operator-defined rules, so incidents can be filtered and counted by tag.
+package tagging
+
+import (
+	"fmt"
+	"os"
+	"path"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// Rule tags the incidents it matches. It matches an incident by namespace pattern,
+// severity and labels; a rule without conditions matches every incident.
+type Rule struct {
+	// Name identifies the rule in errors and logs
+	Name string `json:"name"`
+
+	// Namespaces are patterns of the incident's namespace (its target), e.g. "shop-*"
+	// (see path.Match)
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// Severities match incidents with one of these severities
+	Severities []string `json:"severities,omitempty"`
+
+	// Labels must all be set on the incident; an empty value matches any value
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Tags are added to matching incidents
+	Tags map[string]string `json:"tags"`
+}
+
+// rulesFile is the on-disk format of the tag rules
+type rulesFile struct {
+	Rules []Rule `json:"rules"`
+}
+
+// Rules tag incidents when they are created. Every matching rule applies; when rules
+// set the same tag, the earliest wins.
+type Rules struct {
+	rules []Rule
+}
+
+// LoadRules reads tag rules from a YAML or JSON file
+func LoadRules(path string) (*Rules, error) {
+	data, err := os.ReadFile(path) //#nosec G304 -- path comes from operator configuration
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tag rules file: %w", err)
+	}
+
+	var file rulesFile
+	if err := yaml.UnmarshalStrict(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse tag rules file %s: %w", path, err)
+	}
+	return NewRules(file.Rules)
+}
+
+// NewRules validates the rules
+func NewRules(rules []Rule) (*Rules, error) {
+	names := make(map[string]bool, len(rules))
+	for i := range rules {
+		rule := &rules[i]
+		if rule.Name == "" {
+			return nil, fmt.Errorf("tag rule %d: name is required", i)
+		}
+		if names[rule.Name] {
+			return nil, fmt.Errorf("tag rule %s: duplicate name", rule.Name)
+		}
+		names[rule.Name] = true
+
+		if len(rule.Tags) == 0 {
+			return nil, fmt.Errorf("tag rule %s: tags are required", rule.Name)
+		}
+		for key, value := range rule.Tags {
+			if !models.IsValidTagKey(key) {
+				return nil, fmt.Errorf("tag rule %s: invalid tag key %q", rule.Name, key)
+			}
+			if len(value) > models.MaxIncidentTagValueLen {
+				return nil, fmt.Errorf("tag rule %s: tag %s exceeds %d characters", rule.Name, key, models.MaxIncidentTagValueLen)
+			}
+		}
+		for _, pattern := range rule.Namespaces {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("tag rule %s: invalid namespace pattern %q", rule.Name, pattern)
+			}
+		}
+		for _, severity := range rule.Severities {
+			if !models.IsValidSeverity(severity) {
+				return nil, fmt.Errorf("tag rule %s: invalid severity %q", rule.Name, severity)
+			}
+		}
+	}
+
+	return &Rules{rules: rules}, nil
+}
+
+// Len returns the number of rules
+func (r *Rules) Len() int {
+	return len(r.rules)
+}
+
+// IncidentTags returns the tags of every rule matching the incident
+func (r *Rules) IncidentTags(incident *models.Incident) map[string]string {
+	var tags map[string]string
+	for i := range r.rules {
+		rule := &r.rules[i]
+		if !rule.matches(incident) {
+			continue
+		}
+		if tags == nil {
+			tags = make(map[string]string)
+		}
+		for key, value := range rule.Tags {
+			if _, exists := tags[key]; !exists {
+				tags[key] = value
+			}
+		}
+	}
+	return tags
+}
+
+// matches reports whether the rule's conditions all hold for the incident
+func (r *Rule) matches(incident *models.Incident) bool {
+	if len(r.Namespaces) > 0 && !matchesAny(r.Namespaces, incident.Target) {
+		return false
+	}
+	if len(r.Severities) > 0 {
+		found := false
+		for _, severity := range r.Severities {
+			if severity == string(incident.Severity) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	for key, value := range r.Labels {
+		label, ok := incident.Labels[key]
+		if !ok || (value != "" && label != value) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesAny reports whether a namespace matches one of the patterns
+func matchesAny(patterns []string, namespace string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, namespace); matched {
+			return true
+		}
+	}
+	return false
+}
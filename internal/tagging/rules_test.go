@@ -0,0 +1,69 @@
+package tagging
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+const rulesYAML = `
+rules:
+  - name: shop
+    namespaces: ["shop-*"]
+    tags:
+      team: storefront
+      cost_center: cc-42
+  - name: payments
+    labels:
+      service: payments
+    tags:
+      team: payments
+      service: payments
+  - name: pager
+    severities: [critical]
+    tags:
+      escalation: pager
+`
+
+func TestLoadRules(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "tags.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(rulesYAML), 0o600))
+	rules, err := LoadRules(file)
+	require.NoError(t, err)
+	assert.Equal(t, 3, rules.Len())
+
+	incident := &models.Incident{Target: "shop-prod", Severity: models.IncidentSeverityCritical, Labels: map[string]string{"service": "payments"}}
+	assert.Equal(t, map[string]string{"team": "storefront", "cost_center": "cc-42", "service": "payments", "escalation": "pager"},
+		rules.IncidentTags(incident), "every matching rule applies and the earliest wins")
+
+	assert.Nil(t, rules.IncidentTags(&models.Incident{Target: "infra", Severity: models.IncidentSeverityLow}))
+
+	require.NoError(t, os.WriteFile(file, []byte("rules:\n  - name: x\n    tag: {team: a}\n"), 0o600))
+	_, err = LoadRules(file)
+	assert.ErrorContains(t, err, "failed to parse tag rules file")
+}
+
+func TestNewRules_Validation(t *testing.T) {
+	tags := map[string]string{"team": "a"}
+	for _, tc := range []struct {
+		rule Rule
+		err  string
+	}{
+		{Rule{Tags: tags}, "tag rule 0: name is required"},
+		{Rule{Name: "a"}, "tag rule a: tags are required"},
+		{Rule{Name: "a", Tags: map[string]string{"team!": "a"}}, `tag rule a: invalid tag key "team!"`},
+		{Rule{Name: "a", Tags: tags, Namespaces: []string{"["}}, `tag rule a: invalid namespace pattern "["`},
+		{Rule{Name: "a", Tags: tags, Severities: []string{"urgent"}}, `tag rule a: invalid severity "urgent"`},
+	} {
+		_, err := NewRules([]Rule{tc.rule})
+		assert.EqualError(t, err, tc.err)
+	}
+
+	_, err := NewRules([]Rule{{Name: "a", Tags: tags}, {Name: "a", Tags: tags}})
+	assert.EqualError(t, err, "tag rule a: duplicate name")
+}
@@ -0,0 +1,472 @@
+package tenant
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/anomaly"
+	"github.com/tosin2013/openshift-coordination-engine/internal/notification"
+)
+
+// DefaultMaxTenants bounds the number of tenants
+const DefaultMaxTenants = 500
+
+// labelCacheTTL bounds how long a namespace's labels are cached
+const labelCacheTTL = time.Minute
+
+// Registry errors
+var (
+	// ErrNotFound is returned when a tenant does not exist
+	ErrNotFound = errors.New("tenant not found")
+
+	// ErrAlreadyExists is returned when a tenant's name or namespace is taken
+	ErrAlreadyExists = errors.New("tenant already exists")
+
+	// ErrLimitReached is returned when DefaultMaxTenants tenants exist
+	ErrLimitReached = errors.New("tenant limit reached")
+
+	// ErrNoCluster is returned when selector tenants are resolved without a
+	// Kubernetes client
+	ErrNoCluster = errors.New("namespace selectors cannot be resolved without a cluster connection")
+)
+
+// Registry keeps the tenants in memory, persisted as JSON in tenants.json. Namespace
+// labels, which selector tenants match on, are read through the Kubernetes API and
+// cached for a minute.
+type Registry struct {
+	tenants  map[string]*Tenant
+	mu       sync.RWMutex
+	dataFile string
+
+	client   kubernetes.Interface
+	labelsMu sync.Mutex
+	labels   map[string]cachedLabels
+
+	// now is replaceable in tests
+	now func() time.Time
+}
+
+type cachedLabels struct {
+	labels  map[string]string
+	expires time.Time
+}
+
+// NewRegistry creates a registry in dataDir (DATA_DIR or /app/data if empty) and
+// loads the tenants onboarded before a restart. Without a client, selector tenants
+// match no namespace.
+func NewRegistry(dataDir string, client kubernetes.Interface) *Registry {
+	if dataDir == "" {
+		dataDir = os.Getenv("DATA_DIR")
+	}
+	if dataDir == "" {
+		dataDir = "/app/data"
+	}
+
+	if err := os.MkdirAll(dataDir, 0o755); err != nil {
+		fmt.Printf("Warning: Could not create data directory %s: %v\n", dataDir, err)
+	}
+	registry := &Registry{
+		tenants:  make(map[string]*Tenant),
+		dataFile: filepath.Join(dataDir, "tenants.json"),
+		client:   client,
+		labels:   make(map[string]cachedLabels),
+		now:      time.Now,
+	}
+
+	if err := registry.load(); err != nil {
+		fmt.Printf("Warning: Could not load tenants from disk: %v\n", err)
+	} else {
+		fmt.Printf("Loaded %d tenants from %s\n", len(registry.tenants), registry.dataFile)
+	}
+
+	return registry
+}
+
+// load reads the tenants from the data file, skipping tenants that no longer validate
+func (r *Registry) load() error {
+	data, err := os.ReadFile(r.dataFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read data file: %w", err)
+	}
+
+	var tenants []*Tenant
+	if err := json.Unmarshal(data, &tenants); err != nil {
+		return fmt.Errorf("failed to unmarshal tenants: %w", err)
+	}
+	for _, tenant := range tenants {
+		if err := tenant.Validate(); err != nil {
+			fmt.Printf("Warning: Skipping invalid tenant %s: %v\n", tenant.Name, err)
+			continue
+		}
+		r.tenants[tenant.Name] = tenant
+	}
+	return nil
+}
+
+// save writes the tenants to the data file. Callers must hold r.mu.
+func (r *Registry) save() error {
+	data, err := json.MarshalIndent(r.sorted(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal tenants: %w", err)
+	}
+
+	// Write to temp file first, then rename (atomic); the file holds webhook secrets
+	tmpFile := r.dataFile + ".tmp"
+	if err := os.WriteFile(tmpFile, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write temp file: %w", err)
+	}
+	if err := os.Rename(tmpFile, r.dataFile); err != nil {
+		return fmt.Errorf("failed to rename temp file: %w", err)
+	}
+	return nil
+}
+
+// sorted returns the tenants in name order. Callers must hold r.mu.
+func (r *Registry) sorted() []*Tenant {
+	tenants := make([]*Tenant, 0, len(r.tenants))
+	for _, tenant := range r.tenants {
+		tenants = append(tenants, tenant)
+	}
+	sort.Slice(tenants, func(i, j int) bool { return tenants[i].Name < tenants[j].Name })
+	return tenants
+}
+
+// namespaceTaken returns the other tenant onboarding a namespace, or nil. Callers
+// must hold r.mu.
+func (r *Registry) namespaceTaken(tenant *Tenant) *Tenant {
+	if tenant.Namespace == "" {
+		return nil
+	}
+	for _, other := range r.tenants {
+		if other.Name != tenant.Name && other.Namespace == tenant.Namespace {
+			return other
+		}
+	}
+	return nil
+}
+
+// Create onboards a tenant and returns it with redacted secrets
+func (r *Registry) Create(tenant *Tenant) (*Tenant, error) {
+	tenant = tenant.copy()
+	if err := tenant.Validate(); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.tenants[tenant.Name]; exists {
+		return nil, fmt.Errorf("%w: %s", ErrAlreadyExists, tenant.Name)
+	}
+	if other := r.namespaceTaken(tenant); other != nil {
+		return nil, fmt.Errorf("%w: namespace %s is onboarded by tenant %s", ErrAlreadyExists, tenant.Namespace, other.Name)
+	}
+	if len(r.tenants) >= DefaultMaxTenants {
+		return nil, fmt.Errorf("%w: at most %d tenants", ErrLimitReached, DefaultMaxTenants)
+	}
+
+	now := r.now().UTC()
+	tenant.CreatedAt = now
+	tenant.UpdatedAt = now
+	r.tenants[tenant.Name] = tenant
+	if err := r.save(); err != nil {
+		delete(r.tenants, tenant.Name)
+		return nil, fmt.Errorf("failed to persist tenant: %w", err)
+	}
+	return tenant.Redacted(), nil
+}
+
+// Update replaces the settings of a tenant and returns it with redacted secrets.
+// Webhooks sent with the redacted secret keep their stored secret.
+func (r *Registry) Update(name string, tenant *Tenant) (*Tenant, error) {
+	tenant = tenant.copy()
+	tenant.Name = name
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	previous, ok := r.tenants[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	tenant.keepSecrets(previous)
+	if err := tenant.Validate(); err != nil {
+		return nil, err
+	}
+	if other := r.namespaceTaken(tenant); other != nil {
+		return nil, fmt.Errorf("%w: namespace %s is onboarded by tenant %s", ErrAlreadyExists, tenant.Namespace, other.Name)
+	}
+
+	tenant.CreatedAt = previous.CreatedAt
+	tenant.UpdatedAt = r.now().UTC()
+	r.tenants[name] = tenant
+	if err := r.save(); err != nil {
+		r.tenants[name] = previous
+		return nil, fmt.Errorf("failed to persist tenant: %w", err)
+	}
+	return tenant.Redacted(), nil
+}
+
+// Get returns a tenant with redacted secrets
+func (r *Registry) Get(name string) (*Tenant, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tenant, ok := r.tenants[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	return tenant.Redacted(), nil
+}
+
+// List returns the tenants in name order, with redacted secrets
+func (r *Registry) List() []*Tenant {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tenants := r.sorted()
+	for i, tenant := range tenants {
+		tenants[i] = tenant.Redacted()
+	}
+	return tenants
+}
+
+// Delete offboards a tenant and returns it with redacted secrets
+func (r *Registry) Delete(name string) (*Tenant, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tenant, ok := r.tenants[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	delete(r.tenants, name)
+	if err := r.save(); err != nil {
+		r.tenants[name] = tenant
+		return nil, fmt.Errorf("failed to persist tenant removal: %w", err)
+	}
+	return tenant.Redacted(), nil
+}
+
+// Export returns copies of the tenants in name order, with their webhook secrets so
+// that a restored engine keeps delivering signed webhooks
+func (r *Registry) Export() []*Tenant {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tenants := r.sorted()
+	for i, tenant := range tenants {
+		tenants[i] = tenant.copy()
+	}
+	return tenants
+}
+
+// Restore imports exported tenants. Without replace they are merged with the stored
+// ones, an imported tenant overwriting the stored one of its name.
+func (r *Registry) Restore(tenants []*Tenant, replace bool) error {
+	copies := make([]*Tenant, 0, len(tenants))
+	for _, tenant := range tenants {
+		if tenant == nil {
+			return fmt.Errorf("empty tenant in archive")
+		}
+		tenant = tenant.copy()
+		if err := tenant.Validate(); err != nil {
+			return fmt.Errorf("invalid tenant %s: %w", tenant.Name, err)
+		}
+		copies = append(copies, tenant)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	previous := r.tenants
+	restored := make(map[string]*Tenant, len(previous)+len(copies))
+	if !replace {
+		for name, tenant := range previous {
+			restored[name] = tenant
+		}
+	}
+	for _, tenant := range copies {
+		restored[tenant.Name] = tenant
+	}
+	if len(restored) > DefaultMaxTenants {
+		return fmt.Errorf("%w: at most %d tenants", ErrLimitReached, DefaultMaxTenants)
+	}
+
+	r.tenants = restored
+	for _, tenant := range copies {
+		if other := r.namespaceTaken(tenant); other != nil {
+			r.tenants = previous
+			return fmt.Errorf("%w: namespace %s is onboarded by tenants %s and %s", ErrAlreadyExists, tenant.Namespace, tenant.Name, other.Name)
+		}
+	}
+	if err := r.save(); err != nil {
+		r.tenants = previous
+		return fmt.Errorf("failed to persist tenants: %w", err)
+	}
+	return nil
+}
+
+// For returns the tenant onboarding a namespace, or nil if it is not onboarded. A
+// tenant naming the namespace wins over selector tenants, which are evaluated in name
+// order. The returned tenant holds webhook secrets and must not be shown to callers.
+func (r *Registry) For(ctx context.Context, namespace string) *Tenant {
+	if namespace == "" {
+		return nil
+	}
+
+	r.mu.RLock()
+	tenants := r.sorted()
+	r.mu.RUnlock()
+
+	var selectors []*Tenant
+	for _, tenant := range tenants {
+		if tenant.Namespace == namespace {
+			return tenant.copy()
+		}
+		if len(tenant.Selector) > 0 {
+			selectors = append(selectors, tenant)
+		}
+	}
+	if len(selectors) == 0 {
+		return nil
+	}
+
+	labels := r.namespaceLabels(ctx, namespace)
+	for _, tenant := range selectors {
+		if tenant.Matches(namespace, labels) {
+			return tenant.copy()
+		}
+	}
+	return nil
+}
+
+// Covers reports whether a namespace is onboarded
+func (r *Registry) Covers(ctx context.Context, namespace string) bool {
+	return r.For(ctx, namespace) != nil
+}
+
+// Namespaces returns the namespaces a tenant onboards. Selector tenants are resolved
+// by listing the namespaces matching the selector.
+func (r *Registry) Namespaces(ctx context.Context, tenant *Tenant) ([]string, error) {
+	if tenant.Namespace != "" {
+		return []string{tenant.Namespace}, nil
+	}
+	if r.client == nil {
+		return nil, ErrNoCluster
+	}
+
+	list, err := r.client.CoreV1().Namespaces().List(ctx, metav1.ListOptions{
+		LabelSelector: k8slabels.SelectorFromSet(tenant.Selector).String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list namespaces of tenant %s: %w", tenant.Name, err)
+	}
+	namespaces := make([]string, 0, len(list.Items))
+	for i := range list.Items {
+		namespaces = append(namespaces, list.Items[i].Name)
+	}
+	sort.Strings(namespaces)
+	return namespaces, nil
+}
+
+// OnboardedNamespaces returns the namespaces of every tenant, sorted. Tenants whose
+// namespaces cannot be resolved are skipped; the first such error is returned with
+// the namespaces of the other tenants.
+func (r *Registry) OnboardedNamespaces(ctx context.Context) ([]string, error) {
+	r.mu.RLock()
+	tenants := r.sorted()
+	r.mu.RUnlock()
+
+	var firstErr error
+	seen := make(map[string]bool)
+	namespaces := []string{}
+	for _, tenant := range tenants {
+		tenantNamespaces, err := r.Namespaces(ctx, tenant)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		for _, namespace := range tenantNamespaces {
+			if !seen[namespace] {
+				seen[namespace] = true
+				namespaces = append(namespaces, namespace)
+			}
+		}
+	}
+	sort.Strings(namespaces)
+	return namespaces, firstErr
+}
+
+// TierLookup returns a tier lookup answering with the criticality of the tenant
+// onboarding a namespace, and with fallback (if not nil) for other namespaces
+func (r *Registry) TierLookup(fallback anomaly.TierLookup) anomaly.TierLookup {
+	return func(ctx context.Context, namespace string) string {
+		if tenant := r.For(ctx, namespace); tenant != nil && tenant.Criticality != "" {
+			return tenant.Criticality
+		}
+		if fallback == nil {
+			return ""
+		}
+		return fallback(ctx, namespace)
+	}
+}
+
+// WebhookTargets returns the notification targets of the tenant onboarding a
+// namespace
+func (r *Registry) WebhookTargets(namespace string) []notification.Target {
+	tenant := r.For(context.Background(), namespace)
+	if tenant == nil {
+		return nil
+	}
+	targets := make([]notification.Target, 0, len(tenant.Notifications))
+	for i := range tenant.Notifications {
+		targets = append(targets, tenant.Notifications[i].target(fmt.Sprintf("tenant/%s/%d", tenant.Name, i)))
+	}
+	return targets
+}
+
+// namespaceLabels returns the labels of a namespace, or nil if it cannot be read.
+// Results, including failures, are cached for a minute.
+func (r *Registry) namespaceLabels(ctx context.Context, namespace string) map[string]string {
+	if r.client == nil {
+		return nil
+	}
+
+	r.labelsMu.Lock()
+	cached, ok := r.labels[namespace]
+	r.labelsMu.Unlock()
+	if ok && r.now().Before(cached.expires) {
+		return cached.labels
+	}
+
+	var labels map[string]string
+	ns, err := r.client.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+	switch {
+	case err == nil:
+		labels = ns.Labels
+	case errors.Is(err, context.Canceled):
+		return nil
+	}
+
+	r.labelsMu.Lock()
+	r.labels[namespace] = cachedLabels{labels: labels, expires: r.now().Add(labelCacheTTL)}
+	r.labelsMu.Unlock()
+	return labels
+}
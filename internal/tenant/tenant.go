@@ -0,0 +1,221 @@
+// Package tenant keeps the scopes onboarded to the engine and their settings.
+//
+// A tenant onboards one namespace, or every namespace matching a label selector, with
+// its criticality tier, anomaly thresholds, business schedules and notification
+// targets. Scheduled scanners only cover onboarded scopes; offboarding a tenant stops
+// them and purges the data stored for its namespaces.
+package tenant
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/anomaly"
+	"github.com/tosin2013/openshift-coordination-engine/internal/notification"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/api/validation"
+)
+
+// Criticality tiers, as used by the default severity matrix
+const (
+	CriticalityCritical = "critical"
+	CriticalityHigh     = "high"
+	CriticalityMedium   = "medium"
+	CriticalityLow      = "low"
+)
+
+// RedactedSecret replaces webhook secrets in API responses. Updating a tenant with
+// the redacted value keeps the stored secret.
+const RedactedSecret = "********"
+
+// Tenant is an onboarded scope and its settings
+type Tenant struct {
+	// Name identifies the tenant (lowercase letters, digits and '-')
+	Name string `json:"name"`
+
+	// Namespace onboards a single namespace; Selector onboards every namespace whose
+	// labels include all of its labels. Exactly one of them is set.
+	Namespace string            `json:"namespace,omitempty"`
+	Selector  map[string]string `json:"selector,omitempty"`
+
+	// Criticality is the tier of the tenant's namespaces, taking precedence over their
+	// criticality label: critical, high, medium or low
+	Criticality string `json:"criticality,omitempty"`
+
+	// Thresholds are the anomaly analysis defaults of the tenant's namespaces
+	Thresholds Thresholds `json:"thresholds"`
+
+	// Schedules are business windows of the tenant, written in Timezone (default
+	// UTC), as in ANOMALY_CALENDARS_FILE
+	Timezone  string                   `json:"timezone,omitempty"`
+	Schedules []anomaly.BusinessWindow `json:"schedules,omitempty"`
+
+	// Notifications are webhooks receiving the events of incidents in the tenant's
+	// namespaces, in addition to the engine-wide targets
+	Notifications []Webhook `json:"notifications,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// calendar holds the compiled schedules
+	calendar *anomaly.BusinessCalendars
+}
+
+// Thresholds are the anomaly analysis defaults of a tenant. Values sent in a request
+// always win.
+type Thresholds struct {
+	// Anomaly is the anomaly score threshold, 0.0-1.0 (0 keeps the engine default)
+	Anomaly float64 `json:"anomaly,omitempty"`
+
+	// Model is the KServe model used for analysis
+	Model string `json:"model,omitempty"`
+}
+
+// Webhook is a notification target of a tenant
+type Webhook struct {
+	URL    string                   `json:"url"`
+	Secret string                   `json:"secret,omitempty"`
+	Events []notification.EventType `json:"events"`
+}
+
+// Validate checks the tenant and compiles its schedules
+func (t *Tenant) Validate() error {
+	var errs validation.Errors
+	switch {
+	case t.Name == "":
+		errs.Add("name", validation.ConstraintRequired, nil, "name is required")
+	case len(k8svalidation.IsDNS1123Label(t.Name)) > 0:
+		errs.Add("name", validation.ConstraintFormat, t.Name,
+			"name must consist of lowercase letters, digits and '-', start and end with a letter or digit, and not exceed 63 characters")
+	}
+
+	switch {
+	case t.Namespace == "" && len(t.Selector) == 0:
+		errs.Add("namespace", validation.ConstraintRequired, nil, "namespace or selector is required")
+	case t.Namespace != "" && len(t.Selector) > 0:
+		errs.Add("selector", validation.ConstraintExclusive, nil, "namespace and selector cannot be combined")
+	case t.Namespace != "" && len(k8svalidation.IsDNS1123Label(t.Namespace)) > 0:
+		errs.Add("namespace", validation.ConstraintFormat, t.Namespace, "namespace must be a valid namespace name")
+	}
+	for key, value := range t.Selector {
+		if problems := append(k8svalidation.IsQualifiedName(key), k8svalidation.IsValidLabelValue(value)...); len(problems) > 0 {
+			errs.Add("selector."+key, validation.ConstraintFormat, value,
+				fmt.Sprintf("selector.%s is not a valid label: %s", key, strings.Join(problems, ", ")))
+		}
+	}
+
+	if t.Criticality != "" {
+		if fieldErr := validation.OneOf("criticality", t.Criticality,
+			CriticalityCritical, CriticalityHigh, CriticalityMedium, CriticalityLow)(); fieldErr != nil {
+			errs = append(errs, *fieldErr)
+		}
+	}
+	if fieldErr := validation.Between("thresholds.anomaly", t.Thresholds.Anomaly, 0, 1)(); fieldErr != nil {
+		errs = append(errs, *fieldErr)
+	}
+
+	t.calendar = nil
+	if len(t.Schedules) > 0 {
+		calendar, err := anomaly.NewBusinessCalendars([]anomaly.BusinessCalendar{
+			{Name: "tenant/" + t.Name, Timezone: t.Timezone, Windows: t.Schedules},
+		})
+		if err != nil {
+			errs.Add("schedules", validation.ConstraintFormat, nil, strings.TrimPrefix(err.Error(), "business calendar tenant/"+t.Name+": "))
+		}
+		t.calendar = calendar
+	}
+
+	for i, webhook := range t.Notifications {
+		field := fmt.Sprintf("notifications[%d]", i)
+		target := webhook.target(field)
+		if err := target.Validate(); err != nil {
+			errs.Add(field, validation.ConstraintFormat, nil, strings.TrimPrefix(err.Error(), "target "))
+		}
+	}
+	return errs.Err()
+}
+
+// Active returns the tenant's business windows active at now
+func (t *Tenant) Active(now time.Time) []anomaly.ActiveWindow {
+	if t.calendar == nil {
+		return nil
+	}
+	return t.calendar.Active("", now)
+}
+
+// Matches reports whether the tenant onboards a namespace with the given labels
+func (t *Tenant) Matches(namespace string, labels map[string]string) bool {
+	if t.Namespace != "" {
+		return t.Namespace == namespace
+	}
+	for key, value := range t.Selector {
+		if labels[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// Scope describes what the tenant onboards, e.g. "namespace payments" or
+// "selector team=payments"
+func (t *Tenant) Scope() string {
+	if t.Namespace != "" {
+		return "namespace " + t.Namespace
+	}
+	pairs := make([]string, 0, len(t.Selector))
+	for key, value := range t.Selector {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return "selector " + strings.Join(pairs, ",")
+}
+
+// Redacted returns a copy of the tenant with its webhook secrets redacted
+func (t *Tenant) Redacted() *Tenant {
+	copied := t.copy()
+	for i := range copied.Notifications {
+		if copied.Notifications[i].Secret != "" {
+			copied.Notifications[i].Secret = RedactedSecret
+		}
+	}
+	return copied
+}
+
+// copy copies the tenant and its slices and maps
+func (t *Tenant) copy() *Tenant {
+	copied := *t
+	if t.Selector != nil {
+		copied.Selector = make(map[string]string, len(t.Selector))
+		for key, value := range t.Selector {
+			copied.Selector[key] = value
+		}
+	}
+	copied.Schedules = append([]anomaly.BusinessWindow(nil), t.Schedules...)
+	copied.Notifications = append([]Webhook(nil), t.Notifications...)
+	return &copied
+}
+
+// keepSecrets keeps the stored secrets of webhooks updated with the redacted value
+func (t *Tenant) keepSecrets(previous *Tenant) {
+	for i := range t.Notifications {
+		webhook := &t.Notifications[i]
+		if webhook.Secret != RedactedSecret {
+			continue
+		}
+		webhook.Secret = ""
+		for _, old := range previous.Notifications {
+			if old.URL == webhook.URL {
+				webhook.Secret = old.Secret
+				break
+			}
+		}
+	}
+}
+
+// target returns the webhook as a notification target
+func (w *Webhook) target(name string) notification.Target {
+	return notification.Target{Name: name, URL: w.URL, Secret: w.Secret, Events: w.Events}
+}
@@ -0,0 +1,154 @@
+package tenant
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/anomaly"
+	"github.com/tosin2013/openshift-coordination-engine/internal/notification"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/api/validation"
+)
+
+func namespace(name string, labels map[string]string) *corev1.Namespace {
+	return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+}
+
+func TestTenant_Validate(t *testing.T) {
+	valid := Tenant{
+		Name:        "payments",
+		Namespace:   "payments",
+		Criticality: CriticalityCritical,
+		Thresholds:  Thresholds{Anomaly: 0.8},
+		Timezone:    "Europe/Berlin",
+		Schedules:   []anomaly.BusinessWindow{{Name: "trading", Days: []string{"mon", "fri"}, Start: "09:00", End: "17:30", ThresholdIncrease: 0.1}},
+		Notifications: []Webhook{
+			{URL: "https://hooks.example.com/payments", Events: []notification.EventType{notification.EventAll}},
+		},
+	}
+	require.NoError(t, valid.Validate())
+
+	invalid := Tenant{
+		Name:          "Payments",
+		Namespace:     "payments",
+		Selector:      map[string]string{"team": "payments"},
+		Criticality:   "urgent",
+		Thresholds:    Thresholds{Anomaly: 1.5},
+		Schedules:     []anomaly.BusinessWindow{{Name: "trading", Start: "25:00", End: "17:00"}},
+		Notifications: []Webhook{{URL: "ftp://hooks.example.com"}},
+	}
+	fields := map[string]string{}
+	for _, fieldErr := range validation.Fields(invalid.Validate()) {
+		fields[fieldErr.Field] = fieldErr.Constraint
+	}
+	assert.Equal(t, map[string]string{
+		"name":               validation.ConstraintFormat,
+		"selector":           validation.ConstraintExclusive,
+		"criticality":        validation.ConstraintEnum,
+		"thresholds.anomaly": validation.ConstraintRange,
+		"schedules":          validation.ConstraintFormat,
+		"notifications[0]":   validation.ConstraintFormat,
+	}, fields)
+
+	assert.Equal(t, "namespace", validation.Fields((&Tenant{Name: "empty"}).Validate())[0].Field)
+}
+
+func TestRegistry(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	clientset := fake.NewSimpleClientset(
+		namespace("checkout", map[string]string{"team": "shop", "criticality": "low"}),
+		namespace("cart", map[string]string{"team": "shop"}),
+		namespace("sandbox", nil),
+	)
+	registry := NewRegistry(dir, clientset)
+
+	created, err := registry.Create(&Tenant{
+		Name:          "payments",
+		Namespace:     "payments",
+		Criticality:   CriticalityCritical,
+		Notifications: []Webhook{{URL: "https://hooks.example.com/payments", Secret: "s3cret", Events: []notification.EventType{notification.EventAll}}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, RedactedSecret, created.Notifications[0].Secret)
+	assert.False(t, created.CreatedAt.IsZero())
+
+	_, err = registry.Create(&Tenant{Name: "shop", Selector: map[string]string{"team": "shop"}, Thresholds: Thresholds{Anomaly: 0.9}})
+	require.NoError(t, err)
+
+	_, err = registry.Create(&Tenant{Name: "payments-2", Namespace: "payments"})
+	assert.ErrorIs(t, err, ErrAlreadyExists)
+	_, err = registry.Create(&Tenant{Name: "payments", Namespace: "other"})
+	assert.ErrorIs(t, err, ErrAlreadyExists)
+
+	// Namespaces resolve by name, then by selector
+	assert.Equal(t, "payments", registry.For(ctx, "payments").Name)
+	assert.Equal(t, "shop", registry.For(ctx, "checkout").Name)
+	assert.Nil(t, registry.For(ctx, "sandbox"))
+	assert.Nil(t, registry.For(ctx, ""))
+	assert.False(t, registry.Covers(ctx, "unknown"))
+
+	namespaces, err := registry.Namespaces(ctx, registry.For(ctx, "cart"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cart", "checkout"}, namespaces)
+	namespaces, err = registry.OnboardedNamespaces(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cart", "checkout", "payments"}, namespaces)
+
+	// Criticality wins over the namespace label; other namespaces use the fallback
+	tiers := registry.TierLookup(func(context.Context, string) string { return "label" })
+	assert.Equal(t, CriticalityCritical, tiers(ctx, "payments"))
+	assert.Equal(t, "label", tiers(ctx, "checkout"))
+
+	targets := registry.WebhookTargets("payments")
+	require.Len(t, targets, 1)
+	assert.Equal(t, "tenant/payments/0", targets[0].Name)
+	assert.Equal(t, "s3cret", targets[0].Secret)
+	assert.Empty(t, registry.WebhookTargets("checkout"))
+
+	// Updates keep redacted secrets and the creation time
+	updated, err := registry.Update("payments", &Tenant{
+		Namespace:     "payments",
+		Criticality:   CriticalityHigh,
+		Notifications: []Webhook{{URL: "https://hooks.example.com/payments", Secret: RedactedSecret, Events: []notification.EventType{notification.EventAll}}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, created.CreatedAt, updated.CreatedAt)
+	assert.Equal(t, "s3cret", registry.WebhookTargets("payments")[0].Secret)
+	_, err = registry.Update("missing", &Tenant{Namespace: "missing"})
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// Tenants survive a restart
+	reloaded := NewRegistry(dir, clientset)
+	require.Len(t, reloaded.List(), 2)
+	assert.Equal(t, CriticalityHigh, reloaded.For(ctx, "payments").Criticality)
+
+	deleted, err := reloaded.Delete("shop")
+	require.NoError(t, err)
+	assert.Equal(t, "selector team=shop", deleted.Scope())
+	assert.Nil(t, reloaded.For(ctx, "checkout"))
+	_, err = reloaded.Delete("shop")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestTenant_Active(t *testing.T) {
+	tenant := &Tenant{
+		Name:      "trading",
+		Namespace: "trading",
+		Timezone:  "America/New_York",
+		Schedules: []anomaly.BusinessWindow{{Name: "market-hours", Start: "09:30", End: "16:00", ThresholdIncrease: 0.1}},
+	}
+	require.NoError(t, tenant.Validate())
+
+	open := time.Date(2026, 3, 2, 15, 0, 0, 0, time.UTC) // 10:00 in New York
+	active := tenant.Active(open)
+	require.Len(t, active, 1)
+	assert.Equal(t, "tenant/trading/market-hours", active[0].String())
+	assert.Empty(t, tenant.Active(open.Add(8*time.Hour)))
+}
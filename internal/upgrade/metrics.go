@@ -0,0 +1,30 @@
+package upgrade
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// UpgradeInProgress is 1 while the ClusterVersion is progressing
+	UpgradeInProgress = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "coordination_engine_cluster_upgrade_in_progress",
+			Help: "Whether a cluster upgrade is in progress (1) and the engine is in conservative mode",
+		},
+	)
+
+	// RemediationsBlocked counts remediation requests refused during an upgrade
+	RemediationsBlocked = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "coordination_engine_upgrade_blocked_remediations_total",
+			Help: "Total number of remediation requests refused because a cluster upgrade was in progress, by source",
+		},
+		[]string{"source"},
+	)
+)
+
+// RecordBlockedRemediation records a remediation refused during an upgrade
+func RecordBlockedRemediation(source string) {
+	RemediationsBlocked.WithLabelValues(source).Inc()
+}
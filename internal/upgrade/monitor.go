@@ -0,0 +1,206 @@
+// Package upgrade switches the engine into a conservative mode while the cluster is
+// upgrading.
+//
+// During an OpenShift upgrade operators roll out new versions and nodes are drained
+// and rebooted, so pod restarts, evictions and transient degradation are expected.
+// The Monitor polls the ClusterVersion and, while it is progressing, lets callers
+// block automated remediation, raise anomaly thresholds and tag new incidents.
+package upgrade
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/integrations"
+)
+
+// Incident labels added while an upgrade is in progress
+const (
+	LabelDuringUpgrade        = "during_upgrade"
+	LabelUpgradeTargetVersion = "upgrade_target_version"
+)
+
+// DefaultCheckInterval is how often the ClusterVersion is polled when none is configured
+const DefaultCheckInterval = time.Minute
+
+// Policy controls what changes while an upgrade is in progress
+type Policy struct {
+	// BlockRemediation refuses automated remediation unless forced
+	BlockRemediation bool
+
+	// ThresholdIncrease is added to anomaly thresholds (capped at 1.0)
+	ThresholdIncrease float64
+
+	// TagIncidents labels new incidents with during_upgrade=true
+	TagIncidents bool
+}
+
+// StatusSource supplies the ClusterVersion state (implemented by integrations.ClusterVersionClient)
+type StatusSource interface {
+	GetStatus(ctx context.Context) (*integrations.ClusterVersionStatus, error)
+}
+
+// State is the last observed upgrade state
+type State struct {
+	InProgress     bool      `json:"in_progress"`
+	Version        string    `json:"version,omitempty"`
+	DesiredVersion string    `json:"desired_version,omitempty"`
+	Message        string    `json:"message,omitempty"`
+	Since          time.Time `json:"since,omitempty"`
+	CheckedAt      time.Time `json:"checked_at,omitempty"`
+
+	// Policy in effect while InProgress is true
+	BlockRemediation  bool    `json:"block_remediation"`
+	ThresholdIncrease float64 `json:"threshold_increase"`
+	TagIncidents      bool    `json:"tag_incidents"`
+}
+
+// Monitor caches the ClusterVersion progressing state and applies the upgrade policy
+type Monitor struct {
+	source StatusSource
+	policy Policy
+	log    *logrus.Logger
+
+	mu    sync.RWMutex
+	state State
+
+	// now is replaceable in tests
+	now func() time.Time
+}
+
+// NewMonitor creates an upgrade monitor. The state is "not upgrading" until the
+// first successful Refresh.
+func NewMonitor(source StatusSource, policy Policy, log *logrus.Logger) *Monitor {
+	return &Monitor{
+		source: source,
+		policy: policy,
+		log:    log,
+		now:    time.Now,
+	}
+}
+
+// Start refreshes the upgrade state immediately and then every interval until ctx is cancelled
+func (m *Monitor) Start(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultCheckInterval
+	}
+
+	go func() {
+		if err := m.Refresh(ctx); err != nil {
+			m.log.WithError(err).Warn("Failed to check ClusterVersion")
+		}
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := m.Refresh(ctx); err != nil {
+					m.log.WithError(err).Warn("Failed to check ClusterVersion")
+				}
+			}
+		}
+	}()
+
+	m.log.WithField("interval", interval).Info("Upgrade-aware mode started")
+}
+
+// Refresh reads the ClusterVersion and updates the cached state. On error the
+// previous state is kept, so a transient API failure does not end conservative mode.
+func (m *Monitor) Refresh(ctx context.Context) error {
+	status, err := m.source.GetStatus(ctx)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	wasInProgress := m.state.InProgress
+	m.state = State{
+		InProgress:     status.Progressing,
+		Version:        status.Version,
+		DesiredVersion: status.DesiredVersion,
+		Message:        status.Message,
+		Since:          status.Since,
+		CheckedAt:      m.now(),
+	}
+	m.mu.Unlock()
+
+	if status.Progressing {
+		UpgradeInProgress.Set(1)
+	} else {
+		UpgradeInProgress.Set(0)
+	}
+
+	switch {
+	case status.Progressing && !wasInProgress:
+		m.log.WithFields(logrus.Fields{
+			"version":         status.Version,
+			"desired_version": status.DesiredVersion,
+		}).Warn("Cluster upgrade in progress, entering conservative mode")
+	case !status.Progressing && wasInProgress:
+		m.log.WithField("version", status.DesiredVersion).Info("Cluster upgrade finished, leaving conservative mode")
+	}
+
+	return nil
+}
+
+// State returns the last observed upgrade state with the policy in effect
+func (m *Monitor) State() State {
+	m.mu.RLock()
+	state := m.state
+	m.mu.RUnlock()
+
+	state.BlockRemediation = m.policy.BlockRemediation
+	state.ThresholdIncrease = m.policy.ThresholdIncrease
+	state.TagIncidents = m.policy.TagIncidents
+	return state
+}
+
+// Active reports whether the cluster is upgrading
+func (m *Monitor) Active() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.state.InProgress
+}
+
+// RemediationBlocked reports whether automated remediation must be refused
+func (m *Monitor) RemediationBlocked() bool {
+	return m.policy.BlockRemediation && m.Active()
+}
+
+// AdjustThreshold raises an anomaly threshold during an upgrade, capped at 1.0
+func (m *Monitor) AdjustThreshold(threshold float64) float64 {
+	if !m.Active() || m.policy.ThresholdIncrease <= 0 {
+		return threshold
+	}
+	threshold += m.policy.ThresholdIncrease
+	if threshold > 1 {
+		threshold = 1
+	}
+	return threshold
+}
+
+// IncidentLabels returns the labels to add to incidents created now, or nil when
+// no upgrade is in progress or tagging is disabled. It implements storage.IncidentLabeler.
+func (m *Monitor) IncidentLabels() map[string]string {
+	if !m.policy.TagIncidents {
+		return nil
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if !m.state.InProgress {
+		return nil
+	}
+
+	labels := map[string]string{LabelDuringUpgrade: "true"}
+	if m.state.DesiredVersion != "" {
+		labels[LabelUpgradeTargetVersion] = m.state.DesiredVersion
+	}
+	return labels
+}
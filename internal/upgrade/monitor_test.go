@@ -0,0 +1,83 @@
+package upgrade
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/integrations"
+)
+
+type fakeSource struct {
+	status *integrations.ClusterVersionStatus
+	err    error
+}
+
+func (f *fakeSource) GetStatus(_ context.Context) (*integrations.ClusterVersionStatus, error) {
+	return f.status, f.err
+}
+
+func newTestMonitor(source StatusSource, policy Policy) *Monitor {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	return NewMonitor(source, policy, log)
+}
+
+func TestMonitor_ConservativeMode(t *testing.T) {
+	source := &fakeSource{status: &integrations.ClusterVersionStatus{
+		Version:        "4.16.3",
+		DesiredVersion: "4.16.5",
+		Progressing:    true,
+	}}
+	m := newTestMonitor(source, Policy{BlockRemediation: true, ThresholdIncrease: 0.15, TagIncidents: true})
+	ctx := context.Background()
+
+	// Nothing is changed before the first check
+	assert.False(t, m.Active())
+	assert.False(t, m.RemediationBlocked())
+	assert.Nil(t, m.IncidentLabels())
+	assert.InDelta(t, 0.7, m.AdjustThreshold(0.7), 1e-9)
+
+	require.NoError(t, m.Refresh(ctx))
+	assert.True(t, m.Active())
+	assert.True(t, m.RemediationBlocked())
+	assert.InDelta(t, 0.85, m.AdjustThreshold(0.7), 1e-9)
+	assert.InDelta(t, 1.0, m.AdjustThreshold(0.95), 1e-9, "threshold is capped at 1.0")
+	assert.Equal(t, map[string]string{
+		LabelDuringUpgrade:        "true",
+		LabelUpgradeTargetVersion: "4.16.5",
+	}, m.IncidentLabels())
+
+	state := m.State()
+	assert.True(t, state.InProgress)
+	assert.Equal(t, "4.16.5", state.DesiredVersion)
+	assert.True(t, state.BlockRemediation)
+	assert.False(t, state.CheckedAt.IsZero())
+
+	// A failed check keeps the previous state
+	source.err = errors.New("api unavailable")
+	assert.Error(t, m.Refresh(ctx))
+	assert.True(t, m.Active())
+
+	source.err = nil
+	source.status = &integrations.ClusterVersionStatus{Version: "4.16.5", DesiredVersion: "4.16.5"}
+	require.NoError(t, m.Refresh(ctx))
+	assert.False(t, m.Active())
+	assert.False(t, m.RemediationBlocked())
+	assert.Nil(t, m.IncidentLabels())
+}
+
+func TestMonitor_Policy(t *testing.T) {
+	source := &fakeSource{status: &integrations.ClusterVersionStatus{Progressing: true}}
+	m := newTestMonitor(source, Policy{})
+	require.NoError(t, m.Refresh(context.Background()))
+
+	assert.True(t, m.Active())
+	assert.False(t, m.RemediationBlocked())
+	assert.InDelta(t, 0.7, m.AdjustThreshold(0.7), 1e-9)
+	assert.Nil(t, m.IncidentLabels())
+}
@@ -0,0 +1,181 @@
+package openapi
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Annotation is the swag annotation block of a handler
+type Annotation struct {
+	// Package is the name of the handler's package, which qualifies its type names
+	Package string
+	Func    string
+
+	ID          string
+	Summary     string
+	Description string
+	Tags        []string
+	Accept      []string
+	Produce     []string
+	Params      []ParamAnnotation
+	Responses   []ResponseAnnotation
+	Method      string
+	Path        string
+}
+
+// ParamAnnotation is an @Param line: name, location (path, query or body), type,
+// whether it is required and its description
+type ParamAnnotation struct {
+	Name        string
+	In          string
+	Type        string
+	Required    bool
+	Description string
+}
+
+// ResponseAnnotation is an @Success or @Failure line. Kind is "object" for JSON
+// responses and "string" for text.
+type ResponseAnnotation struct {
+	Status      int
+	Kind        string
+	Type        string
+	Description string
+}
+
+var (
+	paramPattern    = regexp.MustCompile(`^(\S+)\s+(path|query|body)\s+(\S+)\s+(true|false)\s+"(.*)"$`)
+	responsePattern = regexp.MustCompile(`^(\d{3})\s+\{(\w+)\}\s+(\S+)(?:\s+"(.*)")?$`)
+	routerPattern   = regexp.MustCompile(`^(\S+)\s+\[(\w+)\]$`)
+)
+
+// ParseAnnotations returns the annotated handlers of the Go files in dirs, sorted by
+// path and method
+func ParseAnnotations(dirs ...string) ([]Annotation, error) {
+	var annotations []Annotation
+	fset := token.NewFileSet()
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			name := entry.Name()
+			if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+				continue
+			}
+			file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.ParseComments)
+			if err != nil {
+				return nil, err
+			}
+			for _, decl := range file.Decls {
+				fn, ok := decl.(*ast.FuncDecl)
+				if !ok || fn.Doc == nil {
+					continue
+				}
+				annotation, found, err := parseAnnotation(file.Name.Name, fn)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", fset.Position(fn.Pos()), err)
+				}
+				if found {
+					annotations = append(annotations, annotation)
+				}
+			}
+		}
+	}
+
+	sort.Slice(annotations, func(i, j int) bool {
+		if annotations[i].Path != annotations[j].Path {
+			return annotations[i].Path < annotations[j].Path
+		}
+		return annotations[i].Method < annotations[j].Method
+	})
+	return annotations, nil
+}
+
+// parseAnnotation parses the swag annotations of a function's doc comment. Functions
+// without @Router are not handlers and are skipped.
+func parseAnnotation(pkg string, fn *ast.FuncDecl) (Annotation, bool, error) {
+	a := Annotation{Package: pkg, Func: fn.Name.Name}
+	var descriptions []string
+	for _, comment := range fn.Doc.List {
+		line := strings.TrimSpace(strings.TrimPrefix(comment.Text, "//"))
+		if !strings.HasPrefix(line, "@") {
+			continue
+		}
+		key, value, _ := strings.Cut(line, " ")
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "@ID":
+			a.ID = value
+		case "@Summary":
+			a.Summary = value
+		case "@Description":
+			descriptions = append(descriptions, value)
+		case "@Tags":
+			a.Tags = append(a.Tags, splitList(value)...)
+		case "@Accept":
+			a.Accept = append(a.Accept, splitList(value)...)
+		case "@Produce":
+			a.Produce = append(a.Produce, splitList(value)...)
+		case "@Param":
+			m := paramPattern.FindStringSubmatch(value)
+			if m == nil {
+				return a, false, fmt.Errorf("invalid @Param %q", value)
+			}
+			a.Params = append(a.Params, ParamAnnotation{Name: m[1], In: m[2], Type: m[3], Required: m[4] == "true", Description: m[5]})
+		case "@Success", "@Failure":
+			m := responsePattern.FindStringSubmatch(value)
+			if m == nil {
+				return a, false, fmt.Errorf("invalid %s %q", key, value)
+			}
+			status, _ := strconv.Atoi(m[1])
+			a.Responses = append(a.Responses, ResponseAnnotation{Status: status, Kind: m[2], Type: m[3], Description: m[4]})
+		case "@Router":
+			m := routerPattern.FindStringSubmatch(value)
+			if m == nil {
+				return a, false, fmt.Errorf("invalid @Router %q", value)
+			}
+			a.Path, a.Method = m[1], strings.ToLower(m[2])
+		}
+	}
+	if a.Path == "" {
+		return a, false, nil
+	}
+
+	a.Description = strings.Join(descriptions, "\n")
+	if a.ID == "" {
+		a.ID = lowerFirst(strings.TrimPrefix(a.Func, "Handle"))
+	}
+	return a, true, nil
+}
+
+// splitList splits a comma-separated annotation value
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// lowerFirst lowercases the first letter of s
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	r := []rune(s)
+	r[0] = unicode.ToLower(r[0])
+	return string(r)
+}
@@ -0,0 +1,279 @@
+// Package openapi generates the OpenAPI document of the REST API from the swag
+// annotations of the handlers in pkg/api/v1 and pkg/api/v2, and the Python client in
+// clients/python from that document. Both are committed; regenerate them with
+// "make openapi" after changing an annotated handler or its request and response types.
+//
+// Schemas are derived from the Go types by reflection, the way encoding/json encodes
+// them, so they cannot drift from the handlers. Annotated types must be listed in Types.
+package openapi
+
+//go:generate go run ../../../cmd/openapi-gen -root ../../..
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Version is the version of the generated document and Python client. Bump the minor
+// version when the API gains endpoints or fields, and the major version when it breaks
+// clients.
+const Version = "1.0.0"
+
+// Document is an OpenAPI 3.0 document
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+}
+
+// Info describes the API
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// PathItem maps the lowercase HTTP methods of a path to their operations
+type PathItem map[string]*Operation
+
+// Operation is an API operation
+type Operation struct {
+	OperationID string               `json:"operationId"`
+	Summary     string               `json:"summary,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Tags        []string             `json:"tags,omitempty"`
+	Parameters  []Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+// Parameter is a path or query parameter
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody is the JSON body of an operation
+type RequestBody struct {
+	Description string               `json:"description,omitempty"`
+	Required    bool                 `json:"required,omitempty"`
+	Content     map[string]MediaType `json:"content"`
+}
+
+// Response is a response of an operation
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType is the schema of a body in one media type
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Components holds the schemas referenced by operations
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+// Build creates the OpenAPI document of the annotated handlers
+func Build(annotations []Annotation) (*Document, error) {
+	doc := &Document{
+		OpenAPI: "3.0.3",
+		Info: Info{
+			Title:       "OpenShift Coordination Engine API",
+			Description: "Anomaly analysis, predictions, capacity and remediation coordination for OpenShift clusters",
+			Version:     Version,
+		},
+		Paths: make(map[string]PathItem),
+	}
+	builder := newSchemaBuilder()
+	ids := make(map[string]string)
+
+	for i := range annotations {
+		a := &annotations[i]
+		if other, ok := ids[a.ID]; ok {
+			return nil, fmt.Errorf("%s %s: operation ID %q is already used by %s; set a unique @ID", strings.ToUpper(a.Method), a.Path, a.ID, other)
+		}
+		ids[a.ID] = strings.ToUpper(a.Method) + " " + a.Path
+
+		op, err := buildOperation(a, builder)
+		if err != nil {
+			return nil, fmt.Errorf("%s %s: %w", strings.ToUpper(a.Method), a.Path, err)
+		}
+		if doc.Paths[a.Path] == nil {
+			doc.Paths[a.Path] = make(PathItem)
+		}
+		doc.Paths[a.Path][a.Method] = op
+	}
+
+	doc.Components.Schemas = builder.components
+	return doc, nil
+}
+
+// buildOperation converts an annotation to an operation
+func buildOperation(a *Annotation, builder *schemaBuilder) (*Operation, error) {
+	op := &Operation{
+		OperationID: a.ID,
+		Summary:     a.Summary,
+		Description: a.Description,
+		Tags:        a.Tags,
+		Responses:   make(map[string]*Response),
+	}
+
+	for _, param := range a.Params {
+		if param.In == "body" {
+			schema, err := typeSchema(a.Package, param.Type, builder)
+			if err != nil {
+				return nil, err
+			}
+			op.RequestBody = &RequestBody{
+				Description: param.Description,
+				Required:    param.Required,
+				Content:     map[string]MediaType{"application/json": {Schema: schema}},
+			}
+			continue
+		}
+		schema, err := paramSchema(param.Type)
+		if err != nil {
+			return nil, fmt.Errorf("parameter %s: %w", param.Name, err)
+		}
+		op.Parameters = append(op.Parameters, Parameter{
+			Name:        param.Name,
+			In:          param.In,
+			Description: param.Description,
+			Required:    param.Required || param.In == "path",
+			Schema:      schema,
+		})
+	}
+
+	for _, resp := range a.Responses {
+		description := resp.Description
+		if description == "" {
+			description = http.StatusText(resp.Status)
+		}
+		response := &Response{Description: description, Content: make(map[string]MediaType)}
+
+		switch resp.Kind {
+		case "object":
+			schema, err := typeSchema(a.Package, resp.Type, builder)
+			if err != nil {
+				return nil, err
+			}
+			response.Content["application/json"] = MediaType{Schema: schema}
+		case "string":
+			for _, mediaType := range textMediaTypes(a.Produce) {
+				response.Content[mediaType] = MediaType{Schema: &Schema{Type: "string"}}
+			}
+		default:
+			return nil, fmt.Errorf("unsupported response kind {%s}", resp.Kind)
+		}
+		op.Responses[strconv.Itoa(resp.Status)] = response
+	}
+	if len(op.Responses) == 0 {
+		return nil, fmt.Errorf("no @Success or @Failure responses")
+	}
+	return op, nil
+}
+
+// typeSchema returns the schema of a type named in an annotation of package pkg
+func typeSchema(pkg, name string, builder *schemaBuilder) (*Schema, error) {
+	switch name {
+	case "object":
+		return &Schema{Type: "object"}, nil
+	case "map[string]interface{}", "map[string]any":
+		return &Schema{Type: "object", AdditionalProperties: &Schema{}}, nil
+	}
+
+	qualified := name
+	if !strings.Contains(name, ".") {
+		qualified = pkg + "." + name
+	}
+	t, ok := Types[qualified]
+	if !ok {
+		return nil, fmt.Errorf("type %s is not listed in openapi.Types", qualified)
+	}
+	return builder.schema(t), nil
+}
+
+// paramSchema returns the schema of a path or query parameter type
+func paramSchema(name string) (*Schema, error) {
+	switch name {
+	case "string":
+		return &Schema{Type: "string"}, nil
+	case "int", "integer":
+		return &Schema{Type: "integer"}, nil
+	case "number":
+		return &Schema{Type: "number"}, nil
+	case "bool", "boolean":
+		return &Schema{Type: "boolean"}, nil
+	default:
+		return nil, fmt.Errorf("unsupported parameter type %s", name)
+	}
+}
+
+// textMediaTypes returns the media types of a text response: the non-JSON @Produce
+// types, or text/plain
+func textMediaTypes(produce []string) []string {
+	var types []string
+	for _, mediaType := range produce {
+		if mediaType != "json" && mediaType != "application/json" {
+			types = append(types, mediaType)
+		}
+	}
+	if len(types) == 0 {
+		types = []string{"text/plain"}
+	}
+	return types
+}
+
+// Marshal encodes the document as indented JSON
+func (d *Document) Marshal() ([]byte, error) {
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// Generated files, relative to the repository root
+const (
+	SpecFile   = "api/openapi/openapi.json"
+	PythonFile = "clients/python/coordination_engine/client.py"
+)
+
+// HandlerDirs are the directories of the annotated handlers, relative to the
+// repository root
+var HandlerDirs = []string{"pkg/api/v1", "pkg/api/v2"}
+
+// Generate returns the OpenAPI document and the Python client of the handlers of the
+// repository at root
+func Generate(root string) (spec, python []byte, err error) {
+	dirs := make([]string, 0, len(HandlerDirs))
+	for _, dir := range HandlerDirs {
+		dirs = append(dirs, filepath.Join(root, dir))
+	}
+	annotations, err := ParseAnnotations(dirs...)
+	if err != nil {
+		return nil, nil, err
+	}
+	doc, err := Build(annotations)
+	if err != nil {
+		return nil, nil, err
+	}
+	if spec, err = doc.Marshal(); err != nil {
+		return nil, nil, err
+	}
+	if python, err = RenderPython(doc); err != nil {
+		return nil, nil, err
+	}
+	return spec, python, nil
+}
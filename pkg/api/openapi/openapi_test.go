@@ -0,0 +1,160 @@
+package openapi
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate_UpToDate(t *testing.T) {
+	root := filepath.Join("..", "..", "..")
+	spec, python, err := Generate(root)
+	require.NoError(t, err)
+
+	for file, generated := range map[string][]byte{SpecFile: spec, PythonFile: python} {
+		committed, err := os.ReadFile(filepath.Join(root, file))
+		require.NoError(t, err)
+		assert.Equal(t, string(committed), string(generated), "%s is out of date, run 'make openapi'", file)
+	}
+}
+
+const annotatedSource = `package v1
+
+// Analyze handles POST /api/v1/things/{name}/analyze
+// @Summary Analyze a thing
+// @Description First line
+// @Description second line
+// @Tags things
+// @Accept json
+// @Produce json
+// @Param name path string true "Thing name"
+// @Param verbose query bool false "Verbose output"
+// @Param request body ThingRequest true "Analysis request"
+// @Success 200 {object} ThingResponse
+// @Failure 400 {object} ErrorResponse "Invalid request"
+// @Router /api/v1/things/{name}/analyze [post]
+func Analyze() {}
+
+// helper is not a handler
+func helper() {}
+`
+
+func TestParseAnnotations(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "things.go"), []byte(annotatedSource), 0o600))
+
+	annotations, err := ParseAnnotations(dir)
+	require.NoError(t, err)
+	require.Len(t, annotations, 1)
+
+	a := annotations[0]
+	assert.Equal(t, "v1", a.Package)
+	assert.Equal(t, "analyze", a.ID)
+	assert.Equal(t, "post", a.Method)
+	assert.Equal(t, "/api/v1/things/{name}/analyze", a.Path)
+	assert.Equal(t, "First line\nsecond line", a.Description)
+	assert.Equal(t, []ParamAnnotation{
+		{Name: "name", In: "path", Type: "string", Required: true, Description: "Thing name"},
+		{Name: "verbose", In: "query", Type: "bool", Description: "Verbose output"},
+		{Name: "request", In: "body", Type: "ThingRequest", Required: true, Description: "Analysis request"},
+	}, a.Params)
+	assert.Equal(t, ResponseAnnotation{Status: 400, Kind: "object", Type: "ErrorResponse", Description: "Invalid request"}, a.Responses[1])
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.go"), []byte("package v1\n\n// @Param name\n// @Router /x [get]\nfunc Broken() {}\n"), 0o600))
+	_, err = ParseAnnotations(dir)
+	assert.ErrorContains(t, err, `invalid @Param "name"`)
+}
+
+type testScope struct {
+	Namespace string `json:"namespace,omitempty"`
+	Pod       string `json:"pod,omitempty"`
+}
+
+type testRequest struct {
+	testScope
+	Pod      int               `json:"pod"` // shadows the embedded field
+	At       time.Time         `json:"at"`
+	Window   time.Duration     `json:"window"`
+	Labels   map[string]string `json:"labels"`
+	Children []*testRequest    `json:"children"`
+	Count    int64             `json:"count,string"`
+	Hidden   string            `json:"-"`
+	internal string
+}
+
+func TestSchemaBuilder(t *testing.T) {
+	builder := newSchemaBuilder()
+	ref := builder.schema(reflect.TypeOf(&testRequest{}))
+	assert.Equal(t, "#/components/schemas/testRequest", ref.Ref)
+
+	schema := builder.components["testRequest"]
+	require.NotNil(t, schema)
+	assert.ElementsMatch(t, []string{"namespace", "pod", "at", "window", "labels", "children", "count"}, keys(schema.Properties))
+	assert.Equal(t, "integer", schema.Properties["pod"].Type)
+	assert.Equal(t, &Schema{Type: "string", Format: "date-time"}, schema.Properties["at"])
+	assert.Equal(t, &Schema{Type: "object", AdditionalProperties: &Schema{Type: "string"}}, schema.Properties["labels"])
+	assert.Equal(t, ref.Ref, schema.Properties["children"].Items.Ref)
+	assert.Equal(t, "string", schema.Properties["count"].Type)
+	assert.NotContains(t, builder.components, "testScope")
+}
+
+func TestBuild(t *testing.T) {
+	Types["v1.testRequest"] = reflect.TypeOf(testRequest{})
+	defer delete(Types, "v1.testRequest")
+
+	annotation := Annotation{
+		Package: "v1", ID: "createThing", Method: "post", Path: "/api/v1/things/{name}",
+		Params: []ParamAnnotation{
+			{Name: "name", In: "path", Type: "string"},
+			{Name: "request", In: "body", Type: "testRequest", Required: true},
+		},
+		Responses: []ResponseAnnotation{{Status: 201, Kind: "object", Type: "testRequest"}},
+	}
+
+	doc, err := Build([]Annotation{annotation})
+	require.NoError(t, err)
+	op := doc.Paths["/api/v1/things/{name}"]["post"]
+	require.NotNil(t, op)
+	assert.True(t, op.Parameters[0].Required, "path parameters are required")
+	assert.Equal(t, "Created", op.Responses["201"].Description)
+	assert.Contains(t, doc.Components.Schemas, "testRequest")
+
+	python, err := RenderPython(doc)
+	require.NoError(t, err)
+	assert.Contains(t, string(python), `def create_thing(self, name: "str", request: "testRequest") -> "testRequest":`)
+	assert.Contains(t, string(python), `f"/api/v1/things/{_path(name)}"`)
+
+	_, err = Build([]Annotation{annotation, annotation})
+	assert.ErrorContains(t, err, `operation ID "createThing" is already used`)
+
+	annotation.Responses[0].Type = "Missing"
+	_, err = Build([]Annotation{annotation})
+	assert.ErrorContains(t, err, "type v1.Missing is not listed in openapi.Types")
+}
+
+func TestSnakeCase(t *testing.T) {
+	for in, want := range map[string]string{
+		"analyzeAnomaliesV2":  "analyze_anomalies_v2",
+		"detectStatefulSet":   "detect_stateful_set",
+		"getHTTPStatus":       "get_http_status",
+		"include_trending":    "include_trending",
+		"label-selector":      "label_selector",
+		"predictionAccuracy":  "prediction_accuracy",
+		"clearDetectionCache": "clear_detection_cache",
+	} {
+		assert.Equal(t, want, snakeCase(in), in)
+	}
+}
+
+func keys(m map[string]*Schema) []string {
+	result := make([]string, 0, len(m))
+	for key := range m {
+		result = append(result, key)
+	}
+	return result
+}
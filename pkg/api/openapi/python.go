@@ -0,0 +1,460 @@
+package openapi
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// pythonTemplate renders the Python client module (data: pythonModule). It only uses
+// the standard library, so it runs in notebooks without installing dependencies.
+const pythonTemplate = `# Code generated by openapi-gen from api/openapi/openapi.json. DO NOT EDIT.
+"""Python client for the OpenShift Coordination Engine REST API.
+
+Example::
+
+    from coordination_engine import Client
+
+    client = Client("https://coordination-engine:8080", token="cet_...")
+    analysis = client.analyze_anomalies({"namespace": "payments", "time_range": "6h"})
+
+GET requests are retried with exponential backoff on connection errors and 429 and
+5xx responses; other requests are only retried on 429, since a failed attempt may
+still have been applied. Error responses raise ApiError.
+"""
+
+from __future__ import annotations
+
+import json
+import ssl
+import time
+import urllib.error
+import urllib.parse
+import urllib.request
+from http import HTTPStatus
+from typing import Any, Dict, List, Optional, TypedDict
+
+__version__ = {{quote .Version}}
+
+__all__ = [
+    "ApiError",
+    "Client",
+{{- range .Types}}
+    {{quote .Name}},
+{{- end}}
+]
+
+{{range .Types}}
+{{.Name}} = TypedDict(
+    {{quote .Name}},
+    {
+{{- range .Fields}}
+        {{quote .Key}}: {{quote .Type}},
+{{- end}}
+    },
+    total=False,
+)
+{{end}}
+
+class ApiError(Exception):
+    """An error response of the coordination engine."""
+
+    def __init__(self, status: int, body: Any):
+        self.status = status
+        self.body = body
+        self.code: Optional[str] = None
+        self.errors: List[Dict[str, Any]] = []
+        message = None
+        if isinstance(body, dict):
+            message = body.get("error") or body.get("message")
+            self.code = body.get("code")
+            self.errors = body.get("errors") or []
+        elif body:
+            message = str(body).strip()
+        if not message:
+            try:
+                message = HTTPStatus(status).phrase
+            except ValueError:
+                message = "error"
+        self.message = message
+        super().__init__(f"coordination engine returned {status}: {message}")
+
+
+class Client:
+    """Client for the coordination engine REST API.
+
+    token is an API token (cet_...) sent as a bearer token. Pass an ssl_context
+    with a client certificate for mutual TLS.
+    """
+
+    def __init__(
+        self,
+        base_url: str,
+        token: Optional[str] = None,
+        timeout: float = 30.0,
+        max_attempts: int = 3,
+        backoff: float = 0.5,
+        max_backoff: float = 10.0,
+        ssl_context: Optional[ssl.SSLContext] = None,
+        user_agent: str = "coordination-engine-python/" + __version__,
+    ):
+        self.base_url = base_url.rstrip("/")
+        self.token = token
+        self.timeout = timeout
+        self.max_attempts = max(1, max_attempts)
+        self.backoff = backoff
+        self.max_backoff = max_backoff
+        self.ssl_context = ssl_context
+        self.user_agent = user_agent
+{{range .Operations}}
+    def {{.Name}}(self{{range .Args}}, {{.}}{{end}}){{if .Returns}} -> {{quote .Returns}}{{end}}:
+        """{{.Doc}}"""
+        return self._request(
+            {{quote .Method}},
+            {{.Path}},
+{{- if .Query}}
+            query={ {{- range $i, $p := .Query}}{{if $i}}, {{end}}{{quote $p.Key}}: {{$p.Name}}{{end -}} },
+{{- end}}
+{{- if .Body}}
+            body={{.Body}},
+{{- end}}
+        )
+{{end}}
+    def _request(
+        self,
+        method: str,
+        path: str,
+        query: Optional[Dict[str, Any]] = None,
+        body: Any = None,
+    ) -> Any:
+        url = self.base_url + path
+        params = {key: _query_value(value) for key, value in (query or {}).items() if value is not None}
+        if params:
+            url += "?" + urllib.parse.urlencode(params)
+
+        headers = {"Accept": "application/json", "User-Agent": self.user_agent}
+        data = None
+        if body is not None:
+            data = json.dumps(body).encode()
+            headers["Content-Type"] = "application/json"
+        if self.token:
+            headers["Authorization"] = "Bearer " + self.token
+
+        delay = self.backoff
+        attempt = 1
+        while True:
+            request = urllib.request.Request(url, data=data, headers=headers, method=method)
+            retry_after = None
+            try:
+                with urllib.request.urlopen(request, timeout=self.timeout, context=self.ssl_context) as response:
+                    return _decode(response.headers.get_content_type(), response.read())
+            except urllib.error.HTTPError as err:
+                status = err.code
+                retry_after = _retry_after(err.headers.get("Retry-After"))
+                error: Exception = ApiError(status, _decode(err.headers.get_content_type(), err.read()))
+            except OSError as err:
+                status = 0
+                error = err
+
+            retryable = status == 429 or (method == "GET" and (status == 0 or status >= 500))
+            if attempt >= self.max_attempts or not retryable:
+                raise error
+            time.sleep(min(retry_after, self.max_backoff) if retry_after else delay)
+            delay = min(delay * 2, self.max_backoff)
+            attempt += 1
+
+
+def _path(value: Any) -> str:
+    return urllib.parse.quote(str(value), safe="")
+
+
+def _query_value(value: Any) -> str:
+    if isinstance(value, bool):
+        return "true" if value else "false"
+    return str(value)
+
+
+def _decode(content_type: str, data: bytes) -> Any:
+    if not data:
+        return None
+    if content_type == "application/json" or content_type.endswith("+json"):
+        return json.loads(data)
+    return data.decode()
+
+
+def _retry_after(value: Optional[str]) -> Optional[float]:
+    try:
+        seconds = float(value) if value else 0
+    except ValueError:
+        return None
+    return seconds if seconds > 0 else None
+`
+
+// pythonModule is the data of pythonTemplate
+type pythonModule struct {
+	Version    string
+	Types      []pythonType
+	Operations []pythonOperation
+}
+
+// pythonType is a TypedDict generated from a component schema
+type pythonType struct {
+	Name   string
+	Fields []pythonField
+}
+
+// pythonField is a key of a TypedDict and its Python type
+type pythonField struct {
+	Key  string
+	Type string
+}
+
+// pythonOperation is a Client method generated from an operation
+type pythonOperation struct {
+	Name    string
+	Doc     string
+	Method  string
+	Path    string // Python expression
+	Args    []string
+	Query   []pythonParam
+	Body    string
+	Returns string
+}
+
+// pythonParam is a query parameter and the method argument holding it
+type pythonParam struct {
+	Key  string
+	Name string
+}
+
+// pythonReserved are names the client module defines or imports, which component
+// schemas cannot use
+var pythonReserved = map[string]bool{
+	"ApiError": true, "Client": true, "Any": true, "Dict": true, "List": true, "Optional": true,
+	"TypedDict": true, "HTTPStatus": true, "json": true, "ssl": true, "time": true, "urllib": true,
+}
+
+var pythonKeywords = map[string]bool{
+	"False": true, "None": true, "True": true, "and": true, "as": true, "assert": true, "async": true,
+	"await": true, "break": true, "class": true, "continue": true, "def": true, "del": true, "elif": true,
+	"else": true, "except": true, "finally": true, "for": true, "from": true, "global": true, "if": true,
+	"import": true, "in": true, "is": true, "lambda": true, "nonlocal": true, "not": true, "or": true,
+	"pass": true, "raise": true, "return": true, "try": true, "while": true, "with": true, "yield": true,
+	"self": true, "query": true, "body": true,
+}
+
+var pathParamPattern = regexp.MustCompile(`\{(\w+)\}`)
+
+// RenderPython renders the Python client module of the document
+func RenderPython(doc *Document) ([]byte, error) {
+	module := pythonModule{Version: doc.Info.Version}
+
+	names := make([]string, 0, len(doc.Components.Schemas))
+	for name := range doc.Components.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if pythonReserved[name] {
+			return nil, fmt.Errorf("schema %s clashes with a name of the Python client", name)
+		}
+		schema := doc.Components.Schemas[name]
+		keys := make([]string, 0, len(schema.Properties))
+		for key := range schema.Properties {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		pyType := pythonType{Name: name}
+		for _, key := range keys {
+			pyType.Fields = append(pyType.Fields, pythonField{Key: key, Type: pythonTypeOf(schema.Properties[key])})
+		}
+		module.Types = append(module.Types, pyType)
+	}
+
+	paths := make([]string, 0, len(doc.Paths))
+	for path := range doc.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		methods := make([]string, 0, len(doc.Paths[path]))
+		for method := range doc.Paths[path] {
+			methods = append(methods, method)
+		}
+		sort.Strings(methods)
+		for _, method := range methods {
+			module.Operations = append(module.Operations, pythonOperationOf(path, method, doc.Paths[path][method]))
+		}
+	}
+
+	tmpl, err := template.New("python").Funcs(template.FuncMap{"quote": strconv.Quote}).Parse(pythonTemplate)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, module); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// pythonOperationOf converts an operation to a Client method
+func pythonOperationOf(path, method string, op *Operation) pythonOperation {
+	pyOp := pythonOperation{Name: snakeCase(op.OperationID), Method: strings.ToUpper(method)}
+
+	var optional []string
+	for _, param := range op.Parameters {
+		name := pythonName(param.Name)
+		switch {
+		case param.In == "path":
+			pyOp.Args = append(pyOp.Args, fmt.Sprintf("%s: %q", name, pythonTypeOf(param.Schema)))
+		case param.Required:
+			pyOp.Args = append(pyOp.Args, fmt.Sprintf("%s: %q", name, pythonTypeOf(param.Schema)))
+			pyOp.Query = append(pyOp.Query, pythonParam{Key: param.Name, Name: name})
+		default:
+			optional = append(optional, fmt.Sprintf("%s: %q = None", name, "Optional["+pythonTypeOf(param.Schema)+"]"))
+			pyOp.Query = append(pyOp.Query, pythonParam{Key: param.Name, Name: name})
+		}
+	}
+	if op.RequestBody != nil {
+		bodyType := pythonTypeOf(op.RequestBody.Content["application/json"].Schema)
+		if op.RequestBody.Required {
+			pyOp.Args = append(pyOp.Args, fmt.Sprintf("request: %q", bodyType))
+		} else {
+			optional = append(optional, fmt.Sprintf("request: %q = None", "Optional["+bodyType+"]"))
+		}
+		pyOp.Body = "request"
+	}
+	if len(optional) > 0 {
+		pyOp.Args = append(append(pyOp.Args, "*"), optional...)
+	}
+
+	pyOp.Path = strconv.Quote(path)
+	if pathParamPattern.MatchString(path) {
+		pyOp.Path = "f" + strconv.Quote(pathParamPattern.ReplaceAllStringFunc(path, func(match string) string {
+			return "{_path(" + pythonName(match[1:len(match)-1]) + ")}"
+		}))
+	}
+
+	pyOp.Returns = pythonReturnType(op)
+	pyOp.Doc = pythonDocstring(op)
+	return pyOp
+}
+
+// pythonReturnType returns the Python type of the first success response
+func pythonReturnType(op *Operation) string {
+	codes := make([]string, 0, len(op.Responses))
+	for code := range op.Responses {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		if !strings.HasPrefix(code, "2") {
+			continue
+		}
+		content := op.Responses[code].Content
+		if media, ok := content["application/json"]; ok {
+			return pythonTypeOf(media.Schema)
+		}
+		if len(content) > 0 {
+			return "str"
+		}
+		return "None"
+	}
+	return "Any"
+}
+
+// pythonDocstring returns the docstring of a Client method: the summary, the
+// description and the endpoint
+func pythonDocstring(op *Operation) string {
+	var parts []string
+	if op.Summary != "" {
+		parts = append(parts, strings.TrimSuffix(op.Summary, ".")+".")
+	}
+	if op.Description != "" {
+		parts = append(parts, op.Description)
+	}
+	doc := strings.Join(parts, "\n\n")
+	doc = strings.ReplaceAll(doc, `\`, `\\`)
+	doc = strings.ReplaceAll(doc, `"""`, `\"\"\"`)
+
+	lines := strings.Split(doc, "\n")
+	if len(lines) == 1 {
+		return doc
+	}
+	for i := 1; i < len(lines); i++ {
+		if lines[i] != "" {
+			lines[i] = "        " + lines[i]
+		}
+	}
+	return strings.Join(lines, "\n") + "\n        "
+}
+
+// pythonTypeOf returns the Python type annotation of a schema
+func pythonTypeOf(schema *Schema) string {
+	if schema == nil {
+		return "Any"
+	}
+	if schema.Ref != "" {
+		name, err := componentName(schema)
+		if err != nil {
+			return "Any"
+		}
+		return name
+	}
+	switch schema.Type {
+	case "string":
+		return "str"
+	case "integer":
+		return "int"
+	case "number":
+		return "float"
+	case "boolean":
+		return "bool"
+	case "array":
+		return "List[" + pythonTypeOf(schema.Items) + "]"
+	case "object":
+		if schema.AdditionalProperties != nil {
+			return "Dict[str, " + pythonTypeOf(schema.AdditionalProperties) + "]"
+		}
+		return "Dict[str, Any]"
+	default:
+		return "Any"
+	}
+}
+
+// pythonName converts a parameter name to a Python identifier
+func pythonName(name string) string {
+	name = snakeCase(name)
+	if pythonKeywords[name] {
+		name += "_"
+	}
+	return name
+}
+
+// snakeCase converts camelCase and kebab-case names to snake_case
+func snakeCase(s string) string {
+	var b strings.Builder
+	runes := []rune(s)
+	for i, r := range runes {
+		switch {
+		case r == '-' || r == '.':
+			b.WriteRune('_')
+		case unicode.IsUpper(r):
+			if i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+				b.WriteRune('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,183 @@
+package openapi
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Schema is an OpenAPI 3.0 schema object
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	durationType      = reflect.TypeOf(time.Duration(0))
+	rawMessageType    = reflect.TypeOf(json.RawMessage(nil))
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// schemaBuilder derives schemas from Go types the way encoding/json encodes them.
+// Named struct types become components, referenced by their type name, prefixed with
+// their package name when two packages define the same name.
+type schemaBuilder struct {
+	components map[string]*Schema
+	names      map[reflect.Type]string
+}
+
+func newSchemaBuilder() *schemaBuilder {
+	return &schemaBuilder{components: make(map[string]*Schema), names: make(map[reflect.Type]string)}
+}
+
+// schema returns the schema of t
+func (b *schemaBuilder) schema(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t == durationType:
+		return &Schema{Type: "integer", Format: "int64", Description: "nanoseconds"}
+	case t == rawMessageType:
+		return &Schema{}
+	case t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType):
+		// Custom JSON encodings, such as Kubernetes times, are either strings or opaque
+		if t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType) || t.Kind() == reflect.String {
+			return &Schema{Type: "string"}
+		}
+		return &Schema{}
+	case t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType):
+		return &Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: b.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: b.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return b.object(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + b.component(t)}
+	default:
+		// Interfaces hold any JSON value
+		return &Schema{}
+	}
+}
+
+// component registers the named struct type t and returns its component name
+func (b *schemaBuilder) component(t reflect.Type) string {
+	if name, ok := b.names[t]; ok {
+		return name
+	}
+
+	name := t.Name()
+	if _, taken := b.components[name]; taken {
+		name = exportedName(packageName(t)) + name
+	}
+	b.names[t] = name
+	b.components[name] = &Schema{Type: "object"} // placeholder for recursive types
+	b.components[name] = b.object(t)
+	return name
+}
+
+// object returns the object schema of the struct type t
+func (b *schemaBuilder) object(t reflect.Type) *Schema {
+	properties := make(map[string]*Schema)
+	depths := make(map[string]int)
+	b.fields(t, 0, properties, depths)
+	return &Schema{Type: "object", Properties: properties}
+}
+
+// fields adds the JSON fields of the struct type t to properties, following the
+// encoding/json rules for embedded structs: the shallowest field of a name wins.
+func (b *schemaBuilder) fields(t reflect.Type, depth int, properties map[string]*Schema, depths map[string]int) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+			b.fields(fieldType, depth+1, properties, depths)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if existing, ok := depths[name]; ok && existing <= depth {
+			continue
+		}
+
+		schema := b.schema(field.Type)
+		if strings.Contains(opts, "string") && schema.Ref == "" && schema.Type != "" && schema.Type != "array" && schema.Type != "object" {
+			schema = &Schema{Type: "string"}
+		}
+		properties[name] = schema
+		depths[name] = depth
+	}
+}
+
+// packageName returns the last element of the package path of t
+func packageName(t reflect.Type) string {
+	path := t.PkgPath()
+	return path[strings.LastIndex(path, "/")+1:]
+}
+
+// exportedName returns s with its first letter uppercased
+func exportedName(s string) string {
+	if s == "" {
+		return s
+	}
+	r := []rune(s)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
+// componentName returns the name of the component a schema references
+func componentName(schema *Schema) (string, error) {
+	name, ok := strings.CutPrefix(schema.Ref, "#/components/schemas/")
+	if !ok {
+		return "", fmt.Errorf("unexpected schema reference %q", schema.Ref)
+	}
+	return name, nil
+}
@@ -0,0 +1,60 @@
+package openapi
+
+import (
+	"reflect"
+
+	v1 "github.com/tosin2013/openshift-coordination-engine/pkg/api/v1"
+	v2 "github.com/tosin2013/openshift-coordination-engine/pkg/api/v2"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/kserve"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// Types maps the type names used in handler annotations, qualified with their package
+// name, to the Go types they refer to. Annotations name types of their own package
+// without qualification. Add the request and response types of newly annotated
+// handlers here.
+var Types = map[string]reflect.Type{
+	"v1.APIServerAnomaliesResponse": reflect.TypeOf(v1.APIServerAnomaliesResponse{}),
+	"v1.AccuracyResponse":           reflect.TypeOf(v1.AccuracyResponse{}),
+	"v1.AnomalyAnalyzeRequest":      reflect.TypeOf(v1.AnomalyAnalyzeRequest{}),
+	"v1.AnomalyAnalyzeResponse":     reflect.TypeOf(v1.AnomalyAnalyzeResponse{}),
+	"v1.AnomalyErrorResponse":       reflect.TypeOf(v1.AnomalyErrorResponse{}),
+	"v1.AnomalyRecordsResponse":     reflect.TypeOf(v1.AnomalyRecordsResponse{}),
+	"v1.AnomalySubscription":        reflect.TypeOf(v1.AnomalySubscription{}),
+	"v1.AnomalySubscriptionRequest": reflect.TypeOf(v1.AnomalySubscriptionRequest{}),
+	"v1.ApplyRecommendationRequest": reflect.TypeOf(v1.ApplyRecommendationRequest{}),
+	"v1.BacktestRequest":            reflect.TypeOf(v1.BacktestRequest{}),
+	"v1.BacktestResponse":           reflect.TypeOf(v1.BacktestResponse{}),
+	"v1.BatchWorkloadsResponse":     reflect.TypeOf(v1.BatchWorkloadsResponse{}),
+	"v1.ClusterCapacityResponse":    reflect.TypeOf(v1.ClusterCapacityResponse{}),
+	"v1.ControlPlaneAnalyzeRequest": reflect.TypeOf(v1.ControlPlaneAnalyzeRequest{}),
+	"v1.CreateIncidentRequest":      reflect.TypeOf(v1.CreateIncidentRequest{}),
+	"v1.CreateIncidentResponse":     reflect.TypeOf(v1.CreateIncidentResponse{}),
+	"v1.DetectionResponse":          reflect.TypeOf(v1.DetectionResponse{}),
+	"v1.ErrorResponse":              reflect.TypeOf(v1.ErrorResponse{}),
+	"v1.FeatureHistoryResponse":     reflect.TypeOf(v1.FeatureHistoryResponse{}),
+	"v1.FeatureRescoreRequest":      reflect.TypeOf(v1.FeatureRescoreRequest{}),
+	"v1.FeatureRescoreResponse":     reflect.TypeOf(v1.FeatureRescoreResponse{}),
+	"v1.FeaturesResponse":           reflect.TypeOf(v1.FeaturesResponse{}),
+	"v1.GetRecommendationsRequest":  reflect.TypeOf(v1.GetRecommendationsRequest{}),
+	"v1.GetRecommendationsResponse": reflect.TypeOf(v1.GetRecommendationsResponse{}),
+	"v1.HealthScoreResponse":        reflect.TypeOf(v1.HealthScoreResponse{}),
+	"v1.IncidentTagStatsResponse":   reflect.TypeOf(v1.IncidentTagStatsResponse{}),
+	"v1.MetricsQueryResponse":       reflect.TypeOf(v1.MetricsQueryResponse{}),
+	"v1.ModelsListResponse":         reflect.TypeOf(v1.ModelsListResponse{}),
+	"v1.NamespaceCapacityResponse":  reflect.TypeOf(v1.NamespaceCapacityResponse{}),
+	"v1.NodeCapacityResponse":       reflect.TypeOf(v1.NodeCapacityResponse{}),
+	"v1.PredictErrorResponse":       reflect.TypeOf(v1.PredictErrorResponse{}),
+	"v1.PredictRequest":             reflect.TypeOf(v1.PredictRequest{}),
+	"v1.PredictResponse":            reflect.TypeOf(v1.PredictResponse{}),
+	"v1.RecommendationApplication":  reflect.TypeOf(v1.RecommendationApplication{}),
+	"v1.RightsizingResponse":        reflect.TypeOf(v1.RightsizingResponse{}),
+	"v1.SelfHealthResponse":         reflect.TypeOf(v1.SelfHealthResponse{}),
+	"v1.UpdateIncidentTagsRequest":  reflect.TypeOf(v1.UpdateIncidentTagsRequest{}),
+	"v2.AnalyzeResponse":            reflect.TypeOf(v2.AnalyzeResponse{}),
+	"kserve.DetectRequest":          reflect.TypeOf(kserve.DetectRequest{}),
+	"kserve.DetectResponse":         reflect.TypeOf(kserve.DetectResponse{}),
+	"kserve.ModelHealthResponse":    reflect.TypeOf(kserve.ModelHealthResponse{}),
+	"kserve.RolloutStatus":          reflect.TypeOf(kserve.RolloutStatus{}),
+	"models.Incident":               reflect.TypeOf(models.Incident{}),
+}
@@ -0,0 +1,505 @@
+package v1
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/apitoken"
+	"github.com/tosin2013/openshift-coordination-engine/internal/backup"
+	"github.com/tosin2013/openshift-coordination-engine/internal/clock"
+	"github.com/tosin2013/openshift-coordination-engine/internal/hardening"
+	"github.com/tosin2013/openshift-coordination-engine/internal/retention"
+	"github.com/tosin2013/openshift-coordination-engine/internal/runtimeconfig"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/api/validation"
+)
+
+// AdminHandler serves the admin API: backup, restore and purge of engine state,
+// hardening manifests for the engine itself, scoped API tokens and runtime
+// configuration objects. Every request must carry the admin token as a bearer token.
+type AdminHandler struct {
+	backups   *backup.Manager
+	baselines *backup.Manager
+	purger    *retention.Purger
+	hardening *hardening.Options
+	apiTokens *apitoken.Store
+	config    *runtimeconfig.Store
+	clock     *clock.Manual
+	token     string
+	log       *logrus.Logger
+}
+
+// NewAdminHandler creates a new admin handler
+func NewAdminHandler(backups *backup.Manager, token string, log *logrus.Logger) *AdminHandler {
+	return &AdminHandler{
+		backups: backups,
+		token:   token,
+		log:     log,
+	}
+}
+
+// SetBaselines enables exporting and importing learned baselines between clusters
+func (h *AdminHandler) SetBaselines(baselines *backup.Manager) {
+	h.baselines = baselines
+}
+
+// SetPurger enables the retention and namespace purge endpoints
+func (h *AdminHandler) SetPurger(purger *retention.Purger) {
+	h.purger = purger
+}
+
+// SetHardeningOptions enables the hardening manifests endpoint for a deployment with
+// the given integrations
+func (h *AdminHandler) SetHardeningOptions(opts hardening.Options) {
+	h.hardening = &opts
+}
+
+// SetTokenStore enables the API token endpoints
+func (h *AdminHandler) SetTokenStore(store *apitoken.Store) {
+	h.apiTokens = store
+}
+
+// PurgeRequest is the body of POST /api/v1/admin/purge
+type PurgeRequest struct {
+	Namespace string `json:"namespace"`
+}
+
+// PurgeResponse reports the items deleted per data type
+type PurgeResponse struct {
+	Status    string                  `json:"status"`
+	Namespace string                  `json:"namespace,omitempty"`
+	Results   []retention.PurgeResult `json:"results"`
+}
+
+// RetentionResponse lists the retention policy per data type
+type RetentionResponse struct {
+	Policies []retention.Policy `json:"policies"`
+}
+
+// BackupRequest is the optional body of POST /api/v1/admin/backup
+type BackupRequest struct {
+	// UploadURL is a pre-signed object storage URL the archive is PUT to; when empty
+	// the archive is returned as a download
+	UploadURL string `json:"upload_url,omitempty"`
+}
+
+// BackupResponse is returned when a backup is uploaded to object storage
+type BackupResponse struct {
+	Status   string          `json:"status"`
+	Manifest backup.Manifest `json:"manifest"`
+}
+
+// RestoreRequest is the JSON body of POST /api/v1/admin/restore when restoring from
+// object storage rather than uploading the archive
+type RestoreRequest struct {
+	SourceURL string `json:"source_url"`
+	Replace   bool   `json:"replace"`
+}
+
+// RestoreResponse reports a completed restore
+type RestoreResponse struct {
+	Status string `json:"status"`
+	*backup.RestoreResult
+}
+
+// CreateTokenRequest is the body of POST /api/v1/admin/tokens
+type CreateTokenRequest struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+
+	// ExpiresIn is how long the token is valid, e.g. "720h"; empty never expires
+	ExpiresIn string `json:"expires_in,omitempty"`
+}
+
+// CreateTokenResponse returns a new API token. The token is only shown here.
+type CreateTokenResponse struct {
+	Status   string          `json:"status"`
+	Token    string          `json:"token"`
+	APIToken *apitoken.Token `json:"api_token"`
+}
+
+// TokenResponse returns a single API token
+type TokenResponse struct {
+	Status   string          `json:"status"`
+	APIToken *apitoken.Token `json:"api_token"`
+}
+
+// ListTokensResponse lists the issued API tokens
+type ListTokensResponse struct {
+	Tokens []*apitoken.Token `json:"tokens"`
+	Total  int               `json:"total"`
+}
+
+// RegisterRoutes registers admin API routes
+func (h *AdminHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/api/v1/admin/backup", h.requireToken(h.Backup)).Methods("POST")
+	router.HandleFunc("/api/v1/admin/restore", h.requireToken(h.Restore)).Methods("POST")
+	router.HandleFunc("/api/v1/admin/baselines", h.requireToken(h.ExportBaselines)).Methods("GET")
+	router.HandleFunc("/api/v1/admin/baselines", h.requireToken(h.ImportBaselines)).Methods("POST")
+	router.HandleFunc("/api/v1/admin/retention", h.requireToken(h.GetRetention)).Methods("GET")
+	router.HandleFunc("/api/v1/admin/purge", h.requireToken(h.Purge)).Methods("POST")
+	router.HandleFunc("/api/v1/admin/hardening/manifests", h.requireToken(h.GetHardeningManifests)).Methods("GET")
+	router.HandleFunc("/api/v1/admin/tokens", h.requireToken(h.CreateToken)).Methods("POST")
+	router.HandleFunc("/api/v1/admin/tokens", h.requireToken(h.ListTokens)).Methods("GET")
+	router.HandleFunc("/api/v1/admin/tokens/{id}", h.requireToken(h.RevokeToken)).Methods("DELETE")
+	h.registerConfigRoutes(router)
+	h.registerClockRoutes(router)
+
+	h.log.Info("Admin API routes registered: /api/v1/admin/backup, /api/v1/admin/restore, /api/v1/admin/baselines, /api/v1/admin/retention, /api/v1/admin/purge, /api/v1/admin/hardening/manifests, /api/v1/admin/tokens, /api/v1/admin/config, /api/v1/admin/clock")
+}
+
+// requireToken rejects requests without the admin bearer token
+func (h *AdminHandler) requireToken(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || h.token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(h.token)) != 1 {
+			h.respondError(w, http.StatusUnauthorized, "admin token required")
+			return
+		}
+		next(w, r)
+	}
+}
+
+// Backup handles POST /api/v1/admin/backup
+// Without a body the archive (application/gzip) is returned as a download; with
+// {"upload_url": ...} it is uploaded to object storage instead.
+func (h *AdminHandler) Backup(w http.ResponseWriter, r *http.Request) {
+	var req BackupRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+			h.respondError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err), validation.DecodeFields(err)...)
+			return
+		}
+	}
+
+	if req.UploadURL != "" {
+		manifest, err := h.backups.Upload(r.Context(), req.UploadURL)
+		if errors.Is(err, backup.ErrInvalidURL) {
+			h.respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if err != nil {
+			h.log.WithError(err).Error("Failed to upload backup")
+			h.respondError(w, http.StatusBadGateway, err.Error())
+			return
+		}
+		h.respondJSON(w, http.StatusOK, BackupResponse{Status: "uploaded", Manifest: *manifest})
+		return
+	}
+
+	// Build the archive first so a failure can still be reported as JSON
+	var archive bytes.Buffer
+	manifest, err := h.backups.Write(&archive)
+	if err != nil {
+		h.log.WithError(err).Error("Failed to write backup")
+		h.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	filename := fmt.Sprintf("coordination-engine-backup-%s.tar.gz", manifest.CreatedAt.Format("20060102T150405Z"))
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	w.Header().Set("Content-Length", strconv.Itoa(archive.Len()))
+	w.WriteHeader(http.StatusOK)
+	if _, err := archive.WriteTo(w); err != nil {
+		h.log.WithError(err).Error("Failed to send backup")
+	}
+}
+
+// Restore handles POST /api/v1/admin/restore
+// The body is either the archive itself (?replace=true drops existing state first)
+// or, with Content-Type application/json, {"source_url": ..., "replace": ...}.
+func (h *AdminHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	var (
+		result *backup.RestoreResult
+		err    error
+	)
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "application/json" {
+		var req RestoreRequest
+		if decodeErr := json.NewDecoder(r.Body).Decode(&req); decodeErr != nil {
+			h.respondError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", decodeErr), validation.DecodeFields(decodeErr)...)
+			return
+		}
+		if req.SourceURL == "" {
+			h.respondError(w, http.StatusBadRequest, "source_url is required",
+				validation.FieldError{Field: "source_url", Constraint: validation.ConstraintRequired, Message: "source_url is required"})
+			return
+		}
+		result, err = h.backups.Download(r.Context(), req.SourceURL, req.Replace)
+	} else {
+		replace, parseErr := parseFlag("replace", r.URL.Query().Get("replace"))
+		if parseErr != nil {
+			h.respondError(w, http.StatusBadRequest, parseErr.Error(), validation.Fields(parseErr)...)
+			return
+		}
+		result, err = h.backups.Restore(r.Body, replace)
+	}
+
+	switch {
+	case errors.Is(err, backup.ErrInvalidArchive), errors.Is(err, backup.ErrInvalidURL):
+		h.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	case err != nil:
+		h.log.WithError(err).Error("Failed to restore backup")
+		h.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, RestoreResponse{Status: "restored", RestoreResult: result})
+}
+
+// ExportBaselines handles GET /api/v1/admin/baselines
+// The archive (application/gzip) holds the learned baselines and calibration data of
+// this cluster and its shape, for importing into a new cluster of similar shape.
+func (h *AdminHandler) ExportBaselines(w http.ResponseWriter, _ *http.Request) {
+	if h.baselines == nil {
+		h.respondError(w, http.StatusServiceUnavailable, "baseline export not configured")
+		return
+	}
+
+	var archive bytes.Buffer
+	manifest, err := h.baselines.Write(&archive)
+	if err != nil {
+		h.log.WithError(err).Error("Failed to export baselines")
+		h.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	filename := fmt.Sprintf("coordination-engine-baselines-%s.tar.gz", manifest.CreatedAt.Format("20060102T150405Z"))
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	w.Header().Set("Content-Length", strconv.Itoa(archive.Len()))
+	w.WriteHeader(http.StatusOK)
+	if _, err := archive.WriteTo(w); err != nil {
+		h.log.WithError(err).Error("Failed to send baselines")
+	}
+}
+
+// ImportBaselines handles POST /api/v1/admin/baselines
+// The body is an archive from GET /api/v1/admin/baselines; its baselines are merged
+// into this engine's. Archives from a cluster of a very different shape are rejected
+// with 409 unless ?force=true.
+func (h *AdminHandler) ImportBaselines(w http.ResponseWriter, r *http.Request) {
+	if h.baselines == nil {
+		h.respondError(w, http.StatusServiceUnavailable, "baseline import not configured")
+		return
+	}
+	force, err := parseFlag("force", r.URL.Query().Get("force"))
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error(), validation.Fields(err)...)
+		return
+	}
+
+	result, err := h.baselines.Import(r.Body, force)
+	switch {
+	case errors.Is(err, backup.ErrInvalidArchive):
+		h.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	case errors.Is(err, backup.ErrShapeMismatch):
+		h.respondError(w, http.StatusConflict, err.Error())
+		return
+	case err != nil:
+		h.log.WithError(err).Error("Failed to import baselines")
+		h.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, RestoreResponse{Status: "imported", RestoreResult: result})
+}
+
+// GetRetention handles GET /api/v1/admin/retention
+func (h *AdminHandler) GetRetention(w http.ResponseWriter, _ *http.Request) {
+	if h.purger == nil {
+		h.respondError(w, http.StatusServiceUnavailable, "data retention not configured")
+		return
+	}
+	h.respondJSON(w, http.StatusOK, RetentionResponse{Policies: h.purger.Policies()})
+}
+
+// Purge handles POST /api/v1/admin/purge
+// With {"namespace": ...} every stored item of the namespace is deleted, e.g. when a
+// tenant is offboarded; with an empty body expired data is purged immediately.
+func (h *AdminHandler) Purge(w http.ResponseWriter, r *http.Request) {
+	if h.purger == nil {
+		h.respondError(w, http.StatusServiceUnavailable, "data retention not configured")
+		return
+	}
+
+	var req PurgeRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+			h.respondError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err), validation.DecodeFields(err)...)
+			return
+		}
+	}
+
+	var (
+		results []retention.PurgeResult
+		err     error
+	)
+	if req.Namespace != "" {
+		results, err = h.purger.PurgeNamespace(req.Namespace)
+	} else {
+		results, err = h.purger.PurgeExpired()
+	}
+	if err != nil {
+		h.log.WithError(err).Error("Failed to purge data")
+		h.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, PurgeResponse{Status: "purged", Namespace: req.Namespace, Results: results})
+}
+
+// GetHardeningManifests handles GET /api/v1/admin/hardening/manifests
+// Returns a NetworkPolicy and minimal ClusterRoles with bindings for the engine as YAML,
+// based on the enabled integrations. The optional name query parameter sets the
+// ServiceAccount and object name prefix (default coordination-engine).
+func (h *AdminHandler) GetHardeningManifests(w http.ResponseWriter, r *http.Request) {
+	if h.hardening == nil {
+		h.respondError(w, http.StatusServiceUnavailable, "hardening manifests not configured")
+		return
+	}
+
+	opts := *h.hardening
+	if name := r.URL.Query().Get("name"); name != "" {
+		if errs := k8svalidation.IsDNS1123Label(name); len(errs) > 0 {
+			message := fmt.Sprintf("invalid name %q: %s", name, strings.Join(errs, ", "))
+			h.respondError(w, http.StatusBadRequest, message,
+				validation.FieldError{Field: "name", Constraint: validation.ConstraintFormat, Value: name, Message: message})
+			return
+		}
+		opts.Name = name
+	}
+	data, err := hardening.GenerateYAML(opts)
+	if err != nil {
+		h.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/yaml")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(data); err != nil {
+		h.log.WithError(err).Error("Failed to write hardening manifests response")
+	}
+}
+
+// CreateToken handles POST /api/v1/admin/tokens
+func (h *AdminHandler) CreateToken(w http.ResponseWriter, r *http.Request) {
+	if h.apiTokens == nil {
+		h.respondError(w, http.StatusServiceUnavailable, "API tokens not configured (set API_TOKENS_ENABLED)")
+		return
+	}
+
+	var req CreateTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err), validation.DecodeFields(err)...)
+		return
+	}
+	var ttl time.Duration
+	if req.ExpiresIn != "" {
+		parsed, err := time.ParseDuration(req.ExpiresIn)
+		if err != nil {
+			message := fmt.Sprintf("invalid expires_in: %q", req.ExpiresIn)
+			h.respondError(w, http.StatusBadRequest, message,
+				validation.FieldError{Field: "expires_in", Constraint: validation.ConstraintFormat, Value: req.ExpiresIn, Message: message})
+			return
+		}
+		ttl = parsed
+	}
+
+	secret, token, err := h.apiTokens.Create(req.Name, req.Scopes, ttl)
+	if fields := validation.Fields(err); len(fields) > 0 {
+		h.respondError(w, http.StatusBadRequest, err.Error(), fields...)
+		return
+	}
+	if err != nil {
+		h.log.WithError(err).Error("Failed to create API token")
+		h.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.log.WithFields(logrus.Fields{
+		"token_id": token.ID,
+		"name":     token.Name,
+		"scopes":   token.Scopes,
+	}).Info("API token created")
+	h.respondJSON(w, http.StatusCreated, CreateTokenResponse{Status: "created", Token: secret, APIToken: token})
+}
+
+// ListTokens handles GET /api/v1/admin/tokens
+func (h *AdminHandler) ListTokens(w http.ResponseWriter, _ *http.Request) {
+	if h.apiTokens == nil {
+		h.respondError(w, http.StatusServiceUnavailable, "API tokens not configured (set API_TOKENS_ENABLED)")
+		return
+	}
+	tokens := h.apiTokens.List()
+	h.respondJSON(w, http.StatusOK, ListTokensResponse{Tokens: tokens, Total: len(tokens)})
+}
+
+// RevokeToken handles DELETE /api/v1/admin/tokens/{id}
+func (h *AdminHandler) RevokeToken(w http.ResponseWriter, r *http.Request) {
+	if h.apiTokens == nil {
+		h.respondError(w, http.StatusServiceUnavailable, "API tokens not configured (set API_TOKENS_ENABLED)")
+		return
+	}
+
+	token, err := h.apiTokens.Revoke(mux.Vars(r)["id"])
+	if errors.Is(err, apitoken.ErrNotFound) {
+		h.respondError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		h.log.WithError(err).Error("Failed to revoke API token")
+		h.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.log.WithFields(logrus.Fields{"token_id": token.ID, "name": token.Name}).Info("API token revoked")
+	h.respondJSON(w, http.StatusOK, TokenResponse{Status: "revoked", APIToken: token})
+}
+
+// parseFlag parses a boolean query parameter such as replace (default false)
+func parseFlag(name, value string) (bool, error) {
+	if value == "" {
+		return false, nil
+	}
+	flag, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, validation.New(name, validation.ConstraintFormat, value, fmt.Sprintf("invalid %s: %q", name, value))
+	}
+	return flag, nil
+}
+
+func (h *AdminHandler) respondJSON(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		h.log.WithError(err).Error("Failed to encode JSON response")
+	}
+}
+
+func (h *AdminHandler) respondError(w http.ResponseWriter, statusCode int, message string, fieldErrs ...validation.FieldError) {
+	response := map[string]interface{}{
+		"status": "error",
+		"error":  message,
+	}
+	if len(fieldErrs) > 0 {
+		response["errors"] = fieldErrs
+	}
+	h.respondJSON(w, statusCode, response)
+}
@@ -0,0 +1,98 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/clock"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/api/validation"
+)
+
+// UpdateClockRequest is the body of POST /api/v1/admin/clock. Exactly one of Advance
+// and Time is set.
+type UpdateClockRequest struct {
+	// Advance moves the clock by a duration, e.g. "5m" to expire cached values
+	Advance string `json:"advance,omitempty"`
+
+	// Time moves the clock to an RFC 3339 instant
+	Time string `json:"time,omitempty"`
+}
+
+// ClockResponse reports the time of the engine's clock
+type ClockResponse struct {
+	Now time.Time `json:"now"`
+}
+
+// SetClock enables the clock endpoints of deterministic mode
+func (h *AdminHandler) SetClock(manual *clock.Manual) {
+	h.clock = manual
+}
+
+// registerClockRoutes registers the deterministic mode clock routes
+func (h *AdminHandler) registerClockRoutes(router *mux.Router) {
+	router.HandleFunc("/api/v1/admin/clock", h.requireToken(h.GetClock)).Methods("GET")
+	router.HandleFunc("/api/v1/admin/clock", h.requireToken(h.UpdateClock)).Methods("POST")
+}
+
+// GetClock handles GET /api/v1/admin/clock
+func (h *AdminHandler) GetClock(w http.ResponseWriter, _ *http.Request) {
+	if h.clock == nil {
+		h.respondClockUnavailable(w)
+		return
+	}
+	h.respondJSON(w, http.StatusOK, ClockResponse{Now: h.clock.Now()})
+}
+
+// UpdateClock handles POST /api/v1/admin/clock
+// Integration tests advance the clock of deterministic mode to expire cached values
+// or move target times; it does not move on its own.
+func (h *AdminHandler) UpdateClock(w http.ResponseWriter, r *http.Request) {
+	if h.clock == nil {
+		h.respondClockUnavailable(w)
+		return
+	}
+
+	var req UpdateClockRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err), validation.DecodeFields(err)...)
+		return
+	}
+
+	var fieldErrs validation.Errors
+	switch {
+	case req.Advance != "" && req.Time != "":
+		fieldErrs.Add("time", validation.ConstraintExclusive, req.Time, "advance and time are mutually exclusive")
+	case req.Advance != "":
+		advance, err := time.ParseDuration(req.Advance)
+		if err != nil {
+			fieldErrs.Add("advance", validation.ConstraintFormat, req.Advance, "advance must be a duration such as 5m")
+			break
+		}
+		h.clock.Advance(advance)
+	case req.Time != "":
+		t, err := time.Parse(time.RFC3339, req.Time)
+		if err != nil {
+			fieldErrs.Add("time", validation.ConstraintFormat, req.Time, "time must be an RFC 3339 time")
+			break
+		}
+		h.clock.Set(t.UTC())
+	default:
+		fieldErrs.Add("advance", validation.ConstraintRequired, nil, "advance or time is required")
+	}
+	if len(fieldErrs) > 0 {
+		h.respondError(w, http.StatusBadRequest, fieldErrs.Error(), fieldErrs...)
+		return
+	}
+
+	now := h.clock.Now()
+	h.log.WithField("now", now).Info("Deterministic clock moved")
+	h.respondJSON(w, http.StatusOK, ClockResponse{Now: now})
+}
+
+func (h *AdminHandler) respondClockUnavailable(w http.ResponseWriter) {
+	h.respondError(w, http.StatusConflict, "the engine runs on the wall clock (set DETERMINISTIC_TIME)")
+}
@@ -0,0 +1,207 @@
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/runtimeconfig"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/api/validation"
+)
+
+// ConfigKindSummary describes a kind of runtime configuration object
+type ConfigKindSummary struct {
+	Kind  runtimeconfig.Kind `json:"kind"`
+	Count int                `json:"count"`
+}
+
+// ConfigKindsResponse lists the kinds of runtime configuration objects
+type ConfigKindsResponse struct {
+	Kinds []ConfigKindSummary `json:"kinds"`
+}
+
+// CreateConfigObjectRequest is the body of POST /api/v1/admin/config/{kind}
+type CreateConfigObjectRequest struct {
+	Name string          `json:"name"`
+	Spec json.RawMessage `json:"spec"`
+}
+
+// UpdateConfigObjectRequest is the body of PUT /api/v1/admin/config/{kind}/{name}
+type UpdateConfigObjectRequest struct {
+	// ResourceVersion is the version the update is based on; the update fails with
+	// 409 if the object changed since
+	ResourceVersion string          `json:"resource_version"`
+	Spec            json.RawMessage `json:"spec"`
+}
+
+// ConfigObjectResponse returns a single runtime configuration object
+type ConfigObjectResponse struct {
+	Status string                `json:"status"`
+	Object *runtimeconfig.Object `json:"object"`
+}
+
+// ConfigObjectsResponse lists the runtime configuration objects of a kind
+type ConfigObjectsResponse struct {
+	Kind    runtimeconfig.Kind      `json:"kind"`
+	Objects []*runtimeconfig.Object `json:"objects"`
+	Total   int                     `json:"total"`
+}
+
+// SetConfigStore enables the runtime configuration endpoints
+func (h *AdminHandler) SetConfigStore(store *runtimeconfig.Store) {
+	h.config = store
+}
+
+// registerConfigRoutes registers the runtime configuration routes
+func (h *AdminHandler) registerConfigRoutes(router *mux.Router) {
+	router.HandleFunc("/api/v1/admin/config", h.requireToken(h.ListConfigKinds)).Methods("GET")
+	router.HandleFunc("/api/v1/admin/config/{kind}", h.requireToken(h.CreateConfigObject)).Methods("POST")
+	router.HandleFunc("/api/v1/admin/config/{kind}", h.requireToken(h.ListConfigObjects)).Methods("GET")
+	router.HandleFunc("/api/v1/admin/config/{kind}/{name}", h.requireToken(h.GetConfigObject)).Methods("GET")
+	router.HandleFunc("/api/v1/admin/config/{kind}/{name}", h.requireToken(h.UpdateConfigObject)).Methods("PUT")
+	router.HandleFunc("/api/v1/admin/config/{kind}/{name}", h.requireToken(h.DeleteConfigObject)).Methods("DELETE")
+}
+
+// ListConfigKinds handles GET /api/v1/admin/config
+func (h *AdminHandler) ListConfigKinds(w http.ResponseWriter, _ *http.Request) {
+	if h.config == nil {
+		h.respondConfigUnavailable(w)
+		return
+	}
+	counts := h.config.Counts()
+	response := ConfigKindsResponse{Kinds: make([]ConfigKindSummary, 0, len(counts))}
+	for _, kind := range runtimeconfig.Kinds() {
+		response.Kinds = append(response.Kinds, ConfigKindSummary{Kind: kind, Count: counts[kind]})
+	}
+	h.respondJSON(w, http.StatusOK, response)
+}
+
+// CreateConfigObject handles POST /api/v1/admin/config/{kind}
+func (h *AdminHandler) CreateConfigObject(w http.ResponseWriter, r *http.Request) {
+	if h.config == nil {
+		h.respondConfigUnavailable(w)
+		return
+	}
+
+	var req CreateConfigObjectRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err), validation.DecodeFields(err)...)
+		return
+	}
+
+	obj, err := h.config.Create(runtimeconfig.Kind(mux.Vars(r)["kind"]), req.Name, req.Spec)
+	if err != nil {
+		h.respondConfigError(w, err)
+		return
+	}
+	h.logConfigChange(obj, "created")
+	h.respondJSON(w, http.StatusCreated, ConfigObjectResponse{Status: "created", Object: obj})
+}
+
+// ListConfigObjects handles GET /api/v1/admin/config/{kind}
+func (h *AdminHandler) ListConfigObjects(w http.ResponseWriter, r *http.Request) {
+	if h.config == nil {
+		h.respondConfigUnavailable(w)
+		return
+	}
+
+	kind := runtimeconfig.Kind(mux.Vars(r)["kind"])
+	objects, err := h.config.List(kind)
+	if err != nil {
+		h.respondConfigError(w, err)
+		return
+	}
+	h.respondJSON(w, http.StatusOK, ConfigObjectsResponse{Kind: kind, Objects: objects, Total: len(objects)})
+}
+
+// GetConfigObject handles GET /api/v1/admin/config/{kind}/{name}
+func (h *AdminHandler) GetConfigObject(w http.ResponseWriter, r *http.Request) {
+	if h.config == nil {
+		h.respondConfigUnavailable(w)
+		return
+	}
+
+	vars := mux.Vars(r)
+	obj, err := h.config.Get(runtimeconfig.Kind(vars["kind"]), vars["name"])
+	if err != nil {
+		h.respondConfigError(w, err)
+		return
+	}
+	h.respondJSON(w, http.StatusOK, ConfigObjectResponse{Status: "ok", Object: obj})
+}
+
+// UpdateConfigObject handles PUT /api/v1/admin/config/{kind}/{name}
+func (h *AdminHandler) UpdateConfigObject(w http.ResponseWriter, r *http.Request) {
+	if h.config == nil {
+		h.respondConfigUnavailable(w)
+		return
+	}
+
+	var req UpdateConfigObjectRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err), validation.DecodeFields(err)...)
+		return
+	}
+
+	vars := mux.Vars(r)
+	obj, err := h.config.Update(runtimeconfig.Kind(vars["kind"]), vars["name"], req.ResourceVersion, req.Spec)
+	if err != nil {
+		h.respondConfigError(w, err)
+		return
+	}
+	h.logConfigChange(obj, "updated")
+	h.respondJSON(w, http.StatusOK, ConfigObjectResponse{Status: "updated", Object: obj})
+}
+
+// DeleteConfigObject handles DELETE /api/v1/admin/config/{kind}/{name}. The optional
+// resource_version query parameter makes the deletion conditional.
+func (h *AdminHandler) DeleteConfigObject(w http.ResponseWriter, r *http.Request) {
+	if h.config == nil {
+		h.respondConfigUnavailable(w)
+		return
+	}
+
+	vars := mux.Vars(r)
+	kind, name := runtimeconfig.Kind(vars["kind"]), vars["name"]
+	if err := h.config.Delete(kind, name, r.URL.Query().Get("resource_version")); err != nil {
+		h.respondConfigError(w, err)
+		return
+	}
+	h.log.WithFields(logrus.Fields{"kind": kind, "name": name}).Info("Runtime configuration object deleted")
+	h.respondJSON(w, http.StatusOK, map[string]string{"status": "deleted", "kind": string(kind), "name": name})
+}
+
+// logConfigChange logs a created or updated object
+func (h *AdminHandler) logConfigChange(obj *runtimeconfig.Object, change string) {
+	h.log.WithFields(logrus.Fields{
+		"kind":             obj.Kind,
+		"name":             obj.Name,
+		"resource_version": obj.ResourceVersion,
+	}).Info("Runtime configuration object " + change)
+}
+
+// respondConfigError maps runtime configuration store errors to HTTP statuses
+func (h *AdminHandler) respondConfigError(w http.ResponseWriter, err error) {
+	if fields := validation.Fields(err); len(fields) > 0 {
+		h.respondError(w, http.StatusBadRequest, err.Error(), fields...)
+		return
+	}
+	switch {
+	case errors.Is(err, runtimeconfig.ErrUnknownKind), errors.Is(err, runtimeconfig.ErrNotFound):
+		h.respondError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, runtimeconfig.ErrAlreadyExists), errors.Is(err, runtimeconfig.ErrConflict),
+		errors.Is(err, runtimeconfig.ErrLimitReached):
+		h.respondError(w, http.StatusConflict, err.Error())
+	default:
+		h.log.WithError(err).Error("Failed to update runtime configuration")
+		h.respondError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+func (h *AdminHandler) respondConfigUnavailable(w http.ResponseWriter) {
+	h.respondError(w, http.StatusServiceUnavailable, "runtime configuration not configured (set RUNTIME_CONFIG_ENABLED)")
+}
@@ -0,0 +1,400 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/apitoken"
+	"github.com/tosin2013/openshift-coordination-engine/internal/backup"
+	"github.com/tosin2013/openshift-coordination-engine/internal/clock"
+	"github.com/tosin2013/openshift-coordination-engine/internal/hardening"
+	"github.com/tosin2013/openshift-coordination-engine/internal/retention"
+	"github.com/tosin2013/openshift-coordination-engine/internal/runtimeconfig"
+	"github.com/tosin2013/openshift-coordination-engine/internal/storage"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+func newTestAdminRouter(t *testing.T) (*mux.Router, *storage.IncidentStore) {
+	t.Helper()
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	store := storage.NewIncidentStoreWithPath(t.TempDir())
+	backups := backup.NewManager("test", time.Second, log)
+	backups.Register(backup.NewIncidentSection(store))
+
+	router := mux.NewRouter()
+	NewAdminHandler(backups, "s3cret", log).RegisterRoutes(router)
+	return router, store
+}
+
+func TestAdminHandler_BackupAndRestore(t *testing.T) {
+	router, store := newTestAdminRouter(t)
+	_, err := store.Create(&models.Incident{
+		Title:       "API pods restarting",
+		Description: "Pods restarting",
+		Severity:    models.IncidentSeverityHigh,
+		Target:      "apps",
+	})
+	require.NoError(t, err)
+
+	t.Run("requires admin token", func(t *testing.T) {
+		for _, auth := range []string{"", "Bearer wrong", "s3cret"} {
+			req := httptest.NewRequest("POST", "/api/v1/admin/backup", http.NoBody)
+			if auth != "" {
+				req.Header.Set("Authorization", auth)
+			}
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+			assert.Equal(t, http.StatusUnauthorized, rr.Code, auth)
+		}
+	})
+
+	req := httptest.NewRequest("POST", "/api/v1/admin/backup", http.NoBody)
+	req.Header.Set("Authorization", "Bearer s3cret")
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/gzip", rr.Header().Get("Content-Type"))
+	assert.Contains(t, rr.Header().Get("Content-Disposition"), "coordination-engine-backup-")
+	archive := rr.Body.Bytes()
+
+	// Restore into another engine, replacing its incidents
+	target, targetStore := newTestAdminRouter(t)
+	req = httptest.NewRequest("POST", "/api/v1/admin/restore?replace=true", bytes.NewReader(archive))
+	req.Header.Set("Authorization", "Bearer s3cret")
+	req.Header.Set("Content-Type", "application/gzip")
+	rr = httptest.NewRecorder()
+	target.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	var resp RestoreResponse
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+	assert.Equal(t, "restored", resp.Status)
+	assert.True(t, resp.Replace)
+	assert.Equal(t, []backup.SectionInfo{{Name: backup.SectionIncidents, Items: 1}}, resp.Restored)
+	assert.Equal(t, 1, targetStore.Count())
+
+	t.Run("rejects invalid archives and urls", func(t *testing.T) {
+		for _, tc := range []struct {
+			contentType string
+			body        string
+		}{
+			{"application/gzip", "not an archive"},
+			{"application/json", `{"source_url": "file:///etc/passwd"}`},
+			{"application/json", `{}`},
+		} {
+			req := httptest.NewRequest("POST", "/api/v1/admin/restore", strings.NewReader(tc.body))
+			req.Header.Set("Authorization", "Bearer s3cret")
+			req.Header.Set("Content-Type", tc.contentType)
+			rr := httptest.NewRecorder()
+			target.ServeHTTP(rr, req)
+			assert.Equal(t, http.StatusBadRequest, rr.Code, tc.body)
+		}
+		assert.Equal(t, 1, targetStore.Count())
+	})
+}
+
+func TestAdminHandler_Baselines(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	newRouter := func(nodes int) (*mux.Router, *storage.IncidentStore) {
+		store := storage.NewIncidentStoreWithPath(t.TempDir())
+		baselines := backup.NewManager("test", time.Second, log)
+		baselines.Register(backup.NewIncidentSection(store))
+		baselines.SetClusterShape(func(context.Context) (*backup.ClusterShape, error) {
+			return &backup.ClusterShape{Nodes: nodes}, nil
+		})
+		handler := NewAdminHandler(backup.NewManager("test", time.Second, log), "s3cret", log)
+		handler.SetBaselines(baselines)
+		router := mux.NewRouter()
+		handler.RegisterRoutes(router)
+		return router, store
+	}
+	do := func(router *mux.Router, method, url string, body []byte) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, url, bytes.NewReader(body))
+		req.Header.Set("Authorization", "Bearer s3cret")
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	t.Run("not configured", func(t *testing.T) {
+		router, _ := newTestAdminRouter(t)
+		assert.Equal(t, http.StatusServiceUnavailable, do(router, "GET", "/api/v1/admin/baselines", nil).Code)
+		assert.Equal(t, http.StatusServiceUnavailable, do(router, "POST", "/api/v1/admin/baselines", nil).Code)
+	})
+
+	source, store := newRouter(6)
+	_, err := store.Create(&models.Incident{Title: "Incident", Description: "Issue", Severity: models.IncidentSeverityLow, Target: "apps"})
+	require.NoError(t, err)
+	rr := do(source, "GET", "/api/v1/admin/baselines", nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Header().Get("Content-Disposition"), "coordination-engine-baselines-")
+	archive := rr.Body.Bytes()
+
+	// A much larger cluster rejects the archive unless forced
+	target, targetStore := newRouter(30)
+	rr = do(target, "POST", "/api/v1/admin/baselines", archive)
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	assert.Contains(t, rr.Body.String(), "6 nodes")
+	assert.Equal(t, 0, targetStore.Count())
+
+	assert.Equal(t, http.StatusBadRequest, do(target, "POST", "/api/v1/admin/baselines?force=maybe", archive).Code)
+
+	rr = do(target, "POST", "/api/v1/admin/baselines?force=true", archive)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var resp RestoreResponse
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+	assert.Equal(t, "imported", resp.Status)
+	assert.False(t, resp.Replace)
+	assert.NotEmpty(t, resp.ShapeMismatch)
+	assert.Equal(t, 1, targetStore.Count())
+}
+
+func TestAdminHandler_Purge(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	store := storage.NewIncidentStoreWithPath(t.TempDir())
+	for _, target := range []string{"tenant-a", "tenant-a", "tenant-b"} {
+		_, err := store.Create(&models.Incident{
+			Title:       "Pods restarting",
+			Description: "Pods restarting",
+			Severity:    models.IncidentSeverityLow,
+			Target:      target,
+		})
+		require.NoError(t, err)
+	}
+
+	router := mux.NewRouter()
+	handler := NewAdminHandler(backup.NewManager("test", time.Second, log), "s3cret", log)
+	handler.RegisterRoutes(router)
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer s3cret")
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	assert.Equal(t, http.StatusServiceUnavailable, send("POST", "/api/v1/admin/purge", `{"namespace": "tenant-a"}`).Code)
+
+	purger := retention.NewPurger(log)
+	purger.Add(retention.IncidentRule(store, 90*24*time.Hour))
+	handler.SetPurger(purger)
+
+	rr := send("GET", "/api/v1/admin/retention", "")
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"retention":"2160h0m0s"`)
+
+	rr = send("POST", "/api/v1/admin/purge", `{"namespace": "tenant-a"}`)
+	require.Equal(t, http.StatusOK, rr.Code)
+	var resp PurgeResponse
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+	assert.Equal(t, "tenant-a", resp.Namespace)
+	assert.Equal(t, []retention.PurgeResult{{DataType: retention.DataTypeIncidents, Purged: 2}}, resp.Results)
+	assert.Equal(t, 1, store.Count())
+
+	// Without a namespace only expired data is purged
+	rr = send("POST", "/api/v1/admin/purge", "")
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, 1, store.Count())
+}
+
+func TestAdminHandler_HardeningManifests(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	router := mux.NewRouter()
+	handler := NewAdminHandler(backup.NewManager("test", time.Second, log), "s3cret", log)
+	handler.RegisterRoutes(router)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, http.NoBody)
+		req.Header.Set("Authorization", "Bearer s3cret")
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	assert.Equal(t, http.StatusServiceUnavailable, get("/api/v1/admin/hardening/manifests").Code)
+
+	handler.SetHardeningOptions(hardening.Options{
+		Namespace:    "self-healing-platform",
+		APIPorts:     []int{8080},
+		UpgradeAware: true,
+		Endpoints:    []hardening.Endpoint{{Name: "prometheus", Namespace: "openshift-monitoring", Port: 9091}},
+	})
+
+	rr := get("/api/v1/admin/hardening/manifests?name=engine")
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	assert.Equal(t, "application/yaml", rr.Header().Get("Content-Type"))
+	body := rr.Body.String()
+	assert.Contains(t, body, "kind: NetworkPolicy")
+	assert.Contains(t, body, "name: engine-cluster-reader")
+	assert.Contains(t, body, "clusterversions")
+	assert.Contains(t, body, "port: 9091")
+
+	assert.Equal(t, http.StatusBadRequest, get("/api/v1/admin/hardening/manifests?name=Not_Valid").Code)
+}
+
+func TestAdminHandler_Tokens(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	router := mux.NewRouter()
+	handler := NewAdminHandler(backup.NewManager("test", time.Second, log), "s3cret", log)
+	handler.RegisterRoutes(router)
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer s3cret")
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	assert.Equal(t, http.StatusServiceUnavailable, send("GET", "/api/v1/admin/tokens", "").Code)
+
+	handler.SetTokenStore(apitoken.NewStore(t.TempDir()))
+
+	rr := send("POST", "/api/v1/admin/tokens", `{"name":"console-plugin","scopes":["read:anomalies","read:incidents"],"expires_in":"720h"}`)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	var created CreateTokenResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &created))
+	assert.True(t, apitoken.IsToken(created.Token))
+	require.NotNil(t, created.APIToken)
+	require.NotNil(t, created.APIToken.ExpiresAt)
+	assert.NotContains(t, rr.Body.String(), `"hash"`)
+
+	rr = send("POST", "/api/v1/admin/tokens", `{"name":"bot","scopes":["delete:everything"]}`)
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"field":"scopes"`)
+	assert.Equal(t, http.StatusBadRequest, send("POST", "/api/v1/admin/tokens", `{"name":"bot","scopes":["read:incidents"],"expires_in":"soon"}`).Code)
+
+	rr = send("GET", "/api/v1/admin/tokens", "")
+	require.Equal(t, http.StatusOK, rr.Code)
+	var list ListTokensResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &list))
+	require.Equal(t, 1, list.Total)
+	assert.Equal(t, "console-plugin", list.Tokens[0].Name)
+	assert.NotContains(t, rr.Body.String(), created.Token)
+
+	rr = send("DELETE", "/api/v1/admin/tokens/"+created.APIToken.ID, "")
+	require.Equal(t, http.StatusOK, rr.Code)
+	var revoked TokenResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &revoked))
+	assert.NotNil(t, revoked.APIToken.RevokedAt)
+	assert.Equal(t, http.StatusNotFound, send("DELETE", "/api/v1/admin/tokens/tok-missing", "").Code)
+}
+
+func TestAdminHandler_Config(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	router := mux.NewRouter()
+	handler := NewAdminHandler(backup.NewManager("test", time.Second, log), "s3cret", log)
+	handler.RegisterRoutes(router)
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer s3cret")
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	assert.Equal(t, http.StatusServiceUnavailable, send("GET", "/api/v1/admin/config", "").Code)
+
+	handler.SetConfigStore(runtimeconfig.NewStore(t.TempDir()))
+
+	rr := send("POST", "/api/v1/admin/config/silences",
+		`{"name":"db-maintenance","spec":{"match":{"namespaces":["db"]},"ends_at":"2099-01-01T00:00:00Z","comment":"CHG-1"}}`)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	var created ConfigObjectResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &created))
+	assert.Equal(t, runtimeconfig.KindSilence, created.Object.Kind)
+	assert.Equal(t, "1", created.Object.ResourceVersion)
+
+	assert.Equal(t, http.StatusConflict, send("POST", "/api/v1/admin/config/silences",
+		`{"name":"db-maintenance","spec":{"match":{"namespaces":["db"]},"ends_at":"2099-01-01T00:00:00Z","comment":"CHG-1"}}`).Code)
+	assert.Equal(t, http.StatusNotFound, send("GET", "/api/v1/admin/config/widgets", "").Code)
+	assert.Equal(t, http.StatusNotFound, send("GET", "/api/v1/admin/config/silences/missing", "").Code)
+
+	rr = send("POST", "/api/v1/admin/config/playbooks", `{"name":"restart","spec":{"steps":[]}}`)
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"field":"spec.steps"`)
+
+	update := `{"resource_version":"1","spec":{"match":{"namespaces":["db"]},"ends_at":"2099-02-01T00:00:00Z","comment":"CHG-1 extended"}}`
+	rr = send("PUT", "/api/v1/admin/config/silences/db-maintenance", update)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	assert.Contains(t, rr.Body.String(), `"resource_version":"2"`)
+
+	// A second editor working from the old version is rejected
+	rr = send("PUT", "/api/v1/admin/config/silences/db-maintenance", update)
+	require.Equal(t, http.StatusConflict, rr.Code)
+	assert.Contains(t, rr.Body.String(), "resource_version 2")
+
+	rr = send("GET", "/api/v1/admin/config/silences", "")
+	require.Equal(t, http.StatusOK, rr.Code)
+	var list ConfigObjectsResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &list))
+	require.Equal(t, 1, list.Total)
+	assert.Contains(t, string(list.Objects[0].Spec), "CHG-1 extended")
+
+	rr = send("GET", "/api/v1/admin/config", "")
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `{"kind":"silences","count":1}`)
+
+	assert.Equal(t, http.StatusConflict, send("DELETE", "/api/v1/admin/config/silences/db-maintenance?resource_version=1", "").Code)
+	assert.Equal(t, http.StatusOK, send("DELETE", "/api/v1/admin/config/silences/db-maintenance?resource_version=2", "").Code)
+	assert.Equal(t, http.StatusNotFound, send("DELETE", "/api/v1/admin/config/silences/db-maintenance", "").Code)
+}
+
+func TestAdminHandler_Clock(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	router := mux.NewRouter()
+	handler := NewAdminHandler(backup.NewManager("test", time.Second, log), "s3cret", log)
+	handler.RegisterRoutes(router)
+
+	send := func(method, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/api/v1/admin/clock", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer s3cret")
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	// The wall clock cannot be moved
+	assert.Equal(t, http.StatusConflict, send("GET", "").Code)
+
+	handler.SetClock(clock.NewManual(time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC)))
+	rr := send("GET", "")
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"now":"2026-03-04T10:00:00Z"}`, rr.Body.String())
+
+	rr = send("POST", `{"advance": "90m"}`)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"now":"2026-03-04T11:30:00Z"}`, rr.Body.String())
+
+	rr = send("POST", `{"time": "2026-03-05T00:00:00+02:00"}`)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"now":"2026-03-04T22:00:00Z"}`, rr.Body.String())
+
+	for _, body := range []string{`{}`, `{"advance": "soon"}`, `{"time": "tomorrow"}`, `{"advance": "1m", "time": "2026-03-05T00:00:00Z"}`} {
+		assert.Equal(t, http.StatusBadRequest, send("POST", body).Code, body)
+	}
+}
@@ -0,0 +1,90 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/alerting"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/api/validation"
+)
+
+// maxAlertWebhookBody bounds the size of an Alertmanager webhook payload
+const maxAlertWebhookBody = 5 << 20
+
+// AlertWebhookHandler receives Alertmanager webhook notifications
+type AlertWebhookHandler struct {
+	ingester *alerting.Ingester
+	log      *logrus.Logger
+}
+
+// NewAlertWebhookHandler creates a new Alertmanager webhook handler
+func NewAlertWebhookHandler(ingester *alerting.Ingester, log *logrus.Logger) *AlertWebhookHandler {
+	return &AlertWebhookHandler{
+		ingester: ingester,
+		log:      log,
+	}
+}
+
+// AlertWebhookResponse reports how the alerts in a notification were handled
+type AlertWebhookResponse struct {
+	Status string `json:"status"`
+	*alerting.Result
+}
+
+// RegisterRoutes registers the Alertmanager webhook route
+func (h *AlertWebhookHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/api/v1/alerts/webhook", h.ReceiveAlerts).Methods("POST")
+	h.log.Info("Alertmanager webhook registered: POST /api/v1/alerts/webhook")
+}
+
+// ReceiveAlerts handles POST /api/v1/alerts/webhook
+// Firing alerts open incidents, re-fires are deduplicated into the open incident and
+// resolved alerts resolve it. See alerting.Ingester for flood control.
+func (h *AlertWebhookHandler) ReceiveAlerts(w http.ResponseWriter, r *http.Request) {
+	var msg alerting.WebhookMessage
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxAlertWebhookBody)).Decode(&msg); err != nil {
+		h.respondError(w, http.StatusBadRequest, "invalid webhook payload: "+err.Error(), validation.DecodeFields(err)...)
+		return
+	}
+
+	result, err := h.ingester.Ingest(&msg)
+	if err != nil {
+		// Alertmanager retries failed notifications; already-handled alerts deduplicate
+		h.log.WithError(err).WithField("group_key", msg.GroupKey).Error("Failed to ingest alerts")
+		h.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.log.WithFields(logrus.Fields{
+		"group_key":    msg.GroupKey,
+		"alerts":       len(msg.Alerts),
+		"created":      result.Created,
+		"deduplicated": result.Deduplicated,
+		"aggregated":   result.Aggregated,
+		"resolved":     result.Resolved,
+	}).Info("Alertmanager notification processed")
+
+	h.respondJSON(w, http.StatusOK, AlertWebhookResponse{Status: "success", Result: result})
+}
+
+func (h *AlertWebhookHandler) respondJSON(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		h.log.WithError(err).Error("Failed to encode JSON response")
+	}
+}
+
+func (h *AlertWebhookHandler) respondError(w http.ResponseWriter, statusCode int, message string, fieldErrs ...validation.FieldError) {
+	response := map[string]interface{}{
+		"status": "error",
+		"error":  message,
+	}
+	if len(fieldErrs) > 0 {
+		response["errors"] = fieldErrs
+	}
+	h.respondJSON(w, statusCode, response)
+}
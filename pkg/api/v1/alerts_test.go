@@ -0,0 +1,62 @@
+package v1
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/alerting"
+	"github.com/tosin2013/openshift-coordination-engine/internal/storage"
+)
+
+func TestAlertWebhookHandler(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	store := storage.NewIncidentStoreWithPath(t.TempDir())
+
+	router := mux.NewRouter()
+	NewAlertWebhookHandler(alerting.NewIngester(store, time.Hour, 0, log), log).RegisterRoutes(router)
+
+	payload := []byte(`{
+		"version": "4",
+		"groupKey": "{}:{alertname=\"KubePodCrashLooping\"}",
+		"status": "firing",
+		"receiver": "coordination-engine",
+		"alerts": [{
+			"status": "firing",
+			"labels": {"alertname": "KubePodCrashLooping", "namespace": "payments", "pod": "api-0", "severity": "critical"},
+			"annotations": {"summary": "Pod api-0 is crash looping"},
+			"startsAt": "2026-01-10T12:00:00Z",
+			"fingerprint": "5c1e9bde2f8b1a77"
+		}]
+	}`)
+
+	post := func(body []byte) (int, AlertWebhookResponse) {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/v1/alerts/webhook", bytes.NewReader(body)))
+		var resp AlertWebhookResponse
+		_ = json.Unmarshal(rr.Body.Bytes(), &resp)
+		return rr.Code, resp
+	}
+
+	code, resp := post(payload)
+	require.Equal(t, http.StatusOK, code)
+	require.NotNil(t, resp.Result)
+	assert.Equal(t, 1, resp.Created)
+
+	code, resp = post(payload)
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 1, resp.Deduplicated)
+	assert.Equal(t, 1, store.Count())
+
+	code, _ = post([]byte(`{"alerts": [`))
+	assert.Equal(t, http.StatusBadRequest, code)
+}
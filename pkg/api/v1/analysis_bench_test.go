@@ -0,0 +1,192 @@
+package v1
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/integrations"
+	"github.com/tosin2013/openshift-coordination-engine/internal/loadtest"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/kserve"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// Benchmarks of the analysis path against mock Prometheus and KServe backends. Run
+// them with "make bench"; "make bench-check" compares the results with a saved
+// baseline and fails on regressions. The latency variants model a real Prometheus,
+// where feature engineering is dominated by query round trips.
+
+// benchLatencies are the mock backend latencies the benchmarks run with
+var benchLatencies = []struct {
+	name    string
+	latency time.Duration
+}{
+	{"instant", 0},
+	{"latency=1ms", time.Millisecond},
+}
+
+// benchBackends holds the mock backends of a benchmark
+type benchBackends struct {
+	prometheus *loadtest.Mock
+	kserve     *loadtest.Mock
+	promClient *integrations.PrometheusClient
+	kserveCli  *kserve.ProxyClient
+}
+
+// newBenchBackends starts mock backends with a latency and clients routed to them
+func newBenchBackends(b *testing.B, latency time.Duration) *benchBackends {
+	b.Helper()
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	backends := &benchBackends{
+		prometheus: loadtest.MockPrometheus(loadtest.MockOptions{Latency: latency}),
+		kserve:     loadtest.MockKServe(loadtest.MockOptions{Latency: latency}),
+	}
+	b.Cleanup(backends.prometheus.Close)
+	b.Cleanup(backends.kserve.Close)
+	backends.promClient = integrations.NewPrometheusClient(backends.prometheus.URL, 5*time.Second, log)
+
+	b.Setenv("KSERVE_ANOMALY_DETECTOR_SERVICE", "anomaly-detector-predictor")
+	b.Setenv("KSERVE_PREDICTIVE_ANALYTICS_SERVICE", "predictive-analytics-predictor")
+	client, err := kserve.NewProxyClient(kserve.ProxyConfig{Namespace: "bench", Timeout: 5 * time.Second}, log)
+	if err != nil {
+		b.Fatal(err)
+	}
+	rollouts, err := kserve.NewRollouts(map[string]kserve.ModelRollout{
+		"anomaly-detector":     {Versions: map[string]string{"v1": backends.kserve.URL}, Pinned: "v1"},
+		"predictive-analytics": {Versions: map[string]string{"v1": backends.kserve.URL}, Pinned: "v1"},
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+	client.SetRollouts(rollouts)
+	backends.kserveCli = client
+	return backends
+}
+
+// reportQueries reports the mock backend requests per operation
+func (bb *benchBackends) reportQueries(b *testing.B) {
+	b.ReportMetric(float64(bb.prometheus.Requests())/float64(b.N), "queries/op")
+}
+
+// newBenchAnomalyHandler returns an anomaly handler served by mock backends
+func newBenchAnomalyHandler(b *testing.B, latency time.Duration) (*AnomalyHandler, *benchBackends) {
+	b.Helper()
+	backends := newBenchBackends(b, latency)
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	handler := NewAnomalyHandler(backends.kserveCli, nil, log)
+	handler.SetPrometheusClient(backends.promClient)
+	return handler, backends
+}
+
+// BenchmarkAnomalyAnalyze measures a full analysis: feature engineering, inference
+// and post-processing
+func BenchmarkAnomalyAnalyze(b *testing.B) {
+	for _, bl := range benchLatencies {
+		b.Run(bl.name, func(b *testing.B) {
+			handler, backends := newBenchAnomalyHandler(b, bl.latency)
+			ctx := context.Background()
+
+			b.ReportAllocs()
+			backends.prometheus.Reset()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := handler.Analyze(ctx, &AnomalyAnalyzeRequest{Scope: models.Scope{Namespace: "payments", Deployment: "api"}}); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.StopTimer()
+			backends.reportQueries(b)
+		})
+	}
+}
+
+// BenchmarkAnomalyAnalyze_Parallel measures analysis throughput under concurrent
+// requests, e.g. scheduled scans of many namespaces
+func BenchmarkAnomalyAnalyze_Parallel(b *testing.B) {
+	for _, bl := range benchLatencies {
+		b.Run(bl.name, func(b *testing.B) {
+			handler, _ := newBenchAnomalyHandler(b, bl.latency)
+			ctx := context.Background()
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					if _, err := handler.Analyze(ctx, &AnomalyAnalyzeRequest{Scope: models.Scope{Namespace: "payments"}}); err != nil {
+						b.Error(err)
+						return
+					}
+				}
+			})
+		})
+	}
+}
+
+// BenchmarkBuildFeatureVector measures feature engineering alone
+func BenchmarkBuildFeatureVector(b *testing.B) {
+	for _, bl := range benchLatencies {
+		b.Run(bl.name, func(b *testing.B) {
+			handler, backends := newBenchAnomalyHandler(b, bl.latency)
+			ctx := context.Background()
+
+			b.ReportAllocs()
+			backends.prometheus.Reset()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				features, _, _, err := handler.buildFeatureVector(ctx, "anomaly-detector", "payments", "", "api", nil, nil)
+				if err != nil {
+					b.Fatal(err)
+				}
+				if len(features) != len(baseMetrics)*len(featureNames) {
+					b.Fatalf("got %d features, want %d", len(features), len(baseMetrics)*len(featureNames))
+				}
+			}
+			b.StopTimer()
+			backends.reportQueries(b)
+		})
+	}
+}
+
+// BenchmarkPredict measures time-specific predictions. The Prometheus client's metric
+// cache is cleared before every prediction so each one queries the backend.
+func BenchmarkPredict(b *testing.B) {
+	requests := []struct {
+		name       string
+		newRequest func() *PredictRequest
+	}{
+		{"single", func() *PredictRequest {
+			return &PredictRequest{Scope: models.Scope{Namespace: "payments"}, Hour: 15, DayOfWeek: 3}
+		}},
+		{"series-24h", func() *PredictRequest {
+			return &PredictRequest{Scope: models.Scope{Namespace: "payments"}, Horizon: &PredictionHorizon{Every: "1h", For: "24h"}}
+		}},
+	}
+	for _, bl := range benchLatencies {
+		for _, rq := range requests {
+			b.Run(rq.name+"/"+bl.name, func(b *testing.B) {
+				backends := newBenchBackends(b, bl.latency)
+				log := logrus.New()
+				log.SetLevel(logrus.ErrorLevel)
+				handler := NewPredictionHandler(backends.kserveCli, backends.promClient, log)
+				ctx := context.Background()
+
+				b.ReportAllocs()
+				backends.prometheus.Reset()
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					backends.promClient.ClearCache()
+					if _, err := handler.Predict(ctx, rq.newRequest()); err != nil {
+						b.Fatal(err)
+					}
+				}
+				b.StopTimer()
+				backends.reportQueries(b)
+			})
+		}
+	}
+}
@@ -4,9 +4,11 @@ package v1
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
 	"net/http"
+	"regexp"
 	"sort"
 	"strings"
 	"time"
@@ -14,8 +16,23 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/sirupsen/logrus"
 
+	"github.com/tosin2013/openshift-coordination-engine/internal/anomaly"
+	"github.com/tosin2013/openshift-coordination-engine/internal/detector"
+	"github.com/tosin2013/openshift-coordination-engine/internal/featureflag"
+	"github.com/tosin2013/openshift-coordination-engine/internal/featurehistory"
 	"github.com/tosin2013/openshift-coordination-engine/internal/integrations"
+	"github.com/tosin2013/openshift-coordination-engine/internal/promql"
+	"github.com/tosin2013/openshift-coordination-engine/internal/redact"
+	"github.com/tosin2013/openshift-coordination-engine/internal/severity"
+	"github.com/tosin2013/openshift-coordination-engine/internal/slo"
+	"github.com/tosin2013/openshift-coordination-engine/internal/storage"
+	"github.com/tosin2013/openshift-coordination-engine/internal/tenant"
+	"github.com/tosin2013/openshift-coordination-engine/internal/upgrade"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/api/validation"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/diagnostics"
 	"github.com/tosin2013/openshift-coordination-engine/pkg/kserve"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/scoring"
 )
 
 // AnomalyHandler handles anomaly analysis API requests
@@ -23,7 +40,59 @@ import (
 type AnomalyHandler struct {
 	kserveClient     *kserve.ProxyClient
 	prometheusClient *integrations.PrometheusClient
-	log              *logrus.Logger
+	rolloutDetector  *detector.RolloutDetector
+	mcpDetector      *detector.MachineConfigUpdateDetector
+	upgrade          *upgrade.Monitor
+	redactor         *redact.Redactor
+	scopeDefaults    *anomaly.ScopeDefaults
+	calendars        *anomaly.BusinessCalendars
+	tenants          Tenants
+	scopeResolver    *anomaly.ScopeResolver
+
+	// Severity matrix and the namespace tiers it weighs (see SetSeverityMatrix)
+	severity *severity.Matrix
+	tiers    anomaly.TierLookup
+
+	// Histogram quantile features appended to the input of histogramModels
+	histogramFeatures []integrations.HistogramFeature
+	histogramModels   map[string]bool
+
+	// Routes and Ingresses of the scope, whose blackbox probes report external
+	// availability; their features are appended to the input of routeModels
+	routeLister *integrations.RouteLister
+	routeModels map[string]bool
+
+	// Artifacts of recent analyses, attached to the incidents created from them
+	artifacts *storage.ArtifactStore
+
+	// Detected anomalies, kept for GET /api/v1/anomalies
+	records *storage.AnomalyStore
+
+	// Site-specific hooks run before and after model inference
+	scoring *scoring.Pipeline
+
+	// Declarative filters applied to detected anomalies before incidents are opened
+	postFilters *anomaly.PostFilters
+
+	// Model of control-plane analyses whose request names none
+	controlPlaneModel string
+
+	// Feature flags gating the histogram and route feature groups per namespace
+	flags *featureflag.Flags
+
+	// Recent model input of onboarded scopes, re-scored with new model versions, and
+	// the namespaces sampled into it
+	featureHistory    *featurehistory.History
+	historyNamespaces FeatureHistoryNamespaces
+
+	// Latency objective of analysis requests, which enables the rolling feature fast
+	// path while its error budget is at risk
+	latencySLO *slo.Tracker
+
+	// Clock of result timestamps and evaluation times
+	now func() time.Time
+
+	log *logrus.Logger
 
 	// Default values when Prometheus is not available
 	defaultMetricValue float64
@@ -38,58 +107,157 @@ func NewAnomalyHandler(
 	return &AnomalyHandler{
 		kserveClient:       kserveClient,
 		prometheusClient:   prometheusClient,
+		severity:           severity.DefaultMatrix(),
 		log:                log,
+		now:                time.Now,
 		defaultMetricValue: 0.5,
 	}
 }
 
+// SetClock replaces the clock of result timestamps and evaluation times, e.g. with
+// the fixed clock of deterministic mode
+func (h *AnomalyHandler) SetClock(now func() time.Time) {
+	h.now = now
+}
+
 // RegisterRoutes registers anomaly analysis API routes
 func (h *AnomalyHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/api/v1/anomalies", h.ListAnomalies).Methods("GET")
 	router.HandleFunc("/api/v1/anomalies/analyze", h.AnalyzeAnomalies).Methods("POST")
-	h.log.Info("Anomaly analysis API endpoint registered: POST /api/v1/anomalies/analyze")
+	router.HandleFunc("/api/v1/anomalies/analyze/control-plane", h.AnalyzeControlPlaneAnomalies).Methods("POST")
+	router.HandleFunc("/api/v1/anomalies/apiserver", h.GetAPIServerAnomalies).Methods("GET")
+	router.HandleFunc("/api/v1/anomalies/recording-rules", h.GetRecordingRules).Methods("GET")
+	router.HandleFunc("/api/v1/anomalies/history", h.GetFeatureHistory).Methods("GET")
+	router.HandleFunc("/api/v1/anomalies/rescore", h.RescoreFeatureHistory).Methods("POST")
+	h.log.Info("Anomaly analysis API endpoints registered: GET /api/v1/anomalies, POST /api/v1/anomalies/analyze, POST /api/v1/anomalies/analyze/control-plane, GET /api/v1/anomalies/recording-rules, GET /api/v1/anomalies/history, POST /api/v1/anomalies/rescore")
 }
 
 // AnomalyAnalyzeRequest represents the request body for anomaly analysis
 type AnomalyAnalyzeRequest struct {
+	// Optional: namespace, deployment or pod to analyze (default: cluster-wide)
+	models.Scope
+
 	TimeRange     string  `json:"time_range"`     // Options: 1h, 6h, 24h, 7d
-	Namespace     string  `json:"namespace"`      // Optional: scope to namespace
-	Deployment    string  `json:"deployment"`     // Optional: scope to deployment
-	Pod           string  `json:"pod"`            // Optional: scope to specific pod
 	LabelSelector string  `json:"label_selector"` // Optional: label selector
 	Threshold     float64 `json:"threshold"`      // Anomaly score threshold (0.0-1.0)
 	ModelName     string  `json:"model_name"`     // KServe model to use (default: anomaly-detector)
+	Debug         bool    `json:"debug"`          // Include per-stage timings and executed PromQL
+	At            string  `json:"at,omitempty"`   // Optional: RFC3339 evaluation time for retroactive analysis (default: now)
+
+	// readOnly keeps internal analyses, such as those of the health score, out of the
+	// anomaly records and feature history
+	readOnly bool
 }
 
 // AnomalyAnalyzeResponse represents the response for anomaly analysis
 type AnomalyAnalyzeResponse struct {
-	Status            string          `json:"status"`
-	TimeRange         string          `json:"time_range"`
-	Scope             AnomalyScope    `json:"scope"`
-	ModelUsed         string          `json:"model_used"`
-	AnomaliesDetected int             `json:"anomalies_detected"`
-	Anomalies         []AnomalyResult `json:"anomalies"`
-	Summary           AnomalySummary  `json:"summary"`
-	Recommendation    string          `json:"recommendation"`
-	Features          FeatureInfo     `json:"features"`
+	Status            string              `json:"status"`
+	TimeRange         string              `json:"time_range"`
+	Scope             AnomalyScope        `json:"scope"`
+	ModelUsed         string              `json:"model_used"`
+	AnomaliesDetected int                 `json:"anomalies_detected"`
+	Anomalies         []AnomalyResult     `json:"anomalies"`
+	Summary           AnomalySummary      `json:"summary"`
+	Recommendation    string              `json:"recommendation"`
+	Features          FeatureInfo         `json:"features"`
+	Debug             *diagnostics.Report `json:"debug,omitempty"`
+
+	// EvaluatedAt is the instant every feature query was evaluated at: the request's
+	// "at" timestamp, or the time of the request
+	EvaluatedAt string `json:"evaluated_at"`
+
+	// AnalysisID identifies the analysis artifact; pass it as analysis_id when creating
+	// an incident from this analysis to attach the artifact to the incident
+	AnalysisID string `json:"analysis_id,omitempty"`
+
+	// UpgradeInProgress is set when the cluster is upgrading and the threshold was
+	// raised to AppliedThreshold by the upgrade policy
+	UpgradeInProgress bool    `json:"upgrade_in_progress,omitempty"`
+	AppliedThreshold  float64 `json:"applied_threshold,omitempty"`
+
+	// ScopeDefaults names the tenant ("tenant/<name>") and scope defaults rule that
+	// chose the model or threshold the request omitted; AppliedThreshold is then set
+	// as well
+	ScopeDefaults string `json:"scope_defaults,omitempty"`
+
+	// BusinessWindows lists the business-cycle windows of the scope active at analysis
+	// time; the threshold raised by them is reported in AppliedThreshold
+	BusinessWindows []anomaly.ActiveWindow `json:"business_windows,omitempty"`
+
+	// ExternalAvailability reports the blackbox probes of the scope's Routes and
+	// Ingresses; routes failing them are reported as anomalies
+	ExternalAvailability []integrations.RouteAvailability `json:"external_availability,omitempty"`
+
+	// DataQuality reports the metrics that were replaced by defaults
+	DataQuality DataQuality `json:"data_quality"`
+
+	// ScoringHooks are the scoring hooks the analysis ran through. Suppressed is set when
+	// one of them, named by SuppressedBy, suppressed the anomaly, e.g. for a namespace in
+	// maintenance.
+	ScoringHooks []string `json:"scoring_hooks,omitempty"`
+	Suppressed   string   `json:"suppressed,omitempty"`
+	SuppressedBy string   `json:"suppressed_by,omitempty"`
+
+	// PostFilters are the post-filters the anomalies ran through. Filtered lists the
+	// anomalies they dropped or hold back for confirmation, which are not counted in
+	// AnomaliesDetected.
+	PostFilters []string          `json:"post_filters,omitempty"`
+	Filtered    []FilteredAnomaly `json:"filtered,omitempty"`
+
+	// FastPath is set when the rolling features were fetched with one batched range
+	// query per metric because the analysis latency SLO's error budget was at risk
+	FastPath bool `json:"fast_path,omitempty"`
+
+	// ModelPredictions holds the raw model labels (-1 anomaly, 1 normal) for
+	// API versions that expose model output directly
+	ModelPredictions []int `json:"-"`
 }
 
 // AnomalyScope describes the scope of the anomaly analysis
 type AnomalyScope struct {
-	Namespace         string `json:"namespace,omitempty"`
-	Deployment        string `json:"deployment,omitempty"`
-	Pod               string `json:"pod,omitempty"`
+	models.Scope
 	TargetDescription string `json:"target_description"`
+
+	// PodPhases counts the scope's pods by lifecycle phase; only running pods are analyzed
+	PodPhases map[string]int `json:"pod_phases,omitempty"`
 }
 
 // AnomalyResult represents a detected anomaly
 type AnomalyResult struct {
 	Timestamp         string             `json:"timestamp"`
 	Severity          string             `json:"severity"`      // critical, warning, info
+	Priority          int                `json:"priority"`      // 0-100, from the severity matrix
 	AnomalyScore      float64            `json:"anomaly_score"` // 0.0-1.0
 	Confidence        float64            `json:"confidence"`    // 0.0-1.0
 	Metrics           map[string]float64 `json:"metrics"`
 	Explanation       string             `json:"explanation"`
 	RecommendedAction string             `json:"recommended_action"`
+
+	// Populated when the anomaly starts shortly after a deployment rollout
+	RecentChanges      []detector.RolloutChange `json:"recent_changes,omitempty"`
+	AlternativeActions []string                 `json:"alternative_actions,omitempty"`
+
+	// Populated when the anomaly coincides with a MachineConfigPool update: node drains
+	// and reboots are expected, so severity is lowered and remediation deferred
+	MachineConfigUpdates []detector.MachineConfigUpdate `json:"machine_config_updates,omitempty"`
+	ExpectedChurn        bool                           `json:"expected_churn,omitempty"`
+
+	// Set when the anomaly falls in a business window of the scope, e.g. trading hours
+	// or month-end, when higher load is expected
+	ExpectedLoad bool `json:"expected_load,omitempty"`
+
+	// LoweredBy names the post-filters that lowered the severity
+	LoweredBy []string `json:"lowered_by,omitempty"`
+}
+
+// FilteredAnomaly is an anomaly a post-filter dropped or holds back for confirmation
+type FilteredAnomaly struct {
+	AnomalyResult
+
+	// Filter names the post-filter, Action is drop or confirm, and Reason says why
+	Filter string `json:"filter"`
+	Action string `json:"action"`
+	Reason string `json:"reason"`
 }
 
 // AnomalySummary provides summary statistics for the analysis
@@ -106,14 +274,22 @@ type FeatureInfo struct {
 	BaseMetrics       []string `json:"base_metrics"`
 	FeaturesPerMetric int      `json:"features_per_metric"`
 	FeatureNames      []string `json:"feature_names"`
+
+	// HistogramFeatures names the histogram quantiles whose features follow the base
+	// metrics' in FeatureNames
+	HistogramFeatures []string `json:"histogram_features,omitempty"`
+
+	// RouteFeatures names the route probe features that follow the histogram features
+	RouteFeatures []string `json:"route_features,omitempty"`
 }
 
 // AnomalyErrorResponse represents an error response for anomaly analysis
 type AnomalyErrorResponse struct {
-	Status  string `json:"status"`
-	Error   string `json:"error"`
-	Details string `json:"details,omitempty"`
-	Code    string `json:"code"`
+	Status  string                  `json:"status"`
+	Error   string                  `json:"error"`
+	Details string                  `json:"details,omitempty"`
+	Code    string                  `json:"code"`
+	Errors  []validation.FieldError `json:"errors,omitempty"`
 }
 
 // Error codes for anomaly analysis failures
@@ -123,6 +299,7 @@ const (
 	ErrCodeAnomalyKServeUnavailable     = "KSERVE_UNAVAILABLE"
 	ErrCodeAnomalyModelNotFound         = "MODEL_NOT_FOUND"
 	ErrCodeAnomalyAnalysisFailed        = "ANALYSIS_FAILED"
+	ErrCodeAnomalyQueryTooExpensive     = "QUERY_TOO_EXPENSIVE"
 )
 
 // Base metrics used for anomaly detection
@@ -161,6 +338,7 @@ var featureNames = []string{
 // @Router /api/v1/anomalies/analyze [post]
 func (h *AnomalyHandler) AnalyzeAnomalies(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
+	start := time.Now()
 
 	// Check content type
 	contentType := r.Header.Get("Content-Type")
@@ -173,18 +351,62 @@ func (h *AnomalyHandler) AnalyzeAnomalies(w http.ResponseWriter, r *http.Request
 	var req AnomalyAnalyzeRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		h.log.WithError(err).Debug("Invalid anomaly analysis request format")
-		h.respondError(w, http.StatusBadRequest, "Invalid request format", err.Error(), ErrCodeAnomalyInvalidRequest)
+		h.respondError(w, http.StatusBadRequest, "Invalid request format", err.Error(), ErrCodeAnomalyInvalidRequest, validation.DecodeFields(err)...)
 		return
 	}
 
+	response, err := h.Analyze(ctx, &req)
+	if err != nil {
+		var requestErr *RequestError
+		if errors.As(err, &requestErr) {
+			h.respondError(w, requestErr.StatusCode, requestErr.Message, requestErr.Details, requestErr.Code, requestErr.Errors...)
+			h.observeLatency(start, requestErr.StatusCode)
+			return
+		}
+		h.respondError(w, http.StatusInternalServerError, "Anomaly analysis failed", err.Error(), ErrCodeAnomalyAnalysisFailed)
+		h.observeLatency(start, http.StatusInternalServerError)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, response)
+	h.observeLatency(start, http.StatusOK)
+}
+
+// Analyze runs anomaly analysis for a request: defaults and validation, feature
+// engineering, model inference and post-processing. It is shared by all API versions
+// and the gRPC server.
+func (h *AnomalyHandler) Analyze(ctx context.Context, req *AnomalyAnalyzeRequest) (*AnomalyAnalyzeResponse, error) {
 	// Set defaults and validate
-	h.setRequestDefaults(&req)
-	if err := h.validateRequest(&req); err != nil {
+	scopeDefaults := h.applyScopeDefaults(ctx, req)
+	h.setRequestDefaults(req)
+	if err := h.validateRequest(req); err != nil {
 		h.log.WithError(err).Debug("Anomaly analysis request validation failed")
-		h.respondError(w, http.StatusBadRequest, err.Error(), "", ErrCodeAnomalyInvalidRequest)
-		return
+		return nil, invalidRequest(err, ErrCodeAnomalyInvalidRequest)
+	}
+	req.Scope = req.Scope.Resolve()
+	now, err := parseEvaluationTime(req.At, h.now())
+	if err != nil {
+		h.log.WithError(err).Debug("Anomaly analysis request evaluation time invalid")
+		return nil, invalidRequest(err, ErrCodeAnomalyInvalidRequest)
 	}
 
+	// Raise the threshold while the cluster is upgrading, when churn is expected. The
+	// upgrade state is only known for now, so retroactive analyses are not adjusted.
+	upgrading := req.At == "" && h.upgrade != nil && h.upgrade.Active()
+	if upgrading {
+		req.Threshold = h.upgrade.AdjustThreshold(req.Threshold)
+	}
+
+	// Expect higher load during the scope's business windows
+	var windows []anomaly.ActiveWindow
+	if h.calendars != nil {
+		windows = h.calendars.Active(req.Namespace, now)
+	}
+	if owner := h.tenantFor(ctx, req.Namespace); owner != nil {
+		windows = append(windows, owner.Active(now)...)
+	}
+	req.Threshold = anomaly.AdjustThreshold(req.Threshold, windows)
+
 	h.log.WithFields(logrus.Fields{
 		"time_range": req.TimeRange,
 		"namespace":  req.Namespace,
@@ -192,26 +414,80 @@ func (h *AnomalyHandler) AnalyzeAnomalies(w http.ResponseWriter, r *http.Request
 		"pod":        req.Pod,
 		"threshold":  req.Threshold,
 		"model_name": req.ModelName,
+		"at":         req.At,
 	}).Info("Processing anomaly analysis request")
 
 	// Check if KServe is available
 	if h.kserveClient == nil {
-		h.respondError(w, http.StatusServiceUnavailable, "KServe integration not enabled", "KServe client is not configured", ErrCodeAnomalyKServeUnavailable)
-		return
+		return nil, &RequestError{
+			StatusCode: http.StatusServiceUnavailable,
+			Message:    "KServe integration not enabled",
+			Details:    "KServe client is not configured",
+			Code:       ErrCodeAnomalyKServeUnavailable,
+		}
 	}
 
 	// Check if model exists
 	if _, exists := h.kserveClient.GetModel(req.ModelName); !exists {
-		h.respondError(w, http.StatusServiceUnavailable, fmt.Sprintf("Model '%s' not available", req.ModelName), "Model not found in KServe", ErrCodeAnomalyModelNotFound)
-		return
+		return nil, &RequestError{
+			StatusCode: http.StatusServiceUnavailable,
+			Message:    fmt.Sprintf("Model '%s' not available", req.ModelName),
+			Details:    "Model not found in KServe",
+			Code:       ErrCodeAnomalyModelNotFound,
+		}
 	}
 
+	// Collect per-stage timings when debugging was requested, and the executed PromQL
+	// and raw model response for the analysis artifact
+	var trace *diagnostics.Trace
+	if req.Debug || h.artifacts != nil {
+		trace = diagnostics.NewTrace()
+		ctx = diagnostics.WithTrace(ctx, trace)
+	}
+	var recorder *kserve.Recorder
+	if h.artifacts != nil {
+		recorder = kserve.NewRecorder()
+		ctx = kserve.WithRecorder(ctx, recorder)
+	}
+
+	// Evaluate every feature query at one instant so the vector is consistent
+	snapshot := integrations.NewSnapshot(now)
+	ctx = integrations.WithSnapshot(ctx, snapshot)
+
+	// Model canary routing is gated per namespace
+	ctx = featureflag.WithNamespace(ctx, req.Namespace)
+
+	// Spend fewer Prometheus round trips while the latency SLO is at risk
+	ctx, fastPath := h.withFastPath(ctx)
+
 	// Build feature vector (45 features)
-	features, metricsData, err := h.buildFeatureVector(ctx, req.Namespace, req.Pod, req.Deployment)
+	endStage := trace.StartStage("feature_engineering")
+	pods := h.resolvePods(ctx, req)
+	routes := h.resolveRoutes(ctx, req)
+	features, metricsData, quality, err := h.buildFeatureVector(ctx, req.ModelName, req.Namespace, req.Pod, req.Deployment, pods, routes)
+	endStage()
+	if costErr, ok := integrations.IsQueryCostError(err); ok {
+		return nil, &RequestError{
+			StatusCode: http.StatusUnprocessableEntity,
+			Message:    "Query scope too broad",
+			Details:    costErr.Error(),
+			Code:       ErrCodeAnomalyQueryTooExpensive,
+		}
+	}
 	if err != nil {
 		h.log.WithError(err).Warn("Failed to build feature vector from Prometheus, using defaults")
 		features = h.getDefaultFeatures()
 		metricsData = h.getDefaultMetricsData()
+		defaulted := append([]string(nil), baseMetrics...)
+		for _, histogram := range h.modelHistogramFeatures(req.ModelName) {
+			features = append(features, h.getDefaultMetricFeatures()...)
+			defaulted = append(defaulted, histogram.Name)
+		}
+		for _, route := range h.modelRouteFeatures(req.ModelName) {
+			features = append(features, h.getDefaultMetricFeatures()...)
+			defaulted = append(defaulted, route.name)
+		}
+		quality = defaultedQuality(err, defaulted...)
 	}
 
 	h.log.WithFields(logrus.Fields{
@@ -219,17 +495,84 @@ func (h *AnomalyHandler) AnalyzeAnomalies(w http.ResponseWriter, r *http.Request
 		"metrics_count": len(baseMetrics),
 	}).Debug("Feature vector built")
 
+	// Run the site-specific hooks on the model input
+	scoringInput := &scoring.Input{
+		Namespace:    req.Namespace,
+		Deployment:   req.Deployment,
+		Pod:          req.Pod,
+		Model:        req.ModelName,
+		At:           snapshot.At(),
+		FeatureNames: h.buildFeatureInfo(req.ModelName).FeatureNames,
+		Features:     features,
+		Metrics:      metricsData,
+	}
+	if err := h.scoring.Before(ctx, scoringInput); err != nil {
+		return nil, scoringHookFailed(err)
+	}
+	features, metricsData = scoringInput.Features, scoringInput.Metrics
+	if err == nil {
+		h.recordFeatures(ctx, req, snapshot.At(), features)
+	}
+
 	// Call KServe anomaly-detector model
 	instances := [][]float64{features}
+	endStage = trace.StartStage("model_inference")
 	resp, err := h.kserveClient.Predict(ctx, req.ModelName, instances)
+	endStage()
 	if err != nil {
 		h.log.WithError(err).WithField("model", req.ModelName).Error("KServe anomaly detection failed")
-		h.respondError(w, http.StatusServiceUnavailable, "Anomaly detection failed", err.Error(), ErrCodeAnomalyAnalysisFailed)
-		return
+		if reqErr := invalidModelResponse(err, "Anomaly detection failed"); reqErr != nil {
+			return nil, reqErr
+		}
+		return nil, &RequestError{
+			StatusCode: http.StatusServiceUnavailable,
+			Message:    "Anomaly detection failed",
+			Details:    err.Error(),
+			Code:       ErrCodeAnomalyAnalysisFailed,
+		}
 	}
 
 	// Process predictions and build response
-	response := h.buildAnalysisResponse(&req, resp, features, metricsData)
+	endStage = trace.StartStage("post_processing")
+	result := h.scorePrediction(resp, metricsData)
+	if err := h.scoring.After(ctx, scoringInput, &result); err != nil {
+		endStage()
+		return nil, scoringHookFailed(err)
+	}
+	response := h.buildAnalysisResponse(req, resp, features, metricsData, result)
+	response.DataQuality = quality
+	stampEvaluationTime(&response, snapshot.At())
+	annotatePodScope(&response, pods)
+	h.annotateExternalAvailability(ctx, &response, routes, features)
+	h.prioritizeAnomalies(ctx, req, &response, pods)
+
+	// Correlate anomalies with recent rollouts and in-progress node updates
+	h.annotateRecentChanges(ctx, req, &response, now)
+	h.annotateMachineConfigUpdates(ctx, req, &response)
+	h.annotateBusinessWindows(&response, windows)
+	h.applyPostFilters(ctx, req, &response, snapshot.At(), features)
+	h.redactExplanations(&response)
+	endStage()
+	if req.Debug {
+		response.Debug = trace.Report()
+	}
+	if h.artifacts != nil {
+		response.AnalysisID = h.rememberArtifact(req, &response, snapshot.At(), features, trace, recorder, resp)
+	}
+	response.FastPath = fastPath
+	if scopeDefaults != "" {
+		response.ScopeDefaults = scopeDefaults
+		response.AppliedThreshold = req.Threshold
+	}
+	if upgrading {
+		response.UpgradeInProgress = true
+		response.AppliedThreshold = req.Threshold
+	}
+	if len(windows) > 0 {
+		response.BusinessWindows = windows
+		response.AppliedThreshold = req.Threshold
+	}
+	h.recordAnomalies(req, &response, snapshot.At(), resp.ModelVersion)
 
 	h.log.WithFields(logrus.Fields{
 		"anomalies_detected": response.AnomaliesDetected,
@@ -237,38 +580,103 @@ func (h *AnomalyHandler) AnalyzeAnomalies(w http.ResponseWriter, r *http.Request
 		"model":              response.ModelUsed,
 	}).Info("Anomaly analysis completed successfully")
 
-	h.respondJSON(w, http.StatusOK, response)
+	return &response, nil
 }
 
-// setRequestDefaults sets default values for optional request fields
-func (h *AnomalyHandler) setRequestDefaults(req *AnomalyAnalyzeRequest) {
-	if req.TimeRange == "" {
-		req.TimeRange = "1h"
+// GetRecordingRules handles GET /api/v1/anomalies/recording-rules
+// @Summary Generate PrometheusRule YAML for anomaly features
+// @Description Returns a PrometheusRule manifest that pre-computes the features used in anomaly detection.
+// @Description Apply it and set PROMETHEUS_USE_RECORDING_RULES=true to query the pre-computed series.
+// @Tags anomaly
+// @Produce application/yaml
+// @Param namespace query string false "Namespace for the PrometheusRule (default: openshift-monitoring)"
+// @Param name query string false "PrometheusRule name"
+// @Param interval query string false "Rule evaluation interval (default: 30s)"
+// @Success 200 {string} string "PrometheusRule YAML"
+// @Failure 400 {object} AnomalyErrorResponse
+// @Router /api/v1/anomalies/recording-rules [get]
+func (h *AnomalyHandler) GetRecordingRules(w http.ResponseWriter, r *http.Request) {
+	namespace := r.URL.Query().Get("namespace")
+	if namespace == "" {
+		namespace = "openshift-monitoring"
+	}
+	interval := r.URL.Query().Get("interval")
+	if interval == "" {
+		interval = "30s"
+	}
+	if _, err := time.ParseDuration(interval); err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid interval", err.Error(), ErrCodeAnomalyInvalidRequest,
+			validation.FieldError{Field: "interval", Constraint: validation.ConstraintFormat, Value: interval, Message: "interval must be a duration"})
+		return
 	}
-	if req.Threshold == 0 {
-		req.Threshold = 0.7
+
+	data, err := integrations.GenerateRecordingRulesYAML(r.URL.Query().Get("name"), namespace, interval)
+	if err != nil {
+		h.respondError(w, http.StatusInternalServerError, "Failed to generate recording rules", err.Error(), ErrCodeAnomalyAnalysisFailed)
+		return
 	}
-	if req.ModelName == "" {
-		req.ModelName = "anomaly-detector"
+
+	w.Header().Set("Content-Type", "application/yaml")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(data); err != nil {
+		h.log.WithError(err).Error("Failed to write recording rules response")
 	}
 }
 
-// validateRequest validates the anomaly analysis request parameters
-func (h *AnomalyHandler) validateRequest(req *AnomalyAnalyzeRequest) error {
-	// Validate time range
-	validTimeRanges := map[string]bool{
-		"1h": true, "6h": true, "24h": true, "7d": true,
+// applyScopeDefaults fills in the model and threshold the request omits, first from
+// the thresholds of the tenant onboarding its namespace, then from the first scope
+// defaults rule matching it, and returns the names of those that applied
+func (h *AnomalyHandler) applyScopeDefaults(ctx context.Context, req *AnomalyAnalyzeRequest) string {
+	var applied []string
+	if owner := h.tenantFor(ctx, req.Namespace); owner != nil && (req.ModelName == "" || req.Threshold == 0) &&
+		(owner.Thresholds.Model != "" || owner.Thresholds.Anomaly != 0) {
+		validation.Default(&req.ModelName, owner.Thresholds.Model)
+		validation.Default(&req.Threshold, owner.Thresholds.Anomaly)
+		applied = append(applied, "tenant/"+owner.Name)
+	}
+
+	if h.scopeDefaults == nil || (req.ModelName != "" && req.Threshold != 0) {
+		return strings.Join(applied, ", ")
 	}
-	if !validTimeRanges[req.TimeRange] {
-		return fmt.Errorf("time_range must be one of: 1h, 6h, 24h, 7d")
+	rule := h.scopeDefaults.Match(ctx, req.Namespace)
+	if rule == nil {
+		return strings.Join(applied, ", ")
 	}
+	validation.Default(&req.ModelName, rule.Model)
+	validation.Default(&req.Threshold, rule.Threshold)
+
+	h.log.WithFields(logrus.Fields{
+		"namespace":  req.Namespace,
+		"rule":       rule.Name,
+		"model_name": req.ModelName,
+		"threshold":  req.Threshold,
+	}).Debug("Applied scope defaults")
+	return strings.Join(append(applied, rule.Name), ", ")
+}
 
-	// Validate threshold
-	if req.Threshold < 0 || req.Threshold > 1 {
-		return fmt.Errorf("threshold must be between 0.0 and 1.0")
+// tenantFor returns the tenant onboarding a namespace, or nil
+func (h *AnomalyHandler) tenantFor(ctx context.Context, namespace string) *tenant.Tenant {
+	if h.tenants == nil || namespace == "" {
+		return nil
 	}
+	return h.tenants.For(ctx, namespace)
+}
 
-	return nil
+// setRequestDefaults sets default values for optional request fields
+func (h *AnomalyHandler) setRequestDefaults(req *AnomalyAnalyzeRequest) {
+	validation.Default(&req.TimeRange, "1h")
+	validation.Default(&req.Threshold, validation.DefaultThreshold)
+	validation.Default(&req.ModelName, "anomaly-detector")
+}
+
+// validateRequest validates the anomaly analysis request parameters
+func (h *AnomalyHandler) validateRequest(req *AnomalyAnalyzeRequest) error {
+	rules := []validation.Rule{
+		validation.OneOf("time_range", req.TimeRange, "1h", "6h", "24h", "7d"),
+		validation.Threshold("threshold", req.Threshold),
+	}
+	rules = append(rules, req.Scope.Rules()...)
+	return validation.Check(rules...)
 }
 
 // buildFeatureVector builds the 45-feature vector from Prometheus metrics
@@ -282,18 +690,38 @@ func (h *AnomalyHandler) validateRequest(req *AnomalyAnalyzeRequest) error {
 // - lag_5: 5-minute lag
 // - diff: value - lag_1
 // - pct_change: (value - lag_1) / lag_1
-func (h *AnomalyHandler) buildFeatureVector(ctx context.Context, namespace, pod, deployment string) ([]float64, map[string]float64, error) {
+//
+// Models configured with histogram features get 9 more features per histogram quantile,
+// and models configured with route features 9 per route probe feature after them.
+// Their values are not part of the returned metrics, which feed the heuristic score.
+//
+// Metrics that cannot be queried get default features and are reported in the returned
+// data quality. Metrics whose exporter the last capability probe found missing are not
+// queried at all, nor are feature groups whose flag is disabled for the namespace.
+func (h *AnomalyHandler) buildFeatureVector(ctx context.Context, model, namespace, pod, deployment string, pods *anomaly.PodScope, routes []integrations.RouteTarget) ([]float64, map[string]float64, DataQuality, error) {
+	var quality DataQuality
 	if h.prometheusClient == nil || !h.prometheusClient.IsAvailable() {
-		return nil, nil, fmt.Errorf("prometheus client not available")
+		return nil, nil, quality, errPrometheusUnavailable
 	}
 
 	features := make([]float64, 0, 45)
 	metricsData := make(map[string]float64)
 
 	for _, metric := range baseMetrics {
-		metricFeatures, currentValue, err := h.queryMetricFeatures(ctx, metric, namespace, pod, deployment)
+		if missing := h.prometheusClient.MissingExporters(integrations.MetricExporters[metric]...); len(missing) > 0 {
+			quality.addDefaulted(metric, missing)
+			features = append(features, h.getDefaultMetricFeatures()...)
+			metricsData[metric] = h.defaultMetricValue
+			continue
+		}
+
+		metricFeatures, currentValue, err := h.queryMetricFeatures(ctx, metric, namespace, pod, deployment, pods)
+		if _, refused := integrations.IsQueryCostError(err); refused {
+			return nil, nil, quality, err
+		}
 		if err != nil {
 			h.log.WithError(err).WithField("metric", metric).Debug("Failed to query metric features, using defaults")
+			quality.addDefaulted(metric, nil)
 			metricFeatures = h.getDefaultMetricFeatures()
 			currentValue = h.defaultMetricValue
 		}
@@ -301,29 +729,111 @@ func (h *AnomalyHandler) buildFeatureVector(ctx context.Context, namespace, pod,
 		metricsData[metric] = currentValue
 	}
 
-	return features, metricsData, nil
+	selector := h.scopeSelector(namespace, pod, deployment, pods)
+	histogramsEnabled := h.featureEnabled(featureflag.HistogramFeatures, namespace, len(h.modelHistogramFeatures(model)))
+	for _, histogram := range h.modelHistogramFeatures(model) {
+		if !histogramsEnabled {
+			quality.addDisabled(histogram.Name)
+			features = append(features, h.getDefaultMetricFeatures()...)
+			continue
+		}
+
+		metricFeatures, err := h.queryRollingFeatures(ctx, histogram.Query(selector))
+		if _, refused := integrations.IsQueryCostError(err); refused {
+			return nil, nil, quality, err
+		}
+		if err != nil {
+			h.log.WithError(err).WithField("histogram", histogram.Name).Debug("Failed to query histogram features, using defaults")
+			quality.addDefaulted(histogram.Name, nil)
+			metricFeatures = h.getDefaultMetricFeatures()
+		}
+		features = append(features, metricFeatures...)
+	}
+
+	routeFeatures, err := h.queryRouteFeatures(ctx, model, namespace, routes, &quality)
+	if err != nil {
+		return nil, nil, quality, err
+	}
+	features = append(features, routeFeatures...)
+
+	return features, metricsData, quality, nil
+}
+
+// featureEnabled reports whether a feature group's flag is enabled for a namespace,
+// counting the skipped group when a model expects features of it
+func (h *AnomalyHandler) featureEnabled(flag, namespace string, expected int) bool {
+	if h.flags.Enabled(flag, namespace) {
+		return true
+	}
+	if expected > 0 {
+		featureflag.RecordDisabled(flag)
+	}
+	return false
+}
+
+// modelHistogramFeatures returns the histogram features a model expects
+func (h *AnomalyHandler) modelHistogramFeatures(model string) []integrations.HistogramFeature {
+	if !h.histogramModels[model] {
+		return nil
+	}
+	return h.histogramFeatures
 }
 
 // queryMetricFeatures queries Prometheus for all features of a single metric
-func (h *AnomalyHandler) queryMetricFeatures(ctx context.Context, metric, namespace, pod, deployment string) ([]float64, float64, error) {
+func (h *AnomalyHandler) queryMetricFeatures(ctx context.Context, metric, namespace, pod, deployment string, pods *anomaly.PodScope) ([]float64, float64, error) {
+	// Prefer pre-computed recording rule series when enabled and the scope allows it.
+	// They aggregate every pod of the scope, so they are not used when pods are excluded.
+	if recorded, ok := h.prometheusClient.RecordedFeatureQueries(metric, namespace, deployment, pod); ok && pods.Excluded() == 0 {
+		metricFeatures, err := h.prometheusClient.GetRecordedAnomalyMetricFeatures(ctx, recorded)
+		if err == nil {
+			return metricFeatures.ToSlice(), metricFeatures.Value, nil
+		}
+		h.log.WithError(err).WithField("metric", metric).Debug("Recorded feature series unavailable, falling back to raw queries")
+	}
+
 	// Build base query based on metric type
-	baseQuery := h.getMetricBaseQuery(metric, namespace, pod, deployment)
+	baseQuery := h.getMetricBaseQuery(metric, namespace, pod, deployment, pods)
+
+	metricFeatures, err := h.queryRollingFeatures(ctx, baseQuery)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query current value for %s: %w", metric, err)
+	}
+	return metricFeatures, metricFeatures[0], nil
+}
+
+// queryRollingFeatures queries the 9 features of a base query: its value, 5-minute
+// rolling statistics, lags and change. A value that is not a number, such as the
+// quantile of a histogram without observations, is an error. On the fast path they
+// are derived from one range query, falling back to one instant query per feature.
+func (h *AnomalyHandler) queryRollingFeatures(ctx context.Context, baseQuery string) ([]float64, error) {
+	if usesFastPath(ctx) {
+		features, err := h.queryRollingFeaturesBatched(ctx, baseQuery)
+		if err == nil {
+			return features, nil
+		}
+		h.log.WithError(err).WithField("query", baseQuery).Debug("Batched rolling features unavailable, falling back to instant queries")
+	}
 
 	// Query current value
 	currentValue, err := h.queryPromQL(ctx, baseQuery)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to query current value for %s: %w", metric, err)
+		return nil, err
+	}
+	if math.IsNaN(currentValue) || math.IsInf(currentValue, 0) {
+		return nil, fmt.Errorf("query returned %v: %s", currentValue, baseQuery)
 	}
 
 	// Query rolling statistics (5m window) - use helper that returns default on error
-	mean5m := h.queryPromQLWithDefault(ctx, fmt.Sprintf("avg_over_time((%s)[5m:])", baseQuery), currentValue)
-	std5m := h.queryPromQLWithDefault(ctx, fmt.Sprintf("stddev_over_time((%s)[5m:])", baseQuery), 0)
-	min5m := h.queryPromQLWithDefault(ctx, fmt.Sprintf("min_over_time((%s)[5m:])", baseQuery), currentValue)
-	max5m := h.queryPromQLWithDefault(ctx, fmt.Sprintf("max_over_time((%s)[5m:])", baseQuery), currentValue)
+	queries := h.prometheusClient.Queries()
+	params := promql.Params{Query: baseQuery}
+	mean5m := h.queryPromQLWithDefault(ctx, queries.MustRender("rolling.mean_5m", params), currentValue)
+	std5m := h.queryPromQLWithDefault(ctx, queries.MustRender("rolling.stddev_5m", params), 0)
+	min5m := h.queryPromQLWithDefault(ctx, queries.MustRender("rolling.min_5m", params), currentValue)
+	max5m := h.queryPromQLWithDefault(ctx, queries.MustRender("rolling.max_5m", params), currentValue)
 
 	// Query lag values
-	lag1 := h.queryPromQLWithDefault(ctx, fmt.Sprintf("(%s) offset 1m", baseQuery), currentValue)
-	lag5 := h.queryPromQLWithDefault(ctx, fmt.Sprintf("(%s) offset 5m", baseQuery), currentValue)
+	lag1 := h.queryPromQLWithDefault(ctx, queries.MustRender("rolling.lag_1m", params), currentValue)
+	lag5 := h.queryPromQLWithDefault(ctx, queries.MustRender("rolling.lag_5m", params), currentValue)
 
 	// Calculate derived features
 	diff := currentValue - lag1
@@ -343,12 +853,32 @@ func (h *AnomalyHandler) queryMetricFeatures(ctx context.Context, metric, namesp
 		lag5,
 		diff,
 		pctChange,
-	}, currentValue, nil
+	}, nil
 }
 
 // getMetricBaseQuery returns the Prometheus query for a given metric
-func (h *AnomalyHandler) getMetricBaseQuery(metric, namespace, pod, deployment string) string {
-	// Build label selectors
+func (h *AnomalyHandler) getMetricBaseQuery(metric, namespace, pod, deployment string, pods *anomaly.PodScope) string {
+	params := promql.Params{Selector: h.scopeSelector(namespace, pod, deployment, pods)}
+
+	// Template of each metric type
+	templates := map[string]string{
+		"node_cpu_utilization":    "node.cpu_utilization",
+		"node_memory_utilization": "node.memory_utilization",
+		"pod_cpu_usage":           "anomaly.pod_cpu_usage",
+		"pod_memory_usage":        "anomaly.pod_memory_usage",
+		"container_restart_count": "anomaly.container_restarts",
+	}
+
+	name, ok := templates[metric]
+	if !ok {
+		return metric // Return metric name as-is if not found
+	}
+	return h.prometheusClient.Queries().MustRender(name, params)
+}
+
+// scopeSelector returns the label matchers of the analysis scope, without braces. When
+// pods are excluded, the running pods replace the deployment's name prefix.
+func (h *AnomalyHandler) scopeSelector(namespace, pod, deployment string, pods *anomaly.PodScope) string {
 	var selectors []string
 	if namespace != "" {
 		selectors = append(selectors, fmt.Sprintf("namespace=%q", namespace))
@@ -356,60 +886,44 @@ func (h *AnomalyHandler) getMetricBaseQuery(metric, namespace, pod, deployment s
 	if pod != "" {
 		selectors = append(selectors, fmt.Sprintf("pod=%q", pod))
 	}
-	if deployment != "" {
-		selectors = append(selectors, fmt.Sprintf(`pod=~"%s-.*"`, deployment))
-	}
-
-	selectorStr := ""
-	if len(selectors) > 0 {
-		selectorStr = strings.Join(selectors, ",")
-	}
-
-	// Define queries for each metric type
-	queries := map[string]string{
-		"node_cpu_utilization": fmt.Sprintf(
-			`avg(1 - rate(node_cpu_seconds_total{mode="idle"%s}[5m]))`,
-			h.prependComma(selectorStr),
-		),
-		"node_memory_utilization": fmt.Sprintf(
-			`1 - (node_memory_MemAvailable_bytes%s / node_memory_MemTotal_bytes%s)`,
-			h.wrapSelector(selectorStr), h.wrapSelector(selectorStr),
-		),
-		"pod_cpu_usage": fmt.Sprintf(
-			`sum(rate(container_cpu_usage_seconds_total{container!=""%s}[5m])) by (pod)`,
-			h.prependComma(selectorStr),
-		),
-		"pod_memory_usage": fmt.Sprintf(
-			`sum(container_memory_working_set_bytes{container!=""%s}) by (pod) / sum(kube_pod_container_resource_limits{resource="memory"%s}) by (pod)`,
-			h.prependComma(selectorStr), h.prependComma(selectorStr),
-		),
-		"container_restart_count": fmt.Sprintf(
-			`sum(kube_pod_container_status_restarts_total{%s}) by (pod)`,
-			selectorStr,
-		),
-	}
-
-	query, ok := queries[metric]
-	if !ok {
-		return metric // Return metric name as-is if not found
+	if matcher := pods.Matcher(); matcher != "" {
+		selectors = append(selectors, matcher)
+	} else if deployment != "" {
+		selectors = append(selectors, fmt.Sprintf(`pod=~%q`, regexp.QuoteMeta(deployment)+"-.*"))
+	}
+	return strings.Join(selectors, ",")
+}
+
+// resolvePods returns the pods of a namespace or deployment scope by lifecycle phase,
+// or nil if they cannot be listed. Pod scopes name their pod and are not resolved.
+func (h *AnomalyHandler) resolvePods(ctx context.Context, req *AnomalyAnalyzeRequest) *anomaly.PodScope {
+	if h.scopeResolver == nil || req.Pod != "" {
+		return nil
 	}
-	return query
+	pods, err := h.scopeResolver.Resolve(ctx, req.Namespace, req.Deployment)
+	if err != nil {
+		h.log.WithError(err).Warn("Failed to resolve scope pods, analyzing pods in every phase")
+		return nil
+	}
+	return pods
 }
 
-// prependComma prepends a comma if selector is non-empty
-func (h *AnomalyHandler) prependComma(selector string) string {
-	if selector != "" {
-		return "," + selector
+// stampEvaluationTime sets the evaluation timestamp of the response and its anomalies
+func stampEvaluationTime(response *AnomalyAnalyzeResponse, at time.Time) {
+	timestamp := at.UTC().Format(time.RFC3339)
+	response.EvaluatedAt = timestamp
+	for i := range response.Anomalies {
+		response.Anomalies[i].Timestamp = timestamp
 	}
-	return ""
 }
 
-// wrapSelector wraps selector with braces if non-empty
-func (h *AnomalyHandler) wrapSelector(selector string) string {
-	if selector != "" {
-		return "{" + selector + "}"
+// annotatePodScope adds the phase breakdown of the scope's pods to the response
+func annotatePodScope(response *AnomalyAnalyzeResponse, pods *anomaly.PodScope) {
+	if pods == nil {
+		return
 	}
-	return ""
+	response.Scope.PodPhases = pods.Phases
+	response.Scope.TargetDescription += fmt.Sprintf(" (pods: %s)", pods.Describe())
 }
 
 // queryPromQL executes a PromQL query and returns the result
@@ -428,12 +942,16 @@ func (h *AnomalyHandler) queryPromQL(ctx context.Context, query string) (float64
 }
 
 // queryPromQLWithDefault executes a PromQL query and returns a default value on error
+// or when the result is not a number, e.g. a histogram quantile without observations
 func (h *AnomalyHandler) queryPromQLWithDefault(ctx context.Context, query string, defaultValue float64) float64 {
 	value, err := h.queryPromQL(ctx, query)
 	if err != nil {
 		h.log.WithError(err).WithField("query", query).Debug("PromQL query failed, using default value")
 		return defaultValue
 	}
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return defaultValue
+	}
 	return value
 }
 
@@ -481,13 +999,8 @@ func (h *AnomalyHandler) getDefaultMetricsData() map[string]float64 {
 	}
 }
 
-// buildAnalysisResponse builds the anomaly analysis response from model predictions
-func (h *AnomalyHandler) buildAnalysisResponse(
-	req *AnomalyAnalyzeRequest,
-	resp *kserve.DetectResponse,
-	features []float64,
-	metricsData map[string]float64,
-) AnomalyAnalyzeResponse {
+// scorePrediction derives the verdict of the scoring hooks from the model predictions
+func (h *AnomalyHandler) scorePrediction(resp *kserve.DetectResponse, metricsData map[string]float64) scoring.Result {
 	// Determine if anomaly was detected
 	isAnomaly := len(resp.Predictions) > 0 && resp.Predictions[0] == -1
 
@@ -500,10 +1013,22 @@ func (h *AnomalyHandler) buildAnalysisResponse(
 		anomalyScore = h.calculateAnomalyScore(metricsData)
 	}
 
+	return scoring.Result{Anomalous: isAnomaly, Score: anomalyScore}
+}
+
+// buildAnalysisResponse builds the anomaly analysis response from model predictions and
+// the verdict of the scoring hooks
+func (h *AnomalyHandler) buildAnalysisResponse(
+	req *AnomalyAnalyzeRequest,
+	resp *kserve.DetectResponse,
+	features []float64,
+	metricsData map[string]float64,
+	result scoring.Result,
+) AnomalyAnalyzeResponse {
 	// Build anomaly results
 	var anomalies []AnomalyResult
-	if isAnomaly && anomalyScore >= req.Threshold {
-		anomaly := h.buildAnomalyResult(metricsData, anomalyScore)
+	if result.Anomalous && result.Suppressed == "" && result.Score >= req.Threshold {
+		anomaly := h.buildAnomalyResult(metricsData, result.Score)
 		anomalies = append(anomalies, anomaly)
 	}
 
@@ -511,7 +1036,7 @@ func (h *AnomalyHandler) buildAnalysisResponse(
 	scope := h.buildScope(req)
 
 	// Build feature info
-	featureInfo := h.buildFeatureInfo()
+	featureInfo := h.buildFeatureInfo(req.ModelName)
 
 	// Calculate summary
 	summary := h.buildSummary(anomalies, features)
@@ -529,6 +1054,10 @@ func (h *AnomalyHandler) buildAnalysisResponse(
 		Summary:           summary,
 		Recommendation:    recommendation,
 		Features:          featureInfo,
+		ScoringHooks:      h.scoring.Names(),
+		Suppressed:        result.Suppressed,
+		SuppressedBy:      result.SuppressedBy,
+		ModelPredictions:  resp.Predictions,
 	}
 }
 
@@ -566,23 +1095,21 @@ func (h *AnomalyHandler) calculateAnomalyScore(metrics map[string]float64) float
 
 // buildAnomalyResult creates an AnomalyResult from metrics data
 func (h *AnomalyHandler) buildAnomalyResult(metrics map[string]float64, score float64) AnomalyResult {
-	// Determine severity based on score
-	severity := "info"
-	if score >= 0.9 {
-		severity = "critical"
-	} else if score >= 0.7 {
-		severity = "warning"
-	}
+	// Determine severity and priority from the score alone; prioritizeAnomalies adds
+	// the scope's tier, blast radius and SLO burn
+	assessment := h.severity.Assess(severity.Factors{Score: score})
+	level := anomalySeverity(assessment.Severity)
 
 	// Build explanation based on metrics
 	explanation := h.generateExplanation(metrics)
 
 	// Recommend action based on severity and metrics
-	recommendedAction := h.recommendAction(metrics, severity)
+	recommendedAction := h.recommendAction(metrics, level)
 
 	return AnomalyResult{
-		Timestamp:         time.Now().UTC().Format(time.RFC3339),
-		Severity:          severity,
+		Timestamp:         h.now().UTC().Format(time.RFC3339),
+		Severity:          level,
+		Priority:          assessment.Priority,
 		AnomalyScore:      score,
 		Confidence:        0.87, // Base confidence from model
 		Metrics:           metrics,
@@ -648,42 +1175,32 @@ func (h *AnomalyHandler) recommendAction(metrics map[string]float64, severity st
 
 // buildScope builds the scope description
 func (h *AnomalyHandler) buildScope(req *AnomalyAnalyzeRequest) AnomalyScope {
-	var description string
-	switch {
-	case req.Pod != "":
-		description = fmt.Sprintf("pod '%s' in namespace '%s'", req.Pod, req.Namespace)
-	case req.Deployment != "":
-		description = fmt.Sprintf("deployment '%s' in namespace '%s'", req.Deployment, req.Namespace)
-	case req.Namespace != "":
-		description = fmt.Sprintf("namespace '%s'", req.Namespace)
-	default:
-		description = "cluster-wide"
-	}
-
-	return AnomalyScope{
-		Namespace:         req.Namespace,
-		Deployment:        req.Deployment,
-		Pod:               req.Pod,
-		TargetDescription: description,
-	}
+	return AnomalyScope{Scope: req.Scope, TargetDescription: req.Scope.Description()}
 }
 
 // buildFeatureInfo builds the feature information section
-func (h *AnomalyHandler) buildFeatureInfo() FeatureInfo {
-	// Generate all feature names
-	allFeatureNames := make([]string, 0, 45)
-	for _, metric := range baseMetrics {
-		for _, feature := range featureNames {
-			allFeatureNames = append(allFeatureNames, fmt.Sprintf("%s_%s", metric, feature))
-		}
-	}
-
-	return FeatureInfo{
+func (h *AnomalyHandler) buildFeatureInfo(model string) FeatureInfo {
+	info := FeatureInfo{
 		TotalFeatures:     45,
 		BaseMetrics:       baseMetrics,
 		FeaturesPerMetric: 9,
-		FeatureNames:      allFeatureNames,
+		FeatureNames:      AnomalyFeatureNames(),
+	}
+	if histograms := h.modelHistogramFeatures(model); len(histograms) > 0 {
+		info.FeatureNames = append(info.FeatureNames, HistogramFeatureNames(histograms)...)
+		info.TotalFeatures = len(info.FeatureNames)
+		for _, histogram := range histograms {
+			info.HistogramFeatures = append(info.HistogramFeatures, histogram.Name)
+		}
+	}
+	if h.routeModels[model] {
+		info.FeatureNames = append(info.FeatureNames, RouteFeatureNames()...)
+		info.TotalFeatures = len(info.FeatureNames)
+		for _, route := range routeFeatures {
+			info.RouteFeatures = append(info.RouteFeatures, route.name)
+		}
 	}
+	return info
 }
 
 // buildSummary builds the analysis summary
@@ -717,6 +1234,17 @@ func (h *AnomalyHandler) generateRecommendation(anomalies []AnomalyResult, summa
 		return "No anomalies detected. System operating normally."
 	}
 
+	expected := 0
+	for _, a := range anomalies {
+		if a.ExpectedChurn {
+			expected++
+		}
+	}
+	if expected == len(anomalies) {
+		return fmt.Sprintf("INFO: %d anomalies coincide with a MachineConfigPool update and are likely expected churn. Wait for the update to complete before remediating.",
+			len(anomalies))
+	}
+
 	// Check for critical anomalies
 	hasCritical := false
 	for _, a := range anomalies {
@@ -750,12 +1278,13 @@ func (h *AnomalyHandler) respondJSON(w http.ResponseWriter, statusCode int, data
 }
 
 // respondError writes an error response
-func (h *AnomalyHandler) respondError(w http.ResponseWriter, statusCode int, message, details, code string) {
+func (h *AnomalyHandler) respondError(w http.ResponseWriter, statusCode int, message, details, code string, fieldErrs ...validation.FieldError) {
 	response := AnomalyErrorResponse{
 		Status:  "error",
 		Error:   message,
 		Details: details,
 		Code:    code,
+		Errors:  fieldErrs,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -766,6 +1295,328 @@ func (h *AnomalyHandler) respondError(w http.ResponseWriter, statusCode int, mes
 	}
 }
 
+// annotateRecentChanges marks anomalies that begin shortly after a deployment rollout.
+// A rollout is the most common cause of a sudden behaviour change, so we surface it
+// in the explanation and offer a rollback as an alternative action.
+func (h *AnomalyHandler) annotateRecentChanges(ctx context.Context, req *AnomalyAnalyzeRequest, response *AnomalyAnalyzeResponse, now time.Time) {
+	if h.rolloutDetector == nil || len(response.Anomalies) == 0 {
+		return
+	}
+
+	changes, err := h.rolloutDetector.RecentRollouts(ctx, req.Namespace, req.Deployment, req.Pod, now)
+	if err != nil {
+		h.log.WithError(err).Warn("Failed to check for recent rollouts")
+		return
+	}
+	if len(changes) == 0 {
+		return
+	}
+
+	for i := range response.Anomalies {
+		applyRolloutContext(&response.Anomalies[i], changes)
+	}
+
+	h.log.WithFields(logrus.Fields{
+		"namespace": req.Namespace,
+		"rollouts":  len(changes),
+	}).Info("Anomaly correlated with recent deployment change")
+}
+
+// applyRolloutContext adds rollout context to a single anomaly result
+func applyRolloutContext(anomaly *AnomalyResult, changes []detector.RolloutChange) {
+	latest := changes[0]
+	note := fmt.Sprintf("recent deployment change detected (deployment '%s' revision %d rolled out %s ago",
+		latest.Deployment, latest.Revision, time.Since(latest.ChangedAt).Round(time.Minute))
+	if latest.ImageChanged {
+		note += ", image changed"
+	}
+	note += ")"
+
+	anomaly.RecentChanges = changes
+	anomaly.Explanation = anomaly.Explanation + "; " + note
+	anomaly.AlternativeActions = append(anomaly.AlternativeActions, "rollback_deployment")
+}
+
+// annotateMachineConfigUpdates downgrades anomalies that coincide with a MachineConfigPool
+// update. The MCO cordons, drains and reboots nodes one at a time, so restarts and
+// evictions are expected churn that must not be "remediated".
+func (h *AnomalyHandler) annotateMachineConfigUpdates(ctx context.Context, req *AnomalyAnalyzeRequest, response *AnomalyAnalyzeResponse) {
+	if h.mcpDetector == nil || len(response.Anomalies) == 0 {
+		return
+	}
+
+	updates, err := h.mcpDetector.UpdatesAffecting(ctx, req.Namespace, req.Pod, "")
+	if err != nil {
+		h.log.WithError(err).Warn("Failed to check for MachineConfigPool updates")
+		return
+	}
+	if len(updates) == 0 {
+		return
+	}
+
+	for i := range response.Anomalies {
+		applyMachineConfigUpdateContext(&response.Anomalies[i], updates)
+		response.Anomalies[i].Priority = h.severity.Lower(response.Anomalies[i].Priority)
+		detector.RecordExpectedChurn("anomaly")
+	}
+	response.Recommendation = h.generateRecommendation(response.Anomalies, response.Summary)
+
+	h.log.WithFields(logrus.Fields{
+		"namespace": req.Namespace,
+		"pools":     len(updates),
+		"anomalies": len(response.Anomalies),
+	}).Info("Anomalies downgraded during MachineConfigPool update")
+}
+
+// applyMachineConfigUpdateContext marks a single anomaly as expected churn
+func applyMachineConfigUpdateContext(anomaly *AnomalyResult, updates []detector.MachineConfigUpdate) {
+	pools := make([]string, 0, len(updates))
+	for _, u := range updates {
+		pools = append(pools, fmt.Sprintf("'%s' %d/%d machines updated", u.Pool, u.UpdatedMachines, u.MachineCount))
+	}
+
+	anomaly.MachineConfigUpdates = updates
+	anomaly.ExpectedChurn = true
+	anomaly.Severity = lowerSeverity(anomaly.Severity)
+	anomaly.Explanation += "; MachineConfigPool update in progress (" + strings.Join(pools, ", ") +
+		"), node drains and reboots are expected"
+	if anomaly.RecommendedAction != "" && anomaly.RecommendedAction != "monitor" {
+		anomaly.AlternativeActions = append(anomaly.AlternativeActions, anomaly.RecommendedAction)
+	}
+	anomaly.RecommendedAction = "wait_for_machineconfig_update"
+}
+
+// annotateBusinessWindows marks anomalies found during business windows as expected
+// load, lowering their severity when a window asks for it
+func (h *AnomalyHandler) annotateBusinessWindows(response *AnomalyAnalyzeResponse, windows []anomaly.ActiveWindow) {
+	if len(windows) == 0 || len(response.Anomalies) == 0 {
+		return
+	}
+
+	names := make([]string, 0, len(windows))
+	lower := false
+	for _, window := range windows {
+		names = append(names, window.String())
+		lower = lower || window.LowerSeverity
+	}
+	for i := range response.Anomalies {
+		result := &response.Anomalies[i]
+		result.ExpectedLoad = true
+		if lower {
+			result.Severity = lowerSeverity(result.Severity)
+			result.Priority = h.severity.Lower(result.Priority)
+		}
+		result.Explanation += "; during expected busy period (" + strings.Join(names, ", ") + ")"
+	}
+	if lower {
+		response.Recommendation = h.generateRecommendation(response.Anomalies, response.Summary)
+	}
+}
+
+// applyPostFilters runs the anomalies through the post-filter chain, moving those it
+// drops or holds back to Filtered and lowering the severity and priority of the others
+// as the filters say
+func (h *AnomalyHandler) applyPostFilters(ctx context.Context, req *AnomalyAnalyzeRequest, response *AnomalyAnalyzeResponse, at time.Time, features []float64) {
+	if h.postFilters == nil {
+		return
+	}
+	response.PostFilters = h.postFilters.Names()
+
+	severities := make([]string, len(response.Anomalies))
+	for i := range response.Anomalies {
+		severities[i] = response.Anomalies[i].Severity
+	}
+	verdicts := h.postFilters.Apply(ctx, anomaly.FilterSubject{
+		Namespace:   req.Namespace,
+		Deployment:  req.Deployment,
+		Pod:         req.Pod,
+		Model:       req.ModelName,
+		At:          at,
+		Retroactive: req.At != "",
+	}, severities)
+
+	kept := response.Anomalies[:0]
+	for i, verdict := range verdicts {
+		result := response.Anomalies[i]
+		for range verdict.LoweredBy {
+			result.Priority = h.severity.Lower(result.Priority)
+		}
+		result.Severity = verdict.Severity
+		result.LoweredBy = verdict.LoweredBy
+		if !verdict.Keep {
+			response.Filtered = append(response.Filtered, FilteredAnomaly{
+				AnomalyResult: result,
+				Filter:        verdict.Filter,
+				Action:        verdict.Action,
+				Reason:        verdict.Reason,
+			})
+			continue
+		}
+		kept = append(kept, result)
+	}
+	response.Anomalies = kept
+	response.AnomaliesDetected = len(kept)
+	response.Summary = h.buildSummary(kept, features)
+	response.Recommendation = h.generateRecommendation(kept, response.Summary)
+	for _, filtered := range response.Filtered {
+		h.log.WithFields(logrus.Fields{
+			"namespace": req.Namespace,
+			"filter":    filtered.Filter,
+			"action":    filtered.Action,
+		}).Debug("Anomaly held back by post-filter")
+	}
+}
+
+// prioritizeAnomalies assesses every anomaly with the severity matrix, weighing its
+// score with the tier of the namespace, the number of running pods in scope and the SLO
+// burn of the scope's least available route
+func (h *AnomalyHandler) prioritizeAnomalies(ctx context.Context, req *AnomalyAnalyzeRequest, response *AnomalyAnalyzeResponse, pods *anomaly.PodScope) {
+	if len(response.Anomalies) == 0 {
+		return
+	}
+
+	var factors severity.Factors
+	if h.tiers != nil && req.Namespace != "" {
+		factors.Tier = h.tiers(ctx, req.Namespace)
+	}
+	if pods != nil {
+		factors.BlastRadius = len(pods.Running)
+	}
+	for _, route := range response.ExternalAvailability {
+		factors.BurnRate = math.Max(factors.BurnRate, h.severity.BurnRate(route.Availability))
+	}
+
+	for i := range response.Anomalies {
+		result := &response.Anomalies[i]
+		factors.Score = result.AnomalyScore
+		assessment := h.severity.Assess(factors)
+		result.Severity = anomalySeverity(assessment.Severity)
+		result.Priority = assessment.Priority
+	}
+	response.Recommendation = h.generateRecommendation(response.Anomalies, response.Summary)
+}
+
+// anomalySeverity maps a severity matrix band to an anomaly severity: critical,
+// warning (high) or info (medium and low)
+func anomalySeverity(band models.IncidentSeverity) string {
+	switch band {
+	case models.IncidentSeverityCritical:
+		return "critical"
+	case models.IncidentSeverityHigh:
+		return "warning"
+	default:
+		return "info"
+	}
+}
+
+// lowerSeverity returns the next lower anomaly severity
+func lowerSeverity(severity string) string {
+	switch severity {
+	case "critical":
+		return "warning"
+	default:
+		return "info"
+	}
+}
+
+// SetSeverityMatrix replaces the default severity matrix. With tiers, the criticality
+// tier of the request namespace weighs into each anomaly's priority.
+func (h *AnomalyHandler) SetSeverityMatrix(matrix *severity.Matrix, tiers anomaly.TierLookup) {
+	h.severity = matrix
+	h.tiers = tiers
+}
+
+// SetMachineConfigUpdateDetector enables downgrading of anomalies during MachineConfigPool updates
+func (h *AnomalyHandler) SetMachineConfigUpdateDetector(mcpDetector *detector.MachineConfigUpdateDetector) {
+	h.mcpDetector = mcpDetector
+}
+
+// SetUpgradeMonitor raises the anomaly threshold while the cluster is upgrading
+func (h *AnomalyHandler) SetUpgradeMonitor(monitor *upgrade.Monitor) {
+	h.upgrade = monitor
+}
+
+// SetScopeDefaults selects the model and threshold of requests that omit them by
+// namespace and criticality tier
+func (h *AnomalyHandler) SetScopeDefaults(defaults *anomaly.ScopeDefaults) {
+	h.scopeDefaults = defaults
+}
+
+// SetTenants applies the thresholds and schedules of the tenant onboarding a
+// request's namespace; they take precedence over scope defaults and business calendars
+func (h *AnomalyHandler) SetTenants(tenants Tenants) {
+	h.tenants = tenants
+}
+
+// SetScopeResolver excludes pending, terminating and completed pods from namespace and
+// deployment scopes
+func (h *AnomalyHandler) SetScopeResolver(resolver *anomaly.ScopeResolver) {
+	h.scopeResolver = resolver
+}
+
+// SetBusinessCalendars takes the business cycles of namespaces into account: during
+// their windows the threshold is raised and anomalies are marked as expected load
+func (h *AnomalyHandler) SetBusinessCalendars(calendars *anomaly.BusinessCalendars) {
+	h.calendars = calendars
+}
+
+// SetHistogramFeatures appends histogram quantile features, e.g. p99 request latency
+// of the scope, to the input of the named models. The models must be trained on the
+// 45 base features followed by HistogramFeatureNames(features).
+func (h *AnomalyHandler) SetHistogramFeatures(features []integrations.HistogramFeature, models []string) {
+	h.histogramFeatures = features
+	h.histogramModels = make(map[string]bool, len(models))
+	for _, model := range models {
+		h.histogramModels[model] = true
+	}
+}
+
+// SetFeatureFlags skips the histogram and route feature groups in namespaces where
+// their flags are disabled; their features take default values
+func (h *AnomalyHandler) SetFeatureFlags(flags *featureflag.Flags) {
+	h.flags = flags
+}
+
+// SetRedactor masks credentials in anomaly explanations
+func (h *AnomalyHandler) SetRedactor(redactor *redact.Redactor) {
+	h.redactor = redactor
+}
+
+// redactExplanations masks credentials that rollout or node context brought into explanations
+func (h *AnomalyHandler) redactExplanations(response *AnomalyAnalyzeResponse) {
+	if h.redactor == nil {
+		return
+	}
+	for i := range response.Anomalies {
+		response.Anomalies[i].Explanation = h.redactor.Redact(redact.SourceExplanation, response.Anomalies[i].Explanation)
+	}
+}
+
+// SetPostFilters sets the filters applied to detected anomalies, in order
+func (h *AnomalyHandler) SetPostFilters(filters *anomaly.PostFilters) {
+	h.postFilters = filters
+}
+
+// SetScoringPipeline runs site-specific hooks before and after model inference
+func (h *AnomalyHandler) SetScoringPipeline(pipeline *scoring.Pipeline) {
+	h.scoring = pipeline
+}
+
+// scoringHookFailed reports a failed scoring hook
+func scoringHookFailed(err error) *RequestError {
+	return &RequestError{
+		StatusCode: http.StatusInternalServerError,
+		Message:    "Scoring hook failed",
+		Details:    err.Error(),
+		Code:       ErrCodeAnomalyAnalysisFailed,
+	}
+}
+
+// SetRolloutDetector enables correlation of anomalies with recent deployment rollouts
+func (h *AnomalyHandler) SetRolloutDetector(rolloutDetector *detector.RolloutDetector) {
+	h.rolloutDetector = rolloutDetector
+}
+
 // SetPrometheusClient sets the Prometheus client (useful for testing)
 func (h *AnomalyHandler) SetPrometheusClient(client *integrations.PrometheusClient) {
 	h.prometheusClient = client
@@ -779,6 +1630,29 @@ func GetBaseMetrics() []string {
 	return result
 }
 
+// HistogramFeatureNames returns the input features of histogram quantiles in model
+// order, 9 per histogram like the base metrics
+func HistogramFeatureNames(histograms []integrations.HistogramFeature) []string {
+	names := make([]string, 0, len(histograms)*len(featureNames))
+	for _, histogram := range histograms {
+		for _, feature := range featureNames {
+			names = append(names, fmt.Sprintf("%s_%s", histogram.Name, feature))
+		}
+	}
+	return names
+}
+
+// AnomalyFeatureNames returns the 45 anomaly-detector input features in model order
+func AnomalyFeatureNames() []string {
+	names := make([]string, 0, len(baseMetrics)*len(featureNames))
+	for _, metric := range baseMetrics {
+		for _, feature := range featureNames {
+			names = append(names, fmt.Sprintf("%s_%s", metric, feature))
+		}
+	}
+	return names
+}
+
 // GetFeatureNames returns the list of feature names per metric
 func GetFeatureNames() []string {
 	result := make([]string, len(featureNames))
@@ -0,0 +1,47 @@
+package v1
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/integrations"
+)
+
+// APIServerAnomaliesResponse is the per-verb API server request rates compared with
+// their learned baselines, and the verbs far above them
+type APIServerAnomaliesResponse struct {
+	Status            string `json:"status"`
+	AnomaliesDetected int    `json:"anomalies_detected"`
+	EvaluatedAt       string `json:"evaluated_at"`
+	integrations.APIServerVerbAnalysis
+}
+
+// GetAPIServerAnomalies handles GET /api/v1/anomalies/apiserver
+// @Summary Detect per-verb API server anomalies
+// @Description Compares the current API server request rate of each verb with its mean and standard deviation over the last 7 days, and reports verbs far above them, such as a LIST storm or WATCH churn, with the clients sending the most requests
+// @Tags anomaly
+// @Produce json
+// @Success 200 {object} APIServerAnomaliesResponse
+// @Failure 500 {object} AnomalyErrorResponse
+// @Failure 503 {object} AnomalyErrorResponse
+// @Router /api/v1/anomalies/apiserver [get]
+func (h *AnomalyHandler) GetAPIServerAnomalies(w http.ResponseWriter, r *http.Request) {
+	if h.prometheusClient == nil || !h.prometheusClient.IsAvailable() {
+		h.respondError(w, http.StatusServiceUnavailable, "Prometheus is not available", "", ErrCodeAnomalyPrometheusUnavailable)
+		return
+	}
+
+	analysis, err := h.prometheusClient.AnalyzeAPIServerVerbs(r.Context())
+	if err != nil {
+		h.log.WithError(err).Warn("API server verb analysis failed")
+		h.respondError(w, http.StatusInternalServerError, "API server verb analysis failed", err.Error(), ErrCodeAnomalyAnalysisFailed)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, APIServerAnomaliesResponse{
+		Status:                "success",
+		AnomaliesDetected:     len(analysis.Anomalies),
+		EvaluatedAt:           h.now().UTC().Format(time.RFC3339),
+		APIServerVerbAnalysis: *analysis,
+	})
+}
@@ -0,0 +1,373 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/integrations"
+	"github.com/tosin2013/openshift-coordination-engine/internal/promql"
+	"github.com/tosin2013/openshift-coordination-engine/internal/severity"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/api/validation"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/diagnostics"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/kserve"
+)
+
+// DefaultControlPlaneModel is the model of control-plane analyses when none is configured
+const DefaultControlPlaneModel = "control-plane-anomaly-detector"
+
+// controlPlaneMetric is a control-plane health signal of the control-plane feature set
+type controlPlaneMetric struct {
+	name     string
+	template string
+
+	// degraded is the value at which the signal alone indicates a degraded control
+	// plane; the heuristic score weighs the ratio to it
+	degraded float64
+	weight   float64
+
+	// issue describes the signal's value in explanations, action is recommended when
+	// it is the most degraded signal
+	issue  string
+	action string
+}
+
+// controlPlaneMetrics are the control-plane signals in model order, 9 features each
+// like the base metrics. They are cluster-wide, so analyses have no scope.
+var controlPlaneMetrics = []controlPlaneMetric{
+	{
+		name: "apiserver_request_latency_p99", template: "apiserver.request_latency_p99",
+		degraded: 1, weight: 0.3,
+		issue: "API server p99 latency %.2fs", action: "investigate_apiserver_load",
+	},
+	{
+		name: "etcd_wal_fsync_p99", template: "etcd.wal_fsync_p99",
+		degraded: 0.5, weight: 0.3,
+		issue: "etcd WAL fsync p99 %.3fs", action: "check_etcd_disk_latency",
+	},
+	{
+		name: "scheduler_pending_pods", template: "scheduler.pending_pods",
+		degraded: 50, weight: 0.2,
+		issue: "%.0f pods pending in the scheduler queues", action: "investigate_unschedulable_pods",
+	},
+	{
+		name: "controller_workqueue_depth", template: "controller_manager.workqueue_depth",
+		degraded: 1000, weight: 0.2,
+		issue: "%.0f items waiting in controller work queues", action: "investigate_controller_backlog",
+	},
+}
+
+// ControlPlaneAnalyzeRequest represents the request body for control-plane anomaly analysis
+type ControlPlaneAnalyzeRequest struct {
+	TimeRange string  `json:"time_range"` // Options: 1h, 6h, 24h, 7d
+	Threshold float64 `json:"threshold"`  // Anomaly score threshold (0.0-1.0)
+	ModelName string  `json:"model_name"` // KServe model to use (default: ANOMALY_CONTROL_PLANE_MODEL)
+	Debug     bool    `json:"debug"`      // Include per-stage timings and executed PromQL
+	At        string  `json:"at,omitempty"`
+}
+
+// SetControlPlaneModel sets the model of control-plane analyses whose request names none
+func (h *AnomalyHandler) SetControlPlaneModel(model string) {
+	h.controlPlaneModel = model
+}
+
+// ControlPlaneFeatureNames returns the input features of control-plane models in model order
+func ControlPlaneFeatureNames() []string {
+	names := make([]string, 0, len(controlPlaneMetrics)*len(featureNames))
+	for _, metric := range controlPlaneMetrics {
+		for _, feature := range featureNames {
+			names = append(names, fmt.Sprintf("%s_%s", metric.name, feature))
+		}
+	}
+	return names
+}
+
+// controlPlaneMetricNames returns the names of the control-plane signals
+func controlPlaneMetricNames() []string {
+	names := make([]string, 0, len(controlPlaneMetrics))
+	for _, metric := range controlPlaneMetrics {
+		names = append(names, metric.name)
+	}
+	return names
+}
+
+// AnalyzeControlPlaneAnomalies handles POST /api/v1/anomalies/analyze/control-plane
+// @Summary Analyze control-plane anomalies
+// @Description Queries API server latency, etcd WAL fsync latency, scheduler queue and controller work queue depths, builds the 36-feature control-plane vector and calls the control-plane KServe model
+// @Tags anomaly
+// @Accept json
+// @Produce json
+// @Param request body ControlPlaneAnalyzeRequest true "Control-plane anomaly analysis request"
+// @Success 200 {object} AnomalyAnalyzeResponse
+// @Failure 400 {object} AnomalyErrorResponse
+// @Failure 503 {object} AnomalyErrorResponse
+// @Router /api/v1/anomalies/analyze/control-plane [post]
+func (h *AnomalyHandler) AnalyzeControlPlaneAnomalies(w http.ResponseWriter, r *http.Request) {
+	contentType := r.Header.Get("Content-Type")
+	if contentType != "" && !strings.HasPrefix(contentType, "application/json") {
+		h.respondError(w, http.StatusBadRequest, "Content-Type must be application/json", "", ErrCodeAnomalyInvalidRequest)
+		return
+	}
+
+	var req ControlPlaneAnalyzeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log.WithError(err).Debug("Invalid control-plane anomaly analysis request format")
+		h.respondError(w, http.StatusBadRequest, "Invalid request format", err.Error(), ErrCodeAnomalyInvalidRequest, validation.DecodeFields(err)...)
+		return
+	}
+
+	response, err := h.AnalyzeControlPlane(r.Context(), &req)
+	if err != nil {
+		var requestErr *RequestError
+		if errors.As(err, &requestErr) {
+			h.respondError(w, requestErr.StatusCode, requestErr.Message, requestErr.Details, requestErr.Code, requestErr.Errors...)
+			return
+		}
+		h.respondError(w, http.StatusInternalServerError, "Control-plane anomaly analysis failed", err.Error(), ErrCodeAnomalyAnalysisFailed)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, response)
+}
+
+// AnalyzeControlPlane runs anomaly analysis of control-plane health: the API server,
+// etcd, the scheduler and the controller manager. It has its own feature set and
+// model; workload scopes, tenants and business windows do not apply.
+func (h *AnomalyHandler) AnalyzeControlPlane(ctx context.Context, req *ControlPlaneAnalyzeRequest) (*AnomalyAnalyzeResponse, error) {
+	validation.Default(&req.TimeRange, "1h")
+	validation.Default(&req.Threshold, validation.DefaultThreshold)
+	validation.Default(&req.ModelName, h.controlPlaneModel)
+	validation.Default(&req.ModelName, DefaultControlPlaneModel)
+	if err := validation.Check(
+		validation.OneOf("time_range", req.TimeRange, "1h", "6h", "24h", "7d"),
+		validation.Threshold("threshold", req.Threshold),
+	); err != nil {
+		h.log.WithError(err).Debug("Control-plane anomaly analysis request validation failed")
+		return nil, invalidRequest(err, ErrCodeAnomalyInvalidRequest)
+	}
+	now, err := parseEvaluationTime(req.At, h.now())
+	if err != nil {
+		h.log.WithError(err).Debug("Control-plane anomaly analysis request evaluation time invalid")
+		return nil, invalidRequest(err, ErrCodeAnomalyInvalidRequest)
+	}
+
+	// Control-plane components are rolled out during upgrades, so raise the threshold
+	// as for workload analyses
+	upgrading := req.At == "" && h.upgrade != nil && h.upgrade.Active()
+	if upgrading {
+		req.Threshold = h.upgrade.AdjustThreshold(req.Threshold)
+	}
+
+	h.log.WithFields(logrus.Fields{
+		"time_range": req.TimeRange,
+		"threshold":  req.Threshold,
+		"model_name": req.ModelName,
+		"at":         req.At,
+	}).Info("Processing control-plane anomaly analysis request")
+
+	if h.kserveClient == nil {
+		return nil, &RequestError{
+			StatusCode: http.StatusServiceUnavailable,
+			Message:    "KServe integration not enabled",
+			Details:    "KServe client is not configured",
+			Code:       ErrCodeAnomalyKServeUnavailable,
+		}
+	}
+	if _, exists := h.kserveClient.GetModel(req.ModelName); !exists {
+		return nil, &RequestError{
+			StatusCode: http.StatusServiceUnavailable,
+			Message:    fmt.Sprintf("Model '%s' not available", req.ModelName),
+			Details:    "Model not found in KServe",
+			Code:       ErrCodeAnomalyModelNotFound,
+		}
+	}
+
+	var trace *diagnostics.Trace
+	if req.Debug {
+		trace = diagnostics.NewTrace()
+		ctx = diagnostics.WithTrace(ctx, trace)
+	}
+	snapshot := integrations.NewSnapshot(now)
+	ctx = integrations.WithSnapshot(ctx, snapshot)
+
+	endStage := trace.StartStage("feature_engineering")
+	features, metricsData, quality, err := h.buildControlPlaneFeatures(ctx)
+	endStage()
+	if costErr, ok := integrations.IsQueryCostError(err); ok {
+		return nil, &RequestError{
+			StatusCode: http.StatusUnprocessableEntity,
+			Message:    "Query scope too broad",
+			Details:    costErr.Error(),
+			Code:       ErrCodeAnomalyQueryTooExpensive,
+		}
+	}
+	if err != nil {
+		h.log.WithError(err).Warn("Failed to build control-plane feature vector from Prometheus, using defaults")
+		features = make([]float64, 0, len(controlPlaneMetrics)*len(featureNames))
+		metricsData = make(map[string]float64, len(controlPlaneMetrics))
+		for _, metric := range controlPlaneMetrics {
+			features = append(features, h.getDefaultMetricFeatures()...)
+			metricsData[metric.name] = 0
+		}
+		quality = defaultedQuality(err, controlPlaneMetricNames()...)
+	}
+
+	endStage = trace.StartStage("model_inference")
+	resp, err := h.kserveClient.Predict(ctx, req.ModelName, [][]float64{features})
+	endStage()
+	if err != nil {
+		h.log.WithError(err).WithField("model", req.ModelName).Error("KServe control-plane anomaly detection failed")
+		if reqErr := invalidModelResponse(err, "Anomaly detection failed"); reqErr != nil {
+			return nil, reqErr
+		}
+		return nil, &RequestError{
+			StatusCode: http.StatusServiceUnavailable,
+			Message:    "Anomaly detection failed",
+			Details:    err.Error(),
+			Code:       ErrCodeAnomalyAnalysisFailed,
+		}
+	}
+
+	endStage = trace.StartStage("post_processing")
+	response := h.buildControlPlaneResponse(req, resp, features, metricsData)
+	response.DataQuality = quality
+	stampEvaluationTime(&response, snapshot.At())
+	h.redactExplanations(&response)
+	endStage()
+	if req.Debug {
+		response.Debug = trace.Report()
+	}
+	if upgrading {
+		response.UpgradeInProgress = true
+		response.AppliedThreshold = req.Threshold
+	}
+
+	h.log.WithFields(logrus.Fields{
+		"anomalies_detected": response.AnomaliesDetected,
+		"max_score":          response.Summary.MaxScore,
+		"model":              response.ModelUsed,
+	}).Info("Control-plane anomaly analysis completed successfully")
+
+	return &response, nil
+}
+
+// buildControlPlaneFeatures builds the control-plane feature vector, 9 rolling features
+// per signal. Signals that cannot be queried get default features and are reported in
+// the returned data quality.
+func (h *AnomalyHandler) buildControlPlaneFeatures(ctx context.Context) ([]float64, map[string]float64, DataQuality, error) {
+	var quality DataQuality
+	if h.prometheusClient == nil || !h.prometheusClient.IsAvailable() {
+		return nil, nil, quality, errPrometheusUnavailable
+	}
+
+	queries := h.prometheusClient.Queries()
+	features := make([]float64, 0, len(controlPlaneMetrics)*len(featureNames))
+	metricsData := make(map[string]float64, len(controlPlaneMetrics))
+	for _, metric := range controlPlaneMetrics {
+		metricFeatures, err := h.queryRollingFeatures(ctx, queries.MustRender(metric.template, promql.Params{}))
+		if _, refused := integrations.IsQueryCostError(err); refused {
+			return nil, nil, quality, err
+		}
+		if err != nil {
+			h.log.WithError(err).WithField("metric", metric.name).Debug("Failed to query control-plane features, using defaults")
+			quality.addDefaulted(metric.name, nil)
+			features = append(features, h.getDefaultMetricFeatures()...)
+			metricsData[metric.name] = 0
+			continue
+		}
+		features = append(features, metricFeatures...)
+		metricsData[metric.name] = metricFeatures[0]
+	}
+	return features, metricsData, quality, nil
+}
+
+// buildControlPlaneResponse builds the analysis response from the model predictions
+func (h *AnomalyHandler) buildControlPlaneResponse(
+	req *ControlPlaneAnalyzeRequest,
+	resp *kserve.DetectResponse,
+	features []float64,
+	metricsData map[string]float64,
+) AnomalyAnalyzeResponse {
+	var anomalies []AnomalyResult
+	if len(resp.Predictions) > 0 && resp.Predictions[0] == -1 {
+		if score := controlPlaneScore(metricsData); score >= req.Threshold {
+			anomalies = append(anomalies, h.buildControlPlaneAnomaly(metricsData, score))
+		}
+	}
+
+	summary := h.buildSummary(anomalies, features)
+	summary.MetricsAnalyzed = len(controlPlaneMetrics)
+
+	return AnomalyAnalyzeResponse{
+		Status:            "success",
+		TimeRange:         req.TimeRange,
+		Scope:             AnomalyScope{TargetDescription: "control plane"},
+		ModelUsed:         req.ModelName,
+		AnomaliesDetected: len(anomalies),
+		Anomalies:         anomalies,
+		Summary:           summary,
+		Recommendation:    h.generateRecommendation(anomalies, summary),
+		Features: FeatureInfo{
+			TotalFeatures:     len(controlPlaneMetrics) * len(featureNames),
+			BaseMetrics:       controlPlaneMetricNames(),
+			FeaturesPerMetric: len(featureNames),
+			FeatureNames:      ControlPlaneFeatureNames(),
+		},
+		ModelPredictions: resp.Predictions,
+	}
+}
+
+// controlPlaneScore is the weighted degradation of the control-plane signals, each
+// capped at its degraded level
+func controlPlaneScore(metrics map[string]float64) float64 {
+	score := 0.0
+	for _, metric := range controlPlaneMetrics {
+		score += math.Min(math.Max(metrics[metric.name]/metric.degraded, 0), 1) * metric.weight
+	}
+	return math.Round(score*100) / 100
+}
+
+// buildControlPlaneAnomaly creates the anomaly of a control-plane analysis, explained by
+// the signals at their degraded level and remediated from the most degraded one
+func (h *AnomalyHandler) buildControlPlaneAnomaly(metrics map[string]float64, score float64) AnomalyResult {
+	assessment := h.severity.Assess(severity.Factors{Score: score})
+	level := anomalySeverity(assessment.Severity)
+
+	var issues []string
+	action, worst := "", 1.0
+	for _, metric := range controlPlaneMetrics {
+		ratio := metrics[metric.name] / metric.degraded
+		if ratio < 1 {
+			continue
+		}
+		issues = append(issues, fmt.Sprintf(metric.issue, metrics[metric.name]))
+		if ratio >= worst {
+			action, worst = metric.action, ratio
+		}
+	}
+	explanation := "Anomalous control-plane behavior detected based on metric patterns"
+	if len(issues) > 0 {
+		explanation = strings.Join(issues, "; ")
+	}
+	if action == "" {
+		action = h.recommendAction(nil, level)
+	}
+
+	return AnomalyResult{
+		Timestamp:         h.now().UTC().Format(time.RFC3339),
+		Severity:          level,
+		Priority:          assessment.Priority,
+		AnomalyScore:      score,
+		Confidence:        0.87, // Base confidence from model
+		Metrics:           metrics,
+		Explanation:       explanation,
+		RecommendedAction: action,
+	}
+}
@@ -0,0 +1,159 @@
+package v1
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/integrations"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/kserve"
+)
+
+func TestAnalyzeControlPlane(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	var instances [][]float64
+	model := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Instances [][]float64 `json:"instances"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		instances = req.Instances
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"predictions": []int{-1}})
+	}))
+	defer model.Close()
+
+	// The API server is slow and etcd fsyncs take 0.8s; scheduler metrics are missing
+	prometheus := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query().Get("query")
+		value := ""
+		switch {
+		case strings.Contains(query, "apiserver_request_duration_seconds_bucket"):
+			value = "2.5"
+		case strings.Contains(query, "etcd_disk_wal_fsync_duration_seconds_bucket"):
+			value = "0.8"
+		case strings.Contains(query, "workqueue_depth"):
+			value = "200"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if value == "" {
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[]}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1700000000,"` + value + `"]}]}}`))
+	}))
+	defer prometheus.Close()
+
+	modelsFile := filepath.Join(t.TempDir(), "models.yaml")
+	require.NoError(t, os.WriteFile(modelsFile, []byte("models:\n  control-plane-anomaly-detector: "+model.URL+"\n"), 0o600))
+	kserveClient, err := kserve.NewProxyClient(kserve.ProxyConfig{Namespace: "test-ns", ModelsFile: modelsFile}, log)
+	require.NoError(t, err)
+
+	handler := NewAnomalyHandler(kserveClient, integrations.NewPrometheusClient(prometheus.URL, 5*time.Second, log), log)
+	router := mux.NewRouter()
+	handler.RegisterRoutes(router)
+
+	req := httptest.NewRequest("POST", "/api/v1/anomalies/analyze/control-plane", bytes.NewBufferString(`{"threshold": 0.5}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp AnomalyAnalyzeResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, DefaultControlPlaneModel, resp.ModelUsed)
+	assert.Equal(t, "control plane", resp.Scope.TargetDescription)
+	assert.Equal(t, 36, resp.Features.TotalFeatures)
+	assert.Equal(t, ControlPlaneFeatureNames(), resp.Features.FeatureNames)
+	assert.Equal(t, []string{"scheduler_pending_pods"}, resp.DataQuality.DefaultedMetrics)
+	require.Len(t, instances, 1)
+	require.Len(t, instances[0], 36)
+	assert.Equal(t, 2.5, instances[0][0], "API server latency value")
+	assert.Equal(t, 0.8, instances[0][9], "etcd fsync value")
+
+	require.Equal(t, 1, resp.AnomaliesDetected)
+	anomaly := resp.Anomalies[0]
+	assert.Equal(t, 0.64, anomaly.AnomalyScore)
+	assert.Equal(t, "info", anomaly.Severity)
+	assert.Equal(t, "API server p99 latency 2.50s; etcd WAL fsync p99 0.800s", anomaly.Explanation)
+	assert.Equal(t, "investigate_apiserver_load", anomaly.RecommendedAction, "the API server is the most degraded")
+
+	// A configured model replaces the default
+	handler.SetControlPlaneModel("control-plane-v2")
+	_, err = handler.AnalyzeControlPlane(req.Context(), &ControlPlaneAnalyzeRequest{})
+	var requestErr *RequestError
+	require.ErrorAs(t, err, &requestErr)
+	assert.Equal(t, ErrCodeAnomalyModelNotFound, requestErr.Code)
+	assert.Contains(t, requestErr.Message, "control-plane-v2")
+
+	_, err = handler.AnalyzeControlPlane(req.Context(), &ControlPlaneAnalyzeRequest{TimeRange: "2h"})
+	require.ErrorAs(t, err, &requestErr)
+	assert.Equal(t, ErrCodeAnomalyInvalidRequest, requestErr.Code)
+}
+
+func TestControlPlaneScore(t *testing.T) {
+	assert.Zero(t, controlPlaneScore(map[string]float64{}))
+	assert.Equal(t, 1.0, controlPlaneScore(map[string]float64{
+		"apiserver_request_latency_p99": 5,
+		"etcd_wal_fsync_p99":            1,
+		"scheduler_pending_pods":        500,
+		"controller_workqueue_depth":    5000,
+	}), "each signal is capped at its degraded level")
+	assert.Equal(t, 0.15, controlPlaneScore(map[string]float64{"etcd_wal_fsync_p99": 0.25}))
+}
+
+func TestGetAPIServerAnomalies(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	// LIST requests at 10x their baseline; every other verb at its baseline
+	prometheus := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query().Get("query")
+		result := `{"metric":{},"value":[1700000000,"40"]}`
+		switch {
+		case strings.HasPrefix(query, "avg_over_time"), strings.HasPrefix(query, "stddev_over_time"):
+			result = `{"metric":{"verb":"LIST"},"value":[1700000000,"40"]}`
+		case strings.HasPrefix(query, "topk"):
+			result = `{"metric":{"client":"rogue-operator/v0.1"},"value":[1700000000,"360"]}`
+		case strings.Contains(query, `verb="LIST"`):
+			result = `{"metric":{},"value":[1700000000,"400"]}`
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[` + result + `]}}`))
+	}))
+	defer prometheus.Close()
+
+	router := mux.NewRouter()
+	NewAnomalyHandler(nil, integrations.NewPrometheusClient(prometheus.URL, 5*time.Second, log), log).RegisterRoutes(router)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/anomalies/apiserver", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp APIServerAnomaliesResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, "success", resp.Status)
+	assert.Equal(t, "7d", resp.BaselineWindow)
+	require.Equal(t, 1, resp.AnomaliesDetected)
+	assert.Equal(t, integrations.APIServerListStorm, resp.Anomalies[0].Kind)
+	assert.Equal(t, []integrations.APIServerClient{{Client: "rogue-operator/v0.1", QPS: 360}}, resp.Anomalies[0].TopClients)
+
+	// Without Prometheus
+	router = mux.NewRouter()
+	NewAnomalyHandler(nil, nil, log).RegisterRoutes(router)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/anomalies/apiserver", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
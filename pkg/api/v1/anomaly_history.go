@@ -0,0 +1,417 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/featureflag"
+	"github.com/tosin2013/openshift-coordination-engine/internal/featurehistory"
+	"github.com/tosin2013/openshift-coordination-engine/internal/integrations"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/api/validation"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/kserve"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/scoring"
+)
+
+const (
+	// rescoreBatchSize bounds the feature vectors sent to a model in one request when
+	// re-scoring history
+	rescoreBatchSize = 240
+
+	// featureSampleTimeout bounds sampling the feature vector of one namespace
+	featureSampleTimeout = 30 * time.Second
+)
+
+// Error codes for feature history requests
+const (
+	ErrCodeFeatureHistoryDisabled = "FEATURE_HISTORY_DISABLED"
+	ErrCodeFeatureHistoryEmpty    = "FEATURE_HISTORY_EMPTY"
+	ErrCodeModelVersionNotFound   = "MODEL_VERSION_NOT_FOUND"
+)
+
+// FeatureHistoryNamespaces lists the namespaces whose feature vectors are sampled
+// into the feature history, e.g. the tenant registry's onboarded namespaces
+type FeatureHistoryNamespaces interface {
+	OnboardedNamespaces(ctx context.Context) ([]string, error)
+}
+
+// FeatureHistoryResponse lists the scopes with retained feature vectors
+type FeatureHistoryResponse struct {
+	Retention  string                       `json:"retention"`
+	Resolution string                       `json:"resolution"`
+	Scopes     []featurehistory.ScopeStatus `json:"scopes"`
+	Count      int                          `json:"count"`
+}
+
+// FeatureRescoreRequest is the body of POST /api/v1/anomalies/rescore
+type FeatureRescoreRequest struct {
+	// Namespace, deployment or pod whose history is re-scored
+	models.Scope
+
+	// ModelName is the model whose input was recorded (default: as for analyze)
+	ModelName string `json:"model_name,omitempty"`
+
+	// Version is the rollout version to score the history with
+	Version string `json:"version"`
+
+	// Baseline is the rollout version to compare with (default: the pinned version)
+	Baseline string `json:"baseline,omitempty"`
+
+	// Since limits the history to the last duration, e.g. "6h" (default: all of it)
+	Since string `json:"since,omitempty"`
+}
+
+// FeatureRescoreResponse compares the decisions of two model versions on the
+// retained feature history of a scope
+type FeatureRescoreResponse struct {
+	Scope     models.Scope `json:"scope"`
+	ModelName string       `json:"model_name"`
+	Version   string       `json:"version"`
+	Baseline  string       `json:"baseline"`
+
+	// Samples is the number of feature vectors re-scored, evaluated From to To
+	Samples int       `json:"samples"`
+	From    time.Time `json:"from"`
+	To      time.Time `json:"to"`
+
+	// VersionAnomalies and BaselineAnomalies count the vectors each version labels
+	// anomalous; Agreement is the fraction of vectors both label alike
+	VersionAnomalies  int     `json:"version_anomalies"`
+	BaselineAnomalies int     `json:"baseline_anomalies"`
+	Agreement         float64 `json:"agreement"`
+
+	// Changed lists the vectors the versions label differently, oldest first
+	Changed []RescoredDecision `json:"changed"`
+}
+
+// RescoredDecision is a feature vector the compared versions label differently
+type RescoredDecision struct {
+	Timestamp         time.Time `json:"timestamp"`
+	VersionAnomalous  bool      `json:"version_anomalous"`
+	BaselineAnomalous bool      `json:"baseline_anomalous"`
+}
+
+// SetFeatureHistory keeps the model input of live analyses of onboarded scopes in
+// history, so new model versions can be compared on it with POST
+// /api/v1/anomalies/rescore. StartFeatureHistory additionally samples the namespaces
+// listed by namespaces every resolution step. Without a tenant registry (see
+// SetTenants) every live analysis is kept.
+func (h *AnomalyHandler) SetFeatureHistory(history *featurehistory.History, namespaces FeatureHistoryNamespaces) {
+	h.featureHistory = history
+	h.historyNamespaces = namespaces
+}
+
+// recordFeatures keeps the model input of a live analysis of an onboarded scope
+func (h *AnomalyHandler) recordFeatures(ctx context.Context, req *AnomalyAnalyzeRequest, at time.Time, features []float64) {
+	if h.featureHistory == nil || req.At != "" || req.readOnly {
+		return
+	}
+	if h.tenants != nil && h.tenantFor(ctx, req.Namespace) == nil {
+		return
+	}
+	h.featureHistory.Record(featureHistoryKey(req.ModelName, req.Scope), at, features)
+}
+
+func featureHistoryKey(model string, scope models.Scope) featurehistory.Key {
+	return featurehistory.Key{Model: model, Namespace: scope.Namespace, Deployment: scope.Deployment, Pod: scope.Pod}
+}
+
+// StartFeatureHistory samples the feature vectors of the listed namespaces every
+// resolution step until ctx is cancelled
+func (h *AnomalyHandler) StartFeatureHistory(ctx context.Context) {
+	if h.featureHistory == nil || h.historyNamespaces == nil {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(h.featureHistory.Resolution())
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				h.sampleFeatureHistory(ctx)
+			}
+		}
+	}()
+	h.log.WithFields(logrus.Fields{
+		"retention":  h.featureHistory.Retention(),
+		"resolution": h.featureHistory.Resolution(),
+	}).Info("Feature history sampling started")
+}
+
+// sampleFeatureHistory records the feature vector of every listed namespace
+func (h *AnomalyHandler) sampleFeatureHistory(ctx context.Context) {
+	namespaces, err := h.historyNamespaces.OnboardedNamespaces(ctx)
+	if err != nil {
+		h.log.WithError(err).Warn("Failed to list all onboarded namespaces for the feature history")
+	}
+	for _, namespace := range namespaces {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := h.sampleFeatures(ctx, namespace); err != nil {
+			h.log.WithError(err).WithField("namespace", namespace).Debug("Skipped feature history sample")
+		}
+	}
+}
+
+// sampleFeatures records the model input an analysis of a namespace would use now.
+// Vectors that could not be built from Prometheus are not recorded.
+func (h *AnomalyHandler) sampleFeatures(ctx context.Context, namespace string) error {
+	ctx, cancel := context.WithTimeout(ctx, featureSampleTimeout)
+	defer cancel()
+
+	req := &AnomalyAnalyzeRequest{Scope: models.Scope{Namespace: namespace}}
+	h.applyScopeDefaults(ctx, req)
+	h.setRequestDefaults(req)
+	req.Scope = req.Scope.Resolve()
+
+	snapshot := integrations.NewSnapshot(h.now())
+	ctx = integrations.WithSnapshot(ctx, snapshot)
+	ctx = featureflag.WithNamespace(ctx, namespace)
+
+	pods := h.resolvePods(ctx, req)
+	routes := h.resolveRoutes(ctx, req)
+	features, metricsData, _, err := h.buildFeatureVector(ctx, req.ModelName, req.Namespace, req.Pod, req.Deployment, pods, routes)
+	if err != nil {
+		return err
+	}
+	input := &scoring.Input{
+		Namespace:    req.Namespace,
+		Model:        req.ModelName,
+		At:           snapshot.At(),
+		FeatureNames: h.buildFeatureInfo(req.ModelName).FeatureNames,
+		Features:     features,
+		Metrics:      metricsData,
+	}
+	if err := h.scoring.Before(ctx, input); err != nil {
+		return err
+	}
+	h.recordFeatures(ctx, req, snapshot.At(), input.Features)
+	return nil
+}
+
+// GetFeatureHistory handles GET /api/v1/anomalies/history
+// @Summary List the scopes with retained feature vectors
+// @ID getFeatureHistory
+// @Tags anomaly
+// @Produce json
+// @Success 200 {object} FeatureHistoryResponse
+// @Failure 503 {object} AnomalyErrorResponse
+// @Router /api/v1/anomalies/history [get]
+func (h *AnomalyHandler) GetFeatureHistory(w http.ResponseWriter, r *http.Request) {
+	if h.featureHistory == nil {
+		h.respondError(w, http.StatusServiceUnavailable, "Feature history not enabled", "Set ANOMALY_FEATURE_HISTORY=true to keep feature vectors", ErrCodeFeatureHistoryDisabled)
+		return
+	}
+	scopes := h.featureHistory.Scopes()
+	h.respondJSON(w, http.StatusOK, FeatureHistoryResponse{
+		Retention:  h.featureHistory.Retention().String(),
+		Resolution: h.featureHistory.Resolution().String(),
+		Scopes:     scopes,
+		Count:      len(scopes),
+	})
+}
+
+// RescoreFeatureHistory handles POST /api/v1/anomalies/rescore
+// @Summary Compare two model versions on the retained feature history of a scope
+// @Description Scores the scope's retained feature vectors with a rollout version and the
+// @Description baseline version, and reports where their decisions differ. Rollout stats
+// @Description are not affected.
+// @ID rescoreFeatureHistory
+// @Tags anomaly
+// @Accept json
+// @Produce json
+// @Param request body FeatureRescoreRequest true "Re-scoring request"
+// @Success 200 {object} FeatureRescoreResponse
+// @Failure 400 {object} AnomalyErrorResponse
+// @Failure 404 {object} AnomalyErrorResponse
+// @Failure 503 {object} AnomalyErrorResponse
+// @Router /api/v1/anomalies/rescore [post]
+func (h *AnomalyHandler) RescoreFeatureHistory(w http.ResponseWriter, r *http.Request) {
+	var req FeatureRescoreRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid request format", err.Error(), ErrCodeAnomalyInvalidRequest, validation.DecodeFields(err)...)
+		return
+	}
+
+	response, err := h.Rescore(r.Context(), &req)
+	if err != nil {
+		var requestErr *RequestError
+		if errors.As(err, &requestErr) {
+			h.respondError(w, requestErr.StatusCode, requestErr.Message, requestErr.Details, requestErr.Code, requestErr.Errors...)
+			return
+		}
+		h.respondError(w, http.StatusInternalServerError, "Re-scoring failed", err.Error(), ErrCodeAnomalyAnalysisFailed)
+		return
+	}
+	h.respondJSON(w, http.StatusOK, response)
+}
+
+// Rescore scores the retained feature history of a scope with two model versions and
+// compares their decisions
+func (h *AnomalyHandler) Rescore(ctx context.Context, req *FeatureRescoreRequest) (*FeatureRescoreResponse, error) {
+	if h.featureHistory == nil {
+		return nil, &RequestError{
+			StatusCode: http.StatusServiceUnavailable,
+			Message:    "Feature history not enabled",
+			Details:    "Set ANOMALY_FEATURE_HISTORY=true to keep feature vectors",
+			Code:       ErrCodeFeatureHistoryDisabled,
+		}
+	}
+	if h.kserveClient == nil {
+		return nil, &RequestError{
+			StatusCode: http.StatusServiceUnavailable,
+			Message:    "KServe integration not enabled",
+			Details:    "KServe client is not configured",
+			Code:       ErrCodeAnomalyKServeUnavailable,
+		}
+	}
+
+	// Resolve the model as an analysis of the scope would
+	analysis := &AnomalyAnalyzeRequest{Scope: req.Scope, ModelName: req.ModelName}
+	h.applyScopeDefaults(ctx, analysis)
+	h.setRequestDefaults(analysis)
+	req.ModelName = analysis.ModelName
+
+	rules := append(req.Scope.Rules(), validation.Required("version", req.Version))
+	if req.Baseline != "" && req.Baseline == req.Version {
+		rules = append(rules, func() *validation.FieldError {
+			return &validation.FieldError{Field: "baseline", Constraint: validation.ConstraintFormat, Value: req.Baseline, Message: "baseline must differ from version"}
+		})
+	}
+	var since time.Time
+	if req.Since != "" {
+		window, err := time.ParseDuration(req.Since)
+		if err != nil || window <= 0 {
+			rules = append(rules, func() *validation.FieldError {
+				return &validation.FieldError{Field: "since", Constraint: validation.ConstraintFormat, Value: req.Since, Message: "since must be a positive duration such as 6h"}
+			})
+		} else {
+			since = h.now().Add(-window)
+		}
+	}
+	if err := validation.Check(rules...); err != nil {
+		return nil, invalidRequest(err, ErrCodeAnomalyInvalidRequest)
+	}
+	req.Scope = req.Scope.Resolve()
+
+	samples := h.featureHistory.Samples(featureHistoryKey(req.ModelName, req.Scope), since)
+	if len(samples) == 0 {
+		return nil, &RequestError{
+			StatusCode: http.StatusNotFound,
+			Message:    "No feature history for scope",
+			Details:    fmt.Sprintf("no feature vectors of model %s are retained for the scope; only live analyses of onboarded scopes are kept", req.ModelName),
+			Code:       ErrCodeFeatureHistoryEmpty,
+		}
+	}
+	instances := make([][]float64, len(samples))
+	for i, sample := range samples {
+		instances[i] = sample.Features
+	}
+
+	versionLabels, _, err := h.scoreVersion(ctx, req.ModelName, req.Version, instances)
+	if err != nil {
+		return nil, err
+	}
+	baselineLabels, baseline, err := h.scoreVersion(ctx, req.ModelName, req.Baseline, instances)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &FeatureRescoreResponse{
+		Scope:     req.Scope,
+		ModelName: req.ModelName,
+		Version:   req.Version,
+		Baseline:  baseline,
+		Samples:   len(samples),
+		From:      samples[0].At,
+		To:        samples[len(samples)-1].At,
+		Changed:   []RescoredDecision{},
+	}
+	agreed := 0
+	for i, sample := range samples {
+		versionAnomalous, baselineAnomalous := versionLabels[i] == -1, baselineLabels[i] == -1
+		if versionAnomalous {
+			response.VersionAnomalies++
+		}
+		if baselineAnomalous {
+			response.BaselineAnomalies++
+		}
+		if versionAnomalous == baselineAnomalous {
+			agreed++
+			continue
+		}
+		response.Changed = append(response.Changed, RescoredDecision{
+			Timestamp:         sample.At,
+			VersionAnomalous:  versionAnomalous,
+			BaselineAnomalous: baselineAnomalous,
+		})
+	}
+	response.Agreement = float64(agreed) / float64(len(samples))
+
+	h.log.WithFields(logrus.Fields{
+		"model":     req.ModelName,
+		"version":   req.Version,
+		"baseline":  baseline,
+		"namespace": req.Namespace,
+		"samples":   len(samples),
+		"changed":   len(response.Changed),
+	}).Info("Re-scored feature history")
+	return response, nil
+}
+
+// scoreVersion labels feature vectors with a model version in batches and returns the
+// labels and the version that answered
+func (h *AnomalyHandler) scoreVersion(ctx context.Context, model, version string, instances [][]float64) ([]int, string, error) {
+	labels := make([]int, 0, len(instances))
+	answered := version
+	for start := 0; start < len(instances); start += rescoreBatchSize {
+		end := min(start+rescoreBatchSize, len(instances))
+		resp, err := h.kserveClient.PredictVersion(ctx, model, version, instances[start:end])
+		if err != nil {
+			return nil, "", rescoreFailed(err, model, version)
+		}
+		labels = append(labels, resp.Predictions...)
+		answered = resp.ModelVersion
+	}
+	return labels, answered, nil
+}
+
+// rescoreFailed maps a failed version prediction to a request error
+func rescoreFailed(err error, model, version string) *RequestError {
+	var versionErr *kserve.VersionNotFoundError
+	var modelErr *kserve.ModelNotFoundError
+	switch {
+	case errors.As(err, &versionErr):
+		return &RequestError{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("Model '%s' has no version '%s'", model, version),
+			Details:    "List the version in the model's rollout (KSERVE_MODEL_ROLLOUT_FILE)",
+			Code:       ErrCodeModelVersionNotFound,
+		}
+	case errors.As(err, &modelErr):
+		return &RequestError{
+			StatusCode: http.StatusServiceUnavailable,
+			Message:    fmt.Sprintf("Model '%s' not available", model),
+			Details:    "Model not found in KServe",
+			Code:       ErrCodeAnomalyModelNotFound,
+		}
+	}
+	if reqErr := invalidModelResponse(err, "Re-scoring failed"); reqErr != nil {
+		return reqErr
+	}
+	return &RequestError{
+		StatusCode: http.StatusServiceUnavailable,
+		Message:    "Re-scoring failed",
+		Details:    err.Error(),
+		Code:       ErrCodeAnomalyAnalysisFailed,
+	}
+}
@@ -0,0 +1,144 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/clock"
+	"github.com/tosin2013/openshift-coordination-engine/internal/featurehistory"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/kserve"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/testsupport"
+)
+
+type staticNamespaces []string
+
+func (n staticNamespaces) OnboardedNamespaces(context.Context) ([]string, error) {
+	return n, nil
+}
+
+func TestAnomalyHandler_RescoreFeatureHistory(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	prom := testsupport.NewPrometheus()
+	prom.SetDefault(0.5)
+	predictors := testsupport.NewKServe()
+	kserveClient, err := predictors.Client(log, "anomaly-detector")
+	require.NoError(t, err)
+	rollouts, err := kserve.NewRollouts(map[string]kserve.ModelRollout{
+		"anomaly-detector": {
+			Versions: map[string]string{"v1": testsupport.ModelURL("anomaly-detector-v1"), "v2": testsupport.ModelURL("anomaly-detector-v2")},
+			Pinned:   "v1",
+		},
+	})
+	require.NoError(t, err)
+	kserveClient.SetRollouts(rollouts)
+	// v2 labels vectors with high utilization anomalous
+	predictors.SetHandler("anomaly-detector-v2", func(instances [][]float64) (interface{}, error) {
+		labels := make([]int, len(instances))
+		for i, instance := range instances {
+			labels[i] = 1
+			if instance[0] > 0.8 {
+				labels[i] = -1
+			}
+		}
+		return labels, nil
+	})
+
+	start := time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC)
+	engineClock := clock.NewManual(start)
+	promClient := prom.Client(log)
+	promClient.SetClock(engineClock.Now)
+	handler := NewAnomalyHandler(kserveClient, nil, log)
+	handler.SetPrometheusClient(promClient)
+	handler.SetClock(engineClock.Now)
+	router := mux.NewRouter()
+	handler.RegisterRoutes(router)
+
+	rescore := func(body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/anomalies/rescore", bytes.NewBufferString(body)))
+		return rec
+	}
+	assert.Equal(t, http.StatusServiceUnavailable, rescore(`{"namespace":"payments","version":"v2"}`).Code, "feature history is off by default")
+
+	handler.SetFeatureHistory(featurehistory.New(featurehistory.Options{Retention: time.Hour, Resolution: time.Minute}), staticNamespaces{"payments"})
+
+	// Live analyses and the sampler record the model input of their scope once a minute
+	ctx := context.Background()
+	for _, utilization := range []float64{0.5, 0.5, 0.9} {
+		prom.SetDefault(utilization)
+		_, err := handler.Analyze(ctx, &AnomalyAnalyzeRequest{Scope: models.Scope{Namespace: "payments", Deployment: "api"}})
+		require.NoError(t, err)
+		handler.sampleFeatureHistory(ctx)
+		engineClock.Advance(time.Minute)
+	}
+	_, err = handler.Analyze(ctx, &AnomalyAnalyzeRequest{Scope: models.Scope{Namespace: "payments", Deployment: "web"}, At: start.Format(time.RFC3339)})
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/anomalies/history", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var history FeatureHistoryResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &history))
+	require.Equal(t, 2, history.Count, "retroactive analyses are not recorded")
+	assert.Equal(t, "", history.Scopes[0].Deployment)
+	assert.Equal(t, 3, history.Scopes[0].Samples)
+	assert.Equal(t, "api", history.Scopes[1].Deployment)
+	assert.Equal(t, 3, history.Scopes[1].Samples)
+
+	predictors.Reset()
+	rec = rescore(`{"namespace":"payments","deployment":"api","version":"v2"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var response FeatureRescoreResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "anomaly-detector", response.ModelName)
+	assert.Equal(t, "v1", response.Baseline)
+	assert.Equal(t, 3, response.Samples)
+	assert.Equal(t, start, response.From)
+	assert.Equal(t, start.Add(2*time.Minute), response.To)
+	assert.Equal(t, 1, response.VersionAnomalies)
+	assert.Zero(t, response.BaselineAnomalies)
+	assert.InDelta(t, 2.0/3, response.Agreement, 1e-9)
+	assert.Equal(t, []RescoredDecision{{Timestamp: start.Add(2 * time.Minute), VersionAnomalous: true}}, response.Changed)
+	assert.Equal(t, 1, predictors.Predictions("anomaly-detector-v2"), "history is scored in batches")
+	status, _ := kserveClient.RolloutStatus("anomaly-detector")
+	requests := map[string]int64{}
+	for _, version := range status.Versions {
+		requests[version.Version] = version.Requests
+	}
+	assert.Equal(t, map[string]int64{"v1": 4}, requests, "re-scoring leaves the rollout stats alone")
+
+	rec = rescore(`{"namespace":"payments","deployment":"api","version":"v2","since":"90s"}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, 1, response.Samples)
+
+	for name, tc := range map[string]struct {
+		body   string
+		status int
+		code   string
+	}{
+		"missing version": {`{"namespace":"payments"}`, http.StatusBadRequest, ErrCodeAnomalyInvalidRequest},
+		"same versions":   {`{"namespace":"payments","version":"v1","baseline":"v1"}`, http.StatusBadRequest, ErrCodeAnomalyInvalidRequest},
+		"invalid since":   {`{"namespace":"payments","version":"v2","since":"yesterday"}`, http.StatusBadRequest, ErrCodeAnomalyInvalidRequest},
+		"unknown version": {`{"namespace":"payments","version":"v9"}`, http.StatusBadRequest, ErrCodeModelVersionNotFound},
+		"no history":      {`{"namespace":"orders","version":"v2"}`, http.StatusNotFound, ErrCodeFeatureHistoryEmpty},
+	} {
+		t.Run(name, func(t *testing.T) {
+			rec := rescore(tc.body)
+			assert.Equal(t, tc.status, rec.Code)
+			assert.Contains(t, rec.Body.String(), tc.code)
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/storage"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/api/validation"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// Anomaly record listing defaults
+const (
+	// DefaultAnomalyRecordsLimit is the number of anomalies listed when no limit is given
+	DefaultAnomalyRecordsLimit = 100
+
+	// MaxAnomalyRecordsLimit bounds the limit of a listing
+	MaxAnomalyRecordsLimit = 1000
+)
+
+// ErrCodeAnomalyRecordsDisabled is returned when detected anomalies are not kept
+const ErrCodeAnomalyRecordsDisabled = "ANOMALY_RECORDS_DISABLED"
+
+// AnomalyRecordsResponse lists stored anomalies, most recently detected first
+type AnomalyRecordsResponse struct {
+	Anomalies []models.AnomalyRecord `json:"anomalies"`
+	Count     int                    `json:"count"`
+}
+
+// SetAnomalyStore keeps every detected anomaly in store and enables GET /api/v1/anomalies
+func (h *AnomalyHandler) SetAnomalyStore(store *storage.AnomalyStore) {
+	h.records = store
+}
+
+// recordAnomalies stores the anomalies of a completed live analysis. Retroactive and
+// read-only analyses are not stored. The store writes them to disk in the background,
+// so analyses are answered without waiting for it.
+func (h *AnomalyHandler) recordAnomalies(req *AnomalyAnalyzeRequest, response *AnomalyAnalyzeResponse, evaluatedAt time.Time, modelVersion string) {
+	if h.records == nil || len(response.Anomalies) == 0 || req.At != "" || req.readOnly {
+		return
+	}
+
+	records := make([]*models.AnomalyRecord, 0, len(response.Anomalies))
+	for i := range response.Anomalies {
+		a := &response.Anomalies[i]
+		records = append(records, &models.AnomalyRecord{
+			Namespace:         req.Namespace,
+			Deployment:        req.Deployment,
+			Pod:               req.Pod,
+			Model:             req.ModelName,
+			ModelVersion:      modelVersion,
+			Severity:          a.Severity,
+			Priority:          a.Priority,
+			AnomalyScore:      a.AnomalyScore,
+			Confidence:        a.Confidence,
+			Metrics:           a.Metrics,
+			Explanation:       a.Explanation,
+			RecommendedAction: a.RecommendedAction,
+			AnalysisID:        response.AnalysisID,
+			DetectedAt:        evaluatedAt.UTC(),
+		})
+	}
+	h.records.Add(records)
+}
+
+// attachAnomalies links the stored anomalies of an analysis to the incident raised
+// from it
+func attachAnomalies(store *storage.AnomalyStore, analysisID, incidentID string, log *logrus.Logger) {
+	if store == nil || analysisID == "" {
+		return
+	}
+	if _, err := store.AttachIncident(analysisID, incidentID); err != nil {
+		log.WithError(err).WithField("incident_id", incidentID).Warn("Failed to link anomalies to incident")
+	}
+}
+
+// ListAnomalies handles GET /api/v1/anomalies
+// @Summary List previously detected anomalies
+// @Description Lists the anomalies detected by past analyses and background scans, most
+// @Description recently detected first, e.g. to correlate them with incidents or export
+// @Description them for retraining.
+// @ID listAnomalies
+// @Tags anomaly
+// @Produce json
+// @Param namespace query string false "Only anomalies of this namespace"
+// @Param severity query string false "Only anomalies of this severity (critical, warning, info)"
+// @Param since query string false "Only anomalies detected since this RFC3339 time, or within this duration, e.g. 24h"
+// @Param limit query int false "Maximum number of anomalies (default 100, at most 1000)"
+// @Success 200 {object} AnomalyRecordsResponse
+// @Failure 400 {object} AnomalyErrorResponse
+// @Failure 503 {object} AnomalyErrorResponse
+// @Router /api/v1/anomalies [get]
+func (h *AnomalyHandler) ListAnomalies(w http.ResponseWriter, r *http.Request) {
+	if h.records == nil {
+		h.respondError(w, http.StatusServiceUnavailable, "Anomaly records not enabled", "Set ANOMALY_RECORDS_ENABLED=true to keep detected anomalies", ErrCodeAnomalyRecordsDisabled)
+		return
+	}
+
+	filter, err := h.parseAnomalyFilter(r.URL.Query())
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error(), "", ErrCodeAnomalyInvalidRequest, validation.Fields(err)...)
+		return
+	}
+
+	anomalies := h.records.List(filter)
+	h.respondJSON(w, http.StatusOK, AnomalyRecordsResponse{Anomalies: anomalies, Count: len(anomalies)})
+}
+
+// parseAnomalyFilter validates the query parameters of GET /api/v1/anomalies
+func (h *AnomalyHandler) parseAnomalyFilter(query url.Values) (storage.AnomalyFilter, error) {
+	filter := storage.AnomalyFilter{
+		Namespace: query.Get("namespace"),
+		Severity:  query.Get("severity"),
+		Limit:     DefaultAnomalyRecordsLimit,
+	}
+
+	var errs validation.Errors
+	if filter.Severity != "" {
+		if err := validation.Check(validation.OneOf("severity", filter.Severity, "critical", "warning", "info")); err != nil {
+			errs = append(errs, validation.Fields(err)...)
+		}
+	}
+	if since := query.Get("since"); since != "" {
+		if at, err := time.Parse(time.RFC3339, since); err == nil {
+			filter.Since = at
+		} else if window, err := time.ParseDuration(since); err == nil && window > 0 {
+			filter.Since = h.now().Add(-window)
+		} else {
+			errs.Add("since", validation.ConstraintFormat, since, "since must be an RFC3339 time or a positive duration such as 24h")
+		}
+	}
+	if limit := query.Get("limit"); limit != "" {
+		parsed, err := strconv.Atoi(limit)
+		if err != nil || parsed < 1 || parsed > MaxAnomalyRecordsLimit {
+			errs.Add("limit", validation.ConstraintRange, limit, fmt.Sprintf("limit must be between 1 and %d", MaxAnomalyRecordsLimit))
+		} else {
+			filter.Limit = parsed
+		}
+	}
+	return filter, errs.Err()
+}
@@ -0,0 +1,115 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/storage"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/testsupport"
+)
+
+func TestAnomalyHandler_ListAnomalies(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	prom := testsupport.NewPrometheus()
+	prom.SetDefault(0.95)
+	predictors := testsupport.NewKServe()
+	predictors.SetPredictions("anomaly-detector", []int{-1})
+	kserveClient, err := predictors.Client(log, "anomaly-detector")
+	require.NoError(t, err)
+
+	handler := NewAnomalyHandler(kserveClient, prom.Client(log), log)
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	handler.SetClock(func() time.Time { return now })
+	router := mux.NewRouter()
+	handler.RegisterRoutes(router)
+	list := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/anomalies"+query, http.NoBody))
+		return w
+	}
+
+	w := list("")
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Contains(t, w.Body.String(), ErrCodeAnomalyRecordsDisabled)
+
+	store := storage.NewMemoryAnomalyStore(0)
+	handler.SetAnomalyStore(store)
+	for _, namespace := range []string{"payments", "checkout"} {
+		response, err := handler.Analyze(context.Background(), &AnomalyAnalyzeRequest{Scope: models.Scope{Namespace: namespace}})
+		require.NoError(t, err)
+		require.Equal(t, 1, response.AnomaliesDetected)
+	}
+
+	w = list("?namespace=payments&since=1h")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp AnomalyRecordsResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Equal(t, 1, resp.Count)
+	record := resp.Anomalies[0]
+	assert.Equal(t, "payments", record.Namespace)
+	assert.Equal(t, "anomaly-detector", record.Model)
+	assert.Equal(t, now, record.DetectedAt)
+	assert.NotEmpty(t, record.Explanation)
+	assert.Greater(t, record.AnomalyScore, 0.7)
+
+	w = list("?since=" + now.Add(time.Minute).Format(time.RFC3339))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Zero(t, resp.Count)
+	assert.NotNil(t, resp.Anomalies)
+
+	w = list("?severity=" + record.Severity + "&limit=1")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, 1, resp.Count)
+
+	w = list("?severity=high&since=yesterday&limit=0")
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	var errResp AnomalyErrorResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&errResp))
+	assert.Equal(t, ErrCodeAnomalyInvalidRequest, errResp.Code)
+	var fields []string
+	for _, fieldErr := range errResp.Errors {
+		fields = append(fields, fieldErr.Field)
+	}
+	assert.Equal(t, []string{"severity", "since", "limit"}, fields)
+
+	t.Run("retroactive analyses are not recorded", func(t *testing.T) {
+		count := store.Count()
+		response, err := handler.Analyze(context.Background(), &AnomalyAnalyzeRequest{
+			Scope: models.Scope{Namespace: "payments"},
+			At:    now.Add(-time.Hour).Format(time.RFC3339),
+		})
+		require.NoError(t, err)
+		require.Equal(t, 1, response.AnomaliesDetected)
+		assert.Equal(t, count, store.Count())
+	})
+
+	t.Run("health score polls are not recorded", func(t *testing.T) {
+		count := store.Count()
+		health := NewHealthScoreHandler(HealthScoreWeights{Anomalies: 1}, nil, log)
+		health.SetAnomalyAnalyzer(handler)
+		for i := 0; i < 3; i++ {
+			score := health.Score(context.Background())
+			require.NotNil(t, score)
+		}
+		assert.Equal(t, count, store.Count())
+
+		// The same cluster-wide analysis requested through the API is recorded
+		_, err := handler.Analyze(context.Background(), &AnomalyAnalyzeRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, count+1, store.Count())
+	})
+}
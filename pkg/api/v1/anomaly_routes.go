@@ -0,0 +1,181 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/featureflag"
+	"github.com/tosin2013/openshift-coordination-engine/internal/integrations"
+	"github.com/tosin2013/openshift-coordination-engine/internal/promql"
+)
+
+const (
+	// routeProbeWindow is the window of the external availability check
+	routeProbeWindow = "5m"
+
+	// routeUnreachableAvailability is the probe success ratio below which a route is
+	// reported unreachable from outside
+	routeUnreachableAvailability = 0.9
+)
+
+// routeFeature is a route probe input feature and the template computing it
+type routeFeature struct {
+	name     string
+	template string
+}
+
+// routeFeatures are the route probe features of route models, 9 each like the base
+// metrics, computed over all probed routes of the scope
+var routeFeatures = []routeFeature{
+	{"route_availability", "route.availability"},
+	{"route_probe_latency_seconds", "route.latency"},
+}
+
+// SetRouteProbes enables external availability checks from blackbox-exporter probes
+// of the Routes and Ingresses of namespace and deployment scopes. Routes failing their
+// probes are reported as anomalies even when internal metrics look normal. The named
+// models additionally get the route features as input, after any histogram features.
+func (h *AnomalyHandler) SetRouteProbes(lister *integrations.RouteLister, models []string) {
+	h.routeLister = lister
+	h.routeModels = make(map[string]bool, len(models))
+	for _, model := range models {
+		h.routeModels[model] = true
+	}
+}
+
+// RouteFeatureNames returns the route probe input features in model order
+func RouteFeatureNames() []string {
+	names := make([]string, 0, len(routeFeatures)*len(featureNames))
+	for _, route := range routeFeatures {
+		for _, feature := range featureNames {
+			names = append(names, fmt.Sprintf("%s_%s", route.name, feature))
+		}
+	}
+	return names
+}
+
+// modelRouteFeatures returns the route probe features a model expects
+func (h *AnomalyHandler) modelRouteFeatures(model string) []routeFeature {
+	if !h.routeModels[model] {
+		return nil
+	}
+	return routeFeatures
+}
+
+// resolveRoutes returns the Routes and Ingresses exposing a namespace or deployment
+// scope, or nil when route probes are disabled or the routes cannot be listed. Pods
+// are not exposed on their own, so pod scopes have none.
+func (h *AnomalyHandler) resolveRoutes(ctx context.Context, req *AnomalyAnalyzeRequest) []integrations.RouteTarget {
+	if h.routeLister == nil || req.Namespace == "" || req.Pod != "" {
+		return nil
+	}
+	routes, err := h.routeLister.Targets(ctx, req.Namespace, req.Deployment)
+	if err != nil {
+		h.log.WithError(err).Warn("Failed to list scope routes, skipping external availability")
+		return nil
+	}
+	return routes
+}
+
+// queryRouteFeatures queries the route probe features of route models. Without routes
+// or blackbox-exporter metrics the features are defaulted and recorded in quality, as
+// they are when the route_features flag is disabled for the namespace.
+func (h *AnomalyHandler) queryRouteFeatures(ctx context.Context, model, namespace string, routes []integrations.RouteTarget, quality *DataQuality) ([]float64, error) {
+	var features []float64
+	missing := h.prometheusClient.MissingExporters(integrations.ExporterBlackbox)
+	params := promql.Params{Selector: integrations.RouteProbeMatcher(routes)}
+	enabled := h.featureEnabled(featureflag.RouteFeatures, namespace, len(h.modelRouteFeatures(model)))
+	for _, route := range h.modelRouteFeatures(model) {
+		if !enabled {
+			quality.addDisabled(route.name)
+			features = append(features, h.getDefaultMetricFeatures()...)
+			continue
+		}
+		if len(missing) > 0 || len(routes) == 0 {
+			quality.addDefaulted(route.name, missing)
+			features = append(features, h.getDefaultMetricFeatures()...)
+			continue
+		}
+
+		metricFeatures, err := h.queryRollingFeatures(ctx, h.prometheusClient.Queries().MustRender(route.template, params))
+		if _, refused := integrations.IsQueryCostError(err); refused {
+			return nil, err
+		}
+		if err != nil {
+			h.log.WithError(err).WithField("feature", route.name).Debug("Failed to query route probe features, using defaults")
+			quality.addDefaulted(route.name, nil)
+			metricFeatures = h.getDefaultMetricFeatures()
+		}
+		features = append(features, metricFeatures...)
+	}
+	return features, nil
+}
+
+// annotateExternalAvailability adds the probe results of the scope's routes to the
+// response and reports routes failing their probes as anomalies, which internal
+// metrics miss when the problem is in the router, DNS or certificates
+func (h *AnomalyHandler) annotateExternalAvailability(ctx context.Context, response *AnomalyAnalyzeResponse, routes []integrations.RouteTarget, features []float64) {
+	if len(routes) == 0 || h.prometheusClient == nil || !h.prometheusClient.IsAvailable() ||
+		len(h.prometheusClient.MissingExporters(integrations.ExporterBlackbox)) > 0 {
+		return
+	}
+	results, err := h.prometheusClient.GetRouteAvailability(ctx, routes, routeProbeWindow)
+	if err != nil {
+		h.log.WithError(err).Debug("Failed to query route probes, skipping external availability")
+		return
+	}
+	response.ExternalAvailability = results
+
+	internalAnomaly := response.AnomaliesDetected > 0
+	unreachable := 0
+	for _, result := range results {
+		if result.Availability >= routeUnreachableAvailability {
+			continue
+		}
+		response.Anomalies = append(response.Anomalies, routeAnomaly(result, internalAnomaly, h.now()))
+		unreachable++
+	}
+	if unreachable == 0 {
+		return
+	}
+
+	h.log.WithFields(logrus.Fields{
+		"namespace":   response.Scope.Namespace,
+		"unreachable": unreachable,
+	}).Info("Routes failing external probes")
+	response.AnomaliesDetected = len(response.Anomalies)
+	response.Summary = h.buildSummary(response.Anomalies, features)
+	response.Recommendation = h.generateRecommendation(response.Anomalies, response.Summary)
+}
+
+// routeAnomaly reports a route failing its probes at now; a route never reached is
+// critical
+func routeAnomaly(result integrations.RouteAvailability, internalAnomaly bool, now time.Time) AnomalyResult {
+	severity := "warning"
+	if result.Availability == 0 {
+		severity = "critical"
+	}
+	explanation := fmt.Sprintf("Service unreachable from outside: probes of %s '%s' (%s) succeeded %.0f%% of the time in the last %s",
+		result.Kind, result.Name, result.Host, result.Availability*100, routeProbeWindow)
+	if !internalAnomaly {
+		explanation += " while internal metrics look normal; check the router, DNS and TLS certificates"
+	}
+
+	return AnomalyResult{
+		Timestamp:    now.UTC().Format(time.RFC3339),
+		Severity:     severity,
+		AnomalyScore: math.Round((1-result.Availability)*100) / 100,
+		// Probe results are observed, not inferred by the model
+		Confidence: 0.95,
+		Metrics: map[string]float64{
+			"route_availability":          result.Availability,
+			"route_probe_latency_seconds": result.LatencySeconds,
+		},
+		Explanation:       explanation,
+		RecommendedAction: "immediate_investigation",
+	}
+}
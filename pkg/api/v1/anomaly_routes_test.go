@@ -0,0 +1,77 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/integrations"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+func TestAnomalyHandler_RouteProbes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query().Get("query")
+		result := `{"metric":{},"value":[1700000000,"0.5"]}`
+		switch {
+		case strings.HasPrefix(query, "avg by (instance)"):
+			result = `{"metric":{"instance":"https://shop.example.com/healthz"},"value":[1700000000,"0.4"]}`
+		case strings.HasPrefix(query, "max by (instance)"):
+			result = `{"metric":{"instance":"https://shop.example.com/healthz"},"value":[1700000000,"2.1"]}`
+		case strings.HasPrefix(query, "avg(probe_success"):
+			result = `{"metric":{},"value":[1700000000,"0.4"]}`
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[` + result + `]}}`))
+	}))
+	defer server.Close()
+
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	clientset := fake.NewSimpleClientset(&networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Name: "shop", Namespace: "shop"},
+		Spec:       networkingv1.IngressSpec{Rules: []networkingv1.IngressRule{{Host: "shop.example.com"}}},
+	})
+	handler := NewAnomalyHandler(nil, integrations.NewPrometheusClient(server.URL, 5*time.Second, log), log)
+	handler.SetRouteProbes(integrations.NewRouteLister(clientset, nil, log), []string{"anomaly-external"})
+
+	req := &AnomalyAnalyzeRequest{Scope: models.Scope{Namespace: "shop"}}
+	routes := handler.resolveRoutes(context.Background(), req)
+	require.Len(t, routes, 1)
+	assert.Empty(t, handler.resolveRoutes(context.Background(), &AnomalyAnalyzeRequest{Scope: models.Scope{Namespace: "shop", Pod: "shop-1"}}))
+
+	features, _, quality, err := handler.buildFeatureVector(context.Background(), "anomaly-external", "shop", "", "", nil, routes)
+	require.NoError(t, err)
+	require.Len(t, features, 63)
+	assert.Equal(t, 0.4, features[45], "route availability value")
+	assert.False(t, quality.Degraded)
+	assert.Equal(t, "route_availability_value", handler.buildFeatureInfo("anomaly-external").FeatureNames[45])
+
+	// Without routes the features are defaulted
+	features, _, quality, err = handler.buildFeatureVector(context.Background(), "anomaly-external", "shop", "", "", nil, nil)
+	require.NoError(t, err)
+	assert.Len(t, features, 63)
+	assert.Equal(t, []string{"route_availability", "route_probe_latency_seconds"}, quality.DefaultedMetrics)
+
+	response := AnomalyAnalyzeResponse{Scope: handler.buildScope(req)}
+	handler.annotateExternalAvailability(context.Background(), &response, routes, features)
+	require.Len(t, response.ExternalAvailability, 1)
+	assert.Equal(t, 2.1, response.ExternalAvailability[0].LatencySeconds)
+	require.Equal(t, 1, response.AnomaliesDetected)
+	anomaly := response.Anomalies[0]
+	assert.Equal(t, "warning", anomaly.Severity)
+	assert.Equal(t, 0.6, anomaly.AnomalyScore)
+	assert.Contains(t, anomaly.Explanation, "probes of Ingress 'shop' (shop.example.com) succeeded 40%")
+	assert.Contains(t, anomaly.Explanation, "while internal metrics look normal")
+	assert.Equal(t, 0.6, response.Summary.MaxScore)
+}
@@ -0,0 +1,306 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/anomaly"
+	"github.com/tosin2013/openshift-coordination-engine/internal/storage"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// scanTimeout bounds the scan of a single namespace
+const scanTimeout = 2 * time.Minute
+
+// Labels set on incidents opened by the anomaly scanner
+const (
+	// LabelScanSource identifies where the incident came from
+	LabelScanSource = "source"
+
+	// SourceAnomalyScanner is the LabelScanSource value of scanner incidents
+	SourceAnomalyScanner = "anomaly-scanner"
+)
+
+// AnomalyScannerOptions configures the background anomaly scanner
+type AnomalyScannerOptions struct {
+	// Namespaces are scanned in order on every run. With tenants, only those that are
+	// onboarded are scanned, and every onboarded namespace if Namespaces is empty.
+	Namespaces []string
+
+	// Interval is the time between runs, e.g. config.DefaultAnomalyScanInterval
+	Interval time.Duration
+}
+
+// OnboardedNamespaces lists the namespaces onboarded by tenants (implemented by
+// tenant.Registry)
+type OnboardedNamespaces interface {
+	OnboardedNamespaces(ctx context.Context) ([]string, error)
+}
+
+// AnomalyScanner periodically analyzes a fixed list of namespaces through the anomaly
+// handler, so anomalies that occur between API requests are not missed. A namespace
+// whose anomalies persist for enough scans gets an incident, which later scans keep up
+// to date and resolve once its anomaly score stays clear.
+type AnomalyScanner struct {
+	anomaly     *AnomalyHandler
+	incidents   *storage.IncidentStore
+	persistence *anomaly.PersistenceTracker
+	resolver    *anomaly.AutoResolver
+	tenants     OnboardedNamespaces
+	opts        AnomalyScannerOptions
+	log         *logrus.Logger
+
+	// scanned are the namespaces of the last run, so the persistence of namespaces that
+	// drop out, e.g. when their tenant is offboarded, starts over
+	scanned map[string]bool
+}
+
+// NewAnomalyScanner creates the scanner. persistence decides when an anomaly opens an
+// incident and resolver when it is resolved.
+func NewAnomalyScanner(
+	handler *AnomalyHandler,
+	incidents *storage.IncidentStore,
+	persistence *anomaly.PersistenceTracker,
+	resolver *anomaly.AutoResolver,
+	opts AnomalyScannerOptions,
+	log *logrus.Logger,
+) *AnomalyScanner {
+	return &AnomalyScanner{
+		anomaly:     handler,
+		incidents:   incidents,
+		persistence: persistence,
+		resolver:    resolver,
+		opts:        opts,
+		log:         log,
+	}
+}
+
+// SetTenants limits scans to namespaces onboarded by a tenant, re-read on every run
+func (s *AnomalyScanner) SetTenants(tenants OnboardedNamespaces) {
+	s.tenants = tenants
+}
+
+// Start scans the namespaces every interval until ctx is cancelled
+func (s *AnomalyScanner) Start(ctx context.Context) {
+	if s.opts.Interval <= 0 {
+		s.log.WithField("interval", s.opts.Interval).Warn("Anomaly scanner not started: interval must be positive")
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(s.opts.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.Scan(ctx)
+			}
+		}
+	}()
+	s.log.WithFields(logrus.Fields{
+		"namespaces": s.opts.Namespaces,
+		"tenants":    s.tenants != nil,
+		"interval":   s.opts.Interval,
+	}).Info("Anomaly scanner started")
+}
+
+// Scan analyzes every namespace once. A failed namespace does not stop the others.
+func (s *AnomalyScanner) Scan(ctx context.Context) {
+	namespaces := s.namespaces(ctx)
+	scanned := make(map[string]bool, len(namespaces))
+	for _, namespace := range namespaces {
+		scanned[namespace] = true
+	}
+	for namespace := range s.scanned {
+		if !scanned[namespace] {
+			s.persistence.Reset(anomaly.ScopeKey(namespace, "", ""))
+		}
+	}
+	s.scanned = scanned
+
+	for _, namespace := range namespaces {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := s.scanNamespace(ctx, namespace); err != nil {
+			anomaly.RecordScan(namespace, anomaly.ScanFailed)
+			s.log.WithError(err).WithField("namespace", namespace).Warn("Background anomaly scan failed")
+		}
+	}
+}
+
+// namespaces returns the namespaces of this run: the configured ones, or with tenants
+// those of them that are onboarded (every onboarded namespace if none are configured)
+func (s *AnomalyScanner) namespaces(ctx context.Context) []string {
+	if s.tenants == nil {
+		return s.opts.Namespaces
+	}
+
+	onboarded, err := s.tenants.OnboardedNamespaces(ctx)
+	if err != nil {
+		s.log.WithError(err).Warn("Failed to list all onboarded namespaces for the anomaly scanner")
+	}
+	if len(s.opts.Namespaces) == 0 {
+		return onboarded
+	}
+
+	isOnboarded := make(map[string]bool, len(onboarded))
+	for _, namespace := range onboarded {
+		isOnboarded[namespace] = true
+	}
+	namespaces := make([]string, 0, len(s.opts.Namespaces))
+	for _, namespace := range s.opts.Namespaces {
+		if isOnboarded[namespace] {
+			namespaces = append(namespaces, namespace)
+		} else {
+			s.log.WithField("namespace", namespace).Debug("Skipping background scan of namespace not onboarded by a tenant")
+		}
+	}
+	return namespaces
+}
+
+// scanNamespace analyzes a namespace and opens, updates or resolves its incident
+func (s *AnomalyScanner) scanNamespace(ctx context.Context, namespace string) error {
+	scanCtx, cancel := context.WithTimeout(ctx, scanTimeout)
+	response, err := s.anomaly.Analyze(scanCtx, &AnomalyAnalyzeRequest{Scope: models.Scope{Namespace: namespace}})
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	detected := response.AnomaliesDetected > 0
+	outcome := anomaly.ScanClean
+	if detected {
+		outcome = anomaly.ScanAnomalous
+	}
+	anomaly.RecordScan(namespace, outcome)
+
+	scope := anomaly.ScopeKey(namespace, "", "")
+	if open := s.openIncident(namespace); open != nil {
+		resolved, err := s.resolver.Evaluate(ctx, scope, open.ID, response.Summary.MaxScore)
+		if err != nil || resolved || !detected {
+			return err
+		}
+		return s.updateIncident(open, response)
+	}
+
+	persistent, streak := s.persistence.Observe(scope, detected)
+	if !persistent {
+		if detected {
+			s.log.WithFields(logrus.Fields{
+				"namespace": namespace,
+				"streak":    streak,
+				"required":  s.persistence.Required(scope),
+			}).Debug("Anomaly detected, waiting for it to persist")
+		}
+		return nil
+	}
+	if err := s.openIncidentFor(namespace, response); err != nil {
+		return err
+	}
+	s.persistence.Reset(scope)
+	return nil
+}
+
+// openIncident returns the active scanner incident of a namespace, e.g. one opened
+// before a restart, or nil if there is none
+func (s *AnomalyScanner) openIncident(namespace string) *models.Incident {
+	active := s.incidents.List(storage.ListFilter{
+		Namespace: namespace,
+		Status:    string(models.IncidentStatusActive),
+	})
+	for _, incident := range active {
+		if incident.Labels[LabelScanSource] == SourceAnomalyScanner {
+			return incident
+		}
+	}
+	return nil
+}
+
+// openIncidentFor creates the incident of a namespace with persistent anomalies and
+// links the analysis artifact and stored anomalies to it
+func (s *AnomalyScanner) openIncidentFor(namespace string, response *AnomalyAnalyzeResponse) error {
+	incident, err := s.incidents.Create(&models.Incident{
+		Title:       fmt.Sprintf("Anomalies detected in namespace %s", namespace),
+		Description: scanDescription(response),
+		Severity:    scanSeverity(response.Anomalies),
+		Target:      namespace,
+		Labels: map[string]string{
+			LabelScanSource: SourceAnomalyScanner,
+			"namespace":     namespace,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create incident: %w", err)
+	}
+	anomaly.ScanIncidents.WithLabelValues(namespace).Inc()
+
+	if s.anomaly.artifacts != nil && response.AnalysisID != "" {
+		if _, err := s.anomaly.artifacts.Attach(response.AnalysisID, incident.ID); err != nil {
+			s.log.WithError(err).WithField("incident_id", incident.ID).Warn("Failed to attach analysis artifact to scanner incident")
+		}
+	}
+	attachAnomalies(s.anomaly.records, response.AnalysisID, incident.ID, s.log)
+
+	s.log.WithFields(logrus.Fields{
+		"incident_id": incident.ID,
+		"namespace":   namespace,
+		"anomalies":   response.AnomaliesDetected,
+		"max_score":   response.Summary.MaxScore,
+	}).Warn("Background anomaly scan opened an incident")
+	return nil
+}
+
+// updateIncident refreshes an open incident with the latest anomalies. Severity is
+// only raised, so a quieter scan does not hide how bad the incident got.
+func (s *AnomalyScanner) updateIncident(incident *models.Incident, response *AnomalyAnalyzeResponse) error {
+	description := scanDescription(response)
+	severity := scanSeverity(response.Anomalies)
+	if severity.Rank() <= incident.Severity.Rank() {
+		severity = incident.Severity
+	}
+	if incident.Description == description && incident.Severity == severity {
+		return nil
+	}
+
+	updated := *incident
+	updated.Description = description
+	updated.Severity = severity
+	if err := s.incidents.Update(&updated); err != nil {
+		return fmt.Errorf("failed to update incident %s: %w", incident.ID, err)
+	}
+	return nil
+}
+
+// scanDescription lists the explanation of each anomaly and the overall recommendation
+func scanDescription(response *AnomalyAnalyzeResponse) string {
+	lines := []string{fmt.Sprintf("Background scan detected %d anomalies (max score %.2f):",
+		response.AnomaliesDetected, response.Summary.MaxScore)}
+	for _, a := range response.Anomalies {
+		lines = append(lines, fmt.Sprintf("- [%s] %s", a.Severity, a.Explanation))
+	}
+	if response.Recommendation != "" {
+		lines = append(lines, response.Recommendation)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// scanSeverity maps the most severe anomaly to an incident severity: critical, high
+// (warning) or medium (info)
+func scanSeverity(anomalies []AnomalyResult) models.IncidentSeverity {
+	severity := models.IncidentSeverityMedium
+	for _, a := range anomalies {
+		switch a.Severity {
+		case "critical":
+			return models.IncidentSeverityCritical
+		case "warning":
+			severity = models.IncidentSeverityHigh
+		}
+	}
+	return severity
+}
@@ -0,0 +1,140 @@
+package v1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/anomaly"
+	"github.com/tosin2013/openshift-coordination-engine/internal/storage"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/testsupport"
+)
+
+func TestAnomalyScanner_Scan(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	prom := testsupport.NewPrometheus()
+	prom.SetDefault(0.05)
+	predictors := testsupport.NewKServe()
+	predictors.SetPredictions("anomaly-detector", []int{1})
+	kserveClient, err := predictors.Client(log, "anomaly-detector")
+	require.NoError(t, err)
+	setAnomalous := func(anomalous bool) {
+		if anomalous {
+			prom.SetDefault(0.95)
+			predictors.SetPredictions("anomaly-detector", []int{-1})
+			return
+		}
+		prom.SetDefault(0.05)
+		predictors.SetPredictions("anomaly-detector", []int{1})
+	}
+
+	incidents := storage.NewIncidentStoreWithPath(t.TempDir())
+	scanner := NewAnomalyScanner(NewAnomalyHandler(kserveClient, prom.Client(log), log), incidents,
+		anomaly.NewPersistenceTracker(2, nil),
+		anomaly.NewAutoResolver(incidents, anomaly.NewResolutionTracker(0.5, 2), log),
+		AnomalyScannerOptions{Namespaces: []string{"payments"}}, log)
+	scannerIncidents := func() []*models.Incident {
+		return incidents.List(storage.ListFilter{Namespace: "payments"})
+	}
+	ctx := context.Background()
+
+	// A clean namespace opens nothing
+	scanner.Scan(ctx)
+	assert.Empty(t, scannerIncidents())
+
+	// Anomalies open an incident once they persist for two scans
+	setAnomalous(true)
+	scanner.Scan(ctx)
+	assert.Empty(t, scannerIncidents(), "a single anomalous scan is not persistent")
+	scanner.Scan(ctx)
+	opened := scannerIncidents()
+	require.Len(t, opened, 1)
+	incident := opened[0]
+	assert.Equal(t, SourceAnomalyScanner, incident.Labels[LabelScanSource])
+	assert.Equal(t, "payments", incident.Target)
+	assert.True(t, incident.IsActive())
+	assert.Contains(t, incident.Description, "Background scan detected")
+
+	// Further anomalous scans keep the same incident
+	scanner.Scan(ctx)
+	assert.Len(t, scannerIncidents(), 1)
+
+	// The incident resolves after two clean scans
+	setAnomalous(false)
+	scanner.Scan(ctx)
+	stored, err := incidents.Get(incident.ID)
+	require.NoError(t, err)
+	assert.True(t, stored.IsActive())
+	scanner.Scan(ctx)
+	stored, err = incidents.Get(incident.ID)
+	require.NoError(t, err)
+	assert.False(t, stored.IsActive())
+	assert.Equal(t, models.IncidentStatusResolved, stored.Status)
+}
+
+// fakeOnboarded lists a fixed set of onboarded namespaces
+type fakeOnboarded struct {
+	namespaces []string
+}
+
+func (f *fakeOnboarded) OnboardedNamespaces(context.Context) ([]string, error) {
+	return f.namespaces, nil
+}
+
+func TestAnomalyScanner_Tenants(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	prom := testsupport.NewPrometheus()
+	prom.SetDefault(0.95)
+	predictors := testsupport.NewKServe()
+	predictors.SetPredictions("anomaly-detector", []int{-1})
+	kserveClient, err := predictors.Client(log, "anomaly-detector")
+	require.NoError(t, err)
+
+	incidents := storage.NewIncidentStoreWithPath(t.TempDir())
+	newScanner := func(namespaces ...string) *AnomalyScanner {
+		return NewAnomalyScanner(NewAnomalyHandler(kserveClient, prom.Client(log), log), incidents,
+			anomaly.NewPersistenceTracker(2, nil),
+			anomaly.NewAutoResolver(incidents, anomaly.NewResolutionTracker(0.5, 2), log),
+			AnomalyScannerOptions{Namespaces: namespaces}, log)
+	}
+	tenants := &fakeOnboarded{namespaces: []string{"checkout", "payments"}}
+	ctx := context.Background()
+
+	// Configured namespaces are limited to onboarded ones
+	scanner := newScanner("payments", "legacy")
+	scanner.SetTenants(tenants)
+	assert.Equal(t, []string{"payments"}, scanner.namespaces(ctx))
+
+	// Without configured namespaces every onboarded namespace is scanned
+	scanner = newScanner()
+	scanner.SetTenants(tenants)
+	assert.Equal(t, []string{"checkout", "payments"}, scanner.namespaces(ctx))
+
+	// Offboarding stops the scans and restarts persistence on re-onboarding
+	scanner.Scan(ctx)
+	tenants.namespaces = []string{"checkout"}
+	scanner.Scan(ctx)
+	require.Len(t, incidents.List(storage.ListFilter{Namespace: "checkout"}), 1)
+	assert.Empty(t, incidents.List(storage.ListFilter{Namespace: "payments"}))
+
+	tenants.namespaces = []string{"checkout", "payments"}
+	scanner.Scan(ctx)
+	assert.Empty(t, incidents.List(storage.ListFilter{Namespace: "payments"}), "a single scan after re-onboarding is not persistent")
+	scanner.Scan(ctx)
+	assert.Len(t, incidents.List(storage.ListFilter{Namespace: "payments"}), 1)
+}
+
+func TestScanSeverity(t *testing.T) {
+	assert.Equal(t, models.IncidentSeverityMedium, scanSeverity(nil))
+	assert.Equal(t, models.IncidentSeverityMedium, scanSeverity([]AnomalyResult{{Severity: "info"}}))
+	assert.Equal(t, models.IncidentSeverityHigh, scanSeverity([]AnomalyResult{{Severity: "info"}, {Severity: "warning"}}))
+	assert.Equal(t, models.IncidentSeverityCritical, scanSeverity([]AnomalyResult{{Severity: "warning"}, {Severity: "critical"}}))
+}
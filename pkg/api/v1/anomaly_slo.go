@@ -0,0 +1,116 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/integrations"
+	"github.com/tosin2013/openshift-coordination-engine/internal/slo"
+)
+
+// rollingWindow is the window of the rolling features, and rollingStep the resolution
+// of the fast path's range query
+const (
+	rollingWindow = 5 * time.Minute
+	rollingStep   = time.Minute
+)
+
+// fastPathKey marks an analysis context whose rolling features use the fast path
+type fastPathKey struct{}
+
+// SetLatencySLO tracks the end-to-end latency of analysis requests against an
+// objective. While its error budget is at risk, analyses fetch rolling features with
+// one batched range query per metric instead of one instant query per feature.
+func (h *AnomalyHandler) SetLatencySLO(tracker *slo.Tracker) {
+	h.latencySLO = tracker
+}
+
+// observeLatency records the duration of an analysis request answered with status.
+// Requests rejected as invalid are not held against the objective.
+func (h *AnomalyHandler) observeLatency(start time.Time, status int) {
+	if h.latencySLO == nil || (status >= http.StatusBadRequest && status < http.StatusInternalServerError) {
+		return
+	}
+	h.latencySLO.Observe(time.Since(start))
+}
+
+// withFastPath decides once per analysis whether its rolling features use the fast
+// path, so a vector is not built half one way and half the other
+func (h *AnomalyHandler) withFastPath(ctx context.Context) (context.Context, bool) {
+	if !h.latencySLO.FastPath() || h.prometheusClient == nil {
+		return ctx, false
+	}
+	return context.WithValue(ctx, fastPathKey{}, true), true
+}
+
+// usesFastPath reports whether the analysis of ctx uses the fast path
+func usesFastPath(ctx context.Context) bool {
+	fast, _ := ctx.Value(fastPathKey{}).(bool)
+	return fast
+}
+
+// queryRollingFeaturesBatched derives the 9 features of a base query from one range
+// query over the rolling window, evaluated at the analysis' instant
+func (h *AnomalyHandler) queryRollingFeaturesBatched(ctx context.Context, baseQuery string) ([]float64, error) {
+	at := integrations.SnapshotFromContext(ctx).At()
+	if at.IsZero() {
+		at = h.now().Truncate(time.Second)
+	}
+	points, err := h.prometheusClient.QueryRange(ctx, baseQuery, at.Add(-rollingWindow), at, rollingStep, integrations.RangeFirst)
+	if err != nil {
+		return nil, err
+	}
+	return rollingFeaturesFromRange(points, at)
+}
+
+// rollingFeaturesFromRange computes the rolling features from the points of a range
+// query ending at at. The statistics cover the points after the start of the window,
+// like the subqueries of the instant path; a missing lag takes the current value.
+func rollingFeaturesFromRange(points []integrations.MetricDataPoint, at time.Time) ([]float64, error) {
+	byTime := make(map[int64]float64, len(points))
+	for _, point := range points {
+		if !math.IsNaN(point.Value) && !math.IsInf(point.Value, 0) {
+			byTime[point.Timestamp.Unix()] = point.Value
+		}
+	}
+	currentValue, ok := byTime[at.Unix()]
+	if !ok {
+		return nil, fmt.Errorf("range query returned no value at %s", at.Format(time.RFC3339))
+	}
+
+	var sum, sumSquares float64
+	minValue, maxValue := currentValue, currentValue
+	n := 0
+	for step := rollingWindow - rollingStep; step >= 0; step -= rollingStep {
+		value, ok := byTime[at.Add(-step).Unix()]
+		if !ok {
+			continue
+		}
+		sum += value
+		sumSquares += value * value
+		minValue = math.Min(minValue, value)
+		maxValue = math.Max(maxValue, value)
+		n++
+	}
+	mean5m := sum / float64(n)
+	std5m := math.Sqrt(math.Max(sumSquares/float64(n)-mean5m*mean5m, 0))
+
+	lag1, ok := byTime[at.Add(-time.Minute).Unix()]
+	if !ok {
+		lag1 = currentValue
+	}
+	lag5, ok := byTime[at.Add(-rollingWindow).Unix()]
+	if !ok {
+		lag5 = currentValue
+	}
+
+	diff := currentValue - lag1
+	pctChange := 0.0
+	if lag1 != 0 {
+		pctChange = (currentValue - lag1) / lag1
+	}
+	return []float64{currentValue, mean5m, std5m, minValue, maxValue, lag1, lag5, diff, pctChange}, nil
+}
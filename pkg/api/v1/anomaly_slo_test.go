@@ -0,0 +1,107 @@
+package v1
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/integrations"
+	"github.com/tosin2013/openshift-coordination-engine/internal/slo"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/testsupport"
+)
+
+func TestAnomalyHandler_LatencySLOFastPath(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	prom := testsupport.NewPrometheus()
+	prom.SetDefault(0.5)
+	kserveClient, err := testsupport.NewKServe().Client(log, "anomaly-detector")
+	require.NoError(t, err)
+
+	handler := NewAnomalyHandler(kserveClient, nil, log)
+	handler.SetPrometheusClient(prom.Client(log))
+	router := mux.NewRouter()
+	handler.RegisterRoutes(router)
+
+	analyze := func() AnomalyAnalyzeResponse {
+		rec := httptest.NewRecorder()
+		body := `{"namespace":"payments","at":"2026-03-10T12:00:00Z"}`
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/anomalies/analyze", bytes.NewBufferString(body)))
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var response AnomalyAnalyzeResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		return response
+	}
+	countCalls := func() (instant, ranged int) {
+		for _, call := range prom.Calls() {
+			if call.Range {
+				ranged++
+			} else {
+				instant++
+			}
+		}
+		return instant, ranged
+	}
+
+	// Without an objective every feature is an instant query
+	assert.False(t, analyze().FastPath)
+	instant, ranged := countCalls()
+	assert.Equal(t, len(baseMetrics)*7, instant)
+	assert.Zero(t, ranged)
+
+	tracker := slo.New(slo.Options{Name: "test_analysis", FastPath: slo.FastPathAlways}, log)
+	handler.SetLatencySLO(tracker)
+	prom.Reset()
+	response := analyze()
+	assert.True(t, response.FastPath)
+	instant, ranged = countCalls()
+	assert.Zero(t, instant)
+	assert.Equal(t, len(baseMetrics), ranged, "one range query per metric")
+	for _, call := range prom.Calls() {
+		assert.Equal(t, time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC), call.End, "evaluated at the analysis instant")
+		assert.Equal(t, 5*time.Minute, call.End.Sub(call.Start))
+		assert.False(t, strings.Contains(call.Query, "offset"))
+	}
+	assert.Equal(t, 1, tracker.Status().Requests, "the request's latency is tracked")
+
+	// A failing range query falls back to instant queries
+	prom.Reset()
+	prom.Fail("node_cpu", http.StatusInternalServerError)
+	analyze()
+	instant, _ = countCalls()
+	assert.NotZero(t, instant)
+}
+
+func TestRollingFeaturesFromRange(t *testing.T) {
+	at := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	points := func(values ...float64) []integrations.MetricDataPoint {
+		out := make([]integrations.MetricDataPoint, len(values))
+		for i, value := range values {
+			out[i] = integrations.MetricDataPoint{Timestamp: at.Add(time.Duration(i-len(values)+1) * time.Minute), Value: value}
+		}
+		return out
+	}
+
+	// The statistics cover the 5 points after the start of the window
+	features, err := rollingFeaturesFromRange(points(9, 1, 2, 3, 4, 5), at)
+	require.NoError(t, err)
+	assert.Equal(t, []float64{5, 3, features[2], 1, 5, 4, 9, 1, 0.25}, features)
+	assert.InDelta(t, 1.41421356, features[2], 1e-6)
+
+	// Missing lags take the current value
+	features, err = rollingFeaturesFromRange(points(2, 4), at)
+	require.NoError(t, err)
+	assert.Equal(t, []float64{4, 3, 1, 2, 4, 2, 4, 2, 1}, features)
+
+	_, err = rollingFeaturesFromRange(points(1, 2)[:1], at)
+	assert.ErrorContains(t, err, "no value at 2026-03-10T12:00:00Z")
+}
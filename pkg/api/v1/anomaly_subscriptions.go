@@ -0,0 +1,436 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/notification"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/api/validation"
+)
+
+// Anomaly subscription defaults
+const (
+	// DefaultSubscriptionInterval is the scan interval of subscriptions that omit one
+	DefaultSubscriptionInterval = 5 * time.Minute
+
+	// DefaultSubscriptionMinInterval is the shortest scan interval a subscription may request
+	DefaultSubscriptionMinInterval = time.Minute
+
+	// DefaultMaxSubscriptions bounds the number of subscriptions
+	DefaultMaxSubscriptions = 100
+
+	// subscriptionTick is how often the scheduler looks for due scans
+	subscriptionTick = 10 * time.Second
+
+	// subscriptionScanTimeout bounds a single scheduled scan
+	subscriptionScanTimeout = 2 * time.Minute
+)
+
+// Error codes for anomaly subscriptions
+const (
+	ErrCodeSubscriptionNotFound     = "SUBSCRIPTION_NOT_FOUND"
+	ErrCodeSubscriptionLimitReached = "SUBSCRIPTION_LIMIT_REACHED"
+	ErrCodeScopeNotOnboarded        = "SCOPE_NOT_ONBOARDED"
+)
+
+// AnomalySubscriptionRequest is the body of POST /api/v1/anomalies/subscriptions
+type AnomalySubscriptionRequest struct {
+	// URL receives an AnomalyScanEvent after each scan
+	URL string `json:"url"`
+
+	// Secret signs deliveries; empty uses the engine's WEBHOOK_SECRET
+	Secret string `json:"secret,omitempty"`
+
+	// Interval between scans, e.g. "15m" (default 5m)
+	Interval string `json:"interval,omitempty"`
+
+	// Analysis is the scope and parameters of each scan, as for POST /api/v1/anomalies/analyze
+	Analysis AnomalyAnalyzeRequest `json:"analysis"`
+}
+
+// AnomalySubscription is a scope scanned on a schedule, whose results are sent to a webhook
+type AnomalySubscription struct {
+	ID        string                `json:"id"`
+	URL       string                `json:"url"`
+	Interval  string                `json:"interval"`
+	Analysis  AnomalyAnalyzeRequest `json:"analysis"`
+	CreatedAt time.Time             `json:"created_at"`
+
+	// NextScanAt is when the next scan is due
+	NextScanAt time.Time `json:"next_scan_at"`
+
+	// LastScanAt, Scans and LastError describe completed scans; a failed scan is not delivered
+	LastScanAt *time.Time `json:"last_scan_at,omitempty"`
+	Scans      int        `json:"scans"`
+	LastError  string     `json:"last_error,omitempty"`
+
+	secret   string
+	interval time.Duration
+	running  bool
+}
+
+// AnomalyScanEvent is the payload delivered to a subscription's webhook
+type AnomalyScanEvent struct {
+	ID             string                  `json:"id"`
+	Type           notification.EventType  `json:"type"`
+	Timestamp      time.Time               `json:"timestamp"`
+	SubscriptionID string                  `json:"subscription_id"`
+	Result         *AnomalyAnalyzeResponse `json:"result"`
+}
+
+// AnomalySubscriptionsResponse lists subscriptions
+type AnomalySubscriptionsResponse struct {
+	Subscriptions []AnomalySubscription `json:"subscriptions"`
+	Count         int                   `json:"count"`
+}
+
+// AnomalySubscriptionOptions bounds the subscriptions callers can register
+type AnomalySubscriptionOptions struct {
+	// MinInterval is the shortest scan interval (DefaultSubscriptionMinInterval if zero)
+	MinInterval time.Duration
+
+	// MaxSubscriptions bounds the number of subscriptions (DefaultMaxSubscriptions if zero)
+	MaxSubscriptions int
+}
+
+// AnomalySubscriptions runs scheduled anomaly scans for registered scopes and sends
+// each result to the subscriber's webhook, so automation does not have to poll.
+// Subscriptions are kept in memory and must be registered again after a restart.
+type AnomalySubscriptions struct {
+	anomaly  *AnomalyHandler
+	notifier *notification.WebhookNotifier
+	tenants  Tenants
+	opts     AnomalySubscriptionOptions
+	log      *logrus.Logger
+
+	mu            sync.Mutex
+	subscriptions map[string]*AnomalySubscription
+
+	// now is replaceable in tests
+	now func() time.Time
+}
+
+// NewAnomalySubscriptions creates the subscription scheduler. Scans run through the
+// anomaly handler and results are delivered through the webhook notifier.
+func NewAnomalySubscriptions(anomaly *AnomalyHandler, notifier *notification.WebhookNotifier, opts AnomalySubscriptionOptions, log *logrus.Logger) *AnomalySubscriptions {
+	if opts.MinInterval <= 0 {
+		opts.MinInterval = DefaultSubscriptionMinInterval
+	}
+	if opts.MaxSubscriptions <= 0 {
+		opts.MaxSubscriptions = DefaultMaxSubscriptions
+	}
+	return &AnomalySubscriptions{
+		anomaly:       anomaly,
+		notifier:      notifier,
+		opts:          opts,
+		log:           log,
+		subscriptions: make(map[string]*AnomalySubscription),
+		now:           time.Now,
+	}
+}
+
+// SetTenants limits scheduled scans to namespaces onboarded by a tenant
+func (s *AnomalySubscriptions) SetTenants(tenants Tenants) {
+	s.tenants = tenants
+}
+
+// RegisterRoutes registers anomaly subscription routes
+func (s *AnomalySubscriptions) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/api/v1/anomalies/subscriptions", s.Create).Methods("POST")
+	router.HandleFunc("/api/v1/anomalies/subscriptions", s.List).Methods("GET")
+	router.HandleFunc("/api/v1/anomalies/subscriptions/{id}", s.Get).Methods("GET")
+	router.HandleFunc("/api/v1/anomalies/subscriptions/{id}", s.Delete).Methods("DELETE")
+
+	s.log.Info("Anomaly subscription API endpoints registered: /api/v1/anomalies/subscriptions, /api/v1/anomalies/subscriptions/{id}")
+}
+
+// Start runs due scans until ctx is cancelled
+func (s *AnomalySubscriptions) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(subscriptionTick)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.runDue(ctx)
+			}
+		}
+	}()
+	s.log.WithField("min_interval", s.opts.MinInterval).Info("Anomaly subscription scheduler started")
+}
+
+// Create handles POST /api/v1/anomalies/subscriptions
+// @Summary Subscribe a webhook to scheduled anomaly scans of a scope
+// @ID createAnomalySubscription
+// @Tags anomaly
+// @Accept json
+// @Produce json
+// @Param request body AnomalySubscriptionRequest true "Subscription"
+// @Success 201 {object} AnomalySubscription
+// @Failure 400 {object} AnomalyErrorResponse
+// @Failure 403 {object} AnomalyErrorResponse
+// @Failure 409 {object} AnomalyErrorResponse
+// @Router /api/v1/anomalies/subscriptions [post]
+func (s *AnomalySubscriptions) Create(w http.ResponseWriter, r *http.Request) {
+	var req AnomalySubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.anomaly.respondError(w, http.StatusBadRequest, "Invalid request format", err.Error(), ErrCodeAnomalyInvalidRequest, validation.DecodeFields(err)...)
+		return
+	}
+
+	interval, err := s.validate(&req)
+	if err != nil {
+		s.anomaly.respondError(w, http.StatusBadRequest, err.Error(), "", ErrCodeAnomalyInvalidRequest, validation.Fields(err)...)
+		return
+	}
+	if err := s.onboarded(r.Context(), req.Analysis.Namespace); err != nil {
+		s.anomaly.respondError(w, http.StatusForbidden, err.Message, err.Details, err.Code)
+		return
+	}
+
+	now := s.now().UTC()
+	sub := &AnomalySubscription{
+		ID:         "sub-" + uuid.New().String(),
+		URL:        req.URL,
+		Interval:   interval.String(),
+		Analysis:   req.Analysis,
+		CreatedAt:  now,
+		NextScanAt: now,
+		secret:     req.Secret,
+		interval:   interval,
+	}
+
+	s.mu.Lock()
+	if len(s.subscriptions) >= s.opts.MaxSubscriptions {
+		s.mu.Unlock()
+		s.anomaly.respondError(w, http.StatusConflict, fmt.Sprintf("at most %d anomaly subscriptions can be registered", s.opts.MaxSubscriptions), "", ErrCodeSubscriptionLimitReached)
+		return
+	}
+	s.subscriptions[sub.ID] = sub
+	created := *sub
+	s.mu.Unlock()
+
+	s.log.WithFields(logrus.Fields{
+		"subscription_id": sub.ID,
+		"url":             webhookHost(sub.URL),
+		"interval":        sub.Interval,
+		"namespace":       sub.Analysis.Namespace,
+	}).Info("Anomaly subscription created")
+	s.anomaly.respondJSON(w, http.StatusCreated, created)
+}
+
+// validate checks a subscription request and returns its scan interval
+func (s *AnomalySubscriptions) validate(req *AnomalySubscriptionRequest) (time.Duration, error) {
+	var errs validation.Errors
+	switch {
+	case req.URL == "":
+		errs.Add("url", validation.ConstraintRequired, nil, "url is required")
+	case !strings.HasPrefix(req.URL, "http://") && !strings.HasPrefix(req.URL, "https://"):
+		errs.Add("url", validation.ConstraintFormat, req.URL, "url must start with http:// or https://")
+	}
+
+	interval := DefaultSubscriptionInterval
+	if req.Interval != "" {
+		parsed, err := time.ParseDuration(req.Interval)
+		switch {
+		case err != nil:
+			errs.Add("interval", validation.ConstraintFormat, req.Interval, "interval must be a duration such as 15m")
+		case parsed < s.opts.MinInterval:
+			errs.Add("interval", validation.ConstraintRange, req.Interval, "interval must be at least "+s.opts.MinInterval.String())
+		default:
+			interval = parsed
+		}
+	}
+
+	// Scope defaults are applied at scan time; only validate what the caller sent
+	analysis := req.Analysis
+	s.anomaly.setRequestDefaults(&analysis)
+	if err := validation.Nest("analysis", s.anomaly.validateRequest(&analysis)); err != nil {
+		errs = append(errs, validation.Fields(err)...)
+	}
+	return interval, errs.Err()
+}
+
+// List handles GET /api/v1/anomalies/subscriptions
+func (s *AnomalySubscriptions) List(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	subscriptions := make([]AnomalySubscription, 0, len(s.subscriptions))
+	for _, sub := range s.subscriptions {
+		subscriptions = append(subscriptions, *sub)
+	}
+	s.mu.Unlock()
+
+	sort.Slice(subscriptions, func(i, j int) bool {
+		return subscriptions[i].CreatedAt.Before(subscriptions[j].CreatedAt)
+	})
+	s.anomaly.respondJSON(w, http.StatusOK, AnomalySubscriptionsResponse{Subscriptions: subscriptions, Count: len(subscriptions)})
+}
+
+// Get handles GET /api/v1/anomalies/subscriptions/{id}
+func (s *AnomalySubscriptions) Get(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	s.mu.Lock()
+	sub, ok := s.subscriptions[id]
+	var found AnomalySubscription
+	if ok {
+		found = *sub
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		s.anomaly.respondError(w, http.StatusNotFound, "anomaly subscription not found: "+id, "", ErrCodeSubscriptionNotFound)
+		return
+	}
+	s.anomaly.respondJSON(w, http.StatusOK, found)
+}
+
+// Delete handles DELETE /api/v1/anomalies/subscriptions/{id}
+func (s *AnomalySubscriptions) Delete(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	s.mu.Lock()
+	_, ok := s.subscriptions[id]
+	delete(s.subscriptions, id)
+	s.mu.Unlock()
+
+	if !ok {
+		s.anomaly.respondError(w, http.StatusNotFound, "anomaly subscription not found: "+id, "", ErrCodeSubscriptionNotFound)
+		return
+	}
+	s.log.WithField("subscription_id", id).Info("Anomaly subscription deleted")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// runDue scans every subscription whose next scan is due. Scans of one subscription
+// never overlap; a scan still running when the next one is due delays it.
+func (s *AnomalySubscriptions) runDue(ctx context.Context) {
+	now := s.now()
+
+	s.mu.Lock()
+	var due []*AnomalySubscription
+	for _, sub := range s.subscriptions {
+		if !sub.running && !now.Before(sub.NextScanAt) {
+			sub.running = true
+			due = append(due, sub)
+		}
+	}
+	s.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, sub := range due {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.scan(ctx, sub)
+		}()
+	}
+	wg.Wait()
+}
+
+// scan runs one scheduled scan and delivers its result
+func (s *AnomalySubscriptions) scan(ctx context.Context, sub *AnomalySubscription) {
+	s.mu.Lock()
+	req := sub.Analysis
+	target := notification.Target{Name: "anomaly-subscription " + sub.ID, URL: sub.URL, Secret: sub.secret}
+	s.mu.Unlock()
+
+	var response *AnomalyAnalyzeResponse
+	var err error
+	if scopeErr := s.onboarded(ctx, req.Namespace); scopeErr != nil {
+		err = scopeErr
+	} else {
+		scanCtx, cancel := context.WithTimeout(ctx, subscriptionScanTimeout)
+		response, err = s.anomaly.Analyze(scanCtx, &req)
+		cancel()
+	}
+
+	var body []byte
+	event := AnomalyScanEvent{
+		ID:             "evt-" + uuid.New().String(),
+		Type:           notification.EventAnomalyScanCompleted,
+		Timestamp:      s.now().UTC(),
+		SubscriptionID: sub.ID,
+		Result:         response,
+	}
+	if err == nil {
+		body, err = json.Marshal(event)
+	}
+
+	s.mu.Lock()
+	finished := s.now().UTC()
+	sub.running = false
+	sub.LastScanAt = &finished
+	sub.NextScanAt = finished.Add(sub.interval)
+	sub.Scans++
+	sub.LastError = ""
+	if err != nil {
+		sub.LastError = scanError(err)
+	}
+	_, active := s.subscriptions[sub.ID]
+	s.mu.Unlock()
+
+	if err != nil {
+		s.log.WithError(err).WithField("subscription_id", sub.ID).Warn("Scheduled anomaly scan failed")
+		return
+	}
+	if !active {
+		return
+	}
+	s.notifier.Deliver(ctx, target, event.ID, event.Type, body)
+}
+
+// onboarded returns an error if tenants are enabled and no tenant onboards namespace.
+// Cluster-wide scans cover every tenant's namespaces and are not allowed then.
+func (s *AnomalySubscriptions) onboarded(ctx context.Context, namespace string) *RequestError {
+	if s.tenants == nil || s.tenants.For(ctx, namespace) != nil {
+		return nil
+	}
+	if namespace == "" {
+		return &RequestError{
+			StatusCode: http.StatusForbidden,
+			Message:    "scheduled scans must target an onboarded namespace",
+			Details:    "cluster-wide scans are disabled while tenant onboarding is enabled",
+			Code:       ErrCodeScopeNotOnboarded,
+		}
+	}
+	return &RequestError{
+		StatusCode: http.StatusForbidden,
+		Message:    "namespace is not onboarded: " + namespace,
+		Details:    "onboard it through POST /api/v1/tenants",
+		Code:       ErrCodeScopeNotOnboarded,
+	}
+}
+
+// scanError describes a failed scan for the subscription status
+func scanError(err error) string {
+	var requestErr *RequestError
+	if errors.As(err, &requestErr) && requestErr.Code != "" {
+		return requestErr.Code + ": " + requestErr.Error()
+	}
+	return err.Error()
+}
+
+// webhookHost returns the scheme and host of a webhook URL for logging; paths and
+// query strings may hold credentials
+func webhookHost(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return parsed.Scheme + "://" + parsed.Host
+}
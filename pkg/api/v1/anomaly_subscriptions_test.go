@@ -0,0 +1,199 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/notification"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/kserve"
+)
+
+func TestAnomalySubscriptions_CRUD(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	notifier, err := notification.NewWebhookNotifier(nil, notification.Options{}, log)
+	require.NoError(t, err)
+	subscriptions := NewAnomalySubscriptions(NewAnomalyHandler(nil, nil, log), notifier, AnomalySubscriptionOptions{MaxSubscriptions: 2}, log)
+	router := mux.NewRouter()
+	subscriptions.RegisterRoutes(router)
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("validation", func(t *testing.T) {
+		tests := []struct {
+			name  string
+			body  string
+			field string
+		}{
+			{"missing url", `{"analysis": {"namespace": "app"}}`, "url"},
+			{"bad url scheme", `{"url": "ftp://hooks.example.com", "analysis": {}}`, "url"},
+			{"bad interval", `{"url": "https://hooks.example.com", "interval": "often"}`, "interval"},
+			{"interval below minimum", `{"url": "https://hooks.example.com", "interval": "30s"}`, "interval"},
+			{"invalid analysis", `{"url": "https://hooks.example.com", "analysis": {"time_range": "2w"}}`, "analysis.time_range"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				w := do("POST", "/api/v1/anomalies/subscriptions", tt.body)
+				require.Equal(t, http.StatusBadRequest, w.Code)
+
+				var resp AnomalyErrorResponse
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+				assert.Equal(t, ErrCodeAnomalyInvalidRequest, resp.Code)
+				require.NotEmpty(t, resp.Errors)
+				assert.Equal(t, tt.field, resp.Errors[0].Field)
+			})
+		}
+	})
+
+	w := do("POST", "/api/v1/anomalies/subscriptions", `{"url": "https://hooks.example.com/scan?token=x", "secret": "s3cret", "interval": "15m", "analysis": {"namespace": "app"}}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+	assert.NotContains(t, w.Body.String(), "s3cret")
+	var created AnomalySubscription
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&created))
+	assert.True(t, strings.HasPrefix(created.ID, "sub-"))
+	assert.Equal(t, "15m0s", created.Interval)
+	assert.Equal(t, "app", created.Analysis.Namespace)
+
+	w = do("POST", "/api/v1/anomalies/subscriptions", `{"url": "https://hooks.example.com/other"}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+	var defaulted AnomalySubscription
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&defaulted))
+	assert.Equal(t, DefaultSubscriptionInterval.String(), defaulted.Interval)
+
+	w = do("POST", "/api/v1/anomalies/subscriptions", `{"url": "https://hooks.example.com/third"}`)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), ErrCodeSubscriptionLimitReached)
+
+	w = do("GET", "/api/v1/anomalies/subscriptions", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var list AnomalySubscriptionsResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&list))
+	assert.Equal(t, 2, list.Count)
+
+	w = do("GET", "/api/v1/anomalies/subscriptions/"+created.ID, "")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = do("DELETE", "/api/v1/anomalies/subscriptions/"+created.ID, "")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	w = do("GET", "/api/v1/anomalies/subscriptions/"+created.ID, "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), ErrCodeSubscriptionNotFound)
+
+	w = do("DELETE", "/api/v1/anomalies/subscriptions/"+created.ID, "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestAnomalySubscriptions_RunDue(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	model := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Instances [][]float64 `json:"instances"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		predictions := make([]int, len(req.Instances))
+		for i := range predictions {
+			predictions[i] = 1
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"predictions": predictions})
+	}))
+	defer model.Close()
+
+	var (
+		mu       sync.Mutex
+		received []AnomalyScanEvent
+		headers  []http.Header
+	)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event AnomalyScanEvent
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&event))
+		mu.Lock()
+		received = append(received, event)
+		headers = append(headers, r.Header.Clone())
+		mu.Unlock()
+	}))
+	defer receiver.Close()
+
+	os.Setenv("KSERVE_ANOMALY_DETECTOR_SERVICE", "anomaly-detector-predictor")
+	defer os.Unsetenv("KSERVE_ANOMALY_DETECTOR_SERVICE")
+	client, err := kserve.NewProxyClient(kserve.ProxyConfig{Namespace: "test-ns"}, log)
+	require.NoError(t, err)
+	rollouts, err := kserve.NewRollouts(map[string]kserve.ModelRollout{
+		"anomaly-detector": {Versions: map[string]string{"v1": model.URL}, Pinned: "v1"},
+	})
+	require.NoError(t, err)
+	client.SetRollouts(rollouts)
+
+	notifier, err := notification.NewWebhookNotifier(nil, notification.Options{MaxAttempts: 1}, log)
+	require.NoError(t, err)
+	subscriptions := NewAnomalySubscriptions(NewAnomalyHandler(client, nil, log), notifier, AnomalySubscriptionOptions{}, log)
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	subscriptions.now = func() time.Time { return now }
+	router := mux.NewRouter()
+	subscriptions.RegisterRoutes(router)
+
+	req := httptest.NewRequest("POST", "/api/v1/anomalies/subscriptions", bytes.NewBufferString(
+		`{"url": "`+receiver.URL+`", "interval": "10m", "analysis": {"namespace": "app"}}`))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+	var created AnomalySubscription
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&created))
+
+	// New subscriptions are scanned on the next tick
+	subscriptions.runDue(context.Background())
+	notifier.Wait()
+
+	mu.Lock()
+	require.Len(t, received, 1)
+	event := received[0]
+	assert.Equal(t, string(notification.EventAnomalyScanCompleted), headers[0].Get(notification.HeaderEvent))
+	mu.Unlock()
+	assert.Equal(t, notification.EventAnomalyScanCompleted, event.Type)
+	assert.Equal(t, created.ID, event.SubscriptionID)
+	require.NotNil(t, event.Result)
+	assert.Equal(t, "success", event.Result.Status)
+	assert.Equal(t, "app", event.Result.Scope.Namespace)
+
+	sub, ok := subscriptions.subscriptions[created.ID]
+	require.True(t, ok)
+	assert.Equal(t, 1, sub.Scans)
+	assert.Empty(t, sub.LastError)
+	assert.Equal(t, now.Add(10*time.Minute), sub.NextScanAt)
+
+	// Not due again until the interval has passed
+	subscriptions.runDue(context.Background())
+	notifier.Wait()
+	mu.Lock()
+	assert.Len(t, received, 1)
+	mu.Unlock()
+
+	now = now.Add(10 * time.Minute)
+	subscriptions.runDue(context.Background())
+	notifier.Wait()
+	mu.Lock()
+	assert.Len(t, received, 2)
+	mu.Unlock()
+}
@@ -2,10 +2,14 @@ package v1
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -13,8 +17,21 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
-
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/anomaly"
+	"github.com/tosin2013/openshift-coordination-engine/internal/detector"
+	"github.com/tosin2013/openshift-coordination-engine/internal/featureflag"
+	"github.com/tosin2013/openshift-coordination-engine/internal/integrations"
+	"github.com/tosin2013/openshift-coordination-engine/internal/redact"
+	"github.com/tosin2013/openshift-coordination-engine/internal/severity"
+	"github.com/tosin2013/openshift-coordination-engine/internal/tenant"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/diagnostics"
 	"github.com/tosin2013/openshift-coordination-engine/pkg/kserve"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/scoring"
 )
 
 func TestAnomalyHandler_AnalyzeAnomalies_Validation(t *testing.T) {
@@ -233,6 +250,63 @@ func TestAnomalyHandler_AnalyzeAnomalies_WithKServe(t *testing.T) {
 	})
 }
 
+func TestAnomalyHandler_ScopeDefaults(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	defaults, err := anomaly.NewScopeDefaults("", []anomaly.ScopeDefaultRule{
+		{Name: "production", Namespaces: []string{"prod-*"}, Model: "anomaly-detector-v2", Threshold: 0.8},
+		{Name: "sandboxes", Namespaces: []string{"sandbox-*"}, Model: "anomaly-detector-lite"},
+	})
+	require.NoError(t, err)
+	handler := NewAnomalyHandler(nil, nil, log)
+	handler.SetScopeDefaults(defaults)
+	ctx := context.Background()
+
+	t.Run("fills omitted model and threshold", func(t *testing.T) {
+		req := &AnomalyAnalyzeRequest{Scope: models.Scope{Namespace: "prod-eu"}}
+		assert.Equal(t, "production", handler.applyScopeDefaults(ctx, req))
+		handler.setRequestDefaults(req)
+		assert.Equal(t, "anomaly-detector-v2", req.ModelName)
+		assert.Equal(t, 0.8, req.Threshold)
+	})
+
+	t.Run("rule without threshold keeps the built-in default", func(t *testing.T) {
+		req := &AnomalyAnalyzeRequest{Scope: models.Scope{Namespace: "sandbox-1"}}
+		assert.Equal(t, "sandboxes", handler.applyScopeDefaults(ctx, req))
+		handler.setRequestDefaults(req)
+		assert.Equal(t, "anomaly-detector-lite", req.ModelName)
+		assert.Equal(t, 0.7, req.Threshold)
+	})
+
+	t.Run("explicit values win", func(t *testing.T) {
+		req := &AnomalyAnalyzeRequest{Scope: models.Scope{Namespace: "prod-eu"}, ModelName: "anomaly-detector", Threshold: 0.5}
+		assert.Empty(t, handler.applyScopeDefaults(ctx, req))
+		assert.Equal(t, "anomaly-detector", req.ModelName)
+		assert.Equal(t, 0.5, req.Threshold)
+	})
+
+	t.Run("unmatched scope uses built-in defaults", func(t *testing.T) {
+		req := &AnomalyAnalyzeRequest{Scope: models.Scope{Namespace: "dev"}}
+		assert.Empty(t, handler.applyScopeDefaults(ctx, req))
+		handler.setRequestDefaults(req)
+		assert.Equal(t, "anomaly-detector", req.ModelName)
+	})
+
+	t.Run("tenant thresholds come before scope rules", func(t *testing.T) {
+		registry := tenant.NewRegistry(t.TempDir(), fake.NewSimpleClientset())
+		_, err := registry.Create(&tenant.Tenant{Name: "payments", Namespace: "prod-payments", Thresholds: tenant.Thresholds{Anomaly: 0.95}})
+		require.NoError(t, err)
+		handler.SetTenants(registry)
+		defer handler.SetTenants(nil)
+
+		req := &AnomalyAnalyzeRequest{Scope: models.Scope{Namespace: "prod-payments"}}
+		assert.Equal(t, "tenant/payments, production", handler.applyScopeDefaults(ctx, req))
+		assert.Equal(t, "anomaly-detector-v2", req.ModelName)
+		assert.Equal(t, 0.95, req.Threshold)
+	})
+}
+
 func TestAnomalyHandler_RequestDefaults(t *testing.T) {
 	log := logrus.New()
 	log.SetLevel(logrus.ErrorLevel)
@@ -327,6 +401,13 @@ func TestAnomalyHandler_ValidateRequest(t *testing.T) {
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "threshold must be between")
 	})
+
+	t.Run("pod scope requires namespace", func(t *testing.T) {
+		req := &AnomalyAnalyzeRequest{Scope: models.Scope{Pod: "api-0"}, TimeRange: "1h", Threshold: 0.7}
+		err := handler.validateRequest(req)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "namespace is required when scope is 'pod'")
+	})
 }
 
 func TestAnomalyHandler_RegisterRoutes(t *testing.T) {
@@ -352,8 +433,7 @@ func TestAnomalyHandler_BuildScope(t *testing.T) {
 
 	t.Run("pod scope", func(t *testing.T) {
 		req := &AnomalyAnalyzeRequest{
-			Namespace: "self-healing-platform",
-			Pod:       "broken-app-xyz",
+			Scope: models.Scope{Namespace: "self-healing-platform", Pod: "broken-app-xyz"},
 		}
 		scope := handler.buildScope(req)
 
@@ -365,8 +445,7 @@ func TestAnomalyHandler_BuildScope(t *testing.T) {
 
 	t.Run("deployment scope", func(t *testing.T) {
 		req := &AnomalyAnalyzeRequest{
-			Namespace:  "self-healing-platform",
-			Deployment: "broken-app",
+			Scope: models.Scope{Namespace: "self-healing-platform", Deployment: "broken-app"},
 		}
 		scope := handler.buildScope(req)
 
@@ -377,7 +456,7 @@ func TestAnomalyHandler_BuildScope(t *testing.T) {
 
 	t.Run("namespace scope", func(t *testing.T) {
 		req := &AnomalyAnalyzeRequest{
-			Namespace: "self-healing-platform",
+			Scope: models.Scope{Namespace: "self-healing-platform"},
 		}
 		scope := handler.buildScope(req)
 
@@ -399,7 +478,7 @@ func TestAnomalyHandler_BuildFeatureInfo(t *testing.T) {
 
 	handler := NewAnomalyHandler(nil, nil, log)
 
-	featureInfo := handler.buildFeatureInfo()
+	featureInfo := handler.buildFeatureInfo("anomaly-detector")
 
 	assert.Equal(t, 45, featureInfo.TotalFeatures)
 	assert.Equal(t, 9, featureInfo.FeaturesPerMetric)
@@ -529,6 +608,40 @@ func TestAnomalyHandler_CalculateAnomalyScore(t *testing.T) {
 	})
 }
 
+func TestAnomalyHandler_ScoringHooks(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	handler := NewAnomalyHandler(nil, nil, log)
+	maintenance, err := scoring.NewMaintenance([]string{"payments-*"})
+	require.NoError(t, err)
+	pipeline, err := scoring.NewPipeline(maintenance)
+	require.NoError(t, err)
+	handler.SetScoringPipeline(pipeline)
+
+	metrics := map[string]float64{"pod_cpu_usage": 0.95, "pod_memory_usage": 0.95, "container_restart_count": 5}
+	resp := &kserve.DetectResponse{Predictions: []int{-1}}
+	ctx := context.Background()
+
+	for namespace, detected := range map[string]int{"payments-eu": 0, "orders": 1} {
+		req := &AnomalyAnalyzeRequest{Scope: models.Scope{Namespace: namespace}, ModelName: "anomaly-detector", Threshold: 0.7}
+		input := &scoring.Input{Namespace: namespace, Metrics: metrics}
+		result := handler.scorePrediction(resp, metrics)
+		require.True(t, result.Anomalous)
+		require.NoError(t, handler.scoring.After(ctx, input, &result))
+
+		response := handler.buildAnalysisResponse(req, resp, nil, metrics, result)
+		assert.Equal(t, detected, response.AnomaliesDetected, namespace)
+		assert.Equal(t, []string{scoring.MaintenanceHookName}, response.ScoringHooks)
+		if detected == 0 {
+			assert.Equal(t, "namespace payments-eu is in maintenance", response.Suppressed)
+			assert.Equal(t, scoring.MaintenanceHookName, response.SuppressedBy)
+		} else {
+			assert.Empty(t, response.Suppressed)
+		}
+	}
+}
+
 func TestAnomalyHandler_GenerateExplanation(t *testing.T) {
 	log := logrus.New()
 	log.SetLevel(logrus.ErrorLevel)
@@ -775,8 +888,7 @@ func TestAnomalyAnalyzeResponse_Structure(t *testing.T) {
 		Status:    "success",
 		TimeRange: "1h",
 		Scope: AnomalyScope{
-			Namespace:         "self-healing-platform",
-			Deployment:        "broken-app",
+			Scope:             models.Scope{Namespace: "self-healing-platform", Deployment: "broken-app"},
 			TargetDescription: "deployment 'broken-app' in namespace 'self-healing-platform'",
 		},
 		ModelUsed:         "anomaly-detector",
@@ -897,3 +1009,550 @@ func TestAnomalyHandler_BuildAnomalyResult(t *testing.T) {
 		assert.Equal(t, "info", result.Severity)
 	})
 }
+
+func TestApplyRolloutContext(t *testing.T) {
+	anomaly := AnomalyResult{
+		Explanation:       "CPU usage elevated (92%)",
+		RecommendedAction: "scale_resources",
+	}
+	changes := []detector.RolloutChange{
+		{
+			Namespace:    "production",
+			Deployment:   "api",
+			Revision:     7,
+			ChangedAt:    time.Now().Add(-10 * time.Minute),
+			ImageChanged: true,
+		},
+	}
+
+	applyRolloutContext(&anomaly, changes)
+
+	assert.Contains(t, anomaly.Explanation, "CPU usage elevated (92%)")
+	assert.Contains(t, anomaly.Explanation, "recent deployment change detected")
+	assert.Contains(t, anomaly.Explanation, "revision 7")
+	assert.Contains(t, anomaly.Explanation, "image changed")
+	assert.Equal(t, "scale_resources", anomaly.RecommendedAction, "primary action is unchanged")
+	assert.Equal(t, []string{"rollback_deployment"}, anomaly.AlternativeActions)
+	assert.Len(t, anomaly.RecentChanges, 1)
+}
+
+func TestAnomalyHandler_RedactExplanations(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	handler := NewAnomalyHandler(nil, nil, log)
+	response := &AnomalyAnalyzeResponse{Anomalies: []AnomalyResult{
+		{Explanation: "Restarts after rollout of api (DATABASE_URL=postgres://app:pa55@db/orders)"},
+	}}
+
+	// Without a redactor explanations are unchanged
+	handler.redactExplanations(response)
+	assert.Contains(t, response.Anomalies[0].Explanation, "pa55")
+
+	redactor, err := redact.New(nil)
+	require.NoError(t, err)
+	handler.SetRedactor(redactor)
+	handler.redactExplanations(response)
+	assert.Equal(t, "Restarts after rollout of api (DATABASE_URL=postgres://app:[REDACTED]@db/orders)", response.Anomalies[0].Explanation)
+}
+
+func TestAnomalyHandler_AnnotateRecentChanges_NoDetector(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	handler := NewAnomalyHandler(nil, nil, log)
+
+	response := AnomalyAnalyzeResponse{
+		Anomalies: []AnomalyResult{{Explanation: "Memory usage high (95%)"}},
+	}
+	handler.annotateRecentChanges(context.Background(), &AnomalyAnalyzeRequest{Scope: models.Scope{Namespace: "default"}}, &response, time.Now())
+
+	assert.Equal(t, "Memory usage high (95%)", response.Anomalies[0].Explanation)
+	assert.Empty(t, response.Anomalies[0].AlternativeActions)
+}
+
+func TestAnomalyHandler_AnnotateMachineConfigUpdates(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	handler := NewAnomalyHandler(nil, nil, log)
+	handler.SetMachineConfigUpdateDetector(newUpdatingMCPDetector(t))
+
+	newResponse := func() AnomalyAnalyzeResponse {
+		return AnomalyAnalyzeResponse{
+			Anomalies: []AnomalyResult{{
+				Severity:          "critical",
+				Explanation:       "Container restarts detected (6)",
+				RecommendedAction: "restart_pod",
+			}},
+			Recommendation: "CRITICAL: Immediate investigation recommended.",
+		}
+	}
+
+	response := newResponse()
+	handler.annotateMachineConfigUpdates(context.Background(), &AnomalyAnalyzeRequest{Scope: models.Scope{Namespace: "apps", Pod: "api-1"}}, &response)
+
+	anomaly := response.Anomalies[0]
+	assert.True(t, anomaly.ExpectedChurn)
+	assert.Equal(t, "warning", anomaly.Severity)
+	assert.Equal(t, "wait_for_machineconfig_update", anomaly.RecommendedAction)
+	assert.Equal(t, []string{"restart_pod"}, anomaly.AlternativeActions)
+	assert.Contains(t, anomaly.Explanation, "MachineConfigPool update in progress ('worker' 1/3 machines updated)")
+	require.Len(t, anomaly.MachineConfigUpdates, 1)
+	assert.Equal(t, []string{"worker-1"}, anomaly.MachineConfigUpdates[0].UpdatingNodes)
+	assert.Contains(t, response.Recommendation, "MachineConfigPool update")
+
+	// Pods on nodes outside the updating pool keep their severity
+	response = newResponse()
+	handler.annotateMachineConfigUpdates(context.Background(), &AnomalyAnalyzeRequest{Scope: models.Scope{Namespace: "apps", Pod: "router-1"}}, &response)
+	assert.False(t, response.Anomalies[0].ExpectedChurn)
+	assert.Equal(t, "critical", response.Anomalies[0].Severity)
+}
+
+func TestAnomalyHandler_AnnotateBusinessWindows(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	handler := NewAnomalyHandler(nil, nil, log)
+
+	newResponse := func() AnomalyAnalyzeResponse {
+		return AnomalyAnalyzeResponse{
+			Anomalies: []AnomalyResult{{Severity: "critical", Explanation: "High CPU utilization"}},
+		}
+	}
+
+	response := newResponse()
+	handler.annotateBusinessWindows(&response, []anomaly.ActiveWindow{{Calendar: "trading", Window: "market-hours", ThresholdIncrease: 0.1}})
+	assert.True(t, response.Anomalies[0].ExpectedLoad)
+	assert.Equal(t, "critical", response.Anomalies[0].Severity)
+	assert.Equal(t, "High CPU utilization; during expected busy period (trading/market-hours)", response.Anomalies[0].Explanation)
+
+	response = newResponse()
+	handler.annotateBusinessWindows(&response, []anomaly.ActiveWindow{
+		{Calendar: "trading", Window: "market-hours"},
+		{Calendar: "trading", Window: "month-end", LowerSeverity: true},
+	})
+	assert.Equal(t, "warning", response.Anomalies[0].Severity)
+	assert.Contains(t, response.Anomalies[0].Explanation, "(trading/market-hours, trading/month-end)")
+
+	response = newResponse()
+	handler.annotateBusinessWindows(&response, nil)
+	assert.False(t, response.Anomalies[0].ExpectedLoad)
+}
+
+func TestAnomalyHandler_PrioritizeAnomalies(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	handler := NewAnomalyHandler(nil, nil, log)
+
+	newResponse := func() AnomalyAnalyzeResponse {
+		return AnomalyAnalyzeResponse{
+			Anomalies: []AnomalyResult{{Severity: "warning", AnomalyScore: 0.75}},
+		}
+	}
+	req := &AnomalyAnalyzeRequest{Scope: models.Scope{Namespace: "payments"}}
+
+	// The default matrix keeps the score bands
+	response := newResponse()
+	handler.prioritizeAnomalies(context.Background(), req, &response, nil)
+	assert.Equal(t, "warning", response.Anomalies[0].Severity)
+	assert.Equal(t, 75, response.Anomalies[0].Priority)
+
+	// A critical namespace with many running pods raises the severity
+	handler.SetSeverityMatrix(severity.DefaultMatrix(), func(_ context.Context, namespace string) string {
+		return map[string]string{"payments": "critical", "sandbox": "low"}[namespace]
+	})
+	response = newResponse()
+	handler.prioritizeAnomalies(context.Background(), req, &response, &anomaly.PodScope{Running: []string{"api-1", "api-2", "api-3", "api-4"}})
+	assert.Equal(t, "critical", response.Anomalies[0].Severity)
+	assert.Equal(t, 95, response.Anomalies[0].Priority)
+	assert.Contains(t, response.Recommendation, "CRITICAL")
+
+	// A low tier namespace lowers it
+	response = newResponse()
+	handler.prioritizeAnomalies(context.Background(), &AnomalyAnalyzeRequest{Scope: models.Scope{Namespace: "sandbox"}}, &response, nil)
+	assert.Equal(t, "info", response.Anomalies[0].Severity)
+	assert.Equal(t, 60, response.Anomalies[0].Priority)
+
+	// Routes failing probes burn the SLO
+	response = newResponse()
+	response.ExternalAvailability = []integrations.RouteAvailability{{Availability: 0.95}}
+	handler.prioritizeAnomalies(context.Background(), &AnomalyAnalyzeRequest{}, &response, nil)
+	assert.Equal(t, 95, response.Anomalies[0].Priority)
+
+	// Downgrades lower the priority with the severity
+	response.Anomalies[0].Severity = "critical"
+	handler.annotateBusinessWindows(&response, []anomaly.ActiveWindow{{Calendar: "trading", Window: "month-end", LowerSeverity: true}})
+	assert.Equal(t, "warning", response.Anomalies[0].Severity)
+	assert.Equal(t, 89, response.Anomalies[0].Priority)
+}
+
+func TestAnomalyHandler_ApplyPostFilters(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	handler := NewAnomalyHandler(nil, nil, log)
+	filters, err := anomaly.NewPostFilters([]anomaly.PostFilter{
+		{Name: "sandbox-quiet", Namespaces: []string{"sandbox"}, Action: anomaly.FilterActionLowerSeverity},
+		{Name: "sandbox-info", Namespaces: []string{"sandbox"}, Severities: []string{"info"}, Action: anomaly.FilterActionDrop},
+	})
+	require.NoError(t, err)
+
+	newResponse := func() AnomalyAnalyzeResponse {
+		return AnomalyAnalyzeResponse{
+			AnomaliesDetected: 2,
+			Anomalies: []AnomalyResult{
+				{Severity: "critical", Priority: 95, AnomalyScore: 0.95},
+				{Severity: "warning", Priority: 75, AnomalyScore: 0.75},
+			},
+		}
+	}
+	req := &AnomalyAnalyzeRequest{Scope: models.Scope{Namespace: "sandbox"}}
+	at := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	// Without filters the response is untouched
+	response := newResponse()
+	handler.applyPostFilters(context.Background(), req, &response, at, nil)
+	assert.Equal(t, newResponse(), response)
+
+	handler.SetPostFilters(filters)
+	response = newResponse()
+	handler.applyPostFilters(context.Background(), req, &response, at, nil)
+	assert.Equal(t, []string{"sandbox-quiet", "sandbox-info"}, response.PostFilters)
+	require.Len(t, response.Anomalies, 1)
+	assert.Equal(t, 1, response.AnomaliesDetected)
+	assert.Equal(t, "warning", response.Anomalies[0].Severity)
+	assert.Equal(t, 89, response.Anomalies[0].Priority)
+	assert.Equal(t, []string{"sandbox-quiet"}, response.Anomalies[0].LoweredBy)
+	assert.Equal(t, 0.95, response.Summary.MaxScore)
+	require.Len(t, response.Filtered, 1)
+	assert.Equal(t, "sandbox-info", response.Filtered[0].Filter)
+	assert.Equal(t, anomaly.FilterActionDrop, response.Filtered[0].Action)
+	assert.Equal(t, "info", response.Filtered[0].Severity)
+	assert.Equal(t, 0.75, response.Filtered[0].AnomalyScore)
+
+	response = newResponse()
+	handler.applyPostFilters(context.Background(), &AnomalyAnalyzeRequest{Scope: models.Scope{Namespace: "payments"}}, &response, at, nil)
+	assert.Len(t, response.Anomalies, 2)
+	assert.Empty(t, response.Filtered)
+}
+
+func TestAnomalyHandler_GetRecordingRules(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	handler := NewAnomalyHandler(nil, nil, log)
+	router := mux.NewRouter()
+	handler.RegisterRoutes(router)
+
+	t.Run("returns PrometheusRule YAML", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/v1/anomalies/recording-rules?namespace=monitoring", http.NoBody)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "application/yaml", rr.Header().Get("Content-Type"))
+		assert.Contains(t, rr.Body.String(), "kind: PrometheusRule")
+		assert.Contains(t, rr.Body.String(), "namespace: monitoring")
+	})
+
+	t.Run("rejects invalid interval", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/v1/anomalies/recording-rules?interval=soon", http.NoBody)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+}
+
+func TestAnomalyHandler_RecordedFeaturesMatchRawQueries(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	promClient := integrations.NewPrometheusClient("http://prometheus", 5*time.Second, log)
+	promClient.SetUseRecordingRules(true)
+	handler := NewAnomalyHandler(nil, promClient, log)
+
+	exprs := make(map[string]string)
+	for _, group := range integrations.FeatureRecordingRules("30s") {
+		for _, rule := range group.Rules {
+			exprs[rule.Record] = rule.Expr
+		}
+	}
+
+	for _, metric := range baseMetrics {
+		t.Run(metric, func(t *testing.T) {
+			recorded, ok := promClient.RecordedFeatureQueries(metric, "payments", "", "")
+			require.True(t, ok)
+			record := strings.SplitN(recorded.Value, "{", 2)[0]
+			expr, ok := exprs[record]
+			require.True(t, ok, "no rule records %s", record)
+
+			// A recorded series selected by namespace must hold what the raw query returns
+			// for that namespace, so the rule may only add namespace to the raw grouping
+			raw := strings.ReplaceAll(handler.getMetricBaseQuery(metric, "", "", "", nil), "{}", "")
+			if recorded.PerPod {
+				assert.Contains(t, raw, "by (pod)")
+				expr = strings.ReplaceAll(expr, "by (namespace, pod)", "by (pod)")
+			}
+			assert.Equal(t, raw, expr)
+		})
+	}
+}
+
+func TestAnomalyHandler_BuildFeatureVector_QueryTooExpensive(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/api/v1/series" {
+			_, _ = w.Write([]byte(`{"status":"success","data":[{"pod":"a"},{"pod":"b"},{"pod":"c"}]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1700000000,"0.5"]}]}}`))
+	}))
+	defer server.Close()
+
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	promClient := integrations.NewPrometheusClient(server.URL, 5*time.Second, log)
+	promClient.SetMaxQuerySeries(2)
+
+	handler := NewAnomalyHandler(nil, promClient, log)
+	_, _, _, err := handler.buildFeatureVector(context.Background(), "anomaly-detector", "", "", "", nil, nil)
+	require.Error(t, err)
+
+	costErr, ok := integrations.IsQueryCostError(err)
+	require.True(t, ok)
+	assert.Contains(t, costErr.Suggestion, "namespace")
+}
+
+func TestAnomalyHandler_BuildFeatureVector_RecordsDebugTrace(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1700000000,"0.5"]}]}}`))
+	}))
+	defer server.Close()
+
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	promClient := integrations.NewPrometheusClient(server.URL, 5*time.Second, log)
+
+	handler := NewAnomalyHandler(nil, promClient, log)
+	trace := diagnostics.NewTrace()
+	ctx := diagnostics.WithTrace(context.Background(), trace)
+
+	_, _, _, err := handler.buildFeatureVector(ctx, "anomaly-detector", "default", "", "", nil, nil)
+	require.NoError(t, err)
+
+	report := trace.Report()
+	require.NotNil(t, report)
+	require.NotEmpty(t, report.Queries)
+	assert.Equal(t, "instant", report.Queries[0].Kind)
+	assert.Contains(t, report.Queries[0].Query, `namespace="default"`)
+}
+
+func TestAnomalyHandler_BuildFeatureVector_HistogramFeatures(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		queries []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query().Get("query")
+		mu.Lock()
+		queries = append(queries, query)
+		mu.Unlock()
+
+		value := "0.5"
+		switch {
+		case strings.HasPrefix(query, "avg_over_time((histogram_quantile"):
+			value = "NaN"
+		case strings.HasPrefix(query, "histogram_quantile"):
+			value = "0.25"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1700000000,"` + value + `"]}]}}`))
+	}))
+	defer server.Close()
+
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	histograms, err := integrations.ParseHistogramFeatures([]string{"api_latency_p99=http_request_duration_seconds:0.99"})
+	require.NoError(t, err)
+	handler := NewAnomalyHandler(nil, integrations.NewPrometheusClient(server.URL, 5*time.Second, log), log)
+	handler.SetHistogramFeatures(histograms, []string{"anomaly-latency"})
+
+	features, metricsData, _, err := handler.buildFeatureVector(context.Background(), "anomaly-latency", "default", "", "", nil, nil)
+	require.NoError(t, err)
+	require.Len(t, features, 54)
+	assert.Equal(t, 0.25, features[45], "histogram quantile value")
+	assert.Equal(t, 0.25, features[46], "NaN rolling mean falls back to the current value")
+	assert.Len(t, metricsData, len(baseMetrics), "histograms do not feed the heuristic score")
+	mu.Lock()
+	assert.Contains(t, queries, `histogram_quantile(0.99, sum by (le) (rate(http_request_duration_seconds_bucket{namespace="default"}[5m])))`)
+	mu.Unlock()
+
+	info := handler.buildFeatureInfo("anomaly-latency")
+	assert.Equal(t, 54, info.TotalFeatures)
+	assert.Equal(t, []string{"api_latency_p99"}, info.HistogramFeatures)
+	assert.Equal(t, "api_latency_p99_value", info.FeatureNames[45])
+
+	// Other models keep the 45 base features
+	features, _, _, err = handler.buildFeatureVector(context.Background(), "anomaly-detector", "default", "", "", nil, nil)
+	require.NoError(t, err)
+	assert.Len(t, features, 45)
+	assert.Empty(t, handler.buildFeatureInfo("anomaly-detector").HistogramFeatures)
+
+	// A disabled flag keeps the vector length but skips the queries
+	flags, err := featureflag.New(map[string]featureflag.Config{
+		featureflag.HistogramFeatures: {Namespaces: map[string]bool{"legacy-*": false}},
+	}, nil)
+	require.NoError(t, err)
+	handler.SetFeatureFlags(flags)
+	mu.Lock()
+	queries = nil
+	mu.Unlock()
+	features, _, quality, err := handler.buildFeatureVector(context.Background(), "anomaly-latency", "legacy-billing", "", "", nil, nil)
+	require.NoError(t, err)
+	require.Len(t, features, 54)
+	assert.Equal(t, handler.getDefaultMetricFeatures(), features[45:])
+	assert.Equal(t, []string{"api_latency_p99"}, quality.DisabledFeatures)
+	assert.False(t, quality.Degraded, "disabled features do not degrade the result")
+	mu.Lock()
+	for _, query := range queries {
+		assert.NotContains(t, query, "histogram_quantile")
+	}
+	mu.Unlock()
+}
+
+func TestAnomalyHandler_BuildFeatureVector_MissingExporters(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		queries []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query().Get("query")
+		mu.Lock()
+		queries = append(queries, query)
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		if query == "count(kube_pod_info)" {
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[]}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1700000000,"0.5"]}]}}`))
+	}))
+	defer server.Close()
+
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	promClient := integrations.NewPrometheusClient(server.URL, 5*time.Second, log)
+	promClient.ProbeCapabilities(context.Background())
+	handler := NewAnomalyHandler(nil, promClient, log)
+
+	features, metricsData, quality, err := handler.buildFeatureVector(context.Background(), "anomaly-detector", "default", "", "", nil, nil)
+	require.NoError(t, err)
+	assert.Len(t, features, 45)
+	assert.True(t, quality.Degraded)
+	assert.False(t, quality.PrometheusUnavailable)
+	assert.Equal(t, []string{integrations.ExporterKubeStateMetrics}, quality.MissingExporters)
+	assert.Equal(t, []string{"pod_memory_usage", "container_restart_count"}, quality.DefaultedMetrics)
+	assert.Equal(t, handler.defaultMetricValue, metricsData["container_restart_count"])
+	assert.Equal(t, 0.5, metricsData["pod_cpu_usage"])
+
+	mu.Lock()
+	for _, query := range queries {
+		assert.NotContains(t, query, "kube_pod_container_status_restarts_total", "queries of missing exporters are skipped")
+	}
+	mu.Unlock()
+}
+
+func TestAnomalyHandler_BuildFeatureVector_ExcludesInactivePods(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		queries []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		queries = append(queries, r.URL.Query().Get("query"))
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1700000000,"0.5"]}]}}`))
+	}))
+	defer server.Close()
+
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	terminating := metav1.Now()
+	client := fake.NewSimpleClientset(
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "api-1", Namespace: "apps"}, Status: corev1.PodStatus{Phase: corev1.PodRunning}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "api-0", Namespace: "apps", DeletionTimestamp: &terminating}, Status: corev1.PodStatus{Phase: corev1.PodRunning}},
+	)
+	handler := NewAnomalyHandler(nil, integrations.NewPrometheusClient(server.URL, 5*time.Second, log), log)
+	handler.SetScopeResolver(anomaly.NewScopeResolver(client, 0))
+
+	req := &AnomalyAnalyzeRequest{Scope: models.Scope{Namespace: "apps", Deployment: "api"}}
+	pods := handler.resolvePods(context.Background(), req)
+	require.NotNil(t, pods)
+
+	_, _, _, err := handler.buildFeatureVector(context.Background(), "anomaly-detector", req.Namespace, req.Pod, req.Deployment, pods, nil)
+	require.NoError(t, err)
+	mu.Lock()
+	assert.Contains(t, queries, `sum(kube_pod_container_status_restarts_total{namespace="apps",pod=~"api-1"}) by (pod)`)
+	for _, query := range queries {
+		assert.NotContains(t, query, `api-.*`, "the running pods replace the deployment prefix")
+	}
+	mu.Unlock()
+
+	response := AnomalyAnalyzeResponse{Scope: handler.buildScope(req)}
+	annotatePodScope(&response, pods)
+	assert.Equal(t, map[string]int{anomaly.PodPhaseRunning: 1, anomaly.PodPhaseTerminating: 1}, response.Scope.PodPhases)
+	assert.Equal(t, "deployment 'api' in namespace 'apps' (pods: 1 running, 1 terminating excluded)", response.Scope.TargetDescription)
+
+	// Pod scopes name their pod and are not resolved
+	assert.Nil(t, handler.resolvePods(context.Background(), &AnomalyAnalyzeRequest{Scope: models.Scope{Namespace: "apps", Pod: "api-0"}}))
+}
+
+func TestAnomalyAnalyzeResponse_DebugOmittedByDefault(t *testing.T) {
+	data, err := json.Marshal(AnomalyAnalyzeResponse{Status: "success"})
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), `"debug"`)
+}
+
+func TestAnomalyHandler_Analyze_RaisesThresholdDuringUpgrade(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	handler := NewAnomalyHandler(nil, nil, log)
+	handler.SetUpgradeMonitor(newUpgradingMonitor(t))
+
+	req := &AnomalyAnalyzeRequest{Scope: models.Scope{Namespace: "apps"}}
+	_, err := handler.Analyze(context.Background(), req)
+
+	// KServe is not configured, but the request was adjusted before inference
+	var requestErr *RequestError
+	require.True(t, errors.As(err, &requestErr))
+	assert.Equal(t, ErrCodeAnomalyKServeUnavailable, requestErr.Code)
+	assert.InDelta(t, 0.85, req.Threshold, 1e-9)
+}
+
+func TestAnomalyHandler_Analyze_Retroactive(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	handler := NewAnomalyHandler(nil, nil, log)
+	handler.SetUpgradeMonitor(newUpgradingMonitor(t))
+
+	// The upgrade state is only known for now and does not adjust retroactive analyses
+	req := &AnomalyAnalyzeRequest{Scope: models.Scope{Namespace: "apps"}, At: time.Now().Add(-9 * time.Hour).Format(time.RFC3339)}
+	_, err := handler.Analyze(context.Background(), req)
+	var requestErr *RequestError
+	require.True(t, errors.As(err, &requestErr))
+	assert.Equal(t, ErrCodeAnomalyKServeUnavailable, requestErr.Code)
+	assert.InDelta(t, 0.7, req.Threshold, 1e-9)
+
+	_, err = handler.Analyze(context.Background(), &AnomalyAnalyzeRequest{Scope: models.Scope{Namespace: "apps"}, At: "03:12"})
+	require.True(t, errors.As(err, &requestErr))
+	assert.Equal(t, http.StatusBadRequest, requestErr.StatusCode)
+	assert.Equal(t, "at", requestErr.Errors[0].Field)
+}
+
+func TestStampEvaluationTime(t *testing.T) {
+	response := AnomalyAnalyzeResponse{Anomalies: []AnomalyResult{{Timestamp: "now"}, {Timestamp: "now"}}}
+	stampEvaluationTime(&response, time.Date(2026, 10, 14, 3, 12, 0, 0, time.FixedZone("CEST", 2*3600)))
+	assert.Equal(t, "2026-10-14T01:12:00Z", response.EvaluatedAt)
+	for _, anomaly := range response.Anomalies {
+		assert.Equal(t, response.EvaluatedAt, anomaly.Timestamp)
+	}
+}
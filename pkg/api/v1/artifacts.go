@@ -0,0 +1,104 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/storage"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/diagnostics"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/kserve"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// IncidentArtifactsResponse lists the analysis artifacts attached to an incident
+type IncidentArtifactsResponse struct {
+	IncidentID string                     `json:"incident_id"`
+	Artifacts  []*models.AnalysisArtifact `json:"artifacts"`
+	Total      int                        `json:"total"`
+}
+
+// SetArtifactStore keeps the feature vector, PromQL, model version and raw model response
+// of every analysis in store, and returns its ID as analysis_id
+func (h *AnomalyHandler) SetArtifactStore(store *storage.ArtifactStore) {
+	h.artifacts = store
+}
+
+// rememberArtifact stores the artifact of a completed analysis and returns its ID
+func (h *AnomalyHandler) rememberArtifact(
+	req *AnomalyAnalyzeRequest,
+	response *AnomalyAnalyzeResponse,
+	evaluatedAt time.Time,
+	features []float64,
+	trace *diagnostics.Trace,
+	recorder *kserve.Recorder,
+	resp *kserve.DetectResponse,
+) string {
+	request, err := json.Marshal(req)
+	if err != nil {
+		h.log.WithError(err).Warn("Failed to encode analysis request for its artifact")
+		return ""
+	}
+
+	artifact := &models.AnalysisArtifact{
+		Request:      request,
+		EvaluatedAt:  evaluatedAt.UTC(),
+		Model:        req.ModelName,
+		ModelVersion: resp.ModelVersion,
+		FeatureNames: response.Features.FeatureNames,
+		Features:     features,
+	}
+	if report := trace.Report(); report != nil {
+		for _, query := range report.Queries {
+			artifact.Queries = append(artifact.Queries, models.ArtifactQuery{Kind: query.Kind, Query: query.Query, Error: query.Error})
+		}
+	}
+	if exchanges := recorder.Exchanges(); len(exchanges) > 0 {
+		artifact.ModelResponse = exchanges[len(exchanges)-1].Response
+	}
+	return h.artifacts.Remember(artifact)
+}
+
+// SetArtifactStore attaches the analysis artifact named by analysis_id to created
+// incidents, and enables GET /api/v1/incidents/{id}/artifacts
+func (h *RemediationHandler) SetArtifactStore(store *storage.ArtifactStore) {
+	h.artifacts = store
+}
+
+// SetAnomalyStore links the anomalies stored for the analysis named by analysis_id to
+// created incidents
+func (h *RemediationHandler) SetAnomalyStore(store *storage.AnomalyStore) {
+	h.anomalies = store
+}
+
+// IncidentArtifacts handles GET /api/v1/incidents/{id}/artifacts
+func (h *RemediationHandler) IncidentArtifacts(w http.ResponseWriter, r *http.Request) {
+	incidentID := mux.Vars(r)["id"]
+
+	if h.artifacts == nil {
+		h.sendErrorResponse(w, http.StatusServiceUnavailable, "Analysis artifacts not enabled (set ANALYSIS_ARTIFACTS_ENABLED)")
+		return
+	}
+	if _, err := h.incidentStore.Get(incidentID); err != nil {
+		h.sendErrorResponse(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	artifacts := h.artifacts.ForIncident(incidentID)
+	if artifacts == nil {
+		artifacts = []*models.AnalysisArtifact{}
+	}
+	h.log.WithFields(logrus.Fields{
+		"incident_id": incidentID,
+		"artifacts":   len(artifacts),
+	}).Debug("Incident artifacts listed")
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(IncidentArtifactsResponse{IncidentID: incidentID, Artifacts: artifacts, Total: len(artifacts)}); err != nil {
+		h.log.WithError(err).Error("Failed to encode incident artifacts response")
+	}
+}
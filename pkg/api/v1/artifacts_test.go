@@ -0,0 +1,87 @@
+package v1
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/storage"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/api/validation"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/kserve"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+func TestAnalysisArtifacts(t *testing.T) {
+	t.Setenv("DATA_DIR", t.TempDir())
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	store := storage.NewArtifactStore("", time.Hour, 10)
+	anomalies := NewAnomalyHandler(nil, nil, log)
+	anomalies.SetArtifactStore(store)
+	remediations := NewRemediationHandler(nil, log)
+	remediations.SetArtifactStore(store)
+
+	evaluatedAt := time.Date(2026, 10, 14, 3, 12, 0, 0, time.UTC)
+	req := &AnomalyAnalyzeRequest{Scope: models.Scope{Namespace: "payments"}, ModelName: "anomaly-detector", Threshold: 0.7}
+	response := &AnomalyAnalyzeResponse{Features: FeatureInfo{FeatureNames: []string{"cpu_usage_value"}}}
+	analysisID := anomalies.rememberArtifact(req, response, evaluatedAt, []float64{0.42}, nil, nil, &kserve.DetectResponse{ModelVersion: "3"})
+	require.NotEmpty(t, analysisID)
+
+	createIncident := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		remediations.CreateIncident(w, httptest.NewRequest("POST", "/api/v1/incidents", bytes.NewBufferString(body)))
+		return w
+	}
+	incidentBody := `{"title": "Anomaly in payments", "description": "cpu", "severity": "high", "target": "payments", "analysis_id": "` + analysisID + `"}`
+
+	w := createIncident(incidentBody)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var created CreateIncidentResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&created))
+	assert.Equal(t, analysisID, created.AnalysisID)
+
+	t.Run("artifact is listed with its incident", func(t *testing.T) {
+		router := mux.NewRouter()
+		router.HandleFunc("/api/v1/incidents/{id}/artifacts", remediations.IncidentArtifacts)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/incidents/"+created.IncidentID+"/artifacts", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var resp IncidentArtifactsResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+		require.Equal(t, 1, resp.Total)
+		artifact := resp.Artifacts[0]
+		assert.Equal(t, analysisID, artifact.ID)
+		assert.Equal(t, created.IncidentID, artifact.IncidentID)
+		assert.Equal(t, evaluatedAt, artifact.EvaluatedAt)
+		assert.Equal(t, "3", artifact.ModelVersion)
+		assert.Equal(t, []float64{0.42}, artifact.Features)
+		assert.Contains(t, string(artifact.Request), `"namespace":"payments"`)
+
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/incidents/inc-missing/artifacts", nil))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("an analysis is attached once", func(t *testing.T) {
+		w := createIncident(incidentBody)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+
+		var resp struct {
+			Errors []validation.FieldError `json:"errors"`
+		}
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+		require.Len(t, resp.Errors, 1)
+		assert.Equal(t, "analysis_id", resp.Errors[0].Field)
+		assert.Equal(t, validation.ConstraintUnknown, resp.Errors[0].Constraint)
+	})
+}
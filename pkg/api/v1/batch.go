@@ -0,0 +1,219 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/integrations"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/api/validation"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/batch"
+)
+
+// batchHistoryWindow is how far back Job runs are read from Prometheus; two weeks
+// give the week-over-week duration trend
+const batchHistoryWindow = "14d"
+
+// BatchHandler handles Job and CronJob workload analysis requests
+type BatchHandler struct {
+	analyzer         *batch.Analyzer
+	prometheusClient *integrations.PrometheusClient
+	now              func() time.Time
+	log              *logrus.Logger
+}
+
+// NewBatchHandler creates a new batch workload handler
+func NewBatchHandler(k8sClient kubernetes.Interface, prometheusClient *integrations.PrometheusClient, log *logrus.Logger) *BatchHandler {
+	return &BatchHandler{
+		analyzer:         batch.NewAnalyzer(k8sClient, log),
+		prometheusClient: prometheusClient,
+		log:              log,
+		now:              time.Now,
+	}
+}
+
+// SetClock replaces the clock of result timestamps and evaluation times, e.g. with
+// the fixed clock of deterministic mode
+func (h *BatchHandler) SetClock(now func() time.Time) {
+	h.now = now
+}
+
+// BatchWorkloadsResponse represents the API response for a batch workload analysis
+type BatchWorkloadsResponse struct {
+	Status    string                 `json:"status"`
+	Namespace string                 `json:"namespace"`
+	Name      string                 `json:"name,omitempty"`
+	Timestamp time.Time              `json:"timestamp"`
+	Summary   BatchWorkloadsSummary  `json:"summary"`
+	Workloads []batch.WorkloadReport `json:"workloads"`
+
+	// DataQuality reports whether runs deleted from the cluster could be read from
+	// Prometheus
+	DataQuality DataQuality `json:"data_quality"`
+}
+
+// BatchWorkloadsSummary counts workloads by finding
+type BatchWorkloadsSummary struct {
+	Workloads       int `json:"workloads"`
+	Failing         int `json:"failing"`
+	MissedSchedules int `json:"missed_schedules"`
+	SlowingDown     int `json:"slowing_down"`
+}
+
+// RegisterRoutes registers batch workload API routes
+func (h *BatchHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/api/v1/batch/workloads", h.Workloads).Methods("GET")
+
+	h.log.Info("Batch workload API routes registered: /api/v1/batch/workloads")
+}
+
+// Workloads handles GET /api/v1/batch/workloads
+// @Summary Analyze Job and CronJob workloads
+// @ID batchWorkloads
+// @Description Reports failure counts, run duration trends and missed schedules of a namespace's CronJobs and Jobs, with recommendations
+// @Tags batch
+// @Produce json
+// @Param namespace query string true "Namespace name"
+// @Param name query string false "CronJob or Job name"
+// @Success 200 {object} BatchWorkloadsResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/batch/workloads [get]
+func (h *BatchHandler) Workloads(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	namespace, name := query.Get("namespace"), query.Get("name")
+
+	h.log.WithFields(logrus.Fields{
+		"namespace": namespace,
+		"name":      name,
+	}).Info("Batch workload analysis request received")
+
+	response, err := h.AnalyzeWorkloads(r.Context(), namespace, name)
+	if err != nil {
+		var requestErr *RequestError
+		if errors.As(err, &requestErr) {
+			h.respondError(w, requestErr.StatusCode, requestErr.Message, requestErr.Errors...)
+			return
+		}
+		h.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.respondJSON(w, http.StatusOK, response)
+}
+
+// AnalyzeWorkloads analyzes the CronJobs and Jobs of a namespace, or the one named.
+// Runs deleted from the cluster are read from kube-state-metrics when Prometheus is
+// available; without them the analysis covers the Jobs still in the cluster.
+func (h *BatchHandler) AnalyzeWorkloads(ctx context.Context, namespace, name string) (*BatchWorkloadsResponse, error) {
+	var fieldErrs validation.Errors
+	if namespace == "" {
+		fieldErrs.Add("namespace", validation.ConstraintRequired, nil, "namespace is required")
+	}
+	if err := fieldErrs.Err(); err != nil {
+		return nil, invalidRequest(err, "")
+	}
+
+	var quality DataQuality
+	history, err := h.runHistory(ctx, namespace)
+	if _, refused := integrations.IsQueryCostError(err); refused {
+		return nil, &RequestError{StatusCode: http.StatusUnprocessableEntity, Message: err.Error()}
+	}
+	if err != nil {
+		h.log.WithError(err).WithField("namespace", namespace).Debug("Job run history unavailable, analyzing Jobs in the cluster only")
+		quality = defaultedQuality(err, "job_run_history")
+	}
+
+	reports, err := h.analyzer.Analyze(ctx, namespace, name, history)
+	if err != nil {
+		h.log.WithError(err).WithField("namespace", namespace).Error("Failed to analyze batch workloads")
+		return nil, &RequestError{StatusCode: http.StatusInternalServerError, Message: "failed to list jobs and cronjobs"}
+	}
+	if name != "" && len(reports) == 0 {
+		return nil, &RequestError{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("cronjob or job %s not found in namespace %s", name, namespace),
+		}
+	}
+
+	summary := BatchWorkloadsSummary{Workloads: len(reports)}
+	for i := range reports {
+		report := &reports[i]
+		if report.Failed > 0 {
+			summary.Failing++
+		}
+		if report.MissedSchedules > 0 {
+			summary.MissedSchedules++
+		}
+		if report.DurationTrend != nil && report.DurationTrend.ChangePercent >= batch.DurationGrowthThreshold*100 {
+			summary.SlowingDown++
+		}
+	}
+
+	h.log.WithFields(logrus.Fields{
+		"namespace": namespace,
+		"workloads": summary.Workloads,
+		"failing":   summary.Failing,
+	}).Info("Batch workload analysis completed")
+
+	return &BatchWorkloadsResponse{
+		Status:      "success",
+		Namespace:   namespace,
+		Name:        name,
+		Timestamp:   h.now().UTC(),
+		Summary:     summary,
+		Workloads:   reports,
+		DataQuality: quality,
+	}, nil
+}
+
+// runHistory reads the namespace's Job runs of the last two weeks from kube-state-metrics
+func (h *BatchHandler) runHistory(ctx context.Context, namespace string) ([]batch.HistoricalRun, error) {
+	if h.prometheusClient == nil || !h.prometheusClient.IsAvailable() {
+		return nil, errPrometheusUnavailable
+	}
+	if missing := h.prometheusClient.MissingExporters(integrations.ExporterKubeStateMetrics); len(missing) > 0 {
+		return nil, &MissingExportersError{Exporters: missing}
+	}
+
+	records, err := h.prometheusClient.GetJobRunHistory(ctx, namespace, batchHistoryWindow)
+	if err != nil {
+		return nil, err
+	}
+	history := make([]batch.HistoricalRun, 0, len(records))
+	for _, record := range records {
+		history = append(history, batch.HistoricalRun{
+			Job:            record.Job,
+			CronJob:        record.CronJob,
+			StartTime:      record.StartTime,
+			CompletionTime: record.CompletionTime,
+			Failed:         record.Failed,
+		})
+	}
+	return history, nil
+}
+
+func (h *BatchHandler) respondJSON(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		h.log.WithError(err).Error("Failed to encode JSON response")
+	}
+}
+
+func (h *BatchHandler) respondError(w http.ResponseWriter, statusCode int, message string, fieldErrs ...validation.FieldError) {
+	response := map[string]interface{}{
+		"status": "error",
+		"error":  message,
+	}
+	if len(fieldErrs) > 0 {
+		response["errors"] = fieldErrs
+	}
+	h.respondJSON(w, statusCode, response)
+}
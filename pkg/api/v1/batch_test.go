@@ -0,0 +1,75 @@
+package v1
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestBatchHandler_Workloads(t *testing.T) {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	start := time.Now().Add(-time.Hour)
+	clientset := fake.NewSimpleClientset(
+		&batchv1.CronJob{
+			ObjectMeta: metav1.ObjectMeta{Name: "report", Namespace: "batch", CreationTimestamp: metav1.NewTime(start)},
+			Spec:       batchv1.CronJobSpec{Schedule: "0 0 1 1 *"},
+		},
+		&batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "report-1",
+				Namespace:       "batch",
+				OwnerReferences: []metav1.OwnerReference{{Kind: "CronJob", Name: "report"}},
+			},
+			Status: batchv1.JobStatus{
+				StartTime:  &metav1.Time{Time: start},
+				Conditions: []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Reason: "BackoffLimitExceeded"}},
+			},
+		},
+	)
+	router := mux.NewRouter()
+	NewBatchHandler(clientset, nil, log).RegisterRoutes(router)
+
+	t.Run("analyzes the namespace without Prometheus history", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/batch/workloads?namespace=batch", nil))
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		var response BatchWorkloadsResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, BatchWorkloadsSummary{Workloads: 1, Failing: 1}, response.Summary)
+		require.Len(t, response.Workloads, 1)
+		assert.Equal(t, "report", response.Workloads[0].Name)
+		assert.Equal(t, 1, response.Workloads[0].Failed)
+
+		assert.True(t, response.DataQuality.Degraded)
+		assert.True(t, response.DataQuality.PrometheusUnavailable)
+		assert.Equal(t, []string{"job_run_history"}, response.DataQuality.DefaultedMetrics)
+	})
+
+	t.Run("requires a namespace", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/batch/workloads", nil))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "namespace is required")
+	})
+
+	t.Run("unknown workload", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/batch/workloads?namespace=batch&name=missing", nil))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
@@ -0,0 +1,150 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/remediation"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/api/validation"
+)
+
+// BudgetHandler reports the remediation budget of namespaces and manages approvals
+// to exceed it
+type BudgetHandler struct {
+	budget *remediation.Budget
+	log    *logrus.Logger
+}
+
+// NewBudgetHandler creates a budget handler. budget may be nil, in which case the
+// endpoints respond 503.
+func NewBudgetHandler(budget *remediation.Budget, log *logrus.Logger) *BudgetHandler {
+	return &BudgetHandler{
+		budget: budget,
+		log:    log,
+	}
+}
+
+// BudgetPolicyResponse describes the enforced budget policy
+type BudgetPolicyResponse struct {
+	DailyLimit int    `json:"daily_limit"`
+	Cooldown   string `json:"cooldown"`
+}
+
+// BudgetResponse is the response of GET /api/v1/remediation/budget
+type BudgetResponse struct {
+	Policy     BudgetPolicyResponse          `json:"policy"`
+	Namespaces []remediation.NamespaceBudget `json:"namespaces"`
+}
+
+// RegisterRoutes registers the budget routes
+func (h *BudgetHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/api/v1/remediation/budget", h.GetBudget).Methods("GET")
+	router.HandleFunc("/api/v1/remediation/budget/approvals", h.CreateApproval).Methods("POST")
+	router.HandleFunc("/api/v1/remediation/budget/approvals/{id}", h.DeleteApproval).Methods("DELETE")
+	router.HandleFunc("/api/v1/remediation/budget/{namespace}", h.GetNamespaceBudget).Methods("GET")
+
+	h.log.Info("Remediation budget API routes registered: /api/v1/remediation/budget")
+}
+
+// GetBudget handles GET /api/v1/remediation/budget
+func (h *BudgetHandler) GetBudget(w http.ResponseWriter, _ *http.Request) {
+	if !h.available(w) {
+		return
+	}
+	h.respondJSON(w, http.StatusOK, BudgetResponse{
+		Policy:     h.policy(),
+		Namespaces: h.budget.Namespaces(),
+	})
+}
+
+// GetNamespaceBudget handles GET /api/v1/remediation/budget/{namespace}
+func (h *BudgetHandler) GetNamespaceBudget(w http.ResponseWriter, r *http.Request) {
+	if !h.available(w) {
+		return
+	}
+	h.respondJSON(w, http.StatusOK, h.budget.Namespace(mux.Vars(r)["namespace"]))
+}
+
+// CreateApproval handles POST /api/v1/remediation/budget/approvals
+func (h *BudgetHandler) CreateApproval(w http.ResponseWriter, r *http.Request) {
+	if !h.available(w) {
+		return
+	}
+
+	var req remediation.BudgetApprovalRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err), validation.DecodeFields(err)...)
+		return
+	}
+	approval, err := h.budget.Approve(&req)
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error(), validation.Fields(err)...)
+		return
+	}
+
+	h.log.WithFields(logrus.Fields{
+		"approval_id": approval.ID,
+		"namespace":   approval.Namespace,
+		"target":      approval.Target,
+		"actions":     approval.Actions,
+		"approved_by": approval.ApprovedBy,
+	}).Info("Remediation budget approval granted")
+	h.respondJSON(w, http.StatusCreated, approval)
+}
+
+// DeleteApproval handles DELETE /api/v1/remediation/budget/approvals/{id}
+func (h *BudgetHandler) DeleteApproval(w http.ResponseWriter, r *http.Request) {
+	if !h.available(w) {
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	if !h.budget.Revoke(id) {
+		h.respondError(w, http.StatusNotFound, "budget approval not found: "+id)
+		return
+	}
+	h.log.WithField("approval_id", id).Info("Remediation budget approval revoked")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// policy returns the enforced policy for responses
+func (h *BudgetHandler) policy() BudgetPolicyResponse {
+	policy := h.budget.Policy()
+	return BudgetPolicyResponse{
+		DailyLimit: policy.DailyLimit,
+		Cooldown:   policy.Cooldown.String(),
+	}
+}
+
+// available responds 503 and returns false if no budget is enforced
+func (h *BudgetHandler) available(w http.ResponseWriter) bool {
+	if h.budget == nil {
+		h.respondError(w, http.StatusServiceUnavailable,
+			"remediation budgets are not configured (set REMEDIATION_DAILY_LIMIT or REMEDIATION_COOLDOWN)")
+		return false
+	}
+	return true
+}
+
+func (h *BudgetHandler) respondJSON(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		h.log.WithError(err).Error("Failed to encode JSON response")
+	}
+}
+
+func (h *BudgetHandler) respondError(w http.ResponseWriter, statusCode int, message string, fieldErrs ...validation.FieldError) {
+	response := map[string]interface{}{
+		"status": "error",
+		"error":  message,
+	}
+	if len(fieldErrs) > 0 {
+		response["errors"] = fieldErrs
+	}
+	h.respondJSON(w, statusCode, response)
+}
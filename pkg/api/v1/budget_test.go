@@ -0,0 +1,95 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/detector"
+	"github.com/tosin2013/openshift-coordination-engine/internal/remediation"
+)
+
+func TestBudgetHandler(t *testing.T) {
+	t.Setenv("DATA_DIR", t.TempDir())
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	orchestrator := remediation.NewOrchestrator(detector.NewDeploymentDetector(fake.NewSimpleClientset(), log), fakeRemediator{}, log)
+	orchestrator.SetBudget(remediation.NewBudget(remediation.BudgetPolicy{DailyLimit: 1, Cooldown: 30 * time.Minute}))
+	handler := NewRemediationHandler(orchestrator, log)
+	router := mux.NewRouter()
+	NewBudgetHandler(orchestrator.Budget(), log).RegisterRoutes(router)
+	serve := func(method, url, body string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(method, url, strings.NewReader(body)))
+		return rr
+	}
+
+	trigger := func(name string) (*TriggerRemediationResponse, error) {
+		req := &TriggerRemediationRequest{IncidentID: "inc-" + name, Namespace: "payments"}
+		req.Resource.Kind = "Deployment"
+		req.Resource.Name = name
+		req.Issue.Type = "CrashLoopBackOff"
+		return handler.Trigger(context.Background(), req)
+	}
+	_, err := trigger("api")
+	require.NoError(t, err)
+
+	// The namespace used its budget
+	_, err = trigger("worker")
+	var reqErr *RequestError
+	require.True(t, errors.As(err, &reqErr))
+	assert.Equal(t, http.StatusTooManyRequests, reqErr.StatusCode)
+	assert.Equal(t, ErrCodeRemediationBudgetExceeded, reqErr.Code)
+
+	rr := serve(http.MethodGet, "/api/v1/remediation/budget/payments", "")
+	require.Equal(t, http.StatusOK, rr.Code)
+	var state remediation.NamespaceBudget
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &state))
+	assert.Equal(t, 1, state.Used)
+	require.Len(t, state.Cooldowns, 1)
+	assert.Equal(t, "deployment/api", state.Cooldowns[0].Target)
+
+	// An approval admits one more remediation
+	rr = serve(http.MethodPost, "/api/v1/remediation/budget/approvals", `{"namespace":"payments","target":"deployment/worker"}`)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "approved_by")
+	rr = serve(http.MethodPost, "/api/v1/remediation/budget/approvals", `{"namespace":"payments","target":"deployment/worker","approved_by":"alice"}`)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	_, err = trigger("worker")
+	require.NoError(t, err)
+
+	rr = serve(http.MethodGet, "/api/v1/remediation/budget", "")
+	require.Equal(t, http.StatusOK, rr.Code)
+	var budget BudgetResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &budget))
+	assert.Equal(t, BudgetPolicyResponse{DailyLimit: 1, Cooldown: "30m0s"}, budget.Policy)
+	require.Len(t, budget.Namespaces, 1)
+	assert.Equal(t, 2, budget.Namespaces[0].Used)
+	assert.Empty(t, budget.Namespaces[0].Approvals, "the approval was used up")
+
+	assert.Equal(t, http.StatusNotFound, serve(http.MethodDelete, "/api/v1/remediation/budget/approvals/bap-missing", "").Code)
+}
+
+func TestBudgetHandler_NotConfigured(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	router := mux.NewRouter()
+	NewBudgetHandler(nil, log).RegisterRoutes(router)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/remediation/budget", http.NoBody))
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	assert.Contains(t, rr.Body.String(), "REMEDIATION_DAILY_LIMIT")
+}
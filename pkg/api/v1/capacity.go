@@ -4,6 +4,7 @@ package v1
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -14,6 +15,7 @@ import (
 	"k8s.io/client-go/kubernetes"
 
 	"github.com/tosin2013/openshift-coordination-engine/internal/integrations"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/api/validation"
 	"github.com/tosin2013/openshift-coordination-engine/pkg/capacity"
 )
 
@@ -21,6 +23,8 @@ import (
 type CapacityHandler struct {
 	analyzer         *capacity.Analyzer
 	prometheusClient *integrations.PrometheusClient
+	operatorClient   *integrations.ClusterOperatorClient
+	now              func() time.Time
 	log              *logrus.Logger
 }
 
@@ -30,9 +34,21 @@ func NewCapacityHandler(k8sClient kubernetes.Interface, prometheusClient *integr
 		analyzer:         capacity.NewAnalyzer(k8sClient, log),
 		prometheusClient: prometheusClient,
 		log:              log,
+		now:              time.Now,
 	}
 }
 
+// SetClock replaces the clock of result timestamps and evaluation times, e.g. with
+// the fixed clock of deterministic mode
+func (h *CapacityHandler) SetClock(now func() time.Time) {
+	h.now = now
+}
+
+// SetClusterOperatorClient adds ClusterOperator health to the cluster infrastructure summary
+func (h *CapacityHandler) SetClusterOperatorClient(client *integrations.ClusterOperatorClient) {
+	h.operatorClient = client
+}
+
 // NamespaceCapacityResponse represents the API response for namespace capacity
 type NamespaceCapacityResponse struct {
 	Status               string                         `json:"status"`
@@ -42,6 +58,7 @@ type NamespaceCapacityResponse struct {
 	CurrentUsage         *capacity.ResourceUsage        `json:"current_usage"`
 	Available            *capacity.AvailableCapacity    `json:"available"`
 	Trending             *capacity.TrendingInfo         `json:"trending,omitempty"`
+	PodBreakdown         []capacity.PodUsage            `json:"pod_breakdown,omitempty"`
 	InfrastructureImpact *capacity.InfrastructureImpact `json:"infrastructure_impact,omitempty"`
 }
 
@@ -61,6 +78,9 @@ type CapacityErrorResponse struct {
 	Status  string `json:"status"`
 	Error   string `json:"error"`
 	Message string `json:"message,omitempty"`
+
+	// Errors lists the invalid request fields of a validation failure
+	Errors []validation.FieldError `json:"errors,omitempty"`
 }
 
 // RegisterRoutes registers capacity API routes
@@ -71,7 +91,13 @@ func (h *CapacityHandler) RegisterRoutes(router *mux.Router) {
 	// Cluster-wide capacity endpoint
 	router.HandleFunc("/api/v1/capacity/cluster", h.ClusterCapacity).Methods("GET")
 
-	h.log.Info("Capacity API routes registered: /api/v1/capacity/namespace/{namespace}, /api/v1/capacity/cluster")
+	// Node overcommit and bin-packing endpoint
+	router.HandleFunc("/api/v1/capacity/nodes", h.NodeCapacity).Methods("GET")
+
+	// Workload right-sizing endpoint
+	router.HandleFunc("/api/v1/rightsizing", h.Rightsizing).Methods("GET")
+
+	h.log.Info("Capacity API routes registered: /api/v1/capacity/namespace/{namespace}, /api/v1/capacity/cluster, /api/v1/capacity/nodes, /api/v1/rightsizing")
 }
 
 // NamespaceCapacity handles GET /api/v1/capacity/namespace/{namespace}
@@ -82,7 +108,10 @@ func (h *CapacityHandler) RegisterRoutes(router *mux.Router) {
 // @Param namespace path string true "Namespace name"
 // @Param include_trending query bool false "Include trending analysis (default: true)"
 // @Param include_infrastructure query bool false "Include infrastructure impact analysis (default: false)"
+// @Param include_pods query bool false "Include per-pod usage over the window (default: false)"
 // @Param window query string false "Trending window - 7d, 14d, 30d (default: 7d)"
+// @Param resolution query string false "Include usage series aggregated to auto, 1h, 6h or 1d"
+// @Param max_points query int false "Maximum points per usage series, 3-1000 (default: 200)"
 // @Success 200 {object} NamespaceCapacityResponse
 // @Failure 400 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
@@ -95,40 +124,128 @@ func (h *CapacityHandler) NamespaceCapacity(w http.ResponseWriter, r *http.Reque
 	// Parse query parameters
 	includeTrending := parseBoolParam(r, "include_trending", true)
 	includeInfrastructure := parseBoolParam(r, "include_infrastructure", false)
+	includePods := parseBoolParam(r, "include_pods", false)
 	window := r.URL.Query().Get("window")
 	if window == "" {
 		window = "7d"
 	}
+	resolution := r.URL.Query().Get("resolution")
+	maxPoints := 0
+	if value := r.URL.Query().Get("max_points"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			h.respondError(w, http.StatusBadRequest, "max_points must be an integer",
+				validation.FieldError{Field: "max_points", Constraint: validation.ConstraintFormat, Value: value, Message: "max_points must be an integer"})
+			return
+		}
+		maxPoints = parsed
+	}
 
 	h.log.WithFields(logrus.Fields{
 		"namespace":              namespace,
 		"include_trending":       includeTrending,
 		"include_infrastructure": includeInfrastructure,
+		"include_pods":           includePods,
 		"window":                 window,
+		"resolution":             resolution,
 	}).Info("Namespace capacity request received")
 
+	response, err := h.AnalyzeNamespace(r.Context(), namespace, NamespaceCapacityOptions{
+		IncludeTrending:       includeTrending,
+		IncludeInfrastructure: includeInfrastructure,
+		IncludePods:           includePods,
+		Window:                window,
+		Resolution:            resolution,
+		MaxPoints:             maxPoints,
+	})
+	if err != nil {
+		var requestErr *RequestError
+		if errors.As(err, &requestErr) {
+			h.respondError(w, requestErr.StatusCode, requestErr.Message, requestErr.Errors...)
+			return
+		}
+		h.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, response)
+}
+
+// NamespaceCapacityOptions selects the optional parts of a namespace capacity analysis
+type NamespaceCapacityOptions struct {
+	IncludeTrending       bool
+	IncludeInfrastructure bool
+	IncludePods           bool   // Per-pod average and peak usage over Window
+	Window                string // Trending window: 7d, 14d, 30d
+
+	// Resolution adds the usage series behind the trend, aggregated to auto, 1h, 6h
+	// or 1d buckets and reduced to at most MaxPoints points per series. Setting only
+	// MaxPoints implies auto.
+	Resolution string
+	MaxPoints  int
+}
+
+// Trend series point limits. The raw hourly series of a 30d window has 720 points.
+const (
+	DefaultTrendMaxPoints = 200
+	MaxTrendPoints        = 1000
+)
+
+// trendWindowSpan returns the time covered by a trending window
+func trendWindowSpan(window string) time.Duration {
+	switch window {
+	case "30d":
+		return 30 * 24 * time.Hour
+	case "14d":
+		return 14 * 24 * time.Hour
+	default: // "7d"
+		return 7 * 24 * time.Hour
+	}
+}
+
+// validateSeriesOptions defaults and validates the trend series options
+func validateSeriesOptions(opts *NamespaceCapacityOptions) error {
+	if opts.Resolution == "" && opts.MaxPoints == 0 {
+		return nil
+	}
+	validation.Default(&opts.Resolution, capacity.ResolutionAuto)
+	validation.Default(&opts.MaxPoints, DefaultTrendMaxPoints)
+	return validation.Check(
+		validation.OneOf("resolution", opts.Resolution, capacity.Resolutions...),
+		validation.Between("max_points", opts.MaxPoints, 3, MaxTrendPoints),
+	)
+}
+
+// AnalyzeNamespace computes quota, usage, available capacity and optionally trending
+// and infrastructure impact for a namespace. It is shared by the REST handler and the
+// MCP server.
+func (h *CapacityHandler) AnalyzeNamespace(ctx context.Context, namespace string, opts NamespaceCapacityOptions) (*NamespaceCapacityResponse, error) {
 	// Validate namespace
 	if namespace == "" {
-		h.respondError(w, http.StatusBadRequest, "namespace is required")
-		return
+		return nil, invalidRequest(validation.New("namespace", validation.ConstraintRequired, nil, "namespace is required"), "")
+	}
+	if opts.Window == "" {
+		opts.Window = "7d"
+	}
+	if err := validateSeriesOptions(&opts); err != nil {
+		return nil, invalidRequest(err, "")
 	}
 
-	ctx := r.Context()
+	// Evaluate every usage query at one instant
+	ctx = integrations.WithSnapshot(ctx, integrations.NewSnapshot(h.now()))
 
 	// Get namespace quota
 	quota, err := h.analyzer.GetNamespaceQuota(ctx, namespace)
 	if err != nil {
 		h.log.WithError(err).WithField("namespace", namespace).Error("Failed to get namespace quota")
-		h.respondError(w, http.StatusInternalServerError, "failed to get namespace quota")
-		return
+		return nil, &RequestError{StatusCode: http.StatusInternalServerError, Message: "failed to get namespace quota"}
 	}
 
 	// Get pod count
 	podCount, err := h.analyzer.GetNamespacePodCount(ctx, namespace)
 	if err != nil {
 		h.log.WithError(err).WithField("namespace", namespace).Error("Failed to get pod count")
-		h.respondError(w, http.StatusInternalServerError, "failed to get pod count")
-		return
+		return nil, &RequestError{StatusCode: http.StatusInternalServerError, Message: "failed to get pod count"}
 	}
 
 	// Get current usage from Prometheus
@@ -141,22 +258,27 @@ func (h *CapacityHandler) NamespaceCapacity(w http.ResponseWriter, r *http.Reque
 	response := &NamespaceCapacityResponse{
 		Status:       "success",
 		Namespace:    namespace,
-		Timestamp:    time.Now().UTC(),
+		Timestamp:    h.now().UTC(),
 		Quota:        quota,
 		CurrentUsage: currentUsage,
 		Available:    available,
 	}
 
 	// Include trending analysis if requested
-	if includeTrending && h.prometheusClient != nil && h.prometheusClient.IsAvailable() {
-		trending := h.calculateTrending(ctx, namespace, window, quota, currentUsage)
+	if opts.IncludeTrending && h.prometheusClient != nil && h.prometheusClient.IsAvailable() {
+		trending := h.calculateTrending(ctx, namespace, opts, quota, currentUsage)
 		if trending != nil {
 			response.Trending = trending
 		}
 	}
 
+	// Include the per-pod breakdown if requested
+	if opts.IncludePods && h.prometheusClient != nil && h.prometheusClient.IsAvailable() {
+		response.PodBreakdown = h.calculatePodBreakdown(ctx, namespace, opts.Window)
+	}
+
 	// Include infrastructure impact if requested
-	if includeInfrastructure && h.prometheusClient != nil && h.prometheusClient.IsAvailable() {
+	if opts.IncludeInfrastructure && h.prometheusClient != nil && h.prometheusClient.IsAvailable() {
 		infrastructure := h.calculateInfrastructureImpact(ctx)
 		if infrastructure != nil {
 			response.InfrastructureImpact = infrastructure
@@ -170,7 +292,7 @@ func (h *CapacityHandler) NamespaceCapacity(w http.ResponseWriter, r *http.Reque
 		"has_trending": response.Trending != nil,
 	}).Info("Namespace capacity analysis completed")
 
-	h.respondJSON(w, http.StatusOK, response)
+	return response, nil
 }
 
 // ClusterCapacity handles GET /api/v1/capacity/cluster
@@ -217,15 +339,15 @@ func (h *CapacityHandler) ClusterCapacity(w http.ResponseWriter, r *http.Request
 	response := &ClusterCapacityResponse{
 		Status:          "success",
 		Scope:           "cluster",
-		Timestamp:       time.Now().UTC(),
+		Timestamp:       h.now().UTC(),
 		ClusterCapacity: clusterCapacity,
 		ClusterUsage:    clusterUsage,
 		Namespaces:      namespaceSummaries,
 	}
 
 	// Include infrastructure health
-	if h.prometheusClient != nil && h.prometheusClient.IsAvailable() {
-		response.Infrastructure = h.calculateClusterInfrastructure(ctx)
+	if (h.prometheusClient != nil && h.prometheusClient.IsAvailable()) || h.operatorClient != nil {
+		response.Infrastructure = h.ClusterInfrastructure(ctx)
 	}
 
 	h.log.WithFields(logrus.Fields{
@@ -236,6 +358,63 @@ func (h *CapacityHandler) ClusterCapacity(w http.ResponseWriter, r *http.Request
 	h.respondJSON(w, http.StatusOK, response)
 }
 
+// NodeCapacityResponse represents the API response for node bin-packing analysis
+type NodeCapacityResponse struct {
+	Status    string    `json:"status"`
+	Timestamp time.Time `json:"timestamp"`
+	*capacity.NodeAnalysis
+}
+
+// NodeCapacity handles GET /api/v1/capacity/nodes
+// @Summary Get node overcommit and bin-packing analysis
+// @Description Returns requests and limits vs allocatable per node, overcommit ratios, fragmentation of free capacity, headroom in reference pods, and suggested descheduler or node pool actions
+// @Tags capacity
+// @Produce json
+// @Param pod_cpu query string false "Reference pod CPU request (default: 500m)"
+// @Param pod_memory query string false "Reference pod memory request (default: 1Gi)"
+// @Success 200 {object} NodeCapacityResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/capacity/nodes [get]
+func (h *CapacityHandler) NodeCapacity(w http.ResponseWriter, r *http.Request) {
+	podCPU, podMemory := r.URL.Query().Get("pod_cpu"), r.URL.Query().Get("pod_memory")
+	reference := capacity.DefaultReferencePod
+	var fieldErrs validation.Errors
+	if size, err := capacity.ParsePodSize(podCPU, ""); err != nil {
+		fieldErrs.Add("pod_cpu", validation.ConstraintFormat, podCPU, err.Error())
+	} else {
+		reference.CPU = size.CPU
+	}
+	if size, err := capacity.ParsePodSize("", podMemory); err != nil {
+		fieldErrs.Add("pod_memory", validation.ConstraintFormat, podMemory, err.Error())
+	} else {
+		reference.Memory = size.Memory
+	}
+	if len(fieldErrs) > 0 {
+		h.respondError(w, http.StatusBadRequest, fieldErrs.Error(), fieldErrs...)
+		return
+	}
+
+	analysis, err := h.analyzer.AnalyzeNodes(r.Context(), reference)
+	if err != nil {
+		h.log.WithError(err).Error("Failed to analyze nodes")
+		h.respondError(w, http.StatusInternalServerError, "failed to analyze nodes")
+		return
+	}
+
+	h.log.WithFields(logrus.Fields{
+		"nodes":       analysis.NodeCount,
+		"headroom":    analysis.Headroom,
+		"suggestions": len(analysis.Suggestions),
+	}).Info("Node capacity analysis completed")
+
+	h.respondJSON(w, http.StatusOK, &NodeCapacityResponse{
+		Status:       "success",
+		Timestamp:    h.now().UTC(),
+		NodeAnalysis: analysis,
+	})
+}
+
 // getNamespaceUsage retrieves current resource usage for a namespace from Prometheus
 func (h *CapacityHandler) getNamespaceUsage(ctx context.Context, namespace string, podCount int, quota *capacity.NamespaceQuota) *capacity.ResourceUsage {
 	usage := &capacity.ResourceUsage{
@@ -249,10 +428,10 @@ func (h *CapacityHandler) getNamespaceUsage(ctx context.Context, namespace strin
 	if cpuUsage, err := h.prometheusClient.GetNamespaceCPUUsage(ctx, namespace); err == nil {
 		percent := 0.0
 		if quota.CPU != nil && quota.CPU.LimitNumeric > 0 {
-			percent = (cpuUsage / quota.CPU.LimitNumeric) * 100
+			percent = (cpuUsage.Float64() / quota.CPU.LimitNumeric.Float64()) * 100
 		}
 		usage.CPU = &capacity.CPUUsage{
-			Used:        formatCPU(cpuUsage),
+			Used:        cpuUsage.String(),
 			UsedNumeric: cpuUsage,
 			Percent:     percent,
 		}
@@ -263,10 +442,10 @@ func (h *CapacityHandler) getNamespaceUsage(ctx context.Context, namespace strin
 	if memUsage, err := h.prometheusClient.GetNamespaceMemoryUsage(ctx, namespace); err == nil {
 		percent := 0.0
 		if quota.Memory != nil && quota.Memory.LimitBytes > 0 {
-			percent = (float64(memUsage) / float64(quota.Memory.LimitBytes)) * 100
+			percent = (memUsage.Float64() / quota.Memory.LimitBytes.Float64()) * 100
 		}
 		usage.Memory = &capacity.MemoryUsage{
-			Used:      formatBytes(memUsage),
+			Used:      memUsage.String(),
 			UsedBytes: memUsage,
 			Percent:   percent,
 		}
@@ -289,7 +468,7 @@ func (h *CapacityHandler) getClusterUsage(ctx context.Context, podCount int) *ca
 
 	if cpuUsage, err := h.prometheusClient.GetClusterCPUUsage(ctx); err == nil {
 		usage.CPU = &capacity.CPUUsage{
-			Used:        formatCPU(cpuUsage),
+			Used:        cpuUsage.String(),
 			UsedNumeric: cpuUsage,
 			Percent:     0,
 		}
@@ -299,7 +478,7 @@ func (h *CapacityHandler) getClusterUsage(ctx context.Context, podCount int) *ca
 
 	if memUsage, err := h.prometheusClient.GetClusterMemoryUsage(ctx); err == nil {
 		usage.Memory = &capacity.MemoryUsage{
-			Used:      formatBytes(memUsage),
+			Used:      memUsage.String(),
 			UsedBytes: memUsage,
 			Percent:   0,
 		}
@@ -348,8 +527,57 @@ func (h *CapacityHandler) getNamespaceSummaries(ctx context.Context) ([]capacity
 	return summaries, nil
 }
 
-// calculateTrending calculates trending data for a namespace
-func (h *CapacityHandler) calculateTrending(ctx context.Context, namespace, window string, quota *capacity.NamespaceQuota, usage *capacity.ResourceUsage) *capacity.TrendingInfo {
+// CapacityWarnings analyzes every non-system namespace with a quota and returns those
+// using at least capacity.WarningUsagePercent of CPU or memory, or projected to reach
+// 85% within capacity.WarningDaysUntil85Percent days. Namespaces that fail analysis are skipped.
+func (h *CapacityHandler) CapacityWarnings(ctx context.Context) ([]capacity.Warning, error) {
+	namespaces, err := h.analyzer.ListNamespaces(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list namespaces: %w", err)
+	}
+
+	warnings := make([]capacity.Warning, 0)
+	for _, ns := range namespaces {
+		if isSystemNamespace(ns) {
+			continue
+		}
+
+		analysis, err := h.AnalyzeNamespace(ctx, ns, NamespaceCapacityOptions{IncludeTrending: true})
+		if err != nil {
+			h.log.WithError(err).WithField("namespace", ns).Debug("Skipping namespace in capacity warnings")
+			continue
+		}
+		if !analysis.Quota.HasQuota {
+			continue
+		}
+
+		warning := capacity.Warning{Namespace: ns, DaysUntil85Percent: -1}
+		if analysis.CurrentUsage.CPU != nil {
+			warning.CPUPercent = analysis.CurrentUsage.CPU.Percent
+		}
+		if analysis.CurrentUsage.Memory != nil {
+			warning.MemoryPercent = analysis.CurrentUsage.Memory.Percent
+		}
+		if analysis.Trending != nil {
+			warning.DaysUntil85Percent = analysis.Trending.DaysUntil85Percent
+			warning.ProjectedExhaustionDate = analysis.Trending.ProjectedExhaustionDate
+		}
+
+		highUsage := warning.CPUPercent >= capacity.WarningUsagePercent || warning.MemoryPercent >= capacity.WarningUsagePercent
+		trendingUp := warning.DaysUntil85Percent >= 0 && warning.DaysUntil85Percent <= capacity.WarningDaysUntil85Percent
+		if highUsage || trendingUp {
+			warnings = append(warnings, warning)
+		}
+	}
+
+	return warnings, nil
+}
+
+// calculateTrending calculates trending data for a namespace. The trend is fitted on
+// the raw hourly points; only the series returned for charting are downsampled.
+func (h *CapacityHandler) calculateTrending(ctx context.Context, namespace string, opts NamespaceCapacityOptions, quota *capacity.NamespaceQuota, usage *capacity.ResourceUsage) *capacity.TrendingInfo {
+	window := opts.Window
+
 	// Get CPU trend data
 	cpuTrend, err := h.prometheusClient.GetNamespaceCPUTrend(ctx, namespace, window)
 	if err != nil {
@@ -383,22 +611,64 @@ func (h *CapacityHandler) calculateTrending(ctx context.Context, namespace, wind
 	currentCPU := 0.0
 	cpuLimit := 0.0
 	if usage.CPU != nil {
-		currentCPU = usage.CPU.UsedNumeric
+		currentCPU = usage.CPU.UsedNumeric.Float64()
 	}
 	if quota.CPU != nil {
-		cpuLimit = quota.CPU.LimitNumeric
+		cpuLimit = quota.CPU.LimitNumeric.Float64()
 	}
 
 	currentMemory := 0.0
 	memoryLimit := 0.0
 	if usage.Memory != nil {
-		currentMemory = float64(usage.Memory.UsedBytes)
+		currentMemory = usage.Memory.UsedBytes.Float64()
 	}
 	if quota.Memory != nil {
-		memoryLimit = float64(quota.Memory.LimitBytes)
+		memoryLimit = quota.Memory.LimitBytes.Float64()
 	}
 
-	return capacity.AnalyzeTrend(cpuDataPoints, memDataPoints, currentCPU, cpuLimit, currentMemory, memoryLimit)
+	trending := capacity.AnalyzeTrend(cpuDataPoints, memDataPoints, currentCPU, cpuLimit, currentMemory, memoryLimit)
+
+	if opts.Resolution != "" {
+		resolution, step, _ := capacity.ResolutionStep(opts.Resolution, trendWindowSpan(window), opts.MaxPoints)
+		trending.Series = &capacity.TrendSeries{
+			Resolution: resolution,
+			CPU:        capacity.Downsample(cpuDataPoints, step, opts.MaxPoints),
+			Memory:     capacity.Downsample(memDataPoints, step, opts.MaxPoints),
+		}
+	}
+
+	return trending
+}
+
+// calculatePodBreakdown summarizes the usage of each pod of a namespace over window
+func (h *CapacityHandler) calculatePodBreakdown(ctx context.Context, namespace, window string) []capacity.PodUsage {
+	cpuTrends, err := h.prometheusClient.GetNamespacePodCPUTrends(ctx, namespace, window)
+	if err != nil {
+		h.log.WithError(err).Debug("Failed to get per-pod CPU trend data")
+	}
+	memTrends, err := h.prometheusClient.GetNamespacePodMemoryTrends(ctx, namespace, window)
+	if err != nil {
+		h.log.WithError(err).Debug("Failed to get per-pod memory trend data")
+	}
+	if len(cpuTrends) == 0 && len(memTrends) == 0 {
+		return nil
+	}
+
+	return capacity.BreakdownByPod(podDataPoints(cpuTrends), podDataPoints(memTrends), capacity.DefaultPodBreakdownLimit)
+}
+
+// podDataPoints converts per-pod Prometheus series to capacity data points
+func podDataPoints(matrix integrations.RangeMatrix) map[string][]capacity.DataPoint {
+	byPod := matrix.ByLabel("pod")
+	result := make(map[string][]capacity.DataPoint, len(byPod))
+	for pod, points := range byPod {
+		converted := make([]capacity.DataPoint, 0, len(points))
+		for _, dp := range points {
+			converted = append(converted, capacity.DataPoint{Timestamp: dp.Timestamp, Value: dp.Value})
+		}
+		result[pod] = converted
+	}
+	return result
 }
 
 // calculateInfrastructureImpact calculates infrastructure impact metrics
@@ -436,16 +706,28 @@ func (h *CapacityHandler) calculateInfrastructureImpact(ctx context.Context) *ca
 	return impact
 }
 
-// calculateClusterInfrastructure calculates cluster infrastructure metrics
-func (h *CapacityHandler) calculateClusterInfrastructure(ctx context.Context) *capacity.ClusterInfrastructure {
+// ClusterInfrastructure reports etcd and ClusterOperator health
+func (h *CapacityHandler) ClusterInfrastructure(ctx context.Context) *capacity.ClusterInfrastructure {
 	infrastructure := &capacity.ClusterInfrastructure{
 		EtcdHealth: "unknown",
 	}
 
 	// Get control plane health
-	health, err := h.prometheusClient.GetControlPlaneHealth(ctx)
-	if err == nil {
-		infrastructure.EtcdHealth = health
+	if h.prometheusClient != nil && h.prometheusClient.IsAvailable() {
+		health, err := h.prometheusClient.GetControlPlaneHealth(ctx)
+		if err == nil {
+			infrastructure.EtcdHealth = health
+		}
+	}
+
+	// Get ClusterOperator conditions
+	if h.operatorClient != nil {
+		summary, err := h.operatorClient.GetSummary(ctx)
+		if err != nil {
+			h.log.WithError(err).Debug("Failed to read ClusterOperator status (may not be OpenShift)")
+		} else {
+			infrastructure.ClusterOperators = summary
+		}
 	}
 
 	return infrastructure
@@ -465,30 +747,6 @@ func parseBoolParam(r *http.Request, name string, defaultValue bool) bool {
 	return parsed
 }
 
-func formatCPU(cores float64) string {
-	millicores := int64(cores * 1000)
-	return strconv.FormatInt(millicores, 10) + "m"
-}
-
-func formatBytes(bytes int64) string {
-	const (
-		KB = 1024
-		MB = KB * 1024
-		GB = MB * 1024
-	)
-
-	switch {
-	case bytes >= GB:
-		return strconv.FormatFloat(float64(bytes)/float64(GB), 'f', 1, 64) + "Gi"
-	case bytes >= MB:
-		return strconv.FormatFloat(float64(bytes)/float64(MB), 'f', 1, 64) + "Mi"
-	case bytes >= KB:
-		return strconv.FormatFloat(float64(bytes)/float64(KB), 'f', 1, 64) + "Ki"
-	default:
-		return strconv.FormatInt(bytes, 10)
-	}
-}
-
 func isSystemNamespace(ns string) bool {
 	systemPrefixes := []string{
 		"kube-",
@@ -512,11 +770,12 @@ func (h *CapacityHandler) respondJSON(w http.ResponseWriter, statusCode int, dat
 	}
 }
 
-func (h *CapacityHandler) respondError(w http.ResponseWriter, statusCode int, message string) {
+func (h *CapacityHandler) respondError(w http.ResponseWriter, statusCode int, message string, fieldErrs ...validation.FieldError) {
 	response := CapacityErrorResponse{
 		Status:  "error",
 		Error:   http.StatusText(statusCode),
 		Message: message,
+		Errors:  fieldErrs,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -1,9 +1,12 @@
 package v1
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -14,10 +17,14 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
 	"k8s.io/client-go/kubernetes/fake"
 
+	"github.com/tosin2013/openshift-coordination-engine/internal/integrations"
 	"github.com/tosin2013/openshift-coordination-engine/pkg/capacity"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/quantity"
 )
 
 func TestCapacityHandler_NamespaceWithQuota(t *testing.T) {
@@ -88,9 +95,9 @@ func TestCapacityHandler_NamespaceWithQuota(t *testing.T) {
 	assert.Equal(t, "test-namespace", response.Namespace)
 	assert.True(t, response.Quota.HasQuota)
 	assert.NotNil(t, response.Quota.CPU)
-	assert.Equal(t, float64(10), response.Quota.CPU.LimitNumeric)
+	assert.Equal(t, quantity.Cores(10), response.Quota.CPU.LimitNumeric)
 	assert.NotNil(t, response.Quota.Memory)
-	assert.Equal(t, int64(50), response.Quota.PodCountLimit)
+	assert.Equal(t, quantity.Count(50), response.Quota.PodCountLimit)
 	assert.Equal(t, 2, response.CurrentUsage.PodCount)
 }
 
@@ -220,6 +227,45 @@ func TestCapacityHandler_ClusterWide(t *testing.T) {
 	assert.Equal(t, "30", response.ClusterCapacity.AllocatableCPU)
 	assert.NotNil(t, response.ClusterUsage)
 	assert.Equal(t, 1, response.ClusterUsage.PodCount)
+	assert.Nil(t, response.Infrastructure, "no infrastructure sources configured")
+}
+
+func TestCapacityHandler_ClusterWide_ClusterOperators(t *testing.T) {
+	operator := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "config.openshift.io/v1",
+		"kind":       "ClusterOperator",
+		"metadata":   map[string]interface{}{"name": "network"},
+		"status": map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Available", "status": "True"},
+				map[string]interface{}{"type": "Degraded", "status": "True", "reason": "RolloutHung"},
+			},
+		},
+	}}
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	handler := NewCapacityHandler(fake.NewSimpleClientset(), nil, logger)
+	handler.SetClusterOperatorClient(integrations.NewClusterOperatorClient(
+		dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), operator), logger))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/capacity/cluster", http.NoBody)
+	rr := httptest.NewRecorder()
+	handler.ClusterCapacity(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var response ClusterCapacityResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+
+	// Operator health is reported even without Prometheus
+	require.NotNil(t, response.Infrastructure)
+	assert.Equal(t, "unknown", response.Infrastructure.EtcdHealth)
+	require.NotNil(t, response.Infrastructure.ClusterOperators)
+	assert.Equal(t, 1, response.Infrastructure.ClusterOperators.Total)
+	assert.Equal(t, []string{"network"}, response.Infrastructure.ClusterOperators.Degraded)
+	require.Len(t, response.Infrastructure.ClusterOperators.Unhealthy, 1)
+	assert.Equal(t, "ClusterOperator network: Degraded=True (RolloutHung)",
+		response.Infrastructure.ClusterOperators.Unhealthy[0].Evidence())
 }
 
 func TestCapacityHandler_InvalidNamespace(t *testing.T) {
@@ -250,6 +296,139 @@ func TestCapacityHandler_InvalidNamespace(t *testing.T) {
 	assert.Contains(t, response.Message, "namespace is required")
 }
 
+func TestCapacityHandler_NamespaceTrendSeries(t *testing.T) {
+	// 30 days of hourly usage samples
+	var matrix strings.Builder
+	matrix.WriteString(`{"status":"success","data":{"resultType":"matrix","result":[{"metric":{},"values":[`)
+	start := time.Now().Add(-30 * 24 * time.Hour).Truncate(time.Hour)
+	for i := 0; i < 720; i++ {
+		if i > 0 {
+			matrix.WriteByte(',')
+		}
+		fmt.Fprintf(&matrix, `[%d,"%d"]`, start.Add(time.Duration(i)*time.Hour).Unix(), 1+i%24)
+	}
+	matrix.WriteString(`]}]}}`)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/api/v1/query_range" {
+			_, _ = w.Write([]byte(matrix.String()))
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1700000000,"2"]}]}}`))
+	}))
+	defer server.Close()
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	fakeClient := fake.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "payments"}})
+	handler := NewCapacityHandler(fakeClient, integrations.NewPrometheusClient(server.URL, 5*time.Second, logger), logger)
+
+	get := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/capacity/namespace/payments?window=30d"+query, http.NoBody)
+		req = mux.SetURLVars(req, map[string]string{"namespace": "payments"})
+		rr := httptest.NewRecorder()
+		handler.NamespaceCapacity(rr, req)
+		return rr
+	}
+
+	tests := []struct {
+		name           string
+		query          string
+		wantResolution string
+		wantPoints     int
+	}{
+		{"auto fits the default point count", "&resolution=auto", "6h", 120},
+		{"max_points implies auto", "&max_points=50", "1d", 30},
+		{"fixed resolution is reduced with LTTB", "&resolution=1h&max_points=100", "1h", 100},
+		{"daily", "&resolution=1d", "1d", 30},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := get(tt.query)
+			require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+			var response NamespaceCapacityResponse
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+			require.NotNil(t, response.Trending)
+			require.NotNil(t, response.Trending.Series)
+			assert.Equal(t, tt.wantResolution, response.Trending.Series.Resolution)
+			// Buckets are aligned to the resolution, so the window may touch one more
+			assert.InDelta(t, tt.wantPoints, len(response.Trending.Series.CPU), 1)
+			assert.InDelta(t, tt.wantPoints, len(response.Trending.Series.Memory), 1)
+		})
+	}
+
+	t.Run("series are only returned on request", func(t *testing.T) {
+		rr := get("")
+		require.Equal(t, http.StatusOK, rr.Code)
+		var response NamespaceCapacityResponse
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		require.NotNil(t, response.Trending)
+		assert.Nil(t, response.Trending.Series)
+	})
+
+	for _, query := range []string{"&resolution=5m", "&max_points=2", "&max_points=5000", "&max_points=many"} {
+		t.Run("invalid "+query, func(t *testing.T) {
+			rr := get(query)
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+			var response CapacityErrorResponse
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+			require.NotEmpty(t, response.Errors)
+		})
+	}
+}
+
+func TestCapacityHandler_NamespacePodBreakdown(t *testing.T) {
+	now := time.Now().Unix()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		query := r.URL.Query().Get("query")
+		switch {
+		case r.URL.Path != "/api/v1/query_range":
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1700000000,"2"]}]}}`))
+		case strings.Contains(query, "by (pod)") && strings.Contains(query, "cpu"):
+			fmt.Fprintf(w, `{"status":"success","data":{"resultType":"matrix","result":[`+
+				`{"metric":{"pod":"api-1"},"values":[[%d,"0.25"],[%d,"0.75"]]},`+
+				`{"metric":{"pod":"api-2"},"values":[[%d,"1.5"]]}]}}`, now-3600, now, now)
+		case strings.Contains(query, "by (pod)"):
+			fmt.Fprintf(w, `{"status":"success","data":{"resultType":"matrix","result":[`+
+				`{"metric":{"pod":"api-1"},"values":[[%d,"1073741824"]]}]}}`, now)
+		default:
+			fmt.Fprintf(w, `{"status":"success","data":{"resultType":"matrix","result":[{"metric":{},"values":[[%d,"1"]]}]}}`, now)
+		}
+	}))
+	defer server.Close()
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	fakeClient := fake.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "payments"}})
+	handler := NewCapacityHandler(fakeClient, integrations.NewPrometheusClient(server.URL, 5*time.Second, logger), logger)
+
+	get := func(query string) NamespaceCapacityResponse {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/capacity/namespace/payments"+query, http.NoBody)
+		req = mux.SetURLVars(req, map[string]string{"namespace": "payments"})
+		rr := httptest.NewRecorder()
+		handler.NamespaceCapacity(rr, req)
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		var response NamespaceCapacityResponse
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		return response
+	}
+
+	assert.Empty(t, get("").PodBreakdown, "the breakdown is only returned on request")
+
+	pods := get("?include_pods=true").PodBreakdown
+	require.Len(t, pods, 2)
+	assert.Equal(t, "api-2", pods[0].Pod)
+	assert.Equal(t, quantity.Cores(1.5), pods[0].CPUAverage)
+	assert.Equal(t, 75.0, pods[0].CPUSharePercent)
+	assert.Equal(t, "api-1", pods[1].Pod)
+	assert.Equal(t, quantity.Cores(0.5), pods[1].CPUAverage)
+	assert.Equal(t, quantity.Cores(0.75), pods[1].CPUPeak)
+	assert.Equal(t, quantity.GiB, pods[1].MemoryAverage)
+	assert.Equal(t, 100.0, pods[1].MemorySharePercent)
+}
+
 func TestTrendingAnalysis_LinearRegression(t *testing.T) {
 	// Test linear regression with known data
 	dataPoints := []capacity.DataPoint{
@@ -363,7 +542,7 @@ func TestTrendDirection(t *testing.T) {
 
 func TestFormatCPU(t *testing.T) {
 	tests := []struct {
-		cores    float64
+		cores    quantity.Cores
 		expected string
 	}{
 		{1.0, "1000m"},
@@ -373,14 +552,14 @@ func TestFormatCPU(t *testing.T) {
 	}
 
 	for _, tt := range tests {
-		result := formatCPU(tt.cores)
+		result := tt.cores.String()
 		assert.Equal(t, tt.expected, result)
 	}
 }
 
 func TestFormatBytes(t *testing.T) {
 	tests := []struct {
-		bytes    int64
+		bytes    quantity.Bytes
 		expected string
 	}{
 		{1024 * 1024 * 1024, "1.0Gi"},
@@ -390,7 +569,7 @@ func TestFormatBytes(t *testing.T) {
 	}
 
 	for _, tt := range tests {
-		result := formatBytes(tt.bytes)
+		result := tt.bytes.String()
 		assert.Equal(t, tt.expected, result)
 	}
 }
@@ -470,14 +649,14 @@ func TestCalculateAvailableCapacity(t *testing.T) {
 	available := capacity.CalculateAvailableCapacity(quota, usage)
 
 	assert.NotNil(t, available.CPU)
-	assert.InDelta(t, 4.0, available.CPU.AvailableNumeric, 0.1)
+	assert.InDelta(t, 4.0, available.CPU.AvailableNumeric.Float64(), 0.1)
 	assert.InDelta(t, 40.0, available.CPU.Percent, 0.1)
 
 	assert.NotNil(t, available.Memory)
-	assert.InDelta(t, 4294967296, available.Memory.AvailableBytes, 1000)
+	assert.InDelta(t, 4294967296, available.Memory.AvailableBytes.Float64(), 1000)
 	assert.InDelta(t, 40.0, available.Memory.Percent, 0.1)
 
-	assert.Equal(t, int64(30), available.PodSlots)
+	assert.Equal(t, quantity.Count(30), available.PodSlots)
 }
 
 func TestAnalyzeTrend(t *testing.T) {
@@ -532,6 +711,8 @@ func TestCapacityHandler_RegisterRoutes(t *testing.T) {
 	}{
 		{http.MethodGet, "/api/v1/capacity/namespace/test"},
 		{http.MethodGet, "/api/v1/capacity/cluster"},
+		{http.MethodGet, "/api/v1/capacity/nodes"},
+		{http.MethodGet, "/api/v1/rightsizing"},
 	}
 
 	for _, tt := range tests {
@@ -575,3 +756,65 @@ func BenchmarkCalculateDailyChangePercent(b *testing.B) {
 		capacity.CalculateDailyChangePercent(dataPoints)
 	}
 }
+
+func TestCapacityHandler_CapacityWarnings(t *testing.T) {
+	objects := []runtime.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "payments"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "openshift-monitoring"}},
+		&corev1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "payments"},
+			Status: corev1.ResourceQuotaStatus{
+				Hard: corev1.ResourceList{
+					corev1.ResourceLimitsCPU:    resource.MustParse("4"),
+					corev1.ResourceLimitsMemory: resource.MustParse("8Gi"),
+				},
+			},
+		},
+	}
+
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	handler := NewCapacityHandler(fake.NewSimpleClientset(objects...), nil, log)
+
+	// Without Prometheus there is no usage data, so nothing is flagged
+	warnings, err := handler.CapacityWarnings(context.Background())
+	require.NoError(t, err)
+	assert.NotNil(t, warnings)
+	assert.Empty(t, warnings)
+}
+
+func TestCapacityHandler_NodeCapacity(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "worker-0"},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("4"),
+				corev1.ResourceMemory: resource.MustParse("16Gi"),
+				corev1.ResourcePods:   resource.MustParse("110"),
+			},
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+		},
+	}
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	handler := NewCapacityHandler(fake.NewSimpleClientset(node), nil, logger)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/capacity/nodes?pod_cpu=1&pod_memory=2Gi", http.NoBody)
+	rr := httptest.NewRecorder()
+	handler.NodeCapacity(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var response NodeCapacityResponse
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&response))
+	assert.Equal(t, "success", response.Status)
+	assert.Equal(t, 1, response.SchedulableNodes)
+	assert.Equal(t, capacity.PodSize{CPU: 1, Memory: 2 * 1024 * 1024 * 1024}, response.ReferencePod)
+	assert.Equal(t, 4, response.Headroom)
+	require.Len(t, response.Nodes, 1)
+	assert.Equal(t, 4.0, response.Nodes[0].FreeCPU)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/capacity/nodes?pod_cpu=lots", http.NoBody)
+	rr = httptest.NewRecorder()
+	handler.NodeCapacity(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
@@ -0,0 +1,106 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/runtimeconfig"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/api/validation"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// ConfigValidationHandler dry-runs remediation policies and playbooks: it type-checks
+// and lints them and simulates them against a sample incident without storing them,
+// so broken configuration is caught before it silently disables remediation
+type ConfigValidationHandler struct {
+	store *runtimeconfig.Store
+	log   *logrus.Logger
+}
+
+// NewConfigValidationHandler creates a validation handler. The store provides the
+// stored policies and playbooks the checked spec refers to; it may be nil.
+func NewConfigValidationHandler(store *runtimeconfig.Store, log *logrus.Logger) *ConfigValidationHandler {
+	return &ConfigValidationHandler{
+		store: store,
+		log:   log,
+	}
+}
+
+// ConfigValidateRequest is the body of POST /api/v1/policies/validate and
+// POST /api/v1/playbooks/validate
+type ConfigValidateRequest struct {
+	// Name is the name the spec would be stored under. It replaces the stored object
+	// of that name and determines the policy's evaluation order.
+	Name string `json:"name,omitempty"`
+
+	// Spec is the policy or playbook spec, as for the admin configuration API
+	Spec json.RawMessage `json:"spec"`
+
+	// Incident is an optional sample incident to simulate the spec against
+	Incident *models.Incident `json:"incident,omitempty"`
+}
+
+// RegisterRoutes registers the validation routes
+func (h *ConfigValidationHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/api/v1/policies/validate", h.ValidatePolicy).Methods("POST")
+	router.HandleFunc("/api/v1/playbooks/validate", h.ValidatePlaybook).Methods("POST")
+
+	h.log.Info("Configuration validation API routes registered: /api/v1/policies/validate, /api/v1/playbooks/validate")
+}
+
+// ValidatePolicy handles POST /api/v1/policies/validate
+func (h *ConfigValidationHandler) ValidatePolicy(w http.ResponseWriter, r *http.Request) {
+	h.validate(w, r, runtimeconfig.KindPolicy, runtimeconfig.CheckPolicy)
+}
+
+// ValidatePlaybook handles POST /api/v1/playbooks/validate
+func (h *ConfigValidationHandler) ValidatePlaybook(w http.ResponseWriter, r *http.Request) {
+	h.validate(w, r, runtimeconfig.KindPlaybook, runtimeconfig.CheckPlaybook)
+}
+
+// validate decodes the request and runs check. Invalid specs are reported in the
+// result with status 200; only malformed requests fail.
+func (h *ConfigValidationHandler) validate(
+	w http.ResponseWriter,
+	r *http.Request,
+	kind runtimeconfig.Kind,
+	check func(*runtimeconfig.Store, string, json.RawMessage, *models.Incident) *runtimeconfig.CheckResult,
+) {
+	var req ConfigValidateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err), validation.DecodeFields(err)...)
+		return
+	}
+
+	result := check(h.store, req.Name, req.Spec, req.Incident)
+	h.log.WithFields(logrus.Fields{
+		"kind":        kind,
+		"name":        req.Name,
+		"valid":       result.Valid,
+		"diagnostics": len(result.Diagnostics),
+	}).Debug("Configuration dry-run completed")
+	h.respondJSON(w, http.StatusOK, result)
+}
+
+func (h *ConfigValidationHandler) respondJSON(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		h.log.WithError(err).Error("Failed to encode JSON response")
+	}
+}
+
+func (h *ConfigValidationHandler) respondError(w http.ResponseWriter, statusCode int, message string, fieldErrs ...validation.FieldError) {
+	response := map[string]interface{}{
+		"status": "error",
+		"error":  message,
+	}
+	if len(fieldErrs) > 0 {
+		response["errors"] = fieldErrs
+	}
+	h.respondJSON(w, statusCode, response)
+}
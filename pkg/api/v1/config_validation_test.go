@@ -0,0 +1,56 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/runtimeconfig"
+)
+
+func TestConfigValidationHandler(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	store := runtimeconfig.NewStore(t.TempDir())
+	_, err := store.Create(runtimeconfig.KindPolicy, "10-deny-prod", json.RawMessage(`{"match":{"namespaces":["prod-*"]},"action":"deny"}`))
+	require.NoError(t, err)
+
+	router := mux.NewRouter()
+	NewConfigValidationHandler(store, log).RegisterRoutes(router)
+
+	post := func(url, body string) (int, runtimeconfig.CheckResult) {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, url, strings.NewReader(body)))
+		var result runtimeconfig.CheckResult
+		_ = json.Unmarshal(rr.Body.Bytes(), &result)
+		return rr.Code, result
+	}
+
+	code, result := post("/api/v1/policies/validate", `{"name":"20-allow","spec":{"action":"allow"},"incident":{"target":"prod-payments"}}`)
+	require.Equal(t, http.StatusOK, code)
+	assert.True(t, result.Valid)
+	require.NotNil(t, result.Policy)
+	assert.Equal(t, "10-deny-prod", result.Policy.DecidedBy)
+
+	// Invalid specs are reported, not rejected
+	code, result = post("/api/v1/playbooks/validate", `{"spec":{"steps":[{"name":"wait","action":"wait"}]}}`)
+	require.Equal(t, http.StatusOK, code)
+	assert.False(t, result.Valid)
+	require.NotEmpty(t, result.Diagnostics)
+	assert.Equal(t, "spec.steps[0].params.duration", result.Diagnostics[0].Field)
+
+	code, _ = post("/api/v1/playbooks/validate", `{"spec":`)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	// Nothing is stored
+	objects, err := store.List(runtimeconfig.KindPolicy)
+	require.NoError(t, err)
+	assert.Len(t, objects, 1)
+}
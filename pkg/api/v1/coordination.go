@@ -13,6 +13,9 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/tosin2013/openshift-coordination-engine/internal/coordination"
+	"github.com/tosin2013/openshift-coordination-engine/internal/featureflag"
+	"github.com/tosin2013/openshift-coordination-engine/internal/upgrade"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/api/validation"
 	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
 )
 
@@ -26,6 +29,9 @@ type CoordinationHandler struct {
 	mu                    sync.RWMutex
 	log                   *logrus.Logger
 	enableMLDetection     bool // Phase 6: feature flag for ML detection
+	upgrade               *upgrade.Monitor
+	observerMode          bool
+	flags                 *featureflag.Flags
 }
 
 // CoordinationWorkflow tracks multi-layer remediation workflows
@@ -47,6 +53,9 @@ type TriggerMultiLayerRemediationRequest struct {
 	IncidentID  string            `json:"incident_id"`
 	Description string            `json:"description"`
 	Resources   []models.Resource `json:"resources"`
+
+	// Force runs the remediation even while the cluster is upgrading
+	Force bool `json:"force,omitempty"`
 }
 
 // TriggerMultiLayerRemediationResponse is the response format
@@ -85,26 +94,63 @@ func (ch *CoordinationHandler) SetMLLayerDetector(mlDetector *coordination.MLLay
 	}
 }
 
+// SetUpgradeMonitor blocks multi-layer remediation while the cluster is upgrading,
+// as configured by the upgrade policy
+func (ch *CoordinationHandler) SetUpgradeMonitor(monitor *upgrade.Monitor) {
+	ch.upgrade = monitor
+}
+
+// SetObserverMode rejects every multi-layer remediation: the engine only observes
+func (ch *CoordinationHandler) SetObserverMode(enabled bool) {
+	ch.observerMode = enabled
+}
+
+// SetFeatureFlags rejects multi-layer remediation touching namespaces where
+// auto_remediation is disabled
+func (ch *CoordinationHandler) SetFeatureFlags(flags *featureflag.Flags) {
+	ch.flags = flags
+}
+
 // TriggerMultiLayerRemediation handles POST /api/v1/coordination/trigger
 func (ch *CoordinationHandler) TriggerMultiLayerRemediation(w http.ResponseWriter, r *http.Request) {
 	var req TriggerMultiLayerRemediationRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		ch.log.WithError(err).Error("Failed to decode request")
-		http.Error(w, fmt.Sprintf("Invalid request: %v", err), http.StatusBadRequest)
+		ch.respondError(w, http.StatusBadRequest, fmt.Sprintf("Invalid request: %v", err), validation.DecodeFields(err)...)
 		return
 	}
 
 	// Validate request
+	var fieldErrs validation.Errors
 	if req.IncidentID == "" {
-		http.Error(w, "incident_id is required", http.StatusBadRequest)
-		return
+		fieldErrs.Add("incident_id", validation.ConstraintRequired, nil, "incident_id is required")
 	}
 	if req.Description == "" {
-		http.Error(w, "description is required", http.StatusBadRequest)
-		return
+		fieldErrs.Add("description", validation.ConstraintRequired, nil, "description is required")
 	}
 	if len(req.Resources) == 0 {
-		http.Error(w, "at least one resource is required", http.StatusBadRequest)
+		fieldErrs.Add("resources", validation.ConstraintRequired, nil, "at least one resource is required")
+	}
+	if len(fieldErrs) > 0 {
+		ch.respondError(w, http.StatusBadRequest, fieldErrs.Error(), fieldErrs...)
+		return
+	}
+
+	if requestErr := checkObserverMode(ch.observerMode, "coordination"); requestErr != nil {
+		ch.log.WithField("incident_id", req.IncidentID).Info("Multi-layer remediation blocked in observer mode")
+		ch.respondError(w, requestErr.StatusCode, requestErr.Error(), requestErr.Errors...)
+		return
+	}
+	for _, resource := range req.Resources {
+		if requestErr := checkFeature(ch.flags, featureflag.AutoRemediation, resource.Namespace); requestErr != nil {
+			ch.log.WithField("incident_id", req.IncidentID).Info("Multi-layer remediation blocked by feature flag")
+			ch.respondError(w, requestErr.StatusCode, requestErr.Error())
+			return
+		}
+	}
+	if requestErr := checkClusterUpgrade(ch.upgrade, req.Force, "coordination"); requestErr != nil {
+		ch.log.WithField("incident_id", req.IncidentID).Info("Multi-layer remediation blocked during cluster upgrade")
+		ch.respondError(w, requestErr.StatusCode, requestErr.Error(), requestErr.Errors...)
 		return
 	}
 
@@ -262,6 +308,22 @@ func generateCoordinationWorkflowID() string {
 	return fmt.Sprintf("cwf-%d", time.Now().UnixNano()%1000000000)
 }
 
+// respondError sends a JSON error response
+func (ch *CoordinationHandler) respondError(w http.ResponseWriter, statusCode int, message string, fieldErrs ...validation.FieldError) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	response := map[string]interface{}{
+		"status": "error",
+		"error":  message,
+	}
+	if len(fieldErrs) > 0 {
+		response["errors"] = fieldErrs
+	}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		ch.log.WithError(err).Error("Failed to encode error response")
+	}
+}
+
 // RegisterRoutes registers coordination API routes
 func (ch *CoordinationHandler) RegisterRoutes(router *mux.Router) {
 	apiV1 := router.PathPrefix("/api/v1").Subrouter()
@@ -0,0 +1,88 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// errPrometheusUnavailable is returned when metrics are needed but no Prometheus
+// client is configured or reachable
+var errPrometheusUnavailable = errors.New("prometheus client not available")
+
+// MissingExportersError is returned instead of querying metrics whose exporters the
+// last capability probe found missing
+type MissingExportersError struct {
+	Exporters []string
+}
+
+func (e *MissingExportersError) Error() string {
+	return fmt.Sprintf("metric exporters not found in Prometheus: %s", strings.Join(e.Exporters, ", "))
+}
+
+// DataQuality reports which inputs of a result could not be measured and were replaced
+// by defaults, so callers can tell a result computed from live metrics from a degraded one
+type DataQuality struct {
+	// Degraded is set when any input was replaced by a default
+	Degraded bool `json:"degraded"`
+
+	// PrometheusUnavailable is set when no metrics could be queried at all
+	PrometheusUnavailable bool `json:"prometheus_unavailable,omitempty"`
+
+	// MissingExporters lists the exporters the inputs need that Prometheus has no
+	// metrics of, e.g. kube-state-metrics
+	MissingExporters []string `json:"missing_exporters,omitempty"`
+
+	// DefaultedMetrics lists the inputs replaced by defaults
+	DefaultedMetrics []string `json:"defaulted_metrics,omitempty"`
+
+	// DisabledFeatures lists the inputs given default values because their feature
+	// flag is disabled; unlike defaulted metrics they do not degrade the result
+	DisabledFeatures []string `json:"disabled_features,omitempty"`
+}
+
+// addDefaulted records an input replaced by a default and the missing exporters that
+// caused it, if known
+func (q *DataQuality) addDefaulted(metric string, missingExporters []string) {
+	q.Degraded = true
+	q.DefaultedMetrics = append(q.DefaultedMetrics, metric)
+	for _, exporter := range missingExporters {
+		if !containsString(q.MissingExporters, exporter) {
+			q.MissingExporters = append(q.MissingExporters, exporter)
+		}
+	}
+	sort.Strings(q.MissingExporters)
+}
+
+// addDisabled records an input given a default value because its feature flag is
+// disabled
+func (q *DataQuality) addDisabled(feature string) {
+	q.DisabledFeatures = append(q.DisabledFeatures, feature)
+}
+
+// defaultedQuality returns the data quality of metrics replaced by defaults because
+// querying them failed with err
+func defaultedQuality(err error, metrics ...string) DataQuality {
+	var quality DataQuality
+	var missing []string
+	var missingErr *MissingExportersError
+	if errors.As(err, &missingErr) {
+		missing = missingErr.Exporters
+	}
+	for _, metric := range metrics {
+		quality.addDefaulted(metric, missing)
+	}
+	quality.PrometheusUnavailable = errors.Is(err, errPrometheusUnavailable)
+	return quality
+}
+
+// containsString reports whether values contains value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
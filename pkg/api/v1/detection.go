@@ -9,6 +9,7 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/tosin2013/openshift-coordination-engine/internal/detector"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/api/validation"
 	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
 )
 
@@ -28,10 +29,11 @@ func NewDetectionHandler(det *detector.DeploymentDetector, log *logrus.Logger) *
 
 // DetectionResponse represents the API response for deployment detection
 type DetectionResponse struct {
-	Success bool                   `json:"success"`
-	Data    *models.DeploymentInfo `json:"data,omitempty"`
-	Error   string                 `json:"error,omitempty"`
-	Message string                 `json:"message,omitempty"`
+	Success bool                    `json:"success"`
+	Data    *models.DeploymentInfo  `json:"data,omitempty"`
+	Error   string                  `json:"error,omitempty"`
+	Message string                  `json:"message,omitempty"`
+	Errors  []validation.FieldError `json:"errors,omitempty"`
 }
 
 // RegisterRoutes registers detection API routes
@@ -70,8 +72,8 @@ func (h *DetectionHandler) DetectDeployment(w http.ResponseWriter, r *http.Reque
 	}).Info("Deployment detection request received")
 
 	// Validate inputs
-	if namespace == "" || name == "" {
-		h.respondError(w, http.StatusBadRequest, "namespace and name are required")
+	if fieldErrs := validateWorkloadPath(namespace, name); len(fieldErrs) > 0 {
+		h.respondError(w, http.StatusBadRequest, "namespace and name are required", fieldErrs...)
 		return
 	}
 
@@ -126,8 +128,8 @@ func (h *DetectionHandler) DetectStatefulSet(w http.ResponseWriter, r *http.Requ
 	}).Info("StatefulSet detection request received")
 
 	// Validate inputs
-	if namespace == "" || name == "" {
-		h.respondError(w, http.StatusBadRequest, "namespace and name are required")
+	if fieldErrs := validateWorkloadPath(namespace, name); len(fieldErrs) > 0 {
+		h.respondError(w, http.StatusBadRequest, "namespace and name are required", fieldErrs...)
 		return
 	}
 
@@ -181,8 +183,8 @@ func (h *DetectionHandler) DetectDaemonSet(w http.ResponseWriter, r *http.Reques
 	}).Info("DaemonSet detection request received")
 
 	// Validate inputs
-	if namespace == "" || name == "" {
-		h.respondError(w, http.StatusBadRequest, "namespace and name are required")
+	if fieldErrs := validateWorkloadPath(namespace, name); len(fieldErrs) > 0 {
+		h.respondError(w, http.StatusBadRequest, "namespace and name are required", fieldErrs...)
 		return
 	}
 
@@ -214,6 +216,7 @@ func (h *DetectionHandler) DetectDaemonSet(w http.ResponseWriter, r *http.Reques
 
 // ClearCache handles POST /api/v1/detect/cache/clear
 // @Summary Clear detection cache
+// @ID clearDetectionCache
 // @Description Clears all cached deployment detection results
 // @Tags detection
 // @Produce json
@@ -240,6 +243,7 @@ func (h *DetectionHandler) ClearCache(w http.ResponseWriter, r *http.Request) {
 
 // GetCacheStats handles GET /api/v1/detect/cache/stats
 // @Summary Get cache statistics
+// @ID detectionCacheStats
 // @Description Returns statistics about the detection cache
 // @Tags detection
 // @Produce json
@@ -279,10 +283,11 @@ func (h *DetectionHandler) respondSuccess(w http.ResponseWriter, data *models.De
 	}
 }
 
-func (h *DetectionHandler) respondError(w http.ResponseWriter, statusCode int, errorMsg string) {
+func (h *DetectionHandler) respondError(w http.ResponseWriter, statusCode int, errorMsg string, fieldErrs ...validation.FieldError) {
 	response := DetectionResponse{
 		Success: false,
 		Error:   errorMsg,
+		Errors:  fieldErrs,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -293,6 +298,18 @@ func (h *DetectionHandler) respondError(w http.ResponseWriter, statusCode int, e
 	}
 }
 
+// validateWorkloadPath reports missing namespace and name path parameters
+func validateWorkloadPath(namespace, name string) validation.Errors {
+	var fieldErrs validation.Errors
+	if namespace == "" {
+		fieldErrs.Add("namespace", validation.ConstraintRequired, nil, "namespace is required")
+	}
+	if name == "" {
+		fieldErrs.Add("name", validation.ConstraintRequired, nil, "name is required")
+	}
+	return fieldErrs
+}
+
 func isNotFoundError(err error) bool {
 	if err == nil {
 		return false
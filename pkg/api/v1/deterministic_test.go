@@ -0,0 +1,73 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/clock"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/kserve"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/testsupport"
+)
+
+// deterministicRun analyzes a scope and predicts its usage against fresh fake backends
+// on a fixed clock, returning the JSON of both responses
+func deterministicRun(t *testing.T, start time.Time) (analysis, prediction []byte) {
+	t.Helper()
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	prom := testsupport.NewPrometheus()
+	prom.SetDefault(0.5)
+	predictors := testsupport.NewKServe()
+
+	engineClock := clock.NewManual(start)
+	promClient := prom.Client(log)
+	promClient.SetClock(engineClock.Now)
+	kserveClient, err := predictors.Client(log, "anomaly-detector", "predictive-analytics")
+	require.NoError(t, err)
+	rollouts, err := kserve.NewRollouts(map[string]kserve.ModelRollout{
+		"anomaly-detector": {
+			Versions: map[string]string{"v1": testsupport.ModelURL("anomaly-detector-v1"), "v2": testsupport.ModelURL("anomaly-detector-v2")},
+			Pinned:   "v1",
+			Canary:   &kserve.CanaryRollout{Version: "v2", Percent: 50},
+		},
+	})
+	require.NoError(t, err)
+	rollouts.SetDeterministic(start, clock.Seeded(7))
+	kserveClient.SetRollouts(rollouts)
+
+	anomalyHandler := NewAnomalyHandler(kserveClient, nil, log)
+	anomalyHandler.SetPrometheusClient(promClient)
+	anomalyHandler.SetClock(engineClock.Now)
+	predictionHandler := NewPredictionHandler(kserveClient, promClient, log)
+	predictionHandler.SetClock(engineClock.Now)
+
+	ctx := context.Background()
+	analyzed, err := anomalyHandler.Analyze(ctx, &AnomalyAnalyzeRequest{Scope: models.Scope{Namespace: "payments", Deployment: "api"}})
+	require.NoError(t, err)
+	analysis, err = json.Marshal(analyzed)
+	require.NoError(t, err)
+
+	response, err := predictionHandler.Predict(ctx, &PredictRequest{Scope: models.Scope{Namespace: "payments"}, Horizon: &PredictionHorizon{Every: "1h", For: "6h"}})
+	require.NoError(t, err)
+	prediction, err = json.Marshal(response)
+	require.NoError(t, err)
+	return analysis, prediction
+}
+
+func TestDeterministicMode(t *testing.T) {
+	start := time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC)
+
+	analysis, prediction := deterministicRun(t, start)
+	replayedAnalysis, replayedPrediction := deterministicRun(t, start)
+	assert.Equal(t, string(analysis), string(replayedAnalysis), "analyses are byte-identical across runs")
+	assert.Equal(t, string(prediction), string(replayedPrediction), "predictions are byte-identical across runs")
+	assert.Contains(t, string(analysis), `"evaluated_at":"2026-03-04T10:00:00Z"`)
+	assert.Contains(t, string(prediction), "2026-03-04T11:00:00Z", "target times count from the clock")
+}
@@ -0,0 +1,57 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/api/validation"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/kserve"
+)
+
+// RequestError is returned by handler logic shared with other API surfaces (Analyze,
+// Predict, Recommend). It carries the HTTP status and error code to respond with, so
+// each surface can map it to its own error representation.
+type RequestError struct {
+	StatusCode int
+	Message    string
+	Details    string
+	Code       string
+
+	// Errors lists the invalid request fields of a validation failure
+	Errors []validation.FieldError
+}
+
+// Error implements the error interface
+func (e *RequestError) Error() string {
+	if e.Details != "" {
+		return fmt.Sprintf("%s: %s", e.Message, e.Details)
+	}
+	return e.Message
+}
+
+// invalidRequest returns a 400 RequestError for a validation failure, keeping the
+// invalid fields of validation.Errors
+func invalidRequest(err error, code string) *RequestError {
+	return &RequestError{
+		StatusCode: http.StatusBadRequest,
+		Message:    err.Error(),
+		Code:       code,
+		Errors:     validation.Fields(err),
+	}
+}
+
+// invalidModelResponse returns a 502 RequestError carrying the code of a model
+// response that failed validation, or nil when err is another kind of failure
+func invalidModelResponse(err error, message string) *RequestError {
+	var invalidErr *kserve.InvalidResponseError
+	if !errors.As(err, &invalidErr) {
+		return nil
+	}
+	return &RequestError{
+		StatusCode: http.StatusBadGateway,
+		Message:    message,
+		Details:    err.Error(),
+		Code:       invalidErr.Code,
+	}
+}
@@ -0,0 +1,117 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/escalation"
+)
+
+// EscalationHandler reports the escalation state of incident groups
+type EscalationHandler struct {
+	tracker *escalation.Tracker
+	log     *logrus.Logger
+}
+
+// NewEscalationHandler creates an escalation handler. tracker may be nil, in which
+// case the endpoints respond 503.
+func NewEscalationHandler(tracker *escalation.Tracker, log *logrus.Logger) *EscalationHandler {
+	return &EscalationHandler{
+		tracker: tracker,
+		log:     log,
+	}
+}
+
+// ListIncidentGroupsResponse is the response of GET /api/v1/incident-groups
+type ListIncidentGroupsResponse struct {
+	Groups []*escalation.Group `json:"groups"`
+	Total  int                 `json:"total"`
+}
+
+// RegisterRoutes registers the incident group routes
+func (h *EscalationHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/api/v1/incident-groups", h.ListGroups).Methods("GET")
+	router.HandleFunc("/api/v1/incident-groups/{id}", h.GetGroup).Methods("GET")
+	router.HandleFunc("/api/v1/incident-groups/{id}", h.ResetGroup).Methods("DELETE")
+
+	h.log.Info("Incident group API routes registered: /api/v1/incident-groups")
+}
+
+// ListGroups handles GET /api/v1/incident-groups. The namespace and action query
+// parameters filter the groups.
+func (h *EscalationHandler) ListGroups(w http.ResponseWriter, r *http.Request) {
+	if !h.available(w) {
+		return
+	}
+
+	namespace, action := r.URL.Query().Get("namespace"), r.URL.Query().Get("action")
+	groups := make([]*escalation.Group, 0)
+	for _, group := range h.tracker.List() {
+		if (namespace == "" || group.Namespace == namespace) && (action == "" || group.Action == action) {
+			groups = append(groups, group)
+		}
+	}
+	h.respondJSON(w, http.StatusOK, ListIncidentGroupsResponse{Groups: groups, Total: len(groups)})
+}
+
+// GetGroup handles GET /api/v1/incident-groups/{id}
+func (h *EscalationHandler) GetGroup(w http.ResponseWriter, r *http.Request) {
+	if !h.available(w) {
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	group := h.tracker.Get(id)
+	if group == nil {
+		h.respondError(w, http.StatusNotFound, "incident group not found: "+id)
+		return
+	}
+	h.respondJSON(w, http.StatusOK, group)
+}
+
+// ResetGroup handles DELETE /api/v1/incident-groups/{id}: the group's next incident
+// takes the first step of its escalation ladder again
+func (h *EscalationHandler) ResetGroup(w http.ResponseWriter, r *http.Request) {
+	if !h.available(w) {
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	found, err := h.tracker.Reset(id)
+	if !found {
+		h.respondError(w, http.StatusNotFound, "incident group not found: "+id)
+		return
+	}
+	if err != nil {
+		h.log.WithError(err).WithField("group_id", id).Warn("Failed to persist incident group reset")
+	}
+	h.log.WithField("group_id", id).Info("Incident group escalation reset")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// available responds 503 and returns false if escalation is not enabled
+func (h *EscalationHandler) available(w http.ResponseWriter) bool {
+	if h.tracker == nil {
+		h.respondError(w, http.StatusServiceUnavailable, "escalation ladders are not enabled (set RUNTIME_CONFIG_ENABLED)")
+		return false
+	}
+	return true
+}
+
+func (h *EscalationHandler) respondJSON(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		h.log.WithError(err).Error("Failed to encode JSON response")
+	}
+}
+
+func (h *EscalationHandler) respondError(w http.ResponseWriter, statusCode int, message string) {
+	h.respondJSON(w, statusCode, map[string]interface{}{
+		"status": "error",
+		"error":  message,
+	})
+}
@@ -0,0 +1,113 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/detector"
+	"github.com/tosin2013/openshift-coordination-engine/internal/escalation"
+	"github.com/tosin2013/openshift-coordination-engine/internal/remediation"
+	"github.com/tosin2013/openshift-coordination-engine/internal/runtimeconfig"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+func TestRemediationHandler_Trigger_Escalation(t *testing.T) {
+	t.Setenv("DATA_DIR", t.TempDir())
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	store := runtimeconfig.NewStore(t.TempDir())
+	_, err := store.Create(runtimeconfig.KindEscalationLadder, "crashloop", json.RawMessage(
+		`{"issue_types":["CrashLoopBackOff"],"steps":[{"action":"restart"},{"action":"scale"},{"action":"page","message":"api keeps crashing"}]}`))
+	require.NoError(t, err)
+	one := int32(1)
+	clientset := fake.NewSimpleClientset(&appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "payments"},
+		Spec:       appsv1.DeploymentSpec{Replicas: &one},
+	})
+
+	orchestrator := remediation.NewOrchestrator(detector.NewDeploymentDetector(clientset, log), fakeRemediator{}, log)
+	handler := NewRemediationHandler(orchestrator, log)
+	tracker := escalation.NewTracker(t.TempDir())
+	handler.SetPolicies(store)
+	handler.SetEscalation(tracker, store, escalation.NewScaler(clientset))
+
+	trigger := func(incidentID string) *TriggerRemediationResponse {
+		req := &TriggerRemediationRequest{IncidentID: incidentID, Namespace: "payments"}
+		req.Resource.Kind = "Deployment"
+		req.Resource.Name = "api"
+		req.Issue.Type = "CrashLoopBackOff"
+		response, err := handler.Trigger(context.Background(), req)
+		require.NoError(t, err)
+		require.NotNil(t, response.Escalation)
+		return response
+	}
+
+	// The first occurrence is remediated as usual
+	first := trigger("inc-1")
+	assert.NotEmpty(t, first.WorkflowID)
+	assert.Equal(t, runtimeconfig.EscalationRestart, first.Escalation.Action)
+
+	// The first recurrence scales the deployment
+	second := trigger("inc-2")
+	assert.Equal(t, "scaled", second.Status)
+	assert.Empty(t, second.WorkflowID)
+	assert.Equal(t, "scaled deployment/api from 1 to 2 replicas", second.Escalation.Detail)
+	deployment, err := clientset.AppsV1().Deployments("payments").Get(context.Background(), "api", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), *deployment.Spec.Replicas)
+
+	// The next one pages: the incident is created with an escalated event
+	third := trigger("inc-3")
+	assert.Equal(t, "paged", third.Status)
+	assert.Equal(t, 3, third.Escalation.Step)
+	incident, err := handler.GetIncidentStore().Get("inc-3")
+	require.NoError(t, err)
+	assert.Equal(t, models.IncidentSeverityHigh, incident.Severity)
+	assert.Equal(t, third.Escalation.GroupID, incident.Labels[escalation.LabelGroup])
+	require.Len(t, incident.Events, 1)
+	assert.Equal(t, models.IncidentEventEscalated, incident.Events[0].Type)
+	assert.Equal(t, "api keeps crashing", incident.Events[0].Message)
+
+	// The group is visible and can be reset
+	router := mux.NewRouter()
+	NewEscalationHandler(tracker, log).RegisterRoutes(router)
+	serve := func(method, url string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(method, url, http.NoBody))
+		return rr
+	}
+
+	rr := serve(http.MethodGet, "/api/v1/incident-groups?action=page")
+	require.Equal(t, http.StatusOK, rr.Code)
+	var list ListIncidentGroupsResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &list))
+	require.Equal(t, 1, list.Total)
+	assert.Equal(t, []string{"inc-1", "inc-2", "inc-3"}, list.Groups[0].IncidentIDs)
+
+	assert.Equal(t, http.StatusNoContent, serve(http.MethodDelete, "/api/v1/incident-groups/"+third.Escalation.GroupID).Code)
+	assert.Equal(t, http.StatusNotFound, serve(http.MethodGet, "/api/v1/incident-groups/"+third.Escalation.GroupID).Code)
+	assert.Equal(t, runtimeconfig.EscalationRestart, trigger("inc-4").Escalation.Action)
+}
+
+func TestEscalationHandler_NotEnabled(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	router := mux.NewRouter()
+	NewEscalationHandler(nil, log).RegisterRoutes(router)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/incident-groups", http.NoBody))
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+}
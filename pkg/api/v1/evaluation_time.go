@@ -0,0 +1,23 @@
+package v1
+
+import (
+	"time"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/api/validation"
+)
+
+// parseEvaluationTime returns the instant an analysis is evaluated at: the request's
+// "at" RFC3339 timestamp for retroactive analysis, or now when it is empty
+func parseEvaluationTime(at string, now time.Time) (time.Time, error) {
+	if at == "" {
+		return now.UTC(), nil
+	}
+	parsed, err := time.Parse(time.RFC3339, at)
+	if err != nil {
+		return time.Time{}, validation.New("at", validation.ConstraintFormat, at, "at must be an RFC3339 timestamp")
+	}
+	if parsed.After(now) {
+		return time.Time{}, validation.New("at", validation.ConstraintRange, at, "at must not be in the future")
+	}
+	return parsed.UTC(), nil
+}
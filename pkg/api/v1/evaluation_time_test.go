@@ -0,0 +1,31 @@
+package v1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/api/validation"
+)
+
+func TestParseEvaluationTime(t *testing.T) {
+	now := time.Date(2026, 10, 14, 9, 35, 0, 0, time.UTC)
+
+	at, err := parseEvaluationTime("", now)
+	require.NoError(t, err)
+	assert.Equal(t, now, at)
+
+	at, err = parseEvaluationTime("2026-10-14T05:12:00+02:00", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 10, 14, 3, 12, 0, 0, time.UTC), at)
+
+	_, err = parseEvaluationTime("last night", now)
+	require.Error(t, err)
+	assert.Equal(t, validation.ConstraintFormat, validation.Fields(err)[0].Constraint)
+
+	_, err = parseEvaluationTime("2026-10-14T10:00:00Z", now)
+	require.Error(t, err)
+	assert.Equal(t, validation.ConstraintRange, validation.Fields(err)[0].Constraint)
+}
@@ -0,0 +1,203 @@
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/execution"
+	"github.com/tosin2013/openshift-coordination-engine/internal/featureflag"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/api/validation"
+)
+
+// ExecutionHandler runs playbooks in the configured external workflow engine and
+// reports the status of the runs
+type ExecutionHandler struct {
+	runner       *execution.Runner
+	observerMode bool
+	flags        *featureflag.Flags
+	log          *logrus.Logger
+}
+
+// NewExecutionHandler creates an execution handler. runner may be nil, in which case
+// the endpoints respond 503.
+func NewExecutionHandler(runner *execution.Runner, log *logrus.Logger) *ExecutionHandler {
+	return &ExecutionHandler{
+		runner: runner,
+		log:    log,
+	}
+}
+
+// SetObserverMode rejects playbook runs other than dry runs: the engine only observes
+func (h *ExecutionHandler) SetObserverMode(enabled bool) {
+	h.observerMode = enabled
+}
+
+// SetFeatureFlags rejects playbook runs other than dry runs targeting namespaces
+// where auto_remediation is disabled
+func (h *ExecutionHandler) SetFeatureFlags(flags *featureflag.Flags) {
+	h.flags = flags
+}
+
+// RunPlaybookRequest is the body of POST /api/v1/playbooks/{name}/run
+type RunPlaybookRequest struct {
+	IncidentID string           `json:"incident_id,omitempty"`
+	IssueType  string           `json:"issue_type,omitempty"`
+	Target     execution.Target `json:"target"`
+
+	// DryRun renders the object that would be submitted without submitting it
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// RunPlaybookResponse is the response of POST /api/v1/playbooks/{name}/run
+type RunPlaybookResponse struct {
+	Execution *execution.Execution `json:"execution"`
+
+	// Object is the rendered Workflow or PipelineRun; it is only set for dry runs
+	Object *unstructured.Unstructured `json:"object,omitempty"`
+}
+
+// ListExecutionsResponse is the response of GET /api/v1/executions
+type ListExecutionsResponse struct {
+	Backend    string                 `json:"backend"`
+	Executions []*execution.Execution `json:"executions"`
+	Total      int                    `json:"total"`
+}
+
+// RegisterRoutes registers the execution routes
+func (h *ExecutionHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/api/v1/playbooks/{name}/run", h.RunPlaybook).Methods("POST")
+	router.HandleFunc("/api/v1/executions", h.ListExecutions).Methods("GET")
+	router.HandleFunc("/api/v1/executions/{id}", h.GetExecution).Methods("GET")
+
+	h.log.Info("Playbook execution API routes registered: /api/v1/playbooks/{name}/run, /api/v1/executions")
+}
+
+// RunPlaybook handles POST /api/v1/playbooks/{name}/run
+func (h *ExecutionHandler) RunPlaybook(w http.ResponseWriter, r *http.Request) {
+	if !h.available(w) {
+		return
+	}
+
+	var body RunPlaybookRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.respondError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err), validation.DecodeFields(err)...)
+		return
+	}
+	req := &execution.Request{
+		Playbook:   mux.Vars(r)["name"],
+		IncidentID: body.IncidentID,
+		IssueType:  body.IssueType,
+		Target:     body.Target,
+	}
+
+	if body.DryRun {
+		exec, obj, err := h.runner.Render(req)
+		if err != nil {
+			h.respondRunError(w, err)
+			return
+		}
+		h.respondJSON(w, http.StatusOK, RunPlaybookResponse{Execution: exec, Object: obj})
+		return
+	}
+
+	if requestErr := checkObserverMode(h.observerMode, "playbook"); requestErr != nil {
+		h.respondError(w, requestErr.StatusCode, requestErr.Error())
+		return
+	}
+	if requestErr := checkFeature(h.flags, featureflag.AutoRemediation, body.Target.Namespace); requestErr != nil {
+		h.respondError(w, requestErr.StatusCode, requestErr.Error())
+		return
+	}
+
+	exec, err := h.runner.Run(r.Context(), req)
+	if err != nil {
+		h.respondRunError(w, err)
+		return
+	}
+	h.respondJSON(w, http.StatusCreated, RunPlaybookResponse{Execution: exec})
+}
+
+// respondRunError maps errors of rendering and submitting a playbook to a status
+func (h *ExecutionHandler) respondRunError(w http.ResponseWriter, err error) {
+	switch {
+	case validation.Fields(err) != nil:
+		h.respondError(w, http.StatusBadRequest, err.Error(), validation.Fields(err)...)
+	case errors.Is(err, execution.ErrPlaybookNotFound), errors.Is(err, execution.ErrNoPlaybook):
+		h.respondError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, execution.ErrUnsupported):
+		h.respondError(w, http.StatusUnprocessableEntity, err.Error())
+	default:
+		h.log.WithError(err).Error("Failed to run playbook")
+		h.respondError(w, http.StatusBadGateway, err.Error())
+	}
+}
+
+// ListExecutions handles GET /api/v1/executions. The incident_id and phase query
+// parameters filter the executions.
+func (h *ExecutionHandler) ListExecutions(w http.ResponseWriter, r *http.Request) {
+	if !h.available(w) {
+		return
+	}
+
+	incidentID, phase := r.URL.Query().Get("incident_id"), r.URL.Query().Get("phase")
+	executions := make([]*execution.Execution, 0)
+	for _, exec := range h.runner.List() {
+		if (incidentID == "" || exec.IncidentID == incidentID) && (phase == "" || string(exec.Phase) == phase) {
+			executions = append(executions, exec)
+		}
+	}
+	h.respondJSON(w, http.StatusOK, ListExecutionsResponse{
+		Backend:    h.runner.Backend(),
+		Executions: executions,
+		Total:      len(executions),
+	})
+}
+
+// GetExecution handles GET /api/v1/executions/{id}
+func (h *ExecutionHandler) GetExecution(w http.ResponseWriter, r *http.Request) {
+	if !h.available(w) {
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	exec := h.runner.Get(id)
+	if exec == nil {
+		h.respondError(w, http.StatusNotFound, "execution not found: "+id)
+		return
+	}
+	h.respondJSON(w, http.StatusOK, exec)
+}
+
+// available responds 503 and returns false if no execution backend is configured
+func (h *ExecutionHandler) available(w http.ResponseWriter) bool {
+	if h.runner == nil {
+		h.respondError(w, http.StatusServiceUnavailable, "playbook execution is not configured (set EXECUTION_BACKEND)")
+		return false
+	}
+	return true
+}
+
+func (h *ExecutionHandler) respondJSON(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		h.log.WithError(err).Error("Failed to encode JSON response")
+	}
+}
+
+func (h *ExecutionHandler) respondError(w http.ResponseWriter, statusCode int, message string, fieldErrs ...validation.FieldError) {
+	response := map[string]interface{}{
+		"status": "error",
+		"error":  message,
+	}
+	if len(fieldErrs) > 0 {
+		response["errors"] = fieldErrs
+	}
+	h.respondJSON(w, statusCode, response)
+}
@@ -0,0 +1,146 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/execution"
+	"github.com/tosin2013/openshift-coordination-engine/internal/runtimeconfig"
+)
+
+// newExecutionRunner returns an Argo runner over a fake cluster and a store holding
+// a crash loop playbook and a policy assigning it to the payments namespace
+func newExecutionRunner(t *testing.T, log *logrus.Logger) (*execution.Runner, *runtimeconfig.Store) {
+	t.Helper()
+	store := runtimeconfig.NewStore(t.TempDir())
+	_, err := store.Create(runtimeconfig.KindPlaybook, "crashloop", json.RawMessage(
+		`{"steps":[{"name":"restart","action":"rollout_restart","timeout":"5m"},{"name":"approve","action":"manual"}]}`))
+	require.NoError(t, err)
+	_, err = store.Create(runtimeconfig.KindPolicy, "payments", json.RawMessage(
+		`{"match":{"namespaces":["payments"]},"action":"allow","playbook":"crashloop"}`))
+	require.NoError(t, err)
+
+	backend, err := execution.NewBackend(execution.BackendArgo, execution.Options{})
+	require.NoError(t, err)
+	client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+	return execution.NewRunner(backend, client, store, execution.NewStore(t.TempDir()), "remediation", log), store
+}
+
+func TestExecutionHandler(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	runner, _ := newExecutionRunner(t, log)
+
+	router := mux.NewRouter()
+	NewExecutionHandler(runner, log).RegisterRoutes(router)
+	serve := func(method, url, body string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(method, url, strings.NewReader(body)))
+		return rr
+	}
+	target := `"target":{"namespace":"payments","kind":"Deployment","name":"api"}`
+
+	// A dry run returns the rendered Workflow and creates nothing
+	rr := serve(http.MethodPost, "/api/v1/playbooks/crashloop/run", `{"dry_run":true,`+target+`}`)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var dryRun RunPlaybookResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &dryRun))
+	assert.Equal(t, "Workflow", dryRun.Object.GetKind())
+	assert.Equal(t, dryRun.Execution.Object.Name, dryRun.Object.GetName())
+
+	rr = serve(http.MethodPost, "/api/v1/playbooks/crashloop/run", `{"incident_id":"inc-1",`+target+`}`)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	var run RunPlaybookResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &run))
+	assert.Equal(t, execution.PhasePending, run.Execution.Phase)
+	assert.Nil(t, run.Object)
+
+	rr = serve(http.MethodGet, "/api/v1/executions?incident_id=inc-1", "")
+	require.Equal(t, http.StatusOK, rr.Code)
+	var list ListExecutionsResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &list))
+	assert.Equal(t, "argo", list.Backend)
+	require.Equal(t, 1, list.Total)
+	assert.Equal(t, run.Execution.ID, list.Executions[0].ID)
+
+	assert.Equal(t, http.StatusOK, serve(http.MethodGet, "/api/v1/executions/"+run.Execution.ID, "").Code)
+	assert.Equal(t, http.StatusNotFound, serve(http.MethodGet, "/api/v1/executions/exec-missing", "").Code)
+	assert.Equal(t, http.StatusNotFound, serve(http.MethodPost, "/api/v1/playbooks/missing/run", `{`+target+`}`).Code)
+
+	rr = serve(http.MethodPost, "/api/v1/playbooks/crashloop/run", `{"target":{"namespace":"payments","kind":"Deployment"}}`)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "target.name")
+}
+
+func TestExecutionHandler_ObserverMode(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	runner, _ := newExecutionRunner(t, log)
+	handler := NewExecutionHandler(runner, log)
+	handler.SetObserverMode(true)
+
+	router := mux.NewRouter()
+	handler.RegisterRoutes(router)
+	serve := func(body string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/v1/playbooks/crashloop/run", strings.NewReader(body)))
+		return rr
+	}
+	target := `"target":{"namespace":"payments","kind":"Deployment","name":"api"}`
+
+	// Dry runs still render the Workflow; real runs are refused
+	assert.Equal(t, http.StatusOK, serve(`{"dry_run":true,`+target+`}`).Code)
+	rr := serve(`{"incident_id":"inc-1",` + target + `}`)
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.Contains(t, rr.Body.String(), "observer mode")
+	assert.Empty(t, runner.List())
+}
+
+func TestExecutionHandler_NotConfigured(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	router := mux.NewRouter()
+	NewExecutionHandler(nil, log).RegisterRoutes(router)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/executions", http.NoBody))
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	assert.Contains(t, rr.Body.String(), "EXECUTION_BACKEND")
+}
+
+func TestRemediationHandler_Trigger_ExecutionBackend(t *testing.T) {
+	t.Setenv("DATA_DIR", t.TempDir())
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	runner, store := newExecutionRunner(t, log)
+
+	// The orchestrator is not needed: the policy's playbook runs in the workflow engine
+	handler := NewRemediationHandler(nil, log)
+	handler.SetPolicies(store)
+	handler.SetExecutionRunner(runner)
+
+	req := &TriggerRemediationRequest{IncidentID: "inc-1", Namespace: "payments"}
+	req.Resource.Kind = "Deployment"
+	req.Resource.Name = "api"
+	req.Issue.Type = "CrashLoopBackOff"
+	response, err := handler.Trigger(context.Background(), req)
+	require.NoError(t, err)
+	assert.Empty(t, response.WorkflowID)
+	assert.Equal(t, "payments", response.Policy)
+	assert.Equal(t, "crashloop", response.Playbook)
+	assert.Equal(t, "argo", response.Backend)
+	assert.Equal(t, "pending", response.Status)
+	require.NotNil(t, runner.Get(response.ExecutionID))
+	assert.Equal(t, "inc-1", runner.Get(response.ExecutionID).IncidentID)
+}
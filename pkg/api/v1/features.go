@@ -0,0 +1,103 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/featureflag"
+)
+
+// ErrCodeFeatureDisabled is returned when a request needs a capability whose feature
+// flag is disabled for the namespace
+const ErrCodeFeatureDisabled = "FEATURE_DISABLED"
+
+// FeaturesHandler reports the feature flags gating engine capabilities
+type FeaturesHandler struct {
+	flags *featureflag.Flags
+	log   *logrus.Logger
+}
+
+// NewFeaturesHandler creates a new feature flags handler. A nil flags reports every
+// flag's default.
+func NewFeaturesHandler(flags *featureflag.Flags, log *logrus.Logger) *FeaturesHandler {
+	return &FeaturesHandler{
+		flags: flags,
+		log:   log,
+	}
+}
+
+// FeaturesResponse lists the feature flags and, when a namespace is given, their
+// value for it
+type FeaturesResponse struct {
+	Flags       []FeatureFlag           `json:"flags"`
+	Namespace   string                  `json:"namespace,omitempty"`
+	Evaluations []FeatureFlagEvaluation `json:"evaluations,omitempty"`
+}
+
+// FeatureFlag is the configuration of a feature flag
+type FeatureFlag struct {
+	featureflag.Status
+}
+
+// FeatureFlagEvaluation is the value of a feature flag for a namespace
+type FeatureFlagEvaluation struct {
+	featureflag.Evaluation
+}
+
+// RegisterRoutes registers feature flag API routes
+func (h *FeaturesHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/api/v1/features", h.List).Methods("GET")
+
+	h.log.Info("Feature flag API routes registered: /api/v1/features")
+}
+
+// List handles GET /api/v1/features
+// @Summary List feature flags
+// @Description Returns every feature flag with its engine-wide value, where the value comes from and its namespace overrides. With ?namespace= it also returns the value of each flag for that namespace and the override that decided it.
+// @Tags features
+// @Produce json
+// @Param namespace query string false "Namespace to evaluate the flags for"
+// @Success 200 {object} FeaturesResponse
+// @Router /api/v1/features [get]
+func (h *FeaturesHandler) List(w http.ResponseWriter, r *http.Request) {
+	response := FeaturesResponse{Flags: make([]FeatureFlag, 0, len(featureflag.Definitions))}
+	for _, status := range h.flags.List() {
+		response.Flags = append(response.Flags, FeatureFlag{Status: status})
+	}
+	if namespace := r.URL.Query().Get("namespace"); namespace != "" {
+		response.Namespace = namespace
+		for _, evaluation := range h.flags.EvaluateAll(namespace) {
+			response.Evaluations = append(response.Evaluations, FeatureFlagEvaluation{Evaluation: evaluation})
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.log.WithError(err).Error("Failed to encode feature flags response")
+	}
+}
+
+// checkFeature rejects a request needing a capability whose feature flag is disabled
+// for the namespace. Like observer mode it cannot be forced.
+func checkFeature(flags *featureflag.Flags, flag, namespace string) *RequestError {
+	evaluation := flags.Evaluate(flag, namespace)
+	if evaluation.Enabled {
+		return nil
+	}
+
+	featureflag.RecordDisabled(flag)
+	details := fmt.Sprintf("feature flag %s is disabled engine-wide", flag)
+	if evaluation.Override != "" {
+		details = fmt.Sprintf("feature flag %s is disabled for namespaces matching %q", flag, evaluation.Override)
+	}
+	return &RequestError{
+		StatusCode: http.StatusForbidden,
+		Message:    fmt.Sprintf("Feature %s disabled for namespace %q", flag, namespace),
+		Details:    details,
+		Code:       ErrCodeFeatureDisabled,
+	}
+}
@@ -0,0 +1,77 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/featureflag"
+)
+
+func TestFeaturesHandler_List(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	flags, err := featureflag.New(map[string]featureflag.Config{
+		featureflag.AutoRemediation: {Namespaces: map[string]bool{"prod-*": false}},
+	}, map[string]bool{featureflag.ModelCanary: false})
+	require.NoError(t, err)
+
+	router := mux.NewRouter()
+	NewFeaturesHandler(flags, log).RegisterRoutes(router)
+	get := func(url string) FeaturesResponse {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, url, http.NoBody))
+		require.Equal(t, http.StatusOK, rr.Code)
+		var response FeaturesResponse
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		return response
+	}
+
+	response := get("/api/v1/features")
+	require.Len(t, response.Flags, len(featureflag.Definitions))
+	assert.Equal(t, featureflag.AutoRemediation, response.Flags[0].Name)
+	assert.Equal(t, map[string]bool{"prod-*": false}, response.Flags[0].Namespaces)
+	assert.Empty(t, response.Evaluations)
+
+	response = get("/api/v1/features?namespace=prod-payments")
+	assert.Equal(t, "prod-payments", response.Namespace)
+	require.Len(t, response.Evaluations, len(featureflag.Definitions))
+	assert.Equal(t, featureflag.Evaluation{Name: featureflag.AutoRemediation, Override: "prod-*"}, response.Evaluations[0].Evaluation)
+	assert.Equal(t, featureflag.Evaluation{Name: featureflag.ModelCanary}, response.Evaluations[2].Evaluation)
+}
+
+func TestRemediationHandler_Trigger_FeatureDisabled(t *testing.T) {
+	t.Setenv("DATA_DIR", t.TempDir())
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	flags, err := featureflag.New(map[string]featureflag.Config{
+		featureflag.AutoRemediation: {Namespaces: map[string]bool{"prod-*": false}},
+	}, nil)
+	require.NoError(t, err)
+	handler := NewRemediationHandler(nil, log)
+	handler.SetFeatureFlags(flags)
+
+	req := &TriggerRemediationRequest{IncidentID: "inc-1", Namespace: "prod-payments", Force: true}
+	req.Resource.Kind = "Deployment"
+	req.Resource.Name = "api"
+	req.Issue.Type = "CrashLoopBackOff"
+
+	_, err = handler.Trigger(context.Background(), req)
+	var requestErr *RequestError
+	require.True(t, errors.As(err, &requestErr), "force does not override a disabled flag")
+	assert.Equal(t, http.StatusForbidden, requestErr.StatusCode)
+	assert.Equal(t, ErrCodeFeatureDisabled, requestErr.Code)
+	assert.Equal(t, `feature flag auto_remediation is disabled for namespaces matching "prod-*"`, requestErr.Details)
+
+	assert.Nil(t, checkFeature(flags, featureflag.AutoRemediation, "staging"))
+	assert.Nil(t, checkFeature(nil, featureflag.AutoRemediation, "prod-payments"), "flags default to enabled")
+}
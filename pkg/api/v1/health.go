@@ -5,13 +5,19 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/sirupsen/logrus"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 
+	"github.com/tosin2013/openshift-coordination-engine/internal/integrations"
+	"github.com/tosin2013/openshift-coordination-engine/internal/observer"
 	"github.com/tosin2013/openshift-coordination-engine/internal/rbac"
+	"github.com/tosin2013/openshift-coordination-engine/internal/selfmonitor"
+	"github.com/tosin2013/openshift-coordination-engine/internal/startup"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/kserve"
 	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
 )
 
@@ -24,6 +30,26 @@ type HealthHandler struct {
 	version      string
 	startTime    time.Time
 	httpClient   *http.Client
+	kserveClient *kserve.ProxyClient
+	promClient   *integrations.PrometheusClient
+	selfMonitor  SelfMonitor
+	startup      StartupGates
+	mode         string
+}
+
+// SelfMonitor reports the engine's own behavior
+type SelfMonitor interface {
+	Status() selfmonitor.Status
+}
+
+// StartupGates reports the dependencies engine startup was gated on
+type StartupGates interface {
+	Results() []startup.Result
+}
+
+// SelfHealthResponse is the response of GET /api/v1/health/self
+type SelfHealthResponse struct {
+	selfmonitor.Status
 }
 
 // NewHealthHandler creates a new health handler
@@ -35,26 +61,48 @@ func NewHealthHandler(log *logrus.Logger, k8sClient *kubernetes.Clientset, rbacV
 		mlServiceURL: mlServiceURL,
 		version:      version,
 		startTime:    startTime,
+		mode:         observer.ModeActive,
 		httpClient: &http.Client{
 			Timeout: 5 * time.Second,
 		},
 	}
 }
 
+// SetKServeClient reports the KServe model pre-flight results as dependencies
+func (h *HealthHandler) SetKServeClient(client *kserve.ProxyClient) {
+	h.kserveClient = client
+}
+
+// SetPrometheusClient reports the metric exporter capability probe results as dependencies
+func (h *HealthHandler) SetPrometheusClient(client *integrations.PrometheusClient) {
+	h.promClient = client
+}
+
+// SetSelfMonitor reports the capabilities flagged as reduced by self-monitoring as a
+// dependency and serves GET /api/v1/health/self
+func (h *HealthHandler) SetSelfMonitor(monitor SelfMonitor) {
+	h.selfMonitor = monitor
+}
+
+// SetStartupGates reports the dependencies engine startup was gated on, with their
+// startup mode, as dependencies
+func (h *HealthHandler) SetStartupGates(gates StartupGates) {
+	h.startup = gates
+}
+
+// SetMode reports the engine mode, e.g. observer.ModeObserver
+func (h *HealthHandler) SetMode(mode string) {
+	h.mode = mode
+}
+
 // ServeHTTP handles the health check request
 func (h *HealthHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	// Create health response
 	health := models.NewHealthResponse(h.version, h.startTime)
-
-	// Check Kubernetes connectivity
-	kubernetesHealth := h.checkKubernetes(ctx)
-	health.AddDependency("kubernetes", &kubernetesHealth)
-
-	// Check ML service connectivity (non-critical)
-	mlServiceHealth := h.checkMLService(ctx)
-	health.AddDependency("ml_service", &mlServiceHealth)
+	health.Mode = h.mode
+	h.addDependencies(ctx, health)
 
 	// Check RBAC permissions
 	rbacStatus := h.checkRBAC(ctx)
@@ -83,6 +131,193 @@ func (h *HealthHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// Dependencies handles GET /api/v1/health/dependencies, reporting the dependency
+// checks without RBAC verification
+func (h *HealthHandler) Dependencies(w http.ResponseWriter, r *http.Request) {
+	health := models.NewHealthResponse(h.version, h.startTime)
+	h.addDependencies(r.Context(), health)
+
+	w.Header().Set("Content-Type", "application/json")
+	if health.Status == models.HealthStatusUnhealthy {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	} else {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	response := map[string]interface{}{
+		"status":       health.Status,
+		"timestamp":    health.Timestamp,
+		"dependencies": health.Dependencies,
+	}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.log.WithError(err).Error("Failed to encode dependencies response")
+	}
+}
+
+// addDependencies runs the dependency checks
+func (h *HealthHandler) addDependencies(ctx context.Context, health *models.HealthResponse) {
+	// Check Kubernetes connectivity
+	kubernetesHealth := h.checkKubernetes(ctx)
+	health.AddDependency("kubernetes", &kubernetesHealth)
+
+	// Check ML service connectivity (non-critical)
+	mlServiceHealth := h.checkMLService(ctx)
+	health.AddDependency("ml_service", &mlServiceHealth)
+
+	// Report KServe model pre-flight results (non-critical)
+	if h.kserveClient != nil {
+		for name, dep := range modelDependencies(h.kserveClient.PreflightResults()) {
+			health.AddDependency(name, &dep)
+		}
+	}
+
+	// Report metric exporter capabilities (non-critical)
+	for name, dep := range exporterDependencies(h.promClient.Capabilities()) {
+		health.AddDependency(name, &dep)
+	}
+
+	// Report the dependencies startup was gated on and how it treated them
+	if h.startup != nil {
+		for _, dep := range startupDependencies(h.startup.Results()) {
+			health.AddDependency(dep.Name, &dep)
+		}
+	}
+
+	// Report the engine's own behavior (non-critical)
+	if h.selfMonitor != nil {
+		dep := selfMonitorDependency(h.selfMonitor.Status())
+		health.AddDependency(dep.Name, &dep)
+	}
+}
+
+// SelfHealth handles GET /api/v1/health/self
+// @Summary Get the engine's self-monitoring status
+// @Description Reports the engine's own error rates, queue depths and cache hit rate, the capabilities flagged as reduced and the open self-incident
+// @Tags health
+// @Produce json
+// @Success 200 {object} SelfHealthResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v1/health/self [get]
+func (h *HealthHandler) SelfHealth(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if h.selfMonitor == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		if err := json.NewEncoder(w).Encode(map[string]string{"status": "error", "error": "self-monitoring not enabled"}); err != nil {
+			h.log.WithError(err).Error("Failed to encode self-monitoring response")
+		}
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(SelfHealthResponse{Status: h.selfMonitor.Status()}); err != nil {
+		h.log.WithError(err).Error("Failed to encode self-monitoring response")
+	}
+}
+
+// selfMonitorDependency reports the engine as degraded while self-monitoring flags
+// reduced functionality
+func selfMonitorDependency(status selfmonitor.Status) models.DependencyHealth {
+	dep := models.DependencyHealth{
+		Name:      "self_monitor",
+		Status:    models.ComponentStatusOK,
+		Message:   "Engine behavior normal",
+		CheckedAt: status.SampledAt,
+	}
+	if !status.Healthy {
+		dep.Status = models.ComponentStatusDegraded
+		dep.Message = "Reduced functionality: " + strings.Join(status.ReducedFunctionality, ", ")
+	}
+	return dep
+}
+
+// exporterDependencies converts the capability probe results into dependencies named
+// metrics_exporter:<exporter>. A missing exporter that built-in queries need is
+// degraded, as is one whose probe failed; missing optional exporters are ok.
+func exporterDependencies(capabilities *integrations.Capabilities) map[string]models.DependencyHealth {
+	statuses := capabilities.Statuses()
+	deps := make(map[string]models.DependencyHealth, len(statuses))
+	for _, status := range statuses {
+		name := "metrics_exporter:" + status.Exporter
+		dep := models.DependencyHealth{
+			Name:      name,
+			Status:    models.ComponentStatusOK,
+			Message:   fmt.Sprintf("Found %s metrics", status.Metric),
+			CheckedAt: capabilities.ProbedAt,
+		}
+		switch {
+		case status.Error != "":
+			dep.Status = models.ComponentStatusDegraded
+			dep.Message = "Probe failed: " + status.Error
+		case !status.Available && status.Optional:
+			dep.Message = fmt.Sprintf("No %s metrics (optional)", status.Metric)
+		case !status.Available:
+			dep.Status = models.ComponentStatusDegraded
+			dep.Message = fmt.Sprintf("No %s metrics, dependent features use defaults", status.Metric)
+		}
+		deps[name] = dep
+	}
+	return deps
+}
+
+// startupDependencies converts the latest startup gate checks into dependencies. An
+// unavailable dependency the engine must not start without is down; the others are
+// degraded.
+func startupDependencies(results []startup.Result) []models.DependencyHealth {
+	deps := make([]models.DependencyHealth, 0, len(results))
+	for _, result := range results {
+		dep := models.DependencyHealth{
+			Name:        result.Dependency,
+			Status:      models.ComponentStatusOK,
+			Message:     "Available",
+			CheckedAt:   result.CheckedAt,
+			StartupMode: string(result.Mode),
+		}
+		if !result.Available {
+			dep.Status = models.ComponentStatusDegraded
+			if result.Mode == startup.ModeFail {
+				dep.Status = models.ComponentStatusDown
+			}
+			dep.Message = "Unavailable: " + result.Error
+		}
+		deps = append(deps, dep)
+	}
+	return deps
+}
+
+// modelDependencies converts model pre-flight results into dependencies named
+// kserve_model:<model> or kserve_model:<model>@<version>. A model that rejects the
+// synthetic feature vector is down; one that cannot be reached is degraded.
+func modelDependencies(results []kserve.PreflightResult) map[string]models.DependencyHealth {
+	deps := make(map[string]models.DependencyHealth, len(results))
+	for i := range results {
+		result := &results[i]
+		name := "kserve_model:" + result.Model
+		if result.Version != "" {
+			name += "@" + result.Version
+		}
+
+		dep := models.DependencyHealth{
+			Name:      name,
+			Status:    models.ComponentStatusOK,
+			Message:   result.Message,
+			CheckedAt: result.CheckedAt,
+		}
+		if result.LatencyMs > 0 {
+			latency := result.LatencyMs
+			dep.Latency = &latency
+		}
+		switch result.Status {
+		case kserve.PreflightOK:
+			dep.Message = fmt.Sprintf("Accepted %d-feature vector", result.Features)
+		case kserve.PreflightMismatch:
+			dep.Status = models.ComponentStatusDown
+		case kserve.PreflightUnavailable:
+			dep.Status = models.ComponentStatusDegraded
+		}
+		deps[name] = dep
+	}
+	return deps
+}
+
 // checkKubernetes verifies Kubernetes API connectivity
 func (h *HealthHandler) checkKubernetes(ctx context.Context) models.DependencyHealth {
 	start := time.Now()
@@ -0,0 +1,361 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/storage"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/capacity"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// Health score components
+const (
+	HealthComponentControlPlane = "control_plane"
+	HealthComponentAnomalies    = "anomalies"
+	HealthComponentCapacity     = "capacity"
+	HealthComponentIncidents    = "incidents"
+)
+
+// Health score statuses: a score of at least HealthScoreHealthy is healthy, of at
+// least HealthScoreDegraded degraded, and critical below
+const (
+	HealthScoreHealthy  = 80
+	HealthScoreDegraded = 50
+)
+
+// capacityWarningPenalty is deducted from the capacity score for every namespace close
+// to its quota, and capacityExhaustedPenalty for one using at least capacityExhaustedPercent
+const (
+	capacityWarningPenalty   = 10.0
+	capacityExhaustedPenalty = 25.0
+	capacityExhaustedPercent = 95.0
+)
+
+// incidentPenalties is deducted from the incident score for every open incident of a severity
+var incidentPenalties = map[models.IncidentSeverity]float64{
+	models.IncidentSeverityCritical: 25,
+	models.IncidentSeverityHigh:     10,
+	models.IncidentSeverityMedium:   4,
+	models.IncidentSeverityLow:      1,
+}
+
+// HealthScoreWeights are the relative weights of the health score components
+type HealthScoreWeights struct {
+	ControlPlane float64
+	Anomalies    float64
+	Capacity     float64
+	Incidents    float64
+}
+
+// InfrastructureReporter reports etcd and ClusterOperator health
+type InfrastructureReporter interface {
+	ClusterInfrastructure(ctx context.Context) *capacity.ClusterInfrastructure
+}
+
+// CapacityReporter lists namespaces that are close to their quota
+type CapacityReporter interface {
+	CapacityWarnings(ctx context.Context) ([]capacity.Warning, error)
+}
+
+// AnomalyAnalyzer runs anomaly analyses
+type AnomalyAnalyzer interface {
+	Analyze(ctx context.Context, req *AnomalyAnalyzeRequest) (*AnomalyAnalyzeResponse, error)
+}
+
+// HealthScoreComponent is one component of the composite health score
+type HealthScoreComponent struct {
+	Name string `json:"name"`
+
+	// Score is the component's health from 0 to 100; it is only set when the
+	// component is available
+	Score int `json:"score"`
+
+	// Weight is the configured share of the component; EffectiveWeight is the share
+	// it was given after the weights of unavailable components were redistributed
+	Weight          float64 `json:"weight"`
+	EffectiveWeight float64 `json:"effective_weight"`
+
+	// Contribution is the points the component adds to the composite score
+	Contribution float64 `json:"contribution"`
+
+	Available bool   `json:"available"`
+	Summary   string `json:"summary,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// HealthScoreResponse is the response of GET /api/v1/health/score
+type HealthScoreResponse struct {
+	// Score is the weighted composite of the available components, from 0 to 100
+	Score int `json:"score"`
+
+	// Status is healthy, degraded or critical
+	Status     string                 `json:"status"`
+	Components []HealthScoreComponent `json:"components"`
+	Timestamp  time.Time              `json:"timestamp"`
+}
+
+// HealthScoreHandler computes a composite cluster health score from control-plane
+// health, anomaly levels, capacity headroom and open incidents
+type HealthScoreHandler struct {
+	weights        HealthScoreWeights
+	incidents      *storage.IncidentStore
+	infrastructure InfrastructureReporter
+	capacity       CapacityReporter
+	anomalies      AnomalyAnalyzer
+	now            func() time.Time
+	log            *logrus.Logger
+}
+
+// NewHealthScoreHandler creates a health score handler. Components whose source is
+// not set are reported unavailable.
+func NewHealthScoreHandler(weights HealthScoreWeights, incidents *storage.IncidentStore, log *logrus.Logger) *HealthScoreHandler {
+	return &HealthScoreHandler{
+		weights:   weights,
+		incidents: incidents,
+		log:       log,
+		now:       time.Now,
+	}
+}
+
+// SetClock replaces the clock of result timestamps and evaluation times, e.g. with
+// the fixed clock of deterministic mode
+func (h *HealthScoreHandler) SetClock(now func() time.Time) {
+	h.now = now
+}
+
+// SetInfrastructureReporter sets the source of the control-plane component
+func (h *HealthScoreHandler) SetInfrastructureReporter(reporter InfrastructureReporter) {
+	h.infrastructure = reporter
+}
+
+// SetCapacityReporter sets the source of the capacity component
+func (h *HealthScoreHandler) SetCapacityReporter(reporter CapacityReporter) {
+	h.capacity = reporter
+}
+
+// SetAnomalyAnalyzer sets the source of the anomaly component
+func (h *HealthScoreHandler) SetAnomalyAnalyzer(analyzer AnomalyAnalyzer) {
+	h.anomalies = analyzer
+}
+
+// GetHealthScore handles GET /api/v1/health/score
+// @Summary Get the cluster health score
+// @Description Computes a 0-100 composite of control-plane health, anomaly levels, capacity headroom and open incident severity, with a per-component breakdown
+// @Tags health
+// @Produce json
+// @Success 200 {object} HealthScoreResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v1/health/score [get]
+func (h *HealthScoreHandler) GetHealthScore(w http.ResponseWriter, r *http.Request) {
+	response := h.Score(r.Context())
+	if response == nil {
+		h.respondError(w, http.StatusServiceUnavailable, "no health score component is available")
+		return
+	}
+	h.respondJSON(w, http.StatusOK, response)
+}
+
+// Score computes the composite health score. The weights of unavailable components
+// are redistributed over the available ones; it returns nil if none is available.
+func (h *HealthScoreHandler) Score(ctx context.Context) *HealthScoreResponse {
+	scorers := []struct {
+		name   string
+		weight float64
+		score  func(context.Context) (float64, string, error)
+	}{
+		{HealthComponentControlPlane, h.weights.ControlPlane, h.controlPlaneScore},
+		{HealthComponentAnomalies, h.weights.Anomalies, h.anomalyScore},
+		{HealthComponentCapacity, h.weights.Capacity, h.capacityScore},
+		{HealthComponentIncidents, h.weights.Incidents, h.incidentScore},
+	}
+
+	totalWeight := 0.0
+	for _, scorer := range scorers {
+		totalWeight += scorer.weight
+	}
+	if totalWeight <= 0 {
+		return nil
+	}
+
+	components := make([]HealthScoreComponent, len(scorers))
+	scores := make([]float64, len(scorers))
+	var wg sync.WaitGroup
+	for i, scorer := range scorers {
+		components[i] = HealthScoreComponent{Name: scorer.name, Weight: scorer.weight / totalWeight}
+		if scorer.weight == 0 {
+			components[i].Error = "component has no weight"
+			continue
+		}
+		wg.Add(1)
+		go func(i int, score func(context.Context) (float64, string, error)) {
+			defer wg.Done()
+			value, summary, err := score(ctx)
+			if err != nil {
+				components[i].Error = err.Error()
+				return
+			}
+			scores[i] = math.Max(0, math.Min(100, value))
+			components[i].Available = true
+			components[i].Summary = summary
+		}(i, scorer.score)
+	}
+	wg.Wait()
+
+	availableWeight := 0.0
+	for _, component := range components {
+		if component.Available {
+			availableWeight += component.Weight
+		}
+	}
+	if availableWeight == 0 {
+		return nil
+	}
+
+	composite := 0.0
+	for i := range components {
+		component := &components[i]
+		if !component.Available {
+			h.log.WithFields(logrus.Fields{
+				"component": component.Name,
+				"error":     component.Error,
+			}).Debug("Health score component unavailable")
+			continue
+		}
+		component.Score = int(math.Round(scores[i]))
+		component.EffectiveWeight = component.Weight / availableWeight
+		component.Contribution = math.Round(scores[i]*component.EffectiveWeight*10) / 10
+		composite += scores[i] * component.EffectiveWeight
+	}
+
+	score := int(math.Round(composite))
+	return &HealthScoreResponse{
+		Score:      score,
+		Status:     healthScoreStatus(score),
+		Components: components,
+		Timestamp:  h.now().UTC(),
+	}
+}
+
+// healthScoreStatus maps a score to healthy, degraded or critical
+func healthScoreStatus(score int) string {
+	switch {
+	case score >= HealthScoreHealthy:
+		return "healthy"
+	case score >= HealthScoreDegraded:
+		return "degraded"
+	default:
+		return "critical"
+	}
+}
+
+// controlPlaneScore is 0 when etcd has no leader, and otherwise the percentage of
+// ClusterOperators that are available and not degraded
+func (h *HealthScoreHandler) controlPlaneScore(ctx context.Context) (float64, string, error) {
+	if h.infrastructure == nil {
+		return 0, "", fmt.Errorf("control-plane health is not configured")
+	}
+	infrastructure := h.infrastructure.ClusterInfrastructure(ctx)
+	operators := infrastructure.ClusterOperators
+	if infrastructure.EtcdHealth == "unhealthy" {
+		return 0, "etcd has no leader", nil
+	}
+	if operators == nil || operators.Total == 0 {
+		if infrastructure.EtcdHealth == "healthy" {
+			return 100, "etcd healthy", nil
+		}
+		return 0, "", fmt.Errorf("neither etcd nor ClusterOperator health is known")
+	}
+
+	summary := fmt.Sprintf("%d of %d ClusterOperators healthy", operators.Healthy, operators.Total)
+	if infrastructure.EtcdHealth == "healthy" {
+		summary = "etcd healthy, " + summary
+	}
+	return 100 * float64(operators.Healthy) / float64(operators.Total), summary, nil
+}
+
+// anomalyScore is 100 less the highest score, as a percentage, of the anomalies found
+// by a cluster-wide analysis
+func (h *HealthScoreHandler) anomalyScore(ctx context.Context) (float64, string, error) {
+	if h.anomalies == nil {
+		return 0, "", fmt.Errorf("anomaly analysis is not configured")
+	}
+	// Polling the score must not fill the anomaly records with the same findings
+	analysis, err := h.anomalies.Analyze(ctx, &AnomalyAnalyzeRequest{readOnly: true})
+	if err != nil {
+		return 0, "", fmt.Errorf("anomaly analysis failed: %w", err)
+	}
+
+	if len(analysis.Anomalies) == 0 {
+		return 100, "no anomalies detected", nil
+	}
+	highest := 0.0
+	for _, anomaly := range analysis.Anomalies {
+		highest = math.Max(highest, anomaly.AnomalyScore)
+	}
+	return 100 * (1 - highest), fmt.Sprintf("%d anomalies detected, highest score %.2f", len(analysis.Anomalies), highest), nil
+}
+
+// capacityScore deducts a penalty for every namespace close to, or trending toward,
+// its quota
+func (h *HealthScoreHandler) capacityScore(ctx context.Context) (float64, string, error) {
+	if h.capacity == nil {
+		return 0, "", fmt.Errorf("capacity analysis is not configured")
+	}
+	warnings, err := h.capacity.CapacityWarnings(ctx)
+	if err != nil {
+		return 0, "", fmt.Errorf("capacity analysis failed: %w", err)
+	}
+
+	score := 100.0
+	exhausted := 0
+	for _, warning := range warnings {
+		if math.Max(warning.CPUPercent, warning.MemoryPercent) >= capacityExhaustedPercent {
+			score -= capacityExhaustedPenalty
+			exhausted++
+			continue
+		}
+		score -= capacityWarningPenalty
+	}
+	return score, fmt.Sprintf("%d namespaces near quota, %d at or above %.0f%%", len(warnings), exhausted, capacityExhaustedPercent), nil
+}
+
+// incidentScore deducts a penalty for every open incident by its severity
+func (h *HealthScoreHandler) incidentScore(_ context.Context) (float64, string, error) {
+	if h.incidents == nil {
+		return 0, "", fmt.Errorf("incident store is not configured")
+	}
+	incidents := h.incidents.List(storage.ListFilter{Status: string(models.IncidentStatusActive)})
+
+	score := 100.0
+	critical := 0
+	for _, incident := range incidents {
+		score -= incidentPenalties[incident.Severity]
+		if incident.Severity == models.IncidentSeverityCritical {
+			critical++
+		}
+	}
+	return score, fmt.Sprintf("%d open incidents, %d critical", len(incidents), critical), nil
+}
+
+func (h *HealthScoreHandler) respondJSON(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		h.log.WithError(err).Error("Failed to encode JSON response")
+	}
+}
+
+func (h *HealthScoreHandler) respondError(w http.ResponseWriter, statusCode int, message string) {
+	h.respondJSON(w, statusCode, map[string]interface{}{
+		"status": "error",
+		"error":  message,
+	})
+}
@@ -0,0 +1,126 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/storage"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/capacity"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+type fakeInfrastructure capacity.ClusterInfrastructure
+
+func (f *fakeInfrastructure) ClusterInfrastructure(context.Context) *capacity.ClusterInfrastructure {
+	infrastructure := capacity.ClusterInfrastructure(*f)
+	return &infrastructure
+}
+
+type fakeCapacityWarnings []capacity.Warning
+
+func (f fakeCapacityWarnings) CapacityWarnings(context.Context) ([]capacity.Warning, error) {
+	return f, nil
+}
+
+type fakeAnomalyAnalyzer struct {
+	anomalies []AnomalyResult
+	err       error
+}
+
+func (f *fakeAnomalyAnalyzer) Analyze(context.Context, *AnomalyAnalyzeRequest) (*AnomalyAnalyzeResponse, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &AnomalyAnalyzeResponse{Anomalies: f.anomalies, AnomaliesDetected: len(f.anomalies)}, nil
+}
+
+func TestHealthScoreHandler_Score(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	incidents := storage.NewIncidentStoreWithPath(t.TempDir())
+	for _, severity := range []models.IncidentSeverity{models.IncidentSeverityCritical, models.IncidentSeverityHigh, models.IncidentSeverityLow} {
+		_, err := incidents.Create(&models.Incident{Title: "Incident", Description: "Issue", Severity: severity, Target: "payments"})
+		require.NoError(t, err)
+	}
+
+	handler := NewHealthScoreHandler(HealthScoreWeights{ControlPlane: 3, Anomalies: 2.5, Capacity: 2, Incidents: 2.5}, incidents, log)
+	handler.SetInfrastructureReporter(&fakeInfrastructure{
+		EtcdHealth:       "healthy",
+		ClusterOperators: &models.ClusterOperatorSummary{Total: 20, Healthy: 18},
+	})
+	handler.SetCapacityReporter(fakeCapacityWarnings{
+		{Namespace: "payments", CPUPercent: 82},
+		{Namespace: "checkout", MemoryPercent: 97},
+	})
+	anomalies := &fakeAnomalyAnalyzer{anomalies: []AnomalyResult{{AnomalyScore: 0.4}, {AnomalyScore: 0.6}}}
+	handler.SetAnomalyAnalyzer(anomalies)
+
+	rr := httptest.NewRecorder()
+	handler.GetHealthScore(rr, httptest.NewRequest(http.MethodGet, "/api/v1/health/score", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	var resp HealthScoreResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	require.Len(t, resp.Components, 4)
+
+	scores := make(map[string]HealthScoreComponent)
+	for _, component := range resp.Components {
+		assert.True(t, component.Available, component.Name)
+		scores[component.Name] = component
+	}
+	assert.Equal(t, 90, scores[HealthComponentControlPlane].Score)
+	assert.Equal(t, "etcd healthy, 18 of 20 ClusterOperators healthy", scores[HealthComponentControlPlane].Summary)
+	assert.Equal(t, 40, scores[HealthComponentAnomalies].Score)
+	assert.Equal(t, 65, scores[HealthComponentCapacity].Score, "one namespace near quota and one exhausted")
+	assert.Equal(t, 64, scores[HealthComponentIncidents].Score, "critical, high and low incidents open")
+	assert.InDelta(t, 0.3, scores[HealthComponentControlPlane].Weight, 1e-9, "weights are normalized")
+
+	// 0.3*90 + 0.25*40 + 0.2*65 + 0.25*64
+	assert.Equal(t, 66, resp.Score)
+	assert.Equal(t, "degraded", resp.Status)
+
+	// The weights of unavailable components are redistributed
+	anomalies.err = errors.New("model unavailable")
+	score := handler.Score(context.Background())
+	require.NotNil(t, score)
+	anomaly := score.Components[1]
+	assert.False(t, anomaly.Available)
+	assert.Contains(t, anomaly.Error, "model unavailable")
+	assert.Zero(t, anomaly.Contribution)
+	assert.InDelta(t, 0.4, score.Components[0].EffectiveWeight, 1e-9)
+	assert.Equal(t, 75, score.Score) // (0.3*90 + 0.2*65 + 0.25*64) / 0.75
+
+	// etcd without a leader fails the control plane
+	handler.SetInfrastructureReporter(&fakeInfrastructure{EtcdHealth: "unhealthy"})
+	score = handler.Score(context.Background())
+	assert.Equal(t, 0, score.Components[0].Score)
+	assert.Equal(t, "critical", score.Status)
+}
+
+func TestHealthScoreHandler_Unavailable(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	handler := NewHealthScoreHandler(HealthScoreWeights{ControlPlane: 1, Anomalies: 1}, nil, log)
+	handler.SetInfrastructureReporter(&fakeInfrastructure{EtcdHealth: "unknown"})
+	rr := httptest.NewRecorder()
+	handler.GetHealthScore(rr, httptest.NewRequest(http.MethodGet, "/api/v1/health/score", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+
+	// Components without weight are not computed
+	handler = NewHealthScoreHandler(HealthScoreWeights{Incidents: 1}, storage.NewIncidentStoreWithPath(t.TempDir()), log)
+	score := handler.Score(context.Background())
+	require.NotNil(t, score)
+	assert.Equal(t, 100, score.Score)
+	assert.Equal(t, "healthy", score.Status)
+	assert.False(t, score.Components[0].Available)
+	assert.InDelta(t, 1.0, score.Components[3].EffectiveWeight, 1e-9)
+}
@@ -0,0 +1,107 @@
+package v1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/integrations"
+	"github.com/tosin2013/openshift-coordination-engine/internal/selfmonitor"
+	"github.com/tosin2013/openshift-coordination-engine/internal/startup"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/kserve"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+func TestModelDependencies(t *testing.T) {
+	checkedAt := time.Now()
+	deps := modelDependencies([]kserve.PreflightResult{
+		{Model: "anomaly-detector", Status: kserve.PreflightOK, Features: 45, LatencyMs: 12, CheckedAt: checkedAt},
+		{Model: "anomaly-detector", Version: "v3", Status: kserve.PreflightMismatch, Features: 45, Message: "model rejected a 45-feature vector"},
+		{Model: "predictive-analytics", Status: kserve.PreflightUnavailable, Message: "request failed"},
+		{Model: "disk-predictor", Status: kserve.PreflightSkipped, Message: "no feature schema registered"},
+	})
+	require.Len(t, deps, 4)
+
+	ok := deps["kserve_model:anomaly-detector"]
+	assert.Equal(t, models.ComponentStatusOK, ok.Status)
+	assert.Equal(t, "Accepted 45-feature vector", ok.Message)
+	require.NotNil(t, ok.Latency)
+	assert.Equal(t, int64(12), *ok.Latency)
+	assert.Equal(t, checkedAt, ok.CheckedAt)
+
+	assert.Equal(t, models.ComponentStatusDown, deps["kserve_model:anomaly-detector@v3"].Status)
+	assert.Equal(t, models.ComponentStatusDegraded, deps["kserve_model:predictive-analytics"].Status)
+	assert.Equal(t, models.ComponentStatusOK, deps["kserve_model:disk-predictor"].Status)
+
+	health := models.NewHealthResponse("test", time.Now())
+	for name, dep := range deps {
+		health.AddDependency(name, &dep)
+	}
+	assert.Equal(t, models.HealthStatusDegraded, health.Status, "model mismatches do not make the engine unhealthy")
+}
+
+func TestExporterDependencies(t *testing.T) {
+	assert.Empty(t, exporterDependencies(nil), "no dependencies before the first probe")
+
+	probedAt := time.Now().UTC()
+	deps := exporterDependencies(&integrations.Capabilities{
+		ProbedAt: probedAt,
+		Exporters: map[string]integrations.ExporterStatus{
+			integrations.ExporterCAdvisor:         {Exporter: integrations.ExporterCAdvisor, Metric: "container_cpu_usage_seconds_total", Available: true},
+			integrations.ExporterKubeStateMetrics: {Exporter: integrations.ExporterKubeStateMetrics, Metric: "kube_pod_info"},
+			integrations.ExporterDCGM:             {Exporter: integrations.ExporterDCGM, Metric: "DCGM_FI_DEV_GPU_UTIL", Optional: true},
+			integrations.ExporterNodeExporter:     {Exporter: integrations.ExporterNodeExporter, Metric: "node_cpu_seconds_total", Error: "timeout"},
+		},
+	})
+	require.Len(t, deps, 4)
+
+	assert.Equal(t, models.ComponentStatusOK, deps["metrics_exporter:cadvisor"].Status)
+	assert.Equal(t, probedAt, deps["metrics_exporter:cadvisor"].CheckedAt)
+	assert.Equal(t, models.ComponentStatusDegraded, deps["metrics_exporter:kube-state-metrics"].Status)
+	assert.Equal(t, models.ComponentStatusOK, deps["metrics_exporter:dcgm"].Status, "optional exporters may be missing")
+	assert.Equal(t, models.ComponentStatusDegraded, deps["metrics_exporter:node-exporter"].Status)
+	assert.Contains(t, deps["metrics_exporter:node-exporter"].Message, "timeout")
+}
+
+func TestSelfMonitorDependency(t *testing.T) {
+	dep := selfMonitorDependency(selfmonitor.Status{Healthy: true})
+	assert.Equal(t, models.ComponentStatusOK, dep.Status)
+
+	dep = selfMonitorDependency(selfmonitor.Status{
+		ReducedFunctionality: []string{selfmonitor.CapabilityDetection, selfmonitor.CapabilityRemediation},
+	})
+	assert.Equal(t, models.ComponentStatusDegraded, dep.Status)
+	assert.Equal(t, "Reduced functionality: deployment_detection, remediation", dep.Message)
+
+	health := models.NewHealthResponse("test", time.Now())
+	health.AddDependency(dep.Name, &dep)
+	assert.Equal(t, models.HealthStatusDegraded, health.Status, "reduced functionality does not make the engine unhealthy")
+}
+
+func TestStartupDependencies(t *testing.T) {
+	checkedAt := time.Now().UTC()
+	deps := startupDependencies([]startup.Result{
+		{Dependency: "kserve", Mode: startup.ModeDegraded, Error: "no model ready", CheckedAt: checkedAt},
+		{Dependency: "prometheus", Mode: startup.ModeWait, Available: true, CheckedAt: checkedAt},
+		{Dependency: "storage", Mode: startup.ModeFail, Error: "/app/data is not writable"},
+	})
+	require.Len(t, deps, 3)
+
+	assert.Equal(t, models.ComponentStatusDegraded, deps[0].Status)
+	assert.Equal(t, "Unavailable: no model ready", deps[0].Message)
+	assert.Equal(t, "degraded", deps[0].StartupMode)
+	assert.Equal(t, models.ComponentStatusOK, deps[1].Status)
+	assert.Equal(t, "wait", deps[1].StartupMode)
+	assert.Equal(t, checkedAt, deps[1].CheckedAt)
+	assert.Equal(t, models.ComponentStatusDown, deps[2].Status, "lost after startup")
+}
+
+func TestFeatureSchemas(t *testing.T) {
+	names := AnomalyFeatureNames()
+	require.Len(t, names, 45)
+	assert.Equal(t, "node_cpu_utilization_value", names[0])
+	assert.Equal(t, "container_restart_count_pct_change", names[44])
+	assert.Equal(t, []string{"hour_of_day", "day_of_week", "cpu_rolling_mean", "memory_rolling_mean"}, PredictionFeatureNames())
+}
@@ -0,0 +1,251 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/storage"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/api/validation"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// UpdateIncidentTagsRequest sets and removes tags of an incident
+type UpdateIncidentTagsRequest struct {
+	// Set adds tags or replaces their values
+	Set map[string]string `json:"set,omitempty"`
+
+	// Remove deletes tags by key; keys also in Set are set
+	Remove []string `json:"remove,omitempty"`
+}
+
+// TagValueStats counts the incidents with one value of a tag
+type TagValueStats struct {
+	Value    string `json:"value"`
+	Total    int    `json:"total"`
+	Active   int    `json:"active"`
+	Resolved int    `json:"resolved"`
+
+	// BySeverity counts the incidents of each severity
+	BySeverity map[string]int `json:"by_severity"`
+
+	// MeanTimeToResolveSeconds averages the resolved incidents, unset when none resolved
+	MeanTimeToResolveSeconds float64 `json:"mean_time_to_resolve_seconds,omitempty"`
+}
+
+// IncidentTagStatsResponse breaks incidents down by the values of a tag, most
+// incidents first
+type IncidentTagStatsResponse struct {
+	Key    string          `json:"key"`
+	Total  int             `json:"total"`
+	Values []TagValueStats `json:"values"`
+
+	// Untagged counts the incidents without the tag
+	Untagged int `json:"untagged"`
+}
+
+// parseTagFilter parses tag query parameters of the form key:value, or key for any
+// value of the tag
+func parseTagFilter(params []string) (map[string]string, *validation.FieldError) {
+	if len(params) == 0 {
+		return nil, nil
+	}
+	tags := make(map[string]string, len(params))
+	for _, param := range params {
+		key, value, _ := strings.Cut(param, ":")
+		if !models.IsValidTagKey(key) {
+			return nil, &validation.FieldError{
+				Field:      "tag",
+				Constraint: validation.ConstraintFormat,
+				Value:      param,
+				Message:    "tag must be key or key:value with a valid tag key",
+			}
+		}
+		tags[key] = value
+	}
+	return tags, nil
+}
+
+// UpdateIncidentTags handles PATCH /api/v1/incidents/{id}/tags
+// @Summary Update incident tags
+// @Description Sets and removes tags of an incident, e.g. to attribute it to a team, service or cost center after it was created
+// @Tags incidents
+// @Accept json
+// @Produce json
+// @Param id path string true "Incident ID"
+// @Param request body UpdateIncidentTagsRequest true "Tags to set and remove"
+// @Success 200 {object} models.Incident
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/incidents/{id}/tags [patch]
+func (h *RemediationHandler) UpdateIncidentTags(w http.ResponseWriter, r *http.Request) {
+	incidentID := mux.Vars(r)["id"]
+
+	var req UpdateIncidentTagsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendErrorResponse(w, http.StatusBadRequest, "Invalid request body: "+err.Error(), validation.DecodeFields(err)...)
+		return
+	}
+	if len(req.Set) == 0 && len(req.Remove) == 0 {
+		h.sendErrorResponse(w, http.StatusBadRequest, "set or remove is required",
+			validation.FieldError{Field: "set", Constraint: validation.ConstraintRequired, Message: "set or remove is required"})
+		return
+	}
+
+	incident, err := h.incidentStore.Get(incidentID)
+	if err != nil {
+		h.sendErrorResponse(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	// Copies of an incident share its tags, so update a new map
+	updated := *incident
+	updated.Tags = make(map[string]string, len(incident.Tags)+len(req.Set))
+	for key, value := range incident.Tags {
+		updated.Tags[key] = value
+	}
+	for _, key := range req.Remove {
+		delete(updated.Tags, key)
+	}
+	for key, value := range req.Set {
+		updated.Tags[key] = value
+	}
+	if err := updated.Validate(); err != nil {
+		h.sendErrorResponse(w, http.StatusBadRequest, err.Error(), validation.Fields(err)...)
+		return
+	}
+	if len(updated.Tags) == 0 {
+		updated.Tags = nil
+	}
+	if err := h.incidentStore.Update(&updated); err != nil {
+		h.sendErrorResponse(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	h.log.WithFields(logrus.Fields{
+		"incident_id": incidentID,
+		"tags":        updated.Tags,
+	}).Info("Incident tags updated")
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(&updated); err != nil {
+		h.log.WithError(err).Error("Failed to encode incident response")
+	}
+}
+
+// IncidentTagStats handles GET /api/v1/incidents/tag-stats
+// @Summary Break incidents down by tag
+// @Description Counts stored incidents per value of a tag, e.g. per team or cost center, with their status, severity and mean time to resolve
+// @Tags incidents
+// @Produce json
+// @Param key query string true "Tag key, e.g. team"
+// @Param namespace query string false "Namespace"
+// @Param tag query string false "Only incidents with this tag (key or key:value), repeatable"
+// @Param since query string false "Only incidents created within this duration, e.g. 168h"
+// @Success 200 {object} IncidentTagStatsResponse
+// @Failure 400 {object} ErrorResponse
+// @Router /api/v1/incidents/tag-stats [get]
+func (h *RemediationHandler) IncidentTagStats(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	key := query.Get("key")
+	if !models.IsValidTagKey(key) {
+		message := "key must be a valid tag key"
+		h.sendErrorResponse(w, http.StatusBadRequest, message,
+			validation.FieldError{Field: "key", Constraint: validation.ConstraintFormat, Value: key, Message: message})
+		return
+	}
+	tags, fieldErr := parseTagFilter(query["tag"])
+	if fieldErr != nil {
+		h.sendErrorResponse(w, http.StatusBadRequest, fieldErr.Message, *fieldErr)
+		return
+	}
+	var since time.Time
+	if sinceStr := query.Get("since"); sinceStr != "" {
+		window, err := time.ParseDuration(sinceStr)
+		if err != nil || window <= 0 {
+			message := "since must be a positive duration"
+			h.sendErrorResponse(w, http.StatusBadRequest, message,
+				validation.FieldError{Field: "since", Constraint: validation.ConstraintFormat, Value: sinceStr, Message: message})
+			return
+		}
+		since = time.Now().Add(-window)
+	}
+
+	incidents := h.incidentStore.List(storage.ListFilter{
+		Namespace: query.Get("namespace"),
+		Status:    "all",
+		Tags:      tags,
+	})
+	response := tagStats(key, incidents, since)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.log.WithError(err).Error("Failed to encode tag stats response")
+	}
+}
+
+// tagStats counts incidents created since a time (zero for all) per value of a tag
+func tagStats(key string, incidents []*models.Incident, since time.Time) IncidentTagStatsResponse {
+	response := IncidentTagStatsResponse{Key: key, Values: make([]TagValueStats, 0)}
+	byValue := make(map[string]*TagValueStats)
+
+	// resolveTimes sums the time to resolve of the resolved incidents of each value
+	type resolveTimes struct {
+		total time.Duration
+		count int
+	}
+	resolved := make(map[string]*resolveTimes)
+	for _, incident := range incidents {
+		if incident.CreatedAt.Before(since) {
+			continue
+		}
+		response.Total++
+		value, ok := incident.Tags[key]
+		if !ok {
+			response.Untagged++
+			continue
+		}
+
+		stats, ok := byValue[value]
+		if !ok {
+			stats = &TagValueStats{Value: value, BySeverity: make(map[string]int)}
+			byValue[value] = stats
+		}
+		stats.Total++
+		stats.BySeverity[string(incident.Severity)]++
+		switch {
+		case incident.IsActive():
+			stats.Active++
+		case incident.Status == models.IncidentStatusResolved:
+			stats.Resolved++
+			if incident.ResolvedAt != nil {
+				if resolved[value] == nil {
+					resolved[value] = &resolveTimes{}
+				}
+				resolved[value].total += incident.ResolvedAt.Sub(incident.CreatedAt)
+				resolved[value].count++
+			}
+		}
+	}
+
+	for value, stats := range byValue {
+		if times := resolved[value]; times != nil {
+			stats.MeanTimeToResolveSeconds = times.total.Seconds() / float64(times.count)
+		}
+		response.Values = append(response.Values, *stats)
+	}
+	sort.Slice(response.Values, func(i, j int) bool {
+		if response.Values[i].Total != response.Values[j].Total {
+			return response.Values[i].Total > response.Values[j].Total
+		}
+		return response.Values[i].Value < response.Values[j].Value
+	})
+	return response
+}
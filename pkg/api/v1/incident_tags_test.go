@@ -0,0 +1,126 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/detector"
+	"github.com/tosin2013/openshift-coordination-engine/internal/remediation"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+func TestRemediationHandler_IncidentTags(t *testing.T) {
+	t.Setenv("DATA_DIR", t.TempDir())
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	orchestrator := remediation.NewOrchestrator(detector.NewDeploymentDetector(fake.NewSimpleClientset(), log), fakeRemediator{}, log)
+	handler := NewRemediationHandler(orchestrator, log)
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v1/incidents", handler.ListIncidents).Methods("GET")
+	router.HandleFunc("/api/v1/incidents", handler.CreateIncident).Methods("POST")
+	router.HandleFunc("/api/v1/incidents/tag-stats", handler.IncidentTagStats).Methods("GET")
+	router.HandleFunc("/api/v1/incidents/{id}/tags", handler.UpdateIncidentTags).Methods("PATCH")
+	serve := func(method, path, body string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return rr
+	}
+	create := func(title, severity, tags string) string {
+		rr := serve(http.MethodPost, "/api/v1/incidents",
+			`{"title":"`+title+`","description":"`+title+`","severity":"`+severity+`","target":"shop","tags":`+tags+`}`)
+		require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+		var created CreateIncidentResponse
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &created))
+		return created.IncidentID
+	}
+	list := func(query string) []string {
+		rr := serve(http.MethodGet, "/api/v1/incidents?"+query, "")
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		var resp struct {
+			Incidents []struct {
+				Title string            `json:"title"`
+				Tags  map[string]string `json:"tags"`
+			} `json:"incidents"`
+		}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+		titles := make([]string, 0, len(resp.Incidents))
+		for _, incident := range resp.Incidents {
+			titles = append(titles, incident.Title)
+		}
+		return titles
+	}
+
+	checkout := create("Checkout errors", "critical", `{"team":"payments","service":"checkout"}`)
+	create("Refund delays", "high", `{"team":"payments"}`)
+	catalog := create("Catalog slow", "medium", `{"team":"storefront"}`)
+	create("Disk filling", "low", `null`)
+
+	assert.ElementsMatch(t, []string{"Checkout errors", "Refund delays"}, list("tag=team:payments"))
+	assert.Equal(t, []string{"Checkout errors"}, list("tag=team:payments&tag=service"))
+	assert.Len(t, list("tag=team"), 3)
+	assert.Equal(t, http.StatusBadRequest, serve(http.MethodGet, "/api/v1/incidents?tag=:payments", "").Code)
+
+	rr := serve(http.MethodPost, "/api/v1/incidents", `{"title":"x","description":"x","severity":"low","target":"shop","tags":{"bad key":"x"}}`)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"field":"tags"`)
+
+	// Retag the catalog incident to payments and drop the checkout service
+	rr = serve(http.MethodPatch, "/api/v1/incidents/"+catalog+"/tags", `{"set":{"team":"payments","cost_center":"cc-42"}}`)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var updated models.Incident
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &updated))
+	assert.Equal(t, map[string]string{"team": "payments", "cost_center": "cc-42"}, updated.Tags)
+	rr = serve(http.MethodPatch, "/api/v1/incidents/"+checkout+"/tags", `{"remove":["service"]}`)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Empty(t, list("tag=service"))
+
+	assert.Equal(t, http.StatusBadRequest, serve(http.MethodPatch, "/api/v1/incidents/"+checkout+"/tags", `{}`).Code)
+	assert.Equal(t, http.StatusBadRequest, serve(http.MethodPatch, "/api/v1/incidents/"+checkout+"/tags", `{"set":{"-x":"y"}}`).Code)
+	assert.Equal(t, http.StatusNotFound, serve(http.MethodPatch, "/api/v1/incidents/inc-missing/tags", `{"remove":["team"]}`).Code)
+
+	// Resolve one payments incident an hour after it was created
+	stored, err := handler.GetIncidentStore().Get(checkout)
+	require.NoError(t, err)
+	resolved := *stored
+	resolved.Resolve()
+	resolvedAt := resolved.CreatedAt.Add(time.Hour)
+	resolved.ResolvedAt = &resolvedAt
+	require.NoError(t, handler.GetIncidentStore().Update(&resolved))
+
+	rr = serve(http.MethodGet, "/api/v1/incidents/tag-stats?key=team", "")
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var stats IncidentTagStatsResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &stats))
+	assert.Equal(t, IncidentTagStatsResponse{
+		Key:   "team",
+		Total: 4,
+		Values: []TagValueStats{{
+			Value:                    "payments",
+			Total:                    3,
+			Active:                   2,
+			Resolved:                 1,
+			BySeverity:               map[string]int{"critical": 1, "high": 1, "medium": 1},
+			MeanTimeToResolveSeconds: 3600,
+		}},
+		Untagged: 1,
+	}, stats)
+
+	rr = serve(http.MethodGet, "/api/v1/incidents/tag-stats?key=team&tag=cost_center:cc-42", "")
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &stats))
+	assert.Equal(t, 1, stats.Total)
+
+	assert.Equal(t, http.StatusBadRequest, serve(http.MethodGet, "/api/v1/incidents/tag-stats", "").Code)
+	assert.Equal(t, http.StatusBadRequest, serve(http.MethodGet, "/api/v1/incidents/tag-stats?key=team&since=1w", "").Code)
+}
@@ -0,0 +1,183 @@
+package v1
+
+import (
+	"crypto/subtle"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/kserve"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/middleware"
+)
+
+// InferOptions configures the raw inference gateway at POST /api/v1/models/{model}/infer
+type InferOptions struct {
+	// Token is a bearer token accepted from callers without a client certificate
+	// (empty accepts only client certificates)
+	Token string
+
+	// RateLimit is the sustained number of requests per second per caller and model
+	RateLimit float64
+
+	// Burst is the number of requests a caller may send at once per model
+	Burst int
+
+	// MaxBodyBytes bounds the request body
+	MaxBodyBytes int64
+}
+
+// inferGateway authenticates and rate limits raw inference requests
+type inferGateway struct {
+	opts     InferOptions
+	limiters *callerLimiters
+}
+
+// SetInferOptions enables the raw inference gateway, which forwards arbitrary request
+// bodies to a registered model. Callers authenticate with a verified client
+// certificate or the bearer token, are rate limited per model, and every request is
+// audited. Must be called before RegisterRoutes.
+func (h *KServeProxyHandler) SetInferOptions(opts InferOptions) {
+	h.infer = &inferGateway{opts: opts, limiters: newCallerLimiters(opts.RateLimit, opts.Burst)}
+}
+
+// authenticate returns the caller of a request: the DNS or common name of its
+// client certificate, or the token holder's address. Client certificates are only
+// present once the server has verified them.
+func (g *inferGateway) authenticate(r *http.Request) (string, bool) {
+	if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
+		cert := r.TLS.PeerCertificates[0]
+		if len(cert.DNSNames) > 0 {
+			return cert.DNSNames[0], true
+		}
+		return cert.Subject.CommonName, true
+	}
+
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || g.opts.Token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(g.opts.Token)) != 1 {
+		return "", false
+	}
+	return "token@" + remoteHost(r), true
+}
+
+// allow takes a request from the caller's bucket for a model
+func (g *inferGateway) allow(caller, model string) bool {
+	return g.limiters.allow(caller + "|" + model)
+}
+
+// HandleInfer handles POST /api/v1/models/{model}/infer
+// @Summary Raw inference passthrough
+// @Description Forwards the request body unchanged to the model's KServe v1 predict endpoint and returns its answer
+// @Tags kserve
+// @Accept json
+// @Produce json
+// @Param model path string true "Model name"
+// @Param request body object true "Model input in the KServe v1 format"
+// @Success 200 {object} object "The model's response"
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v1/models/{model}/infer [post]
+func (h *KServeProxyHandler) HandleInfer(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	modelName := mux.Vars(r)["model"]
+	var (
+		caller, version, outcome string
+		status                   int
+		requestBytes             int
+		responseBytes            int
+	)
+	defer func() {
+		label := modelName
+		if _, ok := h.proxyClient.GetModel(modelName); !ok {
+			label = "unknown"
+		}
+		kserve.InferRequests.WithLabelValues(label, outcome).Inc()
+		h.log.WithFields(logrus.Fields{
+			"audit":          "kserve_infer",
+			"request_id":     w.Header().Get(middleware.RequestIDHeader),
+			"caller":         caller,
+			"remote_addr":    r.RemoteAddr,
+			"model":          modelName,
+			"version":        version,
+			"status":         status,
+			"outcome":        outcome,
+			"request_bytes":  requestBytes,
+			"response_bytes": responseBytes,
+			"duration_ms":    time.Since(start).Milliseconds(),
+		}).Info("Model inference request")
+	}()
+
+	reject := func(statusCode int, outcomeLabel, message string) {
+		status, outcome = statusCode, outcomeLabel
+		h.respondError(w, statusCode, message)
+	}
+
+	var ok bool
+	if caller, ok = h.infer.authenticate(r); !ok {
+		reject(http.StatusUnauthorized, "unauthorized", "client certificate or inference token required")
+		return
+	}
+	if _, exists := h.proxyClient.GetModel(modelName); !exists {
+		reject(http.StatusNotFound, "rejected", (&kserve.ModelNotFoundError{ModelName: modelName}).Error())
+		return
+	}
+	if !h.infer.allow(caller, modelName) {
+		w.Header().Set("Retry-After", h.infer.limiters.retryAfter())
+		reject(http.StatusTooManyRequests, "rate_limited", "rate limit exceeded for model: "+modelName)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, h.infer.opts.MaxBodyBytes))
+	requestBytes = len(body)
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		reject(http.StatusRequestEntityTooLarge, "rejected", "request body exceeds "+strconv.FormatInt(tooLarge.Limit, 10)+" bytes")
+		return
+	case err != nil:
+		reject(http.StatusBadRequest, "rejected", "failed to read request body: "+err.Error())
+		return
+	case len(body) == 0:
+		reject(http.StatusBadRequest, "rejected", "request body is empty")
+		return
+	}
+
+	resp, err := h.proxyClient.Infer(r.Context(), modelName, body, r.Header.Get("Content-Type"))
+	if err != nil {
+		h.log.WithError(err).WithField("model", modelName).Error("KServe inference failed")
+		var unavailableErr *kserve.ModelUnavailableError
+		if errors.As(err, &unavailableErr) {
+			reject(http.StatusServiceUnavailable, "unavailable", err.Error())
+			return
+		}
+		reject(http.StatusBadGateway, "unavailable", err.Error())
+		return
+	}
+
+	version, status, responseBytes = resp.ModelVersion, resp.StatusCode, len(resp.Body)
+	outcome = "success"
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		outcome = "model_error"
+	}
+
+	contentType := resp.ContentType
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	w.Header().Set("Content-Type", contentType)
+	if version != "" {
+		w.Header().Set("X-Model-Version", version)
+	}
+	w.WriteHeader(resp.StatusCode)
+	if _, err := w.Write(resp.Body); err != nil {
+		h.log.WithError(err).Error("Failed to write inference response")
+	}
+}
@@ -10,12 +10,14 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/sirupsen/logrus"
 
+	"github.com/tosin2013/openshift-coordination-engine/pkg/api/validation"
 	"github.com/tosin2013/openshift-coordination-engine/pkg/kserve"
 )
 
 // KServeProxyHandler handles KServe model proxy API requests (ADR-039, ADR-040)
 type KServeProxyHandler struct {
 	proxyClient *kserve.ProxyClient
+	infer       *inferGateway
 	log         *logrus.Logger
 }
 
@@ -43,7 +45,16 @@ func (h *KServeProxyHandler) RegisterRoutes(router *mux.Router) {
 	// GET /api/v1/models/{model}/health - Check model health
 	router.HandleFunc("/api/v1/models/{model}/health", h.CheckModelHealth).Methods("GET")
 
-	h.log.Info("KServe proxy API routes registered: /api/v1/detect, /api/v1/models, /api/v1/models/{model}/health")
+	// GET /api/v1/models/{model}/rollout - Version pinning, canary split and per-version outcomes
+	router.HandleFunc("/api/v1/models/{model}/rollout", h.GetModelRollout).Methods("GET")
+
+	h.log.Info("KServe proxy API routes registered: /api/v1/detect, /api/v1/models, /api/v1/models/{model}/health, /api/v1/models/{model}/rollout")
+
+	// POST /api/v1/models/{model}/infer - Authenticated, rate limited raw inference passthrough
+	if h.infer != nil {
+		router.HandleFunc("/api/v1/models/{model}/infer", h.HandleInfer).Methods("POST")
+		h.log.Info("KServe inference gateway registered: /api/v1/models/{model}/infer")
+	}
 }
 
 // HandleDetect handles POST /api/v1/detect
@@ -71,18 +82,20 @@ func (h *KServeProxyHandler) HandleDetect(w http.ResponseWriter, r *http.Request
 	var req kserve.DetectRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		h.log.WithError(err).Debug("Invalid detect request format")
-		h.respondError(w, http.StatusBadRequest, "Invalid request format")
+		h.respondError(w, http.StatusBadRequest, "Invalid request format", validation.DecodeFields(err)...)
 		return
 	}
 
 	// Validate request
+	var fieldErrs validation.Errors
 	if req.Model == "" {
-		h.respondError(w, http.StatusBadRequest, "Missing 'model' field")
-		return
+		fieldErrs.Add("model", validation.ConstraintRequired, nil, "Missing 'model' field")
 	}
-
 	if len(req.Instances) == 0 {
-		h.respondError(w, http.StatusBadRequest, "Missing 'instances' field")
+		fieldErrs.Add("instances", validation.ConstraintRequired, nil, "Missing 'instances' field")
+	}
+	if len(fieldErrs) > 0 {
+		h.respondError(w, http.StatusBadRequest, fieldErrs.Error(), fieldErrs...)
 		return
 	}
 
@@ -99,11 +112,18 @@ func (h *KServeProxyHandler) HandleDetect(w http.ResponseWriter, r *http.Request
 		// Check error type for appropriate HTTP status
 		var notFoundErr *kserve.ModelNotFoundError
 		var unavailableErr *kserve.ModelUnavailableError
+		var invalidErr *kserve.InvalidResponseError
 		switch {
 		case errors.As(err, &notFoundErr):
 			h.respondError(w, http.StatusNotFound, err.Error())
 		case errors.As(err, &unavailableErr):
 			h.respondError(w, http.StatusServiceUnavailable, err.Error())
+		case errors.As(err, &invalidErr):
+			h.respondJSON(w, http.StatusBadGateway, ErrorResponse{
+				Error:   err.Error(),
+				Success: false,
+				Code:    invalidErr.Code,
+			})
 		default:
 			h.respondError(w, http.StatusInternalServerError, "Prediction failed: "+err.Error())
 		}
@@ -154,7 +174,8 @@ func (h *KServeProxyHandler) CheckModelHealth(w http.ResponseWriter, r *http.Req
 	modelName := vars["model"]
 
 	if modelName == "" {
-		h.respondError(w, http.StatusBadRequest, "Model name is required")
+		h.respondError(w, http.StatusBadRequest, "Model name is required",
+			validation.FieldError{Field: "model", Constraint: validation.ConstraintRequired, Message: "Model name is required"})
 		return
 	}
 
@@ -177,6 +198,27 @@ func (h *KServeProxyHandler) CheckModelHealth(w http.ResponseWriter, r *http.Req
 	h.respondJSON(w, http.StatusOK, health)
 }
 
+// GetModelRollout handles GET /api/v1/models/{model}/rollout
+// @Summary Get a model's version rollout
+// @Description Returns the pinned and canary versions of a model and compares their recorded outcomes
+// @Tags kserve
+// @Produce json
+// @Param model path string true "Model name"
+// @Success 200 {object} kserve.RolloutStatus
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/models/{model}/rollout [get]
+func (h *KServeProxyHandler) GetModelRollout(w http.ResponseWriter, r *http.Request) {
+	modelName := mux.Vars(r)["model"]
+
+	status, ok := h.proxyClient.RolloutStatus(modelName)
+	if !ok {
+		h.respondError(w, http.StatusNotFound, "no rollout configured for model: "+modelName)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, status)
+}
+
 // ModelsListResponse represents the response for listing models
 type ModelsListResponse struct {
 	Models []string `json:"models"`
@@ -187,6 +229,12 @@ type ModelsListResponse struct {
 type ErrorResponse struct {
 	Error   string `json:"error"`
 	Success bool   `json:"success"`
+
+	// Code identifies the failure, e.g. the validation code of an invalid model response
+	Code string `json:"code,omitempty"`
+
+	// Errors lists the invalid request fields of a validation failure
+	Errors []validation.FieldError `json:"errors,omitempty"`
 }
 
 // respondJSON writes a JSON response
@@ -200,10 +248,11 @@ func (h *KServeProxyHandler) respondJSON(w http.ResponseWriter, statusCode int,
 }
 
 // respondError writes an error response
-func (h *KServeProxyHandler) respondError(w http.ResponseWriter, statusCode int, message string) {
+func (h *KServeProxyHandler) respondError(w http.ResponseWriter, statusCode int, message string, fieldErrs ...validation.FieldError) {
 	response := ErrorResponse{
 		Error:   message,
 		Success: false,
+		Errors:  fieldErrs,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
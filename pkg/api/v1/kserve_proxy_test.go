@@ -3,9 +3,11 @@ package v1
 import (
 	"bytes"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -229,6 +231,84 @@ func TestKServeProxyHandler_RegisterRoutes(t *testing.T) {
 	}
 }
 
+func TestKServeProxyHandler_HandleInfer(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	// The custom model echoes its input, or rejects bodies without instances
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		if !bytes.Contains(body, []byte("instances")) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error": "missing instances"}`))
+			return
+		}
+		w.Write([]byte(`{"predictions": ` + string(bytes.TrimPrefix(bytes.TrimSuffix(body, []byte("}")), []byte(`{"instances": `))) + `}`))
+	}))
+	defer mockServer.Close()
+
+	os.Setenv("KSERVE_CUSTOM_MODEL_SERVICE", "custom-model-predictor")
+	defer os.Unsetenv("KSERVE_CUSTOM_MODEL_SERVICE")
+
+	client, err := kserve.NewProxyClient(kserve.ProxyConfig{Namespace: "test-ns"}, log)
+	require.NoError(t, err)
+	rollouts, err := kserve.NewRollouts(map[string]kserve.ModelRollout{
+		"custom-model": {Versions: map[string]string{"v1": mockServer.URL}, Pinned: "v1"},
+	})
+	require.NoError(t, err)
+	client.SetRollouts(rollouts)
+
+	handler := NewKServeProxyHandler(client, log)
+	handler.SetInferOptions(InferOptions{Token: "secret", RateLimit: 0.01, Burst: 3, MaxBodyBytes: 64})
+	router := mux.NewRouter()
+	handler.RegisterRoutes(router)
+
+	infer := func(model, token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/api/v1/models/"+model+"/infer", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("requires authentication", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, infer("custom-model", "", `{"instances": [[1]]}`).Code)
+		assert.Equal(t, http.StatusUnauthorized, infer("custom-model", "wrong", `{"instances": [[1]]}`).Code)
+	})
+
+	t.Run("unknown model", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotFound, infer("other-model", "secret", `{"instances": [[1]]}`).Code)
+	})
+
+	t.Run("body too large", func(t *testing.T) {
+		w := infer("custom-model", "secret", `{"instances": [[`+strings.Repeat("1,", 40)+`1]]}`)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	})
+
+	t.Run("passes the body through", func(t *testing.T) {
+		w := infer("custom-model", "secret", `{"instances": [[1, 2]]}`)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "v1", w.Header().Get("X-Model-Version"))
+		assert.JSONEq(t, `{"predictions": [[1, 2]]}`, w.Body.String())
+	})
+
+	t.Run("returns model errors unchanged", func(t *testing.T) {
+		w := infer("custom-model", "secret", `{"inputs": []}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error": "missing instances"}`, w.Body.String())
+	})
+
+	t.Run("rate limited", func(t *testing.T) {
+		w := infer("custom-model", "secret", `{"instances": [[1]]}`)
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "100", w.Header().Get("Retry-After"))
+	})
+}
+
 func TestErrorResponse_JSON(t *testing.T) {
 	resp := ErrorResponse{
 		Error:   "test error",
@@ -0,0 +1,459 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/apitoken"
+	"github.com/tosin2013/openshift-coordination-engine/internal/integrations"
+	"github.com/tosin2013/openshift-coordination-engine/internal/promql"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/api/validation"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// Metrics query defaults: the range covered without start, the number of points a
+// chart gets without step, the finest step and the most points a range may have
+const (
+	DefaultMetricsQueryRange  = time.Hour
+	defaultMetricsQueryPoints = 120
+	minMetricsQueryStep       = 15 * time.Second
+	maxMetricsQueryPoints     = 11000
+)
+
+// metricsQuery is a named query the metrics proxy serves: the PromQL template it runs
+// at each scope level it supports, and the unit of its values
+type metricsQuery struct {
+	Unit      string
+	Templates map[models.ScopeLevel]string
+}
+
+// metricsQueries are the queries callers may run by name. Raw PromQL is never
+// accepted, so the proxy cannot be used to read arbitrary series.
+var metricsQueries = map[string]metricsQuery{
+	"cpu_usage": {Unit: "cores", Templates: map[models.ScopeLevel]string{
+		models.ScopeCluster:    "cluster.cpu_usage",
+		models.ScopeNamespace:  "namespace.cpu_usage",
+		models.ScopeDeployment: "scope.cpu_usage",
+		models.ScopePod:        "scope.cpu_usage",
+	}},
+	"memory_usage": {Unit: "bytes", Templates: map[models.ScopeLevel]string{
+		models.ScopeCluster:    "cluster.memory_usage",
+		models.ScopeNamespace:  "namespace.memory_usage",
+		models.ScopeDeployment: "scope.memory_usage",
+		models.ScopePod:        "scope.memory_usage",
+	}},
+	"cpu_utilization": {Unit: "ratio", Templates: map[models.ScopeLevel]string{
+		models.ScopeCluster:    "cluster.cpu_utilization",
+		models.ScopeNamespace:  "namespace.cpu_utilization",
+		models.ScopeDeployment: "scope.cpu_utilization",
+		models.ScopePod:        "scope.cpu_utilization",
+	}},
+	"memory_utilization": {Unit: "ratio", Templates: map[models.ScopeLevel]string{
+		models.ScopeCluster:    "cluster.memory_utilization",
+		models.ScopeNamespace:  "namespace.memory_utilization",
+		models.ScopeDeployment: "scope.memory_utilization",
+		models.ScopePod:        "scope.memory_utilization",
+	}},
+	"pod_cpu_usage": {Unit: "cores", Templates: map[models.ScopeLevel]string{
+		models.ScopeNamespace: "namespace.pod_cpu_usage",
+	}},
+	"pod_memory_usage": {Unit: "bytes", Templates: map[models.ScopeLevel]string{
+		models.ScopeNamespace: "namespace.pod_memory_usage",
+	}},
+	"container_restarts": {Unit: "count", Templates: map[models.ScopeLevel]string{
+		models.ScopeNamespace: "namespace.container_restarts",
+	}},
+}
+
+// MetricsQueryNames returns the names of the queries the metrics proxy serves, sorted
+func MetricsQueryNames() []string {
+	names := make([]string, 0, len(metricsQueries))
+	for name := range metricsQueries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// MetricsQueryOptions configures the metrics proxy at GET /api/v1/metrics/query
+type MetricsQueryOptions struct {
+	// RateLimit is the sustained number of queries per second per caller
+	RateLimit float64
+
+	// Burst is the number of queries a caller may send at once
+	Burst int
+
+	// MaxRange bounds the time range of a query
+	MaxRange time.Duration
+}
+
+// MetricsQueryRequest is a metrics query: a named query over a scope and time range
+type MetricsQueryRequest struct {
+	Query string
+	Scope models.Scope
+	Start time.Time
+	End   time.Time
+	Step  time.Duration
+}
+
+// MetricsQueryResponse is the result of a metrics query, normalized for charts
+type MetricsQueryResponse struct {
+	Status string `json:"status"`
+	Query  string `json:"query"`
+
+	// Template is the ID of the PromQL template the query ran, e.g. "scope.cpu_usage@v1"
+	Template string       `json:"template"`
+	Unit     string       `json:"unit"`
+	Scope    models.Scope `json:"scope"`
+	Start    time.Time    `json:"start"`
+	End      time.Time    `json:"end"`
+	Step     string       `json:"step"`
+
+	// Series are sorted by their labels; points without a finite value are dropped
+	Series []MetricsSeries `json:"series"`
+}
+
+// MetricsSeries is one series of a metrics query result
+type MetricsSeries struct {
+	Labels map[string]string `json:"labels"`
+	Points []MetricsPoint    `json:"points"`
+}
+
+// MetricsPoint is a value of a series at a point in time
+type MetricsPoint struct {
+	Timestamp time.Time `json:"timestamp"`
+	Value     float64   `json:"value"`
+}
+
+// MetricsQueryHandler serves named Prometheus queries over a scope, so the console
+// plugin can render charts without being granted direct Thanos access
+type MetricsQueryHandler struct {
+	prometheusClient *integrations.PrometheusClient
+	opts             MetricsQueryOptions
+	limiters         *callerLimiters
+	log              *logrus.Logger
+	now              func() time.Time
+}
+
+// NewMetricsQueryHandler creates a new metrics query handler
+func NewMetricsQueryHandler(prometheusClient *integrations.PrometheusClient, opts MetricsQueryOptions, log *logrus.Logger) *MetricsQueryHandler {
+	return &MetricsQueryHandler{
+		prometheusClient: prometheusClient,
+		opts:             opts,
+		limiters:         newCallerLimiters(opts.RateLimit, opts.Burst),
+		log:              log,
+		now:              time.Now,
+	}
+}
+
+// SetClock replaces the clock queries without an end are evaluated at
+func (h *MetricsQueryHandler) SetClock(now func() time.Time) {
+	h.now = now
+}
+
+// RegisterRoutes registers metrics query API routes
+func (h *MetricsQueryHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/api/v1/metrics/query", h.Query).Methods("GET")
+
+	h.log.Info("Metrics query API routes registered: /api/v1/metrics/query")
+}
+
+// Query handles GET /api/v1/metrics/query
+// @Summary Run a named metrics query
+// @Description Runs a named PromQL template (not raw PromQL) over a scope and time range and returns its series normalized for charts. Queries are rate limited per caller and subject to the query cost guard.
+// @Tags metrics
+// @Produce json
+// @Param query query string true "Query name - cpu_usage, memory_usage, cpu_utilization, memory_utilization, pod_cpu_usage, pod_memory_usage or container_restarts"
+// @Param namespace query string false "Namespace"
+// @Param deployment query string false "Deployment name"
+// @Param pod query string false "Pod name"
+// @Param scope query string false "Scope level - cluster, namespace, deployment or pod (default: inferred from the fields)"
+// @Param start query string false "Start of the range, RFC 3339 (default: 1h before end)"
+// @Param end query string false "End of the range, RFC 3339 (default: now)"
+// @Param step query string false "Resolution, e.g. 30s or 5m (default: 1/120 of the range, at least 15s)"
+// @Success 200 {object} MetricsQueryResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v1/metrics/query [get]
+func (h *MetricsQueryHandler) Query(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	caller := metricsCaller(r)
+	query := r.URL.Query()
+	var (
+		status = http.StatusOK
+		series int
+	)
+	defer func() {
+		h.log.WithFields(logrus.Fields{
+			"caller":      caller,
+			"query":       query.Get("query"),
+			"namespace":   query.Get("namespace"),
+			"deployment":  query.Get("deployment"),
+			"pod":         query.Get("pod"),
+			"status":      status,
+			"series":      series,
+			"duration_ms": time.Since(start).Milliseconds(),
+		}).Info("Metrics query request")
+	}()
+
+	if !h.limiters.allow(caller) {
+		status = http.StatusTooManyRequests
+		w.Header().Set("Retry-After", h.limiters.retryAfter())
+		h.respondError(w, status, "metrics query rate limit exceeded")
+		return
+	}
+
+	req, fieldErrs := h.parseRequest(query)
+	if len(fieldErrs) > 0 {
+		status = http.StatusBadRequest
+		h.respondError(w, status, fieldErrs.Error(), fieldErrs...)
+		return
+	}
+
+	response, err := h.QueryMetrics(r.Context(), req)
+	if err != nil {
+		var requestErr *RequestError
+		if !errors.As(err, &requestErr) {
+			requestErr = &RequestError{StatusCode: http.StatusInternalServerError, Message: err.Error()}
+		}
+		status = requestErr.StatusCode
+		h.respondError(w, status, requestErr.Error(), requestErr.Errors...)
+		return
+	}
+
+	series = len(response.Series)
+	h.respondJSON(w, http.StatusOK, response)
+}
+
+// parseRequest reads a metrics query from query parameters. A missing end is now and a
+// missing start DefaultMetricsQueryRange before it; the step is checked by QueryMetrics.
+func (h *MetricsQueryHandler) parseRequest(query url.Values) (MetricsQueryRequest, validation.Errors) {
+	get := query.Get
+	var fieldErrs validation.Errors
+	req := MetricsQueryRequest{
+		Query: get("query"),
+		Scope: models.Scope{
+			Namespace:  get("namespace"),
+			Deployment: get("deployment"),
+			Pod:        get("pod"),
+			Level:      models.ScopeLevel(get("scope")),
+		},
+		End: h.now().UTC().Truncate(time.Second),
+	}
+
+	times := []struct {
+		name  string
+		value *time.Time
+	}{
+		{"end", &req.End},
+		{"start", &req.Start},
+	}
+	for _, t := range times {
+		value := get(t.name)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			fieldErrs.Add(t.name, validation.ConstraintFormat, value, t.name+" must be an RFC 3339 timestamp")
+			continue
+		}
+		*t.value = parsed.UTC()
+	}
+	if req.Start.IsZero() {
+		req.Start = req.End.Add(-DefaultMetricsQueryRange)
+	}
+
+	if value := get("step"); value != "" {
+		step, err := time.ParseDuration(value)
+		if err != nil || step <= 0 {
+			fieldErrs.Add("step", validation.ConstraintFormat, value, "step must be a positive duration, e.g. 30s or 5m")
+		} else {
+			req.Step = step
+		}
+	}
+	return req, fieldErrs
+}
+
+// QueryMetrics runs a named query over a scope and time range. A zero step takes
+// 1/120 of the range, at least 15s.
+func (h *MetricsQueryHandler) QueryMetrics(ctx context.Context, req MetricsQueryRequest) (*MetricsQueryResponse, error) {
+	var fieldErrs validation.Errors
+	definition, ok := metricsQueries[req.Query]
+	switch {
+	case req.Query == "":
+		fieldErrs.Add("query", validation.ConstraintRequired, nil, "query is required")
+	case !ok:
+		fieldErrs.Add("query", validation.ConstraintEnum, req.Query,
+			"query must be one of: "+strings.Join(MetricsQueryNames(), ", "))
+	}
+
+	scope := req.Scope
+	var template string
+	if err := scope.Validate(); err != nil {
+		fieldErrs = append(fieldErrs, validation.Fields(err)...)
+	} else if scope = scope.Resolve(); ok {
+		if template = definition.Templates[scope.Level]; template == "" {
+			fieldErrs.Add("scope", validation.ConstraintEnum, string(scope.Level),
+				fmt.Sprintf("query %s does not support scope '%s'; supported: %s", req.Query, scope.Level, definition.levels()))
+		}
+	}
+
+	span := req.End.Sub(req.Start)
+	step := req.Step
+	if step == 0 {
+		step = max((span / defaultMetricsQueryPoints).Truncate(time.Second), minMetricsQueryStep)
+	}
+	switch {
+	case span <= 0:
+		fieldErrs.Add("start", validation.ConstraintRange, req.Start.Format(time.RFC3339), "start must be before end")
+	case h.opts.MaxRange > 0 && span > h.opts.MaxRange:
+		fieldErrs.Add("start", validation.ConstraintRange, req.Start.Format(time.RFC3339),
+			fmt.Sprintf("range must not exceed %s", h.opts.MaxRange))
+	case step < minMetricsQueryStep:
+		fieldErrs.Add("step", validation.ConstraintRange, step.String(), fmt.Sprintf("step must be at least %s", minMetricsQueryStep))
+	case span/step > maxMetricsQueryPoints:
+		fieldErrs.Add("step", validation.ConstraintRange, step.String(),
+			fmt.Sprintf("step is too fine for the range; at most %d points are returned", maxMetricsQueryPoints))
+	}
+	if err := fieldErrs.Err(); err != nil {
+		return nil, invalidRequest(err, "")
+	}
+
+	if h.prometheusClient == nil || !h.prometheusClient.IsAvailable() {
+		return nil, &RequestError{StatusCode: http.StatusServiceUnavailable, Message: "prometheus is not available"}
+	}
+
+	queries := h.prometheusClient.Queries()
+	candidates, err := queries.Candidates(template, promql.Params{
+		Selector:  integrations.ScopedSelector(scope.Namespace, scope.Deployment, scope.Pod),
+		Namespace: scope.Namespace,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var matrix integrations.RangeMatrix
+	for _, candidate := range candidates {
+		matrix, err = h.prometheusClient.QueryRangeMatrix(ctx, candidate, req.Start, req.End, step)
+		if err == nil {
+			break
+		}
+		var costErr *integrations.QueryCostError
+		if errors.As(err, &costErr) {
+			return nil, &RequestError{StatusCode: http.StatusUnprocessableEntity, Message: costErr.Error()}
+		}
+		h.log.WithError(err).WithFields(logrus.Fields{
+			"template": template,
+			"query":    candidate,
+		}).Debug("Metrics query failed, trying next variant")
+	}
+	if err != nil {
+		h.log.WithError(err).WithField("template", template).Error("Metrics query failed")
+		return nil, &RequestError{StatusCode: http.StatusBadGateway, Message: "failed to query metrics from prometheus"}
+	}
+
+	tmpl, _ := queries.Template(template)
+	return &MetricsQueryResponse{
+		Status:   "success",
+		Query:    req.Query,
+		Template: tmpl.ID(),
+		Unit:     definition.Unit,
+		Scope:    scope,
+		Start:    req.Start,
+		End:      req.End,
+		Step:     step.String(),
+		Series:   normalizeSeries(matrix),
+	}, nil
+}
+
+// levels lists the scope levels a query supports, narrowest first
+func (q metricsQuery) levels() string {
+	var levels []string
+	for _, level := range models.ScopeLevels {
+		if _, ok := q.Templates[level]; ok {
+			levels = append(levels, string(level))
+		}
+	}
+	return strings.Join(levels, ", ")
+}
+
+// normalizeSeries sorts series by their labels and drops points without a finite
+// value, which JSON cannot encode
+func normalizeSeries(matrix integrations.RangeMatrix) []MetricsSeries {
+	sorted := append(integrations.RangeMatrix(nil), matrix...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return integrations.SeriesKey(sorted[i].Metric) < integrations.SeriesKey(sorted[j].Metric)
+	})
+
+	series := make([]MetricsSeries, 0, len(sorted))
+	for _, s := range sorted {
+		labels := s.Metric
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		points := make([]MetricsPoint, 0, len(s.Points))
+		for _, point := range s.Points {
+			if math.IsNaN(point.Value) || math.IsInf(point.Value, 0) {
+				continue
+			}
+			points = append(points, MetricsPoint{Timestamp: point.Timestamp.UTC(), Value: point.Value})
+		}
+		series = append(series, MetricsSeries{Labels: labels, Points: points})
+	}
+	return series
+}
+
+// metricsCaller identifies the caller a metrics query is rate limited as: its API
+// token, its verified client certificate or its address
+func metricsCaller(r *http.Request) string {
+	if token := apitoken.FromContext(r.Context()); token != nil {
+		return "token:" + token.ID
+	}
+	if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
+		cert := r.TLS.PeerCertificates[0]
+		if len(cert.DNSNames) > 0 {
+			return "cert:" + cert.DNSNames[0]
+		}
+		return "cert:" + cert.Subject.CommonName
+	}
+	return remoteHost(r)
+}
+
+func (h *MetricsQueryHandler) respondJSON(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		h.log.WithError(err).Error("Failed to encode JSON response")
+	}
+}
+
+func (h *MetricsQueryHandler) respondError(w http.ResponseWriter, statusCode int, message string, fieldErrs ...validation.FieldError) {
+	response := ErrorResponse{
+		Error:   message,
+		Success: false,
+		Errors:  fieldErrs,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.log.WithError(err).Error("Failed to encode error response")
+	}
+}
@@ -0,0 +1,151 @@
+package v1
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/integrations"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/testsupport"
+)
+
+func newMetricsQueryRouter(t *testing.T, prom *testsupport.Prometheus, opts MetricsQueryOptions) *mux.Router {
+	t.Helper()
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	var client *integrations.PrometheusClient
+	if prom != nil {
+		client = prom.Client(log)
+	}
+	handler := NewMetricsQueryHandler(client, opts, log)
+	handler.SetClock(func() time.Time { return time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC) })
+	router := mux.NewRouter()
+	handler.RegisterRoutes(router)
+	return router
+}
+
+func getMetricsQuery(router *mux.Router, query string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/metrics/query?"+query, nil))
+	return rec
+}
+
+func TestMetricsQueryHandler_Query(t *testing.T) {
+	prom := testsupport.NewPrometheus()
+	prom.SetRange("container_cpu_usage_seconds_total", 0.5, 0.75, 1)
+	router := newMetricsQueryRouter(t, prom, MetricsQueryOptions{RateLimit: 100, Burst: 100, MaxRange: 24 * time.Hour})
+
+	rec := getMetricsQuery(router, "query=cpu_usage&namespace=payments&deployment=api&start=2026-03-10T11:00:00Z&step=1m")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var response MetricsQueryResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "scope.cpu_usage@v1", response.Template)
+	assert.Equal(t, "cores", response.Unit)
+	assert.Equal(t, "deployment", string(response.Scope.Level))
+	assert.Equal(t, "1m0s", response.Step)
+	require.Len(t, response.Series, 1)
+	assert.Equal(t, []MetricsPoint{
+		{Timestamp: time.Date(2026, 3, 10, 11, 0, 0, 0, time.UTC), Value: 0.5},
+		{Timestamp: time.Date(2026, 3, 10, 11, 1, 0, 0, time.UTC), Value: 0.75},
+		{Timestamp: time.Date(2026, 3, 10, 11, 2, 0, 0, time.UTC), Value: 1},
+	}, response.Series[0].Points)
+
+	calls := prom.Calls()
+	require.Len(t, calls, 1)
+	assert.True(t, calls[0].Range)
+	assert.Contains(t, calls[0].Query, `namespace="payments"`)
+	assert.Contains(t, calls[0].Query, `pod=~"api-.*"`)
+	assert.Equal(t, time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC), calls[0].End, "end defaults to now")
+
+	// Without start and step the last hour is charted in 120 points
+	prom.Reset()
+	rec = getMetricsQuery(router, "query=memory_usage")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	calls = prom.Calls()
+	require.Len(t, calls, 1)
+	assert.Equal(t, time.Hour, calls[0].End.Sub(calls[0].Start))
+	assert.Equal(t, 30*time.Second, calls[0].Step)
+	assert.True(t, strings.HasPrefix(calls[0].Query, "sum(container_memory_usage_bytes{container"), "cluster template: %s", calls[0].Query)
+}
+
+func TestMetricsQueryHandler_Invalid(t *testing.T) {
+	prom := testsupport.NewPrometheus()
+	router := newMetricsQueryRouter(t, prom, MetricsQueryOptions{RateLimit: 100, Burst: 100, MaxRange: 24 * time.Hour})
+
+	tests := []struct {
+		name, query, field, message string
+	}{
+		{"missing query", "namespace=payments", "query", "query is required"},
+		{"raw PromQL", "query=up", "query", "query must be one of"},
+		{"unsupported scope", "query=container_restarts", "scope", "does not support scope 'cluster'; supported: namespace"},
+		{"pod without namespace", "query=cpu_usage&pod=api-1", "namespace", "namespace is required"},
+		{"bad start", "query=cpu_usage&start=yesterday", "start", "RFC 3339"},
+		{"range too long", "query=cpu_usage&start=2026-03-08T12:00:00Z", "start", "range must not exceed 24h0m0s"},
+		{"step too fine", "query=cpu_usage&step=1s", "step", "step must be at least 15s"},
+		{"injected deployment", "query=cpu_usage&namespace=x&deployment=" + url.QueryEscape(`a"}) or vector(1) or foo{b="`), "deployment", "is invalid"},
+		{"invalid namespace", "query=cpu_usage&namespace=Payments", "namespace", "is invalid"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := getMetricsQuery(router, tt.query)
+			require.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+			var response ErrorResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			require.NotEmpty(t, response.Errors)
+			assert.Equal(t, tt.field, response.Errors[0].Field)
+			assert.Contains(t, response.Errors[0].Message, tt.message)
+		})
+	}
+	assert.Empty(t, prom.Calls(), "invalid queries do not reach Prometheus")
+}
+
+func TestMetricsQueryHandler_Failures(t *testing.T) {
+	rec := getMetricsQuery(newMetricsQueryRouter(t, nil, MetricsQueryOptions{RateLimit: 100, Burst: 100}), "query=cpu_usage")
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	prom := testsupport.NewPrometheus()
+	prom.Fail("container_cpu_usage_seconds_total", http.StatusInternalServerError)
+	rec = getMetricsQuery(newMetricsQueryRouter(t, prom, MetricsQueryOptions{RateLimit: 100, Burst: 100}), "query=cpu_usage")
+	assert.Equal(t, http.StatusBadGateway, rec.Code)
+
+	// Callers get Burst queries at once
+	router := newMetricsQueryRouter(t, testsupport.NewPrometheus(), MetricsQueryOptions{RateLimit: 0.5, Burst: 2})
+	for i := 0; i < 2; i++ {
+		require.Equal(t, http.StatusOK, getMetricsQuery(router, "query=cpu_usage").Code)
+	}
+	rec = getMetricsQuery(router, "query=cpu_usage")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "2", rec.Header().Get("Retry-After"))
+}
+
+func TestNormalizeSeries(t *testing.T) {
+	at := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	series := normalizeSeries(integrations.RangeMatrix{
+		{Metric: map[string]string{"pod": "web-1"}, Points: []integrations.MetricDataPoint{{Timestamp: at, Value: 1}}},
+		{Metric: map[string]string{"pod": "api-1"}, Points: []integrations.MetricDataPoint{
+			{Timestamp: at, Value: math.NaN()},
+			{Timestamp: at.Add(time.Minute), Value: 2},
+			{Timestamp: at.Add(2 * time.Minute), Value: math.Inf(1)},
+		}},
+		{Points: []integrations.MetricDataPoint{}},
+	})
+
+	require.Len(t, series, 3)
+	assert.Equal(t, "api-1", series[0].Labels["pod"])
+	assert.Equal(t, []MetricsPoint{{Timestamp: at.Add(time.Minute), Value: 2}}, series[0].Points)
+	assert.Equal(t, "web-1", series[1].Labels["pod"])
+	assert.Equal(t, map[string]string{}, series[2].Labels, "labels are never null")
+
+	_, err := json.Marshal(series)
+	assert.NoError(t, err)
+}
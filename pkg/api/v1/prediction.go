@@ -4,6 +4,7 @@ package v1
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
@@ -12,19 +13,29 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/sirupsen/logrus"
 
+	"github.com/tosin2013/openshift-coordination-engine/internal/featureflag"
 	"github.com/tosin2013/openshift-coordination-engine/internal/integrations"
+	"github.com/tosin2013/openshift-coordination-engine/internal/prediction"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/api/validation"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/capacity"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/diagnostics"
 	"github.com/tosin2013/openshift-coordination-engine/pkg/kserve"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
 )
 
 // PredictionHandler handles time-specific resource prediction API requests
 type PredictionHandler struct {
 	kserveClient     *kserve.ProxyClient
 	prometheusClient *integrations.PrometheusClient
+	now              func() time.Time
 	log              *logrus.Logger
 
 	// Default values when Prometheus is not available
 	defaultCPURollingMean    float64
 	defaultMemoryRollingMean float64
+
+	// tracker stores served predictions for accuracy tracking (optional)
+	tracker *prediction.Tracker
 }
 
 // NewPredictionHandler creates a new prediction handler
@@ -37,37 +48,74 @@ func NewPredictionHandler(
 		kserveClient:             kserveClient,
 		prometheusClient:         prometheusClient,
 		log:                      log,
+		now:                      time.Now,
 		defaultCPURollingMean:    0.65, // 65% average CPU usage
 		defaultMemoryRollingMean: 0.72, // 72% average memory usage
 	}
 }
 
+// SetClock replaces the clock of result timestamps and evaluation times, e.g. with
+// the fixed clock of deterministic mode
+func (h *PredictionHandler) SetClock(now func() time.Time) {
+	h.now = now
+}
+
+// SetAccuracyTracker enables storing served predictions and the accuracy endpoint
+func (h *PredictionHandler) SetAccuracyTracker(tracker *prediction.Tracker) {
+	h.tracker = tracker
+}
+
 // RegisterRoutes registers prediction API routes
 func (h *PredictionHandler) RegisterRoutes(router *mux.Router) {
 	router.HandleFunc("/api/v1/predict", h.HandlePredict).Methods("POST")
-	h.log.Info("Prediction API endpoint registered: POST /api/v1/predict")
+	router.HandleFunc("/api/v1/predict/backtest", h.HandleBacktest).Methods("POST")
+	router.HandleFunc("/api/v1/predict/accuracy", h.HandleAccuracy).Methods("GET")
+	h.log.Info("Prediction API endpoints registered: POST /api/v1/predict, POST /api/v1/predict/backtest, GET /api/v1/predict/accuracy")
 }
 
 // PredictRequest represents the request body for time-specific predictions
 type PredictRequest struct {
-	Hour       int    `json:"hour"`        // Required: 0-23 (hour of day)
-	DayOfWeek  int    `json:"day_of_week"` // Required: 0=Monday, 6=Sunday
-	Namespace  string `json:"namespace"`   // Optional: namespace filter
-	Deployment string `json:"deployment"`  // Optional: deployment filter
-	Pod        string `json:"pod"`         // Optional: specific pod filter
-	Scope      string `json:"scope"`       // Optional: pod, deployment, namespace, cluster (default: namespace)
-	Model      string `json:"model"`       // Optional: KServe model name (default: predictive-analytics)
+	Hour      int    `json:"hour"`         // Required: 0-23 (hour of day)
+	DayOfWeek int    `json:"day_of_week"`  // Required: 0=Monday, 6=Sunday
+	Model     string `json:"model"`        // Optional: KServe model name (default: predictive-analytics)
+	Debug     bool   `json:"debug"`        // Optional: include per-stage timings and executed PromQL
+	At        string `json:"at,omitempty"` // Optional: RFC3339 evaluation time for retroactive predictions (default: now)
+
+	// Optional: namespace, deployment or pod filter; scope is inferred from them when omitted
+	models.Scope
+
+	// Optional: predict a series of target times instead of hour/day_of_week.
+	// TargetTimes takes RFC3339 timestamps; Horizon is a shorthand for evenly spaced times.
+	TargetTimes []string           `json:"target_times,omitempty"`
+	Horizon     *PredictionHorizon `json:"horizon,omitempty"`
 }
 
+// PredictionHorizon describes evenly spaced target times, e.g. every 1h for the next 24h.
+// The first target time is the next multiple of Every after now. Resolution is an
+// alternative to Every: 1h, 6h, 1d, or auto for the finest of them that yields at most
+// MaxPoints target times.
+type PredictionHorizon struct {
+	Every      string `json:"every,omitempty"`      // Go duration, at least 1h
+	For        string `json:"for"`                  // Go duration covered by the series
+	Resolution string `json:"resolution,omitempty"` // auto, 1h, 6h, 1d
+	MaxPoints  int    `json:"max_points,omitempty"` // Target times for auto (default: 168)
+}
+
+// MaxPredictionPoints caps the number of target times in a series request (one week hourly)
+const MaxPredictionPoints = 168
+
 // PredictResponse represents the response for time-specific predictions
 type PredictResponse struct {
-	Status         string           `json:"status"`
-	Scope          string           `json:"scope"`
-	Target         string           `json:"target"`
-	Predictions    PredictionValues `json:"predictions"`
-	CurrentMetrics CurrentMetrics   `json:"current_metrics"`
-	ModelInfo      ModelInfo        `json:"model_info"`
-	TargetTime     TargetTimeInfo   `json:"target_time"`
+	Status         string              `json:"status"`
+	Scope          string              `json:"scope"`
+	Target         string              `json:"target"`
+	Predictions    PredictionValues    `json:"predictions"`
+	CurrentMetrics CurrentMetrics      `json:"current_metrics"`
+	ModelInfo      ModelInfo           `json:"model_info"`
+	TargetTime     TargetTimeInfo      `json:"target_time"`
+	Series         []PredictionPoint   `json:"series,omitempty"`
+	DataQuality    DataQuality         `json:"data_quality"`
+	Debug          *diagnostics.Report `json:"debug,omitempty"`
 }
 
 // PredictionValues contains the predicted resource usage percentages
@@ -98,12 +146,20 @@ type TargetTimeInfo struct {
 	ISOTimestamp string `json:"iso_timestamp"`
 }
 
+// PredictionPoint is one prediction in a series response
+type PredictionPoint struct {
+	TargetTime  TargetTimeInfo   `json:"target_time"`
+	Predictions PredictionValues `json:"predictions"`
+	Confidence  float64          `json:"confidence"`
+}
+
 // PredictErrorResponse represents an error response for predictions
 type PredictErrorResponse struct {
-	Status  string `json:"status"`
-	Error   string `json:"error"`
-	Details string `json:"details,omitempty"`
-	Code    string `json:"code"`
+	Status  string                  `json:"status"`
+	Error   string                  `json:"error"`
+	Details string                  `json:"details,omitempty"`
+	Code    string                  `json:"code"`
+	Errors  []validation.FieldError `json:"errors,omitempty"`
 }
 
 // Error codes for prediction failures
@@ -113,11 +169,13 @@ const (
 	ErrCodeKServeUnavailable     = "KSERVE_UNAVAILABLE"
 	ErrCodeModelNotFound         = "MODEL_NOT_FOUND"
 	ErrCodePredictionFailed      = "PREDICTION_FAILED"
+	ErrCodeQueryTooExpensive     = "QUERY_TOO_EXPENSIVE"
 )
 
 // HandlePredict handles POST /api/v1/predict
 // @Summary Get time-specific resource usage predictions
-// @Description Provides time-specific resource usage predictions using KServe ML models and Prometheus metrics
+// @Description Provides time-specific resource usage predictions using KServe ML models and Prometheus metrics.
+// @Description Pass target_times or horizon to predict a series of target times in one call.
 // @Tags prediction
 // @Accept json
 // @Produce json
@@ -140,19 +198,51 @@ func (h *PredictionHandler) HandlePredict(w http.ResponseWriter, r *http.Request
 	var req PredictRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		h.log.WithError(err).Debug("Invalid predict request format")
-		h.respondError(w, http.StatusBadRequest, "Invalid request format", err.Error(), ErrCodeInvalidRequest)
+		h.respondError(w, http.StatusBadRequest, "Invalid request format", err.Error(), ErrCodeInvalidRequest, validation.DecodeFields(err)...)
+		return
+	}
+
+	response, err := h.Predict(ctx, &req)
+	if err != nil {
+		var requestErr *RequestError
+		if errors.As(err, &requestErr) {
+			h.respondError(w, requestErr.StatusCode, requestErr.Message, requestErr.Details, requestErr.Code, requestErr.Errors...)
+			return
+		}
+		h.respondError(w, http.StatusInternalServerError, "Prediction failed", err.Error(), ErrCodePredictionFailed)
 		return
 	}
 
+	h.respondJSON(w, http.StatusOK, response)
+}
+
+// Predict runs a time-specific prediction for a request: validation and defaults,
+// Prometheus metrics collection, model inference and post-processing. It is shared
+// by the REST handler and the gRPC server.
+func (h *PredictionHandler) Predict(ctx context.Context, req *PredictRequest) (*PredictResponse, error) {
 	// Validate request
-	if err := h.validateRequest(&req); err != nil {
+	if err := h.validateRequest(req); err != nil {
 		h.log.WithError(err).Debug("Predict request validation failed")
-		h.respondError(w, http.StatusBadRequest, err.Error(), "", ErrCodeInvalidRequest)
-		return
+		return nil, invalidRequest(err, ErrCodeInvalidRequest)
+	}
+
+	now, err := parseEvaluationTime(req.At, h.now())
+	if err != nil {
+		h.log.WithError(err).Debug("Predict request evaluation time invalid")
+		return nil, invalidRequest(err, ErrCodeInvalidRequest)
+	}
+
+	targets, err := h.targetTimes(req, now)
+	if err != nil {
+		h.log.WithError(err).Debug("Predict request target times invalid")
+		return nil, invalidRequest(err, ErrCodeInvalidRequest)
 	}
 
 	// Set defaults
-	h.setRequestDefaults(&req)
+	h.setRequestDefaults(req)
+
+	// Model canary routing is gated per namespace
+	ctx = featureflag.WithNamespace(ctx, req.Namespace)
 
 	h.log.WithFields(logrus.Fields{
 		"hour":        req.Hour,
@@ -160,35 +250,174 @@ func (h *PredictionHandler) HandlePredict(w http.ResponseWriter, r *http.Request
 		"namespace":   req.Namespace,
 		"deployment":  req.Deployment,
 		"pod":         req.Pod,
-		"scope":       req.Scope,
+		"scope":       req.Level,
 		"model":       req.Model,
+		"points":      len(targets),
+		"at":          req.At,
 	}).Info("Processing prediction request")
 
 	// Check if KServe is available
 	if h.kserveClient == nil {
-		h.respondError(w, http.StatusServiceUnavailable, "KServe integration not enabled", "KServe client is not configured", ErrCodeKServeUnavailable)
-		return
+		return nil, &RequestError{
+			StatusCode: http.StatusServiceUnavailable,
+			Message:    "KServe integration not enabled",
+			Details:    "KServe client is not configured",
+			Code:       ErrCodeKServeUnavailable,
+		}
 	}
 
 	// Check if model exists
 	if _, exists := h.kserveClient.GetModel(req.Model); !exists {
-		h.respondError(w, http.StatusServiceUnavailable, fmt.Sprintf("Model '%s' not available", req.Model), "Model not found in KServe", ErrCodeModelNotFound)
-		return
+		return nil, &RequestError{
+			StatusCode: http.StatusServiceUnavailable,
+			Message:    fmt.Sprintf("Model '%s' not available", req.Model),
+			Details:    "Model not found in KServe",
+			Code:       ErrCodeModelNotFound,
+		}
+	}
+
+	// Collect per-stage timings when debugging was requested
+	var trace *diagnostics.Trace
+	if req.Debug {
+		trace = diagnostics.NewTrace()
+		ctx = diagnostics.WithTrace(ctx, trace)
 	}
 
+	// Evaluate every metric at one instant so the features are consistent
+	snapshot := integrations.NewSnapshot(now)
+	ctx = integrations.WithSnapshot(ctx, snapshot)
+
 	// Get current metrics from Prometheus
-	cpuRollingMean, memoryRollingMean, prometheusErr := h.getScopedMetrics(ctx, &req)
+	endStage := trace.StartStage("prometheus_metrics")
+	var quality DataQuality
+	cpuRollingMean, memoryRollingMean, prometheusErr := h.getScopedMetrics(ctx, req)
+	endStage()
+	if costErr, ok := integrations.IsQueryCostError(prometheusErr); ok {
+		return nil, &RequestError{
+			StatusCode: http.StatusUnprocessableEntity,
+			Message:    "Query scope too broad",
+			Details:    costErr.Error(),
+			Code:       ErrCodeQueryTooExpensive,
+		}
+	}
 	if prometheusErr != nil {
 		h.log.WithError(prometheusErr).Warn("Failed to get Prometheus metrics, using defaults")
 		cpuRollingMean = h.defaultCPURollingMean
 		memoryRollingMean = h.defaultMemoryRollingMean
+		quality = defaultedQuality(prometheusErr, "cpu_rolling_mean", "memory_rolling_mean")
+	}
+
+	// Predict every target time against the same rolling means
+	points := make([]PredictionPoint, 0, len(targets))
+	var modelVersion string
+	for _, target := range targets {
+		endStage = trace.StartStage("model_inference")
+		cpuPercent, memoryPercent, confidence, version, err := h.predictPoint(ctx, req.Model, target, cpuRollingMean, memoryRollingMean)
+		endStage()
+		if err != nil {
+			return nil, err
+		}
+		modelVersion = version
+		points = append(points, PredictionPoint{
+			TargetTime:  target,
+			Predictions: PredictionValues{CPUPercent: cpuPercent, MemoryPercent: memoryPercent},
+			Confidence:  confidence,
+		})
+	}
+
+	// Build response
+	endStage = trace.StartStage("post_processing")
+	first := points[0]
+	response := PredictResponse{
+		Status:      "success",
+		Scope:       string(req.Level),
+		Target:      h.getTarget(req),
+		Predictions: first.Predictions,
+		CurrentMetrics: CurrentMetrics{
+			CPURollingMean:    cpuRollingMean * 100, // Convert to percentage
+			MemoryRollingMean: memoryRollingMean * 100,
+			Timestamp:         snapshot.At().UTC().Format(time.RFC3339),
+			TimeRange:         "24h",
+		},
+		ModelInfo: ModelInfo{
+			Name:       req.Model,
+			Version:    modelVersion,
+			Confidence: first.Confidence,
+		},
+		TargetTime:  first.TargetTime,
+		DataQuality: quality,
+	}
+	if req.isSeries() {
+		response.Series = points
+	}
+	endStage()
+	response.Debug = trace.Report()
+
+	// Retroactive predictions are backtests, not forecasts to score against actuals
+	if req.At == "" {
+		h.recordPredictions(req, &response, points)
 	}
 
-	// Build prediction instances
-	// Features: [hour_of_day, day_of_week, cpu_rolling_mean, memory_rolling_mean]
+	h.log.WithFields(logrus.Fields{
+		"scope":          response.Scope,
+		"target":         response.Target,
+		"points":         len(points),
+		"cpu_percent":    first.Predictions.CPUPercent,
+		"memory_percent": first.Predictions.MemoryPercent,
+		"confidence":     first.Confidence,
+	}).Info("Prediction completed successfully")
+
+	return &response, nil
+}
+
+// recordPredictions stores the served predictions for accuracy tracking
+func (h *PredictionHandler) recordPredictions(req *PredictRequest, response *PredictResponse, points []PredictionPoint) {
+	if h.tracker == nil {
+		return
+	}
+
+	records := make([]*models.PredictionRecord, 0, len(points))
+	for _, point := range points {
+		targetTime, err := time.Parse(time.RFC3339, point.TargetTime.ISOTimestamp)
+		if err != nil {
+			continue
+		}
+		records = append(records, &models.PredictionRecord{
+			Model:           req.Model,
+			ModelVersion:    response.ModelInfo.Version,
+			Scope:           response.Scope,
+			Target:          response.Target,
+			Namespace:       req.Namespace,
+			Deployment:      req.Deployment,
+			Pod:             req.Pod,
+			TargetTime:      targetTime,
+			PredictedCPU:    point.Predictions.CPUPercent,
+			PredictedMemory: point.Predictions.MemoryPercent,
+			Confidence:      point.Confidence,
+		})
+	}
+	h.tracker.Record(records)
+}
+
+// predictionFeatureNames are the predictive-analytics input features in model order
+var predictionFeatureNames = []string{"hour_of_day", "day_of_week", "cpu_rolling_mean", "memory_rolling_mean"}
+
+// PredictionFeatureNames returns the predictive-analytics input features in model order
+func PredictionFeatureNames() []string {
+	return append([]string(nil), predictionFeatureNames...)
+}
+
+// predictPoint runs model inference for a single target time
+func (h *PredictionHandler) predictPoint(
+	ctx context.Context,
+	model string,
+	target TargetTimeInfo,
+	cpuRollingMean, memoryRollingMean float64,
+) (cpuPercent, memoryPercent, confidence float64, modelVersion string, err error) {
+	// Features in predictionFeatureNames order
 	instances := [][]float64{{
-		float64(req.Hour),
-		float64(req.DayOfWeek),
+		float64(target.Hour),
+		float64(target.DayOfWeek),
 		cpuRollingMean,
 		memoryRollingMean,
 	}}
@@ -200,177 +429,179 @@ func (h *PredictionHandler) HandlePredict(w http.ResponseWriter, r *http.Request
 	}).Debug("Prepared prediction instances")
 
 	// Call KServe model with flexible response handling
-	resp, err := h.kserveClient.PredictFlexible(ctx, req.Model, instances)
-	if err != nil {
-		h.log.WithError(err).WithField("model", req.Model).Error("KServe prediction failed")
-		h.respondError(w, http.StatusServiceUnavailable, "Prediction failed", err.Error(), ErrCodePredictionFailed)
-		return
+	resp, predictErr := h.kserveClient.PredictFlexible(ctx, model, instances)
+	if predictErr != nil {
+		h.log.WithError(predictErr).WithField("model", model).Error("KServe prediction failed")
+		if reqErr := invalidModelResponse(predictErr, "Prediction failed"); reqErr != nil {
+			return 0, 0, 0, "", reqErr
+		}
+		return 0, 0, 0, "", predictionFailed(predictErr.Error())
 	}
 
 	// Process predictions based on response type
-	var cpuPercent, memoryPercent, confidence float64
-	var modelVersion string
-
 	switch resp.Type {
 	case "forecast":
 		if resp.ForecastResponse == nil {
-			h.respondError(w, http.StatusServiceUnavailable, "Prediction failed", "Empty forecast response from model", ErrCodePredictionFailed)
-			return
+			return 0, 0, 0, "", predictionFailed("Empty forecast response from model")
 		}
 		cpuPercent, memoryPercent, confidence = h.processForecastPredictions(resp.ForecastResponse, cpuRollingMean, memoryRollingMean)
-		modelVersion = resp.ForecastResponse.ModelVersion
+		return cpuPercent, memoryPercent, confidence, resp.ForecastResponse.ModelVersion, nil
 	case "anomaly":
 		if resp.AnomalyResponse == nil {
-			h.respondError(w, http.StatusServiceUnavailable, "Prediction failed", "Empty anomaly response from model", ErrCodePredictionFailed)
-			return
+			return 0, 0, 0, "", predictionFailed("Empty anomaly response from model")
 		}
 		cpuPercent, memoryPercent, confidence = h.processAnomalyPredictions(resp.AnomalyResponse, cpuRollingMean, memoryRollingMean)
-		modelVersion = resp.AnomalyResponse.ModelVersion
+		return cpuPercent, memoryPercent, confidence, resp.AnomalyResponse.ModelVersion, nil
 	default:
-		h.respondError(w, http.StatusServiceUnavailable, "Prediction failed", "Unknown response format from model", ErrCodePredictionFailed)
-		return
+		return 0, 0, 0, "", predictionFailed("Unknown response format from model")
 	}
+}
 
-	// Calculate target ISO timestamp
-	targetTimestamp := h.calculateTargetTimestamp(req.Hour, req.DayOfWeek)
-
-	// Build response
-	response := PredictResponse{
-		Status: "success",
-		Scope:  req.Scope,
-		Target: h.getTarget(&req),
-		Predictions: PredictionValues{
-			CPUPercent:    cpuPercent,
-			MemoryPercent: memoryPercent,
-		},
-		CurrentMetrics: CurrentMetrics{
-			CPURollingMean:    cpuRollingMean * 100, // Convert to percentage
-			MemoryRollingMean: memoryRollingMean * 100,
-			Timestamp:         time.Now().UTC().Format(time.RFC3339),
-			TimeRange:         "24h",
-		},
-		ModelInfo: ModelInfo{
-			Name:       req.Model,
-			Version:    modelVersion,
-			Confidence: confidence,
-		},
-		TargetTime: TargetTimeInfo{
-			Hour:         req.Hour,
-			DayOfWeek:    req.DayOfWeek,
-			ISOTimestamp: targetTimestamp,
-		},
+// steps resolves the horizon's step and span
+func (hz *PredictionHorizon) steps() (every, span time.Duration, err error) {
+	if hz.Resolution == "" {
+		every, err = time.ParseDuration(hz.Every)
+		if err != nil || every < time.Hour {
+			return 0, 0, validation.New("horizon.every", validation.ConstraintRange, hz.Every,
+				"horizon.every must be a duration of at least 1h")
+		}
+		span, err = time.ParseDuration(hz.For)
+		if err != nil || span < every {
+			return 0, 0, validation.New("horizon.for", validation.ConstraintRange, hz.For,
+				"horizon.for must be a duration of at least horizon.every")
+		}
+		return every, span, nil
 	}
 
-	h.log.WithFields(logrus.Fields{
-		"scope":          response.Scope,
-		"target":         response.Target,
-		"cpu_percent":    cpuPercent,
-		"memory_percent": memoryPercent,
-		"confidence":     confidence,
-	}).Info("Prediction completed successfully")
-
-	h.respondJSON(w, http.StatusOK, response)
-}
-
-// validateRequest validates the prediction request parameters
-func (h *PredictionHandler) validateRequest(req *PredictRequest) error {
-	if err := h.validateTimeFields(req); err != nil {
-		return err
+	if hz.Every != "" {
+		return 0, 0, validation.New("horizon.resolution", validation.ConstraintExclusive, hz.Resolution,
+			"horizon.every and horizon.resolution are mutually exclusive")
 	}
-	if err := h.validateScope(req); err != nil {
-		return err
+	maxPoints := hz.MaxPoints
+	validation.Default(&maxPoints, MaxPredictionPoints)
+	if err := validation.Check(
+		validation.OneOf("horizon.resolution", hz.Resolution, capacity.Resolutions...),
+		validation.Between("horizon.max_points", maxPoints, 1, MaxPredictionPoints),
+	); err != nil {
+		return 0, 0, err
 	}
-	return h.validateScopeRequirements(req)
-}
-
-// validateTimeFields validates hour and day_of_week fields
-func (h *PredictionHandler) validateTimeFields(req *PredictRequest) error {
-	if req.Hour < 0 || req.Hour > 23 {
-		return fmt.Errorf("hour must be between 0-23")
+	span, err = time.ParseDuration(hz.For)
+	if err != nil || span < time.Hour {
+		return 0, 0, validation.New("horizon.for", validation.ConstraintRange, hz.For,
+			"horizon.for must be a duration of at least 1h")
 	}
-	if req.DayOfWeek < 0 || req.DayOfWeek > 6 {
-		return fmt.Errorf("day_of_week must be between 0-6 (0=Monday, 6=Sunday)")
+	_, every, _ = capacity.ResolutionStep(hz.Resolution, span, maxPoints)
+	if span < every {
+		return 0, 0, validation.New("horizon.for", validation.ConstraintRange, hz.For,
+			"horizon.for must be a duration of at least horizon.resolution")
 	}
-	return nil
+	return every, span, nil
 }
 
-// validateScope validates the scope field if provided
-func (h *PredictionHandler) validateScope(req *PredictRequest) error {
-	if req.Scope == "" {
-		return nil
-	}
-	validScopes := map[string]bool{
-		"pod":        true,
-		"deployment": true,
-		"namespace":  true,
-		"cluster":    true,
+// isSeries reports whether the request asks for a series of predictions
+func (r *PredictRequest) isSeries() bool {
+	return len(r.TargetTimes) > 0 || r.Horizon != nil
+}
+
+// targetTimes resolves the request's target times. Single-point requests use hour and
+// day_of_week; series requests use target_times or the horizon shorthand.
+func (h *PredictionHandler) targetTimes(req *PredictRequest, now time.Time) ([]TargetTimeInfo, error) {
+	if !req.isSeries() {
+		return []TargetTimeInfo{{
+			Hour:         req.Hour,
+			DayOfWeek:    req.DayOfWeek,
+			ISOTimestamp: h.calculateTargetTimestamp(req.Hour, req.DayOfWeek, now),
+		}}, nil
 	}
-	if !validScopes[req.Scope] {
-		return fmt.Errorf("scope must be one of: pod, deployment, namespace, cluster")
+	if len(req.TargetTimes) > 0 && req.Horizon != nil {
+		return nil, validation.New("horizon", validation.ConstraintExclusive, nil, "target_times and horizon are mutually exclusive")
 	}
-	return nil
-}
 
-// validateScopeRequirements validates scope-specific field requirements
-func (h *PredictionHandler) validateScopeRequirements(req *PredictRequest) error {
-	switch req.Scope {
-	case "pod":
-		if req.Pod == "" {
-			return fmt.Errorf("pod name is required when scope is 'pod'")
+	var times []time.Time
+	if req.Horizon != nil {
+		every, span, err := req.Horizon.steps()
+		if err != nil {
+			return nil, err
+		}
+		if span/every > MaxPredictionPoints {
+			return nil, validation.New("horizon.for", validation.ConstraintRange, req.Horizon.For,
+				fmt.Sprintf("horizon yields more than %d target times", MaxPredictionPoints))
 		}
-		if req.Namespace == "" {
-			return fmt.Errorf("namespace is required when scope is 'pod'")
+		start := now.UTC().Truncate(every)
+		for offset := every; offset <= span; offset += every {
+			times = append(times, start.Add(offset))
 		}
-	case "deployment":
-		if req.Deployment == "" {
-			return fmt.Errorf("deployment name is required when scope is 'deployment'")
+	} else {
+		if len(req.TargetTimes) > MaxPredictionPoints {
+			return nil, validation.New("target_times", validation.ConstraintRange, len(req.TargetTimes),
+				fmt.Sprintf("at most %d target_times are allowed", MaxPredictionPoints))
 		}
-		if req.Namespace == "" {
-			return fmt.Errorf("namespace is required when scope is 'deployment'")
+		var errs validation.Errors
+		for i, value := range req.TargetTimes {
+			parsed, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				errs.Add(fmt.Sprintf("target_times[%d]", i), validation.ConstraintFormat, value,
+					fmt.Sprintf("target_times must be RFC3339 timestamps: %q", value))
+				continue
+			}
+			times = append(times, parsed.UTC())
+		}
+		if err := errs.Err(); err != nil {
+			return nil, err
 		}
 	}
-	return nil
-}
 
-// setRequestDefaults sets default values for optional request fields
-func (h *PredictionHandler) setRequestDefaults(req *PredictRequest) {
-	if req.Scope == "" {
-		req.Scope = h.inferScope(req)
+	targets := make([]TargetTimeInfo, 0, len(times))
+	for _, t := range times {
+		targets = append(targets, TargetTimeInfo{
+			Hour:         t.Hour(),
+			DayOfWeek:    (int(t.Weekday()) + 6) % 7, // Go uses Sunday=0, our API Monday=0
+			ISOTimestamp: t.Truncate(time.Hour).Format(time.RFC3339),
+		})
 	}
+	return targets, nil
+}
 
-	if req.Model == "" {
-		req.Model = "predictive-analytics"
+// predictionFailed builds the 503 error returned when the model call or its response is unusable
+func predictionFailed(details string) *RequestError {
+	return &RequestError{
+		StatusCode: http.StatusServiceUnavailable,
+		Message:    "Prediction failed",
+		Details:    details,
+		Code:       ErrCodePredictionFailed,
 	}
 }
 
-// inferScope determines the scope based on provided fields
-func (h *PredictionHandler) inferScope(req *PredictRequest) string {
-	switch {
-	case req.Pod != "":
-		return "pod"
-	case req.Deployment != "":
-		return "deployment"
-	case req.Namespace != "":
-		return "namespace"
-	default:
-		return "cluster"
+// validateRequest validates the prediction request parameters
+func (h *PredictionHandler) validateRequest(req *PredictRequest) error {
+	rules := []validation.Rule{
+		validation.Between("hour", req.Hour, 0, 23),
+		validation.Between("day_of_week", req.DayOfWeek, 0, 6),
 	}
+	rules = append(rules, req.Scope.Rules()...)
+	return validation.Check(rules...)
+}
+
+// setRequestDefaults sets default values for optional request fields
+func (h *PredictionHandler) setRequestDefaults(req *PredictRequest) {
+	req.Scope = req.Scope.Resolve()
+	validation.Default(&req.Model, "predictive-analytics")
 }
 
 // getScopedMetrics retrieves CPU and memory rolling means based on the request scope
 func (h *PredictionHandler) getScopedMetrics(ctx context.Context, req *PredictRequest) (float64, float64, error) {
 	if h.prometheusClient == nil || !h.prometheusClient.IsAvailable() {
-		return h.defaultCPURollingMean, h.defaultMemoryRollingMean, fmt.Errorf("prometheus client not available")
+		return h.defaultCPURollingMean, h.defaultMemoryRollingMean, errPrometheusUnavailable
 	}
 
-	switch req.Scope {
-	case "cluster":
+	switch req.Level {
+	case models.ScopeCluster:
 		return h.getScopedMetricsForCluster(ctx)
-	case "namespace":
+	case models.ScopeNamespace:
 		return h.getScopedMetricsForNamespace(ctx, req.Namespace)
-	case "deployment":
+	case models.ScopeDeployment:
 		return h.getScopedMetricsForDeployment(ctx, req.Namespace, req.Deployment)
-	case "pod":
+	case models.ScopePod:
 		return h.getScopedMetricsForPod(ctx, req.Namespace, req.Pod)
 	default:
 		return h.getScopedMetricsForCluster(ctx)
@@ -397,6 +628,10 @@ func (h *PredictionHandler) getScopedMetricsForPod(ctx context.Context, namespac
 
 // getMetricsWithScope is a helper that queries Prometheus with the given scope parameters
 func (h *PredictionHandler) getMetricsWithScope(ctx context.Context, namespace, deployment, pod, scopeName string) (float64, float64, error) {
+	// Scoped usage comes from cAdvisor container metrics, with no fallback
+	if missing := h.prometheusClient.MissingExporters(integrations.ExporterCAdvisor); len(missing) > 0 {
+		return 0, 0, &MissingExportersError{Exporters: missing}
+	}
 	cpuValue, err := h.prometheusClient.GetScopedCPURollingMean(ctx, namespace, deployment, pod)
 	if err != nil {
 		return 0, 0, fmt.Errorf("failed to get %s CPU metrics: %w", scopeName, err)
@@ -508,17 +743,17 @@ func (h *PredictionHandler) processPredictions(resp *kserve.DetectResponse, cpuR
 
 // getTarget returns the target identifier based on the request scope
 func (h *PredictionHandler) getTarget(req *PredictRequest) string {
-	switch req.Scope {
-	case "pod":
+	switch req.Level {
+	case models.ScopePod:
 		return fmt.Sprintf("%s/%s", req.Namespace, req.Pod)
-	case "deployment":
+	case models.ScopeDeployment:
 		return fmt.Sprintf("%s/%s", req.Namespace, req.Deployment)
-	case "namespace":
+	case models.ScopeNamespace:
 		if req.Namespace != "" {
 			return req.Namespace
 		}
 		return "all-namespaces"
-	case "cluster":
+	case models.ScopeCluster:
 		return "cluster"
 	default:
 		if req.Namespace != "" {
@@ -528,9 +763,10 @@ func (h *PredictionHandler) getTarget(req *PredictRequest) string {
 	}
 }
 
-// calculateTargetTimestamp calculates the ISO timestamp for the prediction target time
-func (h *PredictionHandler) calculateTargetTimestamp(hour, dayOfWeek int) string {
-	now := time.Now().UTC()
+// calculateTargetTimestamp calculates the ISO timestamp of the next occurrence of hour
+// and day of week after now
+func (h *PredictionHandler) calculateTargetTimestamp(hour, dayOfWeek int, now time.Time) string {
+	now = now.UTC()
 
 	// Calculate days until target day of week
 	// Go uses Sunday=0, Monday=1, etc.
@@ -584,12 +820,13 @@ func (h *PredictionHandler) respondJSON(w http.ResponseWriter, statusCode int, d
 }
 
 // respondError writes an error response
-func (h *PredictionHandler) respondError(w http.ResponseWriter, statusCode int, message, details, code string) {
+func (h *PredictionHandler) respondError(w http.ResponseWriter, statusCode int, message, details, code string, fieldErrs ...validation.FieldError) {
 	response := PredictErrorResponse{
 		Status:  "error",
 		Error:   message,
 		Details: details,
 		Code:    code,
+		Errors:  fieldErrs,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -0,0 +1,65 @@
+package v1
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/prediction"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/api/validation"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// maxAccuracyWindow bounds the accuracy window to the retained prediction history
+const maxAccuracyWindow = 30 * 24 * time.Hour
+
+// AccuracyResponse represents the response for GET /api/v1/predict/accuracy
+type AccuracyResponse struct {
+	Status string `json:"status"`
+	*prediction.AccuracySummary
+}
+
+// HandleAccuracy handles GET /api/v1/predict/accuracy
+// @Summary Get rolling prediction accuracy
+// @ID predictionAccuracy
+// @Description Summarizes the realized error of served predictions whose target time has passed, per model and target, with drift against the preceding week
+// @Tags prediction
+// @Produce json
+// @Param model query string false "Model name"
+// @Param scope query string false "Scope (pod, deployment, namespace, cluster)"
+// @Param target query string false "Target, e.g. namespace/deployment"
+// @Param window query string false "Rolling window of target times (default 24h, max 720h)"
+// @Success 200 {object} AccuracyResponse
+// @Failure 400 {object} PredictErrorResponse
+// @Failure 503 {object} PredictErrorResponse
+// @Router /api/v1/predict/accuracy [get]
+func (h *PredictionHandler) HandleAccuracy(w http.ResponseWriter, r *http.Request) {
+	if h.tracker == nil {
+		h.respondError(w, http.StatusServiceUnavailable, "Prediction accuracy tracking not enabled", "", ErrCodePredictionFailed)
+		return
+	}
+
+	query := r.URL.Query()
+	filter := prediction.AccuracyFilter{
+		Model:  query.Get("model"),
+		Scope:  query.Get("scope"),
+		Target: query.Get("target"),
+		Window: prediction.DefaultAccuracyWindow,
+	}
+	if raw := query.Get("window"); raw != "" {
+		window, err := time.ParseDuration(raw)
+		if err != nil || window < time.Hour || window > maxAccuracyWindow {
+			h.respondError(w, http.StatusBadRequest, "window must be a duration between 1h and 720h", raw, ErrCodeInvalidRequest,
+				validation.FieldError{Field: "window", Constraint: validation.ConstraintRange, Value: raw, Message: "window must be a duration between 1h and 720h"})
+			return
+		}
+		filter.Window = window
+	}
+	if filter.Scope != "" {
+		if err := validation.Check(validation.OneOf("scope", models.ScopeLevel(filter.Scope), models.ScopeLevels...)); err != nil {
+			h.respondError(w, http.StatusBadRequest, err.Error(), "", ErrCodeInvalidRequest, validation.Fields(err)...)
+			return
+		}
+	}
+
+	h.respondJSON(w, http.StatusOK, AccuracyResponse{Status: "success", AccuracySummary: h.tracker.Accuracy(filter)})
+}
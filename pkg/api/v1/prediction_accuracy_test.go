@@ -0,0 +1,75 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/prediction"
+	"github.com/tosin2013/openshift-coordination-engine/internal/storage"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+func TestPredictionHandler_HandleAccuracy(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	handler := NewPredictionHandler(nil, nil, log)
+	router := mux.NewRouter()
+	handler.RegisterRoutes(router)
+
+	t.Run("tracking disabled", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/v1/predict/accuracy", http.NoBody)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	})
+
+	store := storage.NewPredictionStore(t.TempDir(), 0)
+	handler.SetAccuracyTracker(prediction.NewTracker(store, nil, log))
+
+	// Served predictions are recorded for every point in the response
+	handler.recordPredictions(
+		&PredictRequest{Scope: models.Scope{Namespace: "payments"}, Model: "predictive-analytics"},
+		&PredictResponse{Scope: "namespace", Target: "payments", ModelInfo: ModelInfo{Version: "v2"}},
+		[]PredictionPoint{
+			{TargetTime: TargetTimeInfo{ISOTimestamp: time.Now().Add(-2 * time.Hour).UTC().Format(time.RFC3339)}, Predictions: PredictionValues{CPUPercent: 40}},
+			{TargetTime: TargetTimeInfo{ISOTimestamp: time.Now().Add(time.Hour).UTC().Format(time.RFC3339)}, Predictions: PredictionValues{CPUPercent: 45}},
+		},
+	)
+	require.Equal(t, 2, store.Count())
+	stored := store.List(storage.PredictionFilter{})
+	assert.Equal(t, "v2", stored[0].ModelVersion)
+	assert.Equal(t, "payments", stored[0].Namespace)
+
+	t.Run("summarizes pending predictions", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/v1/predict/accuracy?target=payments&window=48h", http.NoBody)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var resp AccuracyResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+		assert.Equal(t, "success", resp.Status)
+		assert.Equal(t, "48h0m0s", resp.Window)
+		assert.Equal(t, 1, resp.Pending)
+		require.Len(t, resp.Groups, 1)
+		assert.Equal(t, "payments", resp.Groups[0].Target)
+	})
+
+	for _, query := range []string{"window=10m", "window=1y", "scope=node"} {
+		t.Run("rejects "+query, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/v1/predict/accuracy?"+query, http.NoBody)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
@@ -0,0 +1,357 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/integrations"
+	"github.com/tosin2013/openshift-coordination-engine/internal/prediction"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/api/validation"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// Backtest limits. Every sample is one model call, so a request makes at most
+// MaxBacktestScopes * MaxBacktestSamples predictions.
+const (
+	MaxBacktestScopes  = 5
+	MaxBacktestSamples = 168
+
+	defaultBacktestWindow = 7 * 24 * time.Hour
+)
+
+// BacktestRequest represents the request body for POST /api/v1/predict/backtest
+type BacktestRequest struct {
+	Scopes  []BacktestScope `json:"scopes"`  // Optional: scopes to evaluate (default: cluster)
+	Start   string          `json:"start"`   // Optional: RFC3339, first replayed timestamp (default: end - 7d)
+	End     string          `json:"end"`     // Optional: RFC3339, last replayed timestamp (default: now - horizon)
+	Horizon string          `json:"horizon"` // Optional: how far ahead each replayed prediction looks (default: 1h)
+	Step    string          `json:"step"`    // Optional: spacing between replayed timestamps (default: 1h)
+	Model   string          `json:"model"`   // Optional: KServe model name (default: predictive-analytics)
+}
+
+// BacktestScope selects the workload a backtest evaluates, with the same fields as PredictRequest
+type BacktestScope = models.Scope
+
+// BacktestResponse reports prediction accuracy per scope
+type BacktestResponse struct {
+	Status  string           `json:"status"`
+	Model   string           `json:"model"`
+	Start   string           `json:"start"`
+	End     string           `json:"end"`
+	Horizon string           `json:"horizon"`
+	Step    string           `json:"step"`
+	Results []BacktestResult `json:"results"`
+}
+
+// BacktestResult holds the prediction error for one scope. Error is set instead of the
+// statistics when the scope's history could not be loaded.
+type BacktestResult struct {
+	Scope   string                `json:"scope"`
+	Target  string                `json:"target"`
+	Samples int                   `json:"samples"`
+	CPU     prediction.ErrorStats `json:"cpu"`
+	Memory  prediction.ErrorStats `json:"memory"`
+	Error   string                `json:"error,omitempty"`
+}
+
+// backtestSample is one replayed prediction: the rolling means observed at a past
+// timestamp and the usage actually observed horizon later
+type backtestSample struct {
+	target       TargetTimeInfo
+	cpuMean      float64
+	memoryMean   float64
+	actualCPU    float64
+	actualMemory float64
+}
+
+// HandleBacktest handles POST /api/v1/predict/backtest
+// @Summary Backtest prediction accuracy
+// @Description Replays the prediction pipeline against historical Prometheus data and compares the predictions to the usage actually observed
+// @Tags prediction
+// @Accept json
+// @Produce json
+// @Param request body BacktestRequest true "Backtest request"
+// @Success 200 {object} BacktestResponse
+// @Failure 400 {object} PredictErrorResponse
+// @Failure 503 {object} PredictErrorResponse
+// @Router /api/v1/predict/backtest [post]
+func (h *PredictionHandler) HandleBacktest(w http.ResponseWriter, r *http.Request) {
+	contentType := r.Header.Get("Content-Type")
+	if contentType != "" && !strings.HasPrefix(contentType, "application/json") {
+		h.respondError(w, http.StatusBadRequest, "Content-Type must be application/json", "", ErrCodeInvalidRequest)
+		return
+	}
+
+	var req BacktestRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid request format", err.Error(), ErrCodeInvalidRequest, validation.DecodeFields(err)...)
+		return
+	}
+
+	response, err := h.Backtest(r.Context(), &req)
+	if err != nil {
+		var requestErr *RequestError
+		if errors.As(err, &requestErr) {
+			h.respondError(w, requestErr.StatusCode, requestErr.Message, requestErr.Details, requestErr.Code, requestErr.Errors...)
+			return
+		}
+		h.respondError(w, http.StatusInternalServerError, "Backtest failed", err.Error(), ErrCodePredictionFailed)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, response)
+}
+
+// backtestWindow holds the parsed time parameters of a backtest request
+type backtestWindow struct {
+	start, end    time.Time
+	horizon, step time.Duration
+}
+
+// Backtest replays predictions at past timestamps for each requested scope and compares
+// them with the usage Prometheus recorded at the predicted time
+func (h *PredictionHandler) Backtest(ctx context.Context, req *BacktestRequest) (*BacktestResponse, error) {
+	window, err := h.validateBacktestRequest(req, h.now().UTC())
+	if err != nil {
+		return nil, invalidRequest(err, ErrCodeInvalidRequest)
+	}
+
+	if h.kserveClient == nil {
+		return nil, &RequestError{
+			StatusCode: http.StatusServiceUnavailable,
+			Message:    "KServe integration not enabled",
+			Details:    "KServe client is not configured",
+			Code:       ErrCodeKServeUnavailable,
+		}
+	}
+	if _, exists := h.kserveClient.GetModel(req.Model); !exists {
+		return nil, &RequestError{
+			StatusCode: http.StatusServiceUnavailable,
+			Message:    fmt.Sprintf("Model '%s' not available", req.Model),
+			Details:    "Model not found in KServe",
+			Code:       ErrCodeModelNotFound,
+		}
+	}
+	if h.prometheusClient == nil || !h.prometheusClient.IsAvailable() {
+		return nil, &RequestError{
+			StatusCode: http.StatusServiceUnavailable,
+			Message:    "Prometheus not available",
+			Details:    "Backtesting needs historical metrics from Prometheus",
+			Code:       ErrCodePrometheusUnavailable,
+		}
+	}
+
+	response := &BacktestResponse{
+		Status:  "success",
+		Model:   req.Model,
+		Start:   window.start.Format(time.RFC3339),
+		End:     window.end.Format(time.RFC3339),
+		Horizon: window.horizon.String(),
+		Step:    window.step.String(),
+		Results: make([]BacktestResult, 0, len(req.Scopes)),
+	}
+
+	for i := range req.Scopes {
+		result, err := h.backtestScope(ctx, req.Model, &req.Scopes[i], window)
+		if err != nil {
+			return nil, err
+		}
+		response.Results = append(response.Results, result)
+	}
+
+	h.log.WithFields(logrus.Fields{
+		"model":   req.Model,
+		"scopes":  len(response.Results),
+		"start":   response.Start,
+		"end":     response.End,
+		"horizon": response.Horizon,
+	}).Info("Prediction backtest completed")
+
+	return response, nil
+}
+
+// backtestScope evaluates a single scope. History failures are reported on the result so
+// other scopes still run; model failures abort the backtest.
+func (h *PredictionHandler) backtestScope(ctx context.Context, model string, scope *BacktestScope, window backtestWindow) (BacktestResult, error) {
+	predictReq := h.scopeRequest(scope)
+	result := BacktestResult{Scope: string(predictReq.Level), Target: h.getTarget(predictReq)}
+
+	cpu, memory, err := h.prometheusClient.GetScopedUsageHistory(ctx,
+		predictReq.Namespace, predictReq.Deployment, predictReq.Pod,
+		window.start, window.end.Add(window.horizon), window.step)
+	if costErr, ok := integrations.IsQueryCostError(err); ok {
+		return result, &RequestError{
+			StatusCode: http.StatusUnprocessableEntity,
+			Message:    "Query scope too broad",
+			Details:    costErr.Error(),
+			Code:       ErrCodeQueryTooExpensive,
+		}
+	}
+	if err != nil {
+		h.log.WithError(err).WithField("target", result.Target).Warn("Failed to load backtest history")
+		result.Error = err.Error()
+		return result, nil
+	}
+
+	samples := pairBacktestSamples(cpu, memory, window)
+	predictedCPU := make([]float64, 0, len(samples))
+	predictedMemory := make([]float64, 0, len(samples))
+	actualCPU := make([]float64, 0, len(samples))
+	actualMemory := make([]float64, 0, len(samples))
+	for _, sample := range samples {
+		cpuPercent, memoryPercent, _, _, err := h.predictPoint(ctx, model, sample.target, sample.cpuMean, sample.memoryMean)
+		if err != nil {
+			return result, err
+		}
+		predictedCPU = append(predictedCPU, cpuPercent)
+		predictedMemory = append(predictedMemory, memoryPercent)
+		actualCPU = append(actualCPU, sample.actualCPU*100)
+		actualMemory = append(actualMemory, sample.actualMemory*100)
+	}
+
+	result.Samples = len(samples)
+	result.CPU = prediction.ComputeErrorStats(predictedCPU, actualCPU)
+	result.Memory = prediction.ComputeErrorStats(predictedMemory, actualMemory)
+	return result, nil
+}
+
+// validateBacktestRequest validates the request, fills in defaults and returns the
+// replay window
+func (h *PredictionHandler) validateBacktestRequest(req *BacktestRequest, now time.Time) (backtestWindow, error) {
+	var window backtestWindow
+
+	if len(req.Scopes) == 0 {
+		req.Scopes = []BacktestScope{{Level: models.ScopeCluster}}
+	}
+	if len(req.Scopes) > MaxBacktestScopes {
+		return window, validation.New("scopes", validation.ConstraintRange, len(req.Scopes),
+			fmt.Sprintf("at most %d scopes are allowed", MaxBacktestScopes))
+	}
+	var errs validation.Errors
+	for i := range req.Scopes {
+		err := req.Scopes[i].Validate()
+		errs = append(errs, validation.Fields(validation.Nest(fmt.Sprintf("scopes[%d]", i), err))...)
+	}
+	if err := errs.Err(); err != nil {
+		return window, err
+	}
+	if req.Model == "" {
+		req.Model = "predictive-analytics"
+	}
+
+	var err error
+	if window.horizon, err = parseBacktestDuration(req.Horizon, "horizon"); err != nil {
+		return window, err
+	}
+	if window.step, err = parseBacktestDuration(req.Step, "step"); err != nil {
+		return window, err
+	}
+	if window.horizon%window.step != 0 {
+		return window, validation.New("horizon", validation.ConstraintRange, req.Horizon, "horizon must be a multiple of step")
+	}
+
+	latest := now.Add(-window.horizon)
+	window.end = latest
+	if req.End != "" {
+		if window.end, err = time.Parse(time.RFC3339, req.End); err != nil {
+			return window, validation.New("end", validation.ConstraintFormat, req.End, "end must be an RFC3339 timestamp")
+		}
+		if window.end.After(latest) {
+			return window, validation.New("end", validation.ConstraintRange, req.End,
+				fmt.Sprintf("end must be at least horizon (%s) in the past", window.horizon))
+		}
+	}
+	// The default window covers the week up to and including end
+	window.start = window.end.Add(window.step - defaultBacktestWindow)
+	if req.Start != "" {
+		if window.start, err = time.Parse(time.RFC3339, req.Start); err != nil {
+			return window, validation.New("start", validation.ConstraintFormat, req.Start, "start must be an RFC3339 timestamp")
+		}
+	}
+	if !window.start.Before(window.end) {
+		return window, validation.New("start", validation.ConstraintRange, req.Start, "start must be before end")
+	}
+
+	// Align to the step so Prometheus samples land on predictable timestamps
+	window.start = window.start.UTC().Truncate(window.step)
+	window.end = window.end.UTC().Truncate(window.step)
+	if samples := int(window.end.Sub(window.start)/window.step) + 1; samples > MaxBacktestSamples {
+		return window, validation.New("step", validation.ConstraintRange, req.Step,
+			fmt.Sprintf("backtest window yields %d samples per scope, at most %d are allowed; widen step or shorten the window",
+				samples, MaxBacktestSamples))
+	}
+	return window, nil
+}
+
+// parseBacktestDuration parses an optional duration of at least one hour, defaulting to 1h
+func parseBacktestDuration(value, field string) (time.Duration, error) {
+	if value == "" {
+		return time.Hour, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < time.Hour {
+		return 0, validation.New(field, validation.ConstraintRange, value, field+" must be a duration of at least 1h")
+	}
+	return d, nil
+}
+
+// scopeRequest converts a backtest scope to a PredictRequest for target naming
+func (h *PredictionHandler) scopeRequest(scope *BacktestScope) *PredictRequest {
+	return &PredictRequest{Scope: scope.Resolve()}
+}
+
+// pairBacktestSamples matches each replayed timestamp in the window with the usage
+// observed horizon later. Timestamps missing either side are skipped.
+func pairBacktestSamples(cpu, memory []integrations.MetricDataPoint, window backtestWindow) []backtestSample {
+	memoryAt := make(map[int64]float64, len(memory))
+	for _, point := range memory {
+		memoryAt[point.Timestamp.Unix()] = point.Value
+	}
+	cpuAt := make(map[int64]float64, len(cpu))
+	for _, point := range cpu {
+		cpuAt[point.Timestamp.Unix()] = point.Value
+	}
+
+	var samples []backtestSample
+	for _, point := range cpu {
+		if point.Timestamp.Before(window.start) || point.Timestamp.After(window.end) {
+			continue
+		}
+		asOf := point.Timestamp.Unix()
+		targetTime := point.Timestamp.Add(window.horizon).UTC()
+		target := targetTime.Unix()
+
+		memoryMean, ok := memoryAt[asOf]
+		if !ok {
+			continue
+		}
+		actualCPU, ok := cpuAt[target]
+		if !ok {
+			continue
+		}
+		actualMemory, ok := memoryAt[target]
+		if !ok {
+			continue
+		}
+
+		samples = append(samples, backtestSample{
+			target: TargetTimeInfo{
+				Hour:         targetTime.Hour(),
+				DayOfWeek:    (int(targetTime.Weekday()) + 6) % 7,
+				ISOTimestamp: targetTime.Format(time.RFC3339),
+			},
+			cpuMean:      point.Value,
+			memoryMean:   memoryMean,
+			actualCPU:    actualCPU,
+			actualMemory: actualMemory,
+		})
+	}
+	return samples
+}
@@ -0,0 +1,150 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/integrations"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/kserve"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+func TestPredictionHandler_ValidateBacktestRequest(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	handler := NewPredictionHandler(nil, nil, log)
+	now := time.Date(2026, 10, 14, 9, 35, 0, 0, time.UTC)
+
+	t.Run("defaults", func(t *testing.T) {
+		req := &BacktestRequest{}
+		window, err := handler.validateBacktestRequest(req, now)
+		require.NoError(t, err)
+
+		assert.Equal(t, []BacktestScope{{Level: models.ScopeCluster}}, req.Scopes)
+		assert.Equal(t, "predictive-analytics", req.Model)
+		assert.Equal(t, time.Hour, window.horizon)
+		assert.Equal(t, time.Hour, window.step)
+		assert.Equal(t, time.Date(2026, 10, 14, 8, 0, 0, 0, time.UTC), window.end)
+		assert.Equal(t, time.Date(2026, 10, 7, 9, 0, 0, 0, time.UTC), window.start)
+	})
+
+	t.Run("explicit window", func(t *testing.T) {
+		req := &BacktestRequest{
+			Scopes:  []BacktestScope{{Namespace: "payments"}, {Namespace: "payments", Deployment: "api"}},
+			Start:   "2026-10-12T00:00:00Z",
+			End:     "2026-10-13T00:00:00Z",
+			Horizon: "6h",
+			Step:    "2h",
+		}
+		window, err := handler.validateBacktestRequest(req, now)
+		require.NoError(t, err)
+		assert.Equal(t, 6*time.Hour, window.horizon)
+		assert.Equal(t, 2*time.Hour, window.step)
+	})
+
+	tests := []struct {
+		name    string
+		req     BacktestRequest
+		wantErr string
+	}{
+		{"too many scopes", BacktestRequest{Scopes: make([]BacktestScope, MaxBacktestScopes+1)}, "at most 5 scopes"},
+		{"invalid scope", BacktestRequest{Scopes: []BacktestScope{{Level: "node"}}}, "scope must be one of"},
+		{"deployment without namespace", BacktestRequest{Scopes: []BacktestScope{{Deployment: "api"}}}, "namespace is required"},
+		{"sub-hour horizon", BacktestRequest{Horizon: "15m"}, "horizon must be"},
+		{"horizon not a multiple of step", BacktestRequest{Horizon: "3h", Step: "2h"}, "multiple of step"},
+		{"end too recent", BacktestRequest{End: "2026-10-14T09:00:00Z"}, "in the past"},
+		{"bad start", BacktestRequest{Start: "last week"}, "RFC3339"},
+		{"start after end", BacktestRequest{Start: "2026-10-14T00:00:00Z", End: "2026-10-13T00:00:00Z"}, "before end"},
+		{"window too long", BacktestRequest{Start: "2026-09-01T00:00:00Z", End: "2026-10-13T00:00:00Z"}, "at most 168"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := tt.req
+			_, err := handler.validateBacktestRequest(&req, now)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestPairBacktestSamples(t *testing.T) {
+	start := time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)
+	series := func(values ...float64) []integrations.MetricDataPoint {
+		points := make([]integrations.MetricDataPoint, len(values))
+		for i, v := range values {
+			points[i] = integrations.MetricDataPoint{Timestamp: start.Add(time.Duration(i) * time.Hour), Value: v}
+		}
+		return points
+	}
+
+	window := backtestWindow{start: start, end: start.Add(2 * time.Hour), horizon: time.Hour, step: time.Hour}
+	cpu := series(0.1, 0.2, 0.3, 0.4)
+	memory := series(0.5, 0.6, 0.7, 0.8)
+	// A gap in the memory series drops the sample that needs it
+	memory = append(memory[:1], memory[2:]...)
+
+	samples := pairBacktestSamples(cpu, memory, window)
+	require.Len(t, samples, 1)
+
+	assert.Equal(t, 0.3, samples[0].cpuMean)
+	assert.Equal(t, 0.7, samples[0].memoryMean)
+	assert.Equal(t, 0.4, samples[0].actualCPU)
+	assert.Equal(t, 0.8, samples[0].actualMemory)
+	assert.Equal(t, TargetTimeInfo{Hour: 3, DayOfWeek: 0, ISOTimestamp: "2026-10-12T03:00:00Z"}, samples[0].target)
+}
+
+func TestPredictionHandler_Backtest_Unavailable(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	t.Run("no kserve", func(t *testing.T) {
+		handler := NewPredictionHandler(nil, nil, log)
+		_, err := handler.Backtest(context.Background(), &BacktestRequest{})
+
+		var requestErr *RequestError
+		require.ErrorAs(t, err, &requestErr)
+		assert.Equal(t, ErrCodeKServeUnavailable, requestErr.Code)
+	})
+
+	t.Run("no prometheus", func(t *testing.T) {
+		os.Setenv("KSERVE_PREDICTIVE_ANALYTICS_SERVICE", "predictive-analytics-predictor")
+		defer os.Unsetenv("KSERVE_PREDICTIVE_ANALYTICS_SERVICE")
+
+		kserveClient, err := kserve.NewProxyClient(kserve.ProxyConfig{Namespace: "test-ns", Timeout: time.Second}, log)
+		require.NoError(t, err)
+
+		handler := NewPredictionHandler(kserveClient, nil, log)
+		reqBody := `{"scopes": [{"namespace": "payments"}]}`
+		req := httptest.NewRequest("POST", "/api/v1/predict/backtest", bytes.NewBufferString(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		handler.HandleBacktest(w, req)
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		var resp PredictErrorResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+		assert.Equal(t, ErrCodePrometheusUnavailable, resp.Code)
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		handler := NewPredictionHandler(nil, nil, log)
+		req := httptest.NewRequest("POST", "/api/v1/predict/backtest", bytes.NewBufferString(`{"horizon": "soon"}`))
+		w := httptest.NewRecorder()
+
+		handler.HandleBacktest(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
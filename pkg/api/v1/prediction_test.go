@@ -2,7 +2,9 @@ package v1
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -14,7 +16,11 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/tosin2013/openshift-coordination-engine/internal/integrations"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/api/validation"
 	"github.com/tosin2013/openshift-coordination-engine/pkg/kserve"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/testsupport"
 )
 
 func TestPredictionHandler_HandlePredict_Validation(t *testing.T) {
@@ -38,7 +44,7 @@ func TestPredictionHandler_HandlePredict_Validation(t *testing.T) {
 		require.NoError(t, err)
 
 		assert.Equal(t, "error", resp.Status)
-		assert.Contains(t, resp.Error, "hour must be between 0-23")
+		assert.Contains(t, resp.Error, "hour must be between 0 and 23")
 		assert.Equal(t, ErrCodeInvalidRequest, resp.Code)
 	})
 
@@ -56,7 +62,7 @@ func TestPredictionHandler_HandlePredict_Validation(t *testing.T) {
 		err := json.NewDecoder(w.Body).Decode(&resp)
 		require.NoError(t, err)
 
-		assert.Contains(t, resp.Error, "hour must be between 0-23")
+		assert.Contains(t, resp.Error, "hour must be between 0 and 23")
 	})
 
 	t.Run("invalid day_of_week - too high", func(t *testing.T) {
@@ -73,7 +79,7 @@ func TestPredictionHandler_HandlePredict_Validation(t *testing.T) {
 		err := json.NewDecoder(w.Body).Decode(&resp)
 		require.NoError(t, err)
 
-		assert.Contains(t, resp.Error, "day_of_week must be between 0-6")
+		assert.Contains(t, resp.Error, "day_of_week must be between 0 and 6")
 	})
 
 	t.Run("invalid day_of_week - negative", func(t *testing.T) {
@@ -90,7 +96,7 @@ func TestPredictionHandler_HandlePredict_Validation(t *testing.T) {
 		err := json.NewDecoder(w.Body).Decode(&resp)
 		require.NoError(t, err)
 
-		assert.Contains(t, resp.Error, "day_of_week must be between 0-6")
+		assert.Contains(t, resp.Error, "day_of_week must be between 0 and 6")
 	})
 
 	t.Run("invalid scope", func(t *testing.T) {
@@ -124,7 +130,7 @@ func TestPredictionHandler_HandlePredict_Validation(t *testing.T) {
 		err := json.NewDecoder(w.Body).Decode(&resp)
 		require.NoError(t, err)
 
-		assert.Contains(t, resp.Error, "pod name is required")
+		assert.Contains(t, resp.Error, "pod is required when scope is 'pod'")
 	})
 
 	t.Run("deployment scope requires deployment name", func(t *testing.T) {
@@ -141,7 +147,7 @@ func TestPredictionHandler_HandlePredict_Validation(t *testing.T) {
 		err := json.NewDecoder(w.Body).Decode(&resp)
 		require.NoError(t, err)
 
-		assert.Contains(t, resp.Error, "deployment name is required")
+		assert.Contains(t, resp.Error, "deployment is required when scope is 'deployment'")
 	})
 
 	t.Run("pod scope requires namespace", func(t *testing.T) {
@@ -194,6 +200,47 @@ func TestPredictionHandler_HandlePredict_Validation(t *testing.T) {
 
 		assert.Contains(t, resp.Error, "Content-Type must be application/json")
 	})
+
+	t.Run("field errors", func(t *testing.T) {
+		reqBody := `{"hour": 25, "day_of_week": 9}`
+		req := httptest.NewRequest("POST", "/api/v1/predict", bytes.NewBufferString(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		handler.HandlePredict(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var resp PredictErrorResponse
+		err := json.NewDecoder(w.Body).Decode(&resp)
+		require.NoError(t, err)
+
+		require.Len(t, resp.Errors, 2)
+		assert.Equal(t, "hour", resp.Errors[0].Field)
+		assert.Equal(t, validation.ConstraintRange, resp.Errors[0].Constraint)
+		assert.EqualValues(t, 25, resp.Errors[0].Value)
+		assert.Equal(t, "day_of_week", resp.Errors[1].Field)
+		assert.EqualValues(t, 9, resp.Errors[1].Value)
+	})
+
+	t.Run("wrong type", func(t *testing.T) {
+		reqBody := `{"hour": "nine", "day_of_week": 3}`
+		req := httptest.NewRequest("POST", "/api/v1/predict", bytes.NewBufferString(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		handler.HandlePredict(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var resp PredictErrorResponse
+		err := json.NewDecoder(w.Body).Decode(&resp)
+		require.NoError(t, err)
+
+		require.Len(t, resp.Errors, 1)
+		assert.Equal(t, "hour", resp.Errors[0].Field)
+		assert.Equal(t, validation.ConstraintType, resp.Errors[0].Constraint)
+	})
 }
 
 func TestPredictionHandler_HandlePredict_NoKServe(t *testing.T) {
@@ -326,39 +373,37 @@ func TestPredictionHandler_Scoping(t *testing.T) {
 
 	t.Run("namespace scope from namespace field", func(t *testing.T) {
 		req := &PredictRequest{
+			Scope:     models.Scope{Namespace: "my-namespace"},
 			Hour:      15,
 			DayOfWeek: 3,
-			Namespace: "my-namespace",
 		}
 		handler.setRequestDefaults(req)
 
-		assert.Equal(t, "namespace", req.Scope)
+		assert.Equal(t, models.ScopeNamespace, req.Level)
 		assert.Equal(t, "my-namespace", handler.getTarget(req))
 	})
 
 	t.Run("deployment scope from deployment field", func(t *testing.T) {
 		req := &PredictRequest{
-			Hour:       15,
-			DayOfWeek:  3,
-			Namespace:  "my-namespace",
-			Deployment: "my-deployment",
+			Scope:     models.Scope{Namespace: "my-namespace", Deployment: "my-deployment"},
+			Hour:      15,
+			DayOfWeek: 3,
 		}
 		handler.setRequestDefaults(req)
 
-		assert.Equal(t, "deployment", req.Scope)
+		assert.Equal(t, models.ScopeDeployment, req.Level)
 		assert.Equal(t, "my-namespace/my-deployment", handler.getTarget(req))
 	})
 
 	t.Run("pod scope from pod field", func(t *testing.T) {
 		req := &PredictRequest{
+			Scope:     models.Scope{Namespace: "my-namespace", Pod: "my-pod-xyz"},
 			Hour:      15,
 			DayOfWeek: 3,
-			Namespace: "my-namespace",
-			Pod:       "my-pod-xyz",
 		}
 		handler.setRequestDefaults(req)
 
-		assert.Equal(t, "pod", req.Scope)
+		assert.Equal(t, models.ScopePod, req.Level)
 		assert.Equal(t, "my-namespace/my-pod-xyz", handler.getTarget(req))
 	})
 
@@ -369,19 +414,19 @@ func TestPredictionHandler_Scoping(t *testing.T) {
 		}
 		handler.setRequestDefaults(req)
 
-		assert.Equal(t, "cluster", req.Scope)
+		assert.Equal(t, models.ScopeCluster, req.Level)
 		assert.Equal(t, "cluster", handler.getTarget(req))
 	})
 
 	t.Run("explicit cluster scope", func(t *testing.T) {
 		req := &PredictRequest{
+			Scope:     models.Scope{Level: "cluster"},
 			Hour:      15,
 			DayOfWeek: 3,
-			Scope:     "cluster",
 		}
 		handler.setRequestDefaults(req)
 
-		assert.Equal(t, "cluster", req.Scope)
+		assert.Equal(t, models.ScopeCluster, req.Level)
 		assert.Equal(t, "cluster", handler.getTarget(req))
 	})
 
@@ -407,6 +452,40 @@ func TestPredictionHandler_Scoping(t *testing.T) {
 	})
 }
 
+func TestPredictionHandler_GetScopedMetrics_DataQuality(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	t.Run("prometheus unavailable", func(t *testing.T) {
+		handler := NewPredictionHandler(nil, nil, log)
+		_, _, err := handler.getScopedMetrics(context.Background(), &PredictRequest{Scope: models.Scope{Level: "cluster"}})
+		require.Error(t, err)
+
+		quality := defaultedQuality(err, "cpu_rolling_mean", "memory_rolling_mean")
+		assert.True(t, quality.Degraded)
+		assert.True(t, quality.PrometheusUnavailable)
+		assert.Empty(t, quality.MissingExporters)
+	})
+
+	t.Run("cadvisor missing", func(t *testing.T) {
+		prom := testsupport.NewPrometheus()
+		promClient := prom.Client(log)
+		promClient.ProbeCapabilities(context.Background())
+		probes := len(prom.Calls())
+		handler := NewPredictionHandler(nil, promClient, log)
+
+		_, _, err := handler.getScopedMetrics(context.Background(), &PredictRequest{Scope: models.Scope{Level: "pod", Namespace: "apps", Pod: "api-0"}})
+		var missingErr *MissingExportersError
+		require.True(t, errors.As(err, &missingErr))
+		assert.Len(t, prom.Calls(), probes, "no usage queries are sent")
+
+		quality := defaultedQuality(err, "cpu_rolling_mean", "memory_rolling_mean")
+		assert.False(t, quality.PrometheusUnavailable)
+		assert.Equal(t, []string{integrations.ExporterCAdvisor}, quality.MissingExporters)
+		assert.Equal(t, []string{"cpu_rolling_mean", "memory_rolling_mean"}, quality.DefaultedMetrics)
+	})
+}
+
 func TestPredictionHandler_RegisterRoutes(t *testing.T) {
 	log := logrus.New()
 	log.SetLevel(logrus.ErrorLevel)
@@ -420,6 +499,9 @@ func TestPredictionHandler_RegisterRoutes(t *testing.T) {
 	req := httptest.NewRequest("POST", "/api/v1/predict", http.NoBody)
 	match := &mux.RouteMatch{}
 	assert.True(t, router.Match(req, match))
+
+	req = httptest.NewRequest("POST", "/api/v1/predict/backtest", http.NoBody)
+	assert.True(t, router.Match(req, &mux.RouteMatch{}))
 }
 
 func TestPredictRequest_Structure(t *testing.T) {
@@ -442,7 +524,7 @@ func TestPredictRequest_Structure(t *testing.T) {
 	assert.Equal(t, "production", req.Namespace)
 	assert.Equal(t, "my-app", req.Deployment)
 	assert.Equal(t, "my-app-xyz", req.Pod)
-	assert.Equal(t, "deployment", req.Scope)
+	assert.Equal(t, models.ScopeDeployment, req.Level)
 	assert.Equal(t, "predictive-analytics", req.Model)
 }
 
@@ -522,7 +604,7 @@ func TestCalculateTargetTimestamp(t *testing.T) {
 
 	t.Run("calculates timestamp for future time", func(t *testing.T) {
 		// Test that we get a valid RFC3339 timestamp
-		timestamp := handler.calculateTargetTimestamp(15, 3)
+		timestamp := handler.calculateTargetTimestamp(15, 3, time.Now())
 		assert.NotEmpty(t, timestamp)
 
 		// Verify it parses correctly
@@ -535,17 +617,146 @@ func TestCalculateTargetTimestamp(t *testing.T) {
 
 	t.Run("handles boundary hours", func(t *testing.T) {
 		// Hour 0 (midnight)
-		timestamp := handler.calculateTargetTimestamp(0, 0)
+		timestamp := handler.calculateTargetTimestamp(0, 0, time.Now())
 		parsed, err := time.Parse(time.RFC3339, timestamp)
 		require.NoError(t, err)
 		assert.Equal(t, 0, parsed.Hour())
 
 		// Hour 23
-		timestamp = handler.calculateTargetTimestamp(23, 6)
+		timestamp = handler.calculateTargetTimestamp(23, 6, time.Now())
 		parsed, err = time.Parse(time.RFC3339, timestamp)
 		require.NoError(t, err)
 		assert.Equal(t, 23, parsed.Hour())
 	})
+
+	t.Run("relative to now", func(t *testing.T) {
+		// Wednesday 2026-10-14 09:35 UTC
+		now := time.Date(2026, 10, 14, 9, 35, 0, 0, time.UTC)
+		assert.Equal(t, "2026-10-14T15:00:00Z", handler.calculateTargetTimestamp(15, 2, now))
+		assert.Equal(t, "2026-10-21T09:00:00Z", handler.calculateTargetTimestamp(9, 2, now), "a passed hour moves to next week")
+	})
+}
+
+func TestPredictionHandler_TargetTimes(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	handler := NewPredictionHandler(nil, nil, log)
+	// Wednesday 2026-10-14 09:35 UTC
+	now := time.Date(2026, 10, 14, 9, 35, 0, 0, time.UTC)
+
+	t.Run("single point uses hour and day_of_week", func(t *testing.T) {
+		targets, err := handler.targetTimes(&PredictRequest{Hour: 15, DayOfWeek: 3}, now)
+		require.NoError(t, err)
+		require.Len(t, targets, 1)
+		assert.Equal(t, 15, targets[0].Hour)
+		assert.Equal(t, 3, targets[0].DayOfWeek)
+	})
+
+	t.Run("horizon expands to hourly points", func(t *testing.T) {
+		req := &PredictRequest{Horizon: &PredictionHorizon{Every: "1h", For: "24h"}}
+		targets, err := handler.targetTimes(req, now)
+		require.NoError(t, err)
+		require.Len(t, targets, 24)
+
+		assert.Equal(t, "2026-10-14T10:00:00Z", targets[0].ISOTimestamp)
+		assert.Equal(t, 10, targets[0].Hour)
+		assert.Equal(t, 2, targets[0].DayOfWeek) // Wednesday
+		assert.Equal(t, "2026-10-15T09:00:00Z", targets[23].ISOTimestamp)
+		assert.Equal(t, 3, targets[23].DayOfWeek) // Thursday
+	})
+
+	t.Run("horizon resolution", func(t *testing.T) {
+		// auto picks 6h steps for a month within the default 168 points
+		targets, err := handler.targetTimes(&PredictRequest{Horizon: &PredictionHorizon{Resolution: "auto", For: "720h"}}, now)
+		require.NoError(t, err)
+		require.Len(t, targets, 120)
+		assert.Equal(t, "2026-10-14T12:00:00Z", targets[0].ISOTimestamp)
+
+		targets, err = handler.targetTimes(&PredictRequest{Horizon: &PredictionHorizon{Resolution: "auto", For: "168h", MaxPoints: 24}}, now)
+		require.NoError(t, err)
+		assert.Len(t, targets, 7)
+
+		targets, err = handler.targetTimes(&PredictRequest{Horizon: &PredictionHorizon{Resolution: "1d", For: "72h"}}, now)
+		require.NoError(t, err)
+		require.Len(t, targets, 3)
+		assert.Equal(t, "2026-10-15T00:00:00Z", targets[0].ISOTimestamp)
+	})
+
+	t.Run("explicit target times", func(t *testing.T) {
+		req := &PredictRequest{TargetTimes: []string{"2026-10-18T23:30:00Z", "2026-10-19T02:00:00+02:00"}}
+		targets, err := handler.targetTimes(req, now)
+		require.NoError(t, err)
+		require.Len(t, targets, 2)
+
+		assert.Equal(t, TargetTimeInfo{Hour: 23, DayOfWeek: 6, ISOTimestamp: "2026-10-18T23:00:00Z"}, targets[0])
+		assert.Equal(t, TargetTimeInfo{Hour: 0, DayOfWeek: 0, ISOTimestamp: "2026-10-19T00:00:00Z"}, targets[1])
+	})
+
+	tests := []struct {
+		name    string
+		req     *PredictRequest
+		wantErr string
+	}{
+		{"both forms", &PredictRequest{TargetTimes: []string{"2026-10-18T23:00:00Z"}, Horizon: &PredictionHorizon{Every: "1h", For: "2h"}}, "mutually exclusive"},
+		{"sub-hour step", &PredictRequest{Horizon: &PredictionHorizon{Every: "30m", For: "2h"}}, "horizon.every"},
+		{"span shorter than step", &PredictRequest{Horizon: &PredictionHorizon{Every: "2h", For: "1h"}}, "horizon.for"},
+		{"too many horizon points", &PredictRequest{Horizon: &PredictionHorizon{Every: "1h", For: "720h"}}, "more than 168"},
+		{"every and resolution", &PredictRequest{Horizon: &PredictionHorizon{Every: "1h", Resolution: "auto", For: "24h"}}, "mutually exclusive"},
+		{"unknown resolution", &PredictRequest{Horizon: &PredictionHorizon{Resolution: "5m", For: "24h"}}, "horizon.resolution"},
+		{"span shorter than resolution", &PredictRequest{Horizon: &PredictionHorizon{Resolution: "1d", For: "6h"}}, "horizon.for"},
+		{"too many resolution points", &PredictRequest{Horizon: &PredictionHorizon{Resolution: "1h", For: "720h"}}, "more than 168"},
+		{"bad timestamp", &PredictRequest{TargetTimes: []string{"tomorrow"}}, "RFC3339"},
+		{"too many target times", &PredictRequest{TargetTimes: make([]string, MaxPredictionPoints+1)}, "at most 168"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := handler.targetTimes(tt.req, now)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestPredictionHandler_HandlePredict_InvalidSeries(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	handler := NewPredictionHandler(nil, nil, log)
+
+	reqBody := `{"horizon": {"every": "1h", "for": "1000h"}}`
+	req := httptest.NewRequest("POST", "/api/v1/predict", bytes.NewBufferString(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.HandlePredict(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	var resp PredictErrorResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, ErrCodeInvalidRequest, resp.Code)
+}
+
+func TestPredictionHandler_HandlePredict_InvalidAt(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	handler := NewPredictionHandler(nil, nil, log)
+
+	reqBody := `{"hour": 3, "day_of_week": 1, "at": "2999-01-01T03:12:00Z"}`
+	req := httptest.NewRequest("POST", "/api/v1/predict", bytes.NewBufferString(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.HandlePredict(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	var resp PredictErrorResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, ErrCodeInvalidRequest, resp.Code)
+	require.Len(t, resp.Errors, 1)
+	assert.Equal(t, "at", resp.Errors[0].Field)
+	assert.Equal(t, validation.ConstraintRange, resp.Errors[0].Constraint)
 }
 
 func TestClampPercentage(t *testing.T) {
@@ -573,12 +784,10 @@ func TestPredictionHandler_ValidateRequest(t *testing.T) {
 
 	t.Run("valid request with all fields", func(t *testing.T) {
 		req := &PredictRequest{
-			Hour:       15,
-			DayOfWeek:  3,
-			Namespace:  "production",
-			Deployment: "my-app",
-			Scope:      "deployment",
-			Model:      "predictive-analytics",
+			Scope:     models.Scope{Namespace: "production", Deployment: "my-app", Level: "deployment"},
+			Hour:      15,
+			DayOfWeek: 3,
+			Model:     "predictive-analytics",
 		}
 		err := handler.validateRequest(req)
 		assert.NoError(t, err)
@@ -586,9 +795,9 @@ func TestPredictionHandler_ValidateRequest(t *testing.T) {
 
 	t.Run("valid namespace scope without namespace", func(t *testing.T) {
 		req := &PredictRequest{
+			Scope:     models.Scope{Level: "namespace"},
 			Hour:      15,
 			DayOfWeek: 3,
-			Scope:     "namespace",
 		}
 		err := handler.validateRequest(req)
 		// Namespace scope without namespace is allowed (falls back to cluster)
@@ -597,9 +806,9 @@ func TestPredictionHandler_ValidateRequest(t *testing.T) {
 
 	t.Run("valid cluster scope", func(t *testing.T) {
 		req := &PredictRequest{
+			Scope:     models.Scope{Level: "cluster"},
 			Hour:      0,
 			DayOfWeek: 0,
-			Scope:     "cluster",
 		}
 		err := handler.validateRequest(req)
 		assert.NoError(t, err)
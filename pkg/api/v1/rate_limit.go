@@ -0,0 +1,66 @@
+package v1
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+// maxCallerLimiters is the number of per-caller rate limiters kept before idle ones are dropped
+const maxCallerLimiters = 1024
+
+// callerLimiters keeps a token bucket per caller key
+type callerLimiters struct {
+	limit float64
+	burst int
+
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+}
+
+// newCallerLimiters creates buckets refilling limit tokens per second, holding up to burst
+func newCallerLimiters(limit float64, burst int) *callerLimiters {
+	return &callerLimiters{limit: limit, burst: burst, limiters: make(map[string]*rate.Limiter)}
+}
+
+// allow takes a request from the bucket of key
+func (l *callerLimiters) allow(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	limiter, ok := l.limiters[key]
+	if !ok {
+		if len(l.limiters) >= maxCallerLimiters {
+			l.pruneLocked()
+		}
+		limiter = rate.NewLimiter(rate.Limit(l.limit), l.burst)
+		l.limiters[key] = limiter
+	}
+	return limiter.Allow()
+}
+
+// pruneLocked drops limiters whose bucket has refilled, i.e. of idle callers
+func (l *callerLimiters) pruneLocked() {
+	for key, limiter := range l.limiters {
+		if limiter.Tokens() >= float64(l.burst) {
+			delete(l.limiters, key)
+		}
+	}
+}
+
+// retryAfter is the number of seconds until a rate limited caller gets a new request
+func (l *callerLimiters) retryAfter() string {
+	return strconv.Itoa(int(math.Ceil(1 / l.limit)))
+}
+
+// remoteHost returns the address of a request's client without its port
+func remoteHost(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
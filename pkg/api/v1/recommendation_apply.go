@@ -0,0 +1,462 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/api/validation"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// Served recommendations can be applied for servedRecommendationTTL; at most
+// maxServedRecommendations are kept, the oldest dropped first
+const (
+	servedRecommendationTTL  = time.Hour
+	maxServedRecommendations = 1000
+)
+
+// DefaultApplicationTrackingInterval is how often workflows started from
+// recommendations are checked for their outcome
+const DefaultApplicationTrackingInterval = 30 * time.Second
+
+// verifyStep is the name of the workflow step checking that a remediation took effect
+const verifyStep = "verify"
+
+// ErrCodeRecommendationNotFound is returned when a recommendation was not served
+// recently enough to be applied
+const ErrCodeRecommendationNotFound = "RECOMMENDATION_NOT_FOUND"
+
+// remediableKinds are the recommendation target kinds that can be remediated without
+// naming a resource
+var remediableKinds = map[string]bool{"pod": true, "deployment": true, "statefulset": true, "daemonset": true}
+
+// ApplyRecommendationRequest applies a recommendation through a remediation workflow
+type ApplyRecommendationRequest struct {
+	// Namespace and Resource name the workload to remediate when the recommendation
+	// does not target one, e.g. a namespace-wide recommendation. A namespace that
+	// differs from the recommendation's is rejected.
+	Namespace string `json:"namespace,omitempty"`
+	Resource  *struct {
+		Kind string `json:"kind"`
+		Name string `json:"name"`
+	} `json:"resource,omitempty"`
+
+	// IncidentID links the workflow to an incident; it defaults to the
+	// recommendation's related incident, or else an incident created for it
+	IncidentID string `json:"incident_id,omitempty"`
+
+	// Force remediates even while the cluster or a MachineConfigPool is updating
+	Force bool `json:"force,omitempty"`
+
+	AppliedBy string `json:"applied_by,omitempty"`
+}
+
+// RecommendationApplication is a recommendation applied through the remediation
+// orchestrator and the outcome of its workflow
+type RecommendationApplication struct {
+	RecommendationID string         `json:"recommendation_id"`
+	Recommendation   Recommendation `json:"recommendation"`
+	IncidentID       string         `json:"incident_id"`
+	Namespace        string         `json:"namespace"`
+	Resource         string         `json:"resource"`
+
+	// Status is the workflow status, or the trigger status of remediations without
+	// a workflow (a playbook run, or a scaled or paged escalation)
+	Status     string `json:"status"`
+	WorkflowID string `json:"workflow_id,omitempty"`
+
+	// Verification is the status of the workflow's verify step, when it has one
+	Verification string `json:"verification,omitempty"`
+
+	// Policy, Playbook, ExecutionID and Backend are as in the remediation trigger response
+	Policy      string `json:"policy,omitempty"`
+	Playbook    string `json:"playbook,omitempty"`
+	ExecutionID string `json:"execution_id,omitempty"`
+	Backend     string `json:"backend,omitempty"`
+
+	// Outcome is the action outcome recorded for the top recommended action once the
+	// workflow finished
+	Outcome *models.ActionOutcome `json:"outcome,omitempty"`
+
+	AppliedBy   string     `json:"applied_by,omitempty"`
+	AppliedAt   time.Time  `json:"applied_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// servedRecommendation is a recommendation returned by Recommend
+type servedRecommendation struct {
+	recommendation Recommendation
+	servedAt       time.Time
+}
+
+// recommendationLedger holds recently served recommendations by ID, so they can be
+// applied, and the applications started from them
+type recommendationLedger struct {
+	mu           sync.Mutex
+	served       map[string]servedRecommendation
+	applications map[string]*RecommendationApplication
+}
+
+func newRecommendationLedger() *recommendationLedger {
+	return &recommendationLedger{
+		served:       make(map[string]servedRecommendation),
+		applications: make(map[string]*RecommendationApplication),
+	}
+}
+
+// serve records recommendations returned at now. Recommendation IDs are only unique
+// within a response, so the latest recommendation served under an ID replaces earlier ones.
+func (l *recommendationLedger) serve(recommendations []Recommendation, now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for i := range recommendations {
+		l.served[recommendations[i].ID] = servedRecommendation{recommendation: recommendations[i], servedAt: now}
+	}
+	for id, served := range l.served {
+		if now.Sub(served.servedAt) > servedRecommendationTTL {
+			delete(l.served, id)
+		}
+	}
+	if excess := len(l.served) - maxServedRecommendations; excess > 0 {
+		ids := make([]string, 0, len(l.served))
+		for id := range l.served {
+			ids = append(ids, id)
+		}
+		sort.Slice(ids, func(i, j int) bool { return l.served[ids[i]].servedAt.Before(l.served[ids[j]].servedAt) })
+		for _, id := range ids[:excess] {
+			delete(l.served, id)
+		}
+	}
+}
+
+// recommendation returns a recommendation served within the TTL before now
+func (l *recommendationLedger) recommendation(id string, now time.Time) (Recommendation, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	served, ok := l.served[id]
+	if !ok || now.Sub(served.servedAt) > servedRecommendationTTL {
+		return Recommendation{}, false
+	}
+	return served.recommendation, true
+}
+
+// SetRemediationHandler enables applying recommendations through the remediation
+// handler, so policies, budgets, playbooks and escalation ladders apply as they do to
+// remediation triggers
+func (h *RecommendationsHandler) SetRemediationHandler(remediation *RemediationHandler) {
+	h.remediation = remediation
+}
+
+// ApplyRecommendation handles POST /api/v1/recommendations/{id}/apply
+// @Summary Apply a recommendation
+// @Description Starts a remediation workflow for a recently served recommendation, subject to remediation policies, budgets and approvals, and tracks its verification outcome
+// @Tags recommendations
+// @Accept json
+// @Produce json
+// @Param id path string true "Recommendation ID"
+// @Param request body ApplyRecommendationRequest false "Workload, incident and approver"
+// @Success 202 {object} RecommendationApplication
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v1/recommendations/{id}/apply [post]
+func (h *RecommendationsHandler) ApplyRecommendation(w http.ResponseWriter, r *http.Request) {
+	if h.remediation == nil {
+		h.respondError(w, http.StatusServiceUnavailable, "Applying recommendations is not enabled")
+		return
+	}
+
+	var req ApplyRecommendationRequest
+	if r.ContentLength > 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.respondError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err), validation.DecodeFields(err)...)
+			return
+		}
+	}
+
+	application, err := h.Apply(r.Context(), mux.Vars(r)["id"], &req)
+	if err != nil {
+		var requestErr *RequestError
+		if errors.As(err, &requestErr) {
+			h.respondError(w, requestErr.StatusCode, requestErr.Error(), requestErr.Errors...)
+			return
+		}
+		h.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.respondJSON(w, http.StatusAccepted, application)
+}
+
+// GetRecommendationApplication handles GET /api/v1/recommendations/{id}/application
+// @Summary Get the application of a recommendation
+// @Description Returns the latest workflow started from a recommendation, its verification status and the recorded action outcome
+// @Tags recommendations
+// @Produce json
+// @Param id path string true "Recommendation ID"
+// @Success 200 {object} RecommendationApplication
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/recommendations/{id}/application [get]
+func (h *RecommendationsHandler) GetRecommendationApplication(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	h.ledger.mu.Lock()
+	application, ok := h.ledger.applications[id]
+	h.ledger.mu.Unlock()
+	if !ok {
+		h.respondError(w, http.StatusNotFound, "recommendation has not been applied: "+id)
+		return
+	}
+	h.respondJSON(w, http.StatusOK, h.trackApplication(application))
+}
+
+// Apply starts a remediation workflow for a recently served recommendation
+func (h *RecommendationsHandler) Apply(ctx context.Context, id string, req *ApplyRecommendationRequest) (*RecommendationApplication, error) {
+	rec, ok := h.ledger.recommendation(id, h.now())
+	if !ok {
+		return nil, &RequestError{
+			StatusCode: http.StatusNotFound,
+			Message:    "Recommendation not found",
+			Details:    fmt.Sprintf("%s was not served in the last %s; request recommendations again", id, servedRecommendationTTL),
+			Code:       ErrCodeRecommendationNotFound,
+		}
+	}
+
+	h.ledger.mu.Lock()
+	previous := h.ledger.applications[id]
+	h.ledger.mu.Unlock()
+	if previous != nil && h.trackApplication(previous).CompletedAt == nil && previous.WorkflowID != "" {
+		return nil, &RequestError{
+			StatusCode: http.StatusConflict,
+			Message:    "Recommendation is being applied",
+			Details:    "workflow " + previous.WorkflowID + " is still running",
+		}
+	}
+
+	trigger, err := h.triggerRequest(&rec, req)
+	if err != nil {
+		return nil, err
+	}
+	// Check the remediation can run before creating its incident, which is removed
+	// again if the trigger is refused later on, e.g. by a policy or the budget
+	if err := h.remediation.checkEnabled(trigger); err != nil {
+		return nil, err
+	}
+	var created string
+	if trigger.IncidentID == "" {
+		incident, err := h.incidentStore.Create(recommendationIncident(&rec, trigger))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create incident for recommendation: %w", err)
+		}
+		trigger.IncidentID = incident.ID
+		created = incident.ID
+	}
+
+	response, err := h.remediation.Trigger(ctx, trigger)
+	if err != nil {
+		if created != "" {
+			if deleteErr := h.incidentStore.Delete(created); deleteErr != nil {
+				h.log.WithError(deleteErr).WithField("incident_id", created).Warn("Failed to delete the incident of a refused recommendation")
+			}
+		}
+		return nil, err
+	}
+
+	application := &RecommendationApplication{
+		RecommendationID: id,
+		Recommendation:   rec,
+		IncidentID:       trigger.IncidentID,
+		Namespace:        trigger.Namespace,
+		Resource:         strings.ToLower(trigger.Resource.Kind) + "/" + trigger.Resource.Name,
+		Status:           response.Status,
+		WorkflowID:       response.WorkflowID,
+		Policy:           response.Policy,
+		Playbook:         response.Playbook,
+		ExecutionID:      response.ExecutionID,
+		Backend:          response.Backend,
+		AppliedBy:        req.AppliedBy,
+		AppliedAt:        h.now().UTC(),
+	}
+	h.ledger.mu.Lock()
+	h.ledger.applications[id] = application
+	h.ledger.mu.Unlock()
+
+	h.log.WithFields(logrus.Fields{
+		"recommendation_id": id,
+		"incident_id":       application.IncidentID,
+		"workflow_id":       application.WorkflowID,
+		"status":            application.Status,
+		"applied_by":        application.AppliedBy,
+	}).Info("Recommendation applied")
+
+	return h.trackApplication(application), nil
+}
+
+// triggerRequest builds the remediation trigger for a recommendation, taking the
+// workload from the recommendation's target or else the request
+func (h *RecommendationsHandler) triggerRequest(rec *Recommendation, req *ApplyRecommendationRequest) (*TriggerRemediationRequest, error) {
+	trigger := &TriggerRemediationRequest{
+		IncidentID: req.IncidentID,
+		Namespace:  rec.Namespace,
+		Force:      req.Force,
+	}
+	if trigger.IncidentID == "" {
+		trigger.IncidentID = rec.RelatedIncidentID
+	}
+
+	if req.Namespace != "" {
+		if rec.Namespace != "" && req.Namespace != rec.Namespace {
+			return nil, &RequestError{
+				StatusCode: http.StatusConflict,
+				Message:    "Recommendation is for another namespace",
+				Details:    fmt.Sprintf("%s is for namespace %s, not %s; request recommendations again", rec.ID, rec.Namespace, req.Namespace),
+			}
+		}
+		trigger.Namespace = req.Namespace
+	}
+
+	if req.Resource != nil {
+		trigger.Resource.Kind = req.Resource.Kind
+		trigger.Resource.Name = req.Resource.Name
+	} else if kind, name, ok := strings.Cut(rec.Target, "/"); ok && remediableKinds[strings.ToLower(kind)] {
+		trigger.Resource.Kind = kind
+		trigger.Resource.Name = name
+	}
+
+	var errs validation.Errors
+	if trigger.Namespace == "" {
+		errs.Add("namespace", validation.ConstraintRequired, nil, "namespace is required for recommendations without one")
+	}
+	if trigger.Resource.Kind == "" || trigger.Resource.Name == "" {
+		errs.Add("resource", validation.ConstraintRequired, nil,
+			fmt.Sprintf("resource is required: target %q is not a pod, deployment, statefulset or daemonset", rec.Target))
+	}
+	if err := errs.Err(); err != nil {
+		return nil, invalidRequest(err, ErrCodeInvalidRequest)
+	}
+
+	trigger.Issue.Type = rec.IssueType
+	trigger.Issue.Severity = rec.Severity
+	trigger.Issue.Description = fmt.Sprintf("Apply recommendation %s: %s", rec.ID, strings.Join(rec.RecommendedActions, ", "))
+	return trigger, nil
+}
+
+// recommendationIncident is the incident created for a recommendation applied
+// without one
+func recommendationIncident(rec *Recommendation, trigger *TriggerRemediationRequest) *models.Incident {
+	severity := models.IncidentSeverity(rec.Severity)
+	if !models.IsValidSeverity(rec.Severity) {
+		severity = models.IncidentSeverityMedium
+	}
+	description := trigger.Issue.Description
+	if len(rec.Evidence) > 0 {
+		description += "\n\n" + strings.Join(rec.Evidence, "\n")
+	}
+	return &models.Incident{
+		Title:             fmt.Sprintf("%s on %s/%s", rec.IssueType, strings.ToLower(trigger.Resource.Kind), trigger.Resource.Name),
+		Description:       description,
+		Severity:          severity,
+		Target:            trigger.Namespace,
+		AffectedResources: []string{strings.ToLower(trigger.Resource.Kind) + "/" + trigger.Resource.Name},
+		Labels:            map[string]string{"source": "recommendation", "recommendation_id": rec.ID},
+		Owner:             rec.Owner,
+	}
+}
+
+// trackApplication updates an application from its workflow and, once the workflow
+// finished, records the outcome of the top recommended action. It returns a copy.
+func (h *RecommendationsHandler) trackApplication(application *RecommendationApplication) *RecommendationApplication {
+	h.ledger.mu.Lock()
+	defer h.ledger.mu.Unlock()
+
+	if application.WorkflowID == "" || application.CompletedAt != nil || h.orchestrator == nil {
+		copied := *application
+		return &copied
+	}
+	workflow, err := h.orchestrator.GetWorkflow(application.WorkflowID)
+	if err != nil {
+		copied := *application
+		return &copied
+	}
+
+	application.Status = string(workflow.Status)
+	for _, step := range workflow.Steps {
+		if step.Name == verifyStep {
+			application.Verification = step.Status
+		}
+	}
+	if !workflow.IsActive() {
+		completedAt := h.now().UTC()
+		if workflow.CompletedAt != nil {
+			completedAt = workflow.CompletedAt.UTC()
+		}
+		application.CompletedAt = &completedAt
+		h.recordApplicationOutcome(application, workflow)
+	}
+
+	copied := *application
+	return &copied
+}
+
+// recordApplicationOutcome records whether the top recommended action of an applied
+// recommendation succeeded: its workflow completed and was not failed by verification
+func (h *RecommendationsHandler) recordApplicationOutcome(application *RecommendationApplication, workflow *models.Workflow) {
+	rec := &application.Recommendation
+	if h.actionRanker == nil || len(rec.RecommendedActions) == 0 {
+		return
+	}
+	outcome, err := h.actionRanker.Record(&models.ActionOutcome{
+		IssueType:        rec.IssueType,
+		Action:           rec.RecommendedActions[0],
+		Success:          workflow.Status == models.WorkflowStatusCompleted && application.Verification != models.StepStatusFailed,
+		Namespace:        application.Namespace,
+		RecommendationID: application.RecommendationID,
+		IncidentID:       application.IncidentID,
+		Source:           "workflow",
+	})
+	if err != nil {
+		h.log.WithError(err).WithField("recommendation_id", application.RecommendationID).Warn("Failed to record applied recommendation outcome")
+		return
+	}
+	application.Outcome = outcome
+}
+
+// TrackApplications checks the workflows of applied recommendations every interval
+// until ctx is cancelled, so their outcomes are recorded without being polled
+func (h *RecommendationsHandler) TrackApplications(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultApplicationTrackingInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			h.ledger.mu.Lock()
+			pending := make([]*RecommendationApplication, 0)
+			for _, application := range h.ledger.applications {
+				if application.WorkflowID != "" && application.CompletedAt == nil {
+					pending = append(pending, application)
+				}
+			}
+			h.ledger.mu.Unlock()
+			for _, application := range pending {
+				h.trackApplication(application)
+			}
+		}
+	}
+}
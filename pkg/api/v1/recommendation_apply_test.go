@@ -0,0 +1,175 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/detector"
+	"github.com/tosin2013/openshift-coordination-engine/internal/knowledge"
+	"github.com/tosin2013/openshift-coordination-engine/internal/remediation"
+	"github.com/tosin2013/openshift-coordination-engine/internal/runtimeconfig"
+	"github.com/tosin2013/openshift-coordination-engine/internal/storage"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+func TestRecommendationsHandler_ApplyRecommendation(t *testing.T) {
+	t.Setenv("DATA_DIR", t.TempDir())
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	orchestrator := remediation.NewOrchestrator(detector.NewDeploymentDetector(fake.NewSimpleClientset(), log), fakeRemediator{}, log)
+	remediationHandler := NewRemediationHandler(orchestrator, log)
+	policies := runtimeconfig.NewStore(t.TempDir())
+	_, err := policies.Create(runtimeconfig.KindPolicy, "deny-frozen", json.RawMessage(
+		`{"match":{"namespaces":["frozen"]},"action":"deny","reason":"change freeze"}`))
+	require.NoError(t, err)
+	remediationHandler.SetPolicies(policies)
+
+	handler := NewRecommendationsHandler(orchestrator, remediationHandler.GetIncidentStore(), nil, log)
+	handler.SetActionRanker(knowledge.NewActionRanker(storage.NewActionOutcomeStore("", 100), 1))
+	handler.SetRemediationHandler(remediationHandler)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v1/recommendations/{id}/apply", handler.ApplyRecommendation).Methods("POST")
+	router.HandleFunc("/api/v1/recommendations/{id}/application", handler.GetRecommendationApplication).Methods("GET")
+	serve := func(method, path, body string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	handler.ledger.serve([]Recommendation{
+		{
+			ID: "rec-pattern-001", Type: "proactive", IssueType: "pod_crash_loop", Target: "deployment/api", Namespace: "payments",
+			Severity: "high", RecommendedActions: []string{"restart_pod", "check_logs"}, Evidence: []string{"api restarted 12 times"},
+		},
+		{ID: "rec-hist-001", IssueType: "oom_kill", Target: "payments", Namespace: "payments", Severity: "medium", RecommendedActions: []string{"increase_memory_limit"}},
+		{ID: "rec-hist-002", IssueType: "oom_kill", Target: "frozen", Namespace: "frozen", Severity: "medium", RecommendedActions: []string{"increase_memory_limit"}},
+	}, time.Now())
+
+	rr := serve(http.MethodPost, "/api/v1/recommendations/rec-pattern-001/apply", `{"applied_by":"alice"}`)
+	require.Equal(t, http.StatusAccepted, rr.Code, rr.Body.String())
+	var application RecommendationApplication
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &application))
+	assert.Equal(t, "deployment/api", application.Resource)
+	assert.Equal(t, "alice", application.AppliedBy)
+	require.NotEmpty(t, application.WorkflowID)
+
+	// Without a related incident, an incident is created for the recommendation
+	incident, err := remediationHandler.GetIncidentStore().Get(application.IncidentID)
+	require.NoError(t, err)
+	assert.Equal(t, "rec-pattern-001", incident.Labels["recommendation_id"])
+	assert.Equal(t, models.IncidentSeverityHigh, incident.Severity)
+
+	workflow, err := orchestrator.GetWorkflow(application.WorkflowID)
+	require.NoError(t, err)
+	assert.Equal(t, "pod_crash_loop", workflow.IssueType)
+
+	// The finished workflow records the outcome of the top recommended action
+	require.Eventually(t, func() bool {
+		rr := serve(http.MethodGet, "/api/v1/recommendations/rec-pattern-001/application", "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &application))
+		return application.CompletedAt != nil
+	}, 5*time.Second, 20*time.Millisecond)
+	assert.Equal(t, string(models.WorkflowStatusCompleted), application.Status)
+	require.NotNil(t, application.Outcome)
+	assert.Equal(t, "restart_pod", application.Outcome.Action)
+	assert.True(t, application.Outcome.Success)
+	assert.Equal(t, "rec-pattern-001", application.Outcome.RecommendationID)
+
+	// Recommendations that do not target a workload need one
+	rr = serve(http.MethodPost, "/api/v1/recommendations/rec-hist-001/apply", "")
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"field":"resource"`)
+	rr = serve(http.MethodPost, "/api/v1/recommendations/rec-hist-001/apply", `{"namespace":"checkout","resource":{"kind":"Deployment","name":"api"}}`)
+	assert.Equal(t, http.StatusConflict, rr.Code, "the namespace must match the recommendation's")
+	rr = serve(http.MethodPost, "/api/v1/recommendations/rec-hist-001/apply", `{"resource":{"kind":"Deployment","name":"api"},"incident_id":"inc-42"}`)
+	require.Equal(t, http.StatusAccepted, rr.Code, rr.Body.String())
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &application))
+	assert.Equal(t, "inc-42", application.IncidentID)
+
+	// Remediation policies apply, and the incident created for a refused recommendation is removed
+	incidents := remediationHandler.GetIncidentStore().Count()
+	rr = serve(http.MethodPost, "/api/v1/recommendations/rec-hist-002/apply", `{"resource":{"kind":"Deployment","name":"api"}}`)
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.Contains(t, rr.Body.String(), "change freeze")
+	assert.Equal(t, incidents, remediationHandler.GetIncidentStore().Count())
+
+	assert.Equal(t, http.StatusNotFound, serve(http.MethodPost, "/api/v1/recommendations/rec-ml-009/apply", "").Code)
+	assert.Equal(t, http.StatusNotFound, serve(http.MethodGet, "/api/v1/recommendations/rec-hist-002/application", "").Code)
+}
+
+func TestRecommendationsHandler_ApplyInObserverMode(t *testing.T) {
+	t.Setenv("DATA_DIR", t.TempDir())
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	orchestrator := remediation.NewOrchestrator(detector.NewDeploymentDetector(fake.NewSimpleClientset(), log), fakeRemediator{}, log)
+	remediationHandler := NewRemediationHandler(orchestrator, log)
+	remediationHandler.SetObserverMode(true)
+	handler := NewRecommendationsHandler(orchestrator, remediationHandler.GetIncidentStore(), nil, log)
+	handler.SetRemediationHandler(remediationHandler)
+	handler.ledger.serve([]Recommendation{
+		{ID: "rec-pattern-001", IssueType: "pod_crash_loop", Target: "deployment/api", Namespace: "payments", Severity: "high", RecommendedActions: []string{"restart_pod"}},
+	}, time.Now())
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v1/recommendations/{id}/apply", handler.ApplyRecommendation).Methods("POST")
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/v1/recommendations/rec-pattern-001/apply", nil))
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.Contains(t, rr.Body.String(), ErrCodeObserverMode)
+	assert.Zero(t, remediationHandler.GetIncidentStore().Count(), "no incident is created for a blocked recommendation")
+}
+
+func TestRecommendationsHandler_ApplyServedRecommendation(t *testing.T) {
+	t.Setenv("DATA_DIR", t.TempDir())
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	orchestrator := remediation.NewOrchestrator(detector.NewDeploymentDetector(fake.NewSimpleClientset(), log), fakeRemediator{}, log)
+	remediationHandler := NewRemediationHandler(orchestrator, log)
+	for i := 0; i < 3; i++ {
+		_, err := remediationHandler.GetIncidentStore().Create(&models.Incident{
+			Title: "Production incident", Description: "Issue in production", Severity: models.IncidentSeverityHigh, Target: "production",
+		})
+		require.NoError(t, err)
+	}
+	handler := NewRecommendationsHandler(nil, remediationHandler.GetIncidentStore(), nil, log)
+
+	// Applying is disabled until a remediation handler is set
+	rr := httptest.NewRecorder()
+	handler.ApplyRecommendation(rr, httptest.NewRequest(http.MethodPost, "/api/v1/recommendations/rec-hist-001/apply", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	handler.SetRemediationHandler(remediationHandler)
+
+	rr = httptest.NewRecorder()
+	handler.GetRecommendations(rr, httptest.NewRequest(http.MethodPost, "/api/v1/recommendations",
+		bytes.NewBufferString(`{"namespace":"production","include_predictions":false,"confidence_threshold":0.5}`)))
+	require.Equal(t, http.StatusOK, rr.Code)
+	var resp GetRecommendationsResponse
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+	require.NotEmpty(t, resp.Recommendations)
+
+	rec := resp.Recommendations[0]
+	var req ApplyRecommendationRequest
+	require.NoError(t, json.Unmarshal([]byte(`{"resource":{"kind":"Deployment","name":"api"}}`), &req))
+	application, err := handler.Apply(context.Background(), rec.ID, &req)
+	require.NoError(t, err)
+	assert.Equal(t, "production", application.Namespace)
+	assert.Equal(t, rec.IssueType, application.Recommendation.IssueType)
+}
@@ -0,0 +1,83 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/advice"
+	"github.com/tosin2013/openshift-coordination-engine/internal/integrations"
+)
+
+// SetActionRules adds the actions of operator-defined rules to the built-in actions of
+// every recommendation, before they are ranked by success rate
+func (h *RecommendationsHandler) SetActionRules(engine *advice.Engine) {
+	h.actionRules = engine
+}
+
+// applyActionRules replaces a recommendation's actions with the result of the action
+// rules and notes the rules that matched in its evidence
+func (h *RecommendationsHandler) applyActionRules(rec *Recommendation, usage *namespaceUsage) {
+	if h.actionRules == nil {
+		return
+	}
+	result := h.actionRules.Evaluate(advice.Subject{
+		IssueType: rec.IssueType,
+		Namespace: rec.Namespace,
+		Severity:  rec.Severity,
+		Metric: func(name string) (float64, bool) {
+			if name == advice.MetricConfidence {
+				return rec.Confidence, true
+			}
+			return usage.get(name, rec.Namespace)
+		},
+	}, rec.RecommendedActions)
+
+	rec.RecommendedActions = result.Actions
+	if len(result.Matched) > 0 {
+		rec.Evidence = append(rec.Evidence, fmt.Sprintf("Matched recommendation rules: %s", strings.Join(result.Matched, ", ")))
+	}
+}
+
+// namespaceUsage reads the CPU and memory utilization of namespaces for action rule
+// conditions. Values are queried on first use and kept for the rest of the request.
+type namespaceUsage struct {
+	ctx    context.Context
+	client *integrations.PrometheusClient
+	values map[string]usageValue
+}
+
+type usageValue struct {
+	value float64
+	ok    bool
+}
+
+func newNamespaceUsage(ctx context.Context, client *integrations.PrometheusClient) *namespaceUsage {
+	return &namespaceUsage{ctx: ctx, client: client, values: make(map[string]usageValue)}
+}
+
+// get returns a utilization metric of a namespace, or of the cluster for an empty one
+func (u *namespaceUsage) get(metric, namespace string) (float64, bool) {
+	if u.client == nil || !u.client.IsAvailable() {
+		return 0, false
+	}
+	key := metric + "|" + namespace
+	if cached, ok := u.values[key]; ok {
+		return cached.value, cached.ok
+	}
+
+	var (
+		value float64
+		err   error
+	)
+	switch metric {
+	case advice.MetricCPUUtilization:
+		value, err = u.client.GetScopedCPURollingMean(u.ctx, namespace, "", "")
+	case advice.MetricMemoryUtilization:
+		value, err = u.client.GetScopedMemoryRollingMean(u.ctx, namespace, "", "")
+	default:
+		return 0, false
+	}
+	u.values[key] = usageValue{value: value, ok: err == nil}
+	return value, err == nil
+}
@@ -4,6 +4,7 @@ package v1
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
@@ -11,11 +12,20 @@ import (
 
 	"github.com/sirupsen/logrus"
 
+	"github.com/tosin2013/openshift-coordination-engine/internal/advice"
+	"github.com/tosin2013/openshift-coordination-engine/internal/imagescan"
 	"github.com/tosin2013/openshift-coordination-engine/internal/integrations"
+	"github.com/tosin2013/openshift-coordination-engine/internal/knowledge"
+	"github.com/tosin2013/openshift-coordination-engine/internal/ownership"
+	"github.com/tosin2013/openshift-coordination-engine/internal/platform"
 	"github.com/tosin2013/openshift-coordination-engine/internal/remediation"
 	"github.com/tosin2013/openshift-coordination-engine/internal/storage"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/api/validation"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/capacity"
 	"github.com/tosin2013/openshift-coordination-engine/pkg/kserve"
 	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/quantity"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/runbook"
 )
 
 // RecommendationsHandler handles ML-powered remediation recommendations API requests
@@ -24,8 +34,40 @@ type RecommendationsHandler struct {
 	incidentStore    *storage.IncidentStore
 	kserveClient     *kserve.ProxyClient
 	prometheusClient *integrations.PrometheusClient
+	runbooks         *runbook.Registry
+	capacityAnalyzer *capacity.Analyzer
+	actionRanker     *knowledge.ActionRanker
+	now              func() time.Time
 	log              *logrus.Logger
 
+	// Certificates expiring within certificateWarning are recommended for renewal
+	certificates       *platform.CertificateScanner
+	certificateWarning time.Duration
+
+	// An etcd database forecast to reach its backend quota within etcdWarning is
+	// recommended for compaction and defragmentation. etcdQuota is assumed when etcd
+	// does not export its quota.
+	etcdQuota   quantity.Bytes
+	etcdWarning time.Duration
+
+	// Sustained scheduler pressure is recommended for node scaling, taint review or
+	// priority class changes depending on why pods cannot be scheduled
+	schedulerPressure *integrations.SchedulerPressureAnalyzer
+
+	// Crashing containers whose image has critical vulnerabilities are recommended for
+	// an image update
+	images *imagescan.Scanner
+
+	// Recommendations in a namespace are attributed to the team owning their target
+	owners *ownership.Resolver
+
+	// Operator-defined rules add organization-specific actions to the built-in ones
+	actionRules *advice.Engine
+
+	// Served recommendations are kept in the ledger to be applied through remediation
+	remediation *RemediationHandler
+	ledger      *recommendationLedger
+
 	// Default values when Prometheus is not available
 	defaultCPURollingMean    float64
 	defaultMemoryRollingMean float64
@@ -44,11 +86,19 @@ func NewRecommendationsHandler(
 		kserveClient:             kserveClient,
 		prometheusClient:         nil, // Optional, set via SetPrometheusClient
 		log:                      log,
+		now:                      time.Now,
+		ledger:                   newRecommendationLedger(),
 		defaultCPURollingMean:    0.65, // 65% average CPU usage
 		defaultMemoryRollingMean: 0.72, // 72% average memory usage
 	}
 }
 
+// SetClock replaces the clock of result timestamps and evaluation times, e.g. with
+// the fixed clock of deterministic mode
+func (h *RecommendationsHandler) SetClock(now func() time.Time) {
+	h.now = now
+}
+
 // SetPrometheusClient sets the Prometheus client for real metrics querying
 func (h *RecommendationsHandler) SetPrometheusClient(client *integrations.PrometheusClient) {
 	h.prometheusClient = client
@@ -57,12 +107,63 @@ func (h *RecommendationsHandler) SetPrometheusClient(client *integrations.Promet
 	}
 }
 
+// SetRunbooks links recommendations to runbooks for their issue type
+func (h *RecommendationsHandler) SetRunbooks(registry *runbook.Registry) {
+	h.runbooks = registry
+}
+
+// SetCapacityAnalyzer enables node bin-packing recommendations (descheduler runs and
+// node pool changes)
+func (h *RecommendationsHandler) SetCapacityAnalyzer(analyzer *capacity.Analyzer) {
+	h.capacityAnalyzer = analyzer
+}
+
+// SetCertificateScanner enables preventive recommendations to renew TLS certificates
+// that expire within warning
+func (h *RecommendationsHandler) SetCertificateScanner(scanner *platform.CertificateScanner, warning time.Duration) {
+	h.certificates = scanner
+	h.certificateWarning = warning
+}
+
+// SetEtcdForecast enables critical recommendations to relieve etcd when its database is
+// forecast from Prometheus to reach its backend quota within warning
+func (h *RecommendationsHandler) SetEtcdForecast(quota quantity.Bytes, warning time.Duration) {
+	h.etcdQuota = quota
+	h.etcdWarning = warning
+}
+
+// SetSchedulerPressure enables recommendations for pods that stay pending and
+// unschedulable, targeted at the dominant reason the scheduler gives
+func (h *RecommendationsHandler) SetSchedulerPressure(analyzer *integrations.SchedulerPressureAnalyzer) {
+	h.schedulerPressure = analyzer
+}
+
+// SetImageScanner enables update_image recommendations for containers that keep
+// crashing on images with critical vulnerabilities
+func (h *RecommendationsHandler) SetImageScanner(scanner *imagescan.Scanner) {
+	h.images = scanner
+}
+
+// SetOwnerResolver attributes recommendations to the team owning their target
+func (h *RecommendationsHandler) SetOwnerResolver(resolver *ownership.Resolver) {
+	h.owners = resolver
+}
+
+// SetActionRanker orders recommended actions by their verified success rates for the
+// issue type and enables /api/v1/recommendations/outcomes
+func (h *RecommendationsHandler) SetActionRanker(ranker *knowledge.ActionRanker) {
+	h.actionRanker = ranker
+}
+
 // GetRecommendationsRequest represents the request body for getting recommendations
 type GetRecommendationsRequest struct {
 	Timeframe           string  `json:"timeframe"`            // "1h", "6h", "24h" (default: "6h")
 	IncludePredictions  *bool   `json:"include_predictions"`  // Include ML predictions (default: true)
 	ConfidenceThreshold float64 `json:"confidence_threshold"` // Minimum confidence 0.0-1.0 (default: 0.7)
-	Namespace           string  `json:"namespace"`            // Optional: filter by namespace
+
+	// Optional: scope to filter by (default: cluster-wide). Recommendations are grouped by
+	// namespace, so a deployment or pod scope filters by its namespace.
+	models.Scope
 }
 
 // Recommendation represents a single remediation recommendation
@@ -79,6 +180,15 @@ type Recommendation struct {
 	Evidence           []string `json:"evidence"`
 	Source             string   `json:"source,omitempty"`
 	RelatedIncidentID  string   `json:"related_incident_id,omitempty"`
+
+	// ActionSuccessRates are the verified success rates of the recommended actions with
+	// enough outcomes in this cluster, in recommended order
+	ActionSuccessRates []knowledge.ActionSuccessRate `json:"action_success_rates,omitempty"`
+
+	Runbooks []runbook.Runbook `json:"runbooks,omitempty"`
+
+	// Owner is the team owning the target, from namespace and workload annotations
+	Owner *models.Owner `json:"owner,omitempty"`
 }
 
 // GetRecommendationsResponse represents the response for getting recommendations
@@ -93,17 +203,51 @@ type GetRecommendationsResponse struct {
 }
 
 // GetRecommendations handles POST /api/v1/recommendations
+// @Summary Get remediation recommendations
+// @Description Gathers recommendations from historical incidents, ML predictions and known failure patterns, filtered by confidence and scope
+// @Tags recommendations
+// @Accept json
+// @Produce json
+// @Param request body GetRecommendationsRequest true "Recommendations request"
+// @Success 200 {object} GetRecommendationsResponse
+// @Failure 400 {object} ErrorResponse
+// @Router /api/v1/recommendations [post]
 func (h *RecommendationsHandler) GetRecommendations(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
 	h.log.Info("Received get recommendations request")
 
-	// Parse and validate request
-	req, err := h.parseAndValidateRequest(r)
+	var req GetRecommendationsRequest
+	if r.ContentLength > 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.log.WithError(err).Debug("Failed to decode request body")
+			h.respondError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err), validation.DecodeFields(err)...)
+			return
+		}
+	}
+
+	response, err := h.Recommend(r.Context(), &req)
 	if err != nil {
-		h.respondError(w, http.StatusBadRequest, err.Error())
+		var requestErr *RequestError
+		if errors.As(err, &requestErr) {
+			h.respondError(w, requestErr.StatusCode, requestErr.Message, requestErr.Errors...)
+			return
+		}
+		h.respondError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
+	h.respondJSON(w, http.StatusOK, response)
+}
+
+// Recommend applies defaults, validates the request and gathers recommendations from
+// historical incidents, ML predictions and known patterns. It is shared by the REST
+// handler and the gRPC server.
+func (h *RecommendationsHandler) Recommend(ctx context.Context, req *GetRecommendationsRequest) (*GetRecommendationsResponse, error) {
+	h.setRequestDefaults(req)
+	if err := h.validateRequest(req); err != nil {
+		return nil, invalidRequest(err, ErrCodeInvalidRequest)
+	}
+	req.Scope = req.Scope.Resolve()
+
 	h.log.WithFields(logrus.Fields{
 		"timeframe":            req.Timeframe,
 		"include_predictions":  *req.IncludePredictions,
@@ -114,46 +258,38 @@ func (h *RecommendationsHandler) GetRecommendations(w http.ResponseWriter, r *ht
 	// Collect and filter recommendations
 	recommendations, mlEnabled := h.collectRecommendations(ctx, req)
 	filteredRecs := h.filterRecommendations(recommendations, req)
-
-	// Build and send response
-	h.sendRecommendationsResponse(w, req, filteredRecs, mlEnabled)
-}
-
-// parseAndValidateRequest parses the request body and validates parameters
-func (h *RecommendationsHandler) parseAndValidateRequest(r *http.Request) (*GetRecommendationsRequest, error) {
-	var req GetRecommendationsRequest
-
-	if r.ContentLength > 0 {
-		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			h.log.WithError(err).Debug("Failed to decode request body")
-			return nil, fmt.Errorf("invalid request body: %w", err)
+	usage := newNamespaceUsage(ctx, h.prometheusClient)
+	for i := range filteredRecs {
+		filteredRecs[i].Runbooks = h.runbooks.Lookup(filteredRecs[i].IssueType, "")
+		h.applyActionRules(&filteredRecs[i], usage)
+		h.rankActions(&filteredRecs[i])
+		if h.owners != nil {
+			filteredRecs[i].Owner = h.owners.Resolve(ctx, filteredRecs[i].Namespace, filteredRecs[i].Target)
 		}
 	}
 
-	// Set defaults
-	if req.Timeframe == "" {
-		req.Timeframe = "6h"
-	}
+	h.ledger.serve(filteredRecs, h.now())
+	return h.buildRecommendationsResponse(req, filteredRecs, mlEnabled), nil
+}
+
+// setRequestDefaults fills in default values for omitted request fields
+func (h *RecommendationsHandler) setRequestDefaults(req *GetRecommendationsRequest) {
+	validation.Default(&req.Timeframe, "6h")
 	if req.IncludePredictions == nil {
 		defaultTrue := true
 		req.IncludePredictions = &defaultTrue
 	}
-	if req.ConfidenceThreshold == 0 {
-		req.ConfidenceThreshold = 0.7
-	}
-
-	// Validate timeframe
-	validTimeframes := map[string]bool{"1h": true, "6h": true, "24h": true}
-	if !validTimeframes[req.Timeframe] {
-		return nil, fmt.Errorf("invalid timeframe: must be '1h', '6h', or '24h'")
-	}
+	validation.Default(&req.ConfidenceThreshold, validation.DefaultThreshold)
+}
 
-	// Validate confidence threshold
-	if req.ConfidenceThreshold < 0 || req.ConfidenceThreshold > 1 {
-		return nil, fmt.Errorf("invalid confidence_threshold: must be between 0.0 and 1.0")
+// validateRequest validates the recommendations request parameters
+func (h *RecommendationsHandler) validateRequest(req *GetRecommendationsRequest) error {
+	rules := []validation.Rule{
+		validation.OneOf("timeframe", req.Timeframe, "1h", "6h", "24h"),
+		validation.Threshold("confidence_threshold", req.ConfidenceThreshold),
 	}
-
-	return &req, nil
+	rules = append(rules, req.Scope.Rules()...)
+	return validation.Check(rules...)
 }
 
 // collectRecommendations gathers recommendations from all sources
@@ -181,9 +317,43 @@ func (h *RecommendationsHandler) collectRecommendations(ctx context.Context, req
 	patternRecs := h.getPatternRecommendations()
 	recommendations = append(recommendations, patternRecs...)
 
+	// Get node bin-packing recommendations
+	capacityRecs := h.getCapacityRecommendations(ctx)
+	recommendations = append(recommendations, capacityRecs...)
+
+	// Get certificate renewal recommendations
+	certificateRecs := h.getCertificateRecommendations(ctx)
+	recommendations = append(recommendations, certificateRecs...)
+
+	// Get etcd quota exhaustion recommendations
+	etcdRecs := h.getEtcdRecommendations(ctx)
+	recommendations = append(recommendations, etcdRecs...)
+
+	// Get scheduler pressure recommendations
+	schedulerRecs := h.getSchedulerRecommendations(ctx)
+	recommendations = append(recommendations, schedulerRecs...)
+
+	// Get image update recommendations
+	imageRecs := h.getImageRecommendations(ctx, req.Namespace)
+	recommendations = append(recommendations, imageRecs...)
+
 	return recommendations, mlEnabled
 }
 
+// rankActions orders a recommendation's actions by their verified success rates and
+// adds the best known one to its evidence
+func (h *RecommendationsHandler) rankActions(rec *Recommendation) {
+	if h.actionRanker == nil {
+		return
+	}
+	rec.RecommendedActions, rec.ActionSuccessRates = h.actionRanker.Rank(rec.IssueType, rec.RecommendedActions)
+	if len(rec.ActionSuccessRates) > 0 {
+		best := rec.ActionSuccessRates[0]
+		rec.Evidence = append(rec.Evidence, fmt.Sprintf("%s succeeded %.0f%% of %d verified attempts for %s in this cluster",
+			best.Action, best.SuccessRate*100, best.Attempts, rec.IssueType))
+	}
+}
+
 // filterRecommendations filters recommendations by confidence and namespace
 func (h *RecommendationsHandler) filterRecommendations(recommendations []Recommendation, req *GetRecommendationsRequest) []Recommendation {
 	filteredRecs := make([]Recommendation, 0, len(recommendations))
@@ -200,11 +370,11 @@ func (h *RecommendationsHandler) filterRecommendations(recommendations []Recomme
 	return filteredRecs
 }
 
-// sendRecommendationsResponse builds and sends the response
-func (h *RecommendationsHandler) sendRecommendationsResponse(w http.ResponseWriter, req *GetRecommendationsRequest, filteredRecs []Recommendation, mlEnabled bool) {
+// buildRecommendationsResponse builds the response for the filtered recommendations
+func (h *RecommendationsHandler) buildRecommendationsResponse(req *GetRecommendationsRequest, filteredRecs []Recommendation, mlEnabled bool) *GetRecommendationsResponse {
 	response := GetRecommendationsResponse{
 		Status:               "success",
-		Timestamp:            time.Now().UTC().Format(time.RFC3339),
+		Timestamp:            h.now().UTC().Format(time.RFC3339),
 		Timeframe:            req.Timeframe,
 		Recommendations:      filteredRecs,
 		TotalRecommendations: len(filteredRecs),
@@ -221,7 +391,7 @@ func (h *RecommendationsHandler) sendRecommendationsResponse(w http.ResponseWrit
 		"timeframe":             req.Timeframe,
 	}).Info("Recommendations generated successfully")
 
-	h.respondJSON(w, http.StatusOK, response)
+	return &response
 }
 
 // getHistoricalRecommendations analyzes historical incidents to generate recommendations
@@ -300,7 +470,7 @@ func (h *RecommendationsHandler) getMLPredictions(ctx context.Context, req *GetR
 	}
 
 	// Get current time for temporal features
-	currentTime := time.Now()
+	currentTime := h.now()
 
 	// Prepare input features matching model training order:
 	// [hour_of_day, day_of_week, cpu_rolling_mean, memory_rolling_mean]
@@ -574,6 +744,292 @@ func (h *RecommendationsHandler) getPatternRecommendations() []Recommendation {
 	return recommendations
 }
 
+// nodeRecommendationConfidence is the confidence of node bin-packing recommendations,
+// which are computed from the scheduler's own accounting rather than predicted
+const nodeRecommendationConfidence = 0.85
+
+// getCapacityRecommendations turns node bin-packing suggestions into cluster-scoped
+// recommendations
+func (h *RecommendationsHandler) getCapacityRecommendations(ctx context.Context) []Recommendation {
+	recommendations := make([]Recommendation, 0)
+
+	if h.capacityAnalyzer == nil {
+		return recommendations
+	}
+
+	analysis, err := h.capacityAnalyzer.AnalyzeNodes(ctx, capacity.DefaultReferencePod)
+	if err != nil {
+		h.log.WithError(err).Warn("Node capacity analysis failed, skipping bin-packing recommendations")
+		return recommendations
+	}
+
+	for i, suggestion := range analysis.Suggestions {
+		recommendations = append(recommendations, Recommendation{
+			ID:                 fmt.Sprintf("rec-capacity-%03d", i+1),
+			Type:               "proactive",
+			IssueType:          suggestion.IssueType,
+			Target:             "cluster-nodes",
+			Severity:           suggestion.Severity,
+			Confidence:         nodeRecommendationConfidence,
+			RecommendedActions: suggestion.Actions,
+			Evidence:           suggestion.Evidence,
+			Source:             "capacity_analysis",
+		})
+	}
+
+	return recommendations
+}
+
+// certificateRecommendationConfidence is the confidence of certificate renewal
+// recommendations, whose expiry is read from the certificates themselves
+const certificateRecommendationConfidence = 0.95
+
+// getCertificateRecommendations recommends renewing TLS certificates before they expire
+func (h *RecommendationsHandler) getCertificateRecommendations(ctx context.Context) []Recommendation {
+	recommendations := make([]Recommendation, 0)
+
+	if h.certificates == nil {
+		return recommendations
+	}
+
+	expiring, err := h.certificates.Expiring(ctx, h.certificateWarning)
+	if err != nil {
+		h.log.WithError(err).Warn("TLS secret scan failed, skipping certificate recommendations")
+		return recommendations
+	}
+
+	for i, certificate := range expiring {
+		evidence := []string{fmt.Sprintf("certificate expires %s (%d days)",
+			certificate.NotAfter.Format(time.RFC3339), certificate.DaysRemaining)}
+		if certificate.Expired() {
+			evidence[0] = fmt.Sprintf("certificate expired %s", certificate.NotAfter.Format(time.RFC3339))
+		}
+		if certificate.Subject != "" {
+			evidence = append(evidence, "subject: "+certificate.Subject)
+		}
+		if len(certificate.DNSNames) > 0 {
+			evidence = append(evidence, "dns names: "+strings.Join(certificate.DNSNames, ", "))
+		}
+
+		actions := getRecommendedActions("certificate_expiry")
+		if certificate.ServingService != "" {
+			// The service CA operator issues a new certificate when the secret is deleted
+			evidence = append(evidence, "service serving certificate of service "+certificate.ServingService)
+			actions = []string{"delete_secret_to_regenerate_serving_certificate", "restart_pods_mounting_secret"}
+		}
+
+		recommendations = append(recommendations, Recommendation{
+			ID:                 fmt.Sprintf("rec-certificate-%03d", i+1),
+			Type:               "proactive",
+			IssueType:          "certificate_expiry",
+			Target:             "secret/" + certificate.Secret,
+			Namespace:          certificate.Namespace,
+			Severity:           certificateSeverity(certificate.DaysRemaining),
+			Confidence:         certificateRecommendationConfidence,
+			PredictedTime:      certificate.NotAfter.Format(time.RFC3339),
+			RecommendedActions: actions,
+			Evidence:           evidence,
+			Source:             "certificate_scan",
+		})
+	}
+
+	return recommendations
+}
+
+// certificateSeverity maps the days left before a certificate expires to a severity
+func certificateSeverity(daysRemaining int) string {
+	switch {
+	case daysRemaining < 0:
+		return "critical"
+	case daysRemaining <= 7:
+		return "high"
+	case daysRemaining <= 14:
+		return "medium"
+	default:
+		return "low"
+	}
+}
+
+// etcdRecommendationConfidence is the confidence of etcd quota recommendations, which
+// extrapolate a week of database growth
+const etcdRecommendationConfidence = 0.8
+
+// getEtcdRecommendations recommends compacting and defragmenting etcd when its database
+// is forecast to reach its backend quota, after which the API server cannot write
+func (h *RecommendationsHandler) getEtcdRecommendations(ctx context.Context) []Recommendation {
+	recommendations := make([]Recommendation, 0)
+
+	if h.etcdWarning <= 0 || h.prometheusClient == nil || !h.prometheusClient.IsAvailable() {
+		return recommendations
+	}
+
+	forecast, err := platform.ForecastEtcd(ctx, h.prometheusClient, h.etcdQuota)
+	if err != nil {
+		h.log.WithError(err).Debug("etcd forecast unavailable, skipping etcd recommendations")
+		return recommendations
+	}
+	if !forecast.ExhaustsWithin(h.etcdWarning) {
+		return recommendations
+	}
+
+	quotaSource := "configured quota"
+	if forecast.QuotaSource == platform.EtcdQuotaFromMetric {
+		quotaSource = "etcd_server_quota_backend_bytes"
+	}
+	evidence := []string{fmt.Sprintf("etcd database %s of %s quota (%.0f%%, %s)",
+		forecast.DBSize, forecast.Quota, forecast.DBSize.Float64()/forecast.Quota.Float64()*100, quotaSource)}
+	actions := getRecommendedActions("etcd_quota_exhaustion")
+	if forecast.DBSize >= forecast.Quota {
+		evidence = append(evidence, "quota reached: etcd raises a NOSPACE alarm and only accepts reads and deletes")
+		actions = append(actions, "disarm_etcd_nospace_alarm")
+	} else {
+		evidence = append(evidence, fmt.Sprintf("growing %s per day over %s, quota reached in %.0f days",
+			quantity.Bytes(forecast.GrowthPerDay), platform.EtcdGrowthWindow, *forecast.DaysUntilQuota))
+	}
+	if reclaimable := forecast.Reclaimable(); reclaimable > 0 {
+		evidence = append(evidence, fmt.Sprintf("defragmentation would reclaim %s of freed pages", reclaimable))
+	}
+	evidence = append(evidence,
+		"compact the keyspace to the current revision, then defragment one member at a time with the leader last",
+		"growth that returns after defragmentation comes from object churn, e.g. events, secrets or custom resources")
+
+	recommendations = append(recommendations, Recommendation{
+		ID:                 "rec-etcd-001",
+		Type:               "proactive",
+		IssueType:          "etcd_quota_exhaustion",
+		Target:             "etcd",
+		Namespace:          "openshift-etcd",
+		Severity:           "critical",
+		Confidence:         etcdRecommendationConfidence,
+		PredictedTime:      forecast.ExhaustsAt.Format(time.RFC3339),
+		RecommendedActions: actions,
+		Evidence:           evidence,
+		Source:             "etcd_forecast",
+	})
+
+	return recommendations
+}
+
+// unexplainedSchedulerConfidence is the confidence of scheduler pressure recommendations
+// made without FailedScheduling events to explain the pressure
+const unexplainedSchedulerConfidence = 0.5
+
+// getSchedulerRecommendations recommends node scaling, taint review or priority class
+// changes when pods stay pending and unschedulable, depending on the reason the
+// scheduler gives for most of them
+func (h *RecommendationsHandler) getSchedulerRecommendations(ctx context.Context) []Recommendation {
+	recommendations := make([]Recommendation, 0)
+
+	if h.schedulerPressure == nil {
+		return recommendations
+	}
+
+	pressure, err := h.schedulerPressure.Analyze(ctx)
+	if err != nil {
+		h.log.WithError(err).Debug("Scheduler pressure unavailable, skipping scheduler recommendations")
+		return recommendations
+	}
+	if !pressure.Sustained {
+		return recommendations
+	}
+
+	evidence := []string{fmt.Sprintf("at least %d pods pending and %d unschedulable throughout the last %s",
+		pressure.PendingPods, pressure.UnschedulablePods, pressure.Window)}
+	confidence := unexplainedSchedulerConfidence
+	actions := integrations.SchedulerPressureActions(integrations.UnschedulableOther)
+	if dominant := pressure.Dominant(); dominant != nil {
+		confidence = float64(dominant.Pods) / float64(pressure.FailedPods)
+		actions = pressure.Actions
+		evidence = append(evidence, fmt.Sprintf("dominant reason %s: %q for %d pods, ruling out %d nodes",
+			dominant.Reason, dominant.Detail, dominant.Pods, dominant.Nodes))
+		for _, reason := range pressure.Reasons {
+			if reason.Reason != dominant.Reason {
+				evidence = append(evidence, fmt.Sprintf("also %s: %q for %d pods", reason.Reason, reason.Detail, reason.Pods))
+			}
+		}
+	} else {
+		evidence = append(evidence, "no FailedScheduling events explain the pressure")
+	}
+
+	recommendations = append(recommendations, Recommendation{
+		ID:                 "rec-scheduler-001",
+		Type:               "reactive",
+		IssueType:          "scheduler_pressure",
+		Target:             "scheduler",
+		Namespace:          "openshift-kube-scheduler",
+		Severity:           "high",
+		Confidence:         confidence,
+		RecommendedActions: actions,
+		Evidence:           evidence,
+		Source:             "scheduler_pressure",
+	})
+
+	return recommendations
+}
+
+// imageRecommendationConfidence is the confidence of image update recommendations: the
+// crashes coincide with the vulnerabilities but need not be caused by them
+const imageRecommendationConfidence = 0.7
+
+// maxEvidenceVulnerabilities is how many critical vulnerabilities an image
+// recommendation names
+const maxEvidenceVulnerabilities = 5
+
+// getImageRecommendations recommends updating the images of containers that keep
+// crashing when their image has known critical vulnerabilities
+func (h *RecommendationsHandler) getImageRecommendations(ctx context.Context, namespace string) []Recommendation {
+	recommendations := make([]Recommendation, 0)
+
+	if h.images == nil {
+		return recommendations
+	}
+
+	crashes, err := h.images.CrashingVulnerableImages(ctx, namespace)
+	if err != nil {
+		h.log.WithError(err).Debug("Image scans unavailable, skipping image recommendations")
+		return recommendations
+	}
+
+	for i, crash := range crashes {
+		ids := crash.Report.CriticalIDs()
+		named := ids
+		if len(named) > maxEvidenceVulnerabilities {
+			named = named[:maxEvidenceVulnerabilities]
+		}
+		evidence := []string{
+			fmt.Sprintf("container %s restarted %d times across %d pods (%s)", crash.Container, crash.Restarts, len(crash.Pods), crash.Reason),
+			fmt.Sprintf("image %s has %d critical vulnerabilities: %s", crash.Report.Image, len(ids), strings.Join(named, ", ")),
+		}
+		fixed := make([]string, 0)
+		for _, vuln := range crash.Report.Critical {
+			if vuln.FixedBy != "" {
+				fixed = append(fixed, fmt.Sprintf("%s %s (%s)", vuln.Package, vuln.FixedBy, vuln.ID))
+			}
+		}
+		if len(fixed) > 0 {
+			if len(fixed) > maxEvidenceVulnerabilities {
+				fixed = fixed[:maxEvidenceVulnerabilities]
+			}
+			evidence = append(evidence, "fixed in "+strings.Join(fixed, ", "))
+		}
+
+		recommendations = append(recommendations, Recommendation{
+			ID:                 fmt.Sprintf("rec-image-%03d", i+1),
+			Type:               "reactive",
+			IssueType:          "vulnerable_image_crash",
+			Target:             "container/" + crash.Container,
+			Namespace:          crash.Namespace,
+			Severity:           "high",
+			Confidence:         imageRecommendationConfidence,
+			RecommendedActions: getRecommendedActions("vulnerable_image_crash"),
+			Evidence:           evidence,
+			Source:             "image_scan",
+		})
+	}
+
+	return recommendations
+}
+
 // parseKeyParts splits a "type:namespace" key into its components
 func parseKeyParts(key string) (issueType, namespace string) {
 	if key == "" {
@@ -636,6 +1092,21 @@ func getRecommendedActions(issueType string) []string {
 			"increase_resources",
 			"review_deployment_config",
 		},
+		"certificate_expiry": {
+			"renew_certificate",
+			"update_tls_secret",
+			"verify_automated_renewal",
+		},
+		"vulnerable_image_crash": {
+			"update_image",
+			"rebuild_image_with_fixed_packages",
+			"review_crash_logs",
+		},
+		"etcd_quota_exhaustion": {
+			"compact_etcd_revisions",
+			"defragment_etcd_members",
+			"prune_high_churn_resources",
+		},
 		"critical": {
 			"immediate_investigation",
 			"scale_resources",
@@ -680,6 +1151,108 @@ func interpretPrediction(instanceIndex int) string {
 	return "resource_issue"
 }
 
+// RecordActionOutcomeRequest reports whether a recommended action resolved an issue
+type RecordActionOutcomeRequest struct {
+	IssueType        string `json:"issue_type"`
+	Action           string `json:"action"`
+	Success          *bool  `json:"success"`
+	Namespace        string `json:"namespace,omitempty"`
+	RecommendationID string `json:"recommendation_id,omitempty"`
+	IncidentID       string `json:"incident_id,omitempty"`
+	Source           string `json:"source,omitempty"`
+}
+
+// ActionOutcomesResponse lists the verified success rates of actions per issue type
+type ActionOutcomesResponse struct {
+	IssueTypes []IssueTypeActionRates `json:"issue_types"`
+}
+
+// IssueTypeActionRates are the success rates of the actions tried for an issue type,
+// most successful first
+type IssueTypeActionRates struct {
+	IssueType string                        `json:"issue_type"`
+	Actions   []knowledge.ActionSuccessRate `json:"actions"`
+}
+
+// RecordActionOutcomeResponse is the stored outcome and the updated success rates of
+// its issue type
+type RecordActionOutcomeResponse struct {
+	Outcome *models.ActionOutcome         `json:"outcome"`
+	Actions []knowledge.ActionSuccessRate `json:"actions"`
+}
+
+// RecordActionOutcome handles POST /api/v1/recommendations/outcomes
+func (h *RecommendationsHandler) RecordActionOutcome(w http.ResponseWriter, r *http.Request) {
+	if h.actionRanker == nil {
+		h.respondError(w, http.StatusServiceUnavailable, "Action ranking not enabled (set ACTION_RANKING_ENABLED)")
+		return
+	}
+
+	var req RecordActionOutcomeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err), validation.DecodeFields(err)...)
+		return
+	}
+	if req.Success == nil {
+		h.respondError(w, http.StatusBadRequest, "success is required",
+			validation.FieldError{Field: "success", Constraint: validation.ConstraintRequired, Message: "success is required"})
+		return
+	}
+	validation.Default(&req.Source, "operator")
+
+	outcome, err := h.actionRanker.Record(&models.ActionOutcome{
+		IssueType:        req.IssueType,
+		Action:           req.Action,
+		Success:          *req.Success,
+		Namespace:        req.Namespace,
+		RecommendationID: req.RecommendationID,
+		IncidentID:       req.IncidentID,
+		Source:           req.Source,
+	})
+	if err != nil {
+		if fieldErrs := validation.Fields(err); len(fieldErrs) > 0 {
+			h.respondError(w, http.StatusBadRequest, err.Error(), fieldErrs...)
+			return
+		}
+		h.log.WithError(err).Error("Failed to record action outcome")
+		h.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.log.WithFields(logrus.Fields{
+		"issue_type": outcome.IssueType,
+		"action":     outcome.Action,
+		"success":    outcome.Success,
+	}).Info("Action outcome recorded")
+
+	h.respondJSON(w, http.StatusCreated, RecordActionOutcomeResponse{
+		Outcome: outcome,
+		Actions: h.actionRanker.Stats(outcome.IssueType),
+	})
+}
+
+// GetActionOutcomes handles GET /api/v1/recommendations/outcomes
+func (h *RecommendationsHandler) GetActionOutcomes(w http.ResponseWriter, r *http.Request) {
+	if h.actionRanker == nil {
+		h.respondError(w, http.StatusServiceUnavailable, "Action ranking not enabled (set ACTION_RANKING_ENABLED)")
+		return
+	}
+
+	issueTypes := h.actionRanker.IssueTypes()
+	if issueType := r.URL.Query().Get("issue_type"); issueType != "" {
+		issueTypes = []string{issueType}
+	}
+
+	response := ActionOutcomesResponse{IssueTypes: make([]IssueTypeActionRates, 0, len(issueTypes))}
+	for _, issueType := range issueTypes {
+		response.IssueTypes = append(response.IssueTypes, IssueTypeActionRates{
+			IssueType: issueType,
+			Actions:   h.actionRanker.Stats(issueType),
+		})
+	}
+	h.respondJSON(w, http.StatusOK, response)
+}
+
 // respondJSON writes a JSON response
 func (h *RecommendationsHandler) respondJSON(w http.ResponseWriter, statusCode int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
@@ -691,11 +1264,14 @@ func (h *RecommendationsHandler) respondJSON(w http.ResponseWriter, statusCode i
 }
 
 // respondError writes an error response
-func (h *RecommendationsHandler) respondError(w http.ResponseWriter, statusCode int, message string) {
+func (h *RecommendationsHandler) respondError(w http.ResponseWriter, statusCode int, message string, fieldErrs ...validation.FieldError) {
 	response := map[string]interface{}{
 		"status": "error",
 		"error":  message,
 	}
+	if len(fieldErrs) > 0 {
+		response["errors"] = fieldErrs
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
@@ -2,20 +2,36 @@ package v1
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
-
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/advice"
+	"github.com/tosin2013/openshift-coordination-engine/internal/imagescan"
+	"github.com/tosin2013/openshift-coordination-engine/internal/integrations"
+	"github.com/tosin2013/openshift-coordination-engine/internal/knowledge"
+	"github.com/tosin2013/openshift-coordination-engine/internal/ownership"
+	"github.com/tosin2013/openshift-coordination-engine/internal/platform"
 	"github.com/tosin2013/openshift-coordination-engine/internal/storage"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/capacity"
 	"github.com/tosin2013/openshift-coordination-engine/pkg/kserve"
 	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/quantity"
 )
 
 func TestRecommendationsHandler_GetRecommendations(t *testing.T) {
@@ -99,7 +115,7 @@ func TestRecommendationsHandler_GetRecommendations(t *testing.T) {
 		var resp map[string]interface{}
 		json.NewDecoder(w.Body).Decode(&resp)
 		assert.Equal(t, "error", resp["status"])
-		assert.Contains(t, resp["error"], "invalid timeframe")
+		assert.Contains(t, resp["error"], "timeframe must be one of")
 	})
 
 	t.Run("invalid confidence threshold - too high", func(t *testing.T) {
@@ -353,6 +369,17 @@ func TestRecommendationsHandler_NamespaceFiltering(t *testing.T) {
 		assert.Equal(t, 0, resp.TotalRecommendations)
 		assert.Contains(t, resp.Message, "No recommendations")
 	})
+
+	t.Run("pod scope requires a namespace", func(t *testing.T) {
+		reqBody := `{"pod": "api-7d9f"}`
+		req := httptest.NewRequest("POST", "/api/v1/recommendations", bytes.NewBufferString(reqBody))
+		w := httptest.NewRecorder()
+
+		handler.GetRecommendations(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `"field":"namespace"`)
+	})
 }
 
 func TestRecommendation_Structure(t *testing.T) {
@@ -490,3 +517,409 @@ func TestGetRecommendationsRequest_Defaults(t *testing.T) {
 	assert.Zero(t, req.ConfidenceThreshold)
 	assert.Empty(t, req.Namespace)
 }
+
+func TestRecommendationsHandler_CapacityRecommendations(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	// One full node: no headroom for the reference pod
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "worker-0"},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("2"),
+				corev1.ResourceMemory: resource.MustParse("4Gi"),
+			},
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+		},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "production"},
+		Spec: corev1.PodSpec{
+			NodeName: "worker-0",
+			Containers: []corev1.Container{{
+				Name: "api",
+				Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("1900m"),
+					corev1.ResourceMemory: resource.MustParse("3Gi"),
+				}},
+			}},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+
+	handler := NewRecommendationsHandler(nil, storage.NewIncidentStore(), nil, log)
+	handler.SetCapacityAnalyzer(capacity.NewAnalyzer(fake.NewSimpleClientset(node, pod), log))
+
+	response, err := handler.Recommend(context.Background(), &GetRecommendationsRequest{})
+	require.NoError(t, err)
+
+	var found *Recommendation
+	for i := range response.Recommendations {
+		if response.Recommendations[i].Source == "capacity_analysis" {
+			found = &response.Recommendations[i]
+		}
+	}
+	require.NotNil(t, found, "node capacity recommendation expected")
+	assert.Equal(t, "node_capacity_low", found.IssueType)
+	assert.Equal(t, "cluster-nodes", found.Target)
+	assert.Equal(t, "high", found.Severity)
+	assert.Contains(t, found.RecommendedActions, "scale_up_node_pool")
+	assert.NotEmpty(t, found.Evidence)
+
+	// Cluster-scoped recommendations are omitted when filtering by namespace
+	response, err = handler.Recommend(context.Background(), &GetRecommendationsRequest{Scope: models.Scope{Namespace: "production"}})
+	require.NoError(t, err)
+	for _, rec := range response.Recommendations {
+		assert.NotEqual(t, "capacity_analysis", rec.Source)
+	}
+}
+
+func TestRecommendationsHandler_CertificateRecommendations(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	servingCert := func(name string, expiresIn time.Duration) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "payments",
+				Annotations: map[string]string{
+					platform.ServingCertExpiryAnnotation:  time.Now().Add(expiresIn).Format(time.RFC3339),
+					platform.ServingCertServiceAnnotation: "api",
+				},
+			},
+			Type: corev1.SecretTypeTLS,
+		}
+	}
+	clientset := fake.NewSimpleClientset(
+		servingCert("api-serving-cert", 5*24*time.Hour+time.Hour),
+		servingCert("later-serving-cert", 90*24*time.Hour),
+	)
+
+	handler := NewRecommendationsHandler(nil, storage.NewIncidentStore(), nil, log)
+	handler.SetCertificateScanner(platform.NewCertificateScanner(clientset, nil, log), 30*24*time.Hour)
+
+	recommendations := handler.getCertificateRecommendations(context.Background())
+	require.Len(t, recommendations, 1, "only certificates expiring within the warning window")
+	rec := recommendations[0]
+	assert.Equal(t, "proactive", rec.Type)
+	assert.Equal(t, "certificate_expiry", rec.IssueType)
+	assert.Equal(t, "secret/api-serving-cert", rec.Target)
+	assert.Equal(t, "payments", rec.Namespace)
+	assert.Equal(t, "high", rec.Severity)
+	assert.Equal(t, "certificate_scan", rec.Source)
+	assert.Equal(t, []string{"delete_secret_to_regenerate_serving_certificate", "restart_pods_mounting_secret"}, rec.RecommendedActions)
+	assert.Contains(t, rec.Evidence[0], "(5 days)")
+
+	assert.Equal(t, "critical", certificateSeverity(-1))
+	assert.Equal(t, "medium", certificateSeverity(14))
+	assert.Equal(t, "low", certificateSeverity(15))
+}
+
+func TestRecommendationsHandler_EtcdRecommendations(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	// 7GiB of the configured 8GiB quota growing 100MiB a day, 2GiB of it freed pages
+	growth := "104857600"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query().Get("query")
+		value := "7516192768"
+		switch {
+		case strings.Contains(query, "quota_backend"):
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[]}}`))
+			return
+		case strings.Contains(query, "deriv("):
+			value = growth
+		case strings.Contains(query, "size_in_use"):
+			value = "5368709120"
+		}
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1700000000,"` + value + `"]}]}}`))
+	}))
+	defer server.Close()
+
+	handler := NewRecommendationsHandler(nil, storage.NewIncidentStore(), nil, log)
+	handler.SetPrometheusClient(integrations.NewPrometheusClient(server.URL, 5*time.Second, log))
+	assert.Empty(t, handler.getEtcdRecommendations(context.Background()), "disabled until SetEtcdForecast")
+
+	handler.SetEtcdForecast(8*quantity.GiB, 30*24*time.Hour)
+	recommendations := handler.getEtcdRecommendations(context.Background())
+	require.Len(t, recommendations, 1)
+	rec := recommendations[0]
+	assert.Equal(t, "etcd_quota_exhaustion", rec.IssueType)
+	assert.Equal(t, "critical", rec.Severity)
+	assert.Equal(t, "etcd_forecast", rec.Source)
+	assert.Equal(t, []string{"compact_etcd_revisions", "defragment_etcd_members", "prune_high_churn_resources"}, rec.RecommendedActions)
+	assert.Equal(t, "etcd database 7.0Gi of 8.0Gi quota (88%, configured quota)", rec.Evidence[0])
+	assert.Contains(t, rec.Evidence[1], "quota reached in 10 days")
+	assert.Equal(t, "defragmentation would reclaim 2.0Gi of freed pages", rec.Evidence[2])
+	assert.NotEmpty(t, rec.PredictedTime)
+
+	// Quota exhaustion beyond the warning window is not recommended
+	growth = "10485760"
+	assert.Empty(t, handler.getEtcdRecommendations(context.Background()))
+}
+
+func TestRecommendationsHandler_SchedulerRecommendations(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	// 20 pods pending and 6 unschedulable throughout the window
+	unschedulable := "6"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		value := "20"
+		if strings.Contains(r.URL.Query().Get("query"), "unschedulable") {
+			value = unschedulable
+		}
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1700000000,"` + value + `"]}]}}`))
+	}))
+	defer server.Close()
+
+	event := func(pod, message string) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: pod + ".1", Namespace: "shop"},
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Namespace: "shop", Name: pod},
+			Reason:         "FailedScheduling",
+			Message:        message,
+			LastTimestamp:  metav1.NewTime(time.Now().Add(-time.Minute)),
+		}
+	}
+	clientset := fake.NewSimpleClientset(
+		event("api-1", "0/5 nodes are available: 5 node(s) had untolerated taint {dedicated: gpu}."),
+		event("api-2", "0/5 nodes are available: 5 node(s) had untolerated taint {dedicated: gpu}."),
+		event("batch-1", "0/5 nodes are available: 2 Insufficient memory, 3 node(s) had untolerated taint {dedicated: gpu}."),
+		event("batch-2", "0/5 nodes are available: 5 Insufficient memory."),
+	)
+
+	handler := NewRecommendationsHandler(nil, storage.NewIncidentStore(), nil, log)
+	assert.Empty(t, handler.getSchedulerRecommendations(context.Background()), "disabled until SetSchedulerPressure")
+
+	prometheusClient := integrations.NewPrometheusClient(server.URL, 5*time.Second, log)
+	handler.SetSchedulerPressure(integrations.NewSchedulerPressureAnalyzer(prometheusClient, clientset,
+		integrations.SchedulerPressureOptions{PendingThreshold: 10, UnschedulableThreshold: 5, Window: 15 * time.Minute}, log))
+	recommendations := handler.getSchedulerRecommendations(context.Background())
+	require.Len(t, recommendations, 1)
+	rec := recommendations[0]
+	assert.Equal(t, "scheduler_pressure", rec.IssueType)
+	assert.Equal(t, "high", rec.Severity)
+	assert.Equal(t, "scheduler_pressure", rec.Source)
+	assert.Equal(t, 0.75, rec.Confidence, "3 of the 4 failing pods hit untolerated taints")
+	assert.Equal(t, []string{"review_node_taints", "add_pod_tolerations"}, rec.RecommendedActions)
+	assert.Equal(t, []string{
+		"at least 20 pods pending and 6 unschedulable throughout the last 15m",
+		`dominant reason untolerated_taints: "node(s) had untolerated taint {dedicated: gpu}" for 3 pods, ruling out 13 nodes`,
+		`also insufficient_resources: "Insufficient memory" for 2 pods`,
+	}, rec.Evidence)
+
+	// The unschedulable queue drained within the window
+	unschedulable = "1"
+	assert.Empty(t, handler.getSchedulerRecommendations(context.Background()))
+}
+
+func TestRecommendationsHandler_ActionOutcomes(t *testing.T) {
+	t.Setenv("DATA_DIR", t.TempDir())
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	handler := NewRecommendationsHandler(nil, storage.NewIncidentStore(), nil, log)
+	post := func(body string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		handler.RecordActionOutcome(rr, httptest.NewRequest(http.MethodPost, "/api/v1/recommendations/outcomes", bytes.NewBufferString(body)))
+		return rr
+	}
+
+	// Ranking not enabled
+	assert.Equal(t, http.StatusServiceUnavailable, post(`{"issue_type":"pod_crash_loop","action":"review_health_probes","success":true}`).Code)
+
+	handler.SetActionRanker(knowledge.NewActionRanker(storage.NewActionOutcomeStore("", 0), 2))
+
+	rr := post(`{"issue_type":"pod_crash_loop","action":"review_health_probes"}`)
+	assert.Equal(t, http.StatusBadRequest, rr.Code, "success is required")
+	rr = post(`{"issue_type":"pod_crash_loop","success":true}`)
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"field":"action"`)
+
+	for i := 0; i < 3; i++ {
+		rr = post(`{"issue_type":"pod_crash_loop","action":"review_health_probes","success":true}`)
+		require.Equal(t, http.StatusCreated, rr.Code)
+	}
+	var recorded RecordActionOutcomeResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &recorded))
+	assert.Equal(t, "operator", recorded.Outcome.Source)
+	assert.Equal(t, []knowledge.ActionSuccessRate{{Action: "review_health_probes", SuccessRate: 1, Attempts: 3}}, recorded.Actions)
+
+	rr = httptest.NewRecorder()
+	handler.GetActionOutcomes(rr, httptest.NewRequest(http.MethodGet, "/api/v1/recommendations/outcomes", http.NoBody))
+	require.Equal(t, http.StatusOK, rr.Code)
+	var listed ActionOutcomesResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &listed))
+	require.Len(t, listed.IssueTypes, 1)
+	assert.Equal(t, "pod_crash_loop", listed.IssueTypes[0].IssueType)
+
+	// The verified action moves to the front of the static order
+	rec := Recommendation{IssueType: "pod_crash_loop", RecommendedActions: getRecommendedActions("pod_crash_loop")}
+	handler.rankActions(&rec)
+	assert.Equal(t, []string{"review_health_probes", "check_container_logs", "verify_resource_limits"}, rec.RecommendedActions)
+	require.Len(t, rec.ActionSuccessRates, 1)
+	assert.Contains(t, rec.Evidence, "review_health_probes succeeded 100% of 3 verified attempts for pod_crash_loop in this cluster")
+}
+
+// testImageScanner scans every image of a fake Quay registry as having a critical
+// openssl vulnerability. pods builds the cluster's pods from an image of the registry;
+// the scanner and the image are returned.
+func testImageScanner(t *testing.T, pods func(image string) []runtime.Object) (*imagescan.Scanner, string) {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"scanned","data":{"Layer":{"Features":[{"Name":"openssl","Version":"1.1.1k","Vulnerabilities":[` +
+			`{"Name":"CVE-2023-0286","Severity":"Critical","FixedBy":"1.1.1t"},{"Name":"CVE-2022-4304","Severity":"Medium"}]}]}}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	quay, err := imagescan.NewQuayClient(server.URL, "", 5*time.Second, log)
+	require.NoError(t, err)
+	image := quay.Host() + "/acme/api@sha256:abc"
+	return imagescan.NewScanner(quay, fake.NewSimpleClientset(pods(image)...), 3, time.Hour, log), image
+}
+
+// crashingPod is a pod of the api deployment whose container runs image
+func crashingPod(name, image string, restarts int32) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop", Labels: map[string]string{"app": "api"}},
+		Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+			Name:         "api",
+			ImageID:      image,
+			RestartCount: restarts,
+			State:        corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+		}}},
+	}
+}
+
+func TestRecommendationsHandler_ImageRecommendations(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	handler := NewRecommendationsHandler(nil, storage.NewIncidentStore(), nil, log)
+	assert.Empty(t, handler.getImageRecommendations(context.Background(), "shop"), "disabled until SetImageScanner")
+
+	scanner, image := testImageScanner(t, func(image string) []runtime.Object {
+		return []runtime.Object{crashingPod("api-1", image, 5), crashingPod("api-2", image, 2)}
+	})
+	handler.SetImageScanner(scanner)
+
+	recommendations := handler.getImageRecommendations(context.Background(), "shop")
+	require.Len(t, recommendations, 1)
+	rec := recommendations[0]
+	assert.Equal(t, "rec-image-001", rec.ID)
+	assert.Equal(t, "vulnerable_image_crash", rec.IssueType)
+	assert.Equal(t, "container/api", rec.Target)
+	assert.Equal(t, "shop", rec.Namespace)
+	assert.Equal(t, "image_scan", rec.Source)
+	assert.Equal(t, imageRecommendationConfidence, rec.Confidence)
+	assert.Equal(t, []string{"update_image", "rebuild_image_with_fixed_packages", "review_crash_logs"}, rec.RecommendedActions)
+	assert.Equal(t, []string{
+		"container api restarted 7 times across 2 pods (CrashLoopBackOff)",
+		"image " + image + " has 1 critical vulnerabilities: CVE-2023-0286",
+		"fixed in openssl 1.1.1t (CVE-2023-0286)",
+	}, rec.Evidence)
+
+	assert.Empty(t, handler.getImageRecommendations(context.Background(), "other"))
+}
+
+func TestRecommendationsHandler_Owner(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	incidentStore := storage.NewIncidentStoreWithPath(t.TempDir())
+	for i := 0; i < 3; i++ {
+		_, err := incidentStore.Create(&models.Incident{
+			Title:       "Production incident",
+			Description: "Issue in production",
+			Severity:    models.IncidentSeverityHigh,
+			Target:      "production",
+		})
+		require.NoError(t, err)
+	}
+
+	handler := NewRecommendationsHandler(nil, incidentStore, nil, log)
+	handler.SetOwnerResolver(ownership.NewResolver(fake.NewSimpleClientset(&corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "production", Annotations: map[string]string{
+			ownership.DefaultAnnotationPrefix + ownership.AnnotationTeam:         "payments",
+			ownership.DefaultAnnotationPrefix + ownership.AnnotationSlackChannel: "#payments-oncall",
+		}},
+	}), "", log))
+
+	req := httptest.NewRequest("POST", "/api/v1/recommendations",
+		bytes.NewBufferString(`{"namespace": "production", "include_predictions": false, "confidence_threshold": 0.5}`))
+	w := httptest.NewRecorder()
+	handler.GetRecommendations(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp GetRecommendationsResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.NotEmpty(t, resp.Recommendations)
+	for _, rec := range resp.Recommendations {
+		assert.Equal(t, &models.Owner{Team: "payments", SlackChannel: "#payments-oncall", Source: "namespace/production"}, rec.Owner)
+	}
+}
+
+func TestRecommendationsHandler_ActionRules(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	incidentStore := storage.NewIncidentStoreWithPath(t.TempDir())
+	for i := 0; i < 3; i++ {
+		_, err := incidentStore.Create(&models.Incident{
+			Title:       "Memory pressure incident",
+			Description: "Memory pressure detected",
+			Severity:    models.IncidentSeverityHigh,
+			Target:      "payments-prod",
+		})
+		require.NoError(t, err)
+	}
+
+	file := filepath.Join(t.TempDir(), "rules.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(`
+rules:
+  - name: payments-oncall
+    namespaces: ["payments-*"]
+    when:
+      - metric: confidence
+        op: ">="
+        value: 0.5
+    actions:
+      - action: page_payments_oncall
+        weight: 2
+  - name: needs-usage
+    when:
+      - metric: memory_utilization
+        op: ">"
+        value: 0.1
+    actions:
+      - action: never_added
+`), 0o600))
+	engine, err := advice.NewEngine(file, 0, log)
+	require.NoError(t, err)
+
+	handler := NewRecommendationsHandler(nil, incidentStore, nil, log)
+	handler.SetActionRules(engine)
+
+	req := httptest.NewRequest("POST", "/api/v1/recommendations",
+		bytes.NewBufferString(`{"namespace": "payments-prod", "include_predictions": false, "confidence_threshold": 0.5}`))
+	w := httptest.NewRecorder()
+	handler.GetRecommendations(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp GetRecommendationsResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.NotEmpty(t, resp.Recommendations)
+	for _, rec := range resp.Recommendations {
+		require.NotEmpty(t, rec.RecommendedActions)
+		assert.Equal(t, "page_payments_oncall", rec.RecommendedActions[0], "the rule's weight ranks its action first")
+		assert.NotContains(t, rec.RecommendedActions, "never_added", "a condition on an unavailable metric fails")
+		assert.Contains(t, rec.Evidence, "Matched recommendation rules: payments-oncall")
+	}
+}
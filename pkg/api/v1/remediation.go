@@ -1,25 +1,95 @@
 package v1
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/sirupsen/logrus"
 
+	"github.com/tosin2013/openshift-coordination-engine/internal/detector"
+	"github.com/tosin2013/openshift-coordination-engine/internal/escalation"
+	"github.com/tosin2013/openshift-coordination-engine/internal/execution"
+	"github.com/tosin2013/openshift-coordination-engine/internal/featureflag"
+	"github.com/tosin2013/openshift-coordination-engine/internal/imagescan"
+	"github.com/tosin2013/openshift-coordination-engine/internal/knowledge"
+	"github.com/tosin2013/openshift-coordination-engine/internal/observer"
 	"github.com/tosin2013/openshift-coordination-engine/internal/remediation"
+	"github.com/tosin2013/openshift-coordination-engine/internal/runtimeconfig"
 	"github.com/tosin2013/openshift-coordination-engine/internal/storage"
+	"github.com/tosin2013/openshift-coordination-engine/internal/upgrade"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/api/validation"
 	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/runbook"
 )
 
+// IncidentSummarizer regenerates the natural-language summary of a stored incident
+// (implemented by summary.Summarizer)
+type IncidentSummarizer interface {
+	SummarizeByID(ctx context.Context, id string) (*models.Incident, error)
+}
+
+// RemediationPolicies returns the policy deciding a remediation request's issue
+// (implemented by runtimeconfig.Store)
+type RemediationPolicies interface {
+	PolicyFor(issue *models.Issue) (string, *runtimeconfig.Policy)
+}
+
+// EscalationLadders returns the escalation ladder for an issue type (implemented by
+// runtimeconfig.Store)
+type EscalationLadders interface {
+	EscalationLadderFor(issueType string) (string, *runtimeconfig.EscalationLadder)
+}
+
 // RemediationHandler handles remediation API requests
 type RemediationHandler struct {
 	orchestrator  *remediation.Orchestrator
 	incidentStore *storage.IncidentStore
+	summarizer    IncidentSummarizer
+	similar       *knowledge.Index
+	runbooks      *runbook.Registry
+	mcpDetector   *detector.MachineConfigUpdateDetector
+	upgrade       *upgrade.Monitor
+	policies      RemediationPolicies
+	executions    *execution.Runner
+	escalations   *escalation.Tracker
+	ladders       EscalationLadders
+	scaler        *escalation.Scaler
+	artifacts     *storage.ArtifactStore
+	anomalies     *storage.AnomalyStore
+	images        *imagescan.Scanner
+	observerMode  bool
+	flags         *featureflag.Flags
 	log           *logrus.Logger
 }
 
+const (
+	// ErrCodeMachineConfigUpdateInProgress is returned when a remediation is deferred
+	// because a MachineConfigPool update explains the issue
+	ErrCodeMachineConfigUpdateInProgress = "MACHINECONFIG_UPDATE_IN_PROGRESS"
+
+	// ErrCodeClusterUpgradeInProgress is returned when automated remediation is
+	// blocked because the cluster is upgrading
+	ErrCodeClusterUpgradeInProgress = "CLUSTER_UPGRADE_IN_PROGRESS"
+
+	// ErrCodeRemediationDenied is returned when a remediation policy denies the request
+	ErrCodeRemediationDenied = "REMEDIATION_DENIED_BY_POLICY"
+
+	// ErrCodeRemediationBudgetExceeded is returned when a remediation would exceed its
+	// namespace's daily limit or its resource's cooldown
+	ErrCodeRemediationBudgetExceeded = "REMEDIATION_BUDGET_EXCEEDED"
+
+	// ErrCodeObserverMode is returned when a remediation is requested while the engine
+	// runs in observer mode
+	ErrCodeObserverMode = "OBSERVER_MODE"
+)
+
 // NewRemediationHandler creates a new remediation handler
 func NewRemediationHandler(orchestrator *remediation.Orchestrator, log *logrus.Logger) *RemediationHandler {
 	return &RemediationHandler{
@@ -34,6 +104,101 @@ func (h *RemediationHandler) GetIncidentStore() *storage.IncidentStore {
 	return h.incidentStore
 }
 
+// SetRunbooks links incidents to runbooks for their issue type and alert name
+func (h *RemediationHandler) SetRunbooks(registry *runbook.Registry) {
+	h.runbooks = registry
+}
+
+// incidentRunbooks returns the runbooks for an incident's issue_type and alertname labels
+func (h *RemediationHandler) incidentRunbooks(incident *models.Incident) []runbook.Runbook {
+	return h.runbooks.Lookup(incident.Labels["issue_type"], incident.Labels["alertname"])
+}
+
+// SetMachineConfigUpdateDetector defers remediations while a MachineConfigPool update
+// is draining and rebooting the affected nodes
+func (h *RemediationHandler) SetMachineConfigUpdateDetector(mcpDetector *detector.MachineConfigUpdateDetector) {
+	h.mcpDetector = mcpDetector
+}
+
+// SetUpgradeMonitor blocks automated remediation while the cluster is upgrading,
+// as configured by the upgrade policy
+func (h *RemediationHandler) SetUpgradeMonitor(monitor *upgrade.Monitor) {
+	h.upgrade = monitor
+}
+
+// SetObserverMode rejects every remediation request: the engine only observes
+func (h *RemediationHandler) SetObserverMode(enabled bool) {
+	h.observerMode = enabled
+}
+
+// SetFeatureFlags rejects remediation in namespaces where auto_remediation is disabled
+func (h *RemediationHandler) SetFeatureFlags(flags *featureflag.Flags) {
+	h.flags = flags
+}
+
+// SetPolicies checks remediation requests against runtime remediation policies
+func (h *RemediationHandler) SetPolicies(policies RemediationPolicies) {
+	h.policies = policies
+}
+
+// SetExecutionRunner runs playbooks in the external workflow engine instead of the
+// built-in workflow: the playbook assigned by the matching policy, or else the first
+// playbook listing the issue type
+func (h *RemediationHandler) SetExecutionRunner(runner *execution.Runner) {
+	h.executions = runner
+}
+
+// SetEscalation escalates recurring incidents along the escalation ladder for their
+// issue type: a restart step runs the regular remediation, a scale step adds replicas
+// with scaler, and a page step escalates the incident to a human
+func (h *RemediationHandler) SetEscalation(tracker *escalation.Tracker, ladders EscalationLadders, scaler *escalation.Scaler) {
+	h.escalations = tracker
+	h.ladders = ladders
+	h.scaler = scaler
+}
+
+// SetSummarizer enables on-demand incident summaries
+func (h *RemediationHandler) SetSummarizer(summarizer IncidentSummarizer) {
+	h.summarizer = summarizer
+}
+
+// SetSimilarityIndex adds similar past incidents and the outcomes of their remediations
+// to created incidents, and enables GET /api/v1/incidents/{id}/similar
+func (h *RemediationHandler) SetSimilarityIndex(index *knowledge.Index) {
+	h.similar = index
+}
+
+// SetImageScanner adds the critical vulnerabilities of the images of the workloads
+// affected by created incidents to the response
+func (h *RemediationHandler) SetImageScanner(scanner *imagescan.Scanner) {
+	h.images = scanner
+}
+
+// incidentImages returns the image scans of the workloads among an incident's affected
+// resources. The incident's namespace label, or else its target, is their namespace.
+func (h *RemediationHandler) incidentImages(ctx context.Context, incident *models.Incident) []imagescan.ImageReport {
+	namespace := incident.Labels["namespace"]
+	if namespace == "" {
+		namespace = incident.Target
+	}
+	seen := make(map[string]bool)
+	reports := make([]imagescan.ImageReport, 0)
+	for _, resource := range incident.AffectedResources {
+		images, err := h.images.WorkloadImages(ctx, namespace, resource)
+		if err != nil {
+			h.log.WithError(err).WithField("resource", resource).Debug("Failed to scan the images of an affected resource")
+			continue
+		}
+		for _, image := range images {
+			if !seen[image.Image] {
+				seen[image.Image] = true
+				reports = append(reports, image)
+			}
+		}
+	}
+	return reports
+}
+
 // TriggerRemediationRequest represents the request body for triggering remediation
 type TriggerRemediationRequest struct {
 	IncidentID string `json:"incident_id"`
@@ -47,6 +212,8 @@ type TriggerRemediationRequest struct {
 		Description string `json:"description"`
 		Severity    string `json:"severity"`
 	} `json:"issue"`
+	// Force remediates even while a MachineConfigPool update is in progress
+	Force bool `json:"force,omitempty"`
 }
 
 // TriggerRemediationResponse represents the response for triggering remediation
@@ -55,6 +222,19 @@ type TriggerRemediationResponse struct {
 	Status            string `json:"status"`
 	DeploymentMethod  string `json:"deployment_method"`
 	EstimatedDuration string `json:"estimated_duration"`
+
+	// Policy and Playbook name the remediation policy that allowed the request and
+	// the playbook it assigns
+	Policy   string `json:"policy,omitempty"`
+	Playbook string `json:"playbook,omitempty"`
+
+	// ExecutionID and Backend identify the playbook run when the playbook was
+	// submitted to an external workflow engine instead of starting a workflow
+	ExecutionID string `json:"execution_id,omitempty"`
+	Backend     string `json:"backend,omitempty"`
+
+	// Escalation is the escalation ladder step taken for a recurring incident
+	Escalation *escalation.Decision `json:"escalation,omitempty"`
 }
 
 // WorkflowResponse represents the response for getting workflow details
@@ -76,6 +256,14 @@ type WorkflowResponse struct {
 	Steps            []models.WorkflowStep `json:"steps,omitempty"`
 }
 
+// WorkflowEventsResponse is the event log of a workflow, the timeline its state is
+// derived from
+type WorkflowEventsResponse struct {
+	WorkflowID string                      `json:"workflow_id"`
+	Events     []remediation.WorkflowEvent `json:"events"`
+	Total      int                         `json:"total"`
+}
+
 // CreateIncidentRequest represents the request body for creating an incident
 type CreateIncidentRequest struct {
 	Title             string            `json:"title"`
@@ -84,15 +272,33 @@ type CreateIncidentRequest struct {
 	Target            string            `json:"target"`
 	AffectedResources []string          `json:"affected_resources,omitempty"`
 	Labels            map[string]string `json:"labels,omitempty"`
+
+	// Tags attribute the incident, e.g. to a team, service or cost center; tag rules
+	// add tags the request does not set
+	Tags map[string]string `json:"tags,omitempty"`
+
+	// AnalysisID attaches the artifact of the anomaly analysis the incident was raised from
+	AnalysisID string `json:"analysis_id,omitempty"`
 }
 
 // CreateIncidentResponse represents the response for creating an incident
 type CreateIncidentResponse struct {
-	Status     string           `json:"status"`
-	IncidentID string           `json:"incident_id"`
-	CreatedAt  string           `json:"created_at"`
-	Incident   *models.Incident `json:"incident"`
-	Message    string           `json:"message"`
+	Status     string            `json:"status"`
+	IncidentID string            `json:"incident_id"`
+	CreatedAt  string            `json:"created_at"`
+	Incident   *models.Incident  `json:"incident"`
+	Runbooks   []runbook.Runbook `json:"runbooks,omitempty"`
+	Message    string            `json:"message"`
+
+	// SimilarIncidents are past incidents like this one and how they were remediated
+	SimilarIncidents *knowledge.Lookup `json:"similar_incidents,omitempty"`
+
+	// AnalysisID is the analysis artifact attached to the incident
+	AnalysisID string `json:"analysis_id,omitempty"`
+
+	// ImageVulnerabilities are the Quay scans of the images run by the affected
+	// workloads, with their critical vulnerabilities
+	ImageVulnerabilities []imagescan.ImageReport `json:"image_vulnerabilities,omitempty"`
 }
 
 // TriggerRemediation handles POST /api/v1/remediation/trigger
@@ -103,26 +309,72 @@ func (h *RemediationHandler) TriggerRemediation(w http.ResponseWriter, r *http.R
 	var req TriggerRemediationRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		h.log.WithError(err).Error("Failed to decode request body")
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		h.sendErrorResponse(w, http.StatusBadRequest, "Invalid request body", validation.DecodeFields(err)...)
 		return
 	}
 
-	// Validate required fields
-	if req.IncidentID == "" {
-		http.Error(w, "incident_id is required", http.StatusBadRequest)
+	response, err := h.Trigger(r.Context(), &req)
+	if err != nil {
+		var requestErr *RequestError
+		if errors.As(err, &requestErr) {
+			h.sendErrorResponse(w, requestErr.StatusCode, requestErr.Error(), requestErr.Errors...)
+			return
+		}
+		h.sendErrorResponse(w, http.StatusInternalServerError, err.Error())
 		return
 	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.log.WithError(err).Error("Failed to encode response")
+	}
+}
+
+// Validate checks the required fields of a remediation trigger request
+func (req *TriggerRemediationRequest) Validate() error {
+	var errs validation.Errors
+	if req.IncidentID == "" {
+		errs.Add("incident_id", validation.ConstraintRequired, nil, "incident_id is required")
+	}
 	if req.Namespace == "" {
-		http.Error(w, "namespace is required", http.StatusBadRequest)
-		return
+		errs.Add("namespace", validation.ConstraintRequired, nil, "namespace is required")
 	}
-	if req.Resource.Name == "" || req.Resource.Kind == "" {
-		http.Error(w, "resource.name and resource.kind are required", http.StatusBadRequest)
-		return
+	if req.Resource.Name == "" {
+		errs.Add("resource.name", validation.ConstraintRequired, nil, "resource.name is required")
+	}
+	if req.Resource.Kind == "" {
+		errs.Add("resource.kind", validation.ConstraintRequired, nil, "resource.kind is required")
 	}
 	if req.Issue.Type == "" {
-		http.Error(w, "issue.type is required", http.StatusBadRequest)
-		return
+		errs.Add("issue.type", validation.ConstraintRequired, nil, "issue.type is required")
+	}
+	return errs.Err()
+}
+
+// checkEnabled returns the error of observer mode, the auto-remediation feature flag
+// or a cluster upgrade blocking a remediation
+func (h *RemediationHandler) checkEnabled(req *TriggerRemediationRequest) error {
+	if err := checkObserverMode(h.observerMode, "remediation"); err != nil {
+		h.log.WithField("incident_id", req.IncidentID).Info("Remediation blocked in observer mode")
+		return err
+	}
+	if err := checkFeature(h.flags, featureflag.AutoRemediation, req.Namespace); err != nil {
+		h.log.WithField("incident_id", req.IncidentID).Info("Remediation blocked by feature flag")
+		return err
+	}
+	if err := checkClusterUpgrade(h.upgrade, req.Force, "remediation"); err != nil {
+		h.log.WithField("incident_id", req.IncidentID).Info("Remediation blocked during cluster upgrade")
+		return err
+	}
+	return nil
+}
+
+// Trigger validates the request and starts a remediation workflow. It is shared by
+// the REST handler and the MCP server.
+func (h *RemediationHandler) Trigger(ctx context.Context, req *TriggerRemediationRequest) (*TriggerRemediationResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, invalidRequest(err, ErrCodeInvalidRequest)
 	}
 
 	h.log.WithFields(logrus.Fields{
@@ -132,6 +384,13 @@ func (h *RemediationHandler) TriggerRemediation(w http.ResponseWriter, r *http.R
 		"issue_type":  req.Issue.Type,
 	}).Info("Triggering remediation workflow")
 
+	if err := h.checkEnabled(req); err != nil {
+		return nil, err
+	}
+	if err := h.checkMachineConfigUpdates(ctx, req); err != nil {
+		return nil, err
+	}
+
 	// Create issue from request
 	issue := &models.Issue{
 		ID:           req.IncidentID, // Use incident ID as issue ID for now
@@ -144,32 +403,410 @@ func (h *RemediationHandler) TriggerRemediation(w http.ResponseWriter, r *http.R
 		DetectedAt:   time.Now(),
 	}
 
+	policyName, policy, err := h.checkPolicies(issue)
+	if err != nil {
+		return nil, err
+	}
+
+	decision := h.planEscalation(issue)
+	var response *TriggerRemediationResponse
+	switch {
+	case decision != nil && decision.Action == runtimeconfig.EscalationScale:
+		response, err = h.scaleUp(ctx, req, issue, decision)
+	case decision != nil && decision.Action == runtimeconfig.EscalationPage:
+		response, err = h.page(req, issue, decision)
+	default:
+		response, err = h.remediate(ctx, req, issue, policyName, policy)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if decision != nil {
+		h.recordEscalation(decision, req.IncidentID)
+		response.Escalation = decision
+	}
+	return response, nil
+}
+
+// remediate runs the issue's playbook in the execution backend, or else starts a
+// remediation workflow
+func (h *RemediationHandler) remediate(
+	ctx context.Context,
+	req *TriggerRemediationRequest,
+	issue *models.Issue,
+	policyName string,
+	policy *runtimeconfig.Policy,
+) (*TriggerRemediationResponse, error) {
+	if response, err := h.runPlaybook(ctx, req, issue, policyName, policy); response != nil || err != nil {
+		return response, err
+	}
+
 	// Trigger remediation workflow
-	workflow, err := h.orchestrator.TriggerRemediation(r.Context(), req.IncidentID, issue)
+	workflow, err := h.orchestrator.TriggerRemediation(ctx, req.IncidentID, issue)
 	if err != nil {
+		if budgetErr := budgetExceeded(err); budgetErr != nil {
+			return nil, budgetErr
+		}
 		h.log.WithError(err).Error("Failed to trigger remediation")
-		http.Error(w, "Failed to trigger remediation: "+err.Error(), http.StatusInternalServerError)
-		return
+		return nil, &RequestError{
+			StatusCode: http.StatusInternalServerError,
+			Message:    "Failed to trigger remediation",
+			Details:    err.Error(),
+		}
 	}
 
-	// Build response
-	response := TriggerRemediationResponse{
+	h.log.WithFields(logrus.Fields{
+		"workflow_id": workflow.ID,
+		"status":      workflow.Status,
+	}).Info("Remediation workflow triggered successfully")
+
+	response := &TriggerRemediationResponse{
 		WorkflowID:        workflow.ID,
 		Status:            string(workflow.Status),
 		DeploymentMethod:  workflow.DeploymentMethod,
 		EstimatedDuration: "5m", // Default estimate
+		Policy:            policyName,
 	}
+	if policy != nil {
+		response.Playbook = policy.Playbook
+	}
+	return response, nil
+}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusAccepted)
-	if err := json.NewEncoder(w).Encode(response); err != nil {
-		h.log.WithError(err).Error("Failed to encode response")
+// runPlaybook submits the issue's playbook to the execution backend. It returns nil
+// and no error if no backend is configured or no playbook applies, so the built-in
+// workflow runs instead.
+func (h *RemediationHandler) runPlaybook(
+	ctx context.Context,
+	req *TriggerRemediationRequest,
+	issue *models.Issue,
+	policyName string,
+	policy *runtimeconfig.Policy,
+) (*TriggerRemediationResponse, error) {
+	if h.executions == nil {
+		return nil, nil
+	}
+
+	run := &execution.Request{
+		IncidentID: req.IncidentID,
+		IssueType:  req.Issue.Type,
+		Target: execution.Target{
+			Namespace: req.Namespace,
+			Kind:      req.Resource.Kind,
+			Name:      req.Resource.Name,
+		},
+	}
+	if policy != nil {
+		run.Playbook = policy.Playbook
+	}
+	exec, obj, err := h.executions.Render(run)
+	if err == nil {
+		err = h.admit(ctx, issue)
+	}
+	if err == nil {
+		exec, err = h.executions.Submit(ctx, exec, obj)
+	}
+	switch {
+	case errors.Is(err, execution.ErrNoPlaybook):
+		return nil, nil
+	case budgetExceeded(err) != nil:
+		return nil, budgetExceeded(err)
+	case validation.Fields(err) != nil:
+		return nil, invalidRequest(err, ErrCodeInvalidRequest)
+	case errors.Is(err, execution.ErrPlaybookNotFound), errors.Is(err, execution.ErrUnsupported):
+		return nil, &RequestError{
+			StatusCode: http.StatusUnprocessableEntity,
+			Message:    "Playbook cannot be run",
+			Details:    err.Error(),
+		}
+	case err != nil:
+		h.log.WithError(err).Error("Failed to submit playbook")
+		return nil, &RequestError{
+			StatusCode: http.StatusBadGateway,
+			Message:    "Failed to submit playbook",
+			Details:    err.Error(),
+		}
 	}
 
 	h.log.WithFields(logrus.Fields{
-		"workflow_id": workflow.ID,
-		"status":      workflow.Status,
-	}).Info("Remediation workflow triggered successfully")
+		"execution_id": exec.ID,
+		"playbook":     exec.Playbook,
+		"backend":      exec.Backend,
+	}).Info("Remediation playbook submitted to execution backend")
+
+	return &TriggerRemediationResponse{
+		Status:            string(exec.Phase),
+		EstimatedDuration: "5m", // Default estimate
+		Policy:            policyName,
+		Playbook:          exec.Playbook,
+		ExecutionID:       exec.ID,
+		Backend:           exec.Backend,
+	}, nil
+}
+
+// admit counts a playbook run against the orchestrator's remediation budget
+func (h *RemediationHandler) admit(ctx context.Context, issue *models.Issue) error {
+	if h.orchestrator == nil {
+		return nil
+	}
+	_, err := h.orchestrator.Admit(ctx, issue)
+	return err
+}
+
+// planEscalation returns the escalation step for the issue, or nil if no escalation
+// ladder applies
+func (h *RemediationHandler) planEscalation(issue *models.Issue) *escalation.Decision {
+	if h.escalations == nil || h.ladders == nil {
+		return nil
+	}
+	name, ladder := h.ladders.EscalationLadderFor(issue.Type)
+	if ladder == nil {
+		return nil
+	}
+
+	decision := h.escalations.Plan(issue, name, ladder)
+	h.log.WithFields(logrus.Fields{
+		"incident_id": issue.ID,
+		"group_id":    decision.GroupID,
+		"ladder":      name,
+		"step":        decision.Step,
+		"action":      decision.Action,
+		"occurrence":  decision.Occurrence,
+	}).Info("Escalation step planned for remediation")
+	return decision
+}
+
+// recordEscalation records that the planned escalation step was taken
+func (h *RemediationHandler) recordEscalation(decision *escalation.Decision, incidentID string) {
+	if _, err := h.escalations.Record(decision, incidentID); err != nil {
+		h.log.WithError(err).WithField("group_id", decision.GroupID).Warn("Failed to persist incident group")
+	}
+}
+
+// scaleUp takes a scale escalation step. A resource without a Deployment or
+// StatefulSet to scale is paged instead.
+func (h *RemediationHandler) scaleUp(ctx context.Context, req *TriggerRemediationRequest, issue *models.Issue, decision *escalation.Decision) (*TriggerRemediationResponse, error) {
+	kind, name, err := h.scaler.Workload(ctx, issue.Namespace, issue.ResourceType, issue.ResourceName)
+	if errors.Is(err, escalation.ErrNotScalable) {
+		decision.Action = runtimeconfig.EscalationPage
+		decision.Detail = err.Error()
+		return h.page(req, issue, decision)
+	}
+	if err == nil {
+		err = h.admit(ctx, issue)
+	}
+	var from, to int32
+	if err == nil {
+		from, to, err = h.scaler.ScaleUp(ctx, issue.Namespace, kind, name, decision.Replicas)
+	}
+	switch {
+	case budgetExceeded(err) != nil:
+		return nil, budgetExceeded(err)
+	case err != nil:
+		h.log.WithError(err).Error("Failed to scale workload")
+		return nil, &RequestError{
+			StatusCode: http.StatusBadGateway,
+			Message:    "Failed to scale workload",
+			Details:    err.Error(),
+		}
+	}
+
+	decision.Detail = fmt.Sprintf("scaled %s/%s from %d to %d replicas", kind, name, from, to)
+	h.log.WithFields(logrus.Fields{
+		"incident_id": issue.ID,
+		"workload":    kind + "/" + name,
+		"replicas":    to,
+	}).Info("Recurring incident escalated by scaling its workload")
+	return &TriggerRemediationResponse{Status: "scaled"}, nil
+}
+
+// page takes a page escalation step: instead of remediating, it adds an escalated
+// event to the incident, which webhooks deliver as incident.escalated. An incident
+// that is not stored yet is created.
+func (h *RemediationHandler) page(req *TriggerRemediationRequest, issue *models.Issue, decision *escalation.Decision) (*TriggerRemediationResponse, error) {
+	message := decision.Message
+	if message == "" {
+		message = fmt.Sprintf("%s on %s/%s recurred %d times despite automated remediation; a human needs to investigate",
+			issue.Type, strings.ToLower(issue.ResourceType), issue.ResourceName, decision.Occurrence)
+	}
+	if decision.Detail != "" {
+		message = decision.Detail + "; " + message
+	}
+	decision.Detail = message
+
+	var err error
+	if stored, getErr := h.incidentStore.Get(req.IncidentID); getErr == nil {
+		updated := *stored
+		updated.Labels = make(map[string]string, len(stored.Labels)+1)
+		for name, value := range stored.Labels {
+			updated.Labels[name] = value
+		}
+		updated.Labels[escalation.LabelGroup] = decision.GroupID
+		updated.Events = append([]models.IncidentEvent(nil), stored.Events...)
+		updated.AddEvent(models.IncidentEventEscalated, message)
+		err = h.incidentStore.Update(&updated)
+	} else {
+		severity := models.IncidentSeverityHigh
+		if models.IsValidSeverity(issue.Severity) && models.IncidentSeverity(issue.Severity).Rank() > severity.Rank() {
+			severity = models.IncidentSeverity(issue.Severity)
+		}
+		incident := &models.Incident{
+			ID:                req.IncidentID,
+			Title:             fmt.Sprintf("Recurring %s on %s", issue.Type, issue.ResourceName),
+			Description:       message,
+			Severity:          severity,
+			Target:            issue.Namespace,
+			AffectedResources: []string{strings.ToLower(issue.ResourceType) + "/" + issue.ResourceName},
+			Labels: map[string]string{
+				"namespace":           issue.Namespace,
+				"issue_type":          issue.Type,
+				escalation.LabelGroup: decision.GroupID,
+			},
+		}
+		incident.AddEvent(models.IncidentEventEscalated, message)
+		_, err = h.incidentStore.Create(incident)
+	}
+	if err != nil {
+		h.log.WithError(err).Error("Failed to page for recurring incident")
+		return nil, &RequestError{
+			StatusCode: http.StatusInternalServerError,
+			Message:    "Failed to page for recurring incident",
+			Details:    err.Error(),
+		}
+	}
+
+	h.log.WithFields(logrus.Fields{
+		"incident_id": req.IncidentID,
+		"group_id":    decision.GroupID,
+	}).Warn("Recurring incident escalated to a human")
+	return &TriggerRemediationResponse{Status: "paged"}, nil
+}
+
+// budgetExceeded returns a 429 RequestError if err is a remediation budget error, or nil
+func budgetExceeded(err error) *RequestError {
+	var budgetErr *remediation.BudgetExceededError
+	if !errors.As(err, &budgetErr) {
+		return nil
+	}
+	return &RequestError{
+		StatusCode: http.StatusTooManyRequests,
+		Message:    "Remediation budget exceeded",
+		Details:    budgetErr.Error() + "; an operator can approve exceeding it with POST /api/v1/remediation/budget/approvals",
+		Code:       ErrCodeRemediationBudgetExceeded,
+	}
+}
+
+// checkPolicies returns the remediation policy matching the issue, or an error if it
+// denies remediation. Unlike upgrade and MachineConfigPool checks, force does not
+// override a denying policy.
+func (h *RemediationHandler) checkPolicies(issue *models.Issue) (string, *runtimeconfig.Policy, error) {
+	if h.policies == nil {
+		return "", nil, nil
+	}
+	name, policy := h.policies.PolicyFor(issue)
+	if policy == nil || policy.Action != runtimeconfig.PolicyDeny {
+		return name, policy, nil
+	}
+
+	h.log.WithFields(logrus.Fields{
+		"incident_id": issue.ID,
+		"namespace":   issue.Namespace,
+		"issue_type":  issue.Type,
+		"policy":      name,
+	}).Info("Remediation denied by policy")
+
+	details := "remediation policy " + name + " denies automated remediation of this issue"
+	if policy.Reason != "" {
+		details += ": " + policy.Reason
+	}
+	return "", nil, &RequestError{
+		StatusCode: http.StatusForbidden,
+		Message:    "Remediation denied by policy",
+		Details:    details,
+		Code:       ErrCodeRemediationDenied,
+	}
+}
+
+// checkClusterUpgrade rejects automated remediation while the cluster is upgrading
+// and the upgrade policy blocks it. It returns nil when monitor is nil or force is set.
+func checkClusterUpgrade(monitor *upgrade.Monitor, force bool, source string) *RequestError {
+	if monitor == nil || force || !monitor.RemediationBlocked() {
+		return nil
+	}
+
+	upgrade.RecordBlockedRemediation(source)
+	state := monitor.State()
+	details := "automated remediation is disabled during cluster upgrades; retry after the upgrade completes or set force=true"
+	if state.DesiredVersion != "" {
+		details = fmt.Sprintf("cluster is upgrading from %s to %s; %s", state.Version, state.DesiredVersion, details)
+	}
+
+	return &RequestError{
+		StatusCode: http.StatusConflict,
+		Message:    "Remediation blocked: cluster upgrade in progress",
+		Details:    details,
+		Code:       ErrCodeClusterUpgradeInProgress,
+	}
+}
+
+// checkObserverMode rejects a remediation request when the engine runs in observer
+// mode. Unlike the other checks it cannot be forced.
+func checkObserverMode(observerMode bool, source string) *RequestError {
+	if !observerMode {
+		return nil
+	}
+
+	observer.RecordBlocked(source)
+	return &RequestError{
+		StatusCode: http.StatusForbidden,
+		Message:    "Remediation disabled: the engine runs in observer mode",
+		Details:    "observer mode reports issues and remediation plans but never changes the cluster; unset OBSERVER_MODE to enable remediation",
+		Code:       ErrCodeObserverMode,
+	}
+}
+
+// checkMachineConfigUpdates rejects remediation of a resource whose restarts or
+// evictions are explained by an in-progress MachineConfigPool update. Detection
+// failures do not block remediation.
+func (h *RemediationHandler) checkMachineConfigUpdates(ctx context.Context, req *TriggerRemediationRequest) error {
+	if h.mcpDetector == nil || req.Force {
+		return nil
+	}
+
+	var pod, node string
+	switch req.Resource.Kind {
+	case "Pod":
+		pod = req.Resource.Name
+	case "Node":
+		node = req.Resource.Name
+	}
+
+	updates, err := h.mcpDetector.UpdatesAffecting(ctx, req.Namespace, pod, node)
+	if err != nil {
+		h.log.WithError(err).Warn("Failed to check for MachineConfigPool updates, continuing with remediation")
+		return nil
+	}
+	if len(updates) == 0 {
+		return nil
+	}
+
+	detector.RecordExpectedChurn("remediation")
+	pools := make([]string, 0, len(updates))
+	for _, u := range updates {
+		pools = append(pools, fmt.Sprintf("%s (%d/%d machines updated)", u.Pool, u.UpdatedMachines, u.MachineCount))
+	}
+	h.log.WithFields(logrus.Fields{
+		"incident_id": req.IncidentID,
+		"resource":    req.Resource.Name,
+		"pools":       pools,
+	}).Info("Remediation deferred during MachineConfigPool update")
+
+	return &RequestError{
+		StatusCode: http.StatusConflict,
+		Message:    "Remediation deferred: MachineConfigPool update in progress",
+		Details:    "node drains and reboots are expected while pools " + strings.Join(pools, ", ") + " update; retry after the update completes or set force=true",
+		Code:       ErrCodeMachineConfigUpdateInProgress,
+	}
 }
 
 // GetWorkflow handles GET /api/v1/workflows/{id}
@@ -223,7 +860,32 @@ func (h *RemediationHandler) GetWorkflow(w http.ResponseWriter, r *http.Request)
 	}).Info("Workflow details retrieved successfully")
 }
 
+// GetWorkflowEvents handles GET /api/v1/workflows/{id}/events
+func (h *RemediationHandler) GetWorkflowEvents(w http.ResponseWriter, r *http.Request) {
+	workflowID := mux.Vars(r)["id"]
+
+	events, err := h.orchestrator.WorkflowEvents(workflowID)
+	if err != nil {
+		h.sendErrorResponse(w, http.StatusNotFound, "Workflow not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(WorkflowEventsResponse{WorkflowID: workflowID, Events: events, Total: len(events)}); err != nil {
+		h.log.WithError(err).Error("Failed to encode workflow events response")
+	}
+}
+
 // CreateIncident handles POST /api/v1/incidents
+// @Summary Create an incident
+// @Tags incidents
+// @Accept json
+// @Produce json
+// @Param request body CreateIncidentRequest true "Incident"
+// @Success 201 {object} CreateIncidentResponse
+// @Failure 400 {object} ErrorResponse
+// @Router /api/v1/incidents [post]
 func (h *RemediationHandler) CreateIncident(w http.ResponseWriter, r *http.Request) {
 	h.log.Info("Received create incident request")
 
@@ -231,7 +893,17 @@ func (h *RemediationHandler) CreateIncident(w http.ResponseWriter, r *http.Reque
 	var req CreateIncidentRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		h.log.WithError(err).Error("Failed to decode request body")
-		h.sendErrorResponse(w, http.StatusBadRequest, "Invalid request body: "+err.Error())
+		h.sendErrorResponse(w, http.StatusBadRequest, "Invalid request body: "+err.Error(), validation.DecodeFields(err)...)
+		return
+	}
+	if req.AnalysisID != "" && (h.artifacts == nil || !h.artifacts.Pending(req.AnalysisID)) {
+		h.sendErrorResponse(w, http.StatusBadRequest, "analysis_id does not name a recent analysis",
+			validation.FieldError{
+				Field:      "analysis_id",
+				Constraint: validation.ConstraintUnknown,
+				Value:      req.AnalysisID,
+				Message:    "analysis_id must name a recent analysis that is not yet attached to an incident",
+			})
 		return
 	}
 
@@ -243,13 +915,14 @@ func (h *RemediationHandler) CreateIncident(w http.ResponseWriter, r *http.Reque
 		Target:            req.Target,
 		AffectedResources: req.AffectedResources,
 		Labels:            req.Labels,
+		Tags:              req.Tags,
 	}
 
 	// Store incident (validation happens in Create)
 	createdIncident, err := h.incidentStore.Create(incident)
 	if err != nil {
 		h.log.WithError(err).Error("Failed to create incident")
-		h.sendErrorResponse(w, http.StatusBadRequest, err.Error())
+		h.sendErrorResponse(w, http.StatusBadRequest, err.Error(), validation.Fields(err)...)
 		return
 	}
 
@@ -266,8 +939,24 @@ func (h *RemediationHandler) CreateIncident(w http.ResponseWriter, r *http.Reque
 		IncidentID: createdIncident.ID,
 		CreatedAt:  createdIncident.CreatedAt.Format(time.RFC3339),
 		Incident:   createdIncident,
+		Runbooks:   h.incidentRunbooks(createdIncident),
 		Message:    "Incident created successfully",
 	}
+	if req.AnalysisID != "" {
+		// The incident exists either way; a failure to persist its artifact is logged
+		if _, err := h.artifacts.Attach(req.AnalysisID, createdIncident.ID); err != nil {
+			h.log.WithError(err).WithField("incident_id", createdIncident.ID).Warn("Failed to attach analysis artifact")
+		} else {
+			response.AnalysisID = req.AnalysisID
+		}
+		attachAnomalies(h.anomalies, req.AnalysisID, createdIncident.ID, h.log)
+	}
+	if h.similar != nil {
+		response.SimilarIncidents = h.similar.Similar(createdIncident, 0)
+	}
+	if h.images != nil {
+		response.ImageVulnerabilities = h.incidentImages(r.Context(), createdIncident)
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
@@ -277,6 +966,18 @@ func (h *RemediationHandler) CreateIncident(w http.ResponseWriter, r *http.Reque
 }
 
 // ListIncidents handles GET /api/v1/incidents
+// @Summary List incidents
+// @Description Lists stored incidents and remediation workflows, newest first
+// @Tags incidents
+// @Produce json
+// @Param namespace query string false "Namespace"
+// @Param severity query string false "Severity (low, medium, high, critical)"
+// @Param status query string false "Status"
+// @Param sort query string false "Sort order: created_at (default) or priority"
+// @Param tag query string false "Only incidents with this tag (key or key:value), repeatable; excludes workflows"
+// @Success 200 {object} object "Incidents and their total"
+// @Failure 400 {object} ErrorResponse
+// @Router /api/v1/incidents [get]
 func (h *RemediationHandler) ListIncidents(w http.ResponseWriter, r *http.Request) {
 	h.log.Info("Listing incidents")
 
@@ -285,13 +986,25 @@ func (h *RemediationHandler) ListIncidents(w http.ResponseWriter, r *http.Reques
 	namespace := query.Get("namespace")
 	severity := query.Get("severity")
 	status := query.Get("status")
+	sortBy := query.Get("sort")
+	if sortBy != "" && sortBy != "created_at" && sortBy != "priority" {
+		h.sendErrorResponse(w, http.StatusBadRequest, "sort must be created_at or priority")
+		return
+	}
+	tags, fieldErr := parseTagFilter(query["tag"])
+	if fieldErr != nil {
+		h.sendErrorResponse(w, http.StatusBadRequest, fieldErr.Message, *fieldErr)
+		return
+	}
 
 	// Get manually created incidents from the store
 	filter := storage.ListFilter{
-		Namespace: namespace,
-		Severity:  severity,
-		Limit:     50, // Default limit
-		Status:    status,
+		Namespace:  namespace,
+		Severity:   severity,
+		Limit:      50, // Default limit
+		Status:     status,
+		ByPriority: sortBy == "priority",
+		Tags:       tags,
 	}
 	storedIncidents := h.incidentStore.List(filter)
 
@@ -309,22 +1022,33 @@ func (h *RemediationHandler) ListIncidents(w http.ResponseWriter, r *http.Reques
 			"description":        inc.Description,
 			"target":             inc.Target,
 			"severity":           string(inc.Severity),
+			"priority":           inc.EffectivePriority(),
 			"status":             string(inc.Status),
 			"created_at":         inc.CreatedAt.Format(time.RFC3339),
 			"affected_resources": inc.AffectedResources,
 			"labels":             inc.Labels,
+			"tags":               inc.Tags,
 			"source":             "manual",
 		}
 		if inc.WorkflowID != "" {
 			incident["workflow_id"] = inc.WorkflowID
 		}
+		if inc.Summary != "" {
+			incident["summary"] = inc.Summary
+		}
+		if inc.Owner != nil {
+			incident["owner"] = inc.Owner
+		}
+		if runbooks := h.incidentRunbooks(inc); len(runbooks) > 0 {
+			incident["runbooks"] = runbooks
+		}
 		incidents = append(incidents, incident)
 	}
 
 	// Add workflow-based incidents
 	for _, wf := range workflows {
-		// Apply namespace filter if specified
-		if namespace != "" && wf.Namespace != namespace {
+		// Apply namespace filter if specified; workflows have no tags
+		if (namespace != "" && wf.Namespace != namespace) || len(tags) > 0 {
 			continue
 		}
 
@@ -350,6 +1074,10 @@ func (h *RemediationHandler) ListIncidents(w http.ResponseWriter, r *http.Reques
 			incident["status"] = "in_progress"
 		}
 
+		if runbooks := h.runbooks.Lookup(wf.IssueType, ""); len(runbooks) > 0 {
+			incident["runbooks"] = runbooks
+		}
+
 		incidents = append(incidents, incident)
 	}
 
@@ -367,14 +1095,83 @@ func (h *RemediationHandler) ListIncidents(w http.ResponseWriter, r *http.Reques
 	h.log.WithField("count", len(incidents)).Info("Incidents listed successfully")
 }
 
+// SummarizeIncident handles POST /api/v1/incidents/{id}/summary
+func (h *RemediationHandler) SummarizeIncident(w http.ResponseWriter, r *http.Request) {
+	incidentID := mux.Vars(r)["id"]
+
+	if h.summarizer == nil {
+		h.sendErrorResponse(w, http.StatusServiceUnavailable, "Incident summarizer not configured (set SUMMARIZER_URL)")
+		return
+	}
+	if _, err := h.incidentStore.Get(incidentID); err != nil {
+		h.sendErrorResponse(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	incident, err := h.summarizer.SummarizeByID(r.Context(), incidentID)
+	if err != nil {
+		h.log.WithError(err).WithField("incident_id", incidentID).Error("Failed to summarize incident")
+		h.sendErrorResponse(w, http.StatusBadGateway, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(incident); err != nil {
+		h.log.WithError(err).Error("Failed to encode incident summary response")
+	}
+}
+
+// SimilarIncidents handles GET /api/v1/incidents/{id}/similar
+func (h *RemediationHandler) SimilarIncidents(w http.ResponseWriter, r *http.Request) {
+	incidentID := mux.Vars(r)["id"]
+
+	if h.similar == nil {
+		h.sendErrorResponse(w, http.StatusServiceUnavailable, "Similar incident lookup not enabled (set INCIDENT_SIMILARITY_ENABLED)")
+		return
+	}
+
+	limit := 0
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed < 1 || parsed > 20 {
+			h.sendErrorResponse(w, http.StatusBadRequest, "limit must be between 1 and 20",
+				validation.FieldError{Field: "limit", Constraint: validation.ConstraintRange, Value: limitStr, Message: "limit must be between 1 and 20"})
+			return
+		}
+		limit = parsed
+	}
+
+	incident, err := h.incidentStore.Get(incidentID)
+	if err != nil {
+		h.sendErrorResponse(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	lookup := h.similar.Similar(incident, limit)
+	h.log.WithFields(logrus.Fields{
+		"incident_id": incidentID,
+		"similar":     len(lookup.Incidents),
+	}).Debug("Similar incidents looked up")
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(lookup); err != nil {
+		h.log.WithError(err).Error("Failed to encode similar incidents response")
+	}
+}
+
 // sendErrorResponse sends a JSON error response
-func (h *RemediationHandler) sendErrorResponse(w http.ResponseWriter, statusCode int, message string) {
+func (h *RemediationHandler) sendErrorResponse(w http.ResponseWriter, statusCode int, message string, fieldErrs ...validation.FieldError) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
-	response := map[string]string{
+	response := map[string]interface{}{
 		"status": "error",
 		"error":  message,
 	}
+	if len(fieldErrs) > 0 {
+		response["errors"] = fieldErrs
+	}
 	if err := json.NewEncoder(w).Encode(response); err != nil {
 		h.log.WithError(err).Error("Failed to encode error response")
 	}
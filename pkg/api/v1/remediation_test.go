@@ -0,0 +1,445 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/detector"
+	"github.com/tosin2013/openshift-coordination-engine/internal/integrations"
+	"github.com/tosin2013/openshift-coordination-engine/internal/knowledge"
+	"github.com/tosin2013/openshift-coordination-engine/internal/remediation"
+	"github.com/tosin2013/openshift-coordination-engine/internal/runtimeconfig"
+	"github.com/tosin2013/openshift-coordination-engine/internal/upgrade"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/api/validation"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// fakeSummarizer sets a fixed summary on the stored incident
+type fakeSummarizer struct {
+	handler *RemediationHandler
+	err     error
+}
+
+func (f *fakeSummarizer) SummarizeByID(_ context.Context, id string) (*models.Incident, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	incident, err := f.handler.GetIncidentStore().Get(id)
+	if err != nil {
+		return nil, err
+	}
+	incident.Summary = "API pods were OOMKilled in production."
+	return incident, nil
+}
+
+func TestSummarizeIncident(t *testing.T) {
+	t.Setenv("DATA_DIR", t.TempDir())
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	handler := NewRemediationHandler(nil, log)
+	incident, err := handler.GetIncidentStore().Create(&models.Incident{
+		Title:       "Pods OOMKilled",
+		Description: "api pods restarted",
+		Severity:    models.IncidentSeverityHigh,
+		Target:      "production",
+	})
+	require.NoError(t, err)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v1/incidents/{id}/summary", handler.SummarizeIncident).Methods("POST")
+	summarize := func(id string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/v1/incidents/"+id+"/summary", http.NoBody))
+		return rr
+	}
+
+	// Summarizer not configured
+	assert.Equal(t, http.StatusServiceUnavailable, summarize(incident.ID).Code)
+
+	summarizer := &fakeSummarizer{handler: handler}
+	handler.SetSummarizer(summarizer)
+
+	assert.Equal(t, http.StatusNotFound, summarize("inc-missing").Code)
+
+	rr := summarize(incident.ID)
+	require.Equal(t, http.StatusOK, rr.Code)
+	var got models.Incident
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &got))
+	assert.Equal(t, "API pods were OOMKilled in production.", got.Summary)
+
+	summarizer.err = errors.New("model not loaded")
+	assert.Equal(t, http.StatusBadGateway, summarize(incident.ID).Code)
+}
+
+func TestSimilarIncidents(t *testing.T) {
+	t.Setenv("DATA_DIR", t.TempDir())
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	handler := NewRemediationHandler(nil, log)
+	past, err := handler.GetIncidentStore().Create(&models.Incident{
+		Title:       "Pods OOMKilled",
+		Description: "api pods restarted after running out of memory",
+		Severity:    models.IncidentSeverityHigh,
+		Target:      "production",
+		Labels:      map[string]string{"issue_type": "oom_killed"},
+	})
+	require.NoError(t, err)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v1/incidents", handler.CreateIncident).Methods("POST")
+	router.HandleFunc("/api/v1/incidents/{id}/similar", handler.SimilarIncidents).Methods("GET")
+	get := func(path string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path, http.NoBody))
+		return rr
+	}
+
+	// Lookup not enabled
+	assert.Equal(t, http.StatusServiceUnavailable, get("/api/v1/incidents/"+past.ID+"/similar").Code)
+
+	handler.SetSimilarityIndex(knowledge.NewIndex(handler.GetIncidentStore(), nil, 0, 0))
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/v1/incidents", strings.NewReader(
+		`{"title":"Pods OOMKilled again","description":"api pods out of memory","severity":"high","target":"production","labels":{"issue_type":"oom_killed"}}`)))
+	require.Equal(t, http.StatusCreated, rr.Code)
+	var created CreateIncidentResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &created))
+	require.NotNil(t, created.SimilarIncidents)
+	require.Len(t, created.SimilarIncidents.Incidents, 1)
+	assert.Equal(t, past.ID, created.SimilarIncidents.Incidents[0].ID)
+	assert.Equal(t, "1 similar past incident", created.SimilarIncidents.Summary)
+
+	rr = get("/api/v1/incidents/" + created.IncidentID + "/similar?limit=5")
+	require.Equal(t, http.StatusOK, rr.Code)
+	var lookup knowledge.Lookup
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &lookup))
+	assert.Len(t, lookup.Incidents, 1)
+
+	// The first incident has no past incidents
+	rr = get("/api/v1/incidents/" + past.ID + "/similar")
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &lookup))
+	assert.Empty(t, lookup.Incidents)
+
+	assert.Equal(t, http.StatusNotFound, get("/api/v1/incidents/inc-missing/similar").Code)
+	assert.Equal(t, http.StatusBadRequest, get("/api/v1/incidents/"+past.ID+"/similar?limit=0").Code)
+}
+
+func TestRemediationHandler_CreateIncident_ImageVulnerabilities(t *testing.T) {
+	t.Setenv("DATA_DIR", t.TempDir())
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	handler := NewRemediationHandler(nil, log)
+	scanner, image := testImageScanner(t, func(image string) []runtime.Object {
+		return []runtime.Object{crashingPod("api-1", image, 5), crashingPod("api-2", image, 2)}
+	})
+	handler.SetImageScanner(scanner)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v1/incidents", handler.CreateIncident).Methods("POST")
+	create := func(body string) CreateIncidentResponse {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/v1/incidents", strings.NewReader(body)))
+		require.Equal(t, http.StatusCreated, rr.Code)
+		var created CreateIncidentResponse
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &created))
+		return created
+	}
+
+	created := create(`{"title":"api crashing","description":"api pods in CrashLoopBackOff","severity":"high","target":"shop","affected_resources":["pod/api-1","pod/api-2"]}`)
+	require.Len(t, created.ImageVulnerabilities, 1, "the pods run the same image")
+	assert.Equal(t, image, created.ImageVulnerabilities[0].Image)
+	assert.Equal(t, []string{"CVE-2023-0286"}, created.ImageVulnerabilities[0].CriticalIDs())
+
+	created = create(`{"title":"node pressure","description":"worker-1 under memory pressure","severity":"high","target":"shop","affected_resources":["node/worker-1"]}`)
+	assert.Empty(t, created.ImageVulnerabilities)
+}
+
+// newUpdatingMCPDetector returns a detector for a cluster whose worker pool is updating
+// worker-1, with pod apps/api-1 on worker-1 and apps/router-1 on the stable infra-0
+func newUpdatingMCPDetector(t *testing.T) *detector.MachineConfigUpdateDetector {
+	t.Helper()
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	pool := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "machineconfiguration.openshift.io/v1",
+		"kind":       "MachineConfigPool",
+		"metadata":   map[string]interface{}{"name": "worker"},
+		"status": map[string]interface{}{
+			"machineCount":        int64(3),
+			"updatedMachineCount": int64(1),
+			"conditions":          []interface{}{map[string]interface{}{"type": "Updating", "status": "True"}},
+		},
+	}}
+	node := func(name, current, desired string) *corev1.Node {
+		return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: map[string]string{
+			detector.CurrentConfigAnnotation: current,
+			detector.DesiredConfigAnnotation: desired,
+		}}}
+	}
+	pod := func(name, nodeName string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "apps"}, Spec: corev1.PodSpec{NodeName: nodeName}}
+	}
+
+	clientset := fake.NewSimpleClientset(
+		node("worker-1", "rendered-worker-old", "rendered-worker-new"),
+		node("infra-0", "rendered-infra-a", "rendered-infra-a"),
+		pod("api-1", "worker-1"),
+		pod("router-1", "infra-0"),
+	)
+	mco := integrations.NewMCOClient(dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), pool), log)
+	return detector.NewMachineConfigUpdateDetector(clientset, mco, log)
+}
+
+func TestRemediationHandler_Trigger_MachineConfigUpdate(t *testing.T) {
+	t.Setenv("DATA_DIR", t.TempDir())
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	handler := NewRemediationHandler(nil, log)
+	handler.SetMachineConfigUpdateDetector(newUpdatingMCPDetector(t))
+
+	req := &TriggerRemediationRequest{IncidentID: "inc-1", Namespace: "apps"}
+	req.Resource.Kind = "Pod"
+	req.Resource.Name = "api-1"
+	req.Issue.Type = "CrashLoopBackOff"
+
+	_, err := handler.Trigger(context.Background(), req)
+	var requestErr *RequestError
+	require.True(t, errors.As(err, &requestErr))
+	assert.Equal(t, http.StatusConflict, requestErr.StatusCode)
+	assert.Equal(t, ErrCodeMachineConfigUpdateInProgress, requestErr.Code)
+	assert.Contains(t, requestErr.Details, "worker (1/3 machines updated)")
+
+	// Pods on nodes of stable pools and forced requests are not deferred
+	req.Resource.Name = "router-1"
+	assert.NoError(t, handler.checkMachineConfigUpdates(context.Background(), req))
+	req.Resource.Name = "api-1"
+	req.Force = true
+	assert.NoError(t, handler.checkMachineConfigUpdates(context.Background(), req))
+}
+
+// newUpgradingMonitor returns an upgrade monitor that has observed a cluster
+// upgrading from 4.16.3 to 4.16.5
+func newUpgradingMonitor(t *testing.T) *upgrade.Monitor {
+	t.Helper()
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	clusterVersion := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "config.openshift.io/v1",
+		"kind":       "ClusterVersion",
+		"metadata":   map[string]interface{}{"name": "version"},
+		"status": map[string]interface{}{
+			"desired": map[string]interface{}{"version": "4.16.5"},
+			"history": []interface{}{
+				map[string]interface{}{"state": "Partial", "version": "4.16.5"},
+				map[string]interface{}{"state": "Completed", "version": "4.16.3"},
+			},
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Progressing", "status": "True"},
+			},
+		},
+	}}
+	client := integrations.NewClusterVersionClient(dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), clusterVersion), log)
+	monitor := upgrade.NewMonitor(client, upgrade.Policy{BlockRemediation: true, ThresholdIncrease: 0.15, TagIncidents: true}, log)
+	require.NoError(t, monitor.Refresh(context.Background()))
+	return monitor
+}
+
+func TestRemediationHandler_TriggerRemediation_FieldErrors(t *testing.T) {
+	t.Setenv("DATA_DIR", t.TempDir())
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	handler := NewRemediationHandler(nil, log)
+
+	body := `{"incident_id": "inc-1", "namespace": "apps", "resource": {"kind": "Deployment"}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/remediation/trigger", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	handler.TriggerRemediation(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	var resp struct {
+		Status string                  `json:"status"`
+		Error  string                  `json:"error"`
+		Errors []validation.FieldError `json:"errors"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Equal(t, "error", resp.Status)
+	require.Len(t, resp.Errors, 2)
+	assert.Equal(t, "resource.name", resp.Errors[0].Field)
+	assert.Equal(t, validation.ConstraintRequired, resp.Errors[0].Constraint)
+	assert.Equal(t, "issue.type", resp.Errors[1].Field)
+}
+
+func TestRemediationHandler_Trigger_ClusterUpgrade(t *testing.T) {
+	t.Setenv("DATA_DIR", t.TempDir())
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	monitor := newUpgradingMonitor(t)
+	handler := NewRemediationHandler(nil, log)
+	handler.SetUpgradeMonitor(monitor)
+
+	req := &TriggerRemediationRequest{IncidentID: "inc-1", Namespace: "apps"}
+	req.Resource.Kind = "Deployment"
+	req.Resource.Name = "api"
+	req.Issue.Type = "CrashLoopBackOff"
+
+	_, err := handler.Trigger(context.Background(), req)
+	var requestErr *RequestError
+	require.True(t, errors.As(err, &requestErr))
+	assert.Equal(t, http.StatusConflict, requestErr.StatusCode)
+	assert.Equal(t, ErrCodeClusterUpgradeInProgress, requestErr.Code)
+	assert.Contains(t, requestErr.Details, "from 4.16.3 to 4.16.5")
+
+	assert.Nil(t, checkClusterUpgrade(monitor, true, "remediation"), "forced requests are not blocked")
+	assert.Nil(t, checkClusterUpgrade(nil, false, "remediation"))
+
+	// Incidents created during the upgrade are tagged, without overwriting labels
+	store := handler.GetIncidentStore()
+	store.SetLabeler(monitor)
+	incident, err := store.Create(&models.Incident{
+		Title:       "API pods restarting",
+		Description: "Pods restarting during upgrade",
+		Severity:    models.IncidentSeverityHigh,
+		Target:      "apps",
+		Labels:      map[string]string{upgrade.LabelUpgradeTargetVersion: "custom"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "true", incident.Labels[upgrade.LabelDuringUpgrade])
+	assert.Equal(t, "custom", incident.Labels[upgrade.LabelUpgradeTargetVersion])
+}
+
+func TestRemediationHandler_Trigger_ObserverMode(t *testing.T) {
+	t.Setenv("DATA_DIR", t.TempDir())
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	handler := NewRemediationHandler(nil, log)
+	handler.SetObserverMode(true)
+
+	req := &TriggerRemediationRequest{IncidentID: "inc-1", Namespace: "apps", Force: true}
+	req.Resource.Kind = "Deployment"
+	req.Resource.Name = "api"
+	req.Issue.Type = "CrashLoopBackOff"
+
+	_, err := handler.Trigger(context.Background(), req)
+	var requestErr *RequestError
+	require.True(t, errors.As(err, &requestErr), "force does not override observer mode")
+	assert.Equal(t, http.StatusForbidden, requestErr.StatusCode)
+	assert.Equal(t, ErrCodeObserverMode, requestErr.Code)
+	assert.Nil(t, checkObserverMode(false, "remediation"))
+}
+
+func TestRemediationHandler_Trigger_Policy(t *testing.T) {
+	t.Setenv("DATA_DIR", t.TempDir())
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	policies := runtimeconfig.NewStore(t.TempDir())
+	_, err := policies.Create(runtimeconfig.KindPolicy, "deny-payments", json.RawMessage(
+		`{"match":{"namespaces":["payments"]},"action":"deny","reason":"change freeze until Friday"}`))
+	require.NoError(t, err)
+	handler := NewRemediationHandler(nil, log)
+	handler.SetPolicies(policies)
+
+	req := &TriggerRemediationRequest{IncidentID: "inc-1", Namespace: "payments", Force: true}
+	req.Resource.Kind = "Deployment"
+	req.Resource.Name = "api"
+	req.Issue.Type = "CrashLoopBackOff"
+
+	_, err = handler.Trigger(context.Background(), req)
+	var requestErr *RequestError
+	require.True(t, errors.As(err, &requestErr), "force does not override a denying policy")
+	assert.Equal(t, http.StatusForbidden, requestErr.StatusCode)
+	assert.Equal(t, ErrCodeRemediationDenied, requestErr.Code)
+	assert.Equal(t, "remediation policy deny-payments denies automated remediation of this issue: change freeze until Friday", requestErr.Details)
+
+	// Issues no denying policy matches pass the check
+	name, policy, err := handler.checkPolicies(&models.Issue{Namespace: "checkout", Type: "CrashLoopBackOff"})
+	require.NoError(t, err)
+	assert.Empty(t, name)
+	assert.Nil(t, policy)
+}
+
+// fakeRemediator succeeds on every remediation
+type fakeRemediator struct{}
+
+func (fakeRemediator) Remediate(context.Context, *models.DeploymentInfo, *models.Issue) error {
+	return nil
+}
+
+func (fakeRemediator) CanRemediate(*models.DeploymentInfo) bool { return true }
+
+func (fakeRemediator) Name() string { return "fake" }
+
+func TestRemediationHandler_WorkflowEvents(t *testing.T) {
+	t.Setenv("DATA_DIR", t.TempDir())
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	orchestrator := remediation.NewOrchestrator(detector.NewDeploymentDetector(fake.NewSimpleClientset(), log), fakeRemediator{}, log)
+	handler := NewRemediationHandler(orchestrator, log)
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v1/workflows/{id}", handler.GetWorkflow).Methods("GET")
+	router.HandleFunc("/api/v1/workflows/{id}/events", handler.GetWorkflowEvents).Methods("GET")
+
+	req := &TriggerRemediationRequest{IncidentID: "inc-1", Namespace: "payments"}
+	req.Resource.Kind = "Deployment"
+	req.Resource.Name = "api"
+	req.Issue.Type = "CrashLoopBackOff"
+	triggered, err := handler.Trigger(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, string(models.DeploymentMethodUnknown), triggered.DeploymentMethod, "detection runs before the trigger returns")
+
+	var resp WorkflowEventsResponse
+	require.Eventually(t, func() bool {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/workflows/"+triggered.WorkflowID+"/events", http.NoBody))
+		require.Equal(t, http.StatusOK, rr.Code)
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+		return resp.Events[len(resp.Events)-1].Type == remediation.EventWorkflowCompleted
+	}, 5*time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, remediation.EventWorkflowCreated, resp.Events[0].Type)
+	assert.Equal(t, resp.Total, len(resp.Events))
+	for i, event := range resp.Events {
+		assert.Equal(t, int64(i+1), event.Sequence)
+	}
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/workflows/"+triggered.WorkflowID, http.NoBody))
+	var workflow WorkflowResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &workflow))
+	assert.Equal(t, string(models.WorkflowStatusCompleted), workflow.Status)
+	require.Len(t, workflow.Steps, 2)
+	assert.Equal(t, "remediate", workflow.Steps[1].Name)
+	assert.Equal(t, 1, workflow.Steps[1].Attempts)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/workflows/wf-missing/events", http.NoBody))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
@@ -0,0 +1,245 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/integrations"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/api/validation"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/capacity"
+)
+
+// Right-sizing export formats
+const (
+	RightsizingFormatJSON  = "json"
+	RightsizingFormatVPA   = "vpa"
+	RightsizingFormatPatch = "patch"
+)
+
+// rightsizingWindows are the supported usage windows
+var rightsizingWindows = map[string]bool{"1d": true, "7d": true, "14d": true, "30d": true}
+
+// RightsizingResponse represents the API response for a right-sizing report
+type RightsizingResponse struct {
+	Status     string                      `json:"status"`
+	Namespace  string                      `json:"namespace"`
+	Workload   string                      `json:"workload,omitempty"`
+	Window     string                      `json:"window"`
+	Margins    capacity.RightsizingMargins `json:"margins"`
+	Timestamp  time.Time                   `json:"timestamp"`
+	Summary    RightsizingSummary          `json:"summary"`
+	Containers []capacity.ContainerSizing  `json:"containers"`
+}
+
+// RightsizingSummary counts containers by suggested CPU and memory request change
+type RightsizingSummary struct {
+	Containers int            `json:"containers"`
+	NoData     int            `json:"no_data"`
+	CPU        map[string]int `json:"cpu"`
+	Memory     map[string]int `json:"memory"`
+}
+
+// RightsizingOptions selects what a right-sizing report covers
+type RightsizingOptions struct {
+	Namespace string
+	Workload  string // optional Deployment, StatefulSet or DaemonSet name
+	Window    string // 1d, 7d, 14d, 30d
+	Margins   capacity.RightsizingMargins
+}
+
+// Rightsizing handles GET /api/v1/rightsizing
+// @Summary Get workload right-sizing recommendations
+// @Description Compares p50/p95/p99 container usage over a window with configured requests and limits and suggests new values, as JSON, VerticalPodAutoscaler objects or patch YAML
+// @Tags capacity
+// @Produce json
+// @Produce application/yaml
+// @Param namespace query string true "Namespace name"
+// @Param workload query string false "Deployment, StatefulSet or DaemonSet name"
+// @Param window query string false "Usage window - 1d, 7d, 14d, 30d (default: 7d)"
+// @Param request_margin query number false "Margin added to p95 usage for requests, 0-1 (default: 0.15)"
+// @Param limit_margin query number false "Margin added to p99 usage for limits, 0-1 (default: 0.30)"
+// @Param format query string false "json, vpa or patch (default: json)"
+// @Success 200 {object} RightsizingResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v1/rightsizing [get]
+func (h *CapacityHandler) Rightsizing(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	var fieldErrs validation.Errors
+	format := query.Get("format")
+	if format == "" {
+		format = RightsizingFormatJSON
+	}
+	if format != RightsizingFormatJSON && format != RightsizingFormatVPA && format != RightsizingFormatPatch {
+		fieldErrs.Add("format", validation.ConstraintEnum, format, "format must be one of: json, vpa, patch")
+	}
+
+	opts := RightsizingOptions{
+		Namespace: query.Get("namespace"),
+		Workload:  query.Get("workload"),
+		Window:    query.Get("window"),
+		Margins: capacity.RightsizingMargins{
+			Request: capacity.DefaultRequestMargin,
+			Limit:   capacity.DefaultLimitMargin,
+		},
+	}
+	margins := []struct {
+		name  string
+		value *float64
+	}{
+		{"request_margin", &opts.Margins.Request},
+		{"limit_margin", &opts.Margins.Limit},
+	}
+	for _, margin := range margins {
+		value := query.Get(margin.name)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil || parsed < 0 || parsed > 1 {
+			fieldErrs.Add(margin.name, validation.ConstraintRange, value, margin.name+" must be a number between 0 and 1")
+			continue
+		}
+		*margin.value = parsed
+	}
+	if len(fieldErrs) > 0 {
+		h.respondError(w, http.StatusBadRequest, fieldErrs.Error(), fieldErrs...)
+		return
+	}
+
+	h.log.WithFields(logrus.Fields{
+		"namespace": opts.Namespace,
+		"workload":  opts.Workload,
+		"window":    opts.Window,
+		"format":    format,
+	}).Info("Right-sizing request received")
+
+	response, err := h.AnalyzeRightsizing(r.Context(), opts)
+	if err != nil {
+		var requestErr *RequestError
+		if errors.As(err, &requestErr) {
+			h.respondError(w, requestErr.StatusCode, requestErr.Message, requestErr.Errors...)
+			return
+		}
+		h.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	switch format {
+	case RightsizingFormatVPA:
+		h.respondYAML(w, capacity.ExportVPA, response.Containers)
+	case RightsizingFormatPatch:
+		h.respondYAML(w, capacity.ExportPatches, response.Containers)
+	default:
+		h.respondJSON(w, http.StatusOK, response)
+	}
+}
+
+// AnalyzeRightsizing compares observed container usage percentiles with the requests
+// and limits of a namespace's workloads and suggests new values
+func (h *CapacityHandler) AnalyzeRightsizing(ctx context.Context, opts RightsizingOptions) (*RightsizingResponse, error) {
+	var fieldErrs validation.Errors
+	if opts.Namespace == "" {
+		fieldErrs.Add("namespace", validation.ConstraintRequired, nil, "namespace is required")
+	}
+	if opts.Window == "" {
+		opts.Window = "7d"
+	}
+	if !rightsizingWindows[opts.Window] {
+		fieldErrs.Add("window", validation.ConstraintEnum, opts.Window, "window must be one of: 1d, 7d, 14d, 30d")
+	}
+	if err := fieldErrs.Err(); err != nil {
+		return nil, invalidRequest(err, "")
+	}
+	if h.prometheusClient == nil || !h.prometheusClient.IsAvailable() {
+		return nil, &RequestError{StatusCode: http.StatusServiceUnavailable, Message: "prometheus is not available"}
+	}
+
+	workloads, err := h.analyzer.ListWorkloadContainers(ctx, opts.Namespace, opts.Workload)
+	if err != nil {
+		h.log.WithError(err).WithField("namespace", opts.Namespace).Error("Failed to list workloads")
+		return nil, &RequestError{StatusCode: http.StatusInternalServerError, Message: "failed to list workloads"}
+	}
+	if opts.Workload != "" && len(workloads) == 0 {
+		return nil, &RequestError{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("workload %s not found in namespace %s", opts.Workload, opts.Namespace),
+		}
+	}
+
+	usage, err := h.prometheusClient.GetContainerUsagePercentiles(ctx, opts.Namespace, opts.Window)
+	if err != nil {
+		var costErr *integrations.QueryCostError
+		if errors.As(err, &costErr) {
+			return nil, &RequestError{StatusCode: http.StatusUnprocessableEntity, Message: costErr.Error()}
+		}
+		h.log.WithError(err).WithField("namespace", opts.Namespace).Error("Failed to query container usage")
+		return nil, &RequestError{StatusCode: http.StatusBadGateway, Message: "failed to query container usage from prometheus"}
+	}
+
+	observed := make([]capacity.ContainerUsage, 0, len(usage))
+	for _, u := range usage {
+		observed = append(observed, capacity.ContainerUsage{
+			Pod:       u.Pod,
+			Container: u.Container,
+			CPU:       capacity.Percentiles{P50: u.CPU.P50, P95: u.CPU.P95, P99: u.CPU.P99},
+			Memory:    capacity.Percentiles{P50: u.Memory.P50, P95: u.Memory.P95, P99: u.Memory.P99},
+		})
+	}
+
+	sizings := capacity.RecommendSizing(workloads, observed, opts.Margins)
+	summary := RightsizingSummary{
+		Containers: len(sizings),
+		CPU:        make(map[string]int),
+		Memory:     make(map[string]int),
+	}
+	for i := range sizings {
+		if !sizings[i].HasSuggestion() {
+			summary.NoData++
+			continue
+		}
+		summary.CPU[sizings[i].CPU.Action]++
+		summary.Memory[sizings[i].Memory.Action]++
+	}
+
+	h.log.WithFields(logrus.Fields{
+		"namespace":  opts.Namespace,
+		"containers": summary.Containers,
+		"no_data":    summary.NoData,
+	}).Info("Right-sizing analysis completed")
+
+	return &RightsizingResponse{
+		Status:     "success",
+		Namespace:  opts.Namespace,
+		Workload:   opts.Workload,
+		Window:     opts.Window,
+		Margins:    opts.Margins,
+		Timestamp:  h.now().UTC(),
+		Summary:    summary,
+		Containers: sizings,
+	}, nil
+}
+
+// respondYAML writes right-sizing suggestions rendered by export
+func (h *CapacityHandler) respondYAML(w http.ResponseWriter, export func([]capacity.ContainerSizing) (string, error), sizings []capacity.ContainerSizing) {
+	body, err := export(sizings)
+	if err != nil {
+		h.log.WithError(err).Error("Failed to render right-sizing YAML")
+		h.respondError(w, http.StatusInternalServerError, "failed to render right-sizing YAML")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/yaml")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write([]byte(body)); err != nil {
+		h.log.WithError(err).Error("Failed to write YAML response")
+	}
+}
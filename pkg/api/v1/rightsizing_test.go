@@ -0,0 +1,140 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/integrations"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/capacity"
+)
+
+// newRightsizingHandler creates a capacity handler backed by a payments/api Deployment
+// and a Prometheus server reporting fixed usage for one of its pods
+func newRightsizingHandler(t *testing.T) *CapacityHandler {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		value := "0.2"
+		if strings.Contains(r.URL.Query().Get("query"), "container_memory_working_set_bytes") {
+			value = "268435456"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[` +
+			`{"metric":{"pod":"api-7d9f5c8b4-x2x7k","container":"api"},"value":[1700000000,"` + value + `"]}]}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "payments"},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name: "api",
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{
+								corev1.ResourceCPU:    resource.MustParse("1"),
+								corev1.ResourceMemory: resource.MustParse("1Gi"),
+							},
+						},
+					}},
+				},
+			},
+		},
+	}
+
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	promClient := integrations.NewPrometheusClient(server.URL, 5*time.Second, log)
+	return NewCapacityHandler(fake.NewSimpleClientset(deployment), promClient, log)
+}
+
+func TestCapacityHandler_Rightsizing(t *testing.T) {
+	handler := newRightsizingHandler(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/rightsizing?namespace=payments&request_margin=0", http.NoBody)
+	rr := httptest.NewRecorder()
+	handler.Rightsizing(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var response RightsizingResponse
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&response))
+
+	assert.Equal(t, "success", response.Status)
+	assert.Equal(t, "7d", response.Window)
+	assert.Equal(t, capacity.RightsizingMargins{Request: 0, Limit: capacity.DefaultLimitMargin}, response.Margins)
+	assert.Equal(t, 1, response.Summary.Containers)
+	assert.Equal(t, 1, response.Summary.CPU[capacity.SizingActionDecrease])
+
+	require.Len(t, response.Containers, 1)
+	sizing := response.Containers[0]
+	assert.Equal(t, "Deployment", sizing.Kind)
+	assert.Equal(t, 1, sizing.Pods)
+	assert.Equal(t, "1", sizing.CPU.Request)
+	assert.Equal(t, "200m", sizing.CPU.SuggestedRequest)
+	assert.Equal(t, "260m", sizing.CPU.SuggestedLimit)
+	assert.Equal(t, "256Mi", sizing.Memory.SuggestedRequest)
+}
+
+func TestCapacityHandler_Rightsizing_Export(t *testing.T) {
+	handler := newRightsizingHandler(t)
+
+	for format, want := range map[string]string{
+		"vpa":   "kind: VerticalPodAutoscaler",
+		"patch": "# kubectl -n payments patch deployment api",
+	} {
+		t.Run(format, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/rightsizing?namespace=payments&workload=api&format="+format, http.NoBody)
+			rr := httptest.NewRecorder()
+			handler.Rightsizing(rr, req)
+
+			require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+			assert.Equal(t, "application/yaml", rr.Header().Get("Content-Type"))
+			assert.Contains(t, rr.Body.String(), want)
+		})
+	}
+}
+
+func TestCapacityHandler_Rightsizing_InvalidRequest(t *testing.T) {
+	handler := newRightsizingHandler(t)
+
+	tests := []struct {
+		name   string
+		query  string
+		status int
+	}{
+		{"missing namespace", "", http.StatusBadRequest},
+		{"invalid window", "namespace=payments&window=3d", http.StatusBadRequest},
+		{"invalid margin", "namespace=payments&limit_margin=2", http.StatusBadRequest},
+		{"invalid format", "namespace=payments&format=helm", http.StatusBadRequest},
+		{"unknown workload", "namespace=payments&workload=web", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/rightsizing?"+tt.query, http.NoBody)
+			rr := httptest.NewRecorder()
+			handler.Rightsizing(rr, req)
+			assert.Equal(t, tt.status, rr.Code, rr.Body.String())
+		})
+	}
+
+	// Prometheus is required
+	noProm := NewCapacityHandler(fake.NewSimpleClientset(), nil, logrus.New())
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/rightsizing?namespace=payments", http.NoBody)
+	rr := httptest.NewRecorder()
+	noProm.Rightsizing(rr, req)
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+}
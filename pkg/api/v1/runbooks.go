@@ -0,0 +1,113 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/api/validation"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/runbook"
+)
+
+// RunbookHandler serves the runbook registry and knowledge-base search
+type RunbookHandler struct {
+	registry *runbook.Registry
+	log      *logrus.Logger
+}
+
+// NewRunbookHandler creates a new runbook handler
+func NewRunbookHandler(registry *runbook.Registry, log *logrus.Logger) *RunbookHandler {
+	return &RunbookHandler{
+		registry: registry,
+		log:      log,
+	}
+}
+
+// RunbookListResponse is the response for listing and searching runbooks
+type RunbookListResponse struct {
+	Runbooks []runbook.Runbook `json:"runbooks"`
+	Total    int               `json:"total"`
+	Warning  string            `json:"warning,omitempty"`
+}
+
+// RegisterRoutes registers runbook API routes
+func (h *RunbookHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/api/v1/runbooks", h.ListRunbooks).Methods("GET")
+	router.HandleFunc("/api/v1/runbooks/search", h.SearchRunbooks).Methods("GET")
+
+	h.log.Info("Runbook API routes registered: /api/v1/runbooks, /api/v1/runbooks/search")
+}
+
+// ListRunbooks handles GET /api/v1/runbooks
+// Query parameters issue_type and alertname return only the runbooks linked to them.
+func (h *RunbookHandler) ListRunbooks(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	issueType := query.Get("issue_type")
+	alertName := query.Get("alertname")
+
+	var runbooks []runbook.Runbook
+	if issueType != "" || alertName != "" {
+		runbooks = h.registry.Lookup(issueType, alertName)
+	} else {
+		runbooks = h.registry.List()
+	}
+	if runbooks == nil {
+		runbooks = []runbook.Runbook{}
+	}
+
+	h.respondJSON(w, http.StatusOK, RunbookListResponse{Runbooks: runbooks, Total: len(runbooks)})
+}
+
+// SearchRunbooks handles GET /api/v1/runbooks/search?q=...&limit=...
+// Results come from the registry first, then from the configured wiki provider. If the
+// provider fails, registry results are still returned with a warning.
+func (h *RunbookHandler) SearchRunbooks(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	q := query.Get("q")
+	if q == "" {
+		h.respondError(w, http.StatusBadRequest, "q is required",
+			validation.FieldError{Field: "q", Constraint: validation.ConstraintRequired, Message: "q is required"})
+		return
+	}
+
+	limit := runbook.DefaultSearchLimit
+	if limitStr := query.Get("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed < 1 || parsed > 100 {
+			h.respondError(w, http.StatusBadRequest, "limit must be between 1 and 100",
+				validation.FieldError{Field: "limit", Constraint: validation.ConstraintRange, Value: limitStr, Message: "limit must be between 1 and 100"})
+			return
+		}
+		limit = parsed
+	}
+
+	runbooks, err := h.registry.Search(r.Context(), q, limit)
+	response := RunbookListResponse{Runbooks: runbooks, Total: len(runbooks)}
+	if err != nil {
+		response.Warning = err.Error()
+	}
+
+	h.respondJSON(w, http.StatusOK, response)
+}
+
+func (h *RunbookHandler) respondJSON(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		h.log.WithError(err).Error("Failed to encode JSON response")
+	}
+}
+
+func (h *RunbookHandler) respondError(w http.ResponseWriter, statusCode int, message string, fieldErrs ...validation.FieldError) {
+	response := map[string]interface{}{
+		"status": "error",
+		"error":  message,
+	}
+	if len(fieldErrs) > 0 {
+		response["errors"] = fieldErrs
+	}
+	h.respondJSON(w, statusCode, response)
+}
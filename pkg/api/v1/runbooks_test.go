@@ -0,0 +1,128 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/storage"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/runbook"
+)
+
+type failingProvider struct{}
+
+func (failingProvider) Name() string { return "wiki" }
+
+func (failingProvider) Search(context.Context, string, int) ([]runbook.Runbook, error) {
+	return nil, errors.New("unauthorized")
+}
+
+func newTestRunbookRegistry(t *testing.T) *runbook.Registry {
+	t.Helper()
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	registry, err := runbook.NewRegistry([]runbook.Runbook{
+		{
+			ID:         "crashloop",
+			Title:      "Pod crash looping",
+			URL:        "https://wiki.example.com/runbooks/crashloop",
+			IssueTypes: []string{"pod_crash_loop"},
+			AlertNames: []string{"KubePodCrashLooping"},
+		},
+		{
+			ID:         "high-severity",
+			Title:      "High severity triage",
+			Content:    "1. Page the owning team",
+			IssueTypes: []string{"high"},
+		},
+	}, log)
+	require.NoError(t, err)
+	return registry
+}
+
+func TestRunbookHandler(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	registry := newTestRunbookRegistry(t)
+	registry.SetProvider(failingProvider{})
+
+	router := mux.NewRouter()
+	NewRunbookHandler(registry, log).RegisterRoutes(router)
+
+	get := func(url string) (int, RunbookListResponse) {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, url, http.NoBody))
+		var resp RunbookListResponse
+		_ = json.Unmarshal(rr.Body.Bytes(), &resp)
+		return rr.Code, resp
+	}
+
+	code, resp := get("/api/v1/runbooks")
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 2, resp.Total)
+
+	code, resp = get("/api/v1/runbooks?alertname=KubePodCrashLooping")
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, resp.Runbooks, 1)
+	assert.Equal(t, "crashloop", resp.Runbooks[0].ID)
+
+	code, resp = get("/api/v1/runbooks?issue_type=disk_pressure")
+	require.Equal(t, http.StatusOK, code)
+	assert.NotNil(t, resp.Runbooks)
+	assert.Zero(t, resp.Total)
+
+	// Provider failures degrade to registry results with a warning
+	code, resp = get("/api/v1/runbooks/search?q=crash")
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, resp.Runbooks, 1)
+	assert.Contains(t, resp.Warning, "wiki search failed")
+
+	code, _ = get("/api/v1/runbooks/search")
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = get("/api/v1/runbooks/search?q=crash&limit=0")
+	assert.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestRunbooks_LinkedToRecommendationsAndIncidents(t *testing.T) {
+	t.Setenv("DATA_DIR", t.TempDir())
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	registry := newTestRunbookRegistry(t)
+
+	remediationHandler := NewRemediationHandler(nil, log)
+	remediationHandler.SetRunbooks(registry)
+	store := remediationHandler.GetIncidentStore()
+	for i := 0; i < 2; i++ {
+		_, err := store.Create(&models.Incident{
+			Title:       "Pods crash looping",
+			Description: "api pods in CrashLoopBackOff",
+			Severity:    models.IncidentSeverityHigh,
+			Target:      "production",
+			Labels:      map[string]string{"alertname": "KubePodCrashLooping"},
+		})
+		require.NoError(t, err)
+	}
+
+	// Historical recommendations use the incident severity as issue type
+	recommendationsHandler := NewRecommendationsHandler(nil, store, nil, log)
+	recommendationsHandler.SetRunbooks(registry)
+	resp, err := recommendationsHandler.Recommend(context.Background(), &GetRecommendationsRequest{ConfidenceThreshold: 0.1})
+	require.NoError(t, err)
+	require.NotEmpty(t, resp.Recommendations)
+	require.Len(t, resp.Recommendations[0].Runbooks, 1)
+	assert.Equal(t, "high-severity", resp.Recommendations[0].Runbooks[0].ID)
+
+	incidents := store.List(storage.ListFilter{})
+	runbooks := remediationHandler.incidentRunbooks(incidents[0])
+	require.Len(t, runbooks, 1)
+	assert.Equal(t, "crashloop", runbooks[0].ID)
+}
@@ -0,0 +1,42 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/security"
+)
+
+// SecurityHandler reports the TLS configuration of the engine's outbound clients
+type SecurityHandler struct {
+	auditor *security.Auditor
+	log     *logrus.Logger
+}
+
+// NewSecurityHandler creates a new security status handler
+func NewSecurityHandler(auditor *security.Auditor, log *logrus.Logger) *SecurityHandler {
+	return &SecurityHandler{
+		auditor: auditor,
+		log:     log,
+	}
+}
+
+// RegisterRoutes registers security API routes
+func (h *SecurityHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/api/v1/status/security", h.GetStatus).Methods("GET")
+
+	h.log.Info("Security API routes registered: /api/v1/status/security")
+}
+
+// GetStatus handles GET /api/v1/status/security
+// It returns the TLS versions and cipher suites each outbound client allows and flags
+// insecure or non-FIPS-compatible settings.
+func (h *SecurityHandler) GetStatus(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(h.auditor.Report()); err != nil {
+		h.log.WithError(err).Error("Failed to encode security status response")
+	}
+}
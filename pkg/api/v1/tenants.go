@@ -0,0 +1,272 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/retention"
+	"github.com/tosin2013/openshift-coordination-engine/internal/tenant"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/api/validation"
+)
+
+// Tenants returns the tenant onboarding a namespace, or nil if the namespace is not
+// onboarded (implemented by tenant.Registry)
+type Tenants interface {
+	For(ctx context.Context, namespace string) *tenant.Tenant
+}
+
+// TenantHandler onboards and offboards tenants
+type TenantHandler struct {
+	registry *tenant.Registry
+	purger   *retention.Purger
+	log      *logrus.Logger
+}
+
+// NewTenantHandler creates a tenant handler. registry may be nil, in which case the
+// endpoints respond 503.
+func NewTenantHandler(registry *tenant.Registry, log *logrus.Logger) *TenantHandler {
+	return &TenantHandler{
+		registry: registry,
+		log:      log,
+	}
+}
+
+// SetPurger purges the data of offboarded tenants' namespaces
+func (h *TenantHandler) SetPurger(purger *retention.Purger) {
+	h.purger = purger
+}
+
+// ListTenantsResponse is the response of GET /api/v1/tenants
+type ListTenantsResponse struct {
+	Tenants []*tenant.Tenant `json:"tenants"`
+	Total   int              `json:"total"`
+}
+
+// OffboardTenantResponse is the response of DELETE /api/v1/tenants/{name}
+type OffboardTenantResponse struct {
+	Status string         `json:"status"`
+	Tenant *tenant.Tenant `json:"tenant"`
+
+	// Namespaces are the namespaces whose data was purged; Results counts the purged
+	// items per data type across them
+	Namespaces []string                `json:"namespaces"`
+	Results    []retention.PurgeResult `json:"results"`
+
+	// DataRetained is set when the data was left to expire under the retention policy
+	DataRetained bool   `json:"data_retained,omitempty"`
+	Error        string `json:"error,omitempty"`
+}
+
+// RegisterRoutes registers the tenant routes
+func (h *TenantHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/api/v1/tenants", h.CreateTenant).Methods("POST")
+	router.HandleFunc("/api/v1/tenants", h.ListTenants).Methods("GET")
+	router.HandleFunc("/api/v1/tenants/{name}", h.GetTenant).Methods("GET")
+	router.HandleFunc("/api/v1/tenants/{name}", h.UpdateTenant).Methods("PUT")
+	router.HandleFunc("/api/v1/tenants/{name}", h.OffboardTenant).Methods("DELETE")
+
+	h.log.Info("Tenant API routes registered: /api/v1/tenants, /api/v1/tenants/{name}")
+}
+
+// CreateTenant handles POST /api/v1/tenants
+func (h *TenantHandler) CreateTenant(w http.ResponseWriter, r *http.Request) {
+	if !h.available(w) {
+		return
+	}
+
+	var body tenant.Tenant
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.respondError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err), validation.DecodeFields(err)...)
+		return
+	}
+	created, err := h.registry.Create(&body)
+	if err != nil {
+		h.respondRegistryError(w, err)
+		return
+	}
+
+	h.log.WithFields(logrus.Fields{
+		"tenant": created.Name,
+		"scope":  created.Scope(),
+	}).Info("Tenant onboarded")
+	h.respondJSON(w, http.StatusCreated, created)
+}
+
+// ListTenants handles GET /api/v1/tenants
+func (h *TenantHandler) ListTenants(w http.ResponseWriter, _ *http.Request) {
+	if !h.available(w) {
+		return
+	}
+
+	tenants := h.registry.List()
+	h.respondJSON(w, http.StatusOK, ListTenantsResponse{Tenants: tenants, Total: len(tenants)})
+}
+
+// GetTenant handles GET /api/v1/tenants/{name}
+func (h *TenantHandler) GetTenant(w http.ResponseWriter, r *http.Request) {
+	if !h.available(w) {
+		return
+	}
+
+	found, err := h.registry.Get(mux.Vars(r)["name"])
+	if err != nil {
+		h.respondRegistryError(w, err)
+		return
+	}
+	h.respondJSON(w, http.StatusOK, found)
+}
+
+// UpdateTenant handles PUT /api/v1/tenants/{name}: the body replaces the tenant's
+// scope and settings
+func (h *TenantHandler) UpdateTenant(w http.ResponseWriter, r *http.Request) {
+	if !h.available(w) {
+		return
+	}
+
+	var body tenant.Tenant
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.respondError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err), validation.DecodeFields(err)...)
+		return
+	}
+	name := mux.Vars(r)["name"]
+	if body.Name != "" && body.Name != name {
+		h.respondError(w, http.StatusBadRequest, "name cannot be changed",
+			validation.FieldError{Field: "name", Constraint: validation.ConstraintFormat, Value: body.Name, Message: "name must match the tenant in the path"})
+		return
+	}
+	updated, err := h.registry.Update(name, &body)
+	if err != nil {
+		h.respondRegistryError(w, err)
+		return
+	}
+
+	h.log.WithFields(logrus.Fields{
+		"tenant": updated.Name,
+		"scope":  updated.Scope(),
+	}).Info("Tenant updated")
+	h.respondJSON(w, http.StatusOK, updated)
+}
+
+// OffboardTenant handles DELETE /api/v1/tenants/{name}. Scanners stop covering the
+// tenant's namespaces and their data is purged, unless keep_data=true leaves it to
+// expire under the retention policy. The namespaces of selector tenants are resolved
+// before the tenant is removed.
+func (h *TenantHandler) OffboardTenant(w http.ResponseWriter, r *http.Request) {
+	if !h.available(w) {
+		return
+	}
+
+	name := mux.Vars(r)["name"]
+	current, err := h.registry.Get(name)
+	if err != nil {
+		h.respondRegistryError(w, err)
+		return
+	}
+	keepData := r.URL.Query().Get("keep_data") == "true"
+
+	var namespaces []string
+	if !keepData && h.purger != nil {
+		if namespaces, err = h.registry.Namespaces(r.Context(), current); err != nil {
+			h.log.WithError(err).WithField("tenant", name).Error("Failed to resolve tenant namespaces")
+			h.respondError(w, http.StatusBadGateway, err.Error())
+			return
+		}
+	}
+
+	offboarded, err := h.registry.Delete(name)
+	if err != nil {
+		h.respondRegistryError(w, err)
+		return
+	}
+	response := OffboardTenantResponse{
+		Status:       "offboarded",
+		Tenant:       offboarded,
+		Namespaces:   make([]string, 0, len(namespaces)),
+		Results:      make([]retention.PurgeResult, 0),
+		DataRetained: keepData || h.purger == nil,
+	}
+	for _, namespace := range namespaces {
+		results, err := h.purger.PurgeNamespace(namespace)
+		response.Results = addPurgeResults(response.Results, results)
+		if err != nil {
+			h.log.WithError(err).WithFields(logrus.Fields{"tenant": name, "namespace": namespace}).Error("Failed to purge tenant data")
+			response.Error = err.Error()
+			break
+		}
+		response.Namespaces = append(response.Namespaces, namespace)
+	}
+
+	h.log.WithFields(logrus.Fields{
+		"tenant":        name,
+		"namespaces":    response.Namespaces,
+		"data_retained": response.DataRetained,
+	}).Info("Tenant offboarded")
+	h.respondJSON(w, http.StatusOK, response)
+}
+
+// addPurgeResults adds purge counts to totals per data type
+func addPurgeResults(totals, results []retention.PurgeResult) []retention.PurgeResult {
+	for _, result := range results {
+		found := false
+		for i := range totals {
+			if totals[i].DataType == result.DataType {
+				totals[i].Purged += result.Purged
+				found = true
+				break
+			}
+		}
+		if !found {
+			totals = append(totals, result)
+		}
+	}
+	return totals
+}
+
+// respondRegistryError maps tenant registry errors to a status
+func (h *TenantHandler) respondRegistryError(w http.ResponseWriter, err error) {
+	switch {
+	case validation.Fields(err) != nil:
+		h.respondError(w, http.StatusBadRequest, err.Error(), validation.Fields(err)...)
+	case errors.Is(err, tenant.ErrNotFound):
+		h.respondError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, tenant.ErrAlreadyExists), errors.Is(err, tenant.ErrLimitReached):
+		h.respondError(w, http.StatusConflict, err.Error())
+	default:
+		h.log.WithError(err).Error("Tenant registry operation failed")
+		h.respondError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// available responds 503 and returns false if tenant onboarding is not enabled
+func (h *TenantHandler) available(w http.ResponseWriter) bool {
+	if h.registry == nil {
+		h.respondError(w, http.StatusServiceUnavailable, "tenant onboarding is not enabled (set TENANTS_ENABLED)")
+		return false
+	}
+	return true
+}
+
+func (h *TenantHandler) respondJSON(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		h.log.WithError(err).Error("Failed to encode JSON response")
+	}
+}
+
+func (h *TenantHandler) respondError(w http.ResponseWriter, statusCode int, message string, fieldErrs ...validation.FieldError) {
+	response := map[string]interface{}{
+		"status": "error",
+		"error":  message,
+	}
+	if len(fieldErrs) > 0 {
+		response["errors"] = fieldErrs
+	}
+	h.respondJSON(w, statusCode, response)
+}
@@ -0,0 +1,173 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/notification"
+	"github.com/tosin2013/openshift-coordination-engine/internal/retention"
+	"github.com/tosin2013/openshift-coordination-engine/internal/storage"
+	"github.com/tosin2013/openshift-coordination-engine/internal/tenant"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+func newTestTenantRegistry(t *testing.T) *tenant.Registry {
+	t.Helper()
+	return tenant.NewRegistry(t.TempDir(), fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "checkout", Labels: map[string]string{"team": "shop"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "cart", Labels: map[string]string{"team": "shop"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "sandbox"}},
+	))
+}
+
+func TestTenantHandler(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	store := storage.NewIncidentStoreWithPath(t.TempDir())
+	for _, target := range []string{"checkout", "cart", "cart", "sandbox"} {
+		_, err := store.Create(&models.Incident{
+			Title:       "Pods restarting",
+			Description: "Pods restarting",
+			Severity:    models.IncidentSeverityLow,
+			Target:      target,
+		})
+		require.NoError(t, err)
+	}
+	purger := retention.NewPurger(log)
+	purger.Add(retention.IncidentRule(store, 90*24*time.Hour))
+
+	registry := newTestTenantRegistry(t)
+	handler := NewTenantHandler(registry, log)
+	handler.SetPurger(purger)
+	router := mux.NewRouter()
+	handler.RegisterRoutes(router)
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := send("POST", "/api/v1/tenants", `{"name": "payments", "namespace": "payments", "criticality": "critical",
+		"notifications": [{"url": "https://hooks.example.com/payments", "secret": "s3cret", "events": ["*"]}]}`)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	assert.NotContains(t, rr.Body.String(), "s3cret")
+
+	rr = send("POST", "/api/v1/tenants", `{"name": "shop", "selector": {"team": "shop"}, "thresholds": {"anomaly": 0.9}}`)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+
+	t.Run("validation", func(t *testing.T) {
+		rr := send("POST", "/api/v1/tenants", `{"name": "Bad Name", "namespace": "x", "thresholds": {"anomaly": 2}}`)
+		require.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Contains(t, rr.Body.String(), `"field":"name"`)
+		assert.Contains(t, rr.Body.String(), `"field":"thresholds.anomaly"`)
+
+		rr = send("POST", "/api/v1/tenants", `{"name": "payments-eu", "namespace": "payments"}`)
+		assert.Equal(t, http.StatusConflict, rr.Code)
+
+		rr = send("PUT", "/api/v1/tenants/payments", `{"name": "renamed", "namespace": "payments"}`)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	rr = send("GET", "/api/v1/tenants", "")
+	require.Equal(t, http.StatusOK, rr.Code)
+	var list ListTenantsResponse
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&list))
+	assert.Equal(t, 2, list.Total)
+
+	rr = send("PUT", "/api/v1/tenants/payments", `{"namespace": "payments", "criticality": "high"}`)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	assert.Equal(t, tenant.CriticalityHigh, registry.For(context.Background(), "payments").Criticality)
+	assert.Equal(t, http.StatusNotFound, send("PUT", "/api/v1/tenants/missing", `{"namespace": "missing"}`).Code)
+	assert.Equal(t, http.StatusNotFound, send("GET", "/api/v1/tenants/missing", "").Code)
+
+	// Offboarding a selector tenant purges every namespace it matched
+	rr = send("DELETE", "/api/v1/tenants/shop", "")
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var offboarded OffboardTenantResponse
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&offboarded))
+	assert.Equal(t, "offboarded", offboarded.Status)
+	assert.Equal(t, []string{"cart", "checkout"}, offboarded.Namespaces)
+	assert.Equal(t, []retention.PurgeResult{{DataType: retention.DataTypeIncidents, Purged: 3}}, offboarded.Results)
+	assert.False(t, offboarded.DataRetained)
+	assert.Equal(t, 1, store.Count())
+	assert.Nil(t, registry.For(context.Background(), "cart"))
+
+	// keep_data leaves the data to the retention policy
+	rr = send("DELETE", "/api/v1/tenants/payments?keep_data=true", "")
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&offboarded))
+	assert.True(t, offboarded.DataRetained)
+	assert.Empty(t, offboarded.Namespaces)
+	assert.Equal(t, http.StatusNotFound, send("DELETE", "/api/v1/tenants/payments", "").Code)
+}
+
+func TestTenantHandler_Disabled(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	router := mux.NewRouter()
+	NewTenantHandler(nil, log).RegisterRoutes(router)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/api/v1/tenants", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	assert.Contains(t, rr.Body.String(), "TENANTS_ENABLED")
+}
+
+func TestAnomalySubscriptions_Tenants(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	registry := newTestTenantRegistry(t)
+	_, err := registry.Create(&tenant.Tenant{Name: "shop", Selector: map[string]string{"team": "shop"}})
+	require.NoError(t, err)
+
+	notifier, err := notification.NewWebhookNotifier(nil, notification.Options{}, log)
+	require.NoError(t, err)
+	subscriptions := NewAnomalySubscriptions(NewAnomalyHandler(nil, nil, log), notifier, AnomalySubscriptionOptions{}, log)
+	subscriptions.SetTenants(registry)
+	router := mux.NewRouter()
+	subscriptions.RegisterRoutes(router)
+
+	subscribe := func(namespace string) *httptest.ResponseRecorder {
+		body := `{"url": "https://hooks.example.com/scan", "analysis": {"namespace": "` + namespace + `"}}`
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest("POST", "/api/v1/anomalies/subscriptions", strings.NewReader(body)))
+		return rr
+	}
+
+	rr := subscribe("sandbox")
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.Contains(t, rr.Body.String(), ErrCodeScopeNotOnboarded)
+	assert.Equal(t, http.StatusForbidden, subscribe("").Code, "cluster-wide scans are not onboarded")
+
+	rr = subscribe("checkout")
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	var created AnomalySubscription
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&created))
+
+	// Scans of offboarded scopes are skipped
+	_, err = registry.Delete("shop")
+	require.NoError(t, err)
+	subscriptions.runDue(context.Background())
+	sub, ok := subscriptions.subscriptions[created.ID]
+	require.True(t, ok)
+	assert.Contains(t, sub.LastError, ErrCodeScopeNotOnboarded)
+	assert.Equal(t, 1, sub.Scans)
+}
@@ -0,0 +1,42 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/upgrade"
+)
+
+// UpgradeHandler reports the upgrade-aware conservative mode
+type UpgradeHandler struct {
+	monitor *upgrade.Monitor
+	log     *logrus.Logger
+}
+
+// NewUpgradeHandler creates a new upgrade status handler
+func NewUpgradeHandler(monitor *upgrade.Monitor, log *logrus.Logger) *UpgradeHandler {
+	return &UpgradeHandler{
+		monitor: monitor,
+		log:     log,
+	}
+}
+
+// RegisterRoutes registers upgrade API routes
+func (h *UpgradeHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/api/v1/upgrade/status", h.GetStatus).Methods("GET")
+
+	h.log.Info("Upgrade API routes registered: /api/v1/upgrade/status")
+}
+
+// GetStatus handles GET /api/v1/upgrade/status
+// It returns the last observed ClusterVersion state and the policy applied while
+// an upgrade is in progress.
+func (h *UpgradeHandler) GetStatus(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(h.monitor.State()); err != nil {
+		h.log.WithError(err).Error("Failed to encode upgrade status response")
+	}
+}
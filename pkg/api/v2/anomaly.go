@@ -0,0 +1,214 @@
+// Package v2 contains the /api/v2 HTTP handlers.
+//
+// v2 handlers share their analysis logic with v1 and differ only in response schemas,
+// so breaking schema changes can ship here while v1 stays stable.
+package v2
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/anomaly"
+	"github.com/tosin2013/openshift-coordination-engine/internal/detector"
+	v1 "github.com/tosin2013/openshift-coordination-engine/pkg/api/v1"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/api/validation"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/api/versioning"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/diagnostics"
+)
+
+// ScoreSourceHeuristic is the source of v2 anomaly scores: the model only returns a
+// label and the score is derived from metric deviations by the coordination engine
+const ScoreSourceHeuristic = "heuristic"
+
+// AnomalyHandler serves the v2 anomaly analysis API
+type AnomalyHandler struct {
+	analyzer *v1.AnomalyHandler
+	log      *logrus.Logger
+}
+
+// NewAnomalyHandler creates a v2 anomaly handler backed by the shared analysis logic
+func NewAnomalyHandler(analyzer *v1.AnomalyHandler, log *logrus.Logger) *AnomalyHandler {
+	return &AnomalyHandler{
+		analyzer: analyzer,
+		log:      log,
+	}
+}
+
+// RegisterRoutes registers v2 anomaly routes on the v2 router group
+func (h *AnomalyHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/anomalies/analyze", h.AnalyzeAnomalies).Methods("POST")
+	h.log.Info("Anomaly analysis API endpoints registered: POST /api/v2/anomalies/analyze")
+}
+
+// AnomalyScore is an anomaly score together with where it came from
+type AnomalyScore struct {
+	Value      float64 `json:"value"`       // 0.0-1.0, higher is more anomalous
+	Source     string  `json:"source"`      // heuristic
+	ModelLabel int     `json:"model_label"` // Raw model output: -1 anomaly, 1 normal
+}
+
+// Anomaly represents a detected anomaly
+type Anomaly struct {
+	Timestamp            string                         `json:"timestamp"`
+	Severity             string                         `json:"severity"`
+	Priority             int                            `json:"priority"` // 0-100, from the severity matrix
+	Score                AnomalyScore                   `json:"score"`
+	Confidence           float64                        `json:"confidence"`
+	Metrics              map[string]float64             `json:"metrics"`
+	Explanation          string                         `json:"explanation"`
+	RecommendedAction    string                         `json:"recommended_action"`
+	AlternativeActions   []string                       `json:"alternative_actions,omitempty"`
+	RecentChanges        []detector.RolloutChange       `json:"recent_changes,omitempty"`
+	MachineConfigUpdates []detector.MachineConfigUpdate `json:"machine_config_updates,omitempty"`
+	ExpectedChurn        bool                           `json:"expected_churn,omitempty"`
+	ExpectedLoad         bool                           `json:"expected_load,omitempty"`
+}
+
+// AnalyzeResponse is the v2 anomaly analysis response
+type AnalyzeResponse struct {
+	APIVersion     string              `json:"api_version"`
+	Status         string              `json:"status"`
+	TimeRange      string              `json:"time_range"`
+	Scope          v1.AnomalyScope     `json:"scope"`
+	Model          string              `json:"model"`
+	IsAnomalous    bool                `json:"is_anomalous"`
+	Anomalies      []Anomaly           `json:"anomalies"`
+	Summary        v1.AnomalySummary   `json:"summary"`
+	Recommendation string              `json:"recommendation"`
+	Features       v1.FeatureInfo      `json:"features"`
+	Debug          *diagnostics.Report `json:"debug,omitempty"`
+	EvaluatedAt    string              `json:"evaluated_at"`
+	AnalysisID     string              `json:"analysis_id,omitempty"`
+	ScoringHooks   []string            `json:"scoring_hooks,omitempty"`
+	Suppressed     string              `json:"suppressed,omitempty"`
+	SuppressedBy   string              `json:"suppressed_by,omitempty"`
+
+	UpgradeInProgress bool    `json:"upgrade_in_progress,omitempty"`
+	AppliedThreshold  float64 `json:"applied_threshold,omitempty"`
+	ScopeDefaults     string  `json:"scope_defaults,omitempty"`
+
+	BusinessWindows []anomaly.ActiveWindow `json:"business_windows,omitempty"`
+}
+
+// AnalyzeAnomalies handles POST /api/v2/anomalies/analyze
+// @Summary Analyze anomalies (v2 schema)
+// @ID analyzeAnomaliesV2
+// @Description Same analysis as v1, with scores reported together with their source and the raw model label
+// @Tags anomaly
+// @Accept json
+// @Produce json
+// @Param request body v1.AnomalyAnalyzeRequest true "Anomaly analysis request"
+// @Success 200 {object} AnalyzeResponse
+// @Failure 400 {object} v1.AnomalyErrorResponse
+// @Failure 422 {object} v1.AnomalyErrorResponse
+// @Failure 503 {object} v1.AnomalyErrorResponse
+// @Router /api/v2/anomalies/analyze [post]
+func (h *AnomalyHandler) AnalyzeAnomalies(w http.ResponseWriter, r *http.Request) {
+	contentType := r.Header.Get("Content-Type")
+	if contentType != "" && !strings.HasPrefix(contentType, "application/json") {
+		h.respondError(w, http.StatusBadRequest, "Content-Type must be application/json", "", v1.ErrCodeAnomalyInvalidRequest)
+		return
+	}
+
+	var req v1.AnomalyAnalyzeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log.WithError(err).Debug("Invalid anomaly analysis request format")
+		h.respondError(w, http.StatusBadRequest, "Invalid request format", err.Error(), v1.ErrCodeAnomalyInvalidRequest, validation.DecodeFields(err)...)
+		return
+	}
+
+	result, err := h.analyzer.Analyze(r.Context(), &req)
+	if err != nil {
+		var requestErr *v1.RequestError
+		if errors.As(err, &requestErr) {
+			h.respondError(w, requestErr.StatusCode, requestErr.Message, requestErr.Details, requestErr.Code, requestErr.Errors...)
+			return
+		}
+		h.respondError(w, http.StatusInternalServerError, "Anomaly analysis failed", err.Error(), v1.ErrCodeAnomalyAnalysisFailed)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, ConvertAnalyzeResponse(result))
+}
+
+// ConvertAnalyzeResponse converts a shared analysis result to the v2 schema
+func ConvertAnalyzeResponse(result *v1.AnomalyAnalyzeResponse) AnalyzeResponse {
+	label := 1
+	if len(result.ModelPredictions) > 0 {
+		label = result.ModelPredictions[0]
+	}
+
+	anomalies := make([]Anomaly, 0, len(result.Anomalies))
+	for _, a := range result.Anomalies {
+		anomalies = append(anomalies, Anomaly{
+			Timestamp: a.Timestamp,
+			Severity:  a.Severity,
+			Priority:  a.Priority,
+			Score: AnomalyScore{
+				Value:      a.AnomalyScore,
+				Source:     ScoreSourceHeuristic,
+				ModelLabel: label,
+			},
+			Confidence:           a.Confidence,
+			Metrics:              a.Metrics,
+			Explanation:          a.Explanation,
+			RecommendedAction:    a.RecommendedAction,
+			AlternativeActions:   a.AlternativeActions,
+			RecentChanges:        a.RecentChanges,
+			MachineConfigUpdates: a.MachineConfigUpdates,
+			ExpectedChurn:        a.ExpectedChurn,
+			ExpectedLoad:         a.ExpectedLoad,
+		})
+	}
+
+	return AnalyzeResponse{
+		APIVersion:     versioning.V2,
+		Status:         result.Status,
+		TimeRange:      result.TimeRange,
+		Scope:          result.Scope,
+		Model:          result.ModelUsed,
+		IsAnomalous:    label == -1,
+		Anomalies:      anomalies,
+		Summary:        result.Summary,
+		Recommendation: result.Recommendation,
+		Features:       result.Features,
+		Debug:          result.Debug,
+		EvaluatedAt:    result.EvaluatedAt,
+		AnalysisID:     result.AnalysisID,
+		ScoringHooks:   result.ScoringHooks,
+		Suppressed:     result.Suppressed,
+		SuppressedBy:   result.SuppressedBy,
+
+		UpgradeInProgress: result.UpgradeInProgress,
+		AppliedThreshold:  result.AppliedThreshold,
+		ScopeDefaults:     result.ScopeDefaults,
+
+		BusinessWindows: result.BusinessWindows,
+	}
+}
+
+// respondJSON writes a JSON response
+func (h *AnomalyHandler) respondJSON(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		h.log.WithError(err).Error("Failed to encode JSON response")
+	}
+}
+
+// respondError writes an error response in the shared error schema
+func (h *AnomalyHandler) respondError(w http.ResponseWriter, statusCode int, message, details, code string, fieldErrs ...validation.FieldError) {
+	h.respondJSON(w, statusCode, v1.AnomalyErrorResponse{
+		Status:  "error",
+		Error:   message,
+		Details: details,
+		Code:    code,
+		Errors:  fieldErrs,
+	})
+}
@@ -0,0 +1,101 @@
+package v2
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1 "github.com/tosin2013/openshift-coordination-engine/pkg/api/v1"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/api/versioning"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+func newTestHandler() *AnomalyHandler {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	return NewAnomalyHandler(v1.NewAnomalyHandler(nil, nil, log), log)
+}
+
+func TestAnomalyHandler_AnalyzeAnomalies_Errors(t *testing.T) {
+	handler := newTestHandler()
+	router := mux.NewRouter()
+	handler.RegisterRoutes(router.PathPrefix("/api/v2").Subrouter())
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+		code   string
+	}{
+		{name: "invalid json", body: `{`, status: http.StatusBadRequest, code: v1.ErrCodeAnomalyInvalidRequest},
+		{name: "invalid time range", body: `{"time_range":"2w"}`, status: http.StatusBadRequest, code: v1.ErrCodeAnomalyInvalidRequest},
+		{name: "kserve unavailable", body: `{"time_range":"1h"}`, status: http.StatusServiceUnavailable, code: v1.ErrCodeAnomalyKServeUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/v2/anomalies/analyze", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.status, rr.Code)
+
+			var resp v1.AnomalyErrorResponse
+			require.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+			assert.Equal(t, "error", resp.Status)
+			assert.Equal(t, tt.code, resp.Code)
+		})
+	}
+}
+
+func TestConvertAnalyzeResponse(t *testing.T) {
+	result := &v1.AnomalyAnalyzeResponse{
+		Status:            "success",
+		TimeRange:         "1h",
+		Scope:             v1.AnomalyScope{Scope: models.Scope{Namespace: "production"}, TargetDescription: "namespace 'production'"},
+		ModelUsed:         "anomaly-detector",
+		AnomaliesDetected: 1,
+		Anomalies: []v1.AnomalyResult{{
+			Severity:           "warning",
+			Priority:           82,
+			AnomalyScore:       0.82,
+			Confidence:         0.9,
+			Explanation:        "High CPU usage",
+			RecommendedAction:  "scale_up",
+			AlternativeActions: []string{"rollback_deployment"},
+		}},
+		Recommendation:   "WARNING",
+		ModelPredictions: []int{-1},
+	}
+
+	converted := ConvertAnalyzeResponse(result)
+
+	assert.Equal(t, versioning.V2, converted.APIVersion)
+	assert.True(t, converted.IsAnomalous)
+	assert.Equal(t, "anomaly-detector", converted.Model)
+	require.Len(t, converted.Anomalies, 1)
+	assert.Equal(t, AnomalyScore{Value: 0.82, Source: ScoreSourceHeuristic, ModelLabel: -1}, converted.Anomalies[0].Score)
+	assert.Equal(t, []string{"rollback_deployment"}, converted.Anomalies[0].AlternativeActions)
+	assert.Equal(t, 82, converted.Anomalies[0].Priority)
+
+	data, err := json.Marshal(converted)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"score":{"value":0.82,"source":"heuristic","model_label":-1}`)
+	assert.NotContains(t, string(data), `"anomaly_score"`)
+}
+
+func TestConvertAnalyzeResponse_Normal(t *testing.T) {
+	converted := ConvertAnalyzeResponse(&v1.AnomalyAnalyzeResponse{Status: "success", ModelPredictions: []int{1}})
+
+	assert.False(t, converted.IsAnomalous)
+	assert.NotNil(t, converted.Anomalies)
+	assert.Empty(t, converted.Anomalies)
+}
@@ -0,0 +1,124 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Rule checks a single field and returns its error, or nil if the field is valid.
+// Handlers describe a request as a list of rules and run them with Check, so the same
+// field gets the same constraint and message on every endpoint.
+type Rule func() *FieldError
+
+// Check runs the rules and returns the failures as Errors, or nil if every field is valid
+func Check(rules ...Rule) error {
+	var errs Errors
+	for _, rule := range rules {
+		if fieldErr := rule(); fieldErr != nil {
+			errs = append(errs, *fieldErr)
+		}
+	}
+	return errs.Err()
+}
+
+// Required fails when value is empty
+func Required(field, value string) Rule {
+	return RequiredWhen(true, "", field, value)
+}
+
+// RequiredWhen fails when cond holds and value is empty; reason completes the message
+// "<field> is required when <reason>"
+func RequiredWhen(cond bool, reason, field, value string) Rule {
+	return func() *FieldError {
+		if !cond || value != "" {
+			return nil
+		}
+		message := field + " is required"
+		if reason != "" {
+			message += " when " + reason
+		}
+		return &FieldError{Field: field, Constraint: ConstraintRequired, Message: message}
+	}
+}
+
+// Name fails when a non-empty value is not a valid name according to check, e.g.
+// k8s.io/apimachinery's IsDNS1123Label. Empty values pass; combine with Required.
+func Name(field, value string, check func(string) []string) Rule {
+	return func() *FieldError {
+		if value == "" {
+			return nil
+		}
+		problems := check(value)
+		if len(problems) == 0 {
+			return nil
+		}
+		return &FieldError{
+			Field:      field,
+			Constraint: ConstraintFormat,
+			Value:      value,
+			Message:    fmt.Sprintf("%s %q is invalid: %s", field, value, problems[0]),
+		}
+	}
+}
+
+// OneOf fails when value is not one of allowed
+func OneOf[T comparable](field string, value T, allowed ...T) Rule {
+	return func() *FieldError {
+		for _, a := range allowed {
+			if value == a {
+				return nil
+			}
+		}
+		names := make([]string, 0, len(allowed))
+		for _, a := range allowed {
+			names = append(names, fmt.Sprint(a))
+		}
+		return &FieldError{
+			Field:      field,
+			Constraint: ConstraintEnum,
+			Value:      value,
+			Message:    fmt.Sprintf("%s must be one of: %s", field, strings.Join(names, ", ")),
+		}
+	}
+}
+
+// Between fails when value is outside [minValue, maxValue]
+func Between[T int | float64](field string, value, minValue, maxValue T) Rule {
+	return func() *FieldError {
+		if value >= minValue && value <= maxValue {
+			return nil
+		}
+		return &FieldError{
+			Field:      field,
+			Constraint: ConstraintRange,
+			Value:      value,
+			Message:    fmt.Sprintf("%s must be between %s and %s", field, formatBound(minValue), formatBound(maxValue)),
+		}
+	}
+}
+
+// formatBound formats range bounds, with one decimal for fractions such as thresholds
+func formatBound[T int | float64](bound T) string {
+	if f, ok := any(bound).(float64); ok {
+		return fmt.Sprintf("%.1f", f)
+	}
+	return fmt.Sprint(bound)
+}
+
+// Default sets *field to value when it holds the zero value
+func Default[T comparable](field *T, value T) {
+	var zero T
+	if *field == zero {
+		*field = value
+	}
+}
+
+// DefaultThreshold is used for omitted anomaly score and confidence thresholds
+const DefaultThreshold = 0.7
+
+// Threshold fails when an anomaly score or confidence threshold is outside [0.0, 1.0].
+// Thresholds of 0 are replaced by DefaultThreshold before validation, so every
+// endpoint treats an omitted threshold the same way.
+func Threshold(field string, value float64) Rule {
+	return Between(field, value, 0.0, 1.0)
+}
@@ -0,0 +1,43 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheck(t *testing.T) {
+	assert.NoError(t, Check(
+		Required("namespace", "production"),
+		OneOf("time_range", "6h", "1h", "6h", "24h"),
+		Between("hour", 0, 0, 23),
+		Threshold("threshold", 1.0),
+	))
+
+	err := Check(
+		Required("namespace", ""),
+		OneOf("time_range", "2w", "1h", "6h", "24h"),
+		Between("hour", 24, 0, 23),
+		Threshold("threshold", 1.5),
+		RequiredWhen(false, "scope is 'pod'", "pod", ""),
+	)
+	fields := Fields(err)
+	require.Len(t, fields, 4)
+	assert.Equal(t, FieldError{Field: "namespace", Constraint: ConstraintRequired, Message: "namespace is required"}, fields[0])
+	assert.Equal(t, FieldError{Field: "time_range", Constraint: ConstraintEnum, Value: "2w", Message: "time_range must be one of: 1h, 6h, 24h"}, fields[1])
+	assert.Equal(t, FieldError{Field: "hour", Constraint: ConstraintRange, Value: 24, Message: "hour must be between 0 and 23"}, fields[2])
+	assert.Equal(t, FieldError{Field: "threshold", Constraint: ConstraintRange, Value: 1.5, Message: "threshold must be between 0.0 and 1.0"}, fields[3])
+}
+
+func TestDefault(t *testing.T) {
+	timeRange, threshold := "", 0.0
+	Default(&timeRange, "1h")
+	Default(&threshold, DefaultThreshold)
+	assert.Equal(t, "1h", timeRange)
+	assert.Equal(t, DefaultThreshold, threshold)
+
+	timeRange = "24h"
+	Default(&timeRange, "1h")
+	assert.Equal(t, "24h", timeRange)
+}
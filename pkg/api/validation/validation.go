@@ -0,0 +1,120 @@
+// Package validation reports invalid API request fields in a structured form.
+//
+// Handlers collect one FieldError per invalid field and return them in the "errors"
+// array of their error response, so UIs can highlight the offending form fields
+// without parsing messages. The error message joins the field messages, so clients
+// that only read the "error" string keep working.
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Constraints a field can violate
+const (
+	// ConstraintRequired means the field is missing or empty
+	ConstraintRequired = "required"
+	// ConstraintRange means a number, duration or count is outside its bounds
+	ConstraintRange = "range"
+	// ConstraintEnum means the value is not one of the allowed values
+	ConstraintEnum = "enum"
+	// ConstraintLength means a string is too long
+	ConstraintLength = "length"
+	// ConstraintFormat means the value is malformed, e.g. an invalid duration or timestamp
+	ConstraintFormat = "format"
+	// ConstraintType means the JSON value has the wrong type
+	ConstraintType = "type"
+	// ConstraintExclusive means the field cannot be combined with another field
+	ConstraintExclusive = "exclusive"
+	// ConstraintUnknown means the value refers to something that does not exist, e.g. an
+	// expired ID
+	ConstraintUnknown = "unknown"
+)
+
+// FieldError describes an invalid request field
+type FieldError struct {
+	// Field is the JSON path of the field (e.g. "horizon.every", "target_times[2]") or
+	// the name of the query or path parameter
+	Field string `json:"field"`
+
+	// Constraint is the rule the field violated, one of the Constraint constants
+	Constraint string `json:"constraint"`
+
+	// Value is the provided value, or its length for length constraints; it is
+	// omitted when the field is missing
+	Value interface{} `json:"value,omitempty"`
+
+	// Message is a human-readable description
+	Message string `json:"message"`
+}
+
+// Errors is a list of invalid fields
+type Errors []FieldError
+
+// Error joins the field messages
+func (e Errors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, fieldErr := range e {
+		messages = append(messages, fieldErr.Message)
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Add records an invalid field
+func (e *Errors) Add(field, constraint string, value interface{}, message string) {
+	*e = append(*e, FieldError{Field: field, Constraint: constraint, Value: value, Message: message})
+}
+
+// Err returns the errors, or nil when no field is invalid
+func (e Errors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// New returns an error for a single invalid field
+func New(field, constraint string, value interface{}, message string) error {
+	return Errors{{Field: field, Constraint: constraint, Value: value, Message: message}}
+}
+
+// Fields returns the field errors in err, or nil if err is not a validation error
+func Fields(err error) []FieldError {
+	var fieldErrs Errors
+	if errors.As(err, &fieldErrs) {
+		return fieldErrs
+	}
+	return nil
+}
+
+// DecodeFields returns the field error for a JSON decoding error caused by a value of
+// the wrong type, or nil for other decoding errors such as malformed JSON
+func DecodeFields(err error) []FieldError {
+	var typeErr *json.UnmarshalTypeError
+	if !errors.As(err, &typeErr) || typeErr.Field == "" {
+		return nil
+	}
+	return []FieldError{{
+		Field:      typeErr.Field,
+		Constraint: ConstraintType,
+		Message:    fmt.Sprintf("%s must be of type %s, got %s", typeErr.Field, typeErr.Type, typeErr.Value),
+	}}
+}
+
+// Nest prefixes the fields of a validation error with the path of the enclosing
+// object, e.g. "scopes[1]"; other errors are returned unchanged
+func Nest(prefix string, err error) error {
+	fieldErrs := Fields(err)
+	if fieldErrs == nil {
+		return err
+	}
+	nested := make(Errors, 0, len(fieldErrs))
+	for _, fieldErr := range fieldErrs {
+		fieldErr.Field = prefix + "." + fieldErr.Field
+		nested = append(nested, fieldErr)
+	}
+	return nested
+}
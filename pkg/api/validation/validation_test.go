@@ -0,0 +1,65 @@
+package validation
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrors(t *testing.T) {
+	var errs Errors
+	assert.NoError(t, errs.Err())
+
+	errs.Add("hour", ConstraintRange, 25, "hour must be between 0 and 23")
+	errs.Add("namespace", ConstraintRequired, nil, "namespace is required")
+	err := errs.Err()
+	require.Error(t, err)
+	assert.Equal(t, "hour must be between 0 and 23; namespace is required", err.Error())
+
+	fields := Fields(fmt.Errorf("validation failed: %w", err))
+	require.Len(t, fields, 2)
+	assert.Equal(t, "hour", fields[0].Field)
+	assert.Equal(t, ConstraintRange, fields[0].Constraint)
+	assert.Equal(t, 25, fields[0].Value)
+
+	assert.Nil(t, Fields(fmt.Errorf("other error")))
+}
+
+func TestFieldError_JSON(t *testing.T) {
+	data, err := json.Marshal([]FieldError{
+		{Field: "window", Constraint: ConstraintEnum, Value: "2w", Message: "invalid window"},
+		{Field: "namespace", Constraint: ConstraintRequired, Message: "namespace is required"},
+	})
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+		{"field": "window", "constraint": "enum", "value": "2w", "message": "invalid window"},
+		{"field": "namespace", "constraint": "required", "message": "namespace is required"}
+	]`, string(data))
+}
+
+func TestDecodeFields(t *testing.T) {
+	var req struct {
+		Hour int `json:"hour"`
+	}
+	err := json.Unmarshal([]byte(`{"hour": "nine"}`), &req)
+	fields := DecodeFields(err)
+	require.Len(t, fields, 1)
+	assert.Equal(t, "hour", fields[0].Field)
+	assert.Equal(t, ConstraintType, fields[0].Constraint)
+
+	err = json.Unmarshal([]byte(`{"hour":`), &req)
+	assert.Nil(t, DecodeFields(err), "malformed JSON has no field")
+}
+
+func TestNest(t *testing.T) {
+	err := Nest("scopes[1]", New("namespace", ConstraintRequired, nil, "namespace is required"))
+	fields := Fields(err)
+	require.Len(t, fields, 1)
+	assert.Equal(t, "scopes[1].namespace", fields[0].Field)
+
+	plain := fmt.Errorf("not a validation error")
+	assert.Equal(t, plain, Nest("scopes[0]", plain))
+}
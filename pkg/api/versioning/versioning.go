@@ -0,0 +1,228 @@
+// Package versioning provides API version routing, negotiation and deprecation headers.
+//
+// Each API version is served under its own path prefix (/api/v1, /api/v2, ...).
+// Clients may also call unversioned paths (/api/anomalies/analyze) and select a version
+// with the API-Version header or a vendor media type in Accept; requests without a
+// preference are routed to the default version.
+package versioning
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// API versions served by the coordination engine
+const (
+	V1 = "v1"
+	V2 = "v2"
+)
+
+const (
+	// HeaderAPIVersion carries the requested version on requests and the served version on responses
+	HeaderAPIVersion = "API-Version"
+
+	// HeaderDeprecation marks responses from a deprecated version
+	HeaderDeprecation = "Deprecation"
+
+	// HeaderSunset is the date after which a deprecated version may be removed (RFC 8594)
+	HeaderSunset = "Sunset"
+
+	// MediaTypePrefix selects a version through Accept, e.g. application/vnd.coordination-engine.v2+json
+	MediaTypePrefix = "application/vnd.coordination-engine."
+
+	// ErrCodeUnsupportedVersion is returned when a client requests an unknown version
+	ErrCodeUnsupportedVersion = "UNSUPPORTED_API_VERSION"
+)
+
+var versionPattern = regexp.MustCompile(`^v[0-9]+$`)
+
+type contextKey struct{}
+
+// Version describes one API version and its lifecycle
+type Version struct {
+	Name       string
+	Deprecated bool
+	Sunset     time.Time // Zero when no removal date is announced
+	Successor  string    // Version clients should migrate to
+}
+
+// Registry holds the served API versions
+type Registry struct {
+	versions       map[string]*Version
+	defaultVersion string
+}
+
+// NewRegistry creates a registry serving the given versions. Unversioned requests are
+// routed to defaultVersion, which must be one of the registered versions.
+func NewRegistry(defaultVersion string, versions ...Version) (*Registry, error) {
+	r := &Registry{versions: make(map[string]*Version)}
+	for i := range versions {
+		v := versions[i]
+		if !versionPattern.MatchString(v.Name) {
+			return nil, fmt.Errorf("invalid API version %q: must look like v1, v2", v.Name)
+		}
+		r.versions[v.Name] = &v
+	}
+
+	if _, ok := r.versions[defaultVersion]; !ok {
+		return nil, fmt.Errorf("default API version %q is not registered", defaultVersion)
+	}
+	r.defaultVersion = defaultVersion
+	return r, nil
+}
+
+// Default returns the version used for requests without a version preference
+func (r *Registry) Default() string {
+	return r.defaultVersion
+}
+
+// Versions returns the registered version names in ascending order
+func (r *Registry) Versions() []string {
+	names := make([]string, 0, len(r.versions))
+	for name := range r.versions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Lookup returns a registered version
+func (r *Registry) Lookup(name string) (Version, bool) {
+	v, ok := r.versions[name]
+	if !ok {
+		return Version{}, false
+	}
+	return *v, true
+}
+
+// Deprecate marks a version as deprecated. Responses from it carry Deprecation, Sunset
+// and successor Link headers so clients can plan their migration.
+func (r *Registry) Deprecate(name string, sunset time.Time, successor string) error {
+	v, ok := r.versions[name]
+	if !ok {
+		return fmt.Errorf("API version %q is not registered", name)
+	}
+	if _, ok := r.versions[successor]; successor != "" && !ok {
+		return fmt.Errorf("successor API version %q is not registered", successor)
+	}
+	v.Deprecated = true
+	v.Sunset = sunset
+	v.Successor = successor
+	return nil
+}
+
+// Group returns a subrouter for a version's path prefix (/api/<version>).
+// Routes registered on it are relative to the prefix.
+func (r *Registry) Group(router *mux.Router, name string) *mux.Router {
+	return router.PathPrefix("/api/" + name).Subrouter()
+}
+
+// Middleware sets version response headers and stores the served version in the request
+// context. Register it on the root router so it applies to every versioned route.
+func (r *Registry) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		name, ok := pathVersion(req.URL.Path)
+		v, registered := r.versions[name]
+		if !ok || !registered {
+			next.ServeHTTP(w, req)
+			return
+		}
+
+		w.Header().Set(HeaderAPIVersion, v.Name)
+		if v.Deprecated {
+			w.Header().Set(HeaderDeprecation, "true")
+			if !v.Sunset.IsZero() {
+				w.Header().Set(HeaderSunset, v.Sunset.UTC().Format(http.TimeFormat))
+			}
+			if v.Successor != "" {
+				w.Header().Add("Link", fmt.Sprintf(`</api/%s>; rel="successor-version"`, v.Successor))
+			}
+		}
+
+		next.ServeHTTP(w, req.WithContext(context.WithValue(req.Context(), contextKey{}, v.Name)))
+	})
+}
+
+// Negotiate routes unversioned /api/... requests to the version selected by the client,
+// rewriting the path before routing. It must wrap the router, since mux middleware only
+// runs after a route has been matched.
+func (r *Registry) Negotiate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		rest, isAPI := strings.CutPrefix(req.URL.Path, "/api/")
+		if !isAPI {
+			next.ServeHTTP(w, req)
+			return
+		}
+		if _, versioned := pathVersion(req.URL.Path); versioned {
+			next.ServeHTTP(w, req)
+			return
+		}
+
+		name := r.requestedVersion(req)
+		if _, ok := r.versions[name]; !ok {
+			respondUnsupported(w, name, r.Versions())
+			return
+		}
+
+		rewritten := req.Clone(req.Context())
+		rewritten.URL.Path = "/api/" + name + "/" + rest
+		rewritten.URL.RawPath = ""
+		next.ServeHTTP(w, rewritten)
+	})
+}
+
+// requestedVersion returns the version selected by the request headers, or the default
+func (r *Registry) requestedVersion(req *http.Request) string {
+	if v := strings.TrimSpace(req.Header.Get(HeaderAPIVersion)); v != "" {
+		return strings.ToLower(v)
+	}
+
+	for _, mediaType := range strings.Split(req.Header.Get("Accept"), ",") {
+		mediaType, _, _ = strings.Cut(strings.TrimSpace(mediaType), ";")
+		if v, ok := strings.CutPrefix(mediaType, MediaTypePrefix); ok {
+			v, _, _ = strings.Cut(v, "+")
+			return strings.ToLower(v)
+		}
+	}
+
+	return r.defaultVersion
+}
+
+// FromContext returns the API version serving the request, or "" outside versioned routes
+func FromContext(ctx context.Context) string {
+	v, _ := ctx.Value(contextKey{}).(string)
+	return v
+}
+
+// pathVersion extracts the version segment from /api/<version>/...
+func pathVersion(path string) (string, bool) {
+	rest, ok := strings.CutPrefix(path, "/api/")
+	if !ok {
+		return "", false
+	}
+	segment, _, _ := strings.Cut(rest, "/")
+	if !versionPattern.MatchString(segment) {
+		return "", false
+	}
+	return segment, true
+}
+
+// respondUnsupported writes a 406 response listing the supported versions
+func respondUnsupported(w http.ResponseWriter, requested string, supported []string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusNotAcceptable)
+	_ = json.NewEncoder(w).Encode(map[string]string{
+		"status":  "error",
+		"error":   fmt.Sprintf("API version '%s' is not supported", requested),
+		"details": "Supported versions: " + strings.Join(supported, ", "),
+		"code":    ErrCodeUnsupportedVersion,
+	})
+}
@@ -0,0 +1,125 @@
+package versioning
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRouter(t *testing.T) (*Registry, http.Handler) {
+	t.Helper()
+
+	registry, err := NewRegistry(V1, Version{Name: V1}, Version{Name: V2})
+	require.NoError(t, err)
+
+	router := mux.NewRouter()
+	router.Use(registry.Middleware)
+	for _, name := range registry.Versions() {
+		name := name
+		registry.Group(router, name).HandleFunc("/anomalies", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(name + ":" + FromContext(r.Context())))
+		})
+	}
+	return registry, registry.Negotiate(router)
+}
+
+func TestNewRegistry_Validation(t *testing.T) {
+	_, err := NewRegistry(V2, Version{Name: V1})
+	assert.Error(t, err)
+
+	_, err = NewRegistry("latest", Version{Name: "latest"})
+	assert.Error(t, err)
+
+	registry, err := NewRegistry(V1, Version{Name: V2}, Version{Name: V1})
+	require.NoError(t, err)
+	assert.Equal(t, []string{V1, V2}, registry.Versions())
+	assert.Equal(t, V1, registry.Default())
+}
+
+func TestRegistry_VersionedPaths(t *testing.T) {
+	_, handler := newTestRouter(t)
+
+	for _, name := range []string{V1, V2} {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest("GET", "/api/"+name+"/anomalies", http.NoBody))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, name+":"+name, rr.Body.String())
+		assert.Equal(t, name, rr.Header().Get(HeaderAPIVersion))
+		assert.Empty(t, rr.Header().Get(HeaderDeprecation))
+	}
+}
+
+func TestRegistry_Negotiate(t *testing.T) {
+	_, handler := newTestRouter(t)
+
+	tests := []struct {
+		name     string
+		headers  map[string]string
+		expected string
+		status   int
+	}{
+		{name: "default version", expected: "v1:v1", status: http.StatusOK},
+		{name: "API-Version header", headers: map[string]string{HeaderAPIVersion: "V2"}, expected: "v2:v2", status: http.StatusOK},
+		{name: "vendor media type", headers: map[string]string{"Accept": "application/vnd.coordination-engine.v2+json; q=1"}, expected: "v2:v2", status: http.StatusOK},
+		{name: "plain accept", headers: map[string]string{"Accept": "application/json"}, expected: "v1:v1", status: http.StatusOK},
+		{name: "unknown version", headers: map[string]string{HeaderAPIVersion: "v9"}, status: http.StatusNotAcceptable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/anomalies", http.NoBody)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.status, rr.Code)
+			if tt.status == http.StatusOK {
+				assert.Equal(t, tt.expected, rr.Body.String())
+			} else {
+				assert.Contains(t, rr.Body.String(), ErrCodeUnsupportedVersion)
+			}
+		})
+	}
+}
+
+func TestRegistry_DeprecationHeaders(t *testing.T) {
+	registry, handler := newTestRouter(t)
+
+	sunset := time.Date(2027, 6, 30, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, registry.Deprecate(V1, sunset, V2))
+	assert.Error(t, registry.Deprecate("v7", sunset, V2))
+	assert.Error(t, registry.Deprecate(V1, sunset, "v7"))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/api/v1/anomalies", http.NoBody))
+
+	assert.Equal(t, "true", rr.Header().Get(HeaderDeprecation))
+	assert.Equal(t, "Wed, 30 Jun 2027 00:00:00 GMT", rr.Header().Get(HeaderSunset))
+	assert.Equal(t, `</api/v2>; rel="successor-version"`, rr.Header().Get("Link"))
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/api/v2/anomalies", http.NoBody))
+	assert.Empty(t, rr.Header().Get(HeaderDeprecation))
+}
+
+func TestRegistry_NonAPIPathsUntouched(t *testing.T) {
+	registry, err := NewRegistry(V1, Version{Name: V1})
+	require.NoError(t, err)
+
+	handler := registry.Negotiate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.URL.Path))
+	}))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/health", http.NoBody))
+	assert.Equal(t, "/health", rr.Body.String())
+	assert.Empty(t, FromContext(httptest.NewRequest("GET", "/health", http.NoBody).Context()))
+}
@@ -0,0 +1,434 @@
+package batch
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Workload kinds
+const (
+	KindCronJob = "CronJob"
+	KindJob     = "Job"
+)
+
+// Run statuses
+const (
+	RunSucceeded = "succeeded"
+	RunFailed    = "failed"
+	RunActive    = "active"
+	RunUnknown   = "unknown"
+)
+
+// Analysis thresholds
+const (
+	// DurationGrowthThreshold is the week-over-week growth of the mean run duration
+	// that is reported
+	DurationGrowthThreshold = 0.2
+
+	// DeadlineHeadroom is the fraction of activeDeadlineSeconds a successful run may
+	// take before the deadline is reported as too tight
+	DeadlineHeadroom = 0.8
+
+	// MissedScheduleGrace is how late a scheduled run may start before it counts as
+	// missed, for CronJobs without startingDeadlineSeconds
+	MissedScheduleGrace = 2 * time.Minute
+
+	// maxMissedSchedules bounds the missed runs counted per CronJob
+	maxMissedSchedules = 100
+
+	// recentRuns is the number of runs listed per workload
+	recentRuns = 10
+)
+
+// Run is one execution of a batch workload
+type Run struct {
+	Job             string     `json:"job"`
+	Status          string     `json:"status"`
+	StartTime       time.Time  `json:"start_time"`
+	CompletionTime  *time.Time `json:"completion_time,omitempty"`
+	DurationSeconds float64    `json:"duration_seconds,omitempty"`
+
+	// FailureReason is the reason of the Job's Failed condition, e.g. DeadlineExceeded
+	// or BackoffLimitExceeded; runs known only from metrics history have none
+	FailureReason string `json:"failure_reason,omitempty"`
+}
+
+// HistoricalRun is a Job run recorded by metrics, which outlives the Job object
+type HistoricalRun struct {
+	Job            string
+	CronJob        string
+	StartTime      time.Time
+	CompletionTime time.Time
+	Failed         bool
+}
+
+// DurationTrend compares the mean duration of the successful runs of the last week
+// with the week before
+type DurationTrend struct {
+	CurrentMeanSeconds  float64 `json:"current_mean_seconds"`
+	PreviousMeanSeconds float64 `json:"previous_mean_seconds"`
+	ChangePercent       float64 `json:"change_percent"`
+	CurrentRuns         int     `json:"current_runs"`
+	PreviousRuns        int     `json:"previous_runs"`
+}
+
+// WorkloadReport is the analysis of a CronJob, or of a Job not created by one
+type WorkloadReport struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+
+	Schedule              string     `json:"schedule,omitempty"`
+	Suspended             bool       `json:"suspended,omitempty"`
+	ActiveDeadlineSeconds *int64     `json:"active_deadline_seconds,omitempty"`
+	LastScheduleTime      *time.Time `json:"last_schedule_time,omitempty"`
+
+	Runs           int            `json:"runs"`
+	Succeeded      int            `json:"succeeded"`
+	Failed         int            `json:"failed"`
+	Active         int            `json:"active"`
+	FailureReasons map[string]int `json:"failure_reasons,omitempty"`
+
+	MaxDurationSeconds float64        `json:"max_duration_seconds,omitempty"`
+	DurationTrend      *DurationTrend `json:"duration_trend,omitempty"`
+
+	// MissedSchedules counts scheduled runs that did not start, since the last one
+	// that did
+	MissedSchedules    int        `json:"missed_schedules"`
+	FirstMissedAt      *time.Time `json:"first_missed_at,omitempty"`
+	ScheduleError      string     `json:"schedule_error,omitempty"`
+	ConcurrencyBlocked bool       `json:"concurrency_blocked,omitempty"`
+
+	Recommendations []string `json:"recommendations"`
+	RecentRuns      []Run    `json:"recent_runs"`
+}
+
+// Analyzer analyzes the Jobs and CronJobs of a namespace
+type Analyzer struct {
+	k8sClient kubernetes.Interface
+	log       *logrus.Logger
+	now       func() time.Time
+}
+
+// NewAnalyzer creates a new batch workload analyzer
+func NewAnalyzer(k8sClient kubernetes.Interface, log *logrus.Logger) *Analyzer {
+	return &Analyzer{k8sClient: k8sClient, log: log, now: time.Now}
+}
+
+// workload collects the runs of one report
+type workload struct {
+	report *WorkloadReport
+	cron   *batchv1.CronJob
+	runs   map[string]*Run
+}
+
+// Analyze reports on the CronJobs of a namespace and the Jobs not created by one. name
+// limits the reports to the CronJob or Job of that name. history adds runs whose Jobs
+// were deleted, e.g. by the CronJob's history limits.
+func (a *Analyzer) Analyze(ctx context.Context, namespace, name string, history []HistoricalRun) ([]WorkloadReport, error) {
+	cronJobs, err := a.k8sClient.BatchV1().CronJobs(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cronjobs in namespace %s: %w", namespace, err)
+	}
+	jobs, err := a.k8sClient.BatchV1().Jobs(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs in namespace %s: %w", namespace, err)
+	}
+
+	workloads := make(map[string]*workload)
+	add := func(kind, workloadName string) *workload {
+		key := kind + "/" + workloadName
+		if workloads[key] == nil {
+			workloads[key] = &workload{
+				report: &WorkloadReport{Kind: kind, Name: workloadName, Namespace: namespace},
+				runs:   make(map[string]*Run),
+			}
+		}
+		return workloads[key]
+	}
+
+	for i := range cronJobs.Items {
+		cron := &cronJobs.Items[i]
+		w := add(KindCronJob, cron.Name)
+		w.cron = cron
+		w.report.Schedule = cron.Spec.Schedule
+		w.report.Suspended = cron.Spec.Suspend != nil && *cron.Spec.Suspend
+		w.report.ActiveDeadlineSeconds = cron.Spec.JobTemplate.Spec.ActiveDeadlineSeconds
+		if cron.Status.LastScheduleTime != nil {
+			last := cron.Status.LastScheduleTime.UTC()
+			w.report.LastScheduleTime = &last
+		}
+	}
+
+	for i := range jobs.Items {
+		job := &jobs.Items[i]
+		var w *workload
+		if owner := cronJobOwner(job); owner != "" {
+			w = add(KindCronJob, owner)
+		} else {
+			w = add(KindJob, job.Name)
+			w.report.ActiveDeadlineSeconds = job.Spec.ActiveDeadlineSeconds
+		}
+		w.runs[job.Name] = jobRun(job)
+	}
+
+	// Runs still in the cluster are more complete than their metrics
+	for _, h := range history {
+		var w *workload
+		if h.CronJob != "" {
+			w = add(KindCronJob, h.CronJob)
+		} else {
+			w = add(KindJob, h.Job)
+		}
+		if w.runs[h.Job] == nil {
+			w.runs[h.Job] = historicalRun(h)
+		}
+	}
+
+	now := a.now().UTC()
+	reports := make([]WorkloadReport, 0, len(workloads))
+	for _, w := range workloads {
+		if name != "" && w.report.Name != name {
+			continue
+		}
+		a.analyzeRuns(w, now)
+		if w.cron != nil {
+			a.detectMissedSchedules(w, now)
+		}
+		w.report.Recommendations = recommend(w.report)
+		reports = append(reports, *w.report)
+	}
+	sort.Slice(reports, func(i, j int) bool {
+		if reports[i].Kind != reports[j].Kind {
+			return reports[i].Kind < reports[j].Kind
+		}
+		return reports[i].Name < reports[j].Name
+	})
+	return reports, nil
+}
+
+// cronJobOwner returns the name of the CronJob that created a Job, if any
+func cronJobOwner(job *batchv1.Job) string {
+	for _, ref := range job.OwnerReferences {
+		if ref.Kind == KindCronJob {
+			return ref.Name
+		}
+	}
+	return ""
+}
+
+// jobRun converts a Job to a run
+func jobRun(job *batchv1.Job) *Run {
+	run := &Run{Job: job.Name, Status: RunActive}
+	if job.Status.StartTime != nil {
+		run.StartTime = job.Status.StartTime.UTC()
+	} else {
+		run.StartTime = job.CreationTimestamp.UTC()
+	}
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batchv1.JobFailed:
+			run.Status = RunFailed
+			run.FailureReason = condition.Reason
+		case batchv1.JobComplete:
+			run.Status = RunSucceeded
+		}
+	}
+	if run.Status == RunSucceeded && job.Status.CompletionTime != nil {
+		completed := job.Status.CompletionTime.UTC()
+		run.CompletionTime = &completed
+		run.DurationSeconds = completed.Sub(run.StartTime).Seconds()
+	}
+	return run
+}
+
+// historicalRun converts a run recorded by metrics
+func historicalRun(h HistoricalRun) *Run {
+	run := &Run{Job: h.Job, Status: RunUnknown, StartTime: h.StartTime}
+	switch {
+	case h.Failed:
+		run.Status = RunFailed
+	case !h.CompletionTime.IsZero():
+		run.Status = RunSucceeded
+		completed := h.CompletionTime
+		run.CompletionTime = &completed
+		run.DurationSeconds = completed.Sub(h.StartTime).Seconds()
+	}
+	return run
+}
+
+// analyzeRuns counts a workload's runs by status and computes its duration trend
+func (a *Analyzer) analyzeRuns(w *workload, now time.Time) {
+	runs := make([]Run, 0, len(w.runs))
+	for _, run := range w.runs {
+		runs = append(runs, *run)
+	}
+	sort.Slice(runs, func(i, j int) bool { return runs[i].StartTime.After(runs[j].StartTime) })
+
+	report := w.report
+	report.Runs = len(runs)
+	var current, previous []float64
+	weekAgo, twoWeeksAgo := now.AddDate(0, 0, -7), now.AddDate(0, 0, -14)
+	for i := range runs {
+		run := &runs[i]
+		switch run.Status {
+		case RunSucceeded:
+			report.Succeeded++
+			if run.DurationSeconds > report.MaxDurationSeconds {
+				report.MaxDurationSeconds = run.DurationSeconds
+			}
+			switch {
+			case run.StartTime.After(weekAgo):
+				current = append(current, run.DurationSeconds)
+			case run.StartTime.After(twoWeeksAgo):
+				previous = append(previous, run.DurationSeconds)
+			}
+		case RunFailed:
+			report.Failed++
+			if run.FailureReason != "" {
+				if report.FailureReasons == nil {
+					report.FailureReasons = make(map[string]int)
+				}
+				report.FailureReasons[run.FailureReason]++
+			}
+		case RunActive:
+			report.Active++
+		}
+	}
+
+	if len(current) > 0 && len(previous) > 0 {
+		trend := &DurationTrend{
+			CurrentMeanSeconds:  mean(current),
+			PreviousMeanSeconds: mean(previous),
+			CurrentRuns:         len(current),
+			PreviousRuns:        len(previous),
+		}
+		if trend.PreviousMeanSeconds > 0 {
+			trend.ChangePercent = (trend.CurrentMeanSeconds - trend.PreviousMeanSeconds) / trend.PreviousMeanSeconds * 100
+		}
+		report.DurationTrend = trend
+	}
+
+	if len(runs) > recentRuns {
+		runs = runs[:recentRuns]
+	}
+	report.RecentRuns = runs
+}
+
+// detectMissedSchedules counts the scheduled times since the last scheduled run that
+// are overdue by more than the CronJob's starting deadline
+func (a *Analyzer) detectMissedSchedules(w *workload, now time.Time) {
+	cron := w.cron
+	if w.report.Suspended {
+		return
+	}
+	schedule, err := ParseSchedule(cron.Spec.Schedule)
+	if err != nil {
+		w.report.ScheduleError = err.Error()
+		return
+	}
+	location := time.UTC
+	if cron.Spec.TimeZone != nil && *cron.Spec.TimeZone != "" {
+		loaded, err := time.LoadLocation(*cron.Spec.TimeZone)
+		if err != nil {
+			w.report.ScheduleError = fmt.Sprintf("unknown time zone %q", *cron.Spec.TimeZone)
+			return
+		}
+		location = loaded
+	}
+
+	since := cron.CreationTimestamp.Time
+	if cron.Status.LastScheduleTime != nil {
+		since = cron.Status.LastScheduleTime.Time
+	}
+	grace := MissedScheduleGrace
+	if cron.Spec.StartingDeadlineSeconds != nil {
+		grace = time.Duration(*cron.Spec.StartingDeadlineSeconds) * time.Second
+	}
+
+	missed := schedule.Between(since.In(location), now.Add(-grace).In(location), maxMissedSchedules)
+	w.report.MissedSchedules = len(missed)
+	if len(missed) > 0 {
+		first := missed[0].UTC()
+		w.report.FirstMissedAt = &first
+		w.report.ConcurrencyBlocked = cron.Spec.ConcurrencyPolicy == batchv1.ForbidConcurrent && len(cron.Status.Active) > 0
+	}
+}
+
+// recommend turns a report's findings into recommendations
+func recommend(report *WorkloadReport) []string {
+	recommendations := []string{}
+
+	deadline := report.ActiveDeadlineSeconds
+	if count := report.FailureReasons["DeadlineExceeded"]; count > 0 {
+		if deadline != nil {
+			recommendations = append(recommendations, fmt.Sprintf(
+				"%d of %d runs exceeded activeDeadlineSeconds (%ds): increase activeDeadlineSeconds or reduce the work per run",
+				count, report.Runs, *deadline))
+		} else {
+			recommendations = append(recommendations, fmt.Sprintf(
+				"%d of %d runs exceeded their deadline: increase activeDeadlineSeconds or reduce the work per run", count, report.Runs))
+		}
+	} else if deadline != nil && *deadline > 0 && report.MaxDurationSeconds >= DeadlineHeadroom*float64(*deadline) {
+		recommendations = append(recommendations, fmt.Sprintf(
+			"the longest successful run took %.0f%% of activeDeadlineSeconds (%ds): increase activeDeadlineSeconds before runs start failing",
+			report.MaxDurationSeconds/float64(*deadline)*100, *deadline))
+	}
+
+	if count := report.FailureReasons["BackoffLimitExceeded"]; count > 0 {
+		recommendations = append(recommendations, fmt.Sprintf(
+			"%d of %d runs failed after exhausting backoffLimit: check the logs of the failed pods", count, report.Runs))
+	}
+	if other := report.Failed - report.FailureReasons["DeadlineExceeded"] - report.FailureReasons["BackoffLimitExceeded"]; other > 0 {
+		recommendations = append(recommendations, fmt.Sprintf("%d of %d runs failed: check the logs of the failed pods", other, report.Runs))
+	}
+
+	if trend := report.DurationTrend; trend != nil && trend.ChangePercent >= DurationGrowthThreshold*100 {
+		recommendations = append(recommendations, fmt.Sprintf(
+			"job duration grew %.0f%% week-over-week (%s to %s): check for growing input data or slower dependencies",
+			trend.ChangePercent, formatSeconds(trend.PreviousMeanSeconds), formatSeconds(trend.CurrentMeanSeconds)))
+	}
+
+	if report.MissedSchedules > 0 {
+		missed := fmt.Sprintf("%d scheduled runs missed since %s", report.MissedSchedules, report.FirstMissedAt.Format(time.RFC3339))
+		if report.MissedSchedules == 1 {
+			missed = "1 scheduled run missed at " + report.FirstMissedAt.Format(time.RFC3339)
+		}
+		if report.ConcurrencyBlocked {
+			recommendations = append(recommendations, missed+
+				": a run is still active and concurrencyPolicy is Forbid, so shorten the runs or lower the schedule frequency")
+		} else {
+			recommendations = append(recommendations, missed+
+				": check startingDeadlineSeconds and the health of the CronJob controller")
+		}
+	}
+	if report.ScheduleError != "" {
+		recommendations = append(recommendations, "schedule could not be checked for missed runs: "+report.ScheduleError)
+	}
+	return recommendations
+}
+
+// mean returns the arithmetic mean of values
+func mean(values []float64) float64 {
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// formatSeconds renders a duration in seconds rounded to the second, e.g. "12m30s"
+func formatSeconds(seconds float64) string {
+	return (time.Duration(seconds) * time.Second).Round(time.Second).String()
+}
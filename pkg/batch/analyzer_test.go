@@ -0,0 +1,172 @@
+package batch
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+var analysisTime = time.Date(2026, time.March, 13, 10, 0, 0, 0, time.UTC)
+
+func testAnalyzer(t *testing.T, objects ...runtime.Object) *Analyzer {
+	t.Helper()
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	analyzer := NewAnalyzer(fake.NewSimpleClientset(objects...), log)
+	analyzer.now = func() time.Time { return analysisTime }
+	return analyzer
+}
+
+func failedJob(name, owner, reason string, start time.Time) *batchv1.Job {
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "batch", CreationTimestamp: metav1.NewTime(start)},
+		Status: batchv1.JobStatus{
+			StartTime:  &metav1.Time{Time: start},
+			Conditions: []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Reason: reason}},
+		},
+	}
+	if owner != "" {
+		job.OwnerReferences = []metav1.OwnerReference{{Kind: KindCronJob, Name: owner}}
+	}
+	return job
+}
+
+func TestAnalyzer_CronJob(t *testing.T) {
+	deadline := int64(3600)
+	lastSchedule := time.Date(2026, time.March, 10, 2, 0, 0, 0, time.UTC)
+	cron := &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "nightly-etl", Namespace: "batch"},
+		Spec: batchv1.CronJobSpec{
+			Schedule: "0 2 * * *",
+			JobTemplate: batchv1.JobTemplateSpec{
+				Spec: batchv1.JobSpec{ActiveDeadlineSeconds: &deadline},
+			},
+		},
+		Status: batchv1.CronJobStatus{LastScheduleTime: &metav1.Time{Time: lastSchedule}},
+	}
+	analyzer := testAnalyzer(t, cron, failedJob("nightly-etl-29000", "nightly-etl", "DeadlineExceeded", lastSchedule))
+
+	var history []HistoricalRun
+	for day, duration := range map[int]time.Duration{1: 1000, 2: 1000, 3: 1000, 7: 1400, 8: 1400, 9: 1400} {
+		start := time.Date(2026, time.March, day, 2, 0, 0, 0, time.UTC)
+		history = append(history, HistoricalRun{
+			Job:            "nightly-etl-" + start.Format("0102"),
+			CronJob:        "nightly-etl",
+			StartTime:      start,
+			CompletionTime: start.Add(duration * time.Second),
+		})
+	}
+	// The Job still in the cluster wins over its metrics
+	history = append(history, HistoricalRun{Job: "nightly-etl-29000", CronJob: "nightly-etl", StartTime: lastSchedule})
+
+	reports, err := analyzer.Analyze(context.Background(), "batch", "", history)
+	require.NoError(t, err)
+	require.Len(t, reports, 1)
+	report := reports[0]
+
+	assert.Equal(t, KindCronJob, report.Kind)
+	assert.Equal(t, 7, report.Runs)
+	assert.Equal(t, 6, report.Succeeded)
+	assert.Equal(t, 1, report.Failed)
+	assert.Equal(t, map[string]int{"DeadlineExceeded": 1}, report.FailureReasons)
+	assert.Equal(t, float64(1400), report.MaxDurationSeconds)
+
+	require.NotNil(t, report.DurationTrend)
+	assert.InDelta(t, 40, report.DurationTrend.ChangePercent, 1e-9)
+	assert.Equal(t, 3, report.DurationTrend.CurrentRuns)
+
+	// March 11, 12 and 13 at 02:00 were not scheduled
+	assert.Equal(t, 3, report.MissedSchedules)
+	require.NotNil(t, report.FirstMissedAt)
+	assert.Equal(t, time.Date(2026, time.March, 11, 2, 0, 0, 0, time.UTC), *report.FirstMissedAt)
+
+	require.Len(t, report.Recommendations, 3)
+	assert.Contains(t, report.Recommendations[0], "1 of 7 runs exceeded activeDeadlineSeconds (3600s): increase activeDeadlineSeconds")
+	assert.Contains(t, report.Recommendations[1], "job duration grew 40% week-over-week (16m40s to 23m20s)")
+	assert.Contains(t, report.Recommendations[2], "3 scheduled runs missed since 2026-03-11T02:00:00Z")
+
+	assert.Len(t, report.RecentRuns, 7)
+	assert.Equal(t, "nightly-etl-29000", report.RecentRuns[0].Job, "newest first")
+	assert.Equal(t, RunFailed, report.RecentRuns[0].Status)
+}
+
+func TestAnalyzer_MissedSchedulesBlockedByConcurrency(t *testing.T) {
+	suspended := true
+	lastSchedule := analysisTime.Add(-3 * time.Hour)
+	analyzer := testAnalyzer(t,
+		&batchv1.CronJob{
+			ObjectMeta: metav1.ObjectMeta{Name: "hourly-sync", Namespace: "batch"},
+			Spec:       batchv1.CronJobSpec{Schedule: "@hourly", ConcurrencyPolicy: batchv1.ForbidConcurrent},
+			Status: batchv1.CronJobStatus{
+				LastScheduleTime: &metav1.Time{Time: lastSchedule},
+				Active:           []corev1.ObjectReference{{Name: "hourly-sync-1"}},
+			},
+		},
+		&batchv1.CronJob{
+			ObjectMeta: metav1.ObjectMeta{Name: "paused", Namespace: "batch"},
+			Spec:       batchv1.CronJobSpec{Schedule: "@hourly", Suspend: &suspended},
+			Status:     batchv1.CronJobStatus{LastScheduleTime: &metav1.Time{Time: lastSchedule}},
+		},
+		&batchv1.CronJob{
+			ObjectMeta: metav1.ObjectMeta{Name: "broken", Namespace: "batch"},
+			Spec:       batchv1.CronJobSpec{Schedule: "every hour"},
+		},
+	)
+
+	reports, err := analyzer.Analyze(context.Background(), "batch", "", nil)
+	require.NoError(t, err)
+	require.Len(t, reports, 3)
+
+	broken, sync, paused := reports[0], reports[1], reports[2]
+	assert.NotEmpty(t, broken.ScheduleError)
+	assert.Contains(t, broken.Recommendations[0], "schedule could not be checked")
+
+	assert.Equal(t, 2, sync.MissedSchedules, "the run at 10:00 is within the grace period")
+	assert.True(t, sync.ConcurrencyBlocked)
+	assert.Contains(t, sync.Recommendations[0], "concurrencyPolicy is Forbid")
+
+	assert.True(t, paused.Suspended)
+	assert.Zero(t, paused.MissedSchedules)
+	assert.Empty(t, paused.Recommendations)
+}
+
+func TestAnalyzer_StandaloneJob(t *testing.T) {
+	deadline := int64(600)
+	start := analysisTime.Add(-time.Hour)
+	completed := start.Add(500 * time.Second)
+	analyzer := testAnalyzer(t,
+		failedJob("migrate-db", "", "BackoffLimitExceeded", start),
+		&batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: "reindex", Namespace: "batch"},
+			Spec:       batchv1.JobSpec{ActiveDeadlineSeconds: &deadline},
+			Status: batchv1.JobStatus{
+				StartTime:      &metav1.Time{Time: start},
+				CompletionTime: &metav1.Time{Time: completed},
+				Conditions:     []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}},
+			},
+		},
+	)
+
+	reports, err := analyzer.Analyze(context.Background(), "batch", "migrate-db", nil)
+	require.NoError(t, err)
+	require.Len(t, reports, 1)
+	assert.Equal(t, KindJob, reports[0].Kind)
+	assert.Equal(t, []string{"1 of 1 runs failed after exhausting backoffLimit: check the logs of the failed pods"}, reports[0].Recommendations)
+
+	reports, err = analyzer.Analyze(context.Background(), "batch", "reindex", nil)
+	require.NoError(t, err)
+	require.Len(t, reports, 1)
+	assert.Equal(t, float64(500), reports[0].MaxDurationSeconds)
+	require.Len(t, reports[0].Recommendations, 1)
+	assert.Contains(t, reports[0].Recommendations[0], "the longest successful run took 83% of activeDeadlineSeconds (600s)")
+}
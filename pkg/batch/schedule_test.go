@@ -0,0 +1,58 @@
+package batch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSchedule_Next(t *testing.T) {
+	from := time.Date(2026, time.March, 13, 10, 7, 30, 0, time.UTC) // a Friday
+
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"*/15 * * * *", time.Date(2026, time.March, 13, 10, 15, 0, 0, time.UTC)},
+		{"5/20 * * * *", time.Date(2026, time.March, 13, 10, 25, 0, 0, time.UTC)},
+		{"0 2 * * *", time.Date(2026, time.March, 14, 2, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2026, time.March, 13, 11, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2026, time.March, 15, 0, 0, 0, 0, time.UTC)},
+		{"30 9 * * mon-fri", time.Date(2026, time.March, 16, 9, 30, 0, 0, time.UTC)},
+		{"0 0 1 jan,jul *", time.Date(2026, time.July, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2026, time.March, 15, 0, 0, 0, 0, time.UTC)},
+		// Restricted day of month and day of week match either
+		{"0 0 20 * 1", time.Date(2026, time.March, 16, 0, 0, 0, 0, time.UTC)},
+		{"0 12 ? * *", time.Date(2026, time.March, 13, 12, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			schedule, err := ParseSchedule(tt.spec)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, schedule.Next(from))
+		})
+	}
+
+	never, err := ParseSchedule("0 0 30 2 *")
+	require.NoError(t, err)
+	assert.True(t, never.Next(from).IsZero())
+}
+
+func TestParseSchedule_Errors(t *testing.T) {
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "*/0 * * * *", "5-1 * * * *", "* * * foo *", "TZ=UTC 0 * * * *"} {
+		_, err := ParseSchedule(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestSchedule_Between(t *testing.T) {
+	schedule, err := ParseSchedule("0 * * * *")
+	require.NoError(t, err)
+
+	from := time.Date(2026, time.March, 13, 10, 0, 0, 0, time.UTC)
+	times := schedule.Between(from, from.Add(3*time.Hour), 10)
+	assert.Equal(t, []time.Time{from.Add(time.Hour), from.Add(2 * time.Hour), from.Add(3 * time.Hour)}, times)
+	assert.Len(t, schedule.Between(from, from.Add(48*time.Hour), 5), 5)
+}
@@ -12,6 +12,9 @@ import (
 	"k8s.io/client-go/kubernetes"
 
 	"github.com/sirupsen/logrus"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/quantity"
 )
 
 // Analyzer provides capacity analysis for namespaces and clusters
@@ -30,34 +33,34 @@ func NewAnalyzer(k8sClient kubernetes.Interface, log *logrus.Logger) *Analyzer {
 
 // QuotaInfo contains resource quota information
 type QuotaInfo struct {
-	CPULimit        string  `json:"limit"`
-	CPULimitNumeric float64 `json:"limit_numeric"`
+	CPULimit        string         `json:"limit"`
+	CPULimitNumeric quantity.Cores `json:"limit_numeric"`
 }
 
 // MemoryQuotaInfo contains memory quota information
 type MemoryQuotaInfo struct {
-	Limit      string `json:"limit"`
-	LimitBytes int64  `json:"limit_bytes"`
+	Limit      string         `json:"limit"`
+	LimitBytes quantity.Bytes `json:"limit_bytes"`
 }
 
 // NamespaceQuota contains namespace quota details
 type NamespaceQuota struct {
-	CPU           *CPUQuota    `json:"cpu,omitempty"`
-	Memory        *MemoryQuota `json:"memory,omitempty"`
-	PodCountLimit int64        `json:"pod_count_limit"`
-	HasQuota      bool         `json:"has_quota"`
+	CPU           *CPUQuota      `json:"cpu,omitempty"`
+	Memory        *MemoryQuota   `json:"memory,omitempty"`
+	PodCountLimit quantity.Count `json:"pod_count_limit"`
+	HasQuota      bool           `json:"has_quota"`
 }
 
 // CPUQuota contains CPU quota information
 type CPUQuota struct {
-	Limit        string  `json:"limit"`
-	LimitNumeric float64 `json:"limit_numeric"`
+	Limit        string         `json:"limit"`
+	LimitNumeric quantity.Cores `json:"limit_numeric"`
 }
 
 // MemoryQuota contains memory quota information
 type MemoryQuota struct {
-	Limit      string `json:"limit"`
-	LimitBytes int64  `json:"limit_bytes"`
+	Limit      string         `json:"limit"`
+	LimitBytes quantity.Bytes `json:"limit_bytes"`
 }
 
 // ResourceUsage contains current resource usage information
@@ -69,46 +72,46 @@ type ResourceUsage struct {
 
 // CPUUsage contains CPU usage details
 type CPUUsage struct {
-	Used        string  `json:"used"`
-	UsedNumeric float64 `json:"used_numeric"`
-	Percent     float64 `json:"percent"`
+	Used        string         `json:"used"`
+	UsedNumeric quantity.Cores `json:"used_numeric"`
+	Percent     float64        `json:"percent"`
 }
 
 // MemoryUsage contains memory usage details
 type MemoryUsage struct {
-	Used      string  `json:"used"`
-	UsedBytes int64   `json:"used_bytes"`
-	Percent   float64 `json:"percent"`
+	Used      string         `json:"used"`
+	UsedBytes quantity.Bytes `json:"used_bytes"`
+	Percent   float64        `json:"percent"`
 }
 
 // AvailableCapacity contains available resource capacity
 type AvailableCapacity struct {
 	CPU      *CPUAvailable    `json:"cpu"`
 	Memory   *MemoryAvailable `json:"memory"`
-	PodSlots int64            `json:"pod_slots"`
+	PodSlots quantity.Count   `json:"pod_slots"`
 }
 
 // CPUAvailable contains available CPU capacity
 type CPUAvailable struct {
-	Available        string  `json:"available"`
-	AvailableNumeric float64 `json:"available_numeric"`
-	Percent          float64 `json:"percent"`
+	Available        string         `json:"available"`
+	AvailableNumeric quantity.Cores `json:"available_numeric"`
+	Percent          float64        `json:"percent"`
 }
 
 // MemoryAvailable contains available memory capacity
 type MemoryAvailable struct {
-	Available      string  `json:"available"`
-	AvailableBytes int64   `json:"available_bytes"`
-	Percent        float64 `json:"percent"`
+	Available      string         `json:"available"`
+	AvailableBytes quantity.Bytes `json:"available_bytes"`
+	Percent        float64        `json:"percent"`
 }
 
 // InfrastructureImpact contains infrastructure impact metrics
 type InfrastructureImpact struct {
-	EtcdObjectCount      int64   `json:"etcd_object_count"`
-	EtcdCapacityPercent  float64 `json:"etcd_capacity_percent"`
-	APIServerQPS         float64 `json:"api_server_qps"`
-	SchedulerQueueLength int     `json:"scheduler_queue_length"`
-	ControlPlaneHealth   string  `json:"control_plane_health"`
+	EtcdObjectCount      quantity.Count `json:"etcd_object_count"`
+	EtcdCapacityPercent  float64        `json:"etcd_capacity_percent"`
+	APIServerQPS         float64        `json:"api_server_qps"`
+	SchedulerQueueLength int            `json:"scheduler_queue_length"`
+	ControlPlaneHealth   string         `json:"control_plane_health"`
 }
 
 // ClusterCapacity contains cluster-wide capacity information
@@ -134,11 +137,31 @@ type NamespaceSummary struct {
 	PodCount      int     `json:"pod_count"`
 }
 
+// Thresholds at which a namespace is reported as running out of capacity
+const (
+	// WarningUsagePercent is the quota usage at which a namespace is flagged
+	WarningUsagePercent = 80.0
+	// WarningDaysUntil85Percent flags namespaces projected to reach 85% of quota within this many days
+	WarningDaysUntil85Percent = 14
+)
+
+// Warning flags a namespace that is close to, or trending toward, its quota
+type Warning struct {
+	Namespace               string  `json:"namespace"`
+	CPUPercent              float64 `json:"cpu_percent"`
+	MemoryPercent           float64 `json:"memory_percent"`
+	DaysUntil85Percent      int     `json:"days_until_85_percent"`
+	ProjectedExhaustionDate string  `json:"projected_exhaustion_date,omitempty"`
+}
+
 // ClusterInfrastructure contains cluster infrastructure health information
 type ClusterInfrastructure struct {
 	ControlPlaneCPUPercent    float64 `json:"control_plane_cpu_percent"`
 	ControlPlaneMemoryPercent float64 `json:"control_plane_memory_percent"`
 	EtcdHealth                string  `json:"etcd_health"`
+
+	// ClusterOperators is set when ClusterOperator status can be read (OpenShift only)
+	ClusterOperators *models.ClusterOperatorSummary `json:"cluster_operators,omitempty"`
 }
 
 // GetNamespaceQuota retrieves resource quota for a namespace
@@ -184,18 +207,18 @@ func (a *Analyzer) GetNamespaceQuota(ctx context.Context, namespace string) (*Na
 	if !totalCPU.IsZero() {
 		result.CPU = &CPUQuota{
 			Limit:        totalCPU.String(),
-			LimitNumeric: float64(totalCPU.MilliValue()) / 1000.0,
+			LimitNumeric: quantity.Cores(float64(totalCPU.MilliValue()) / 1000.0),
 		}
 	}
 
 	if !totalMemory.IsZero() {
 		result.Memory = &MemoryQuota{
 			Limit:      totalMemory.String(),
-			LimitBytes: totalMemory.Value(),
+			LimitBytes: quantity.Bytes(totalMemory.Value()),
 		}
 	}
 
-	result.PodCountLimit = podCount
+	result.PodCountLimit = quantity.Count(podCount)
 
 	return result, nil
 }
@@ -303,10 +326,10 @@ func CalculateAvailableCapacity(quota *NamespaceQuota, usage *ResourceUsage) *Av
 		}
 		percent := 0.0
 		if quota.CPU.LimitNumeric > 0 {
-			percent = (availableCPU / quota.CPU.LimitNumeric) * 100
+			percent = (availableCPU.Float64() / quota.CPU.LimitNumeric.Float64()) * 100
 		}
 		result.CPU = &CPUAvailable{
-			Available:        formatCPU(availableCPU),
+			Available:        availableCPU.String(),
 			AvailableNumeric: availableCPU,
 			Percent:          percent,
 		}
@@ -319,17 +342,17 @@ func CalculateAvailableCapacity(quota *NamespaceQuota, usage *ResourceUsage) *Av
 		}
 		percent := 0.0
 		if quota.Memory.LimitBytes > 0 {
-			percent = (float64(availableBytes) / float64(quota.Memory.LimitBytes)) * 100
+			percent = (availableBytes.Float64() / quota.Memory.LimitBytes.Float64()) * 100
 		}
 		result.Memory = &MemoryAvailable{
-			Available:      formatBytes(availableBytes),
+			Available:      availableBytes.String(),
 			AvailableBytes: availableBytes,
 			Percent:        percent,
 		}
 	}
 
 	if quota.PodCountLimit > 0 {
-		result.PodSlots = quota.PodCountLimit - int64(usage.PodCount)
+		result.PodSlots = quota.PodCountLimit - quantity.Count(usage.PodCount)
 		if result.PodSlots < 0 {
 			result.PodSlots = 0
 		}
@@ -340,28 +363,12 @@ func CalculateAvailableCapacity(quota *NamespaceQuota, usage *ResourceUsage) *Av
 
 // formatCPU formats CPU in millicores
 func formatCPU(cores float64) string {
-	millicores := int64(cores * 1000)
-	return fmt.Sprintf("%dm", millicores)
+	return quantity.Cores(cores).String()
 }
 
 // formatBytes formats bytes in human-readable format
 func formatBytes(bytes int64) string {
-	const (
-		KB = 1024
-		MB = KB * 1024
-		GB = MB * 1024
-	)
-
-	switch {
-	case bytes >= GB:
-		return fmt.Sprintf("%.1fGi", float64(bytes)/float64(GB))
-	case bytes >= MB:
-		return fmt.Sprintf("%.1fMi", float64(bytes)/float64(MB))
-	case bytes >= KB:
-		return fmt.Sprintf("%.1fKi", float64(bytes)/float64(KB))
-	default:
-		return fmt.Sprintf("%d", bytes)
-	}
+	return quantity.Bytes(bytes).String()
 }
 
 // FormatDuration formats a duration in human-readable format
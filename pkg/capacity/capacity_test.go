@@ -7,6 +7,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/quantity"
 )
 
 func TestLinearRegression(t *testing.T) {
@@ -361,7 +363,7 @@ func TestCalculateAvailableCapacity(t *testing.T) {
 		usage            *ResourceUsage
 		expectedCPU      float64
 		expectedMem      int64
-		expectedPodSlots int64
+		expectedPodSlots quantity.Count
 	}{
 		{
 			name: "normal usage",
@@ -417,12 +419,12 @@ func TestCalculateAvailableCapacity(t *testing.T) {
 
 			if tt.quota.CPU != nil && tt.usage.CPU != nil {
 				require.NotNil(t, result.CPU)
-				assert.InDelta(t, tt.expectedCPU, result.CPU.AvailableNumeric, 0.1)
+				assert.InDelta(t, tt.expectedCPU, result.CPU.AvailableNumeric.Float64(), 0.1)
 			}
 
 			if tt.quota.Memory != nil && tt.usage.Memory != nil {
 				require.NotNil(t, result.Memory)
-				assert.InDelta(t, tt.expectedMem, result.Memory.AvailableBytes, 1000)
+				assert.InDelta(t, tt.expectedMem, result.Memory.AvailableBytes.Float64(), 1000)
 			}
 
 			if tt.quota.PodCountLimit > 0 {
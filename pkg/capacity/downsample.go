@@ -0,0 +1,149 @@
+package capacity
+
+import (
+	"math"
+	"time"
+)
+
+// Trend series resolutions accepted by the trend and forecast APIs
+const (
+	// ResolutionAuto picks the finest resolution that fits the requested point count
+	ResolutionAuto = "auto"
+	// Resolution1h aggregates samples into hourly buckets
+	Resolution1h = "1h"
+	// Resolution6h aggregates samples into 6 hour buckets
+	Resolution6h = "6h"
+	// Resolution1d aggregates samples into daily buckets
+	Resolution1d = "1d"
+)
+
+// Resolutions lists the accepted resolutions, finest first after auto
+var Resolutions = []string{ResolutionAuto, Resolution1h, Resolution6h, Resolution1d}
+
+// resolutionSteps maps fixed resolutions to their bucket width, finest first
+var resolutionSteps = []struct {
+	name string
+	step time.Duration
+}{
+	{Resolution1h, time.Hour},
+	{Resolution6h, 6 * time.Hour},
+	{Resolution1d, 24 * time.Hour},
+}
+
+// ResolutionStep returns the bucket width of a fixed resolution. For auto it returns
+// the finest step that covers span in at most maxPoints buckets, falling back to the
+// coarsest. It returns false for unknown resolutions.
+func ResolutionStep(resolution string, span time.Duration, maxPoints int) (string, time.Duration, bool) {
+	if resolution == ResolutionAuto {
+		for _, r := range resolutionSteps {
+			if maxPoints <= 0 || int(span/r.step) <= maxPoints {
+				return r.name, r.step, true
+			}
+		}
+		last := resolutionSteps[len(resolutionSteps)-1]
+		return last.name, last.step, true
+	}
+	for _, r := range resolutionSteps {
+		if r.name == resolution {
+			return r.name, r.step, true
+		}
+	}
+	return "", 0, false
+}
+
+// Aggregate averages points, sorted by timestamp, into buckets of width step aligned
+// to the Unix epoch. Each bucket is reported at its start. Non-finite values are left
+// out of the means and buckets without finite values are dropped.
+func Aggregate(points []DataPoint, step time.Duration) []DataPoint {
+	if step <= 0 || len(points) == 0 {
+		return points
+	}
+
+	result := make([]DataPoint, 0, len(points))
+	var bucket time.Time
+	var sum float64
+	var count int
+	flush := func() {
+		if count > 0 {
+			result = append(result, DataPoint{Timestamp: bucket, Value: sum / float64(count)})
+		}
+		sum, count = 0, 0
+	}
+	for _, p := range points {
+		if math.IsNaN(p.Value) || math.IsInf(p.Value, 0) {
+			continue
+		}
+		start := p.Timestamp.Truncate(step)
+		if !start.Equal(bucket) {
+			flush()
+			bucket = start
+		}
+		sum += p.Value
+		count++
+	}
+	flush()
+	return result
+}
+
+// LTTB downsamples points, sorted by timestamp, to threshold points with the
+// Largest-Triangle-Three-Buckets algorithm. Unlike averaging it keeps the peaks and
+// troughs that give a chart its shape. The first and last points are always kept;
+// series already within threshold, or thresholds below 3, are returned unchanged.
+func LTTB(points []DataPoint, threshold int) []DataPoint {
+	if threshold < 3 || len(points) <= threshold {
+		return points
+	}
+
+	sampled := make([]DataPoint, 0, threshold)
+	sampled = append(sampled, points[0])
+
+	// The points between the first and the last are split into threshold-2 buckets
+	every := float64(len(points)-2) / float64(threshold-2)
+	selected := 0
+	for i := 0; i < threshold-2; i++ {
+		// The average of the next bucket is the third vertex of the triangles
+		nextStart := int(math.Floor(float64(i+1)*every)) + 1
+		nextEnd := int(math.Floor(float64(i+2)*every)) + 1
+		if nextEnd > len(points) {
+			nextEnd = len(points)
+		}
+		var avgX, avgY float64
+		for _, p := range points[nextStart:nextEnd] {
+			avgX += unixSeconds(p.Timestamp)
+			avgY += p.Value
+		}
+		n := float64(nextEnd - nextStart)
+		avgX /= n
+		avgY /= n
+
+		// Keep the point of this bucket forming the largest triangle with the point
+		// selected last and the next bucket's average
+		start := int(math.Floor(float64(i)*every)) + 1
+		end := nextStart
+		ax, ay := unixSeconds(points[selected].Timestamp), points[selected].Value
+		maxArea := -1.0
+		next := start
+		for j := start; j < end; j++ {
+			area := math.Abs((ax-avgX)*(points[j].Value-ay) - (ax-unixSeconds(points[j].Timestamp))*(avgY-ay))
+			if area > maxArea {
+				maxArea = area
+				next = j
+			}
+		}
+		sampled = append(sampled, points[next])
+		selected = next
+	}
+
+	return append(sampled, points[len(points)-1])
+}
+
+// Downsample aggregates points into step buckets, then reduces them to at most
+// maxPoints with LTTB. A zero step or maxPoints skips that stage.
+func Downsample(points []DataPoint, step time.Duration, maxPoints int) []DataPoint {
+	return LTTB(Aggregate(points, step), maxPoints)
+}
+
+// unixSeconds returns t as fractional Unix seconds for triangle areas
+func unixSeconds(t time.Time) float64 {
+	return float64(t.UnixNano()) / float64(time.Second)
+}
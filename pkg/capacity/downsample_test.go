@@ -0,0 +1,108 @@
+package capacity
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// hourlyPoints returns count hourly points from start with values from fn
+func hourlyPoints(start time.Time, count int, fn func(i int) float64) []DataPoint {
+	points := make([]DataPoint, count)
+	for i := range points {
+		points[i] = DataPoint{Timestamp: start.Add(time.Duration(i) * time.Hour), Value: fn(i)}
+	}
+	return points
+}
+
+func TestResolutionStep(t *testing.T) {
+	tests := []struct {
+		resolution string
+		span       time.Duration
+		maxPoints  int
+		wantName   string
+		wantStep   time.Duration
+	}{
+		{Resolution1h, 30 * 24 * time.Hour, 10, Resolution1h, time.Hour},
+		{Resolution6h, 7 * 24 * time.Hour, 0, Resolution6h, 6 * time.Hour},
+		{Resolution1d, 7 * 24 * time.Hour, 0, Resolution1d, 24 * time.Hour},
+		{ResolutionAuto, 7 * 24 * time.Hour, 200, Resolution1h, time.Hour},
+		{ResolutionAuto, 30 * 24 * time.Hour, 200, Resolution6h, 6 * time.Hour},
+		{ResolutionAuto, 30 * 24 * time.Hour, 60, Resolution1d, 24 * time.Hour},
+		{ResolutionAuto, 30 * 24 * time.Hour, 10, Resolution1d, 24 * time.Hour},
+	}
+	for _, tt := range tests {
+		name, step, ok := ResolutionStep(tt.resolution, tt.span, tt.maxPoints)
+		require.True(t, ok, tt.resolution)
+		assert.Equal(t, tt.wantName, name, "%s over %s in %d points", tt.resolution, tt.span, tt.maxPoints)
+		assert.Equal(t, tt.wantStep, step)
+	}
+
+	_, _, ok := ResolutionStep("5m", time.Hour, 0)
+	assert.False(t, ok)
+}
+
+func TestAggregate(t *testing.T) {
+	start := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	points := hourlyPoints(start, 48, func(i int) float64 { return float64(i) })
+	points[7].Value = math.NaN()
+
+	daily := Aggregate(points, 24*time.Hour)
+	require.Len(t, daily, 2)
+	assert.Equal(t, start, daily[0].Timestamp)
+	assert.InDelta(t, (276.0-7)/23, daily[0].Value, 1e-9, "NaN is left out of the mean")
+	assert.Equal(t, start.Add(24*time.Hour), daily[1].Timestamp)
+	assert.Equal(t, 35.5, daily[1].Value)
+
+	sixHourly := Aggregate(points, 6*time.Hour)
+	assert.Len(t, sixHourly, 8)
+	assert.Equal(t, 2.5, sixHourly[0].Value)
+
+	assert.Equal(t, points, Aggregate(points, 0))
+	assert.Empty(t, Aggregate(nil, time.Hour))
+}
+
+func TestLTTB(t *testing.T) {
+	start := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("keeps the shape", func(t *testing.T) {
+		// A flat 30 day hourly series with a one hour spike and dip
+		points := hourlyPoints(start, 720, func(int) float64 { return 1 })
+		points[300].Value = 50
+		points[500].Value = -20
+
+		sampled := LTTB(points, 100)
+		require.Len(t, sampled, 100)
+		assert.Equal(t, points[0], sampled[0])
+		assert.Equal(t, points[719], sampled[99])
+		assert.Contains(t, sampled, points[300], "the spike survives downsampling")
+		assert.Contains(t, sampled, points[500], "the dip survives downsampling")
+		for i := 1; i < len(sampled); i++ {
+			assert.True(t, sampled[i].Timestamp.After(sampled[i-1].Timestamp))
+		}
+	})
+
+	t.Run("short series are unchanged", func(t *testing.T) {
+		points := hourlyPoints(start, 10, func(i int) float64 { return float64(i) })
+		assert.Equal(t, points, LTTB(points, 10))
+		assert.Equal(t, points, LTTB(points, 50))
+		assert.Equal(t, points, LTTB(points, 2))
+	})
+
+	t.Run("downsample aggregates then reduces", func(t *testing.T) {
+		points := hourlyPoints(start, 720, func(i int) float64 { return float64(i % 24) })
+		assert.Len(t, Downsample(points, 6*time.Hour, 200), 120)
+		assert.Len(t, Downsample(points, time.Hour, 200), 200)
+	})
+}
+
+func BenchmarkLTTB(b *testing.B) {
+	points := hourlyPoints(time.Now(), 720, func(i int) float64 { return math.Sin(float64(i) / 10) })
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		LTTB(points, 200)
+	}
+}
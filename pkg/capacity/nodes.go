@@ -0,0 +1,424 @@
+package capacity
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Node bin-packing thresholds used to suggest actions
+const (
+	// FragmentationThreshold is the share of free capacity stranded in pieces too small
+	// for the reference pod above which a descheduler run is suggested
+	FragmentationThreshold = 0.4
+
+	// MinHeadroomPods is the number of reference pods the cluster should still fit
+	MinHeadroomPods = 2
+
+	// HighRequestPercent and LowRequestPercent bound healthy cluster request utilization
+	HighRequestPercent = 85.0
+	LowRequestPercent  = 40.0
+
+	// ImbalancePercent is the gap between CPU and memory request utilization that
+	// suggests a node pool with a different CPU to memory ratio
+	ImbalancePercent = 40.0
+
+	// MaxMemoryOvercommit is the memory limits to allocatable ratio above which nodes
+	// risk OOM kills when workloads burst together
+	MaxMemoryOvercommit = 1.5
+)
+
+// DefaultReferencePod is the pod size headroom and fragmentation are measured with
+var DefaultReferencePod = PodSize{CPU: 0.5, Memory: 1024 * 1024 * 1024}
+
+// PodSize is the CPU (cores) and memory (bytes) requests of a pod
+type PodSize struct {
+	CPU    float64 `json:"cpu_cores"`
+	Memory int64   `json:"memory_bytes"`
+}
+
+// NodeAllocation compares the requests and limits of the pods on a node with its
+// allocatable resources. CPU is in cores and memory in bytes; overcommit ratios are
+// limits divided by allocatable.
+type NodeAllocation struct {
+	Name                 string   `json:"name"`
+	Roles                []string `json:"roles,omitempty"`
+	Schedulable          bool     `json:"schedulable"`
+	Pods                 int      `json:"pods"`
+	PodCapacity          int64    `json:"pod_capacity"`
+	AllocatableCPU       float64  `json:"allocatable_cpu"`
+	AllocatableMemory    int64    `json:"allocatable_memory"`
+	RequestedCPU         float64  `json:"requested_cpu"`
+	RequestedMemory      int64    `json:"requested_memory"`
+	LimitsCPU            float64  `json:"limits_cpu"`
+	LimitsMemory         int64    `json:"limits_memory"`
+	CPURequestPercent    float64  `json:"cpu_request_percent"`
+	MemoryRequestPercent float64  `json:"memory_request_percent"`
+	CPUOvercommit        float64  `json:"cpu_overcommit_ratio"`
+	MemoryOvercommit     float64  `json:"memory_overcommit_ratio"`
+	FreeCPU              float64  `json:"free_cpu"`
+	FreeMemory           int64    `json:"free_memory"`
+	ReferencePodsFit     int      `json:"reference_pods_fit"`
+}
+
+// ResourceBinPacking summarizes one resource across schedulable nodes. Fragmentation is
+// the share (0-1) of free capacity that cannot host a reference pod because it is split
+// across nodes or paired with too little of the other resource.
+type ResourceBinPacking struct {
+	Allocatable     float64 `json:"allocatable"`
+	Requested       float64 `json:"requested"`
+	Limits          float64 `json:"limits"`
+	Free            float64 `json:"free"`
+	LargestFree     float64 `json:"largest_free"`
+	RequestPercent  float64 `json:"request_percent"`
+	OvercommitRatio float64 `json:"overcommit_ratio"`
+	Fragmentation   float64 `json:"fragmentation"`
+}
+
+// NodeSuggestion is an action suggested by the node analysis
+type NodeSuggestion struct {
+	IssueType string   `json:"issue_type"`
+	Severity  string   `json:"severity"`
+	Actions   []string `json:"actions"`
+	Evidence  []string `json:"evidence"`
+}
+
+// NodeAnalysis is the bin-packing health of the cluster's nodes
+type NodeAnalysis struct {
+	NodeCount        int                `json:"node_count"`
+	SchedulableNodes int                `json:"schedulable_nodes"`
+	ReferencePod     PodSize            `json:"reference_pod"`
+	Headroom         int                `json:"headroom_reference_pods"`
+	CPU              ResourceBinPacking `json:"cpu"`
+	Memory           ResourceBinPacking `json:"memory"`
+	Nodes            []NodeAllocation   `json:"nodes"`
+	Suggestions      []NodeSuggestion   `json:"suggestions"`
+}
+
+// AnalyzeNodes computes per-node requests, limits and free capacity, how many reference
+// pods still fit (headroom) and how fragmented the free capacity is. Only Ready,
+// uncordoned nodes without NoSchedule or NoExecute taints count toward cluster totals.
+func (a *Analyzer) AnalyzeNodes(ctx context.Context, reference PodSize) (*NodeAnalysis, error) {
+	if reference.CPU <= 0 || reference.Memory <= 0 {
+		reference = DefaultReferencePod
+	}
+
+	nodes, err := a.k8sClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	pods, err := a.k8sClient.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list all pods: %w", err)
+	}
+
+	allocations := make(map[string]*NodeAllocation, len(nodes.Items))
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		allocations[node.Name] = &NodeAllocation{
+			Name:              node.Name,
+			Roles:             nodeRoles(node),
+			Schedulable:       nodeSchedulable(node),
+			PodCapacity:       node.Status.Allocatable.Pods().Value(),
+			AllocatableCPU:    float64(node.Status.Allocatable.Cpu().MilliValue()) / 1000.0,
+			AllocatableMemory: node.Status.Allocatable.Memory().Value(),
+		}
+	}
+
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		alloc, ok := allocations[pod.Spec.NodeName]
+		if !ok {
+			continue
+		}
+		requests, limits := podResources(pod)
+		alloc.Pods++
+		alloc.RequestedCPU += float64(requests.Cpu().MilliValue()) / 1000.0
+		alloc.RequestedMemory += requests.Memory().Value()
+		alloc.LimitsCPU += float64(limits.Cpu().MilliValue()) / 1000.0
+		alloc.LimitsMemory += limits.Memory().Value()
+	}
+
+	analysis := &NodeAnalysis{
+		NodeCount:    len(nodes.Items),
+		ReferencePod: reference,
+		Nodes:        make([]NodeAllocation, 0, len(allocations)),
+		Suggestions:  []NodeSuggestion{},
+	}
+	var usableCPU, usableMemory float64
+	for _, alloc := range allocations {
+		alloc.FreeCPU = math.Max(alloc.AllocatableCPU-alloc.RequestedCPU, 0)
+		alloc.FreeMemory = max(alloc.AllocatableMemory-alloc.RequestedMemory, 0)
+		alloc.CPURequestPercent = percentOf(alloc.RequestedCPU, alloc.AllocatableCPU)
+		alloc.MemoryRequestPercent = percentOf(float64(alloc.RequestedMemory), float64(alloc.AllocatableMemory))
+		alloc.CPUOvercommit = ratioOf(alloc.LimitsCPU, alloc.AllocatableCPU)
+		alloc.MemoryOvercommit = ratioOf(float64(alloc.LimitsMemory), float64(alloc.AllocatableMemory))
+		alloc.FreeCPU = roundTo(alloc.FreeCPU, 3)
+		alloc.RequestedCPU = roundTo(alloc.RequestedCPU, 3)
+		alloc.LimitsCPU = roundTo(alloc.LimitsCPU, 3)
+
+		if alloc.Schedulable {
+			fit := int(math.Min(
+				math.Floor(alloc.FreeCPU/reference.CPU+1e-9),
+				math.Floor(float64(alloc.FreeMemory)/float64(reference.Memory)),
+			))
+			if slots := int(alloc.PodCapacity) - alloc.Pods; alloc.PodCapacity > 0 && slots < fit {
+				fit = max(slots, 0)
+			}
+			alloc.ReferencePodsFit = fit
+
+			analysis.SchedulableNodes++
+			analysis.Headroom += fit
+			usableCPU += float64(fit) * reference.CPU
+			usableMemory += float64(fit) * float64(reference.Memory)
+			addBinPacking(&analysis.CPU, alloc.AllocatableCPU, alloc.RequestedCPU, alloc.LimitsCPU, alloc.FreeCPU)
+			addBinPacking(&analysis.Memory, float64(alloc.AllocatableMemory), float64(alloc.RequestedMemory),
+				float64(alloc.LimitsMemory), float64(alloc.FreeMemory))
+		}
+		analysis.Nodes = append(analysis.Nodes, *alloc)
+	}
+
+	finishBinPacking(&analysis.CPU, usableCPU, 3)
+	finishBinPacking(&analysis.Memory, usableMemory, 0)
+	sort.Slice(analysis.Nodes, func(i, j int) bool {
+		return analysis.Nodes[i].Name < analysis.Nodes[j].Name
+	})
+	analysis.Suggestions = suggestNodeActions(analysis)
+
+	return analysis, nil
+}
+
+// addBinPacking adds a schedulable node's resource figures to the cluster totals
+func addBinPacking(b *ResourceBinPacking, allocatable, requested, limits, free float64) {
+	b.Allocatable += allocatable
+	b.Requested += requested
+	b.Limits += limits
+	b.Free += free
+	b.LargestFree = math.Max(b.LargestFree, free)
+}
+
+// finishBinPacking derives the ratios once all nodes are added; usable is the free
+// capacity reference pods can occupy
+func finishBinPacking(b *ResourceBinPacking, usable float64, decimals int) {
+	b.RequestPercent = percentOf(b.Requested, b.Allocatable)
+	b.OvercommitRatio = ratioOf(b.Limits, b.Allocatable)
+	if b.Free > 0 {
+		b.Fragmentation = roundTo(math.Max(1-usable/b.Free, 0), 2)
+	}
+	b.Allocatable = roundTo(b.Allocatable, decimals)
+	b.Requested = roundTo(b.Requested, decimals)
+	b.Limits = roundTo(b.Limits, decimals)
+	b.Free = roundTo(b.Free, decimals)
+	b.LargestFree = roundTo(b.LargestFree, decimals)
+}
+
+// suggestNodeActions suggests descheduler runs and node pool changes from the analysis
+func suggestNodeActions(analysis *NodeAnalysis) []NodeSuggestion {
+	suggestions := []NodeSuggestion{}
+	if analysis.SchedulableNodes == 0 {
+		return suggestions
+	}
+	cpu, memory := &analysis.CPU, &analysis.Memory
+	reference := fmt.Sprintf("%s CPU / %s memory", formatCPU(analysis.ReferencePod.CPU), formatBytes(analysis.ReferencePod.Memory))
+
+	lowHeadroom := analysis.Headroom < MinHeadroomPods
+	fragmented := cpu.Fragmentation >= FragmentationThreshold || memory.Fragmentation >= FragmentationThreshold
+	roomToConsolidate := cpu.Free >= 2*analysis.ReferencePod.CPU && memory.Free >= 2*float64(analysis.ReferencePod.Memory)
+
+	if fragmented && roomToConsolidate {
+		severity := "medium"
+		if lowHeadroom {
+			severity = "high"
+		}
+		suggestions = append(suggestions, NodeSuggestion{
+			IssueType: "node_fragmentation",
+			Severity:  severity,
+			Actions:   []string{"run_descheduler", "enable_descheduler_profile_LifecycleAndUtilization", "review_pod_anti_affinity"},
+			Evidence: []string{
+				fmt.Sprintf("%.0f%% of free CPU and %.0f%% of free memory cannot host a %s pod",
+					cpu.Fragmentation*100, memory.Fragmentation*100, reference),
+				fmt.Sprintf("Cluster has %s CPU and %s memory free but only fits %d such pods",
+					formatCPU(cpu.Free), formatBytes(int64(math.Round(memory.Free))), analysis.Headroom),
+			},
+		})
+	}
+
+	if lowHeadroom || cpu.RequestPercent >= HighRequestPercent || memory.RequestPercent >= HighRequestPercent {
+		severity := "medium"
+		if lowHeadroom {
+			severity = "high"
+		}
+		suggestions = append(suggestions, NodeSuggestion{
+			IssueType: "node_capacity_low",
+			Severity:  severity,
+			Actions:   []string{"scale_up_node_pool", "enable_cluster_autoscaler", "review_resource_requests"},
+			Evidence: []string{
+				fmt.Sprintf("Requests use %.1f%% of allocatable CPU and %.1f%% of allocatable memory", cpu.RequestPercent, memory.RequestPercent),
+				fmt.Sprintf("Only %d more %s pods fit on %d schedulable nodes", analysis.Headroom, reference, analysis.SchedulableNodes),
+			},
+		})
+	}
+
+	if analysis.SchedulableNodes > 3 && cpu.RequestPercent < LowRequestPercent && memory.RequestPercent < LowRequestPercent {
+		suggestions = append(suggestions, NodeSuggestion{
+			IssueType: "node_pool_underutilized",
+			Severity:  "low",
+			Actions:   []string{"scale_down_node_pool", "run_descheduler", "enable_cluster_autoscaler_scale_down"},
+			Evidence: []string{
+				fmt.Sprintf("Requests use only %.1f%% of allocatable CPU and %.1f%% of allocatable memory across %d nodes",
+					cpu.RequestPercent, memory.RequestPercent, analysis.SchedulableNodes),
+			},
+		})
+	}
+
+	if math.Abs(cpu.RequestPercent-memory.RequestPercent) >= ImbalancePercent {
+		bound, idle, poolType := "CPU", "memory", "compute-optimized"
+		if memory.RequestPercent > cpu.RequestPercent {
+			bound, idle, poolType = "memory", "CPU", "memory-optimized"
+		}
+		suggestions = append(suggestions, NodeSuggestion{
+			IssueType: "node_resource_imbalance",
+			Severity:  "low",
+			Actions:   []string{"add_" + strings.ReplaceAll(poolType, "-", "_") + "_node_pool", "review_resource_requests"},
+			Evidence: []string{
+				fmt.Sprintf("Requests use %.1f%% of allocatable CPU but %.1f%% of allocatable memory",
+					cpu.RequestPercent, memory.RequestPercent),
+				fmt.Sprintf("Nodes fill up on %s while %s sits idle; a %s node pool would pack workloads better", bound, idle, poolType),
+			},
+		})
+	}
+
+	if memory.OvercommitRatio > MaxMemoryOvercommit {
+		suggestions = append(suggestions, NodeSuggestion{
+			IssueType: "memory_overcommit",
+			Severity:  "medium",
+			Actions:   []string{"review_memory_limits", "align_memory_requests_with_limits"},
+			Evidence: []string{
+				fmt.Sprintf("Memory limits are %.2fx allocatable memory; pods risk OOM kills when they burst together", memory.OvercommitRatio),
+			},
+		})
+	}
+
+	return suggestions
+}
+
+// podResources returns the effective requests and limits of a pod: the larger of the
+// sum over its containers and any single init container, plus pod overhead
+func podResources(pod *corev1.Pod) (requests, limits corev1.ResourceList) {
+	requests, limits = corev1.ResourceList{}, corev1.ResourceList{}
+	for i := range pod.Spec.Containers {
+		addResources(requests, pod.Spec.Containers[i].Resources.Requests)
+		addResources(limits, pod.Spec.Containers[i].Resources.Limits)
+	}
+	for i := range pod.Spec.InitContainers {
+		maxResources(requests, pod.Spec.InitContainers[i].Resources.Requests)
+		maxResources(limits, pod.Spec.InitContainers[i].Resources.Limits)
+	}
+	addResources(requests, pod.Spec.Overhead)
+	addResources(limits, pod.Spec.Overhead)
+	return requests, limits
+}
+
+// addResources adds the CPU and memory of src to dst
+func addResources(dst, src corev1.ResourceList) {
+	for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		if quantity, ok := src[name]; ok {
+			total := dst[name]
+			total.Add(quantity)
+			dst[name] = total
+		}
+	}
+}
+
+// maxResources raises the CPU and memory of dst to those of src where larger
+func maxResources(dst, src corev1.ResourceList) {
+	for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		if quantity, ok := src[name]; ok {
+			if current := dst[name]; quantity.Cmp(current) > 0 {
+				dst[name] = quantity.DeepCopy()
+			}
+		}
+	}
+}
+
+// nodeRoles returns the node-role.kubernetes.io/<role> labels of a node
+func nodeRoles(node *corev1.Node) []string {
+	var roles []string
+	for label := range node.Labels {
+		if role, ok := strings.CutPrefix(label, "node-role.kubernetes.io/"); ok && role != "" {
+			roles = append(roles, role)
+		}
+	}
+	sort.Strings(roles)
+	return roles
+}
+
+// nodeSchedulable reports whether new pods without tolerations can land on a node
+func nodeSchedulable(node *corev1.Node) bool {
+	if node.Spec.Unschedulable {
+		return false
+	}
+	for _, taint := range node.Spec.Taints {
+		if taint.Effect == corev1.TaintEffectNoSchedule || taint.Effect == corev1.TaintEffectNoExecute {
+			return false
+		}
+	}
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// ParsePodSize parses CPU and memory quantities (e.g. "500m", "1Gi"); empty values
+// take the DefaultReferencePod size
+func ParsePodSize(cpu, memory string) (PodSize, error) {
+	size := DefaultReferencePod
+	if cpu != "" {
+		quantity, err := resource.ParseQuantity(cpu)
+		if err != nil || quantity.Sign() <= 0 {
+			return size, fmt.Errorf("invalid CPU quantity %q", cpu)
+		}
+		size.CPU = float64(quantity.MilliValue()) / 1000.0
+	}
+	if memory != "" {
+		quantity, err := resource.ParseQuantity(memory)
+		if err != nil || quantity.Sign() <= 0 {
+			return size, fmt.Errorf("invalid memory quantity %q", memory)
+		}
+		size.Memory = quantity.Value()
+	}
+	return size, nil
+}
+
+// percentOf returns part as a percentage of total, rounded to one decimal
+func percentOf(part, total float64) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return roundTo(part/total*100, 1)
+}
+
+// ratioOf returns part divided by total, rounded to two decimals
+func ratioOf(part, total float64) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return roundTo(part/total, 2)
+}
+
+// roundTo rounds value to the given number of decimals
+func roundTo(value float64, decimals int) float64 {
+	scale := math.Pow(10, float64(decimals))
+	return math.Round(value*scale) / scale
+}
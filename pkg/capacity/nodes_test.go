@@ -0,0 +1,187 @@
+package capacity
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const gib = 1024 * mib
+
+func testNode(name string, mutate func(*corev1.Node)) *corev1.Node {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{"node-role.kubernetes.io/worker": ""},
+		},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("4"),
+				corev1.ResourceMemory: resource.MustParse("16Gi"),
+				corev1.ResourcePods:   resource.MustParse("110"),
+			},
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+		},
+	}
+	if mutate != nil {
+		mutate(node)
+	}
+	return node
+}
+
+func testPod(name, node, cpu, memory, memoryLimit string, phase corev1.PodPhase) *corev1.Pod {
+	container := corev1.Container{
+		Name: "app",
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(cpu),
+				corev1.ResourceMemory: resource.MustParse(memory),
+			},
+		},
+	}
+	if memoryLimit != "" {
+		container.Resources.Limits = corev1.ResourceList{corev1.ResourceMemory: resource.MustParse(memoryLimit)}
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "apps"},
+		Spec:       corev1.PodSpec{NodeName: node, Containers: []corev1.Container{container}},
+		Status:     corev1.PodStatus{Phase: phase},
+	}
+}
+
+func TestAnalyzer_AnalyzeNodes(t *testing.T) {
+	// worker-a is CPU-full and worker-b memory-full: plenty of free capacity overall,
+	// but no node fits a 500m / 1Gi pod
+	initPod := testPod("batch", "worker-a", "100m", "1Gi", "", corev1.PodRunning)
+	initPod.Spec.InitContainers = []corev1.Container{{
+		Name: "init",
+		Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+			corev1.ResourceCPU: resource.MustParse("2"),
+		}},
+	}}
+	objects := []runtime.Object{
+		testNode("worker-a", nil),
+		testNode("worker-b", nil),
+		testNode("master-0", func(n *corev1.Node) {
+			n.Labels = map[string]string{"node-role.kubernetes.io/master": ""}
+			n.Spec.Taints = []corev1.Taint{{Key: "node-role.kubernetes.io/master", Effect: corev1.TaintEffectNoSchedule}}
+		}),
+		testNode("worker-c", func(n *corev1.Node) { n.Spec.Unschedulable = true }),
+		testPod("api", "worker-a", "1800m", "1Gi", "40Gi", corev1.PodRunning),
+		initPod,
+		testPod("cache", "worker-b", "200m", "15872Mi", "", corev1.PodRunning),
+		testPod("done", "worker-b", "2", "8Gi", "", corev1.PodSucceeded),
+		testPod("etcd", "master-0", "1", "4Gi", "", corev1.PodRunning),
+		testPod("pending", "", "1", "1Gi", "", corev1.PodPending),
+	}
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	analyzer := NewAnalyzer(fake.NewSimpleClientset(objects...), log)
+
+	analysis, err := analyzer.AnalyzeNodes(context.Background(), PodSize{})
+	require.NoError(t, err)
+
+	assert.Equal(t, DefaultReferencePod, analysis.ReferencePod)
+	assert.Equal(t, 4, analysis.NodeCount)
+	assert.Equal(t, 2, analysis.SchedulableNodes)
+	require.Len(t, analysis.Nodes, 4)
+
+	workerA := analysis.Nodes[1]
+	assert.Equal(t, "worker-a", workerA.Name)
+	assert.Equal(t, []string{"worker"}, workerA.Roles)
+	assert.Equal(t, 2, workerA.Pods)
+	assert.Equal(t, 3.8, workerA.RequestedCPU, "init containers count when larger than the app containers")
+	assert.Equal(t, 95.0, workerA.CPURequestPercent)
+	assert.Equal(t, 2.5, workerA.MemoryOvercommit)
+	assert.Equal(t, 0, workerA.ReferencePodsFit)
+
+	master := analysis.Nodes[0]
+	assert.False(t, master.Schedulable)
+	assert.Equal(t, []string{"master"}, master.Roles)
+	assert.Equal(t, 1, master.Pods)
+	assert.False(t, analysis.Nodes[3].Schedulable, "cordoned nodes are not schedulable")
+
+	// Totals cover the two schedulable workers only
+	assert.Equal(t, 8.0, analysis.CPU.Allocatable)
+	assert.Equal(t, 4.0, analysis.CPU.Requested)
+	assert.Equal(t, 50.0, analysis.CPU.RequestPercent)
+	assert.Equal(t, 3.8, analysis.CPU.LargestFree)
+	assert.Equal(t, 1.0, analysis.CPU.Fragmentation)
+	assert.Equal(t, 1.0, analysis.Memory.Fragmentation)
+	assert.Equal(t, 1.25, analysis.Memory.OvercommitRatio)
+	assert.Equal(t, 0, analysis.Headroom)
+
+	issues := make(map[string]string)
+	for _, s := range analysis.Suggestions {
+		issues[s.IssueType] = s.Severity
+	}
+	assert.Equal(t, map[string]string{"node_fragmentation": "high", "node_capacity_low": "high"}, issues)
+	assert.Contains(t, analysis.Suggestions[0].Actions, "run_descheduler")
+}
+
+func TestSuggestNodeActions(t *testing.T) {
+	base := func() *NodeAnalysis {
+		return &NodeAnalysis{
+			SchedulableNodes: 6,
+			ReferencePod:     DefaultReferencePod,
+			Headroom:         20,
+			CPU:              ResourceBinPacking{RequestPercent: 60, Free: 10},
+			Memory:           ResourceBinPacking{RequestPercent: 60, Free: 40 * gib},
+		}
+	}
+	issueTypes := func(analysis *NodeAnalysis) []string {
+		var types []string
+		for _, s := range suggestNodeActions(analysis) {
+			types = append(types, s.IssueType)
+		}
+		return types
+	}
+
+	assert.Empty(t, issueTypes(base()), "a healthy cluster needs no action")
+
+	underutilized := base()
+	underutilized.CPU.RequestPercent, underutilized.Memory.RequestPercent = 20, 30
+	assert.Equal(t, []string{"node_pool_underutilized"}, issueTypes(underutilized))
+
+	imbalanced := base()
+	imbalanced.CPU.RequestPercent, imbalanced.Memory.RequestPercent = 30, 80
+	suggestions := suggestNodeActions(imbalanced)
+	require.Len(t, suggestions, 1)
+	assert.Equal(t, "node_resource_imbalance", suggestions[0].IssueType)
+	assert.Equal(t, "add_memory_optimized_node_pool", suggestions[0].Actions[0])
+
+	overcommitted := base()
+	overcommitted.Memory.OvercommitRatio = 2
+	assert.Equal(t, []string{"memory_overcommit"}, issueTypes(overcommitted))
+
+	fragmented := base()
+	fragmented.CPU.Fragmentation = 0.5
+	assert.Equal(t, []string{"node_fragmentation"}, issueTypes(fragmented))
+	fragmented.CPU.Free = 0.5
+	assert.Empty(t, issueTypes(fragmented), "too little free capacity to consolidate")
+
+	assert.Empty(t, issueTypes(&NodeAnalysis{}), "no schedulable nodes")
+}
+
+func TestParsePodSize(t *testing.T) {
+	size, err := ParsePodSize("", "")
+	require.NoError(t, err)
+	assert.Equal(t, DefaultReferencePod, size)
+
+	size, err = ParsePodSize("250m", "512Mi")
+	require.NoError(t, err)
+	assert.Equal(t, PodSize{CPU: 0.25, Memory: 512 * mib}, size)
+
+	_, err = ParsePodSize("lots", "")
+	assert.Error(t, err)
+	_, err = ParsePodSize("", "-1Gi")
+	assert.Error(t, err)
+}
@@ -0,0 +1,105 @@
+package capacity
+
+import (
+	"math"
+	"sort"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/quantity"
+)
+
+// DefaultPodBreakdownLimit caps the pods listed in a namespace usage breakdown
+const DefaultPodBreakdownLimit = 50
+
+// PodUsage summarizes the usage of one pod over a trending window
+type PodUsage struct {
+	Pod                string         `json:"pod"`
+	CPUAverage         quantity.Cores `json:"cpu_average"`
+	CPUPeak            quantity.Cores `json:"cpu_peak"`
+	MemoryAverage      quantity.Bytes `json:"memory_average_bytes"`
+	MemoryPeak         quantity.Bytes `json:"memory_peak_bytes"`
+	CPUSharePercent    float64        `json:"cpu_share_percent"`    // of the namespace's average CPU
+	MemorySharePercent float64        `json:"memory_share_percent"` // of the namespace's average memory
+}
+
+// BreakdownByPod summarizes per-pod CPU and memory series, keyed by pod name, sorted by
+// average CPU and then average memory, heaviest first. At most limit pods are returned;
+// a limit of 0 returns all. Shares are computed over all pods, listed or not.
+func BreakdownByPod(cpu, memory map[string][]DataPoint, limit int) []PodUsage {
+	pods := make(map[string]*PodUsage)
+	get := func(name string) *PodUsage {
+		if pods[name] == nil {
+			pods[name] = &PodUsage{Pod: name}
+		}
+		return pods[name]
+	}
+
+	var cpuTotal, memoryTotal float64
+	cpuAverages := make(map[string]float64, len(cpu))
+	for name, points := range cpu {
+		avg, peak := averageAndPeak(points)
+		usage := get(name)
+		usage.CPUAverage, usage.CPUPeak = quantity.Cores(round3(avg)), quantity.Cores(round3(peak))
+		cpuAverages[name] = avg
+		cpuTotal += avg
+	}
+	memoryAverages := make(map[string]float64, len(memory))
+	for name, points := range memory {
+		avg, peak := averageAndPeak(points)
+		usage := get(name)
+		// averageAndPeak only sees finite values, so the conversions cannot fail
+		usage.MemoryAverage, _ = quantity.BytesFromFloat(avg)
+		usage.MemoryPeak, _ = quantity.BytesFromFloat(peak)
+		memoryAverages[name] = avg
+		memoryTotal += avg
+	}
+
+	result := make([]PodUsage, 0, len(pods))
+	for _, usage := range pods {
+		if cpuTotal > 0 {
+			usage.CPUSharePercent = math.Round(cpuAverages[usage.Pod]/cpuTotal*10000) / 100
+		}
+		if memoryTotal > 0 {
+			usage.MemorySharePercent = math.Round(memoryAverages[usage.Pod]/memoryTotal*10000) / 100
+		}
+		result = append(result, *usage)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].CPUAverage != result[j].CPUAverage {
+			return result[i].CPUAverage > result[j].CPUAverage
+		}
+		if result[i].MemoryAverage != result[j].MemoryAverage {
+			return result[i].MemoryAverage > result[j].MemoryAverage
+		}
+		return result[i].Pod < result[j].Pod
+	})
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
+
+// averageAndPeak returns the mean and maximum of the finite values of points
+func averageAndPeak(points []DataPoint) (avg, peak float64) {
+	var sum float64
+	var count int
+	for _, p := range points {
+		if math.IsNaN(p.Value) || math.IsInf(p.Value, 0) {
+			continue
+		}
+		sum += p.Value
+		if count == 0 || p.Value > peak {
+			peak = p.Value
+		}
+		count++
+	}
+	if count == 0 {
+		return 0, 0
+	}
+	return sum / float64(count), peak
+}
+
+// round3 rounds to 3 decimal places, i.e. whole millicores
+func round3(value float64) float64 {
+	return math.Round(value*1000) / 1000
+}
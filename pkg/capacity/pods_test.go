@@ -0,0 +1,49 @@
+package capacity
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/quantity"
+)
+
+func TestBreakdownByPod(t *testing.T) {
+	start := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	constant := func(v float64) func(int) float64 { return func(int) float64 { return v } }
+
+	cpu := map[string][]DataPoint{
+		"api-1":    hourlyPoints(start, 24, func(i int) float64 { return float64(i%2) + 0.5 }),
+		"api-2":    hourlyPoints(start, 24, constant(0.5)),
+		"worker-1": hourlyPoints(start, 24, constant(3)),
+	}
+	cpu["api-2"][3].Value = math.NaN()
+	memory := map[string][]DataPoint{
+		"api-1":    hourlyPoints(start, 24, constant(512<<20)),
+		"api-2":    hourlyPoints(start, 24, constant(512<<20)),
+		"batch-1":  hourlyPoints(start, 24, constant(1<<30)),
+		"worker-1": hourlyPoints(start, 24, constant(0)),
+	}
+
+	pods := BreakdownByPod(cpu, memory, 0)
+	require.Len(t, pods, 4, "pods with only memory data are included")
+	assert.Equal(t, []string{"worker-1", "api-1", "api-2", "batch-1"}, []string{pods[0].Pod, pods[1].Pod, pods[2].Pod, pods[3].Pod})
+
+	assert.Equal(t, quantity.Cores(3), pods[0].CPUAverage)
+	assert.Equal(t, 66.67, pods[0].CPUSharePercent)
+	assert.Equal(t, quantity.Cores(1), pods[1].CPUAverage)
+	assert.Equal(t, quantity.Cores(1.5), pods[1].CPUPeak)
+	assert.Equal(t, quantity.Cores(0.5), pods[2].CPUAverage, "NaN samples are skipped")
+	assert.Equal(t, 512*quantity.MiB, pods[1].MemoryAverage)
+	assert.Equal(t, 25.0, pods[1].MemorySharePercent)
+	assert.Equal(t, 50.0, pods[3].MemorySharePercent)
+	assert.Zero(t, pods[3].CPUAverage)
+
+	limited := BreakdownByPod(cpu, memory, 2)
+	assert.Equal(t, pods[:2], limited, "shares cover every pod even when the list is limited")
+
+	assert.Empty(t, BreakdownByPod(nil, nil, 0))
+}
@@ -0,0 +1,319 @@
+package capacity
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Right-sizing defaults. Suggested requests cover p95 usage plus RequestMargin and
+// suggested limits cover p99 usage plus LimitMargin.
+const (
+	DefaultRequestMargin = 0.15
+	DefaultLimitMargin   = 0.30
+
+	// MinCPURequestCores and MinMemoryRequestBytes floor suggestions for idle containers
+	MinCPURequestCores    = 0.01
+	MinMemoryRequestBytes = 32 * 1024 * 1024
+
+	// rightsizingTolerance is the relative change below which a request is kept
+	rightsizingTolerance = 0.10
+)
+
+// Right-sizing actions for a container resource
+const (
+	SizingActionIncrease = "increase"
+	SizingActionDecrease = "decrease"
+	SizingActionKeep     = "keep"
+	SizingActionSet      = "set"     // no request is set today
+	SizingActionNoData   = "no_data" // no usage was observed in the window
+)
+
+// Percentiles holds usage percentiles over a window
+type Percentiles struct {
+	P50 float64 `json:"p50"`
+	P95 float64 `json:"p95"`
+	P99 float64 `json:"p99"`
+}
+
+// max returns the element-wise maximum of two percentile sets
+func (p Percentiles) max(other Percentiles) Percentiles {
+	return Percentiles{
+		P50: math.Max(p.P50, other.P50),
+		P95: math.Max(p.P95, other.P95),
+		P99: math.Max(p.P99, other.P99),
+	}
+}
+
+// WorkloadContainer is a container of a Deployment, StatefulSet or DaemonSet with its
+// configured requests and limits (CPU in cores, memory in bytes; zero when unset)
+type WorkloadContainer struct {
+	Kind          string
+	Name          string
+	Namespace     string
+	Container     string
+	CPURequest    float64
+	CPULimit      float64
+	MemoryRequest int64
+	MemoryLimit   int64
+}
+
+// ContainerUsage is the observed usage of one pod container (CPU in cores, memory in bytes)
+type ContainerUsage struct {
+	Pod       string
+	Container string
+	CPU       Percentiles
+	Memory    Percentiles
+}
+
+// RightsizingMargins are the safety margins added to observed usage, as fractions
+type RightsizingMargins struct {
+	Request float64 `json:"request"`
+	Limit   float64 `json:"limit"`
+}
+
+// ResourceSizing compares a container resource's usage with its configuration. Usage
+// percentiles are in cores for CPU and bytes for memory; requests and limits are
+// Kubernetes quantities.
+type ResourceSizing struct {
+	Usage            Percentiles `json:"usage"`
+	Request          string      `json:"request,omitempty"`
+	Limit            string      `json:"limit,omitempty"`
+	SuggestedRequest string      `json:"suggested_request,omitempty"`
+	SuggestedLimit   string      `json:"suggested_limit,omitempty"`
+	Action           string      `json:"action"`
+}
+
+// ContainerSizing is the right-sizing recommendation for one workload container
+type ContainerSizing struct {
+	Kind      string         `json:"kind"`
+	Name      string         `json:"name"`
+	Namespace string         `json:"namespace"`
+	Container string         `json:"container"`
+	Pods      int            `json:"pods"`
+	CPU       ResourceSizing `json:"cpu"`
+	Memory    ResourceSizing `json:"memory"`
+}
+
+// HasSuggestion reports whether usage was observed and new values were computed
+func (c *ContainerSizing) HasSuggestion() bool {
+	return c.CPU.Action != SizingActionNoData
+}
+
+// ListWorkloadContainers returns the containers of the Deployments, StatefulSets and
+// DaemonSets in a namespace, optionally only those of the workload called name
+func (a *Analyzer) ListWorkloadContainers(ctx context.Context, namespace, name string) ([]WorkloadContainer, error) {
+	var containers []WorkloadContainer
+	add := func(kind, workload string, spec *corev1.PodSpec) {
+		if name != "" && workload != name {
+			return
+		}
+		for i := range spec.Containers {
+			c := &spec.Containers[i]
+			containers = append(containers, WorkloadContainer{
+				Kind:          kind,
+				Name:          workload,
+				Namespace:     namespace,
+				Container:     c.Name,
+				CPURequest:    float64(c.Resources.Requests.Cpu().MilliValue()) / 1000.0,
+				CPULimit:      float64(c.Resources.Limits.Cpu().MilliValue()) / 1000.0,
+				MemoryRequest: c.Resources.Requests.Memory().Value(),
+				MemoryLimit:   c.Resources.Limits.Memory().Value(),
+			})
+		}
+	}
+
+	deployments, err := a.k8sClient.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deployments: %w", err)
+	}
+	for i := range deployments.Items {
+		add("Deployment", deployments.Items[i].Name, &deployments.Items[i].Spec.Template.Spec)
+	}
+
+	statefulSets, err := a.k8sClient.AppsV1().StatefulSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list statefulsets: %w", err)
+	}
+	for i := range statefulSets.Items {
+		add("StatefulSet", statefulSets.Items[i].Name, &statefulSets.Items[i].Spec.Template.Spec)
+	}
+
+	daemonSets, err := a.k8sClient.AppsV1().DaemonSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list daemonsets: %w", err)
+	}
+	for i := range daemonSets.Items {
+		add("DaemonSet", daemonSets.Items[i].Name, &daemonSets.Items[i].Spec.Template.Spec)
+	}
+
+	return containers, nil
+}
+
+// ownsPod reports whether a pod name follows the naming of a workload's pods:
+// <deployment>-<hash>-<suffix>, <statefulset>-<ordinal> or <daemonset>-<suffix>
+func ownsPod(kind, workload, pod string) bool {
+	rest, ok := strings.CutPrefix(pod, workload+"-")
+	if !ok || rest == "" {
+		return false
+	}
+	switch kind {
+	case "Deployment":
+		return strings.Count(rest, "-") == 1
+	case "StatefulSet":
+		return strings.Trim(rest, "0123456789") == ""
+	case "DaemonSet":
+		return !strings.Contains(rest, "-")
+	}
+	return false
+}
+
+// RecommendSizing matches observed pod usage to workload containers and suggests new
+// requests and limits. Usage of all pods of a workload is combined by taking the
+// highest percentiles, so suggestions fit the busiest replica.
+func RecommendSizing(workloads []WorkloadContainer, usage []ContainerUsage, margins RightsizingMargins) []ContainerSizing {
+	type observed struct {
+		pods   map[string]bool
+		cpu    Percentiles
+		memory Percentiles
+	}
+	byContainer := make(map[int]*observed)
+
+	for _, u := range usage {
+		// The longest matching workload name wins, so "api-gateway-x2x7k" belongs to
+		// DaemonSet api-gateway rather than Deployment api
+		owner := -1
+		for i := range workloads {
+			w := &workloads[i]
+			if w.Container != u.Container || !ownsPod(w.Kind, w.Name, u.Pod) {
+				continue
+			}
+			if owner < 0 || len(w.Name) > len(workloads[owner].Name) {
+				owner = i
+			}
+		}
+		if owner < 0 {
+			continue
+		}
+
+		o := byContainer[owner]
+		if o == nil {
+			o = &observed{pods: make(map[string]bool)}
+			byContainer[owner] = o
+		}
+		o.pods[u.Pod] = true
+		o.cpu = o.cpu.max(u.CPU)
+		o.memory = o.memory.max(u.Memory)
+	}
+
+	results := make([]ContainerSizing, 0, len(workloads))
+	for i := range workloads {
+		w := &workloads[i]
+		sizing := ContainerSizing{
+			Kind:      w.Kind,
+			Name:      w.Name,
+			Namespace: w.Namespace,
+			Container: w.Container,
+			CPU: ResourceSizing{
+				Request: formatCPUQuantity(w.CPURequest),
+				Limit:   formatCPUQuantity(w.CPULimit),
+				Action:  SizingActionNoData,
+			},
+			Memory: ResourceSizing{
+				Request: formatMemoryQuantity(w.MemoryRequest),
+				Limit:   formatMemoryQuantity(w.MemoryLimit),
+				Action:  SizingActionNoData,
+			},
+		}
+
+		if o := byContainer[i]; o != nil {
+			sizing.Pods = len(o.pods)
+			sizing.CPU = suggestCPU(sizing.CPU, o.cpu, w.CPURequest, margins)
+			sizing.Memory = suggestMemory(sizing.Memory, o.memory, w.MemoryRequest, margins)
+		}
+		results = append(results, sizing)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Kind != results[j].Kind {
+			return results[i].Kind < results[j].Kind
+		}
+		if results[i].Name != results[j].Name {
+			return results[i].Name < results[j].Name
+		}
+		return results[i].Container < results[j].Container
+	})
+	return results
+}
+
+// suggestCPU fills in the CPU suggestion, rounded up to 5 millicores
+func suggestCPU(sizing ResourceSizing, usage Percentiles, current float64, margins RightsizingMargins) ResourceSizing {
+	request := math.Max(usage.P95*(1+margins.Request), MinCPURequestCores)
+	request = math.Ceil(request*200) / 200
+	limit := math.Max(math.Ceil(usage.P99*(1+margins.Limit)*200)/200, request)
+
+	sizing.Usage = roundPercentiles(usage, 3)
+	sizing.SuggestedRequest = formatCPUQuantity(request)
+	sizing.SuggestedLimit = formatCPUQuantity(limit)
+	sizing.Action = sizingAction(current, request)
+	return sizing
+}
+
+// suggestMemory fills in the memory suggestion, rounded up to whole MiB
+func suggestMemory(sizing ResourceSizing, usage Percentiles, current int64, margins RightsizingMargins) ResourceSizing {
+	const mib = 1024 * 1024
+	request := math.Max(usage.P95*(1+margins.Request), MinMemoryRequestBytes)
+	request = math.Ceil(request/mib) * mib
+	limit := math.Max(math.Ceil(usage.P99*(1+margins.Limit)/mib)*mib, request)
+
+	sizing.Usage = roundPercentiles(usage, 0)
+	sizing.SuggestedRequest = formatMemoryQuantity(int64(request))
+	sizing.SuggestedLimit = formatMemoryQuantity(int64(limit))
+	sizing.Action = sizingAction(float64(current), request)
+	return sizing
+}
+
+// sizingAction classifies the change from the current to the suggested request
+func sizingAction(current, suggested float64) string {
+	switch {
+	case current <= 0:
+		return SizingActionSet
+	case math.Abs(suggested-current)/current <= rightsizingTolerance:
+		return SizingActionKeep
+	case suggested > current:
+		return SizingActionIncrease
+	default:
+		return SizingActionDecrease
+	}
+}
+
+// roundPercentiles rounds percentiles to the given number of decimals
+func roundPercentiles(p Percentiles, decimals int) Percentiles {
+	return Percentiles{
+		P50: roundTo(p.P50, decimals),
+		P95: roundTo(p.P95, decimals),
+		P99: roundTo(p.P99, decimals),
+	}
+}
+
+// formatCPUQuantity formats cores as a Kubernetes quantity ("250m", "2"); zero is empty
+func formatCPUQuantity(cores float64) string {
+	if cores <= 0 {
+		return ""
+	}
+	return resource.NewMilliQuantity(int64(math.Round(cores*1000)), resource.DecimalSI).String()
+}
+
+// formatMemoryQuantity formats bytes as a Kubernetes quantity ("256Mi"); zero is empty
+func formatMemoryQuantity(bytes int64) string {
+	if bytes <= 0 {
+		return ""
+	}
+	return resource.NewQuantity(bytes, resource.BinarySI).String()
+}
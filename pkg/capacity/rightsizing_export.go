@@ -0,0 +1,115 @@
+package capacity
+
+import (
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// VPAUpdateMode is the update mode of exported VerticalPodAutoscalers. "Initial" applies
+// the bounds when pods are created without evicting running pods.
+const VPAUpdateMode = "Initial"
+
+// workloadSizing groups the container suggestions of one workload
+type workloadSizing struct {
+	kind, name, namespace string
+	containers            []ContainerSizing
+}
+
+// groupByWorkload groups containers with suggestions by workload, keeping their order
+func groupByWorkload(sizings []ContainerSizing) []workloadSizing {
+	var groups []workloadSizing
+	index := make(map[string]int)
+	for _, sizing := range sizings {
+		if !sizing.HasSuggestion() {
+			continue
+		}
+		key := sizing.Kind + "/" + sizing.Namespace + "/" + sizing.Name
+		i, exists := index[key]
+		if !exists {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, workloadSizing{kind: sizing.Kind, name: sizing.Name, namespace: sizing.Namespace})
+		}
+		groups[i].containers = append(groups[i].containers, sizing)
+	}
+	return groups
+}
+
+// ExportVPA renders one VerticalPodAutoscaler per workload as multi-document YAML.
+// Suggested requests become minAllowed and suggested limits maxAllowed.
+func ExportVPA(sizings []ContainerSizing) (string, error) {
+	var docs []string
+	for _, group := range groupByWorkload(sizings) {
+		policies := make([]map[string]interface{}, 0, len(group.containers))
+		for _, c := range group.containers {
+			policies = append(policies, map[string]interface{}{
+				"containerName":       c.Container,
+				"controlledResources": []string{"cpu", "memory"},
+				"minAllowed":          map[string]string{"cpu": c.CPU.SuggestedRequest, "memory": c.Memory.SuggestedRequest},
+				"maxAllowed":          map[string]string{"cpu": c.CPU.SuggestedLimit, "memory": c.Memory.SuggestedLimit},
+			})
+		}
+
+		vpa := map[string]interface{}{
+			"apiVersion": "autoscaling.k8s.io/v1",
+			"kind":       "VerticalPodAutoscaler",
+			"metadata": map[string]string{
+				"name":      group.name,
+				"namespace": group.namespace,
+			},
+			"spec": map[string]interface{}{
+				"targetRef": map[string]string{
+					"apiVersion": "apps/v1",
+					"kind":       group.kind,
+					"name":       group.name,
+				},
+				"updatePolicy":   map[string]string{"updateMode": VPAUpdateMode},
+				"resourcePolicy": map[string]interface{}{"containerPolicies": policies},
+			},
+		}
+
+		doc, err := yaml.Marshal(vpa)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal VPA for %s %s: %w", group.kind, group.name, err)
+		}
+		docs = append(docs, string(doc))
+	}
+	return strings.Join(docs, "---\n"), nil
+}
+
+// ExportPatches renders one strategic merge patch per workload as multi-document YAML,
+// each preceded by the kubectl command that applies it
+func ExportPatches(sizings []ContainerSizing) (string, error) {
+	var docs []string
+	for _, group := range groupByWorkload(sizings) {
+		containers := make([]map[string]interface{}, 0, len(group.containers))
+		for _, c := range group.containers {
+			containers = append(containers, map[string]interface{}{
+				"name": c.Container,
+				"resources": map[string]interface{}{
+					"requests": map[string]string{"cpu": c.CPU.SuggestedRequest, "memory": c.Memory.SuggestedRequest},
+					"limits":   map[string]string{"cpu": c.CPU.SuggestedLimit, "memory": c.Memory.SuggestedLimit},
+				},
+			})
+		}
+
+		patch := map[string]interface{}{
+			"spec": map[string]interface{}{
+				"template": map[string]interface{}{
+					"spec": map[string]interface{}{"containers": containers},
+				},
+			},
+		}
+
+		doc, err := yaml.Marshal(patch)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal patch for %s %s: %w", group.kind, group.name, err)
+		}
+		header := fmt.Sprintf("# kubectl -n %s patch %s %s --patch-file <this document>\n",
+			group.namespace, strings.ToLower(group.kind), group.name)
+		docs = append(docs, header+string(doc))
+	}
+	return strings.Join(docs, "---\n"), nil
+}
@@ -0,0 +1,116 @@
+package capacity
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const mib = 1024 * 1024
+
+func TestOwnsPod(t *testing.T) {
+	tests := []struct {
+		kind, workload, pod string
+		want                bool
+	}{
+		{"Deployment", "api", "api-7d9f5c8b4-x2x7k", true},
+		{"Deployment", "api", "api-gateway-x2x7k", true},
+		{"Deployment", "api", "api-x2x7k", false},
+		{"Deployment", "api", "apiserver-7d9f5c8b4-x2x7k", false},
+		{"StatefulSet", "db", "db-0", true},
+		{"StatefulSet", "db", "db-primary", false},
+		{"DaemonSet", "agent", "agent-x2x7k", true},
+		{"DaemonSet", "agent", "agent-7d9f5c8b4-x2x7k", false},
+		{"Job", "batch", "batch-x2x7k", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.kind+"/"+tt.pod, func(t *testing.T) {
+			assert.Equal(t, tt.want, ownsPod(tt.kind, tt.workload, tt.pod))
+		})
+	}
+}
+
+func TestRecommendSizing(t *testing.T) {
+	workloads := []WorkloadContainer{
+		{Kind: "Deployment", Name: "api", Namespace: "payments", Container: "api",
+			CPURequest: 1, CPULimit: 2, MemoryRequest: 1024 * mib, MemoryLimit: 2048 * mib},
+		{Kind: "DaemonSet", Name: "api-gateway", Namespace: "payments", Container: "api"},
+		{Kind: "StatefulSet", Name: "db", Namespace: "payments", Container: "postgres",
+			CPURequest: 0.5, MemoryRequest: 512 * mib},
+	}
+	usage := []ContainerUsage{
+		{Pod: "api-7d9f5c8b4-aaaaa", Container: "api",
+			CPU: Percentiles{P50: 0.1, P95: 0.2, P99: 0.3}, Memory: Percentiles{P50: 200 * mib, P95: 300 * mib, P99: 400 * mib}},
+		{Pod: "api-7d9f5c8b4-bbbbb", Container: "api",
+			CPU: Percentiles{P50: 0.15, P95: 0.25, P99: 0.28}, Memory: Percentiles{P50: 100 * mib, P95: 200 * mib, P99: 350 * mib}},
+		{Pod: "api-gateway-ccccc", Container: "api",
+			CPU: Percentiles{P95: 0.001}, Memory: Percentiles{P95: mib}},
+		{Pod: "orphan-ddddd", Container: "api", CPU: Percentiles{P95: 5}},
+	}
+
+	sizings := RecommendSizing(workloads, usage, RightsizingMargins{Request: DefaultRequestMargin, Limit: DefaultLimitMargin})
+	require.Len(t, sizings, 3)
+
+	gateway, api, db := sizings[0], sizings[1], sizings[2]
+
+	assert.Equal(t, "api", api.Name)
+	assert.Equal(t, 2, api.Pods, "the gateway pod belongs to the longer workload name")
+	assert.Equal(t, Percentiles{P50: 0.15, P95: 0.25, P99: 0.3}, api.CPU.Usage, "replicas are combined by maximum")
+	assert.Equal(t, "1", api.CPU.Request)
+	assert.Equal(t, "290m", api.CPU.SuggestedRequest) // 0.25 * 1.15 rounded up to 5m
+	assert.Equal(t, "390m", api.CPU.SuggestedLimit)   // 0.3 * 1.3
+	assert.Equal(t, SizingActionDecrease, api.CPU.Action)
+	assert.Equal(t, "345Mi", api.Memory.SuggestedRequest)
+	assert.Equal(t, "520Mi", api.Memory.SuggestedLimit)
+	assert.Equal(t, SizingActionDecrease, api.Memory.Action)
+
+	assert.Equal(t, "api-gateway", gateway.Name)
+	assert.Equal(t, "10m", gateway.CPU.SuggestedRequest, "idle containers get the minimum request")
+	assert.Equal(t, "32Mi", gateway.Memory.SuggestedRequest)
+	assert.Equal(t, SizingActionSet, gateway.CPU.Action)
+
+	assert.False(t, db.HasSuggestion())
+	assert.Equal(t, SizingActionNoData, db.Memory.Action)
+	assert.Equal(t, "512Mi", db.Memory.Request)
+	assert.Empty(t, db.CPU.SuggestedRequest)
+}
+
+func TestSizingAction(t *testing.T) {
+	assert.Equal(t, SizingActionSet, sizingAction(0, 1))
+	assert.Equal(t, SizingActionKeep, sizingAction(1, 1.05))
+	assert.Equal(t, SizingActionIncrease, sizingAction(1, 1.2))
+	assert.Equal(t, SizingActionDecrease, sizingAction(1, 0.5))
+}
+
+func TestExportRightsizing(t *testing.T) {
+	workloads := []WorkloadContainer{
+		{Kind: "Deployment", Name: "api", Namespace: "payments", Container: "api"},
+		{Kind: "Deployment", Name: "api", Namespace: "payments", Container: "proxy"},
+		{Kind: "StatefulSet", Name: "db", Namespace: "payments", Container: "postgres"},
+	}
+	usage := []ContainerUsage{
+		{Pod: "api-7d9f5c8b4-aaaaa", Container: "api", CPU: Percentiles{P95: 0.2, P99: 0.3}, Memory: Percentiles{P95: 300 * mib, P99: 400 * mib}},
+		{Pod: "api-7d9f5c8b4-aaaaa", Container: "proxy", CPU: Percentiles{P95: 0.01}, Memory: Percentiles{P95: 50 * mib}},
+	}
+	sizings := RecommendSizing(workloads, usage, RightsizingMargins{Request: DefaultRequestMargin, Limit: DefaultLimitMargin})
+
+	vpa, err := ExportVPA(sizings)
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(vpa, "kind: VerticalPodAutoscaler"), "workloads without data are skipped")
+	assert.Contains(t, vpa, "apiVersion: autoscaling.k8s.io/v1")
+	assert.Contains(t, vpa, "updateMode: Initial")
+	assert.Contains(t, vpa, "containerName: proxy")
+	assert.Contains(t, vpa, "    - containerName: api\n      controlledResources:\n      - cpu\n      - memory\n      maxAllowed:\n        cpu: 390m\n        memory: 520Mi\n      minAllowed:\n        cpu: 230m\n        memory: 345Mi\n")
+
+	patch, err := ExportPatches(sizings)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(patch, "# kubectl -n payments patch deployment api --patch-file <this document>\nspec:\n  template:\n"))
+	assert.Contains(t, patch, "      - name: api\n        resources:\n          limits:\n            cpu: 390m\n            memory: 520Mi\n          requests:\n            cpu: 230m\n            memory: 345Mi\n")
+	assert.NotContains(t, patch, "postgres")
+
+	empty, err := ExportPatches(nil)
+	require.NoError(t, err)
+	assert.Empty(t, empty)
+}
@@ -32,12 +32,20 @@ type TrendingInfo struct {
 	DaysUntil85Percent      int            `json:"days_until_85_percent"`
 	ProjectedExhaustionDate string         `json:"projected_exhaustion_date,omitempty"`
 	Confidence              float64        `json:"confidence"`
+	Series                  *TrendSeries   `json:"series,omitempty"`
+}
+
+// TrendSeries holds downsampled usage series for charting the trend
+type TrendSeries struct {
+	Resolution string      `json:"resolution"`
+	CPU        []DataPoint `json:"cpu,omitempty"`
+	Memory     []DataPoint `json:"memory,omitempty"`
 }
 
 // DataPoint represents a single metric data point
 type DataPoint struct {
-	Timestamp time.Time
-	Value     float64
+	Timestamp time.Time `json:"timestamp"`
+	Value     float64   `json:"value"`
 }
 
 // LinearRegression calculates the slope and intercept of a linear regression
@@ -0,0 +1,473 @@
+// Package client is a typed Go client for the coordination engine v1 REST API, so other
+// platform services and assistants can analyze anomalies, request predictions and
+// recommendations, manage incidents and decide remediation approvals without
+// hand-rolling HTTP calls:
+//
+//	c, err := client.New("https://coordination-engine:8080", client.Options{Token: token})
+//	if err != nil {
+//		return err
+//	}
+//	resp, err := c.Analyze(ctx, &v1.AnomalyAnalyzeRequest{Scope: models.Scope{Namespace: "payments"}})
+//
+// Requests are retried with exponential backoff on connection errors, 429 and 5xx
+// responses. Requests that change state (creating incidents, triggering remediation,
+// deciding approvals) are only retried on 429, since a failed attempt may still have
+// been applied. Error responses are returned as *Error.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	v1 "github.com/tosin2013/openshift-coordination-engine/pkg/api/v1"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/api/validation"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/mcp"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/runbook"
+)
+
+// Client defaults
+const (
+	DefaultTimeout        = 30 * time.Second
+	DefaultMaxAttempts    = 3
+	DefaultInitialBackoff = 500 * time.Millisecond
+	DefaultMaxBackoff     = 10 * time.Second
+	DefaultUserAgent      = "openshift-coordination-engine-client"
+)
+
+// Options configures a Client
+type Options struct {
+	// Token is an API token (cet_...) sent as a bearer token
+	Token string
+
+	// HTTPClient sends the requests; set its transport for mutual TLS. Default: an
+	// http.Client with DefaultTimeout.
+	HTTPClient *http.Client
+
+	// MaxAttempts is the total number of attempts per request, including the first
+	MaxAttempts int
+	// InitialBackoff is the wait before the first retry; it doubles for each retry
+	InitialBackoff time.Duration
+	// MaxBackoff caps the wait between retries
+	MaxBackoff time.Duration
+
+	// UserAgent identifies the calling service
+	UserAgent string
+}
+
+// Client calls the coordination engine v1 REST API. It is safe for concurrent use.
+type Client struct {
+	baseURL *url.URL
+	opts    Options
+
+	// sleep is replaceable in tests
+	sleep func(ctx context.Context, d time.Duration) bool
+}
+
+// New creates a client for the engine at baseURL, e.g. "http://localhost:8080"
+func New(baseURL string, opts Options) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid base URL %q: scheme must be http or https", baseURL)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid base URL %q: host is required", baseURL)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/")
+
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = &http.Client{Timeout: DefaultTimeout}
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = DefaultMaxAttempts
+	}
+	if opts.InitialBackoff <= 0 {
+		opts.InitialBackoff = DefaultInitialBackoff
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = DefaultMaxBackoff
+	}
+	if opts.UserAgent == "" {
+		opts.UserAgent = DefaultUserAgent
+	}
+	return &Client{baseURL: u, opts: opts, sleep: sleepContext}, nil
+}
+
+// Error is an error response of the engine
+type Error struct {
+	StatusCode int
+	Message    string
+	Details    string
+
+	// Code is the machine-readable error code, e.g. INVALID_REQUEST, when the endpoint
+	// returns one
+	Code string
+
+	// Errors lists the invalid request fields of a 400 response
+	Errors []validation.FieldError
+
+	// RequiredScope is the API token scope a 403 response asks for
+	RequiredScope string
+}
+
+// Error implements the error interface
+func (e *Error) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = http.StatusText(e.StatusCode)
+	}
+	if e.Details != "" {
+		msg = fmt.Sprintf("%s: %s", msg, e.Details)
+	}
+	return fmt.Sprintf("coordination engine returned %d: %s", e.StatusCode, msg)
+}
+
+// IsNotFound reports whether err is a 404 response
+func IsNotFound(err error) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// Analyze runs an anomaly analysis (POST /api/v1/anomalies/analyze)
+func (c *Client) Analyze(ctx context.Context, req *v1.AnomalyAnalyzeRequest) (*v1.AnomalyAnalyzeResponse, error) {
+	var resp v1.AnomalyAnalyzeResponse
+	if err := c.do(ctx, http.MethodPost, "/api/v1/anomalies/analyze", nil, req, &resp, true); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Predict requests a resource usage prediction (POST /api/v1/predict)
+func (c *Client) Predict(ctx context.Context, req *v1.PredictRequest) (*v1.PredictResponse, error) {
+	var resp v1.PredictResponse
+	if err := c.do(ctx, http.MethodPost, "/api/v1/predict", nil, req, &resp, true); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Recommend requests remediation recommendations (POST /api/v1/recommendations)
+func (c *Client) Recommend(ctx context.Context, req *v1.GetRecommendationsRequest) (*v1.GetRecommendationsResponse, error) {
+	var resp v1.GetRecommendationsResponse
+	if err := c.do(ctx, http.MethodPost, "/api/v1/recommendations", nil, req, &resp, true); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ApplyRecommendation starts a remediation workflow for a recommendation served within
+// the last hour (POST /api/v1/recommendations/{id}/apply)
+func (c *Client) ApplyRecommendation(ctx context.Context, id string, req *v1.ApplyRecommendationRequest) (*v1.RecommendationApplication, error) {
+	var resp v1.RecommendationApplication
+	if err := c.do(ctx, http.MethodPost, "/api/v1/recommendations/"+url.PathEscape(id)+"/apply", nil, req, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetRecommendationApplication returns the workflow and outcome of an applied
+// recommendation (GET /api/v1/recommendations/{id}/application)
+func (c *Client) GetRecommendationApplication(ctx context.Context, id string) (*v1.RecommendationApplication, error) {
+	var resp v1.RecommendationApplication
+	if err := c.do(ctx, http.MethodGet, "/api/v1/recommendations/"+url.PathEscape(id)+"/application", nil, nil, &resp, true); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// IncidentFilter filters ListIncidents; empty fields do not filter
+type IncidentFilter struct {
+	Namespace string
+	Severity  string
+	Status    string
+
+	// Sort is "created_at" (default) or "priority"
+	Sort string
+
+	// Tags must all be set on an incident; an empty value matches any value
+	Tags map[string]string
+}
+
+// Incident is an entry of the incident list: a stored incident (Source "manual") or a
+// remediation workflow (Source "workflow")
+type Incident struct {
+	ID                string            `json:"id"`
+	Title             string            `json:"title,omitempty"`
+	Description       string            `json:"description,omitempty"`
+	Target            string            `json:"target"`
+	Namespace         string            `json:"namespace,omitempty"`
+	Resource          string            `json:"resource,omitempty"`
+	IssueType         string            `json:"issue_type,omitempty"`
+	Severity          string            `json:"severity"`
+	Priority          int               `json:"priority,omitempty"`
+	Status            string            `json:"status"`
+	CreatedAt         string            `json:"created_at"`
+	AffectedResources []string          `json:"affected_resources,omitempty"`
+	Labels            map[string]string `json:"labels,omitempty"`
+	Tags              map[string]string `json:"tags,omitempty"`
+	Owner             *models.Owner     `json:"owner,omitempty"`
+	WorkflowID        string            `json:"workflow_id,omitempty"`
+	Summary           string            `json:"summary,omitempty"`
+	Runbooks          []runbook.Runbook `json:"runbooks,omitempty"`
+	Source            string            `json:"source"`
+}
+
+// ListIncidents lists incidents (GET /api/v1/incidents)
+func (c *Client) ListIncidents(ctx context.Context, filter IncidentFilter) ([]Incident, error) {
+	query := url.Values{}
+	setQuery(query, "namespace", filter.Namespace)
+	setQuery(query, "severity", filter.Severity)
+	setQuery(query, "status", filter.Status)
+	setQuery(query, "sort", filter.Sort)
+	keys := make([]string, 0, len(filter.Tags))
+	for key := range filter.Tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if value := filter.Tags[key]; value == "" {
+			query.Add("tag", key)
+		} else {
+			query.Add("tag", key+":"+value)
+		}
+	}
+
+	var resp struct {
+		Incidents []Incident `json:"incidents"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/api/v1/incidents", query, nil, &resp, true); err != nil {
+		return nil, err
+	}
+	return resp.Incidents, nil
+}
+
+// CreateIncident creates an incident (POST /api/v1/incidents)
+func (c *Client) CreateIncident(ctx context.Context, req *v1.CreateIncidentRequest) (*v1.CreateIncidentResponse, error) {
+	var resp v1.CreateIncidentResponse
+	if err := c.do(ctx, http.MethodPost, "/api/v1/incidents", nil, req, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// TriggerRemediation starts the remediation of an incident (POST /api/v1/remediation/trigger)
+func (c *Client) TriggerRemediation(ctx context.Context, req *v1.TriggerRemediationRequest) (*v1.TriggerRemediationResponse, error) {
+	var resp v1.TriggerRemediationResponse
+	if err := c.do(ctx, http.MethodPost, "/api/v1/remediation/trigger", nil, req, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetWorkflow returns a remediation workflow (GET /api/v1/workflows/{id})
+func (c *Client) GetWorkflow(ctx context.Context, id string) (*v1.WorkflowResponse, error) {
+	var resp v1.WorkflowResponse
+	if err := c.do(ctx, http.MethodGet, "/api/v1/workflows/"+url.PathEscape(id), nil, nil, &resp, true); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ListApprovals lists the remediation approvals requested through MCP, optionally only
+// those with a status (GET /api/v1/mcp/approvals)
+func (c *Client) ListApprovals(ctx context.Context, status mcp.ApprovalStatus) ([]mcp.Approval, error) {
+	query := url.Values{}
+	setQuery(query, "status", string(status))
+
+	var resp struct {
+		Approvals []mcp.Approval `json:"approvals"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/api/v1/mcp/approvals", query, nil, &resp, true); err != nil {
+		return nil, err
+	}
+	return resp.Approvals, nil
+}
+
+// GetApproval returns a remediation approval (GET /api/v1/mcp/approvals/{id})
+func (c *Client) GetApproval(ctx context.Context, id string) (*mcp.Approval, error) {
+	var resp mcp.Approval
+	if err := c.do(ctx, http.MethodGet, "/api/v1/mcp/approvals/"+url.PathEscape(id), nil, nil, &resp, true); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Approve approves a pending remediation (POST /api/v1/mcp/approvals/{id}/approve). The
+// client's token must be the admin token or an API token with the approve:remediation
+// scope, and is recorded as the approver.
+func (c *Client) Approve(ctx context.Context, id, comment string) (*mcp.Approval, error) {
+	return c.decide(ctx, id, "approve", comment)
+}
+
+// Reject rejects a pending remediation (POST /api/v1/mcp/approvals/{id}/reject)
+func (c *Client) Reject(ctx context.Context, id, comment string) (*mcp.Approval, error) {
+	return c.decide(ctx, id, "reject", comment)
+}
+
+// decide posts an approval decision
+func (c *Client) decide(ctx context.Context, id, decision, comment string) (*mcp.Approval, error) {
+	body := map[string]string{"comment": comment}
+	var resp mcp.Approval
+	if err := c.do(ctx, http.MethodPost, "/api/v1/mcp/approvals/"+url.PathEscape(id)+"/"+decision, nil, body, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// do sends a request and decodes the JSON response into out, retrying transient
+// failures. Requests that are not idempotent are only retried on 429.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, in, out interface{}, idempotent bool) error {
+	var body []byte
+	if in != nil {
+		var err error
+		if body, err = json.Marshal(in); err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+	}
+
+	u := *c.baseURL
+	u.Path += path
+	u.RawQuery = query.Encode()
+
+	backoff := c.opts.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		statusCode, retryAfter, err := c.attempt(ctx, method, u.String(), body, out)
+		if err == nil {
+			return nil
+		}
+		if attempt == c.opts.MaxAttempts || !retryable(statusCode, idempotent) || ctx.Err() != nil {
+			return err
+		}
+
+		wait := backoff
+		if retryAfter > 0 {
+			wait = min(retryAfter, c.opts.MaxBackoff)
+		}
+		if !c.sleep(ctx, wait) {
+			return fmt.Errorf("%w (giving up: %v)", err, ctx.Err())
+		}
+		backoff = min(backoff*2, c.opts.MaxBackoff)
+	}
+}
+
+// attempt sends a request once. It returns the response status code (0 when no response
+// was received) and the Retry-After delay of the response.
+func (c *Client) attempt(ctx context.Context, method, rawURL string, body []byte, out interface{}) (int, time.Duration, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, reader)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", c.opts.UserAgent)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.opts.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.opts.Token)
+	}
+
+	resp, err := c.opts.HTTPClient.Do(req)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%s %s failed: %w", method, req.URL.Path, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode >= 300 {
+		return resp.StatusCode, parseRetryAfter(resp.Header.Get("Retry-After")), decodeError(resp.StatusCode, data)
+	}
+	if out != nil {
+		if err := json.Unmarshal(data, out); err != nil {
+			return resp.StatusCode, 0, fmt.Errorf("failed to decode response: %w", err)
+		}
+	}
+	return resp.StatusCode, 0, nil
+}
+
+// errorBody covers the error response shapes of the v1 handlers
+type errorBody struct {
+	Error         string                  `json:"error"`
+	Message       string                  `json:"message"`
+	Details       string                  `json:"details"`
+	Code          string                  `json:"code"`
+	Errors        []validation.FieldError `json:"errors"`
+	RequiredScope string                  `json:"required_scope"`
+}
+
+// decodeError converts an error response to *Error, keeping a body that is not JSON as
+// the message
+func decodeError(statusCode int, data []byte) *Error {
+	apiErr := &Error{StatusCode: statusCode}
+	var body errorBody
+	if err := json.Unmarshal(data, &body); err != nil {
+		apiErr.Message = strings.TrimSpace(string(data))
+		return apiErr
+	}
+	apiErr.Message = body.Error
+	if apiErr.Message == "" {
+		apiErr.Message = body.Message
+	}
+	apiErr.Details = body.Details
+	apiErr.Code = body.Code
+	apiErr.Errors = body.Errors
+	apiErr.RequiredScope = body.RequiredScope
+	return apiErr
+}
+
+// retryable reports whether a failed attempt should be retried; statusCode 0 means no
+// response was received
+func retryable(statusCode int, idempotent bool) bool {
+	if statusCode == http.StatusTooManyRequests {
+		return true
+	}
+	return idempotent && (statusCode == 0 || statusCode >= 500)
+}
+
+// parseRetryAfter parses a Retry-After header in seconds, returning 0 when absent or
+// invalid
+func parseRetryAfter(value string) time.Duration {
+	seconds, err := strconv.Atoi(value)
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// setQuery sets a query parameter unless the value is empty
+func setQuery(query url.Values, key, value string) {
+	if value != "" {
+		query.Set(key, value)
+	}
+}
+
+// sleepContext waits for d, returning false if ctx is cancelled first
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,221 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1 "github.com/tosin2013/openshift-coordination-engine/pkg/api/v1"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/api/validation"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/mcp"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// newTestClient creates a client for a test server that does not wait between retries
+func newTestClient(t *testing.T, handler http.Handler, opts Options) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	c, err := New(server.URL+"/", opts)
+	require.NoError(t, err)
+	c.sleep = func(context.Context, time.Duration) bool { return true }
+	return c
+}
+
+func TestClient_Handlers(t *testing.T) {
+	t.Setenv("DATA_DIR", t.TempDir())
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	router := mux.NewRouter()
+	remediations := v1.NewRemediationHandler(nil, log)
+	router.HandleFunc("/api/v1/incidents", remediations.CreateIncident).Methods("POST")
+	recommendations := v1.NewRecommendationsHandler(nil, remediations.GetIncidentStore(), nil, log)
+	router.HandleFunc("/api/v1/recommendations", recommendations.GetRecommendations).Methods("POST")
+	approvals := mcp.NewApprovalStore(time.Hour, log)
+	approvals.SetAdminToken("admin-secret")
+	approvals.RegisterRoutes(router)
+
+	c := newTestClient(t, router, Options{})
+	ctx := context.Background()
+
+	t.Run("incidents and recommendations", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			created, err := c.CreateIncident(ctx, &v1.CreateIncidentRequest{
+				Title: "Memory pressure", Description: "OOM kills", Severity: "high", Target: "payments",
+			})
+			require.NoError(t, err)
+			assert.NotEmpty(t, created.IncidentID)
+		}
+
+		resp, err := c.Recommend(ctx, &v1.GetRecommendationsRequest{ConfidenceThreshold: 0.1, Scope: models.Scope{Namespace: "payments"}})
+		require.NoError(t, err)
+		require.NotEmpty(t, resp.Recommendations)
+		assert.Equal(t, "payments", resp.Recommendations[0].Namespace)
+	})
+
+	t.Run("field errors", func(t *testing.T) {
+		_, err := c.Recommend(ctx, &v1.GetRecommendationsRequest{Timeframe: "2d"})
+		var apiErr *Error
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+		require.Len(t, apiErr.Errors, 1)
+		assert.Equal(t, "timeframe", apiErr.Errors[0].Field)
+		assert.Equal(t, validation.ConstraintEnum, apiErr.Errors[0].Constraint)
+	})
+
+	t.Run("approvals", func(t *testing.T) {
+		requested := approvals.Request(&v1.TriggerRemediationRequest{IncidentID: "inc-1", Namespace: "payments"}, "restart", "token:tok-assistant")
+
+		pending, err := c.ListApprovals(ctx, mcp.ApprovalPending)
+		require.NoError(t, err)
+		require.Len(t, pending, 1)
+		assert.Equal(t, requested.ID, pending[0].ID)
+
+		_, err = c.Approve(ctx, requested.ID, "ok")
+		var apiErr *Error
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusUnauthorized, apiErr.StatusCode)
+
+		admin := newTestClient(t, router, Options{Token: "admin-secret"})
+		approved, err := admin.Approve(ctx, requested.ID, "ok")
+		require.NoError(t, err)
+		assert.Equal(t, mcp.ApprovalApproved, approved.Status)
+
+		got, err := c.GetApproval(ctx, requested.ID)
+		require.NoError(t, err)
+		assert.Equal(t, mcp.IdentityAdmin, got.DecidedBy)
+
+		_, err = admin.Reject(ctx, requested.ID, "")
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusConflict, apiErr.StatusCode)
+
+		_, err = c.GetApproval(ctx, "apr-missing")
+		assert.True(t, IsNotFound(err))
+	})
+}
+
+func TestClient_Requests(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("token and query parameters", func(t *testing.T) {
+		c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "Bearer cet_test", r.Header.Get("Authorization"))
+			assert.Equal(t, DefaultUserAgent, r.Header.Get("User-Agent"))
+			assert.Equal(t, "/api/v1/incidents", r.URL.Path)
+			assert.Equal(t, "namespace=payments&sort=priority&tag=cost_center&tag=team%3Apayments", r.URL.RawQuery)
+			_, _ = w.Write([]byte(`{"incidents": [{"id": "inc-1", "priority": 80, "tags": {"team": "payments"}, "source": "manual"}], "total": 1}`))
+		}), Options{Token: "cet_test"})
+
+		incidents, err := c.ListIncidents(ctx, IncidentFilter{Namespace: "payments", Sort: "priority",
+			Tags: map[string]string{"team": "payments", "cost_center": ""}})
+		require.NoError(t, err)
+		require.Len(t, incidents, 1)
+		assert.Equal(t, 80, incidents[0].Priority)
+		assert.Equal(t, map[string]string{"team": "payments"}, incidents[0].Tags)
+	})
+
+	t.Run("apply recommendation", func(t *testing.T) {
+		c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "/api/v1/recommendations/rec-hist-001/apply", r.URL.Path)
+			var req v1.ApplyRecommendationRequest
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			assert.Equal(t, "alice", req.AppliedBy)
+			w.WriteHeader(http.StatusAccepted)
+			_, _ = w.Write([]byte(`{"recommendation_id": "rec-hist-001", "workflow_id": "wf-1", "status": "in_progress"}`))
+		}), Options{})
+
+		application, err := c.ApplyRecommendation(ctx, "rec-hist-001", &v1.ApplyRecommendationRequest{AppliedBy: "alice"})
+		require.NoError(t, err)
+		assert.Equal(t, "wf-1", application.WorkflowID)
+	})
+
+	t.Run("idempotent requests are retried", func(t *testing.T) {
+		var calls atomic.Int32
+		c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			if calls.Add(1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				_, _ = w.Write([]byte(`{"status": "error", "error": "KServe unavailable", "code": "KSERVE_UNAVAILABLE"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"status": "success", "scope": "cluster"}`))
+		}), Options{})
+
+		resp, err := c.Predict(ctx, &v1.PredictRequest{Hour: 9})
+		require.NoError(t, err)
+		assert.Equal(t, "cluster", resp.Scope)
+		assert.Equal(t, int32(3), calls.Load())
+	})
+
+	t.Run("retries give up after max attempts", func(t *testing.T) {
+		var calls atomic.Int32
+		c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"status": "error", "error": "KServe unavailable", "code": "KSERVE_UNAVAILABLE"}`))
+		}), Options{MaxAttempts: 2})
+
+		_, err := c.Analyze(ctx, &v1.AnomalyAnalyzeRequest{})
+		var apiErr *Error
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, "KSERVE_UNAVAILABLE", apiErr.Code)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("state changes are only retried when rate limited", func(t *testing.T) {
+		var calls atomic.Int32
+		c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			switch calls.Add(1) {
+			case 1:
+				w.Header().Set("Retry-After", "1")
+				w.WriteHeader(http.StatusTooManyRequests)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+				_, _ = w.Write([]byte("upstream failure"))
+			}
+		}), Options{})
+
+		_, err := c.TriggerRemediation(ctx, &v1.TriggerRemediationRequest{IncidentID: "inc-1"})
+		var apiErr *Error
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusInternalServerError, apiErr.StatusCode)
+		assert.Equal(t, "upstream failure", apiErr.Message)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("cancelled context stops retrying", func(t *testing.T) {
+		c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}), Options{})
+		c.sleep = sleepContext
+
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+		_, err := c.GetWorkflow(ctx, "wf-1")
+		assert.True(t, errors.Is(err, context.Canceled))
+	})
+}
+
+func TestNew(t *testing.T) {
+	for _, baseURL := range []string{"", "localhost:8080", "ftp://engine", "http://"} {
+		_, err := New(baseURL, Options{})
+		assert.Error(t, err, baseURL)
+	}
+
+	c, err := New("https://engine.example.com/prefix/", Options{})
+	require.NoError(t, err)
+	assert.Equal(t, "/prefix", c.baseURL.Path)
+	assert.Equal(t, DefaultMaxAttempts, c.opts.MaxAttempts)
+}
@@ -4,6 +4,8 @@ package config
 import (
 	"fmt"
 	"os"
+	"path"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -16,6 +18,17 @@ type Config struct {
 	MetricsPort int    `json:"metrics_port"`
 	LogLevel    string `json:"log_level"`
 
+	// GRPCPort serves the gRPC API for machine clients (0 disables it)
+	GRPCPort int `json:"grpc_port"`
+
+	// ObserverMode keeps every remediation capability loaded but disabled: the engine
+	// detects and plans, and refuses remediation requests and cluster writes
+	ObserverMode bool `json:"observer_mode"`
+
+	// TenantsEnabled enables the tenant onboarding API. Scheduled scans then only
+	// cover namespaces onboarded by a tenant.
+	TenantsEnabled bool `json:"tenants_enabled"`
+
 	// Kubernetes configuration
 	Kubeconfig string `json:"kubeconfig,omitempty"`
 	Namespace  string `json:"namespace"`
@@ -27,12 +40,158 @@ type Config struct {
 	// Prometheus configuration for metrics querying
 	PrometheusURL string `json:"prometheus_url,omitempty"` // URL for Prometheus API queries
 
+	// PrometheusCAFile is a PEM CA bundle used to verify the Prometheus certificate
+	// (empty skips verification, which the security audit flags)
+	PrometheusCAFile string `json:"prometheus_ca_file,omitempty"`
+
+	// PrometheusExtraLabels are constant label matchers (e.g. cluster=prod-east) added to
+	// every PromQL query, for federated/central Prometheus holding many clusters
+	PrometheusExtraLabels map[string]string `json:"prometheus_extra_labels,omitempty"`
+
+	// PrometheusUseRecordingRules queries pre-computed anomaly feature series
+	// (see GET /api/v1/anomalies/recording-rules) instead of raw metrics
+	PrometheusUseRecordingRules bool `json:"prometheus_use_recording_rules"`
+
+	// PrometheusMaxQuerySeries refuses generated queries estimated to touch more
+	// series than this (0 disables the guardrail)
+	PrometheusMaxQuerySeries int `json:"prometheus_max_query_series"`
+
+	// PrometheusRangeMaxPoints downsamples range query results, e.g. 30-day trends, to
+	// at most this many points per series while they are parsed (0 keeps all points)
+	PrometheusRangeMaxPoints int `json:"prometheus_range_max_points"`
+
+	// PrometheusCapabilityProbeInterval is how often the engine re-checks which metric
+	// exporters Prometheus has data of (0 probes only at startup)
+	PrometheusCapabilityProbeInterval time.Duration `json:"prometheus_capability_probe_interval"`
+
 	// KServe Integration (ADR-039)
 	KServe KServeConfig `json:"kserve"`
 
+	// Anomaly noise reduction
+	Anomaly AnomalyConfig `json:"anomaly"`
+
+	// MCP server for AI assistant integration
+	MCP MCPConfig `json:"mcp"`
+
+	// LLM incident summaries
+	Summarizer SummarizerConfig `json:"summarizer"`
+
+	// Grafana annotations and Kubernetes Events of engine actions
+	Annotations AnnotationsConfig `json:"annotations"`
+
+	// Runbook registry and wiki search
+	Runbook RunbookConfig `json:"runbook"`
+
+	// Outbound webhook notifications
+	Webhook WebhookConfig `json:"webhook"`
+
+	// Email alerts and digests
+	Email EmailConfig `json:"email"`
+
+	// Alertmanager webhook ingestion
+	AlertIngest AlertIngestConfig `json:"alert_ingest"`
+
+	// Incident tags from policy rules and alerts
+	IncidentTags IncidentTagsConfig `json:"incident_tags"`
+
+	// Team ownership from namespace and workload annotations
+	Ownership OwnershipConfig `json:"ownership"`
+
+	// Weighting of the composite cluster health score
+	HealthScore HealthScoreConfig `json:"health_score"`
+
+	// Prediction accuracy tracking
+	PredictionTracking PredictionTrackingConfig `json:"prediction_tracking"`
+
+	// Conservative mode while the cluster is upgrading
+	Upgrade UpgradeConfig `json:"upgrade"`
+
+	// In-cluster DNS and TLS certificate expiry checks
+	Platform PlatformConfig `json:"platform"`
+
+	// Recommendations for sustained scheduler queue growth
+	SchedulerPressure SchedulerPressureConfig `json:"scheduler_pressure"`
+
+	// Critical vulnerabilities of running images from a Quay registry
+	ImageScan ImageScanConfig `json:"image_scan"`
+
+	// Similar past incident lookup
+	Similarity SimilarityConfig `json:"similarity"`
+
+	// Ranking of recommended actions by verified outcomes
+	ActionRanking ActionRankingConfig `json:"action_ranking"`
+
+	// Operator-defined rules adding actions to recommendations
+	RecommendationRules RecommendationRulesConfig `json:"recommendation_rules"`
+
+	// Persisted inputs and model output of the analyses incidents are raised from
+	Artifacts ArtifactsConfig `json:"artifacts"`
+
+	// Severity and priority of anomalies and incidents
+	Severity SeverityConfig `json:"severity"`
+
+	// Admin API (backup, restore and purge)
+	Admin AdminConfig `json:"admin"`
+
+	// Scoped API tokens for integrations
+	APITokens APITokensConfig `json:"api_tokens"`
+
+	// Runtime configuration objects managed through the admin API
+	RuntimeConfig RuntimeConfigConfig `json:"runtime_config"`
+
+	// Remediation workflow step retries and verification
+	Workflow WorkflowConfig `json:"workflow"`
+
+	// External workflow engine running remediation playbooks
+	Execution ExecutionConfig `json:"execution"`
+
+	// Per-namespace remediation budgets and per-resource cooldowns
+	Budget BudgetConfig `json:"budget"`
+
+	// Data retention per data type
+	Retention RetentionConfig `json:"retention"`
+
+	// Encryption of stored incident payloads
+	Encryption EncryptionConfig `json:"encryption"`
+
+	// Credential redaction in stored and notified text
+	Redaction RedactionConfig `json:"redaction"`
+
+	// Outbound TLS audit
+	Security SecurityConfig `json:"security"`
+
+	// TLS and mutual TLS for the HTTP API server
+	ServerTLS ServerTLSConfig `json:"server_tls"`
+
+	// Load shedding under engine resource pressure
+	LoadShed LoadShedConfig `json:"load_shed"`
+
+	// Anomaly detection over the engine's own metrics
+	SelfMonitor SelfMonitorConfig `json:"self_monitor"`
+
+	// Latency objective of analysis requests
+	AnalysisSLO AnalysisSLOConfig `json:"analysis_slo"`
+
+	// How startup treats unavailable dependencies
+	Startup StartupConfig `json:"startup"`
+
+	// Named, scoped Prometheus queries for the console plugin
+	MetricsQuery MetricsQueryConfig `json:"metrics_query"`
+
+	// Feature flags gating capabilities per environment and namespace
+	FeatureFlags FeatureFlagsConfig `json:"feature_flags"`
+
+	// Fixed clock and seeded random sources for integration tests and replays
+	Deterministic DeterministicConfig `json:"deterministic"`
+
 	// HTTP client configuration
 	HTTPTimeout time.Duration `json:"http_timeout"`
 
+	// Server-side timeouts of API requests: AnalysisRequestTimeout applies to analysis,
+	// prediction, capacity and batch endpoints, RequestTimeout to the rest (0 disables)
+	RequestTimeout         time.Duration `json:"request_timeout"`
+	AnalysisRequestTimeout time.Duration `json:"analysis_request_timeout"`
+
 	// Feature flags
 	EnableCORS      bool     `json:"enable_cors"`
 	CORSAllowOrigin []string `json:"cors_allow_origin,omitempty"`
@@ -64,6 +223,57 @@ type KServeConfig struct {
 
 	// Timeout for KServe API calls
 	Timeout time.Duration `json:"timeout"`
+
+	// RolloutFile is a YAML or JSON file pinning models to versions and splitting canary traffic (empty disables)
+	RolloutFile string `json:"rollout_file,omitempty"`
+
+	// ModelsFile is a YAML or JSON file of extra models, typically a mounted ConfigMap;
+	// models are refreshed when it changes and on SIGHUP (empty disables)
+	ModelsFile string `json:"models_file,omitempty"`
+
+	// PreflightEnabled sends a synthetic feature vector to each model at startup to verify its input shape
+	PreflightEnabled bool `json:"preflight_enabled"`
+
+	// AdaptiveTimeout derives per-model timeouts from observed latency instead of Timeout
+	AdaptiveTimeout KServeAdaptiveTimeoutConfig `json:"adaptive_timeout"`
+
+	// Infer serves POST /api/v1/models/{model}/infer, a raw inference passthrough for custom models
+	Infer KServeInferConfig `json:"infer"`
+}
+
+// KServeInferConfig holds settings for the raw inference gateway. Callers authenticate
+// with a client certificate when mutual TLS is enabled, or with Token.
+type KServeInferConfig struct {
+	// Enabled serves the gateway
+	Enabled bool `json:"enabled"`
+
+	// Token is a bearer token accepted from callers without a client certificate
+	Token string `json:"-"`
+
+	// RateLimit is the sustained number of requests per second per caller and model
+	RateLimit float64 `json:"rate_limit"`
+
+	// Burst is the number of requests a caller may send at once per model
+	Burst int `json:"burst"`
+
+	// MaxBodyBytes bounds the request body
+	MaxBodyBytes int `json:"max_body_bytes"`
+}
+
+// KServeAdaptiveTimeoutConfig derives each model's request timeout from its rolling
+// p95 latency times Factor, bounded by Min and Max
+type KServeAdaptiveTimeoutConfig struct {
+	// Enabled turns adaptive timeouts on; until a model has enough requests Timeout applies
+	Enabled bool `json:"enabled"`
+
+	// Factor multiplies the p95 latency
+	Factor float64 `json:"factor"`
+
+	// Min is the shortest timeout
+	Min time.Duration `json:"min"`
+
+	// Max is the longest timeout
+	Max time.Duration `json:"max"`
 }
 
 // KServeServices holds the names of KServe InferenceServices (legacy, for backward compatibility)
@@ -75,6 +285,767 @@ type KServeServices struct {
 	PredictiveAnalytics string `json:"predictive_analytics"`
 }
 
+// AnomalyConfig holds settings that control when anomalies become incidents
+type AnomalyConfig struct {
+	// PersistenceEvaluations is the number of consecutive evaluations an anomaly score
+	// must stay above threshold before an incident is created ("for" semantics).
+	// 0 or 1 creates an incident on the first evaluation above threshold.
+	PersistenceEvaluations int `json:"persistence_evaluations"`
+
+	// PersistenceOverrides maps scopes ("namespace", "namespace/deployment") to a
+	// scope-specific evaluation count. Parsed from ANOMALY_PERSISTENCE_OVERRIDES,
+	// e.g. "production=5,production/api=2"
+	PersistenceOverrides map[string]int `json:"persistence_overrides,omitempty"`
+
+	// ClearThreshold is the score below which an evaluation counts towards auto-resolution.
+	// It should be lower than the trigger threshold to provide hysteresis.
+	ClearThreshold float64 `json:"clear_threshold"`
+
+	// ClearEvaluations is the number of consecutive clear evaluations before an
+	// incident is resolved automatically
+	ClearEvaluations int `json:"clear_evaluations"`
+
+	// ScopeDefaultsFile is a YAML or JSON file mapping namespace patterns and
+	// criticality tiers to the model and threshold used when an analysis request
+	// omits them (empty uses anomaly-detector and 0.7 everywhere)
+	ScopeDefaultsFile string `json:"scope_defaults_file,omitempty"`
+
+	// CalendarsFile is a YAML or JSON file of per-namespace business-cycle calendars
+	// (trading hours, batch windows, month-end) during which higher load is expected
+	CalendarsFile string `json:"calendars_file,omitempty"`
+
+	// PostFiltersFile is a YAML or JSON file of filters applied in order to detected
+	// anomalies, e.g. dropping info anomalies of dev namespaces or requiring a second
+	// anomalous analysis outside business hours
+	PostFiltersFile string `json:"post_filters_file,omitempty"`
+
+	// HistogramFeatures are histogram quantile features as "name=metric:quantile", e.g.
+	// "api_latency_p99=http_request_duration_seconds:0.99", appended in order to the
+	// input of HistogramModels
+	HistogramFeatures []string `json:"histogram_features,omitempty"`
+
+	// HistogramModels are the KServe models trained with the histogram features
+	HistogramModels []string `json:"histogram_models,omitempty"`
+
+	// SubscriptionsEnabled serves /api/v1/anomalies/subscriptions, which runs scheduled
+	// scans of a scope and sends each result to a webhook
+	SubscriptionsEnabled bool `json:"subscriptions_enabled"`
+
+	// SubscriptionMinInterval is the shortest scan interval a subscription may request
+	SubscriptionMinInterval time.Duration `json:"subscription_min_interval"`
+
+	// MaxSubscriptions bounds the number of subscriptions
+	MaxSubscriptions int `json:"max_subscriptions"`
+
+	// RecordsEnabled keeps every detected anomaly in DATA_DIR/anomalies.json for
+	// GET /api/v1/anomalies, at most RecordLimit (oldest dropped first)
+	RecordsEnabled bool `json:"records_enabled"`
+	RecordLimit    int  `json:"record_limit"`
+
+	// ScanNamespaces are analyzed in the background every ScanInterval. Anomalies that
+	// persist for PersistenceEvaluations scans open an incident, which is resolved after
+	// ClearEvaluations scans below ClearThreshold. With TenantsEnabled only onboarded
+	// namespaces are scanned, every one of them if ScanNamespaces is empty; otherwise
+	// empty disables the scans.
+	ScanNamespaces []string      `json:"scan_namespaces,omitempty"`
+	ScanInterval   time.Duration `json:"scan_interval"`
+
+	// FeatureHistory keeps the recent feature vectors of onboarded scopes, sampled every
+	// FeatureHistoryResolution for FeatureHistoryRetention, so a new model version can
+	// re-score them through /api/v1/anomalies/rescore
+	FeatureHistory           bool          `json:"feature_history"`
+	FeatureHistoryRetention  time.Duration `json:"feature_history_retention"`
+	FeatureHistoryResolution time.Duration `json:"feature_history_resolution"`
+
+	// FeatureHistoryMaxScopes bounds the number of scopes with a feature history; the
+	// least recently sampled scope is dropped first
+	FeatureHistoryMaxScopes int `json:"feature_history_max_scopes"`
+
+	// ExcludeInactivePods leaves pending, terminating and completed pods out of namespace
+	// and deployment analyses, so their restarts and memory ratios do not skew features
+	ExcludeInactivePods bool `json:"exclude_inactive_pods"`
+
+	// MaxScopePods is the most running pods a scope is narrowed to; larger scopes are
+	// analyzed with pods in every phase
+	MaxScopePods int `json:"max_scope_pods"`
+
+	// RouteProbes reads blackbox-exporter probes of the Routes and Ingresses of analyzed
+	// scopes and reports routes unreachable from outside as anomalies
+	RouteProbes bool `json:"route_probes"`
+
+	// RouteProbeModels are the KServe models trained with the route probe features
+	RouteProbeModels []string `json:"route_probe_models,omitempty"`
+
+	// ScoringHooks are the names of the scoring hooks registered by the binary, run in
+	// order before and after model inference
+	ScoringHooks []string `json:"scoring_hooks,omitempty"`
+
+	// MaintenanceNamespaces are namespace name patterns whose anomalies are suppressed by
+	// the built-in maintenance scoring hook, e.g. "payments-*" (see path.Match)
+	MaintenanceNamespaces []string `json:"maintenance_namespaces,omitempty"`
+
+	// ControlPlaneModel is the KServe model trained with the control-plane feature set,
+	// used by /api/v1/anomalies/analyze/control-plane when a request names no model
+	ControlPlaneModel string `json:"control_plane_model"`
+}
+
+// MCPConfig holds settings for the Model Context Protocol server
+type MCPConfig struct {
+	// Enabled serves MCP tools at POST /mcp
+	Enabled bool `json:"enabled"`
+
+	// ApprovalTTL is how long a remediation requested through MCP waits for a human decision
+	ApprovalTTL time.Duration `json:"approval_ttl"`
+}
+
+// SummarizerConfig holds settings for LLM-generated incident summaries
+type SummarizerConfig struct {
+	// URL is the root of an OpenAI-compatible API that /chat/completions is appended to,
+	// e.g. https://api.openai.com/v1 or a vLLM KServe service's /openai/v1 (empty disables)
+	URL string `json:"url,omitempty"`
+
+	// Model is the model name sent with each request
+	Model string `json:"model,omitempty"`
+
+	// APIKey is sent as a bearer token when set
+	APIKey string `json:"-"`
+
+	// Timeout bounds a single summary request
+	Timeout time.Duration `json:"timeout"`
+
+	// MaxTokens bounds the length of a generated summary
+	MaxTokens int `json:"max_tokens"`
+}
+
+// Enabled returns true if an LLM endpoint is configured
+func (s *SummarizerConfig) Enabled() bool {
+	return s.URL != ""
+}
+
+// AnnotationsConfig holds settings for publishing incidents and remediations as
+// Grafana annotations and Kubernetes Events
+type AnnotationsConfig struct {
+	// GrafanaURL is the root of the Grafana instance annotations are posted to (empty disables)
+	GrafanaURL string `json:"grafana_url,omitempty"`
+
+	// GrafanaToken is a service account token with the annotations:write permission
+	GrafanaToken string `json:"-"`
+
+	// GrafanaDashboardUID limits annotations to one dashboard (empty creates
+	// organization annotations, queried by tag)
+	GrafanaDashboardUID string `json:"grafana_dashboard_uid,omitempty"`
+
+	// KubernetesEvents records Kubernetes Events on the objects incidents and
+	// remediations affected
+	KubernetesEvents bool `json:"kubernetes_events"`
+
+	// Timeout bounds publishing one annotation to one destination
+	Timeout time.Duration `json:"timeout"`
+}
+
+// Enabled returns true if annotations are published anywhere
+func (a *AnnotationsConfig) Enabled() bool {
+	return a.GrafanaURL != "" || a.KubernetesEvents
+}
+
+// RunbookConfig holds settings for runbook links and knowledge-base search
+type RunbookConfig struct {
+	// File is a YAML or JSON runbook registry (empty means no registry runbooks)
+	File string `json:"file,omitempty"`
+
+	// WikiURL is the base URL of a Confluence wiki to search (empty disables search)
+	WikiURL string `json:"wiki_url,omitempty"`
+
+	// WikiSpace optionally limits wiki search to one space key
+	WikiSpace string `json:"wiki_space,omitempty"`
+
+	// WikiToken is sent as a bearer token when set
+	WikiToken string `json:"-"`
+}
+
+// WebhookConfig holds settings for outbound webhook notifications
+type WebhookConfig struct {
+	// File is a YAML or JSON list of webhook targets (empty disables webhooks)
+	File string `json:"file,omitempty"`
+
+	// Secret signs payloads for targets that do not set their own secret
+	Secret string `json:"-"`
+
+	// MaxAttempts is the total number of attempts per delivery, including the first
+	MaxAttempts int `json:"max_attempts"`
+
+	// InitialBackoff is the wait before the first retry; it doubles for each retry
+	InitialBackoff time.Duration `json:"initial_backoff"`
+
+	// MaxBackoff caps the wait between retries
+	MaxBackoff time.Duration `json:"max_backoff"`
+
+	// Timeout bounds a single delivery attempt
+	Timeout time.Duration `json:"timeout"`
+}
+
+// Enabled returns true if a webhook targets file is configured
+func (w *WebhookConfig) Enabled() bool {
+	return w.File != ""
+}
+
+// EmailConfig holds settings for email alerts and digests
+type EmailConfig struct {
+	// SMTPHost is the SMTP relay (empty disables email)
+	SMTPHost string `json:"smtp_host,omitempty"`
+
+	// SMTPPort is the SMTP relay port; STARTTLS is used when the server offers it
+	SMTPPort int `json:"smtp_port"`
+
+	// Username and Password enable PLAIN authentication when Username is set
+	Username string `json:"username,omitempty"`
+	Password string `json:"-"`
+
+	// From is the sender address
+	From string `json:"from,omitempty"`
+
+	// TeamsFile is a YAML or JSON list of teams, their recipients and namespaces
+	TeamsFile string `json:"teams_file,omitempty"`
+
+	// TemplateDir optionally holds alert.tmpl and digest.tmpl overriding the built-in templates
+	TemplateDir string `json:"template_dir,omitempty"`
+
+	// DigestHour is the hour of day (UTC) digests are sent
+	DigestHour int `json:"digest_hour"`
+
+	// DigestWeekday is the day weekly digests are sent (e.g. monday)
+	DigestWeekday string `json:"digest_weekday"`
+}
+
+// Enabled returns true if an SMTP relay is configured
+func (e *EmailConfig) Enabled() bool {
+	return e.SMTPHost != ""
+}
+
+// Weekday returns DigestWeekday as a time.Weekday, defaulting to Monday if it is invalid
+func (e *EmailConfig) Weekday() time.Weekday {
+	if day, ok := weekdays[strings.ToLower(e.DigestWeekday)]; ok {
+		return day
+	}
+	return time.Monday
+}
+
+// AlertIngestConfig holds deduplication and flood control settings for Alertmanager alerts
+type AlertIngestConfig struct {
+	// DedupWindow folds re-fires of an alert into its open incident if it fired within this window
+	DedupWindow time.Duration `json:"dedup_window"`
+
+	// FloodLimit is the maximum number of new alert incidents per namespace per hour;
+	// further alerts are aggregated into one flood incident (0 disables flood control)
+	FloodLimit int `json:"flood_limit"`
+}
+
+// IncidentTagsConfig holds the sources of incident tags besides the create request
+type IncidentTagsConfig struct {
+	// RulesFile is a YAML or JSON file of rules tagging incidents by namespace, severity
+	// and labels (empty disables)
+	RulesFile string `json:"rules_file"`
+
+	// AlertLabels are the Alertmanager alert labels copied to the tags of alert incidents
+	AlertLabels []string `json:"alert_labels"`
+}
+
+// OwnershipConfig holds settings for resolving the team owning incidents and
+// recommendations from annotations
+type OwnershipConfig struct {
+	// Enabled attaches owners to incidents and recommendations and routes notifications by them
+	Enabled bool `json:"enabled"`
+
+	// AnnotationPrefix prefixes the owner, owner-slack-channel and owner-email annotations
+	AnnotationPrefix string `json:"annotation_prefix"`
+}
+
+// HealthScoreConfig holds the weights of the components of the composite cluster
+// health score. Weights are relative; they are normalized to sum to 1.
+type HealthScoreConfig struct {
+	// ControlPlaneWeight weighs etcd and ClusterOperator health
+	ControlPlaneWeight float64 `json:"control_plane_weight"`
+
+	// AnomalyWeight weighs the highest score of a cluster-wide anomaly analysis
+	AnomalyWeight float64 `json:"anomaly_weight"`
+
+	// CapacityWeight weighs the quota headroom of namespaces
+	CapacityWeight float64 `json:"capacity_weight"`
+
+	// IncidentWeight weighs the open incidents by severity
+	IncidentWeight float64 `json:"incident_weight"`
+}
+
+// PredictionTrackingConfig holds settings for storing predictions and tracking their accuracy
+type PredictionTrackingConfig struct {
+	// Enabled stores every served prediction and evaluates it once its target time passes
+	Enabled bool `json:"enabled"`
+
+	// EvaluationInterval is how often predictions whose target time passed are evaluated
+	EvaluationInterval time.Duration `json:"evaluation_interval"`
+
+	// HistoryLimit is the number of stored predictions kept (oldest dropped first)
+	HistoryLimit int `json:"history_limit"`
+}
+
+// AdminConfig holds settings for the admin API
+type AdminConfig struct {
+	// Token must be sent as a bearer token to call /api/v1/admin endpoints (empty disables them)
+	Token string `json:"-"`
+
+	// BackupTimeout bounds uploading a backup to, or downloading it from, object storage
+	BackupTimeout time.Duration `json:"backup_timeout"`
+}
+
+// Enabled returns true if an admin token is configured
+func (a *AdminConfig) Enabled() bool {
+	return a.Token != ""
+}
+
+// APITokensConfig holds settings for scoped API tokens
+type APITokensConfig struct {
+	// Enabled checks the scopes of API tokens presented to the API; tokens are
+	// managed through the admin API
+	Enabled bool `json:"enabled"`
+
+	// Required rejects requests to scoped routes that present no API token
+	Required bool `json:"required"`
+}
+
+// RuntimeConfigConfig holds settings for runtime configuration objects
+type RuntimeConfigConfig struct {
+	// Enabled stores remediation policies, silences, scan schedules, notification
+	// channels and playbooks in DATA_DIR and applies them; they are managed through
+	// the admin API
+	Enabled bool `json:"enabled"`
+}
+
+// WorkflowConfig holds settings for executing remediation workflows
+type WorkflowConfig struct {
+	// MaxAttempts is how often the remediation step is attempted before the workflow
+	// fails; 0 means once
+	MaxAttempts int `json:"max_attempts"`
+
+	// RetryBackoff is the delay before the first retry; it doubles after each failure
+	RetryBackoff time.Duration `json:"retry_backoff"`
+
+	// VerifyTimeout is how long to wait for the remediated workload to finish rolling
+	// out. If it does not, Helm remediations are rolled back. 0 disables verification.
+	VerifyTimeout time.Duration `json:"verify_timeout"`
+}
+
+// ExecutionConfig holds settings for running playbooks in an external workflow engine
+type ExecutionConfig struct {
+	// Backend renders playbooks into Argo Workflows ("argo") or Tekton PipelineRuns
+	// ("tekton"); empty disables playbook execution
+	Backend string `json:"backend,omitempty"`
+
+	// Namespace is where runs are submitted; empty uses the engine's namespace
+	Namespace string `json:"namespace,omitempty"`
+
+	// ServiceAccount runs the playbook steps; empty uses the workflow engine's default
+	ServiceAccount string `json:"service_account,omitempty"`
+
+	// RunnerImage provides the oc binary the steps run
+	RunnerImage string `json:"runner_image"`
+
+	// PollInterval is how often the status of unfinished runs is read
+	PollInterval time.Duration `json:"poll_interval"`
+}
+
+// BudgetConfig holds settings for limiting automated remediation
+type BudgetConfig struct {
+	// DailyLimit is the number of automated remediations allowed per namespace in a
+	// rolling 24 hours; 0 is unlimited
+	DailyLimit int `json:"daily_limit"`
+
+	// Cooldown is the minimum time between remediations of the same resource; 0
+	// disables cooldowns
+	Cooldown time.Duration `json:"cooldown"`
+}
+
+// EncryptionConfig holds settings for encrypting stored incident payloads at rest
+type EncryptionConfig struct {
+	// KeysFile holds "<id>=<base64 AES key>" lines, primary key first, typically
+	// mounted from a Secret (empty disables encryption)
+	KeysFile string `json:"keys_file,omitempty"`
+}
+
+// Enabled returns true if an encryption keys file is configured
+func (e *EncryptionConfig) Enabled() bool {
+	return e.KeysFile != ""
+}
+
+// RedactionConfig holds settings for masking credentials before persistence or notification
+type RedactionConfig struct {
+	// Enabled masks tokens, passwords and connection strings in incidents, evidence and explanations
+	Enabled bool `json:"enabled"`
+
+	// PatternsFile holds extra regular expressions, one per line, added to the built-in patterns
+	PatternsFile string `json:"patterns_file,omitempty"`
+}
+
+// SecurityConfig holds settings for the outbound TLS configuration audit
+type SecurityConfig struct {
+	// StrictTLS refuses to start when the audit finds high severity issues, such as
+	// disabled certificate verification
+	StrictTLS bool `json:"strict_tls"`
+
+	// RequireFIPS reports running outside Go's FIPS 140-3 mode as a high severity issue
+	RequireFIPS bool `json:"require_fips"`
+}
+
+// ServerTLSConfig holds TLS and mutual TLS settings for the HTTP API server
+type ServerTLSConfig struct {
+	// CertFile and KeyFile are the PEM serving certificate and key, e.g. from the
+	// OpenShift service CA (empty serves plain HTTP)
+	CertFile string `json:"cert_file,omitempty"`
+	KeyFile  string `json:"key_file,omitempty"`
+
+	// ClientCAFile is a PEM bundle of CAs issuing client certificates; when set, every
+	// HTTP request except health probes and every gRPC connection needs a verified
+	// client certificate
+	ClientCAFile string `json:"client_ca_file,omitempty"`
+
+	// CRLFile holds PEM or DER revocation lists from the client CAs
+	CRLFile string `json:"crl_file,omitempty"`
+
+	// OCSP checks client certificates against their issuer's OCSP responder
+	OCSP bool `json:"ocsp"`
+
+	// OCSPFailOpen accepts client certificates when the OCSP responder is unavailable
+	OCSPFailOpen bool `json:"ocsp_fail_open"`
+
+	// AllowedClients limits client certificates to these DNS names or common names
+	AllowedClients []string `json:"allowed_clients,omitempty"`
+}
+
+// Enabled returns true if the API server serves TLS
+func (t *ServerTLSConfig) Enabled() bool {
+	return t.CertFile != ""
+}
+
+// MutualTLS returns true if client certificates are required
+func (t *ServerTLSConfig) MutualTLS() bool {
+	return t.ClientCAFile != ""
+}
+
+// LoadShedConfig holds the thresholds at which the engine sheds requests to protect
+// itself: expensive endpoints first, then everything except health probes and incident reads
+type LoadShedConfig struct {
+	// Enabled samples the engine's CPU, memory and goroutines and sheds load under pressure
+	Enabled bool `json:"enabled"`
+
+	// Interval is how often resources are sampled
+	Interval time.Duration `json:"interval"`
+
+	// CPUThreshold is the share of the engine's CPUs in use at which load is shed (0-1]
+	CPUThreshold float64 `json:"cpu_threshold"`
+
+	// MemoryThreshold is the share of the memory limit (GOMEMLIMIT or the container
+	// limit) in use at which load is shed (0-1]
+	MemoryThreshold float64 `json:"memory_threshold"`
+
+	// GoroutineThreshold is the goroutine count at which load is shed
+	GoroutineThreshold int `json:"goroutine_threshold"`
+
+	// RetryAfter is sent to clients whose requests are shed
+	RetryAfter time.Duration `json:"retry_after"`
+}
+
+// SelfMonitorConfig holds the thresholds at which the engine considers its own behavior
+// anomalous and raises a self-incident flagging the capabilities that are reduced
+type SelfMonitorConfig struct {
+	// Enabled samples the engine's own error rates, queue depths and cache hit rate
+	Enabled bool `json:"enabled"`
+
+	// Interval is how often the engine's metrics are sampled
+	Interval time.Duration `json:"interval"`
+
+	// ErrorRateThreshold is the share of failed detections, remediations or model
+	// inferences in an interval that is anomalous (0-1]
+	ErrorRateThreshold float64 `json:"error_rate_threshold"`
+
+	// QueueDepthThreshold is the number of active workflows or plans that is anomalous
+	QueueDepthThreshold int `json:"queue_depth_threshold"`
+
+	// CacheCollapseRatio is the share of its learned average below which the detection
+	// cache hit rate has collapsed (0-1)
+	CacheCollapseRatio float64 `json:"cache_collapse_ratio"`
+
+	// ResolveAfter is the number of consecutive normal samples after which the
+	// self-incident is resolved
+	ResolveAfter int `json:"resolve_after"`
+}
+
+// AnalysisSLOConfig holds the latency objective of analysis requests, whose burn rate
+// the engine exports and alerts on, and when analyses take the batched range query
+// fast path to protect it
+type AnalysisSLOConfig struct {
+	// Enabled tracks the end-to-end latency of POST /api/v1/anomalies/analyze
+	Enabled bool `json:"enabled"`
+
+	// Latency is the duration an analysis must complete within
+	Latency time.Duration `json:"latency"`
+
+	// Objective is the share of analyses that must complete within Latency (0-1),
+	// e.g. 0.95 for "p95 < 3s"
+	Objective float64 `json:"objective"`
+
+	// Window is the rolling window the error budget is computed over, at least 12m
+	Window time.Duration `json:"window"`
+
+	// AlertBurnRate is the burn rate of both the window and its last twelfth at which
+	// the objective alerts
+	AlertBurnRate float64 `json:"alert_burn_rate"`
+
+	// FastPath is when analyses fetch rolling features with one range query per
+	// metric: auto while the short window burns faster than FastPathBurnRate, always
+	// or never
+	FastPath         string  `json:"fast_path"`
+	FastPathBurnRate float64 `json:"fast_path_burn_rate"`
+}
+
+// MetricsQueryConfig holds the metrics proxy at GET /api/v1/metrics/query, which runs
+// named query templates over a scope so the console plugin can render charts without
+// direct Thanos access
+type MetricsQueryConfig struct {
+	// Enabled serves the proxy
+	Enabled bool `json:"enabled"`
+
+	// RateLimit is the sustained number of queries per second per caller
+	RateLimit float64 `json:"rate_limit"`
+
+	// Burst is the number of queries a caller may send at once
+	Burst int `json:"burst"`
+
+	// MaxRange bounds the time range of a query
+	MaxRange time.Duration `json:"max_range"`
+}
+
+// StartupConfig holds how engine startup treats each dependency while it is
+// unavailable: wait for it with backoff, start degraded without it, or fail
+type StartupConfig struct {
+	// Prometheus, KServe and Storage are the modes of each dependency: wait, degraded
+	// or fail. Storage is the DATA_DIR incidents and workflow events are persisted in.
+	Prometheus string `json:"prometheus"`
+	KServe     string `json:"kserve"`
+	Storage    string `json:"storage"`
+
+	// WaitTimeout bounds how long a dependency in wait mode is waited for before the
+	// engine starts degraded
+	WaitTimeout time.Duration `json:"wait_timeout"`
+
+	// Backoff is the delay before the second check in wait mode; it doubles after
+	// every failed check
+	Backoff time.Duration `json:"backoff"`
+
+	// Recheck is how often the dependencies are checked again after startup
+	Recheck time.Duration `json:"recheck"`
+}
+
+// FeatureFlagsConfig holds where feature flags are configured
+type FeatureFlagsConfig struct {
+	// File is a YAML or JSON file setting flags engine-wide and per namespace (empty
+	// keeps the defaults)
+	File string `json:"file,omitempty"`
+
+	// Flags sets flags engine-wide, taking precedence over File, e.g. for one environment
+	Flags map[string]bool `json:"flags,omitempty"`
+}
+
+// DeterministicConfig runs the engine on a clock that stands still until advanced
+// and on seeded random sources, so integration tests and replays of the scoring
+// heuristics produce byte-identical output across runs
+type DeterministicConfig struct {
+	// Time is the RFC 3339 instant the engine's clock starts at; empty runs the engine
+	// on the wall clock
+	Time string `json:"time,omitempty"`
+
+	// Seed seeds random sources such as canary traffic splitting
+	Seed int `json:"seed"`
+}
+
+// Enabled reports whether the engine runs in deterministic mode
+func (d DeterministicConfig) Enabled() bool {
+	return d.Time != ""
+}
+
+// Start returns the instant the engine's clock starts at. It is only meaningful when
+// Enabled and the config is valid.
+func (d DeterministicConfig) Start() time.Time {
+	start, _ := time.Parse(time.RFC3339, d.Time)
+	return start.UTC()
+}
+
+// RetentionConfig holds how long each type of stored data is kept (0 keeps it forever)
+type RetentionConfig struct {
+	// Incidents are purged when not updated within this period
+	Incidents time.Duration `json:"incidents"`
+
+	// Predictions are purged this long after they were served
+	Predictions time.Duration `json:"predictions"`
+
+	// Anomalies are purged this long after they were detected
+	Anomalies time.Duration `json:"anomalies"`
+
+	// PurgeInterval is how often expired data is purged
+	PurgeInterval time.Duration `json:"purge_interval"`
+}
+
+// UpgradeConfig holds the policy applied while the ClusterVersion is progressing
+type UpgradeConfig struct {
+	// Enabled watches the ClusterVersion and switches to conservative mode during upgrades
+	Enabled bool `json:"enabled"`
+
+	// BlockRemediation refuses automated remediation during an upgrade unless forced
+	BlockRemediation bool `json:"block_remediation"`
+
+	// ThresholdIncrease is added to the anomaly threshold during an upgrade (capped at 1.0)
+	ThresholdIncrease float64 `json:"threshold_increase"`
+
+	// TagIncidents labels incidents created during an upgrade with during_upgrade=true
+	TagIncidents bool `json:"tag_incidents"`
+
+	// CheckInterval is how often the ClusterVersion is polled
+	CheckInterval time.Duration `json:"check_interval"`
+}
+
+// PlatformConfig holds settings for the platform layer checks of in-cluster DNS, TLS
+// certificates and etcd storage
+type PlatformConfig struct {
+	// Enabled adds the DNS and certificate checks to platform health and recommends
+	// renewing expiring certificates and relieving etcd before it reaches its quota
+	Enabled bool `json:"enabled"`
+
+	// DNSErrorRateThreshold is the fraction of CoreDNS SERVFAIL and REFUSED responses
+	// above which the platform layer is unhealthy
+	DNSErrorRateThreshold float64 `json:"dns_error_rate_threshold"`
+
+	// CertificateExpiryWarningDays is how many days before expiry TLS certificates are
+	// recommended for renewal
+	CertificateExpiryWarningDays int `json:"certificate_expiry_warning_days"`
+
+	// CertificateNamespaces limits the TLS secrets scanned to these namespaces (empty
+	// scans every namespace)
+	CertificateNamespaces []string `json:"certificate_namespaces,omitempty"`
+
+	// EtcdQuotaGiB is the etcd backend quota assumed when etcd does not export
+	// etcd_server_quota_backend_bytes
+	EtcdQuotaGiB int `json:"etcd_quota_gib"`
+
+	// EtcdQuotaWarningDays is how many days before the etcd database is forecast to
+	// reach its quota a critical recommendation is made
+	EtcdQuotaWarningDays int `json:"etcd_quota_warning_days"`
+}
+
+// SchedulerPressureConfig holds settings for the recommendations made when pods stay
+// pending and unschedulable
+type SchedulerPressureConfig struct {
+	// Enabled explains sustained scheduler pressure from FailedScheduling events in
+	// recommendations and the infrastructure health summary
+	Enabled bool `json:"enabled"`
+
+	// PendingThreshold is the fewest pending pods over Window that counts as pressure
+	PendingThreshold int `json:"pending_threshold"`
+
+	// UnschedulableThreshold is the fewest unschedulable pods over Window that counts
+	// as pressure
+	UnschedulableThreshold int `json:"unschedulable_threshold"`
+
+	// Window is how long both counts must stay at their thresholds
+	Window time.Duration `json:"window"`
+}
+
+// ImageScanConfig holds settings for looking up the vulnerabilities of running images in
+// the Clair scans of a Quay registry
+type ImageScanConfig struct {
+	// QuayURL is the base URL of the Quay registry, e.g. https://quay.io (empty
+	// disables image scans). Only images pulled from its host are looked up.
+	QuayURL string `json:"quay_url,omitempty"`
+
+	// QuayToken is sent as a bearer token when set
+	QuayToken string `json:"-"`
+
+	// MinRestarts is how many restarts make a container count as crashing, for
+	// update_image recommendations
+	MinRestarts int `json:"min_restarts"`
+
+	// CacheTTL is how long the scan of an image digest is reused
+	CacheTTL time.Duration `json:"cache_ttl"`
+}
+
+// SimilarityConfig holds settings for the lookup of past incidents similar to a new one
+type SimilarityConfig struct {
+	// Enabled adds similar past incidents to created incidents and enables
+	// GET /api/v1/incidents/{id}/similar
+	Enabled bool `json:"enabled"`
+
+	// Limit is how many similar incidents are returned by default
+	Limit int `json:"limit"`
+
+	// MinScore is the lowest metadata similarity (0-1) of a returned incident
+	MinScore float64 `json:"min_score"`
+}
+
+// ActionRankingConfig holds settings for ordering recommended actions by how often they
+// resolved the same issue type in this cluster
+type ActionRankingConfig struct {
+	// Enabled ranks recommended actions and accepts verified action outcomes
+	Enabled bool `json:"enabled"`
+
+	// MinAttempts is how many verified outcomes an action needs before it is ranked by
+	// its own success rate
+	MinAttempts int `json:"min_attempts"`
+
+	// HistoryLimit is the number of action outcomes kept; the oldest are dropped first
+	HistoryLimit int `json:"history_limit"`
+}
+
+// RecommendationRulesConfig holds the rules adding organization-specific actions to
+// the built-in actions of recommendations
+type RecommendationRulesConfig struct {
+	// File is a YAML or JSON file of rules matching recommendations by issue type,
+	// namespace, severity and metric thresholds (empty disables)
+	File string `json:"file"`
+
+	// ReloadInterval is how often the file is checked for changes
+	ReloadInterval time.Duration `json:"reload_interval"`
+}
+
+// ArtifactsConfig holds settings for keeping the feature vector, PromQL, model version
+// and raw model response of anomaly analyses with the incidents created from them
+type ArtifactsConfig struct {
+	// Enabled records an artifact for every analysis and attaches it to the incident
+	// created with its analysis_id
+	Enabled bool `json:"enabled"`
+
+	// PendingTTL is how long an analysis can still be attached to a new incident
+	PendingTTL time.Duration `json:"pending_ttl"`
+
+	// PendingLimit is the number of unattached artifacts kept; the oldest are dropped first
+	PendingLimit int `json:"pending_limit"`
+}
+
+// SeverityConfig holds settings for deriving the severity and priority of anomalies and
+// incidents
+type SeverityConfig struct {
+	// MatrixFile is a YAML or JSON severity matrix weighing anomaly score, namespace
+	// tier, blast radius and SLO burn; without it the default matrix is used
+	MatrixFile string `json:"matrix_file,omitempty"`
+}
+
+// weekdays maps lowercase day names to time.Weekday
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
 // GetAnomalyDetectorURL returns the full URL for the anomaly detector KServe service
 func (k *KServeConfig) GetAnomalyDetectorURL() string {
 	if k.Services.AnomalyDetector == "" {
@@ -125,72 +1096,591 @@ func (k *KServeConfig) HasServices() bool {
 	return len(k.GetAllServices()) > 0
 }
 
-// ServiceCount returns the number of configured KServe services
-func (k *KServeConfig) ServiceCount() int {
-	return len(k.GetAllServices())
-}
+// ServiceCount returns the number of configured KServe services
+func (k *KServeConfig) ServiceCount() int {
+	return len(k.GetAllServices())
+}
+
+// Default configuration values
+const (
+	DefaultPort            = 8080
+	DefaultMetricsPort     = 9090
+	DefaultGRPCPort        = 50051
+	DefaultLogLevel        = "info"
+	DefaultNamespace       = "self-healing-platform"
+	DefaultMLServiceURL    = "" // Deprecated: use KServe integration
+	DefaultHTTPTimeout     = 30 * time.Second
+	DefaultRequestTimeout  = 15 * time.Second
+	DefaultAnalysisTimeout = 2 * time.Minute
+	DefaultKubernetesQPS   = 50.0
+	DefaultKubernetesBurst = 100
+	DefaultEnableCORS      = false
+
+	// Prometheus defaults - empty means disabled
+	// In OpenShift, typically: https://prometheus-k8s.openshift-monitoring.svc:9091
+	DefaultPrometheusURL = ""
+
+	// DefaultPrometheusMaxQuerySeries guards against cluster-wide per-pod breakdowns
+	DefaultPrometheusMaxQuerySeries = 1000000
+
+	// DefaultPrometheusCapabilityProbeInterval picks up exporters installed after startup
+	DefaultPrometheusCapabilityProbeInterval = 15 * time.Minute
+
+	// KServe defaults (ADR-039)
+	DefaultKServeEnabled       = true
+	DefaultKServeNamespace     = "self-healing-platform"
+	DefaultKServeTimeout       = 10 * time.Second
+	DefaultKServePredictorPort = 8080 // KServe predictors in RawDeployment mode listen on 8080
+	DefaultKServePreflight     = true
+
+	// KServe adaptive timeout defaults
+	DefaultKServeAdaptiveTimeoutEnabled = true
+	DefaultKServeAdaptiveTimeoutFactor  = 3.0
+	DefaultKServeAdaptiveTimeoutMin     = 1 * time.Second
+	DefaultKServeAdaptiveTimeoutMax     = 60 * time.Second
+
+	// KServe inference gateway defaults
+	DefaultKServeInferEnabled      = false
+	DefaultKServeInferRateLimit    = 10.0
+	DefaultKServeInferBurst        = 20
+	DefaultKServeInferMaxBodyBytes = 10 << 20
+
+	// Anomaly defaults
+	DefaultAnomalyPersistenceEvaluations = 3
+	DefaultAnomalyClearThreshold         = 0.5
+	DefaultAnomalyClearEvaluations       = 3
+	DefaultAnomalyExcludeInactivePods    = true
+	DefaultAnomalyMaxScopePods           = 200
+	DefaultAnomalyControlPlaneModel      = "control-plane-anomaly-detector"
+
+	// Anomaly subscription defaults
+	DefaultAnomalySubscriptionsEnabled    = true
+	DefaultAnomalySubscriptionMinInterval = time.Minute
+	DefaultAnomalyMaxSubscriptions        = 100
+
+	// Anomaly record defaults
+	DefaultAnomalyRecordsEnabled = true
+	DefaultAnomalyRecordLimit    = 10000
+
+	// Anomaly scanner defaults
+	DefaultAnomalyScanInterval = 5 * time.Minute
+
+	// Anomaly feature history defaults
+	DefaultAnomalyFeatureHistoryRetention  = 24 * time.Hour
+	DefaultAnomalyFeatureHistoryResolution = time.Minute
+	DefaultAnomalyFeatureHistoryMaxScopes  = 100
+
+	// MCP defaults
+	DefaultMCPEnabled     = true
+	DefaultMCPApprovalTTL = 30 * time.Minute
+
+	// Summarizer defaults
+	DefaultSummarizerTimeout   = 30 * time.Second
+	DefaultSummarizerMaxTokens = 256
+
+	// Annotations defaults
+	DefaultAnnotationsKubernetesEvents = false
+	DefaultAnnotationsTimeout          = 10 * time.Second
+
+	// Webhook defaults
+	DefaultWebhookMaxAttempts    = 5
+	DefaultWebhookInitialBackoff = 1 * time.Second
+	DefaultWebhookMaxBackoff     = 5 * time.Minute
+	DefaultWebhookTimeout        = 10 * time.Second
+
+	// Email defaults
+	DefaultEmailSMTPPort      = 587
+	DefaultEmailDigestHour    = 8
+	DefaultEmailDigestWeekday = "monday"
+
+	// Alert ingestion defaults
+	DefaultAlertDedupWindow = 1 * time.Hour
+	DefaultAlertFloodLimit  = 20
+
+	// Incident tag defaults
+	DefaultIncidentTagAlertLabels = "team,service,cost_center"
+
+	// Ownership defaults
+	DefaultOwnershipEnabled          = true
+	DefaultOwnershipAnnotationPrefix = "coordination.openshift.io/"
+
+	// Health score defaults
+	DefaultHealthScoreControlPlaneWeight = 0.3
+	DefaultHealthScoreAnomalyWeight      = 0.25
+	DefaultHealthScoreCapacityWeight     = 0.2
+	DefaultHealthScoreIncidentWeight     = 0.25
+
+	// Prediction tracking defaults
+	DefaultPredictionTrackingEnabled = true
+	DefaultPredictionEvalInterval    = 5 * time.Minute
+	DefaultPredictionHistoryLimit    = 10000
+
+	// Upgrade-aware mode defaults
+	DefaultUpgradeAwareEnabled      = true
+	DefaultUpgradeBlockRemediation  = true
+	DefaultUpgradeThresholdIncrease = 0.15
+	DefaultUpgradeTagIncidents      = true
+	DefaultUpgradeCheckInterval     = time.Minute
+
+	// Platform check defaults
+	DefaultPlatformChecksEnabled                = true
+	DefaultPlatformDNSErrorRateThreshold        = 0.05
+	DefaultPlatformCertificateExpiryWarningDays = 30
+	DefaultPlatformEtcdQuotaGiB                 = 8
+	DefaultPlatformEtcdQuotaWarningDays         = 30
+
+	// Scheduler pressure defaults
+	DefaultSchedulerPressureEnabled                = true
+	DefaultSchedulerPressurePendingThreshold       = 10
+	DefaultSchedulerPressureUnschedulableThreshold = 5
+	DefaultSchedulerPressureWindow                 = 15 * time.Minute
+
+	// Image scan defaults
+	DefaultImageScanMinRestarts = 3
+	DefaultImageScanCacheTTL    = time.Hour
+
+	// Similar incident lookup defaults
+	DefaultSimilarityEnabled  = true
+	DefaultSimilarityLimit    = 3
+	DefaultSimilarityMinScore = 0.3
+
+	// Action ranking defaults
+	DefaultActionRankingEnabled     = true
+	DefaultActionRankingMinAttempts = 3
+	DefaultActionOutcomeHistory     = 5000
+
+	// Recommendation rules defaults
+	DefaultRecommendationRulesReloadInterval = 30 * time.Second
+
+	// Analysis artifact defaults
+	DefaultArtifactsEnabled      = true
+	DefaultArtifactsPendingTTL   = time.Hour
+	DefaultArtifactsPendingLimit = 500
+
+	// Admin API defaults
+	DefaultAdminBackupTimeout = 5 * time.Minute
+
+	// API token defaults
+	DefaultAPITokensEnabled  = true
+	DefaultAPITokensRequired = false
+
+	// Runtime configuration defaults
+	DefaultRuntimeConfigEnabled = true
+
+	// Remediation workflow defaults
+	DefaultWorkflowMaxAttempts   = 1
+	DefaultWorkflowRetryBackoff  = 30 * time.Second
+	DefaultWorkflowVerifyTimeout = time.Duration(0)
+
+	// Playbook execution defaults
+	DefaultExecutionRunnerImage  = "registry.redhat.io/openshift4/ose-cli:latest"
+	DefaultExecutionPollInterval = 15 * time.Second
+
+	// Data retention defaults
+	DefaultRetentionIncidents     = 90 * 24 * time.Hour
+	DefaultRetentionPredictions   = 30 * 24 * time.Hour
+	DefaultRetentionAnomalies     = 30 * 24 * time.Hour
+	DefaultRetentionPurgeInterval = time.Hour
+
+	// Load shedding defaults
+	DefaultLoadShedEnabled            = true
+	DefaultLoadShedInterval           = 5 * time.Second
+	DefaultLoadShedCPUThreshold       = 0.85
+	DefaultLoadShedMemoryThreshold    = 0.85
+	DefaultLoadShedGoroutineThreshold = 10000
+	DefaultLoadShedRetryAfter         = 30 * time.Second
+
+	// Self-monitoring defaults
+	DefaultSelfMonitorEnabled             = true
+	DefaultSelfMonitorInterval            = time.Minute
+	DefaultSelfMonitorErrorRateThreshold  = 0.25
+	DefaultSelfMonitorQueueDepthThreshold = 50
+	DefaultSelfMonitorCacheCollapseRatio  = 0.5
+	DefaultSelfMonitorResolveAfter        = 3
+
+	// Analysis latency SLO defaults
+	DefaultAnalysisSLOEnabled          = true
+	DefaultAnalysisSLOLatency          = 3 * time.Second
+	DefaultAnalysisSLOObjective        = 0.95
+	DefaultAnalysisSLOWindow           = time.Hour
+	DefaultAnalysisSLOAlertBurnRate    = 2.0
+	DefaultAnalysisSLOFastPath         = "auto"
+	DefaultAnalysisSLOFastPathBurnRate = 1.0
+
+	// Startup dependency gating defaults
+	DefaultStartupPrometheus  = "wait"
+	DefaultStartupKServe      = "degraded"
+	DefaultStartupStorage     = "fail"
+	DefaultStartupWaitTimeout = 30 * time.Second
+	DefaultStartupBackoff     = time.Second
+	DefaultStartupRecheck     = 30 * time.Second
+
+	// Metrics query proxy defaults
+	DefaultMetricsQueryEnabled   = true
+	DefaultMetricsQueryRateLimit = 5.0
+	DefaultMetricsQueryBurst     = 20
+	DefaultMetricsQueryMaxRange  = 7 * 24 * time.Hour
+
+	// Deterministic mode defaults
+	DefaultDeterministicSeed = 1
+)
+
+// labelNamePattern matches valid Prometheus label names
+var labelNamePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// Valid log levels
+var validLogLevels = map[string]bool{
+	"debug": true,
+	"info":  true,
+	"warn":  true,
+	"error": true,
+	"fatal": true,
+	"panic": true,
+}
+
+// Load loads configuration from environment variables with defaults
+func Load() (*Config, error) {
+	cfg := &Config{
+		Port:                              getEnvAsInt("PORT", DefaultPort),
+		MetricsPort:                       getEnvAsInt("METRICS_PORT", DefaultMetricsPort),
+		GRPCPort:                          getEnvAsInt("GRPC_PORT", DefaultGRPCPort),
+		ObserverMode:                      getEnvAsBool("OBSERVER_MODE", false),
+		TenantsEnabled:                    getEnvAsBool("TENANTS_ENABLED", false),
+		LogLevel:                          getEnv("LOG_LEVEL", DefaultLogLevel),
+		Kubeconfig:                        getEnv("KUBECONFIG", ""),
+		Namespace:                         getEnv("NAMESPACE", DefaultNamespace),
+		MLServiceURL:                      getEnv("ML_SERVICE_URL", DefaultMLServiceURL), // Deprecated
+		ArgocdAPIURL:                      getEnv("ARGOCD_API_URL", ""),
+		PrometheusURL:                     getEnv("PROMETHEUS_URL", DefaultPrometheusURL),
+		PrometheusCAFile:                  getEnv("PROMETHEUS_CA_FILE", ""),
+		PrometheusExtraLabels:             getEnvAsStringMap("PROMETHEUS_EXTRA_LABELS"),
+		PrometheusUseRecordingRules:       getEnvAsBool("PROMETHEUS_USE_RECORDING_RULES", false),
+		PrometheusMaxQuerySeries:          getEnvAsInt("PROMETHEUS_MAX_QUERY_SERIES", DefaultPrometheusMaxQuerySeries),
+		PrometheusRangeMaxPoints:          getEnvAsInt("PROMETHEUS_RANGE_MAX_POINTS", 0),
+		PrometheusCapabilityProbeInterval: getEnvAsDuration("PROMETHEUS_CAPABILITY_PROBE_INTERVAL", DefaultPrometheusCapabilityProbeInterval),
+		HTTPTimeout:                       getEnvAsDuration("HTTP_TIMEOUT", DefaultHTTPTimeout),
+		RequestTimeout:                    getEnvAsDuration("REQUEST_TIMEOUT", DefaultRequestTimeout),
+		AnalysisRequestTimeout:            getEnvAsDuration("ANALYSIS_REQUEST_TIMEOUT", DefaultAnalysisTimeout),
+		EnableCORS:                        getEnvAsBool("ENABLE_CORS", DefaultEnableCORS),
+		CORSAllowOrigin:                   getEnvAsSlice("CORS_ALLOW_ORIGIN", []string{"*"}),
+		KubernetesQPS:                     getEnvAsFloat32("KUBERNETES_QPS", DefaultKubernetesQPS),
+		KubernetesBurst:                   getEnvAsInt("KUBERNETES_BURST", DefaultKubernetesBurst),
+
+		// KServe configuration (ADR-039, ADR-040)
+		KServe: KServeConfig{
+			Enabled:       getEnvAsBool("ENABLE_KSERVE_INTEGRATION", DefaultKServeEnabled),
+			Namespace:     getEnv("KSERVE_NAMESPACE", DefaultKServeNamespace),
+			PredictorPort: getEnvAsInt("KSERVE_PREDICTOR_PORT", DefaultKServePredictorPort),
+			Services: KServeServices{
+				AnomalyDetector:     getEnv("KSERVE_ANOMALY_DETECTOR_SERVICE", ""),
+				PredictiveAnalytics: getEnv("KSERVE_PREDICTIVE_ANALYTICS_SERVICE", ""),
+			},
+			DynamicServices:  discoverKServeServicesFromEnv(),
+			Timeout:          getEnvAsDuration("KSERVE_TIMEOUT", DefaultKServeTimeout),
+			RolloutFile:      getEnv("KSERVE_MODEL_ROLLOUT_FILE", ""),
+			ModelsFile:       getEnv("KSERVE_MODELS_FILE", ""),
+			PreflightEnabled: getEnvAsBool("KSERVE_PREFLIGHT_ENABLED", DefaultKServePreflight),
+			AdaptiveTimeout: KServeAdaptiveTimeoutConfig{
+				Enabled: getEnvAsBool("KSERVE_ADAPTIVE_TIMEOUT_ENABLED", DefaultKServeAdaptiveTimeoutEnabled),
+				Factor:  getEnvAsFloat64("KSERVE_ADAPTIVE_TIMEOUT_FACTOR", DefaultKServeAdaptiveTimeoutFactor),
+				Min:     getEnvAsDuration("KSERVE_ADAPTIVE_TIMEOUT_MIN", DefaultKServeAdaptiveTimeoutMin),
+				Max:     getEnvAsDuration("KSERVE_ADAPTIVE_TIMEOUT_MAX", DefaultKServeAdaptiveTimeoutMax),
+			},
+			Infer: KServeInferConfig{
+				Enabled:      getEnvAsBool("KSERVE_INFER_ENABLED", DefaultKServeInferEnabled),
+				Token:        getEnv("KSERVE_INFER_TOKEN", ""),
+				RateLimit:    getEnvAsFloat64("KSERVE_INFER_RATE_LIMIT", DefaultKServeInferRateLimit),
+				Burst:        getEnvAsInt("KSERVE_INFER_BURST", DefaultKServeInferBurst),
+				MaxBodyBytes: getEnvAsInt("KSERVE_INFER_MAX_BODY_BYTES", DefaultKServeInferMaxBodyBytes),
+			},
+		},
+
+		Anomaly: AnomalyConfig{
+			PersistenceEvaluations: getEnvAsInt("ANOMALY_PERSISTENCE_EVALUATIONS", DefaultAnomalyPersistenceEvaluations),
+			PersistenceOverrides:   getEnvAsIntMap("ANOMALY_PERSISTENCE_OVERRIDES"),
+			ClearThreshold:         getEnvAsFloat64("ANOMALY_CLEAR_THRESHOLD", DefaultAnomalyClearThreshold),
+			ClearEvaluations:       getEnvAsInt("ANOMALY_CLEAR_EVALUATIONS", DefaultAnomalyClearEvaluations),
+			ScopeDefaultsFile:      getEnv("ANOMALY_SCOPE_DEFAULTS_FILE", ""),
+			CalendarsFile:          getEnv("ANOMALY_CALENDARS_FILE", ""),
+			PostFiltersFile:        getEnv("ANOMALY_POST_FILTERS_FILE", ""),
+			HistogramFeatures:      getEnvAsSlice("ANOMALY_HISTOGRAM_FEATURES", nil),
+			HistogramModels:        getEnvAsSlice("ANOMALY_HISTOGRAM_MODELS", nil),
+
+			SubscriptionsEnabled:    getEnvAsBool("ANOMALY_SUBSCRIPTIONS_ENABLED", DefaultAnomalySubscriptionsEnabled),
+			SubscriptionMinInterval: getEnvAsDuration("ANOMALY_SUBSCRIPTION_MIN_INTERVAL", DefaultAnomalySubscriptionMinInterval),
+			MaxSubscriptions:        getEnvAsInt("ANOMALY_MAX_SUBSCRIPTIONS", DefaultAnomalyMaxSubscriptions),
+
+			RecordsEnabled: getEnvAsBool("ANOMALY_RECORDS_ENABLED", DefaultAnomalyRecordsEnabled),
+			RecordLimit:    getEnvAsInt("ANOMALY_RECORD_LIMIT", DefaultAnomalyRecordLimit),
+
+			ScanNamespaces: getEnvAsSlice("ANOMALY_SCAN_NAMESPACES", nil),
+			ScanInterval:   getEnvAsDuration("ANOMALY_SCAN_INTERVAL", DefaultAnomalyScanInterval),
+
+			FeatureHistory:           getEnvAsBool("ANOMALY_FEATURE_HISTORY", false),
+			FeatureHistoryRetention:  getEnvAsDuration("ANOMALY_FEATURE_HISTORY_RETENTION", DefaultAnomalyFeatureHistoryRetention),
+			FeatureHistoryResolution: getEnvAsDuration("ANOMALY_FEATURE_HISTORY_RESOLUTION", DefaultAnomalyFeatureHistoryResolution),
+			FeatureHistoryMaxScopes:  getEnvAsInt("ANOMALY_FEATURE_HISTORY_MAX_SCOPES", DefaultAnomalyFeatureHistoryMaxScopes),
+
+			ExcludeInactivePods: getEnvAsBool("ANOMALY_EXCLUDE_INACTIVE_PODS", DefaultAnomalyExcludeInactivePods),
+			MaxScopePods:        getEnvAsInt("ANOMALY_MAX_SCOPE_PODS", DefaultAnomalyMaxScopePods),
+
+			RouteProbes:      getEnvAsBool("ANOMALY_ROUTE_PROBES", false),
+			RouteProbeModels: getEnvAsSlice("ANOMALY_ROUTE_PROBE_MODELS", nil),
+
+			ScoringHooks:          getEnvAsSlice("ANOMALY_SCORING_HOOKS", nil),
+			MaintenanceNamespaces: getEnvAsSlice("ANOMALY_MAINTENANCE_NAMESPACES", nil),
+
+			ControlPlaneModel: getEnv("ANOMALY_CONTROL_PLANE_MODEL", DefaultAnomalyControlPlaneModel),
+		},
+
+		MCP: MCPConfig{
+			Enabled:     getEnvAsBool("MCP_ENABLED", DefaultMCPEnabled),
+			ApprovalTTL: getEnvAsDuration("MCP_APPROVAL_TTL", DefaultMCPApprovalTTL),
+		},
+
+		Summarizer: SummarizerConfig{
+			URL:       getEnv("SUMMARIZER_URL", ""),
+			Model:     getEnv("SUMMARIZER_MODEL", ""),
+			APIKey:    getEnv("SUMMARIZER_API_KEY", ""),
+			Timeout:   getEnvAsDuration("SUMMARIZER_TIMEOUT", DefaultSummarizerTimeout),
+			MaxTokens: getEnvAsInt("SUMMARIZER_MAX_TOKENS", DefaultSummarizerMaxTokens),
+		},
+
+		Annotations: AnnotationsConfig{
+			GrafanaURL:          getEnv("ANNOTATIONS_GRAFANA_URL", ""),
+			GrafanaToken:        getEnv("ANNOTATIONS_GRAFANA_TOKEN", ""),
+			GrafanaDashboardUID: getEnv("ANNOTATIONS_GRAFANA_DASHBOARD_UID", ""),
+			KubernetesEvents:    getEnvAsBool("ANNOTATIONS_KUBERNETES_EVENTS", DefaultAnnotationsKubernetesEvents),
+			Timeout:             getEnvAsDuration("ANNOTATIONS_TIMEOUT", DefaultAnnotationsTimeout),
+		},
+
+		Runbook: RunbookConfig{
+			File:      getEnv("RUNBOOK_FILE", ""),
+			WikiURL:   getEnv("RUNBOOK_WIKI_URL", ""),
+			WikiSpace: getEnv("RUNBOOK_WIKI_SPACE", ""),
+			WikiToken: getEnv("RUNBOOK_WIKI_TOKEN", ""),
+		},
+
+		Webhook: WebhookConfig{
+			File:           getEnv("WEBHOOK_FILE", ""),
+			Secret:         getEnv("WEBHOOK_SECRET", ""),
+			MaxAttempts:    getEnvAsInt("WEBHOOK_MAX_ATTEMPTS", DefaultWebhookMaxAttempts),
+			InitialBackoff: getEnvAsDuration("WEBHOOK_INITIAL_BACKOFF", DefaultWebhookInitialBackoff),
+			MaxBackoff:     getEnvAsDuration("WEBHOOK_MAX_BACKOFF", DefaultWebhookMaxBackoff),
+			Timeout:        getEnvAsDuration("WEBHOOK_TIMEOUT", DefaultWebhookTimeout),
+		},
+
+		Email: EmailConfig{
+			SMTPHost:      getEnv("EMAIL_SMTP_HOST", ""),
+			SMTPPort:      getEnvAsInt("EMAIL_SMTP_PORT", DefaultEmailSMTPPort),
+			Username:      getEnv("EMAIL_SMTP_USERNAME", ""),
+			Password:      getEnv("EMAIL_SMTP_PASSWORD", ""),
+			From:          getEnv("EMAIL_FROM", ""),
+			TeamsFile:     getEnv("EMAIL_TEAMS_FILE", ""),
+			TemplateDir:   getEnv("EMAIL_TEMPLATE_DIR", ""),
+			DigestHour:    getEnvAsInt("EMAIL_DIGEST_HOUR", DefaultEmailDigestHour),
+			DigestWeekday: getEnv("EMAIL_DIGEST_WEEKDAY", DefaultEmailDigestWeekday),
+		},
+
+		AlertIngest: AlertIngestConfig{
+			DedupWindow: getEnvAsDuration("ALERT_DEDUP_WINDOW", DefaultAlertDedupWindow),
+			FloodLimit:  getEnvAsInt("ALERT_FLOOD_LIMIT", DefaultAlertFloodLimit),
+		},
+
+		IncidentTags: IncidentTagsConfig{
+			RulesFile:   getEnv("INCIDENT_TAG_RULES_FILE", ""),
+			AlertLabels: getEnvAsSlice("INCIDENT_TAG_ALERT_LABELS", strings.Split(DefaultIncidentTagAlertLabels, ",")),
+		},
+
+		Ownership: OwnershipConfig{
+			Enabled:          getEnvAsBool("OWNERSHIP_ENABLED", DefaultOwnershipEnabled),
+			AnnotationPrefix: getEnv("OWNERSHIP_ANNOTATION_PREFIX", DefaultOwnershipAnnotationPrefix),
+		},
+
+		HealthScore: HealthScoreConfig{
+			ControlPlaneWeight: getEnvAsFloat64("HEALTH_SCORE_CONTROL_PLANE_WEIGHT", DefaultHealthScoreControlPlaneWeight),
+			AnomalyWeight:      getEnvAsFloat64("HEALTH_SCORE_ANOMALY_WEIGHT", DefaultHealthScoreAnomalyWeight),
+			CapacityWeight:     getEnvAsFloat64("HEALTH_SCORE_CAPACITY_WEIGHT", DefaultHealthScoreCapacityWeight),
+			IncidentWeight:     getEnvAsFloat64("HEALTH_SCORE_INCIDENT_WEIGHT", DefaultHealthScoreIncidentWeight),
+		},
+
+		PredictionTracking: PredictionTrackingConfig{
+			Enabled:            getEnvAsBool("PREDICTION_TRACKING_ENABLED", DefaultPredictionTrackingEnabled),
+			EvaluationInterval: getEnvAsDuration("PREDICTION_EVALUATION_INTERVAL", DefaultPredictionEvalInterval),
+			HistoryLimit:       getEnvAsInt("PREDICTION_HISTORY_LIMIT", DefaultPredictionHistoryLimit),
+		},
+
+		Upgrade: UpgradeConfig{
+			Enabled:           getEnvAsBool("UPGRADE_AWARE_ENABLED", DefaultUpgradeAwareEnabled),
+			BlockRemediation:  getEnvAsBool("UPGRADE_BLOCK_REMEDIATION", DefaultUpgradeBlockRemediation),
+			ThresholdIncrease: getEnvAsFloat64("UPGRADE_THRESHOLD_INCREASE", DefaultUpgradeThresholdIncrease),
+			TagIncidents:      getEnvAsBool("UPGRADE_TAG_INCIDENTS", DefaultUpgradeTagIncidents),
+			CheckInterval:     getEnvAsDuration("UPGRADE_CHECK_INTERVAL", DefaultUpgradeCheckInterval),
+		},
+
+		Platform: PlatformConfig{
+			Enabled:                      getEnvAsBool("PLATFORM_CHECKS_ENABLED", DefaultPlatformChecksEnabled),
+			DNSErrorRateThreshold:        getEnvAsFloat64("PLATFORM_DNS_ERROR_RATE_THRESHOLD", DefaultPlatformDNSErrorRateThreshold),
+			CertificateExpiryWarningDays: getEnvAsInt("PLATFORM_CERT_EXPIRY_WARNING_DAYS", DefaultPlatformCertificateExpiryWarningDays),
+			CertificateNamespaces:        getEnvAsSlice("PLATFORM_CERT_NAMESPACES", nil),
+			EtcdQuotaGiB:                 getEnvAsInt("PLATFORM_ETCD_QUOTA_GIB", DefaultPlatformEtcdQuotaGiB),
+			EtcdQuotaWarningDays:         getEnvAsInt("PLATFORM_ETCD_QUOTA_WARNING_DAYS", DefaultPlatformEtcdQuotaWarningDays),
+		},
+
+		SchedulerPressure: SchedulerPressureConfig{
+			Enabled:                getEnvAsBool("SCHEDULER_PRESSURE_ENABLED", DefaultSchedulerPressureEnabled),
+			PendingThreshold:       getEnvAsInt("SCHEDULER_PRESSURE_PENDING_THRESHOLD", DefaultSchedulerPressurePendingThreshold),
+			UnschedulableThreshold: getEnvAsInt("SCHEDULER_PRESSURE_UNSCHEDULABLE_THRESHOLD", DefaultSchedulerPressureUnschedulableThreshold),
+			Window:                 getEnvAsDuration("SCHEDULER_PRESSURE_WINDOW", DefaultSchedulerPressureWindow),
+		},
+
+		ImageScan: ImageScanConfig{
+			QuayURL:     getEnv("IMAGE_SCAN_QUAY_URL", ""),
+			QuayToken:   getEnv("IMAGE_SCAN_QUAY_TOKEN", ""),
+			MinRestarts: getEnvAsInt("IMAGE_SCAN_MIN_RESTARTS", DefaultImageScanMinRestarts),
+			CacheTTL:    getEnvAsDuration("IMAGE_SCAN_CACHE_TTL", DefaultImageScanCacheTTL),
+		},
+
+		Similarity: SimilarityConfig{
+			Enabled:  getEnvAsBool("INCIDENT_SIMILARITY_ENABLED", DefaultSimilarityEnabled),
+			Limit:    getEnvAsInt("INCIDENT_SIMILARITY_LIMIT", DefaultSimilarityLimit),
+			MinScore: getEnvAsFloat64("INCIDENT_SIMILARITY_MIN_SCORE", DefaultSimilarityMinScore),
+		},
+
+		ActionRanking: ActionRankingConfig{
+			Enabled:      getEnvAsBool("ACTION_RANKING_ENABLED", DefaultActionRankingEnabled),
+			MinAttempts:  getEnvAsInt("ACTION_RANKING_MIN_ATTEMPTS", DefaultActionRankingMinAttempts),
+			HistoryLimit: getEnvAsInt("ACTION_OUTCOME_HISTORY_LIMIT", DefaultActionOutcomeHistory),
+		},
+
+		RecommendationRules: RecommendationRulesConfig{
+			File:           getEnv("RECOMMENDATION_RULES_FILE", ""),
+			ReloadInterval: getEnvAsDuration("RECOMMENDATION_RULES_RELOAD_INTERVAL", DefaultRecommendationRulesReloadInterval),
+		},
+
+		Artifacts: ArtifactsConfig{
+			Enabled:      getEnvAsBool("ANALYSIS_ARTIFACTS_ENABLED", DefaultArtifactsEnabled),
+			PendingTTL:   getEnvAsDuration("ANALYSIS_ARTIFACTS_PENDING_TTL", DefaultArtifactsPendingTTL),
+			PendingLimit: getEnvAsInt("ANALYSIS_ARTIFACTS_PENDING_LIMIT", DefaultArtifactsPendingLimit),
+		},
+
+		Severity: SeverityConfig{
+			MatrixFile: getEnv("SEVERITY_MATRIX_FILE", ""),
+		},
+
+		Admin: AdminConfig{
+			Token:         getEnv("ADMIN_TOKEN", ""),
+			BackupTimeout: getEnvAsDuration("ADMIN_BACKUP_TIMEOUT", DefaultAdminBackupTimeout),
+		},
+
+		APITokens: APITokensConfig{
+			Enabled:  getEnvAsBool("API_TOKENS_ENABLED", DefaultAPITokensEnabled),
+			Required: getEnvAsBool("API_TOKENS_REQUIRED", DefaultAPITokensRequired),
+		},
+
+		RuntimeConfig: RuntimeConfigConfig{
+			Enabled: getEnvAsBool("RUNTIME_CONFIG_ENABLED", DefaultRuntimeConfigEnabled),
+		},
+
+		Workflow: WorkflowConfig{
+			MaxAttempts:   getEnvAsInt("WORKFLOW_MAX_ATTEMPTS", DefaultWorkflowMaxAttempts),
+			RetryBackoff:  getEnvAsDuration("WORKFLOW_RETRY_BACKOFF", DefaultWorkflowRetryBackoff),
+			VerifyTimeout: getEnvAsDuration("WORKFLOW_VERIFY_TIMEOUT", DefaultWorkflowVerifyTimeout),
+		},
+
+		Execution: ExecutionConfig{
+			Backend:        getEnv("EXECUTION_BACKEND", ""),
+			Namespace:      getEnv("EXECUTION_NAMESPACE", ""),
+			ServiceAccount: getEnv("EXECUTION_SERVICE_ACCOUNT", ""),
+			RunnerImage:    getEnv("EXECUTION_RUNNER_IMAGE", DefaultExecutionRunnerImage),
+			PollInterval:   getEnvAsDuration("EXECUTION_POLL_INTERVAL", DefaultExecutionPollInterval),
+		},
+
+		Budget: BudgetConfig{
+			DailyLimit: getEnvAsInt("REMEDIATION_DAILY_LIMIT", 0),
+			Cooldown:   getEnvAsDuration("REMEDIATION_COOLDOWN", 0),
+		},
+
+		Retention: RetentionConfig{
+			Incidents:     getEnvAsDuration("RETENTION_INCIDENTS", DefaultRetentionIncidents),
+			Predictions:   getEnvAsDuration("RETENTION_PREDICTIONS", DefaultRetentionPredictions),
+			Anomalies:     getEnvAsDuration("RETENTION_ANOMALIES", DefaultRetentionAnomalies),
+			PurgeInterval: getEnvAsDuration("RETENTION_PURGE_INTERVAL", DefaultRetentionPurgeInterval),
+		},
+
+		Encryption: EncryptionConfig{
+			KeysFile: getEnv("ENCRYPTION_KEYS_FILE", ""),
+		},
+
+		Redaction: RedactionConfig{
+			Enabled:      getEnvAsBool("REDACTION_ENABLED", true),
+			PatternsFile: getEnv("REDACTION_PATTERNS_FILE", ""),
+		},
 
-// Default configuration values
-const (
-	DefaultPort            = 8080
-	DefaultMetricsPort     = 9090
-	DefaultLogLevel        = "info"
-	DefaultNamespace       = "self-healing-platform"
-	DefaultMLServiceURL    = "" // Deprecated: use KServe integration
-	DefaultHTTPTimeout     = 30 * time.Second
-	DefaultKubernetesQPS   = 50.0
-	DefaultKubernetesBurst = 100
-	DefaultEnableCORS      = false
+		Security: SecurityConfig{
+			StrictTLS:   getEnvAsBool("SECURITY_STRICT_TLS", false),
+			RequireFIPS: getEnvAsBool("SECURITY_REQUIRE_FIPS", false),
+		},
 
-	// Prometheus defaults - empty means disabled
-	// In OpenShift, typically: https://prometheus-k8s.openshift-monitoring.svc:9091
-	DefaultPrometheusURL = ""
+		ServerTLS: ServerTLSConfig{
+			CertFile:       getEnv("TLS_CERT_FILE", ""),
+			KeyFile:        getEnv("TLS_KEY_FILE", ""),
+			ClientCAFile:   getEnv("TLS_CLIENT_CA_FILE", ""),
+			CRLFile:        getEnv("TLS_CRL_FILE", ""),
+			OCSP:           getEnvAsBool("TLS_OCSP_ENABLED", false),
+			OCSPFailOpen:   getEnvAsBool("TLS_OCSP_FAIL_OPEN", false),
+			AllowedClients: getEnvAsSlice("TLS_ALLOWED_CLIENTS", nil),
+		},
 
-	// KServe defaults (ADR-039)
-	DefaultKServeEnabled       = true
-	DefaultKServeNamespace     = "self-healing-platform"
-	DefaultKServeTimeout       = 10 * time.Second
-	DefaultKServePredictorPort = 8080 // KServe predictors in RawDeployment mode listen on 8080
-)
+		LoadShed: LoadShedConfig{
+			Enabled:            getEnvAsBool("LOAD_SHED_ENABLED", DefaultLoadShedEnabled),
+			Interval:           getEnvAsDuration("LOAD_SHED_INTERVAL", DefaultLoadShedInterval),
+			CPUThreshold:       getEnvAsFloat64("LOAD_SHED_CPU_THRESHOLD", DefaultLoadShedCPUThreshold),
+			MemoryThreshold:    getEnvAsFloat64("LOAD_SHED_MEMORY_THRESHOLD", DefaultLoadShedMemoryThreshold),
+			GoroutineThreshold: getEnvAsInt("LOAD_SHED_GOROUTINE_THRESHOLD", DefaultLoadShedGoroutineThreshold),
+			RetryAfter:         getEnvAsDuration("LOAD_SHED_RETRY_AFTER", DefaultLoadShedRetryAfter),
+		},
 
-// Valid log levels
-var validLogLevels = map[string]bool{
-	"debug": true,
-	"info":  true,
-	"warn":  true,
-	"error": true,
-	"fatal": true,
-	"panic": true,
-}
+		FeatureFlags: FeatureFlagsConfig{
+			File:  getEnv("FEATURE_FLAGS_FILE", ""),
+			Flags: getEnvAsBoolMap("FEATURE_FLAGS"),
+		},
 
-// Load loads configuration from environment variables with defaults
-func Load() (*Config, error) {
-	cfg := &Config{
-		Port:            getEnvAsInt("PORT", DefaultPort),
-		MetricsPort:     getEnvAsInt("METRICS_PORT", DefaultMetricsPort),
-		LogLevel:        getEnv("LOG_LEVEL", DefaultLogLevel),
-		Kubeconfig:      getEnv("KUBECONFIG", ""),
-		Namespace:       getEnv("NAMESPACE", DefaultNamespace),
-		MLServiceURL:    getEnv("ML_SERVICE_URL", DefaultMLServiceURL), // Deprecated
-		ArgocdAPIURL:    getEnv("ARGOCD_API_URL", ""),
-		PrometheusURL:   getEnv("PROMETHEUS_URL", DefaultPrometheusURL),
-		HTTPTimeout:     getEnvAsDuration("HTTP_TIMEOUT", DefaultHTTPTimeout),
-		EnableCORS:      getEnvAsBool("ENABLE_CORS", DefaultEnableCORS),
-		CORSAllowOrigin: getEnvAsSlice("CORS_ALLOW_ORIGIN", []string{"*"}),
-		KubernetesQPS:   getEnvAsFloat32("KUBERNETES_QPS", DefaultKubernetesQPS),
-		KubernetesBurst: getEnvAsInt("KUBERNETES_BURST", DefaultKubernetesBurst),
+		Deterministic: DeterministicConfig{
+			Time: getEnv("DETERMINISTIC_TIME", ""),
+			Seed: getEnvAsInt("DETERMINISTIC_SEED", DefaultDeterministicSeed),
+		},
 
-		// KServe configuration (ADR-039, ADR-040)
-		KServe: KServeConfig{
-			Enabled:       getEnvAsBool("ENABLE_KSERVE_INTEGRATION", DefaultKServeEnabled),
-			Namespace:     getEnv("KSERVE_NAMESPACE", DefaultKServeNamespace),
-			PredictorPort: getEnvAsInt("KSERVE_PREDICTOR_PORT", DefaultKServePredictorPort),
-			Services: KServeServices{
-				AnomalyDetector:     getEnv("KSERVE_ANOMALY_DETECTOR_SERVICE", ""),
-				PredictiveAnalytics: getEnv("KSERVE_PREDICTIVE_ANALYTICS_SERVICE", ""),
-			},
-			DynamicServices: discoverKServeServicesFromEnv(),
-			Timeout:         getEnvAsDuration("KSERVE_TIMEOUT", DefaultKServeTimeout),
+		SelfMonitor: SelfMonitorConfig{
+			Enabled:             getEnvAsBool("SELF_MONITOR_ENABLED", DefaultSelfMonitorEnabled),
+			Interval:            getEnvAsDuration("SELF_MONITOR_INTERVAL", DefaultSelfMonitorInterval),
+			ErrorRateThreshold:  getEnvAsFloat64("SELF_MONITOR_ERROR_RATE_THRESHOLD", DefaultSelfMonitorErrorRateThreshold),
+			QueueDepthThreshold: getEnvAsInt("SELF_MONITOR_QUEUE_DEPTH_THRESHOLD", DefaultSelfMonitorQueueDepthThreshold),
+			CacheCollapseRatio:  getEnvAsFloat64("SELF_MONITOR_CACHE_COLLAPSE_RATIO", DefaultSelfMonitorCacheCollapseRatio),
+			ResolveAfter:        getEnvAsInt("SELF_MONITOR_RESOLVE_AFTER", DefaultSelfMonitorResolveAfter),
+		},
+		AnalysisSLO: AnalysisSLOConfig{
+			Enabled:          getEnvAsBool("ANALYSIS_SLO_ENABLED", DefaultAnalysisSLOEnabled),
+			Latency:          getEnvAsDuration("ANALYSIS_SLO_LATENCY", DefaultAnalysisSLOLatency),
+			Objective:        getEnvAsFloat64("ANALYSIS_SLO_OBJECTIVE", DefaultAnalysisSLOObjective),
+			Window:           getEnvAsDuration("ANALYSIS_SLO_WINDOW", DefaultAnalysisSLOWindow),
+			AlertBurnRate:    getEnvAsFloat64("ANALYSIS_SLO_ALERT_BURN_RATE", DefaultAnalysisSLOAlertBurnRate),
+			FastPath:         getEnv("ANALYSIS_SLO_FAST_PATH", DefaultAnalysisSLOFastPath),
+			FastPathBurnRate: getEnvAsFloat64("ANALYSIS_SLO_FAST_PATH_BURN_RATE", DefaultAnalysisSLOFastPathBurnRate),
+		},
+		Startup: StartupConfig{
+			Prometheus:  getEnv("STARTUP_PROMETHEUS", DefaultStartupPrometheus),
+			KServe:      getEnv("STARTUP_KSERVE", DefaultStartupKServe),
+			Storage:     getEnv("STARTUP_STORAGE", DefaultStartupStorage),
+			WaitTimeout: getEnvAsDuration("STARTUP_WAIT_TIMEOUT", DefaultStartupWaitTimeout),
+			Backoff:     getEnvAsDuration("STARTUP_BACKOFF", DefaultStartupBackoff),
+			Recheck:     getEnvAsDuration("STARTUP_RECHECK_INTERVAL", DefaultStartupRecheck),
+		},
+		MetricsQuery: MetricsQueryConfig{
+			Enabled:   getEnvAsBool("METRICS_QUERY_ENABLED", DefaultMetricsQueryEnabled),
+			RateLimit: getEnvAsFloat64("METRICS_QUERY_RATE_LIMIT", DefaultMetricsQueryRateLimit),
+			Burst:     getEnvAsInt("METRICS_QUERY_BURST", DefaultMetricsQueryBurst),
+			MaxRange:  getEnvAsDuration("METRICS_QUERY_MAX_RANGE", DefaultMetricsQueryMaxRange),
 		},
 	}
 
@@ -218,6 +1708,12 @@ func (c *Config) Validate() error {
 	if c.Port == c.MetricsPort {
 		errors = append(errors, "port and metrics_port cannot be the same")
 	}
+	if c.GRPCPort < 0 || c.GRPCPort > 65535 {
+		errors = append(errors, fmt.Sprintf("invalid grpc_port: %d (must be 0-65535, 0 disables gRPC)", c.GRPCPort))
+	}
+	if c.GRPCPort != 0 && (c.GRPCPort == c.Port || c.GRPCPort == c.MetricsPort) {
+		errors = append(errors, "grpc_port cannot be the same as port or metrics_port")
+	}
 
 	// Validate log level
 	if !validLogLevels[strings.ToLower(c.LogLevel)] {
@@ -245,6 +1741,31 @@ func (c *Config) Validate() error {
 		if c.KServe.Timeout > 2*time.Minute {
 			errors = append(errors, fmt.Sprintf("kserve.timeout too long: %s (must be <= 2m)", c.KServe.Timeout))
 		}
+		if adaptive := c.KServe.AdaptiveTimeout; adaptive.Enabled {
+			if adaptive.Factor < 1 {
+				errors = append(errors, fmt.Sprintf("kserve.adaptive_timeout.factor must be >= 1: %g", adaptive.Factor))
+			}
+			if adaptive.Min <= 0 || adaptive.Min > adaptive.Max {
+				errors = append(errors, fmt.Sprintf("kserve.adaptive_timeout.min (%s) must be positive and not exceed max (%s)", adaptive.Min, adaptive.Max))
+			}
+			if adaptive.Max > 5*time.Minute {
+				errors = append(errors, fmt.Sprintf("kserve.adaptive_timeout.max too long: %s (must be <= 5m)", adaptive.Max))
+			}
+		}
+		if infer := c.KServe.Infer; infer.Enabled {
+			if infer.Token == "" && !c.ServerTLS.MutualTLS() {
+				errors = append(errors, "kserve.infer requires KSERVE_INFER_TOKEN or mutual TLS (TLS_CLIENT_CA_FILE)")
+			}
+			if infer.RateLimit <= 0 {
+				errors = append(errors, fmt.Sprintf("kserve.infer.rate_limit must be positive: %g", infer.RateLimit))
+			}
+			if infer.Burst < 1 {
+				errors = append(errors, fmt.Sprintf("kserve.infer.burst must be at least 1: %d", infer.Burst))
+			}
+			if infer.MaxBodyBytes <= 0 {
+				errors = append(errors, fmt.Sprintf("kserve.infer.max_body_bytes must be positive: %d", infer.MaxBodyBytes))
+			}
+		}
 	} else if c.MLServiceURL != "" {
 		// Legacy ML_SERVICE_URL validation (deprecated but still supported)
 		if !strings.HasPrefix(c.MLServiceURL, "http://") && !strings.HasPrefix(c.MLServiceURL, "https://") {
@@ -267,6 +1788,441 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	// Validate anomaly noise reduction settings
+	if c.Anomaly.PersistenceEvaluations < 0 {
+		errors = append(errors, fmt.Sprintf("anomaly.persistence_evaluations cannot be negative: %d", c.Anomaly.PersistenceEvaluations))
+	}
+	for scope, n := range c.Anomaly.PersistenceOverrides {
+		if n < 1 {
+			errors = append(errors, fmt.Sprintf("anomaly.persistence_overrides[%s] must be >= 1: %d", scope, n))
+		}
+	}
+
+	if c.Anomaly.ClearThreshold < 0 || c.Anomaly.ClearThreshold > 1 {
+		errors = append(errors, fmt.Sprintf("anomaly.clear_threshold must be between 0.0 and 1.0: %.2f", c.Anomaly.ClearThreshold))
+	}
+	if c.Anomaly.ClearEvaluations < 0 {
+		errors = append(errors, fmt.Sprintf("anomaly.clear_evaluations cannot be negative: %d", c.Anomaly.ClearEvaluations))
+	}
+	if (len(c.Anomaly.HistogramFeatures) > 0) != (len(c.Anomaly.HistogramModels) > 0) {
+		errors = append(errors, "anomaly.histogram_features and anomaly.histogram_models must be set together")
+	}
+	if len(c.Anomaly.RouteProbeModels) > 0 && !c.Anomaly.RouteProbes {
+		errors = append(errors, "anomaly.route_probe_models requires anomaly.route_probes")
+	}
+	for _, pattern := range c.Anomaly.MaintenanceNamespaces {
+		if _, err := path.Match(pattern, ""); err != nil {
+			errors = append(errors, fmt.Sprintf("anomaly.maintenance_namespaces has an invalid pattern: %q", pattern))
+		}
+	}
+	if c.Anomaly.ExcludeInactivePods && c.Anomaly.MaxScopePods < 1 {
+		errors = append(errors, fmt.Sprintf("anomaly.max_scope_pods must be at least 1: %d", c.Anomaly.MaxScopePods))
+	}
+	if c.Anomaly.SubscriptionsEnabled {
+		if c.Anomaly.SubscriptionMinInterval < 10*time.Second {
+			errors = append(errors, fmt.Sprintf("anomaly.subscription_min_interval too short: %s (must be >= 10s)", c.Anomaly.SubscriptionMinInterval))
+		}
+		if c.Anomaly.MaxSubscriptions < 1 {
+			errors = append(errors, fmt.Sprintf("anomaly.max_subscriptions must be at least 1: %d", c.Anomaly.MaxSubscriptions))
+		}
+	}
+	if c.Anomaly.RecordsEnabled && c.Anomaly.RecordLimit < 1 {
+		errors = append(errors, fmt.Sprintf("anomaly.record_limit must be at least 1: %d", c.Anomaly.RecordLimit))
+	}
+	if (len(c.Anomaly.ScanNamespaces) > 0 || c.TenantsEnabled) && c.Anomaly.ScanInterval < time.Minute {
+		errors = append(errors, fmt.Sprintf("anomaly.scan_interval too short: %s (must be >= 1m)", c.Anomaly.ScanInterval))
+	}
+
+	if c.Anomaly.FeatureHistory {
+		if c.Anomaly.FeatureHistoryResolution < 10*time.Second {
+			errors = append(errors, fmt.Sprintf("anomaly.feature_history_resolution too short: %s (must be >= 10s)", c.Anomaly.FeatureHistoryResolution))
+		} else if samples := c.Anomaly.FeatureHistoryRetention / c.Anomaly.FeatureHistoryResolution; samples < 1 || samples > 10080 {
+			errors = append(errors, fmt.Sprintf("anomaly.feature_history_retention must hold 1 to 10080 samples of feature_history_resolution: %s", c.Anomaly.FeatureHistoryRetention))
+		}
+		if c.Anomaly.FeatureHistoryMaxScopes < 1 {
+			errors = append(errors, fmt.Sprintf("anomaly.feature_history_max_scopes must be at least 1: %d", c.Anomaly.FeatureHistoryMaxScopes))
+		}
+	}
+
+	// Validate MCP configuration
+	if c.MCP.Enabled && c.MCP.ApprovalTTL < time.Minute {
+		errors = append(errors, fmt.Sprintf("mcp.approval_ttl must be at least 1m: %s", c.MCP.ApprovalTTL))
+	}
+
+	// Validate summarizer configuration
+	if c.Summarizer.Enabled() {
+		if !strings.HasPrefix(c.Summarizer.URL, "http://") && !strings.HasPrefix(c.Summarizer.URL, "https://") {
+			errors = append(errors, fmt.Sprintf("summarizer.url must start with http:// or https://: %s", c.Summarizer.URL))
+		}
+		if c.Summarizer.Model == "" {
+			errors = append(errors, "summarizer.model is required when summarizer.url is set")
+		}
+		if c.Summarizer.Timeout < 1*time.Second || c.Summarizer.Timeout > 5*time.Minute {
+			errors = append(errors, fmt.Sprintf("summarizer.timeout must be between 1s and 5m: %s", c.Summarizer.Timeout))
+		}
+		if c.Summarizer.MaxTokens < 1 {
+			errors = append(errors, fmt.Sprintf("summarizer.max_tokens must be positive: %d", c.Summarizer.MaxTokens))
+		}
+	}
+
+	// Validate annotations configuration
+	if c.Annotations.GrafanaURL != "" {
+		if !strings.HasPrefix(c.Annotations.GrafanaURL, "http://") && !strings.HasPrefix(c.Annotations.GrafanaURL, "https://") {
+			errors = append(errors, fmt.Sprintf("annotations.grafana_url must start with http:// or https://: %s", c.Annotations.GrafanaURL))
+		}
+	}
+	if c.Annotations.Enabled() && (c.Annotations.Timeout < 1*time.Second || c.Annotations.Timeout > 5*time.Minute) {
+		errors = append(errors, fmt.Sprintf("annotations.timeout must be between 1s and 5m: %s", c.Annotations.Timeout))
+	}
+
+	// Validate runbook wiki URL if provided
+	if c.Runbook.WikiURL != "" {
+		if !strings.HasPrefix(c.Runbook.WikiURL, "http://") && !strings.HasPrefix(c.Runbook.WikiURL, "https://") {
+			errors = append(errors, fmt.Sprintf("runbook.wiki_url must start with http:// or https://: %s", c.Runbook.WikiURL))
+		}
+	}
+
+	// Validate webhook retry settings
+	if c.Webhook.Enabled() {
+		if c.Webhook.MaxAttempts < 1 || c.Webhook.MaxAttempts > 20 {
+			errors = append(errors, fmt.Sprintf("webhook.max_attempts must be between 1 and 20: %d", c.Webhook.MaxAttempts))
+		}
+		if c.Webhook.InitialBackoff <= 0 {
+			errors = append(errors, fmt.Sprintf("webhook.initial_backoff must be positive: %s", c.Webhook.InitialBackoff))
+		}
+		if c.Webhook.MaxBackoff < c.Webhook.InitialBackoff {
+			errors = append(errors, fmt.Sprintf("webhook.max_backoff (%s) must be at least webhook.initial_backoff (%s)", c.Webhook.MaxBackoff, c.Webhook.InitialBackoff))
+		}
+		if c.Webhook.Timeout < 1*time.Second || c.Webhook.Timeout > 5*time.Minute {
+			errors = append(errors, fmt.Sprintf("webhook.timeout must be between 1s and 5m: %s", c.Webhook.Timeout))
+		}
+	}
+
+	// Validate email configuration
+	if c.Email.Enabled() {
+		if c.Email.SMTPPort < 1 || c.Email.SMTPPort > 65535 {
+			errors = append(errors, fmt.Sprintf("email.smtp_port must be between 1 and 65535: %d", c.Email.SMTPPort))
+		}
+		if c.Email.From == "" {
+			errors = append(errors, "email.from is required when email.smtp_host is set")
+		}
+		if c.Email.TeamsFile == "" {
+			errors = append(errors, "email.teams_file is required when email.smtp_host is set")
+		}
+		if c.Email.DigestHour < 0 || c.Email.DigestHour > 23 {
+			errors = append(errors, fmt.Sprintf("email.digest_hour must be between 0 and 23: %d", c.Email.DigestHour))
+		}
+		if _, ok := weekdays[strings.ToLower(c.Email.DigestWeekday)]; !ok {
+			errors = append(errors, fmt.Sprintf("invalid email.digest_weekday: %s", c.Email.DigestWeekday))
+		}
+	}
+
+	// Validate alert ingestion; a zero config (tests, embedding) uses the ingester defaults
+	if c.AlertIngest.DedupWindow < 0 || (c.AlertIngest.DedupWindow > 0 && c.AlertIngest.DedupWindow < time.Minute) {
+		errors = append(errors, fmt.Sprintf("alert_ingest.dedup_window must be at least 1m: %s", c.AlertIngest.DedupWindow))
+	}
+	if c.AlertIngest.FloodLimit < 0 {
+		errors = append(errors, fmt.Sprintf("alert_ingest.flood_limit cannot be negative: %d", c.AlertIngest.FloodLimit))
+	}
+
+	// Validate incident tag sources
+	for _, label := range c.IncidentTags.AlertLabels {
+		if !labelNamePattern.MatchString(label) {
+			errors = append(errors, fmt.Sprintf("incident_tags.alert_labels must be Prometheus label names: %q", label))
+		}
+	}
+
+	// Validate ownership
+	if c.Ownership.Enabled && !strings.HasSuffix(c.Ownership.AnnotationPrefix, "/") {
+		errors = append(errors, fmt.Sprintf("ownership.annotation_prefix must end with /: %q", c.Ownership.AnnotationPrefix))
+	}
+
+	// Validate health score weights
+	weights := []struct {
+		name  string
+		value float64
+	}{
+		{"control_plane_weight", c.HealthScore.ControlPlaneWeight},
+		{"anomaly_weight", c.HealthScore.AnomalyWeight},
+		{"capacity_weight", c.HealthScore.CapacityWeight},
+		{"incident_weight", c.HealthScore.IncidentWeight},
+	}
+	for _, weight := range weights {
+		if weight.value < 0 {
+			errors = append(errors, fmt.Sprintf("health_score.%s must not be negative: %g", weight.name, weight.value))
+		}
+	}
+
+	// Validate prediction tracking
+	if c.PredictionTracking.Enabled {
+		if c.PredictionTracking.EvaluationInterval < time.Minute {
+			errors = append(errors, fmt.Sprintf("prediction_tracking.evaluation_interval must be at least 1m: %s", c.PredictionTracking.EvaluationInterval))
+		}
+		if c.PredictionTracking.HistoryLimit < 1 {
+			errors = append(errors, fmt.Sprintf("prediction_tracking.history_limit must be positive: %d", c.PredictionTracking.HistoryLimit))
+		}
+	}
+
+	// Validate upgrade-aware mode
+	if c.Upgrade.Enabled {
+		if c.Upgrade.ThresholdIncrease < 0 || c.Upgrade.ThresholdIncrease > 1 {
+			errors = append(errors, fmt.Sprintf("upgrade.threshold_increase must be between 0.0 and 1.0: %.2f", c.Upgrade.ThresholdIncrease))
+		}
+		if c.Upgrade.CheckInterval < 10*time.Second {
+			errors = append(errors, fmt.Sprintf("upgrade.check_interval must be at least 10s: %s", c.Upgrade.CheckInterval))
+		}
+	}
+
+	if c.Platform.Enabled {
+		if c.Platform.DNSErrorRateThreshold <= 0 || c.Platform.DNSErrorRateThreshold > 1 {
+			errors = append(errors, fmt.Sprintf("platform.dns_error_rate_threshold must be between 0.0 (exclusive) and 1.0: %.2f", c.Platform.DNSErrorRateThreshold))
+		}
+		if c.Platform.CertificateExpiryWarningDays < 1 {
+			errors = append(errors, fmt.Sprintf("platform.certificate_expiry_warning_days must be at least 1: %d", c.Platform.CertificateExpiryWarningDays))
+		}
+		if c.Platform.EtcdQuotaGiB < 1 {
+			errors = append(errors, fmt.Sprintf("platform.etcd_quota_gib must be at least 1: %d", c.Platform.EtcdQuotaGiB))
+		}
+		if c.Platform.EtcdQuotaWarningDays < 1 {
+			errors = append(errors, fmt.Sprintf("platform.etcd_quota_warning_days must be at least 1: %d", c.Platform.EtcdQuotaWarningDays))
+		}
+	}
+
+	if c.SchedulerPressure.Enabled {
+		if c.SchedulerPressure.PendingThreshold < 1 {
+			errors = append(errors, fmt.Sprintf("scheduler_pressure.pending_threshold must be at least 1: %d", c.SchedulerPressure.PendingThreshold))
+		}
+		if c.SchedulerPressure.UnschedulableThreshold < 1 {
+			errors = append(errors, fmt.Sprintf("scheduler_pressure.unschedulable_threshold must be at least 1: %d", c.SchedulerPressure.UnschedulableThreshold))
+		}
+		if c.SchedulerPressure.Window < time.Minute {
+			errors = append(errors, fmt.Sprintf("scheduler_pressure.window must be at least 1m: %s", c.SchedulerPressure.Window))
+		}
+	}
+
+	if c.ImageScan.QuayURL != "" {
+		if !strings.HasPrefix(c.ImageScan.QuayURL, "http://") && !strings.HasPrefix(c.ImageScan.QuayURL, "https://") {
+			errors = append(errors, fmt.Sprintf("image_scan.quay_url must start with http:// or https://: %s", c.ImageScan.QuayURL))
+		}
+		if c.ImageScan.MinRestarts < 1 {
+			errors = append(errors, fmt.Sprintf("image_scan.min_restarts must be at least 1: %d", c.ImageScan.MinRestarts))
+		}
+		if c.ImageScan.CacheTTL < time.Minute {
+			errors = append(errors, fmt.Sprintf("image_scan.cache_ttl must be at least 1m: %s", c.ImageScan.CacheTTL))
+		}
+	}
+
+	if c.Similarity.Enabled {
+		if c.Similarity.Limit < 1 || c.Similarity.Limit > 20 {
+			errors = append(errors, fmt.Sprintf("similarity.limit must be between 1 and 20: %d", c.Similarity.Limit))
+		}
+		if c.Similarity.MinScore <= 0 || c.Similarity.MinScore > 1 {
+			errors = append(errors, fmt.Sprintf("similarity.min_score must be between 0.0 (exclusive) and 1.0: %.2f", c.Similarity.MinScore))
+		}
+	}
+
+	if c.Workflow.MaxAttempts < 0 || c.Workflow.MaxAttempts > 10 {
+		errors = append(errors, fmt.Sprintf("workflow.max_attempts must be between 1 and 10: %d", c.Workflow.MaxAttempts))
+	}
+	if c.Workflow.RetryBackoff < 0 {
+		errors = append(errors, fmt.Sprintf("workflow.retry_backoff must not be negative: %s", c.Workflow.RetryBackoff))
+	}
+	if c.Workflow.VerifyTimeout < 0 {
+		errors = append(errors, fmt.Sprintf("workflow.verify_timeout must not be negative: %s", c.Workflow.VerifyTimeout))
+	}
+
+	if c.Execution.Backend != "" && c.Execution.Backend != "argo" && c.Execution.Backend != "tekton" {
+		errors = append(errors, fmt.Sprintf("execution.backend must be argo or tekton: %s", c.Execution.Backend))
+	}
+	if c.Execution.PollInterval < 0 {
+		errors = append(errors, fmt.Sprintf("execution.poll_interval must not be negative: %s", c.Execution.PollInterval))
+	}
+
+	if c.Budget.DailyLimit < 0 {
+		errors = append(errors, fmt.Sprintf("budget.daily_limit must not be negative: %d", c.Budget.DailyLimit))
+	}
+	if c.Budget.Cooldown < 0 {
+		errors = append(errors, fmt.Sprintf("budget.cooldown must not be negative: %s", c.Budget.Cooldown))
+	}
+
+	if c.ActionRanking.Enabled {
+		if c.ActionRanking.MinAttempts < 1 {
+			errors = append(errors, fmt.Sprintf("action_ranking.min_attempts must be at least 1: %d", c.ActionRanking.MinAttempts))
+		}
+		if c.ActionRanking.HistoryLimit < 1 {
+			errors = append(errors, fmt.Sprintf("action_ranking.history_limit must be at least 1: %d", c.ActionRanking.HistoryLimit))
+		}
+	}
+
+	if c.RecommendationRules.File != "" && c.RecommendationRules.ReloadInterval < time.Second {
+		errors = append(errors, fmt.Sprintf("recommendation_rules.reload_interval must be at least 1s: %s", c.RecommendationRules.ReloadInterval))
+	}
+
+	if c.Artifacts.Enabled {
+		if c.Artifacts.PendingTTL < time.Minute {
+			errors = append(errors, fmt.Sprintf("artifacts.pending_ttl must be at least 1m: %s", c.Artifacts.PendingTTL))
+		}
+		if c.Artifacts.PendingLimit < 1 {
+			errors = append(errors, fmt.Sprintf("artifacts.pending_limit must be at least 1: %d", c.Artifacts.PendingLimit))
+		}
+	}
+
+	// Validate admin API
+	if c.Admin.Enabled() && c.Admin.BackupTimeout < time.Second {
+		errors = append(errors, fmt.Sprintf("admin.backup_timeout must be at least 1s: %s", c.Admin.BackupTimeout))
+	}
+
+	// Validate API tokens
+	if c.APITokens.Required && !c.APITokens.Enabled {
+		errors = append(errors, "api_tokens.required needs api_tokens.enabled")
+	}
+
+	// Validate data retention
+	if c.Retention.Incidents < 0 {
+		errors = append(errors, fmt.Sprintf("retention.incidents cannot be negative: %s", c.Retention.Incidents))
+	}
+	if c.Retention.Predictions < 0 {
+		errors = append(errors, fmt.Sprintf("retention.predictions cannot be negative: %s", c.Retention.Predictions))
+	}
+	if c.Retention.Anomalies < 0 {
+		errors = append(errors, fmt.Sprintf("retention.anomalies cannot be negative: %s", c.Retention.Anomalies))
+	}
+	if (c.Retention.Incidents > 0 || c.Retention.Predictions > 0 || c.Retention.Anomalies > 0) && c.Retention.PurgeInterval < time.Minute {
+		errors = append(errors, fmt.Sprintf("retention.purge_interval must be at least 1m: %s", c.Retention.PurgeInterval))
+	}
+
+	// Validate load shedding
+	if c.LoadShed.Enabled {
+		if c.LoadShed.Interval < time.Second {
+			errors = append(errors, fmt.Sprintf("load_shed.interval must be at least 1s: %s", c.LoadShed.Interval))
+		}
+		if c.LoadShed.CPUThreshold <= 0 || c.LoadShed.CPUThreshold > 1 {
+			errors = append(errors, fmt.Sprintf("load_shed.cpu_threshold must be in (0, 1]: %g", c.LoadShed.CPUThreshold))
+		}
+		if c.LoadShed.MemoryThreshold <= 0 || c.LoadShed.MemoryThreshold > 1 {
+			errors = append(errors, fmt.Sprintf("load_shed.memory_threshold must be in (0, 1]: %g", c.LoadShed.MemoryThreshold))
+		}
+		if c.LoadShed.GoroutineThreshold < 1 {
+			errors = append(errors, fmt.Sprintf("load_shed.goroutine_threshold must be at least 1: %d", c.LoadShed.GoroutineThreshold))
+		}
+		if c.LoadShed.RetryAfter < time.Second {
+			errors = append(errors, fmt.Sprintf("load_shed.retry_after must be at least 1s: %s", c.LoadShed.RetryAfter))
+		}
+	}
+
+	// Validate self-monitoring
+	if c.SelfMonitor.Enabled {
+		if c.SelfMonitor.Interval < time.Second {
+			errors = append(errors, fmt.Sprintf("self_monitor.interval must be at least 1s: %s", c.SelfMonitor.Interval))
+		}
+		if c.SelfMonitor.ErrorRateThreshold <= 0 || c.SelfMonitor.ErrorRateThreshold > 1 {
+			errors = append(errors, fmt.Sprintf("self_monitor.error_rate_threshold must be in (0, 1]: %g", c.SelfMonitor.ErrorRateThreshold))
+		}
+		if c.SelfMonitor.QueueDepthThreshold < 1 {
+			errors = append(errors, fmt.Sprintf("self_monitor.queue_depth_threshold must be at least 1: %d", c.SelfMonitor.QueueDepthThreshold))
+		}
+		if c.SelfMonitor.CacheCollapseRatio <= 0 || c.SelfMonitor.CacheCollapseRatio >= 1 {
+			errors = append(errors, fmt.Sprintf("self_monitor.cache_collapse_ratio must be in (0, 1): %g", c.SelfMonitor.CacheCollapseRatio))
+		}
+		if c.SelfMonitor.ResolveAfter < 1 {
+			errors = append(errors, fmt.Sprintf("self_monitor.resolve_after must be at least 1: %d", c.SelfMonitor.ResolveAfter))
+		}
+	}
+
+	// Validate the analysis latency SLO
+	if c.AnalysisSLO.Enabled {
+		if c.AnalysisSLO.Latency <= 0 {
+			errors = append(errors, fmt.Sprintf("analysis_slo.latency must be positive: %s", c.AnalysisSLO.Latency))
+		}
+		if c.AnalysisSLO.Objective <= 0 || c.AnalysisSLO.Objective >= 1 {
+			errors = append(errors, fmt.Sprintf("analysis_slo.objective must be in (0, 1): %g", c.AnalysisSLO.Objective))
+		}
+		if c.AnalysisSLO.Window < 12*time.Minute {
+			errors = append(errors, fmt.Sprintf("analysis_slo.window must be at least 12m: %s", c.AnalysisSLO.Window))
+		}
+		if c.AnalysisSLO.AlertBurnRate <= 0 {
+			errors = append(errors, fmt.Sprintf("analysis_slo.alert_burn_rate must be positive: %g", c.AnalysisSLO.AlertBurnRate))
+		}
+		switch c.AnalysisSLO.FastPath {
+		case "auto", "always", "never":
+		default:
+			errors = append(errors, fmt.Sprintf("analysis_slo.fast_path must be auto, always or never: %s", c.AnalysisSLO.FastPath))
+		}
+		if c.AnalysisSLO.FastPathBurnRate <= 0 {
+			errors = append(errors, fmt.Sprintf("analysis_slo.fast_path_burn_rate must be positive: %g", c.AnalysisSLO.FastPathBurnRate))
+		}
+	}
+
+	// Validate startup dependency gating; zero values take the gates' defaults
+	for _, gate := range []struct{ name, mode string }{
+		{"prometheus", c.Startup.Prometheus},
+		{"kserve", c.Startup.KServe},
+		{"storage", c.Startup.Storage},
+	} {
+		switch gate.mode {
+		case "", "wait", "degraded", "fail":
+		default:
+			errors = append(errors, fmt.Sprintf("startup.%s must be wait, degraded or fail: %s", gate.name, gate.mode))
+		}
+	}
+	if c.Startup.WaitTimeout < 0 {
+		errors = append(errors, fmt.Sprintf("startup.wait_timeout cannot be negative: %s", c.Startup.WaitTimeout))
+	}
+	if c.Startup.Backoff < 0 || (c.Startup.WaitTimeout > 0 && c.Startup.Backoff > c.Startup.WaitTimeout) {
+		errors = append(errors, fmt.Sprintf("startup.backoff must be positive and at most startup.wait_timeout: %s", c.Startup.Backoff))
+	}
+	if c.Startup.Recheck != 0 && c.Startup.Recheck < time.Second {
+		errors = append(errors, fmt.Sprintf("startup.recheck must be at least 1s: %s", c.Startup.Recheck))
+	}
+
+	// Validate the metrics query proxy
+	if query := c.MetricsQuery; query.Enabled {
+		if query.RateLimit <= 0 {
+			errors = append(errors, fmt.Sprintf("metrics_query.rate_limit must be positive: %g", query.RateLimit))
+		}
+		if query.Burst < 1 {
+			errors = append(errors, fmt.Sprintf("metrics_query.burst must be at least 1: %d", query.Burst))
+		}
+		if query.MaxRange < time.Hour {
+			errors = append(errors, fmt.Sprintf("metrics_query.max_range must be at least 1h: %s", query.MaxRange))
+		}
+	}
+
+	// Validate deterministic mode
+	if c.Deterministic.Enabled() {
+		if _, err := time.Parse(time.RFC3339, c.Deterministic.Time); err != nil {
+			errors = append(errors, fmt.Sprintf("deterministic.time must be an RFC 3339 time: %q", c.Deterministic.Time))
+		}
+	}
+
+	// Validate API server TLS
+	if (c.ServerTLS.CertFile == "") != (c.ServerTLS.KeyFile == "") {
+		errors = append(errors, "server_tls.cert_file and server_tls.key_file must be set together")
+	}
+	if c.ServerTLS.MutualTLS() && !c.ServerTLS.Enabled() {
+		errors = append(errors, "server_tls.client_ca_file requires server_tls.cert_file")
+	}
+	if !c.ServerTLS.MutualTLS() && (c.ServerTLS.CRLFile != "" || c.ServerTLS.OCSP || len(c.ServerTLS.AllowedClients) > 0) {
+		errors = append(errors, "server_tls.crl_file, server_tls.ocsp and server_tls.allowed_clients require server_tls.client_ca_file")
+	}
+
+	if c.PrometheusMaxQuerySeries < 0 {
+		errors = append(errors, fmt.Sprintf("prometheus_max_query_series cannot be negative: %d", c.PrometheusMaxQuerySeries))
+	}
+	if c.PrometheusRangeMaxPoints < 0 {
+		errors = append(errors, fmt.Sprintf("prometheus_range_max_points cannot be negative: %d", c.PrometheusRangeMaxPoints))
+	}
+	if c.PrometheusCapabilityProbeInterval < 0 {
+		errors = append(errors, fmt.Sprintf("prometheus_capability_probe_interval cannot be negative: %s", c.PrometheusCapabilityProbeInterval))
+	}
+
+	// Validate Prometheus extra label names
+	for name := range c.PrometheusExtraLabels {
+		if !labelNamePattern.MatchString(name) {
+			errors = append(errors, fmt.Sprintf("invalid prometheus extra label name: %q", name))
+		}
+	}
+
 	// Validate HTTP timeout
 	if c.HTTPTimeout < 1*time.Second {
 		errors = append(errors, fmt.Sprintf("http_timeout too short: %s (must be >= 1s)", c.HTTPTimeout))
@@ -275,6 +2231,14 @@ func (c *Config) Validate() error {
 		errors = append(errors, fmt.Sprintf("http_timeout too long: %s (must be <= 5m)", c.HTTPTimeout))
 	}
 
+	// Validate server-side request timeouts
+	if c.RequestTimeout < 0 || c.AnalysisRequestTimeout < 0 {
+		errors = append(errors, "request_timeout and analysis_request_timeout cannot be negative")
+	}
+	if c.RequestTimeout > 0 && c.AnalysisRequestTimeout > 0 && c.AnalysisRequestTimeout < c.RequestTimeout {
+		errors = append(errors, fmt.Sprintf("analysis_request_timeout must be at least request_timeout: %s < %s", c.AnalysisRequestTimeout, c.RequestTimeout))
+	}
+
 	// Validate Kubernetes client settings
 	if c.KubernetesQPS <= 0 {
 		errors = append(errors, fmt.Sprintf("kubernetes_qps must be positive: %f", c.KubernetesQPS))
@@ -339,6 +2303,19 @@ func getEnvAsFloat32(key string, defaultVal float32) float32 {
 	return float32(value)
 }
 
+// getEnvAsFloat64 gets an environment variable as a float64 or returns a default value
+func getEnvAsFloat64(key string, defaultVal float64) float64 {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultVal
+	}
+	value, err := strconv.ParseFloat(valueStr, 64)
+	if err != nil {
+		return defaultVal
+	}
+	return value
+}
+
 // getEnvAsBool gets an environment variable as a boolean or returns a default value
 func getEnvAsBool(key string, defaultVal bool) bool {
 	valueStr := os.Getenv(key)
@@ -384,6 +2361,52 @@ func getEnvAsSlice(key string, defaultVal []string) []string {
 	return result
 }
 
+// getEnvAsStringMap parses an environment variable of the form "key=value,other=value".
+// Malformed entries are skipped.
+func getEnvAsStringMap(key string) map[string]string {
+	result := make(map[string]string)
+	for _, pair := range getEnvAsSlice(key, nil) {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		name := strings.TrimSpace(parts[0])
+		if name == "" {
+			continue
+		}
+		result[name] = strings.TrimSpace(parts[1])
+	}
+	return result
+}
+
+// getEnvAsIntMap parses an environment variable of the form "key=1,other=2".
+// Entries with non-integer values are skipped.
+func getEnvAsIntMap(key string) map[string]int {
+	result := make(map[string]int)
+	for name, valueStr := range getEnvAsStringMap(key) {
+		value, err := strconv.Atoi(valueStr)
+		if err != nil {
+			continue
+		}
+		result[name] = value
+	}
+	return result
+}
+
+// getEnvAsBoolMap parses an environment variable of the form "key=true,other=false".
+// Entries with non-boolean values are skipped.
+func getEnvAsBoolMap(key string) map[string]bool {
+	result := make(map[string]bool)
+	for name, valueStr := range getEnvAsStringMap(key) {
+		value, err := strconv.ParseBool(valueStr)
+		if err != nil {
+			continue
+		}
+		result[name] = value
+	}
+	return result
+}
+
 // discoverKServeServicesFromEnv discovers KServe services from environment variables.
 // Pattern: KSERVE_<MODEL_NAME>_SERVICE = service-name
 // Example: KSERVE_DISK_FAILURE_PREDICTOR_SERVICE = disk-failure-predictor-predictor
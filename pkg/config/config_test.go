@@ -24,6 +24,7 @@ func TestLoad_Defaults(t *testing.T) {
 	// Verify defaults
 	assert.Equal(t, DefaultPort, cfg.Port)
 	assert.Equal(t, DefaultMetricsPort, cfg.MetricsPort)
+	assert.Equal(t, DefaultGRPCPort, cfg.GRPCPort)
 	assert.Equal(t, DefaultLogLevel, cfg.LogLevel)
 	assert.Equal(t, DefaultNamespace, cfg.Namespace)
 	assert.Equal(t, DefaultMLServiceURL, cfg.MLServiceURL) // Empty by default
@@ -37,6 +38,13 @@ func TestLoad_Defaults(t *testing.T) {
 	assert.True(t, cfg.KServe.Enabled)
 	assert.Equal(t, DefaultKServeNamespace, cfg.KServe.Namespace)
 	assert.Equal(t, DefaultKServeTimeout, cfg.KServe.Timeout)
+	assert.True(t, cfg.KServe.PreflightEnabled)
+	assert.Equal(t, KServeAdaptiveTimeoutConfig{
+		Enabled: true,
+		Factor:  DefaultKServeAdaptiveTimeoutFactor,
+		Min:     DefaultKServeAdaptiveTimeoutMin,
+		Max:     DefaultKServeAdaptiveTimeoutMax,
+	}, cfg.KServe.AdaptiveTimeout)
 }
 
 func TestLoad_FromEnvironment(t *testing.T) {
@@ -60,6 +68,13 @@ func TestLoad_FromEnvironment(t *testing.T) {
 	os.Setenv("KSERVE_ANOMALY_DETECTOR_SERVICE", "anomaly-detector-predictor")
 	os.Setenv("KSERVE_PREDICTIVE_ANALYTICS_SERVICE", "predictive-analytics-predictor")
 	os.Setenv("KSERVE_TIMEOUT", "15s")
+	os.Setenv("KSERVE_MODEL_ROLLOUT_FILE", "/etc/coordination-engine/rollouts.yaml")
+	os.Setenv("KSERVE_PREFLIGHT_ENABLED", "false")
+	os.Setenv("KSERVE_ADAPTIVE_TIMEOUT_FACTOR", "4")
+	os.Setenv("KSERVE_ADAPTIVE_TIMEOUT_MAX", "30s")
+	os.Setenv("KSERVE_INFER_ENABLED", "true")
+	os.Setenv("KSERVE_INFER_TOKEN", "infer-secret")
+	os.Setenv("KSERVE_INFER_RATE_LIMIT", "2.5")
 	defer clearEnv(t)
 
 	cfg, err := Load()
@@ -83,6 +98,15 @@ func TestLoad_FromEnvironment(t *testing.T) {
 	assert.Equal(t, "anomaly-detector-predictor", cfg.KServe.Services.AnomalyDetector)
 	assert.Equal(t, "predictive-analytics-predictor", cfg.KServe.Services.PredictiveAnalytics)
 	assert.Equal(t, 15*time.Second, cfg.KServe.Timeout)
+	assert.Equal(t, "/etc/coordination-engine/rollouts.yaml", cfg.KServe.RolloutFile)
+	assert.Empty(t, cfg.KServe.DynamicServices, "rollout file is not a model service")
+	assert.False(t, cfg.KServe.PreflightEnabled)
+	assert.Equal(t, 4.0, cfg.KServe.AdaptiveTimeout.Factor)
+	assert.Equal(t, 30*time.Second, cfg.KServe.AdaptiveTimeout.Max)
+	assert.True(t, cfg.KServe.Infer.Enabled)
+	assert.Equal(t, "infer-secret", cfg.KServe.Infer.Token)
+	assert.Equal(t, 2.5, cfg.KServe.Infer.RateLimit)
+	assert.Equal(t, DefaultKServeInferBurst, cfg.KServe.Infer.Burst)
 }
 
 func TestLoad_FromEnvironment_LegacyML(t *testing.T) {
@@ -187,6 +211,48 @@ func TestValidate_InvalidPort(t *testing.T) {
 	}
 }
 
+func TestValidate_GRPCPort(t *testing.T) {
+	tests := []struct {
+		name      string
+		grpcPort  int
+		wantError bool
+	}{
+		{"disabled", 0, false},
+		{"valid port", 50051, false},
+		{"negative", -1, true},
+		{"too high", 70000, true},
+		{"same as port", 8080, true},
+		{"same as metrics port", 9090, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				Port:            8080,
+				MetricsPort:     9090,
+				GRPCPort:        tt.grpcPort,
+				LogLevel:        "info",
+				Namespace:       "default",
+				HTTPTimeout:     30 * time.Second,
+				KubernetesQPS:   50.0,
+				KubernetesBurst: 100,
+				KServe: KServeConfig{
+					Enabled:   true,
+					Namespace: "default",
+					Services:  KServeServices{AnomalyDetector: "anomaly-detector"},
+					Timeout:   10 * time.Second,
+				},
+			}
+			err := cfg.Validate()
+			if tt.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestValidate_InvalidLogLevel(t *testing.T) {
 	cfg := &Config{
 		Port:            8080,
@@ -505,6 +571,11 @@ func clearEnv(t *testing.T) {
 		"ENABLE_KSERVE_INTEGRATION", "KSERVE_NAMESPACE", "KSERVE_PREDICTOR_PORT",
 		"KSERVE_ANOMALY_DETECTOR_SERVICE", "KSERVE_PREDICTIVE_ANALYTICS_SERVICE",
 		"KSERVE_TIMEOUT",
+		"KSERVE_MODEL_ROLLOUT_FILE", "KSERVE_PREFLIGHT_ENABLED",
+		"KSERVE_ADAPTIVE_TIMEOUT_ENABLED", "KSERVE_ADAPTIVE_TIMEOUT_FACTOR",
+		"KSERVE_ADAPTIVE_TIMEOUT_MIN", "KSERVE_ADAPTIVE_TIMEOUT_MAX",
+		"KSERVE_INFER_ENABLED", "KSERVE_INFER_TOKEN", "KSERVE_INFER_RATE_LIMIT",
+		"KSERVE_INFER_BURST", "KSERVE_INFER_MAX_BODY_BYTES",
 	}
 	for _, key := range envVars {
 		os.Unsetenv(key)
@@ -603,6 +674,53 @@ func TestKServeConfig_Validation(t *testing.T) {
 			wantError: true,
 			errorMsg:  "kserve.timeout too long",
 		},
+		{
+			name: "adaptive timeout factor below one",
+			kserve: KServeConfig{
+				Enabled:         true,
+				Namespace:       "default",
+				Services:        KServeServices{AnomalyDetector: "anomaly-detector"},
+				Timeout:         10 * time.Second,
+				AdaptiveTimeout: KServeAdaptiveTimeoutConfig{Enabled: true, Factor: 0.5, Min: time.Second, Max: time.Minute},
+			},
+			wantError: true,
+			errorMsg:  "kserve.adaptive_timeout.factor must be >= 1",
+		},
+		{
+			name: "adaptive timeout min above max",
+			kserve: KServeConfig{
+				Enabled:         true,
+				Namespace:       "default",
+				Services:        KServeServices{AnomalyDetector: "anomaly-detector"},
+				Timeout:         10 * time.Second,
+				AdaptiveTimeout: KServeAdaptiveTimeoutConfig{Enabled: true, Factor: 3, Min: 2 * time.Minute, Max: time.Minute},
+			},
+			wantError: true,
+			errorMsg:  "kserve.adaptive_timeout.min (2m0s) must be positive and not exceed max (1m0s)",
+		},
+		{
+			name: "inference gateway without authentication",
+			kserve: KServeConfig{
+				Enabled:   true,
+				Namespace: "default",
+				Services:  KServeServices{AnomalyDetector: "anomaly-detector"},
+				Timeout:   10 * time.Second,
+				Infer:     KServeInferConfig{Enabled: true, RateLimit: 10, Burst: 20, MaxBodyBytes: 1024},
+			},
+			wantError: true,
+			errorMsg:  "kserve.infer requires KSERVE_INFER_TOKEN or mutual TLS",
+		},
+		{
+			name: "inference gateway with token",
+			kserve: KServeConfig{
+				Enabled:   true,
+				Namespace: "default",
+				Services:  KServeServices{AnomalyDetector: "anomaly-detector"},
+				Timeout:   10 * time.Second,
+				Infer:     KServeInferConfig{Enabled: true, Token: "secret", RateLimit: 10, Burst: 20, MaxBodyBytes: 1024},
+			},
+			wantError: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -1009,3 +1127,1395 @@ func TestValidate_WithDynamicServicesOnly(t *testing.T) {
 	err := cfg.Validate()
 	assert.NoError(t, err)
 }
+
+func TestGetEnvAsIntMap(t *testing.T) {
+	os.Setenv("TEST_INT_MAP", "production=5, production/api=2,bad,dev=x")
+	defer os.Unsetenv("TEST_INT_MAP")
+
+	result := getEnvAsIntMap("TEST_INT_MAP")
+	assert.Equal(t, map[string]int{"production": 5, "production/api": 2}, result)
+
+	assert.Empty(t, getEnvAsIntMap("TEST_INT_MAP_UNSET"))
+}
+
+func TestLoad_AnomalyNoiseReduction(t *testing.T) {
+	os.Setenv("KSERVE_ANOMALY_DETECTOR_SERVICE", "anomaly-detector-predictor")
+	os.Setenv("ANOMALY_PERSISTENCE_EVALUATIONS", "4")
+	os.Setenv("ANOMALY_PERSISTENCE_OVERRIDES", "production=6")
+	os.Setenv("ANOMALY_CLEAR_THRESHOLD", "0.4")
+	os.Setenv("ANOMALY_SCOPE_DEFAULTS_FILE", "/etc/coordination-engine/scope-defaults.yaml")
+	os.Setenv("ANOMALY_CALENDARS_FILE", "/etc/coordination-engine/calendars.yaml")
+	os.Setenv("ANOMALY_POST_FILTERS_FILE", "/etc/coordination-engine/post-filters.yaml")
+	os.Setenv("ANOMALY_HISTOGRAM_FEATURES", "api_latency_p99=http_request_duration_seconds:0.99, db_p90=db_query_seconds:0.9")
+	os.Setenv("ANOMALY_HISTOGRAM_MODELS", "anomaly-latency")
+	defer func() {
+		os.Unsetenv("KSERVE_ANOMALY_DETECTOR_SERVICE")
+		os.Unsetenv("ANOMALY_PERSISTENCE_EVALUATIONS")
+		os.Unsetenv("ANOMALY_PERSISTENCE_OVERRIDES")
+		os.Unsetenv("ANOMALY_CLEAR_THRESHOLD")
+		os.Unsetenv("ANOMALY_SCOPE_DEFAULTS_FILE")
+		os.Unsetenv("ANOMALY_CALENDARS_FILE")
+		os.Unsetenv("ANOMALY_POST_FILTERS_FILE")
+		os.Unsetenv("ANOMALY_HISTOGRAM_FEATURES")
+		os.Unsetenv("ANOMALY_HISTOGRAM_MODELS")
+	}()
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, 4, cfg.Anomaly.PersistenceEvaluations)
+	assert.Equal(t, map[string]int{"production": 6}, cfg.Anomaly.PersistenceOverrides)
+	assert.Equal(t, 0.4, cfg.Anomaly.ClearThreshold)
+	assert.Equal(t, DefaultAnomalyClearEvaluations, cfg.Anomaly.ClearEvaluations)
+	assert.Equal(t, "/etc/coordination-engine/scope-defaults.yaml", cfg.Anomaly.ScopeDefaultsFile)
+	assert.Equal(t, "/etc/coordination-engine/calendars.yaml", cfg.Anomaly.CalendarsFile)
+	assert.Equal(t, "/etc/coordination-engine/post-filters.yaml", cfg.Anomaly.PostFiltersFile)
+	assert.Equal(t, []string{"api_latency_p99=http_request_duration_seconds:0.99", "db_p90=db_query_seconds:0.9"}, cfg.Anomaly.HistogramFeatures)
+	assert.Equal(t, []string{"anomaly-latency"}, cfg.Anomaly.HistogramModels)
+}
+
+func TestValidate_AnomalyNoiseReduction(t *testing.T) {
+	cfg := &Config{
+		Port:            8080,
+		MetricsPort:     9090,
+		LogLevel:        "info",
+		Namespace:       "default",
+		HTTPTimeout:     30 * time.Second,
+		KubernetesQPS:   50.0,
+		KubernetesBurst: 100,
+		Anomaly: AnomalyConfig{
+			PersistenceEvaluations: -1,
+			PersistenceOverrides:   map[string]int{"production": 0},
+			ClearThreshold:         1.5,
+			HistogramFeatures:      []string{"api_latency_p99=http_request_duration_seconds:0.99"},
+		},
+	}
+
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "anomaly.persistence_evaluations cannot be negative")
+	assert.Contains(t, err.Error(), "anomaly.persistence_overrides[production] must be >= 1")
+	assert.Contains(t, err.Error(), "anomaly.clear_threshold must be between 0.0 and 1.0")
+	assert.Contains(t, err.Error(), "anomaly.histogram_features and anomaly.histogram_models must be set together")
+}
+
+func TestLoad_AnomalyInactivePods(t *testing.T) {
+	os.Setenv("KSERVE_ANOMALY_DETECTOR_SERVICE", "anomaly-detector-predictor")
+	defer os.Unsetenv("KSERVE_ANOMALY_DETECTOR_SERVICE")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.True(t, cfg.Anomaly.ExcludeInactivePods)
+	assert.Equal(t, DefaultAnomalyMaxScopePods, cfg.Anomaly.MaxScopePods)
+
+	os.Setenv("ANOMALY_MAX_SCOPE_PODS", "0")
+	defer os.Unsetenv("ANOMALY_MAX_SCOPE_PODS")
+	_, err = Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "anomaly.max_scope_pods must be at least 1")
+
+	os.Setenv("ANOMALY_EXCLUDE_INACTIVE_PODS", "false")
+	defer os.Unsetenv("ANOMALY_EXCLUDE_INACTIVE_PODS")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.False(t, cfg.Anomaly.ExcludeInactivePods)
+}
+
+func TestLoad_AnomalyRouteProbes(t *testing.T) {
+	os.Setenv("KSERVE_ANOMALY_DETECTOR_SERVICE", "anomaly-detector-predictor")
+	os.Setenv("ANOMALY_ROUTE_PROBE_MODELS", "anomaly-external")
+	defer func() {
+		os.Unsetenv("KSERVE_ANOMALY_DETECTOR_SERVICE")
+		os.Unsetenv("ANOMALY_ROUTE_PROBE_MODELS")
+	}()
+
+	_, err := Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "anomaly.route_probe_models requires anomaly.route_probes")
+
+	os.Setenv("ANOMALY_ROUTE_PROBES", "true")
+	defer os.Unsetenv("ANOMALY_ROUTE_PROBES")
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.True(t, cfg.Anomaly.RouteProbes)
+	assert.Equal(t, []string{"anomaly-external"}, cfg.Anomaly.RouteProbeModels)
+}
+
+func TestLoad_AnomalyControlPlaneModel(t *testing.T) {
+	os.Setenv("KSERVE_ANOMALY_DETECTOR_SERVICE", "anomaly-detector-predictor")
+	defer os.Unsetenv("KSERVE_ANOMALY_DETECTOR_SERVICE")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, DefaultAnomalyControlPlaneModel, cfg.Anomaly.ControlPlaneModel)
+
+	os.Setenv("ANOMALY_CONTROL_PLANE_MODEL", "control-plane-v2")
+	defer os.Unsetenv("ANOMALY_CONTROL_PLANE_MODEL")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, "control-plane-v2", cfg.Anomaly.ControlPlaneModel)
+}
+
+func TestLoad_AnomalyScoringHooks(t *testing.T) {
+	os.Setenv("KSERVE_ANOMALY_DETECTOR_SERVICE", "anomaly-detector-predictor")
+	os.Setenv("ANOMALY_SCORING_HOOKS", "baseline,maintenance")
+	os.Setenv("ANOMALY_MAINTENANCE_NAMESPACES", "[payments")
+	defer func() {
+		os.Unsetenv("KSERVE_ANOMALY_DETECTOR_SERVICE")
+		os.Unsetenv("ANOMALY_SCORING_HOOKS")
+		os.Unsetenv("ANOMALY_MAINTENANCE_NAMESPACES")
+	}()
+
+	_, err := Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `anomaly.maintenance_namespaces has an invalid pattern: "[payments"`)
+
+	os.Setenv("ANOMALY_MAINTENANCE_NAMESPACES", "payments-*,orders")
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"baseline", "maintenance"}, cfg.Anomaly.ScoringHooks)
+	assert.Equal(t, []string{"payments-*", "orders"}, cfg.Anomaly.MaintenanceNamespaces)
+}
+
+func TestLoad_PlatformChecks(t *testing.T) {
+	os.Setenv("KSERVE_ANOMALY_DETECTOR_SERVICE", "anomaly-detector-predictor")
+	defer os.Unsetenv("KSERVE_ANOMALY_DETECTOR_SERVICE")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.True(t, cfg.Platform.Enabled)
+	assert.Equal(t, DefaultPlatformDNSErrorRateThreshold, cfg.Platform.DNSErrorRateThreshold)
+	assert.Equal(t, DefaultPlatformCertificateExpiryWarningDays, cfg.Platform.CertificateExpiryWarningDays)
+	assert.Empty(t, cfg.Platform.CertificateNamespaces)
+	assert.Equal(t, DefaultPlatformEtcdQuotaGiB, cfg.Platform.EtcdQuotaGiB)
+	assert.Equal(t, DefaultPlatformEtcdQuotaWarningDays, cfg.Platform.EtcdQuotaWarningDays)
+
+	os.Setenv("PLATFORM_CERT_NAMESPACES", "openshift-ingress,payments")
+	os.Setenv("PLATFORM_CERT_EXPIRY_WARNING_DAYS", "0")
+	os.Setenv("PLATFORM_DNS_ERROR_RATE_THRESHOLD", "1.5")
+	os.Setenv("PLATFORM_ETCD_QUOTA_GIB", "0")
+	os.Setenv("PLATFORM_ETCD_QUOTA_WARNING_DAYS", "0")
+	defer func() {
+		os.Unsetenv("PLATFORM_CERT_NAMESPACES")
+		os.Unsetenv("PLATFORM_CERT_EXPIRY_WARNING_DAYS")
+		os.Unsetenv("PLATFORM_DNS_ERROR_RATE_THRESHOLD")
+		os.Unsetenv("PLATFORM_ETCD_QUOTA_GIB")
+		os.Unsetenv("PLATFORM_ETCD_QUOTA_WARNING_DAYS")
+	}()
+	_, err = Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "platform.dns_error_rate_threshold must be between 0.0 (exclusive) and 1.0")
+	assert.Contains(t, err.Error(), "platform.certificate_expiry_warning_days must be at least 1")
+	assert.Contains(t, err.Error(), "platform.etcd_quota_gib must be at least 1")
+	assert.Contains(t, err.Error(), "platform.etcd_quota_warning_days must be at least 1")
+
+	os.Setenv("PLATFORM_CHECKS_ENABLED", "false")
+	defer os.Unsetenv("PLATFORM_CHECKS_ENABLED")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.False(t, cfg.Platform.Enabled)
+	assert.Equal(t, []string{"openshift-ingress", "payments"}, cfg.Platform.CertificateNamespaces)
+}
+
+func TestLoad_SchedulerPressure(t *testing.T) {
+	os.Setenv("KSERVE_ANOMALY_DETECTOR_SERVICE", "anomaly-detector-predictor")
+	defer os.Unsetenv("KSERVE_ANOMALY_DETECTOR_SERVICE")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.True(t, cfg.SchedulerPressure.Enabled)
+	assert.Equal(t, DefaultSchedulerPressurePendingThreshold, cfg.SchedulerPressure.PendingThreshold)
+	assert.Equal(t, DefaultSchedulerPressureUnschedulableThreshold, cfg.SchedulerPressure.UnschedulableThreshold)
+	assert.Equal(t, DefaultSchedulerPressureWindow, cfg.SchedulerPressure.Window)
+
+	os.Setenv("SCHEDULER_PRESSURE_PENDING_THRESHOLD", "0")
+	os.Setenv("SCHEDULER_PRESSURE_UNSCHEDULABLE_THRESHOLD", "0")
+	os.Setenv("SCHEDULER_PRESSURE_WINDOW", "30s")
+	defer func() {
+		os.Unsetenv("SCHEDULER_PRESSURE_PENDING_THRESHOLD")
+		os.Unsetenv("SCHEDULER_PRESSURE_UNSCHEDULABLE_THRESHOLD")
+		os.Unsetenv("SCHEDULER_PRESSURE_WINDOW")
+	}()
+	_, err = Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "scheduler_pressure.pending_threshold must be at least 1")
+	assert.Contains(t, err.Error(), "scheduler_pressure.unschedulable_threshold must be at least 1")
+	assert.Contains(t, err.Error(), "scheduler_pressure.window must be at least 1m")
+
+	os.Setenv("SCHEDULER_PRESSURE_ENABLED", "false")
+	defer os.Unsetenv("SCHEDULER_PRESSURE_ENABLED")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.False(t, cfg.SchedulerPressure.Enabled)
+}
+
+func TestLoad_ImageScan(t *testing.T) {
+	os.Setenv("KSERVE_ANOMALY_DETECTOR_SERVICE", "anomaly-detector-predictor")
+	defer os.Unsetenv("KSERVE_ANOMALY_DETECTOR_SERVICE")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Empty(t, cfg.ImageScan.QuayURL)
+	assert.Equal(t, DefaultImageScanMinRestarts, cfg.ImageScan.MinRestarts)
+	assert.Equal(t, DefaultImageScanCacheTTL, cfg.ImageScan.CacheTTL)
+
+	os.Setenv("IMAGE_SCAN_QUAY_URL", "quay.io")
+	os.Setenv("IMAGE_SCAN_MIN_RESTARTS", "0")
+	os.Setenv("IMAGE_SCAN_CACHE_TTL", "10s")
+	defer func() {
+		os.Unsetenv("IMAGE_SCAN_QUAY_URL")
+		os.Unsetenv("IMAGE_SCAN_MIN_RESTARTS")
+		os.Unsetenv("IMAGE_SCAN_CACHE_TTL")
+	}()
+	_, err = Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "image_scan.quay_url must start with http:// or https://")
+	assert.Contains(t, err.Error(), "image_scan.min_restarts must be at least 1")
+	assert.Contains(t, err.Error(), "image_scan.cache_ttl must be at least 1m")
+
+	os.Setenv("IMAGE_SCAN_QUAY_URL", "https://quay.io")
+	os.Setenv("IMAGE_SCAN_QUAY_TOKEN", "secret")
+	os.Setenv("IMAGE_SCAN_MIN_RESTARTS", "5")
+	os.Setenv("IMAGE_SCAN_CACHE_TTL", "30m")
+	defer os.Unsetenv("IMAGE_SCAN_QUAY_TOKEN")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, "https://quay.io", cfg.ImageScan.QuayURL)
+	assert.Equal(t, "secret", cfg.ImageScan.QuayToken)
+	assert.Equal(t, 5, cfg.ImageScan.MinRestarts)
+	assert.Equal(t, 30*time.Minute, cfg.ImageScan.CacheTTL)
+}
+
+func TestLoad_Similarity(t *testing.T) {
+	os.Setenv("KSERVE_ANOMALY_DETECTOR_SERVICE", "anomaly-detector-predictor")
+	defer os.Unsetenv("KSERVE_ANOMALY_DETECTOR_SERVICE")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.True(t, cfg.Similarity.Enabled)
+	assert.Equal(t, DefaultSimilarityLimit, cfg.Similarity.Limit)
+	assert.Equal(t, DefaultSimilarityMinScore, cfg.Similarity.MinScore)
+
+	os.Setenv("INCIDENT_SIMILARITY_LIMIT", "50")
+	os.Setenv("INCIDENT_SIMILARITY_MIN_SCORE", "0")
+	defer func() {
+		os.Unsetenv("INCIDENT_SIMILARITY_LIMIT")
+		os.Unsetenv("INCIDENT_SIMILARITY_MIN_SCORE")
+	}()
+	_, err = Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "similarity.limit must be between 1 and 20")
+	assert.Contains(t, err.Error(), "similarity.min_score must be between 0.0 (exclusive) and 1.0")
+
+	os.Setenv("INCIDENT_SIMILARITY_ENABLED", "false")
+	defer os.Unsetenv("INCIDENT_SIMILARITY_ENABLED")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.False(t, cfg.Similarity.Enabled)
+}
+
+func TestLoad_ActionRanking(t *testing.T) {
+	os.Setenv("KSERVE_ANOMALY_DETECTOR_SERVICE", "anomaly-detector-predictor")
+	defer os.Unsetenv("KSERVE_ANOMALY_DETECTOR_SERVICE")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.True(t, cfg.ActionRanking.Enabled)
+	assert.Equal(t, DefaultActionRankingMinAttempts, cfg.ActionRanking.MinAttempts)
+	assert.Equal(t, DefaultActionOutcomeHistory, cfg.ActionRanking.HistoryLimit)
+
+	os.Setenv("ACTION_RANKING_MIN_ATTEMPTS", "0")
+	defer os.Unsetenv("ACTION_RANKING_MIN_ATTEMPTS")
+	_, err = Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "action_ranking.min_attempts must be at least 1")
+
+	os.Setenv("ACTION_RANKING_ENABLED", "false")
+	defer os.Unsetenv("ACTION_RANKING_ENABLED")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.False(t, cfg.ActionRanking.Enabled)
+}
+
+func TestLoad_Artifacts(t *testing.T) {
+	os.Setenv("KSERVE_ANOMALY_DETECTOR_SERVICE", "anomaly-detector-predictor")
+	defer os.Unsetenv("KSERVE_ANOMALY_DETECTOR_SERVICE")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.True(t, cfg.Artifacts.Enabled)
+	assert.Equal(t, DefaultArtifactsPendingTTL, cfg.Artifacts.PendingTTL)
+	assert.Equal(t, DefaultArtifactsPendingLimit, cfg.Artifacts.PendingLimit)
+
+	os.Setenv("ANALYSIS_ARTIFACTS_PENDING_TTL", "30s")
+	defer os.Unsetenv("ANALYSIS_ARTIFACTS_PENDING_TTL")
+	_, err = Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "artifacts.pending_ttl must be at least 1m")
+
+	os.Setenv("ANALYSIS_ARTIFACTS_ENABLED", "false")
+	defer os.Unsetenv("ANALYSIS_ARTIFACTS_ENABLED")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.False(t, cfg.Artifacts.Enabled)
+}
+
+func TestLoad_RequestTimeouts(t *testing.T) {
+	os.Setenv("KSERVE_ANOMALY_DETECTOR_SERVICE", "anomaly-detector-predictor")
+	defer os.Unsetenv("KSERVE_ANOMALY_DETECTOR_SERVICE")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, DefaultRequestTimeout, cfg.RequestTimeout)
+	assert.Equal(t, DefaultAnalysisTimeout, cfg.AnalysisRequestTimeout)
+
+	os.Setenv("ANALYSIS_REQUEST_TIMEOUT", "10s")
+	defer os.Unsetenv("ANALYSIS_REQUEST_TIMEOUT")
+	_, err = Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "analysis_request_timeout must be at least request_timeout")
+}
+
+func TestLoad_AnalysisSLO(t *testing.T) {
+	os.Setenv("KSERVE_ANOMALY_DETECTOR_SERVICE", "anomaly-detector-predictor")
+	defer os.Unsetenv("KSERVE_ANOMALY_DETECTOR_SERVICE")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.True(t, cfg.AnalysisSLO.Enabled)
+	assert.Equal(t, DefaultAnalysisSLOLatency, cfg.AnalysisSLO.Latency)
+	assert.Equal(t, DefaultAnalysisSLOObjective, cfg.AnalysisSLO.Objective)
+	assert.Equal(t, "auto", cfg.AnalysisSLO.FastPath)
+
+	os.Setenv("ANALYSIS_SLO_LATENCY", "2s")
+	defer os.Unsetenv("ANALYSIS_SLO_LATENCY")
+	os.Setenv("ANALYSIS_SLO_FAST_PATH", "always")
+	defer os.Unsetenv("ANALYSIS_SLO_FAST_PATH")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, 2*time.Second, cfg.AnalysisSLO.Latency)
+	assert.Equal(t, "always", cfg.AnalysisSLO.FastPath)
+
+	os.Setenv("ANALYSIS_SLO_FAST_PATH", "sometimes")
+	os.Setenv("ANALYSIS_SLO_OBJECTIVE", "1")
+	defer os.Unsetenv("ANALYSIS_SLO_OBJECTIVE")
+	os.Setenv("ANALYSIS_SLO_WINDOW", "5m")
+	defer os.Unsetenv("ANALYSIS_SLO_WINDOW")
+	_, err = Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "analysis_slo.fast_path must be auto, always or never")
+	assert.Contains(t, err.Error(), "analysis_slo.objective must be in (0, 1)")
+	assert.Contains(t, err.Error(), "analysis_slo.window must be at least 12m")
+
+	os.Setenv("ANALYSIS_SLO_ENABLED", "false")
+	defer os.Unsetenv("ANALYSIS_SLO_ENABLED")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.False(t, cfg.AnalysisSLO.Enabled)
+}
+
+func TestLoad_RecommendationRules(t *testing.T) {
+	os.Setenv("KSERVE_ANOMALY_DETECTOR_SERVICE", "anomaly-detector-predictor")
+	defer os.Unsetenv("KSERVE_ANOMALY_DETECTOR_SERVICE")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Empty(t, cfg.RecommendationRules.File)
+	assert.Equal(t, DefaultRecommendationRulesReloadInterval, cfg.RecommendationRules.ReloadInterval)
+
+	os.Setenv("RECOMMENDATION_RULES_FILE", "/etc/coordination-engine/recommendation-rules.yaml")
+	defer os.Unsetenv("RECOMMENDATION_RULES_FILE")
+	os.Setenv("RECOMMENDATION_RULES_RELOAD_INTERVAL", "100ms")
+	defer os.Unsetenv("RECOMMENDATION_RULES_RELOAD_INTERVAL")
+	_, err = Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "recommendation_rules.reload_interval must be at least 1s")
+
+	os.Setenv("RECOMMENDATION_RULES_RELOAD_INTERVAL", "1m")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, cfg.RecommendationRules.ReloadInterval)
+}
+
+func TestLoad_MetricsQuery(t *testing.T) {
+	os.Setenv("KSERVE_ANOMALY_DETECTOR_SERVICE", "anomaly-detector-predictor")
+	defer os.Unsetenv("KSERVE_ANOMALY_DETECTOR_SERVICE")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.True(t, cfg.MetricsQuery.Enabled)
+	assert.Equal(t, DefaultMetricsQueryRateLimit, cfg.MetricsQuery.RateLimit)
+	assert.Equal(t, DefaultMetricsQueryMaxRange, cfg.MetricsQuery.MaxRange)
+
+	os.Setenv("METRICS_QUERY_MAX_RANGE", "24h")
+	defer os.Unsetenv("METRICS_QUERY_MAX_RANGE")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, 24*time.Hour, cfg.MetricsQuery.MaxRange)
+
+	os.Setenv("METRICS_QUERY_RATE_LIMIT", "0")
+	defer os.Unsetenv("METRICS_QUERY_RATE_LIMIT")
+	os.Setenv("METRICS_QUERY_MAX_RANGE", "10m")
+	_, err = Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "metrics_query.rate_limit must be positive")
+	assert.Contains(t, err.Error(), "metrics_query.max_range must be at least 1h")
+
+	os.Setenv("METRICS_QUERY_ENABLED", "false")
+	defer os.Unsetenv("METRICS_QUERY_ENABLED")
+	_, err = Load()
+	require.NoError(t, err)
+}
+
+func TestLoad_Startup(t *testing.T) {
+	os.Setenv("KSERVE_ANOMALY_DETECTOR_SERVICE", "anomaly-detector-predictor")
+	defer os.Unsetenv("KSERVE_ANOMALY_DETECTOR_SERVICE")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, "wait", cfg.Startup.Prometheus)
+	assert.Equal(t, "degraded", cfg.Startup.KServe)
+	assert.Equal(t, "fail", cfg.Startup.Storage)
+	assert.Equal(t, DefaultStartupWaitTimeout, cfg.Startup.WaitTimeout)
+
+	os.Setenv("STARTUP_STORAGE", "degraded")
+	defer os.Unsetenv("STARTUP_STORAGE")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, "degraded", cfg.Startup.Storage)
+
+	os.Setenv("STARTUP_KSERVE", "retry")
+	defer os.Unsetenv("STARTUP_KSERVE")
+	os.Setenv("STARTUP_BACKOFF", "1m")
+	defer os.Unsetenv("STARTUP_BACKOFF")
+	_, err = Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "startup.kserve must be wait, degraded or fail: retry")
+	assert.Contains(t, err.Error(), "startup.backoff must be positive and at most startup.wait_timeout")
+}
+
+func TestLoad_LoadShed(t *testing.T) {
+	os.Setenv("KSERVE_ANOMALY_DETECTOR_SERVICE", "anomaly-detector-predictor")
+	defer os.Unsetenv("KSERVE_ANOMALY_DETECTOR_SERVICE")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.True(t, cfg.LoadShed.Enabled)
+	assert.Equal(t, DefaultLoadShedCPUThreshold, cfg.LoadShed.CPUThreshold)
+	assert.Equal(t, DefaultLoadShedRetryAfter, cfg.LoadShed.RetryAfter)
+
+	os.Setenv("LOAD_SHED_MEMORY_THRESHOLD", "1.5")
+	defer os.Unsetenv("LOAD_SHED_MEMORY_THRESHOLD")
+	_, err = Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "load_shed.memory_threshold must be in (0, 1]")
+
+	os.Setenv("LOAD_SHED_ENABLED", "false")
+	defer os.Unsetenv("LOAD_SHED_ENABLED")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.False(t, cfg.LoadShed.Enabled)
+}
+
+func TestLoad_SelfMonitor(t *testing.T) {
+	os.Setenv("KSERVE_ANOMALY_DETECTOR_SERVICE", "anomaly-detector-predictor")
+	defer os.Unsetenv("KSERVE_ANOMALY_DETECTOR_SERVICE")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.True(t, cfg.SelfMonitor.Enabled)
+	assert.Equal(t, DefaultSelfMonitorInterval, cfg.SelfMonitor.Interval)
+	assert.Equal(t, DefaultSelfMonitorErrorRateThreshold, cfg.SelfMonitor.ErrorRateThreshold)
+
+	os.Setenv("SELF_MONITOR_QUEUE_DEPTH_THRESHOLD", "20")
+	defer os.Unsetenv("SELF_MONITOR_QUEUE_DEPTH_THRESHOLD")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, 20, cfg.SelfMonitor.QueueDepthThreshold)
+
+	os.Setenv("SELF_MONITOR_CACHE_COLLAPSE_RATIO", "1")
+	defer os.Unsetenv("SELF_MONITOR_CACHE_COLLAPSE_RATIO")
+	_, err = Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "self_monitor.cache_collapse_ratio must be in (0, 1)")
+
+	os.Setenv("SELF_MONITOR_ENABLED", "false")
+	defer os.Unsetenv("SELF_MONITOR_ENABLED")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.False(t, cfg.SelfMonitor.Enabled)
+}
+
+func TestLoad_FeatureFlags(t *testing.T) {
+	os.Setenv("KSERVE_ANOMALY_DETECTOR_SERVICE", "anomaly-detector-predictor")
+	defer os.Unsetenv("KSERVE_ANOMALY_DETECTOR_SERVICE")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Empty(t, cfg.FeatureFlags.File)
+	assert.Empty(t, cfg.FeatureFlags.Flags)
+
+	os.Setenv("FEATURE_FLAGS_FILE", "/etc/coordination-engine/flags.yaml")
+	defer os.Unsetenv("FEATURE_FLAGS_FILE")
+	os.Setenv("FEATURE_FLAGS", "auto_remediation=false, model_canary=true, route_features=maybe")
+	defer os.Unsetenv("FEATURE_FLAGS")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, "/etc/coordination-engine/flags.yaml", cfg.FeatureFlags.File)
+	assert.Equal(t, map[string]bool{"auto_remediation": false, "model_canary": true}, cfg.FeatureFlags.Flags)
+}
+
+func TestLoad_Deterministic(t *testing.T) {
+	os.Setenv("KSERVE_ANOMALY_DETECTOR_SERVICE", "anomaly-detector-predictor")
+	defer os.Unsetenv("KSERVE_ANOMALY_DETECTOR_SERVICE")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.False(t, cfg.Deterministic.Enabled())
+	assert.Equal(t, DefaultDeterministicSeed, cfg.Deterministic.Seed)
+
+	os.Setenv("DETERMINISTIC_TIME", "2024-03-04T10:00:00+01:00")
+	defer os.Unsetenv("DETERMINISTIC_TIME")
+	os.Setenv("DETERMINISTIC_SEED", "42")
+	defer os.Unsetenv("DETERMINISTIC_SEED")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.True(t, cfg.Deterministic.Enabled())
+	assert.Equal(t, time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC), cfg.Deterministic.Start())
+	assert.Equal(t, 42, cfg.Deterministic.Seed)
+
+	os.Setenv("DETERMINISTIC_TIME", "2024-03-04 10:00")
+	_, err = Load()
+	assert.ErrorContains(t, err, "deterministic.time must be an RFC 3339 time")
+}
+
+func TestLoad_SeverityMatrix(t *testing.T) {
+	os.Setenv("KSERVE_ANOMALY_DETECTOR_SERVICE", "anomaly-detector-predictor")
+	defer os.Unsetenv("KSERVE_ANOMALY_DETECTOR_SERVICE")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Empty(t, cfg.Severity.MatrixFile)
+
+	os.Setenv("SEVERITY_MATRIX_FILE", "/etc/coordination-engine/severity.yaml")
+	defer os.Unsetenv("SEVERITY_MATRIX_FILE")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, "/etc/coordination-engine/severity.yaml", cfg.Severity.MatrixFile)
+}
+
+func TestLoad_APITokens(t *testing.T) {
+	os.Setenv("KSERVE_ANOMALY_DETECTOR_SERVICE", "anomaly-detector-predictor")
+	defer os.Unsetenv("KSERVE_ANOMALY_DETECTOR_SERVICE")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.True(t, cfg.APITokens.Enabled)
+	assert.False(t, cfg.APITokens.Required)
+
+	os.Setenv("API_TOKENS_REQUIRED", "true")
+	defer os.Unsetenv("API_TOKENS_REQUIRED")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.True(t, cfg.APITokens.Required)
+
+	os.Setenv("API_TOKENS_ENABLED", "false")
+	defer os.Unsetenv("API_TOKENS_ENABLED")
+	_, err = Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "api_tokens.required needs api_tokens.enabled")
+}
+
+func TestLoad_RuntimeConfig(t *testing.T) {
+	os.Setenv("KSERVE_ANOMALY_DETECTOR_SERVICE", "anomaly-detector-predictor")
+	defer os.Unsetenv("KSERVE_ANOMALY_DETECTOR_SERVICE")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.True(t, cfg.RuntimeConfig.Enabled)
+
+	os.Setenv("RUNTIME_CONFIG_ENABLED", "false")
+	defer os.Unsetenv("RUNTIME_CONFIG_ENABLED")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.False(t, cfg.RuntimeConfig.Enabled)
+}
+
+func TestLoad_Workflow(t *testing.T) {
+	os.Setenv("KSERVE_ANOMALY_DETECTOR_SERVICE", "anomaly-detector-predictor")
+	defer os.Unsetenv("KSERVE_ANOMALY_DETECTOR_SERVICE")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, DefaultWorkflowMaxAttempts, cfg.Workflow.MaxAttempts)
+	assert.Equal(t, DefaultWorkflowRetryBackoff, cfg.Workflow.RetryBackoff)
+	assert.Zero(t, cfg.Workflow.VerifyTimeout)
+
+	os.Setenv("WORKFLOW_MAX_ATTEMPTS", "3")
+	os.Setenv("WORKFLOW_VERIFY_TIMEOUT", "5m")
+	defer func() {
+		os.Unsetenv("WORKFLOW_MAX_ATTEMPTS")
+		os.Unsetenv("WORKFLOW_VERIFY_TIMEOUT")
+	}()
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, 3, cfg.Workflow.MaxAttempts)
+	assert.Equal(t, 5*time.Minute, cfg.Workflow.VerifyTimeout)
+
+	os.Setenv("WORKFLOW_MAX_ATTEMPTS", "11")
+	_, err = Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "workflow.max_attempts must be between 1 and 10")
+}
+
+func TestLoad_Execution(t *testing.T) {
+	os.Setenv("KSERVE_ANOMALY_DETECTOR_SERVICE", "anomaly-detector-predictor")
+	defer os.Unsetenv("KSERVE_ANOMALY_DETECTOR_SERVICE")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Empty(t, cfg.Execution.Backend)
+	assert.Equal(t, DefaultExecutionRunnerImage, cfg.Execution.RunnerImage)
+	assert.Equal(t, DefaultExecutionPollInterval, cfg.Execution.PollInterval)
+
+	os.Setenv("EXECUTION_BACKEND", "tekton")
+	os.Setenv("EXECUTION_NAMESPACE", "remediation-pipelines")
+	defer func() {
+		os.Unsetenv("EXECUTION_BACKEND")
+		os.Unsetenv("EXECUTION_NAMESPACE")
+	}()
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, "tekton", cfg.Execution.Backend)
+	assert.Equal(t, "remediation-pipelines", cfg.Execution.Namespace)
+
+	os.Setenv("EXECUTION_BACKEND", "jenkins")
+	_, err = Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "execution.backend must be argo or tekton")
+}
+
+func TestLoad_Budget(t *testing.T) {
+	os.Setenv("KSERVE_ANOMALY_DETECTOR_SERVICE", "anomaly-detector-predictor")
+	defer os.Unsetenv("KSERVE_ANOMALY_DETECTOR_SERVICE")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Zero(t, cfg.Budget.DailyLimit)
+	assert.Zero(t, cfg.Budget.Cooldown)
+
+	os.Setenv("REMEDIATION_DAILY_LIMIT", "20")
+	os.Setenv("REMEDIATION_COOLDOWN", "30m")
+	defer func() {
+		os.Unsetenv("REMEDIATION_DAILY_LIMIT")
+		os.Unsetenv("REMEDIATION_COOLDOWN")
+	}()
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, 20, cfg.Budget.DailyLimit)
+	assert.Equal(t, 30*time.Minute, cfg.Budget.Cooldown)
+
+	os.Setenv("REMEDIATION_DAILY_LIMIT", "-1")
+	_, err = Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "budget.daily_limit must not be negative")
+}
+
+func TestLoad_ObserverMode(t *testing.T) {
+	os.Setenv("KSERVE_ANOMALY_DETECTOR_SERVICE", "anomaly-detector-predictor")
+	defer os.Unsetenv("KSERVE_ANOMALY_DETECTOR_SERVICE")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.False(t, cfg.ObserverMode)
+
+	os.Setenv("OBSERVER_MODE", "true")
+	defer os.Unsetenv("OBSERVER_MODE")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.True(t, cfg.ObserverMode)
+}
+
+func TestLoad_Tenants(t *testing.T) {
+	os.Setenv("KSERVE_ANOMALY_DETECTOR_SERVICE", "anomaly-detector-predictor")
+	defer os.Unsetenv("KSERVE_ANOMALY_DETECTOR_SERVICE")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.False(t, cfg.TenantsEnabled)
+
+	os.Setenv("TENANTS_ENABLED", "true")
+	defer os.Unsetenv("TENANTS_ENABLED")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.True(t, cfg.TenantsEnabled)
+}
+
+func TestLoad_PrometheusRangeMaxPoints(t *testing.T) {
+	os.Setenv("KSERVE_ANOMALY_DETECTOR_SERVICE", "anomaly-detector-predictor")
+	defer os.Unsetenv("KSERVE_ANOMALY_DETECTOR_SERVICE")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Zero(t, cfg.PrometheusRangeMaxPoints)
+
+	os.Setenv("PROMETHEUS_RANGE_MAX_POINTS", "720")
+	defer os.Unsetenv("PROMETHEUS_RANGE_MAX_POINTS")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, 720, cfg.PrometheusRangeMaxPoints)
+
+	os.Setenv("PROMETHEUS_RANGE_MAX_POINTS", "-1")
+	_, err = Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "prometheus_range_max_points cannot be negative")
+}
+
+func TestLoad_AnomalySubscriptions(t *testing.T) {
+	os.Setenv("KSERVE_ANOMALY_DETECTOR_SERVICE", "anomaly-detector-predictor")
+	defer os.Unsetenv("KSERVE_ANOMALY_DETECTOR_SERVICE")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.True(t, cfg.Anomaly.SubscriptionsEnabled)
+	assert.Equal(t, DefaultAnomalySubscriptionMinInterval, cfg.Anomaly.SubscriptionMinInterval)
+	assert.Equal(t, DefaultAnomalyMaxSubscriptions, cfg.Anomaly.MaxSubscriptions)
+
+	os.Setenv("ANOMALY_SUBSCRIPTION_MIN_INTERVAL", "5m")
+	os.Setenv("ANOMALY_MAX_SUBSCRIPTIONS", "0")
+	defer func() {
+		os.Unsetenv("ANOMALY_SUBSCRIPTION_MIN_INTERVAL")
+		os.Unsetenv("ANOMALY_MAX_SUBSCRIPTIONS")
+	}()
+	_, err = Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "anomaly.max_subscriptions must be at least 1")
+
+	os.Setenv("ANOMALY_SUBSCRIPTIONS_ENABLED", "false")
+	defer os.Unsetenv("ANOMALY_SUBSCRIPTIONS_ENABLED")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.False(t, cfg.Anomaly.SubscriptionsEnabled)
+	assert.Equal(t, 5*time.Minute, cfg.Anomaly.SubscriptionMinInterval)
+}
+
+func TestLoad_AnomalyRecords(t *testing.T) {
+	os.Setenv("KSERVE_ANOMALY_DETECTOR_SERVICE", "anomaly-detector-predictor")
+	defer func() {
+		os.Unsetenv("KSERVE_ANOMALY_DETECTOR_SERVICE")
+		os.Unsetenv("ANOMALY_RECORDS_ENABLED")
+		os.Unsetenv("ANOMALY_RECORD_LIMIT")
+	}()
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.True(t, cfg.Anomaly.RecordsEnabled)
+	assert.Equal(t, DefaultAnomalyRecordLimit, cfg.Anomaly.RecordLimit)
+
+	os.Setenv("ANOMALY_RECORD_LIMIT", "0")
+	_, err = Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "anomaly.record_limit must be at least 1")
+
+	os.Setenv("ANOMALY_RECORDS_ENABLED", "false")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.False(t, cfg.Anomaly.RecordsEnabled)
+}
+
+func TestLoad_AnomalyScanner(t *testing.T) {
+	os.Setenv("KSERVE_ANOMALY_DETECTOR_SERVICE", "anomaly-detector-predictor")
+	defer os.Unsetenv("KSERVE_ANOMALY_DETECTOR_SERVICE")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Empty(t, cfg.Anomaly.ScanNamespaces)
+	assert.Equal(t, DefaultAnomalyScanInterval, cfg.Anomaly.ScanInterval)
+
+	os.Setenv("ANOMALY_SCAN_NAMESPACES", "payments, checkout")
+	os.Setenv("ANOMALY_SCAN_INTERVAL", "30s")
+	defer func() {
+		os.Unsetenv("ANOMALY_SCAN_NAMESPACES")
+		os.Unsetenv("ANOMALY_SCAN_INTERVAL")
+	}()
+	_, err = Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "anomaly.scan_interval too short")
+
+	os.Setenv("ANOMALY_SCAN_INTERVAL", "10m")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"payments", "checkout"}, cfg.Anomaly.ScanNamespaces)
+	assert.Equal(t, 10*time.Minute, cfg.Anomaly.ScanInterval)
+
+	// Tenants are scanned without configured namespaces
+	os.Unsetenv("ANOMALY_SCAN_NAMESPACES")
+	os.Setenv("ANOMALY_SCAN_INTERVAL", "30s")
+	t.Setenv("TENANTS_ENABLED", "true")
+	_, err = Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "anomaly.scan_interval too short")
+}
+
+func TestLoad_AnomalyFeatureHistory(t *testing.T) {
+	t.Setenv("KSERVE_ANOMALY_DETECTOR_SERVICE", "anomaly-detector-predictor")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.False(t, cfg.Anomaly.FeatureHistory)
+	assert.Equal(t, DefaultAnomalyFeatureHistoryRetention, cfg.Anomaly.FeatureHistoryRetention)
+	assert.Equal(t, DefaultAnomalyFeatureHistoryResolution, cfg.Anomaly.FeatureHistoryResolution)
+	assert.Equal(t, DefaultAnomalyFeatureHistoryMaxScopes, cfg.Anomaly.FeatureHistoryMaxScopes)
+
+	t.Setenv("ANOMALY_FEATURE_HISTORY", "true")
+	t.Setenv("ANOMALY_FEATURE_HISTORY_RETENTION", "6h")
+	t.Setenv("ANOMALY_FEATURE_HISTORY_RESOLUTION", "5m")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.True(t, cfg.Anomaly.FeatureHistory)
+	assert.Equal(t, 6*time.Hour, cfg.Anomaly.FeatureHistoryRetention)
+	assert.Equal(t, 5*time.Minute, cfg.Anomaly.FeatureHistoryResolution)
+
+	t.Setenv("ANOMALY_FEATURE_HISTORY_RETENTION", "720h")
+	t.Setenv("ANOMALY_FEATURE_HISTORY_RESOLUTION", "1m")
+	_, err = Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "anomaly.feature_history_retention must hold 1 to 10080 samples")
+
+	t.Setenv("ANOMALY_FEATURE_HISTORY_RETENTION", "24h")
+	t.Setenv("ANOMALY_FEATURE_HISTORY_RESOLUTION", "1s")
+	_, err = Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "anomaly.feature_history_resolution too short")
+}
+
+func TestLoad_PrometheusExtraLabels(t *testing.T) {
+	os.Setenv("KSERVE_ANOMALY_DETECTOR_SERVICE", "anomaly-detector-predictor")
+	os.Setenv("PROMETHEUS_EXTRA_LABELS", "cluster=prod-east, env=production")
+	defer func() {
+		os.Unsetenv("KSERVE_ANOMALY_DETECTOR_SERVICE")
+		os.Unsetenv("PROMETHEUS_EXTRA_LABELS")
+	}()
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"cluster": "prod-east", "env": "production"}, cfg.PrometheusExtraLabels)
+}
+
+func TestValidate_InvalidPrometheusExtraLabel(t *testing.T) {
+	cfg := &Config{
+		Port:                  8080,
+		MetricsPort:           9090,
+		LogLevel:              "info",
+		Namespace:             "default",
+		HTTPTimeout:           30 * time.Second,
+		KubernetesQPS:         50.0,
+		KubernetesBurst:       100,
+		PrometheusExtraLabels: map[string]string{"bad-label": "x"},
+	}
+
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid prometheus extra label name: "bad-label"`)
+}
+
+func TestLoad_MCP(t *testing.T) {
+	os.Setenv("KSERVE_ANOMALY_DETECTOR_SERVICE", "anomaly-detector-predictor")
+	os.Setenv("MCP_APPROVAL_TTL", "10m")
+	defer func() {
+		os.Unsetenv("KSERVE_ANOMALY_DETECTOR_SERVICE")
+		os.Unsetenv("MCP_APPROVAL_TTL")
+	}()
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, DefaultMCPEnabled, cfg.MCP.Enabled)
+	assert.Equal(t, 10*time.Minute, cfg.MCP.ApprovalTTL)
+}
+
+func TestValidate_MCPApprovalTTL(t *testing.T) {
+	cfg := &Config{
+		Port:            8080,
+		MetricsPort:     9090,
+		LogLevel:        "info",
+		Namespace:       "default",
+		HTTPTimeout:     30 * time.Second,
+		KubernetesQPS:   50.0,
+		KubernetesBurst: 100,
+		MCP:             MCPConfig{Enabled: true, ApprovalTTL: 10 * time.Second},
+	}
+
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "mcp.approval_ttl must be at least 1m")
+}
+
+func TestLoad_Summarizer(t *testing.T) {
+	os.Setenv("KSERVE_ANOMALY_DETECTOR_SERVICE", "anomaly-detector-predictor")
+	os.Setenv("SUMMARIZER_URL", "http://granite-predictor.models.svc:8080/openai/v1")
+	os.Setenv("SUMMARIZER_MODEL", "granite")
+	defer func() {
+		os.Unsetenv("KSERVE_ANOMALY_DETECTOR_SERVICE")
+		os.Unsetenv("SUMMARIZER_URL")
+		os.Unsetenv("SUMMARIZER_MODEL")
+	}()
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.True(t, cfg.Summarizer.Enabled())
+	assert.Equal(t, "granite", cfg.Summarizer.Model)
+	assert.Equal(t, DefaultSummarizerTimeout, cfg.Summarizer.Timeout)
+	assert.Equal(t, DefaultSummarizerMaxTokens, cfg.Summarizer.MaxTokens)
+}
+
+func TestValidate_Summarizer(t *testing.T) {
+	cfg := &Config{
+		Port:            8080,
+		MetricsPort:     9090,
+		LogLevel:        "info",
+		Namespace:       "default",
+		HTTPTimeout:     30 * time.Second,
+		KubernetesQPS:   50.0,
+		KubernetesBurst: 100,
+	}
+	require.NoError(t, cfg.Validate(), "summarizer is disabled without a URL")
+
+	cfg.Summarizer = SummarizerConfig{URL: "granite:8080", Timeout: 0}
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "summarizer.url must start with http://")
+	assert.Contains(t, err.Error(), "summarizer.model is required")
+	assert.Contains(t, err.Error(), "summarizer.timeout must be between 1s and 5m")
+	assert.Contains(t, err.Error(), "summarizer.max_tokens must be positive")
+}
+
+func TestLoad_Annotations(t *testing.T) {
+	os.Setenv("KSERVE_ANOMALY_DETECTOR_SERVICE", "anomaly-detector-predictor")
+	os.Setenv("ANNOTATIONS_GRAFANA_URL", "https://grafana.example.com")
+	os.Setenv("ANNOTATIONS_GRAFANA_TOKEN", "glsa_secret")
+	os.Setenv("ANNOTATIONS_KUBERNETES_EVENTS", "true")
+	defer func() {
+		os.Unsetenv("KSERVE_ANOMALY_DETECTOR_SERVICE")
+		os.Unsetenv("ANNOTATIONS_GRAFANA_URL")
+		os.Unsetenv("ANNOTATIONS_GRAFANA_TOKEN")
+		os.Unsetenv("ANNOTATIONS_KUBERNETES_EVENTS")
+	}()
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.True(t, cfg.Annotations.Enabled())
+	assert.Equal(t, "glsa_secret", cfg.Annotations.GrafanaToken)
+	assert.True(t, cfg.Annotations.KubernetesEvents)
+	assert.Equal(t, DefaultAnnotationsTimeout, cfg.Annotations.Timeout)
+
+	cfg.Annotations.GrafanaURL = "grafana:3000"
+	cfg.Annotations.Timeout = 0
+	err = cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "annotations.grafana_url must start with http://")
+	assert.Contains(t, err.Error(), "annotations.timeout must be between 1s and 5m")
+}
+
+func TestValidate_RunbookWikiURL(t *testing.T) {
+	cfg := &Config{
+		Port:            8080,
+		MetricsPort:     9090,
+		LogLevel:        "info",
+		Namespace:       "default",
+		HTTPTimeout:     30 * time.Second,
+		KubernetesQPS:   50.0,
+		KubernetesBurst: 100,
+		Runbook:         RunbookConfig{WikiURL: "wiki.example.com"},
+	}
+
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "runbook.wiki_url must start with http:// or https://")
+
+	cfg.Runbook.WikiURL = "https://wiki.example.com"
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestValidate_Webhook(t *testing.T) {
+	cfg := &Config{
+		Port:            8080,
+		MetricsPort:     9090,
+		LogLevel:        "info",
+		Namespace:       "default",
+		HTTPTimeout:     30 * time.Second,
+		KubernetesQPS:   50.0,
+		KubernetesBurst: 100,
+		Webhook: WebhookConfig{
+			File:           "/etc/coordination-engine/webhooks.yaml",
+			MaxAttempts:    0,
+			InitialBackoff: 10 * time.Second,
+			MaxBackoff:     time.Second,
+			Timeout:        DefaultWebhookTimeout,
+		},
+	}
+
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "webhook.max_attempts must be between 1 and 20")
+	assert.Contains(t, err.Error(), "webhook.max_backoff")
+
+	cfg.Webhook.MaxAttempts = DefaultWebhookMaxAttempts
+	cfg.Webhook.MaxBackoff = DefaultWebhookMaxBackoff
+	assert.NoError(t, cfg.Validate())
+
+	// Retry settings are ignored while webhooks are disabled
+	assert.NoError(t, (&Config{
+		Port:            8080,
+		MetricsPort:     9090,
+		LogLevel:        "info",
+		Namespace:       "default",
+		HTTPTimeout:     30 * time.Second,
+		KubernetesQPS:   50.0,
+		KubernetesBurst: 100,
+	}).Validate())
+}
+
+func TestValidate_Email(t *testing.T) {
+	cfg := &Config{
+		Port:            8080,
+		MetricsPort:     9090,
+		LogLevel:        "info",
+		Namespace:       "default",
+		HTTPTimeout:     30 * time.Second,
+		KubernetesQPS:   50.0,
+		KubernetesBurst: 100,
+		Email: EmailConfig{
+			SMTPHost:      "smtp.example.com",
+			SMTPPort:      DefaultEmailSMTPPort,
+			DigestHour:    24,
+			DigestWeekday: "someday",
+		},
+	}
+
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "email.from is required")
+	assert.Contains(t, err.Error(), "email.teams_file is required")
+	assert.Contains(t, err.Error(), "email.digest_hour must be between 0 and 23")
+	assert.Contains(t, err.Error(), "invalid email.digest_weekday")
+
+	cfg.Email.From = "coordination-engine@example.com"
+	cfg.Email.TeamsFile = "/etc/coordination-engine/teams.yaml"
+	cfg.Email.DigestHour = DefaultEmailDigestHour
+	cfg.Email.DigestWeekday = "Friday"
+	assert.NoError(t, cfg.Validate())
+	assert.Equal(t, time.Friday, cfg.Email.Weekday())
+}
+
+func TestLoad_AlertIngest(t *testing.T) {
+	os.Setenv("KSERVE_ANOMALY_DETECTOR_SERVICE", "anomaly-detector-predictor")
+	os.Setenv("ALERT_DEDUP_WINDOW", "30s")
+	os.Setenv("ALERT_FLOOD_LIMIT", "-1")
+	defer func() {
+		os.Unsetenv("KSERVE_ANOMALY_DETECTOR_SERVICE")
+		os.Unsetenv("ALERT_DEDUP_WINDOW")
+		os.Unsetenv("ALERT_FLOOD_LIMIT")
+	}()
+
+	_, err := Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "alert_ingest.dedup_window must be at least 1m")
+	assert.Contains(t, err.Error(), "alert_ingest.flood_limit cannot be negative")
+
+	os.Setenv("ALERT_DEDUP_WINDOW", "2h")
+	os.Setenv("ALERT_FLOOD_LIMIT", "0")
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, 2*time.Hour, cfg.AlertIngest.DedupWindow)
+	assert.Zero(t, cfg.AlertIngest.FloodLimit)
+}
+
+func TestLoad_IncidentTags(t *testing.T) {
+	os.Setenv("KSERVE_ANOMALY_DETECTOR_SERVICE", "anomaly-detector-predictor")
+	defer os.Unsetenv("KSERVE_ANOMALY_DETECTOR_SERVICE")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Empty(t, cfg.IncidentTags.RulesFile)
+	assert.Equal(t, []string{"team", "service", "cost_center"}, cfg.IncidentTags.AlertLabels)
+
+	os.Setenv("INCIDENT_TAG_RULES_FILE", "/etc/engine/tags.yaml")
+	os.Setenv("INCIDENT_TAG_ALERT_LABELS", "team,cost-center")
+	defer func() {
+		os.Unsetenv("INCIDENT_TAG_RULES_FILE")
+		os.Unsetenv("INCIDENT_TAG_ALERT_LABELS")
+	}()
+	_, err = Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `incident_tags.alert_labels must be Prometheus label names: "cost-center"`)
+
+	os.Setenv("INCIDENT_TAG_ALERT_LABELS", "owner")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, "/etc/engine/tags.yaml", cfg.IncidentTags.RulesFile)
+	assert.Equal(t, []string{"owner"}, cfg.IncidentTags.AlertLabels)
+}
+
+func TestLoad_Ownership(t *testing.T) {
+	os.Setenv("KSERVE_ANOMALY_DETECTOR_SERVICE", "anomaly-detector-predictor")
+	defer os.Unsetenv("KSERVE_ANOMALY_DETECTOR_SERVICE")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.True(t, cfg.Ownership.Enabled)
+	assert.Equal(t, "coordination.openshift.io/", cfg.Ownership.AnnotationPrefix)
+
+	os.Setenv("OWNERSHIP_ANNOTATION_PREFIX", "example.com")
+	defer os.Unsetenv("OWNERSHIP_ANNOTATION_PREFIX")
+	_, err = Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `ownership.annotation_prefix must end with /: "example.com"`)
+
+	os.Setenv("OWNERSHIP_ENABLED", "false")
+	defer os.Unsetenv("OWNERSHIP_ENABLED")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.False(t, cfg.Ownership.Enabled)
+}
+
+func TestLoad_HealthScore(t *testing.T) {
+	os.Setenv("KSERVE_ANOMALY_DETECTOR_SERVICE", "anomaly-detector-predictor")
+	defer os.Unsetenv("KSERVE_ANOMALY_DETECTOR_SERVICE")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, DefaultHealthScoreControlPlaneWeight, cfg.HealthScore.ControlPlaneWeight)
+	assert.Equal(t, DefaultHealthScoreIncidentWeight, cfg.HealthScore.IncidentWeight)
+
+	os.Setenv("HEALTH_SCORE_CAPACITY_WEIGHT", "-0.5")
+	defer os.Unsetenv("HEALTH_SCORE_CAPACITY_WEIGHT")
+	_, err = Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "health_score.capacity_weight must not be negative: -0.5")
+
+	os.Setenv("HEALTH_SCORE_CAPACITY_WEIGHT", "0")
+	os.Setenv("HEALTH_SCORE_CONTROL_PLANE_WEIGHT", "0")
+	defer os.Unsetenv("HEALTH_SCORE_CONTROL_PLANE_WEIGHT")
+	os.Setenv("HEALTH_SCORE_INCIDENT_WEIGHT", "2")
+	defer os.Unsetenv("HEALTH_SCORE_INCIDENT_WEIGHT")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, 2.0, cfg.HealthScore.IncidentWeight)
+	assert.Zero(t, cfg.HealthScore.ControlPlaneWeight)
+}
+
+func TestLoad_PrometheusCapabilityProbeInterval(t *testing.T) {
+	os.Setenv("KSERVE_ANOMALY_DETECTOR_SERVICE", "anomaly-detector-predictor")
+	defer os.Unsetenv("KSERVE_ANOMALY_DETECTOR_SERVICE")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, DefaultPrometheusCapabilityProbeInterval, cfg.PrometheusCapabilityProbeInterval)
+
+	os.Setenv("PROMETHEUS_CAPABILITY_PROBE_INTERVAL", "-1m")
+	defer os.Unsetenv("PROMETHEUS_CAPABILITY_PROBE_INTERVAL")
+	_, err = Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "prometheus_capability_probe_interval cannot be negative")
+
+	os.Setenv("PROMETHEUS_CAPABILITY_PROBE_INTERVAL", "0")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Zero(t, cfg.PrometheusCapabilityProbeInterval)
+}
+
+func TestLoad_PredictionTracking(t *testing.T) {
+	os.Setenv("KSERVE_ANOMALY_DETECTOR_SERVICE", "anomaly-detector-predictor")
+	os.Setenv("PREDICTION_EVALUATION_INTERVAL", "10s")
+	os.Setenv("PREDICTION_HISTORY_LIMIT", "0")
+	defer func() {
+		os.Unsetenv("KSERVE_ANOMALY_DETECTOR_SERVICE")
+		os.Unsetenv("PREDICTION_EVALUATION_INTERVAL")
+		os.Unsetenv("PREDICTION_HISTORY_LIMIT")
+		os.Unsetenv("PREDICTION_TRACKING_ENABLED")
+	}()
+
+	_, err := Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "prediction_tracking.evaluation_interval must be at least 1m")
+	assert.Contains(t, err.Error(), "prediction_tracking.history_limit must be positive")
+
+	// Settings are not validated when tracking is disabled
+	os.Setenv("PREDICTION_TRACKING_ENABLED", "false")
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.False(t, cfg.PredictionTracking.Enabled)
+
+	os.Unsetenv("PREDICTION_TRACKING_ENABLED")
+	os.Unsetenv("PREDICTION_EVALUATION_INTERVAL")
+	os.Unsetenv("PREDICTION_HISTORY_LIMIT")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.True(t, cfg.PredictionTracking.Enabled)
+	assert.Equal(t, 5*time.Minute, cfg.PredictionTracking.EvaluationInterval)
+	assert.Equal(t, 10000, cfg.PredictionTracking.HistoryLimit)
+}
+
+func TestLoad_Upgrade(t *testing.T) {
+	os.Setenv("KSERVE_ANOMALY_DETECTOR_SERVICE", "anomaly-detector-predictor")
+	os.Setenv("UPGRADE_THRESHOLD_INCREASE", "1.5")
+	os.Setenv("UPGRADE_CHECK_INTERVAL", "1s")
+	defer func() {
+		os.Unsetenv("KSERVE_ANOMALY_DETECTOR_SERVICE")
+		os.Unsetenv("UPGRADE_THRESHOLD_INCREASE")
+		os.Unsetenv("UPGRADE_CHECK_INTERVAL")
+		os.Unsetenv("UPGRADE_AWARE_ENABLED")
+	}()
+
+	_, err := Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "upgrade.threshold_increase must be between 0.0 and 1.0")
+	assert.Contains(t, err.Error(), "upgrade.check_interval must be at least 10s")
+
+	// Settings are not validated when upgrade-aware mode is disabled
+	os.Setenv("UPGRADE_AWARE_ENABLED", "false")
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.False(t, cfg.Upgrade.Enabled)
+
+	os.Unsetenv("UPGRADE_AWARE_ENABLED")
+	os.Unsetenv("UPGRADE_THRESHOLD_INCREASE")
+	os.Unsetenv("UPGRADE_CHECK_INTERVAL")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.True(t, cfg.Upgrade.Enabled)
+	assert.True(t, cfg.Upgrade.BlockRemediation)
+	assert.True(t, cfg.Upgrade.TagIncidents)
+	assert.InDelta(t, 0.15, cfg.Upgrade.ThresholdIncrease, 1e-9)
+	assert.Equal(t, time.Minute, cfg.Upgrade.CheckInterval)
+}
+
+func TestLoad_Admin(t *testing.T) {
+	os.Setenv("KSERVE_ANOMALY_DETECTOR_SERVICE", "anomaly-detector-predictor")
+	defer func() {
+		os.Unsetenv("KSERVE_ANOMALY_DETECTOR_SERVICE")
+		os.Unsetenv("ADMIN_TOKEN")
+		os.Unsetenv("ADMIN_BACKUP_TIMEOUT")
+	}()
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.False(t, cfg.Admin.Enabled())
+	assert.Equal(t, 5*time.Minute, cfg.Admin.BackupTimeout)
+
+	os.Setenv("ADMIN_TOKEN", "s3cret")
+	os.Setenv("ADMIN_BACKUP_TIMEOUT", "0s")
+	_, err = Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "admin.backup_timeout must be at least 1s")
+}
+
+func TestLoad_Retention(t *testing.T) {
+	os.Setenv("KSERVE_ANOMALY_DETECTOR_SERVICE", "anomaly-detector-predictor")
+	defer func() {
+		os.Unsetenv("KSERVE_ANOMALY_DETECTOR_SERVICE")
+		os.Unsetenv("RETENTION_INCIDENTS")
+		os.Unsetenv("RETENTION_PURGE_INTERVAL")
+	}()
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, 90*24*time.Hour, cfg.Retention.Incidents)
+	assert.Equal(t, 30*24*time.Hour, cfg.Retention.Predictions)
+	assert.Equal(t, 30*24*time.Hour, cfg.Retention.Anomalies)
+	assert.Equal(t, time.Hour, cfg.Retention.PurgeInterval)
+
+	os.Setenv("RETENTION_INCIDENTS", "-1h")
+	os.Setenv("RETENTION_PURGE_INTERVAL", "10s")
+	_, err = Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "retention.incidents cannot be negative")
+	assert.Contains(t, err.Error(), "retention.purge_interval must be at least 1m")
+}
+
+func TestLoad_Redaction(t *testing.T) {
+	os.Setenv("KSERVE_ANOMALY_DETECTOR_SERVICE", "anomaly-detector-predictor")
+	defer func() {
+		os.Unsetenv("KSERVE_ANOMALY_DETECTOR_SERVICE")
+		os.Unsetenv("REDACTION_ENABLED")
+		os.Unsetenv("REDACTION_PATTERNS_FILE")
+	}()
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.True(t, cfg.Redaction.Enabled)
+	assert.Empty(t, cfg.Redaction.PatternsFile)
+
+	os.Setenv("REDACTION_ENABLED", "false")
+	os.Setenv("REDACTION_PATTERNS_FILE", "/etc/coordination-engine/redaction-patterns")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.False(t, cfg.Redaction.Enabled)
+	assert.Equal(t, "/etc/coordination-engine/redaction-patterns", cfg.Redaction.PatternsFile)
+}
+
+func TestLoad_Security(t *testing.T) {
+	os.Setenv("KSERVE_ANOMALY_DETECTOR_SERVICE", "anomaly-detector-predictor")
+	defer func() {
+		os.Unsetenv("KSERVE_ANOMALY_DETECTOR_SERVICE")
+		os.Unsetenv("SECURITY_STRICT_TLS")
+		os.Unsetenv("SECURITY_REQUIRE_FIPS")
+		os.Unsetenv("PROMETHEUS_CA_FILE")
+	}()
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.False(t, cfg.Security.StrictTLS)
+	assert.False(t, cfg.Security.RequireFIPS)
+	assert.Empty(t, cfg.PrometheusCAFile)
+
+	os.Setenv("SECURITY_STRICT_TLS", "true")
+	os.Setenv("SECURITY_REQUIRE_FIPS", "true")
+	os.Setenv("PROMETHEUS_CA_FILE", "/var/run/secrets/kubernetes.io/serviceaccount/service-ca.crt")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.True(t, cfg.Security.StrictTLS)
+	assert.True(t, cfg.Security.RequireFIPS)
+	assert.Equal(t, "/var/run/secrets/kubernetes.io/serviceaccount/service-ca.crt", cfg.PrometheusCAFile)
+}
+
+func TestLoad_ServerTLS(t *testing.T) {
+	os.Setenv("KSERVE_ANOMALY_DETECTOR_SERVICE", "anomaly-detector-predictor")
+	defer func() {
+		os.Unsetenv("KSERVE_ANOMALY_DETECTOR_SERVICE")
+		os.Unsetenv("TLS_CERT_FILE")
+		os.Unsetenv("TLS_KEY_FILE")
+		os.Unsetenv("TLS_CLIENT_CA_FILE")
+		os.Unsetenv("TLS_CRL_FILE")
+		os.Unsetenv("TLS_OCSP_ENABLED")
+		os.Unsetenv("TLS_ALLOWED_CLIENTS")
+	}()
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.False(t, cfg.ServerTLS.Enabled())
+	assert.False(t, cfg.ServerTLS.MutualTLS())
+
+	os.Setenv("TLS_CERT_FILE", "/etc/tls/tls.crt")
+	os.Setenv("TLS_KEY_FILE", "/etc/tls/tls.key")
+	os.Setenv("TLS_CLIENT_CA_FILE", "/etc/client-ca/ca.crt")
+	os.Setenv("TLS_CRL_FILE", "/etc/client-ca/ca.crl")
+	os.Setenv("TLS_OCSP_ENABLED", "true")
+	os.Setenv("TLS_ALLOWED_CLIENTS", "console-plugin, ai-assistant")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.True(t, cfg.ServerTLS.Enabled())
+	assert.True(t, cfg.ServerTLS.MutualTLS())
+	assert.Equal(t, "/etc/client-ca/ca.crl", cfg.ServerTLS.CRLFile)
+	assert.True(t, cfg.ServerTLS.OCSP)
+	assert.False(t, cfg.ServerTLS.OCSPFailOpen)
+	assert.Equal(t, []string{"console-plugin", "ai-assistant"}, cfg.ServerTLS.AllowedClients)
+
+	os.Unsetenv("TLS_KEY_FILE")
+	_, err = Load()
+	assert.Error(t, err, "certificate without key")
+
+	os.Setenv("TLS_KEY_FILE", "/etc/tls/tls.key")
+	os.Unsetenv("TLS_CLIENT_CA_FILE")
+	_, err = Load()
+	assert.Error(t, err, "revocation checks without client CA bundle")
+}
@@ -0,0 +1,115 @@
+// Package diagnostics provides request-scoped timing traces for troubleshooting slow requests.
+package diagnostics
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+type contextKey struct{}
+
+// Stage is the timing of one processing stage of a request
+type Stage struct {
+	Name       string  `json:"name"`
+	DurationMs float64 `json:"duration_ms"`
+}
+
+// QueryTiming is the timing of one backend query (e.g. PromQL)
+type QueryTiming struct {
+	Kind       string  `json:"kind"` // instant, range
+	Query      string  `json:"query"`
+	DurationMs float64 `json:"duration_ms"`
+	Error      string  `json:"error,omitempty"`
+}
+
+// Report is the debug section included in responses when "debug": true is requested
+type Report struct {
+	TotalMs float64       `json:"total_ms"`
+	Stages  []Stage       `json:"stages"`
+	Queries []QueryTiming `json:"queries"`
+}
+
+// Trace collects stage and query timings for a single request.
+// All methods are safe to call on a nil *Trace, so instrumented code does not
+// need to check whether debugging was requested.
+type Trace struct {
+	mu      sync.Mutex
+	start   time.Time
+	stages  []Stage
+	queries []QueryTiming
+}
+
+// NewTrace starts a new trace
+func NewTrace() *Trace {
+	return &Trace{start: time.Now()}
+}
+
+// WithTrace returns a context carrying the trace
+func WithTrace(ctx context.Context, t *Trace) context.Context {
+	if t == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, contextKey{}, t)
+}
+
+// FromContext returns the trace carried by ctx, or nil
+func FromContext(ctx context.Context) *Trace {
+	if ctx == nil {
+		return nil
+	}
+	t, _ := ctx.Value(contextKey{}).(*Trace)
+	return t
+}
+
+// StartStage starts timing a named stage and returns a function that ends it
+func (t *Trace) StartStage(name string) func() {
+	if t == nil {
+		return func() {}
+	}
+	start := time.Now()
+	return func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		t.stages = append(t.stages, Stage{Name: name, DurationMs: toMillis(time.Since(start))})
+	}
+}
+
+// RecordQuery records the execution of a backend query
+func (t *Trace) RecordQuery(kind, query string, duration time.Duration, err error) {
+	if t == nil {
+		return
+	}
+	timing := QueryTiming{Kind: kind, Query: query, DurationMs: toMillis(duration)}
+	if err != nil {
+		timing.Error = err.Error()
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.queries = append(t.queries, timing)
+}
+
+// Report returns a snapshot of the trace
+func (t *Trace) Report() *Report {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	report := &Report{
+		TotalMs: toMillis(time.Since(t.start)),
+		Stages:  make([]Stage, len(t.stages)),
+		Queries: make([]QueryTiming, len(t.queries)),
+	}
+	copy(report.Stages, t.stages)
+	copy(report.Queries, t.queries)
+	return report
+}
+
+// toMillis converts a duration to milliseconds rounded to microsecond precision
+func toMillis(d time.Duration) float64 {
+	return math.Round(float64(d.Microseconds())) / 1000
+}
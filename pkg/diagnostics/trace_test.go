@@ -0,0 +1,43 @@
+package diagnostics
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrace_RecordsStagesAndQueries(t *testing.T) {
+	trace := NewTrace()
+	ctx := WithTrace(context.Background(), trace)
+
+	end := FromContext(ctx).StartStage("feature_engineering")
+	FromContext(ctx).RecordQuery("instant", `up{namespace="a"}`, 12*time.Millisecond, nil)
+	FromContext(ctx).RecordQuery("range", `rate(x[5m])`, 3*time.Millisecond, errors.New("timeout"))
+	end()
+
+	report := trace.Report()
+	require.NotNil(t, report)
+	require.Len(t, report.Stages, 1)
+	assert.Equal(t, "feature_engineering", report.Stages[0].Name)
+	require.Len(t, report.Queries, 2)
+	assert.Equal(t, `up{namespace="a"}`, report.Queries[0].Query)
+	assert.Equal(t, 12.0, report.Queries[0].DurationMs)
+	assert.Equal(t, "timeout", report.Queries[1].Error)
+	assert.GreaterOrEqual(t, report.TotalMs, 0.0)
+}
+
+func TestTrace_NilSafe(t *testing.T) {
+	ctx := context.Background()
+	trace := FromContext(ctx)
+	assert.Nil(t, trace)
+
+	// None of these should panic
+	trace.StartStage("x")()
+	trace.RecordQuery("instant", "up", time.Millisecond, nil)
+	assert.Nil(t, trace.Report())
+	assert.Equal(t, ctx, WithTrace(ctx, nil))
+}
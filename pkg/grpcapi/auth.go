@@ -0,0 +1,117 @@
+package grpcapi
+
+import (
+	"context"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/apitoken"
+	pb "github.com/tosin2013/openshift-coordination-engine/pkg/grpcapi/coordinationv1"
+)
+
+// methodScopes maps each CoordinationService method to the API token scope of the
+// REST route it shares handler logic with. Methods of other services, such as health
+// and reflection, are exempt.
+var methodScopes = map[string]apitoken.Scope{
+	pb.CoordinationService_Analyze_FullMethodName:        apitoken.ScopeReadAnomalies,
+	pb.CoordinationService_Predict_FullMethodName:        apitoken.ScopeReadAnomalies,
+	pb.CoordinationService_Recommend_FullMethodName:      apitoken.ScopeReadIncidents,
+	pb.CoordinationService_ListIncidents_FullMethodName:  apitoken.ScopeReadIncidents,
+	pb.CoordinationService_WatchIncidents_FullMethodName: apitoken.ScopeReadIncidents,
+}
+
+// serviceMethodPrefix starts the full name of every CoordinationService method
+var serviceMethodPrefix = "/" + pb.CoordinationService_ServiceDesc.ServiceName + "/"
+
+// tokenAuth checks API token scopes on gRPC calls like apitoken.Middleware does for
+// REST requests. Tokens are sent as "authorization: Bearer <token>" metadata.
+type tokenAuth struct {
+	store    *apitoken.Store
+	required bool
+	log      *logrus.Logger
+}
+
+// authorize returns ctx carrying the call's API token, or an Unauthenticated or
+// PermissionDenied error
+func (a *tokenAuth) authorize(ctx context.Context, method string) (context.Context, error) {
+	if !strings.HasPrefix(method, serviceMethodPrefix) {
+		return ctx, nil
+	}
+	scope, ok := methodScopes[method]
+	if !ok {
+		// A method added without a scope is denied rather than left open
+		return nil, a.reject(ctx, method, codes.PermissionDenied, apitoken.ReasonInsufficientScope, scope, nil, "method has no API token scope")
+	}
+
+	var credential string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		for _, value := range md.Get("authorization") {
+			if bearer, found := strings.CutPrefix(value, "Bearer "); found {
+				credential = bearer
+				break
+			}
+		}
+	}
+	if !apitoken.IsToken(credential) {
+		if !a.required {
+			return ctx, nil
+		}
+		return nil, a.reject(ctx, method, codes.Unauthenticated, apitoken.ReasonMissing, scope, nil, "API token required")
+	}
+
+	token, err := a.store.Authenticate(credential)
+	if err != nil {
+		return nil, a.reject(ctx, method, codes.Unauthenticated, apitoken.AuthenticationReason(err), scope, nil, err.Error())
+	}
+	if !token.HasScope(scope) {
+		return nil, a.reject(ctx, method, codes.PermissionDenied, apitoken.ReasonInsufficientScope, scope, token,
+			"API token lacks scope "+string(scope))
+	}
+	return apitoken.NewContext(ctx, token), nil
+}
+
+// reject records a rejected call and returns its status error
+func (a *tokenAuth) reject(ctx context.Context, method string, code codes.Code, reason string, scope apitoken.Scope, token *apitoken.Token, message string) error {
+	apitoken.RecordRejection(reason)
+	fields := logrus.Fields{
+		"method": method,
+		"reason": reason,
+		"scope":  scope,
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		fields["remote_addr"] = p.Addr.String()
+	}
+	if token != nil {
+		fields["token_id"] = token.ID
+		fields["token_name"] = token.Name
+	}
+	a.log.WithFields(fields).Warn("gRPC call rejected by API token check")
+	return status.Error(code, message)
+}
+
+// unary enforces API token scopes on unary calls
+func (a *tokenAuth) unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := a.authorize(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// stream enforces API token scopes on streaming calls
+func (a *tokenAuth) stream() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if _, err := a.authorize(ss.Context(), info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
@@ -0,0 +1,217 @@
+package grpcapi
+
+import (
+	"errors"
+	"net/http"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/storage"
+	v1 "github.com/tosin2013/openshift-coordination-engine/pkg/api/v1"
+	pb "github.com/tosin2013/openshift-coordination-engine/pkg/grpcapi/coordinationv1"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// statusFromError maps handler errors to gRPC status errors. *v1.RequestError carries
+// the HTTP status the REST API would respond with; it is translated to the matching
+// gRPC code and the REST error code is kept in the message for clients that match on it.
+// Invalid request fields are attached as a BadRequest detail.
+func statusFromError(err error) error {
+	var requestErr *v1.RequestError
+	if !errors.As(err, &requestErr) {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	msg := requestErr.Error()
+	if requestErr.Code != "" {
+		msg = requestErr.Code + ": " + msg
+	}
+	st := status.New(codeFromHTTPStatus(requestErr.StatusCode), msg)
+	if len(requestErr.Errors) == 0 {
+		return st.Err()
+	}
+
+	badRequest := &errdetails.BadRequest{}
+	for _, fieldErr := range requestErr.Errors {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       fieldErr.Field,
+			Description: fieldErr.Constraint + ": " + fieldErr.Message,
+		})
+	}
+	withDetails, err := st.WithDetails(badRequest)
+	if err != nil {
+		return st.Err()
+	}
+	return withDetails.Err()
+}
+
+// codeFromHTTPStatus returns the gRPC code corresponding to an HTTP status
+func codeFromHTTPStatus(statusCode int) codes.Code {
+	switch statusCode {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.AlreadyExists
+	case http.StatusUnprocessableEntity:
+		return codes.FailedPrecondition
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	case http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	default:
+		return codes.Internal
+	}
+}
+
+func analyzeRequestFromProto(req *pb.AnalyzeRequest) *v1.AnomalyAnalyzeRequest {
+	return &v1.AnomalyAnalyzeRequest{
+		Scope:         models.Scope{Namespace: req.GetNamespace(), Deployment: req.GetDeployment(), Pod: req.GetPod()},
+		TimeRange:     req.GetTimeRange(),
+		LabelSelector: req.GetLabelSelector(),
+		Threshold:     req.GetThreshold(),
+		ModelName:     req.GetModelName(),
+	}
+}
+
+func analyzeResponseToProto(resp *v1.AnomalyAnalyzeResponse) *pb.AnalyzeResponse {
+	anomalies := make([]*pb.Anomaly, 0, len(resp.Anomalies))
+	for i := range resp.Anomalies {
+		a := &resp.Anomalies[i]
+		anomalies = append(anomalies, &pb.Anomaly{
+			Timestamp:          a.Timestamp,
+			Severity:           a.Severity,
+			AnomalyScore:       a.AnomalyScore,
+			Confidence:         a.Confidence,
+			Metrics:            a.Metrics,
+			Explanation:        a.Explanation,
+			RecommendedAction:  a.RecommendedAction,
+			AlternativeActions: a.AlternativeActions,
+		})
+	}
+
+	return &pb.AnalyzeResponse{
+		TimeRange: resp.TimeRange,
+		Scope: &pb.AnomalyScope{
+			Namespace:         resp.Scope.Namespace,
+			Deployment:        resp.Scope.Deployment,
+			Pod:               resp.Scope.Pod,
+			TargetDescription: resp.Scope.TargetDescription,
+		},
+		ModelUsed:         resp.ModelUsed,
+		AnomaliesDetected: int32(resp.AnomaliesDetected), //nolint:gosec // small count
+		Anomalies:         anomalies,
+		Summary: &pb.AnomalySummary{
+			MaxScore:          resp.Summary.MaxScore,
+			AverageScore:      resp.Summary.AverageScore,
+			MetricsAnalyzed:   int32(resp.Summary.MetricsAnalyzed),   //nolint:gosec // small count
+			FeaturesGenerated: int32(resp.Summary.FeaturesGenerated), //nolint:gosec // small count
+		},
+		Recommendation: resp.Recommendation,
+	}
+}
+
+func predictRequestFromProto(req *pb.PredictRequest) *v1.PredictRequest {
+	return &v1.PredictRequest{
+		Hour:      int(req.GetHour()),
+		DayOfWeek: int(req.GetDayOfWeek()),
+		Scope: models.Scope{
+			Namespace:  req.GetNamespace(),
+			Deployment: req.GetDeployment(),
+			Pod:        req.GetPod(),
+			Level:      models.ScopeLevel(req.GetScope()),
+		},
+		Model: req.GetModel(),
+	}
+}
+
+func predictResponseToProto(resp *v1.PredictResponse) *pb.PredictResponse {
+	return &pb.PredictResponse{
+		Scope:                    resp.Scope,
+		Target:                   resp.Target,
+		CpuPercent:               resp.Predictions.CPUPercent,
+		MemoryPercent:            resp.Predictions.MemoryPercent,
+		CurrentCpuRollingMean:    resp.CurrentMetrics.CPURollingMean,
+		CurrentMemoryRollingMean: resp.CurrentMetrics.MemoryRollingMean,
+		ModelName:                resp.ModelInfo.Name,
+		ModelVersion:             resp.ModelInfo.Version,
+		Confidence:               resp.ModelInfo.Confidence,
+		TargetTime:               resp.TargetTime.ISOTimestamp,
+	}
+}
+
+func recommendRequestFromProto(req *pb.RecommendRequest) *v1.GetRecommendationsRequest {
+	return &v1.GetRecommendationsRequest{
+		Timeframe:           req.GetTimeframe(),
+		IncludePredictions:  req.IncludePredictions,
+		ConfidenceThreshold: req.GetConfidenceThreshold(),
+		Scope:               models.Scope{Namespace: req.GetNamespace()},
+	}
+}
+
+func recommendResponseToProto(resp *v1.GetRecommendationsResponse) *pb.RecommendResponse {
+	recommendations := make([]*pb.Recommendation, 0, len(resp.Recommendations))
+	for i := range resp.Recommendations {
+		r := &resp.Recommendations[i]
+		recommendations = append(recommendations, &pb.Recommendation{
+			Id:                 r.ID,
+			Type:               r.Type,
+			IssueType:          r.IssueType,
+			Target:             r.Target,
+			Namespace:          r.Namespace,
+			Severity:           r.Severity,
+			Confidence:         r.Confidence,
+			PredictedTime:      r.PredictedTime,
+			RecommendedActions: r.RecommendedActions,
+			Evidence:           r.Evidence,
+			Source:             r.Source,
+			RelatedIncidentId:  r.RelatedIncidentID,
+		})
+	}
+
+	return &pb.RecommendResponse{
+		Timeframe:       resp.Timeframe,
+		Recommendations: recommendations,
+		MlEnabled:       resp.MLEnabled,
+		Message:         resp.Message,
+	}
+}
+
+func incidentToProto(inc *models.Incident) *pb.Incident {
+	return &pb.Incident{
+		Id:                inc.ID,
+		Title:             inc.Title,
+		Description:       inc.Description,
+		Target:            inc.Target,
+		Severity:          string(inc.Severity),
+		Status:            string(inc.Status),
+		CreatedAt:         timestamppb.New(inc.CreatedAt),
+		UpdatedAt:         timestamppb.New(inc.UpdatedAt),
+		AffectedResources: inc.AffectedResources,
+		Labels:            inc.Labels,
+		WorkflowId:        inc.WorkflowID,
+	}
+}
+
+func incidentChangeToProto(change *storage.IncidentChange) *pb.IncidentEvent {
+	eventType := pb.IncidentEvent_TYPE_UNSPECIFIED
+	switch change.Type {
+	case storage.IncidentCreated:
+		eventType = pb.IncidentEvent_TYPE_CREATED
+	case storage.IncidentUpdated:
+		eventType = pb.IncidentEvent_TYPE_UPDATED
+	case storage.IncidentDeleted:
+		eventType = pb.IncidentEvent_TYPE_DELETED
+	}
+
+	return &pb.IncidentEvent{
+		Type:     eventType,
+		Incident: incidentToProto(&change.Incident),
+	}
+}
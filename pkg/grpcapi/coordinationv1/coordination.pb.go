@@ -0,0 +1,1572 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.8
+// 	protoc        (unknown)
+// source: coordination/v1/coordination.proto
+
+package coordinationv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type IncidentEvent_Type int32
+
+const (
+	IncidentEvent_TYPE_UNSPECIFIED IncidentEvent_Type = 0
+	IncidentEvent_TYPE_CREATED     IncidentEvent_Type = 1
+	IncidentEvent_TYPE_UPDATED     IncidentEvent_Type = 2
+	IncidentEvent_TYPE_DELETED     IncidentEvent_Type = 3
+)
+
+// Enum value maps for IncidentEvent_Type.
+var (
+	IncidentEvent_Type_name = map[int32]string{
+		0: "TYPE_UNSPECIFIED",
+		1: "TYPE_CREATED",
+		2: "TYPE_UPDATED",
+		3: "TYPE_DELETED",
+	}
+	IncidentEvent_Type_value = map[string]int32{
+		"TYPE_UNSPECIFIED": 0,
+		"TYPE_CREATED":     1,
+		"TYPE_UPDATED":     2,
+		"TYPE_DELETED":     3,
+	}
+)
+
+func (x IncidentEvent_Type) Enum() *IncidentEvent_Type {
+	p := new(IncidentEvent_Type)
+	*p = x
+	return p
+}
+
+func (x IncidentEvent_Type) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (IncidentEvent_Type) Descriptor() protoreflect.EnumDescriptor {
+	return file_coordination_v1_coordination_proto_enumTypes[0].Descriptor()
+}
+
+func (IncidentEvent_Type) Type() protoreflect.EnumType {
+	return &file_coordination_v1_coordination_proto_enumTypes[0]
+}
+
+func (x IncidentEvent_Type) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use IncidentEvent_Type.Descriptor instead.
+func (IncidentEvent_Type) EnumDescriptor() ([]byte, []int) {
+	return file_coordination_v1_coordination_proto_rawDescGZIP(), []int{14, 0}
+}
+
+type AnalyzeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TimeRange     string                 `protobuf:"bytes,1,opt,name=time_range,json=timeRange,proto3" json:"time_range,omitempty"`
+	Namespace     string                 `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Deployment    string                 `protobuf:"bytes,3,opt,name=deployment,proto3" json:"deployment,omitempty"`
+	Pod           string                 `protobuf:"bytes,4,opt,name=pod,proto3" json:"pod,omitempty"`
+	LabelSelector string                 `protobuf:"bytes,5,opt,name=label_selector,json=labelSelector,proto3" json:"label_selector,omitempty"`
+	Threshold     float64                `protobuf:"fixed64,6,opt,name=threshold,proto3" json:"threshold,omitempty"`
+	ModelName     string                 `protobuf:"bytes,7,opt,name=model_name,json=modelName,proto3" json:"model_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AnalyzeRequest) Reset() {
+	*x = AnalyzeRequest{}
+	mi := &file_coordination_v1_coordination_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnalyzeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnalyzeRequest) ProtoMessage() {}
+
+func (x *AnalyzeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_coordination_v1_coordination_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnalyzeRequest.ProtoReflect.Descriptor instead.
+func (*AnalyzeRequest) Descriptor() ([]byte, []int) {
+	return file_coordination_v1_coordination_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *AnalyzeRequest) GetTimeRange() string {
+	if x != nil {
+		return x.TimeRange
+	}
+	return ""
+}
+
+func (x *AnalyzeRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *AnalyzeRequest) GetDeployment() string {
+	if x != nil {
+		return x.Deployment
+	}
+	return ""
+}
+
+func (x *AnalyzeRequest) GetPod() string {
+	if x != nil {
+		return x.Pod
+	}
+	return ""
+}
+
+func (x *AnalyzeRequest) GetLabelSelector() string {
+	if x != nil {
+		return x.LabelSelector
+	}
+	return ""
+}
+
+func (x *AnalyzeRequest) GetThreshold() float64 {
+	if x != nil {
+		return x.Threshold
+	}
+	return 0
+}
+
+func (x *AnalyzeRequest) GetModelName() string {
+	if x != nil {
+		return x.ModelName
+	}
+	return ""
+}
+
+type AnomalyScope struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Namespace         string                 `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Deployment        string                 `protobuf:"bytes,2,opt,name=deployment,proto3" json:"deployment,omitempty"`
+	Pod               string                 `protobuf:"bytes,3,opt,name=pod,proto3" json:"pod,omitempty"`
+	TargetDescription string                 `protobuf:"bytes,4,opt,name=target_description,json=targetDescription,proto3" json:"target_description,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *AnomalyScope) Reset() {
+	*x = AnomalyScope{}
+	mi := &file_coordination_v1_coordination_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnomalyScope) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnomalyScope) ProtoMessage() {}
+
+func (x *AnomalyScope) ProtoReflect() protoreflect.Message {
+	mi := &file_coordination_v1_coordination_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnomalyScope.ProtoReflect.Descriptor instead.
+func (*AnomalyScope) Descriptor() ([]byte, []int) {
+	return file_coordination_v1_coordination_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *AnomalyScope) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *AnomalyScope) GetDeployment() string {
+	if x != nil {
+		return x.Deployment
+	}
+	return ""
+}
+
+func (x *AnomalyScope) GetPod() string {
+	if x != nil {
+		return x.Pod
+	}
+	return ""
+}
+
+func (x *AnomalyScope) GetTargetDescription() string {
+	if x != nil {
+		return x.TargetDescription
+	}
+	return ""
+}
+
+type Anomaly struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Timestamp          string                 `protobuf:"bytes,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Severity           string                 `protobuf:"bytes,2,opt,name=severity,proto3" json:"severity,omitempty"`
+	AnomalyScore       float64                `protobuf:"fixed64,3,opt,name=anomaly_score,json=anomalyScore,proto3" json:"anomaly_score,omitempty"`
+	Confidence         float64                `protobuf:"fixed64,4,opt,name=confidence,proto3" json:"confidence,omitempty"`
+	Metrics            map[string]float64     `protobuf:"bytes,5,rep,name=metrics,proto3" json:"metrics,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed64,2,opt,name=value"`
+	Explanation        string                 `protobuf:"bytes,6,opt,name=explanation,proto3" json:"explanation,omitempty"`
+	RecommendedAction  string                 `protobuf:"bytes,7,opt,name=recommended_action,json=recommendedAction,proto3" json:"recommended_action,omitempty"`
+	AlternativeActions []string               `protobuf:"bytes,8,rep,name=alternative_actions,json=alternativeActions,proto3" json:"alternative_actions,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *Anomaly) Reset() {
+	*x = Anomaly{}
+	mi := &file_coordination_v1_coordination_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Anomaly) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Anomaly) ProtoMessage() {}
+
+func (x *Anomaly) ProtoReflect() protoreflect.Message {
+	mi := &file_coordination_v1_coordination_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Anomaly.ProtoReflect.Descriptor instead.
+func (*Anomaly) Descriptor() ([]byte, []int) {
+	return file_coordination_v1_coordination_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Anomaly) GetTimestamp() string {
+	if x != nil {
+		return x.Timestamp
+	}
+	return ""
+}
+
+func (x *Anomaly) GetSeverity() string {
+	if x != nil {
+		return x.Severity
+	}
+	return ""
+}
+
+func (x *Anomaly) GetAnomalyScore() float64 {
+	if x != nil {
+		return x.AnomalyScore
+	}
+	return 0
+}
+
+func (x *Anomaly) GetConfidence() float64 {
+	if x != nil {
+		return x.Confidence
+	}
+	return 0
+}
+
+func (x *Anomaly) GetMetrics() map[string]float64 {
+	if x != nil {
+		return x.Metrics
+	}
+	return nil
+}
+
+func (x *Anomaly) GetExplanation() string {
+	if x != nil {
+		return x.Explanation
+	}
+	return ""
+}
+
+func (x *Anomaly) GetRecommendedAction() string {
+	if x != nil {
+		return x.RecommendedAction
+	}
+	return ""
+}
+
+func (x *Anomaly) GetAlternativeActions() []string {
+	if x != nil {
+		return x.AlternativeActions
+	}
+	return nil
+}
+
+type AnomalySummary struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	MaxScore          float64                `protobuf:"fixed64,1,opt,name=max_score,json=maxScore,proto3" json:"max_score,omitempty"`
+	AverageScore      float64                `protobuf:"fixed64,2,opt,name=average_score,json=averageScore,proto3" json:"average_score,omitempty"`
+	MetricsAnalyzed   int32                  `protobuf:"varint,3,opt,name=metrics_analyzed,json=metricsAnalyzed,proto3" json:"metrics_analyzed,omitempty"`
+	FeaturesGenerated int32                  `protobuf:"varint,4,opt,name=features_generated,json=featuresGenerated,proto3" json:"features_generated,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *AnomalySummary) Reset() {
+	*x = AnomalySummary{}
+	mi := &file_coordination_v1_coordination_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnomalySummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnomalySummary) ProtoMessage() {}
+
+func (x *AnomalySummary) ProtoReflect() protoreflect.Message {
+	mi := &file_coordination_v1_coordination_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnomalySummary.ProtoReflect.Descriptor instead.
+func (*AnomalySummary) Descriptor() ([]byte, []int) {
+	return file_coordination_v1_coordination_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *AnomalySummary) GetMaxScore() float64 {
+	if x != nil {
+		return x.MaxScore
+	}
+	return 0
+}
+
+func (x *AnomalySummary) GetAverageScore() float64 {
+	if x != nil {
+		return x.AverageScore
+	}
+	return 0
+}
+
+func (x *AnomalySummary) GetMetricsAnalyzed() int32 {
+	if x != nil {
+		return x.MetricsAnalyzed
+	}
+	return 0
+}
+
+func (x *AnomalySummary) GetFeaturesGenerated() int32 {
+	if x != nil {
+		return x.FeaturesGenerated
+	}
+	return 0
+}
+
+type AnalyzeResponse struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	TimeRange         string                 `protobuf:"bytes,1,opt,name=time_range,json=timeRange,proto3" json:"time_range,omitempty"`
+	Scope             *AnomalyScope          `protobuf:"bytes,2,opt,name=scope,proto3" json:"scope,omitempty"`
+	ModelUsed         string                 `protobuf:"bytes,3,opt,name=model_used,json=modelUsed,proto3" json:"model_used,omitempty"`
+	AnomaliesDetected int32                  `protobuf:"varint,4,opt,name=anomalies_detected,json=anomaliesDetected,proto3" json:"anomalies_detected,omitempty"`
+	Anomalies         []*Anomaly             `protobuf:"bytes,5,rep,name=anomalies,proto3" json:"anomalies,omitempty"`
+	Summary           *AnomalySummary        `protobuf:"bytes,6,opt,name=summary,proto3" json:"summary,omitempty"`
+	Recommendation    string                 `protobuf:"bytes,7,opt,name=recommendation,proto3" json:"recommendation,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *AnalyzeResponse) Reset() {
+	*x = AnalyzeResponse{}
+	mi := &file_coordination_v1_coordination_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnalyzeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnalyzeResponse) ProtoMessage() {}
+
+func (x *AnalyzeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_coordination_v1_coordination_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnalyzeResponse.ProtoReflect.Descriptor instead.
+func (*AnalyzeResponse) Descriptor() ([]byte, []int) {
+	return file_coordination_v1_coordination_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *AnalyzeResponse) GetTimeRange() string {
+	if x != nil {
+		return x.TimeRange
+	}
+	return ""
+}
+
+func (x *AnalyzeResponse) GetScope() *AnomalyScope {
+	if x != nil {
+		return x.Scope
+	}
+	return nil
+}
+
+func (x *AnalyzeResponse) GetModelUsed() string {
+	if x != nil {
+		return x.ModelUsed
+	}
+	return ""
+}
+
+func (x *AnalyzeResponse) GetAnomaliesDetected() int32 {
+	if x != nil {
+		return x.AnomaliesDetected
+	}
+	return 0
+}
+
+func (x *AnalyzeResponse) GetAnomalies() []*Anomaly {
+	if x != nil {
+		return x.Anomalies
+	}
+	return nil
+}
+
+func (x *AnalyzeResponse) GetSummary() *AnomalySummary {
+	if x != nil {
+		return x.Summary
+	}
+	return nil
+}
+
+func (x *AnalyzeResponse) GetRecommendation() string {
+	if x != nil {
+		return x.Recommendation
+	}
+	return ""
+}
+
+type PredictRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Hour          int32                  `protobuf:"varint,1,opt,name=hour,proto3" json:"hour,omitempty"`
+	DayOfWeek     int32                  `protobuf:"varint,2,opt,name=day_of_week,json=dayOfWeek,proto3" json:"day_of_week,omitempty"`
+	Namespace     string                 `protobuf:"bytes,3,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Deployment    string                 `protobuf:"bytes,4,opt,name=deployment,proto3" json:"deployment,omitempty"`
+	Pod           string                 `protobuf:"bytes,5,opt,name=pod,proto3" json:"pod,omitempty"`
+	Scope         string                 `protobuf:"bytes,6,opt,name=scope,proto3" json:"scope,omitempty"`
+	Model         string                 `protobuf:"bytes,7,opt,name=model,proto3" json:"model,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PredictRequest) Reset() {
+	*x = PredictRequest{}
+	mi := &file_coordination_v1_coordination_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PredictRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PredictRequest) ProtoMessage() {}
+
+func (x *PredictRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_coordination_v1_coordination_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PredictRequest.ProtoReflect.Descriptor instead.
+func (*PredictRequest) Descriptor() ([]byte, []int) {
+	return file_coordination_v1_coordination_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *PredictRequest) GetHour() int32 {
+	if x != nil {
+		return x.Hour
+	}
+	return 0
+}
+
+func (x *PredictRequest) GetDayOfWeek() int32 {
+	if x != nil {
+		return x.DayOfWeek
+	}
+	return 0
+}
+
+func (x *PredictRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *PredictRequest) GetDeployment() string {
+	if x != nil {
+		return x.Deployment
+	}
+	return ""
+}
+
+func (x *PredictRequest) GetPod() string {
+	if x != nil {
+		return x.Pod
+	}
+	return ""
+}
+
+func (x *PredictRequest) GetScope() string {
+	if x != nil {
+		return x.Scope
+	}
+	return ""
+}
+
+func (x *PredictRequest) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+type PredictResponse struct {
+	state                    protoimpl.MessageState `protogen:"open.v1"`
+	Scope                    string                 `protobuf:"bytes,1,opt,name=scope,proto3" json:"scope,omitempty"`
+	Target                   string                 `protobuf:"bytes,2,opt,name=target,proto3" json:"target,omitempty"`
+	CpuPercent               float64                `protobuf:"fixed64,3,opt,name=cpu_percent,json=cpuPercent,proto3" json:"cpu_percent,omitempty"`
+	MemoryPercent            float64                `protobuf:"fixed64,4,opt,name=memory_percent,json=memoryPercent,proto3" json:"memory_percent,omitempty"`
+	CurrentCpuRollingMean    float64                `protobuf:"fixed64,5,opt,name=current_cpu_rolling_mean,json=currentCpuRollingMean,proto3" json:"current_cpu_rolling_mean,omitempty"`
+	CurrentMemoryRollingMean float64                `protobuf:"fixed64,6,opt,name=current_memory_rolling_mean,json=currentMemoryRollingMean,proto3" json:"current_memory_rolling_mean,omitempty"`
+	ModelName                string                 `protobuf:"bytes,7,opt,name=model_name,json=modelName,proto3" json:"model_name,omitempty"`
+	ModelVersion             string                 `protobuf:"bytes,8,opt,name=model_version,json=modelVersion,proto3" json:"model_version,omitempty"`
+	Confidence               float64                `protobuf:"fixed64,9,opt,name=confidence,proto3" json:"confidence,omitempty"`
+	TargetTime               string                 `protobuf:"bytes,10,opt,name=target_time,json=targetTime,proto3" json:"target_time,omitempty"`
+	unknownFields            protoimpl.UnknownFields
+	sizeCache                protoimpl.SizeCache
+}
+
+func (x *PredictResponse) Reset() {
+	*x = PredictResponse{}
+	mi := &file_coordination_v1_coordination_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PredictResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PredictResponse) ProtoMessage() {}
+
+func (x *PredictResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_coordination_v1_coordination_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PredictResponse.ProtoReflect.Descriptor instead.
+func (*PredictResponse) Descriptor() ([]byte, []int) {
+	return file_coordination_v1_coordination_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *PredictResponse) GetScope() string {
+	if x != nil {
+		return x.Scope
+	}
+	return ""
+}
+
+func (x *PredictResponse) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+func (x *PredictResponse) GetCpuPercent() float64 {
+	if x != nil {
+		return x.CpuPercent
+	}
+	return 0
+}
+
+func (x *PredictResponse) GetMemoryPercent() float64 {
+	if x != nil {
+		return x.MemoryPercent
+	}
+	return 0
+}
+
+func (x *PredictResponse) GetCurrentCpuRollingMean() float64 {
+	if x != nil {
+		return x.CurrentCpuRollingMean
+	}
+	return 0
+}
+
+func (x *PredictResponse) GetCurrentMemoryRollingMean() float64 {
+	if x != nil {
+		return x.CurrentMemoryRollingMean
+	}
+	return 0
+}
+
+func (x *PredictResponse) GetModelName() string {
+	if x != nil {
+		return x.ModelName
+	}
+	return ""
+}
+
+func (x *PredictResponse) GetModelVersion() string {
+	if x != nil {
+		return x.ModelVersion
+	}
+	return ""
+}
+
+func (x *PredictResponse) GetConfidence() float64 {
+	if x != nil {
+		return x.Confidence
+	}
+	return 0
+}
+
+func (x *PredictResponse) GetTargetTime() string {
+	if x != nil {
+		return x.TargetTime
+	}
+	return ""
+}
+
+type RecommendRequest struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Timeframe           string                 `protobuf:"bytes,1,opt,name=timeframe,proto3" json:"timeframe,omitempty"`
+	IncludePredictions  *bool                  `protobuf:"varint,2,opt,name=include_predictions,json=includePredictions,proto3,oneof" json:"include_predictions,omitempty"`
+	ConfidenceThreshold float64                `protobuf:"fixed64,3,opt,name=confidence_threshold,json=confidenceThreshold,proto3" json:"confidence_threshold,omitempty"`
+	Namespace           string                 `protobuf:"bytes,4,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *RecommendRequest) Reset() {
+	*x = RecommendRequest{}
+	mi := &file_coordination_v1_coordination_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecommendRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecommendRequest) ProtoMessage() {}
+
+func (x *RecommendRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_coordination_v1_coordination_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecommendRequest.ProtoReflect.Descriptor instead.
+func (*RecommendRequest) Descriptor() ([]byte, []int) {
+	return file_coordination_v1_coordination_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *RecommendRequest) GetTimeframe() string {
+	if x != nil {
+		return x.Timeframe
+	}
+	return ""
+}
+
+func (x *RecommendRequest) GetIncludePredictions() bool {
+	if x != nil && x.IncludePredictions != nil {
+		return *x.IncludePredictions
+	}
+	return false
+}
+
+func (x *RecommendRequest) GetConfidenceThreshold() float64 {
+	if x != nil {
+		return x.ConfidenceThreshold
+	}
+	return 0
+}
+
+func (x *RecommendRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+type Recommendation struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Id                 string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type               string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	IssueType          string                 `protobuf:"bytes,3,opt,name=issue_type,json=issueType,proto3" json:"issue_type,omitempty"`
+	Target             string                 `protobuf:"bytes,4,opt,name=target,proto3" json:"target,omitempty"`
+	Namespace          string                 `protobuf:"bytes,5,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Severity           string                 `protobuf:"bytes,6,opt,name=severity,proto3" json:"severity,omitempty"`
+	Confidence         float64                `protobuf:"fixed64,7,opt,name=confidence,proto3" json:"confidence,omitempty"`
+	PredictedTime      string                 `protobuf:"bytes,8,opt,name=predicted_time,json=predictedTime,proto3" json:"predicted_time,omitempty"`
+	RecommendedActions []string               `protobuf:"bytes,9,rep,name=recommended_actions,json=recommendedActions,proto3" json:"recommended_actions,omitempty"`
+	Evidence           []string               `protobuf:"bytes,10,rep,name=evidence,proto3" json:"evidence,omitempty"`
+	Source             string                 `protobuf:"bytes,11,opt,name=source,proto3" json:"source,omitempty"`
+	RelatedIncidentId  string                 `protobuf:"bytes,12,opt,name=related_incident_id,json=relatedIncidentId,proto3" json:"related_incident_id,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *Recommendation) Reset() {
+	*x = Recommendation{}
+	mi := &file_coordination_v1_coordination_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Recommendation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Recommendation) ProtoMessage() {}
+
+func (x *Recommendation) ProtoReflect() protoreflect.Message {
+	mi := &file_coordination_v1_coordination_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Recommendation.ProtoReflect.Descriptor instead.
+func (*Recommendation) Descriptor() ([]byte, []int) {
+	return file_coordination_v1_coordination_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Recommendation) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Recommendation) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Recommendation) GetIssueType() string {
+	if x != nil {
+		return x.IssueType
+	}
+	return ""
+}
+
+func (x *Recommendation) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+func (x *Recommendation) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *Recommendation) GetSeverity() string {
+	if x != nil {
+		return x.Severity
+	}
+	return ""
+}
+
+func (x *Recommendation) GetConfidence() float64 {
+	if x != nil {
+		return x.Confidence
+	}
+	return 0
+}
+
+func (x *Recommendation) GetPredictedTime() string {
+	if x != nil {
+		return x.PredictedTime
+	}
+	return ""
+}
+
+func (x *Recommendation) GetRecommendedActions() []string {
+	if x != nil {
+		return x.RecommendedActions
+	}
+	return nil
+}
+
+func (x *Recommendation) GetEvidence() []string {
+	if x != nil {
+		return x.Evidence
+	}
+	return nil
+}
+
+func (x *Recommendation) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *Recommendation) GetRelatedIncidentId() string {
+	if x != nil {
+		return x.RelatedIncidentId
+	}
+	return ""
+}
+
+type RecommendResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Timeframe       string                 `protobuf:"bytes,1,opt,name=timeframe,proto3" json:"timeframe,omitempty"`
+	Recommendations []*Recommendation      `protobuf:"bytes,2,rep,name=recommendations,proto3" json:"recommendations,omitempty"`
+	MlEnabled       bool                   `protobuf:"varint,3,opt,name=ml_enabled,json=mlEnabled,proto3" json:"ml_enabled,omitempty"`
+	Message         string                 `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *RecommendResponse) Reset() {
+	*x = RecommendResponse{}
+	mi := &file_coordination_v1_coordination_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecommendResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecommendResponse) ProtoMessage() {}
+
+func (x *RecommendResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_coordination_v1_coordination_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecommendResponse.ProtoReflect.Descriptor instead.
+func (*RecommendResponse) Descriptor() ([]byte, []int) {
+	return file_coordination_v1_coordination_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *RecommendResponse) GetTimeframe() string {
+	if x != nil {
+		return x.Timeframe
+	}
+	return ""
+}
+
+func (x *RecommendResponse) GetRecommendations() []*Recommendation {
+	if x != nil {
+		return x.Recommendations
+	}
+	return nil
+}
+
+func (x *RecommendResponse) GetMlEnabled() bool {
+	if x != nil {
+		return x.MlEnabled
+	}
+	return false
+}
+
+func (x *RecommendResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type Incident struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Id                string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title             string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Description       string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Target            string                 `protobuf:"bytes,4,opt,name=target,proto3" json:"target,omitempty"`
+	Severity          string                 `protobuf:"bytes,5,opt,name=severity,proto3" json:"severity,omitempty"`
+	Status            string                 `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"`
+	CreatedAt         *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt         *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	AffectedResources []string               `protobuf:"bytes,9,rep,name=affected_resources,json=affectedResources,proto3" json:"affected_resources,omitempty"`
+	Labels            map[string]string      `protobuf:"bytes,10,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	WorkflowId        string                 `protobuf:"bytes,11,opt,name=workflow_id,json=workflowId,proto3" json:"workflow_id,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *Incident) Reset() {
+	*x = Incident{}
+	mi := &file_coordination_v1_coordination_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Incident) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Incident) ProtoMessage() {}
+
+func (x *Incident) ProtoReflect() protoreflect.Message {
+	mi := &file_coordination_v1_coordination_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Incident.ProtoReflect.Descriptor instead.
+func (*Incident) Descriptor() ([]byte, []int) {
+	return file_coordination_v1_coordination_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Incident) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Incident) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Incident) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Incident) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+func (x *Incident) GetSeverity() string {
+	if x != nil {
+		return x.Severity
+	}
+	return ""
+}
+
+func (x *Incident) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Incident) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Incident) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Incident) GetAffectedResources() []string {
+	if x != nil {
+		return x.AffectedResources
+	}
+	return nil
+}
+
+func (x *Incident) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *Incident) GetWorkflowId() string {
+	if x != nil {
+		return x.WorkflowId
+	}
+	return ""
+}
+
+type ListIncidentsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Namespace     string                 `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Severity      string                 `protobuf:"bytes,2,opt,name=severity,proto3" json:"severity,omitempty"`
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	Limit         int32                  `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListIncidentsRequest) Reset() {
+	*x = ListIncidentsRequest{}
+	mi := &file_coordination_v1_coordination_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListIncidentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListIncidentsRequest) ProtoMessage() {}
+
+func (x *ListIncidentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_coordination_v1_coordination_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListIncidentsRequest.ProtoReflect.Descriptor instead.
+func (*ListIncidentsRequest) Descriptor() ([]byte, []int) {
+	return file_coordination_v1_coordination_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListIncidentsRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *ListIncidentsRequest) GetSeverity() string {
+	if x != nil {
+		return x.Severity
+	}
+	return ""
+}
+
+func (x *ListIncidentsRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ListIncidentsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ListIncidentsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Incidents     []*Incident            `protobuf:"bytes,1,rep,name=incidents,proto3" json:"incidents,omitempty"`
+	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListIncidentsResponse) Reset() {
+	*x = ListIncidentsResponse{}
+	mi := &file_coordination_v1_coordination_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListIncidentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListIncidentsResponse) ProtoMessage() {}
+
+func (x *ListIncidentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_coordination_v1_coordination_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListIncidentsResponse.ProtoReflect.Descriptor instead.
+func (*ListIncidentsResponse) Descriptor() ([]byte, []int) {
+	return file_coordination_v1_coordination_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ListIncidentsResponse) GetIncidents() []*Incident {
+	if x != nil {
+		return x.Incidents
+	}
+	return nil
+}
+
+func (x *ListIncidentsResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type WatchIncidentsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Namespace     string                 `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Severity      string                 `protobuf:"bytes,2,opt,name=severity,proto3" json:"severity,omitempty"`
+	SendInitial   bool                   `protobuf:"varint,3,opt,name=send_initial,json=sendInitial,proto3" json:"send_initial,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchIncidentsRequest) Reset() {
+	*x = WatchIncidentsRequest{}
+	mi := &file_coordination_v1_coordination_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchIncidentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchIncidentsRequest) ProtoMessage() {}
+
+func (x *WatchIncidentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_coordination_v1_coordination_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchIncidentsRequest.ProtoReflect.Descriptor instead.
+func (*WatchIncidentsRequest) Descriptor() ([]byte, []int) {
+	return file_coordination_v1_coordination_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *WatchIncidentsRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *WatchIncidentsRequest) GetSeverity() string {
+	if x != nil {
+		return x.Severity
+	}
+	return ""
+}
+
+func (x *WatchIncidentsRequest) GetSendInitial() bool {
+	if x != nil {
+		return x.SendInitial
+	}
+	return false
+}
+
+type IncidentEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          IncidentEvent_Type     `protobuf:"varint,1,opt,name=type,proto3,enum=coordination.v1.IncidentEvent_Type" json:"type,omitempty"`
+	Incident      *Incident              `protobuf:"bytes,2,opt,name=incident,proto3" json:"incident,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IncidentEvent) Reset() {
+	*x = IncidentEvent{}
+	mi := &file_coordination_v1_coordination_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IncidentEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IncidentEvent) ProtoMessage() {}
+
+func (x *IncidentEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_coordination_v1_coordination_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IncidentEvent.ProtoReflect.Descriptor instead.
+func (*IncidentEvent) Descriptor() ([]byte, []int) {
+	return file_coordination_v1_coordination_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *IncidentEvent) GetType() IncidentEvent_Type {
+	if x != nil {
+		return x.Type
+	}
+	return IncidentEvent_TYPE_UNSPECIFIED
+}
+
+func (x *IncidentEvent) GetIncident() *Incident {
+	if x != nil {
+		return x.Incident
+	}
+	return nil
+}
+
+var File_coordination_v1_coordination_proto protoreflect.FileDescriptor
+
+const file_coordination_v1_coordination_proto_rawDesc = "" +
+	"\n" +
+	"\"coordination/v1/coordination.proto\x12\x0fcoordination.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xe3\x01\n" +
+	"\x0eAnalyzeRequest\x12\x1d\n" +
+	"\n" +
+	"time_range\x18\x01 \x01(\tR\ttimeRange\x12\x1c\n" +
+	"\tnamespace\x18\x02 \x01(\tR\tnamespace\x12\x1e\n" +
+	"\n" +
+	"deployment\x18\x03 \x01(\tR\n" +
+	"deployment\x12\x10\n" +
+	"\x03pod\x18\x04 \x01(\tR\x03pod\x12%\n" +
+	"\x0elabel_selector\x18\x05 \x01(\tR\rlabelSelector\x12\x1c\n" +
+	"\tthreshold\x18\x06 \x01(\x01R\tthreshold\x12\x1d\n" +
+	"\n" +
+	"model_name\x18\a \x01(\tR\tmodelName\"\x8d\x01\n" +
+	"\fAnomalyScope\x12\x1c\n" +
+	"\tnamespace\x18\x01 \x01(\tR\tnamespace\x12\x1e\n" +
+	"\n" +
+	"deployment\x18\x02 \x01(\tR\n" +
+	"deployment\x12\x10\n" +
+	"\x03pod\x18\x03 \x01(\tR\x03pod\x12-\n" +
+	"\x12target_description\x18\x04 \x01(\tR\x11targetDescription\"\x87\x03\n" +
+	"\aAnomaly\x12\x1c\n" +
+	"\ttimestamp\x18\x01 \x01(\tR\ttimestamp\x12\x1a\n" +
+	"\bseverity\x18\x02 \x01(\tR\bseverity\x12#\n" +
+	"\ranomaly_score\x18\x03 \x01(\x01R\fanomalyScore\x12\x1e\n" +
+	"\n" +
+	"confidence\x18\x04 \x01(\x01R\n" +
+	"confidence\x12?\n" +
+	"\ametrics\x18\x05 \x03(\v2%.coordination.v1.Anomaly.MetricsEntryR\ametrics\x12 \n" +
+	"\vexplanation\x18\x06 \x01(\tR\vexplanation\x12-\n" +
+	"\x12recommended_action\x18\a \x01(\tR\x11recommendedAction\x12/\n" +
+	"\x13alternative_actions\x18\b \x03(\tR\x12alternativeActions\x1a:\n" +
+	"\fMetricsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value:\x028\x01\"\xac\x01\n" +
+	"\x0eAnomalySummary\x12\x1b\n" +
+	"\tmax_score\x18\x01 \x01(\x01R\bmaxScore\x12#\n" +
+	"\raverage_score\x18\x02 \x01(\x01R\faverageScore\x12)\n" +
+	"\x10metrics_analyzed\x18\x03 \x01(\x05R\x0fmetricsAnalyzed\x12-\n" +
+	"\x12features_generated\x18\x04 \x01(\x05R\x11featuresGenerated\"\xce\x02\n" +
+	"\x0fAnalyzeResponse\x12\x1d\n" +
+	"\n" +
+	"time_range\x18\x01 \x01(\tR\ttimeRange\x123\n" +
+	"\x05scope\x18\x02 \x01(\v2\x1d.coordination.v1.AnomalyScopeR\x05scope\x12\x1d\n" +
+	"\n" +
+	"model_used\x18\x03 \x01(\tR\tmodelUsed\x12-\n" +
+	"\x12anomalies_detected\x18\x04 \x01(\x05R\x11anomaliesDetected\x126\n" +
+	"\tanomalies\x18\x05 \x03(\v2\x18.coordination.v1.AnomalyR\tanomalies\x129\n" +
+	"\asummary\x18\x06 \x01(\v2\x1f.coordination.v1.AnomalySummaryR\asummary\x12&\n" +
+	"\x0erecommendation\x18\a \x01(\tR\x0erecommendation\"\xc0\x01\n" +
+	"\x0ePredictRequest\x12\x12\n" +
+	"\x04hour\x18\x01 \x01(\x05R\x04hour\x12\x1e\n" +
+	"\vday_of_week\x18\x02 \x01(\x05R\tdayOfWeek\x12\x1c\n" +
+	"\tnamespace\x18\x03 \x01(\tR\tnamespace\x12\x1e\n" +
+	"\n" +
+	"deployment\x18\x04 \x01(\tR\n" +
+	"deployment\x12\x10\n" +
+	"\x03pod\x18\x05 \x01(\tR\x03pod\x12\x14\n" +
+	"\x05scope\x18\x06 \x01(\tR\x05scope\x12\x14\n" +
+	"\x05model\x18\a \x01(\tR\x05model\"\x84\x03\n" +
+	"\x0fPredictResponse\x12\x14\n" +
+	"\x05scope\x18\x01 \x01(\tR\x05scope\x12\x16\n" +
+	"\x06target\x18\x02 \x01(\tR\x06target\x12\x1f\n" +
+	"\vcpu_percent\x18\x03 \x01(\x01R\n" +
+	"cpuPercent\x12%\n" +
+	"\x0ememory_percent\x18\x04 \x01(\x01R\rmemoryPercent\x127\n" +
+	"\x18current_cpu_rolling_mean\x18\x05 \x01(\x01R\x15currentCpuRollingMean\x12=\n" +
+	"\x1bcurrent_memory_rolling_mean\x18\x06 \x01(\x01R\x18currentMemoryRollingMean\x12\x1d\n" +
+	"\n" +
+	"model_name\x18\a \x01(\tR\tmodelName\x12#\n" +
+	"\rmodel_version\x18\b \x01(\tR\fmodelVersion\x12\x1e\n" +
+	"\n" +
+	"confidence\x18\t \x01(\x01R\n" +
+	"confidence\x12\x1f\n" +
+	"\vtarget_time\x18\n" +
+	" \x01(\tR\n" +
+	"targetTime\"\xcf\x01\n" +
+	"\x10RecommendRequest\x12\x1c\n" +
+	"\ttimeframe\x18\x01 \x01(\tR\ttimeframe\x124\n" +
+	"\x13include_predictions\x18\x02 \x01(\bH\x00R\x12includePredictions\x88\x01\x01\x121\n" +
+	"\x14confidence_threshold\x18\x03 \x01(\x01R\x13confidenceThreshold\x12\x1c\n" +
+	"\tnamespace\x18\x04 \x01(\tR\tnamespaceB\x16\n" +
+	"\x14_include_predictions\"\x81\x03\n" +
+	"\x0eRecommendation\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x1d\n" +
+	"\n" +
+	"issue_type\x18\x03 \x01(\tR\tissueType\x12\x16\n" +
+	"\x06target\x18\x04 \x01(\tR\x06target\x12\x1c\n" +
+	"\tnamespace\x18\x05 \x01(\tR\tnamespace\x12\x1a\n" +
+	"\bseverity\x18\x06 \x01(\tR\bseverity\x12\x1e\n" +
+	"\n" +
+	"confidence\x18\a \x01(\x01R\n" +
+	"confidence\x12%\n" +
+	"\x0epredicted_time\x18\b \x01(\tR\rpredictedTime\x12/\n" +
+	"\x13recommended_actions\x18\t \x03(\tR\x12recommendedActions\x12\x1a\n" +
+	"\bevidence\x18\n" +
+	" \x03(\tR\bevidence\x12\x16\n" +
+	"\x06source\x18\v \x01(\tR\x06source\x12.\n" +
+	"\x13related_incident_id\x18\f \x01(\tR\x11relatedIncidentId\"\xb5\x01\n" +
+	"\x11RecommendResponse\x12\x1c\n" +
+	"\ttimeframe\x18\x01 \x01(\tR\ttimeframe\x12I\n" +
+	"\x0frecommendations\x18\x02 \x03(\v2\x1f.coordination.v1.RecommendationR\x0frecommendations\x12\x1d\n" +
+	"\n" +
+	"ml_enabled\x18\x03 \x01(\bR\tmlEnabled\x12\x18\n" +
+	"\amessage\x18\x04 \x01(\tR\amessage\"\xde\x03\n" +
+	"\bIncident\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x16\n" +
+	"\x06target\x18\x04 \x01(\tR\x06target\x12\x1a\n" +
+	"\bseverity\x18\x05 \x01(\tR\bseverity\x12\x16\n" +
+	"\x06status\x18\x06 \x01(\tR\x06status\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12-\n" +
+	"\x12affected_resources\x18\t \x03(\tR\x11affectedResources\x12=\n" +
+	"\x06labels\x18\n" +
+	" \x03(\v2%.coordination.v1.Incident.LabelsEntryR\x06labels\x12\x1f\n" +
+	"\vworkflow_id\x18\v \x01(\tR\n" +
+	"workflowId\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"~\n" +
+	"\x14ListIncidentsRequest\x12\x1c\n" +
+	"\tnamespace\x18\x01 \x01(\tR\tnamespace\x12\x1a\n" +
+	"\bseverity\x18\x02 \x01(\tR\bseverity\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\x05R\x05limit\"f\n" +
+	"\x15ListIncidentsResponse\x127\n" +
+	"\tincidents\x18\x01 \x03(\v2\x19.coordination.v1.IncidentR\tincidents\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\"t\n" +
+	"\x15WatchIncidentsRequest\x12\x1c\n" +
+	"\tnamespace\x18\x01 \x01(\tR\tnamespace\x12\x1a\n" +
+	"\bseverity\x18\x02 \x01(\tR\bseverity\x12!\n" +
+	"\fsend_initial\x18\x03 \x01(\bR\vsendInitial\"\xd3\x01\n" +
+	"\rIncidentEvent\x127\n" +
+	"\x04type\x18\x01 \x01(\x0e2#.coordination.v1.IncidentEvent.TypeR\x04type\x125\n" +
+	"\bincident\x18\x02 \x01(\v2\x19.coordination.v1.IncidentR\bincident\"R\n" +
+	"\x04Type\x12\x14\n" +
+	"\x10TYPE_UNSPECIFIED\x10\x00\x12\x10\n" +
+	"\fTYPE_CREATED\x10\x01\x12\x10\n" +
+	"\fTYPE_UPDATED\x10\x02\x12\x10\n" +
+	"\fTYPE_DELETED\x10\x032\xc1\x03\n" +
+	"\x13CoordinationService\x12L\n" +
+	"\aAnalyze\x12\x1f.coordination.v1.AnalyzeRequest\x1a .coordination.v1.AnalyzeResponse\x12L\n" +
+	"\aPredict\x12\x1f.coordination.v1.PredictRequest\x1a .coordination.v1.PredictResponse\x12R\n" +
+	"\tRecommend\x12!.coordination.v1.RecommendRequest\x1a\".coordination.v1.RecommendResponse\x12^\n" +
+	"\rListIncidents\x12%.coordination.v1.ListIncidentsRequest\x1a&.coordination.v1.ListIncidentsResponse\x12Z\n" +
+	"\x0eWatchIncidents\x12&.coordination.v1.WatchIncidentsRequest\x1a\x1e.coordination.v1.IncidentEvent0\x01B^Z\\github.com/tosin2013/openshift-coordination-engine/pkg/grpcapi/coordinationv1;coordinationv1b\x06proto3"
+
+var (
+	file_coordination_v1_coordination_proto_rawDescOnce sync.Once
+	file_coordination_v1_coordination_proto_rawDescData []byte
+)
+
+func file_coordination_v1_coordination_proto_rawDescGZIP() []byte {
+	file_coordination_v1_coordination_proto_rawDescOnce.Do(func() {
+		file_coordination_v1_coordination_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_coordination_v1_coordination_proto_rawDesc), len(file_coordination_v1_coordination_proto_rawDesc)))
+	})
+	return file_coordination_v1_coordination_proto_rawDescData
+}
+
+var file_coordination_v1_coordination_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_coordination_v1_coordination_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
+var file_coordination_v1_coordination_proto_goTypes = []any{
+	(IncidentEvent_Type)(0),       // 0: coordination.v1.IncidentEvent.Type
+	(*AnalyzeRequest)(nil),        // 1: coordination.v1.AnalyzeRequest
+	(*AnomalyScope)(nil),          // 2: coordination.v1.AnomalyScope
+	(*Anomaly)(nil),               // 3: coordination.v1.Anomaly
+	(*AnomalySummary)(nil),        // 4: coordination.v1.AnomalySummary
+	(*AnalyzeResponse)(nil),       // 5: coordination.v1.AnalyzeResponse
+	(*PredictRequest)(nil),        // 6: coordination.v1.PredictRequest
+	(*PredictResponse)(nil),       // 7: coordination.v1.PredictResponse
+	(*RecommendRequest)(nil),      // 8: coordination.v1.RecommendRequest
+	(*Recommendation)(nil),        // 9: coordination.v1.Recommendation
+	(*RecommendResponse)(nil),     // 10: coordination.v1.RecommendResponse
+	(*Incident)(nil),              // 11: coordination.v1.Incident
+	(*ListIncidentsRequest)(nil),  // 12: coordination.v1.ListIncidentsRequest
+	(*ListIncidentsResponse)(nil), // 13: coordination.v1.ListIncidentsResponse
+	(*WatchIncidentsRequest)(nil), // 14: coordination.v1.WatchIncidentsRequest
+	(*IncidentEvent)(nil),         // 15: coordination.v1.IncidentEvent
+	nil,                           // 16: coordination.v1.Anomaly.MetricsEntry
+	nil,                           // 17: coordination.v1.Incident.LabelsEntry
+	(*timestamppb.Timestamp)(nil), // 18: google.protobuf.Timestamp
+}
+var file_coordination_v1_coordination_proto_depIdxs = []int32{
+	16, // 0: coordination.v1.Anomaly.metrics:type_name -> coordination.v1.Anomaly.MetricsEntry
+	2,  // 1: coordination.v1.AnalyzeResponse.scope:type_name -> coordination.v1.AnomalyScope
+	3,  // 2: coordination.v1.AnalyzeResponse.anomalies:type_name -> coordination.v1.Anomaly
+	4,  // 3: coordination.v1.AnalyzeResponse.summary:type_name -> coordination.v1.AnomalySummary
+	9,  // 4: coordination.v1.RecommendResponse.recommendations:type_name -> coordination.v1.Recommendation
+	18, // 5: coordination.v1.Incident.created_at:type_name -> google.protobuf.Timestamp
+	18, // 6: coordination.v1.Incident.updated_at:type_name -> google.protobuf.Timestamp
+	17, // 7: coordination.v1.Incident.labels:type_name -> coordination.v1.Incident.LabelsEntry
+	11, // 8: coordination.v1.ListIncidentsResponse.incidents:type_name -> coordination.v1.Incident
+	0,  // 9: coordination.v1.IncidentEvent.type:type_name -> coordination.v1.IncidentEvent.Type
+	11, // 10: coordination.v1.IncidentEvent.incident:type_name -> coordination.v1.Incident
+	1,  // 11: coordination.v1.CoordinationService.Analyze:input_type -> coordination.v1.AnalyzeRequest
+	6,  // 12: coordination.v1.CoordinationService.Predict:input_type -> coordination.v1.PredictRequest
+	8,  // 13: coordination.v1.CoordinationService.Recommend:input_type -> coordination.v1.RecommendRequest
+	12, // 14: coordination.v1.CoordinationService.ListIncidents:input_type -> coordination.v1.ListIncidentsRequest
+	14, // 15: coordination.v1.CoordinationService.WatchIncidents:input_type -> coordination.v1.WatchIncidentsRequest
+	5,  // 16: coordination.v1.CoordinationService.Analyze:output_type -> coordination.v1.AnalyzeResponse
+	7,  // 17: coordination.v1.CoordinationService.Predict:output_type -> coordination.v1.PredictResponse
+	10, // 18: coordination.v1.CoordinationService.Recommend:output_type -> coordination.v1.RecommendResponse
+	13, // 19: coordination.v1.CoordinationService.ListIncidents:output_type -> coordination.v1.ListIncidentsResponse
+	15, // 20: coordination.v1.CoordinationService.WatchIncidents:output_type -> coordination.v1.IncidentEvent
+	16, // [16:21] is the sub-list for method output_type
+	11, // [11:16] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
+}
+
+func init() { file_coordination_v1_coordination_proto_init() }
+func file_coordination_v1_coordination_proto_init() {
+	if File_coordination_v1_coordination_proto != nil {
+		return
+	}
+	file_coordination_v1_coordination_proto_msgTypes[7].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_coordination_v1_coordination_proto_rawDesc), len(file_coordination_v1_coordination_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   17,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_coordination_v1_coordination_proto_goTypes,
+		DependencyIndexes: file_coordination_v1_coordination_proto_depIdxs,
+		EnumInfos:         file_coordination_v1_coordination_proto_enumTypes,
+		MessageInfos:      file_coordination_v1_coordination_proto_msgTypes,
+	}.Build()
+	File_coordination_v1_coordination_proto = out.File
+	file_coordination_v1_coordination_proto_goTypes = nil
+	file_coordination_v1_coordination_proto_depIdxs = nil
+}
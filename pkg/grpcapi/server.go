@@ -0,0 +1,265 @@
+// Package grpcapi serves the coordination engine's gRPC API for machine clients.
+//
+// The gRPC service mirrors the REST API and shares its handler logic: requests are
+// converted to the /api/v1 request types, run through the same Analyze, Predict and
+// Recommend methods, and the responses converted back to protobuf messages.
+package grpcapi
+
+import (
+	"context"
+	"crypto/tls"
+	"runtime/debug"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/apitoken"
+	"github.com/tosin2013/openshift-coordination-engine/internal/storage"
+	v1 "github.com/tosin2013/openshift-coordination-engine/pkg/api/v1"
+	pb "github.com/tosin2013/openshift-coordination-engine/pkg/grpcapi/coordinationv1"
+)
+
+const (
+	// DefaultIncidentLimit matches the default page size of GET /api/v1/incidents
+	DefaultIncidentLimit = 50
+
+	// watchBufferSize is the number of incident changes buffered per WatchIncidents stream
+	watchBufferSize = 64
+)
+
+// Server implements the CoordinationService gRPC API
+type Server struct {
+	pb.UnimplementedCoordinationServiceServer
+
+	anomaly         *v1.AnomalyHandler
+	prediction      *v1.PredictionHandler
+	recommendations *v1.RecommendationsHandler
+	incidents       *storage.IncidentStore
+	log             *logrus.Logger
+}
+
+// NewServer creates a gRPC server backed by the REST handlers' shared logic
+func NewServer(
+	anomaly *v1.AnomalyHandler,
+	prediction *v1.PredictionHandler,
+	recommendations *v1.RecommendationsHandler,
+	incidents *storage.IncidentStore,
+	log *logrus.Logger,
+) *Server {
+	return &Server{
+		anomaly:         anomaly,
+		prediction:      prediction,
+		recommendations: recommendations,
+		incidents:       incidents,
+		log:             log,
+	}
+}
+
+// ServerOptions secures the gRPC server
+type ServerOptions struct {
+	// Tokens checks API token scopes on CoordinationService calls, as on the REST API
+	Tokens *apitoken.Store
+
+	// TokensRequired rejects calls without an API token; otherwise they pass unchecked
+	TokensRequired bool
+
+	// TLS serves the API over TLS instead of plaintext. With mutual TLS it must require
+	// and verify client certificates, see mtls.Server.StrictTLSConfig.
+	TLS *tls.Config
+}
+
+// NewGRPCServer creates a grpc.Server with the coordination, standard health and
+// reflection services registered, plus panic recovery, request logging and, with
+// API tokens, scope checking interceptors
+func NewGRPCServer(srv *Server, opts ServerOptions, log *logrus.Logger) *grpc.Server {
+	unary := []grpc.UnaryServerInterceptor{unaryRecovery(log), unaryLogger(log)}
+	stream := []grpc.StreamServerInterceptor{streamRecovery(log), streamLogger(log)}
+	if opts.Tokens != nil {
+		auth := &tokenAuth{store: opts.Tokens, required: opts.TokensRequired, log: log}
+		unary = append(unary, auth.unary())
+		stream = append(stream, auth.stream())
+	}
+
+	serverOpts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...),
+	}
+	if opts.TLS != nil {
+		serverOpts = append(serverOpts, grpc.Creds(credentials.NewTLS(opts.TLS)))
+	}
+	grpcServer := grpc.NewServer(serverOpts...)
+	pb.RegisterCoordinationServiceServer(grpcServer, srv)
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus(pb.CoordinationService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+
+	// Reflection lets tools like grpcurl discover the schema without the .proto files
+	reflection.Register(grpcServer)
+
+	return grpcServer
+}
+
+// Analyze runs ML anomaly analysis
+func (s *Server) Analyze(ctx context.Context, req *pb.AnalyzeRequest) (*pb.AnalyzeResponse, error) {
+	resp, err := s.anomaly.Analyze(ctx, analyzeRequestFromProto(req))
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	return analyzeResponseToProto(resp), nil
+}
+
+// Predict forecasts resource usage at a time of day
+func (s *Server) Predict(ctx context.Context, req *pb.PredictRequest) (*pb.PredictResponse, error) {
+	resp, err := s.prediction.Predict(ctx, predictRequestFromProto(req))
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	return predictResponseToProto(resp), nil
+}
+
+// Recommend returns remediation recommendations
+func (s *Server) Recommend(ctx context.Context, req *pb.RecommendRequest) (*pb.RecommendResponse, error) {
+	resp, err := s.recommendations.Recommend(ctx, recommendRequestFromProto(req))
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	return recommendResponseToProto(resp), nil
+}
+
+// ListIncidents lists stored incidents, newest first
+func (s *Server) ListIncidents(_ context.Context, req *pb.ListIncidentsRequest) (*pb.ListIncidentsResponse, error) {
+	if req.GetLimit() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "limit cannot be negative")
+	}
+
+	limit := int(req.GetLimit())
+	if limit == 0 {
+		limit = DefaultIncidentLimit
+	}
+
+	stored := s.incidents.List(storage.ListFilter{
+		Namespace: req.GetNamespace(),
+		Severity:  req.GetSeverity(),
+		Status:    req.GetStatus(),
+		Limit:     limit,
+	})
+
+	incidents := make([]*pb.Incident, 0, len(stored))
+	for _, inc := range stored {
+		incidents = append(incidents, incidentToProto(inc))
+	}
+
+	return &pb.ListIncidentsResponse{
+		Incidents: incidents,
+		Total:     int32(len(incidents)), //nolint:gosec // bounded by the list limit
+	}, nil
+}
+
+// WatchIncidents streams incident changes until the client cancels the stream
+func (s *Server) WatchIncidents(req *pb.WatchIncidentsRequest, stream grpc.ServerStreamingServer[pb.IncidentEvent]) error {
+	// Subscribe before listing so no change between the two is missed
+	changes, unsubscribe := s.incidents.Subscribe(watchBufferSize)
+	defer unsubscribe()
+
+	filter := storage.ListFilter{
+		Namespace: req.GetNamespace(),
+		Severity:  req.GetSeverity(),
+	}
+
+	if req.GetSendInitial() {
+		for _, inc := range s.incidents.List(filter) {
+			event := &pb.IncidentEvent{Type: pb.IncidentEvent_TYPE_CREATED, Incident: incidentToProto(inc)}
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+		}
+	}
+
+	ctx := stream.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case change, ok := <-changes:
+			if !ok {
+				return status.Error(codes.Unavailable, "incident watch closed")
+			}
+			if !filter.Matches(&change.Incident) {
+				continue
+			}
+			if err := stream.Send(incidentChangeToProto(&change)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// unaryRecovery converts panics in unary handlers into Internal errors
+func unaryRecovery(log *logrus.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				log.WithFields(logrus.Fields{
+					"method": info.FullMethod,
+					"panic":  p,
+					"stack":  string(debug.Stack()),
+				}).Error("Panic recovered in gRPC handler")
+				err = status.Error(codes.Internal, "internal server error")
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// streamRecovery converts panics in streaming handlers into Internal errors
+func streamRecovery(log *logrus.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				log.WithFields(logrus.Fields{
+					"method": info.FullMethod,
+					"panic":  p,
+					"stack":  string(debug.Stack()),
+				}).Error("Panic recovered in gRPC stream handler")
+				err = status.Error(codes.Internal, "internal server error")
+			}
+		}()
+		return handler(srv, ss)
+	}
+}
+
+// unaryLogger logs each unary call with its status code and duration
+func unaryLogger(log *logrus.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		log.WithFields(logrus.Fields{
+			"method":   info.FullMethod,
+			"code":     status.Code(err).String(),
+			"duration": time.Since(start).String(),
+		}).Info("gRPC request completed")
+		return resp, err
+	}
+}
+
+// streamLogger logs each stream when it ends with its status code and duration
+func streamLogger(log *logrus.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		log.WithFields(logrus.Fields{
+			"method":   info.FullMethod,
+			"code":     status.Code(err).String(),
+			"duration": time.Since(start).String(),
+		}).Info("gRPC stream completed")
+		return err
+	}
+}
@@ -0,0 +1,258 @@
+package grpcapi
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/apitoken"
+	"github.com/tosin2013/openshift-coordination-engine/internal/storage"
+	v1 "github.com/tosin2013/openshift-coordination-engine/pkg/api/v1"
+	pb "github.com/tosin2013/openshift-coordination-engine/pkg/grpcapi/coordinationv1"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// newTestClient serves a Server without KServe over an in-memory connection
+func newTestClient(t *testing.T) (pb.CoordinationServiceClient, *storage.IncidentStore) {
+	t.Helper()
+	return newTestClientWithOptions(t, ServerOptions{})
+}
+
+// newTestClientWithOptions is newTestClient with server options
+func newTestClientWithOptions(t *testing.T, opts ServerOptions) (pb.CoordinationServiceClient, *storage.IncidentStore) {
+	t.Helper()
+
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	store := storage.NewIncidentStoreWithPath(t.TempDir())
+	srv := NewServer(
+		v1.NewAnomalyHandler(nil, nil, log),
+		v1.NewPredictionHandler(nil, nil, log),
+		v1.NewRecommendationsHandler(nil, store, nil, log),
+		store,
+		log,
+	)
+
+	listener := bufconn.Listen(1024 * 1024)
+	grpcServer := NewGRPCServer(srv, opts, log)
+	go func() { _ = grpcServer.Serve(listener) }()
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return pb.NewCoordinationServiceClient(conn), store
+}
+
+func createIncident(t *testing.T, store *storage.IncidentStore, target string, severity models.IncidentSeverity) *models.Incident {
+	t.Helper()
+	inc, err := store.Create(&models.Incident{
+		Title:       "High memory usage",
+		Description: "Memory pressure in " + target,
+		Severity:    severity,
+		Target:      target,
+	})
+	require.NoError(t, err)
+	return inc
+}
+
+func TestServer_ErrorCodes(t *testing.T) {
+	client, _ := newTestClient(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		call func() error
+		code codes.Code
+	}{
+		{
+			name: "analyze invalid time range",
+			call: func() error {
+				_, err := client.Analyze(ctx, &pb.AnalyzeRequest{TimeRange: "2w"})
+				return err
+			},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "analyze without kserve",
+			call: func() error {
+				_, err := client.Analyze(ctx, &pb.AnalyzeRequest{})
+				return err
+			},
+			code: codes.Unavailable,
+		},
+		{
+			name: "predict invalid hour",
+			call: func() error {
+				_, err := client.Predict(ctx, &pb.PredictRequest{Hour: 24})
+				return err
+			},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "predict without kserve",
+			call: func() error {
+				_, err := client.Predict(ctx, &pb.PredictRequest{Hour: 9, DayOfWeek: 1})
+				return err
+			},
+			code: codes.Unavailable,
+		},
+		{
+			name: "recommend invalid timeframe",
+			call: func() error {
+				_, err := client.Recommend(ctx, &pb.RecommendRequest{Timeframe: "2w"})
+				return err
+			},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "list incidents negative limit",
+			call: func() error {
+				_, err := client.ListIncidents(ctx, &pb.ListIncidentsRequest{Limit: -1})
+				return err
+			},
+			code: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			require.Error(t, err)
+			assert.Equal(t, tt.code, status.Code(err))
+		})
+	}
+}
+
+func TestServer_FieldViolations(t *testing.T) {
+	client, _ := newTestClient(t)
+
+	_, err := client.Predict(context.Background(), &pb.PredictRequest{Hour: 24, DayOfWeek: 9})
+	require.Error(t, err)
+	st := status.Convert(err)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+
+	require.Len(t, st.Details(), 1)
+	badRequest, ok := st.Details()[0].(*errdetails.BadRequest)
+	require.True(t, ok)
+	fields := make([]string, 0, len(badRequest.GetFieldViolations()))
+	for _, violation := range badRequest.GetFieldViolations() {
+		fields = append(fields, violation.GetField())
+	}
+	assert.Equal(t, []string{"hour", "day_of_week"}, fields)
+}
+
+func TestServer_Recommend(t *testing.T) {
+	client, store := newTestClient(t)
+	createIncident(t, store, "production", models.IncidentSeverityHigh)
+	createIncident(t, store, "production", models.IncidentSeverityHigh)
+
+	resp, err := client.Recommend(context.Background(), &pb.RecommendRequest{ConfidenceThreshold: 0.1})
+	require.NoError(t, err)
+
+	assert.Equal(t, "6h", resp.GetTimeframe())
+	assert.False(t, resp.GetMlEnabled())
+	assert.NotEmpty(t, resp.GetRecommendations())
+}
+
+func TestServer_ListIncidents(t *testing.T) {
+	client, store := newTestClient(t)
+	created := createIncident(t, store, "production", models.IncidentSeverityHigh)
+	createIncident(t, store, "staging", models.IncidentSeverityLow)
+
+	resp, err := client.ListIncidents(context.Background(), &pb.ListIncidentsRequest{Namespace: "production"})
+	require.NoError(t, err)
+
+	require.Len(t, resp.GetIncidents(), 1)
+	assert.Equal(t, int32(1), resp.GetTotal())
+	inc := resp.GetIncidents()[0]
+	assert.Equal(t, created.ID, inc.GetId())
+	assert.Equal(t, "high", inc.GetSeverity())
+	assert.Equal(t, "active", inc.GetStatus())
+	assert.Equal(t, created.CreatedAt.Unix(), inc.GetCreatedAt().AsTime().Unix())
+}
+
+func TestServer_WatchIncidents(t *testing.T) {
+	client, store := newTestClient(t)
+	existing := createIncident(t, store, "production", models.IncidentSeverityHigh)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.WatchIncidents(ctx, &pb.WatchIncidentsRequest{Namespace: "production", SendInitial: true})
+	require.NoError(t, err)
+
+	event, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, pb.IncidentEvent_TYPE_CREATED, event.GetType())
+	assert.Equal(t, existing.ID, event.GetIncident().GetId())
+
+	// Changes outside the watched namespace are filtered out
+	createIncident(t, store, "staging", models.IncidentSeverityHigh)
+
+	existing.Status = models.IncidentStatusResolved
+	require.NoError(t, store.Update(existing))
+
+	event, err = stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, pb.IncidentEvent_TYPE_UPDATED, event.GetType())
+	assert.Equal(t, existing.ID, event.GetIncident().GetId())
+	assert.Equal(t, "resolved", event.GetIncident().GetStatus())
+
+	require.NoError(t, store.Delete(existing.ID))
+
+	event, err = stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, pb.IncidentEvent_TYPE_DELETED, event.GetType())
+	assert.Equal(t, existing.ID, event.GetIncident().GetId())
+}
+
+func TestServer_APITokens(t *testing.T) {
+	tokens := apitoken.NewStore(t.TempDir())
+	client, _ := newTestClientWithOptions(t, ServerOptions{Tokens: tokens, TokensRequired: true})
+
+	reader, _, err := tokens.Create("reader", []string{"read:incidents"}, 0)
+	require.NoError(t, err)
+	analyst, _, err := tokens.Create("analyst", []string{"read:anomalies"}, 0)
+	require.NoError(t, err)
+
+	withToken := func(secret string) context.Context {
+		return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+secret)
+	}
+
+	_, err = client.ListIncidents(context.Background(), &pb.ListIncidentsRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = client.ListIncidents(withToken("cet_invalid"), &pb.ListIncidentsRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = client.ListIncidents(withToken(analyst), &pb.ListIncidentsRequest{})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = client.ListIncidents(withToken(reader), &pb.ListIncidentsRequest{})
+	assert.NoError(t, err)
+
+	stream, err := client.WatchIncidents(withToken(analyst), &pb.WatchIncidentsRequest{})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
@@ -0,0 +1,104 @@
+package kserve
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// InferResponse is a model's answer to a raw inference request
+type InferResponse struct {
+	// StatusCode is the status the model answered with
+	StatusCode int
+
+	// ContentType is the model's Content-Type header
+	ContentType string
+
+	// Body is the unmodified response body
+	Body []byte
+
+	// ModelVersion is the rollout version that served the request, empty without a rollout
+	ModelVersion string
+}
+
+// Infer forwards a raw KServe v1 request body to a model's predictor and returns its
+// answer without interpreting it, so custom models can be served through the engine
+// without model-specific code. Rollout routing and adaptive timeouts apply as for
+// Predict. Non-2xx answers are returned as responses, not errors, but count as failed
+// requests in the rollout statistics.
+func (c *ProxyClient) Infer(ctx context.Context, modelName string, body []byte, contentType string) (result *InferResponse, err error) {
+	model, version, exists := c.route(modelName, c.canaryAllowed(ctx, modelName))
+	if !exists {
+		return nil, &ModelNotFoundError{ModelName: modelName}
+	}
+	if version != "" {
+		start := time.Now()
+		defer func() {
+			if err == nil && (result.StatusCode < 200 || result.StatusCode >= 300) {
+				c.rollouts.record(modelName, version, time.Since(start), fmt.Errorf("model returned status %d", result.StatusCode), false)
+				return
+			}
+			c.rollouts.record(modelName, version, time.Since(start), err, false)
+		}()
+	}
+
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	endpoint := fmt.Sprintf("%s/v1/models/model:predict", model.URL)
+
+	reqCtx, cancel := c.requestContext(ctx, modelName, version)
+	defer cancel()
+	httpReq, err := http.NewRequestWithContext(reqCtx, "POST", endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", contentType)
+	httpReq.Header.Set("Accept", "application/json")
+
+	startTime := time.Now()
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		c.observeLatency(ctx, modelName, version, time.Since(startTime), err)
+		c.log.WithFields(logrus.Fields{
+			"model":    modelName,
+			"endpoint": endpoint,
+		}).WithError(err).Error("KServe infer request failed")
+		return nil, &ModelUnavailableError{ModelName: modelName, Cause: err}
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			c.log.WithError(closeErr).Warn("Failed to close response body")
+		}
+	}()
+
+	respBody, err := io.ReadAll(resp.Body)
+	duration := time.Since(startTime)
+	c.observeLatency(ctx, modelName, version, duration, err)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+			return nil, &ModelUnavailableError{ModelName: modelName, Cause: err}
+		}
+		return nil, fmt.Errorf("failed to read response body from model %s: %w", modelName, err)
+	}
+
+	c.log.WithFields(logrus.Fields{
+		"model":    modelName,
+		"endpoint": endpoint,
+		"status":   resp.StatusCode,
+		"duration": duration.Milliseconds(),
+	}).Debug("KServe infer request completed")
+
+	return &InferResponse{
+		StatusCode:   resp.StatusCode,
+		ContentType:  resp.Header.Get("Content-Type"),
+		Body:         respBody,
+		ModelVersion: version,
+	}, nil
+}
@@ -0,0 +1,79 @@
+package kserve
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProxyClient_Infer(t *testing.T) {
+	var received string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/models/model:predict", r.URL.Path)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		body, _ := io.ReadAll(r.Body)
+		received = string(body)
+		w.Header().Set("Content-Type", "application/json")
+		if received == "{}" {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+		_, _ = w.Write([]byte(`{"predictions": [[0.1, 0.9]]}`))
+	}))
+	defer server.Close()
+
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	client, err := NewProxyClient(ProxyConfig{Namespace: "test-ns"}, log)
+	require.NoError(t, err)
+	client.models["custom-model"] = &ModelInfo{Name: "custom-model", URL: "http://unused"}
+	rollouts, err := NewRollouts(map[string]ModelRollout{
+		"custom-model": {Versions: map[string]string{"v1": server.URL}, Pinned: "v1"},
+	})
+	require.NoError(t, err)
+	client.SetRollouts(rollouts)
+
+	resp, err := client.Infer(context.Background(), "custom-model", []byte(`{"instances": [[1, 2, 3]]}`), "")
+	require.NoError(t, err)
+	assert.Equal(t, `{"instances": [[1, 2, 3]]}`, received)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/json", resp.ContentType)
+	assert.Equal(t, `{"predictions": [[0.1, 0.9]]}`, string(resp.Body))
+	assert.Equal(t, "v1", resp.ModelVersion)
+
+	// Model errors are returned as responses but count as failed requests
+	resp, err = client.Infer(context.Background(), "custom-model", []byte(`{}`), "application/json")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	status, ok := client.RolloutStatus("custom-model")
+	require.True(t, ok)
+	require.Len(t, status.Versions, 1)
+	assert.Equal(t, int64(2), status.Versions[0].Requests)
+	assert.Equal(t, int64(1), status.Versions[0].Errors)
+
+	_, err = client.Infer(context.Background(), "missing-model", []byte(`{}`), "")
+	var notFound *ModelNotFoundError
+	assert.True(t, errors.As(err, &notFound))
+}
+
+func TestProxyClient_Infer_Unavailable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	url := server.URL
+	server.Close()
+
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	client, err := NewProxyClient(ProxyConfig{Namespace: "test-ns"}, log)
+	require.NoError(t, err)
+	client.models["custom-model"] = &ModelInfo{Name: "custom-model", URL: url}
+
+	_, err = client.Infer(context.Background(), "custom-model", []byte(`{"instances": [[1]]}`), "")
+	var unavailable *ModelUnavailableError
+	assert.True(t, errors.As(err, &unavailable))
+}
@@ -0,0 +1,63 @@
+package kserve
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// ModelVersionRequests counts requests to models with a rollout by version and outcome
+	ModelVersionRequests = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "coordination_engine_kserve_model_version_requests_total",
+			Help: "Total number of requests to KServe models with a rollout, by model, version and outcome (success, error)",
+		},
+		[]string{"model", "version", "outcome"},
+	)
+
+	// ModelVersionLatency tracks request latency of models with a rollout by version
+	ModelVersionLatency = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "coordination_engine_kserve_model_version_request_duration_seconds",
+			Help:    "Latency of requests to KServe models with a rollout, by model and version",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"model", "version"},
+	)
+
+	// ModelVersionAnomalies counts anomalous predictions of models with a rollout by version
+	ModelVersionAnomalies = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "coordination_engine_kserve_model_version_anomalies_total",
+			Help: "Total number of anomalous predictions from KServe models with a rollout, by model and version",
+		},
+		[]string{"model", "version"},
+	)
+
+	// ModelTimeoutSeconds is the adaptive request timeout of each model version
+	ModelTimeoutSeconds = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "coordination_engine_kserve_model_timeout_seconds",
+			Help: "Adaptive request timeout of KServe models derived from their p95 latency, by model and version",
+		},
+		[]string{"model", "version"},
+	)
+
+	// InferRequests counts raw inference requests through the infer gateway by outcome
+	InferRequests = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "coordination_engine_kserve_infer_requests_total",
+			Help: "Total number of raw inference requests to KServe models, by model and outcome (success, model_error, unavailable, unauthorized, rate_limited, rejected)",
+		},
+		[]string{"model", "outcome"},
+	)
+
+	// ModelChanges counts models added, removed or updated by registry refreshes
+	ModelChanges = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "coordination_engine_kserve_model_registry_changes_total",
+			Help: "Total number of KServe models added, removed or updated by registry refreshes, by action",
+		},
+		[]string{"action"},
+	)
+)
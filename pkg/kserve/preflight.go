@@ -0,0 +1,211 @@
+package kserve
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Pre-flight statuses
+const (
+	// PreflightOK means the model accepted the synthetic feature vector
+	PreflightOK = "ok"
+
+	// PreflightMismatch means the model rejected the vector or answered in an unexpected shape
+	PreflightMismatch = "mismatch"
+
+	// PreflightUnavailable means the model could not be reached
+	PreflightUnavailable = "unavailable"
+
+	// PreflightSkipped means no feature schema is registered for the model
+	PreflightSkipped = "skipped"
+)
+
+// PreflightResult is the outcome of sending a synthetic feature vector to a model
+type PreflightResult struct {
+	// Model is the logical model name
+	Model string `json:"model"`
+
+	// Version is the rollout version checked, empty for the registered service
+	Version string `json:"version,omitempty"`
+
+	// Status is ok, mismatch, unavailable or skipped
+	Status string `json:"status"`
+
+	// Features is the length of the synthetic vector sent
+	Features int `json:"features,omitempty"`
+
+	// Message explains a mismatch or failure
+	Message string `json:"message,omitempty"`
+
+	// LatencyMs is the duration of the warm-up request
+	LatencyMs int64 `json:"latency_ms,omitempty"`
+
+	// CheckedAt is when the check ran
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// preflightState holds the feature schemas and the latest pre-flight results
+type preflightState struct {
+	mu      sync.RWMutex
+	schemas map[string][]string
+	results []PreflightResult
+	ran     bool
+}
+
+// RegisterFeatureSchema records the ordered input features a model expects. It is
+// used to build the synthetic vector sent by Preflight.
+func (c *ProxyClient) RegisterFeatureSchema(modelName string, features []string) {
+	c.preflight.mu.Lock()
+	defer c.preflight.mu.Unlock()
+	if c.preflight.schemas == nil {
+		c.preflight.schemas = make(map[string][]string)
+	}
+	c.preflight.schemas[modelName] = append([]string(nil), features...)
+}
+
+// FeatureSchema returns the input features registered for a model
+func (c *ProxyClient) FeatureSchema(modelName string) ([]string, bool) {
+	c.preflight.mu.RLock()
+	defer c.preflight.mu.RUnlock()
+	features, ok := c.preflight.schemas[modelName]
+	return features, ok
+}
+
+// Preflight warms up every registered model, and each version of its rollout, by
+// sending one synthetic feature vector shaped by the model's feature schema. The
+// results replace those of the previous run and are returned by PreflightResults.
+func (c *ProxyClient) Preflight(ctx context.Context) []PreflightResult {
+	names := c.ListModels()
+	sort.Strings(names)
+
+	var results []PreflightResult
+	for _, name := range names {
+		model, ok := c.GetModel(name)
+		if !ok {
+			continue
+		}
+		features, hasSchema := c.FeatureSchema(name)
+		if !hasSchema {
+			results = append(results, PreflightResult{
+				Model:     name,
+				Status:    PreflightSkipped,
+				Message:   "no feature schema registered",
+				CheckedAt: time.Now(),
+			})
+			continue
+		}
+
+		results = append(results, c.preflightModel(ctx, name, "", model, len(features)))
+		versions := c.VersionModels(name)
+		labels := make([]string, 0, len(versions))
+		for version := range versions {
+			labels = append(labels, version)
+		}
+		sort.Strings(labels)
+		for _, version := range labels {
+			results = append(results, c.preflightModel(ctx, name, version, versions[version], len(features)))
+		}
+	}
+
+	for i := range results {
+		if results[i].Status == PreflightOK || results[i].Status == PreflightSkipped {
+			continue
+		}
+		c.log.WithFields(logrus.Fields{
+			"model":   results[i].Model,
+			"version": results[i].Version,
+			"status":  results[i].Status,
+		}).Warn("KServe model pre-flight check failed: " + results[i].Message)
+	}
+
+	c.preflight.mu.Lock()
+	c.preflight.results = results
+	c.preflight.ran = true
+	c.preflight.mu.Unlock()
+	return results
+}
+
+// PreflightResults returns the results of the latest Preflight run
+func (c *ProxyClient) PreflightResults() []PreflightResult {
+	c.preflight.mu.RLock()
+	defer c.preflight.mu.RUnlock()
+	return append([]PreflightResult(nil), c.preflight.results...)
+}
+
+// preflightModel sends a zero-valued vector of the schema's width to one service
+func (c *ProxyClient) preflightModel(ctx context.Context, name, version string, model *ModelInfo, width int) PreflightResult {
+	result := PreflightResult{Model: name, Version: version, Features: width, CheckedAt: time.Now()}
+
+	jsonData, err := json.Marshal(map[string]interface{}{
+		"instances": [][]float64{make([]float64, width)},
+	})
+	if err != nil {
+		result.Status = PreflightMismatch
+		result.Message = fmt.Sprintf("failed to encode request: %v", err)
+		return result
+	}
+
+	endpoint := fmt.Sprintf("%s/v1/models/model:predict", model.URL)
+	reqCtx, cancel := c.requestContext(ctx, name, version)
+	defer cancel()
+	httpReq, err := http.NewRequestWithContext(reqCtx, "POST", endpoint, bytes.NewBuffer(jsonData))
+	if err != nil {
+		result.Status = PreflightUnavailable
+		result.Message = fmt.Sprintf("failed to create request: %v", err)
+		return result
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/json")
+
+	start := time.Now()
+	resp, err := c.httpClient.Do(httpReq)
+	result.LatencyMs = time.Since(start).Milliseconds()
+	if err != nil {
+		result.Status = PreflightUnavailable
+		result.Message = fmt.Sprintf("request failed: %v", err)
+		return result
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			c.log.WithError(closeErr).Warn("Failed to close pre-flight response body")
+		}
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		result.Status = PreflightUnavailable
+		result.Message = fmt.Sprintf("failed to read response: %v", err)
+		return result
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode >= http.StatusInternalServerError && len(body) == 0:
+		result.Status = PreflightUnavailable
+		result.Message = fmt.Sprintf("model returned status %d", resp.StatusCode)
+		return result
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		result.Status = PreflightMismatch
+		result.Message = fmt.Sprintf("model rejected a %d-feature vector (status %d): %s",
+			width, resp.StatusCode, strings.TrimSpace(string(body)))
+		return result
+	}
+
+	if _, err := c.parseModelResponse(name, body, 1); err != nil {
+		result.Status = PreflightMismatch
+		result.Message = err.Error()
+		return result
+	}
+
+	result.Status = PreflightOK
+	return result
+}
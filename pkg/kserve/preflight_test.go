@@ -0,0 +1,97 @@
+package kserve
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// shapeServer behaves like a KServe sklearn predictor trained on width features
+func shapeServer(t *testing.T, width int) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Instances [][]float64 `json:"instances"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		if got := len(req.Instances[0]); got != width {
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprintf(w, `{"error": "X has %d features, but IsolationForest is expecting %d features as input."}`, got, width)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"predictions": []int{1}})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestProxyClient_Preflight(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	client, err := NewProxyClient(ProxyConfig{Namespace: "test-ns"}, log)
+	require.NoError(t, err)
+	client.models["anomaly-detector"] = &ModelInfo{Name: "anomaly-detector", URL: shapeServer(t, 45).URL}
+	client.models["disk-predictor"] = &ModelInfo{Name: "disk-predictor", URL: shapeServer(t, 3).URL}
+	client.models["offline"] = &ModelInfo{Name: "offline", URL: "http://127.0.0.1:1"}
+	client.models["unknown"] = &ModelInfo{Name: "unknown", URL: "http://127.0.0.1:1"}
+
+	features := make([]string, 45)
+	client.RegisterFeatureSchema("anomaly-detector", features)
+	client.RegisterFeatureSchema("disk-predictor", []string{"read_errors", "reallocated_sectors"})
+	client.RegisterFeatureSchema("offline", []string{"a"})
+
+	results := client.Preflight(context.Background())
+	require.Len(t, results, 4)
+	assert.Equal(t, results, client.PreflightResults())
+
+	byModel := make(map[string]PreflightResult)
+	for _, result := range results {
+		byModel[result.Model] = result
+	}
+
+	assert.Equal(t, PreflightOK, byModel["anomaly-detector"].Status)
+	assert.Equal(t, 45, byModel["anomaly-detector"].Features)
+
+	assert.Equal(t, PreflightMismatch, byModel["disk-predictor"].Status)
+	assert.Contains(t, byModel["disk-predictor"].Message, "rejected a 2-feature vector")
+	assert.Contains(t, byModel["disk-predictor"].Message, "expecting 3 features")
+
+	assert.Equal(t, PreflightUnavailable, byModel["offline"].Status)
+	assert.Equal(t, PreflightSkipped, byModel["unknown"].Status)
+}
+
+func TestProxyClient_Preflight_RolloutVersions(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	client, err := NewProxyClient(ProxyConfig{Namespace: "test-ns"}, log)
+	require.NoError(t, err)
+	client.models["anomaly-detector"] = &ModelInfo{Name: "anomaly-detector", URL: shapeServer(t, 45).URL}
+	client.RegisterFeatureSchema("anomaly-detector", make([]string, 45))
+
+	rollouts, err := NewRollouts(map[string]ModelRollout{
+		"anomaly-detector": {
+			Versions: map[string]string{"v3": shapeServer(t, 48).URL},
+			Canary:   &CanaryRollout{Version: "v3", Percent: 10},
+		},
+	})
+	require.NoError(t, err)
+	client.SetRollouts(rollouts)
+
+	results := client.Preflight(context.Background())
+	require.Len(t, results, 2)
+	assert.Equal(t, PreflightOK, results[0].Status)
+	assert.Equal(t, "v3", results[1].Version)
+	assert.Equal(t, PreflightMismatch, results[1].Status)
+
+	status, _ := client.RolloutStatus("anomaly-detector")
+	assert.Zero(t, status.Versions[1].Requests, "warm-up requests are not rollout outcomes")
+}
@@ -26,6 +26,28 @@ type ProxyClient struct {
 	httpClient    *http.Client
 	log           *logrus.Logger
 	modelsMutex   sync.RWMutex
+
+	// modelsFile adds or overrides models from a file, e.g. a mounted ConfigMap (optional)
+	modelsFile string
+
+	// configModels adds or overrides models of the environment (optional)
+	configModels map[string]string
+
+	// onModelsChanged is called after a refresh changed the registered models (optional)
+	onModelsChanged func([]ModelChange)
+
+	// rollouts pins models to versions and splits canary traffic (optional)
+	rollouts *Rollouts
+
+	// canaryGate decides whether a request may be split off to a canary; nil allows
+	// every request (optional)
+	canaryGate func(ctx context.Context, modelName string) bool
+
+	// preflight holds feature schemas and warm-up results
+	preflight preflightState
+
+	// latency derives adaptive timeouts; nil uses the fixed client timeout
+	latency *latencyTracker
 }
 
 // ModelInfo contains information about a registered KServe model
@@ -54,6 +76,19 @@ type ProxyConfig struct {
 
 	// Timeout for HTTP requests to KServe services
 	Timeout time.Duration
+
+	// AdaptiveTimeout derives per-model timeouts from observed latency (optional)
+	AdaptiveTimeout AdaptiveTimeoutConfig
+
+	// ModelsFile is a YAML or JSON file mapping model names to InferenceService names or
+	// URLs, typically a mounted ConfigMap. Its models are added to, and override, the
+	// KSERVE_<MODEL>_SERVICE models (optional).
+	ModelsFile string
+
+	// Models maps model names to InferenceService names or URLs like the models file.
+	// They override the KSERVE_<MODEL>_SERVICE models and are overridden by the models
+	// file (optional).
+	Models map[string]string
 }
 
 // DefaultPredictorPort is the default port for KServe predictors in RawDeployment mode
@@ -139,6 +174,9 @@ type ModelHealthResponse struct {
 
 	// Message contains additional information
 	Message string `json:"message,omitempty"`
+
+	// TimeoutMs is the request timeout currently applied to the model
+	TimeoutMs int64 `json:"timeout_ms,omitempty"`
 }
 
 // NewProxyClient creates a new KServe proxy client with dynamic model discovery
@@ -168,7 +206,8 @@ func NewProxyClient(cfg ProxyConfig, log *logrus.Logger) (*ProxyClient, error) {
 	client := &ProxyClient{
 		namespace:     cfg.Namespace,
 		predictorPort: predictorPort,
-		models:        make(map[string]*ModelInfo),
+		modelsFile:    cfg.ModelsFile,
+		configModels:  cfg.Models,
 		httpClient: &http.Client{
 			Transport: transport,
 			Timeout:   timeout,
@@ -176,8 +215,19 @@ func NewProxyClient(cfg ProxyConfig, log *logrus.Logger) (*ProxyClient, error) {
 		log: log,
 	}
 
-	// Load models from environment variables
-	client.loadModelsFromEnv()
+	// Adaptive timeouts are applied per request; the client timeout only
+	// bounds requests that outlive the largest adaptive timeout
+	if cfg.AdaptiveTimeout.Enabled {
+		client.latency = newLatencyTracker(cfg.AdaptiveTimeout, timeout)
+		client.httpClient.Timeout = max(timeout, client.latency.cfg.Max)
+	}
+
+	// Load models from environment variables and the models file
+	models, err := client.discoverModels()
+	if err != nil {
+		return nil, err
+	}
+	client.models = models
 
 	if len(client.models) == 0 {
 		log.Warn("No KServe models discovered from environment variables")
@@ -188,13 +238,16 @@ func NewProxyClient(cfg ProxyConfig, log *logrus.Logger) (*ProxyClient, error) {
 	return client, nil
 }
 
-// loadModelsFromEnv discovers models from environment variables.
+// HTTPClient returns the client used to reach KServe predictors, e.g. to audit its TLS configuration
+func (c *ProxyClient) HTTPClient() *http.Client {
+	return c.httpClient
+}
+
+// modelsFromEnv discovers models from environment variables.
 // Pattern: KSERVE_<MODEL_NAME>_SERVICE = service-name
 // Example: KSERVE_ANOMALY_DETECTOR_SERVICE = anomaly-detector-predictor
-func (c *ProxyClient) loadModelsFromEnv() {
-	c.modelsMutex.Lock()
-	defer c.modelsMutex.Unlock()
-
+func (c *ProxyClient) modelsFromEnv() map[string]*ModelInfo {
+	models := make(map[string]*ModelInfo)
 	for _, env := range os.Environ() {
 		// Skip non-KServe environment variables
 		if !strings.HasPrefix(env, "KSERVE_") {
@@ -227,9 +280,9 @@ func (c *ProxyClient) loadModelsFromEnv() {
 		modelName = strings.ToLower(strings.ReplaceAll(modelName, "_", "-"))
 
 		// Build service URL with the predictor port
-		url := fmt.Sprintf("http://%s.%s.svc.cluster.local:%d", serviceName, c.namespace, c.predictorPort)
+		url := c.serviceURL(serviceName)
 
-		c.models[modelName] = &ModelInfo{
+		models[modelName] = &ModelInfo{
 			Name:        modelName,
 			ServiceName: serviceName,
 			Namespace:   c.namespace,
@@ -241,8 +294,81 @@ func (c *ProxyClient) loadModelsFromEnv() {
 			"service": serviceName,
 			"url":     url,
 			"port":    c.predictorPort,
-		}).Debug("Registered KServe model from environment")
+		}).Debug("Discovered KServe model from environment")
 	}
+	return models
+}
+
+// serviceURL returns the URL of a predictor service in the KServe namespace
+func (c *ProxyClient) serviceURL(serviceName string) string {
+	return fmt.Sprintf("http://%s.%s.svc.cluster.local:%d", serviceName, c.namespace, c.predictorPort)
+}
+
+// SetRollouts enables version pinning and canary splits for the models they name.
+// It must be called before the client serves requests.
+func (c *ProxyClient) SetRollouts(rollouts *Rollouts) {
+	c.rollouts = rollouts
+}
+
+// SetCanaryGate restricts canary traffic to the requests gate allows; the others
+// go to the pinned version. It must be called before the client serves requests.
+func (c *ProxyClient) SetCanaryGate(gate func(ctx context.Context, modelName string) bool) {
+	c.canaryGate = gate
+}
+
+// canaryAllowed reports whether a prediction request may be routed to a canary
+func (c *ProxyClient) canaryAllowed(ctx context.Context, modelName string) bool {
+	return c.canaryGate == nil || c.canaryGate(ctx, modelName)
+}
+
+// RolloutStatus returns the rollout of a model and the outcomes recorded per version
+func (c *ProxyClient) RolloutStatus(modelName string) (*RolloutStatus, bool) {
+	if c.rollouts == nil {
+		return nil, false
+	}
+	return c.rollouts.Status(modelName)
+}
+
+// VersionModels returns the services of a model's rollout versions by version label
+func (c *ProxyClient) VersionModels(modelName string) map[string]*ModelInfo {
+	model, exists := c.GetModel(modelName)
+	if !exists || c.rollouts == nil {
+		return nil
+	}
+	services := c.rollouts.Services(modelName)
+	models := make(map[string]*ModelInfo, len(services))
+	for version, service := range services {
+		models[version] = c.versionModel(model, service)
+	}
+	return models
+}
+
+// route returns the service handling a request to a registered model and the
+// version chosen by its rollout ("" without one). Canary traffic is only split
+// off for predictions; health checks always target the pinned version.
+func (c *ProxyClient) route(modelName string, canary bool) (*ModelInfo, string, bool) {
+	model, exists := c.GetModel(modelName)
+	if !exists || c.rollouts == nil {
+		return model, "", exists
+	}
+
+	pick := c.rollouts.pinned
+	if canary {
+		pick = c.rollouts.pick
+	}
+	version, service, ok := pick(modelName)
+	if !ok || service == "" {
+		return model, version, true
+	}
+	return c.versionModel(model, service), version, true
+}
+
+// versionModel describes the service of one version of a model
+func (c *ProxyClient) versionModel(model *ModelInfo, service string) *ModelInfo {
+	if isURL(service) {
+		return &ModelInfo{Name: model.Name, ServiceName: service, Namespace: model.Namespace, URL: strings.TrimSuffix(service, "/")}
+	}
+	return &ModelInfo{Name: model.Name, ServiceName: service, Namespace: c.namespace, URL: c.serviceURL(service)}
 }
 
 // ListModels returns a list of registered model names
@@ -286,12 +412,43 @@ func (c *ProxyClient) ModelCount() int {
 }
 
 // Predict calls a KServe model for predictions
-func (c *ProxyClient) Predict(ctx context.Context, modelName string, instances [][]float64) (*DetectResponse, error) {
-	model, exists := c.GetModel(modelName)
+func (c *ProxyClient) Predict(ctx context.Context, modelName string, instances [][]float64) (result *DetectResponse, err error) {
+	model, version, exists := c.route(modelName, c.canaryAllowed(ctx, modelName))
 	if !exists {
 		return nil, &ModelNotFoundError{ModelName: modelName}
 	}
+	if version != "" {
+		start := time.Now()
+		defer func() {
+			c.rollouts.record(modelName, version, time.Since(start), err, isAnomalous(result))
+		}()
+	}
+	return c.detect(ctx, modelName, model, version, instances)
+}
+
+// PredictVersion calls one rollout version of a model, bypassing canary splits; an
+// empty version calls the pinned version. The outcome is not recorded in the rollout
+// stats, so comparing versions offline does not skew the live ones.
+func (c *ProxyClient) PredictVersion(ctx context.Context, modelName, version string, instances [][]float64) (*DetectResponse, error) {
+	if version == "" {
+		model, pinned, exists := c.route(modelName, false)
+		if !exists {
+			return nil, &ModelNotFoundError{ModelName: modelName}
+		}
+		return c.detect(ctx, modelName, model, pinned, instances)
+	}
+	if _, exists := c.GetModel(modelName); !exists {
+		return nil, &ModelNotFoundError{ModelName: modelName}
+	}
+	model, ok := c.VersionModels(modelName)[version]
+	if !ok {
+		return nil, &VersionNotFoundError{ModelName: modelName, Version: version}
+	}
+	return c.detect(ctx, modelName, model, version, instances)
+}
 
+// detect sends an anomaly detection request to the service of a model version
+func (c *ProxyClient) detect(ctx context.Context, modelName string, model *ModelInfo, version string, instances [][]float64) (*DetectResponse, error) {
 	// Build KServe v1 request
 	kserveReq := map[string]interface{}{
 		"instances": instances,
@@ -308,8 +465,10 @@ func (c *ProxyClient) Predict(ctx context.Context, modelName string, instances [
 	// model name (e.g., "anomaly-detector") for user-facing APIs and service resolution
 	endpoint := fmt.Sprintf("%s/v1/models/model:predict", model.URL)
 
-	// Create HTTP request
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewBuffer(jsonData))
+	// Create HTTP request bounded by the model's timeout
+	reqCtx, cancel := c.requestContext(ctx, modelName, version)
+	defer cancel()
+	httpReq, err := http.NewRequestWithContext(reqCtx, "POST", endpoint, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -321,6 +480,7 @@ func (c *ProxyClient) Predict(ctx context.Context, modelName string, instances [
 	startTime := time.Now()
 	resp, err := c.httpClient.Do(httpReq)
 	duration := time.Since(startTime)
+	c.observeLatency(ctx, modelName, version, duration, err)
 
 	if err != nil {
 		c.log.WithFields(logrus.Fields{
@@ -378,25 +538,47 @@ func (c *ProxyClient) Predict(ctx context.Context, modelName string, instances [
 		ModelVersion string `json:"model_version,omitempty"`
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&kserveResp); err != nil {
-		return nil, fmt.Errorf("failed to decode response from model %s: %w", modelName, err)
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body from model %s: %w", modelName, err)
+	}
+	if err := json.Unmarshal(bodyBytes, &kserveResp); err != nil {
+		return nil, decodeError(modelName, bodyBytes, err)
+	}
+	if err := ValidateAnomalyPredictions(modelName, kserveResp.Predictions, len(instances)); err != nil {
+		return nil, err
 	}
 
+	modelVersion := kserveResp.ModelVersion
+	if version != "" {
+		modelVersion = version
+	}
+	RecorderFromContext(ctx).record(modelName, modelVersion, bodyBytes)
 	return &DetectResponse{
 		Predictions:  kserveResp.Predictions,
 		ModelName:    modelName,
-		ModelVersion: kserveResp.ModelVersion,
+		ModelVersion: modelVersion,
 	}, nil
 }
 
 // PredictFlexible calls a KServe model and returns a flexible response that handles
 // different model response formats (anomaly-detector vs predictive-analytics).
 // This method uses a type switch based on the model name to properly parse the response.
-func (c *ProxyClient) PredictFlexible(ctx context.Context, modelName string, instances [][]float64) (*ModelResponse, error) {
-	model, exists := c.GetModel(modelName)
+func (c *ProxyClient) PredictFlexible(ctx context.Context, modelName string, instances [][]float64) (result *ModelResponse, err error) {
+	model, version, exists := c.route(modelName, c.canaryAllowed(ctx, modelName))
 	if !exists {
 		return nil, &ModelNotFoundError{ModelName: modelName}
 	}
+	if version != "" {
+		start := time.Now()
+		defer func() {
+			var anomaly *DetectResponse
+			if result != nil {
+				anomaly = result.AnomalyResponse
+			}
+			c.rollouts.record(modelName, version, time.Since(start), err, isAnomalous(anomaly))
+		}()
+	}
 
 	// Build KServe v1 request
 	kserveReq := map[string]interface{}{
@@ -411,8 +593,10 @@ func (c *ProxyClient) PredictFlexible(ctx context.Context, modelName string, ins
 	// Build endpoint URL - KServe v1 protocol: /v1/models/<model>:predict
 	endpoint := fmt.Sprintf("%s/v1/models/model:predict", model.URL)
 
-	// Create HTTP request
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewBuffer(jsonData))
+	// Create HTTP request bounded by the model's timeout
+	reqCtx, cancel := c.requestContext(ctx, modelName, version)
+	defer cancel()
+	httpReq, err := http.NewRequestWithContext(reqCtx, "POST", endpoint, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -424,6 +608,7 @@ func (c *ProxyClient) PredictFlexible(ctx context.Context, modelName string, ins
 	startTime := time.Now()
 	resp, err := c.httpClient.Do(httpReq)
 	duration := time.Since(startTime)
+	c.observeLatency(ctx, modelName, version, duration, err)
 
 	if err != nil {
 		c.log.WithFields(logrus.Fields{
@@ -481,19 +666,42 @@ func (c *ProxyClient) PredictFlexible(ctx context.Context, modelName string, ins
 	}
 
 	// Parse response based on model type
-	return c.parseModelResponse(modelName, bodyBytes)
+	result, err = c.parseModelResponse(modelName, bodyBytes, len(instances))
+	if err == nil {
+		if version != "" {
+			setModelVersion(result, version)
+		}
+		RecorderFromContext(ctx).record(modelName, version, bodyBytes)
+	}
+	return result, err
+}
+
+// setModelVersion labels a response with the version chosen by the model's rollout
+func setModelVersion(resp *ModelResponse, version string) {
+	if resp.AnomalyResponse != nil {
+		resp.AnomalyResponse.ModelVersion = version
+	}
+	if resp.ForecastResponse != nil {
+		resp.ForecastResponse.ModelVersion = version
+	}
 }
 
-// parseModelResponse parses the response body based on the model type
-func (c *ProxyClient) parseModelResponse(modelName string, body []byte) (*ModelResponse, error) {
+// isAnomalous reports whether an anomaly-detector response flags its first instance
+func isAnomalous(resp *DetectResponse) bool {
+	return resp != nil && len(resp.Predictions) > 0 && resp.Predictions[0] == -1
+}
+
+// parseModelResponse parses the response body based on the model type and validates
+// it against the number of instances sent
+func (c *ProxyClient) parseModelResponse(modelName string, body []byte, instances int) (*ModelResponse, error) {
 	switch modelName {
 	case "predictive-analytics":
-		return c.parseForecastResponse(modelName, body)
+		return c.parseForecastResponse(modelName, body, instances)
 	case "anomaly-detector":
-		return c.parseAnomalyResponse(modelName, body)
+		return c.parseAnomalyResponse(modelName, body, instances)
 	default:
 		// Try to detect the response type by attempting to parse both formats
-		return c.parseAutoDetectResponse(modelName, body)
+		return c.parseAutoDetectResponse(modelName, body, instances)
 	}
 }
 
@@ -507,7 +715,7 @@ func (c *ProxyClient) parseModelResponse(modelName string, body []byte) (*ModelR
 // Format 2 - Array (standard sklearn multi-output):
 //
 //	{"predictions": [[cpu_value, memory_value], ...]}
-func (c *ProxyClient) parseForecastResponse(modelName string, body []byte) (*ModelResponse, error) {
+func (c *ProxyClient) parseForecastResponse(modelName string, body []byte, instances int) (*ModelResponse, error) {
 	// Try Format 1: Nested structure (custom wrapper or rich model output)
 	var nestedResp struct {
 		Predictions    map[string]ForecastResult `json:"predictions"`
@@ -523,16 +731,17 @@ func (c *ProxyClient) parseForecastResponse(modelName string, body []byte) (*Mod
 			"model":  modelName,
 			"format": "nested",
 		}).Debug("Parsed forecast response in nested format")
-		return &ModelResponse{
-			Type: "forecast",
-			ForecastResponse: &ForecastResponse{
-				Predictions:    nestedResp.Predictions,
-				ModelName:      modelName,
-				ModelVersion:   nestedResp.ModelVersion,
-				Timestamp:      nestedResp.Timestamp,
-				LookbackWindow: nestedResp.LookbackWindow,
-			},
-		}, nil
+		forecast := &ForecastResponse{
+			Predictions:    nestedResp.Predictions,
+			ModelName:      modelName,
+			ModelVersion:   nestedResp.ModelVersion,
+			Timestamp:      nestedResp.Timestamp,
+			LookbackWindow: nestedResp.LookbackWindow,
+		}
+		if err := validateForecast(modelName, forecast, -1, instances); err != nil {
+			return nil, err
+		}
+		return &ModelResponse{Type: "forecast", ForecastResponse: forecast}, nil
 	}
 
 	// Fallback to Format 2: Array structure (sklearn multi-output)
@@ -543,7 +752,7 @@ func (c *ProxyClient) parseForecastResponse(modelName string, body []byte) (*Mod
 	}
 
 	if err := json.Unmarshal(body, &arrayResp); err != nil {
-		return nil, fmt.Errorf("failed to parse forecast response from model %s: %w", modelName, err)
+		return nil, decodeError(modelName, body, err)
 	}
 
 	// Convert array format to nested format
@@ -594,18 +803,19 @@ func (c *ProxyClient) parseForecastResponse(modelName string, body []byte) (*Mod
 		}).Debug("Converted single-output array forecast to nested format")
 	}
 
-	return &ModelResponse{
-		Type: "forecast",
-		ForecastResponse: &ForecastResponse{
-			Predictions:  predictions,
-			ModelName:    modelName,
-			ModelVersion: arrayResp.ModelVersion,
-		},
-	}, nil
+	forecast := &ForecastResponse{
+		Predictions:  predictions,
+		ModelName:    modelName,
+		ModelVersion: arrayResp.ModelVersion,
+	}
+	if err := validateForecast(modelName, forecast, len(arrayResp.Predictions), instances); err != nil {
+		return nil, err
+	}
+	return &ModelResponse{Type: "forecast", ForecastResponse: forecast}, nil
 }
 
 // parseAnomalyResponse parses an anomaly-detector model response
-func (c *ProxyClient) parseAnomalyResponse(modelName string, body []byte) (*ModelResponse, error) {
+func (c *ProxyClient) parseAnomalyResponse(modelName string, body []byte, instances int) (*ModelResponse, error) {
 	var anomalyResp struct {
 		Predictions  []int  `json:"predictions"`
 		ModelName    string `json:"model_name,omitempty"`
@@ -613,7 +823,10 @@ func (c *ProxyClient) parseAnomalyResponse(modelName string, body []byte) (*Mode
 	}
 
 	if err := json.Unmarshal(body, &anomalyResp); err != nil {
-		return nil, fmt.Errorf("failed to decode anomaly response from model %s: %w", modelName, err)
+		return nil, decodeError(modelName, body, err)
+	}
+	if err := ValidateAnomalyPredictions(modelName, anomalyResp.Predictions, instances); err != nil {
+		return nil, err
 	}
 
 	return &ModelResponse{
@@ -627,16 +840,17 @@ func (c *ProxyClient) parseAnomalyResponse(modelName string, body []byte) (*Mode
 }
 
 // parseAutoDetectResponse tries to detect and parse the response format automatically
-func (c *ProxyClient) parseAutoDetectResponse(modelName string, body []byte) (*ModelResponse, error) {
+func (c *ProxyClient) parseAutoDetectResponse(modelName string, body []byte, instances int) (*ModelResponse, error) {
 	// First, try to unmarshal into a generic map to inspect the structure
 	var rawResp map[string]interface{}
 	if err := json.Unmarshal(body, &rawResp); err != nil {
-		return nil, fmt.Errorf("failed to decode response from model %s: %w", modelName, err)
+		return nil, decodeError(modelName, body, err)
 	}
 
 	predictions, exists := rawResp["predictions"]
 	if !exists {
-		return nil, fmt.Errorf("response from model %s missing 'predictions' field", modelName)
+		return nil, &InvalidResponseError{ModelName: modelName, Code: ErrCodeMalformedResponse,
+			Message: "response is missing the 'predictions' field"}
 	}
 
 	// Check if predictions is an array or object
@@ -647,16 +861,17 @@ func (c *ProxyClient) parseAutoDetectResponse(modelName string, body []byte) (*M
 			// Check if it's an array of arrays (sklearn multi-output forecast)
 			if _, isArray := pred[0].([]interface{}); isArray {
 				// Array of arrays format: [[cpu, mem], ...] -> forecast
-				return c.parseForecastResponse(modelName, body)
+				return c.parseForecastResponse(modelName, body, instances)
 			}
 		}
 		// Simple array format: [0, 1, 0, ...] -> anomaly-detector
-		return c.parseAnomalyResponse(modelName, body)
+		return c.parseAnomalyResponse(modelName, body, instances)
 	case map[string]interface{}:
 		// Predictive-analytics format: predictions is a nested object
-		return c.parseForecastResponse(modelName, body)
+		return c.parseForecastResponse(modelName, body, instances)
 	default:
-		return nil, fmt.Errorf("unsupported predictions format from model %s", modelName)
+		return nil, &InvalidResponseError{ModelName: modelName, Code: ErrCodeMalformedResponse,
+			Message: fmt.Sprintf("unsupported predictions format %T", pred)}
 	}
 }
 
@@ -677,7 +892,7 @@ func (c *ProxyClient) PredictForecast(ctx context.Context, modelName string, ins
 
 // CheckModelHealth checks if a specific KServe model is healthy
 func (c *ProxyClient) CheckModelHealth(ctx context.Context, modelName string) (*ModelHealthResponse, error) {
-	model, exists := c.GetModel(modelName)
+	model, version, exists := c.route(modelName, false)
 	if !exists {
 		return &ModelHealthResponse{
 			Model:     modelName,
@@ -691,7 +906,9 @@ func (c *ProxyClient) CheckModelHealth(ctx context.Context, modelName string) (*
 	// Note: KServe defaults to model name "model" when spec.predictor.model.name is not set
 	endpoint := fmt.Sprintf("%s/v1/models/model", model.URL)
 
-	httpReq, err := http.NewRequestWithContext(ctx, "GET", endpoint, http.NoBody)
+	reqCtx, cancel := c.requestContext(ctx, modelName, version)
+	defer cancel()
+	httpReq, err := http.NewRequestWithContext(reqCtx, "GET", endpoint, http.NoBody)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create health check request: %w", err)
 	}
@@ -718,6 +935,7 @@ func (c *ProxyClient) CheckModelHealth(ctx context.Context, modelName string) (*
 			Status:    "ready",
 			Service:   model.ServiceName,
 			Namespace: model.Namespace,
+			TimeoutMs: c.ModelTimeout(modelName).Milliseconds(),
 		}, nil
 	}
 
@@ -757,19 +975,6 @@ func (c *ProxyClient) Close() {
 	c.httpClient.CloseIdleConnections()
 }
 
-// RefreshModels reloads models from environment variables
-func (c *ProxyClient) RefreshModels() {
-	c.modelsMutex.Lock()
-	// Clear existing models
-	c.models = make(map[string]*ModelInfo)
-	c.modelsMutex.Unlock()
-
-	// Reload from environment
-	c.loadModelsFromEnv()
-
-	c.log.WithField("models", c.ListModels()).Info("KServe models refreshed from environment")
-}
-
 // ModelNotFoundError is returned when a model is not registered
 type ModelNotFoundError struct {
 	ModelName string
@@ -779,6 +984,16 @@ func (e *ModelNotFoundError) Error() string {
 	return fmt.Sprintf("model not found: %s", e.ModelName)
 }
 
+// VersionNotFoundError is returned when a model's rollout does not list a version
+type VersionNotFoundError struct {
+	ModelName string
+	Version   string
+}
+
+func (e *VersionNotFoundError) Error() string {
+	return fmt.Sprintf("model %s has no rollout version %s", e.ModelName, e.Version)
+}
+
 // ModelUnavailableError is returned when a model is unavailable
 type ModelUnavailableError struct {
 	ModelName string
@@ -504,7 +504,8 @@ func TestProxyClient_RefreshModels(t *testing.T) {
 	os.Setenv("KSERVE_NEW_MODEL_SERVICE", "new-service")
 	defer os.Unsetenv("KSERVE_NEW_MODEL_SERVICE")
 
-	client.RefreshModels()
+	_, err = client.RefreshModels(TriggerManual)
+	require.NoError(t, err)
 
 	assert.Equal(t, 2, client.ModelCount())
 	assert.Contains(t, client.ListModels(), "new-model")
@@ -726,7 +727,7 @@ func TestProxyClient_PredictFlexible_AnomalyResponse(t *testing.T) {
 		URL:         server.URL,
 	}
 
-	instances := [][]float64{{0.5, 1.2, 0.8}}
+	instances := [][]float64{{0.5, 1.2, 0.8}, {0.4, 1.0, 0.7}, {0.9, 1.5, 0.6}}
 
 	result, err := client.PredictFlexible(context.Background(), "anomaly-detector", instances)
 
@@ -956,7 +957,7 @@ func TestProxyClient_PredictFlexible_ArrayFormat_MultipleSamples(t *testing.T) {
 		URL:  server.URL,
 	}
 
-	result, err := client.PredictFlexible(context.Background(), "predictive-analytics", [][]float64{{14.0, 2.0}})
+	result, err := client.PredictFlexible(context.Background(), "predictive-analytics", [][]float64{{14.0, 2.0}, {15.0, 2.0}, {16.0, 2.0}})
 
 	require.NoError(t, err)
 	require.NotNil(t, result)
@@ -1002,7 +1003,7 @@ func TestProxyClient_PredictFlexible_ArrayFormat_SingleOutput(t *testing.T) {
 		URL:  server.URL,
 	}
 
-	result, err := client.PredictFlexible(context.Background(), "predictive-analytics", [][]float64{{14.0}})
+	result, err := client.PredictFlexible(context.Background(), "predictive-analytics", [][]float64{{14.0}, {15.0}, {16.0}})
 
 	require.NoError(t, err)
 	require.NotNil(t, result)
@@ -1139,7 +1140,7 @@ func TestProxyClient_AutoDetect_SimpleArray(t *testing.T) {
 		URL:  server.URL,
 	}
 
-	result, err := client.PredictFlexible(context.Background(), "custom-anomaly-model", [][]float64{{1.0, 2.0, 3.0}})
+	result, err := client.PredictFlexible(context.Background(), "custom-anomaly-model", [][]float64{{1.0}, {2.0}, {3.0}})
 
 	require.NoError(t, err)
 	assert.Equal(t, "anomaly", result.Type, "Simple array should be detected as anomaly")
@@ -0,0 +1,65 @@
+package kserve
+
+import (
+	"context"
+	"sync"
+)
+
+type recorderKey struct{}
+
+// Exchange is a successful model call captured by a Recorder
+type Exchange struct {
+	Model    string
+	Version  string
+	Response []byte // raw response body
+}
+
+// Recorder captures the raw responses of the model calls made with its context, so a
+// decision can be reproduced from exactly what the model returned. All methods are
+// safe to call on a nil *Recorder.
+type Recorder struct {
+	mu        sync.Mutex
+	exchanges []Exchange
+}
+
+// NewRecorder creates an empty recorder
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// WithRecorder returns a context carrying the recorder
+func WithRecorder(ctx context.Context, r *Recorder) context.Context {
+	if r == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, recorderKey{}, r)
+}
+
+// RecorderFromContext returns the recorder carried by ctx, or nil
+func RecorderFromContext(ctx context.Context) *Recorder {
+	if ctx == nil {
+		return nil
+	}
+	r, _ := ctx.Value(recorderKey{}).(*Recorder)
+	return r
+}
+
+// Exchanges returns the captured model calls in call order
+func (r *Recorder) Exchanges() []Exchange {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Exchange(nil), r.exchanges...)
+}
+
+// record captures a model response
+func (r *Recorder) record(model, version string, body []byte) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.exchanges = append(r.exchanges, Exchange{Model: model, Version: version, Response: append([]byte(nil), body...)})
+}
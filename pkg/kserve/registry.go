@@ -0,0 +1,194 @@
+package kserve
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"sigs.k8s.io/yaml"
+)
+
+// Model change actions
+const (
+	ModelAdded   = "added"
+	ModelRemoved = "removed"
+	ModelUpdated = "updated"
+)
+
+// Refresh triggers, recorded with model changes
+const (
+	TriggerSignal = "sighup"
+	TriggerFile   = "models_file"
+	TriggerManual = "manual"
+)
+
+// DefaultModelsFileInterval is how often WatchModels checks the models file for changes
+const DefaultModelsFileInterval = 30 * time.Second
+
+// ModelChange is a model added, removed or moved to another service by a refresh
+type ModelChange struct {
+	Model  string `json:"model"`
+	Action string `json:"action"`
+
+	// URL is the model's service URL after the refresh, PreviousURL before it
+	URL         string `json:"url,omitempty"`
+	PreviousURL string `json:"previous_url,omitempty"`
+}
+
+// modelsFile is the on-disk format of the models file
+type modelsFile struct {
+	// Models maps model names to an InferenceService predictor name in the KServe
+	// namespace or to a full URL
+	Models map[string]string `json:"models"`
+}
+
+// discoverModels returns the models of the environment, the configuration and the
+// models file
+func (c *ProxyClient) discoverModels() (map[string]*ModelInfo, error) {
+	models := c.modelsFromEnv()
+	for name, service := range c.configModels {
+		if name == "" || strings.TrimSpace(service) == "" {
+			return nil, fmt.Errorf("KServe model %q needs a name and a service", name)
+		}
+		models[name] = c.versionModel(&ModelInfo{Name: name}, service)
+	}
+	if c.modelsFile == "" {
+		return models, nil
+	}
+
+	data, err := os.ReadFile(c.modelsFile) //#nosec G304 -- path comes from operator configuration
+	if err != nil {
+		return nil, fmt.Errorf("failed to read KServe models file: %w", err)
+	}
+	var file modelsFile
+	if err := yaml.UnmarshalStrict(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse KServe models file %s: %w", c.modelsFile, err)
+	}
+	for name, service := range file.Models {
+		if name == "" || strings.TrimSpace(service) == "" {
+			return nil, fmt.Errorf("KServe models file %s: model %q needs a name and a service", c.modelsFile, name)
+		}
+		models[name] = c.versionModel(&ModelInfo{Name: name}, service)
+	}
+	return models, nil
+}
+
+// OnModelsChanged registers a function called with the changes of every refresh that
+// changed the registered models. It must be called before refreshes start.
+func (c *ProxyClient) OnModelsChanged(fn func([]ModelChange)) {
+	c.onModelsChanged = fn
+}
+
+// RefreshModels rediscovers the models from environment variables and the models file
+// and replaces the registered models in one step, so lookups never see a partial
+// registry. When discovery fails the registered models are kept. Each change is
+// written to the audit log with the trigger of the refresh.
+func (c *ProxyClient) RefreshModels(trigger string) ([]ModelChange, error) {
+	models, err := c.discoverModels()
+	if err != nil {
+		c.log.WithError(err).WithField("trigger", trigger).Error("KServe model refresh failed, keeping the registered models")
+		return nil, err
+	}
+
+	c.modelsMutex.Lock()
+	changes := diffModels(c.models, models)
+	c.models = models
+	c.modelsMutex.Unlock()
+
+	for _, change := range changes {
+		ModelChanges.WithLabelValues(change.Action).Inc()
+		c.log.WithFields(logrus.Fields{
+			"audit":        "kserve_models",
+			"trigger":      trigger,
+			"model":        change.Model,
+			"action":       change.Action,
+			"url":          change.URL,
+			"previous_url": change.PreviousURL,
+		}).Info("KServe model registry changed")
+	}
+	c.log.WithFields(logrus.Fields{
+		"trigger": trigger,
+		"models":  len(models),
+		"changes": len(changes),
+	}).Info("KServe models refreshed")
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	if c.onModelsChanged != nil {
+		c.onModelsChanged(changes)
+	}
+
+	// Warm up newly registered models if pre-flight checks are in use
+	c.preflight.mu.RLock()
+	ran := c.preflight.ran
+	c.preflight.mu.RUnlock()
+	if ran {
+		go c.Preflight(context.Background())
+	}
+	return changes, nil
+}
+
+// WatchModels refreshes the models whenever the models file changes, checking every
+// interval until ctx is cancelled. Mounted ConfigMaps are updated by swapping a
+// symlink, which changes the modification time of the resolved file.
+func (c *ProxyClient) WatchModels(ctx context.Context, interval time.Duration) {
+	if c.modelsFile == "" {
+		return
+	}
+	if interval <= 0 {
+		interval = DefaultModelsFileInterval
+	}
+
+	modTime := func() time.Time {
+		info, err := os.Stat(c.modelsFile)
+		if err != nil {
+			return time.Time{}
+		}
+		return info.ModTime()
+	}
+	last := modTime()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				current := modTime()
+				if current.IsZero() || current.Equal(last) {
+					continue
+				}
+				last = current
+				_, _ = c.RefreshModels(TriggerFile) // failures are logged and keep the registered models
+			}
+		}
+	}()
+}
+
+// diffModels returns the changes from old to updated, sorted by model name
+func diffModels(old, updated map[string]*ModelInfo) []ModelChange {
+	var changes []ModelChange
+	for name, model := range updated {
+		previous, ok := old[name]
+		switch {
+		case !ok:
+			changes = append(changes, ModelChange{Model: name, Action: ModelAdded, URL: model.URL})
+		case previous.URL != model.URL:
+			changes = append(changes, ModelChange{Model: name, Action: ModelUpdated, URL: model.URL, PreviousURL: previous.URL})
+		}
+	}
+	for name, model := range old {
+		if _, ok := updated[name]; !ok {
+			changes = append(changes, ModelChange{Model: name, Action: ModelRemoved, PreviousURL: model.URL})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Model < changes[j].Model })
+	return changes
+}
@@ -0,0 +1,152 @@
+package kserve
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRegistryClient(t *testing.T, modelsFile string) *ProxyClient {
+	t.Helper()
+	log := logrus.New()
+	log.SetLevel(logrus.PanicLevel)
+	client, err := NewProxyClient(ProxyConfig{Namespace: "test-ns", ModelsFile: modelsFile}, log)
+	require.NoError(t, err)
+	return client
+}
+
+func writeModelsFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+func TestModelsFile(t *testing.T) {
+	t.Setenv("KSERVE_ANOMALY_DETECTOR_SERVICE", "anomaly-detector-predictor")
+	path := filepath.Join(t.TempDir(), "models.yaml")
+	writeModelsFile(t, path, `models:
+  anomaly-detector: anomaly-detector-v2-predictor
+  disk-forecaster: https://forecaster.example.com/
+`)
+
+	client := newRegistryClient(t, path)
+	model, ok := client.GetModel("anomaly-detector")
+	require.True(t, ok)
+	assert.Equal(t, "http://anomaly-detector-v2-predictor.test-ns.svc.cluster.local:8080", model.URL, "the file overrides the environment")
+	model, ok = client.GetModel("disk-forecaster")
+	require.True(t, ok)
+	assert.Equal(t, "https://forecaster.example.com", model.URL)
+
+	writeModelsFile(t, path, "models:\n  broken: \"\"\n")
+	_, err := NewProxyClient(ProxyConfig{Namespace: "test-ns", ModelsFile: path}, logrus.New())
+	assert.ErrorContains(t, err, `model "broken" needs a name and a service`)
+}
+
+func TestProxyConfig_Models(t *testing.T) {
+	t.Setenv("KSERVE_ANOMALY_DETECTOR_SERVICE", "anomaly-detector-predictor")
+	path := filepath.Join(t.TempDir(), "models.yaml")
+	writeModelsFile(t, path, "models:\n  capacity: capacity-v2-predictor\n")
+
+	log := logrus.New()
+	log.SetLevel(logrus.PanicLevel)
+	client, err := NewProxyClient(ProxyConfig{Namespace: "test-ns", ModelsFile: path, Models: map[string]string{
+		"anomaly-detector": "http://anomaly-detector.test",
+		"capacity":         "capacity-predictor",
+	}}, log)
+	require.NoError(t, err)
+	model, _ := client.GetModel("anomaly-detector")
+	assert.Equal(t, "http://anomaly-detector.test", model.URL, "the configuration overrides the environment")
+	model, _ = client.GetModel("capacity")
+	assert.Equal(t, "http://capacity-v2-predictor.test-ns.svc.cluster.local:8080", model.URL, "the file overrides the configuration")
+
+	_, err = NewProxyClient(ProxyConfig{Namespace: "test-ns", Models: map[string]string{"broken": " "}}, log)
+	assert.ErrorContains(t, err, `model "broken" needs a name and a service`)
+}
+
+func TestRefreshModels_Changes(t *testing.T) {
+	t.Setenv("KSERVE_ANOMALY_DETECTOR_SERVICE", "anomaly-detector-predictor")
+	path := filepath.Join(t.TempDir(), "models.yaml")
+	writeModelsFile(t, path, "models:\n  capacity: capacity-predictor\n  old: old-predictor\n")
+	client := newRegistryClient(t, path)
+
+	var notified []ModelChange
+	client.OnModelsChanged(func(changes []ModelChange) { notified = changes })
+
+	writeModelsFile(t, path, "models:\n  capacity: capacity-v2-predictor\n  forecaster: forecaster-predictor\n")
+	changes, err := client.RefreshModels(TriggerManual)
+	require.NoError(t, err)
+	assert.Equal(t, []ModelChange{
+		{Model: "capacity", Action: ModelUpdated, URL: "http://capacity-v2-predictor.test-ns.svc.cluster.local:8080", PreviousURL: "http://capacity-predictor.test-ns.svc.cluster.local:8080"},
+		{Model: "forecaster", Action: ModelAdded, URL: "http://forecaster-predictor.test-ns.svc.cluster.local:8080"},
+		{Model: "old", Action: ModelRemoved, PreviousURL: "http://old-predictor.test-ns.svc.cluster.local:8080"},
+	}, changes)
+	assert.Equal(t, changes, notified)
+
+	changes, err = client.RefreshModels(TriggerManual)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+
+	// an invalid file keeps the registered models
+	writeModelsFile(t, path, "models: [")
+	_, err = client.RefreshModels(TriggerManual)
+	require.Error(t, err)
+	assert.ElementsMatch(t, []string{"anomaly-detector", "capacity", "forecaster"}, client.ListModels())
+}
+
+func TestRefreshModels_LookupsNeverMiss(t *testing.T) {
+	t.Setenv("KSERVE_ANOMALY_DETECTOR_SERVICE", "anomaly-detector-predictor")
+	client := newRegistryClient(t, "")
+
+	var misses atomic.Int64
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					if _, ok := client.GetModel("anomaly-detector"); !ok {
+						misses.Add(1)
+					}
+				}
+			}
+		}()
+	}
+	for i := 0; i < 200; i++ {
+		_, err := client.RefreshModels(TriggerSignal)
+		require.NoError(t, err)
+	}
+	close(stop)
+	wg.Wait()
+	assert.Zero(t, misses.Load())
+}
+
+func TestWatchModels(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "models.yaml")
+	writeModelsFile(t, path, "models:\n  capacity: capacity-predictor\n")
+	client := newRegistryClient(t, path)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client.WatchModels(ctx, 10*time.Millisecond)
+
+	writeModelsFile(t, path, "models:\n  capacity: capacity-predictor\n  forecaster: forecaster-predictor\n")
+	later := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(path, later, later))
+
+	assert.Eventually(t, func() bool {
+		_, ok := client.GetModel("forecaster")
+		return ok
+	}, 2*time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,289 @@
+package kserve
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+// DefaultVersion labels the InferenceService registered through KSERVE_<MODEL>_SERVICE
+// when a rollout does not pin the model to another version
+const DefaultVersion = "default"
+
+// ModelRollout pins a logical model to one InferenceService version and optionally
+// sends a share of its requests to a canary version. The split is made by the engine
+// for each request and is independent of KServe's own canary traffic.
+type ModelRollout struct {
+	// Versions maps version labels (e.g. "v3") to an InferenceService predictor name
+	// in the KServe namespace or to a full URL
+	Versions map[string]string `json:"versions"`
+
+	// Pinned is the version serving requests not sent to the canary. Empty keeps
+	// the service registered through KSERVE_<MODEL>_SERVICE.
+	Pinned string `json:"pinned,omitempty"`
+
+	// Canary optionally sends a percentage of requests to another version
+	Canary *CanaryRollout `json:"canary,omitempty"`
+}
+
+// CanaryRollout sends a percentage of a model's requests to a version
+type CanaryRollout struct {
+	// Version is the version label receiving canary traffic
+	Version string `json:"version"`
+
+	// Percent of requests sent to the canary (1-100)
+	Percent int `json:"percent"`
+}
+
+// rolloutsFile is the on-disk format of the model rollouts
+type rolloutsFile struct {
+	Models map[string]ModelRollout `json:"models"`
+}
+
+// VersionStats compares the outcomes of requests served by one model version
+type VersionStats struct {
+	// Version is the version label
+	Version string `json:"version"`
+
+	// Role is "pinned" or "canary"
+	Role string `json:"role"`
+
+	// Requests is the number of requests sent to the version
+	Requests int64 `json:"requests"`
+
+	// Errors is the number of requests that failed
+	Errors int64 `json:"errors"`
+
+	// ErrorRate is Errors / Requests
+	ErrorRate float64 `json:"error_rate"`
+
+	// AvgLatencyMs is the mean request latency in milliseconds
+	AvgLatencyMs float64 `json:"avg_latency_ms"`
+
+	// Anomalies is the number of successful anomaly predictions flagged as anomalous
+	Anomalies int64 `json:"anomalies"`
+
+	// AnomalyRate is Anomalies / successful requests
+	AnomalyRate float64 `json:"anomaly_rate"`
+}
+
+// RolloutStatus reports a model's rollout and the outcomes recorded per version
+type RolloutStatus struct {
+	Model         string         `json:"model"`
+	Pinned        string         `json:"pinned"`
+	Canary        string         `json:"canary,omitempty"`
+	CanaryPercent int            `json:"canary_percent,omitempty"`
+	Since         time.Time      `json:"since"`
+	Versions      []VersionStats `json:"versions"`
+}
+
+type versionOutcomes struct {
+	requests  int64
+	errors    int64
+	anomalies int64
+	latency   time.Duration
+}
+
+// Rollouts holds the version pinning and canary splits of logical models and
+// records how each version performed
+type Rollouts struct {
+	models map[string]ModelRollout
+	since  time.Time
+
+	// percent returns a number in [0, 100) used to pick canary requests
+	percent func() int
+
+	mu       sync.Mutex
+	outcomes map[string]map[string]*versionOutcomes
+}
+
+// LoadRollouts reads model rollouts from a YAML or JSON file
+func LoadRollouts(path string) (*Rollouts, error) {
+	data, err := os.ReadFile(path) //#nosec G304 -- path comes from operator configuration
+	if err != nil {
+		return nil, fmt.Errorf("failed to read model rollout file: %w", err)
+	}
+
+	var file rolloutsFile
+	if err := yaml.UnmarshalStrict(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse model rollout file %s: %w", path, err)
+	}
+	return NewRollouts(file.Models)
+}
+
+// NewRollouts validates the rollouts of each logical model
+func NewRollouts(models map[string]ModelRollout) (*Rollouts, error) {
+	for name, rollout := range models {
+		for version, service := range rollout.Versions {
+			if version == "" || version == DefaultVersion {
+				return nil, fmt.Errorf("model rollout %s: invalid version label %q", name, version)
+			}
+			if service == "" {
+				return nil, fmt.Errorf("model rollout %s: version %s has no service", name, version)
+			}
+		}
+		if rollout.Pinned != "" {
+			if _, ok := rollout.Versions[rollout.Pinned]; !ok {
+				return nil, fmt.Errorf("model rollout %s: pinned version %s is not listed in versions", name, rollout.Pinned)
+			}
+		}
+		if canary := rollout.Canary; canary != nil {
+			if _, ok := rollout.Versions[canary.Version]; !ok {
+				return nil, fmt.Errorf("model rollout %s: canary version %q is not listed in versions", name, canary.Version)
+			}
+			if canary.Version == rollout.Pinned {
+				return nil, fmt.Errorf("model rollout %s: canary version %s is already pinned", name, canary.Version)
+			}
+			if canary.Percent < 1 || canary.Percent > 100 {
+				return nil, fmt.Errorf("model rollout %s: canary percent must be between 1 and 100", name)
+			}
+		}
+		if rollout.Pinned == "" && rollout.Canary == nil {
+			return nil, fmt.Errorf("model rollout %s: pinned or canary is required", name)
+		}
+	}
+
+	return &Rollouts{
+		models: models,
+		since:  time.Now(),
+		percent: func() int {
+			return rand.IntN(100) //#nosec G404 -- traffic splitting does not need a cryptographic source
+		},
+		outcomes: make(map[string]map[string]*versionOutcomes),
+	}, nil
+}
+
+// SetDeterministic reports outcomes since start and picks canary requests with intN,
+// which returns numbers in [0, n), e.g. from a seeded source so canary splits are the
+// same on every run. It must be called before the client serves requests.
+func (r *Rollouts) SetDeterministic(start time.Time, intN func(n int) int) {
+	r.since = start
+	r.percent = func() int {
+		return intN(100)
+	}
+}
+
+// Models returns the names of the models with a rollout
+func (r *Rollouts) Models() []string {
+	names := make([]string, 0, len(r.models))
+	for name := range r.models {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Services returns the version services of a model by version label
+func (r *Rollouts) Services(model string) map[string]string {
+	return r.models[model].Versions
+}
+
+// pinned returns the pinned version of a model and its service, which is empty
+// for DefaultVersion. ok is false if the model has no rollout.
+func (r *Rollouts) pinned(model string) (version, service string, ok bool) {
+	rollout, ok := r.models[model]
+	if !ok {
+		return "", "", false
+	}
+	if rollout.Pinned == "" {
+		return DefaultVersion, "", true
+	}
+	return rollout.Pinned, rollout.Versions[rollout.Pinned], true
+}
+
+// pick chooses the version serving a request
+func (r *Rollouts) pick(model string) (version, service string, ok bool) {
+	rollout, ok := r.models[model]
+	if !ok {
+		return "", "", false
+	}
+	if canary := rollout.Canary; canary != nil && r.percent() < canary.Percent {
+		return canary.Version, rollout.Versions[canary.Version], true
+	}
+	return r.pinned(model)
+}
+
+// record stores the outcome of a request served by a model version. anomalous
+// is only meaningful for successful anomaly-detector responses.
+func (r *Rollouts) record(model, version string, latency time.Duration, err error, anomalous bool) {
+	outcome := "success"
+	if err != nil {
+		outcome = "error"
+	}
+	ModelVersionRequests.WithLabelValues(model, version, outcome).Inc()
+	ModelVersionLatency.WithLabelValues(model, version).Observe(latency.Seconds())
+	if err == nil && anomalous {
+		ModelVersionAnomalies.WithLabelValues(model, version).Inc()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	versions, ok := r.outcomes[model]
+	if !ok {
+		versions = make(map[string]*versionOutcomes)
+		r.outcomes[model] = versions
+	}
+	o, ok := versions[version]
+	if !ok {
+		o = &versionOutcomes{}
+		versions[version] = o
+	}
+	o.requests++
+	o.latency += latency
+	switch {
+	case err != nil:
+		o.errors++
+	case anomalous:
+		o.anomalies++
+	}
+}
+
+// Status returns the rollout of a model with the outcomes recorded since the
+// rollout was loaded
+func (r *Rollouts) Status(model string) (*RolloutStatus, bool) {
+	rollout, ok := r.models[model]
+	if !ok {
+		return nil, false
+	}
+
+	pinned, _, _ := r.pinned(model)
+	status := &RolloutStatus{Model: model, Pinned: pinned, Since: r.since}
+	versions := []string{pinned}
+	if rollout.Canary != nil {
+		status.Canary = rollout.Canary.Version
+		status.CanaryPercent = rollout.Canary.Percent
+		versions = append(versions, rollout.Canary.Version)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, version := range versions {
+		stats := VersionStats{Version: version, Role: "pinned"}
+		if version == status.Canary {
+			stats.Role = "canary"
+		}
+		if o, ok := r.outcomes[model][version]; ok && o.requests > 0 {
+			stats.Requests = o.requests
+			stats.Errors = o.errors
+			stats.Anomalies = o.anomalies
+			stats.ErrorRate = float64(o.errors) / float64(o.requests)
+			stats.AvgLatencyMs = float64(o.latency.Milliseconds()) / float64(o.requests)
+			if succeeded := o.requests - o.errors; succeeded > 0 {
+				stats.AnomalyRate = float64(o.anomalies) / float64(succeeded)
+			}
+		}
+		status.Versions = append(status.Versions, stats)
+	}
+	return status, true
+}
+
+// isURL reports whether a version service is a full URL rather than a predictor name
+func isURL(service string) bool {
+	return strings.HasPrefix(service, "http://") || strings.HasPrefix(service, "https://")
+}
@@ -0,0 +1,227 @@
+package kserve
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// versionServer answers anomaly-detector predictions and counts its requests
+func versionServer(t *testing.T, prediction int, calls *int) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*calls++
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"predictions": []int{prediction}})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestProxyClient_Rollout(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	var v2Calls, v3Calls int
+	v2 := versionServer(t, 1, &v2Calls)
+	v3 := versionServer(t, -1, &v3Calls)
+
+	client, err := NewProxyClient(ProxyConfig{Namespace: "test-ns"}, log)
+	require.NoError(t, err)
+	client.models["anomaly-detector"] = &ModelInfo{Name: "anomaly-detector", ServiceName: "anomaly-detector-predictor", URL: "http://unused.invalid"}
+
+	rollouts, err := NewRollouts(map[string]ModelRollout{
+		"anomaly-detector": {
+			Versions: map[string]string{"v2": v2.URL, "v3": v3.URL + "/"},
+			Pinned:   "v2",
+			Canary:   &CanaryRollout{Version: "v3", Percent: 10},
+		},
+	})
+	require.NoError(t, err)
+	// Send every tenth request to the canary
+	n := 0
+	rollouts.percent = func() int {
+		n++
+		return (n * 10) % 100
+	}
+	client.SetRollouts(rollouts)
+
+	ctx := context.Background()
+	for i := 0; i < 20; i++ {
+		resp, err := client.Predict(ctx, "anomaly-detector", [][]float64{{0.5}})
+		require.NoError(t, err)
+		if resp.ModelVersion == "v3" {
+			assert.Equal(t, []int{-1}, resp.Predictions)
+		} else {
+			assert.Equal(t, "v2", resp.ModelVersion)
+		}
+	}
+	assert.Equal(t, 18, v2Calls)
+	assert.Equal(t, 2, v3Calls)
+
+	status, ok := client.RolloutStatus("anomaly-detector")
+	require.True(t, ok)
+	assert.Equal(t, "v2", status.Pinned)
+	assert.Equal(t, "v3", status.Canary)
+	assert.Equal(t, 10, status.CanaryPercent)
+	require.Len(t, status.Versions, 2)
+	assert.Equal(t, VersionStats{Version: "v2", Role: "pinned", Requests: 18}, zeroLatency(status.Versions[0]))
+	assert.Equal(t, VersionStats{Version: "v3", Role: "canary", Requests: 2, Anomalies: 2, AnomalyRate: 1}, zeroLatency(status.Versions[1]))
+
+	// Health checks target the pinned version
+	health, err := client.CheckModelHealth(ctx, "anomaly-detector")
+	require.NoError(t, err)
+	assert.Equal(t, "ready", health.Status)
+	assert.Equal(t, v2.URL, health.Service)
+	assert.Equal(t, 19, v2Calls)
+
+	_, ok = client.RolloutStatus("predictive-analytics")
+	assert.False(t, ok)
+}
+
+func TestProxyClient_PredictVersion(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	var v2Calls, v3Calls int
+	v2 := versionServer(t, 1, &v2Calls)
+	v3 := versionServer(t, -1, &v3Calls)
+
+	client, err := NewProxyClient(ProxyConfig{Namespace: "test-ns"}, log)
+	require.NoError(t, err)
+	client.models["anomaly-detector"] = &ModelInfo{Name: "anomaly-detector", URL: "http://unused.invalid"}
+	rollouts, err := NewRollouts(map[string]ModelRollout{
+		"anomaly-detector": {
+			Versions: map[string]string{"v2": v2.URL, "v3": v3.URL},
+			Pinned:   "v2",
+			Canary:   &CanaryRollout{Version: "v3", Percent: 100},
+		},
+	})
+	require.NoError(t, err)
+	client.SetRollouts(rollouts)
+
+	ctx := context.Background()
+	resp, err := client.PredictVersion(ctx, "anomaly-detector", "", [][]float64{{0.5}})
+	require.NoError(t, err)
+	assert.Equal(t, "v2", resp.ModelVersion, "an empty version calls the pinned version")
+	resp, err = client.PredictVersion(ctx, "anomaly-detector", "v3", [][]float64{{0.5}})
+	require.NoError(t, err)
+	assert.Equal(t, []int{-1}, resp.Predictions)
+	assert.Equal(t, "v3", resp.ModelVersion)
+	assert.Equal(t, 1, v2Calls)
+	assert.Equal(t, 1, v3Calls)
+
+	status, _ := client.RolloutStatus("anomaly-detector")
+	for _, version := range status.Versions {
+		assert.Zero(t, version.Requests, "version %s", version.Version)
+	}
+
+	_, err = client.PredictVersion(ctx, "anomaly-detector", "v9", [][]float64{{0.5}})
+	var versionErr *VersionNotFoundError
+	assert.ErrorAs(t, err, &versionErr)
+	_, err = client.PredictVersion(ctx, "predictive-analytics", "v3", [][]float64{{0.5}})
+	var modelErr *ModelNotFoundError
+	assert.ErrorAs(t, err, &modelErr)
+}
+
+func TestProxyClient_Rollout_CanaryErrors(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	var defaultCalls int
+	base := versionServer(t, 1, &defaultCalls)
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	client, err := NewProxyClient(ProxyConfig{Namespace: "test-ns"}, log)
+	require.NoError(t, err)
+	client.models["anomaly-detector"] = &ModelInfo{Name: "anomaly-detector", URL: base.URL}
+
+	rollouts, err := NewRollouts(map[string]ModelRollout{
+		"anomaly-detector": {
+			Versions: map[string]string{"v3": failing.URL},
+			Canary:   &CanaryRollout{Version: "v3", Percent: 100},
+		},
+	})
+	require.NoError(t, err)
+	client.SetRollouts(rollouts)
+
+	_, err = client.PredictFlexible(context.Background(), "anomaly-detector", [][]float64{{0.5}})
+	require.Error(t, err)
+
+	status, _ := client.RolloutStatus("anomaly-detector")
+	assert.Equal(t, DefaultVersion, status.Pinned)
+	assert.Equal(t, int64(1), status.Versions[1].Errors)
+	assert.Equal(t, 1.0, status.Versions[1].ErrorRate)
+	assert.Zero(t, defaultCalls)
+
+	// Requests the gate denies a canary go to the pinned version
+	client.SetCanaryGate(func(ctx context.Context, _ string) bool { return ctx.Value(canaryKey{}) == nil })
+	_, err = client.PredictFlexible(context.WithValue(context.Background(), canaryKey{}, "denied"), "anomaly-detector", [][]float64{{0.5}})
+	require.NoError(t, err)
+	assert.Equal(t, 1, defaultCalls)
+}
+
+type canaryKey struct{}
+
+func TestLoadRollouts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rollouts.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+models:
+  anomaly-detector:
+    versions:
+      v2: anomaly-detector-v2-predictor
+      v3: anomaly-detector-v3-predictor
+    pinned: v2
+    canary:
+      version: v3
+      percent: 10
+`), 0o600))
+
+	rollouts, err := LoadRollouts(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"anomaly-detector"}, rollouts.Models())
+
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	client, err := NewProxyClient(ProxyConfig{Namespace: "ml", PredictorPort: 8080}, log)
+	require.NoError(t, err)
+	client.models["anomaly-detector"] = &ModelInfo{Name: "anomaly-detector"}
+	client.SetRollouts(rollouts)
+	models := client.VersionModels("anomaly-detector")
+	require.Len(t, models, 2)
+	assert.Equal(t, "http://anomaly-detector-v3-predictor.ml.svc.cluster.local:8080", models["v3"].URL)
+}
+
+func TestNewRollouts_Errors(t *testing.T) {
+	versions := map[string]string{"v2": "svc-v2", "v3": "svc-v3"}
+	tests := map[string]ModelRollout{
+		"pinned or canary is required":      {Versions: versions},
+		"pinned version v4 is not listed":   {Versions: versions, Pinned: "v4"},
+		"canary version \"v4\" is not":      {Versions: versions, Canary: &CanaryRollout{Version: "v4", Percent: 10}},
+		"canary version v2 is already":      {Versions: versions, Pinned: "v2", Canary: &CanaryRollout{Version: "v2", Percent: 10}},
+		"canary percent must be between":    {Versions: versions, Canary: &CanaryRollout{Version: "v3", Percent: 0}},
+		"version v2 has no service":         {Versions: map[string]string{"v2": ""}, Pinned: "v2"},
+		"invalid version label \"default\"": {Versions: map[string]string{DefaultVersion: "svc"}, Pinned: DefaultVersion},
+	}
+	for want, rollout := range tests {
+		_, err := NewRollouts(map[string]ModelRollout{"anomaly-detector": rollout})
+		require.Error(t, err, want)
+		assert.Contains(t, err.Error(), want)
+	}
+}
+
+func zeroLatency(stats VersionStats) VersionStats {
+	stats.AvgLatencyMs = 0
+	return stats
+}
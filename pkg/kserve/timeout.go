@@ -0,0 +1,162 @@
+package kserve
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Adaptive timeout defaults
+const (
+	// DefaultTimeoutFactor multiplies a model's p95 latency into its timeout
+	DefaultTimeoutFactor = 3.0
+
+	// DefaultMinTimeout is the shortest adaptive timeout
+	DefaultMinTimeout = time.Second
+
+	// DefaultMaxTimeout is the longest adaptive timeout
+	DefaultMaxTimeout = time.Minute
+
+	// latencyWindowSize is the number of recent requests the p95 is computed over
+	latencyWindowSize = 100
+
+	// minLatencySamples is the number of requests observed before the timeout adapts
+	minLatencySamples = 20
+)
+
+// AdaptiveTimeoutConfig derives each model's request timeout from its observed
+// latency: the rolling p95 times Factor, bounded by Min and Max. Until enough
+// requests have been observed the configured timeout is used.
+type AdaptiveTimeoutConfig struct {
+	// Enabled turns adaptive timeouts on
+	Enabled bool
+
+	// Factor multiplies the p95 latency (default 3)
+	Factor float64
+
+	// Min and Max bound the timeout (defaults 1s and 1m)
+	Min time.Duration
+	Max time.Duration
+}
+
+// latencyWindow is a ring buffer of recent request latencies
+type latencyWindow struct {
+	samples [latencyWindowSize]time.Duration
+	count   int
+	next    int
+}
+
+func (w *latencyWindow) add(latency time.Duration) {
+	w.samples[w.next] = latency
+	w.next = (w.next + 1) % latencyWindowSize
+	if w.count < latencyWindowSize {
+		w.count++
+	}
+}
+
+func (w *latencyWindow) p95() time.Duration {
+	sorted := make([]time.Duration, w.count)
+	copy(sorted, w.samples[:w.count])
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return sorted[(w.count*95+99)/100-1]
+}
+
+// latencyTracker keeps a latency window per model version. Models without a
+// rollout are tracked as DefaultVersion.
+type latencyTracker struct {
+	cfg      AdaptiveTimeoutConfig
+	fallback time.Duration
+
+	mu      sync.Mutex
+	windows map[string]*latencyWindow
+}
+
+func newLatencyTracker(cfg AdaptiveTimeoutConfig, fallback time.Duration) *latencyTracker {
+	if cfg.Factor <= 0 {
+		cfg.Factor = DefaultTimeoutFactor
+	}
+	if cfg.Min <= 0 {
+		cfg.Min = DefaultMinTimeout
+	}
+	if cfg.Max <= 0 {
+		cfg.Max = DefaultMaxTimeout
+	}
+	return &latencyTracker{cfg: cfg, fallback: fallback, windows: make(map[string]*latencyWindow)}
+}
+
+// timeout returns the current timeout of a model version
+func (t *latencyTracker) timeout(model, version string) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.timeoutLocked(latencyKey(model, version))
+}
+
+func latencyKey(model, version string) string {
+	if version == "" {
+		version = DefaultVersion
+	}
+	return model + "@" + version
+}
+
+func (t *latencyTracker) timeoutLocked(key string) time.Duration {
+	w, ok := t.windows[key]
+	if !ok || w.count < minLatencySamples {
+		return t.fallback
+	}
+	timeout := time.Duration(float64(w.p95()) * t.cfg.Factor)
+	return min(max(timeout, t.cfg.Min), t.cfg.Max)
+}
+
+// observe records the latency of a request. Requests that hit their timeout are
+// recorded at the timeout so that a model that slows down gets longer timeouts;
+// other failures are ignored.
+func (t *latencyTracker) observe(model, version string, latency time.Duration, err error) {
+	if err != nil && !errors.Is(err, context.DeadlineExceeded) {
+		return
+	}
+
+	key := latencyKey(model, version)
+	t.mu.Lock()
+	w, ok := t.windows[key]
+	if !ok {
+		w = &latencyWindow{}
+		t.windows[key] = w
+	}
+	w.add(latency)
+	timeout := t.timeoutLocked(key)
+	t.mu.Unlock()
+
+	if version == "" {
+		version = DefaultVersion
+	}
+	ModelTimeoutSeconds.WithLabelValues(model, version).Set(timeout.Seconds())
+}
+
+// requestContext bounds a request to a model version by its adaptive timeout
+func (c *ProxyClient) requestContext(ctx context.Context, model, version string) (context.Context, context.CancelFunc) {
+	if c.latency == nil {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, c.latency.timeout(model, version))
+}
+
+// observeLatency records the latency of a request to a model version. ctx is the
+// caller's context: requests it cancelled or timed out are ignored.
+func (c *ProxyClient) observeLatency(ctx context.Context, model, version string, latency time.Duration, err error) {
+	if c.latency == nil || ctx.Err() != nil {
+		return
+	}
+	c.latency.observe(model, version, latency, err)
+}
+
+// ModelTimeout returns the request timeout currently applied to a model's pinned
+// version, which is the configured timeout unless adaptive timeouts are enabled
+func (c *ProxyClient) ModelTimeout(modelName string) time.Duration {
+	if c.latency == nil {
+		return c.httpClient.Timeout
+	}
+	_, version, _ := c.route(modelName, false)
+	return c.latency.timeout(modelName, version)
+}
@@ -0,0 +1,101 @@
+package kserve
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLatencyTracker(t *testing.T) {
+	tracker := newLatencyTracker(AdaptiveTimeoutConfig{Enabled: true, Factor: 3, Min: time.Second, Max: 20 * time.Second}, 10*time.Second)
+
+	// Too few samples: the configured timeout applies
+	for i := 0; i < minLatencySamples-1; i++ {
+		tracker.observe("anomaly-detector", "", 100*time.Millisecond, nil)
+	}
+	assert.Equal(t, 10*time.Second, tracker.timeout("anomaly-detector", ""))
+
+	// Fast model: 3 x 100ms is raised to the minimum
+	tracker.observe("anomaly-detector", "", 100*time.Millisecond, nil)
+	assert.Equal(t, time.Second, tracker.timeout("anomaly-detector", DefaultVersion))
+
+	// Slow but healthy model: p95 of 5s gives 15s, beyond the fixed 10s
+	for i := 0; i < latencyWindowSize; i++ {
+		tracker.observe("predictive-analytics", "", time.Duration(i%10+1)*time.Second/2, nil)
+	}
+	assert.Equal(t, 15*time.Second, tracker.timeout("predictive-analytics", ""))
+
+	// Very slow model: capped at the maximum
+	for i := 0; i < minLatencySamples; i++ {
+		tracker.observe("forecaster", "v2", 30*time.Second, nil)
+	}
+	assert.Equal(t, 20*time.Second, tracker.timeout("forecaster", "v2"))
+	assert.Equal(t, 10*time.Second, tracker.timeout("forecaster", "v3"), "versions are tracked separately")
+
+	// Timeouts count as samples, other failures do not
+	tracker.observe("other", "", 5*time.Second, context.DeadlineExceeded)
+	tracker.observe("other", "", 5*time.Second, errors.New("connection refused"))
+	assert.Equal(t, 1, tracker.windows["other@default"].count)
+}
+
+func TestLatencyWindow_Rolls(t *testing.T) {
+	var w latencyWindow
+	for i := 0; i < latencyWindowSize; i++ {
+		w.add(10 * time.Second)
+	}
+	for i := 0; i < latencyWindowSize; i++ {
+		w.add(time.Second)
+	}
+	assert.Equal(t, latencyWindowSize, w.count)
+	assert.Equal(t, time.Second, w.p95(), "old samples are evicted")
+}
+
+func TestProxyClient_AdaptiveTimeout(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	// Instances with a negative feature hang until the client gives up
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Instances [][]float64 `json:"instances"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		if req.Instances[0][0] < 0 {
+			<-r.Context().Done()
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"predictions": []int{1}})
+	}))
+	defer server.Close()
+
+	client, err := NewProxyClient(ProxyConfig{
+		Namespace:       "test-ns",
+		Timeout:         10 * time.Second,
+		AdaptiveTimeout: AdaptiveTimeoutConfig{Enabled: true, Factor: 2, Min: 200 * time.Millisecond, Max: time.Minute},
+	}, log)
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, client.HTTPClient().Timeout)
+	client.models["anomaly-detector"] = &ModelInfo{Name: "anomaly-detector", URL: server.URL}
+
+	ctx := context.Background()
+	assert.Equal(t, 10*time.Second, client.ModelTimeout("anomaly-detector"))
+	for i := 0; i < minLatencySamples; i++ {
+		_, err := client.Predict(ctx, "anomaly-detector", [][]float64{{0.5}})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 200*time.Millisecond, client.ModelTimeout("anomaly-detector"))
+
+	// A hung model is abandoned after the adaptive timeout rather than 10s
+	start := time.Now()
+	_, err = client.Predict(ctx, "anomaly-detector", [][]float64{{-1}})
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+}
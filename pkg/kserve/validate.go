@@ -0,0 +1,109 @@
+package kserve
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// Error codes of InvalidResponseError
+const (
+	// ErrCodePredictionCountMismatch means the model returned a different number of
+	// predictions than instances sent
+	ErrCodePredictionCountMismatch = "PREDICTION_COUNT_MISMATCH"
+
+	// ErrCodePredictionOutOfRange means a prediction or score is outside its expected range
+	ErrCodePredictionOutOfRange = "PREDICTION_OUT_OF_RANGE"
+
+	// ErrCodePredictionNotFinite means a prediction is NaN or infinite
+	ErrCodePredictionNotFinite = "PREDICTION_NOT_FINITE"
+
+	// ErrCodeMalformedResponse means the response is not a valid KServe v1 response
+	ErrCodeMalformedResponse = "MALFORMED_MODEL_RESPONSE"
+)
+
+// InvalidResponseError is returned when a model answers successfully but its
+// response fails validation, which usually means the model is misconfigured
+type InvalidResponseError struct {
+	ModelName string
+	Code      string
+	Message   string
+	Cause     error
+}
+
+func (e *InvalidResponseError) Error() string {
+	return fmt.Sprintf("invalid response from model %s (%s): %s", e.ModelName, e.Code, e.Message)
+}
+
+func (e *InvalidResponseError) Unwrap() error {
+	return e.Cause
+}
+
+// nonFiniteToken matches the NaN and Infinity literals Python's json module writes
+var nonFiniteToken = regexp.MustCompile(`[\[:,]\s*-?(NaN|Infinity)\s*[\],}]`)
+
+// decodeError classifies a failure to decode a model response
+func decodeError(modelName string, body []byte, err error) error {
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case nonFiniteToken.Match(body):
+		return &InvalidResponseError{ModelName: modelName, Code: ErrCodePredictionNotFinite,
+			Message: "response contains NaN or Infinity", Cause: err}
+	case errors.As(err, &typeErr) && strings.HasPrefix(typeErr.Value, "number") && typeErr.Type != nil && typeErr.Type.Kind() == reflect.Float64:
+		return &InvalidResponseError{ModelName: modelName, Code: ErrCodePredictionNotFinite,
+			Message: fmt.Sprintf("%s overflows a float64", typeErr.Value), Cause: err}
+	default:
+		return &InvalidResponseError{ModelName: modelName, Code: ErrCodeMalformedResponse,
+			Message: fmt.Sprintf("failed to decode response: %v", err), Cause: err}
+	}
+}
+
+// ValidateAnomalyPredictions checks that an anomaly-detector returned one
+// prediction per instance and that each is -1 (anomaly), 0 or 1 (normal).
+// A nil slice means the response had no predictions field.
+func ValidateAnomalyPredictions(modelName string, predictions []int, instances int) error {
+	if predictions == nil {
+		return &InvalidResponseError{ModelName: modelName, Code: ErrCodeMalformedResponse,
+			Message: "response is missing the 'predictions' field"}
+	}
+	if len(predictions) != instances {
+		return &InvalidResponseError{ModelName: modelName, Code: ErrCodePredictionCountMismatch,
+			Message: fmt.Sprintf("returned %d predictions for %d instances", len(predictions), instances)}
+	}
+	for i, prediction := range predictions {
+		if prediction < -1 || prediction > 1 {
+			return &InvalidResponseError{ModelName: modelName, Code: ErrCodePredictionOutOfRange,
+				Message: fmt.Sprintf("predictions[%d] is %d, expected -1, 0 or 1", i, prediction)}
+		}
+	}
+	return nil
+}
+
+// validateForecast checks that forecast values are finite and confidences lie in
+// [0, 1]. rows is the number of prediction rows of an array-format response, which
+// must equal the number of instances; nested responses pass -1.
+func validateForecast(modelName string, resp *ForecastResponse, rows, instances int) error {
+	if rows >= 0 && rows != instances {
+		return &InvalidResponseError{ModelName: modelName, Code: ErrCodePredictionCountMismatch,
+			Message: fmt.Sprintf("returned %d predictions for %d instances", rows, instances)}
+	}
+	for metric, result := range resp.Predictions {
+		for i, value := range result.Forecast {
+			if math.IsNaN(value) || math.IsInf(value, 0) {
+				return &InvalidResponseError{ModelName: modelName, Code: ErrCodePredictionNotFinite,
+					Message: fmt.Sprintf("%s.forecast[%d] is %v", metric, i, value)}
+			}
+		}
+		for i, confidence := range result.Confidence {
+			if math.IsNaN(confidence) || confidence < 0 || confidence > 1 {
+				return &InvalidResponseError{ModelName: modelName, Code: ErrCodePredictionOutOfRange,
+					Message: fmt.Sprintf("%s.confidence[%d] is %v, expected 0.0 to 1.0", metric, i, confidence)}
+			}
+		}
+	}
+	return nil
+}
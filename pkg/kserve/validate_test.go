@@ -0,0 +1,117 @@
+package kserve
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateAnomalyPredictions(t *testing.T) {
+	assert.NoError(t, ValidateAnomalyPredictions("m", []int{-1, 0, 1}, 3))
+
+	err := ValidateAnomalyPredictions("m", []int{-1}, 2)
+	var invalid *InvalidResponseError
+	require.True(t, errors.As(err, &invalid))
+	assert.Equal(t, ErrCodePredictionCountMismatch, invalid.Code)
+
+	err = ValidateAnomalyPredictions("m", []int{1, 7}, 2)
+	require.True(t, errors.As(err, &invalid))
+	assert.Equal(t, ErrCodePredictionOutOfRange, invalid.Code)
+}
+
+func TestProxyClient_InvalidResponses(t *testing.T) {
+	tests := []struct {
+		name      string
+		flexible  bool
+		instances [][]float64
+		body      string
+		wantCode  string
+	}{
+		{
+			name:      "fewer predictions than instances",
+			instances: [][]float64{{0.1}, {0.2}},
+			body:      `{"predictions": [1]}`,
+			wantCode:  ErrCodePredictionCountMismatch,
+		},
+		{
+			name:      "anomaly score out of range",
+			instances: [][]float64{{0.1}},
+			body:      `{"predictions": [3]}`,
+			wantCode:  ErrCodePredictionOutOfRange,
+		},
+		{
+			name:      "missing predictions",
+			instances: [][]float64{{0.1}},
+			body:      `{"error": "model not loaded"}`,
+			wantCode:  ErrCodeMalformedResponse,
+		},
+		{
+			name:      "NaN forecast",
+			flexible:  true,
+			instances: [][]float64{{14.0, 2.0}},
+			body:      `{"predictions": [[NaN, 0.5]]}`,
+			wantCode:  ErrCodePredictionNotFinite,
+		},
+		{
+			name:      "overflowing forecast",
+			flexible:  true,
+			instances: [][]float64{{14.0, 2.0}},
+			body:      `{"predictions": [[1e999, 0.5]]}`,
+			wantCode:  ErrCodePredictionNotFinite,
+		},
+		{
+			name:      "forecast rows do not match instances",
+			flexible:  true,
+			instances: [][]float64{{14.0, 2.0}},
+			body:      `{"predictions": [[0.5, 0.6], [0.55, 0.65]]}`,
+			wantCode:  ErrCodePredictionCountMismatch,
+		},
+		{
+			name:      "confidence out of range",
+			flexible:  true,
+			instances: [][]float64{{14.0, 2.0}},
+			body:      `{"predictions": {"cpu_usage": {"forecast": [0.5], "confidence": [1.5]}}}`,
+			wantCode:  ErrCodePredictionOutOfRange,
+		},
+		{
+			name:      "unsupported format",
+			flexible:  true,
+			instances: [][]float64{{14.0, 2.0}},
+			body:      `{"predictions": "ok"}`,
+			wantCode:  ErrCodeMalformedResponse,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			log := logrus.New()
+			log.SetLevel(logrus.ErrorLevel)
+			client, err := NewProxyClient(ProxyConfig{Namespace: "test-ns"}, log)
+			require.NoError(t, err)
+			client.models["model"] = &ModelInfo{Name: "model", URL: server.URL}
+
+			if tt.flexible {
+				_, err = client.PredictFlexible(context.Background(), "model", tt.instances)
+			} else {
+				_, err = client.Predict(context.Background(), "model", tt.instances)
+			}
+
+			var invalid *InvalidResponseError
+			require.True(t, errors.As(err, &invalid), "expected InvalidResponseError, got %v", err)
+			assert.Equal(t, tt.wantCode, invalid.Code)
+			assert.Equal(t, "model", invalid.ModelName)
+		})
+	}
+}
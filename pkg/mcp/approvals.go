@@ -0,0 +1,365 @@
+package mcp
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/apitoken"
+	v1 "github.com/tosin2013/openshift-coordination-engine/pkg/api/v1"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/api/validation"
+)
+
+// DefaultApprovalTTL is how long a remediation request waits for a decision
+const DefaultApprovalTTL = 30 * time.Minute
+
+// IdentityAdmin is the approver recorded for decisions made with the admin token
+const IdentityAdmin = "admin"
+
+// ErrSelfApproval is returned when a credential decides a remediation it requested
+var ErrSelfApproval = errors.New("approvals cannot be decided by the credential that requested them")
+
+// ApprovalStatus is the state of a remediation approval
+type ApprovalStatus string
+
+// Approval statuses
+const (
+	ApprovalPending  ApprovalStatus = "pending_approval"
+	ApprovalApproved ApprovalStatus = "approved"
+	ApprovalRejected ApprovalStatus = "rejected"
+	ApprovalExpired  ApprovalStatus = "expired"
+	ApprovalExecuted ApprovalStatus = "executed"
+	ApprovalFailed   ApprovalStatus = "failed"
+)
+
+// Approval is a remediation requested through MCP and the human decision on it
+type Approval struct {
+	ID          string                       `json:"id"`
+	Status      ApprovalStatus               `json:"status"`
+	Request     v1.TriggerRemediationRequest `json:"request"`
+	Reason      string                       `json:"reason,omitempty"`
+	RequestedBy string                       `json:"requested_by,omitempty"`
+	CreatedAt   time.Time                    `json:"created_at"`
+	ExpiresAt   time.Time                    `json:"expires_at"`
+	DecidedBy   string                       `json:"decided_by,omitempty"`
+	DecidedAt   *time.Time                   `json:"decided_at,omitempty"`
+	Comment     string                       `json:"comment,omitempty"`
+	WorkflowID  string                       `json:"workflow_id,omitempty"`
+	Error       string                       `json:"error,omitempty"`
+}
+
+// ApprovalStore holds remediation approvals in memory. Approvals can only be decided
+// through the REST API with the admin token or an API token with the
+// approve:remediation scope, and never by the API token that requested them, so an
+// assistant cannot approve its own requests. An approved request can be executed once.
+type ApprovalStore struct {
+	approvals  map[string]*Approval
+	ttl        time.Duration
+	adminToken string
+	mu         sync.Mutex
+	log        *logrus.Logger
+
+	// now is replaceable in tests
+	now func() time.Time
+}
+
+// NewApprovalStore creates an approval store; ttl <= 0 uses DefaultApprovalTTL
+func NewApprovalStore(ttl time.Duration, log *logrus.Logger) *ApprovalStore {
+	if ttl <= 0 {
+		ttl = DefaultApprovalTTL
+	}
+	return &ApprovalStore{
+		approvals: make(map[string]*Approval),
+		ttl:       ttl,
+		log:       log,
+		now:       time.Now,
+	}
+}
+
+// SetAdminToken lets the admin token decide approvals
+func (s *ApprovalStore) SetAdminToken(token string) {
+	s.adminToken = token
+}
+
+// Request records a pending approval for a remediation request. requestedBy is the
+// identity of the caller (see Requester) and may not decide the approval.
+func (s *ApprovalStore) Request(req *v1.TriggerRemediationRequest, reason, requestedBy string) Approval {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	approval := &Approval{
+		ID:          "apr-" + uuid.New().String()[:8],
+		Status:      ApprovalPending,
+		Request:     *req,
+		Reason:      reason,
+		RequestedBy: requestedBy,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(s.ttl),
+	}
+	s.approvals[approval.ID] = approval
+
+	s.log.WithFields(logrus.Fields{
+		"approval_id":  approval.ID,
+		"requested_by": requestedBy,
+		"namespace":    req.Namespace,
+		"resource":     req.Resource.Kind + "/" + req.Resource.Name,
+		"issue_type":   req.Issue.Type,
+	}).Info("Remediation approval requested via MCP")
+
+	return *approval
+}
+
+// Get returns an approval by ID
+func (s *ApprovalStore) Get(id string) (Approval, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	approval, ok := s.approvals[id]
+	if !ok {
+		return Approval{}, false
+	}
+	s.expire(approval)
+	return *approval, true
+}
+
+// List returns all approvals, newest first
+func (s *ApprovalStore) List() []Approval {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	results := make([]Approval, 0, len(s.approvals))
+	for _, approval := range s.approvals {
+		s.expire(approval)
+		results = append(results, *approval)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].CreatedAt.After(results[j].CreatedAt)
+	})
+	return results
+}
+
+// Decide approves or rejects a pending approval. It returns ErrSelfApproval when
+// decidedBy requested the approval.
+func (s *ApprovalStore) Decide(id string, approve bool, decidedBy, comment string) (Approval, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	approval, ok := s.approvals[id]
+	if !ok {
+		return Approval{}, fmt.Errorf("approval not found: %s", id)
+	}
+	s.expire(approval)
+	if approval.Status != ApprovalPending {
+		return Approval{}, fmt.Errorf("approval %s is %s, not pending", id, approval.Status)
+	}
+	if approval.RequestedBy != "" && approval.RequestedBy == decidedBy {
+		return Approval{}, ErrSelfApproval
+	}
+
+	now := s.now()
+	approval.Status = ApprovalRejected
+	if approve {
+		approval.Status = ApprovalApproved
+	}
+	approval.DecidedBy = decidedBy
+	approval.DecidedAt = &now
+	approval.Comment = comment
+
+	s.log.WithFields(logrus.Fields{
+		"approval_id": id,
+		"status":      approval.Status,
+		"decided_by":  decidedBy,
+	}).Info("Remediation approval decided")
+
+	return *approval, nil
+}
+
+// Claim takes an approved request for execution. It fails unless the approval is
+// approved and unexpired, and marks it executed so it cannot be claimed twice.
+func (s *ApprovalStore) Claim(id string) (Approval, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	approval, ok := s.approvals[id]
+	if !ok {
+		return Approval{}, fmt.Errorf("approval not found: %s", id)
+	}
+	s.expire(approval)
+
+	switch approval.Status {
+	case ApprovalApproved:
+		approval.Status = ApprovalExecuted
+		return *approval, nil
+	case ApprovalPending:
+		return Approval{}, fmt.Errorf("approval %s is still pending: an operator must approve it first", id)
+	default:
+		return Approval{}, fmt.Errorf("approval %s is %s and cannot be executed", id, approval.Status)
+	}
+}
+
+// RecordExecution stores the outcome of executing a claimed approval
+func (s *ApprovalStore) RecordExecution(id, workflowID string, execErr error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	approval, ok := s.approvals[id]
+	if !ok {
+		return
+	}
+	approval.WorkflowID = workflowID
+	if execErr != nil {
+		approval.Status = ApprovalFailed
+		approval.Error = execErr.Error()
+	}
+}
+
+// expire marks a pending or approved approval expired once its TTL has passed.
+// Callers must hold s.mu.
+func (s *ApprovalStore) expire(approval *Approval) {
+	if (approval.Status == ApprovalPending || approval.Status == ApprovalApproved) && s.now().After(approval.ExpiresAt) {
+		approval.Status = ApprovalExpired
+	}
+}
+
+// Requester returns the identity of the API token that authenticated ctx, or "" if
+// the request was not made with an API token
+func Requester(ctx context.Context) string {
+	if token := apitoken.FromContext(ctx); token != nil {
+		return tokenIdentity(token)
+	}
+	return ""
+}
+
+// tokenIdentity identifies an API token in approvals
+func tokenIdentity(token *apitoken.Token) string {
+	return "token:" + token.ID
+}
+
+// approver returns the authenticated identity deciding an approval: an API token with
+// the approve:remediation scope, or the admin token. ok is false for any other caller.
+func (s *ApprovalStore) approver(r *http.Request) (identity string, ok bool) {
+	if token := apitoken.FromContext(r.Context()); token != nil {
+		return tokenIdentity(token), token.HasScope(apitoken.ScopeApproveRemediation)
+	}
+	bearer, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if found && s.adminToken != "" && subtle.ConstantTimeCompare([]byte(bearer), []byte(s.adminToken)) == 1 {
+		return IdentityAdmin, true
+	}
+	return "", false
+}
+
+// decisionRequest is the body of the approve/reject endpoints. The approver is taken
+// from the request's credential.
+type decisionRequest struct {
+	Comment string `json:"comment"`
+}
+
+// RegisterRoutes registers the approval endpoints used by operators
+func (s *ApprovalStore) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/api/v1/mcp/approvals", s.handleList).Methods("GET")
+	router.HandleFunc("/api/v1/mcp/approvals/{id}", s.handleGet).Methods("GET")
+	router.HandleFunc("/api/v1/mcp/approvals/{id}/approve", s.handleDecision(true)).Methods("POST")
+	router.HandleFunc("/api/v1/mcp/approvals/{id}/reject", s.handleDecision(false)).Methods("POST")
+	if s.adminToken == "" {
+		s.log.Warn("ADMIN_TOKEN not set, MCP approvals can only be decided with API tokens that have the approve:remediation scope")
+	}
+	s.log.Info("MCP approval API endpoints registered: /api/v1/mcp/approvals")
+}
+
+// handleList handles GET /api/v1/mcp/approvals
+func (s *ApprovalStore) handleList(w http.ResponseWriter, r *http.Request) {
+	status := ApprovalStatus(r.URL.Query().Get("status"))
+
+	approvals := make([]Approval, 0)
+	for _, approval := range s.List() {
+		if status == "" || approval.Status == status {
+			approvals = append(approvals, approval)
+		}
+	}
+
+	s.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"approvals": approvals,
+		"total":     len(approvals),
+	})
+}
+
+// handleGet handles GET /api/v1/mcp/approvals/{id}
+func (s *ApprovalStore) handleGet(w http.ResponseWriter, r *http.Request) {
+	approval, ok := s.Get(mux.Vars(r)["id"])
+	if !ok {
+		s.respondError(w, http.StatusNotFound, "approval not found")
+		return
+	}
+	s.respondJSON(w, http.StatusOK, approval)
+}
+
+// handleDecision handles POST /api/v1/mcp/approvals/{id}/approve and /reject
+func (s *ApprovalStore) handleDecision(approve bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		decidedBy, ok := s.approver(r)
+		if !ok {
+			if decidedBy == "" {
+				s.respondError(w, http.StatusUnauthorized, "admin token or API token with scope approve:remediation required")
+			} else {
+				s.respondError(w, http.StatusForbidden, "API token lacks scope approve:remediation")
+			}
+			return
+		}
+
+		var req decisionRequest
+		if r.ContentLength > 0 {
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				s.respondError(w, http.StatusBadRequest, "invalid request body: "+err.Error(), validation.DecodeFields(err)...)
+				return
+			}
+		}
+
+		id := mux.Vars(r)["id"]
+		if _, ok := s.Get(id); !ok {
+			s.respondError(w, http.StatusNotFound, "approval not found")
+			return
+		}
+
+		approval, err := s.Decide(id, approve, decidedBy, req.Comment)
+		if errors.Is(err, ErrSelfApproval) {
+			s.respondError(w, http.StatusForbidden, err.Error())
+			return
+		}
+		if err != nil {
+			s.respondError(w, http.StatusConflict, err.Error())
+			return
+		}
+		s.respondJSON(w, http.StatusOK, approval)
+	}
+}
+
+func (s *ApprovalStore) respondJSON(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		s.log.WithError(err).Error("Failed to encode approval response")
+	}
+}
+
+func (s *ApprovalStore) respondError(w http.ResponseWriter, statusCode int, message string, fieldErrs ...validation.FieldError) {
+	response := map[string]interface{}{
+		"status": "error",
+		"error":  message,
+	}
+	if len(fieldErrs) > 0 {
+		response["errors"] = fieldErrs
+	}
+	s.respondJSON(w, statusCode, response)
+}
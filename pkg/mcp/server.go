@@ -0,0 +1,252 @@
+// Package mcp exposes coordination engine capabilities as Model Context Protocol tools.
+//
+// The server implements the MCP streamable HTTP transport in its JSON-only form: clients
+// POST JSON-RPC 2.0 messages to a single endpoint and receive JSON responses. Tools call
+// the same shared handler logic as the REST API. Tools that change the cluster require a
+// human approval recorded through the REST API before they execute (see ApprovalStore).
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+// ProtocolVersion is the latest MCP protocol revision supported by the server
+const ProtocolVersion = "2025-06-18"
+
+// supportedProtocolVersions lists revisions the server can negotiate
+var supportedProtocolVersions = map[string]bool{
+	"2024-11-05": true,
+	"2025-03-26": true,
+	"2025-06-18": true,
+}
+
+// JSON-RPC 2.0 error codes
+const (
+	errCodeParse          = -32700
+	errCodeInvalidRequest = -32600
+	errCodeMethodNotFound = -32601
+	errCodeInvalidParams  = -32602
+)
+
+// maxRequestBodySize limits the size of a JSON-RPC request body
+const maxRequestBodySize = 1 << 20
+
+// Server is an MCP server exposing coordination engine tools
+type Server struct {
+	tools   []*Tool
+	byName  map[string]*Tool
+	version string
+	log     *logrus.Logger
+}
+
+// NewServer creates an MCP server exposing the given tools
+func NewServer(version string, log *logrus.Logger, tools ...*Tool) *Server {
+	s := &Server{
+		byName:  make(map[string]*Tool, len(tools)),
+		version: version,
+		log:     log,
+	}
+	for _, tool := range tools {
+		s.tools = append(s.tools, tool)
+		s.byName[tool.Name] = tool
+	}
+	return s
+}
+
+// RegisterRoutes registers the MCP endpoint at /mcp
+func (s *Server) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/mcp", s.ServeHTTP).Methods("POST")
+	router.HandleFunc("/mcp", s.methodNotAllowed).Methods("GET", "DELETE")
+	s.log.WithField("tools", len(s.tools)).Info("MCP endpoint registered: POST /mcp")
+}
+
+// rpcRequest is a JSON-RPC 2.0 request or notification
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// isNotification reports whether the message expects no response
+func (r *rpcRequest) isNotification() bool {
+	return len(r.ID) == 0
+}
+
+// rpcResponse is a JSON-RPC 2.0 response
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+// rpcError is a JSON-RPC 2.0 error object
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// ServeHTTP handles a JSON-RPC message posted to the MCP endpoint
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req rpcRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBodySize)).Decode(&req); err != nil {
+		s.respond(w, &rpcResponse{ID: json.RawMessage("null"), Error: &rpcError{Code: errCodeParse, Message: "parse error: " + err.Error()}})
+		return
+	}
+
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		s.respond(w, &rpcResponse{ID: nullIfEmpty(req.ID), Error: &rpcError{Code: errCodeInvalidRequest, Message: "invalid JSON-RPC 2.0 request"}})
+		return
+	}
+
+	// Notifications (e.g. notifications/initialized) are acknowledged without a body
+	if req.isNotification() {
+		s.log.WithField("method", req.Method).Debug("MCP notification received")
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	result, rpcErr := s.dispatch(r.Context(), &req)
+	s.respond(w, &rpcResponse{ID: req.ID, Result: result, Error: rpcErr})
+}
+
+// dispatch routes a request to its method implementation
+func (s *Server) dispatch(ctx context.Context, req *rpcRequest) (interface{}, *rpcError) {
+	switch req.Method {
+	case "initialize":
+		return s.initialize(req.Params)
+	case "ping":
+		return struct{}{}, nil
+	case "tools/list":
+		return s.listTools(), nil
+	case "tools/call":
+		return s.callTool(ctx, req.Params)
+	default:
+		return nil, &rpcError{Code: errCodeMethodNotFound, Message: fmt.Sprintf("method not found: %s", req.Method)}
+	}
+}
+
+// initialize negotiates the protocol version and advertises server capabilities
+func (s *Server) initialize(params json.RawMessage) (interface{}, *rpcError) {
+	var p struct {
+		ProtocolVersion string `json:"protocolVersion"`
+	}
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, &rpcError{Code: errCodeInvalidParams, Message: "invalid initialize params: " + err.Error()}
+		}
+	}
+
+	version := ProtocolVersion
+	if supportedProtocolVersions[p.ProtocolVersion] {
+		version = p.ProtocolVersion
+	}
+
+	return map[string]interface{}{
+		"protocolVersion": version,
+		"capabilities": map[string]interface{}{
+			"tools": map[string]interface{}{"listChanged": false},
+		},
+		"serverInfo": map[string]string{
+			"name":    "openshift-coordination-engine",
+			"version": s.version,
+		},
+		"instructions": "Tools that change the cluster return an approval_id first; " +
+			"call them again with that approval_id once a human has approved the request.",
+	}, nil
+}
+
+// listTools returns the tool definitions
+func (s *Server) listTools() interface{} {
+	defs := make([]map[string]interface{}, 0, len(s.tools))
+	for _, tool := range s.tools {
+		defs = append(defs, map[string]interface{}{
+			"name":        tool.Name,
+			"description": tool.Description,
+			"inputSchema": tool.InputSchema,
+		})
+	}
+	return map[string]interface{}{"tools": defs}
+}
+
+// callTool runs a tool. Tool failures are reported in the result with isError set, so
+// the model can see them; only unknown tools and malformed params are protocol errors.
+func (s *Server) callTool(ctx context.Context, params json.RawMessage) (interface{}, *rpcError) {
+	var p struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	}
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, &rpcError{Code: errCodeInvalidParams, Message: "invalid tools/call params: " + err.Error()}
+	}
+
+	tool, ok := s.byName[p.Name]
+	if !ok {
+		return nil, &rpcError{Code: errCodeInvalidParams, Message: fmt.Sprintf("unknown tool: %s", p.Name)}
+	}
+
+	args := p.Arguments
+	if len(args) == 0 || string(args) == "null" {
+		args = json.RawMessage("{}")
+	}
+
+	s.log.WithField("tool", tool.Name).Info("MCP tool call")
+
+	result, err := tool.Handler(ctx, args)
+	if err != nil {
+		s.log.WithError(err).WithField("tool", tool.Name).Warn("MCP tool call failed")
+		return toolResult(map[string]string{"error": err.Error()}, true), nil
+	}
+	return toolResult(result, false), nil
+}
+
+// toolResult wraps a tool's output as MCP content: a JSON text block for all clients
+// plus structuredContent for clients that support it
+func toolResult(data interface{}, isError bool) map[string]interface{} {
+	text, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		text = []byte(fmt.Sprintf(`{"error": %q}`, err.Error()))
+		isError = true
+	}
+
+	result := map[string]interface{}{
+		"content": []map[string]string{{"type": "text", "text": string(text)}},
+		"isError": isError,
+	}
+	if !isError {
+		result["structuredContent"] = data
+	}
+	return result
+}
+
+// respond writes a JSON-RPC response
+func (s *Server) respond(w http.ResponseWriter, resp *rpcResponse) {
+	resp.JSONRPC = "2.0"
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		s.log.WithError(err).Error("Failed to encode MCP response")
+	}
+}
+
+// methodNotAllowed rejects GET (server-sent event streams) and DELETE (sessions),
+// which this stateless server does not offer
+func (s *Server) methodNotAllowed(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Allow", "POST")
+	http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+}
+
+// nullIfEmpty returns a JSON null ID for responses to messages without one
+func nullIfEmpty(id json.RawMessage) json.RawMessage {
+	if len(id) == 0 {
+		return json.RawMessage("null")
+	}
+	return id
+}
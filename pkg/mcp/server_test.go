@@ -0,0 +1,350 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/apitoken"
+	v1 "github.com/tosin2013/openshift-coordination-engine/pkg/api/v1"
+)
+
+// fakeTrigger records remediation requests instead of running workflows
+type fakeTrigger struct {
+	requests []v1.TriggerRemediationRequest
+	err      error
+}
+
+func (f *fakeTrigger) Trigger(_ context.Context, req *v1.TriggerRemediationRequest) (*v1.TriggerRemediationResponse, error) {
+	f.requests = append(f.requests, *req)
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &v1.TriggerRemediationResponse{WorkflowID: "wf-123", Status: "pending"}, nil
+}
+
+const testAdminToken = "admin-secret"
+
+type testEnv struct {
+	router    *mux.Router
+	approvals *ApprovalStore
+	trigger   *fakeTrigger
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+
+	env := &testEnv{
+		router:    mux.NewRouter(),
+		approvals: NewApprovalStore(time.Minute, log),
+		trigger:   &fakeTrigger{},
+	}
+	env.approvals.SetAdminToken(testAdminToken)
+	env.approvals.RegisterRoutes(env.router)
+	NewServer("test", log,
+		AnalyzeAnomaliesTool(v1.NewAnomalyHandler(nil, nil, log)),
+		ExecuteRemediationWithApprovalTool(env.trigger, env.approvals),
+	).RegisterRoutes(env.router)
+	return env
+}
+
+// rpc posts a JSON-RPC request and decodes the response
+func (e *testEnv) rpc(t *testing.T, method string, params interface{}) map[string]interface{} {
+	t.Helper()
+	body, err := json.Marshal(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "method": method, "params": params})
+	require.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	e.router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/mcp", bytes.NewReader(body)))
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, "2.0", resp["jsonrpc"])
+	return resp
+}
+
+// callTool calls a tool and returns its structured (or error) result
+func (e *testEnv) callTool(t *testing.T, name string, args interface{}) (map[string]interface{}, bool) {
+	t.Helper()
+	resp := e.rpc(t, "tools/call", map[string]interface{}{"name": name, "arguments": args})
+	require.Nil(t, resp["error"])
+
+	result := resp["result"].(map[string]interface{})
+	content := result["content"].([]interface{})[0].(map[string]interface{})
+	var data map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(content["text"].(string)), &data))
+	return data, result["isError"].(bool)
+}
+
+// decide approves or rejects with the admin token
+func (e *testEnv) decide(t *testing.T, id, action string) int {
+	t.Helper()
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/mcp/approvals/"+id+"/"+action, bytes.NewBufferString(`{"comment":"ok"}`))
+	req.Header.Set("Authorization", "Bearer "+testAdminToken)
+	e.router.ServeHTTP(rr, req)
+	return rr.Code
+}
+
+var remediationRequest = map[string]interface{}{
+	"incident_id": "inc-1",
+	"namespace":   "production",
+	"resource":    map[string]string{"kind": "Deployment", "name": "api"},
+	"issue":       map[string]string{"type": "CrashLoopBackOff"},
+	"reason":      "api pods crash looping",
+}
+
+func TestServer_Initialize(t *testing.T) {
+	env := newTestEnv(t)
+
+	resp := env.rpc(t, "initialize", map[string]interface{}{"protocolVersion": "2025-03-26"})
+	result := resp["result"].(map[string]interface{})
+	assert.Equal(t, "2025-03-26", result["protocolVersion"])
+	assert.Contains(t, result["capabilities"], "tools")
+
+	resp = env.rpc(t, "initialize", map[string]interface{}{"protocolVersion": "1999-01-01"})
+	assert.Equal(t, ProtocolVersion, resp["result"].(map[string]interface{})["protocolVersion"])
+}
+
+func TestServer_Notification(t *testing.T) {
+	env := newTestEnv(t)
+	rr := httptest.NewRecorder()
+	env.router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/mcp",
+		bytes.NewBufferString(`{"jsonrpc":"2.0","method":"notifications/initialized"}`)))
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+	assert.Empty(t, rr.Body.String())
+}
+
+func TestServer_ProtocolErrors(t *testing.T) {
+	env := newTestEnv(t)
+
+	tests := []struct {
+		name string
+		body string
+		code float64
+	}{
+		{name: "parse error", body: `{`, code: errCodeParse},
+		{name: "wrong version", body: `{"jsonrpc":"1.0","id":1,"method":"ping"}`, code: errCodeInvalidRequest},
+		{name: "unknown method", body: `{"jsonrpc":"2.0","id":1,"method":"resources/list"}`, code: errCodeMethodNotFound},
+		{name: "unknown tool", body: `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"delete_cluster"}}`, code: errCodeInvalidParams},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			env.router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/mcp", bytes.NewBufferString(tt.body)))
+			var resp map[string]interface{}
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			assert.Equal(t, tt.code, resp["error"].(map[string]interface{})["code"])
+		})
+	}
+}
+
+func TestServer_ListTools(t *testing.T) {
+	env := newTestEnv(t)
+
+	resp := env.rpc(t, "tools/list", nil)
+	tools := resp["result"].(map[string]interface{})["tools"].([]interface{})
+	require.Len(t, tools, 2)
+
+	names := make([]string, 0, len(tools))
+	for _, tool := range tools {
+		def := tool.(map[string]interface{})
+		names = append(names, def["name"].(string))
+		assert.Equal(t, "object", def["inputSchema"].(map[string]interface{})["type"])
+	}
+	assert.Equal(t, []string{ToolAnalyzeAnomalies, ToolExecuteRemediationWithApproval}, names)
+}
+
+func TestServer_ToolErrorsAreResults(t *testing.T) {
+	env := newTestEnv(t)
+
+	data, isError := env.callTool(t, ToolAnalyzeAnomalies, map[string]interface{}{"time_range": "1h"})
+	assert.True(t, isError)
+	assert.Contains(t, data["error"], "KServe integration not enabled")
+
+	data, isError = env.callTool(t, ToolAnalyzeAnomalies, map[string]interface{}{"namespce": "typo"})
+	assert.True(t, isError)
+	assert.Contains(t, data["error"], "unknown field")
+}
+
+func TestExecuteRemediation_RequiresApproval(t *testing.T) {
+	env := newTestEnv(t)
+
+	// First call only records the request
+	data, isError := env.callTool(t, ToolExecuteRemediationWithApproval, remediationRequest)
+	require.False(t, isError)
+	assert.Equal(t, string(ApprovalPending), data["status"])
+	approvalID := data["approval_id"].(string)
+	assert.Empty(t, env.trigger.requests)
+
+	// Executing before approval fails
+	data, isError = env.callTool(t, ToolExecuteRemediationWithApproval, map[string]string{"approval_id": approvalID})
+	assert.True(t, isError)
+	assert.Contains(t, data["error"], "still pending")
+
+	// An operator approves through the REST API
+	assert.Equal(t, http.StatusOK, env.decide(t, approvalID, "approve"))
+
+	data, isError = env.callTool(t, ToolExecuteRemediationWithApproval, map[string]string{"approval_id": approvalID})
+	require.False(t, isError)
+	assert.Equal(t, "wf-123", data["workflow_id"])
+	require.Len(t, env.trigger.requests, 1)
+	assert.Equal(t, "api", env.trigger.requests[0].Resource.Name)
+
+	approval, ok := env.approvals.Get(approvalID)
+	require.True(t, ok)
+	assert.Equal(t, ApprovalExecuted, approval.Status)
+	assert.Equal(t, IdentityAdmin, approval.DecidedBy)
+	assert.Equal(t, "ok", approval.Comment)
+	assert.Equal(t, "wf-123", approval.WorkflowID)
+
+	// An approval executes only once
+	data, isError = env.callTool(t, ToolExecuteRemediationWithApproval, map[string]string{"approval_id": approvalID})
+	assert.True(t, isError)
+	assert.Contains(t, data["error"], "executed")
+	assert.Len(t, env.trigger.requests, 1)
+}
+
+func TestExecuteRemediation_Rejected(t *testing.T) {
+	env := newTestEnv(t)
+
+	data, _ := env.callTool(t, ToolExecuteRemediationWithApproval, remediationRequest)
+	approvalID := data["approval_id"].(string)
+
+	assert.Equal(t, http.StatusOK, env.decide(t, approvalID, "reject"))
+	assert.Equal(t, http.StatusConflict, env.decide(t, approvalID, "approve"))
+
+	data, isError := env.callTool(t, ToolExecuteRemediationWithApproval, map[string]string{"approval_id": approvalID})
+	assert.True(t, isError)
+	assert.Contains(t, data["error"], "rejected")
+	assert.Empty(t, env.trigger.requests)
+}
+
+func TestExecuteRemediation_InvalidRequest(t *testing.T) {
+	env := newTestEnv(t)
+
+	data, isError := env.callTool(t, ToolExecuteRemediationWithApproval, map[string]string{"namespace": "production"})
+	assert.True(t, isError)
+	assert.Contains(t, data["error"], "incident_id is required")
+	assert.Empty(t, env.approvals.List())
+}
+
+func TestApprovalStore_Expiry(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	store := NewApprovalStore(time.Minute, log)
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+
+	approval := store.Request(&v1.TriggerRemediationRequest{IncidentID: "inc-1"}, "", "")
+	_, err := store.Decide(approval.ID, true, "alice", "")
+	require.NoError(t, err)
+
+	now = now.Add(2 * time.Minute)
+	_, err = store.Claim(approval.ID)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "expired")
+}
+
+func TestApprovalStore_FailedExecution(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	store := NewApprovalStore(time.Minute, log)
+
+	approval := store.Request(&v1.TriggerRemediationRequest{IncidentID: "inc-1"}, "", "")
+	_, err := store.Decide(approval.ID, true, "alice", "")
+	require.NoError(t, err)
+	_, err = store.Claim(approval.ID)
+	require.NoError(t, err)
+
+	store.RecordExecution(approval.ID, "", errors.New("no remediator"))
+	got, _ := store.Get(approval.ID)
+	assert.Equal(t, ApprovalFailed, got.Status)
+	assert.Equal(t, "no remediator", got.Error)
+}
+
+func TestApprovalRoutes_RequireApprover(t *testing.T) {
+	env := newTestEnv(t)
+	approval := env.approvals.Request(&v1.TriggerRemediationRequest{IncidentID: "inc-1"}, "", "")
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/mcp/approvals/"+approval.ID+"/approve", bytes.NewBufferString(`{"decided_by":"alice"}`))
+	env.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	rr = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/mcp/approvals/"+approval.ID+"/approve", http.NoBody)
+	req.Header.Set("Authorization", "Bearer wrong")
+	env.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	assert.Equal(t, http.StatusNotFound, env.decide(t, "apr-missing", "approve"))
+
+	rr = httptest.NewRecorder()
+	env.router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/mcp/approvals?status=pending_approval", http.NoBody))
+	require.Equal(t, http.StatusOK, rr.Code)
+	var resp struct {
+		Total int `json:"total"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, 1, resp.Total)
+}
+
+func TestApprovalRoutes_SelfApproval(t *testing.T) {
+	env := newTestEnv(t)
+	tokens := apitoken.NewStore(t.TempDir())
+	handler := apitoken.Middleware(tokens, true, logrus.New())(env.router)
+
+	assistant, _, err := tokens.Create("assistant", []string{"execute:remediation", "approve:remediation"}, 0)
+	require.NoError(t, err)
+	executor, _, err := tokens.Create("executor", []string{"execute:remediation"}, 0)
+	require.NoError(t, err)
+	operator, operatorToken, err := tokens.Create("operator", []string{"approve:remediation"}, 0)
+	require.NoError(t, err)
+
+	send := func(secret, path, body string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, path, bytes.NewBufferString(body))
+		req.Header.Set("Authorization", "Bearer "+secret)
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	args, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0", "id": 1, "method": "tools/call",
+		"params": map[string]interface{}{"name": ToolExecuteRemediationWithApproval, "arguments": remediationRequest},
+	})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, send(assistant, "/mcp", string(args)).Code)
+	pending := env.approvals.List()
+	require.Len(t, pending, 1)
+	approvalID := pending[0].ID
+	assert.NotEmpty(t, pending[0].RequestedBy)
+
+	// The requesting token cannot approve, even with the approve scope
+	rr := send(assistant, "/api/v1/mcp/approvals/"+approvalID+"/approve", "{}")
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.Contains(t, rr.Body.String(), "requested them")
+
+	// Executing remediation does not allow deciding approvals
+	assert.Equal(t, http.StatusForbidden, send(executor, "/api/v1/mcp/approvals/"+approvalID+"/approve", "{}").Code)
+
+	require.Equal(t, http.StatusOK, send(operator, "/api/v1/mcp/approvals/"+approvalID+"/approve", "{}").Code)
+	approval, _ := env.approvals.Get(approvalID)
+	assert.Equal(t, ApprovalApproved, approval.Status)
+	assert.Equal(t, "token:"+operatorToken.ID, approval.DecidedBy)
+}
@@ -0,0 +1,234 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/remediation"
+	v1 "github.com/tosin2013/openshift-coordination-engine/pkg/api/v1"
+)
+
+// Tool is an MCP tool: a named operation with a JSON Schema for its arguments
+type Tool struct {
+	Name        string
+	Description string
+	InputSchema map[string]interface{}
+	Handler     func(ctx context.Context, args json.RawMessage) (interface{}, error)
+}
+
+// AnomalyAnalyzer runs ML anomaly analysis (implemented by v1.AnomalyHandler)
+type AnomalyAnalyzer interface {
+	Analyze(ctx context.Context, req *v1.AnomalyAnalyzeRequest) (*v1.AnomalyAnalyzeResponse, error)
+}
+
+// Recommender returns remediation recommendations (implemented by v1.RecommendationsHandler)
+type Recommender interface {
+	Recommend(ctx context.Context, req *v1.GetRecommendationsRequest) (*v1.GetRecommendationsResponse, error)
+}
+
+// CapacityAnalyzer analyzes namespace capacity (implemented by v1.CapacityHandler)
+type CapacityAnalyzer interface {
+	AnalyzeNamespace(ctx context.Context, namespace string, opts v1.NamespaceCapacityOptions) (*v1.NamespaceCapacityResponse, error)
+}
+
+// RemediationTrigger starts remediation workflows (implemented by v1.RemediationHandler)
+type RemediationTrigger interface {
+	Trigger(ctx context.Context, req *v1.TriggerRemediationRequest) (*v1.TriggerRemediationResponse, error)
+}
+
+// Tool names
+const (
+	ToolAnalyzeAnomalies               = "analyze_anomalies"
+	ToolGetRecommendations             = "get_recommendations"
+	ToolGetCapacityForecast            = "get_capacity_forecast"
+	ToolExecuteRemediationWithApproval = "execute_remediation_with_approval"
+)
+
+// AnalyzeAnomaliesTool runs ML anomaly detection for a namespace, deployment or pod
+func AnalyzeAnomaliesTool(analyzer AnomalyAnalyzer) *Tool {
+	return &Tool{
+		Name: ToolAnalyzeAnomalies,
+		Description: "Run ML anomaly detection over Prometheus metrics for a namespace, deployment or pod. " +
+			"Returns detected anomalies with severity, score, explanation and recommended action. Read-only.",
+		InputSchema: objectSchema(map[string]interface{}{
+			"time_range": enumProperty("Analysis window (default: 1h)", "1h", "6h", "24h", "7d"),
+			"namespace":  stringProperty("Namespace to analyze"),
+			"deployment": stringProperty("Deployment to analyze (requires namespace)"),
+			"pod":        stringProperty("Pod to analyze (requires namespace)"),
+			"threshold":  numberProperty("Anomaly score threshold 0.0-1.0 (default: 0.7)"),
+			"at":         stringProperty("RFC3339 time to evaluate the metrics at, for retroactive analysis (default: now)"),
+		}),
+		Handler: func(ctx context.Context, args json.RawMessage) (interface{}, error) {
+			var req v1.AnomalyAnalyzeRequest
+			if err := decodeArgs(args, &req); err != nil {
+				return nil, err
+			}
+			return analyzer.Analyze(ctx, &req)
+		},
+	}
+}
+
+// GetRecommendationsTool returns remediation recommendations from history, ML and patterns
+func GetRecommendationsTool(recommender Recommender) *Tool {
+	return &Tool{
+		Name: ToolGetRecommendations,
+		Description: "Get remediation recommendations derived from incident history, ML predictions and known patterns. " +
+			"Read-only; use execute_remediation_with_approval to act on a recommendation.",
+		InputSchema: objectSchema(map[string]interface{}{
+			"timeframe":            enumProperty("Prediction horizon (default: 6h)", "1h", "6h", "24h"),
+			"include_predictions":  map[string]interface{}{"type": "boolean", "description": "Include ML predictions (default: true)"},
+			"confidence_threshold": numberProperty("Minimum confidence 0.0-1.0 (default: 0.7)"),
+			"namespace":            stringProperty("Only return recommendations for this namespace"),
+		}),
+		Handler: func(ctx context.Context, args json.RawMessage) (interface{}, error) {
+			var req v1.GetRecommendationsRequest
+			if err := decodeArgs(args, &req); err != nil {
+				return nil, err
+			}
+			return recommender.Recommend(ctx, &req)
+		},
+	}
+}
+
+// GetCapacityForecastTool returns namespace capacity with a usage trend projection
+func GetCapacityForecastTool(analyzer CapacityAnalyzer) *Tool {
+	return &Tool{
+		Name: ToolGetCapacityForecast,
+		Description: "Get current quota, usage and available capacity for a namespace, with a linear trend " +
+			"projecting when usage reaches 85% of quota. Read-only.",
+		InputSchema: objectSchema(map[string]interface{}{
+			"namespace":              stringProperty("Namespace to forecast"),
+			"window":                 enumProperty("Trending window (default: 7d)", "7d", "14d", "30d"),
+			"resolution":             enumProperty("Include usage series at this resolution (default: none)", "auto", "1h", "6h", "1d"),
+			"include_infrastructure": map[string]interface{}{"type": "boolean", "description": "Include infrastructure impact (default: false)"},
+			"include_pods":           map[string]interface{}{"type": "boolean", "description": "Include per-pod average and peak usage (default: false)"},
+		}, "namespace"),
+		Handler: func(ctx context.Context, args json.RawMessage) (interface{}, error) {
+			var req struct {
+				Namespace             string `json:"namespace"`
+				Window                string `json:"window"`
+				Resolution            string `json:"resolution"`
+				IncludeInfrastructure bool   `json:"include_infrastructure"`
+				IncludePods           bool   `json:"include_pods"`
+			}
+			if err := decodeArgs(args, &req); err != nil {
+				return nil, err
+			}
+			return analyzer.AnalyzeNamespace(ctx, req.Namespace, v1.NamespaceCapacityOptions{
+				IncludeTrending:       true,
+				IncludeInfrastructure: req.IncludeInfrastructure,
+				IncludePods:           req.IncludePods,
+				Window:                req.Window,
+				Resolution:            req.Resolution,
+			})
+		},
+	}
+}
+
+// remediationArgs are the arguments of execute_remediation_with_approval
+type remediationArgs struct {
+	v1.TriggerRemediationRequest
+	ApprovalID string `json:"approval_id"`
+	Reason     string `json:"reason"`
+}
+
+// ExecuteRemediationWithApprovalTool triggers remediation only after human approval.
+// The first call records the request and returns an approval ID; once an operator has
+// approved it through the REST API, calling again with the approval ID executes the
+// request exactly as it was approved.
+func ExecuteRemediationWithApprovalTool(trigger RemediationTrigger, approvals *ApprovalStore) *Tool {
+	return &Tool{
+		Name: ToolExecuteRemediationWithApproval,
+		Description: "Request a remediation workflow for a resource. Changes the cluster, so it requires human approval: " +
+			"call without approval_id to submit the request and receive an approval_id, ask the operator to approve it, " +
+			"then call again with only approval_id to execute the approved request.",
+		InputSchema: objectSchema(map[string]interface{}{
+			"approval_id": stringProperty("ID returned by a previous call; executes the approved request"),
+			"incident_id": stringProperty("Incident being remediated"),
+			"namespace":   stringProperty("Namespace of the resource"),
+			"resource": objectSchema(map[string]interface{}{
+				"kind": stringProperty("Resource kind, e.g. Deployment, Pod"),
+				"name": stringProperty("Resource name"),
+			}, "kind", "name"),
+			"issue": objectSchema(map[string]interface{}{
+				"type":        stringProperty("Issue type, e.g. CrashLoopBackOff, OOMKilled"),
+				"description": stringProperty("Issue description"),
+				"severity":    stringProperty("Issue severity"),
+			}, "type"),
+			"reason": stringProperty("Why this remediation is needed, shown to the approver"),
+		}),
+		Handler: func(ctx context.Context, args json.RawMessage) (interface{}, error) {
+			var req remediationArgs
+			if err := decodeArgs(args, &req); err != nil {
+				return nil, err
+			}
+
+			if req.ApprovalID == "" {
+				if err := req.Validate(); err != nil {
+					return nil, err
+				}
+				approval := approvals.Request(&req.TriggerRemediationRequest, req.Reason, Requester(ctx))
+				return map[string]interface{}{
+					"status":      ApprovalPending,
+					"approval_id": approval.ID,
+					"expires_at":  approval.ExpiresAt.Format(time.RFC3339),
+					"message": fmt.Sprintf("Remediation requires human approval. Ask an operator to approve it "+
+						"(POST /api/v1/mcp/approvals/%s/approve), then call %s again with approval_id.",
+						approval.ID, ToolExecuteRemediationWithApproval),
+				}, nil
+			}
+
+			approved, err := approvals.Claim(req.ApprovalID)
+			if err != nil {
+				return nil, err
+			}
+
+			// The operator's approval also covers exceeding the remediation budget
+			resp, err := trigger.Trigger(remediation.WithApprover(ctx, approved.DecidedBy), &approved.Request)
+			if err != nil {
+				approvals.RecordExecution(approved.ID, "", err)
+				return nil, err
+			}
+			approvals.RecordExecution(approved.ID, resp.WorkflowID, nil)
+			return resp, nil
+		},
+	}
+}
+
+// decodeArgs decodes tool arguments, rejecting unknown fields so a misspelled
+// argument fails loudly instead of being silently ignored
+func decodeArgs(args json.RawMessage, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(args))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+	return nil
+}
+
+func objectSchema(properties map[string]interface{}, required ...string) map[string]interface{} {
+	schema := map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func stringProperty(description string) map[string]interface{} {
+	return map[string]interface{}{"type": "string", "description": description}
+}
+
+func numberProperty(description string) map[string]interface{} {
+	return map[string]interface{}{"type": "number", "description": description}
+}
+
+func enumProperty(description string, values ...string) map[string]interface{} {
+	return map[string]interface{}{"type": "string", "description": description, "enum": values}
+}
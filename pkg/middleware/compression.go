@@ -0,0 +1,173 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultCompressionMinSize is the smallest response body, in bytes, that is gzip compressed.
+// Smaller payloads are sent as-is since compression overhead outweighs the savings.
+const DefaultCompressionMinSize = 1024
+
+// bufferedResponseWriter captures the status code and body of a response so that
+// middleware can inspect the full payload before anything is sent to the client.
+type bufferedResponseWriter struct {
+	header     http.Header
+	statusCode int
+	body       bytes.Buffer
+}
+
+func newBufferedResponseWriter() *bufferedResponseWriter {
+	return &bufferedResponseWriter{header: make(http.Header), statusCode: http.StatusOK}
+}
+
+func (bw *bufferedResponseWriter) Header() http.Header {
+	return bw.header
+}
+
+func (bw *bufferedResponseWriter) WriteHeader(code int) {
+	bw.statusCode = code
+}
+
+func (bw *bufferedResponseWriter) Write(b []byte) (int, error) {
+	return bw.body.Write(b)
+}
+
+// copyHeaders copies the captured headers to the real response writer
+func (bw *bufferedResponseWriter) copyHeaders(w http.ResponseWriter) {
+	for key, values := range bw.header {
+		w.Header()[key] = values
+	}
+}
+
+// Compress creates a middleware that gzip compresses responses for clients that
+// send "Accept-Encoding: gzip". Bodies smaller than minSize, and responses that
+// already carry a Content-Encoding, are passed through unchanged.
+func Compress(minSize int, log *logrus.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !acceptsGzip(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			bw := newBufferedResponseWriter()
+			next.ServeHTTP(bw, r)
+
+			bw.copyHeaders(w)
+			w.Header().Add("Vary", "Accept-Encoding")
+
+			body := bw.body.Bytes()
+			if len(body) < minSize || w.Header().Get("Content-Encoding") != "" || !bodyAllowed(bw.statusCode) {
+				w.WriteHeader(bw.statusCode)
+				if _, err := w.Write(body); err != nil {
+					log.WithError(err).Debug("Failed to write response")
+				}
+				return
+			}
+
+			var compressed bytes.Buffer
+			gz := gzip.NewWriter(&compressed)
+			if _, err := gz.Write(body); err != nil {
+				log.WithError(err).Error("Failed to gzip response")
+			}
+			if err := gz.Close(); err != nil {
+				log.WithError(err).Error("Failed to finalize gzip response")
+			}
+
+			w.Header().Set("Content-Encoding", "gzip")
+			w.Header().Set("Content-Length", strconv.Itoa(compressed.Len()))
+			w.WriteHeader(bw.statusCode)
+			if _, err := w.Write(compressed.Bytes()); err != nil {
+				log.WithError(err).Debug("Failed to write compressed response")
+			}
+		})
+	}
+}
+
+// ETag creates a middleware that adds a weak ETag to successful GET responses and
+// answers 304 Not Modified when the request's If-None-Match matches it. This lets
+// polling clients such as console plugins skip downloading unchanged payloads.
+func ETag(log *logrus.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet && r.Method != http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			bw := newBufferedResponseWriter()
+			next.ServeHTTP(bw, r)
+			bw.copyHeaders(w)
+
+			if bw.statusCode != http.StatusOK || w.Header().Get("ETag") != "" {
+				w.WriteHeader(bw.statusCode)
+				if _, err := w.Write(bw.body.Bytes()); err != nil {
+					log.WithError(err).Debug("Failed to write response")
+				}
+				return
+			}
+
+			etag := computeETag(bw.body.Bytes())
+			w.Header().Set("ETag", etag)
+
+			if etagMatches(r.Header.Get("If-None-Match"), etag) {
+				w.Header().Del("Content-Length")
+				w.Header().Del("Content-Type")
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+
+			w.WriteHeader(http.StatusOK)
+			if _, err := w.Write(bw.body.Bytes()); err != nil {
+				log.WithError(err).Debug("Failed to write response")
+			}
+		})
+	}
+}
+
+// computeETag returns a weak ETag for a body. Weak validators are used because the
+// same representation may be served with or without gzip content encoding.
+func computeETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `W/"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// etagMatches reports whether an If-None-Match header matches an ETag using weak comparison
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// acceptsGzip reports whether the client accepts gzip encoded responses
+func acceptsGzip(r *http.Request) bool {
+	for _, encoding := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(encoding), ";")
+		if strings.TrimSpace(name) != "gzip" {
+			continue
+		}
+		// "gzip;q=0" explicitly refuses gzip
+		return strings.ReplaceAll(strings.TrimSpace(params), " ", "") != "q=0"
+	}
+	return false
+}
+
+// bodyAllowed reports whether a response with the given status may carry a body
+func bodyAllowed(status int) bool {
+	return status >= 200 && status != http.StatusNoContent && status != http.StatusNotModified
+}
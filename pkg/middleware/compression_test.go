@@ -0,0 +1,136 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func jsonHandler(body string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(body))
+	})
+}
+
+func TestCompress_LargeResponse(t *testing.T) {
+	log := logrus.New()
+	body := `{"incidents":"` + strings.Repeat("a", 4096) + `"}`
+	handler := Compress(DefaultCompressionMinSize, log)(jsonHandler(body))
+
+	req := httptest.NewRequest("GET", "/api/v1/incidents", http.NoBody)
+	req.Header.Set("Accept-Encoding", "gzip, deflate")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "gzip", rr.Header().Get("Content-Encoding"))
+	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+	assert.Contains(t, rr.Header().Values("Vary"), "Accept-Encoding")
+	assert.Less(t, rr.Body.Len(), len(body))
+
+	gz, err := gzip.NewReader(rr.Body)
+	require.NoError(t, err)
+	decoded, err := io.ReadAll(gz)
+	require.NoError(t, err)
+	assert.Equal(t, body, string(decoded))
+}
+
+func TestCompress_SkipsSmallResponsesAndUnsupportedClients(t *testing.T) {
+	log := logrus.New()
+
+	t.Run("small body", func(t *testing.T) {
+		handler := Compress(DefaultCompressionMinSize, log)(jsonHandler(`{"status":"ok"}`))
+		req := httptest.NewRequest("GET", "/health", http.NoBody)
+		req.Header.Set("Accept-Encoding", "gzip")
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		assert.Empty(t, rr.Header().Get("Content-Encoding"))
+		assert.Equal(t, `{"status":"ok"}`, rr.Body.String())
+	})
+
+	t.Run("client without gzip", func(t *testing.T) {
+		body := strings.Repeat("a", 4096)
+		handler := Compress(DefaultCompressionMinSize, log)(jsonHandler(body))
+		req := httptest.NewRequest("GET", "/api/v1/incidents", http.NoBody)
+		req.Header.Set("Accept-Encoding", "gzip;q=0")
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		assert.Empty(t, rr.Header().Get("Content-Encoding"))
+		assert.Equal(t, body, rr.Body.String())
+	})
+}
+
+func TestETag_NotModified(t *testing.T) {
+	log := logrus.New()
+	handler := ETag(log)(jsonHandler(`{"nodes":3}`))
+
+	req := httptest.NewRequest("GET", "/api/v1/capacity/cluster", http.NoBody)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	etag := rr.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	assert.True(t, strings.HasPrefix(etag, `W/"`))
+	assert.Equal(t, `{"nodes":3}`, rr.Body.String())
+
+	req = httptest.NewRequest("GET", "/api/v1/capacity/cluster", http.NoBody)
+	req.Header.Set("If-None-Match", etag)
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotModified, rr.Code)
+	assert.Empty(t, rr.Body.String())
+	assert.Equal(t, etag, rr.Header().Get("ETag"))
+}
+
+func TestETag_ChangedPayload(t *testing.T) {
+	log := logrus.New()
+	first := httptest.NewRecorder()
+	ETag(log)(jsonHandler(`{"nodes":3}`)).ServeHTTP(first, httptest.NewRequest("GET", "/", http.NoBody))
+
+	req := httptest.NewRequest("GET", "/", http.NoBody)
+	req.Header.Set("If-None-Match", first.Header().Get("ETag"))
+	rr := httptest.NewRecorder()
+	ETag(log)(jsonHandler(`{"nodes":4}`)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.NotEqual(t, first.Header().Get("ETag"), rr.Header().Get("ETag"))
+	assert.Equal(t, `{"nodes":4}`, rr.Body.String())
+}
+
+func TestETag_IgnoresNonGetAndErrors(t *testing.T) {
+	log := logrus.New()
+
+	rr := httptest.NewRecorder()
+	ETag(log)(jsonHandler(`{}`)).ServeHTTP(rr, httptest.NewRequest("POST", "/api/v1/incidents", http.NoBody))
+	assert.Empty(t, rr.Header().Get("ETag"))
+
+	failing := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	rr = httptest.NewRecorder()
+	ETag(log)(failing).ServeHTTP(rr, httptest.NewRequest("GET", "/api/v1/incidents", http.NoBody))
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.Empty(t, rr.Header().Get("ETag"))
+}
+
+func TestETagMatches(t *testing.T) {
+	etag := `W/"abc"`
+	assert.True(t, etagMatches(`W/"abc"`, etag))
+	assert.True(t, etagMatches(`"abc"`, etag))
+	assert.True(t, etagMatches(`"x", W/"abc"`, etag))
+	assert.True(t, etagMatches(`*`, etag))
+	assert.False(t, etagMatches(``, etag))
+	assert.False(t, etagMatches(`W/"def"`, etag))
+}
@@ -22,7 +22,7 @@ func DefaultCORSConfig() *CORSConfig {
 		AllowedOrigins:   []string{"*"},
 		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
 		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-Request-ID"},
-		ExposedHeaders:   []string{"X-Request-ID"},
+		ExposedHeaders:   []string{"X-Request-ID", "X-Coordination-Mode"},
 		AllowCredentials: false,
 		MaxAge:           3600,
 	}
@@ -41,6 +41,11 @@ func (rw *responseWriter) Write(b []byte) (int, error) {
 	return n, nil
 }
 
+// Unwrap returns the wrapped writer for http.ResponseController
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
 // RequestLogger creates a middleware that logs HTTP requests
 func RequestLogger(log *logrus.Logger) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
@@ -0,0 +1,18 @@
+package middleware
+
+import "net/http"
+
+const (
+	// ModeHeader is the header name for the engine mode, e.g. "observer"
+	ModeHeader = "X-Coordination-Mode"
+)
+
+// Mode creates a middleware that marks every response with the engine mode
+func Mode(mode string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set(ModeHeader, mode)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
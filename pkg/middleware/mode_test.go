@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMode(t *testing.T) {
+	handler := Mode("observer")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("POST", "/api/v1/remediation/trigger", http.NoBody))
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.Equal(t, "observer", rr.Header().Get(ModeHeader))
+}
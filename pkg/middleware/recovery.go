@@ -8,31 +8,44 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-// Recovery creates a middleware that recovers from panics
+// Recovery creates a middleware that recovers from panics. The panic and its stack are
+// logged with the request ID; the client only gets a 500 with that ID to correlate
+// it. When the handler had already started its response, the connection is aborted
+// instead, since the status can no longer change.
 func Recovery(log *logrus.Logger) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tw := &trackingWriter{ResponseWriter: w}
 			defer func() {
-				if err := recover(); err != nil {
-					// Log the panic with stack trace
-					log.WithFields(logrus.Fields{
-						"error":      fmt.Sprintf("%v", err),
-						"stack":      string(debug.Stack()),
-						"method":     r.Method,
-						"path":       r.URL.Path,
-						"request_id": r.Header.Get(RequestIDHeader),
-					}).Error("Panic recovered in HTTP handler")
+				err := recover()
+				if err == nil {
+					return
+				}
+				if err == http.ErrAbortHandler {
+					panic(err)
+				}
+
+				requestID := requestIDOf(w, r)
+				log.WithFields(logrus.Fields{
+					"error":      fmt.Sprintf("%v", err),
+					"stack":      string(debug.Stack()),
+					"method":     r.Method,
+					"path":       r.URL.Path,
+					"request_id": requestID,
+				}).Error("Panic recovered in HTTP handler")
 
-					// Return 500 Internal Server Error
-					w.Header().Set("Content-Type", "application/json")
-					w.WriteHeader(http.StatusInternalServerError)
-					if _, err := w.Write([]byte(`{"error":"Internal server error","message":"An unexpected error occurred"}`)); err != nil {
-						log.WithError(err).Error("Failed to write panic recovery response")
-					}
+				if tw.wroteHeader {
+					panic(http.ErrAbortHandler)
 				}
+				writeJSONError(w, log, http.StatusInternalServerError, map[string]string{
+					"status":     "error",
+					"error":      "Internal server error",
+					"code":       "internal_error",
+					"request_id": requestID,
+				})
 			}()
 
-			next.ServeHTTP(w, r)
+			next.ServeHTTP(tw, r)
 		})
 	}
 }
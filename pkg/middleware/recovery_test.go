@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -50,3 +51,40 @@ func TestRecovery_WithPanic(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, rr.Code)
 	assert.Contains(t, rr.Body.String(), "Internal server error")
 }
+
+func TestRecovery_CorrelatesRequestID(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.PanicLevel)
+
+	handler := Recovery(log)(RequestLogger(log)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("secret detail")
+	})))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/test", http.NoBody))
+
+	var body map[string]string
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+	assert.Equal(t, "internal_error", body["code"])
+	assert.NotEmpty(t, body["request_id"])
+	assert.Equal(t, rr.Header().Get(RequestIDHeader), body["request_id"])
+	assert.NotContains(t, rr.Body.String(), "secret detail")
+	assert.NotContains(t, rr.Body.String(), "goroutine")
+}
+
+func TestRecovery_AbortsStartedResponse(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.PanicLevel)
+
+	handler := Recovery(log)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"partial":`))
+		panic("mid-stream")
+	}))
+
+	rr := httptest.NewRecorder()
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		handler.ServeHTTP(rr, httptest.NewRequest("GET", "/test", http.NoBody))
+	})
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
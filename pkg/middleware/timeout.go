@@ -0,0 +1,126 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// timeoutGrace is how long past its timeout a request may still write, so a handler
+// that gives up on its cancelled context can send its error response
+const timeoutGrace = 5 * time.Second
+
+// RouteTimeout sets the timeout of the routes under Prefix (and ending in Suffix, if
+// set). Prefixes are API paths without their /api/vN prefix, e.g. "/anomalies/analyze".
+type RouteTimeout struct {
+	Prefix, Suffix string
+	Timeout        time.Duration
+}
+
+// apiVersionPrefix matches the API version at the start of a path
+var apiVersionPrefix = regexp.MustCompile(`^/api/v[0-9]+`)
+
+// timeoutFor returns the timeout of the first route matching path, or fallback
+func timeoutFor(routes []RouteTimeout, path string, fallback time.Duration) time.Duration {
+	path = apiVersionPrefix.ReplaceAllString(path, "")
+	for _, route := range routes {
+		if (path == route.Prefix || strings.HasPrefix(path, route.Prefix+"/")) && strings.HasSuffix(path, route.Suffix) {
+			return route.Timeout
+		}
+	}
+	return fallback
+}
+
+// Timeout creates a middleware that bounds the time spent on each request: the
+// request context is cancelled after the route's timeout (fallback for unlisted
+// routes) and the connection's read and write deadlines are moved to shortly after
+// it, so a stuck handler or slow client cannot hold a connection forever. A handler
+// that returns after its deadline without responding gets a 504.
+func Timeout(fallback time.Duration, routes []RouteTimeout, log *logrus.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			timeout := timeoutFor(routes, r.URL.Path, fallback)
+			if timeout <= 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			deadline := time.Now().Add(timeout)
+			rc := http.NewResponseController(w)
+			if err := rc.SetWriteDeadline(deadline.Add(timeoutGrace)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+				log.WithError(err).Debug("Failed to set write deadline")
+			}
+			if err := rc.SetReadDeadline(deadline); err != nil && !errors.Is(err, http.ErrNotSupported) {
+				log.WithError(err).Debug("Failed to set read deadline")
+			}
+
+			ctx, cancel := context.WithDeadline(r.Context(), deadline)
+			defer cancel()
+
+			tw := &trackingWriter{ResponseWriter: w}
+			next.ServeHTTP(tw, r.WithContext(ctx))
+			if tw.wroteHeader || !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return
+			}
+
+			requestID := requestIDOf(w, r)
+			log.WithFields(logrus.Fields{
+				"method":     r.Method,
+				"path":       r.URL.Path,
+				"timeout":    timeout.String(),
+				"request_id": requestID,
+			}).Warn("Request timed out")
+			writeJSONError(w, log, http.StatusGatewayTimeout, map[string]string{
+				"status":     "error",
+				"error":      fmt.Sprintf("Request timed out after %s", timeout),
+				"code":       "timeout",
+				"request_id": requestID,
+			})
+		})
+	}
+}
+
+// trackingWriter records whether the response has been started
+type trackingWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+func (tw *trackingWriter) WriteHeader(code int) {
+	tw.wroteHeader = true
+	tw.ResponseWriter.WriteHeader(code)
+}
+
+func (tw *trackingWriter) Write(b []byte) (int, error) {
+	tw.wroteHeader = true
+	return tw.ResponseWriter.Write(b)
+}
+
+// Unwrap returns the wrapped writer for http.ResponseController
+func (tw *trackingWriter) Unwrap() http.ResponseWriter {
+	return tw.ResponseWriter
+}
+
+// requestIDOf returns the request ID set by RequestLogger, or the one the client sent
+func requestIDOf(w http.ResponseWriter, r *http.Request) string {
+	if id := w.Header().Get(RequestIDHeader); id != "" {
+		return id
+	}
+	return r.Header.Get(RequestIDHeader)
+}
+
+// writeJSONError writes an error response body
+func writeJSONError(w http.ResponseWriter, log *logrus.Logger, status int, body map[string]string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.WithError(err).Debug("Failed to write error response")
+	}
+}
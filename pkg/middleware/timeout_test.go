@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeoutFor(t *testing.T) {
+	routes := []RouteTimeout{
+		{Prefix: "/incidents", Suffix: "/summary", Timeout: time.Minute},
+		{Prefix: "/anomalies/analyze", Timeout: 2 * time.Minute},
+		{Prefix: "/stream", Timeout: 0},
+	}
+
+	assert.Equal(t, 2*time.Minute, timeoutFor(routes, "/api/v1/anomalies/analyze", time.Second))
+	assert.Equal(t, 2*time.Minute, timeoutFor(routes, "/api/v2/anomalies/analyze", time.Second))
+	assert.Equal(t, time.Minute, timeoutFor(routes, "/api/v1/incidents/inc-1/summary", time.Second))
+	assert.Equal(t, time.Second, timeoutFor(routes, "/api/v1/incidents/inc-1/similar", time.Second))
+	assert.Equal(t, time.Second, timeoutFor(routes, "/api/v1/anomalies/analyzer", time.Second))
+	assert.Zero(t, timeoutFor(routes, "/api/v1/stream", time.Second))
+}
+
+func TestTimeout_CancelsContext(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.PanicLevel)
+
+	handler := Timeout(20*time.Millisecond, nil, log)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+
+	req := httptest.NewRequest("POST", "/api/v1/anomalies/analyze", http.NoBody)
+	req.Header.Set(RequestIDHeader, "req-1")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusGatewayTimeout, rr.Code)
+	var body map[string]string
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+	assert.Equal(t, "timeout", body["code"])
+	assert.Equal(t, "req-1", body["request_id"])
+	assert.Equal(t, "Request timed out after 20ms", body["error"])
+}
+
+func TestTimeout_KeepsHandlerResponse(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.PanicLevel)
+
+	handler := Timeout(20*time.Millisecond, nil, log)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/api/v1/capacity/cluster", http.NoBody))
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+}
+
+func TestTimeout_RouteOverServer(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.PanicLevel)
+
+	// the route timeout applies behind RequestLogger, whose writer unwraps to the connection
+	handler := RequestLogger(log)(Timeout(time.Millisecond, []RouteTimeout{{Prefix: "/slow", Timeout: 50 * time.Millisecond}}, log)(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-r.Context().Done()
+			w.WriteHeader(http.StatusOK)
+		})))
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	start := time.Now()
+	resp, err := http.Get(server.URL + "/api/v1/slow")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Less(t, time.Since(start), timeoutGrace, "context is cancelled at the route timeout")
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/api/validation"
+)
+
+// ActionOutcome records whether a remediation action resolved an issue once the result
+// was verified
+type ActionOutcome struct {
+	ID        string `json:"id"`
+	IssueType string `json:"issue_type"`
+	Action    string `json:"action"`
+
+	// Success is true when verification showed the issue resolved after the action
+	Success bool `json:"success"`
+
+	Namespace        string `json:"namespace,omitempty"`
+	RecommendationID string `json:"recommendation_id,omitempty"`
+	IncidentID       string `json:"incident_id,omitempty"`
+
+	// Source is who verified the outcome, e.g. "operator" or "workflow"
+	Source string `json:"source,omitempty"`
+
+	VerifiedAt time.Time `json:"verified_at"`
+}
+
+// Validate checks the required fields of an action outcome
+func (o *ActionOutcome) Validate() error {
+	var errs validation.Errors
+	if o.IssueType == "" {
+		errs.Add("issue_type", validation.ConstraintRequired, nil, "issue_type is required")
+	} else if len(o.IssueType) > 100 {
+		errs.Add("issue_type", validation.ConstraintLength, len(o.IssueType), "issue_type must not exceed 100 characters")
+	}
+	if o.Action == "" {
+		errs.Add("action", validation.ConstraintRequired, nil, "action is required")
+	} else if len(o.Action) > 100 {
+		errs.Add("action", validation.ConstraintLength, len(o.Action), "action must not exceed 100 characters")
+	}
+	return errs.Err()
+}
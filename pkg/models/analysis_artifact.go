@@ -0,0 +1,41 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AnalysisArtifact records the inputs and model output of an anomaly analysis, so the
+// decision can be reproduced and debugged after the underlying metrics have expired
+type AnalysisArtifact struct {
+	ID         string `json:"id"`
+	IncidentID string `json:"incident_id,omitempty"`
+
+	// Request is the analysis request after defaults were applied
+	Request json.RawMessage `json:"request"`
+
+	// EvaluatedAt is the instant every PromQL query was evaluated at
+	EvaluatedAt time.Time `json:"evaluated_at"`
+
+	Model        string `json:"model"`
+	ModelVersion string `json:"model_version,omitempty"`
+
+	// FeatureNames and Features are the feature vector sent to the model, in model order
+	FeatureNames []string  `json:"feature_names"`
+	Features     []float64 `json:"features"`
+
+	// Queries are the PromQL queries run to build the features, in execution order
+	Queries []ArtifactQuery `json:"queries"`
+
+	// ModelResponse is the raw response body of the model
+	ModelResponse json.RawMessage `json:"model_response,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ArtifactQuery is a PromQL query run for an analysis
+type ArtifactQuery struct {
+	Kind  string `json:"kind"` // instant, range
+	Query string `json:"query"`
+	Error string `json:"error,omitempty"`
+}
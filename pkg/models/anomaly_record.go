@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// AnomalyRecord is a stored anomaly detected by an analysis, kept so operators can
+// review past anomalies, correlate them with incidents and export them to retrain
+// models
+type AnomalyRecord struct {
+	ID         string `json:"id"`
+	Namespace  string `json:"namespace,omitempty"`
+	Deployment string `json:"deployment,omitempty"`
+	Pod        string `json:"pod,omitempty"`
+
+	// Model and ModelVersion scored the analysis
+	Model        string `json:"model"`
+	ModelVersion string `json:"model_version,omitempty"`
+
+	// Severity is critical, warning or info
+	Severity          string             `json:"severity"`
+	Priority          int                `json:"priority"`
+	AnomalyScore      float64            `json:"anomaly_score"`
+	Confidence        float64            `json:"confidence"`
+	Metrics           map[string]float64 `json:"metrics,omitempty"`
+	Explanation       string             `json:"explanation"`
+	RecommendedAction string             `json:"recommended_action,omitempty"`
+
+	// AnalysisID names the analysis artifact, whose feature vector the anomaly was
+	// detected from, and IncidentID the incident raised from it
+	AnalysisID string `json:"analysis_id,omitempty"`
+	IncidentID string `json:"incident_id,omitempty"`
+
+	// DetectedAt is the evaluation time of the analysis
+	DetectedAt time.Time `json:"detected_at"`
+	CreatedAt  time.Time `json:"created_at"`
+}
@@ -0,0 +1,133 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ClusterOperator condition types reported by OpenShift
+const (
+	ClusterOperatorAvailable   = "Available"
+	ClusterOperatorDegraded    = "Degraded"
+	ClusterOperatorProgressing = "Progressing"
+)
+
+// ClusterOperatorCondition is a status condition of a ClusterOperator
+type ClusterOperatorCondition struct {
+	Type               string    `json:"type"`
+	Status             string    `json:"status"`
+	Reason             string    `json:"reason,omitempty"`
+	Message            string    `json:"message,omitempty"`
+	LastTransitionTime time.Time `json:"last_transition_time"`
+}
+
+// ClusterOperatorStatus is the Available/Degraded/Progressing state of a ClusterOperator
+type ClusterOperatorStatus struct {
+	Name        string                     `json:"name"`
+	Version     string                     `json:"version,omitempty"`
+	Available   bool                       `json:"available"`
+	Degraded    bool                       `json:"degraded"`
+	Progressing bool                       `json:"progressing"`
+	Conditions  []ClusterOperatorCondition `json:"conditions,omitempty"`
+}
+
+// IsHealthy returns true if the operator is available and not degraded.
+// Progressing alone is healthy: operators progress during every upgrade.
+func (s *ClusterOperatorStatus) IsHealthy() bool {
+	return s.Available && !s.Degraded
+}
+
+// Condition returns the condition of the given type, or nil if it is not reported
+func (s *ClusterOperatorStatus) Condition(condType string) *ClusterOperatorCondition {
+	for i := range s.Conditions {
+		if s.Conditions[i].Type == condType {
+			return &s.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// Evidence describes why the operator is unhealthy, e.g.
+// "ClusterOperator network: Degraded=True (RolloutHung: DaemonSet is not making progress)".
+// Returns an empty string for healthy operators.
+func (s *ClusterOperatorStatus) Evidence() string {
+	var problems []string
+	if s.Degraded {
+		problems = append(problems, s.describeCondition(ClusterOperatorDegraded, "True"))
+	}
+	if !s.Available {
+		problems = append(problems, s.describeCondition(ClusterOperatorAvailable, "False"))
+	}
+	if len(problems) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("ClusterOperator %s: %s", s.Name, strings.Join(problems, "; "))
+}
+
+// describeCondition formats a condition as "Type=Status (Reason: Message)"
+func (s *ClusterOperatorStatus) describeCondition(condType, status string) string {
+	cond := s.Condition(condType)
+	if cond == nil {
+		return fmt.Sprintf("%s condition not reported", condType)
+	}
+
+	text := fmt.Sprintf("%s=%s", condType, status)
+	switch {
+	case cond.Reason != "" && cond.Message != "":
+		text += fmt.Sprintf(" (%s: %s)", cond.Reason, cond.Message)
+	case cond.Reason != "":
+		text += fmt.Sprintf(" (%s)", cond.Reason)
+	case cond.Message != "":
+		text += fmt.Sprintf(" (%s)", cond.Message)
+	}
+	return text
+}
+
+// ClusterOperatorSummary aggregates ClusterOperator health for the platform layer
+type ClusterOperatorSummary struct {
+	Total       int                     `json:"total"`
+	Healthy     int                     `json:"healthy"`
+	Degraded    []string                `json:"degraded"`
+	Unavailable []string                `json:"unavailable"`
+	Progressing []string                `json:"progressing"`
+	Unhealthy   []ClusterOperatorStatus `json:"unhealthy,omitempty"`
+}
+
+// SummarizeClusterOperators aggregates operator statuses. Name lists are sorted.
+func SummarizeClusterOperators(operators []ClusterOperatorStatus) *ClusterOperatorSummary {
+	summary := &ClusterOperatorSummary{
+		Total:       len(operators),
+		Degraded:    []string{},
+		Unavailable: []string{},
+		Progressing: []string{},
+	}
+
+	for i := range operators {
+		op := &operators[i]
+		if op.Degraded {
+			summary.Degraded = append(summary.Degraded, op.Name)
+		}
+		if !op.Available {
+			summary.Unavailable = append(summary.Unavailable, op.Name)
+		}
+		if op.Progressing {
+			summary.Progressing = append(summary.Progressing, op.Name)
+		}
+		if op.IsHealthy() {
+			summary.Healthy++
+		} else {
+			summary.Unhealthy = append(summary.Unhealthy, *op)
+		}
+	}
+
+	sort.Strings(summary.Degraded)
+	sort.Strings(summary.Unavailable)
+	sort.Strings(summary.Progressing)
+	sort.Slice(summary.Unhealthy, func(i, j int) bool {
+		return summary.Unhealthy[i].Name < summary.Unhealthy[j].Name
+	})
+
+	return summary
+}
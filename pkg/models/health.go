@@ -33,6 +33,10 @@ type DependencyHealth struct {
 	Message   string          `json:"message,omitempty"`
 	Latency   *int64          `json:"latency_ms,omitempty"` // Response time in milliseconds
 	CheckedAt time.Time       `json:"checked_at"`
+
+	// StartupMode is how engine startup treats the dependency while it is unavailable:
+	// wait, degraded or fail
+	StartupMode string `json:"startup_mode,omitempty"`
 }
 
 // RBACStatus represents RBAC permission check status
@@ -50,6 +54,7 @@ type HealthResponse struct {
 	Status       HealthStatus                `json:"status"`
 	Timestamp    time.Time                   `json:"timestamp"`
 	Version      string                      `json:"version"`
+	Mode         string                      `json:"mode,omitempty"`
 	Uptime       int64                       `json:"uptime_seconds"`
 	Dependencies map[string]DependencyHealth `json:"dependencies"`
 	RBAC         RBACStatus                  `json:"rbac"`
@@ -2,7 +2,11 @@ package models
 
 import (
 	"fmt"
+	"regexp"
+	"sort"
 	"time"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/api/validation"
 )
 
 // IncidentStatus represents the current state of an incident
@@ -26,20 +30,59 @@ const (
 	IncidentSeverityCritical IncidentSeverity = "critical"
 )
 
+// IncidentEventType identifies an entry in an incident's timeline
+type IncidentEventType string
+
+// Incident event type constants
+const (
+	IncidentEventResolved     IncidentEventType = "resolved"
+	IncidentEventAutoResolved IncidentEventType = "auto_resolved"
+	IncidentEventAggregated   IncidentEventType = "alert_aggregated"
+	IncidentEventEscalated    IncidentEventType = "escalated"
+)
+
+// IncidentEvent records a state change in an incident's timeline
+type IncidentEvent struct {
+	Type      IncidentEventType `json:"type"`
+	Message   string            `json:"message"`
+	Timestamp time.Time         `json:"timestamp"`
+}
+
 // Incident represents a manually or automatically created incident for tracking
 type Incident struct {
 	ID                string            `json:"id"`
 	Title             string            `json:"title"`
 	Description       string            `json:"description"`
 	Severity          IncidentSeverity  `json:"severity"`
+	Priority          int               `json:"priority,omitempty"` // 0-100, from the severity matrix
 	Target            string            `json:"target"`
 	Status            IncidentStatus    `json:"status"`
 	AffectedResources []string          `json:"affected_resources,omitempty"`
 	Labels            map[string]string `json:"labels,omitempty"`
+	Tags              map[string]string `json:"tags,omitempty"` // e.g. team, service, cost_center
+	Owner             *Owner            `json:"owner,omitempty"`
 	CreatedAt         time.Time         `json:"created_at"`
 	UpdatedAt         time.Time         `json:"updated_at"`
 	ResolvedAt        *time.Time        `json:"resolved_at,omitempty"`
 	WorkflowID        string            `json:"workflow_id,omitempty"`
+	Events            []IncidentEvent   `json:"events,omitempty"`
+	Summary           string            `json:"summary,omitempty"`
+	SummarizedAt      *time.Time        `json:"summarized_at,omitempty"`
+}
+
+// Limits on incident tags
+const (
+	MaxIncidentTags        = 32
+	MaxIncidentTagValueLen = 256
+)
+
+// tagKeyPattern is the format of tag keys, e.g. "team" or "cost-center"
+var tagKeyPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.\-/]{0,62}$`)
+
+// IsValidTagKey checks if a tag key is 1-63 letters, digits, '_', '.', '-' or '/',
+// starting with a letter or digit
+func IsValidTagKey(key string) bool {
+	return tagKeyPattern.MatchString(key)
 }
 
 // ValidSeverities returns all valid severity values
@@ -62,33 +105,55 @@ func IsValidSeverity(severity string) bool {
 	return false
 }
 
+// Rank orders severities from low (1) to critical (4); unknown severities rank 0
+func (s IncidentSeverity) Rank() int {
+	for i, severity := range ValidSeverities() {
+		if severity == s {
+			return i + 1
+		}
+	}
+	return 0
+}
+
 // Validate checks if the incident is valid
 func (i *Incident) Validate() error {
+	var errs validation.Errors
 	if i.Title == "" {
-		return fmt.Errorf("title is required")
-	}
-	if len(i.Title) > 200 {
-		return fmt.Errorf("title must not exceed 200 characters")
+		errs.Add("title", validation.ConstraintRequired, nil, "title is required")
+	} else if len(i.Title) > 200 {
+		errs.Add("title", validation.ConstraintLength, len(i.Title), "title must not exceed 200 characters")
 	}
 	if i.Description == "" {
-		return fmt.Errorf("description is required")
-	}
-	if len(i.Description) > 2000 {
-		return fmt.Errorf("description must not exceed 2000 characters")
+		errs.Add("description", validation.ConstraintRequired, nil, "description is required")
+	} else if len(i.Description) > 2000 {
+		errs.Add("description", validation.ConstraintLength, len(i.Description), "description must not exceed 2000 characters")
 	}
 	if i.Severity == "" {
-		return fmt.Errorf("severity is required")
-	}
-	if !IsValidSeverity(string(i.Severity)) {
-		return fmt.Errorf("severity must be one of: low, medium, high, critical")
+		errs.Add("severity", validation.ConstraintRequired, nil, "severity is required")
+	} else if !IsValidSeverity(string(i.Severity)) {
+		errs.Add("severity", validation.ConstraintEnum, string(i.Severity), "severity must be one of: low, medium, high, critical")
 	}
 	if i.Target == "" {
-		return fmt.Errorf("target is required")
+		errs.Add("target", validation.ConstraintRequired, nil, "target is required")
+	} else if len(i.Target) > 100 {
+		errs.Add("target", validation.ConstraintLength, len(i.Target), "target must not exceed 100 characters")
+	}
+	if len(i.Tags) > MaxIncidentTags {
+		errs.Add("tags", validation.ConstraintLength, len(i.Tags), fmt.Sprintf("tags must not exceed %d entries", MaxIncidentTags))
 	}
-	if len(i.Target) > 100 {
-		return fmt.Errorf("target must not exceed 100 characters")
+	keys := make([]string, 0, len(i.Tags))
+	for key := range i.Tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if value := i.Tags[key]; !IsValidTagKey(key) {
+			errs.Add("tags", validation.ConstraintFormat, key, fmt.Sprintf("tag key %q must be 1-63 letters, digits, '_', '.', '-' or '/'", key))
+		} else if len(value) > MaxIncidentTagValueLen {
+			errs.Add("tags."+key, validation.ConstraintLength, len(value), fmt.Sprintf("tag %s must not exceed %d characters", key, MaxIncidentTagValueLen))
+		}
 	}
-	return nil
+	return errs.Err()
 }
 
 // String returns a human-readable representation
@@ -101,6 +166,15 @@ func (i *Incident) String() string {
 	)
 }
 
+// EffectivePriority returns the incident's priority, or one derived from its severity
+// (low 25 to critical 100) if it was stored without a priority
+func (i *Incident) EffectivePriority() int {
+	if i.Priority > 0 {
+		return i.Priority
+	}
+	return i.Severity.Rank() * 100 / len(ValidSeverities())
+}
+
 // IsActive returns true if the incident is currently active
 func (i *Incident) IsActive() bool {
 	return i.Status == IncidentStatusActive
@@ -114,6 +188,22 @@ func (i *Incident) Resolve() {
 	i.UpdatedAt = now
 }
 
+// AutoResolve marks the incident as resolved because the underlying condition cleared,
+// recording the reason in the incident timeline
+func (i *Incident) AutoResolve(message string) {
+	i.Resolve()
+	i.AddEvent(IncidentEventAutoResolved, message)
+}
+
+// AddEvent appends an event to the incident timeline
+func (i *Incident) AddEvent(eventType IncidentEventType, message string) {
+	i.Events = append(i.Events, IncidentEvent{
+		Type:      eventType,
+		Message:   message,
+		Timestamp: time.Now(),
+	})
+}
+
 // Cancel marks the incident as cancelled
 func (i *Incident) Cancel() {
 	i.Status = IncidentStatusCancelled
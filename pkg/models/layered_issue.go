@@ -75,6 +75,9 @@ type LayeredIssue struct {
 	DetectedAt        time.Time            `json:"detected_at"`
 	Severity          string               `json:"severity"` // critical, high, medium, low
 
+	// Evidence explains why a layer is affected, e.g. the condition of a degraded ClusterOperator
+	Evidence map[Layer][]string `json:"evidence,omitempty"`
+
 	// ML-enhanced fields (Phase 6)
 	MLPredictions     *MLLayerPredictions `json:"ml_predictions,omitempty"`
 	LayerConfidence   map[Layer]float64   `json:"layer_confidence,omitempty"`
@@ -151,6 +154,24 @@ func (li *LayeredIssue) AddImpactedResource(layer Layer, resource Resource) {
 	li.ImpactedResources[layer] = append(li.ImpactedResources[layer], resource)
 }
 
+// HasImpactedResource returns true if a resource of the given kind and name is impacted in a layer
+func (li *LayeredIssue) HasImpactedResource(layer Layer, kind, name string) bool {
+	for _, r := range li.ImpactedResources[layer] {
+		if r.Kind == kind && r.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// AddEvidence records why a layer is affected
+func (li *LayeredIssue) AddEvidence(layer Layer, evidence string) {
+	if li.Evidence == nil {
+		li.Evidence = make(map[Layer][]string)
+	}
+	li.Evidence[layer] = append(li.Evidence[layer], evidence)
+}
+
 // GetResourcesForLayer returns all impacted resources for a specific layer
 func (li *LayeredIssue) GetResourcesForLayer(layer Layer) []Resource {
 	if li.ImpactedResources == nil {
@@ -0,0 +1,18 @@
+package models
+
+// Owner is the team responsible for a workload or namespace and how to reach its
+// on-call rotation
+type Owner struct {
+	Team         string `json:"team,omitempty"`
+	SlackChannel string `json:"slack_channel,omitempty"`
+	Email        string `json:"email,omitempty"`
+
+	// Source is the most specific object the owner was read from, e.g.
+	// "deployment/api" or "namespace/shop"
+	Source string `json:"source,omitempty"`
+}
+
+// IsZero reports whether no way of reaching the owner is known
+func (o *Owner) IsZero() bool {
+	return o == nil || (o.Team == "" && o.SlackChannel == "" && o.Email == "")
+}
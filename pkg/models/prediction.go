@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+// PredictionRecord is a stored resource usage prediction. Once its target time has
+// passed, the usage actually observed and the prediction error are filled in.
+type PredictionRecord struct {
+	ID           string `json:"id"`
+	Model        string `json:"model"`
+	ModelVersion string `json:"model_version,omitempty"`
+	Scope        string `json:"scope"`
+	Target       string `json:"target"`
+	Namespace    string `json:"namespace,omitempty"`
+	Deployment   string `json:"deployment,omitempty"`
+	Pod          string `json:"pod,omitempty"`
+
+	// TargetTime is the time the prediction is for
+	TargetTime time.Time `json:"target_time"`
+
+	// Predicted usage in percent of cluster allocatable
+	PredictedCPU    float64 `json:"predicted_cpu"`
+	PredictedMemory float64 `json:"predicted_memory"`
+	Confidence      float64 `json:"confidence"`
+
+	CreatedAt time.Time `json:"created_at"`
+
+	// Observed usage in percent, set once the target time has passed
+	ActualCPU    *float64   `json:"actual_cpu,omitempty"`
+	ActualMemory *float64   `json:"actual_memory,omitempty"`
+	EvaluatedAt  *time.Time `json:"evaluated_at,omitempty"`
+}
+
+// Evaluated reports whether the actual usage has been recorded
+func (p *PredictionRecord) Evaluated() bool {
+	return p.EvaluatedAt != nil
+}
+
+// CPUError returns the signed CPU prediction error (predicted - actual) in percentage points
+func (p *PredictionRecord) CPUError() float64 {
+	if p.ActualCPU == nil {
+		return 0
+	}
+	return p.PredictedCPU - *p.ActualCPU
+}
+
+// MemoryError returns the signed memory prediction error (predicted - actual) in percentage points
+func (p *PredictionRecord) MemoryError() float64 {
+	if p.ActualMemory == nil {
+		return 0
+	}
+	return p.PredictedMemory - *p.ActualMemory
+}
@@ -0,0 +1,121 @@
+package models
+
+import (
+	"fmt"
+
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/api/validation"
+)
+
+// ScopeLevel is how much of the cluster a request targets
+type ScopeLevel string
+
+// Scope levels, from the whole cluster down to a single pod
+const (
+	ScopeCluster    ScopeLevel = "cluster"
+	ScopeNamespace  ScopeLevel = "namespace"
+	ScopeDeployment ScopeLevel = "deployment"
+	ScopePod        ScopeLevel = "pod"
+)
+
+// ScopeLevels lists the valid scope levels, narrowest first
+var ScopeLevels = []ScopeLevel{ScopePod, ScopeDeployment, ScopeNamespace, ScopeCluster}
+
+// Scope is the part of the cluster a request targets: a pod, a deployment, a namespace or
+// the whole cluster. Every endpoint and metric query taking a scope uses this type, so
+// they infer, validate and describe it the same way.
+type Scope struct {
+	Namespace  string `json:"namespace,omitempty"`
+	Deployment string `json:"deployment,omitempty"`
+	Pod        string `json:"pod,omitempty"`
+
+	// Level is the explicit scope level; empty infers the narrowest level the fields
+	// identify
+	Level ScopeLevel `json:"scope,omitempty"`
+}
+
+// InferScopeLevel returns the narrowest scope level identified by the fields
+func InferScopeLevel(namespace, deployment, pod string) ScopeLevel {
+	switch {
+	case pod != "":
+		return ScopePod
+	case deployment != "":
+		return ScopeDeployment
+	case namespace != "":
+		return ScopeNamespace
+	default:
+		return ScopeCluster
+	}
+}
+
+// level returns the explicit level, or the inferred one
+func (s Scope) level() ScopeLevel {
+	if s.Level == "" {
+		return InferScopeLevel(s.Namespace, s.Deployment, s.Pod)
+	}
+	return s.Level
+}
+
+// Rules returns the validation rules of the scope. An explicit level must be one of
+// ScopeLevels. The pod and deployment levels need the workload name and its namespace.
+// A namespace level without a namespace falls back to the cluster, so it is not
+// rejected. Names must be valid Kubernetes names, since they end up in PromQL label
+// matchers.
+func (s Scope) Rules() []validation.Rule {
+	level := s.level()
+	if err := validation.OneOf("scope", level, ScopeLevels...)(); err != nil {
+		return []validation.Rule{func() *validation.FieldError { return err }}
+	}
+
+	reason := fmt.Sprintf("scope is '%s'", level)
+	return []validation.Rule{
+		validation.RequiredWhen(level == ScopePod, reason, "pod", s.Pod),
+		validation.RequiredWhen(level == ScopeDeployment, reason, "deployment", s.Deployment),
+		validation.RequiredWhen(level == ScopePod || level == ScopeDeployment, reason, "namespace", s.Namespace),
+		validation.Name("namespace", s.Namespace, k8svalidation.IsDNS1123Label),
+		validation.Name("deployment", s.Deployment, k8svalidation.IsDNS1123Subdomain),
+		validation.Name("pod", s.Pod, k8svalidation.IsDNS1123Subdomain),
+	}
+}
+
+// Validate checks the scope; see Rules
+func (s Scope) Validate() error {
+	return validation.Check(s.Rules()...)
+}
+
+// Resolve returns the scope with its level set and the fields narrower than the level
+// cleared, so a request for namespace "apps" with deployment "api" and scope "namespace"
+// covers the whole namespace. A namespace level without a namespace resolves to the
+// cluster. The scope should be valid.
+func (s Scope) Resolve() Scope {
+	s.Level = s.level()
+	if s.Level == ScopeNamespace && s.Namespace == "" {
+		s.Level = ScopeCluster
+	}
+
+	switch s.Level {
+	case ScopeCluster:
+		s.Namespace, s.Deployment, s.Pod = "", "", ""
+	case ScopeNamespace:
+		s.Deployment, s.Pod = "", ""
+	case ScopeDeployment:
+		s.Pod = ""
+	}
+	return s
+}
+
+// Description describes the scope for people, e.g. "deployment 'api' in namespace 'apps'"
+func (s Scope) Description() string {
+	switch s.level() {
+	case ScopePod:
+		return fmt.Sprintf("pod '%s' in namespace '%s'", s.Pod, s.Namespace)
+	case ScopeDeployment:
+		return fmt.Sprintf("deployment '%s' in namespace '%s'", s.Deployment, s.Namespace)
+	case ScopeNamespace:
+		if s.Namespace != "" {
+			return fmt.Sprintf("namespace '%s'", s.Namespace)
+		}
+	}
+	return "cluster-wide"
+}
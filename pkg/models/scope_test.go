@@ -0,0 +1,61 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tosin2013/openshift-coordination-engine/pkg/api/validation"
+)
+
+func TestScope_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		scope  Scope
+		fields []string
+	}{
+		{name: "cluster", scope: Scope{Level: ScopeCluster}},
+		{name: "inferred cluster"},
+		{name: "namespace scope falls back to cluster", scope: Scope{Level: ScopeNamespace}},
+		{name: "deployment", scope: Scope{Level: ScopeDeployment, Namespace: "apps", Deployment: "api"}},
+		{name: "unknown scope", scope: Scope{Level: "node"}, fields: []string{"scope"}},
+		{name: "pod scope without fields", scope: Scope{Level: ScopePod}, fields: []string{"pod", "namespace"}},
+		{name: "deployment scope without name", scope: Scope{Level: ScopeDeployment, Namespace: "apps"}, fields: []string{"deployment"}},
+		{name: "inferred pod scope without namespace", scope: Scope{Pod: "api-0"}, fields: []string{"namespace"}},
+		{name: "invalid names", scope: Scope{Namespace: "Apps", Deployment: `a"}) or vector(1)`, Pod: "api_0"}, fields: []string{"namespace", "deployment", "pod"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fields []string
+			for _, fieldErr := range validation.Fields(tt.scope.Validate()) {
+				fields = append(fields, fieldErr.Field)
+			}
+			assert.Equal(t, tt.fields, fields)
+		})
+	}
+}
+
+func TestInferScopeLevel(t *testing.T) {
+	assert.Equal(t, ScopePod, InferScopeLevel("apps", "api", "api-0"))
+	assert.Equal(t, ScopeDeployment, InferScopeLevel("apps", "api", ""))
+	assert.Equal(t, ScopeNamespace, InferScopeLevel("apps", "", ""))
+	assert.Equal(t, ScopeCluster, InferScopeLevel("", "", ""))
+}
+
+func TestScope_Resolve(t *testing.T) {
+	assert.Equal(t, Scope{Namespace: "apps", Deployment: "api", Pod: "api-0", Level: ScopePod},
+		Scope{Namespace: "apps", Deployment: "api", Pod: "api-0"}.Resolve())
+	assert.Equal(t, Scope{Namespace: "apps", Level: ScopeNamespace},
+		Scope{Namespace: "apps", Deployment: "api", Level: ScopeNamespace}.Resolve())
+	assert.Equal(t, Scope{Level: ScopeCluster}, Scope{Level: ScopeNamespace}.Resolve())
+	assert.Equal(t, Scope{Level: ScopeCluster}, Scope{Namespace: "apps", Level: ScopeCluster}.Resolve())
+}
+
+func TestScope_Description(t *testing.T) {
+	assert.Equal(t, "pod 'api-0' in namespace 'apps'", Scope{Namespace: "apps", Pod: "api-0"}.Description())
+	assert.Equal(t, "deployment 'api' in namespace 'apps'", Scope{Namespace: "apps", Deployment: "api"}.Description())
+	assert.Equal(t, "namespace 'apps'", Scope{Namespace: "apps"}.Description())
+	assert.Equal(t, "cluster-wide", Scope{}.Description())
+	assert.Equal(t, "cluster-wide", Scope{Level: ScopeNamespace}.Description())
+}
@@ -31,15 +31,35 @@ type Workflow struct {
 	Steps            []WorkflowStep `json:"steps,omitempty"`
 }
 
+// Workflow step status constants
+const (
+	StepStatusPending            = "pending"
+	StepStatusRunning            = "running"
+	StepStatusRetrying           = "retrying"
+	StepStatusCompleted          = "completed"
+	StepStatusFailed             = "failed"
+	StepStatusSkipped            = "skipped"
+	StepStatusCompensated        = "compensated"
+	StepStatusCompensationFailed = "compensation_failed"
+)
+
 // WorkflowStep represents a single step in the workflow
 type WorkflowStep struct {
 	Order        int        `json:"order"`
+	Name         string     `json:"name,omitempty"`
 	Layer        string     `json:"layer,omitempty"` // "infrastructure", "platform", "application"
 	Description  string     `json:"description"`
-	Status       string     `json:"status"` // "pending", "running", "completed", "failed"
+	Status       string     `json:"status"` // one of the StepStatus constants
+	Attempts     int        `json:"attempts,omitempty"`
 	StartedAt    *time.Time `json:"started_at,omitempty"`
 	CompletedAt  *time.Time `json:"completed_at,omitempty"`
 	ErrorMessage string     `json:"error_message,omitempty"`
+
+	// NextAttemptAt is when a failed step is retried
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"`
+
+	// Reason explains why a step was skipped or not compensated
+	Reason string `json:"reason,omitempty"`
 }
 
 // Duration returns the workflow execution duration
@@ -61,7 +81,7 @@ func (w *Workflow) AddStep(description string) *WorkflowStep {
 	step := WorkflowStep{
 		Order:       len(w.Steps),
 		Description: description,
-		Status:      "pending",
+		Status:      StepStatusPending,
 	}
 	w.Steps = append(w.Steps, step)
 	return &w.Steps[len(w.Steps)-1]
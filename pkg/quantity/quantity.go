@@ -0,0 +1,137 @@
+// Package quantity provides typed resource quantities for API responses and strict
+// conversions from Prometheus sample values into them.
+//
+// Prometheus encodes every sample as a decimal string holding a float64. Converting
+// such values to integers with a plain int64() cast truncates (1073741823.9999 bytes
+// becomes 1073741823) and turns NaN or infinite samples into arbitrary numbers, so
+// conversions here round to the nearest unit and fail on non-finite values instead.
+package quantity
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ErrNotFinite is returned when a NaN or infinite value is converted to a quantity
+var ErrNotFinite = errors.New("value is not finite")
+
+// ErrOutOfRange is returned when a value does not fit the quantity's integer type
+var ErrOutOfRange = errors.New("value out of range")
+
+// ParseFloat parses a sample value as Prometheus encodes it: a decimal or exponent
+// number, or NaN, +Inf or -Inf. Unlike fmt.Sscanf it rejects trailing garbage such as
+// "12abc" and empty strings.
+func ParseFloat(s string) (float64, error) {
+	trimmed := strings.TrimSpace(s)
+	switch trimmed {
+	case "NaN":
+		return math.NaN(), nil
+	case "+Inf", "Inf":
+		return math.Inf(1), nil
+	case "-Inf":
+		return math.Inf(-1), nil
+	}
+	value, err := strconv.ParseFloat(trimmed, 64)
+	if err != nil {
+		var numErr *strconv.NumError
+		if errors.As(err, &numErr) && errors.Is(numErr.Err, strconv.ErrRange) {
+			return value, nil // ±Inf for values beyond float64, as Prometheus does
+		}
+		return 0, fmt.Errorf("invalid sample value %q", s)
+	}
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		// strconv also accepts spellings Prometheus never emits, e.g. "nan" and "infinity"
+		return 0, fmt.Errorf("invalid sample value %q", s)
+	}
+	return value, nil
+}
+
+// Bytes is an amount of memory or storage in bytes
+type Bytes int64
+
+// Binary byte multiples
+const (
+	KiB Bytes = 1 << (10 * (iota + 1))
+	MiB
+	GiB
+	TiB
+)
+
+// BytesFromFloat rounds a sample value to whole bytes
+func BytesFromFloat(value float64) (Bytes, error) {
+	n, err := roundToInt64(value)
+	return Bytes(n), err
+}
+
+// Float64 returns the byte count as a float for ratios
+func (b Bytes) Float64() float64 {
+	return float64(b)
+}
+
+// String formats the byte count with a binary suffix, e.g. 1.5Gi
+func (b Bytes) String() string {
+	switch {
+	case b >= TiB:
+		return fmt.Sprintf("%.1fTi", float64(b)/float64(TiB))
+	case b >= GiB:
+		return fmt.Sprintf("%.1fGi", float64(b)/float64(GiB))
+	case b >= MiB:
+		return fmt.Sprintf("%.1fMi", float64(b)/float64(MiB))
+	case b >= KiB:
+		return fmt.Sprintf("%.1fKi", float64(b)/float64(KiB))
+	default:
+		return strconv.FormatInt(int64(b), 10)
+	}
+}
+
+// Cores is an amount of CPU in cores. Fractions are kept; Millicores rounds.
+type Cores float64
+
+// CoresFromFloat checks that a sample value is a finite number of cores
+func CoresFromFloat(value float64) (Cores, error) {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, ErrNotFinite
+	}
+	return Cores(value), nil
+}
+
+// Float64 returns the cores as a float
+func (c Cores) Float64() float64 {
+	return float64(c)
+}
+
+// Millicores rounds the cores to whole millicores
+func (c Cores) Millicores() int64 {
+	return int64(math.Round(float64(c) * 1000))
+}
+
+// String formats the cores in millicores, e.g. 1500m
+func (c Cores) String() string {
+	return strconv.FormatInt(c.Millicores(), 10) + "m"
+}
+
+// Count is a number of objects, such as pods or etcd keys
+type Count int64
+
+// CountFromFloat rounds a sample value to a whole count
+func CountFromFloat(value float64) (Count, error) {
+	n, err := roundToInt64(value)
+	return Count(n), err
+}
+
+// roundToInt64 rounds value to the nearest integer, failing on NaN, infinities and
+// values beyond the int64 range
+func roundToInt64(value float64) (int64, error) {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, ErrNotFinite
+	}
+	rounded := math.Round(value)
+	// float64(math.MaxInt64) rounds up to 2^63, which no longer fits
+	if rounded >= math.MaxInt64 || rounded < math.MinInt64 {
+		return 0, ErrOutOfRange
+	}
+	return int64(rounded), nil
+}
@@ -0,0 +1,79 @@
+package quantity
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFloat(t *testing.T) {
+	valid := map[string]float64{
+		"0":                    0,
+		"1.5":                  1.5,
+		" 42 ":                 42,
+		"-3":                   -3,
+		"1e9":                  1e9,
+		"1073741824":           1073741824,
+		"9007199254740993":     9007199254740992, // nearest float64
+		"0.000000000000000001": 1e-18,
+		"+Inf":                 math.Inf(1),
+		"-Inf":                 math.Inf(-1),
+		"1e400":                math.Inf(1),
+	}
+	for input, want := range valid {
+		got, err := ParseFloat(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, want, got, input)
+	}
+
+	nan, err := ParseFloat("NaN")
+	require.NoError(t, err)
+	assert.True(t, math.IsNaN(nan))
+
+	for _, input := range []string{"", " ", "12abc", "1.5.2", "0x", "nan", "infinity", "1,5", "[1]"} {
+		_, err := ParseFloat(input)
+		assert.Error(t, err, "%q must be rejected", input)
+	}
+}
+
+func TestBytes(t *testing.T) {
+	b, err := BytesFromFloat(1073741823.9999)
+	require.NoError(t, err)
+	assert.Equal(t, GiB, b, "values are rounded, not truncated")
+	assert.Equal(t, "1.0Gi", b.String())
+	assert.Equal(t, "1.5Mi", (MiB + MiB/2).String())
+	assert.Equal(t, "2.0Ti", (2 * TiB).String())
+	assert.Equal(t, "512", Bytes(512).String())
+
+	_, err = BytesFromFloat(math.NaN())
+	assert.ErrorIs(t, err, ErrNotFinite)
+	_, err = BytesFromFloat(math.Inf(1))
+	assert.ErrorIs(t, err, ErrNotFinite)
+	_, err = BytesFromFloat(1e19)
+	assert.ErrorIs(t, err, ErrOutOfRange)
+	_, err = BytesFromFloat(math.MaxInt64)
+	assert.ErrorIs(t, err, ErrOutOfRange)
+}
+
+func TestCores(t *testing.T) {
+	c, err := CoresFromFloat(1.2345)
+	require.NoError(t, err)
+	assert.Equal(t, 1.2345, c.Float64(), "fractions are kept")
+	assert.Equal(t, int64(1235), c.Millicores())
+	assert.Equal(t, "1235m", c.String())
+	assert.Equal(t, "300m", Cores(0.29999999).String(), "millicores are rounded, not truncated")
+
+	_, err = CoresFromFloat(math.NaN())
+	assert.ErrorIs(t, err, ErrNotFinite)
+}
+
+func TestCount(t *testing.T) {
+	n, err := CountFromFloat(41999.9999)
+	require.NoError(t, err)
+	assert.Equal(t, Count(42000), n)
+
+	_, err = CountFromFloat(math.Inf(-1))
+	assert.ErrorIs(t, err, ErrNotFinite)
+}
@@ -0,0 +1,122 @@
+package runbook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ConfluenceProvider searches pages in a Confluence wiki using CQL
+type ConfluenceProvider struct {
+	baseURL    string
+	space      string
+	token      string
+	httpClient *http.Client
+	log        *logrus.Logger
+}
+
+// NewConfluenceProvider creates a Confluence search provider. space optionally limits
+// results to one space key; token is sent as a bearer token (personal access token).
+func NewConfluenceProvider(baseURL, space, token string, timeout time.Duration, log *logrus.Logger) *ConfluenceProvider {
+	return &ConfluenceProvider{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		space:   space,
+		token:   token,
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+		log: log,
+	}
+}
+
+// HTTPClient returns the client used to reach the wiki, e.g. to audit its TLS configuration
+func (p *ConfluenceProvider) HTTPClient() *http.Client {
+	return p.httpClient
+}
+
+// Name returns the provider name
+func (p *ConfluenceProvider) Name() string {
+	return "confluence"
+}
+
+// confluenceSearchResponse is the subset of /rest/api/content/search used by the provider
+type confluenceSearchResponse struct {
+	Results []struct {
+		ID    string `json:"id"`
+		Title string `json:"title"`
+		Links struct {
+			WebUI string `json:"webui"`
+		} `json:"_links"`
+	} `json:"results"`
+	Links struct {
+		Base string `json:"base"`
+	} `json:"_links"`
+}
+
+// Search finds pages whose text matches the query
+func (p *ConfluenceProvider) Search(ctx context.Context, query string, limit int) ([]Runbook, error) {
+	cql := fmt.Sprintf("type=page AND text ~ %s", strconv.Quote(query))
+	if p.space != "" {
+		cql += fmt.Sprintf(" AND space=%s", strconv.Quote(p.space))
+	}
+
+	params := url.Values{}
+	params.Set("cql", cql)
+	params.Set("limit", strconv.Itoa(limit))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+"/rest/api/content/search?"+params.Encode(), http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if p.token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.token)
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			p.log.WithError(closeErr).Warn("Failed to close response body")
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		body, readErr := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		if readErr != nil {
+			return nil, fmt.Errorf("unexpected status %d, failed to read body: %w", resp.StatusCode, readErr)
+		}
+		return nil, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var result confluenceSearchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	base := result.Links.Base
+	if base == "" {
+		base = p.baseURL
+	}
+
+	runbooks := make([]Runbook, 0, len(result.Results))
+	for _, page := range result.Results {
+		runbooks = append(runbooks, Runbook{
+			ID:     "confluence-" + page.ID,
+			Title:  page.Title,
+			URL:    base + page.Links.WebUI,
+			Source: p.Name(),
+		})
+	}
+	return runbooks, nil
+}
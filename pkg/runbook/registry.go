@@ -0,0 +1,219 @@
+// Package runbook links issue types and alert names to operator runbooks.
+//
+// A Registry holds runbooks loaded from a YAML or JSON file. Each runbook points to a URL,
+// embeds Markdown content, or both, and lists the issue types and alert names it covers.
+// Recommendations and incidents carry the runbooks that match them. An optional Provider
+// adds full-text search through an existing wiki.
+package runbook
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"sigs.k8s.io/yaml"
+)
+
+// SourceRegistry marks runbooks that come from the registry file
+const SourceRegistry = "registry"
+
+// DefaultSearchLimit is the number of results returned when no limit is given
+const DefaultSearchLimit = 10
+
+// Runbook is an operator procedure for an issue type or alert
+type Runbook struct {
+	ID         string   `json:"id"`
+	Title      string   `json:"title"`
+	URL        string   `json:"url,omitempty"`
+	Content    string   `json:"content,omitempty"` // Embedded Markdown
+	IssueTypes []string `json:"issue_types,omitempty"`
+	AlertNames []string `json:"alert_names,omitempty"`
+	Source     string   `json:"source,omitempty"`
+}
+
+// Validate checks that the runbook can be served and matched
+func (r *Runbook) Validate() error {
+	if r.ID == "" {
+		return fmt.Errorf("id is required")
+	}
+	if r.Title == "" {
+		return fmt.Errorf("runbook %s: title is required", r.ID)
+	}
+	if r.URL == "" && r.Content == "" {
+		return fmt.Errorf("runbook %s: url or content is required", r.ID)
+	}
+	if r.URL != "" && !strings.HasPrefix(r.URL, "http://") && !strings.HasPrefix(r.URL, "https://") {
+		return fmt.Errorf("runbook %s: url must start with http:// or https://", r.ID)
+	}
+	if len(r.IssueTypes) == 0 && len(r.AlertNames) == 0 {
+		return fmt.Errorf("runbook %s: at least one issue type or alert name is required", r.ID)
+	}
+	return nil
+}
+
+// Provider searches an external knowledge base such as a wiki
+type Provider interface {
+	// Name identifies the provider in the Source field of its results
+	Name() string
+	// Search returns up to limit runbooks matching the query
+	Search(ctx context.Context, query string, limit int) ([]Runbook, error)
+}
+
+// Registry maps issue types and alert names to runbooks. Matching is case-insensitive.
+type Registry struct {
+	runbooks []Runbook
+	byIssue  map[string][]int
+	byAlert  map[string][]int
+	provider Provider
+	log      *logrus.Logger
+}
+
+// NewRegistry creates a registry from the given runbooks, rejecting invalid or duplicate entries
+func NewRegistry(runbooks []Runbook, log *logrus.Logger) (*Registry, error) {
+	r := &Registry{
+		runbooks: make([]Runbook, 0, len(runbooks)),
+		byIssue:  make(map[string][]int),
+		byAlert:  make(map[string][]int),
+		log:      log,
+	}
+
+	seen := make(map[string]bool, len(runbooks))
+	for i := range runbooks {
+		rb := runbooks[i]
+		if err := rb.Validate(); err != nil {
+			return nil, err
+		}
+		if seen[rb.ID] {
+			return nil, fmt.Errorf("duplicate runbook id: %s", rb.ID)
+		}
+		seen[rb.ID] = true
+
+		rb.Source = SourceRegistry
+		idx := len(r.runbooks)
+		r.runbooks = append(r.runbooks, rb)
+		for _, issueType := range rb.IssueTypes {
+			key := normalize(issueType)
+			r.byIssue[key] = append(r.byIssue[key], idx)
+		}
+		for _, alert := range rb.AlertNames {
+			key := normalize(alert)
+			r.byAlert[key] = append(r.byAlert[key], idx)
+		}
+	}
+
+	return r, nil
+}
+
+// registryFile is the on-disk format of a runbook registry
+type registryFile struct {
+	Runbooks []Runbook `json:"runbooks"`
+}
+
+// LoadFile reads runbooks from a YAML or JSON file of the form {"runbooks": [...]}
+func LoadFile(path string) ([]Runbook, error) {
+	data, err := os.ReadFile(path) //#nosec G304 -- path comes from operator configuration
+	if err != nil {
+		return nil, fmt.Errorf("failed to read runbook file: %w", err)
+	}
+
+	var file registryFile
+	if err := yaml.UnmarshalStrict(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse runbook file %s: %w", path, err)
+	}
+	return file.Runbooks, nil
+}
+
+// SetProvider enables search through an external knowledge base
+func (r *Registry) SetProvider(p Provider) {
+	r.provider = p
+}
+
+// List returns all registered runbooks
+func (r *Registry) List() []Runbook {
+	if r == nil {
+		return []Runbook{}
+	}
+	results := make([]Runbook, len(r.runbooks))
+	copy(results, r.runbooks)
+	return results
+}
+
+// Lookup returns the runbooks for an issue type and/or alert name, without duplicates.
+// It is safe to call on a nil registry.
+func (r *Registry) Lookup(issueType, alertName string) []Runbook {
+	if r == nil {
+		return nil
+	}
+
+	var indexes []int
+	if issueType != "" {
+		indexes = append(indexes, r.byIssue[normalize(issueType)]...)
+	}
+	if alertName != "" {
+		indexes = append(indexes, r.byAlert[normalize(alertName)]...)
+	}
+	if len(indexes) == 0 {
+		return nil
+	}
+
+	sort.Ints(indexes)
+	results := make([]Runbook, 0, len(indexes))
+	for i, idx := range indexes {
+		if i > 0 && indexes[i-1] == idx {
+			continue
+		}
+		results = append(results, r.runbooks[idx])
+	}
+	return results
+}
+
+// Search returns registry runbooks whose title, issue types or alert names contain the
+// query, followed by results from the provider if one is configured. When the provider
+// fails, the registry results are returned together with the error.
+func (r *Registry) Search(ctx context.Context, query string, limit int) ([]Runbook, error) {
+	if limit <= 0 {
+		limit = DefaultSearchLimit
+	}
+
+	needle := normalize(query)
+	results := make([]Runbook, 0)
+	for i := range r.runbooks {
+		if len(results) >= limit {
+			return results, nil
+		}
+		if r.runbooks[i].matches(needle) {
+			results = append(results, r.runbooks[i])
+		}
+	}
+
+	if r.provider == nil || len(results) >= limit {
+		return results, nil
+	}
+
+	external, err := r.provider.Search(ctx, query, limit-len(results))
+	if err != nil {
+		r.log.WithError(err).WithField("provider", r.provider.Name()).Warn("Runbook provider search failed")
+		return results, fmt.Errorf("%s search failed: %w", r.provider.Name(), err)
+	}
+	return append(results, external...), nil
+}
+
+// matches reports whether a normalized query appears in the runbook's title or keys
+func (r *Runbook) matches(needle string) bool {
+	if needle == "" || strings.Contains(normalize(r.Title), needle) {
+		return true
+	}
+	for _, key := range append(append([]string{}, r.IssueTypes...), r.AlertNames...) {
+		if strings.Contains(normalize(key), needle) {
+			return true
+		}
+	}
+	return false
+}
+
+func normalize(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
@@ -0,0 +1,167 @@
+package runbook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testLogger() *logrus.Logger {
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	return log
+}
+
+var testRunbooks = []Runbook{
+	{
+		ID:         "crashloop",
+		Title:      "Pod crash looping",
+		URL:        "https://wiki.example.com/runbooks/crashloop",
+		IssueTypes: []string{"pod_crash_loop", "CrashLoopBackOff"},
+		AlertNames: []string{"KubePodCrashLooping"},
+	},
+	{
+		ID:         "memory",
+		Title:      "Memory pressure",
+		Content:    "## Memory pressure\n\n1. Check `oc adm top pods`",
+		IssueTypes: []string{"memory_pressure", "OOMKilled"},
+	},
+}
+
+// fakeProvider returns fixed results or an error
+type fakeProvider struct {
+	results []Runbook
+	err     error
+	limit   int
+}
+
+func (f *fakeProvider) Name() string { return "fake" }
+
+func (f *fakeProvider) Search(_ context.Context, _ string, limit int) ([]Runbook, error) {
+	f.limit = limit
+	return f.results, f.err
+}
+
+func TestRegistry_Lookup(t *testing.T) {
+	registry, err := NewRegistry(testRunbooks, testLogger())
+	require.NoError(t, err)
+
+	results := registry.Lookup("crashloopbackoff", "KubePodCrashLooping")
+	require.Len(t, results, 1, "a runbook matching both keys is returned once")
+	assert.Equal(t, "crashloop", results[0].ID)
+	assert.Equal(t, SourceRegistry, results[0].Source)
+
+	results = registry.Lookup("OOMKilled", "")
+	require.Len(t, results, 1)
+	assert.Contains(t, results[0].Content, "oc adm top pods")
+
+	assert.Empty(t, registry.Lookup("disk_pressure", ""))
+
+	var nilRegistry *Registry
+	assert.Empty(t, nilRegistry.Lookup("pod_crash_loop", ""))
+	assert.Empty(t, nilRegistry.List())
+}
+
+func TestNewRegistry_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		runbooks []Runbook
+		wantErr  string
+	}{
+		{name: "missing id", runbooks: []Runbook{{Title: "x"}}, wantErr: "id is required"},
+		{name: "missing target", runbooks: []Runbook{{ID: "a", Title: "x", IssueTypes: []string{"y"}}}, wantErr: "url or content is required"},
+		{name: "bad url", runbooks: []Runbook{{ID: "a", Title: "x", URL: "wiki/page", IssueTypes: []string{"y"}}}, wantErr: "url must start with"},
+		{name: "no keys", runbooks: []Runbook{{ID: "a", Title: "x", URL: "https://w"}}, wantErr: "at least one issue type or alert name"},
+		{name: "duplicate", runbooks: []Runbook{testRunbooks[0], testRunbooks[0]}, wantErr: "duplicate runbook id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewRegistry(tt.runbooks, testLogger())
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "runbooks.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+runbooks:
+  - id: crashloop
+    title: Pod crash looping
+    url: https://wiki.example.com/runbooks/crashloop
+    issue_types: [pod_crash_loop]
+    alert_names: [KubePodCrashLooping]
+`), 0o600))
+
+	runbooks, err := LoadFile(path)
+	require.NoError(t, err)
+	require.Len(t, runbooks, 1)
+	assert.Equal(t, []string{"KubePodCrashLooping"}, runbooks[0].AlertNames)
+
+	require.NoError(t, os.WriteFile(path, []byte("runbooks:\n  - id: a\n    titel: typo\n"), 0o600))
+	_, err = LoadFile(path)
+	assert.Error(t, err, "unknown fields are rejected")
+}
+
+func TestRegistry_Search(t *testing.T) {
+	registry, err := NewRegistry(testRunbooks, testLogger())
+	require.NoError(t, err)
+
+	results, err := registry.Search(context.Background(), "memory", 0)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "memory", results[0].ID)
+
+	provider := &fakeProvider{results: []Runbook{{ID: "confluence-1", Title: "Memory tuning", Source: "fake"}}}
+	registry.SetProvider(provider)
+
+	results, err = registry.Search(context.Background(), "memory", 5)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, "confluence-1", results[1].ID)
+	assert.Equal(t, 4, provider.limit)
+
+	provider.err = errors.New("unauthorized")
+	results, err = registry.Search(context.Background(), "memory", 5)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "fake search failed")
+	assert.Len(t, results, 1, "registry results are kept when the provider fails")
+}
+
+func TestConfluenceProvider_Search(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/rest/api/content/search", r.URL.Path)
+		assert.Equal(t, `type=page AND text ~ "OOMKilled" AND space="OPS"`, r.URL.Query().Get("cql"))
+		assert.Equal(t, "3", r.URL.Query().Get("limit"))
+		assert.Equal(t, "Bearer pat", r.Header.Get("Authorization"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"results": [{"id": "42", "title": "OOMKilled pods", "_links": {"webui": "/spaces/OPS/pages/42"}}],
+			"_links": {"base": "https://wiki.example.com"}
+		}`))
+	}))
+	defer server.Close()
+
+	provider := NewConfluenceProvider(server.URL+"/", "OPS", "pat", 5*time.Second, testLogger())
+	results, err := provider.Search(context.Background(), "OOMKilled", 3)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, Runbook{
+		ID:     "confluence-42",
+		Title:  "OOMKilled pods",
+		URL:    "https://wiki.example.com/spaces/OPS/pages/42",
+		Source: "confluence",
+	}, results[0])
+}
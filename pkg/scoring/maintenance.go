@@ -0,0 +1,46 @@
+package scoring
+
+import (
+	"context"
+	"fmt"
+	"path"
+)
+
+// MaintenanceHookName is the name of the built-in maintenance hook
+const MaintenanceHookName = "maintenance"
+
+// Maintenance is a post-inference hook that suppresses the anomalies of namespaces in
+// maintenance. Cluster-wide analyses are never suppressed.
+type Maintenance struct {
+	patterns []string
+}
+
+// NewMaintenance creates a maintenance hook for namespace name patterns, e.g.
+// "payments-*" (see path.Match)
+func NewMaintenance(patterns []string) (*Maintenance, error) {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid maintenance namespace pattern %q: %w", pattern, err)
+		}
+	}
+	return &Maintenance{patterns: append([]string(nil), patterns...)}, nil
+}
+
+// Name implements Hook
+func (m *Maintenance) Name() string {
+	return MaintenanceHookName
+}
+
+// AfterInference implements PostInference
+func (m *Maintenance) AfterInference(_ context.Context, input *Input, result *Result) error {
+	if !result.Anomalous || input.Namespace == "" {
+		return nil
+	}
+	for _, pattern := range m.patterns {
+		if matched, _ := path.Match(pattern, input.Namespace); matched {
+			result.Suppressed = fmt.Sprintf("namespace %s is in maintenance", input.Namespace)
+			return nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,215 @@
+// Package scoring runs site-specific hooks before and after the model inference of an
+// anomaly analysis, so logic such as normalizing features against baselines or
+// suppressing the scores of namespaces in maintenance does not require changing the
+// analysis handlers.
+//
+// A hook implements PreInference, PostInference or both. Hooks built into a custom
+// binary register themselves by name from an init function and are enabled with
+// ANOMALY_SCORING_HOOKS:
+//
+//	func init() {
+//		scoring.Register(baselineHook{})
+//	}
+//
+// Hooks run in the order they are enabled; an error from any hook fails the analysis.
+package scoring
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Input is what the model is asked to score. Pre-inference hooks may change the
+// features and metrics in place, but not the number of features.
+type Input struct {
+	Namespace  string
+	Deployment string
+	Pod        string
+	Model      string
+
+	// At is the instant the features were evaluated at
+	At time.Time
+
+	// FeatureNames and Features are the model input, in model order
+	FeatureNames []string
+	Features     []float64
+
+	// Metrics are the current values of the base metrics, from which the score of an
+	// anomalous prediction is derived
+	Metrics map[string]float64
+}
+
+// Result is the verdict derived from the model prediction. Post-inference hooks may
+// change it.
+type Result struct {
+	// Anomalous reports whether the model labelled the input anomalous
+	Anomalous bool
+
+	// Score is the anomaly score, between 0 and 1
+	Score float64
+
+	// Suppressed, when set by a hook, is why the result is not reported as an anomaly
+	Suppressed string
+
+	// SuppressedBy is the name of the hook that suppressed the result, set by the pipeline
+	SuppressedBy string
+}
+
+// Hook is a named scoring hook
+type Hook interface {
+	Name() string
+}
+
+// PreInference is a hook run on the features before they are sent to the model
+type PreInference interface {
+	Hook
+	BeforeInference(ctx context.Context, input *Input) error
+}
+
+// PostInference is a hook run on the result of the model prediction
+type PostInference interface {
+	Hook
+	AfterInference(ctx context.Context, input *Input, result *Result) error
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Hook)
+)
+
+// Register makes a hook available to Lookup under its name. It panics if the hook
+// implements neither PreInference nor PostInference or its name is taken, as those are
+// programming errors of the binary.
+func Register(hook Hook) {
+	if err := checkHook(hook); err != nil {
+		panic(err)
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, exists := registry[hook.Name()]; exists {
+		panic(fmt.Sprintf("scoring: hook %q registered twice", hook.Name()))
+	}
+	registry[hook.Name()] = hook
+}
+
+// Lookup returns the registered hooks with the given names, in order
+func Lookup(names []string) ([]Hook, error) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	hooks := make([]Hook, 0, len(names))
+	for _, name := range names {
+		hook, ok := registry[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown scoring hook %q (registered: %v)", name, registeredNames())
+		}
+		hooks = append(hooks, hook)
+	}
+	return hooks, nil
+}
+
+// registeredNames returns the names of the registered hooks, sorted. Callers must hold
+// registryMu.
+func registeredNames() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// checkHook verifies that a hook can be run
+func checkHook(hook Hook) error {
+	if hook == nil || hook.Name() == "" {
+		return fmt.Errorf("scoring: hook must have a name")
+	}
+	_, pre := hook.(PreInference)
+	_, post := hook.(PostInference)
+	if !pre && !post {
+		return fmt.Errorf("scoring: hook %q implements neither PreInference nor PostInference", hook.Name())
+	}
+	return nil
+}
+
+// Pipeline runs hooks around model inference. All methods are safe to call on a nil
+// *Pipeline, which runs no hooks.
+type Pipeline struct {
+	names []string
+	pre   []PreInference
+	post  []PostInference
+}
+
+// NewPipeline creates a pipeline running hooks in order
+func NewPipeline(hooks ...Hook) (*Pipeline, error) {
+	p := &Pipeline{}
+	for _, hook := range hooks {
+		if err := checkHook(hook); err != nil {
+			return nil, err
+		}
+		p.names = append(p.names, hook.Name())
+		if pre, ok := hook.(PreInference); ok {
+			p.pre = append(p.pre, pre)
+		}
+		if post, ok := hook.(PostInference); ok {
+			p.post = append(p.post, post)
+		}
+	}
+	return p, nil
+}
+
+// Names returns the names of the pipeline's hooks, in order
+func (p *Pipeline) Names() []string {
+	if p == nil {
+		return nil
+	}
+	return append([]string(nil), p.names...)
+}
+
+// Before runs the pre-inference hooks. It fails if a hook errs, changes the number of
+// features or leaves a feature that is not finite.
+func (p *Pipeline) Before(ctx context.Context, input *Input) error {
+	if p == nil {
+		return nil
+	}
+	for _, hook := range p.pre {
+		count := len(input.Features)
+		if err := hook.BeforeInference(ctx, input); err != nil {
+			return fmt.Errorf("scoring hook %s: %w", hook.Name(), err)
+		}
+		if len(input.Features) != count {
+			return fmt.Errorf("scoring hook %s changed the feature count from %d to %d", hook.Name(), count, len(input.Features))
+		}
+		for i, value := range input.Features {
+			if math.IsNaN(value) || math.IsInf(value, 0) {
+				return fmt.Errorf("scoring hook %s set feature %d to %v", hook.Name(), i, value)
+			}
+		}
+	}
+	return nil
+}
+
+// After runs the post-inference hooks. It fails if a hook errs or leaves a score
+// outside 0-1, and records the first hook that suppresses the result.
+func (p *Pipeline) After(ctx context.Context, input *Input, result *Result) error {
+	if p == nil {
+		return nil
+	}
+	for _, hook := range p.post {
+		if err := hook.AfterInference(ctx, input, result); err != nil {
+			return fmt.Errorf("scoring hook %s: %w", hook.Name(), err)
+		}
+		if math.IsNaN(result.Score) || result.Score < 0 || result.Score > 1 {
+			return fmt.Errorf("scoring hook %s set score %v outside 0-1", hook.Name(), result.Score)
+		}
+		if result.Suppressed != "" && result.SuppressedBy == "" {
+			result.SuppressedBy = hook.Name()
+		}
+	}
+	return nil
+}
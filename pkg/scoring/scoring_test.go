@@ -0,0 +1,139 @@
+package scoring
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scaleHook divides every feature by a baseline
+type scaleHook struct {
+	baseline float64
+	err      error
+}
+
+func (h scaleHook) Name() string { return "scale" }
+
+func (h scaleHook) BeforeInference(_ context.Context, input *Input) error {
+	if h.err != nil {
+		return h.err
+	}
+	for i := range input.Features {
+		input.Features[i] /= h.baseline
+	}
+	return nil
+}
+
+// dropHook removes the last feature
+type dropHook struct{}
+
+func (dropHook) Name() string { return "drop" }
+
+func (dropHook) BeforeInference(_ context.Context, input *Input) error {
+	input.Features = input.Features[:len(input.Features)-1]
+	return nil
+}
+
+// boostHook adds to the score
+type boostHook struct{ by float64 }
+
+func (h boostHook) Name() string { return "boost" }
+
+func (h boostHook) AfterInference(_ context.Context, _ *Input, result *Result) error {
+	result.Score += h.by
+	return nil
+}
+
+// namedOnly implements neither hook interface
+type namedOnly struct{}
+
+func (namedOnly) Name() string { return "named" }
+
+func TestPipeline(t *testing.T) {
+	ctx := context.Background()
+	maintenance, err := NewMaintenance([]string{"payments-*"})
+	require.NoError(t, err)
+
+	t.Run("hooks run in order around inference", func(t *testing.T) {
+		pipeline, err := NewPipeline(scaleHook{baseline: 2}, boostHook{by: 0.1}, maintenance)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"scale", "boost", "maintenance"}, pipeline.Names())
+
+		input := &Input{Namespace: "payments-eu", Features: []float64{1, 4}}
+		require.NoError(t, pipeline.Before(ctx, input))
+		assert.Equal(t, []float64{0.5, 2}, input.Features)
+
+		result := &Result{Anomalous: true, Score: 0.6}
+		require.NoError(t, pipeline.After(ctx, input, result))
+		assert.InDelta(t, 0.7, result.Score, 1e-9)
+		assert.Equal(t, "namespace payments-eu is in maintenance", result.Suppressed)
+		assert.Equal(t, MaintenanceHookName, result.SuppressedBy)
+	})
+
+	t.Run("maintenance leaves other scopes alone", func(t *testing.T) {
+		pipeline, err := NewPipeline(maintenance)
+		require.NoError(t, err)
+		for _, namespace := range []string{"orders", ""} {
+			result := &Result{Anomalous: true, Score: 0.9}
+			require.NoError(t, pipeline.After(ctx, &Input{Namespace: namespace}, result))
+			assert.Empty(t, result.Suppressed)
+		}
+	})
+
+	t.Run("invalid hook output fails", func(t *testing.T) {
+		pipeline, err := NewPipeline(dropHook{})
+		require.NoError(t, err)
+		assert.ErrorContains(t, pipeline.Before(ctx, &Input{Features: []float64{1, 2}}), "changed the feature count from 2 to 1")
+
+		pipeline, err = NewPipeline(scaleHook{baseline: 0})
+		require.NoError(t, err)
+		assert.ErrorContains(t, pipeline.Before(ctx, &Input{Features: []float64{1}}), "set feature 0 to +Inf")
+
+		failing := errors.New("baseline unavailable")
+		pipeline, err = NewPipeline(scaleHook{baseline: 1, err: failing})
+		require.NoError(t, err)
+		assert.ErrorIs(t, pipeline.Before(ctx, &Input{Features: []float64{1}}), failing)
+
+		pipeline, err = NewPipeline(boostHook{by: 0.5})
+		require.NoError(t, err)
+		assert.ErrorContains(t, pipeline.After(ctx, &Input{}, &Result{Score: 0.8}), "outside 0-1")
+	})
+
+	t.Run("nil pipeline runs nothing", func(t *testing.T) {
+		var pipeline *Pipeline
+		input := &Input{Features: []float64{1}}
+		require.NoError(t, pipeline.Before(ctx, input))
+		require.NoError(t, pipeline.After(ctx, input, &Result{}))
+		assert.Nil(t, pipeline.Names())
+	})
+
+	t.Run("hooks must implement a stage", func(t *testing.T) {
+		_, err := NewPipeline(namedOnly{})
+		assert.ErrorContains(t, err, "implements neither")
+		_, err = NewMaintenance([]string{"[payments"})
+		assert.Error(t, err)
+	})
+}
+
+func TestRegistry(t *testing.T) {
+	Register(boostHook{by: 0.1})
+	defer func() {
+		registryMu.Lock()
+		delete(registry, "boost")
+		registryMu.Unlock()
+	}()
+
+	hooks, err := Lookup([]string{"boost"})
+	require.NoError(t, err)
+	require.Len(t, hooks, 1)
+	assert.Equal(t, "boost", hooks[0].Name())
+
+	_, err = Lookup([]string{"baseline"})
+	assert.ErrorContains(t, err, `unknown scoring hook "baseline" (registered: [boost])`)
+
+	assert.Panics(t, func() { Register(boostHook{}) })
+	assert.Panics(t, func() { Register(namedOnly{}) })
+}
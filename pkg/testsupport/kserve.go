@@ -0,0 +1,196 @@
+package testsupport
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/loadtest"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/kserve"
+)
+
+// kserveDomain is the domain of the predictor URLs answered by the fake KServe
+const kserveDomain = ".kserve.testsupport"
+
+// ModelURL returns the predictor URL of a model served by the fake KServe, e.g. for
+// the versions of a kserve.ModelRollout
+func ModelURL(model string) string {
+	return "http://" + model + kserveDomain
+}
+
+// ModelCall is a request answered by the fake KServe
+type ModelCall struct {
+	// Model is the predictor that answered: the model name, or the name given to
+	// ModelURL for rollout versions
+	Model string
+
+	// Instances are the instances of a prediction, nil for health checks
+	Instances [][]float64
+
+	// Health is true for health checks
+	Health bool
+}
+
+// PredictFunc computes the predictions of a model for its instances
+type PredictFunc func(instances [][]float64) (interface{}, error)
+
+// KServe is an in-memory fake of KServe v1 predictors. It answers the predictions and
+// health checks of a kserve.ProxyClient from scripted responses and records every
+// request.
+//
+// Unscripted models answer like the load test predictors: instances of the
+// predictive-analytics width get a CPU and memory forecast, all others are predicted
+// normal (1).
+type KServe struct {
+	mu       sync.Mutex
+	handlers map[string]PredictFunc
+	failures map[string]int
+	calls    []ModelCall
+}
+
+// NewKServe creates a fake KServe with default predictions for every model
+func NewKServe() *KServe {
+	return &KServe{
+		handlers: make(map[string]PredictFunc),
+		failures: make(map[string]int),
+	}
+}
+
+// Client returns a ProxyClient with the named models registered at the fake. Models
+// of KSERVE_<MODEL>_SERVICE variables are registered as well but not answered.
+func (k *KServe) Client(log *logrus.Logger, models ...string) (*kserve.ProxyClient, error) {
+	urls := make(map[string]string, len(models))
+	for _, model := range models {
+		urls[model] = ModelURL(model)
+	}
+	client, err := kserve.NewProxyClient(kserve.ProxyConfig{
+		Namespace: "testsupport",
+		Timeout:   5 * time.Second,
+		Models:    urls,
+	}, log)
+	if err != nil {
+		return nil, err
+	}
+	client.HTTPClient().Transport = k
+	return client, nil
+}
+
+// SetPredictions answers every prediction of a model with the same predictions, e.g.
+// []int{-1} to flag an anomaly
+func (k *KServe) SetPredictions(model string, predictions interface{}) {
+	k.SetHandler(model, func([][]float64) (interface{}, error) { return predictions, nil })
+}
+
+// SetHandler computes the predictions of a model. An error answers with status 500.
+func (k *KServe) SetHandler(model string, fn PredictFunc) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.handlers[model] = fn
+	delete(k.failures, model)
+}
+
+// Fail answers the predictions and health checks of a model with an HTTP error status
+func (k *KServe) Fail(model string, status int) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.failures[model] = status
+}
+
+// Calls returns the requests answered so far, oldest first
+func (k *KServe) Calls() []ModelCall {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return append([]ModelCall(nil), k.calls...)
+}
+
+// Predictions returns the number of predictions a model answered
+func (k *KServe) Predictions(model string) int {
+	n := 0
+	for _, call := range k.Calls() {
+		if call.Model == model && !call.Health {
+			n++
+		}
+	}
+	return n
+}
+
+// Reset forgets the recorded requests; scripted responses are kept
+func (k *KServe) Reset() {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.calls = nil
+}
+
+// RoundTrip answers a KServe v1 request
+func (k *KServe) RoundTrip(req *http.Request) (*http.Response, error) {
+	model, ok := strings.CutSuffix(req.URL.Hostname(), kserveDomain)
+	if !ok {
+		return nil, fmt.Errorf("no fake predictor at %s", req.URL.Host)
+	}
+
+	call := ModelCall{Model: model}
+	switch {
+	case req.Method == http.MethodGet && req.URL.Path == "/v1/models/model":
+		call.Health = true
+	case req.Method == http.MethodPost && req.URL.Path == "/v1/models/model:predict":
+		var body struct {
+			Instances [][]float64 `json:"instances"`
+		}
+		err := json.NewDecoder(req.Body).Decode(&body)
+		_ = req.Body.Close()
+		if err != nil {
+			return jsonResponse(req, http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("invalid request: %v", err)}), nil
+		}
+		call.Instances = body.Instances
+	default:
+		return jsonResponse(req, http.StatusNotFound, map[string]string{"error": "unsupported path " + req.URL.Path}), nil
+	}
+
+	k.mu.Lock()
+	k.calls = append(k.calls, call)
+	status, failing := k.failures[model]
+	handler := k.handlers[model]
+	k.mu.Unlock()
+
+	switch {
+	case failing:
+		return jsonResponse(req, status, map[string]string{"error": fmt.Sprintf("scripted failure of %s", model)}), nil
+	case call.Health:
+		return jsonResponse(req, http.StatusOK, map[string]interface{}{"name": "model", "ready": true}), nil
+	}
+
+	if handler == nil {
+		handler = defaultPredictions
+	}
+	predictions, err := handler(call.Instances)
+	if err != nil {
+		return jsonResponse(req, http.StatusInternalServerError, map[string]string{"error": err.Error()}), nil
+	}
+	return jsonResponse(req, http.StatusOK, map[string]interface{}{
+		"predictions":   predictions,
+		"model_name":    model,
+		"model_version": "testsupport",
+	}), nil
+}
+
+// defaultPredictions forecasts predictive-analytics instances and predicts all others
+// normal
+func defaultPredictions(instances [][]float64) (interface{}, error) {
+	if len(instances) > 0 && len(instances[0]) == loadtest.ForecastFeatures {
+		forecasts := make([][]float64, len(instances))
+		for i := range forecasts {
+			forecasts[i] = []float64{0.7, 0.75}
+		}
+		return forecasts, nil
+	}
+	flags := make([]int, len(instances))
+	for i := range flags {
+		flags[i] = 1
+	}
+	return flags, nil
+}
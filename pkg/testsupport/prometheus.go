@@ -0,0 +1,300 @@
+package testsupport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/integrations"
+)
+
+// PrometheusURL is the base URL of clients created by Prometheus.Client
+const PrometheusURL = "http://prometheus.testsupport"
+
+// Series is one labelled series of a scripted instant query result
+type Series struct {
+	Labels map[string]string
+	Value  float64
+}
+
+// PrometheusCall is a query answered by the fake Prometheus
+type PrometheusCall struct {
+	// Query is the PromQL expression, after external labels were injected
+	Query string
+
+	// Range is true for range queries, which set Start, End and Step
+	Range bool
+	Start time.Time
+	End   time.Time
+	Step  time.Duration
+
+	// Time is the evaluation time of an instant query, zero for "now"
+	Time time.Time
+}
+
+// promRule scripts the response to queries containing match
+type promRule struct {
+	match  string
+	series []Series
+	points []float64
+	status int
+}
+
+// Prometheus is an in-memory fake of the Prometheus HTTP API. It answers the instant
+// and range queries of a PrometheusClient from scripted responses and records every
+// query, so handler tests need neither a cluster nor an HTTP server.
+//
+// Responses are scripted per query substring, typically a metric name. The most
+// recently scripted match wins, so a test can override a broader rule. Queries
+// matching no rule return an empty result, or the SetDefault value.
+type Prometheus struct {
+	mu       sync.Mutex
+	rules    []promRule
+	fallback *float64
+	calls    []PrometheusCall
+}
+
+// NewPrometheus creates a fake Prometheus answering every query with an empty result
+func NewPrometheus() *Prometheus {
+	return &Prometheus{}
+}
+
+// Client returns a PrometheusClient whose queries are answered by the fake
+func (p *Prometheus) Client(log *logrus.Logger) *integrations.PrometheusClient {
+	client := integrations.NewPrometheusClient(PrometheusURL, 5*time.Second, log)
+	client.HTTPClient().Transport = p
+	return client
+}
+
+// Set answers queries containing match with a single sample. Range queries get the
+// value at every step.
+func (p *Prometheus) Set(match string, value float64) {
+	p.SetSeries(match, Series{Value: value})
+}
+
+// SetSeries answers instant queries containing match with labelled series. Range
+// queries get the first series' value at every step.
+func (p *Prometheus) SetSeries(match string, series ...Series) {
+	p.script(promRule{match: match, series: series})
+}
+
+// SetRange answers range queries containing match with one value per step, starting
+// at the start of the range. Instant queries get the last value.
+func (p *Prometheus) SetRange(match string, values ...float64) {
+	p.script(promRule{match: match, points: values})
+}
+
+// Fail answers queries containing match with an HTTP error status
+func (p *Prometheus) Fail(match string, status int) {
+	p.script(promRule{match: match, status: status})
+}
+
+// SetDefault answers queries matching no rule with a single sample
+func (p *Prometheus) SetDefault(value float64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.fallback = &value
+}
+
+func (p *Prometheus) script(rule promRule) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.rules = append(p.rules, rule)
+}
+
+// Calls returns the queries answered so far, oldest first
+func (p *Prometheus) Calls() []PrometheusCall {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]PrometheusCall(nil), p.calls...)
+}
+
+// Queries returns the PromQL of the queries answered so far, oldest first
+func (p *Prometheus) Queries() []string {
+	calls := p.Calls()
+	queries := make([]string, len(calls))
+	for i, call := range calls {
+		queries[i] = call.Query
+	}
+	return queries
+}
+
+// Reset forgets the recorded queries; scripted responses are kept
+func (p *Prometheus) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.calls = nil
+}
+
+// RoundTrip answers a Prometheus API request
+func (p *Prometheus) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+	params := req.URL.Query()
+	call := PrometheusCall{Query: params.Get("query")}
+	switch req.URL.Path {
+	case "/api/v1/query":
+		call.Time, _ = parsePromTime(params.Get("time"))
+	case "/api/v1/query_range":
+		call.Range = true
+		var err error
+		if call.Start, err = parsePromTime(params.Get("start")); err != nil {
+			return jsonResponse(req, http.StatusBadRequest, promError(err)), nil
+		}
+		if call.End, err = parsePromTime(params.Get("end")); err != nil {
+			return jsonResponse(req, http.StatusBadRequest, promError(err)), nil
+		}
+		if call.Step, err = parsePromDuration(params.Get("step")); err != nil {
+			return jsonResponse(req, http.StatusBadRequest, promError(err)), nil
+		}
+	default:
+		return jsonResponse(req, http.StatusNotFound, promError(fmt.Errorf("unsupported path %s", req.URL.Path))), nil
+	}
+
+	p.mu.Lock()
+	p.calls = append(p.calls, call)
+	rule, ok := p.lookup(call.Query)
+	p.mu.Unlock()
+
+	if ok && rule.status != 0 {
+		return jsonResponse(req, rule.status, promError(fmt.Errorf("scripted failure for %q", rule.match))), nil
+	}
+	if call.Range {
+		return jsonResponse(req, http.StatusOK, matrixBody(rule, ok, call)), nil
+	}
+	return jsonResponse(req, http.StatusOK, vectorBody(rule, ok, call)), nil
+}
+
+// lookup returns the most recent rule matching a query, falling back to the default
+// value. Callers must hold p.mu.
+func (p *Prometheus) lookup(query string) (promRule, bool) {
+	for i := len(p.rules) - 1; i >= 0; i-- {
+		if strings.Contains(query, p.rules[i].match) {
+			return p.rules[i], true
+		}
+	}
+	if p.fallback != nil {
+		return promRule{series: []Series{{Value: *p.fallback}}}, true
+	}
+	return promRule{}, false
+}
+
+type promSeries struct {
+	Metric map[string]string `json:"metric"`
+	Value  []interface{}     `json:"value,omitempty"`
+	Values [][]interface{}   `json:"values,omitempty"`
+}
+
+func vectorBody(rule promRule, ok bool, call PrometheusCall) map[string]interface{} {
+	at := call.Time
+	if at.IsZero() {
+		at = time.Now()
+	}
+	result := []promSeries{}
+	if ok {
+		series := rule.series
+		if len(rule.points) > 0 {
+			series = []Series{{Value: rule.points[len(rule.points)-1]}}
+		}
+		for _, s := range series {
+			result = append(result, promSeries{Metric: labels(s.Labels), Value: promSample(at, s.Value)})
+		}
+	}
+	return promData("vector", result)
+}
+
+func matrixBody(rule promRule, ok bool, call PrometheusCall) map[string]interface{} {
+	result := []promSeries{}
+	if ok && (len(rule.points) > 0 || len(rule.series) > 0) {
+		step := call.Step
+		if step <= 0 {
+			step = time.Minute
+		}
+		series := promSeries{Metric: map[string]string{}, Values: [][]interface{}{}}
+		if len(rule.points) > 0 {
+			for i, value := range rule.points {
+				at := call.Start.Add(time.Duration(i) * step)
+				if at.After(call.End) {
+					break
+				}
+				series.Values = append(series.Values, promSample(at, value))
+			}
+		} else {
+			series.Metric = labels(rule.series[0].Labels)
+			for at := call.Start; !at.After(call.End); at = at.Add(step) {
+				series.Values = append(series.Values, promSample(at, rule.series[0].Value))
+			}
+		}
+		result = append(result, series)
+	}
+	return promData("matrix", result)
+}
+
+func promData(resultType string, result []promSeries) map[string]interface{} {
+	return map[string]interface{}{
+		"status": "success",
+		"data":   map[string]interface{}{"resultType": resultType, "result": result},
+	}
+}
+
+func promError(err error) map[string]interface{} {
+	return map[string]interface{}{"status": "error", "errorType": "bad_data", "error": err.Error()}
+}
+
+func promSample(at time.Time, value float64) []interface{} {
+	return []interface{}{float64(at.Unix()), strconv.FormatFloat(value, 'f', -1, 64)}
+}
+
+func labels(l map[string]string) map[string]string {
+	if l == nil {
+		return map[string]string{}
+	}
+	return l
+}
+
+func jsonResponse(req *http.Request, status int, body interface{}) *http.Response {
+	data, _ := json.Marshal(body)
+	return &http.Response{
+		StatusCode: status,
+		Status:     fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(bytes.NewReader(data)),
+		Request:    req,
+	}
+}
+
+// parsePromTime parses a Prometheus API timestamp: Unix seconds or RFC 3339
+func parsePromTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if seconds, err := strconv.ParseFloat(s, 64); err == nil {
+		return time.Unix(0, int64(seconds*float64(time.Second))).UTC(), nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q", s)
+	}
+	return t, nil
+}
+
+// parsePromDuration parses a Prometheus API duration: seconds or a Go duration
+func parsePromDuration(s string) (time.Duration, error) {
+	if seconds, err := strconv.ParseFloat(s, 64); err == nil {
+		return time.Duration(seconds * float64(time.Second)), nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid step %q", s)
+	}
+	return d, nil
+}
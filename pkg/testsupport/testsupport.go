@@ -0,0 +1,53 @@
+// Package testsupport provides in-memory fakes of the engine's backends for handler
+// and integration tests.
+//
+// Prometheus and KServe fake the Prometheus HTTP API and KServe v1 predictors at the
+// transport of the real clients, so the clients' query building, caching, response
+// validation and error handling run unchanged while responses are scripted and
+// requests recorded. No HTTP server is started:
+//
+//	prom := testsupport.NewPrometheus()
+//	prom.Set("container_cpu_usage_seconds_total", 0.8)
+//	models := testsupport.NewKServe()
+//	models.SetPredictions("anomaly-detector", []int{-1})
+//	kserveClient, err := models.Client(log, "anomaly-detector")
+//	handler := v1.NewAnomalyHandler(kserveClient, nil, log)
+//	handler.SetPrometheusClient(prom.Client(log))
+//
+// The store helpers return stores seeded with fixtures that are never persisted.
+package testsupport
+
+import (
+	"github.com/tosin2013/openshift-coordination-engine/internal/storage"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+// NewIncidentStore returns an in-memory incident store holding the incidents, which
+// need an ID
+func NewIncidentStore(incidents ...*models.Incident) (*storage.IncidentStore, error) {
+	store := storage.NewMemoryIncidentStore()
+	if err := store.Restore(incidents, true); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// NewPredictionStore returns an in-memory prediction store holding the records, which
+// need an ID
+func NewPredictionStore(records ...models.PredictionRecord) (*storage.PredictionStore, error) {
+	store := storage.NewMemoryPredictionStore(0)
+	if err := store.Restore(records, true); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// NewActionOutcomeStore returns an in-memory action outcome store holding the
+// outcomes, which need an ID
+func NewActionOutcomeStore(outcomes ...models.ActionOutcome) (*storage.ActionOutcomeStore, error) {
+	store := storage.NewMemoryActionOutcomeStore(0)
+	if err := store.Restore(outcomes, true); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
@@ -0,0 +1,155 @@
+package testsupport
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/integrations"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/kserve"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/models"
+)
+
+func testLogger() *logrus.Logger {
+	log := logrus.New()
+	log.SetLevel(logrus.PanicLevel)
+	return log
+}
+
+func TestPrometheus(t *testing.T) {
+	prom := NewPrometheus()
+	client := prom.Client(testLogger())
+	ctx := context.Background()
+
+	_, err := client.Query(ctx, `sum(up)`)
+	assert.Error(t, err, "unscripted queries return no data")
+
+	prom.Set("up", 1)
+	prom.Set(`up{job="etcd"}`, 0)
+	value, err := client.Query(ctx, `sum(up{job="api"})`)
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, value)
+	value, err = client.Query(ctx, `sum(up{job="etcd"})`)
+	require.NoError(t, err)
+	assert.Equal(t, 0.0, value, "the most recent match wins")
+
+	prom.SetSeries("kube_pod_info", Series{Labels: map[string]string{"pod": "a"}, Value: 1}, Series{Labels: map[string]string{"pod": "b"}, Value: 2})
+	samples, err := client.QueryVector(ctx, `kube_pod_info`)
+	require.NoError(t, err)
+	assert.Len(t, samples, 2)
+	assert.Equal(t, "b", samples[1].Labels["pod"])
+
+	start := time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC)
+	prom.SetRange("memory", 1, 2, 3)
+	points, err := client.QueryRange(ctx, `sum(memory)`, start, start.Add(time.Hour), time.Minute, integrations.RangeFirst)
+	require.NoError(t, err)
+	require.Len(t, points, 3)
+	assert.Equal(t, start.Add(2*time.Minute), points[2].Timestamp.UTC())
+	assert.Equal(t, 3.0, points[2].Value)
+
+	prom.Fail("memory", http.StatusServiceUnavailable)
+	_, err = client.Query(ctx, `sum(memory)`)
+	assert.ErrorContains(t, err, "status 503")
+
+	prom.SetDefault(0.5)
+	value, err = client.Query(ctx, `sum(anything)`)
+	require.NoError(t, err)
+	assert.Equal(t, 0.5, value)
+
+	calls := prom.Calls()
+	require.Len(t, calls, 7)
+	assert.True(t, calls[4].Range)
+	assert.Equal(t, start, calls[4].Start)
+	assert.Equal(t, time.Minute, calls[4].Step)
+	assert.Equal(t, `sum(up{job="api"})`, prom.Queries()[1])
+	prom.Reset()
+	assert.Empty(t, prom.Calls())
+}
+
+func TestKServe(t *testing.T) {
+	models := NewKServe()
+	client, err := models.Client(testLogger(), "anomaly-detector", "predictive-analytics")
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	detected, err := client.Predict(ctx, "anomaly-detector", [][]float64{{0.1}, {0.2}})
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 1}, detected.Predictions)
+
+	models.SetPredictions("anomaly-detector", []int{-1})
+	detected, err = client.Predict(ctx, "anomaly-detector", [][]float64{{0.9}})
+	require.NoError(t, err)
+	assert.Equal(t, []int{-1}, detected.Predictions)
+
+	forecast, err := client.PredictFlexible(ctx, "predictive-analytics", [][]float64{{15, 3, 0.5, 0.6}})
+	require.NoError(t, err)
+	require.NotNil(t, forecast.ForecastResponse)
+
+	models.SetHandler("predictive-analytics", func([][]float64) (interface{}, error) {
+		return nil, errors.New("model not loaded")
+	})
+	_, err = client.PredictFlexible(ctx, "predictive-analytics", [][]float64{{15, 3, 0.5, 0.6}})
+	assert.ErrorContains(t, err, "model not loaded")
+
+	health, err := client.CheckModelHealth(ctx, "anomaly-detector")
+	require.NoError(t, err)
+	assert.Equal(t, "ready", health.Status)
+	models.Fail("anomaly-detector", http.StatusServiceUnavailable)
+	health, err = client.CheckModelHealth(ctx, "anomaly-detector")
+	require.NoError(t, err)
+	assert.Equal(t, "unavailable", health.Status)
+
+	assert.Equal(t, 2, models.Predictions("anomaly-detector"))
+	calls := models.Calls()
+	require.Len(t, calls, 6)
+	assert.Equal(t, ModelCall{Model: "anomaly-detector", Instances: [][]float64{{0.9}}}, calls[1])
+	assert.Equal(t, ModelCall{Model: "anomaly-detector", Health: true}, calls[4])
+	models.Reset()
+	assert.Empty(t, models.Calls())
+}
+
+func TestKServe_Rollout(t *testing.T) {
+	fake := NewKServe()
+	client, err := fake.Client(testLogger(), "anomaly-detector")
+	require.NoError(t, err)
+	rollouts, err := kserve.NewRollouts(map[string]kserve.ModelRollout{
+		"anomaly-detector": {Versions: map[string]string{"v2": ModelURL("anomaly-detector-v2")}, Pinned: "v2"},
+	})
+	require.NoError(t, err)
+	client.SetRollouts(rollouts)
+
+	fake.SetPredictions("anomaly-detector-v2", []int{-1})
+	detected, err := client.Predict(context.Background(), "anomaly-detector", [][]float64{{0.9}})
+	require.NoError(t, err)
+	assert.Equal(t, []int{-1}, detected.Predictions)
+	assert.Equal(t, "v2", detected.ModelVersion)
+	assert.Equal(t, 1, fake.Predictions("anomaly-detector-v2"))
+	assert.Zero(t, fake.Predictions("anomaly-detector"))
+}
+
+func TestStores(t *testing.T) {
+	incidents, err := NewIncidentStore(&models.Incident{ID: "inc-1", Title: "Pods restarting", Severity: models.IncidentSeverityLow})
+	require.NoError(t, err)
+	incident, err := incidents.Get("inc-1")
+	require.NoError(t, err)
+	assert.Equal(t, "Pods restarting", incident.Title)
+	_, err = incidents.Create(&models.Incident{Title: "Node not ready", Description: "Node not ready", Severity: models.IncidentSeverityHigh, Target: "worker-1"})
+	require.NoError(t, err, "in-memory stores are not persisted")
+	assert.Equal(t, 2, incidents.Count())
+	_, err = NewIncidentStore(&models.Incident{Title: "no id"})
+	assert.Error(t, err)
+
+	predictions, err := NewPredictionStore(models.PredictionRecord{ID: "pred-1"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, predictions.Count())
+
+	outcomes, err := NewActionOutcomeStore(models.ActionOutcome{ID: "out-1"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, outcomes.Count())
+}
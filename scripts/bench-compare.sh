@@ -0,0 +1,74 @@
+#!/bin/bash
+# bench-compare.sh - Fail when benchmarks regressed against a saved baseline
+#
+# Usage: ./scripts/bench-compare.sh <baseline.txt> <current.txt>
+#
+# Both files are "go test -bench -benchmem" output; results of repeated runs
+# (-count) are averaged per benchmark. A benchmark regresses when its ns/op grows
+# by more than BENCH_TOLERANCE percent (default 15) or its allocs/op by more than
+# BENCH_ALLOC_TOLERANCE percent (default 5). Allocation counts are stable between
+# machines; timings are only comparable on the same machine.
+
+set -e
+
+BASELINE="$1"
+CURRENT="$2"
+BENCH_TOLERANCE="${BENCH_TOLERANCE:-15}"
+BENCH_ALLOC_TOLERANCE="${BENCH_ALLOC_TOLERANCE:-5}"
+
+if [ -z "$BASELINE" ] || [ -z "$CURRENT" ]; then
+    echo "Usage: $0 <baseline.txt> <current.txt>"
+    exit 2
+fi
+if [ ! -f "$BASELINE" ]; then
+    echo "Baseline $BASELINE not found; save one with 'make bench-baseline'"
+    exit 2
+fi
+if [ ! -f "$CURRENT" ]; then
+    echo "Benchmark results $CURRENT not found; run 'make bench'"
+    exit 2
+fi
+
+awk -v tol="$BENCH_TOLERANCE" -v alloc_tol="$BENCH_ALLOC_TOLERANCE" '
+# Sum ns/op and allocs/op per benchmark of one file
+function record(prefix,    i) {
+    name = $1
+    for (i = 3; i < NF; i++) {
+        if ($(i + 1) == "ns/op") { ns[prefix, name] += $i; nsn[prefix, name]++ }
+        if ($(i + 1) == "allocs/op") { al[prefix, name] += $i; aln[prefix, name]++ }
+    }
+    if (prefix == "cur" && !(name in seen)) { seen[name] = 1; order[++count] = name }
+}
+FNR == NR && /^Benchmark/ { record("base"); next }
+/^Benchmark/ { record("cur") }
+END {
+    failed = 0
+    printf "%-56s %14s %14s %8s %12s %12s %8s\n", "BENCHMARK", "BASE NS/OP", "NS/OP", "DELTA", "BASE ALLOCS", "ALLOCS", "DELTA"
+    for (i = 1; i <= count; i++) {
+        name = order[i]
+        if (!(("base", name) in nsn)) {
+            printf "%-56s %14s %14.0f %8s\n", name, "-", ns["cur", name] / nsn["cur", name], "new"
+            continue
+        }
+        bns = ns["base", name] / nsn["base", name]
+        cns = ns["cur", name] / nsn["cur", name]
+        dns = bns > 0 ? (cns - bns) * 100 / bns : 0
+        status = ""
+        if (dns > tol) { status = "  REGRESSED (time)"; failed = 1 }
+
+        dal = 0; bal = 0; cal = 0
+        if ((("base", name) in aln) && (("cur", name) in aln)) {
+            bal = al["base", name] / aln["base", name]
+            cal = al["cur", name] / aln["cur", name]
+            dal = bal > 0 ? (cal - bal) * 100 / bal : (cal > 0 ? 100 : 0)
+            if (dal > alloc_tol) { status = status "  REGRESSED (allocs)"; failed = 1 }
+        }
+        printf "%-56s %14.0f %14.0f %+7.1f%% %12.0f %12.0f %+7.1f%%%s\n", name, bns, cns, dns, bal, cal, dal, status
+    }
+    if (failed) {
+        printf "\nBenchmarks regressed beyond %s%% time or %s%% allocations\n", tol, alloc_tol
+        exit 1
+    }
+    printf "\nNo regressions beyond %s%% time or %s%% allocations\n", tol, alloc_tol
+}
+' "$BASELINE" "$CURRENT"
@@ -0,0 +1,180 @@
+//go:build load
+
+package load
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tosin2013/openshift-coordination-engine/internal/integrations"
+	"github.com/tosin2013/openshift-coordination-engine/internal/loadtest"
+	v1 "github.com/tosin2013/openshift-coordination-engine/pkg/api/v1"
+	"github.com/tosin2013/openshift-coordination-engine/pkg/kserve"
+)
+
+// Settings, overridable through the environment
+const (
+	defaultDuration    = 5 * time.Second
+	defaultLatency     = 2 * time.Millisecond
+	defaultConcurrency = "1,8,32"
+)
+
+// scenario is an API request driven at increasing concurrency
+type scenario struct {
+	name string
+	path string
+	body string
+}
+
+var scenarios = []scenario{
+	{"analyze", "/api/v1/anomalies/analyze", `{"namespace":"payments","deployment":"api"}`},
+	{"predict", "/api/v1/predict", `{"hour":15,"day_of_week":3,"namespace":"payments"}`},
+	{"predict-series", "/api/v1/predict", `{"namespace":"payments","horizon":{"every":"1h","for":"24h"}}`},
+}
+
+// TestAnalysisLoad drives the analyze and predict endpoints of an in-process engine
+// backed by mock Prometheus and KServe, and writes a throughput, latency and
+// allocation report. Run it with "make load-test".
+//
+//	LOAD_DURATION     duration of each scenario (default 5s)
+//	LOAD_LATENCY      latency of every mock backend response (default 2ms)
+//	LOAD_CONCURRENCY  comma-separated worker counts (default 1,8,32)
+//	LOAD_REPORT_DIR   directory for load-report.txt and load-report.json
+func TestAnalysisLoad(t *testing.T) {
+	duration := envDuration(t, "LOAD_DURATION", defaultDuration)
+	latency := envDuration(t, "LOAD_LATENCY", defaultLatency)
+	concurrency := envInts(t, "LOAD_CONCURRENCY", defaultConcurrency)
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	prometheus := loadtest.MockPrometheus(loadtest.MockOptions{Latency: latency})
+	defer prometheus.Close()
+	model := loadtest.MockKServe(loadtest.MockOptions{Latency: latency})
+	defer model.Close()
+
+	t.Setenv("KSERVE_ANOMALY_DETECTOR_SERVICE", "anomaly-detector-predictor")
+	t.Setenv("KSERVE_PREDICTIVE_ANALYTICS_SERVICE", "predictive-analytics-predictor")
+	kserveClient, err := kserve.NewProxyClient(kserve.ProxyConfig{Namespace: "load", Timeout: 30 * time.Second}, log)
+	require.NoError(t, err)
+	rollouts, err := kserve.NewRollouts(map[string]kserve.ModelRollout{
+		"anomaly-detector":     {Versions: map[string]string{"v1": model.URL}, Pinned: "v1"},
+		"predictive-analytics": {Versions: map[string]string{"v1": model.URL}, Pinned: "v1"},
+	})
+	require.NoError(t, err)
+	kserveClient.SetRollouts(rollouts)
+	promClient := integrations.NewPrometheusClient(prometheus.URL, 30*time.Second, log)
+
+	router := mux.NewRouter()
+	anomalyHandler := v1.NewAnomalyHandler(kserveClient, nil, log)
+	anomalyHandler.SetPrometheusClient(promClient)
+	anomalyHandler.RegisterRoutes(router)
+	v1.NewPredictionHandler(kserveClient, promClient, log).RegisterRoutes(router)
+	engine := httptest.NewServer(router)
+	defer engine.Close()
+
+	client := &http.Client{Transport: &http.Transport{MaxIdleConnsPerHost: 64}, Timeout: time.Minute}
+	var reports []*loadtest.Report
+	for _, sc := range scenarios {
+		for _, workers := range concurrency {
+			prometheus.Reset()
+			model.Reset()
+			// Predictions cache the scope's rolling means; start every scenario cold
+			promClient.ClearCache()
+
+			report, err := loadtest.Run(context.Background(), loadtest.Config{
+				Name:        fmt.Sprintf("%s/c=%d", sc.name, workers),
+				Concurrency: workers,
+				Duration:    duration,
+			}, func(context.Context) error {
+				// In-flight requests finish at the end of the run, so every backend
+				// call belongs to a counted request
+				return post(context.Background(), client, engine.URL+sc.path, sc.body)
+			})
+			require.NoError(t, err)
+			require.Zero(t, report.Errors, "scenario %s: %s", report.Name, report.FirstError)
+			if report.Requests > 0 {
+				report.BackendCallsPerOp = map[string]float64{
+					"prometheus": float64(prometheus.Requests()) / float64(report.Requests),
+					"kserve":     float64(model.Requests()) / float64(report.Requests),
+				}
+			}
+			reports = append(reports, report)
+		}
+	}
+
+	var text bytes.Buffer
+	fmt.Fprintf(&text, "Analysis path load report (%s per scenario, %s backend latency)\n\n", duration, latency)
+	require.NoError(t, loadtest.WriteText(&text, reports))
+	t.Log("\n" + text.String())
+
+	if dir := os.Getenv("LOAD_REPORT_DIR"); dir != "" {
+		require.NoError(t, os.MkdirAll(dir, 0o750))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "load-report.txt"), text.Bytes(), 0o600))
+		data, err := json.MarshalIndent(reports, "", "  ")
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "load-report.json"), data, 0o600))
+	}
+}
+
+// post sends a JSON request and fails on any status but 200
+func post(ctx context.Context, client *http.Client, url, body string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBufferString(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s responded %d: %s", url, resp.StatusCode, data)
+	}
+	return nil
+}
+
+// envDuration reads a duration setting
+func envDuration(t *testing.T, name string, def time.Duration) time.Duration {
+	t.Helper()
+	value := os.Getenv(name)
+	if value == "" {
+		return def
+	}
+	d, err := time.ParseDuration(value)
+	require.NoError(t, err, "invalid %s", name)
+	return d
+}
+
+// envInts reads a comma-separated list of positive integers
+func envInts(t *testing.T, name, def string) []int {
+	t.Helper()
+	value := os.Getenv(name)
+	if value == "" {
+		value = def
+	}
+	var ints []int
+	for _, field := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(field))
+		require.NoError(t, err, "invalid %s", name)
+		require.Positive(t, n, "invalid %s", name)
+		ints = append(ints, n)
+	}
+	return ints
+}